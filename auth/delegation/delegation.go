@@ -0,0 +1,314 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package delegation lets a proxy mint short lived tokens binding a caller,
+// a target and the methods it may call there, which servers verify. This lets
+// a server insist it's only reached via a trusted proxy, and only by callers
+// the proxy vouches for, i.e.
+//
+//	allow {
+//	  input.delegation.issuer = "proxy.example.com"
+//	  input.delegation.subject = "alice"
+//	}
+//
+// The proxy mints a token with a Minter when it opens a stream to a target
+// and sends it in the stream metadata. On the server Hook verifies it and
+// exposes it to policy as input.delegation. Tokens are bearer credentials
+// while they're valid, so keep their TTL short and have policy also check the
+// peer is the proxy (i.e. with input.peer.principal).
+package delegation
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+const (
+	// MetadataKey is the gRPC metadata key carrying the token.
+	MetadataKey = "sansshell-delegation"
+
+	// DefaultTTL is how long tokens are valid for unless changed with
+	// NewMinter.
+	DefaultTTL = 5 * time.Minute
+
+	// Allow this much difference between proxy and server clocks.
+	clockSkew = time.Minute
+)
+
+var validMethods = []string{"ES256", "ES384", "ES512", "RS256", "EdDSA"}
+
+// A Grant describes what a token permits.
+type Grant struct {
+	// Subject is the caller the proxy is acting for.
+	Subject string
+	// Target is the server the token may be presented to.
+	Target string
+	// Methods are the full method names the token may be used for.
+	Methods []string
+}
+
+// claims are the JWT claims of a token. The target is the audience.
+type claims struct {
+	jwt.RegisteredClaims
+	Methods []string `json:"methods"`
+}
+
+// A Minter creates tokens on behalf of a proxy.
+type Minter struct {
+	issuer string
+	key    crypto.PrivateKey
+	method jwt.SigningMethod
+	ttl    time.Duration
+}
+
+// NewMinter returns a Minter which signs tokens as `issuer` using `key`, which
+// must be an ECDSA (P-256, P-384 or P-521), RSA or Ed25519 private key.
+// If ttl is zero DefaultTTL is used.
+func NewMinter(issuer string, key crypto.PrivateKey, ttl time.Duration) (*Minter, error) {
+	if issuer == "" {
+		return nil, errors.New("issuer must be set")
+	}
+	var method jwt.SigningMethod
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			method = jwt.SigningMethodES256
+		case elliptic.P384():
+			method = jwt.SigningMethodES384
+		case elliptic.P521():
+			method = jwt.SigningMethodES512
+		default:
+			return nil, fmt.Errorf("unsupported ECDSA curve %s", k.Curve.Params().Name)
+		}
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	case ed25519.PrivateKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	return &Minter{
+		issuer: issuer,
+		key:    key,
+		method: method,
+		ttl:    ttl,
+	}, nil
+}
+
+// LoadMinter is NewMinter with a PEM encoded private key read from keyFile.
+func LoadMinter(issuer string, keyFile string, ttl time.Duration) (*Minter, error) {
+	b, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", keyFile)
+	}
+	var key crypto.PrivateKey
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't parse private key in %s: %w", keyFile, err)
+	}
+	return NewMinter(issuer, key, ttl)
+}
+
+// Mint returns a token for `g`.
+func (m *Minter) Mint(g Grant) (string, error) {
+	if g.Subject == "" {
+		return "", errors.New("token must have a subject")
+	}
+	if g.Target == "" {
+		return "", errors.New("token must have a target")
+	}
+	if len(g.Methods) == 0 {
+		return "", errors.New("token must have at least one method")
+	}
+	now := time.Now()
+	c := &claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   g.Subject,
+			Audience:  jwt.ClaimStrings{g.Target},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.ttl)),
+		},
+		Methods: g.Methods,
+	}
+	return jwt.NewWithClaims(m.method, c).SignedString(m.key)
+}
+
+// A Verifier checks tokens against the public keys of trusted proxies.
+type Verifier struct {
+	keys map[string]crypto.PublicKey
+}
+
+// NewVerifier returns a Verifier which accepts tokens from the given issuers,
+// each of which must be signed with the corresponding public key.
+func NewVerifier(keys map[string]crypto.PublicKey) (*Verifier, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no trusted proxy keys")
+	}
+	out := make(map[string]crypto.PublicKey, len(keys))
+	for iss, k := range keys {
+		switch k.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		default:
+			return nil, fmt.Errorf("unsupported key type %T for %s", k, iss)
+		}
+		out[iss] = k
+	}
+	return &Verifier{keys: out}, nil
+}
+
+// LoadVerifier is NewVerifier with keys read from files (keyed by issuer)
+// containing either a PEM encoded public key or certificate.
+func LoadVerifier(keyFiles map[string]string) (*Verifier, error) {
+	keys := make(map[string]crypto.PublicKey, len(keyFiles))
+	for iss, f := range keyFiles {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM data in %s", f)
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("can't parse certificate in %s: %w", f, err)
+			}
+			keys[iss] = cert.PublicKey
+		default:
+			k, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("can't parse public key in %s: %w", f, err)
+			}
+			keys[iss] = k
+		}
+	}
+	return NewVerifier(keys)
+}
+
+// Verify checks the signature and validity of `token` and that it permits
+// `method`, returning its contents.
+func (v *Verifier) Verify(token string, method string) (*rpcauth.DelegationInput, error) {
+	c := &claims{}
+	// Times are checked below to allow for clock skew.
+	p := jwt.NewParser(jwt.WithValidMethods(validMethods), jwt.WithoutClaimsValidation())
+	_, err := p.ParseWithClaims(token, c, func(t *jwt.Token) (interface{}, error) {
+		k, ok := v.keys[c.Issuer]
+		if !ok {
+			return nil, fmt.Errorf("untrusted issuer %q", c.Issuer)
+		}
+		return k, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !c.VerifyExpiresAt(now.Add(-clockSkew), true) {
+		return nil, errors.New("token expired")
+	}
+	if !c.VerifyIssuedAt(now.Add(clockSkew), true) {
+		return nil, errors.New("token issued in the future")
+	}
+	if c.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	if len(c.Audience) != 1 {
+		return nil, errors.New("token must have one target")
+	}
+	permitted := false
+	for _, m := range c.Methods {
+		if m == method {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		return nil, fmt.Errorf("token doesn't permit method %s", method)
+	}
+	return &rpcauth.DelegationInput{
+		Issuer:  c.Issuer,
+		Subject: c.Subject,
+		Target:  c.Audience[0],
+		Methods: c.Methods,
+		Expires: c.ExpiresAt.Time,
+	}, nil
+}
+
+// Hook returns an RPCAuthzHook which verifies any token in the request
+// metadata with `v` and sets input.Delegation. The token is removed from
+// input.Metadata. If audience is set the token must have been minted for it
+// as a target. Requests with an invalid token are rejected, as are ones
+// without a token if required is set.
+func Hook(v *Verifier, audience string, required bool) rpcauth.RPCAuthzHook {
+	return rpcauth.RPCAuthzHookFunc(func(ctx context.Context, input *rpcauth.RPCAuthInput) error {
+		vals := input.Metadata.Get(MetadataKey)
+		input.Metadata.Delete(MetadataKey)
+		if len(vals) == 0 {
+			if required {
+				return status.Error(codes.PermissionDenied, "request must be delegated by a trusted proxy")
+			}
+			return nil
+		}
+		if len(vals) > 1 {
+			return status.Error(codes.Unauthenticated, "multiple delegation tokens")
+		}
+		d, err := v.Verify(vals[0], input.Method)
+		if err != nil {
+			return status.Errorf(codes.Unauthenticated, "invalid delegation token: %v", err)
+		}
+		if audience != "" && d.Target != audience {
+			return status.Errorf(codes.Unauthenticated, "delegation token is for %s, not %s", d.Target, audience)
+		}
+		if err := rpcauth.CheckProxyTarget(input, d.Target); err != nil {
+			return err
+		}
+		input.Delegation = d
+		return nil
+	})
+}
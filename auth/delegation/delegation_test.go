@@ -0,0 +1,250 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package delegation
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const method = "/Foo.Bar/Baz"
+
+var grant = Grant{
+	Subject: "alice",
+	Target:  "host:50042",
+	Methods: []string{method, "/Foo.Bar/Qux"},
+}
+
+func TestMintVerify(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	testutil.FatalOnErr("ecdsa.GenerateKey", err, t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	testutil.FatalOnErr("rsa.GenerateKey", err, t)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	testutil.FatalOnErr("ed25519.GenerateKey", err, t)
+
+	for _, tc := range []struct {
+		name string
+		key  crypto.Signer
+	}{
+		{"ecdsa", ecKey},
+		{"rsa", rsaKey},
+		{"ed25519", edKey},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := NewMinter("proxy", tc.key, 0)
+			testutil.FatalOnErr("NewMinter", err, t)
+			token, err := m.Mint(grant)
+			testutil.FatalOnErr("Mint", err, t)
+			v, err := NewVerifier(map[string]crypto.PublicKey{"proxy": tc.key.Public()})
+			testutil.FatalOnErr("NewVerifier", err, t)
+			got, err := v.Verify(token, method)
+			testutil.FatalOnErr("Verify", err, t)
+			want := &rpcauth.DelegationInput{
+				Issuer:  "proxy",
+				Subject: grant.Subject,
+				Target:  grant.Target,
+				Methods: grant.Methods,
+				Expires: got.Expires,
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Verify diff (-want +got):\n%s", diff)
+			}
+			if d := time.Until(got.Expires); d < DefaultTTL-time.Minute || d > DefaultTTL {
+				t.Errorf("Expires = %v, want about %v from now", got.Expires, DefaultTTL)
+			}
+
+			_, err = v.Verify(token, "/Foo.Bar/Other")
+			testutil.FatalOnNoErr("other method", err, t)
+
+			// A token signed by another key, or for an unknown issuer, is rejected.
+			other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			testutil.FatalOnErr("ecdsa.GenerateKey", err, t)
+			bad, err := NewMinter("proxy", other, 0)
+			testutil.FatalOnErr("NewMinter", err, t)
+			token, err = bad.Mint(grant)
+			testutil.FatalOnErr("Mint", err, t)
+			_, err = v.Verify(token, method)
+			testutil.FatalOnNoErr("wrong key", err, t)
+			bad, err = NewMinter("other-proxy", tc.key, 0)
+			testutil.FatalOnErr("NewMinter", err, t)
+			token, err = bad.Mint(grant)
+			testutil.FatalOnErr("Mint", err, t)
+			_, err = v.Verify(token, method)
+			testutil.FatalOnNoErr("unknown issuer", err, t)
+		})
+	}
+
+	// Expired tokens are rejected.
+	m, err := NewMinter("proxy", ecKey, -2*clockSkew)
+	testutil.FatalOnErr("NewMinter", err, t)
+	token, err := m.Mint(grant)
+	testutil.FatalOnErr("Mint", err, t)
+	v, err := NewVerifier(map[string]crypto.PublicKey{"proxy": ecKey.Public()})
+	testutil.FatalOnErr("NewVerifier", err, t)
+	_, err = v.Verify(token, method)
+	testutil.FatalOnNoErr("expired", err, t)
+
+	// Tokens must be fully scoped.
+	m, err = NewMinter("proxy", ecKey, 0)
+	testutil.FatalOnErr("NewMinter", err, t)
+	for _, g := range []Grant{
+		{Target: grant.Target, Methods: grant.Methods},
+		{Subject: grant.Subject, Methods: grant.Methods},
+		{Subject: grant.Subject, Target: grant.Target},
+	} {
+		_, err = m.Mint(g)
+		testutil.FatalOnNoErr("incomplete grant", err, t)
+	}
+
+	_, err = NewMinter("", ecKey, 0)
+	testutil.FatalOnNoErr("no issuer", err, t)
+	_, err = NewMinter("proxy", "key", 0)
+	testutil.FatalOnNoErr("bad key", err, t)
+	_, err = NewVerifier(nil)
+	testutil.FatalOnNoErr("no keys", err, t)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	der, err := x509.MarshalECPrivateKey(key)
+	testutil.FatalOnErr("MarshalECPrivateKey", err, t)
+	keyFile := filepath.Join(dir, "key.pem")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600), t)
+	der, err = x509.MarshalPKIXPublicKey(key.Public())
+	testutil.FatalOnErr("MarshalPKIXPublicKey", err, t)
+	pubFile := filepath.Join(dir, "pub.pem")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(pubFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600), t)
+
+	m, err := LoadMinter("proxy", keyFile, 0)
+	testutil.FatalOnErr("LoadMinter", err, t)
+	v, err := LoadVerifier(map[string]string{"proxy": pubFile})
+	testutil.FatalOnErr("LoadVerifier", err, t)
+	token, err := m.Mint(grant)
+	testutil.FatalOnErr("Mint", err, t)
+	_, err = v.Verify(token, method)
+	testutil.FatalOnErr("Verify", err, t)
+
+	_, err = LoadMinter("proxy", pubFile, 0)
+	testutil.FatalOnNoErr("LoadMinter with public key", err, t)
+	_, err = LoadVerifier(map[string]string{"proxy": filepath.Join(dir, "missing")})
+	testutil.FatalOnNoErr("LoadVerifier with missing file", err, t)
+}
+
+func TestHook(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	m, err := NewMinter("proxy", key, 0)
+	testutil.FatalOnErr("NewMinter", err, t)
+	token, err := m.Mint(grant)
+	testutil.FatalOnErr("Mint", err, t)
+	v, err := NewVerifier(map[string]crypto.PublicKey{"proxy": key.Public()})
+	testutil.FatalOnErr("NewVerifier", err, t)
+
+	for _, tc := range []struct {
+		name      string
+		md        metadata.MD
+		target    string
+		audience  string
+		required  bool
+		wantCode  codes.Code
+		wantToken bool
+	}{
+		{
+			name:      "valid",
+			md:        metadata.Pairs(MetadataKey, token),
+			wantToken: true,
+		},
+		{
+			name:      "matching audience",
+			md:        metadata.Pairs(MetadataKey, token),
+			audience:  grant.Target,
+			wantToken: true,
+		},
+		{
+			name:     "other audience",
+			md:       metadata.Pairs(MetadataKey, token),
+			audience: "other:50042",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "other requested target",
+			md:       metadata.Pairs(MetadataKey, token),
+			target:   "other:50042",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "missing",
+		},
+		{
+			name:     "missing but required",
+			required: true,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "invalid",
+			md:       metadata.Pairs(MetadataKey, "bogus"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "multiple",
+			md:       metadata.Pairs(MetadataKey, token, MetadataKey, token),
+			wantCode: codes.Unauthenticated,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := &rpcauth.RPCAuthInput{Method: method, Metadata: tc.md}
+			if tc.target != "" {
+				input.Host = &rpcauth.HostAuthInput{Target: tc.target}
+			}
+			err := Hook(v, tc.audience, tc.required).Hook(ctx, input)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Hook() code = %v, want %v (err %v)", got, tc.wantCode, err)
+			}
+			if got := input.Delegation != nil; got != tc.wantToken {
+				t.Errorf("Delegation set = %t, want %t", got, tc.wantToken)
+			}
+			if len(input.Metadata.Get(MetadataKey)) != 0 {
+				t.Error("token wasn't removed from metadata")
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package enroll implements a simple certificate enrollment flow so operators
+// can obtain short lived sansshell client certificates without a separate PKI
+// process:
+//
+//  1. The client generates a key and a certificate signing request (CSR).
+//  2. It POSTs the PEM encoded CSR to an Issuer with a bearer token (i.e.
+//     an OIDC ID token) proving who it is.
+//  3. The Issuer verifies the token and returns a certificate for the token's
+//     identity, signed by its CA, which servers are configured to trust.
+//
+// The identity in the certificate always comes from the token. Any names
+// requested in the CSR are ignored, the CSR only proves possession of the key.
+package enroll
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/auth/oidc"
+)
+
+const (
+	// DefaultTTL is how long issued certificates are valid for unless
+	// changed with WithTTL.
+	DefaultTTL = 12 * time.Hour
+
+	// ContentType is the type of CSRs sent to, and certificates returned
+	// by, an Issuer.
+	ContentType = "application/x-pem-file"
+
+	// Requests and responses larger than this are rejected.
+	maxBodySize = 64 * 1024
+
+	// Certificates are backdated by this to allow for clock skew.
+	backdate = time.Minute
+
+	bearerPrefix = "Bearer "
+)
+
+// A TokenVerifier checks a bearer token and returns the identity it proves.
+type TokenVerifier func(ctx context.Context, token string) (string, error)
+
+// OIDC returns a TokenVerifier accepting ID tokens verified by `v`. The
+// identity is the principal claim (see oidc.WithPrincipalClaim).
+func OIDC(v *oidc.Verifier) TokenVerifier {
+	return func(ctx context.Context, token string) (string, error) {
+		claims, err := v.Verify(ctx, token)
+		if err != nil {
+			return "", err
+		}
+		principal := v.Principal(claims)
+		if principal == "" {
+			return "", errors.New("token has no principal")
+		}
+		return principal, nil
+	}
+}
+
+// An Issuer is an http.Handler issuing client certificates for CSRs
+// accompanied by a valid bearer token.
+type Issuer struct {
+	ca     *x509.Certificate
+	key    crypto.Signer
+	verify TokenVerifier
+	ttl    time.Duration
+}
+
+// An Option controls the behavior of an Issuer.
+type Option interface {
+	apply(*Issuer)
+}
+
+type optionFunc func(*Issuer)
+
+func (o optionFunc) apply(i *Issuer) {
+	o(i)
+}
+
+// WithTTL returns an option to issue certificates valid for `ttl`
+// instead of DefaultTTL.
+func WithTTL(ttl time.Duration) Option {
+	return optionFunc(func(i *Issuer) {
+		i.ttl = ttl
+	})
+}
+
+// NewIssuer returns an Issuer signing certificates with the CA `ca` and its
+// key, for identities proved to `verify`.
+func NewIssuer(ca *x509.Certificate, key crypto.Signer, verify TokenVerifier, opts ...Option) (*Issuer, error) {
+	if !ca.IsCA {
+		return nil, errors.New("issuer certificate isn't a CA")
+	}
+	if verify == nil {
+		return nil, errors.New("a token verifier is required")
+	}
+	i := &Issuer{
+		ca:     ca,
+		key:    key,
+		verify: verify,
+		ttl:    DefaultTTL,
+	}
+	for _, o := range opts {
+		o.apply(i)
+	}
+	if i.ttl <= 0 {
+		return nil, errors.New("ttl must be positive")
+	}
+	return i, nil
+}
+
+// LoadIssuer is NewIssuer with the CA certificate and key read from PEM
+// files.
+func LoadIssuer(certFile, keyFile string, verify TokenVerifier, opts ...Option) (*Issuer, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load CA: %w", err)
+	}
+	ca, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("could not parse CA: %w", err)
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported CA key type %T", pair.PrivateKey)
+	}
+	return NewIssuer(ca, key, verify, opts...)
+}
+
+// Issue returns a DER encoded certificate for `identity` with the key
+// from `csr`, which must be correctly signed.
+func (i *Issuer) Issue(csr *x509.CertificateRequest, identity string) ([]byte, error) {
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid CSR signature: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: identity},
+		NotBefore:    now.Add(-backdate),
+		NotAfter:     now.Add(i.ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	switch {
+	case strings.Contains(identity, "://"):
+		u, err := url.Parse(identity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URI identity %q: %w", identity, err)
+		}
+		tmpl.URIs = []*url.URL{u}
+	case strings.Contains(identity, "@"):
+		tmpl.EmailAddresses = []string{identity}
+	}
+	return x509.CreateCertificate(rand.Reader, tmpl, i.ca, csr.PublicKey, i.key)
+}
+
+// ServeHTTP implements http.Handler. It expects a POST of a PEM encoded CSR
+// with an Authorization header carrying a bearer token, and responds with
+// the PEM encoded certificate followed by the CA certificate.
+func (i *Issuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, bearerPrefix) {
+		http.Error(w, "bearer token required", http.StatusUnauthorized)
+		return
+	}
+	identity, err := i.verify(r.Context(), strings.TrimPrefix(auth, bearerPrefix))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid bearer token: %v", err), http.StatusUnauthorized)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxBodySize {
+		http.Error(w, "CSR too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	block, _ := pem.Decode(body)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		http.Error(w, "body must be a PEM encoded CERTIFICATE REQUEST", http.StatusBadRequest)
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid CSR: %v", err), http.StatusBadRequest)
+		return
+	}
+	der, err := i.Issue(csr, identity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chain := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: i.ca.Raw})...)
+	w.Header().Set("Content-Type", ContentType)
+	w.Write(chain)
+}
+
+// Enroll generates a new ECDSA P-256 key and requests a certificate for it
+// from the Issuer at `url` with `token`. It returns the PEM encoded
+// certificate chain and key, suitable for tls.X509KeyPair.
+func Enroll(ctx context.Context, client *http.Client, url string, token string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create CSR: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}))))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("Authorization", bearerPrefix+token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("enrollment failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	if _, err := tls.X509KeyPair(body, keyPEM); err != nil {
+		return nil, nil, fmt.Errorf("invalid certificate returned: %w", err)
+	}
+	return body, keyPEM, nil
+}
+
+// EnrollToFiles calls Enroll and writes the certificate and key to
+// `certFile` and `keyFile`. The key is only readable by the owner.
+func EnrollToFiles(ctx context.Context, client *http.Client, url string, token string, certFile string, keyFile string) error {
+	cert, key, err := Enroll(ctx, client, url, token)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyFile, key, 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(certFile, cert, 0644)
+}
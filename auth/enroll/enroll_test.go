@@ -0,0 +1,305 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package enroll
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const testToken = "good-token"
+
+func testVerifier(identity string) TokenVerifier {
+	return func(_ context.Context, token string) (string, error) {
+		if token != testToken {
+			return "", errors.New("bad token")
+		}
+		return identity, nil
+	}
+}
+
+func newTestCA(t *testing.T, isCA bool) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "enroll CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	testutil.FatalOnErr("CreateCertificate", err, t)
+	ca, err := x509.ParseCertificate(der)
+	testutil.FatalOnErr("ParseCertificate", err, t)
+	return ca, key
+}
+
+func TestNewIssuer(t *testing.T) {
+	ca, key := newTestCA(t, true)
+	notCA, notCAKey := newTestCA(t, false)
+	for _, tc := range []struct {
+		name    string
+		ca      *x509.Certificate
+		key     *ecdsa.PrivateKey
+		verify  TokenVerifier
+		opts    []Option
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			ca:     ca,
+			key:    key,
+			verify: testVerifier("alice"),
+		},
+		{
+			name:    "not a CA",
+			ca:      notCA,
+			key:     notCAKey,
+			verify:  testVerifier("alice"),
+			wantErr: true,
+		},
+		{
+			name:    "no verifier",
+			ca:      ca,
+			key:     key,
+			wantErr: true,
+		},
+		{
+			name:    "bad ttl",
+			ca:      ca,
+			key:     key,
+			verify:  testVerifier("alice"),
+			opts:    []Option{WithTTL(-time.Hour)},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewIssuer(tc.ca, tc.key, tc.verify, tc.opts...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewIssuer: got error %v, wantErr %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnroll(t *testing.T) {
+	ca, key := newTestCA(t, true)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	for _, tc := range []struct {
+		name      string
+		identity  string
+		ttl       time.Duration
+		wantURI   string
+		wantEmail string
+	}{
+		{
+			name:     "plain identity",
+			identity: "alice",
+			ttl:      time.Hour,
+		},
+		{
+			name:     "spiffe identity",
+			identity: "spiffe://example.com/deployer",
+			ttl:      DefaultTTL,
+			wantURI:  "spiffe://example.com/deployer",
+		},
+		{
+			name:      "email identity",
+			identity:  "alice@example.com",
+			ttl:       30 * time.Minute,
+			wantEmail: "alice@example.com",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			issuer, err := NewIssuer(ca, key, testVerifier(tc.identity), WithTTL(tc.ttl))
+			testutil.FatalOnErr("NewIssuer", err, t)
+			srv := httptest.NewServer(issuer)
+			defer srv.Close()
+
+			certPEM, keyPEM, err := Enroll(context.Background(), srv.Client(), srv.URL, testToken)
+			testutil.FatalOnErr("Enroll", err, t)
+			pair, err := tls.X509KeyPair(certPEM, keyPEM)
+			testutil.FatalOnErr("X509KeyPair", err, t)
+			if len(pair.Certificate) != 2 {
+				t.Fatalf("got %d certificates, want the leaf and CA", len(pair.Certificate))
+			}
+			cert, err := x509.ParseCertificate(pair.Certificate[0])
+			testutil.FatalOnErr("ParseCertificate", err, t)
+
+			if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+				t.Errorf("issued certificate doesn't verify as a client certificate: %v", err)
+			}
+			if got := cert.Subject.CommonName; got != tc.identity {
+				t.Errorf("CommonName = %q, want %q", got, tc.identity)
+			}
+			var gotURI, gotEmail string
+			if len(cert.URIs) > 0 {
+				gotURI = cert.URIs[0].String()
+			}
+			if len(cert.EmailAddresses) > 0 {
+				gotEmail = cert.EmailAddresses[0]
+			}
+			if gotURI != tc.wantURI {
+				t.Errorf("URI SAN = %q, want %q", gotURI, tc.wantURI)
+			}
+			if gotEmail != tc.wantEmail {
+				t.Errorf("email SAN = %q, want %q", gotEmail, tc.wantEmail)
+			}
+			if got := cert.NotAfter.Sub(cert.NotBefore); got != tc.ttl+backdate {
+				t.Errorf("certificate valid for %v, want %v", got, tc.ttl+backdate)
+			}
+		})
+	}
+}
+
+func TestServeHTTPErrors(t *testing.T) {
+	ca, key := newTestCA(t, true)
+	issuer, err := NewIssuer(ca, key, testVerifier("alice"))
+	testutil.FatalOnErr("NewIssuer", err, t)
+	srv := httptest.NewServer(issuer)
+	defer srv.Close()
+
+	csrKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, csrKey)
+	testutil.FatalOnErr("CreateCertificateRequest", err, t)
+	csrPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}))
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		auth   string
+		body   string
+		want   int
+	}{
+		{
+			name:   "valid",
+			method: http.MethodPost,
+			auth:   "Bearer " + testToken,
+			body:   csrPEM,
+			want:   http.StatusOK,
+		},
+		{
+			name:   "GET",
+			method: http.MethodGet,
+			auth:   "Bearer " + testToken,
+			want:   http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "no token",
+			method: http.MethodPost,
+			body:   csrPEM,
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "bad token",
+			method: http.MethodPost,
+			auth:   "Bearer bad-token",
+			body:   csrPEM,
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "not PEM",
+			method: http.MethodPost,
+			auth:   "Bearer " + testToken,
+			body:   "hello",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "wrong PEM type",
+			method: http.MethodPost,
+			auth:   "Bearer " + testToken,
+			body:   string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})),
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "too large",
+			method: http.MethodPost,
+			auth:   "Bearer " + testToken,
+			body:   strings.Repeat("x", maxBodySize+1),
+			want:   http.StatusRequestEntityTooLarge,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, srv.URL, strings.NewReader(tc.body))
+			testutil.FatalOnErr("NewRequest", err, t)
+			if tc.auth != "" {
+				req.Header.Set("Authorization", tc.auth)
+			}
+			resp, err := srv.Client().Do(req)
+			testutil.FatalOnErr("Do", err, t)
+			resp.Body.Close()
+			if resp.StatusCode != tc.want {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tc.want)
+			}
+		})
+	}
+}
+
+func TestEnrollToFiles(t *testing.T) {
+	ca, key := newTestCA(t, true)
+	issuer, err := NewIssuer(ca, key, testVerifier("alice"))
+	testutil.FatalOnErr("NewIssuer", err, t)
+	srv := httptest.NewServer(issuer)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := EnrollToFiles(context.Background(), srv.Client(), srv.URL, "bad-token", certFile, keyFile); err == nil {
+		t.Fatal("enrolled with a bad token")
+	}
+	if _, err := os.Stat(keyFile); !os.IsNotExist(err) {
+		t.Errorf("key written after failed enrollment: %v", err)
+	}
+
+	err = EnrollToFiles(context.Background(), srv.Client(), srv.URL, testToken, certFile, keyFile)
+	testutil.FatalOnErr("EnrollToFiles", err, t)
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		t.Errorf("can't load enrolled key pair: %v", err)
+	}
+	fi, err := os.Stat(keyFile)
+	testutil.FatalOnErr("Stat", err, t)
+	if got := fi.Mode().Perm(); got != 0600 {
+		t.Errorf("key mode = %v, want 0600", got)
+	}
+}
@@ -0,0 +1,173 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package grants provides time limited access grants (i.e. for break-glass
+// access) to policies. A grants document is JSON mapping principals (see
+// rpcauth.PrincipalID) to the methods they're granted and when each grant
+// expires, in RFC 3339 format:
+//
+//	{
+//	  "alice": {"/Exec.Exec/Run": "2022-06-01T12:00:00Z"},
+//	  "bob": {"*": "2022-06-01T08:00:00Z"}
+//	}
+//
+// Hook adds the caller's unexpired grants to the policy input, so policies
+// can allow them with i.e.
+//
+//	allow {
+//	  input.grants[input.method]
+//	}
+//
+// What the method keys mean (i.e. "*" above) is entirely up to the policy.
+// Grants stop applying once they expire even if the document isn't reloaded.
+package grants
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+const (
+	// DefaultRefreshInterval is how often Poll reloads grants by default.
+	DefaultRefreshInterval = time.Minute
+
+	// Documents larger than this are rejected.
+	maxSize = 16 * 1024 * 1024
+)
+
+// Grants maps principals to the expiry times of their grants, keyed by method.
+type Grants map[string]map[string]time.Time
+
+// Parse decodes a grants document.
+func Parse(b []byte) (Grants, error) {
+	var g Grants
+	if err := json.Unmarshal(b, &g); err != nil {
+		return nil, fmt.Errorf("can't parse grants: %w", err)
+	}
+	return g, nil
+}
+
+// A Store holds the current grants. The zero value has no grants.
+type Store struct {
+	mu     sync.RWMutex
+	grants Grants
+}
+
+// Set replaces the grants in the store.
+func (s *Store) Set(g Grants) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grants = g
+}
+
+// Active returns the grants of `principal` which haven't expired at `now`,
+// or nil if there are none.
+func (s *Store) Active(principal string, now time.Time) map[string]time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out map[string]time.Time
+	for method, expires := range s.grants[principal] {
+		if !now.Before(expires) {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]time.Time)
+		}
+		out[method] = expires
+	}
+	return out
+}
+
+// Hook returns an RPCAuthzHook which sets input.Grants to the active grants
+// in `s` of the principal making the request.
+func Hook(s *Store) rpcauth.RPCAuthzHook {
+	return rpcauth.RPCAuthzHookFunc(func(ctx context.Context, input *rpcauth.RPCAuthInput) error {
+		principal := rpcauth.PrincipalID(input)
+		if principal == "" {
+			return nil
+		}
+		input.Grants = s.Active(principal, time.Now())
+		return nil
+	})
+}
+
+// Load reads a grants document from `source`, which is either an HTTP(S)
+// URL or a file name.
+func Load(ctx context.Context, client *http.Client, source string) (Grants, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		b, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+		return Parse(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("can't fetch grants from %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching grants from %s returned %s", source, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("can't read grants from %s: %w", source, err)
+	}
+	if len(b) > maxSize {
+		return nil, fmt.Errorf("grants from %s are larger than %d bytes", source, maxSize)
+	}
+	return Parse(b)
+}
+
+// Poll reloads the grants in `s` from `source` (see Load) every `interval`
+// until `ctx` is done. Errors are logged and the existing grants are left in
+// place, so revoking a grant by removing it only takes effect once the
+// document can be loaded again. This blocks so is generally run in its own
+// goroutine.
+func Poll(ctx context.Context, client *http.Client, source string, interval time.Duration, s *Store) {
+	logger := logr.FromContextOrDiscard(ctx).WithValues("source", source)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		g, err := Load(ctx, client, source)
+		if err != nil {
+			logger.Error(err, "grants reload")
+			continue
+		}
+		s.Set(g)
+		logger.V(1).Info("reloaded grants")
+	}
+}
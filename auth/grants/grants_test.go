@@ -0,0 +1,177 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package grants
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const doc = `{
+  "alice": {"/Exec.Exec/Run": "2030-01-01T00:00:00Z", "/Foo/Bar": "2020-01-01T00:00:00Z"},
+  "bob": {"*": "2030-01-01T00:00:00Z"}
+}`
+
+func TestParse(t *testing.T) {
+	g, err := Parse([]byte(doc))
+	testutil.FatalOnErr("Parse", err, t)
+	want := Grants{
+		"alice": {
+			"/Exec.Exec/Run": time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+			"/Foo/Bar":       time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"bob": {"*": time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	testutil.DiffErr("Parse", g, want, t)
+
+	for _, bad := range []string{`[]`, `{"alice": {"/Foo/Bar": "tomorrow"}}`} {
+		_, err := Parse([]byte(bad))
+		testutil.FatalOnNoErr(bad, err, t)
+	}
+}
+
+func TestHook(t *testing.T) {
+	ctx := context.Background()
+	g, err := Parse([]byte(doc))
+	testutil.FatalOnErr("Parse", err, t)
+	s := &Store{}
+	s.Set(g)
+	hook := Hook(s)
+
+	for _, tc := range []struct {
+		name      string
+		principal string
+		want      map[string]time.Time
+	}{
+		{
+			name:      "expired grants are dropped",
+			principal: "alice",
+			want:      map[string]time.Time{"/Exec.Exec/Run": time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:      "wildcard",
+			principal: "bob",
+			want:      map[string]time.Time{"*": time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:      "no grants",
+			principal: "carol",
+		},
+		{
+			name: "no principal",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := &rpcauth.RPCAuthInput{Peer: &rpcauth.PeerAuthInput{Principal: &rpcauth.PrincipalAuthInput{ID: tc.principal}}}
+			testutil.FatalOnErr("Hook", hook.Hook(ctx, input), t)
+			testutil.DiffErr("grants", input.Grants, tc.want, t)
+		})
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	ctx := context.Background()
+	g, err := Parse([]byte(doc))
+	testutil.FatalOnErr("Parse", err, t)
+	s := &Store{}
+	s.Set(g)
+	authz, err := rpcauth.NewWithPolicy(ctx, `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.grants[input.method]
+}
+`, rpcauth.RPCAuthzHookFunc(func(ctx context.Context, input *rpcauth.RPCAuthInput) error {
+		input.Peer = &rpcauth.PeerAuthInput{Principal: &rpcauth.PrincipalAuthInput{ID: "alice"}}
+		return nil
+	}), Hook(s))
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+
+	testutil.FatalOnErr("granted", authz.Eval(ctx, &rpcauth.RPCAuthInput{Method: "/Exec.Exec/Run"}), t)
+	testutil.FatalOnNoErr("expired", authz.Eval(ctx, &rpcauth.RPCAuthInput{Method: "/Foo/Bar"}), t)
+}
+
+func TestLoadAndPoll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	file := filepath.Join(t.TempDir(), "grants.json")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(file, []byte(doc), 0644), t)
+	g, err := Load(ctx, http.DefaultClient, file)
+	testutil.FatalOnErr("Load file", err, t)
+	if len(g) != 2 {
+		t.Fatalf("Load(%s) = %v, want 2 principals", file, g)
+	}
+	_, err = Load(ctx, http.DefaultClient, file+".missing")
+	testutil.FatalOnNoErr("Load missing file", err, t)
+
+	var mu sync.Mutex
+	body := doc
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if body == "" {
+			http.Error(w, "gone", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	g, err = Load(ctx, srv.Client(), srv.URL)
+	testutil.FatalOnErr("Load URL", err, t)
+	s := &Store{}
+	s.Set(g)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if s.Active("bob", now) == nil {
+		t.Fatal("bob has no grants")
+	}
+
+	// Failed reloads keep the existing grants.
+	mu.Lock()
+	body = ""
+	mu.Unlock()
+	go Poll(ctx, srv.Client(), srv.URL, 10*time.Millisecond, s)
+	time.Sleep(50 * time.Millisecond)
+	if s.Active("bob", now) == nil {
+		t.Fatal("bob lost grants after failed reload")
+	}
+
+	mu.Lock()
+	body = `{"carol": {"*": "2030-01-01T00:00:00Z"}}`
+	mu.Unlock()
+	for i := 0; s.Active("carol", now) == nil; i++ {
+		if i > 100 {
+			t.Fatal("grants weren't reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if s.Active("bob", now) != nil {
+		t.Error("bob still has grants after reload")
+	}
+}
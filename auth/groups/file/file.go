@@ -0,0 +1,118 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package file provides a groups.Provider reading group membership from a
+// JSON file mapping group names to their members, i.e.
+//
+//	{
+//	  "sre": ["alice", "bob"],
+//	  "deployers": ["spiffe://example.com/deployer"]
+//	}
+//
+// Importing this package registers a provider named "file" reading the file
+// given by --groups-file. The file is re-read whenever it changes.
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/auth/groups"
+)
+
+const providerName = "file"
+
+var flagFile string
+
+// Name returns the name of the provider configured by flags.
+func Name() string { return providerName }
+
+// provider implements groups.Provider for a file.
+type provider struct {
+	// filename is a pointer so the flag value can be used once parsed.
+	filename *string
+
+	mu      sync.Mutex
+	file    string
+	modTime time.Time
+	size    int64
+	members map[string][]string
+}
+
+// New returns a provider reading group membership from `filename`.
+func New(filename string) groups.Provider {
+	return &provider{filename: &filename}
+}
+
+// load re-reads the file if it has changed. Must be called with p.mu held.
+func (p *provider) load() error {
+	file := *p.filename
+	if file == "" {
+		return errors.New("no groups file configured")
+	}
+	fi, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	if file == p.file && fi.ModTime().Equal(p.modTime) && fi.Size() == p.size && p.members != nil {
+		return nil
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var g map[string][]string
+	if err := json.Unmarshal(b, &g); err != nil {
+		return fmt.Errorf("can't parse groups file %s: %w", file, err)
+	}
+	members := make(map[string][]string)
+	for group, m := range g {
+		for _, principal := range m {
+			members[principal] = append(members[principal], group)
+		}
+	}
+	for _, groups := range members {
+		sort.Strings(groups)
+	}
+	p.file = file
+	p.modTime = fi.ModTime()
+	p.size = fi.Size()
+	p.members = members
+	return nil
+}
+
+func (p *provider) Groups(ctx context.Context, principal string) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+	return p.members[principal], nil
+}
+
+func init() {
+	flag.StringVar(&flagFile, "groups-file", "", "JSON file mapping group names to lists of members, for the file group provider.")
+	if err := groups.Register(providerName, &provider{filename: &flagFile}); err != nil {
+		panic(err)
+	}
+}
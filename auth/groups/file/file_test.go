@@ -0,0 +1,74 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "groups.json")
+	write := func(contents string, mod time.Time) {
+		t.Helper()
+		testutil.FatalOnErr("WriteFile", os.WriteFile(filename, []byte(contents), 0644), t)
+		testutil.FatalOnErr("Chtimes", os.Chtimes(filename, mod, mod), t)
+	}
+	check := func(principal string, want []string) {
+		t.Helper()
+		got, err := New(filename).Groups(ctx, principal)
+		testutil.FatalOnErr("Groups", err, t)
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Groups(%s) diff (-want +got):\n%s", principal, diff)
+		}
+	}
+
+	now := time.Now()
+	write(`{"sre": ["alice", "bob"], "dev": ["alice"]}`, now.Add(-time.Hour))
+	p := New(filename)
+	got, err := p.Groups(ctx, "alice")
+	testutil.FatalOnErr("Groups", err, t)
+	if diff := cmp.Diff([]string{"dev", "sre"}, got); diff != "" {
+		t.Errorf("Groups(alice) diff (-want +got):\n%s", diff)
+	}
+	check("bob", []string{"sre"})
+	check("carol", nil)
+
+	// Changes are picked up.
+	write(`{"sre": ["carol"]}`, now)
+	got, err = p.Groups(ctx, "carol")
+	testutil.FatalOnErr("Groups", err, t)
+	if diff := cmp.Diff([]string{"sre"}, got); diff != "" {
+		t.Errorf("Groups(carol) after update diff (-want +got):\n%s", diff)
+	}
+
+	write("not json", now.Add(time.Hour))
+	_, err = p.Groups(ctx, "carol")
+	testutil.FatalOnNoErr("Groups with bad file", err, t)
+	_, err = New(filepath.Join(t.TempDir(), "missing")).Groups(ctx, "carol")
+	testutil.FatalOnNoErr("Groups with missing file", err, t)
+	_, err = New("").Groups(ctx, "carol")
+	testutil.FatalOnNoErr("Groups with no file", err, t)
+}
@@ -0,0 +1,189 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package google provides a groups.Provider returning the Google groups
+// a principal is (transitively) a member of, using the Cloud Identity API.
+//
+// Importing this package registers a provider named "google" configured with
+// the --google-groups-* flags. Groups are returned by email address. The
+// caller needs the Groups Reader role (or equivalent) and an access token
+// with the cloud-identity.groups.readonly scope, which by default is fetched
+// from the GCE metadata server.
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/auth/groups"
+)
+
+const (
+	providerName = "google"
+
+	// DefaultEndpoint is the Cloud Identity API.
+	DefaultEndpoint = "https://cloudidentity.googleapis.com"
+
+	metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+	// Responses larger than this are rejected.
+	maxResponseSize = 1024 * 1024
+)
+
+// Config describes how to query Cloud Identity.
+type Config struct {
+	// Endpoint of the API. Defaults to DefaultEndpoint.
+	Endpoint string
+	// Domain if set is appended (after an @) to principals which aren't
+	// email addresses.
+	Domain string
+	// TokenFile if set contains the access token to use. It's re-read for
+	// every lookup. Otherwise a token is fetched from the metadata server.
+	TokenFile string
+	// HTTPClient if set is used for all requests.
+	HTTPClient *http.Client
+}
+
+var flagConfig = &Config{}
+
+// Name returns the name of the provider configured by flags.
+func Name() string { return providerName }
+
+// provider implements groups.Provider with Cloud Identity.
+type provider struct {
+	cfg *Config
+}
+
+// New returns a provider using the settings in `cfg`.
+func New(cfg *Config) groups.Provider {
+	return &provider{cfg: cfg}
+}
+
+func (p *provider) client() *http.Client {
+	if p.cfg.HTTPClient != nil {
+		return p.cfg.HTTPClient
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// get fetches url and decodes the JSON response into v.
+func (p *provider) get(ctx context.Context, url string, header http.Header, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, vals := range header {
+		req.Header[k] = vals
+	}
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", req.URL.Path, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v)
+}
+
+// token returns the access token to call the API with.
+func (p *provider) token(ctx context.Context) (string, error) {
+	if p.cfg.TokenFile != "" {
+		b, err := os.ReadFile(p.cfg.TokenFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	var t struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := p.get(ctx, metadataTokenURL, http.Header{"Metadata-Flavor": {"Google"}}, &t); err != nil {
+		return "", fmt.Errorf("can't get token from metadata server: %w", err)
+	}
+	if t.AccessToken == "" {
+		return "", errors.New("metadata server returned no token")
+	}
+	return t.AccessToken, nil
+}
+
+func (p *provider) Groups(ctx context.Context, principal string) ([]string, error) {
+	if !strings.Contains(principal, "@") {
+		if p.cfg.Domain == "" {
+			// Can't be a Google account.
+			return nil, nil
+		}
+		principal = principal + "@" + p.cfg.Domain
+	}
+	token, err := p.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := p.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	query := fmt.Sprintf("member_key_id == '%s' && 'cloudidentity.googleapis.com/groups.discussion_forum' in labels", strings.ReplaceAll(principal, "'", `\'`))
+	header := http.Header{"Authorization": {"Bearer " + token}}
+	var out []string
+	pageToken := ""
+	for {
+		v := url.Values{"query": {query}}
+		if pageToken != "" {
+			v.Set("pageToken", pageToken)
+		}
+		var resp struct {
+			Memberships []struct {
+				GroupKey struct {
+					ID string `json:"id"`
+				} `json:"groupKey"`
+			} `json:"memberships"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		u := strings.TrimSuffix(endpoint, "/") + "/v1/groups/-/memberships:searchTransitiveGroups?" + v.Encode()
+		if err := p.get(ctx, u, header, &resp); err != nil {
+			return nil, fmt.Errorf("searching groups of %s: %w", principal, err)
+		}
+		for _, m := range resp.Memberships {
+			if m.GroupKey.ID != "" {
+				out = append(out, m.GroupKey.ID)
+			}
+		}
+		if resp.NextPageToken == "" {
+			return out, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func init() {
+	flag.StringVar(&flagConfig.Endpoint, "google-groups-endpoint", DefaultEndpoint, "Cloud Identity API endpoint for the google group provider")
+	flag.StringVar(&flagConfig.Domain, "google-groups-domain", "", "Domain appended to principals which aren't email addresses when looking up their Google groups")
+	flag.StringVar(&flagConfig.TokenFile, "google-groups-token-file", "", "File containing an access token for the Cloud Identity API. If empty one is fetched from the GCE metadata server.")
+
+	if err := groups.Register(providerName, New(flagConfig)); err != nil {
+		panic(err)
+	}
+}
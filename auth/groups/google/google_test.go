@@ -0,0 +1,111 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	memberships := map[string][][]string{
+		// Two pages of groups.
+		"alice@example.com": {{"sre@example.com", "dev@example.com"}, {"all@example.com"}},
+	}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v1/groups/-/memberships:searchTransitiveGroups" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		query := r.URL.Query().Get("query")
+		var pages [][]string
+		for member, p := range memberships {
+			if strings.Contains(query, "member_key_id == '"+member+"'") {
+				pages = p
+			}
+		}
+		page := 0
+		if r.URL.Query().Get("pageToken") == "next" {
+			page = 1
+		}
+		type groupKey struct {
+			ID string `json:"id"`
+		}
+		type membership struct {
+			GroupKey groupKey `json:"groupKey"`
+		}
+		resp := struct {
+			Memberships   []membership `json:"memberships"`
+			NextPageToken string       `json:"nextPageToken,omitempty"`
+		}{}
+		if page < len(pages) {
+			for _, g := range pages[page] {
+				resp.Memberships = append(resp.Memberships, membership{GroupKey: groupKey{ID: g}})
+			}
+			if page+1 < len(pages) {
+				resp.NextPageToken = "next"
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(s.Close)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(tokenFile, []byte("token\n"), 0600), t)
+
+	p := New(&Config{Endpoint: s.URL, Domain: "example.com", TokenFile: tokenFile})
+	for _, tc := range []struct {
+		principal string
+		want      []string
+	}{
+		{"alice@example.com", []string{"sre@example.com", "dev@example.com", "all@example.com"}},
+		{"alice", []string{"sre@example.com", "dev@example.com", "all@example.com"}},
+		{"bob@example.com", nil},
+	} {
+		got, err := p.Groups(ctx, tc.principal)
+		testutil.FatalOnErr("Groups "+tc.principal, err, t)
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("Groups(%s) diff (-want +got):\n%s", tc.principal, diff)
+		}
+	}
+
+	// Without a domain principals which aren't emails have no groups.
+	got, err := New(&Config{Endpoint: s.URL, TokenFile: tokenFile}).Groups(ctx, "alice")
+	testutil.FatalOnErr("Groups without domain", err, t)
+	if got != nil {
+		t.Errorf("Groups without domain = %v, want none", got)
+	}
+
+	badToken := filepath.Join(t.TempDir(), "bad")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(badToken, []byte("bad"), 0600), t)
+	_, err = New(&Config{Endpoint: s.URL, TokenFile: badToken}).Groups(ctx, "alice@example.com")
+	testutil.FatalOnNoErr("Groups with bad token", err, t)
+}
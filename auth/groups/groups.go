@@ -0,0 +1,204 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package groups resolves the authenticated principal of an RPC to the
+// groups it belongs to (i.e. from LDAP or a file) so policies can be written
+// against groups rather than individual identities:
+//
+//	allow {
+//	  "sre" in input.peer.principal.groups
+//	}
+//
+// Providers register themselves by name, typically in init() of a package
+// such as groups/file, and one is selected with Lookup. Hook then returns an
+// rpcauth.RPCAuthzHook adding the principal's groups to the policy input.
+package groups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+// A Provider returns the groups a principal belongs to.
+type Provider interface {
+	// Groups returns the groups `principal` is a member of. Unknown
+	// principals have no groups, which isn't an error.
+	Groups(ctx context.Context, principal string) ([]string, error)
+}
+
+// ProviderFunc is a func adapter for Provider
+type ProviderFunc func(context.Context, string) ([]string, error)
+
+// Groups implements Provider.Groups
+func (p ProviderFunc) Groups(ctx context.Context, principal string) ([]string, error) {
+	return p(ctx, principal)
+}
+
+var (
+	providerMu sync.RWMutex
+	providers  = make(map[string]Provider)
+)
+
+// Register associates a name with a Provider. Implementations will
+// typically call Register during init()
+func Register(name string, p Provider) error {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	if p == nil {
+		return errors.New("provider cannot be nil")
+	}
+	if _, exists := providers[name]; exists {
+		return errors.New("duplicate registration of group provider with name: " + name)
+	}
+	providers[name] = p
+	return nil
+}
+
+// Lookup returns the Provider registered as `name` or an error if there
+// isn't one.
+func Lookup(name string) (Provider, error) {
+	providerMu.RLock()
+	p, ok := providers[name]
+	providerMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown group provider %s", name)
+	}
+	return p, nil
+}
+
+// Providers returns the names of all registered providers as a sorted list.
+func Providers() []string {
+	providerMu.RLock()
+	defer providerMu.RUnlock()
+	var out []string
+	for p := range providers {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Principal returns the identity groups are looked up for. See
+// rpcauth.PrincipalID.
+func Principal(input *rpcauth.RPCAuthInput) string {
+	return rpcauth.PrincipalID(input)
+}
+
+// Hook returns an RPCAuthzHook which looks up the groups of the principal
+// (see Principal) with `p` and adds them to input.Peer.Principal.Groups,
+// setting the principal ID if it wasn't already. Requests are rejected with
+// Unavailable if the lookup fails, so a policy denying by group can't be
+// bypassed by an outage.
+func Hook(p Provider) rpcauth.RPCAuthzHook {
+	return rpcauth.RPCAuthzHookFunc(func(ctx context.Context, input *rpcauth.RPCAuthInput) error {
+		principal := Principal(input)
+		if principal == "" {
+			return nil
+		}
+		groups, err := p.Groups(ctx, principal)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "group lookup for %s failed: %v", principal, err)
+		}
+		if input.Peer.Principal == nil {
+			input.Peer.Principal = &rpcauth.PrincipalAuthInput{ID: principal}
+		}
+		input.Peer.Principal.Groups = merge(input.Peer.Principal.Groups, groups)
+		return nil
+	})
+}
+
+// merge returns the sorted union of a and b.
+func merge(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	seen := make(map[string]bool)
+	var out []string
+	for _, l := range [][]string{a, b} {
+		for _, g := range l {
+			if !seen[g] {
+				seen[g] = true
+				out = append(out, g)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// cached is a Provider remembering results of another for a time.
+type cached struct {
+	p   Provider
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	groups  []string
+	expires time.Time
+}
+
+// Cached returns a Provider which remembers the groups returned by `p` for
+// each principal for `ttl`, so slow or remote providers aren't queried for
+// every RPC. Errors aren't cached.
+func Cached(p Provider, ttl time.Duration) Provider {
+	return &cached{
+		p:       p,
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+func (c *cached) Groups(ctx context.Context, principal string) ([]string, error) {
+	now := time.Now()
+	c.mu.Lock()
+	e, ok := c.entries[principal]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.groups, nil
+	}
+	groups, err := c.p.Groups(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[principal] = cacheEntry{groups: groups, expires: now.Add(c.ttl)}
+	return groups, nil
+}
+
+// for testing
+func unregisterAll() {
+	providerMu.Lock()
+	providers = make(map[string]Provider)
+	providerMu.Unlock()
+}
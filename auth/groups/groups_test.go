@@ -0,0 +1,164 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package groups
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+var testGroups = map[string][]string{
+	"alice":                     {"sre", "dev"},
+	"spiffe://example.com/bob":  {"deployers"},
+	"carol":                     nil,
+	"spiffe://example.com/fail": nil,
+}
+
+var testProvider = ProviderFunc(func(ctx context.Context, principal string) ([]string, error) {
+	if principal == "spiffe://example.com/fail" {
+		return nil, errors.New("lookup failed")
+	}
+	return testGroups[principal], nil
+})
+
+func TestRegister(t *testing.T) {
+	unregisterAll()
+	t.Cleanup(unregisterAll)
+	testutil.FatalOnErr("Register", Register("test", testProvider), t)
+	testutil.FatalOnNoErr("duplicate Register", Register("test", testProvider), t)
+	testutil.FatalOnNoErr("nil Register", Register("nil", nil), t)
+	_, err := Lookup("test")
+	testutil.FatalOnErr("Lookup", err, t)
+	_, err = Lookup("missing")
+	testutil.FatalOnNoErr("Lookup missing", err, t)
+	if diff := cmp.Diff([]string{"test"}, Providers()); diff != "" {
+		t.Errorf("Providers() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestHook(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name     string
+		peer     *rpcauth.PeerAuthInput
+		want     *rpcauth.PrincipalAuthInput
+		wantCode codes.Code
+	}{
+		{
+			name: "no peer",
+		},
+		{
+			name: "cert common name",
+			peer: &rpcauth.PeerAuthInput{Cert: &rpcauth.CertAuthInput{Subject: pkix.Name{CommonName: "alice"}}},
+			want: &rpcauth.PrincipalAuthInput{ID: "alice", Groups: []string{"dev", "sre"}},
+		},
+		{
+			name: "spiffe id",
+			peer: &rpcauth.PeerAuthInput{Cert: &rpcauth.CertAuthInput{Subject: pkix.Name{CommonName: "alice"}, SPIFFEID: "spiffe://example.com/bob"}},
+			want: &rpcauth.PrincipalAuthInput{ID: "spiffe://example.com/bob", Groups: []string{"deployers"}},
+		},
+		{
+			name: "existing principal merged",
+			peer: &rpcauth.PeerAuthInput{Principal: &rpcauth.PrincipalAuthInput{ID: "alice", Groups: []string{"token-group", "sre"}}},
+			want: &rpcauth.PrincipalAuthInput{ID: "alice", Groups: []string{"dev", "sre", "token-group"}},
+		},
+		{
+			name: "no groups",
+			peer: &rpcauth.PeerAuthInput{Cert: &rpcauth.CertAuthInput{Subject: pkix.Name{CommonName: "carol"}}},
+			want: &rpcauth.PrincipalAuthInput{ID: "carol"},
+		},
+		{
+			name:     "lookup failure",
+			peer:     &rpcauth.PeerAuthInput{Cert: &rpcauth.CertAuthInput{SPIFFEID: "spiffe://example.com/fail"}},
+			wantCode: codes.Unavailable,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := &rpcauth.RPCAuthInput{Method: "/Foo.Bar/Baz", Peer: tc.peer}
+			err := Hook(testProvider).Hook(ctx, input)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Hook() = %v, want code %v", err, tc.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			var got *rpcauth.PrincipalAuthInput
+			if input.Peer != nil {
+				got = input.Peer.Principal
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("principal diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCached(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	fail := false
+	p := Cached(ProviderFunc(func(ctx context.Context, principal string) ([]string, error) {
+		calls++
+		if fail {
+			return nil, errors.New("lookup failed")
+		}
+		return testGroups[principal], nil
+	}), time.Hour)
+	for i := 0; i < 3; i++ {
+		got, err := p.Groups(ctx, "alice")
+		testutil.FatalOnErr("Groups", err, t)
+		if diff := cmp.Diff(testGroups["alice"], got); diff != "" {
+			t.Errorf("Groups diff (-want +got):\n%s", diff)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("provider called %d times, want 1", calls)
+	}
+
+	// Errors aren't cached.
+	fail = true
+	for i := 0; i < 2; i++ {
+		_, err := p.Groups(ctx, "bob")
+		testutil.FatalOnNoErr("Groups", err, t)
+	}
+	if calls != 3 {
+		t.Errorf("provider called %d times, want 3", calls)
+	}
+
+	// Nor are results past the TTL.
+	calls = 0
+	fail = false
+	p.(*cached).ttl = 0
+	for i := 0; i < 2; i++ {
+		_, err := p.Groups(ctx, "carol")
+		testutil.FatalOnErr("Groups", err, t)
+	}
+	if calls != 2 {
+		t.Errorf("provider called %d times with zero TTL, want 2", calls)
+	}
+}
@@ -0,0 +1,187 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package ldap provides a groups.Provider which searches an LDAP directory
+// for the groups a principal is a member of.
+//
+// Importing this package registers a provider named "ldap" configured with
+// the --ldap-* flags. Each lookup binds and searches for groups matching
+// --ldap-group-filter with {principal} replaced by the (escaped) principal,
+// returning the --ldap-group-attr attribute of each. Use groups.Cached to
+// avoid a search for every RPC.
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/Snowflake-Labs/sansshell/auth/groups"
+	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+)
+
+const (
+	providerName = "ldap"
+
+	// DefaultGroupFilter matches posixGroup entries listing the principal
+	// as a memberUid.
+	DefaultGroupFilter = "(&(objectClass=posixGroup)(memberUid={principal}))"
+
+	// DefaultGroupAttr is the attribute naming a group.
+	DefaultGroupAttr = "cn"
+
+	principalPlaceholder = "{principal}"
+)
+
+// Config describes the directory to search.
+type Config struct {
+	// URL of the server, i.e. ldaps://ldap.example.com
+	URL string
+	// BindDN if set is the DN to bind as before searching.
+	BindDN string
+	// BindPasswordFile contains the password for BindDN. It's re-read for
+	// every lookup so it can be rotated.
+	BindPasswordFile string
+	// BaseDN to search for groups under.
+	BaseDN string
+	// GroupFilter is the search filter for a principal's groups. It must
+	// contain {principal}. Defaults to DefaultGroupFilter.
+	GroupFilter string
+	// GroupAttr is the attribute returned as the group name. Defaults
+	// to DefaultGroupAttr.
+	GroupAttr string
+	// CAFile if set is used to verify the server instead of the system roots.
+	CAFile string
+	// Timeout for each lookup.
+	Timeout time.Duration
+}
+
+var flagConfig = &Config{}
+
+// Name returns the name of the provider configured by flags.
+func Name() string { return providerName }
+
+// provider implements groups.Provider with LDAP searches.
+type provider struct {
+	cfg *Config
+}
+
+// New returns a provider searching the directory described by `cfg`.
+func New(cfg *Config) groups.Provider {
+	return &provider{cfg: cfg}
+}
+
+// filter returns the group search filter for principal.
+func (p *provider) filter(principal string) (string, error) {
+	f := p.cfg.GroupFilter
+	if f == "" {
+		f = DefaultGroupFilter
+	}
+	if !strings.Contains(f, principalPlaceholder) {
+		return "", fmt.Errorf("group filter %q doesn't contain %s", f, principalPlaceholder)
+	}
+	return strings.ReplaceAll(f, principalPlaceholder, ldap.EscapeFilter(principal)), nil
+}
+
+func (p *provider) dial() (*ldap.Conn, error) {
+	if p.cfg.URL == "" {
+		return nil, errors.New("no LDAP server configured")
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if p.cfg.CAFile != "" {
+		pool, err := mtls.LoadRootOfTrust(p.cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't load LDAP CA: %w", err)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	conn, err := ldap.DialURL(p.cfg.URL, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	if p.cfg.Timeout > 0 {
+		conn.SetTimeout(p.cfg.Timeout)
+	}
+	if p.cfg.BindDN != "" {
+		var password []byte
+		if p.cfg.BindPasswordFile != "" {
+			password, err = os.ReadFile(p.cfg.BindPasswordFile)
+			if err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+		if err := conn.Bind(p.cfg.BindDN, strings.TrimSpace(string(password))); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("LDAP bind as %s: %w", p.cfg.BindDN, err)
+		}
+	}
+	return conn, nil
+}
+
+func (p *provider) Groups(ctx context.Context, principal string) ([]string, error) {
+	filter, err := p.filter(principal)
+	if err != nil {
+		return nil, err
+	}
+	attr := p.cfg.GroupAttr
+	if attr == "" {
+		attr = DefaultGroupAttr
+	}
+	conn, err := p.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var timeLimit int
+	if p.cfg.Timeout > 0 {
+		timeLimit = int(p.cfg.Timeout.Seconds())
+	}
+	req := ldap.NewSearchRequest(p.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, timeLimit, false, filter, []string{attr}, nil)
+	res, err := conn.Search(req)
+	if err != nil {
+		return nil, fmt.Errorf("LDAP search %s: %w", filter, err)
+	}
+	var out []string
+	for _, e := range res.Entries {
+		if v := e.GetAttributeValue(attr); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func init() {
+	flag.StringVar(&flagConfig.URL, "ldap-url", "", "URL of the LDAP server for the ldap group provider, i.e. ldaps://ldap.example.com")
+	flag.StringVar(&flagConfig.BindDN, "ldap-bind-dn", "", "DN to bind to the LDAP server as. If empty binds anonymously.")
+	flag.StringVar(&flagConfig.BindPasswordFile, "ldap-bind-password-file", "", "File containing the password for --ldap-bind-dn")
+	flag.StringVar(&flagConfig.BaseDN, "ldap-base-dn", "", "Base DN to search for groups under")
+	flag.StringVar(&flagConfig.GroupFilter, "ldap-group-filter", DefaultGroupFilter, "LDAP filter matching the groups a principal is a member of. {principal} is replaced with the principal.")
+	flag.StringVar(&flagConfig.GroupAttr, "ldap-group-attr", DefaultGroupAttr, "Attribute of matching entries used as the group name")
+	flag.StringVar(&flagConfig.CAFile, "ldap-ca-file", "", "CA bundle used to verify the LDAP server. If empty the system roots are used.")
+	flag.DurationVar(&flagConfig.Timeout, "ldap-timeout", 10*time.Second, "Timeout for each LDAP lookup")
+
+	if err := groups.Register(providerName, New(flagConfig)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,93 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package ldap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		filter    string
+		principal string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "default",
+			principal: "alice",
+			want:      "(&(objectClass=posixGroup)(memberUid=alice))",
+		},
+		{
+			name:      "escaped",
+			filter:    "(member=uid={principal},ou=people,dc=example,dc=com)",
+			principal: "bob)(uid=*",
+			want:      "(member=uid=bob\\29\\28uid=\\2a,ou=people,dc=example,dc=com)",
+		},
+		{
+			name:      "no placeholder",
+			filter:    "(objectClass=posixGroup)",
+			principal: "alice",
+			wantErr:   true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			p := &provider{cfg: &Config{GroupFilter: tc.filter}}
+			got, err := p.filter(tc.principal)
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if got != tc.want {
+				t.Errorf("filter(%q) = %q, want %q", tc.principal, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGroupsErrors(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name string
+		cfg  *Config
+	}{
+		{
+			name: "no url",
+			cfg:  &Config{},
+		},
+		{
+			name: "bad url",
+			cfg:  &Config{URL: "http://example.com"},
+		},
+		{
+			name: "missing ca",
+			cfg:  &Config{URL: "ldaps://localhost:1", CAFile: "/no/such/file"},
+		},
+		{
+			name: "unreachable",
+			cfg:  &Config{URL: "ldap://localhost:1"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.cfg).Groups(ctx, "alice")
+			testutil.FatalOnNoErr(tc.name, err, t)
+		})
+	}
+}
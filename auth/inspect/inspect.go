@@ -0,0 +1,214 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package inspect decomposes the requests of services which run commands or
+// change the system into normalized fields (see rpcauth.RequestInput), so
+// policies can be written against them rather than the raw message:
+//
+//	allow {
+//	  input.request.binary = "/usr/bin/systemctl"
+//	  input.request.args = ["restart", "nginx.service"]
+//	}
+//
+// Inspectors for the Exec, Packages and Service services are built in, and
+// others can be added with Register. Hook returns an rpcauth.RPCAuthzHook
+// setting input.request for requests with an Inspector.
+package inspect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	execpb "github.com/Snowflake-Labs/sansshell/services/exec"
+	packagespb "github.com/Snowflake-Labs/sansshell/services/packages"
+	servicepb "github.com/Snowflake-Labs/sansshell/services/service"
+)
+
+// An Inspector returns the normalized fields of a request message. It
+// returns an error if the request can't be normalized, which rejects it.
+type Inspector func(proto.Message) (*rpcauth.RequestInput, error)
+
+var (
+	inspectorMu sync.RWMutex
+	inspectors  = make(map[string]Inspector)
+)
+
+// Register associates an Inspector with request messages of type
+// `messageType` ('Package.Message'). Implementations will typically call
+// Register during init()
+func Register(messageType string, i Inspector) error {
+	inspectorMu.Lock()
+	defer inspectorMu.Unlock()
+	if i == nil {
+		return errors.New("inspector cannot be nil")
+	}
+	if _, exists := inspectors[messageType]; exists {
+		return errors.New("duplicate registration of inspector for message type: " + messageType)
+	}
+	inspectors[messageType] = i
+	return nil
+}
+
+// MessageTypes returns the message types with an Inspector as a sorted list.
+func MessageTypes() []string {
+	inspectorMu.RLock()
+	defer inspectorMu.RUnlock()
+	var out []string
+	for t := range inspectors {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func lookup(messageType string) Inspector {
+	inspectorMu.RLock()
+	defer inspectorMu.RUnlock()
+	return inspectors[messageType]
+}
+
+// Inspect returns the normalized fields of `req`, or nil if there's no
+// Inspector for its type.
+func Inspect(req proto.Message) (*rpcauth.RequestInput, error) {
+	i := lookup(string(proto.MessageName(req)))
+	if i == nil {
+		return nil, nil
+	}
+	return i(req)
+}
+
+// Hook returns an RPCAuthzHook setting input.Request for requests with
+// an Inspector. Requests which can't be normalized are rejected with
+// InvalidArgument, so a policy never sees a partial decomposition.
+func Hook() rpcauth.RPCAuthzHook {
+	return rpcauth.RPCAuthzHookFunc(func(ctx context.Context, input *rpcauth.RPCAuthInput) error {
+		i := lookup(input.MessageType)
+		if i == nil || len(input.Message) == 0 {
+			return nil
+		}
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(input.MessageType))
+		if err != nil {
+			return status.Errorf(codes.Internal, "unknown message type %s: %v", input.MessageType, err)
+		}
+		msg := mt.New().Interface()
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(input.Message, msg); err != nil {
+			return status.Errorf(codes.Internal, "can't decode %s: %v", input.MessageType, err)
+		}
+		req, err := i(msg)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid %s: %v", input.MessageType, err)
+		}
+		input.Request = req
+		return nil
+	})
+}
+
+// Unit returns the systemd unit for a service name, which the Service
+// service accepts with or without a .service suffix.
+func Unit(name string) string {
+	if name == "" || strings.HasSuffix(name, ".service") {
+		return name
+	}
+	return name + ".service"
+}
+
+func inspectExec(m proto.Message) (*rpcauth.RequestInput, error) {
+	req := m.(*execpb.ExecRequest)
+	// As services/util.RunCommand requires.
+	if !filepath.IsAbs(req.Command) || filepath.Clean(req.Command) != req.Command {
+		return nil, fmt.Errorf("command %q isn't an absolute, clean path", req.Command)
+	}
+	return &rpcauth.RequestInput{
+		Action: "run",
+		Binary: req.Command,
+		Args:   req.Args,
+	}, nil
+}
+
+func inspectInstall(m proto.Message) (*rpcauth.RequestInput, error) {
+	req := m.(*packagespb.InstallRequest)
+	return &rpcauth.RequestInput{
+		Action:  "install",
+		Package: req.Name,
+		Version: req.Version,
+		Repo:    req.Repo,
+	}, nil
+}
+
+func inspectUpdate(m proto.Message) (*rpcauth.RequestInput, error) {
+	req := m.(*packagespb.UpdateRequest)
+	return &rpcauth.RequestInput{
+		Action:     "update",
+		Package:    req.Name,
+		Version:    req.NewVersion,
+		OldVersion: req.OldVersion,
+		Repo:       req.Repo,
+	}, nil
+}
+
+func inspectStatus(m proto.Message) (*rpcauth.RequestInput, error) {
+	req := m.(*servicepb.StatusRequest)
+	return &rpcauth.RequestInput{
+		Action: "status",
+		Unit:   Unit(req.ServiceName),
+	}, nil
+}
+
+func inspectAction(m proto.Message) (*rpcauth.RequestInput, error) {
+	req := m.(*servicepb.ActionRequest)
+	var action string
+	switch req.Action {
+	case servicepb.Action_ACTION_START:
+		action = "start"
+	case servicepb.Action_ACTION_STOP:
+		action = "stop"
+	case servicepb.Action_ACTION_RESTART:
+		action = "restart"
+	default:
+		return nil, fmt.Errorf("unknown action %v", req.Action)
+	}
+	return &rpcauth.RequestInput{
+		Action: action,
+		Unit:   Unit(req.ServiceName),
+	}, nil
+}
+
+func init() {
+	for m, i := range map[proto.Message]Inspector{
+		&execpb.ExecRequest{}:        inspectExec,
+		&packagespb.InstallRequest{}: inspectInstall,
+		&packagespb.UpdateRequest{}:  inspectUpdate,
+		&servicepb.StatusRequest{}:   inspectStatus,
+		&servicepb.ActionRequest{}:   inspectAction,
+	} {
+		if err := Register(string(proto.MessageName(m)), i); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package inspect
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	execpb "github.com/Snowflake-Labs/sansshell/services/exec"
+	packagespb "github.com/Snowflake-Labs/sansshell/services/packages"
+	servicepb "github.com/Snowflake-Labs/sansshell/services/service"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestRegister(t *testing.T) {
+	if err := Register("Exec.ExecRequest", inspectExec); err == nil {
+		t.Error("duplicate registration didn't fail")
+	}
+	if err := Register("Foo.Bar", nil); err == nil {
+		t.Error("nil inspector registration didn't fail")
+	}
+	want := []string{
+		"Exec.ExecRequest",
+		"Packages.InstallRequest",
+		"Packages.UpdateRequest",
+		"Service.ActionRequest",
+		"Service.StatusRequest",
+	}
+	if diff := cmp.Diff(want, MessageTypes()); diff != "" {
+		t.Errorf("MessageTypes() (-want +got):\n%s", diff)
+	}
+}
+
+func TestHook(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		method   string
+		req      proto.Message
+		want     *rpcauth.RequestInput
+		wantCode codes.Code
+	}{
+		{
+			name:   "exec",
+			method: "/Exec.Exec/Run",
+			req:    &execpb.ExecRequest{Command: "/usr/bin/systemctl", Args: []string{"restart", "nginx"}},
+			want: &rpcauth.RequestInput{
+				Action: "run",
+				Binary: "/usr/bin/systemctl",
+				Args:   []string{"restart", "nginx"},
+			},
+		},
+		{
+			name:     "exec relative path",
+			method:   "/Exec.Exec/Run",
+			req:      &execpb.ExecRequest{Command: "systemctl"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "exec unclean path",
+			method:   "/Exec.Exec/Run",
+			req:      &execpb.ExecRequest{Command: "/tmp/../usr/bin/id"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:   "package install",
+			method: "/Packages.Packages/Install",
+			req:    &packagespb.InstallRequest{Name: "nginx", Version: "1:1.20.1-1.el8.x86_64", Repo: "epel"},
+			want: &rpcauth.RequestInput{
+				Action:  "install",
+				Package: "nginx",
+				Version: "1:1.20.1-1.el8.x86_64",
+				Repo:    "epel",
+			},
+		},
+		{
+			name:   "package update",
+			method: "/Packages.Packages/Update",
+			req:    &packagespb.UpdateRequest{Name: "nginx", OldVersion: "1:1.20.0-1.el8.x86_64", NewVersion: "1:1.20.1-1.el8.x86_64"},
+			want: &rpcauth.RequestInput{
+				Action:     "update",
+				Package:    "nginx",
+				Version:    "1:1.20.1-1.el8.x86_64",
+				OldVersion: "1:1.20.0-1.el8.x86_64",
+			},
+		},
+		{
+			name:   "service restart",
+			method: "/Service.Service/Action",
+			req:    &servicepb.ActionRequest{ServiceName: "nginx", Action: servicepb.Action_ACTION_RESTART},
+			want: &rpcauth.RequestInput{
+				Action: "restart",
+				Unit:   "nginx.service",
+			},
+		},
+		{
+			name:   "service stop with suffix",
+			method: "/Service.Service/Action",
+			req:    &servicepb.ActionRequest{ServiceName: "nginx.service", Action: servicepb.Action_ACTION_STOP},
+			want: &rpcauth.RequestInput{
+				Action: "stop",
+				Unit:   "nginx.service",
+			},
+		},
+		{
+			name:     "service unknown action",
+			method:   "/Service.Service/Action",
+			req:      &servicepb.ActionRequest{ServiceName: "nginx"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:   "service status",
+			method: "/Service.Service/Status",
+			req:    &servicepb.StatusRequest{ServiceName: "sshd"},
+			want: &rpcauth.RequestInput{
+				Action: "status",
+				Unit:   "sshd.service",
+			},
+		},
+		{
+			name:   "no inspector",
+			method: "/HealthCheck.HealthCheck/Ok",
+			req:    &emptypb.Empty{},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input, err := rpcauth.NewRPCAuthInput(context.Background(), tc.method, tc.req)
+			testutil.FatalOnErr("NewRPCAuthInput", err, t)
+			err = Hook().Hook(context.Background(), input)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Hook: got code %v (%v), want %v", got, err, tc.wantCode)
+			}
+			if diff := cmp.Diff(tc.want, input.Request); diff != "" {
+				t.Errorf("input.Request (-want +got):\n%s", diff)
+			}
+			got, err := Inspect(tc.req)
+			if (err != nil) != (tc.wantCode != codes.OK) {
+				t.Fatalf("Inspect: got error %v, want code %v", err, tc.wantCode)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Inspect (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+	input.request.action = "restart"
+	input.request.unit = "nginx.service"
+}
+
+allow {
+	input.request.binary = "/usr/bin/systemctl"
+	input.request.args = ["restart", "nginx.service"]
+}
+`
+	authz, err := rpcauth.NewWithPolicy(ctx, policy, Hook())
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+	for _, tc := range []struct {
+		name   string
+		method string
+		req    proto.Message
+		allow  bool
+	}{
+		{
+			name:   "restart nginx",
+			method: "/Service.Service/Action",
+			req:    &servicepb.ActionRequest{ServiceName: "nginx", Action: servicepb.Action_ACTION_RESTART},
+			allow:  true,
+		},
+		{
+			name:   "stop nginx",
+			method: "/Service.Service/Action",
+			req:    &servicepb.ActionRequest{ServiceName: "nginx", Action: servicepb.Action_ACTION_STOP},
+		},
+		{
+			name:   "restart sshd",
+			method: "/Service.Service/Action",
+			req:    &servicepb.ActionRequest{ServiceName: "sshd", Action: servicepb.Action_ACTION_RESTART},
+		},
+		{
+			name:   "systemctl restart nginx",
+			method: "/Exec.Exec/Run",
+			req:    &execpb.ExecRequest{Command: "/usr/bin/systemctl", Args: []string{"restart", "nginx.service"}},
+			allow:  true,
+		},
+		{
+			name:   "systemctl restart nginx and sshd",
+			method: "/Exec.Exec/Run",
+			req:    &execpb.ExecRequest{Command: "/usr/bin/systemctl", Args: []string{"restart", "nginx.service", "sshd.service"}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input, err := rpcauth.NewRPCAuthInput(ctx, tc.method, tc.req)
+			testutil.FatalOnErr("NewRPCAuthInput", err, t)
+			err = authz.Eval(ctx, input)
+			if (err == nil) != tc.allow {
+				t.Errorf("Eval: got %v, want allowed %t", err, tc.allow)
+			}
+		})
+	}
+}
@@ -26,40 +26,57 @@ import (
 )
 
 // LoadClientCredentials returns transport credentials for SansShell clients,
-// based on the provided `loaderName`
-func LoadClientCredentials(ctx context.Context, loaderName string) (credentials.TransportCredentials, error) {
+// based on the provided `loaderName`, with any TLS options applied.
+func LoadClientCredentials(ctx context.Context, loaderName string, opts ...TLSOption) (credentials.TransportCredentials, error) {
 	loader, err := Loader(loaderName)
 	if err != nil {
 		return nil, err
 	}
 
-	pool, err := loader.LoadRootCA(ctx)
-	if err != nil {
-		return nil, err
+	var pool *x509.CertPool
+	rrl, reloadRoots := loader.(ReloadingRootsLoader)
+	if reloadRoots {
+		roots, err := rrl.RootCAReloader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts[:len(opts):len(opts)], withReloadingRootCAs(roots))
+	} else {
+		pool, err = loader.LoadRootCA(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if rl, ok := loader.(ReloadingCredentialsLoader); ok {
+		r, err := rl.ClientCertReloader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return NewReloadingClientCredentials(r, pool, opts...), nil
 	}
 	cert, err := loader.LoadClientCertificate(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return NewClientCredentials(cert, pool), nil
+	return NewClientCredentials(cert, pool, opts...), nil
 }
 
 // NewClientCredentials returns transport credentials for SansShell clients.
-func NewClientCredentials(cert tls.Certificate, CAPool *x509.CertPool) credentials.TransportCredentials {
-	return credentials.NewTLS(&tls.Config{
+func NewClientCredentials(cert tls.Certificate, CAPool *x509.CertPool, opts ...TLSOption) credentials.TransportCredentials {
+	return credentials.NewTLS(applyTLSOptions(&tls.Config{
 		Certificates: []tls.Certificate{cert},
 		RootCAs:      CAPool,
-		MinVersion:   tls.VersionTLS13,
-	})
+		MinVersion:   DefaultMinTLSVersion,
+	}, opts))
 }
 
 // LoadClientTLS reads the certificates and keys from disk at the supplied paths,
 // and assembles them into a set of TransportCredentials for the gRPC client.
-func LoadClientTLS(clientCertFile, clientKeyFile string, CAPool *x509.CertPool) (credentials.TransportCredentials, error) {
+func LoadClientTLS(clientCertFile, clientKeyFile string, CAPool *x509.CertPool, opts ...TLSOption) (credentials.TransportCredentials, error) {
 	// Read in client credentials
 	cert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
 	if err != nil {
 		return nil, fmt.Errorf("could not read client credentials: %w", err)
 	}
-	return NewClientCredentials(cert, CAPool), nil
+	return NewClientCredentials(cert, CAPool, opts...), nil
 }
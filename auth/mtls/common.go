@@ -18,23 +18,113 @@ package mtls
 
 import (
 	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
+// rootExtensions are the file extensions of CAs loaded from a directory.
+var rootExtensions = map[string]bool{
+	".pem": true,
+	".crt": true,
+	".cer": true,
+}
+
 // LoadRootOfTrust will load an CA root of trust(s) from the given
-// file and return a CertPool to use in validating certificates.
-// All CA's to validate against must be presented together in the PEM
-// file.
-func LoadRootOfTrust(filename string) (*x509.CertPool, error) {
-	// Read in the root of trust for client identities
-	ca, err := os.ReadFile(filename)
+// path and return a CertPool to use in validating certificates.
+// The path may be a PEM file, a directory (in which case all .pem, .crt
+// and .cer files in it are read) or a glob pattern matching PEM files.
+// This allows trusting several CAs at once, i.e. both the old and new
+// roots during a CA migration.
+//
+// Every certificate found is trusted, so bundles may include intermediate
+// CAs alongside roots and certificates issued by them will validate even
+// if the peer doesn't present the intermediate.
+func LoadRootOfTrust(path string) (*x509.CertPool, error) {
+	files, err := rootFiles(path)
 	if err != nil {
-		return nil, fmt.Errorf("could not read CA from %q: %w", filename, err)
+		return nil, err
 	}
 	capool := x509.NewCertPool()
-	if !capool.AppendCertsFromPEM(ca) {
-		return nil, fmt.Errorf("could not add CA cert to pool: %w", err)
+	for _, f := range files {
+		// Read in the root of trust for client identities
+		ca, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA from %q: %w", f, err)
+		}
+		certs, err := parseCertificates(ca)
+		if err != nil {
+			return nil, fmt.Errorf("could not add CA cert from %q to pool: %w", f, err)
+		}
+		for _, c := range certs {
+			capool.AddCert(c)
+		}
 	}
 	return capool, nil
 }
+
+// rootFiles returns the files LoadRootOfTrust reads for path.
+func rootFiles(path string) ([]string, error) {
+	if strings.ContainsAny(path, "*?[") {
+		files, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA pattern %q: %w", path, err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no CA files match %q", path)
+		}
+		return files, nil
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA from %q: %w", path, err)
+	}
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA directory %q: %w", path, err)
+	}
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || !rootExtensions[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		files = append(files, filepath.Join(path, e.Name()))
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no CA files in directory %q", path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// parseCertificates returns all of the certificates in PEM data, which
+// must contain at least one.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" || len(block.Headers) != 0 {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM certificates found")
+	}
+	return certs, nil
+}
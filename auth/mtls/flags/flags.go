@@ -30,7 +30,8 @@ import (
 )
 
 const (
-	loaderName = "flags"
+	loaderName       = "flags"
+	reloadLoaderName = "flags-reload"
 
 	defaultClientCertPath = ".sansshell/client.pem"
 	defaultClientKeyPath  = ".sansshell/client.key"
@@ -68,6 +69,33 @@ func (flagLoader) LoadServerCertificate(context.Context) (tls.Certificate, error
 	return tls.LoadX509KeyPair(serverCertFile, serverKeyFile)
 }
 
+// ReloadName returns the loader to use to set mtls params via flags,
+// reloading the client/server certificates and root of trust from disk
+// as they change.
+func ReloadName() string { return reloadLoaderName }
+
+// reloadingFlagLoader is a flagLoader which also implements
+// mtls.ReloadingCredentialsLoader and mtls.ReloadingRootsLoader.
+type reloadingFlagLoader struct {
+	flagLoader
+}
+
+func (reloadingFlagLoader) ClientCAReloader(context.Context) (*mtls.RootReloader, error) {
+	return mtls.NewRootReloader(rootCAFile)
+}
+
+func (reloadingFlagLoader) RootCAReloader(context.Context) (*mtls.RootReloader, error) {
+	return mtls.NewRootReloader(rootCAFile)
+}
+
+func (reloadingFlagLoader) ClientCertReloader(context.Context) (mtls.CertificateSource, error) {
+	return mtls.NewCertReloader(clientCertFile, clientKeyFile)
+}
+
+func (reloadingFlagLoader) ServerCertReloader(context.Context) (mtls.CertificateSource, error) {
+	return mtls.NewCertReloader(serverCertFile, serverKeyFile)
+}
+
 func init() {
 	cd, err := os.UserHomeDir()
 	if err != nil {
@@ -84,9 +112,12 @@ func init() {
 	flag.StringVar(&clientKeyFile, "client-key", clientKeyFile, "Path to this client's key")
 	flag.StringVar(&serverCertFile, "server-cert", serverCertFile, "Path to an x509 server cert, PEM format")
 	flag.StringVar(&serverKeyFile, "server-key", serverKeyFile, "Path to the server's TLS key")
-	flag.StringVar(&rootCAFile, "root-ca", rootCAFile, "The root of trust for remote identities, PEM format")
+	flag.StringVar(&rootCAFile, "root-ca", rootCAFile, "The root of trust for remote identities, PEM format. May also be a directory or glob of PEM files.")
 
 	if err := mtls.Register(loaderName, flagLoader{}); err != nil {
 		panic(err)
 	}
+	if err := mtls.Register(reloadLoaderName, reloadingFlagLoader{}); err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,345 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package gcpkms provides an mtls.CredentialsLoader whose private key is held
+// in Google Cloud KMS, so it never exists on the host.
+//
+// Importing this package registers a loader named "gcpkms" which can then be
+// selected with --credential-source and configured with the --gcpkms-* flags.
+// The key is an asymmetric signing CryptoKeyVersion and the certificate chain
+// for it is read from --gcpkms-cert, which is re-read on every load so it can
+// be reissued for the same key. The same key is used for client and server
+// certificates.
+//
+// Every TLS handshake makes an AsymmetricSign call, which needs an access token
+// for an identity with roles/cloudkms.signerVerifier on the key. By default the
+// token is fetched from the GCE metadata server. EC keys are recommended; RSA
+// keys must use a PSS algorithm to be usable with TLS 1.3.
+package gcpkms
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+)
+
+const (
+	loaderName = "gcpkms"
+
+	// DefaultEndpoint is the Cloud KMS API.
+	DefaultEndpoint = "https://cloudkms.googleapis.com"
+
+	metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+	// Responses larger than this are rejected.
+	maxResponseSize = 1024 * 1024
+)
+
+// Config describes the key to use and how to reach Cloud KMS.
+type Config struct {
+	// KeyName is the resource name of the CryptoKeyVersion, i.e.
+	// projects/p/locations/l/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1
+	KeyName string
+	// CertFile is a PEM file with the certificate chain for the key.
+	CertFile string
+	// RootCAFile is a PEM file with the root of trust for peers.
+	RootCAFile string
+	// Endpoint of the API. Defaults to DefaultEndpoint.
+	Endpoint string
+	// TokenFile if set contains the access token to use. It's re-read for
+	// every request. Otherwise a token is fetched from the metadata server.
+	TokenFile string
+	// HTTPClient if set is used for all requests.
+	HTTPClient *http.Client
+}
+
+var flagConfig = &Config{}
+
+// Name returns the loader to use to obtain mtls params from Cloud KMS.
+func Name() string { return loaderName }
+
+// loader implements mtls.CredentialsLoader. The public key is fetched from
+// KMS on first use and kept for the life of the process.
+type loader struct {
+	cfg *Config
+
+	mu     sync.Mutex
+	signer *signer
+}
+
+// NewLoader returns a loader using the key described by `cfg`. It can be
+// registered with mtls.Register under another name to use different settings
+// than the flags.
+func NewLoader(cfg *Config) mtls.CredentialsLoader {
+	return &loader{cfg: cfg}
+}
+
+func (l *loader) LoadClientCA(context.Context) (*x509.CertPool, error) {
+	return mtls.LoadRootOfTrust(l.cfg.RootCAFile)
+}
+
+func (l *loader) LoadRootCA(context.Context) (*x509.CertPool, error) {
+	return mtls.LoadRootOfTrust(l.cfg.RootCAFile)
+}
+
+func (l *loader) LoadClientCertificate(ctx context.Context) (tls.Certificate, error) {
+	return l.certificate(ctx)
+}
+
+func (l *loader) LoadServerCertificate(ctx context.Context) (tls.Certificate, error) {
+	return l.certificate(ctx)
+}
+
+func (l *loader) client() *http.Client {
+	if l.cfg.HTTPClient != nil {
+		return l.cfg.HTTPClient
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// do performs a request and decodes the JSON response into v.
+func (l *loader) do(ctx context.Context, method string, url string, header http.Header, body interface{}, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return err
+	}
+	for k, vals := range header {
+		req.Header[k] = vals
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := l.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var gerr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(b, &gerr) == nil && gerr.Error.Message != "" {
+			return fmt.Errorf("%s: %s: %s", req.URL.Path, resp.Status, gerr.Error.Message)
+		}
+		return fmt.Errorf("%s: %s", req.URL.Path, resp.Status)
+	}
+	return json.Unmarshal(b, v)
+}
+
+// token returns the access token to call the API with.
+func (l *loader) token(ctx context.Context) (string, error) {
+	if l.cfg.TokenFile != "" {
+		b, err := os.ReadFile(l.cfg.TokenFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	var t struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := l.do(ctx, http.MethodGet, metadataTokenURL, http.Header{"Metadata-Flavor": {"Google"}}, nil, &t); err != nil {
+		return "", fmt.Errorf("can't get token from metadata server: %w", err)
+	}
+	if t.AccessToken == "" {
+		return "", errors.New("metadata server returned no token")
+	}
+	return t.AccessToken, nil
+}
+
+// call invokes `verb` (i.e. ":asymmetricSign") on the configured key.
+func (l *loader) call(ctx context.Context, method string, verb string, body interface{}, v interface{}) error {
+	token, err := l.token(ctx)
+	if err != nil {
+		return err
+	}
+	endpoint := l.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	url := strings.TrimSuffix(endpoint, "/") + "/v1/" + l.cfg.KeyName + verb
+	return l.do(ctx, method, url, http.Header{"Authorization": {"Bearer " + token}}, body, v)
+}
+
+// open fetches the public key of the configured key version.
+func (l *loader) open(ctx context.Context) (*signer, error) {
+	if l.cfg.KeyName == "" {
+		return nil, errors.New("no Cloud KMS key configured")
+	}
+	var resp struct {
+		PEM       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := l.call(ctx, http.MethodGet, "/publicKey", nil, &resp); err != nil {
+		return nil, fmt.Errorf("can't get public key of %s: %w", l.cfg.KeyName, err)
+	}
+	block, _ := pem.Decode([]byte(resp.PEM))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM public key returned for %s", l.cfg.KeyName)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse public key of %s: %w", l.cfg.KeyName, err)
+	}
+	if _, ok := pub.(*rsa.PublicKey); ok && !strings.HasPrefix(resp.Algorithm, "RSA_SIGN_") {
+		return nil, fmt.Errorf("%s has algorithm %s which can't be used for signing", l.cfg.KeyName, resp.Algorithm)
+	}
+	return &signer{l: l, pub: pub, pss: strings.HasPrefix(resp.Algorithm, "RSA_SIGN_PSS_")}, nil
+}
+
+func (l *loader) certificate(ctx context.Context) (tls.Certificate, error) {
+	if l.cfg.CertFile == "" {
+		return tls.Certificate{}, errors.New("no certificate configured for Cloud KMS key")
+	}
+	b, err := os.ReadFile(l.cfg.CertFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	var chain [][]byte
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			chain = append(chain, block.Bytes)
+		}
+	}
+	if len(chain) == 0 {
+		return tls.Certificate{}, fmt.Errorf("no certificates found in %s", l.cfg.CertFile)
+	}
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.signer == nil {
+		s, err := l.open(ctx)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		l.signer = s
+	}
+	if k, ok := leaf.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !k.Equal(l.signer.pub) {
+		return tls.Certificate{}, fmt.Errorf("certificate in %s doesn't match the public key of %s", l.cfg.CertFile, l.cfg.KeyName)
+	}
+	return tls.Certificate{
+		Certificate: chain,
+		Leaf:        leaf,
+		PrivateKey:  l.signer,
+	}, nil
+}
+
+// signer implements crypto.Signer with AsymmetricSign calls.
+type signer struct {
+	l   *loader
+	pub crypto.PublicKey
+	// pss is set for RSA keys which sign with PSS rather than PKCS#1 v1.5.
+	pss bool
+}
+
+// Public implements crypto.Signer.
+func (s *signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// digestFields are the names of the Digest message fields for each hash.
+var digestFields = map[crypto.Hash]string{
+	crypto.SHA256: "sha256",
+	crypto.SHA384: "sha384",
+	crypto.SHA512: "sha512",
+}
+
+// Sign implements crypto.Signer. The key's algorithm fixes the padding and
+// hash, so requests for anything else fail.
+func (s *signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if _, ok := s.pub.(*rsa.PublicKey); ok {
+		if _, isPSS := opts.(*rsa.PSSOptions); isPSS != s.pss {
+			return nil, fmt.Errorf("%s doesn't support the requested RSA padding", s.l.cfg.KeyName)
+		}
+	}
+	field, ok := digestFields[opts.HashFunc()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash %v", opts.HashFunc())
+	}
+	if len(digest) != opts.HashFunc().Size() {
+		return nil, fmt.Errorf("digest length %d doesn't match %v", len(digest), opts.HashFunc())
+	}
+	req := map[string]interface{}{
+		"digest": map[string]string{field: base64.StdEncoding.EncodeToString(digest)},
+	}
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+	// crypto.Signer has no context so bound the call ourselves.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := s.l.call(ctx, http.MethodPost, ":asymmetricSign", req, &resp); err != nil {
+		return nil, fmt.Errorf("Cloud KMS sign: %w", err)
+	}
+	// ECDSA signatures are already DER encoded as Go expects.
+	sig, err := base64.StdEncoding.DecodeString(resp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from Cloud KMS: %w", err)
+	}
+	return sig, nil
+}
+
+func init() {
+	flag.StringVar(&flagConfig.KeyName, "gcpkms-key", "", "Resource name of the Cloud KMS CryptoKeyVersion holding the private key for the gcpkms credential source")
+	flag.StringVar(&flagConfig.CertFile, "gcpkms-cert", "", "PEM certificate chain for the Cloud KMS key")
+	flag.StringVar(&flagConfig.RootCAFile, "gcpkms-root-ca", "", "The root of trust for remote identities, PEM format")
+	flag.StringVar(&flagConfig.Endpoint, "gcpkms-endpoint", DefaultEndpoint, "Cloud KMS API endpoint for the gcpkms credential source")
+	flag.StringVar(&flagConfig.TokenFile, "gcpkms-token-file", "", "File containing an access token for the Cloud KMS API. If empty one is fetched from the GCE metadata server.")
+
+	if err := mtls.Register(loaderName, NewLoader(flagConfig)); err != nil {
+		panic(err)
+	}
+}
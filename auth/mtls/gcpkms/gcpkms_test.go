@@ -0,0 +1,267 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package gcpkms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const (
+	testToken = "test-token"
+	testKey   = "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"
+)
+
+// fakeKMS implements the publicKey and asymmetricSign methods for one key.
+type fakeKMS struct {
+	t         *testing.T
+	key       crypto.Signer
+	algorithm string
+	signs     int
+}
+
+func newFakeKMS(t *testing.T, key crypto.Signer, algorithm string) (*fakeKMS, *httptest.Server) {
+	t.Helper()
+	f := &fakeKMS{t: t, key: key, algorithm: algorithm}
+	s := httptest.NewServer(f)
+	t.Cleanup(s.Close)
+	return f, s
+}
+
+func (f *fakeKMS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+testToken {
+		http.Error(w, `{"error": {"message": "bad token"}}`, http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/"+testKey+"/publicKey":
+		der, err := x509.MarshalPKIXPublicKey(f.key.Public())
+		testutil.FatalOnErr("MarshalPKIXPublicKey", err, f.t)
+		json.NewEncoder(w).Encode(map[string]string{
+			"pem":       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+			"algorithm": f.algorithm,
+		})
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/"+testKey+":asymmetricSign":
+		var req struct {
+			Digest struct {
+				SHA256 []byte `json:"sha256"`
+			} `json:"digest"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Digest.SHA256) == 0 {
+			http.Error(w, `{"error": {"message": "bad digest"}}`, http.StatusBadRequest)
+			return
+		}
+		var opts crypto.SignerOpts = crypto.SHA256
+		if strings.HasPrefix(f.algorithm, "RSA_SIGN_PSS_") {
+			opts = &rsa.PSSOptions{Hash: crypto.SHA256, SaltLength: rsa.PSSSaltLengthEqualsHash}
+		}
+		sig, err := f.key.Sign(rand.Reader, req.Digest.SHA256, opts)
+		testutil.FatalOnErr("Sign", err, f.t)
+		f.signs++
+		json.NewEncoder(w).Encode(map[string]string{"signature": base64.StdEncoding.EncodeToString(sig)})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// writeCert writes a self signed certificate for `key` to a temp file.
+func writeCert(t *testing.T, key crypto.Signer) (string, *x509.CertPool) {
+	t.Helper()
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "server"},
+		DNSNames:              []string{"server"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	testutil.FatalOnErr("CreateCertificate", err, t)
+	cert, err := x509.ParseCertificate(der)
+	testutil.FatalOnErr("ParseCertificate", err, t)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	file := filepath.Join(t.TempDir(), "cert.pem")
+	err = os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	testutil.FatalOnErr("WriteFile", err, t)
+	return file, pool
+}
+
+func writeToken(t *testing.T) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "token")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(file, []byte(testToken+"\n"), 0600), t)
+	return file
+}
+
+func TestLoader(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	testutil.FatalOnErr("GenerateKey", err, t)
+
+	for _, tc := range []struct {
+		name      string
+		key       crypto.Signer
+		algorithm string
+	}{
+		{"ecdsa", ecKey, "EC_SIGN_P256_SHA256"},
+		{"rsa-pss", rsaKey, "RSA_SIGN_PSS_2048_SHA256"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			kms, s := newFakeKMS(t, tc.key, tc.algorithm)
+			certFile, pool := writeCert(t, tc.key)
+			l := NewLoader(&Config{
+				KeyName:   testKey,
+				CertFile:  certFile,
+				Endpoint:  s.URL,
+				TokenFile: writeToken(t),
+			})
+			cert, err := l.LoadServerCertificate(context.Background())
+			testutil.FatalOnErr("LoadServerCertificate", err, t)
+			if _, ok := cert.PrivateKey.(*signer); !ok {
+				t.Fatalf("private key is %T, want a KMS signer", cert.PrivateKey)
+			}
+
+			// A full handshake must succeed with the server key signing in KMS.
+			c1, c2 := net.Pipe()
+			defer c1.Close()
+			defer c2.Close()
+			server := tls.Server(c1, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS13})
+			client := tls.Client(c2, &tls.Config{RootCAs: pool, ServerName: "server", MinVersion: tls.VersionTLS13})
+			errc := make(chan error, 1)
+			go func() { errc <- server.Handshake() }()
+			testutil.FatalOnErr("client handshake", client.Handshake(), t)
+			testutil.FatalOnErr("server handshake", <-errc, t)
+			if kms.signs == 0 {
+				t.Fatal("handshake didn't sign with KMS")
+			}
+
+			// Direct signatures verify against the certificate key.
+			digest := sha256.Sum256([]byte("hello"))
+			var opts crypto.SignerOpts = crypto.SHA256
+			if tc.algorithm == "RSA_SIGN_PSS_2048_SHA256" {
+				opts = &rsa.PSSOptions{Hash: crypto.SHA256, SaltLength: rsa.PSSSaltLengthEqualsHash}
+			}
+			sig, err := cert.PrivateKey.(crypto.Signer).Sign(rand.Reader, digest[:], opts)
+			testutil.FatalOnErr("Sign", err, t)
+			switch pub := cert.Leaf.PublicKey.(type) {
+			case *ecdsa.PublicKey:
+				if !ecdsa.VerifyASN1(pub, digest[:], sig) {
+					t.Fatal("ECDSA signature didn't verify")
+				}
+			case *rsa.PublicKey:
+				testutil.FatalOnErr("VerifyPSS", rsa.VerifyPSS(pub, crypto.SHA256, digest[:], sig, opts.(*rsa.PSSOptions)), t)
+			}
+		})
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	_, s := newFakeKMS(t, key, "EC_SIGN_P256_SHA256")
+	_, rsaKMS := newFakeKMS(t, rsaKey, "RSA_SIGN_PKCS1_2048_SHA256")
+	certFile, _ := writeCert(t, key)
+	otherCert, _ := writeCert(t, other)
+	rsaCert, _ := writeCert(t, rsaKey)
+	token := writeToken(t)
+	badToken := filepath.Join(t.TempDir(), "bad")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(badToken, []byte("nope"), 0600), t)
+
+	for _, tc := range []struct {
+		name    string
+		cfg     *Config
+		wantErr string
+	}{
+		{
+			name:    "no key",
+			cfg:     &Config{CertFile: certFile, Endpoint: s.URL, TokenFile: token},
+			wantErr: "no Cloud KMS key configured",
+		},
+		{
+			name:    "no cert",
+			cfg:     &Config{KeyName: testKey, Endpoint: s.URL, TokenFile: token},
+			wantErr: "no certificate configured",
+		},
+		{
+			name:    "bad token",
+			cfg:     &Config{KeyName: testKey, CertFile: certFile, Endpoint: s.URL, TokenFile: badToken},
+			wantErr: "bad token",
+		},
+		{
+			name:    "unknown key",
+			cfg:     &Config{KeyName: testKey + "0", CertFile: certFile, Endpoint: s.URL, TokenFile: token},
+			wantErr: "404",
+		},
+		{
+			name:    "mismatched cert",
+			cfg:     &Config{KeyName: testKey, CertFile: otherCert, Endpoint: s.URL, TokenFile: token},
+			wantErr: "doesn't match the public key",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewLoader(tc.cfg).LoadClientCertificate(context.Background())
+			testutil.WantErr(tc.name, err, true, t)
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error %v doesn't contain %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	// A PKCS#1 key can't produce PSS signatures (and so can't do TLS 1.3).
+	cert, err := NewLoader(&Config{KeyName: testKey, CertFile: rsaCert, Endpoint: rsaKMS.URL, TokenFile: token}).LoadClientCertificate(context.Background())
+	testutil.FatalOnErr("LoadClientCertificate", err, t)
+	digest := sha256.Sum256([]byte("hello"))
+	_, err = cert.PrivateKey.(crypto.Signer).Sign(rand.Reader, digest[:], &rsa.PSSOptions{Hash: crypto.SHA256})
+	testutil.FatalOnNoErr("PSS with PKCS#1 key", err, t)
+	_, err = cert.PrivateKey.(crypto.Signer).Sign(rand.Reader, digest[:], crypto.SHA256)
+	testutil.FatalOnErr("PKCS#1 sign", err, t)
+	_, err = cert.PrivateKey.(crypto.Signer).Sign(rand.Reader, make([]byte, 20), crypto.SHA1)
+	testutil.FatalOnNoErr("SHA1", err, t)
+}
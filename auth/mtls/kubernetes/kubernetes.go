@@ -0,0 +1,228 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package kubernetes provides an mtls.CredentialsLoader which reads
+// certificates from a Kubernetes TLS secret (i.e. one maintained by
+// cert-manager) through the API server, for pods which can't mount it.
+//
+// Importing this package registers a loader named "kubernetes-secret" which
+// can then be selected with --credential-source. The secret must contain
+// tls.crt and tls.key, and ca.crt with the root of trust for peers. It's used
+// for both client and server certificates and refetched periodically (see
+// --k8s-secret-refresh) so rotations are picked up without a restart.
+//
+// By default the pod's service account is used to talk to the API server, so
+// it needs permission to get the secret.
+package kubernetes
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+)
+
+const (
+	loaderName = "kubernetes-secret"
+
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	// Secrets larger than this are rejected.
+	maxResponseSize = 1024 * 1024
+)
+
+// Config describes where to find the TLS secret.
+type Config struct {
+	// APIServer is the base URL of the Kubernetes API server.
+	APIServer string
+	// TokenFile contains the bearer token to authenticate with. It's
+	// re-read for every request as projected tokens rotate.
+	TokenFile string
+	// CAFile is the CA bundle used to verify the API server.
+	CAFile string
+	// Namespace containing the secret.
+	Namespace string
+	// Secret is the name of the kubernetes.io/tls secret.
+	Secret string
+	// Refresh if non-zero is how often to refetch the secret.
+	Refresh time.Duration
+	// HTTPClient if set is used instead of one built from CAFile.
+	HTTPClient *http.Client
+}
+
+var flagConfig = &Config{}
+
+// Name returns the loader to use to obtain mtls params from a Kubernetes secret.
+func Name() string { return loaderName }
+
+// loader implements mtls.ReloadingCredentialsLoader by reading a secret.
+type loader struct {
+	cfg *Config
+}
+
+// NewLoader returns a loader reading the secret described by `cfg`. It can
+// be registered with mtls.Register under another name to use different settings
+// than the flags.
+func NewLoader(cfg *Config) mtls.ReloadingCredentialsLoader {
+	return &loader{cfg: cfg}
+}
+
+func (l *loader) client() (*http.Client, error) {
+	if l.cfg.HTTPClient != nil {
+		return l.cfg.HTTPClient, nil
+	}
+	pool, err := mtls.LoadRootOfTrust(l.cfg.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("can't load API server CA: %w", err)
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		},
+		Timeout: 30 * time.Second,
+	}, nil
+}
+
+// secret fetches the data of the configured secret.
+func (l *loader) secret(ctx context.Context) (map[string][]byte, error) {
+	if l.cfg.APIServer == "" {
+		return nil, errors.New("no kubernetes API server configured (not running in a pod?)")
+	}
+	if l.cfg.Secret == "" {
+		return nil, errors.New("no kubernetes secret configured")
+	}
+	client, err := l.client()
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/api/v1/namespaces/%s/secrets/%s", strings.TrimSuffix(l.cfg.APIServer, "/"), l.cfg.Namespace, l.cfg.Secret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if l.cfg.TokenFile != "" {
+		token, err := os.ReadFile(l.cfg.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get secret %s/%s: %s", l.cfg.Namespace, l.cfg.Secret, resp.Status)
+	}
+	var s struct {
+		// encoding/json decodes the base64 values.
+		Data map[string][]byte `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&s); err != nil {
+		return nil, fmt.Errorf("can't parse secret %s/%s: %w", l.cfg.Namespace, l.cfg.Secret, err)
+	}
+	return s.Data, nil
+}
+
+func (l *loader) cert(ctx context.Context) (*tls.Certificate, error) {
+	data, err := l.secret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(data["tls.crt"], data["tls.key"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid key pair in secret %s/%s: %w", l.cfg.Namespace, l.cfg.Secret, err)
+	}
+	return &cert, nil
+}
+
+func (l *loader) pool(ctx context.Context) (*x509.CertPool, error) {
+	data, err := l.secret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data["ca.crt"]) {
+		return nil, fmt.Errorf("no CA certificates in ca.crt of secret %s/%s", l.cfg.Namespace, l.cfg.Secret)
+	}
+	return pool, nil
+}
+
+func (l *loader) certificate(ctx context.Context) (tls.Certificate, error) {
+	cert, err := l.cert(ctx)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return *cert, nil
+}
+
+func (l *loader) LoadClientCA(ctx context.Context) (*x509.CertPool, error) {
+	return l.pool(ctx)
+}
+
+func (l *loader) LoadRootCA(ctx context.Context) (*x509.CertPool, error) {
+	return l.pool(ctx)
+}
+
+func (l *loader) LoadClientCertificate(ctx context.Context) (tls.Certificate, error) {
+	return l.certificate(ctx)
+}
+
+func (l *loader) LoadServerCertificate(ctx context.Context) (tls.Certificate, error) {
+	return l.certificate(ctx)
+}
+
+func (l *loader) ClientCertReloader(ctx context.Context) (mtls.CertificateSource, error) {
+	return mtls.NewRenewingSource(ctx, l.cert, l.cfg.Refresh)
+}
+
+func (l *loader) ServerCertReloader(ctx context.Context) (mtls.CertificateSource, error) {
+	return mtls.NewRenewingSource(ctx, l.cert, l.cfg.Refresh)
+}
+
+func init() {
+	var apiServer string
+	if host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT"); host != "" && port != "" {
+		apiServer = "https://" + net.JoinHostPort(host, port)
+	}
+	namespace := "default"
+	if b, err := os.ReadFile(serviceAccountDir + "/namespace"); err == nil {
+		namespace = strings.TrimSpace(string(b))
+	}
+	flag.StringVar(&flagConfig.APIServer, "k8s-api-server", apiServer, "Kubernetes API server URL for the kubernetes-secret credential source. Defaults to the in-cluster address.")
+	flag.StringVar(&flagConfig.TokenFile, "k8s-token-file", serviceAccountDir+"/token", "File containing the token used to authenticate to the Kubernetes API server")
+	flag.StringVar(&flagConfig.CAFile, "k8s-ca-file", serviceAccountDir+"/ca.crt", "CA bundle used to verify the Kubernetes API server")
+	flag.StringVar(&flagConfig.Namespace, "k8s-secret-namespace", namespace, "Namespace of the TLS secret. Defaults to the pod's namespace.")
+	flag.StringVar(&flagConfig.Secret, "k8s-secret", "", "Name of the kubernetes.io/tls secret holding tls.crt, tls.key and ca.crt")
+	flag.DurationVar(&flagConfig.Refresh, "k8s-secret-refresh", 5*time.Minute, "How often to refetch the TLS secret to pick up rotations")
+
+	if err := mtls.Register(loaderName, NewLoader(flagConfig)); err != nil {
+		panic(err)
+	}
+}
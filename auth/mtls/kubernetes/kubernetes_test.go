@@ -0,0 +1,144 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const testToken = "sa-token"
+
+func readFile(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("..", "testdata", name))
+	testutil.FatalOnErr("ReadFile", err, t)
+	return b
+}
+
+func newFakeAPIServer(t *testing.T, data map[string][]byte) *httptest.Server {
+	t.Helper()
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v1/namespaces/sansshell/secrets/sansshell-tls" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"kind": "Secret",
+			"type": "kubernetes.io/tls",
+			"data": data,
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeAPIServer(t, map[string][]byte{
+		"tls.crt": readFile(t, "leaf.pem"),
+		"tls.key": readFile(t, "leaf.key"),
+		"ca.crt":  readFile(t, "root.pem"),
+	})
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(tokenFile, []byte(testToken), 0600), t)
+	caFile := filepath.Join(dir, "ca.crt")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+	testutil.FatalOnErr("WriteFile", os.WriteFile(caFile, caPEM, 0600), t)
+
+	cfg := &Config{
+		APIServer: s.URL,
+		TokenFile: tokenFile,
+		CAFile:    caFile,
+		Namespace: "sansshell",
+		Secret:    "sansshell-tls",
+	}
+	l := NewLoader(cfg)
+	_, err := l.LoadRootCA(ctx)
+	testutil.FatalOnErr("LoadRootCA", err, t)
+	_, err = l.LoadClientCA(ctx)
+	testutil.FatalOnErr("LoadClientCA", err, t)
+	cert, err := l.LoadServerCertificate(ctx)
+	testutil.FatalOnErr("LoadServerCertificate", err, t)
+	want := readFile(t, "leaf.pem")
+	block, _ := pem.Decode(want)
+	if string(cert.Certificate[0]) != string(block.Bytes) {
+		t.Error("certificate doesn't match the secret")
+	}
+	src, err := l.ServerCertReloader(ctx)
+	testutil.FatalOnErr("ServerCertReloader", err, t)
+	_, err = src.GetCertificate(nil)
+	testutil.FatalOnErr("GetCertificate", err, t)
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(*Config)
+	}{
+		{
+			name:   "no api server",
+			mutate: func(c *Config) { c.APIServer = "" },
+		},
+		{
+			name:   "no secret",
+			mutate: func(c *Config) { c.Secret = "" },
+		},
+		{
+			name:   "unknown secret",
+			mutate: func(c *Config) { c.Secret = "other" },
+		},
+		{
+			name:   "bad token",
+			mutate: func(c *Config) { c.TokenFile = caFile },
+		},
+		{
+			name:   "untrusted api server",
+			mutate: func(c *Config) { c.CAFile = filepath.Join("..", "testdata", "root.pem") },
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := *cfg
+			tc.mutate(&c)
+			_, err := NewLoader(&c).LoadClientCertificate(ctx)
+			testutil.FatalOnNoErr(tc.name, err, t)
+		})
+	}
+
+	// A secret without a CA or a valid key pair is rejected.
+	s = newFakeAPIServer(t, map[string][]byte{
+		"tls.crt": readFile(t, "leaf.pem"),
+		"tls.key": readFile(t, "client.key"),
+	})
+	l = NewLoader(&Config{APIServer: s.URL, TokenFile: tokenFile, Namespace: "sansshell", Secret: "sansshell-tls", HTTPClient: s.Client()})
+	_, err = l.LoadClientCertificate(ctx)
+	testutil.FatalOnNoErr("mismatched key", err, t)
+	_, err = l.LoadRootCA(ctx)
+	testutil.FatalOnNoErr("missing ca.crt", err, t)
+}
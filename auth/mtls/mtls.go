@@ -57,6 +57,36 @@ type CredentialsLoader interface {
 	LoadServerCertificate(context.Context) (tls.Certificate, error)
 }
 
+// A ReloadingCredentialsLoader is a CredentialsLoader which can also supply
+// certificates that are reloaded as they rotate. If a registered loader implements
+// this LoadClientCredentials/LoadServerCredentials will use these methods in
+// preference to LoadClientCertificate/LoadServerCertificate.
+type ReloadingCredentialsLoader interface {
+	CredentialsLoader
+
+	// ClientCertReloader returns a CertificateSource for the client certificate.
+	ClientCertReloader(context.Context) (CertificateSource, error)
+
+	// ServerCertReloader returns a CertificateSource for the server certificate.
+	ServerCertReloader(context.Context) (CertificateSource, error)
+}
+
+// A ReloadingRootsLoader is a CredentialsLoader which can also supply roots
+// of trust that are reloaded as they change. If a registered loader implements
+// this LoadClientCredentials/LoadServerCredentials will use these methods in
+// preference to LoadRootCA/LoadClientCA.
+type ReloadingRootsLoader interface {
+	CredentialsLoader
+
+	// ClientCAReloader returns a RootReloader for the pool used by a server
+	// to validate client certificates.
+	ClientCAReloader(context.Context) (*RootReloader, error)
+
+	// RootCAReloader returns a RootReloader for the pool used by clients
+	// to validate server certificates.
+	RootCAReloader(context.Context) (*RootReloader, error)
+}
+
 // Register associates a name with a mechanism for loading credentials.
 // Implementations of CredentialsLoader will typically call Register
 // during init()
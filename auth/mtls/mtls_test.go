@@ -18,13 +18,25 @@ package mtls
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"log"
+	"math/big"
 	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	"google.golang.org/grpc"
@@ -142,6 +154,149 @@ func TestLoadRootOfTrust(t *testing.T) {
 
 	_, err = LoadRootOfTrust("no-file")
 	testutil.FatalOnNoErr("bad CA root", err, t)
+
+	_, err = LoadRootOfTrust("testdata/root.key")
+	testutil.FatalOnNoErr("file without certificates", err, t)
+
+	oldCA, oldKey := newTestCA(t, "old")
+	newCA, newKey := newTestCA(t, "new")
+	intermediate, _ := newTestCert(t, "intermediate", newCA, newKey, true)
+	oldLeaf, _ := newTestCert(t, "old-leaf", oldCA, oldKey, false)
+	newLeaf, _ := newTestCert(t, "new-leaf", newCA, newKey, false)
+
+	dir := t.TempDir()
+	writePEM(t, filepath.Join(dir, "old.pem"), oldCA)
+	// A bundle with an intermediate.
+	writePEM(t, filepath.Join(dir, "new.crt"), newCA, intermediate)
+	writePEM(t, filepath.Join(dir, "ignored.txt"), oldCA)
+
+	for _, path := range []string{dir, filepath.Join(dir, "*")} {
+		pool, err := LoadRootOfTrust(path)
+		testutil.FatalOnErr(path, err, t)
+		for _, leaf := range []*x509.Certificate{oldLeaf, newLeaf} {
+			if _, err := leaf.Verify(x509.VerifyOptions{Roots: pool}); err != nil {
+				t.Errorf("%s: %s doesn't verify: %v", path, leaf.Subject.CommonName, err)
+			}
+		}
+	}
+	// Only .pem, .crt and .cer files are read from directories but globs
+	// are taken as is.
+	_, err = LoadRootOfTrust(filepath.Join(dir, "*.txt"))
+	testutil.FatalOnErr("glob", err, t)
+	_, err = LoadRootOfTrust(filepath.Join(dir, "*.none"))
+	testutil.FatalOnNoErr("glob matching nothing", err, t)
+	_, err = LoadRootOfTrust(t.TempDir())
+	testutil.FatalOnNoErr("empty directory", err, t)
+}
+
+func TestRootReloader(t *testing.T) {
+	oldCA, oldKey := newTestCA(t, "old")
+	newCA, newKey := newTestCA(t, "new")
+	oldLeaf, _ := newTestCert(t, "old-leaf", oldCA, oldKey, false)
+	newLeaf, _ := newTestCert(t, "new-leaf", newCA, newKey, false)
+	verifies := func(r *RootReloader, leaf *x509.Certificate) bool {
+		r.lastChk = time.Time{}
+		_, err := leaf.Verify(x509.VerifyOptions{Roots: r.Pool()})
+		return err == nil
+	}
+
+	dir := t.TempDir()
+	_, err := NewRootReloader(dir)
+	testutil.FatalOnNoErr("empty directory", err, t)
+
+	writePEM(t, filepath.Join(dir, "old.pem"), oldCA)
+	r, err := NewRootReloader(dir)
+	testutil.FatalOnErr("NewRootReloader", err, t)
+	if !verifies(r, oldLeaf) || verifies(r, newLeaf) {
+		t.Fatal("only the old CA should be trusted")
+	}
+
+	// Adding the new CA is picked up once the check interval passes.
+	writePEM(t, filepath.Join(dir, "new.pem"), newCA)
+	r.lastChk = time.Now()
+	if _, err := newLeaf.Verify(x509.VerifyOptions{Roots: r.Pool()}); err == nil {
+		t.Fatal("roots reloaded inside the check interval")
+	}
+	if !verifies(r, oldLeaf) || !verifies(r, newLeaf) {
+		t.Fatal("both CAs should be trusted")
+	}
+	testutil.FatalOnErr("LastError", r.LastError(), t)
+
+	// A bad file keeps the current pool.
+	err = os.WriteFile(filepath.Join(dir, "bad.pem"), []byte("garbage"), 0600)
+	testutil.FatalOnErr("WriteFile", err, t)
+	if !verifies(r, newLeaf) {
+		t.Fatal("roots changed after a failed reload")
+	}
+	testutil.FatalOnNoErr("LastError after bad file", r.LastError(), t)
+
+	// Retiring the old CA.
+	for _, f := range []string{"bad.pem", "old.pem"} {
+		testutil.FatalOnErr("Remove", os.Remove(filepath.Join(dir, f)), t)
+	}
+	if verifies(r, oldLeaf) || !verifies(r, newLeaf) {
+		t.Fatal("only the new CA should be trusted")
+	}
+}
+
+func TestReloadingRootsHandshake(t *testing.T) {
+	oldCA, oldKey := newTestCA(t, "old")
+	newCA, newKey := newTestCA(t, "new")
+	dir := t.TempDir()
+	writePEM(t, filepath.Join(dir, "old.pem"), oldCA)
+	roots, err := NewRootReloader(dir)
+	testutil.FatalOnErr("NewRootReloader", err, t)
+
+	handshake := func(serverCert, clientCert tls.Certificate, serverName string) error {
+		t.Helper()
+		roots.lastChk = time.Time{}
+		serverCfg := applyTLSOptions(&tls.Config{
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			Certificates: []tls.Certificate{serverCert},
+		}, []TLSOption{withReloadingClientCAs(roots)})
+		clientCfg := applyTLSOptions(&tls.Config{
+			Certificates: []tls.Certificate{clientCert},
+			ServerName:   serverName,
+		}, []TLSOption{withReloadingRootCAs(roots)})
+		lis, err := net.Listen("tcp", "localhost:0")
+		testutil.FatalOnErr("Listen", err, t)
+		defer lis.Close()
+		errc := make(chan error, 1)
+		go func() {
+			sc, err := lis.Accept()
+			if err != nil {
+				errc <- err
+				return
+			}
+			defer sc.Close()
+			errc <- tls.Server(sc, serverCfg).Handshake()
+		}()
+		cc, err := net.Dial("tcp", lis.Addr().String())
+		testutil.FatalOnErr("Dial", err, t)
+		defer cc.Close()
+		if err := tls.Client(cc, clientCfg).Handshake(); err != nil {
+			cc.Close()
+			<-errc
+			return err
+		}
+		// With TLS 1.3 the client finishes before the server has
+		// verified it, so the server's result decides.
+		return <-errc
+	}
+
+	oldServer := newTestKeyPair(t, "localhost", oldCA, oldKey)
+	oldClient := newTestKeyPair(t, "client", oldCA, oldKey)
+	newServer := newTestKeyPair(t, "localhost", newCA, newKey)
+	newClient := newTestKeyPair(t, "client", newCA, newKey)
+
+	testutil.FatalOnErr("old CA", handshake(oldServer, oldClient, "localhost"), t)
+	testutil.FatalOnNoErr("wrong server name", handshake(oldServer, oldClient, "other"), t)
+	testutil.FatalOnNoErr("new server before trusted", handshake(newServer, oldClient, "localhost"), t)
+	testutil.FatalOnNoErr("new client before trusted", handshake(oldServer, newClient, "localhost"), t)
+
+	writePEM(t, filepath.Join(dir, "new.pem"), newCA)
+	testutil.FatalOnErr("new CA", handshake(newServer, newClient, "localhost"), t)
+	testutil.FatalOnErr("mixed CAs", handshake(oldServer, newClient, "localhost"), t)
 }
 
 type simpleLoader struct {
@@ -182,6 +337,8 @@ func TestLoadClientServerCredentials(t *testing.T) {
 	Register("simple", &simpleLoader{name: "simple"})
 	Register("errorCA", &simpleLoader{name: "errorCA"})
 	Register("errorCert", &simpleLoader{name: "errorCert"})
+	Register("reload", &reloadingLoader{simpleLoader{name: "reload"}})
+	Register("errorReload", &reloadingLoader{simpleLoader{name: "errorReload"}})
 
 	for _, tc := range []struct {
 		name    string
@@ -207,6 +364,15 @@ func TestLoadClientServerCredentials(t *testing.T) {
 			name:   "good creds",
 			loader: "simple",
 		},
+		{
+			name:    "bad reloading creds",
+			loader:  "errorReload",
+			wantErr: true,
+		},
+		{
+			name:   "good reloading creds",
+			loader: "reload",
+		},
 	} {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
@@ -275,6 +441,172 @@ func TestHealthCheck(t *testing.T) {
 	}
 }
 
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	b, err := os.ReadFile(src)
+	testutil.FatalOnErr("ReadFile", err, t)
+	err = os.WriteFile(dst, b, 0600)
+	testutil.FatalOnErr("WriteFile", err, t)
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "cert.key")
+
+	_, err := NewCertReloader(certFile, keyFile)
+	testutil.FatalOnNoErr("missing files", err, t)
+
+	copyFile(t, "testdata/leaf.pem", certFile)
+	copyFile(t, "testdata/leaf.key", keyFile)
+	r, err := NewCertReloader(certFile, keyFile)
+	testutil.FatalOnErr("NewCertReloader", err, t)
+	leaf := r.Certificate().Certificate[0]
+
+	// Nothing changed so this should be the same cert.
+	r.lastChk = time.Time{}
+	if got := r.Certificate().Certificate[0]; string(got) != string(leaf) {
+		t.Fatal("certificate changed without files changing")
+	}
+
+	// Rotate to the client cert and bump the mtimes so it's noticed.
+	copyFile(t, "testdata/client.pem", certFile)
+	copyFile(t, "testdata/client.key", keyFile)
+	future := time.Now().Add(time.Minute)
+	for _, f := range []string{certFile, keyFile} {
+		err := os.Chtimes(f, future, future)
+		testutil.FatalOnErr("Chtimes", err, t)
+	}
+
+	// Inside the check interval nothing is reloaded.
+	r.lastChk = time.Now()
+	if got := r.Certificate().Certificate[0]; string(got) != string(leaf) {
+		t.Fatal("certificate reloaded inside the check interval")
+	}
+
+	r.lastChk = time.Time{}
+	cert, err := r.GetCertificate(nil)
+	testutil.FatalOnErr("GetCertificate", err, t)
+	client := cert.Certificate[0]
+	if string(client) == string(leaf) {
+		t.Fatal("certificate didn't reload after files changed")
+	}
+	testutil.FatalOnErr("LastError", r.LastError(), t)
+
+	// A bad key shouldn't replace the current cert.
+	err = os.WriteFile(keyFile, []byte("garbage"), 0600)
+	testutil.FatalOnErr("WriteFile", err, t)
+	future = future.Add(time.Minute)
+	err = os.Chtimes(keyFile, future, future)
+	testutil.FatalOnErr("Chtimes", err, t)
+	r.lastChk = time.Time{}
+	cert, err = r.GetClientCertificate(nil)
+	testutil.FatalOnErr("GetClientCertificate", err, t)
+	if string(cert.Certificate[0]) != string(client) {
+		t.Fatal("certificate changed after a failed reload")
+	}
+	testutil.FatalOnNoErr("LastError after bad key", r.LastError(), t)
+
+	_, err = LoadServerTLSReloading(certFile, keyFile, nil)
+	testutil.FatalOnNoErr("LoadServerTLSReloading with bad key", err, t)
+	_, err = LoadClientTLSReloading(certFile, keyFile, nil)
+	testutil.FatalOnNoErr("LoadClientTLSReloading with bad key", err, t)
+	_, err = LoadServerTLSReloading("testdata/leaf.pem", "testdata/leaf.key", nil)
+	testutil.FatalOnErr("LoadServerTLSReloading", err, t)
+	_, err = LoadClientTLSReloading("testdata/client.pem", "testdata/client.key", nil)
+	testutil.FatalOnErr("LoadClientTLSReloading", err, t)
+}
+
+func TestRenewingSource(t *testing.T) {
+	ctx := context.Background()
+	load := func(name string) *tls.Certificate {
+		t.Helper()
+		cert, err := tls.LoadX509KeyPair("testdata/"+name+".pem", "testdata/"+name+".key")
+		testutil.FatalOnErr("LoadX509KeyPair", err, t)
+		return &cert
+	}
+	var next *tls.Certificate
+	var nextErr error
+	fetches := 0
+	fetch := func(context.Context) (*tls.Certificate, error) {
+		fetches++
+		return next, nextErr
+	}
+
+	nextErr = errors.New("fetch failed")
+	_, err := NewRenewingSource(ctx, fetch, 0)
+	testutil.FatalOnNoErr("NewRenewingSource with failing fetch", err, t)
+
+	nextErr = nil
+	next = load("leaf")
+	r, err := NewRenewingSource(ctx, fetch, 0)
+	testutil.FatalOnErr("NewRenewingSource", err, t)
+	leaf := r.Certificate().Certificate[0]
+
+	// A failed renewal keeps the current cert and isn't retried immediately.
+	r.renewAt = time.Time{}
+	nextErr = errors.New("fetch failed")
+	cert, err := r.GetCertificate(nil)
+	testutil.FatalOnErr("GetCertificate", err, t)
+	if string(cert.Certificate[0]) != string(leaf) {
+		t.Fatal("certificate changed after a failed renewal")
+	}
+	testutil.FatalOnNoErr("LastError after failed fetch", r.LastError(), t)
+	before := fetches
+	r.Certificate()
+	if fetches != before {
+		t.Fatal("fetch retried inside the retry interval")
+	}
+
+	// Once due it renews.
+	r.retryAt = time.Time{}
+	nextErr = nil
+	next = load("client")
+	cert, err = r.GetClientCertificate(nil)
+	testutil.FatalOnErr("GetClientCertificate", err, t)
+	if string(cert.Certificate[0]) == string(leaf) {
+		t.Fatal("certificate not renewed")
+	}
+	testutil.FatalOnErr("LastError", r.LastError(), t)
+	if cert.Leaf == nil {
+		t.Error("renewed certificate has no parsed leaf")
+	}
+
+	// A fresh cert isn't due for renewal until 2/3 of its lifetime unless the interval is shorter.
+	now := time.Now()
+	fresh := &tls.Certificate{
+		Certificate: [][]byte{{}},
+		Leaf:        &x509.Certificate{NotBefore: now.Add(-time.Hour), NotAfter: now.Add(2 * time.Hour)},
+	}
+	testutil.FatalOnErr("set", r.set(fresh, now), t)
+	if want := now.Add(time.Hour); !r.renewAt.Equal(want) {
+		t.Errorf("renewAt = %v, want %v", r.renewAt, want)
+	}
+	r.interval = time.Minute
+	testutil.FatalOnErr("set", r.set(fresh, now), t)
+	if want := now.Add(time.Minute); !r.renewAt.Equal(want) {
+		t.Errorf("renewAt with interval = %v, want %v", r.renewAt, want)
+	}
+	testutil.FatalOnNoErr("set empty", r.set(&tls.Certificate{}, now), t)
+}
+
+type reloadingLoader struct {
+	simpleLoader
+}
+
+func (r *reloadingLoader) ClientCertReloader(context.Context) (CertificateSource, error) {
+	if r.name == "errorReload" {
+		return nil, errors.New("ClientCertReloader error")
+	}
+	return NewCertReloader("testdata/client.pem", "testdata/client.key")
+}
+
+func (r *reloadingLoader) ServerCertReloader(context.Context) (CertificateSource, error) {
+	if r.name == "errorReload" {
+		return nil, errors.New("ServerCertReloader error")
+	}
+	return NewCertReloader("testdata/leaf.pem", "testdata/leaf.key")
+}
+
 type noopLoader struct {
 	name string
 	CredentialsLoader
@@ -302,3 +634,227 @@ func TestRegister(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTLSOptions(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		minVersion string
+		ciphers    string
+		curves     string
+		want       *tls.Config
+		wantErr    bool
+	}{
+		{
+			name: "defaults",
+			want: &tls.Config{MinVersion: DefaultMinTLSVersion},
+		},
+		{
+			name:       "all set",
+			minVersion: "TLS1.2",
+			ciphers:    "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			curves:     "P-384,x25519",
+			want: &tls.Config{
+				MinVersion:       tls.VersionTLS12,
+				CipherSuites:     []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384},
+				CurvePreferences: []tls.CurveID{tls.CurveP384, tls.X25519},
+			},
+		},
+		{
+			name:       "insecure version",
+			minVersion: "1.1",
+			wantErr:    true,
+		},
+		{
+			name:       "unknown version",
+			minVersion: "1.4",
+			wantErr:    true,
+		},
+		{
+			name:    "insecure cipher",
+			ciphers: "TLS_RSA_WITH_RC4_128_SHA",
+			wantErr: true,
+		},
+		{
+			name:    "unknown cipher",
+			ciphers: "TLS_NOPE",
+			wantErr: true,
+		},
+		{
+			name:    "unknown curve",
+			curves:  "P224",
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := ParseTLSOptions(tc.minVersion, tc.ciphers, tc.curves)
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if tc.wantErr {
+				return
+			}
+			got := applyTLSOptions(&tls.Config{MinVersion: DefaultMinTLSVersion}, opts)
+			if got.MinVersion != tc.want.MinVersion {
+				t.Errorf("MinVersion = %x, want %x", got.MinVersion, tc.want.MinVersion)
+			}
+			if !reflect.DeepEqual(got.CipherSuites, tc.want.CipherSuites) {
+				t.Errorf("CipherSuites = %v, want %v", got.CipherSuites, tc.want.CipherSuites)
+			}
+			if !reflect.DeepEqual(got.CurvePreferences, tc.want.CurvePreferences) {
+				t.Errorf("CurvePreferences = %v, want %v", got.CurvePreferences, tc.want.CurvePreferences)
+			}
+		})
+	}
+}
+
+func TestExpectedSANs(t *testing.T) {
+	spiffe, err := url.Parse("spiffe://example.com/db/1")
+	testutil.FatalOnErr("url.Parse", err, t)
+	cert := &x509.Certificate{
+		DNSNames: []string{"db1.example.com"},
+		URIs:     []*url.URL{spiffe},
+	}
+	rules := []SANRule{
+		{Target: "*.db.example.com", SANs: []string{"spiffe://example.com/db/*"}},
+		{Target: "*.web.example.com", SANs: []string{"*.web.example.com", "spiffe://example.com/web/*"}},
+	}
+	for _, tc := range []struct {
+		name       string
+		serverName string
+		certs      []*x509.Certificate
+		wantErr    bool
+	}{
+		{name: "SPIFFE ID matches", serverName: "db1.db.example.com", certs: []*x509.Certificate{cert}},
+		{name: "case insensitive target", serverName: "DB1.db.example.com", certs: []*x509.Certificate{cert}},
+		{name: "no SAN matches", serverName: "www.web.example.com", certs: []*x509.Certificate{cert}, wantErr: true},
+		{name: "no rule", serverName: "other.example.com", certs: []*x509.Certificate{cert}},
+		{name: "no certificate", serverName: "db1.db.example.com", wantErr: true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkSANs(rules, tls.ConnectionState{ServerName: tc.serverName, PeerCertificates: tc.certs})
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+		})
+	}
+
+	// The check must run alongside, not instead of, verification with
+	// reloading roots.
+	ca, caKey := newTestCA(t, "ca")
+	dir := t.TempDir()
+	writePEM(t, filepath.Join(dir, "ca.pem"), ca)
+	roots, err := NewRootReloader(dir)
+	testutil.FatalOnErr("NewRootReloader", err, t)
+	server, _ := newTestCert(t, "localhost", ca, caKey, false)
+	other, _ := newTestCA(t, "other")
+	for _, tc := range []struct {
+		name    string
+		rules   []SANRule
+		cert    *x509.Certificate
+		wantErr bool
+	}{
+		{name: "allowed", rules: []SANRule{{Target: "localhost", SANs: []string{"localhost"}}}, cert: server},
+		{name: "unexpected SAN", rules: []SANRule{{Target: "localhost", SANs: []string{"db*"}}}, cert: server, wantErr: true},
+		{name: "untrusted", rules: []SANRule{{Target: "localhost", SANs: []string{"*"}}}, cert: other, wantErr: true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := applyTLSOptions(&tls.Config{}, []TLSOption{WithExpectedSANs(tc.rules...), withReloadingRootCAs(roots)})
+			err := c.VerifyConnection(tls.ConnectionState{ServerName: "localhost", PeerCertificates: []*x509.Certificate{tc.cert}})
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+		})
+	}
+}
+
+func TestParseSANRules(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		spec    string
+		want    []SANRule
+		wantErr bool
+	}{
+		{name: "empty"},
+		{
+			name: "combined",
+			spec: "*.DB.example.com=spiffe://example.com/db/*, web=web.example.com,*.db.example.com=db.example.com",
+			want: []SANRule{
+				{Target: "*.db.example.com", SANs: []string{"spiffe://example.com/db/*", "db.example.com"}},
+				{Target: "web", SANs: []string{"web.example.com"}},
+			},
+		},
+		{name: "missing san", spec: "web=", wantErr: true},
+		{name: "missing separator", spec: "web", wantErr: true},
+		{name: "bad pattern", spec: "web=[", wantErr: true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseSANRules(tc.spec)
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ParseSANRules(%q) = %+v, want %+v", tc.spec, got, tc.want)
+			}
+		})
+	}
+}
+
+// newTestCA returns a new self signed CA.
+func newTestCA(t *testing.T, name string) (*x509.Certificate, crypto.Signer) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	testutil.FatalOnErr("CreateCertificate", err, t)
+	cert, err := x509.ParseCertificate(der)
+	testutil.FatalOnErr("ParseCertificate", err, t)
+	return cert, key
+}
+
+// newTestCert returns a certificate for `name` issued by `ca`.
+func newTestCert(t *testing.T, name string, ca *x509.Certificate, caKey crypto.Signer, isCA bool) (*x509.Certificate, crypto.Signer) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if isCA {
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, key.Public(), caKey)
+	testutil.FatalOnErr("CreateCertificate", err, t)
+	cert, err := x509.ParseCertificate(der)
+	testutil.FatalOnErr("ParseCertificate", err, t)
+	return cert, key
+}
+
+// newTestKeyPair returns a tls.Certificate for `name` issued by `ca`.
+func newTestKeyPair(t *testing.T, name string, ca *x509.Certificate, caKey crypto.Signer) tls.Certificate {
+	t.Helper()
+	cert, key := newTestCert(t, name, ca, caKey, false)
+	return tls.Certificate{Certificate: [][]byte{cert.Raw}, PrivateKey: key, Leaf: cert}
+}
+
+// writePEM writes `certs` to `filename` in PEM format.
+func writePEM(t *testing.T, filename string, certs ...*x509.Certificate) {
+	t.Helper()
+	var b []byte
+	for _, c := range certs {
+		b = append(b, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})...)
+	}
+	testutil.FatalOnErr("WriteFile", os.WriteFile(filename, b, 0600), t)
+}
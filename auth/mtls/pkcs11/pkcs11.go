@@ -0,0 +1,389 @@
+//go:build cgo
+// +build cgo
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package pkcs11 provides an mtls.CredentialsLoader whose private key stays
+// in a PKCS#11 token (an HSM, TPM via tpm2-pkcs11, a smart card, etc).
+//
+// Importing this package registers a loader named "pkcs11" which can then be
+// selected with --credential-source and configured with the --pkcs11-* flags.
+// The certificate chain is read from --pkcs11-cert, or if that's unset from the
+// certificate object on the token with the same label as the key. The same key
+// is used for client and server certificates.
+//
+// This package requires cgo as the PKCS#11 module is loaded at runtime.
+package pkcs11
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+)
+
+const loaderName = "pkcs11"
+
+// Config describes where to find the key and certificate.
+type Config struct {
+	// Module is the path to the vendor's PKCS#11 shared library.
+	Module string
+	// TokenLabel selects the token. If empty the first present token is used.
+	TokenLabel string
+	// PIN is the user PIN for the token. Ignored if PINFile is set.
+	PIN string
+	// PINFile if set contains the user PIN.
+	PINFile string
+	// KeyLabel is the CKA_LABEL of the private key.
+	KeyLabel string
+	// CertFile if set is a PEM file with the certificate chain for the key.
+	// Otherwise a certificate object with KeyLabel is read from the token.
+	CertFile string
+	// RootCAFile is a PEM file with the root of trust for peers.
+	RootCAFile string
+}
+
+var flagConfig = &Config{}
+
+// Name returns the loader to use to obtain mtls params from a PKCS#11 token.
+func Name() string { return loaderName }
+
+// loader implements mtls.CredentialsLoader. The token is opened on first use
+// and kept open for the life of the process.
+type loader struct {
+	cfg *Config
+
+	mu   sync.Mutex
+	cert *tls.Certificate
+}
+
+// NewLoader returns a loader using the token and key described by `cfg`. It
+// can be registered with mtls.Register under another name to use different
+// settings than the flags.
+func NewLoader(cfg *Config) mtls.CredentialsLoader {
+	return &loader{cfg: cfg}
+}
+
+func (l *loader) LoadClientCA(context.Context) (*x509.CertPool, error) {
+	return mtls.LoadRootOfTrust(l.cfg.RootCAFile)
+}
+
+func (l *loader) LoadRootCA(context.Context) (*x509.CertPool, error) {
+	return mtls.LoadRootOfTrust(l.cfg.RootCAFile)
+}
+
+func (l *loader) LoadClientCertificate(context.Context) (tls.Certificate, error) {
+	return l.certificate()
+}
+
+func (l *loader) LoadServerCertificate(context.Context) (tls.Certificate, error) {
+	return l.certificate()
+}
+
+func (l *loader) certificate() (tls.Certificate, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cert != nil {
+		return *l.cert, nil
+	}
+	cert, err := l.open()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	l.cert = cert
+	return *cert, nil
+}
+
+func (l *loader) pin() (string, error) {
+	if l.cfg.PINFile != "" {
+		b, err := os.ReadFile(l.cfg.PINFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	return l.cfg.PIN, nil
+}
+
+// open loads the module, logs in and returns a certificate backed by the token key.
+func (l *loader) open() (cert *tls.Certificate, err error) {
+	if l.cfg.Module == "" {
+		return nil, errors.New("no PKCS#11 module configured")
+	}
+	if l.cfg.KeyLabel == "" {
+		return nil, errors.New("no PKCS#11 key label configured")
+	}
+	ctx := pkcs11.New(l.cfg.Module)
+	if ctx == nil {
+		return nil, fmt.Errorf("can't load PKCS#11 module %s", l.cfg.Module)
+	}
+	defer func() {
+		if err != nil {
+			ctx.Destroy()
+		}
+	}()
+	if err := ctx.Initialize(); err != nil {
+		return nil, fmt.Errorf("PKCS#11 initialize: %w", err)
+	}
+	slot, err := l.findSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return nil, fmt.Errorf("PKCS#11 open session: %w", err)
+	}
+	pin, err := l.pin()
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Login(session, pkcs11.CKU_USER, pin); err != nil {
+		if e, ok := err.(pkcs11.Error); !ok || e != pkcs11.CKR_USER_ALREADY_LOGGED_IN {
+			return nil, fmt.Errorf("PKCS#11 login: %w", err)
+		}
+	}
+	key, err := findObject(ctx, session, pkcs11.CKO_PRIVATE_KEY, l.cfg.KeyLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain [][]byte
+	if l.cfg.CertFile != "" {
+		b, err := os.ReadFile(l.cfg.CertFile)
+		if err != nil {
+			return nil, err
+		}
+		chain = pemCertificates(b)
+	} else {
+		o, err := findObject(ctx, session, pkcs11.CKO_CERTIFICATE, l.cfg.KeyLabel)
+		if err != nil {
+			return nil, err
+		}
+		attrs, err := ctx.GetAttributeValue(session, o, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil)})
+		if err != nil {
+			return nil, fmt.Errorf("PKCS#11 read certificate: %w", err)
+		}
+		chain = [][]byte{attrs[0].Value}
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("no certificate found for PKCS#11 key")
+	}
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Certificate{
+		Certificate: chain,
+		Leaf:        leaf,
+		PrivateKey: &signer{
+			ctx:     ctx,
+			session: session,
+			key:     key,
+			pub:     leaf.PublicKey,
+		},
+	}, nil
+}
+
+func (l *loader) findSlot(ctx *pkcs11.Ctx) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("PKCS#11 slot list: %w", err)
+	}
+	for _, s := range slots {
+		if l.cfg.TokenLabel == "" {
+			return s, nil
+		}
+		info, err := ctx.GetTokenInfo(s)
+		if err == nil && info.Label == l.cfg.TokenLabel {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("no PKCS#11 token with label %q", l.cfg.TokenLabel)
+}
+
+func findObject(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, class uint, label string) (pkcs11.ObjectHandle, error) {
+	tmpl := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := ctx.FindObjectsInit(session, tmpl); err != nil {
+		return 0, fmt.Errorf("PKCS#11 find: %w", err)
+	}
+	defer ctx.FindObjectsFinal(session)
+	objs, _, err := ctx.FindObjects(session, 1)
+	if err != nil {
+		return 0, fmt.Errorf("PKCS#11 find: %w", err)
+	}
+	if len(objs) == 0 {
+		return 0, fmt.Errorf("no PKCS#11 object of class %d with label %q", class, label)
+	}
+	return objs[0], nil
+}
+
+// signer implements crypto.Signer using a private key on the token.
+type signer struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	key     pkcs11.ObjectHandle
+	pub     crypto.PublicKey
+
+	// Sessions can only run one operation at a time.
+	mu sync.Mutex
+}
+
+// Public implements crypto.Signer.
+func (s *signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign implements crypto.Signer.
+func (s *signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var mech *pkcs11.Mechanism
+	data := digest
+	switch s.pub.(type) {
+	case *ecdsa.PublicKey:
+		mech = pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)
+	case *rsa.PublicKey:
+		if pss, ok := opts.(*rsa.PSSOptions); ok {
+			params, err := pssParams(pss, len(digest))
+			if err != nil {
+				return nil, err
+			}
+			mech = pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_PSS, params)
+			break
+		}
+		var err error
+		data, err = pkcs1DigestInfo(opts.HashFunc(), digest)
+		if err != nil {
+			return nil, err
+		}
+		mech = pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS, nil)
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", s.pub)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ctx.SignInit(s.session, []*pkcs11.Mechanism{mech}, s.key); err != nil {
+		return nil, fmt.Errorf("PKCS#11 sign init: %w", err)
+	}
+	sig, err := s.ctx.Sign(s.session, data)
+	if err != nil {
+		return nil, fmt.Errorf("PKCS#11 sign: %w", err)
+	}
+	if _, ok := s.pub.(*ecdsa.PublicKey); ok {
+		return ecdsaASN1(sig)
+	}
+	return sig, nil
+}
+
+var hashMechanisms = map[crypto.Hash]struct{ hash, mgf uint }{
+	crypto.SHA256: {pkcs11.CKM_SHA256, pkcs11.CKG_MGF1_SHA256},
+	crypto.SHA384: {pkcs11.CKM_SHA384, pkcs11.CKG_MGF1_SHA384},
+	crypto.SHA512: {pkcs11.CKM_SHA512, pkcs11.CKG_MGF1_SHA512},
+}
+
+func pssParams(opts *rsa.PSSOptions, digestLen int) ([]byte, error) {
+	m, ok := hashMechanisms[opts.Hash]
+	if !ok {
+		return nil, fmt.Errorf("unsupported PSS hash %v", opts.Hash)
+	}
+	salt := opts.SaltLength
+	if salt == rsa.PSSSaltLengthEqualsHash || salt == rsa.PSSSaltLengthAuto {
+		// TLS always uses a salt the length of the hash.
+		salt = digestLen
+	}
+	return pkcs11.NewPSSParams(m.hash, m.mgf, uint(salt)), nil
+}
+
+// DER prefixes of the DigestInfo structure for PKCS#1 v1.5 signatures (RFC 8017 9.2).
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// pkcs1DigestInfo returns the data CKM_RSA_PKCS needs to produce a PKCS#1 v1.5
+// signature of `digest`, which the mechanism doesn't wrap itself.
+func pkcs1DigestInfo(h crypto.Hash, digest []byte) ([]byte, error) {
+	prefix, ok := digestInfoPrefixes[h]
+	if !ok {
+		return nil, fmt.Errorf("unsupported PKCS#1 hash %v", h)
+	}
+	if len(digest) != h.Size() {
+		return nil, fmt.Errorf("digest length %d doesn't match %v", len(digest), h)
+	}
+	return append(append([]byte{}, prefix...), digest...), nil
+}
+
+// ecdsaASN1 converts a PKCS#11 ECDSA signature (r || s) into the ASN.1 form Go expects.
+func ecdsaASN1(sig []byte) ([]byte, error) {
+	if len(sig) == 0 || len(sig)%2 != 0 {
+		return nil, fmt.Errorf("invalid ECDSA signature length %d", len(sig))
+	}
+	n := len(sig) / 2
+	return asn1.Marshal(struct{ R, S *big.Int }{
+		R: new(big.Int).SetBytes(sig[:n]),
+		S: new(big.Int).SetBytes(sig[n:]),
+	})
+}
+
+// pemCertificates returns the DER bytes of each certificate in `b`.
+func pemCertificates(b []byte) [][]byte {
+	var out [][]byte
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			return out
+		}
+		if block.Type == "CERTIFICATE" {
+			out = append(out, block.Bytes)
+		}
+	}
+}
+
+func init() {
+	flag.StringVar(&flagConfig.Module, "pkcs11-module", "", "Path to the PKCS#11 module (shared library) for the pkcs11 credential source")
+	flag.StringVar(&flagConfig.TokenLabel, "pkcs11-token-label", "", "Label of the PKCS#11 token holding the key. If empty the first token found is used.")
+	flag.StringVar(&flagConfig.PINFile, "pkcs11-pin-file", "", "File containing the PKCS#11 user PIN. If unset $PKCS11_PIN is used.")
+	flag.StringVar(&flagConfig.KeyLabel, "pkcs11-key-label", "", "Label of the private key on the PKCS#11 token")
+	flag.StringVar(&flagConfig.CertFile, "pkcs11-cert", "", "PEM certificate chain for the PKCS#11 key. If unset the certificate with the key's label is read from the token.")
+	flag.StringVar(&flagConfig.RootCAFile, "pkcs11-root-ca", "", "The root of trust for remote identities, PEM format")
+	flagConfig.PIN = os.Getenv("PKCS11_PIN")
+
+	if err := mtls.Register(loaderName, NewLoader(flagConfig)); err != nil {
+		panic(err)
+	}
+}
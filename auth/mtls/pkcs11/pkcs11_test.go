@@ -0,0 +1,116 @@
+//go:build cgo
+// +build cgo
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package pkcs11
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"os"
+	"testing"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestPKCS1DigestInfo(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	for _, tc := range []struct {
+		hash   crypto.Hash
+		digest []byte
+	}{
+		{crypto.SHA256, func() []byte { d := sha256.Sum256([]byte("hello")); return d[:] }()},
+		{crypto.SHA384, func() []byte { d := sha512.Sum384([]byte("hello")); return d[:] }()},
+		{crypto.SHA512, func() []byte { d := sha512.Sum512([]byte("hello")); return d[:] }()},
+	} {
+		data, err := pkcs1DigestInfo(tc.hash, tc.digest)
+		testutil.FatalOnErr(tc.hash.String(), err, t)
+		// Hash 0 signs the data as given, which is what CKM_RSA_PKCS does.
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, 0, data)
+		testutil.FatalOnErr("SignPKCS1v15", err, t)
+		err = rsa.VerifyPKCS1v15(&key.PublicKey, tc.hash, tc.digest, sig)
+		testutil.FatalOnErr("VerifyPKCS1v15 "+tc.hash.String(), err, t)
+	}
+	_, err = pkcs1DigestInfo(crypto.MD5, make([]byte, 16))
+	testutil.FatalOnNoErr("MD5", err, t)
+	_, err = pkcs1DigestInfo(crypto.SHA256, make([]byte, 20))
+	testutil.FatalOnNoErr("short digest", err, t)
+}
+
+func TestECDSAASN1(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	digest := sha256.Sum256([]byte("hello"))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	testutil.FatalOnErr("Sign", err, t)
+	// PKCS#11 returns fixed width big endian r || s.
+	raw := make([]byte, 64)
+	r.FillBytes(raw[:32])
+	s.FillBytes(raw[32:])
+	sig, err := ecdsaASN1(raw)
+	testutil.FatalOnErr("ecdsaASN1", err, t)
+	if !ecdsa.VerifyASN1(&key.PublicKey, digest[:], sig) {
+		t.Error("converted signature doesn't verify")
+	}
+	_, err = ecdsaASN1(raw[:63])
+	testutil.FatalOnNoErr("odd length", err, t)
+}
+
+func TestPEMCertificates(t *testing.T) {
+	b, err := os.ReadFile("../testdata/leaf.pem")
+	testutil.FatalOnErr("ReadFile", err, t)
+	key, err := os.ReadFile("../testdata/leaf.key")
+	testutil.FatalOnErr("ReadFile", err, t)
+	if got := pemCertificates(append(append(b, key...), b...)); len(got) != 2 {
+		t.Errorf("got %d certificates, want 2", len(got))
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name string
+		cfg  *Config
+	}{
+		{
+			name: "no module",
+			cfg:  &Config{KeyLabel: "key"},
+		},
+		{
+			name: "no key label",
+			cfg:  &Config{Module: "/no/such/module.so"},
+		},
+		{
+			name: "missing module",
+			cfg:  &Config{Module: "/no/such/module.so", KeyLabel: "key"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewLoader(tc.cfg).LoadClientCertificate(ctx)
+			testutil.FatalOnNoErr(tc.name, err, t)
+		})
+	}
+}
@@ -0,0 +1,327 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// A CertificateSource supplies the certificate to present for each TLS handshake
+// rather than a fixed one. Implementations are expected to handle rotation
+// internally (i.e. CertReloader, or a SPIFFE Workload API source).
+type CertificateSource interface {
+	// GetCertificate implements the tls.Config callback of the same name.
+	GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error)
+
+	// GetClientCertificate implements the tls.Config callback of the same name.
+	GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error)
+}
+
+// CertReloader holds a certificate/key pair loaded from disk and reloads it
+// whenever either file changes. It's intended to be plugged into the
+// GetCertificate/GetClientCertificate callbacks of a tls.Config so that
+// long running processes pick up rotated leaf certificates on the next
+// handshake without a restart.
+//
+// If a reload fails (i.e. the cert has been written but not the key yet)
+// the previously loaded pair continues to be served and the reload is
+// retried on a later handshake.
+type CertReloader struct {
+	certFile, keyFile string
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	certMod  time.Time
+	keyMod   time.Time
+	lastErr  error
+	checkInt time.Duration
+	lastChk  time.Time
+}
+
+// NewCertReloader loads the given certificate/key pair and returns a
+// CertReloader which serves it. An error is returned if the initial load fails.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	c := &CertReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		checkInt: time.Second,
+	}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// modTimes returns the modification times of the cert and key files.
+func (c *CertReloader) modTimes() (time.Time, time.Time, error) {
+	cs, err := os.Stat(c.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	ks, err := os.Stat(c.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return cs.ModTime(), ks.ModTime(), nil
+}
+
+// reload must be called with c.mu held (or before c is shared).
+func (c *CertReloader) reload() error {
+	certMod, keyMod, err := c.modTimes()
+	if err != nil {
+		return fmt.Errorf("could not stat credentials: %w", err)
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("could not read credentials: %w", err)
+	}
+	c.cert = &cert
+	c.certMod = certMod
+	c.keyMod = keyMod
+	return nil
+}
+
+// Certificate returns the current certificate, reloading it first if
+// the underlying files have changed. Files are checked at most once a second.
+func (c *CertReloader) Certificate() *tls.Certificate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if now.Sub(c.lastChk) < c.checkInt {
+		return c.cert
+	}
+	c.lastChk = now
+	certMod, keyMod, err := c.modTimes()
+	if err != nil {
+		c.lastErr = err
+		return c.cert
+	}
+	if certMod.Equal(c.certMod) && keyMod.Equal(c.keyMod) {
+		return c.cert
+	}
+	c.lastErr = c.reload()
+	return c.cert
+}
+
+// LastError returns the error (if any) from the most recent reload attempt.
+func (c *CertReloader) LastError() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastErr
+}
+
+// GetCertificate implements the tls.Config callback of the same name.
+func (c *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.Certificate(), nil
+}
+
+// GetClientCertificate implements the tls.Config callback of the same name.
+func (c *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return c.Certificate(), nil
+}
+
+// RootReloader holds a root of trust loaded with LoadRootOfTrust and reloads
+// it whenever the files it was loaded from change, including files being
+// added to or removed from a directory or glob. This allows new CAs to be
+// trusted (or old ones retired) without restarting long running processes.
+//
+// As with CertReloader a failed reload leaves the current pool in place and
+// is retried later.
+type RootReloader struct {
+	path string
+
+	mu       sync.Mutex
+	pool     *x509.CertPool
+	sig      string
+	lastErr  error
+	checkInt time.Duration
+	lastChk  time.Time
+}
+
+// NewRootReloader loads the root of trust at `path` (see LoadRootOfTrust)
+// and returns a RootReloader which serves it. An error is returned if the
+// initial load fails.
+func NewRootReloader(path string) (*RootReloader, error) {
+	r := &RootReloader{
+		path:     path,
+		checkInt: time.Second,
+	}
+	sig, err := r.signature()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.reload(sig); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// signature returns a string which changes if the set of files making up
+// the root of trust, or any of their contents, changes.
+func (r *RootReloader) signature() (string, error) {
+	files, err := rootFiles(r.path)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return "", fmt.Errorf("could not stat CA: %w", err)
+		}
+		fmt.Fprintf(&sb, "%s\x00%d\x00%d\x00", f, fi.ModTime().UnixNano(), fi.Size())
+	}
+	return sb.String(), nil
+}
+
+// reload must be called with r.mu held (or before r is shared).
+func (r *RootReloader) reload(sig string) error {
+	pool, err := LoadRootOfTrust(r.path)
+	if err != nil {
+		return err
+	}
+	r.pool = pool
+	r.sig = sig
+	return nil
+}
+
+// Pool returns the current root of trust, reloading it first if the
+// underlying files have changed. Files are checked at most once a second.
+func (r *RootReloader) Pool() *x509.CertPool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if now.Sub(r.lastChk) < r.checkInt {
+		return r.pool
+	}
+	r.lastChk = now
+	sig, err := r.signature()
+	if err != nil {
+		r.lastErr = err
+		return r.pool
+	}
+	if sig == r.sig {
+		return r.pool
+	}
+	r.lastErr = r.reload(sig)
+	return r.pool
+}
+
+// LastError returns the error (if any) from the most recent reload attempt.
+func (r *RootReloader) LastError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastErr
+}
+
+// withReloadingClientCAs returns an option changing a server config to
+// verify clients with the current pool from `roots` on every handshake.
+func withReloadingClientCAs(roots *RootReloader) TLSOption {
+	return func(c *tls.Config) {
+		c.ClientCAs = roots.Pool()
+		c.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cfg := c.Clone()
+			cfg.GetConfigForClient = nil
+			cfg.ClientCAs = roots.Pool()
+			return cfg, nil
+		}
+	}
+}
+
+// withReloadingRootCAs returns an option changing a client config to verify
+// servers with the current pool from `roots` on every handshake. As tls.Config
+// has no hook to supply RootCAs per handshake the standard verification is
+// replaced with an equivalent one in VerifyConnection, which then runs any
+// VerifyConnection already set.
+func withReloadingRootCAs(roots *RootReloader) TLSOption {
+	return func(c *tls.Config) {
+		c.RootCAs = nil
+		c.InsecureSkipVerify = true
+		prev := c.VerifyConnection
+		c.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("server presented no certificate")
+			}
+			if cs.ServerName == "" {
+				return errors.New("no server name to verify")
+			}
+			intermediates := x509.NewCertPool()
+			for _, cert := range cs.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+			_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+				Roots:         roots.Pool(),
+				Intermediates: intermediates,
+				DNSName:       cs.ServerName,
+			})
+			if err != nil || prev == nil {
+				return err
+			}
+			return prev(cs)
+		}
+	}
+}
+
+// NewReloadingServerCredentials creates transport credentials for a SansShell server
+// which present the current certificate from `source` on every handshake.
+func NewReloadingServerCredentials(source CertificateSource, CAPool *x509.CertPool, opts ...TLSOption) credentials.TransportCredentials {
+	return credentials.NewTLS(applyTLSOptions(&tls.Config{
+		ClientAuth:     tls.RequireAndVerifyClientCert,
+		GetCertificate: source.GetCertificate,
+		ClientCAs:      CAPool,
+		MinVersion:     DefaultMinTLSVersion,
+	}, opts))
+}
+
+// NewReloadingClientCredentials creates transport credentials for SansShell clients
+// which present the current certificate from `source` on every handshake.
+func NewReloadingClientCredentials(source CertificateSource, CAPool *x509.CertPool, opts ...TLSOption) credentials.TransportCredentials {
+	return credentials.NewTLS(applyTLSOptions(&tls.Config{
+		GetClientCertificate: source.GetClientCertificate,
+		RootCAs:              CAPool,
+		MinVersion:           DefaultMinTLSVersion,
+	}, opts))
+}
+
+// LoadServerTLSReloading is the same as LoadServerTLS except the certificate and
+// key are reloaded from disk whenever they change.
+func LoadServerTLSReloading(certFile, keyFile string, CAPool *x509.CertPool, opts ...TLSOption) (credentials.TransportCredentials, error) {
+	r, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewReloadingServerCredentials(r, CAPool, opts...), nil
+}
+
+// LoadClientTLSReloading is the same as LoadClientTLS except the certificate and
+// key are reloaded from disk whenever they change.
+func LoadClientTLSReloading(certFile, keyFile string, CAPool *x509.CertPool, opts ...TLSOption) (credentials.TransportCredentials, error) {
+	r, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewReloadingClientCredentials(r, CAPool, opts...), nil
+}
@@ -0,0 +1,137 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// Wait at least this long between failed fetches.
+	renewRetryInterval = 10 * time.Second
+
+	// Limit on how long a single fetch may block a handshake.
+	renewFetchTimeout = 30 * time.Second
+)
+
+// A FetchFunc obtains a new certificate (i.e. by issuing one from a CA or
+// reading it from a secret store).
+type FetchFunc func(context.Context) (*tls.Certificate, error)
+
+// RenewingSource is a CertificateSource for certificates obtained from a
+// remote service. The current certificate is refetched once two thirds of
+// its validity period has passed, and optionally also after a fixed interval.
+//
+// Fetches happen on the handshake which notices renewal is due. If one fails
+// the current certificate continues to be served and the fetch is retried
+// on a later handshake.
+type RenewingSource struct {
+	fetch    FetchFunc
+	interval time.Duration
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	renewAt time.Time
+	retryAt time.Time
+	lastErr error
+}
+
+// NewRenewingSource fetches an initial certificate with `fetch` and returns a
+// RenewingSource which serves it. If `interval` is non-zero the certificate is
+// also refetched at least that often, which allows picking up certificates
+// rotated by another process before they near expiry.
+func NewRenewingSource(ctx context.Context, fetch FetchFunc, interval time.Duration) (*RenewingSource, error) {
+	r := &RenewingSource{
+		fetch:    fetch,
+		interval: interval,
+	}
+	cert, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.set(cert, time.Now()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// set installs cert and computes when it should be renewed. Must be called
+// with r.mu held (or before r is shared).
+func (r *RenewingSource) set(cert *tls.Certificate, now time.Time) error {
+	if len(cert.Certificate) == 0 {
+		return errors.New("fetched certificate is empty")
+	}
+	leaf := cert.Leaf
+	if leaf == nil {
+		var err error
+		leaf, err = x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return err
+		}
+		cert.Leaf = leaf
+	}
+	r.cert = cert
+	r.renewAt = leaf.NotBefore.Add(leaf.NotAfter.Sub(leaf.NotBefore) * 2 / 3)
+	if r.interval > 0 && now.Add(r.interval).Before(r.renewAt) {
+		r.renewAt = now.Add(r.interval)
+	}
+	return nil
+}
+
+// Certificate returns the current certificate, fetching a new one first
+// if renewal is due.
+func (r *RenewingSource) Certificate() *tls.Certificate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if now.Before(r.renewAt) || now.Before(r.retryAt) {
+		return r.cert
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), renewFetchTimeout)
+	defer cancel()
+	cert, err := r.fetch(ctx)
+	if err == nil {
+		err = r.set(cert, now)
+	}
+	r.lastErr = err
+	if err != nil {
+		r.retryAt = now.Add(renewRetryInterval)
+	}
+	return r.cert
+}
+
+// LastError returns the error (if any) from the most recent fetch.
+func (r *RenewingSource) LastError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastErr
+}
+
+// GetCertificate implements the tls.Config callback of the same name.
+func (r *RenewingSource) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// GetClientCertificate implements the tls.Config callback of the same name.
+func (r *RenewingSource) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
@@ -0,0 +1,127 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// A SANRule requires servers dialed with a name matching Target to present
+// a certificate with a subject alternative name (DNS name, URI such as a
+// SPIFFE ID, IP address or email) matching one of SANs. Both are patterns
+// as accepted by path.Match, i.e. "*.db.example.com" or
+// "spiffe://example.com/db/*".
+type SANRule struct {
+	Target string
+	SANs   []string
+}
+
+// WithExpectedSANs returns an option for client credentials which checks
+// servers against the first of `rules` whose Target matches the name they
+// were dialed with, in addition to normal verification. Servers matching no
+// rule are only verified normally. This stops a server with a valid
+// certificate for another name (i.e. reached by a hijacked DNS entry) from
+// impersonating one of a group of targets.
+func WithExpectedSANs(rules ...SANRule) TLSOption {
+	return func(c *tls.Config) {
+		prev := c.VerifyConnection
+		c.VerifyConnection = func(cs tls.ConnectionState) error {
+			if prev != nil {
+				if err := prev(cs); err != nil {
+					return err
+				}
+			}
+			return checkSANs(rules, cs)
+		}
+	}
+}
+
+// checkSANs verifies the server in `cs` against the first matching rule.
+func checkSANs(rules []SANRule, cs tls.ConnectionState) error {
+	for _, r := range rules {
+		if ok, _ := path.Match(r.Target, strings.ToLower(cs.ServerName)); !ok {
+			continue
+		}
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("server presented no certificate")
+		}
+		sans := certSANs(cs.PeerCertificates[0])
+		for _, p := range r.SANs {
+			for _, s := range sans {
+				if ok, _ := path.Match(p, s); ok {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("certificate for %s has none of the expected SANs %v (has %v)", cs.ServerName, r.SANs, sans)
+	}
+	return nil
+}
+
+// certSANs returns all subject alternative names in cert as strings.
+func certSANs(cert *x509.Certificate) []string {
+	var out []string
+	for _, n := range cert.DNSNames {
+		out = append(out, strings.ToLower(n))
+	}
+	for _, u := range cert.URIs {
+		out = append(out, u.String())
+	}
+	for _, ip := range cert.IPAddresses {
+		out = append(out, ip.String())
+	}
+	out = append(out, cert.EmailAddresses...)
+	return out
+}
+
+// ParseSANRules parses a comma separated list of target=san entries into
+// rules, i.e. "*.db.example.com=spiffe://example.com/db/*". Entries for
+// the same target are combined into one rule accepting any of their SANs.
+// Rules are kept in the order their targets first appear.
+func ParseSANRules(spec string) ([]SANRule, error) {
+	var out []SANRule
+	index := make(map[string]int)
+	for _, e := range strings.Split(spec, ",") {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid SAN rule %q, must be target=san", e)
+		}
+		target := strings.ToLower(kv[0])
+		for _, p := range []string{target, kv[1]} {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q in SAN rule %q: %v", p, e, err)
+			}
+		}
+		i, ok := index[target]
+		if !ok {
+			i = len(out)
+			index[target] = i
+			out = append(out, SANRule{Target: target})
+		}
+		out[i].SANs = append(out[i].SANs, kv[1])
+	}
+	return out, nil
+}
@@ -26,41 +26,58 @@ import (
 )
 
 // LoadServerCredentials returns transport credentials for a SansShell server as
-// retrieved from the specified `loaderName`
-func LoadServerCredentials(ctx context.Context, loaderName string) (credentials.TransportCredentials, error) {
+// retrieved from the specified `loaderName`, with any TLS options applied.
+func LoadServerCredentials(ctx context.Context, loaderName string, opts ...TLSOption) (credentials.TransportCredentials, error) {
 	loader, err := Loader(loaderName)
 	if err != nil {
 		return nil, err
 	}
 
-	pool, err := loader.LoadClientCA(ctx)
-	if err != nil {
-		return nil, err
+	var pool *x509.CertPool
+	rrl, reloadRoots := loader.(ReloadingRootsLoader)
+	if reloadRoots {
+		roots, err := rrl.ClientCAReloader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts[:len(opts):len(opts)], withReloadingClientCAs(roots))
+	} else {
+		pool, err = loader.LoadClientCA(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if rl, ok := loader.(ReloadingCredentialsLoader); ok {
+		r, err := rl.ServerCertReloader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return NewReloadingServerCredentials(r, pool, opts...), nil
 	}
 	cert, err := loader.LoadServerCertificate(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return NewServerCredentials(cert, pool), nil
+	return NewServerCredentials(cert, pool, opts...), nil
 }
 
 // NewServerCredentials creates transport credentials for a SansShell server.
-func NewServerCredentials(cert tls.Certificate, CAPool *x509.CertPool) credentials.TransportCredentials {
-	return credentials.NewTLS(&tls.Config{
+func NewServerCredentials(cert tls.Certificate, CAPool *x509.CertPool, opts ...TLSOption) credentials.TransportCredentials {
+	return credentials.NewTLS(applyTLSOptions(&tls.Config{
 		ClientAuth:   tls.RequireAndVerifyClientCert,
 		Certificates: []tls.Certificate{cert},
 		ClientCAs:    CAPool,
-		MinVersion:   tls.VersionTLS13,
-	})
+		MinVersion:   DefaultMinTLSVersion,
+	}, opts))
 }
 
 // LoadServerTLS reads the certificates and keys from disk at the supplied paths,
 // and assembles them into a set of TransportCredentials for the gRPC server.
-func LoadServerTLS(clientCertFile, clientKeyFile string, CAPool *x509.CertPool) (credentials.TransportCredentials, error) {
+func LoadServerTLS(clientCertFile, clientKeyFile string, CAPool *x509.CertPool, opts ...TLSOption) (credentials.TransportCredentials, error) {
 	// Read in client credentials
 	cert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
 	if err != nil {
 		return nil, fmt.Errorf("reading client credentials: %w", err)
 	}
-	return NewServerCredentials(cert, CAPool), nil
+	return NewServerCredentials(cert, CAPool, opts...), nil
 }
@@ -0,0 +1,180 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package spiffe provides an mtls.CredentialsLoader which obtains X.509 SVIDs
+// and trust bundles from a SPIFFE Workload API endpoint (such as spire-agent)
+// instead of PEM files on disk.
+//
+// Importing this package registers a loader named "spiffe" which can then be
+// selected with --credential-source. The SVID presented on each handshake is
+// always the latest one delivered by the Workload API so rotation needs no restart.
+// The trust bundle is read once when credentials are created.
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"sync"
+
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+
+	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+)
+
+const loaderName = "spiffe"
+
+var socketAddr string
+
+// Name returns the loader to use to obtain mtls params from the SPIFFE Workload API.
+func Name() string { return loaderName }
+
+// spiffeLoader implements mtls.ReloadingCredentialsLoader using a
+// workloadapi.X509Source which is created on first use.
+type spiffeLoader struct {
+	mu     sync.Mutex
+	source *workloadapi.X509Source
+}
+
+func (s *spiffeLoader) getSource(ctx context.Context) (*workloadapi.X509Source, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.source != nil {
+		return s.source, nil
+	}
+	var opts []workloadapi.X509SourceOption
+	if socketAddr != "" {
+		opts = append(opts, workloadapi.WithClientOptions(workloadapi.WithAddr(socketAddr)))
+	}
+	src, err := workloadapi.NewX509Source(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create SPIFFE X509 source: %w", err)
+	}
+	s.source = src
+	return src, nil
+}
+
+func (s *spiffeLoader) pool(ctx context.Context) (*x509.CertPool, error) {
+	src, err := s.getSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	svid, err := src.GetX509SVID()
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := src.GetX509BundleForTrustDomain(svid.ID.TrustDomain())
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	for _, c := range bundle.X509Authorities() {
+		pool.AddCert(c)
+	}
+	return pool, nil
+}
+
+func (s *spiffeLoader) certificate(ctx context.Context) (tls.Certificate, error) {
+	src, err := s.getSource(ctx)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	cert, err := NewCertificateSource(src).GetCertificate(nil)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return *cert, nil
+}
+
+func (s *spiffeLoader) LoadClientCA(ctx context.Context) (*x509.CertPool, error) {
+	return s.pool(ctx)
+}
+
+func (s *spiffeLoader) LoadRootCA(ctx context.Context) (*x509.CertPool, error) {
+	return s.pool(ctx)
+}
+
+func (s *spiffeLoader) LoadClientCertificate(ctx context.Context) (tls.Certificate, error) {
+	return s.certificate(ctx)
+}
+
+func (s *spiffeLoader) LoadServerCertificate(ctx context.Context) (tls.Certificate, error) {
+	return s.certificate(ctx)
+}
+
+func (s *spiffeLoader) ClientCertReloader(ctx context.Context) (mtls.CertificateSource, error) {
+	src, err := s.getSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewCertificateSource(src), nil
+}
+
+func (s *spiffeLoader) ServerCertReloader(ctx context.Context) (mtls.CertificateSource, error) {
+	return s.ClientCertReloader(ctx)
+}
+
+// certSource adapts an x509svid.Source into an mtls.CertificateSource.
+type certSource struct {
+	src x509svid.Source
+}
+
+// NewCertificateSource returns an mtls.CertificateSource which presents the
+// current SVID from `src` (generally a *workloadapi.X509Source) on each handshake.
+func NewCertificateSource(src x509svid.Source) mtls.CertificateSource {
+	return &certSource{src: src}
+}
+
+func (c *certSource) cert() (*tls.Certificate, error) {
+	svid, err := c.src.GetX509SVID()
+	if err != nil {
+		return nil, err
+	}
+	if len(svid.Certificates) == 0 {
+		return nil, errors.New("SVID has no certificates")
+	}
+	cert := &tls.Certificate{
+		PrivateKey: svid.PrivateKey,
+		Leaf:       svid.Certificates[0],
+	}
+	for _, c := range svid.Certificates {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+	return cert, nil
+}
+
+// GetCertificate implements mtls.CertificateSource.
+func (c *certSource) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.cert()
+}
+
+// GetClientCertificate implements mtls.CertificateSource.
+func (c *certSource) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return c.cert()
+}
+
+func init() {
+	socketAddr, _ = workloadapi.GetDefaultAddress()
+	flag.StringVar(&socketAddr, "spiffe-endpoint-socket", socketAddr, "Address of the SPIFFE Workload API (i.e. unix:///tmp/agent.sock). Defaults to $SPIFFE_ENDPOINT_SOCKET")
+
+	if err := mtls.Register(loaderName, &spiffeLoader{}); err != nil {
+		panic(err)
+	}
+}
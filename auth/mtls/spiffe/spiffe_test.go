@@ -0,0 +1,80 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package spiffe
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+
+	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+type fakeSource struct {
+	svid *x509svid.SVID
+	err  error
+}
+
+func (f *fakeSource) GetX509SVID() (*x509svid.SVID, error) {
+	return f.svid, f.err
+}
+
+func TestCertificateSource(t *testing.T) {
+	pair, err := tls.LoadX509KeyPair("../testdata/leaf.pem", "../testdata/leaf.key")
+	testutil.FatalOnErr("LoadX509KeyPair", err, t)
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	testutil.FatalOnErr("ParseCertificate", err, t)
+
+	src := &fakeSource{
+		svid: &x509svid.SVID{
+			Certificates: []*x509.Certificate{leaf},
+			PrivateKey:   pair.PrivateKey.(crypto.Signer),
+		},
+	}
+	cs := NewCertificateSource(src)
+	cert, err := cs.GetCertificate(nil)
+	testutil.FatalOnErr("GetCertificate", err, t)
+	if got, want := string(cert.Certificate[0]), string(leaf.Raw); got != want {
+		t.Fatal("GetCertificate returned the wrong certificate")
+	}
+	if cert.Leaf != leaf {
+		t.Fatal("GetCertificate didn't set Leaf")
+	}
+	_, err = cs.GetClientCertificate(nil)
+	testutil.FatalOnErr("GetClientCertificate", err, t)
+
+	src.svid = &x509svid.SVID{}
+	_, err = cs.GetCertificate(nil)
+	testutil.FatalOnNoErr("empty SVID", err, t)
+
+	src.err = errors.New("no SVID")
+	_, err = cs.GetClientCertificate(nil)
+	testutil.FatalOnNoErr("source error", err, t)
+}
+
+func TestRegistered(t *testing.T) {
+	l, err := mtls.Loader(Name())
+	testutil.FatalOnErr("Loader", err, t)
+	if _, ok := l.(mtls.ReloadingCredentialsLoader); !ok {
+		t.Fatalf("loader %T doesn't implement mtls.ReloadingCredentialsLoader", l)
+	}
+}
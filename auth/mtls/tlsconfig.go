@@ -0,0 +1,170 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package mtls
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// DefaultMinTLSVersion is the minimum TLS version negotiated unless
+// overridden with WithMinTLSVersion.
+const DefaultMinTLSVersion = tls.VersionTLS13
+
+// A TLSOption adjusts the tls.Config used for SansShell credentials. Options
+// are applied after the defaults so they can only replace them, and they
+// don't affect the certificates or roots of trust.
+type TLSOption func(*tls.Config)
+
+// WithMinTLSVersion returns an option setting the minimum TLS version
+// (i.e. tls.VersionTLS12) instead of DefaultMinTLSVersion.
+func WithMinTLSVersion(version uint16) TLSOption {
+	return func(c *tls.Config) {
+		c.MinVersion = version
+	}
+}
+
+// WithCipherSuites returns an option restricting the cipher suites offered
+// or accepted for TLS 1.2 and earlier. TLS 1.3 suites aren't configurable
+// (see crypto/tls) so this has no effect with the default minimum version.
+func WithCipherSuites(suites ...uint16) TLSOption {
+	return func(c *tls.Config) {
+		c.CipherSuites = suites
+	}
+}
+
+// WithCurvePreferences returns an option setting the elliptic curves used
+// for key exchange, in preference order.
+func WithCurvePreferences(curves ...tls.CurveID) TLSOption {
+	return func(c *tls.Config) {
+		c.CurvePreferences = curves
+	}
+}
+
+// applyTLSOptions returns `c` after applying `opts` to it.
+func applyTLSOptions(c *tls.Config, opts []TLSOption) *tls.Config {
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion converts a version such as "1.2" into its crypto/tls constant.
+// Versions before 1.2 are rejected.
+func ParseTLSVersion(version string) (uint16, error) {
+	v, ok := tlsVersions[strings.TrimPrefix(strings.ToLower(version), "tls")]
+	if !ok {
+		return 0, fmt.Errorf("unknown TLS version %q", version)
+	}
+	if v < tls.VersionTLS12 {
+		return 0, fmt.Errorf("TLS version %s is insecure", version)
+	}
+	return v, nil
+}
+
+// ParseCipherSuites converts a comma separated list of cipher suite names as
+// used by crypto/tls (i.e. TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384) into their IDs.
+// Suites Go considers insecure are rejected.
+func ParseCipherSuites(names string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, s := range tls.CipherSuites() {
+		known[s.Name] = s.ID
+	}
+	insecure := make(map[string]bool)
+	for _, s := range tls.InsecureCipherSuites() {
+		insecure[s.Name] = true
+	}
+	var out []uint16
+	for _, n := range strings.Split(names, ",") {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		if insecure[n] {
+			return nil, fmt.Errorf("cipher suite %s is insecure", n)
+		}
+		id, ok := known[n]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %q", n)
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+var curves = map[string]tls.CurveID{
+	"X25519": tls.X25519,
+	"P256":   tls.CurveP256,
+	"P384":   tls.CurveP384,
+	"P521":   tls.CurveP521,
+}
+
+// ParseCurves converts a comma separated list of curve names (X25519, P256,
+// P384, P521) into crypto/tls curve IDs, preserving order.
+func ParseCurves(names string) ([]tls.CurveID, error) {
+	var out []tls.CurveID
+	for _, n := range strings.Split(names, ",") {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		c, ok := curves[strings.ToUpper(strings.ReplaceAll(n, "-", ""))]
+		if !ok {
+			return nil, fmt.Errorf("unknown curve %q", n)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// ParseTLSOptions returns the options for the given minimum TLS version and
+// comma separated lists of cipher suites and curves, as accepted by
+// ParseTLSVersion, ParseCipherSuites and ParseCurves. Empty values keep the
+// defaults.
+func ParseTLSOptions(minVersion, cipherSuites, curves string) ([]TLSOption, error) {
+	var opts []TLSOption
+	if minVersion != "" {
+		v, err := ParseTLSVersion(minVersion)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithMinTLSVersion(v))
+	}
+	if cipherSuites != "" {
+		s, err := ParseCipherSuites(cipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithCipherSuites(s...))
+	}
+	if curves != "" {
+		c, err := ParseCurves(curves)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithCurvePreferences(c...))
+	}
+	return opts, nil
+}
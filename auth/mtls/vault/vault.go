@@ -0,0 +1,294 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package vault provides an mtls.CredentialsLoader which issues certificates
+// from a HashiCorp Vault PKI secrets engine.
+//
+// Importing this package registers a loader named "vault" which can then be
+// selected with --credential-source and configured with the --vault-* flags.
+// A new certificate is issued at startup and again once two thirds of its
+// lifetime has passed, so the Vault role's TTL controls rotation. The same role
+// is used for client and server certificates so it must allow both usages if
+// a process needs both.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+)
+
+const (
+	loaderName = "vault"
+
+	// Vault responses larger than this are rejected.
+	maxResponseSize = 1024 * 1024
+)
+
+// Config describes how to obtain certificates from Vault.
+type Config struct {
+	// Addr is the Vault server URL, i.e. https://vault.example.com:8200
+	Addr string
+	// Token is the Vault token to authenticate with. Ignored if TokenFile is set.
+	Token string
+	// TokenFile if set is read for the token on every request so an external
+	// agent can keep it renewed.
+	TokenFile string
+	// Namespace is the Vault Enterprise namespace, if any.
+	Namespace string
+	// Mount is the path the PKI secrets engine is mounted at (i.e. "pki").
+	Mount string
+	// Role is the PKI role to issue certificates with.
+	Role string
+	// CommonName is the CN to request.
+	CommonName string
+	// AltNames are additional DNS/email SANs to request.
+	AltNames []string
+	// IPSANs are IP address SANs to request.
+	IPSANs []string
+	// TTL if non-zero is the requested certificate lifetime. Otherwise the
+	// role's default is used.
+	TTL time.Duration
+	// HTTPClient is used for requests to Vault. If nil http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+var flagConfig = &Config{}
+
+// Name returns the loader to use to obtain mtls params from Vault.
+func Name() string { return loaderName }
+
+// loader implements mtls.ReloadingCredentialsLoader using a Vault PKI role.
+type loader struct {
+	cfg *Config
+}
+
+// NewLoader returns a loader which issues certificates from Vault according
+// to `cfg`. It can be registered with mtls.Register under another name to
+// use different settings than the flags.
+func NewLoader(cfg *Config) mtls.ReloadingCredentialsLoader {
+	return &loader{cfg: cfg}
+}
+
+func (l *loader) client() *http.Client {
+	if l.cfg.HTTPClient != nil {
+		return l.cfg.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (l *loader) token() (string, error) {
+	if l.cfg.TokenFile != "" {
+		b, err := os.ReadFile(l.cfg.TokenFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	if l.cfg.Token == "" {
+		return "", errors.New("no vault token configured")
+	}
+	return l.cfg.Token, nil
+}
+
+// do performs a request against the Vault API at `path` and returns the body.
+func (l *loader) do(ctx context.Context, method string, path string, body interface{}) ([]byte, error) {
+	if l.cfg.Addr == "" {
+		return nil, errors.New("no vault address configured")
+	}
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(b)
+	}
+	url := strings.TrimSuffix(l.cfg.Addr, "/") + "/v1/" + path
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return nil, err
+	}
+	token, err := l.token()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if l.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", l.cfg.Namespace)
+	}
+	resp, err := l.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var verr struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(b, &verr) == nil && len(verr.Errors) > 0 {
+			return nil, fmt.Errorf("vault %s %s: %s: %s", method, path, resp.Status, strings.Join(verr.Errors, "; "))
+		}
+		return nil, fmt.Errorf("vault %s %s: %s", method, path, resp.Status)
+	}
+	return b, nil
+}
+
+// issueResponse is the subset of the PKI issue response we use.
+type issueResponse struct {
+	Data struct {
+		Certificate string   `json:"certificate"`
+		IssuingCA   string   `json:"issuing_ca"`
+		CAChain     []string `json:"ca_chain"`
+		PrivateKey  string   `json:"private_key"`
+	} `json:"data"`
+}
+
+// issue requests a new certificate from the configured role.
+func (l *loader) issue(ctx context.Context) (*tls.Certificate, error) {
+	if l.cfg.Role == "" {
+		return nil, errors.New("no vault PKI role configured")
+	}
+	req := map[string]string{
+		"common_name": l.cfg.CommonName,
+	}
+	if len(l.cfg.AltNames) > 0 {
+		req["alt_names"] = strings.Join(l.cfg.AltNames, ",")
+	}
+	if len(l.cfg.IPSANs) > 0 {
+		req["ip_sans"] = strings.Join(l.cfg.IPSANs, ",")
+	}
+	if l.cfg.TTL > 0 {
+		req["ttl"] = l.cfg.TTL.String()
+	}
+	b, err := l.do(ctx, http.MethodPost, l.cfg.Mount+"/issue/"+l.cfg.Role, req)
+	if err != nil {
+		return nil, err
+	}
+	var resp issueResponse
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, fmt.Errorf("can't parse vault issue response: %w", err)
+	}
+	// Present the intermediates along with the leaf so peers only need the root.
+	chain := resp.Data.Certificate
+	for _, c := range resp.Data.CAChain {
+		chain += "\n" + c
+	}
+	if len(resp.Data.CAChain) == 0 && resp.Data.IssuingCA != "" {
+		chain += "\n" + resp.Data.IssuingCA
+	}
+	cert, err := tls.X509KeyPair([]byte(chain), []byte(resp.Data.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate from vault: %w", err)
+	}
+	return &cert, nil
+}
+
+// pool returns the CA certificate(s) of the PKI mount.
+func (l *loader) pool(ctx context.Context) (*x509.CertPool, error) {
+	b, err := l.do(ctx, http.MethodGet, l.cfg.Mount+"/ca_chain", nil)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no CA certificates found at vault %s/ca_chain", l.cfg.Mount)
+	}
+	return pool, nil
+}
+
+func (l *loader) certificate(ctx context.Context) (tls.Certificate, error) {
+	cert, err := l.issue(ctx)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return *cert, nil
+}
+
+func (l *loader) LoadClientCA(ctx context.Context) (*x509.CertPool, error) {
+	return l.pool(ctx)
+}
+
+func (l *loader) LoadRootCA(ctx context.Context) (*x509.CertPool, error) {
+	return l.pool(ctx)
+}
+
+func (l *loader) LoadClientCertificate(ctx context.Context) (tls.Certificate, error) {
+	return l.certificate(ctx)
+}
+
+func (l *loader) LoadServerCertificate(ctx context.Context) (tls.Certificate, error) {
+	return l.certificate(ctx)
+}
+
+func (l *loader) ClientCertReloader(ctx context.Context) (mtls.CertificateSource, error) {
+	return mtls.NewRenewingSource(ctx, l.issue, 0)
+}
+
+func (l *loader) ServerCertReloader(ctx context.Context) (mtls.CertificateSource, error) {
+	return mtls.NewRenewingSource(ctx, l.issue, 0)
+}
+
+// stringList is a flag.Value for comma separated lists.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(v string) error {
+	*s = nil
+	for _, e := range strings.Split(v, ",") {
+		if e != "" {
+			*s = append(*s, e)
+		}
+	}
+	return nil
+}
+
+func init() {
+	hostname, _ := os.Hostname()
+	flag.StringVar(&flagConfig.Addr, "vault-addr", os.Getenv("VAULT_ADDR"), "Vault server URL for the vault credential source. Defaults to $VAULT_ADDR")
+	flag.StringVar(&flagConfig.TokenFile, "vault-token-file", "", "File containing the Vault token, re-read on every request. If unset $VAULT_TOKEN is used.")
+	flag.StringVar(&flagConfig.Namespace, "vault-namespace", os.Getenv("VAULT_NAMESPACE"), "Vault namespace, if any. Defaults to $VAULT_NAMESPACE")
+	flag.StringVar(&flagConfig.Mount, "vault-pki-mount", "pki", "Path the Vault PKI secrets engine is mounted at")
+	flag.StringVar(&flagConfig.Role, "vault-pki-role", "", "Vault PKI role to issue certificates from")
+	flag.StringVar(&flagConfig.CommonName, "vault-common-name", hostname, "Common name to request for issued certificates")
+	flag.Var((*stringList)(&flagConfig.AltNames), "vault-alt-names", "Comma separated DNS/email SANs to request for issued certificates")
+	flag.Var((*stringList)(&flagConfig.IPSANs), "vault-ip-sans", "Comma separated IP SANs to request for issued certificates")
+	flag.DurationVar(&flagConfig.TTL, "vault-cert-ttl", 0, "Lifetime to request for issued certificates. If zero the role default is used.")
+	flagConfig.Token = os.Getenv("VAULT_TOKEN")
+
+	if err := mtls.Register(loaderName, NewLoader(flagConfig)); err != nil {
+		panic(err)
+	}
+}
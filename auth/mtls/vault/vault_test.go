@@ -0,0 +1,211 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const testToken = "s.testtoken"
+
+// fakeVault implements just enough of the PKI secrets engine API.
+type fakeVault struct {
+	t      *testing.T
+	caKey  *ecdsa.PrivateKey
+	ca     *x509.Certificate
+	caPEM  []byte
+	issued int
+}
+
+func newFakeVault(t *testing.T) *httptest.Server {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fake vault CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	testutil.FatalOnErr("CreateCertificate", err, t)
+	ca, err := x509.ParseCertificate(der)
+	testutil.FatalOnErr("ParseCertificate", err, t)
+	f := &fakeVault{
+		t:     t,
+		caKey: key,
+		ca:    ca,
+		caPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+	s := httptest.NewServer(f)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != testToken {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string][]string{"errors": {"permission denied"}})
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/pki/ca_chain":
+		w.Write(f.caPEM)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/pki/issue/sansshell":
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		ttl, err := time.ParseDuration(req["ttl"])
+		if err != nil {
+			ttl = time.Hour
+		}
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		testutil.FatalOnErr("GenerateKey", err, f.t)
+		f.issued++
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(f.issued + 1)),
+			Subject:      pkix.Name{CommonName: req["common_name"]},
+			DNSNames:     []string{req["alt_names"]},
+			NotBefore:    time.Now().Add(-time.Minute),
+			NotAfter:     time.Now().Add(ttl),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, f.ca, &key.PublicKey, f.caKey)
+		testutil.FatalOnErr("CreateCertificate", err, f.t)
+		kb, err := x509.MarshalECPrivateKey(key)
+		testutil.FatalOnErr("MarshalECPrivateKey", err, f.t)
+		resp := issueResponse{}
+		resp.Data.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+		resp.Data.IssuingCA = string(f.caPEM)
+		resp.Data.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kb}))
+		json.NewEncoder(w).Encode(resp)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeVault(t)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	err := os.WriteFile(tokenFile, []byte(testToken+"\n"), 0600)
+	testutil.FatalOnErr("WriteFile", err, t)
+
+	l := NewLoader(&Config{
+		Addr:       s.URL,
+		TokenFile:  tokenFile,
+		Mount:      "pki",
+		Role:       "sansshell",
+		CommonName: "host.example.com",
+		AltNames:   []string{"alt.example.com"},
+		TTL:        time.Hour,
+	})
+	pool, err := l.LoadRootCA(ctx)
+	testutil.FatalOnErr("LoadRootCA", err, t)
+	cert, err := l.LoadServerCertificate(ctx)
+	testutil.FatalOnErr("LoadServerCertificate", err, t)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	testutil.FatalOnErr("ParseCertificate", err, t)
+	if leaf.Subject.CommonName != "host.example.com" {
+		t.Errorf("CN = %q, want host.example.com", leaf.Subject.CommonName)
+	}
+	if len(cert.Certificate) != 2 {
+		t.Errorf("got chain of %d certs, want leaf + issuing CA", len(cert.Certificate))
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	testutil.FatalOnErr("Verify against CA from LoadRootCA", err, t)
+
+	src, err := l.ClientCertReloader(ctx)
+	testutil.FatalOnErr("ClientCertReloader", err, t)
+	c, err := src.GetClientCertificate(nil)
+	testutil.FatalOnErr("GetClientCertificate", err, t)
+	if c.Leaf == nil || c.Leaf.Subject.CommonName != "host.example.com" {
+		t.Errorf("unexpected reloading cert %+v", c.Leaf)
+	}
+
+	// The full credentials helpers work with this loader.
+	err = mtls.Register("vault-test", l)
+	testutil.FatalOnErr("Register", err, t)
+	_, err = mtls.LoadServerCredentials(ctx, "vault-test")
+	testutil.FatalOnErr("LoadServerCredentials", err, t)
+	_, err = mtls.LoadClientCredentials(ctx, "vault-test")
+	testutil.FatalOnErr("LoadClientCredentials", err, t)
+}
+
+func TestLoaderErrors(t *testing.T) {
+	ctx := context.Background()
+	s := newFakeVault(t)
+	for _, tc := range []struct {
+		name string
+		cfg  *Config
+	}{
+		{
+			name: "no address",
+			cfg:  &Config{Token: testToken, Mount: "pki", Role: "sansshell"},
+		},
+		{
+			name: "no token",
+			cfg:  &Config{Addr: s.URL, Mount: "pki", Role: "sansshell"},
+		},
+		{
+			name: "bad token",
+			cfg:  &Config{Addr: s.URL, Token: "wrong", Mount: "pki", Role: "sansshell"},
+		},
+		{
+			name: "missing token file",
+			cfg:  &Config{Addr: s.URL, TokenFile: "/no/such/file", Mount: "pki", Role: "sansshell"},
+		},
+		{
+			name: "no role",
+			cfg:  &Config{Addr: s.URL, Token: testToken, Mount: "pki"},
+		},
+		{
+			name: "unknown role",
+			cfg:  &Config{Addr: s.URL, Token: testToken, Mount: "pki", Role: "other"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewLoader(tc.cfg).LoadClientCertificate(ctx)
+			testutil.FatalOnNoErr(tc.name, err, t)
+		})
+	}
+	_, err := NewLoader(&Config{Addr: s.URL, Token: testToken, Mount: "other"}).LoadClientCA(ctx)
+	testutil.FatalOnNoErr("LoadClientCA from unknown mount", err, t)
+}
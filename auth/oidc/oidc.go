@@ -0,0 +1,452 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package oidc provides authentication of sansshell clients via OIDC
+// ID tokens (or any JWT signed by a key published as a JWKS) passed as
+// bearer tokens in gRPC metadata.
+//
+// On the server side Hook returns an rpcauth.RPCAuthzHook which validates
+// the token and populates the peer identity, principal and token claims in
+// the policy input. On the client side NewPerRPCCredentials attaches a token
+// to every RPC.
+//
+// Tokens are carried on top of the existing TLS transport. When a valid token
+// is present its identity replaces any principal derived from the client
+// certificate, so a shared client certificate plus per-user tokens can be used
+// where issuing user certificates is impractical.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+const (
+	// MetadataKey is the gRPC metadata key carrying the bearer token.
+	MetadataKey = "authorization"
+
+	// IdentityType is the type of peer identities established from tokens.
+	IdentityType = "oidc"
+
+	bearerPrefix = "Bearer "
+
+	// Don't refetch keys for an unknown key ID more often than this.
+	minRefreshInterval = time.Minute
+
+	// Responses from issuers larger than this are rejected.
+	maxResponseSize = 1024 * 1024
+)
+
+var validMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// A Verifier validates tokens from a fixed set of issuers.
+type Verifier struct {
+	audience       string
+	client         *http.Client
+	principalClaim string
+	groupsClaim    string
+	issuers        map[string]*issuer
+}
+
+// issuer tracks the signing keys for one issuer.
+type issuer struct {
+	url     string
+	jwksURL string
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// An Option controls the behavior of a Verifier.
+type Option interface {
+	apply(*Verifier)
+}
+
+type optionFunc func(*Verifier)
+
+func (o optionFunc) apply(v *Verifier) {
+	o(v)
+}
+
+// WithHTTPClient returns an option to use `client` for discovery and key
+// fetches rather than http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return optionFunc(func(v *Verifier) {
+		v.client = client
+	})
+}
+
+// WithPrincipalClaim returns an option to use the given claim as the principal
+// ID in policy input instead of "sub" (i.e. "email").
+func WithPrincipalClaim(claim string) Option {
+	return optionFunc(func(v *Verifier) {
+		v.principalClaim = claim
+	})
+}
+
+// WithGroupsClaim returns an option to use the given claim for principal
+// groups in policy input instead of "groups".
+func WithGroupsClaim(claim string) Option {
+	return optionFunc(func(v *Verifier) {
+		v.groupsClaim = claim
+	})
+}
+
+// WithJWKSURL returns an option which sets the JWKS location for `iss` directly
+// instead of using OIDC discovery. `iss` must also be passed to NewVerifier.
+func WithJWKSURL(iss string, jwksURL string) Option {
+	return optionFunc(func(v *Verifier) {
+		if i, ok := v.issuers[iss]; ok {
+			i.jwksURL = jwksURL
+		}
+	})
+}
+
+// NewVerifier returns a Verifier accepting tokens for `audience` issued by any
+// of `issuers`. Keys are discovered (via /.well-known/openid-configuration)
+// and fetched on first use and refreshed when an unknown key ID is seen.
+func NewVerifier(audience string, issuers []string, opts ...Option) (*Verifier, error) {
+	if audience == "" {
+		return nil, errors.New("audience must be set")
+	}
+	if len(issuers) == 0 {
+		return nil, errors.New("at least one issuer must be set")
+	}
+	v := &Verifier{
+		audience:       audience,
+		client:         http.DefaultClient,
+		principalClaim: "sub",
+		groupsClaim:    "groups",
+		issuers:        make(map[string]*issuer),
+	}
+	for _, i := range issuers {
+		v.issuers[i] = &issuer{url: i}
+	}
+	for _, opt := range opts {
+		opt.apply(v)
+	}
+	return v, nil
+}
+
+// Verify checks the signature, issuer, audience and validity window of `token`
+// and returns its claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	p := jwt.NewParser(jwt.WithValidMethods(validMethods))
+	_, err := p.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		iss, _ := claims["iss"].(string)
+		i, ok := v.issuers[iss]
+		if !ok {
+			return nil, fmt.Errorf("untrusted issuer %q", iss)
+		}
+		kid, _ := t.Header["kid"].(string)
+		return i.key(ctx, v.client, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Parsing has validated exp/nbf/iat if present. OIDC requires exp.
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("token has no expiry")
+	}
+	if !claims.VerifyAudience(v.audience, true) {
+		return nil, errors.New("token audience mismatch")
+	}
+	return claims, nil
+}
+
+// Principal returns the principal ID (see WithPrincipalClaim) from claims
+// returned by Verify, or an empty string if there isn't one.
+func (v *Verifier) Principal(claims jwt.MapClaims) string {
+	p, _ := claims[v.principalClaim].(string)
+	return p
+}
+
+// key returns the public key for `kid`, fetching keys as needed.
+func (i *issuer) key(ctx context.Context, client *http.Client, kid string) (crypto.PublicKey, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if k, ok := i.lookup(kid); ok {
+		return k, nil
+	}
+	if time.Since(i.fetched) < minRefreshInterval {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if err := i.refresh(ctx, client); err != nil {
+		return nil, err
+	}
+	if k, ok := i.lookup(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// lookup finds `kid`. If the token has no key ID and there's exactly one key, it's used.
+// Must be called with i.mu held.
+func (i *issuer) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(i.keys) == 1 {
+		for _, k := range i.keys {
+			return k, true
+		}
+	}
+	k, ok := i.keys[kid]
+	return k, ok
+}
+
+// refresh (re)loads the JWKS. Must be called with i.mu held.
+func (i *issuer) refresh(ctx context.Context, client *http.Client) error {
+	i.fetched = time.Now()
+	if i.jwksURL == "" {
+		var doc struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := getJSON(ctx, client, strings.TrimSuffix(i.url, "/")+"/.well-known/openid-configuration", &doc); err != nil {
+			return err
+		}
+		if doc.Issuer != i.url {
+			return fmt.Errorf("discovery document issuer %q doesn't match %q", doc.Issuer, i.url)
+		}
+		if doc.JWKSURI == "" {
+			return fmt.Errorf("discovery document for %s has no jwks_uri", i.url)
+		}
+		i.jwksURL = doc.JWKSURI
+	}
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, client, i.jwksURL, &jwks); err != nil {
+		return err
+	}
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pk, err := k.publicKey()
+		if err != nil {
+			// Skip keys we don't understand rather than failing everything.
+			continue
+		}
+		keys[k.Kid] = pk
+	}
+	i.keys = keys
+	return nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching %s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(out)
+}
+
+// jwk is the subset of RFC 7517 needed for RSA and EC signing keys.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func b64Int(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64Int(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64Int(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := b64Int(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64Int(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("EC point not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// Authenticate implements rpcauth.Authenticator. It validates a bearer token
+// found in the request metadata and, if valid, sets the peer principal (from
+// the principal and groups claims) and token claims in the policy input and
+// returns an identity of IdentityType for the principal. The token itself is
+// removed from the input metadata so it's never logged or audited.
+func (v *Verifier) Authenticate(ctx context.Context, input *rpcauth.RPCAuthInput) (*rpcauth.PeerIdentity, error) {
+	vals := input.Metadata.Get(MetadataKey)
+	input.Metadata.Delete(MetadataKey)
+	if len(vals) == 0 {
+		return nil, nil
+	}
+	if len(vals) > 1 || !strings.HasPrefix(vals[0], bearerPrefix) {
+		return nil, status.Error(codes.Unauthenticated, "malformed authorization metadata")
+	}
+	claims, err := v.Verify(ctx, strings.TrimPrefix(vals[0], bearerPrefix))
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid bearer token: %v", err)
+	}
+	if input.Peer == nil {
+		input.Peer = &rpcauth.PeerAuthInput{}
+	}
+	iss, _ := claims["iss"].(string)
+	sub, _ := claims["sub"].(string)
+	input.Peer.Token = &rpcauth.TokenAuthInput{
+		Issuer:  iss,
+		Subject: sub,
+		Claims:  claims,
+	}
+	principal := &rpcauth.PrincipalAuthInput{ID: v.Principal(claims)}
+	switch g := claims[v.groupsClaim].(type) {
+	case []interface{}:
+		for _, e := range g {
+			if s, ok := e.(string); ok {
+				principal.Groups = append(principal.Groups, s)
+			}
+		}
+	case string:
+		principal.Groups = []string{g}
+	}
+	input.Peer.Principal = principal
+	if principal.ID == "" {
+		return nil, nil
+	}
+	id := &rpcauth.PeerIdentity{
+		ID:     principal.ID,
+		Type:   IdentityType,
+		Issuer: iss,
+	}
+	if sub != principal.ID {
+		id.Attributes = map[string]string{"subject": sub}
+	}
+	return id, nil
+}
+
+// Hook returns an RPCAuthzHook which authenticates the peer with `v` (see
+// Verifier.Authenticate), making the token's identity the peer's primary one.
+//
+// If `required` is true requests without a token are rejected with Unauthenticated.
+// Invalid tokens are always rejected.
+func Hook(v *Verifier, required bool) rpcauth.RPCAuthzHook {
+	return rpcauth.RPCAuthzHookFunc(func(ctx context.Context, input *rpcauth.RPCAuthInput) error {
+		if len(input.Metadata.Get(MetadataKey)) == 0 {
+			if required {
+				return status.Error(codes.Unauthenticated, "bearer token required")
+			}
+			return nil
+		}
+		id, err := v.Authenticate(ctx, input)
+		if err != nil {
+			return err
+		}
+		if id != nil {
+			rpcauth.AddIdentity(input, id)
+		}
+		return nil
+	})
+}
+
+// perRPCCredentials implements credentials.PerRPCCredentials for a bearer token.
+type perRPCCredentials struct {
+	token func() (string, error)
+}
+
+// NewPerRPCCredentials returns credentials which attach the token returned by
+// `token` to every RPC. It's called for each RPC so it may return refreshed tokens.
+// Tokens are only sent over secure transports.
+func NewPerRPCCredentials(token func() (string, error)) credentials.PerRPCCredentials {
+	return &perRPCCredentials{token: token}
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (p *perRPCCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	t, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{MetadataKey: bearerPrefix + t}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+func (p *perRPCCredentials) RequireTransportSecurity() bool {
+	return true
+}
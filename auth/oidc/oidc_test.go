@@ -0,0 +1,332 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const policy = `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.peer.principal.id = "alice@example.com"
+  input.peer.principal.groups[_] = "admins"
+  input.peer.token.claims.env = "prod"
+}
+`
+
+type testIssuer struct {
+	*httptest.Server
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	testutil.FatalOnErr("rsa.GenerateKey", err, t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("ecdsa.GenerateKey", err, t)
+	ti := &testIssuer{rsaKey: rsaKey, ecKey: ecKey}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   ti.URL,
+			"jwks_uri": ti.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": "rsa",
+					"use": "sig",
+					"n":   b64(rsaKey.N.Bytes()),
+					"e":   b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+				},
+				{
+					"kty": "EC",
+					"kid": "ec",
+					"crv": "P-256",
+					"x":   b64(ecKey.X.Bytes()),
+					"y":   b64(ecKey.Y.Bytes()),
+				},
+				{
+					"kty": "oct",
+					"kid": "ignored",
+				},
+			},
+		})
+	})
+	ti.Server = httptest.NewServer(mux)
+	t.Cleanup(ti.Close)
+	return ti
+}
+
+func (ti *testIssuer) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":    ti.URL,
+		"sub":    "alice@example.com",
+		"aud":    "sansshell",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"groups": []string{"users", "admins"},
+		"env":    "prod",
+	}
+}
+
+func (ti *testIssuer) sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(method, claims)
+	tok.Header["kid"] = kid
+	var key interface{} = ti.rsaKey
+	if kid == "ec" {
+		key = ti.ecKey
+	}
+	s, err := tok.SignedString(key)
+	testutil.FatalOnErr("SignedString", err, t)
+	return s
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	ti := newTestIssuer(t)
+	other := newTestIssuer(t)
+	v, err := NewVerifier("sansshell", []string{ti.URL})
+	testutil.FatalOnErr("NewVerifier", err, t)
+
+	with := func(k string, val interface{}) jwt.MapClaims {
+		c := ti.claims()
+		if val == nil {
+			delete(c, k)
+		} else {
+			c[k] = val
+		}
+		return c
+	}
+
+	for _, tc := range []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "RSA",
+			token: ti.sign(t, jwt.SigningMethodRS256, "rsa", ti.claims()),
+		},
+		{
+			name:  "EC",
+			token: ti.sign(t, jwt.SigningMethodES256, "ec", ti.claims()),
+		},
+		{
+			name:  "audience list",
+			token: ti.sign(t, jwt.SigningMethodRS256, "rsa", with("aud", []string{"other", "sansshell"})),
+		},
+		{
+			name:    "wrong audience",
+			token:   ti.sign(t, jwt.SigningMethodRS256, "rsa", with("aud", "other")),
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			token:   ti.sign(t, jwt.SigningMethodRS256, "rsa", with("exp", time.Now().Add(-time.Minute).Unix())),
+			wantErr: true,
+		},
+		{
+			name:    "no expiry",
+			token:   ti.sign(t, jwt.SigningMethodRS256, "rsa", with("exp", nil)),
+			wantErr: true,
+		},
+		{
+			name:    "untrusted issuer",
+			token:   other.sign(t, jwt.SigningMethodRS256, "rsa", other.claims()),
+			wantErr: true,
+		},
+		{
+			name:    "signed by other issuer's key",
+			token:   other.sign(t, jwt.SigningMethodRS256, "rsa", ti.claims()),
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			token:   ti.sign(t, jwt.SigningMethodRS256, "missing", ti.claims()),
+			wantErr: true,
+		},
+		{
+			name: "HMAC not allowed",
+			token: func() string {
+				s, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, ti.claims()).SignedString([]byte("secret"))
+				return s
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "garbage",
+			token:   "not.a.token",
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := v.Verify(ctx, tc.token)
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if err == nil && claims["sub"] != "alice@example.com" {
+				t.Errorf("sub = %v, want alice@example.com", claims["sub"])
+			}
+		})
+	}
+
+	_, err = NewVerifier("", []string{ti.URL})
+	testutil.FatalOnNoErr("NewVerifier without audience", err, t)
+	_, err = NewVerifier("sansshell", nil)
+	testutil.FatalOnNoErr("NewVerifier without issuers", err, t)
+}
+
+func TestHook(t *testing.T) {
+	ctx := context.Background()
+	ti := newTestIssuer(t)
+	v, err := NewVerifier("sansshell", []string{ti.URL})
+	testutil.FatalOnErr("NewVerifier", err, t)
+	good := ti.sign(t, jwt.SigningMethodRS256, "rsa", ti.claims())
+	staging := ti.claims()
+	staging["env"] = "staging"
+	wrongEnv := ti.sign(t, jwt.SigningMethodRS256, "rsa", staging)
+
+	for _, tc := range []struct {
+		name     string
+		md       metadata.MD
+		required bool
+		wantCode codes.Code
+	}{
+		{
+			name:     "valid token",
+			md:       metadata.Pairs(MetadataKey, "Bearer "+good),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "policy denies claims",
+			md:       metadata.Pairs(MetadataKey, "Bearer "+wrongEnv),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "no token and optional",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "no token and required",
+			required: true,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "not bearer",
+			md:       metadata.Pairs(MetadataKey, "Basic Zm9vOmJhcg=="),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "invalid token",
+			md:       metadata.Pairs(MetadataKey, "Bearer "+good+"x"),
+			wantCode: codes.Unauthenticated,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var saw *rpcauth.RPCAuthInput
+			authz, err := rpcauth.NewWithPolicy(ctx, policy, Hook(v, tc.required), rpcauth.RPCAuthzHookFunc(func(ctx context.Context, input *rpcauth.RPCAuthInput) error {
+				saw = input
+				return nil
+			}))
+			testutil.FatalOnErr("NewWithPolicy", err, t)
+			input := &rpcauth.RPCAuthInput{Method: "/Foo/Bar", Metadata: tc.md.Copy()}
+			err = authz.Eval(ctx, input)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Eval() code = %v (%v), want %v", got, err, tc.wantCode)
+			}
+			if saw != nil && len(saw.Metadata.Get(MetadataKey)) != 0 {
+				t.Errorf("token was left in policy input metadata")
+			}
+		})
+	}
+}
+
+func TestHookIdentity(t *testing.T) {
+	ctx := context.Background()
+	ti := newTestIssuer(t)
+	v, err := NewVerifier("sansshell", []string{ti.URL})
+	testutil.FatalOnErr("NewVerifier", err, t)
+	token := ti.sign(t, jwt.SigningMethodRS256, "rsa", ti.claims())
+
+	cert := &rpcauth.PeerIdentity{ID: "shared-client", Type: rpcauth.IdentityTypeMTLS}
+	input := &rpcauth.RPCAuthInput{
+		Method:   "/Foo/Bar",
+		Metadata: metadata.Pairs(MetadataKey, "Bearer "+token),
+		Peer: &rpcauth.PeerAuthInput{
+			Identity:   cert,
+			Identities: []*rpcauth.PeerIdentity{cert},
+		},
+	}
+	err = Hook(v, true).Hook(ctx, input)
+	testutil.FatalOnErr("Hook", err, t)
+	want := &rpcauth.PeerIdentity{ID: "alice@example.com", Type: IdentityType, Issuer: ti.URL}
+	if diff := cmp.Diff(want, input.Peer.Identity); diff != "" {
+		t.Errorf("Identity diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]*rpcauth.PeerIdentity{want, cert}, input.Peer.Identities); diff != "" {
+		t.Errorf("Identities diff (-want +got):\n%s", diff)
+	}
+	if got := rpcauth.PrincipalID(input); got != want.ID {
+		t.Errorf("PrincipalID() = %q, want %q", got, want.ID)
+	}
+}
+
+func TestPerRPCCredentials(t *testing.T) {
+	creds := NewPerRPCCredentials(func() (string, error) { return "tok", nil })
+	md, err := creds.GetRequestMetadata(context.Background())
+	testutil.FatalOnErr("GetRequestMetadata", err, t)
+	if got, want := md[MetadataKey], "Bearer tok"; got != want {
+		t.Errorf("metadata = %q, want %q", got, want)
+	}
+	if !creds.RequireTransportSecurity() {
+		t.Error("RequireTransportSecurity() = false, want true")
+	}
+}
@@ -0,0 +1,227 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package opa
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/topdown/builtins"
+	"github.com/open-policy-agent/opa/types"
+)
+
+// Builtin functions available to all sansshell policies, in addition to
+// the standard Rego builtins. As with standard builtins, invalid arguments
+// make the expression calling one undefined, which denies the request
+// rather than failing open.
+const (
+	// TargetInCIDRBuiltin is the name of a function returning true if the
+	// target (an IP address, optionally with a port as in input.host.target)
+	// is within the CIDR block, i.e.
+	//
+	//	sansshell.target_in_cidr(input.host.target, "10.0.0.0/8")
+	//
+	// Targets given as hostnames never match.
+	TargetInCIDRBuiltin = "sansshell.target_in_cidr"
+
+	// PathMatchBuiltin is the name of a function returning true if a path
+	// matches a glob pattern. Patterns are matched a path element at a time
+	// using the syntax of path.Match, except that an element of "**"
+	// matches zero or more elements, i.e.
+	//
+	//	sansshell.path_match("/var/log/**/*.log", input.message.filename)
+	//
+	// Only absolute, clean paths can match so "/var/log/../../etc/passwd"
+	// won't match "/var/log/**".
+	PathMatchBuiltin = "sansshell.path_match"
+
+	// ShellSplitBuiltin is the name of a function splitting a command line
+	// into words using shell quoting rules, i.e.
+	//
+	//	sansshell.shell_split(`echo "hello world"`) == ["echo", "hello world"]
+	//
+	// which can be used to write Exec allowlists as command lines. Only
+	// quoting and escaping are handled, there's no expansion of any kind.
+	ShellSplitBuiltin = "sansshell.shell_split"
+)
+
+// builtinFuncs are passed to every rego.New call made by prepare.
+var builtinFuncs = []func(*rego.Rego){
+	rego.Function2(&rego.Function{
+		Name:    TargetInCIDRBuiltin,
+		Decl:    types.NewFunction(types.Args(types.S, types.S), types.B),
+		Memoize: true,
+	}, targetInCIDR),
+	rego.Function2(&rego.Function{
+		Name:    PathMatchBuiltin,
+		Decl:    types.NewFunction(types.Args(types.S, types.S), types.B),
+		Memoize: true,
+	}, pathMatch),
+	rego.Function1(&rego.Function{
+		Name:    ShellSplitBuiltin,
+		Decl:    types.NewFunction(types.Args(types.S), types.NewArray(nil, types.S)),
+		Memoize: true,
+	}, shellSplit),
+}
+
+func targetInCIDR(_ rego.BuiltinContext, a, b *ast.Term) (*ast.Term, error) {
+	target, err := builtins.StringOperand(a.Value, 1)
+	if err != nil {
+		return nil, err
+	}
+	cidr, err := builtins.StringOperand(b.Value, 2)
+	if err != nil {
+		return nil, err
+	}
+	_, network, err := net.ParseCIDR(string(cidr))
+	if err != nil {
+		return nil, builtins.NewOperandErr(2, "invalid CIDR %q", string(cidr))
+	}
+	host := string(target)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+	return ast.BooleanTerm(ip != nil && network.Contains(ip)), nil
+}
+
+func pathMatch(_ rego.BuiltinContext, a, b *ast.Term) (*ast.Term, error) {
+	pattern, err := builtins.StringOperand(a.Value, 1)
+	if err != nil {
+		return nil, err
+	}
+	p, err := builtins.StringOperand(b.Value, 2)
+	if err != nil {
+		return nil, err
+	}
+	if !path.IsAbs(string(pattern)) {
+		return nil, builtins.NewOperandErr(1, "pattern %q must be absolute", string(pattern))
+	}
+	if !path.IsAbs(string(p)) || path.Clean(string(p)) != string(p) {
+		return ast.BooleanTerm(false), nil
+	}
+	matched, err := matchElements(strings.Split(string(pattern)[1:], "/"), strings.Split(string(p)[1:], "/"))
+	if err != nil {
+		return nil, builtins.NewOperandErr(1, "invalid pattern %q: %v", string(pattern), err)
+	}
+	return ast.BooleanTerm(matched), nil
+}
+
+// matchElements matches path elements against pattern elements where "**"
+// matches any number of elements.
+func matchElements(pattern, elems []string) (bool, error) {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(elems); i >= 0; i-- {
+				matched, err := matchElements(pattern[1:], elems[i:])
+				if err != nil || matched {
+					return matched, err
+				}
+			}
+			return false, nil
+		}
+		if len(elems) == 0 {
+			return false, nil
+		}
+		matched, err := path.Match(pattern[0], elems[0])
+		if err != nil || !matched {
+			return false, err
+		}
+		pattern, elems = pattern[1:], elems[1:]
+	}
+	return len(elems) == 0, nil
+}
+
+func shellSplit(_ rego.BuiltinContext, a *ast.Term) (*ast.Term, error) {
+	s, err := builtins.StringOperand(a.Value, 1)
+	if err != nil {
+		return nil, err
+	}
+	words, err := splitWords(string(s))
+	if err != nil {
+		return nil, builtins.NewOperandErr(1, "%v", err)
+	}
+	terms := make([]*ast.Term, 0, len(words))
+	for _, w := range words {
+		terms = append(terms, ast.StringTerm(w))
+	}
+	return ast.ArrayTerm(terms...), nil
+}
+
+// splitWords splits s into words as a POSIX shell would, handling single
+// and double quotes and backslash escapes.
+func splitWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			inWord = true
+			i++
+			if i == len(s) {
+				return nil, fmt.Errorf("trailing backslash in %q", s)
+			}
+			// An escaped newline is a line continuation.
+			if s[i] != '\n' {
+				word.WriteByte(s[i])
+			}
+		case c == '\'':
+			inWord = true
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in %q", s)
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			inWord = true
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				// Within double quotes backslash only escapes these.
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, fmt.Errorf("unterminated double quote in %q", s)
+			}
+		default:
+			inWord = true
+			word.WriteByte(c)
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
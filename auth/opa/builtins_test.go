@@ -0,0 +1,171 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package opa
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestBuiltins(t *testing.T) {
+	ctx := context.Background()
+	policy := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  sansshell.target_in_cidr(input.target, input.cidr)
+}
+
+allow {
+  sansshell.path_match(input.pattern, input.path)
+}
+
+allow {
+  sansshell.shell_split(input.cmdline) == input.words
+}
+`
+	p, err := NewAuthzPolicy(ctx, policy)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+
+	for _, tc := range []struct {
+		name  string
+		input map[string]interface{}
+		want  bool
+	}{
+		{
+			name:  "ip in cidr",
+			input: map[string]interface{}{"target": "10.1.2.3", "cidr": "10.0.0.0/8"},
+			want:  true,
+		},
+		{
+			name:  "ip and port in cidr",
+			input: map[string]interface{}{"target": "10.1.2.3:50042", "cidr": "10.0.0.0/8"},
+			want:  true,
+		},
+		{
+			name:  "ipv6 and port in cidr",
+			input: map[string]interface{}{"target": "[fd00::1]:50042", "cidr": "fd00::/8"},
+			want:  true,
+		},
+		{
+			name:  "ip outside cidr",
+			input: map[string]interface{}{"target": "192.168.1.1:50042", "cidr": "10.0.0.0/8"},
+		},
+		{
+			name:  "hostname",
+			input: map[string]interface{}{"target": "localhost:50042", "cidr": "127.0.0.0/8"},
+		},
+		{
+			name:  "bad cidr",
+			input: map[string]interface{}{"target": "10.1.2.3", "cidr": "10.0.0.0"},
+		},
+		{
+			name:  "path glob",
+			input: map[string]interface{}{"pattern": "/var/log/*.log", "path": "/var/log/messages.log"},
+			want:  true,
+		},
+		{
+			name:  "glob doesn't cross directories",
+			input: map[string]interface{}{"pattern": "/var/log/*.log", "path": "/var/log/nginx/access.log"},
+		},
+		{
+			name:  "double star",
+			input: map[string]interface{}{"pattern": "/var/log/**/*.log", "path": "/var/log/nginx/access.log"},
+			want:  true,
+		},
+		{
+			name:  "double star matches nothing",
+			input: map[string]interface{}{"pattern": "/var/log/**/*.log", "path": "/var/log/messages.log"},
+			want:  true,
+		},
+		{
+			name:  "trailing double star",
+			input: map[string]interface{}{"pattern": "/var/log/**", "path": "/var/log/a/b/c"},
+			want:  true,
+		},
+		{
+			name:  "unclean path",
+			input: map[string]interface{}{"pattern": "/var/log/**", "path": "/var/log/../../etc/passwd"},
+		},
+		{
+			name:  "relative path",
+			input: map[string]interface{}{"pattern": "/var/log/**", "path": "var/log/messages"},
+		},
+		{
+			name:  "relative pattern",
+			input: map[string]interface{}{"pattern": "var/log/**", "path": "/var/log/messages"},
+		},
+		{
+			name:  "bad pattern",
+			input: map[string]interface{}{"pattern": "/var/log/[", "path": "/var/log/["},
+		},
+		{
+			name:  "shell split",
+			input: map[string]interface{}{"cmdline": `echo "hello world" 'it''s' a\ b`, "words": []string{"echo", "hello world", "its", "a b"}},
+			want:  true,
+		},
+		{
+			name:  "shell split mismatch",
+			input: map[string]interface{}{"cmdline": "echo hello world", "words": []string{"echo", "hello world"}},
+		},
+		{
+			name:  "shell split unterminated",
+			input: map[string]interface{}{"cmdline": `echo "hello`, "words": []string{"echo", "hello"}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.Eval(ctx, tc.input)
+			testutil.FatalOnErr("Eval", err, t)
+			if got != tc.want {
+				t.Fatalf("Eval(%v) = %v, want %v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "", want: nil},
+		{in: "  ls   -l\t/tmp\n", want: []string{"ls", "-l", "/tmp"}},
+		{in: `sh -c 'echo $HOME'`, want: []string{"sh", "-c", "echo $HOME"}},
+		{in: `echo "a \"b\" \$c \d"`, want: []string{"echo", `a "b" $c \d`}},
+		{in: `echo ""`, want: []string{"echo", ""}},
+		{in: "echo a\\\nb", want: []string{"echo", "ab"}},
+		{in: `echo \`, wantErr: true},
+		{in: `echo 'a`, wantErr: true},
+		{in: `echo "a`, wantErr: true},
+	} {
+		got, err := splitWords(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("splitWords(%q) error %v, want error %v", tc.in, err, tc.wantErr)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("splitWords(%q) mismatch (-want +got):\n%s", tc.in, diff)
+		}
+	}
+}
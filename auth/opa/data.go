@@ -0,0 +1,158 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package opa
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/open-policy-agent/opa/storage"
+	"github.com/open-policy-agent/opa/util"
+)
+
+const (
+	// DataRoot is where data documents are stored, so a document set with
+	// SetData(ctx, "oncall", doc) is available to policies as
+	// data.external.oncall.
+	DataRoot = "external"
+
+	// DefaultDataRefreshInterval is how often PollData reloads a data
+	// document by default.
+	DefaultDataRefreshInterval = time.Minute
+
+	// Data documents larger than this are rejected.
+	maxDataSize = 16 * 1024 * 1024
+)
+
+// Data document names must be valid Rego identifiers, so policies can
+// refer to them as data.external.<name>.
+var dataName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// SetData stores `doc`, which must be representable as JSON, as the data
+// document `name`, available to policies as data.external.<name>. It
+// replaces any existing document of that name. It is safe to call
+// concurrently with Eval.
+func (q *AuthzPolicy) SetData(ctx context.Context, name string, doc interface{}) error {
+	if !dataName.MatchString(name) {
+		return fmt.Errorf("invalid data document name %q", name)
+	}
+	if err := util.RoundTrip(&doc); err != nil {
+		return fmt.Errorf("data document %s can't be stored: %w", name, err)
+	}
+	if err := storage.WriteOne(ctx, q.store, storage.AddOp, storage.Path{DataRoot, name}, doc); err != nil {
+		return fmt.Errorf("can't store data document %s: %w", name, err)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.generation++
+	return nil
+}
+
+// ParseData parses a JSON data document.
+func ParseData(b []byte) (interface{}, error) {
+	var doc interface{}
+	if err := util.UnmarshalJSON(b, &doc); err != nil {
+		return nil, fmt.Errorf("invalid data document: %w", err)
+	}
+	return doc, nil
+}
+
+// readData reads the raw data document from `source`, which is either
+// an HTTP(S) URL or a file name.
+func readData(ctx context.Context, client *http.Client, source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("can't fetch data from %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching data from %s returned %s", source, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxDataSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("can't read data from %s: %w", source, err)
+	}
+	if len(b) > maxDataSize {
+		return nil, fmt.Errorf("data from %s is larger than %d bytes", source, maxDataSize)
+	}
+	return b, nil
+}
+
+// LoadData reads a JSON data document from `source`, which is either an
+// HTTP(S) URL or a file name.
+func LoadData(ctx context.Context, client *http.Client, source string) (interface{}, error) {
+	b, err := readData(ctx, client, source)
+	if err != nil {
+		return nil, err
+	}
+	return ParseData(b)
+}
+
+// PollData reloads the data document `name` in `policy` from `source` (see
+// LoadData) every `interval` until `ctx` is done. After the first reload
+// the document is only replaced when it changes, as that flushes decisions
+// cached for the policy (see Generation). Errors are logged and the existing document is left in
+// place. This blocks so is generally run in its own goroutine.
+func PollData(ctx context.Context, client *http.Client, name string, source string, interval time.Duration, policy *AuthzPolicy) {
+	logger := logr.FromContextOrDiscard(ctx).WithValues("data", name, "source", source)
+	var last []byte
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		b, err := readData(ctx, client, source)
+		if err != nil {
+			logger.Error(err, "data reload")
+			continue
+		}
+		h := sha256.Sum256(b)
+		if bytes.Equal(h[:], last) {
+			continue
+		}
+		doc, err := ParseData(b)
+		if err != nil {
+			logger.Error(err, "data reload")
+			continue
+		}
+		if err := policy.SetData(ctx, name, doc); err != nil {
+			logger.Error(err, "data reload")
+			continue
+		}
+		last = h[:]
+		logger.Info("reloaded data document")
+	}
+}
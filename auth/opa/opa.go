@@ -21,11 +21,19 @@ package opa
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"sync"
 
 	"github.com/go-logr/logr"
 	"github.com/open-policy-agent/opa/ast"
 	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage"
+	"github.com/open-policy-agent/opa/storage/inmem"
 	"github.com/open-policy-agent/opa/topdown"
 )
 
@@ -36,6 +44,21 @@ const (
 
 	// DefaultAuthzQuery is the default query used for policy evaluation.
 	DefaultAuthzQuery = "data.sansshell.authz.allow"
+
+	// DefaultDenialHintsQuery is the default query used to explain why a request
+	// was denied. Policies may define deny_reason as either a string or a set of
+	// strings. If it's undefined no hints are returned.
+	DefaultDenialHintsQuery = "data.sansshell.authz.deny_reason"
+
+	// DefaultApprovalQuery is the default query used to decide if an allowed
+	// request must also be approved by another party before it runs (see
+	// rpcauth.RequireApprovals). If it's undefined no approval is required.
+	DefaultApprovalQuery = "data.sansshell.authz.require_approval"
+
+	// DefaultRateLimitQuery is the default query used to find the rate limit
+	// for an allowed request. It should evaluate to an object as described
+	// by RateLimit. If it's undefined the request isn't rate limited.
+	DefaultRateLimitQuery = "data.sansshell.authz.rate_limit"
 )
 
 var (
@@ -49,12 +72,31 @@ var (
 // An AuthzPolicy performs policy checking by evaluating input against
 // a sansshell rego policy file.
 type AuthzPolicy struct {
-	query rego.PreparedEvalQuery
-	b     *bytes.Buffer
+	options *policyOptions
+	// store holds external data documents (see SetData).
+	store storage.Store
+
+	mu         sync.RWMutex
+	compiled   *compiled
+	generation uint64
+}
+
+// compiled is the result of preparing a policy for evaluation.
+type compiled struct {
+	query       rego.PreparedEvalQuery
+	denialHints rego.PreparedEvalQuery
+	approval    rego.PreparedEvalQuery
+	rateLimit   rego.PreparedEvalQuery
+	b           *bytes.Buffer
+	version     string
 }
 
 type policyOptions struct {
-	query string
+	query            string
+	denialHintsQuery string
+	approvalQuery    string
+	rateLimitQuery   string
+	fragments        map[string]string
 }
 
 // An Option controls the behavior of an AuthzPolicy
@@ -78,42 +120,196 @@ func WithAllowQuery(query string) Option {
 	})
 }
 
+// WithDenialHintsQuery returns an option to use `query` to explain denials
+// instead of DefaultDenialHintsQuery. The query should evaluate to either
+// a string or a set/array of strings.
+func WithDenialHintsQuery(query string) Option {
+	return optionFunc(func(o *policyOptions) {
+		o.denialHintsQuery = query
+	})
+}
+
+// WithApprovalQuery returns an option to use `query` to decide if a request
+// requires approval instead of DefaultApprovalQuery. As with WithAllowQuery
+// it should evaluate to 'true' iff approval is required.
+func WithApprovalQuery(query string) Option {
+	return optionFunc(func(o *policyOptions) {
+		o.approvalQuery = query
+	})
+}
+
+// WithRateLimitQuery returns an option to find the rate limit for a request
+// with `query` instead of DefaultRateLimitQuery.
+func WithRateLimitQuery(query string) Option {
+	return optionFunc(func(o *policyOptions) {
+		o.rateLimitQuery = query
+	})
+}
+
+// WithFragments returns an option to compile additional policy modules, keyed
+// by name, together with the main policy. Each fragment must also use
+// SansshellRegoPackage so its rules combine with the main policy: i.e. an
+// `allow` rule in a fragment permits requests in addition to those allowed by
+// the main policy. As only one default may exist for a rule, fragments should
+// not declare `default allow`, leaving that to the main policy.
+//
+// Fragments are kept when the main policy is replaced with Update.
+func WithFragments(fragments map[string]string) Option {
+	return optionFunc(func(o *policyOptions) {
+		o.fragments = fragments
+	})
+}
+
 // NewAuthzPolicy creates a new AuthzPolicy by parsing the policy given
 // in the string `policy`.
 // It returns an error if the policy cannot be parsed, or does not use
 // SansshellRegoPackage in its package declaration.
 func NewAuthzPolicy(ctx context.Context, policy string, opts ...Option) (*AuthzPolicy, error) {
 	options := &policyOptions{
-		query: DefaultAuthzQuery,
+		query:            DefaultAuthzQuery,
+		denialHintsQuery: DefaultDenialHintsQuery,
+		approvalQuery:    DefaultApprovalQuery,
+		rateLimitQuery:   DefaultRateLimitQuery,
 	}
 	for _, opt := range opts {
 		opt.apply(options)
 	}
+	store := inmem.NewFromObject(map[string]interface{}{DataRoot: map[string]interface{}{}})
+	c, err := prepare(ctx, policy, options, store)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthzPolicy{
+		options:  options,
+		store:    store,
+		compiled: c,
+	}, nil
+}
+
+// Update replaces the policy evaluated by this AuthzPolicy with the one
+// in `policy`, using the same options it was originally created with.
+// On error the existing policy remains in effect. It is safe to call
+// concurrently with Eval.
+func (q *AuthzPolicy) Update(ctx context.Context, policy string) error {
+	c, err := prepare(ctx, policy, q.options, q.store)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.compiled = c
+	q.generation++
+	return nil
+}
+
+// Generation returns a counter which increases every time the policy is
+// successfully replaced with Update, or a data document is set with SetData.
+// Anything derived from earlier policy decisions (i.e. caches) should be
+// discarded when it changes.
+func (q *AuthzPolicy) Generation() uint64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.generation
+}
+
+// Version returns a digest identifying the policy (and any fragments) in
+// effect. Unlike Generation it's the same for the same policy in different
+// processes so it can be compared across hosts. It doesn't cover data
+// documents.
+func (q *AuthzPolicy) Version() string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.compiled.version
+}
+
+// parseModule parses a single policy module and checks its package.
+func parseModule(filename string, policy string) (*ast.Module, error) {
 	parserOpts := ast.ParserOptions{FutureKeywords: []string{"in"}}
-	module, err := ast.ParseModuleWithOpts("sanshell-authz-policy.rego", policy, parserOpts)
+	module, err := ast.ParseModuleWithOpts(filename, policy, parserOpts)
 	if err != nil {
 		return nil, fmt.Errorf("policy parse error: %w", err)
 	}
 
 	if !module.Package.Equal(sansshellPackage) {
-		return nil, fmt.Errorf("policy has invalid package '%s' (must be '%s')", module.Package, sansshellPackage)
+		return nil, fmt.Errorf("policy %s has invalid package '%s' (must be '%s')", filename, module.Package, sansshellPackage)
+	}
+	return module, nil
+}
+
+// prepare parses and compiles `policy` (and any fragments) for evaluation
+// against the data in `store`.
+func prepare(ctx context.Context, policy string, options *policyOptions, store storage.Store) (*compiled, error) {
+	module, err := parseModule("sanshell-authz-policy.rego", policy)
+	if err != nil {
+		return nil, err
+	}
+	modules := []*ast.Module{module}
+	names := make([]string, 0, len(options.fragments))
+	for name := range options.fragments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:%s", len(policy), policy)
+	for _, name := range names {
+		m, err := parseModule(name+".rego", options.fragments[name])
+		if err != nil {
+			return nil, fmt.Errorf("fragment %s: %w", name, err)
+		}
+		modules = append(modules, m)
+		fmt.Fprintf(h, "%d:%s%d:%s", len(name), name, len(options.fragments[name]), options.fragments[name])
+	}
+	withModules := func(opts ...func(*rego.Rego)) []func(*rego.Rego) {
+		opts = append(opts, rego.Store(store))
+		opts = append(opts, builtinFuncs...)
+		for _, m := range modules {
+			opts = append(opts, rego.ParsedModule(m))
+		}
+		return opts
 	}
 
 	b := &bytes.Buffer{}
-	r := rego.New(
+	r := rego.New(withModules(
 		rego.Query(options.query),
-		rego.ParsedModule(module),
 		rego.EnablePrintStatements(true),
 		rego.PrintHook(topdown.NewPrintHook(b)),
-	)
+	)...)
 
 	prepared, err := r.PrepareForEval(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("rego: PrepareForEval() error: %w", err)
 	}
-	return &AuthzPolicy{
-		query: prepared,
-		b:     b,
+
+	r = rego.New(withModules(
+		rego.Query(options.denialHintsQuery),
+	)...)
+	denialHints, err := r.PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rego: PrepareForEval() for denial hints error: %w", err)
+	}
+
+	r = rego.New(withModules(
+		rego.Query(options.approvalQuery),
+	)...)
+	approval, err := r.PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rego: PrepareForEval() for approval error: %w", err)
+	}
+
+	r = rego.New(withModules(
+		rego.Query(options.rateLimitQuery),
+	)...)
+	rateLimit, err := r.PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rego: PrepareForEval() for rate limit error: %w", err)
+	}
+	return &compiled{
+		query:       prepared,
+		denialHints: denialHints,
+		approval:    approval,
+		rateLimit:   rateLimit,
+		b:           b,
+		version:     hex.EncodeToString(h.Sum(nil)),
 	}, nil
 }
 
@@ -122,12 +318,164 @@ func NewAuthzPolicy(ctx context.Context, policy string, opts ...Option) (*AuthzP
 // `input` is permitted by the policy.
 func (q *AuthzPolicy) Eval(ctx context.Context, input interface{}) (bool, error) {
 	logger := logr.FromContextOrDiscard(ctx)
-	results, err := q.query.Eval(ctx, rego.EvalInput(input))
+	q.mu.RLock()
+	c := q.compiled
+	q.mu.RUnlock()
+	results, err := c.query.Eval(ctx, rego.EvalInput(input))
 	if err != nil {
 		return false, fmt.Errorf("authz policy evaluation error: %w", err)
 	}
-	if q.b.Len() > 0 {
-		logger.V(1).Info("print statements", "buffer", q.b.String())
+	if c.b.Len() > 0 {
+		logger.V(1).Info("print statements", "buffer", c.b.String())
+	}
+	return results.Allowed(), nil
+}
+
+// MatchedRules evaluates the allow query against `input` with tracing and
+// returns the rules whose bodies were satisfied along the way, other than
+// defaults, as "name (file:row)" in the order they were first satisfied.
+// It's intended for debugging policies as it's much slower than Eval.
+func (q *AuthzPolicy) MatchedRules(ctx context.Context, input interface{}) ([]string, error) {
+	q.mu.RLock()
+	c := q.compiled
+	q.mu.RUnlock()
+	tracer := topdown.NewBufferTracer()
+	if _, err := c.query.Eval(ctx, rego.EvalInput(input), rego.EvalQueryTracer(tracer)); err != nil {
+		return nil, fmt.Errorf("authz policy evaluation error: %w", err)
+	}
+	var rules []string
+	seen := make(map[string]bool)
+	for _, e := range *tracer {
+		rule, ok := e.Node.(*ast.Rule)
+		if e.Op != topdown.ExitOp || !ok || rule.Default {
+			continue
+		}
+		r := rule.Head.Name.String()
+		if rule.Location != nil {
+			r = fmt.Sprintf("%s (%s:%d)", r, rule.Location.File, rule.Location.Row)
+		}
+		if !seen[r] {
+			seen[r] = true
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+// DenialHints evaluates the denial hints query (DefaultDenialHintsQuery unless
+// changed with WithDenialHintsQuery) against `input` and returns any explanations
+// the policy provides for why it was denied. Returns no hints (and no error)
+// if the policy doesn't define any.
+func (q *AuthzPolicy) DenialHints(ctx context.Context, input interface{}) ([]string, error) {
+	q.mu.RLock()
+	c := q.compiled
+	q.mu.RUnlock()
+	results, err := c.denialHints.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("authz denial hints evaluation error: %w", err)
+	}
+	var hints []string
+	for _, r := range results {
+		for _, e := range r.Expressions {
+			switch v := e.Value.(type) {
+			case string:
+				hints = append(hints, v)
+			case []interface{}:
+				for _, h := range v {
+					if s, ok := h.(string); ok {
+						hints = append(hints, s)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(hints)
+	return hints, nil
+}
+
+// RequiresApproval evaluates the approval query (DefaultApprovalQuery unless
+// changed with WithApprovalQuery) against `input`, returning 'true' iff the
+// policy requires the request to be approved before it runs.
+func (q *AuthzPolicy) RequiresApproval(ctx context.Context, input interface{}) (bool, error) {
+	q.mu.RLock()
+	c := q.compiled
+	q.mu.RUnlock()
+	results, err := c.approval.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return false, fmt.Errorf("authz approval evaluation error: %w", err)
 	}
 	return results.Allowed(), nil
 }
+
+// A RateLimit is the maximum rate the policy permits a request to be made at.
+// Policies define it as an object, i.e. to limit each caller to 2 requests a
+// second with bursts of up to 10 for each method:
+//
+//	rate_limit = {"rps": 2, "burst": 10, "key": input.method}
+type RateLimit struct {
+	// RPS is the sustained rate in requests per second. It must be positive.
+	RPS float64
+	// Burst is the number of requests which may be made at once. If not
+	// set by the policy it's RPS rounded up.
+	Burst int
+	// Key optionally partitions requests sharing a limit. Each caller has
+	// a separate limit for each distinct key.
+	Key string
+}
+
+// RateLimit evaluates the rate limit query (DefaultRateLimitQuery unless
+// changed with WithRateLimitQuery) against `input`, returning the limit the
+// policy sets for the request or nil if there isn't one.
+func (q *AuthzPolicy) RateLimit(ctx context.Context, input interface{}) (*RateLimit, error) {
+	q.mu.RLock()
+	c := q.compiled
+	q.mu.RUnlock()
+	results, err := c.rateLimit.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("authz rate limit evaluation error: %w", err)
+	}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return nil, nil
+	}
+	v, ok := results[0].Expressions[0].Value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("rate limit must be an object, not %T", results[0].Expressions[0].Value)
+	}
+	limit := &RateLimit{}
+	for k, val := range v {
+		switch k {
+		case "rps", "burst":
+			n, ok := val.(json.Number)
+			if !ok {
+				return nil, fmt.Errorf("rate limit %s must be a number", k)
+			}
+			f, err := n.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("rate limit %s: %w", k, err)
+			}
+			if k == "rps" {
+				limit.RPS = f
+			} else {
+				limit.Burst = int(f)
+			}
+		case "key":
+			if limit.Key, ok = val.(string); !ok {
+				return nil, fmt.Errorf("rate limit key must be a string")
+			}
+		default:
+			return nil, fmt.Errorf("unknown rate limit field %q", k)
+		}
+	}
+	if limit.RPS <= 0 {
+		return nil, fmt.Errorf("rate limit rps must be positive")
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = int(math.Ceil(limit.RPS))
+	}
+	return limit, nil
+}
+
+// AllowQuery returns the query used to make authorization decisions.
+func (q *AuthzPolicy) AllowQuery() string {
+	return q.options.query
+}
@@ -18,10 +18,20 @@ package opa
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+	"github.com/google/go-cmp/cmp"
 	"github.com/open-policy-agent/opa/ast"
 )
 
@@ -190,3 +200,603 @@ allow {
 		})
 	}
 }
+
+func TestAuthzPolicyUpdate(t *testing.T) {
+	ctx := context.Background()
+	policy, err := NewAuthzPolicy(ctx, `
+package sansshell.authz
+
+allow {
+  input.foo = "bar"
+}
+`)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+
+	check := func(input interface{}, want bool) {
+		t.Helper()
+		allowed, err := policy.Eval(ctx, input)
+		testutil.FatalOnErr("Eval", err, t)
+		if allowed != want {
+			t.Fatalf("Eval(%v), allowed = %v, want %v", input, allowed, want)
+		}
+	}
+	check(map[string]string{"foo": "bar"}, true)
+	check(map[string]string{"foo": "baz"}, false)
+	if got := policy.Generation(); got != 0 {
+		t.Errorf("Generation() = %d before Update, want 0", got)
+	}
+	version := policy.Version()
+	if version == "" {
+		t.Error("Version() is empty")
+	}
+
+	err = policy.Update(ctx, `
+package sansshell.authz
+
+allow {
+  input.foo = "baz"
+}
+`)
+	testutil.FatalOnErr("Update", err, t)
+	check(map[string]string{"foo": "bar"}, false)
+	check(map[string]string{"foo": "baz"}, true)
+
+	// A bad update leaves the current policy in place.
+	err = policy.Update(ctx, "package another.name")
+	testutil.FatalOnNoErr("Update with bad package", err, t)
+	check(map[string]string{"foo": "baz"}, true)
+	if got := policy.Generation(); got != 1 {
+		t.Errorf("Generation() = %d after one good Update, want 1", got)
+	}
+	if policy.Version() == version {
+		t.Error("Version() unchanged after Update")
+	}
+
+	// The same policy has the same version anywhere.
+	same, err := NewAuthzPolicy(ctx, `
+package sansshell.authz
+
+allow {
+  input.foo = "baz"
+}
+`)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	if got, want := same.Version(), policy.Version(); got != want {
+		t.Errorf("Version() = %s for the same policy, want %s", got, want)
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	filename := filepath.Join(t.TempDir(), "policy.rego")
+	write := func(policy string, mod time.Time) {
+		t.Helper()
+		err := os.WriteFile(filename, []byte(policy), 0644)
+		testutil.FatalOnErr("WriteFile", err, t)
+		err = os.Chtimes(filename, mod, mod)
+		testutil.FatalOnErr("Chtimes", err, t)
+	}
+	waitFor := func(policy *AuthzPolicy, input interface{}) {
+		t.Helper()
+		for i := 0; ; i++ {
+			allowed, err := policy.Eval(ctx, input)
+			testutil.FatalOnErr("Eval", err, t)
+			if allowed {
+				return
+			}
+			if i > 500 {
+				t.Fatalf("policy never allowed %v", input)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	now := time.Now()
+	allowBar := `
+package sansshell.authz
+
+allow {
+  input.foo = "bar"
+}
+`
+	write(allowBar, now)
+	policy, err := NewAuthzPolicy(ctx, allowBar)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	go WatchFile(ctx, filename, 10*time.Millisecond, policy)
+
+	// A broken policy is ignored.
+	write("package another.name", now.Add(time.Minute))
+	time.Sleep(50 * time.Millisecond)
+	waitFor(policy, map[string]string{"foo": "bar"})
+
+	write(`
+package sansshell.authz
+
+allow {
+  input.foo = "baz"
+}
+`, now.Add(2*time.Minute))
+	waitFor(policy, map[string]string{"foo": "baz"})
+}
+
+type verifierFunc func(ctx context.Context, source string, policy []byte) error
+
+func (f verifierFunc) VerifyPolicy(ctx context.Context, source string, policy []byte) error {
+	return f(ctx, source, policy)
+}
+
+func TestWatchFileVerified(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	filename := filepath.Join(t.TempDir(), "policy.rego")
+	allowBar := `
+package sansshell.authz
+
+allow {
+  input.foo = "bar"
+}
+`
+	allowBaz := `
+package sansshell.authz
+
+allow {
+  input.foo = "baz"
+}
+`
+	err := os.WriteFile(filename, []byte(allowBar), 0644)
+	testutil.FatalOnErr("WriteFile", err, t)
+	policy, err := NewAuthzPolicy(ctx, allowBar)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	verified := make(chan string, 10)
+	v := verifierFunc(func(ctx context.Context, source string, p []byte) error {
+		select {
+		case verified <- source:
+		default:
+		}
+		if strings.Contains(string(p), "baz") {
+			return errors.New("bad signature")
+		}
+		return nil
+	})
+	go WatchFileVerified(ctx, filename, 10*time.Millisecond, policy, v)
+	time.Sleep(50 * time.Millisecond)
+
+	mod := time.Now().Add(time.Minute)
+	err = os.WriteFile(filename, []byte(allowBaz), 0644)
+	testutil.FatalOnErr("WriteFile", err, t)
+	err = os.Chtimes(filename, mod, mod)
+	testutil.FatalOnErr("Chtimes", err, t)
+	select {
+	case source := <-verified:
+		if source != filename {
+			t.Errorf("verified source %s, want %s", source, filename)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("changed policy never verified")
+	}
+	// Give it a chance to (incorrectly) apply the update.
+	time.Sleep(50 * time.Millisecond)
+	allowed, err := policy.Eval(ctx, map[string]string{"foo": "baz"})
+	testutil.FatalOnErr("Eval", err, t)
+	if allowed {
+		t.Fatal("unverified policy was applied")
+	}
+
+	// Rejected policies are retried as the signature may not have been
+	// updated yet.
+	select {
+	case <-verified:
+	case <-time.After(5 * time.Second):
+		t.Fatal("rejected policy never verified again")
+	}
+}
+
+func TestDenialHints(t *testing.T) {
+	ctx := context.Background()
+	policyString := `
+package sansshell.authz
+
+deny_reason[msg] {
+  input.foo = "bar"
+  msg := "foo may not be bar"
+}
+
+deny_reason[msg] {
+  input.baz
+  msg := "baz must not be set"
+}
+
+why = "custom"
+`
+	policy, err := NewAuthzPolicy(ctx, policyString)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	hints, err := policy.DenialHints(ctx, map[string]string{"foo": "bar", "baz": "1"})
+	testutil.FatalOnErr("DenialHints", err, t)
+	testutil.DiffErr("DenialHints", hints, []string{"baz must not be set", "foo may not be bar"}, t)
+
+	hints, err = policy.DenialHints(ctx, map[string]string{})
+	testutil.FatalOnErr("DenialHints", err, t)
+	if len(hints) != 0 {
+		t.Fatalf("DenialHints() = %v, want none", hints)
+	}
+
+	policy, err = NewAuthzPolicy(ctx, policyString, WithDenialHintsQuery("data.sansshell.authz.why"))
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	hints, err = policy.DenialHints(ctx, nil)
+	testutil.FatalOnErr("DenialHints", err, t)
+	testutil.DiffErr("DenialHints with custom query", hints, []string{"custom"}, t)
+
+	// Policies without any deny_reason just produce no hints.
+	policy, err = NewAuthzPolicy(ctx, "package sansshell.authz")
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	hints, err = policy.DenialHints(ctx, nil)
+	testutil.FatalOnErr("DenialHints", err, t)
+	if len(hints) != 0 {
+		t.Fatalf("DenialHints() = %v, want none", hints)
+	}
+}
+
+func TestMatchedRules(t *testing.T) {
+	ctx := context.Background()
+	policyString := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.foo = "bar"
+}
+
+allow {
+  is_admin
+}
+
+is_admin {
+  input.user = "admin"
+}
+`
+	policy, err := NewAuthzPolicy(ctx, policyString)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	for _, tc := range []struct {
+		name  string
+		input map[string]string
+		want  []string
+	}{
+		{
+			name:  "first rule",
+			input: map[string]string{"foo": "bar"},
+			want:  []string{"allow (sanshell-authz-policy.rego:6)"},
+		},
+		{
+			name:  "helper rule",
+			input: map[string]string{"user": "admin"},
+			want:  []string{"is_admin (sanshell-authz-policy.rego:14)", "allow (sanshell-authz-policy.rego:10)"},
+		},
+		{
+			name:  "no match",
+			input: map[string]string{},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := policy.MatchedRules(ctx, tc.input)
+			testutil.FatalOnErr("MatchedRules", err, t)
+			testutil.DiffErr("MatchedRules", got, tc.want, t)
+		})
+	}
+}
+
+func TestRequiresApproval(t *testing.T) {
+	ctx := context.Background()
+	policyString := `
+package sansshell.authz
+
+require_approval {
+  input.foo = "bar"
+}
+
+dangerous {
+  input.baz
+}
+`
+	policy, err := NewAuthzPolicy(ctx, policyString)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	for _, tc := range []struct {
+		name  string
+		input map[string]string
+		want  bool
+	}{
+		{name: "required", input: map[string]string{"foo": "bar"}, want: true},
+		{name: "undefined", input: map[string]string{"foo": "baz"}},
+	} {
+		got, err := policy.RequiresApproval(ctx, tc.input)
+		testutil.FatalOnErr(tc.name, err, t)
+		if got != tc.want {
+			t.Errorf("%s: RequiresApproval() = %t, want %t", tc.name, got, tc.want)
+		}
+	}
+
+	policy, err = NewAuthzPolicy(ctx, policyString, WithApprovalQuery("data.sansshell.authz.dangerous"))
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	got, err := policy.RequiresApproval(ctx, map[string]string{"baz": "1"})
+	testutil.FatalOnErr("RequiresApproval with custom query", err, t)
+	if !got {
+		t.Error("RequiresApproval() with custom query = false, want true")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	ctx := context.Background()
+	policyString := `
+package sansshell.authz
+
+rate_limit = {"rps": 2, "burst": 5, "key": input.method} {
+  input.foo = "bar"
+}
+
+rate_limit = {"rps": 0.5} {
+  input.foo = "slow"
+}
+
+rate_limit = {"rps": 0} {
+  input.foo = "zero"
+}
+
+rate_limit = "fast" {
+  input.foo = "string"
+}
+
+rate_limit = {"rps": 1, "bogus": true} {
+  input.foo = "unknown"
+}
+
+other = {"rps": 3} {
+  input.foo = "other"
+}
+`
+	policy, err := NewAuthzPolicy(ctx, policyString)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	for _, tc := range []struct {
+		name    string
+		input   map[string]string
+		want    *RateLimit
+		wantErr bool
+	}{
+		{name: "limit", input: map[string]string{"foo": "bar", "method": "/Foo/Bar"}, want: &RateLimit{RPS: 2, Burst: 5, Key: "/Foo/Bar"}},
+		{name: "default burst", input: map[string]string{"foo": "slow"}, want: &RateLimit{RPS: 0.5, Burst: 1}},
+		{name: "undefined", input: map[string]string{"foo": "baz"}},
+		{name: "zero rps", input: map[string]string{"foo": "zero"}, wantErr: true},
+		{name: "not an object", input: map[string]string{"foo": "string"}, wantErr: true},
+		{name: "unknown field", input: map[string]string{"foo": "unknown"}, wantErr: true},
+	} {
+		got, err := policy.RateLimit(ctx, tc.input)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%s: RateLimit() error %v, want error %t", tc.name, err, tc.wantErr)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("%s: RateLimit() mismatch (-want +got):\n%s", tc.name, diff)
+		}
+	}
+
+	policy, err = NewAuthzPolicy(ctx, policyString, WithRateLimitQuery("data.sansshell.authz.other"))
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	got, err := policy.RateLimit(ctx, map[string]string{"foo": "other"})
+	testutil.FatalOnErr("RateLimit with custom query", err, t)
+	if got == nil || got.RPS != 3 {
+		t.Errorf("RateLimit() with custom query = %+v, want rps 3", got)
+	}
+}
+
+func TestWithFragments(t *testing.T) {
+	ctx := context.Background()
+	main := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.foo = "bar"
+}
+`
+	fragments := map[string]string{
+		"baz": `
+package sansshell.authz
+
+allow {
+  input.foo = "baz"
+}
+`,
+	}
+	policy, err := NewAuthzPolicy(ctx, main, WithFragments(fragments))
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+
+	check := func(input interface{}, want bool) {
+		t.Helper()
+		allowed, err := policy.Eval(ctx, input)
+		testutil.FatalOnErr("Eval", err, t)
+		if allowed != want {
+			t.Fatalf("Eval(%v), allowed = %v, want %v", input, allowed, want)
+		}
+	}
+	check(map[string]string{"foo": "bar"}, true)
+	check(map[string]string{"foo": "baz"}, true)
+	check(map[string]string{"foo": "qux"}, false)
+
+	// Fragments survive replacing the main policy.
+	err = policy.Update(ctx, `
+package sansshell.authz
+
+default allow = false
+`)
+	testutil.FatalOnErr("Update", err, t)
+	check(map[string]string{"foo": "bar"}, false)
+	check(map[string]string{"foo": "baz"}, true)
+
+	for _, tc := range []struct {
+		name     string
+		fragment string
+	}{
+		{
+			name:     "wrong package",
+			fragment: "package another.name",
+		},
+		{
+			name:     "parse error",
+			fragment: "package sansshell.authz\n\nallow {",
+		},
+		{
+			name: "conflicting default",
+			fragment: `
+package sansshell.authz
+
+default allow = true
+`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewAuthzPolicy(ctx, main, WithFragments(map[string]string{"bad": tc.fragment}))
+			testutil.FatalOnNoErr(tc.name, err, t)
+		})
+	}
+}
+
+func TestSetData(t *testing.T) {
+	ctx := context.Background()
+	policy, err := NewAuthzPolicy(ctx, `
+package sansshell.authz
+
+allow {
+  data.external.owners[input.host] = input.user
+}
+`)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	input := map[string]string{"host": "db1", "user": "alice"}
+
+	allowed, err := policy.Eval(ctx, input)
+	testutil.FatalOnErr("Eval", err, t)
+	if allowed {
+		t.Fatal("allowed without data")
+	}
+
+	gen := policy.Generation()
+	err = policy.SetData(ctx, "owners", map[string]string{"db1": "alice"})
+	testutil.FatalOnErr("SetData", err, t)
+	if policy.Generation() == gen {
+		t.Error("SetData didn't change the generation")
+	}
+	allowed, err = policy.Eval(ctx, input)
+	testutil.FatalOnErr("Eval", err, t)
+	if !allowed {
+		t.Fatal("not allowed with data")
+	}
+
+	// Data is kept when the policy changes.
+	err = policy.Update(ctx, `
+package sansshell.authz
+
+allow {
+  data.external.owners[input.host] = input.user
+  input.user != "bob"
+}
+`)
+	testutil.FatalOnErr("Update", err, t)
+	allowed, err = policy.Eval(ctx, input)
+	testutil.FatalOnErr("Eval", err, t)
+	if !allowed {
+		t.Fatal("data lost on Update")
+	}
+
+	err = policy.SetData(ctx, "owners", map[string]string{"db1": "carol"})
+	testutil.FatalOnErr("SetData", err, t)
+	allowed, err = policy.Eval(ctx, input)
+	testutil.FatalOnErr("Eval", err, t)
+	if allowed {
+		t.Fatal("allowed with replaced data")
+	}
+
+	for _, name := range []string{"", "bad-name", "1st", "a.b"} {
+		if err := policy.SetData(ctx, name, map[string]string{}); err == nil {
+			t.Errorf("SetData(%q) didn't fail", name)
+		}
+	}
+	if err := policy.SetData(ctx, "bad", make(chan int)); err == nil {
+		t.Error("SetData of a non-JSON value didn't fail")
+	}
+}
+
+func TestLoadAndPollData(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	doc := `{"oncall": ["alice"]}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprint(w, doc)
+	}))
+	defer ts.Close()
+	filename := filepath.Join(t.TempDir(), "data.json")
+	err := os.WriteFile(filename, []byte(`{"windows": [{"start": 1}]}`), 0644)
+	testutil.FatalOnErr("WriteFile", err, t)
+
+	got, err := LoadData(ctx, ts.Client(), ts.URL)
+	testutil.FatalOnErr("LoadData", err, t)
+	want := map[string]interface{}{"oncall": []interface{}{"alice"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LoadData(%s) (-want +got):\n%s", ts.URL, diff)
+	}
+	got, err = LoadData(ctx, ts.Client(), filename)
+	testutil.FatalOnErr("LoadData", err, t)
+	want = map[string]interface{}{"windows": []interface{}{map[string]interface{}{"start": json.Number("1")}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LoadData(%s) (-want +got):\n%s", filename, diff)
+	}
+	if _, err := LoadData(ctx, ts.Client(), filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadData of a missing file didn't fail")
+	}
+	if _, err := ParseData([]byte("{not json")); err == nil {
+		t.Error("ParseData of invalid JSON didn't fail")
+	}
+
+	policy, err := NewAuthzPolicy(ctx, `
+package sansshell.authz
+
+allow {
+  input.user in data.external.roster.oncall
+}
+`)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	go PollData(ctx, ts.Client(), "roster", ts.URL, 10*time.Millisecond, policy)
+	waitFor := func(user string, want bool) {
+		t.Helper()
+		for i := 0; ; i++ {
+			allowed, err := policy.Eval(ctx, map[string]string{"user": user})
+			testutil.FatalOnErr("Eval", err, t)
+			if allowed == want {
+				return
+			}
+			if i > 500 {
+				t.Fatalf("policy never returned %t for %s", want, user)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitFor("alice", true)
+	waitFor("bob", false)
+
+	// Invalid documents are ignored.
+	mu.Lock()
+	doc = "{not json"
+	mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	waitFor("alice", true)
+
+	mu.Lock()
+	doc = `{"oncall": ["bob"]}`
+	mu.Unlock()
+	waitFor("bob", true)
+	waitFor("alice", false)
+}
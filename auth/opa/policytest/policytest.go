@@ -0,0 +1,286 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package policytest helps operators test sansshell OPA policies before
+// deploying them. It builds the same policy input a sansshell server would
+// generate for a request from a given identity, and can either evaluate a
+// policy against it directly (for Go unit tests) or write inputs out as a Rego
+// module of fixtures for use with `opa test`:
+//
+//	read := policytest.MustInput("/LocalFile.LocalFile/Read",
+//	  &localfile.ReadActionRequest{...},
+//	  policytest.WithCert(policytest.NewCert("alice", "spiffe://example.com/alice")))
+//	err := policytest.Check(ctx, policy, read)
+//
+// or, in a Rego test next to the policy,
+//
+//	test_read_hosts { allow with input as data.sansshell.fixtures.read }
+package policytest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Snowflake-Labs/sansshell/auth/inspect"
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+// DefaultFixturesPackage is the Rego package WriteFixtures uses if none is given.
+const DefaultFixturesPackage = "sansshell.fixtures"
+
+// request is the state an InputOption can modify.
+type request struct {
+	cert      *x509.Certificate
+	spiffeID  *url.URL
+	peerAddr  net.Addr
+	hostAddr  net.Addr
+	md        metadata.MD
+	principal *rpcauth.PrincipalAuthInput
+}
+
+// An InputOption describes part of the request or caller identity.
+type InputOption interface {
+	apply(*request) error
+}
+
+type optionFunc func(*request) error
+
+func (o optionFunc) apply(r *request) error {
+	return o(r)
+}
+
+// WithCert returns an option setting the client certificate presented by
+// the caller. See NewCert for building one.
+func WithCert(cert *x509.Certificate) InputOption {
+	return optionFunc(func(r *request) error {
+		r.cert = cert
+		if r.spiffeID == nil {
+			// TLS only reports a SPIFFE ID when the cert has exactly one URI SAN using that scheme.
+			if len(cert.URIs) == 1 && cert.URIs[0].Scheme == "spiffe" {
+				r.spiffeID = cert.URIs[0]
+			}
+		}
+		return nil
+	})
+}
+
+// WithPeerAddr returns an option setting the caller's address as "ip:port".
+func WithPeerAddr(hostport string) InputOption {
+	return optionFunc(func(r *request) error {
+		addr, err := net.ResolveTCPAddr("tcp", hostport)
+		if err != nil {
+			return err
+		}
+		r.peerAddr = addr
+		return nil
+	})
+}
+
+// WithHostAddr returns an option setting the address of the server handling
+// the request as "ip:port" (the server adds this as input.host.net).
+func WithHostAddr(hostport string) InputOption {
+	return optionFunc(func(r *request) error {
+		addr, err := net.ResolveTCPAddr("tcp", hostport)
+		if err != nil {
+			return err
+		}
+		r.hostAddr = addr
+		return nil
+	})
+}
+
+// WithMetadata returns an option adding key/value pairs to the request's
+// gRPC metadata.
+func WithMetadata(kv ...string) InputOption {
+	return optionFunc(func(r *request) error {
+		if len(kv)%2 != 0 {
+			return fmt.Errorf("odd number of metadata key/values: %v", kv)
+		}
+		r.md = metadata.Join(r.md, metadata.Pairs(kv...))
+		return nil
+	})
+}
+
+// WithJustification returns an option setting the justification a client
+// sends with sanssh --justification.
+func WithJustification(justification string) InputOption {
+	return WithMetadata(rpcauth.ReqJustKey, justification)
+}
+
+// WithProxyTarget returns an option setting the target a client asked a
+// proxy for when it forwarded the request (the server adds this as
+// input.host.target).
+func WithProxyTarget(target string) InputOption {
+	return WithMetadata(rpcauth.ProxyTargetKey, target)
+}
+
+// WithPrincipal returns an option setting input.peer.principal, as an authz
+// hook (i.e. OIDC tokens or a group provider) would in a real server.
+func WithPrincipal(id string, groups ...string) InputOption {
+	return optionFunc(func(r *request) error {
+		r.principal = &rpcauth.PrincipalAuthInput{ID: id, Groups: groups}
+		return nil
+	})
+}
+
+// NewCert returns a certificate suitable for WithCert identifying `commonName`.
+// Each SAN is added as a URI if it contains "://", an IP address or email
+// address if it parses as one, and a DNS name otherwise. The certificate
+// isn't signed as policy input only uses its fields.
+func NewCert(commonName string, sans ...string) *x509.Certificate {
+	now := time.Now()
+	cert := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		Issuer:       pkix.Name{CommonName: "policytest CA"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+	}
+	for _, san := range sans {
+		if strings.Contains(san, "://") {
+			if u, err := url.Parse(san); err == nil {
+				cert.URIs = append(cert.URIs, u)
+				continue
+			}
+		}
+		if ip := net.ParseIP(san); ip != nil {
+			cert.IPAddresses = append(cert.IPAddresses, ip)
+			continue
+		}
+		if _, err := mail.ParseAddress(san); err == nil {
+			cert.EmailAddresses = append(cert.EmailAddresses, san)
+			continue
+		}
+		cert.DNSNames = append(cert.DNSNames, san)
+	}
+	return cert
+}
+
+// Input returns the policy input a sansshell server builds for a call to
+// `method` with `req` as the request message. Without options the caller
+// has no certificate or address; use options to describe the caller. As in
+// a server, input.request is set for requests with an inspect.Inspector.
+func Input(method string, req proto.Message, opts ...InputOption) (*rpcauth.RPCAuthInput, error) {
+	r := &request{}
+	for _, opt := range opts {
+		if err := opt.apply(r); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := context.Background()
+	if r.md != nil {
+		ctx = metadata.NewIncomingContext(ctx, r.md)
+	}
+	if r.cert != nil || r.peerAddr != nil {
+		p := &peer.Peer{Addr: r.peerAddr}
+		if r.cert != nil {
+			p.AuthInfo = credentials.TLSInfo{
+				State: tls.ConnectionState{
+					PeerCertificates: []*x509.Certificate{r.cert},
+				},
+				SPIFFEID: r.spiffeID,
+			}
+		}
+		ctx = peer.NewContext(ctx, p)
+	}
+	input, err := rpcauth.NewRPCAuthInput(ctx, method, req)
+	if err != nil {
+		return nil, err
+	}
+	if r.hostAddr != nil {
+		if err := rpcauth.HostNetHook(r.hostAddr).Hook(ctx, input); err != nil {
+			return nil, err
+		}
+	}
+	if err := rpcauth.ProxyTargetHook().Hook(ctx, input); err != nil {
+		return nil, err
+	}
+	if err := inspect.Hook().Hook(ctx, input); err != nil {
+		return nil, err
+	}
+	if r.principal != nil {
+		input.Peer.Principal = r.principal
+	}
+	return input, nil
+}
+
+// MustInput is like Input but panics on error. It's intended for building
+// test tables.
+func MustInput(method string, req proto.Message, opts ...InputOption) *rpcauth.RPCAuthInput {
+	input, err := Input(method, req, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return input
+}
+
+// Check evaluates `policy` against `input` exactly as a server would, running
+// any `hooks` first. It returns nil if the request is permitted, and
+// otherwise the status error the caller would receive.
+func Check(ctx context.Context, policy string, input *rpcauth.RPCAuthInput, hooks ...rpcauth.RPCAuthzHook) error {
+	authz, err := rpcauth.NewWithPolicy(ctx, policy, hooks...)
+	if err != nil {
+		return err
+	}
+	return authz.Eval(ctx, input)
+}
+
+// WriteFixtures writes a Rego module in package `pkg` (DefaultFixturesPackage if empty)
+// with one rule per input, named by its key in `inputs`, to `w`. Load the module
+// alongside a policy and its tests with `opa test` and refer to the inputs as
+// i.e. data.sansshell.fixtures.<name>. Names must be valid Rego identifiers.
+func WriteFixtures(w io.Writer, pkg string, inputs map[string]*rpcauth.RPCAuthInput) error {
+	if pkg == "" {
+		pkg = DefaultFixturesPackage
+	}
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if _, err := fmt.Fprintf(w, "# Code generated by policytest.WriteFixtures. DO NOT EDIT.\n\npackage %s\n", pkg); err != nil {
+		return err
+	}
+	for _, name := range names {
+		b, err := json.MarshalIndent(inputs[name], "", "  ")
+		if err != nil {
+			return fmt.Errorf("can't marshal %s: %v", name, err)
+		}
+		if _, err := fmt.Fprintf(w, "\n%s = %s\n", name, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package policytest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-policy-agent/opa/tester"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	execpb "github.com/Snowflake-Labs/sansshell/services/exec"
+	lfpb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+var (
+	ops   = WithPrincipal("bob", "ops")
+	admin = WithCert(NewCert("admin-host", "spiffe://admin.example.com/host/1", "10.1.2.3", "admin.example.com"))
+)
+
+func read(filename string) *lfpb.ReadActionRequest {
+	return &lfpb.ReadActionRequest{
+		Request: &lfpb.ReadActionRequest_File{
+			File: &lfpb.ReadRequest{Filename: filename},
+		},
+	}
+}
+
+var echo = &execpb.ExecRequest{Command: "/bin/echo", Args: []string{"hi"}}
+
+func fixtures() map[string]*rpcauth.RPCAuthInput {
+	return map[string]*rpcauth.RPCAuthInput{
+		"ops_read_log":         MustInput("/LocalFile.LocalFile/Read", read("/var/log/messages"), ops),
+		"ops_read_shadow":      MustInput("/LocalFile.LocalFile/Read", read("/etc/shadow"), ops),
+		"admin_exec":           MustInput("/Exec.Exec/Run", echo, admin),
+		"admin_exec_justified": MustInput("/Exec.Exec/Run", echo, admin, WithJustification("ticket-123")),
+	}
+}
+
+func TestInput(t *testing.T) {
+	input, err := Input("/Exec.Exec/Run", echo, admin, WithPeerAddr("10.0.0.1:4567"), WithHostAddr("10.0.0.2:50042"), WithProxyTarget("canary1:50042"), WithMetadata("foo", "bar"))
+	testutil.FatalOnErr("Input", err, t)
+	if input.MessageType != "Exec.ExecRequest" {
+		t.Errorf("type = %q, want Exec.ExecRequest", input.MessageType)
+	}
+	if got, want := input.Peer.Net.Address, "10.0.0.1"; got != want {
+		t.Errorf("peer address = %q, want %q", got, want)
+	}
+	if got, want := input.Host.Net.Port, "50042"; got != want {
+		t.Errorf("host port = %q, want %q", got, want)
+	}
+	if got, want := input.Host.Target, "canary1:50042"; got != want {
+		t.Errorf("host target = %q, want %q", got, want)
+	}
+	if got, want := input.Peer.Cert.SPIFFEID, "spiffe://admin.example.com/host/1"; got != want {
+		t.Errorf("spiffe id = %q, want %q", got, want)
+	}
+	cert := input.Peer.Cert
+	if len(cert.IPAddresses) != 1 || len(cert.DNSNames) != 1 || cert.Subject.CommonName != "admin-host" {
+		t.Errorf("unexpected cert input %+v", cert)
+	}
+	if got := input.Metadata.Get("foo"); len(got) != 1 || got[0] != "bar" {
+		t.Errorf("metadata foo = %v, want [bar]", got)
+	}
+
+	_, err = Input("/Exec.Exec/Run", echo, WithMetadata("odd"))
+	testutil.FatalOnNoErr("odd metadata", err, t)
+	_, err = Input("/Exec.Exec/Run", echo, WithPeerAddr("not an address"))
+	testutil.FatalOnNoErr("bad address", err, t)
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	policy, err := os.ReadFile(filepath.Join("testdata", "policy.rego"))
+	testutil.FatalOnErr("ReadFile", err, t)
+
+	for name, input := range fixtures() {
+		name, input := name, input
+		t.Run(name, func(t *testing.T) {
+			err := Check(ctx, string(policy), input)
+			want := codes.OK
+			if name == "ops_read_shadow" || name == "admin_exec" {
+				want = codes.PermissionDenied
+			}
+			if got := status.Code(err); got != want {
+				t.Errorf("Check() = %v, want code %v", err, want)
+			}
+		})
+	}
+}
+
+func TestWriteFixtures(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	for _, f := range []string{"policy.rego", "policy_test.rego"} {
+		b, err := os.ReadFile(filepath.Join("testdata", f))
+		testutil.FatalOnErr("ReadFile", err, t)
+		testutil.FatalOnErr("WriteFile", os.WriteFile(filepath.Join(dir, f), b, 0644), t)
+	}
+	out, err := os.Create(filepath.Join(dir, "fixtures.rego"))
+	testutil.FatalOnErr("Create", err, t)
+	testutil.FatalOnErr("WriteFixtures", WriteFixtures(out, "", fixtures()), t)
+	testutil.FatalOnErr("Close", out.Close(), t)
+
+	// This is equivalent to running `opa test` on the directory.
+	results, err := tester.Run(ctx, dir)
+	testutil.FatalOnErr("tester.Run", err, t)
+	if len(results) != 3 {
+		t.Fatalf("ran %d rego tests, want 3", len(results))
+	}
+	for _, r := range results {
+		if !r.Pass() {
+			t.Errorf("rego test failed: %v", r)
+		}
+	}
+}
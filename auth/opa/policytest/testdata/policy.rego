@@ -0,0 +1,16 @@
+package sansshell.authz
+
+default allow = false
+
+# Anyone in the ops group may read under /var/log.
+allow {
+	input.type = "LocalFile.ReadActionRequest"
+	startswith(input.message.file.filename, "/var/log/")
+	input.peer.principal.groups[_] = "ops"
+}
+
+# Hosts in the admin SPIFFE trust domain may run anything with a justification.
+allow {
+	startswith(input.peer.cert.spiffeid, "spiffe://admin.example.com/")
+	input.metadata["sansshell-justification"][0] != ""
+}
@@ -0,0 +1,14 @@
+package sansshell.authz
+
+test_ops_read_logs {
+	allow with input as data.sansshell.fixtures.ops_read_log
+}
+
+test_ops_read_etc_denied {
+	not allow with input as data.sansshell.fixtures.ops_read_shadow
+}
+
+test_admin_needs_justification {
+	allow with input as data.sansshell.fixtures.admin_exec_justified
+	not allow with input as data.sansshell.fixtures.admin_exec
+}
@@ -0,0 +1,218 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package remote provides support for fetching sansshell authz policies
+// from an HTTP(S) endpoint and periodically refreshing them.
+//
+// The endpoint may serve either a raw rego policy or an OPA bundle
+// (a gzipped tarball). Bundles must contain exactly one .rego file which
+// declares the sansshell policy package. ETags are used so unchanged
+// policies aren't recompiled on every poll.
+package remote
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa"
+)
+
+const (
+	// DefaultRefreshInterval is how often a Fetcher polls by default.
+	DefaultRefreshInterval = time.Minute
+
+	// Policies (or bundles) larger than this are rejected.
+	maxPolicySize = 16 * 1024 * 1024
+)
+
+// A Fetcher retrieves a policy from a URL.
+type Fetcher struct {
+	url      string
+	client   *http.Client
+	verifier opa.PolicyVerifier
+
+	mu   sync.Mutex
+	etag string
+}
+
+// An Option controls the behavior of a Fetcher.
+type Option interface {
+	apply(*Fetcher)
+}
+
+type optionFunc func(*Fetcher)
+
+func (o optionFunc) apply(f *Fetcher) {
+	o(f)
+}
+
+// WithHTTPClient returns an option to use `client` for requests rather than
+// http.DefaultClient. This is generally used to configure TLS.
+func WithHTTPClient(client *http.Client) Option {
+	return optionFunc(func(f *Fetcher) {
+		f.client = client
+	})
+}
+
+// WithVerifier returns an option to reject policies (or bundles) which
+// `verifier` doesn't accept. It's given the raw response body.
+func WithVerifier(verifier opa.PolicyVerifier) Option {
+	return optionFunc(func(f *Fetcher) {
+		f.verifier = verifier
+	})
+}
+
+// New returns a Fetcher for the policy at `url`.
+func New(url string, opts ...Option) *Fetcher {
+	f := &Fetcher{
+		url:    url,
+		client: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt.apply(f)
+	}
+	return f
+}
+
+// Fetch retrieves the policy. If the server indicates the policy hasn't changed
+// since the last successful Fetch (via ETag) changed will be false and policy empty.
+func (f *Fetcher) Fetch(ctx context.Context) (policy string, changed bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return "", false, err
+	}
+	f.mu.Lock()
+	etag := f.etag
+	f.mu.Unlock()
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("can't fetch policy from %s: %w", f.url, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return "", false, nil
+	case http.StatusOK:
+	default:
+		return "", false, fmt.Errorf("fetching policy from %s returned %s", f.url, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPolicySize+1))
+	if err != nil {
+		return "", false, fmt.Errorf("can't read policy from %s: %w", f.url, err)
+	}
+	if len(body) > maxPolicySize {
+		return "", false, fmt.Errorf("policy from %s is larger than %d bytes", f.url, maxPolicySize)
+	}
+	if f.verifier != nil {
+		if err := f.verifier.VerifyPolicy(ctx, f.url, body); err != nil {
+			return "", false, fmt.Errorf("policy from %s failed verification: %w", f.url, err)
+		}
+	}
+	policy, err = parse(body)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid policy from %s: %w", f.url, err)
+	}
+
+	f.mu.Lock()
+	f.etag = resp.Header.Get("ETag")
+	f.mu.Unlock()
+	return policy, true, nil
+}
+
+// parse returns the rego policy from either a raw policy or a gzipped OPA bundle.
+func parse(body []byte) (string, error) {
+	// gzip magic number.
+	if !bytes.HasPrefix(body, []byte{0x1f, 0x8b}) {
+		return string(body), nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	tr := tar.NewReader(gz)
+	var policy string
+	found := false
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Ext(hdr.Name) != ".rego" {
+			continue
+		}
+		if found {
+			return "", errors.New("bundle contains more than one .rego file")
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			return "", err
+		}
+		policy = string(b)
+		found = true
+	}
+	if !found {
+		return "", errors.New("bundle contains no .rego files")
+	}
+	return policy, nil
+}
+
+// Poll fetches the policy every `interval` until `ctx` is done and applies any
+// changes to `policy` with AuthzPolicy.Update. Errors are logged and the
+// existing policy is left in place. This blocks so is generally run in its own goroutine.
+func (f *Fetcher) Poll(ctx context.Context, interval time.Duration, policy *opa.AuthzPolicy) {
+	logger := logr.FromContextOrDiscard(ctx).WithValues("url", f.url)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		p, changed, err := f.Fetch(ctx)
+		if err != nil {
+			logger.Error(err, "policy fetch")
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if err := policy.Update(ctx, p); err != nil {
+			logger.Error(err, "policy update")
+			continue
+		}
+		logger.Info("updated policy")
+	}
+}
@@ -0,0 +1,229 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package remote
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const (
+	allowBar = `
+package sansshell.authz
+
+allow {
+  input.foo = "bar"
+}
+`
+	allowBaz = `
+package sansshell.authz
+
+allow {
+  input.foo = "baz"
+}
+`
+)
+
+func makeBundle(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, contents := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(contents)),
+			Typeflag: tar.TypeReg,
+		})
+		testutil.FatalOnErr("WriteHeader", err, t)
+		_, err = tw.Write([]byte(contents))
+		testutil.FatalOnErr("Write", err, t)
+	}
+	testutil.FatalOnErr("tar Close", tw.Close(), t)
+	testutil.FatalOnErr("gzip Close", gz.Close(), t)
+	return buf.Bytes()
+}
+
+// policyServer serves `body` with an ETag derived from `version`.
+type policyServer struct {
+	mu      sync.Mutex
+	body    []byte
+	version int
+	status  int
+	fetches int
+}
+
+func (p *policyServer) set(body []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.body = body
+	p.version++
+}
+
+func (p *policyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fetches++
+	if p.status != 0 {
+		w.WriteHeader(p.status)
+		return
+	}
+	etag := fmt.Sprintf(`"%d"`, p.version)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Write(p.body)
+}
+
+func TestFetch(t *testing.T) {
+	ps := &policyServer{}
+	srv := httptest.NewServer(ps)
+	defer srv.Close()
+	ctx := context.Background()
+	f := New(srv.URL, WithHTTPClient(srv.Client()))
+
+	ps.set([]byte(allowBar))
+	p, changed, err := f.Fetch(ctx)
+	testutil.FatalOnErr("Fetch", err, t)
+	if !changed || p != allowBar {
+		t.Fatalf("Fetch() = %q, %v, want %q, true", p, changed, allowBar)
+	}
+
+	// Same ETag so nothing changed.
+	p, changed, err = f.Fetch(ctx)
+	testutil.FatalOnErr("Fetch", err, t)
+	if changed || p != "" {
+		t.Fatalf("Fetch() = %q, %v, want unchanged", p, changed)
+	}
+
+	ps.set(makeBundle(t, map[string]string{"data.json": "{}", "policy/authz.rego": allowBaz}))
+	p, changed, err = f.Fetch(ctx)
+	testutil.FatalOnErr("Fetch bundle", err, t)
+	if !changed || p != allowBaz {
+		t.Fatalf("Fetch() = %q, %v, want %q, true", p, changed, allowBaz)
+	}
+
+	for _, tc := range []struct {
+		name string
+		body []byte
+	}{
+		{
+			name: "bundle with 2 policies",
+			body: makeBundle(t, map[string]string{"a.rego": allowBar, "b.rego": allowBaz}),
+		},
+		{
+			name: "bundle with no policies",
+			body: makeBundle(t, map[string]string{"data.json": "{}"}),
+		},
+		{
+			name: "truncated bundle",
+			body: makeBundle(t, map[string]string{"a.rego": allowBar})[:20],
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ps.set(tc.body)
+			_, _, err := f.Fetch(ctx)
+			testutil.FatalOnNoErr(tc.name, err, t)
+		})
+	}
+
+	ps.mu.Lock()
+	ps.status = http.StatusNotFound
+	ps.mu.Unlock()
+	_, _, err = f.Fetch(ctx)
+	testutil.FatalOnNoErr("404", err, t)
+}
+
+type verifierFunc func(ctx context.Context, source string, policy []byte) error
+
+func (f verifierFunc) VerifyPolicy(ctx context.Context, source string, policy []byte) error {
+	return f(ctx, source, policy)
+}
+
+func TestFetchVerified(t *testing.T) {
+	ps := &policyServer{}
+	srv := httptest.NewServer(ps)
+	defer srv.Close()
+	ctx := context.Background()
+	bundle := makeBundle(t, map[string]string{"policy/authz.rego": allowBaz})
+	v := verifierFunc(func(ctx context.Context, source string, policy []byte) error {
+		if source != srv.URL {
+			t.Errorf("verifying %s, want %s", source, srv.URL)
+		}
+		// The raw bundle is verified, not the extracted policy.
+		if !bytes.Equal(policy, bundle) {
+			return errors.New("bad signature")
+		}
+		return nil
+	})
+	f := New(srv.URL, WithHTTPClient(srv.Client()), WithVerifier(v))
+
+	ps.set(bundle)
+	p, changed, err := f.Fetch(ctx)
+	testutil.FatalOnErr("Fetch", err, t)
+	if !changed || p != allowBaz {
+		t.Fatalf("Fetch() = %q, %v, want %q, true", p, changed, allowBaz)
+	}
+
+	ps.set([]byte(allowBar))
+	_, _, err = f.Fetch(ctx)
+	testutil.FatalOnNoErr("unverified policy", err, t)
+}
+
+func TestPoll(t *testing.T) {
+	ps := &policyServer{}
+	ps.set([]byte(allowBar))
+	srv := httptest.NewServer(ps)
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	policy, err := opa.NewAuthzPolicy(ctx, allowBar)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	f := New(srv.URL)
+	go f.Poll(ctx, 10*time.Millisecond, policy)
+
+	ps.set([]byte(allowBaz))
+	input := map[string]string{"foo": "baz"}
+	for i := 0; ; i++ {
+		allowed, err := policy.Eval(ctx, input)
+		testutil.FatalOnErr("Eval", err, t)
+		if allowed {
+			break
+		}
+		if i > 500 {
+			t.Fatal("policy never updated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,128 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package rpcauth
+
+import (
+	"context"
+	"sync"
+)
+
+// ActionInput describes a secondary check a service makes with
+// AuthorizeAction while handling a request, i.e. for each file of a
+// streaming read:
+//
+//	allow {
+//	  input.action.name = "read"
+//	  startswith(input.action.resource, "/var/log/")
+//	}
+type ActionInput struct {
+	// What the service is about to do, i.e. "read".
+	Name string `json:"name"`
+
+	// What it's about to do it to, i.e. a filename.
+	Resource string `json:"resource"`
+
+	// Any other details the service supplies.
+	Extra map[string]interface{} `json:"extra"`
+}
+
+// authzState is carried in the context of authorized requests so services
+// can make further checks with the same Authorizer and input.
+type authzState struct {
+	authz  *Authorizer
+	method string
+
+	mu    sync.Mutex
+	input *RPCAuthInput
+}
+
+func (s *authzState) setInput(input *RPCAuthInput) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.input = input
+}
+
+func (s *authzState) getInput() *RPCAuthInput {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.input
+}
+
+type authzKey struct{}
+
+// contextWithAuthz returns a copy of ctx carrying `s`.
+func contextWithAuthz(ctx context.Context, s *authzState) context.Context {
+	return context.WithValue(ctx, authzKey{}, s)
+}
+
+// AuthorizerFromContext returns the Authorizer which allowed the current
+// request, or nil if there's none.
+func AuthorizerFromContext(ctx context.Context) *Authorizer {
+	s, _ := ctx.Value(authzKey{}).(*authzState)
+	if s == nil {
+		return nil
+	}
+	return s.authz
+}
+
+// AuthorizeAction checks with the policy of the Authorizer which allowed
+// the current request whether `action` may be performed on `resource`, so
+// services can authorize work a request leads to in more detail than the
+// request itself, possibly many times for one request. The policy input is
+// that of the request (for streams the most recently received message)
+// with Action set. Hooks, audit sinks and all other Authorizer features
+// apply as for the request. Note rules which allow a request also allow
+// its actions unless they require input.action == null.
+//
+// It returns nil, a PermissionDenied status or another status.Error as
+// Authorizer.Eval does. Requests not authorized by an Authorizer (i.e.
+// servers run without a policy, as in tests) are always allowed.
+func AuthorizeAction(ctx context.Context, action, resource string, extra map[string]interface{}) error {
+	s, _ := ctx.Value(authzKey{}).(*authzState)
+	if s == nil {
+		return nil
+	}
+	input := s.getInput()
+	if input != nil {
+		input = copyInput(input)
+	} else {
+		// A stream which hasn't received a message yet.
+		var err error
+		if input, err = NewRPCAuthInput(ctx, s.method, nil); err != nil {
+			return err
+		}
+	}
+	input.Action = &ActionInput{
+		Name:     action,
+		Resource: resource,
+		Extra:    extra,
+	}
+	return s.authz.Eval(ctx, input)
+}
+
+// copyInput returns a copy of `in` which can be evaluated again without
+// hooks changing `in`.
+func copyInput(in *RPCAuthInput) *RPCAuthInput {
+	out := *in
+	if in.Peer != nil {
+		// Hooks may replace peer fields, which mustn't leak back
+		// into the original.
+		p := *in.Peer
+		out.Peer = &p
+	}
+	return &out
+}
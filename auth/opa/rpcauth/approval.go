@@ -0,0 +1,57 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package rpcauth
+
+import (
+	"context"
+)
+
+// ApprovalIDKey is the metadata key a client sets to the ID of an approved
+// request when retrying it.
+const ApprovalIDKey = "sansshell-approval-id"
+
+// An ApprovalGate decides if a request the policy requires approval for
+// (see opa.DefaultApprovalQuery) may run.
+type ApprovalGate interface {
+	// Check returns nil if `input` carries a valid approval (typically
+	// an ID in the ApprovalIDKey metadata). Otherwise it returns an error,
+	// usually after recording the request so it can be approved.
+	Check(ctx context.Context, input *RPCAuthInput) error
+}
+
+// approvalHook is a no-op RPCAuthzHook used to carry an ApprovalGate
+// through the existing hook plumbing (i.e. server.Serve) to an Authorizer.
+type approvalHook struct {
+	gate ApprovalGate
+}
+
+func (approvalHook) Hook(context.Context, *RPCAuthInput) error {
+	return nil
+}
+
+// RequireApprovals returns an RPCAuthzHook which, when passed to New or
+// NewWithPolicy, makes the Authorizer check requests the policy allows but
+// also requires approval for with `gate`, i.e.
+//
+//	require_approval {
+//	  input.method = "/Exec.Exec/Run"
+//	}
+//
+// Such decisions are never cached.
+func RequireApprovals(gate ApprovalGate) RPCAuthzHook {
+	return approvalHook{gate: gate}
+}
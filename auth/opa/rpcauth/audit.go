@@ -0,0 +1,112 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package rpcauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// AuditEvent records a single authorization decision.
+type AuditEvent struct {
+	// When the decision was made.
+	Time time.Time `json:"time"`
+
+	// The GRPC method name, as '/Package.Service/Method'
+	Method string `json:"method"`
+
+	// The message type as 'Package.Message'
+	MessageType string `json:"type"`
+
+	// The calling peer (identity and network), if known.
+	Peer *PeerAuthInput `json:"peer"`
+
+	// The host serving (or in the proxy case the target of) the RPC, if known.
+	Host *HostAuthInput `json:"host"`
+
+	// The justification supplied by the client, if any.
+	Justification string `json:"justification,omitempty"`
+
+	// Hex encoded SHA256 of the JSON encoded RPCAuthInput which was evaluated.
+	// This allows correlating with verbose logs without recording request
+	// contents in the audit trail.
+	InputHash string `json:"inputhash"`
+
+	// Whether the request was permitted.
+	Allowed bool `json:"allowed"`
+
+	// The policy query used for the decision.
+	Query string `json:"query"`
+
+	// For denials, the reason returned to the caller.
+	Reason string `json:"reason,omitempty"`
+
+	// The complete input which was evaluated. It's not serialized as it
+	// contains the request message, but sinks may record it.
+	Input *RPCAuthInput `json:"-"`
+
+	// True if the policy denied the request but it was permitted
+	// anyway because the Authorizer is in dry run mode.
+	DryRun bool `json:"dryrun,omitempty"`
+}
+
+// An AuditSink receives an AuditEvent for every authorization decision made
+// by an Authorizer it's attached to (see AuditHook). Audit is called synchronously
+// on the RPC path so implementations which do remote I/O should buffer.
+// Errors are logged but never change the authorization decision.
+type AuditSink interface {
+	Audit(context.Context, *AuditEvent) error
+}
+
+// AuditSinkFunc is a func adapter for AuditSink
+type AuditSinkFunc func(context.Context, *AuditEvent) error
+
+// Audit implements AuditSink.Audit
+func (a AuditSinkFunc) Audit(ctx context.Context, event *AuditEvent) error {
+	return a(ctx, event)
+}
+
+// auditHook is a no-op RPCAuthzHook used to carry an AuditSink through
+// the existing hook plumbing (i.e. server.Serve) to an Authorizer.
+type auditHook struct {
+	sink AuditSink
+}
+
+func (auditHook) Hook(context.Context, *RPCAuthInput) error {
+	return nil
+}
+
+// AuditHook returns an RPCAuthzHook which, when passed to New or NewWithPolicy,
+// sends every authorization decision to `sink`. The hook doesn't modify the
+// input itself and can appear anywhere in the list of hooks.
+func AuditHook(sink AuditSink) RPCAuthzHook {
+	return auditHook{sink: sink}
+}
+
+// HashInput returns the hex encoded SHA256 of the JSON form of input, or
+// an empty string if it can't be marshaled.
+func HashInput(input *RPCAuthInput) string {
+	b, err := json.Marshal(input)
+	if err != nil {
+		return ""
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
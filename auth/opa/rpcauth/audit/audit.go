@@ -0,0 +1,85 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package audit provides rpcauth.AuditSink implementations for recording
+// authorization decisions to a local file, syslog, a remote gRPC collector or
+// an OPA compatible decision log service.
+//
+// Attach a sink to an Authorizer by passing rpcauth.AuditHook(sink) along with
+// any other authz hooks.
+package audit
+
+// To regenerate the proto headers if the proto changes, just run go generate
+// and this encodes the necessary magic:
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=require_unimplemented_servers=false:. --go-grpc_opt=paths=source_relative audit.proto
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+// WriterSink writes each event as a line of JSON to an io.Writer.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink returns a WriterSink writing to `w`.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Audit implements rpcauth.AuditSink.
+func (w *WriterSink) Audit(ctx context.Context, event *rpcauth.AuditEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.w.Write(b)
+	return err
+}
+
+// FileSink is a WriterSink which appends to a local file.
+type FileSink struct {
+	*WriterSink
+	f *os.File
+}
+
+// NewFileSink opens (creating if needed) `filename` for appending and returns
+// a FileSink which writes events to it.
+func NewFileSink(filename string) (*FileSink, error) {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{
+		WriterSink: NewWriterSink(f),
+		f:          f,
+	}, nil
+}
+
+// Close closes the underlying file.
+func (f *FileSink) Close() error {
+	return f.f.Close()
+}
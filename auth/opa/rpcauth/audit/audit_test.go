@@ -0,0 +1,242 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const policy = `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.method = "/Foo/Allowed"
+}
+`
+
+// evalBoth runs one allowed and one denied request through an Authorizer with `sink` attached.
+func evalBoth(t *testing.T, sink rpcauth.AuditSink) {
+	t.Helper()
+	ctx := context.Background()
+	authz, err := rpcauth.NewWithPolicy(ctx, policy, rpcauth.AuditHook(sink))
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+	err = authz.Eval(ctx, &rpcauth.RPCAuthInput{
+		Method: "/Foo/Allowed",
+		Peer: &rpcauth.PeerAuthInput{
+			Principal: &rpcauth.PrincipalAuthInput{ID: "alice"},
+		},
+	})
+	testutil.FatalOnErr("Eval allowed", err, t)
+	err = authz.Eval(ctx, &rpcauth.RPCAuthInput{
+		Method: "/Foo/Denied",
+		Host: &rpcauth.HostAuthInput{
+			Net: &rpcauth.NetAuthInput{Address: "10.0.0.1"},
+		},
+	})
+	testutil.FatalOnNoErr("Eval denied", err, t)
+}
+
+func TestFileSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(filename)
+	testutil.FatalOnErr("NewFileSink", err, t)
+	evalBoth(t, sink)
+	testutil.FatalOnErr("Close", sink.Close(), t)
+
+	f, err := os.Open(filename)
+	testutil.FatalOnErr("Open", err, t)
+	defer f.Close()
+	var events []*rpcauth.AuditEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		e := &rpcauth.AuditEvent{}
+		err := json.Unmarshal(scanner.Bytes(), e)
+		testutil.FatalOnErr("Unmarshal", err, t)
+		events = append(events, e)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if !events[0].Allowed || events[0].Method != "/Foo/Allowed" || events[0].Peer.Principal.ID != "alice" {
+		t.Errorf("unexpected allowed event %+v", events[0])
+	}
+	if events[1].Allowed || events[1].Method != "/Foo/Denied" || events[1].Reason == "" || events[1].Host.Net.Address != "10.0.0.1" {
+		t.Errorf("unexpected denied event %+v", events[1])
+	}
+	for _, e := range events {
+		if e.InputHash == "" || e.Query == "" || e.Time.IsZero() {
+			t.Errorf("event missing fields: %+v", e)
+		}
+	}
+
+	_, err = NewFileSink(filepath.Join(t.TempDir(), "no-such-dir", "audit.log"))
+	testutil.FatalOnNoErr("NewFileSink in missing dir", err, t)
+}
+
+type fakeCollector struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+func (f *fakeCollector) Record(ctx context.Context, req *RecordRequest) (*emptypb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, req.Events...)
+	return &emptypb.Empty{}, nil
+}
+
+func TestCollectorSink(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	collector := &fakeCollector{}
+	RegisterCollectorServer(s, collector)
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			log.Fatalf("Server exited with error: %v", err)
+		}
+	}()
+	defer s.GracefulStop()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("DialContext", err, t)
+	defer conn.Close()
+
+	sink := NewCollectorSink(conn, logr.Discard())
+	evalBoth(t, sink)
+	// Close flushes everything pending.
+	testutil.FatalOnErr("Close", sink.Close(), t)
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if len(collector.events) != 2 {
+		t.Fatalf("collector got %d events, want 2", len(collector.events))
+	}
+	if e := collector.events[0]; !e.Allowed || e.Principal != "alice" || e.Method != "/Foo/Allowed" {
+		t.Errorf("unexpected allowed event %+v", e)
+	}
+	if e := collector.events[1]; e.Allowed || e.HostAddress != "10.0.0.1" || e.Reason == "" {
+		t.Errorf("unexpected denied event %+v", e)
+	}
+	if got := sink.Dropped(); got != 0 {
+		t.Errorf("Dropped() = %d, want 0", got)
+	}
+}
+
+func TestDecisionLogSink(t *testing.T) {
+	var mu sync.Mutex
+	var decisions []map[string]interface{}
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" || r.Method != http.MethodPost {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var batch []map[string]interface{}
+		if err := json.NewDecoder(gz).Decode(&batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		decisions = append(decisions, batch...)
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(tokenFile, []byte("secret\n"), 0600), t)
+	sink := NewDecisionLogSink(srv.URL+"/logs", logr.Discard(), WithHTTPClient(srv.Client()), WithLabels(map[string]string{"id": "host1"}), WithBearerTokenFile(tokenFile))
+	evalBoth(t, sink)
+	testutil.FatalOnErr("Close", sink.Close(), t)
+
+	mu.Lock()
+	got := decisions
+	mu.Unlock()
+	if len(got) != 2 {
+		t.Fatalf("got %d decisions, want 2", len(got))
+	}
+	mu.Lock()
+	gotAuth := auth
+	mu.Unlock()
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want Bearer secret", gotAuth)
+	}
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for i, want := range []struct {
+		method string
+		result bool
+	}{
+		{"/Foo/Allowed", true},
+		{"/Foo/Denied", false},
+	} {
+		d := got[i]
+		if d["path"] != "sansshell/authz/allow" || d["result"] != want.result {
+			t.Errorf("decision %d = %v, want path sansshell/authz/allow result %t", i, d, want.result)
+		}
+		if input, ok := d["input"].(map[string]interface{}); !ok || input["method"] != want.method {
+			t.Errorf("decision %d input = %v, want method %s", i, d["input"], want.method)
+		}
+		if labels, ok := d["labels"].(map[string]interface{}); !ok || labels["id"] != "host1" || labels["version"] != "sansshell" {
+			t.Errorf("decision %d labels = %v", i, d["labels"])
+		}
+		if id, _ := d["decision_id"].(string); !uuid.MatchString(id) {
+			t.Errorf("decision %d id %q isn't a UUID", i, id)
+		}
+	}
+	if got := sink.Dropped(); got != 0 {
+		t.Errorf("Dropped() = %d, want 0", got)
+	}
+
+	// Failed uploads are counted as dropped.
+	sink = NewDecisionLogSink(srv.URL+"/logs", logr.Discard(), WithHTTPClient(srv.Client()), WithBearerTokenFile(tokenFile+".missing"), WithoutInput())
+	evalBoth(t, sink)
+	testutil.FatalOnErr("Close", sink.Close(), t)
+	if got := sink.Dropped(); got != 2 {
+		t.Errorf("Dropped() = %d, want 2", got)
+	}
+}
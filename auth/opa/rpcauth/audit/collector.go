@@ -0,0 +1,167 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+const (
+	// DefaultBatchSize is the maximum number of events sent in one Record call.
+	DefaultBatchSize = 100
+
+	// DefaultFlushInterval is how often buffered events are sent even if
+	// a batch isn't full.
+	DefaultFlushInterval = time.Second
+
+	// Events beyond this many waiting to be sent are dropped.
+	defaultBufferSize = 10000
+)
+
+// ErrBufferFull is returned from CollectorSink.Audit when events can't be
+// queued because the collector isn't keeping up.
+var ErrBufferFull = errors.New("audit buffer full, event dropped")
+
+// CollectorSink sends events in batches to a remote Collector service.
+// Events are buffered so Audit never blocks on the network. If the buffer
+// fills (i.e. the collector is unreachable) new events are dropped and counted.
+type CollectorSink struct {
+	client   CollectorClient
+	logger   logr.Logger
+	events   chan *Event
+	batch    int
+	interval time.Duration
+	dropped  int64
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewCollectorSink returns a CollectorSink which sends events over `conn`.
+// The caller is responsible for closing `conn` after calling Close.
+func NewCollectorSink(conn grpc.ClientConnInterface, logger logr.Logger) *CollectorSink {
+	c := &CollectorSink{
+		client:   NewCollectorClient(conn),
+		logger:   logger,
+		events:   make(chan *Event, defaultBufferSize),
+		batch:    DefaultBatchSize,
+		interval: DefaultFlushInterval,
+		done:     make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// Audit implements rpcauth.AuditSink.
+func (c *CollectorSink) Audit(ctx context.Context, event *rpcauth.AuditEvent) error {
+	select {
+	case c.events <- EventFromAudit(event):
+		return nil
+	default:
+		atomic.AddInt64(&c.dropped, 1)
+		return ErrBufferFull
+	}
+}
+
+// Dropped returns the number of events which couldn't be queued.
+func (c *CollectorSink) Dropped() int64 {
+	return atomic.LoadInt64(&c.dropped)
+}
+
+// Close flushes any buffered events and stops the sink. Audit must not be
+// called after Close.
+func (c *CollectorSink) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.events)
+	})
+	<-c.done
+	return nil
+}
+
+func (c *CollectorSink) run() {
+	defer close(c.done)
+	t := time.NewTicker(c.interval)
+	defer t.Stop()
+	var pending []*Event
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := c.client.Record(ctx, &RecordRequest{Events: pending}); err != nil {
+			c.logger.Error(err, "audit collector Record", "events", len(pending))
+			atomic.AddInt64(&c.dropped, int64(len(pending)))
+		}
+		pending = nil
+	}
+	for {
+		select {
+		case e, ok := <-c.events:
+			if !ok {
+				flush()
+				return
+			}
+			pending = append(pending, e)
+			if len(pending) >= c.batch {
+				flush()
+			}
+		case <-t.C:
+			flush()
+		}
+	}
+}
+
+// EventFromAudit converts an rpcauth.AuditEvent into its wire form.
+func EventFromAudit(a *rpcauth.AuditEvent) *Event {
+	e := &Event{
+		Time:      timestamppb.New(a.Time),
+		Method:    a.Method,
+		Type:      a.MessageType,
+		InputHash: a.InputHash,
+		Allowed:   a.Allowed,
+		Query:     a.Query,
+		Reason:    a.Reason,
+	}
+	if p := a.Peer; p != nil {
+		e.Principal = rpcauth.PeerID(p)
+		if p.Principal != nil {
+			e.PrincipalGroups = p.Principal.Groups
+		}
+		if p.Net != nil {
+			e.PeerAddress = p.Net.Address
+		}
+		if p.Cert != nil {
+			e.PeerSpiffeId = p.Cert.SPIFFEID
+			e.PeerSubject = p.Cert.Subject.String()
+		}
+	}
+	if a.Host != nil && a.Host.Net != nil {
+		e.HostAddress = a.Host.Net.Address
+	}
+	return e
+}
@@ -0,0 +1,271 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+// DecisionLog is a decision in the format of OPA's decision log API, so
+// decisions can be sent to existing OPA decision log services.
+type DecisionLog struct {
+	Labels      map[string]string `json:"labels"`
+	DecisionID  string            `json:"decision_id"`
+	Path        string            `json:"path"`
+	Input       json.RawMessage   `json:"input,omitempty"`
+	Result      bool              `json:"result"`
+	RequestedBy string            `json:"requested_by"`
+	Timestamp   time.Time         `json:"timestamp"`
+}
+
+// DecisionLogSink sends events in gzipped JSON batches to an OPA compatible
+// decision log service (i.e. https://example.com/logs). As with CollectorSink
+// events are buffered so Audit never blocks on the network, and are dropped
+// (and counted) if the buffer fills or an upload fails.
+type DecisionLogSink struct {
+	url       string
+	client    *http.Client
+	labels    map[string]string
+	tokenFile string
+	omitInput bool
+	logger    logr.Logger
+	events    chan *DecisionLog
+	batch     int
+	interval  time.Duration
+	dropped   int64
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// A DecisionLogOption configures a DecisionLogSink.
+type DecisionLogOption interface {
+	apply(*DecisionLogSink)
+}
+
+type decisionLogOptionFunc func(*DecisionLogSink)
+
+func (o decisionLogOptionFunc) apply(d *DecisionLogSink) {
+	o(d)
+}
+
+// WithHTTPClient returns an option to use `client` for uploads rather than
+// http.DefaultClient. This is generally used to configure TLS.
+func WithHTTPClient(client *http.Client) DecisionLogOption {
+	return decisionLogOptionFunc(func(d *DecisionLogSink) {
+		d.client = client
+	})
+}
+
+// WithLabels returns an option adding `labels` to every decision. OPA
+// sets "id" (the instance) and "version"; by default "version" is
+// "sansshell".
+func WithLabels(labels map[string]string) DecisionLogOption {
+	return decisionLogOptionFunc(func(d *DecisionLogSink) {
+		for k, v := range labels {
+			d.labels[k] = v
+		}
+	})
+}
+
+// WithBearerTokenFile returns an option to authenticate uploads with the
+// bearer token in `filename`, which is re-read for every upload.
+func WithBearerTokenFile(filename string) DecisionLogOption {
+	return decisionLogOptionFunc(func(d *DecisionLogSink) {
+		d.tokenFile = filename
+	})
+}
+
+// WithoutInput returns an option to omit the policy input (which includes
+// the request message) from decisions.
+func WithoutInput() DecisionLogOption {
+	return decisionLogOptionFunc(func(d *DecisionLogSink) {
+		d.omitInput = true
+	})
+}
+
+// NewDecisionLogSink returns a DecisionLogSink uploading to `url`. Call
+// Close to flush buffered events.
+func NewDecisionLogSink(url string, logger logr.Logger, opts ...DecisionLogOption) *DecisionLogSink {
+	d := &DecisionLogSink{
+		url:      url,
+		client:   http.DefaultClient,
+		labels:   map[string]string{"version": "sansshell"},
+		logger:   logger,
+		events:   make(chan *DecisionLog, defaultBufferSize),
+		batch:    DefaultBatchSize,
+		interval: DefaultFlushInterval,
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt.apply(d)
+	}
+	go d.run()
+	return d
+}
+
+// Audit implements rpcauth.AuditSink.
+func (d *DecisionLogSink) Audit(ctx context.Context, event *rpcauth.AuditEvent) error {
+	dl, err := d.decisionLog(event)
+	if err != nil {
+		return err
+	}
+	select {
+	case d.events <- dl:
+		return nil
+	default:
+		atomic.AddInt64(&d.dropped, 1)
+		return ErrBufferFull
+	}
+}
+
+// decisionLog converts an event into a DecisionLog. The input is marshaled
+// immediately as the caller may change it after Audit returns.
+func (d *DecisionLogSink) decisionLog(a *rpcauth.AuditEvent) (*DecisionLog, error) {
+	id, err := decisionID()
+	if err != nil {
+		return nil, err
+	}
+	dl := &DecisionLog{
+		Labels:     d.labels,
+		DecisionID: id,
+		Path:       strings.ReplaceAll(strings.TrimPrefix(a.Query, "data."), ".", "/"),
+		// In dry run mode denied requests are allowed, but the policy result was false.
+		Result:    a.Allowed && !a.DryRun,
+		Timestamp: a.Time,
+	}
+	if a.Peer != nil && a.Peer.Net != nil {
+		dl.RequestedBy = a.Peer.Net.Address
+	}
+	if a.Input != nil && !d.omitInput {
+		b, err := json.Marshal(a.Input)
+		if err != nil {
+			return nil, err
+		}
+		dl.Input = b
+	}
+	return dl, nil
+}
+
+// decisionID returns a random (version 4) UUID.
+func decisionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// Dropped returns the number of events which couldn't be queued or uploaded.
+func (d *DecisionLogSink) Dropped() int64 {
+	return atomic.LoadInt64(&d.dropped)
+}
+
+// Close flushes any buffered events and stops the sink. Audit must not be
+// called after Close.
+func (d *DecisionLogSink) Close() error {
+	d.closeOnce.Do(func() {
+		close(d.events)
+	})
+	<-d.done
+	return nil
+}
+
+// upload sends a batch of decisions.
+func (d *DecisionLogSink) upload(ctx context.Context, batch []*DecisionLog) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(batch); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	if d.tokenFile != "" {
+		token, err := os.ReadFile(d.tokenFile)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("decision log upload to %s returned %s", d.url, resp.Status)
+	}
+	return nil
+}
+
+func (d *DecisionLogSink) run() {
+	defer close(d.done)
+	t := time.NewTicker(d.interval)
+	defer t.Stop()
+	var pending []*DecisionLog
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := d.upload(ctx, pending); err != nil {
+			d.logger.Error(err, "decision log upload", "events", len(pending))
+			atomic.AddInt64(&d.dropped, int64(len(pending)))
+		}
+		pending = nil
+	}
+	for {
+		select {
+		case e, ok := <-d.events:
+			if !ok {
+				flush()
+				return
+			}
+			pending = append(pending, e)
+			if len(pending) >= d.batch {
+				flush()
+			}
+		case <-t.C:
+			flush()
+		}
+	}
+}
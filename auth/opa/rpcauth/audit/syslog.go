@@ -0,0 +1,61 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log/syslog"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+// SyslogSink sends each event as JSON to the local syslog daemon.
+// Denials are logged at warning level, everything else at info.
+type SyslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogSink returns a SyslogSink using the given facility
+// (i.e. syslog.LOG_AUTHPRIV) and tag.
+func NewSyslogSink(facility syslog.Priority, tag string) (*SyslogSink, error) {
+	w, err := syslog.New(facility|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{w: w}, nil
+}
+
+// Audit implements rpcauth.AuditSink.
+func (s *SyslogSink) Audit(ctx context.Context, event *rpcauth.AuditEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if event.Allowed {
+		return s.w.Info(string(b))
+	}
+	return s.w.Warning(string(b))
+}
+
+// Close closes the connection to syslog.
+func (s *SyslogSink) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,155 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package rpcauth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultCacheSize is the number of decisions a DecisionCache holds if
+// no size is given.
+const DefaultCacheSize = 10000
+
+// decisionCache remembers allow decisions for a limited time. Only allows
+// are cached so a denial is always re-evaluated (and explained).
+type decisionCache struct {
+	ttl time.Duration
+	max int
+
+	// Recorders of hits, misses and evictions.
+	metrics []CacheMetrics
+
+	mu         sync.Mutex
+	generation uint64
+	entries    map[string]time.Time
+}
+
+func newDecisionCache(ttl time.Duration, max int) *decisionCache {
+	if max <= 0 {
+		max = DefaultCacheSize
+	}
+	return &decisionCache{
+		ttl:     ttl,
+		max:     max,
+		entries: make(map[string]time.Time),
+	}
+}
+
+// allowed returns true if `key` was allowed by policy `generation` within the TTL.
+// A different generation than last seen flushes the cache.
+func (c *decisionCache) allowed(key string, generation uint64, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkGeneration(generation)
+	exp, ok := c.entries[key]
+	if ok && now.Before(exp) {
+		c.observe(CacheHit, 1)
+		return true
+	}
+	if ok {
+		delete(c.entries, key)
+		c.observe(CacheEviction, 1)
+	}
+	c.observe(CacheMiss, 1)
+	return false
+}
+
+// add records `key` as allowed by policy `generation`.
+func (c *decisionCache) add(key string, generation uint64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkGeneration(generation)
+	if c.generation != generation {
+		// Decided by a policy which has since been replaced.
+		return
+	}
+	if len(c.entries) >= c.max {
+		n := len(c.entries)
+		for k, exp := range c.entries {
+			if !now.Before(exp) {
+				delete(c.entries, k)
+			}
+		}
+		c.observe(CacheEviction, n-len(c.entries))
+	}
+	if len(c.entries) >= c.max {
+		// Still full of live entries so drop an arbitrary one.
+		for k := range c.entries {
+			delete(c.entries, k)
+			c.observe(CacheEviction, 1)
+			break
+		}
+	}
+	c.entries[key] = now.Add(c.ttl)
+}
+
+// checkGeneration flushes the cache if generation is newer than the one
+// entries were recorded under. Must be called with c.mu held.
+func (c *decisionCache) checkGeneration(generation uint64) {
+	if generation > c.generation {
+		c.flush()
+		c.generation = generation
+	}
+}
+
+// flush drops all entries. Must be called with c.mu held.
+func (c *decisionCache) flush() {
+	c.observe(CacheEviction, len(c.entries))
+	c.entries = make(map[string]time.Time)
+}
+
+// observe reports `count` cache events to c.metrics. Must be called with
+// c.mu held.
+func (c *decisionCache) observe(event string, count int) {
+	if count == 0 {
+		return
+	}
+	for _, m := range c.metrics {
+		m.ObserveCache(event, count)
+	}
+}
+
+// cacheHook is a no-op RPCAuthzHook used to carry cache settings through
+// the existing hook plumbing (i.e. server.Serve) to an Authorizer.
+type cacheHook struct {
+	ttl time.Duration
+	max int
+}
+
+func (cacheHook) Hook(context.Context, *RPCAuthInput) error {
+	return nil
+}
+
+// DecisionCache returns an RPCAuthzHook which, when passed to New or NewWithPolicy,
+// enables caching of allow decisions for `ttl`. Up to `maxEntries` decisions are
+// kept (DefaultCacheSize if <= 0).
+//
+// Decisions are keyed on the complete policy input after all other hooks have run
+// (identity, method, message, metadata, etc) so the cache only skips Rego evaluation
+// for repeats of an identical request, such as the same message sent many times on
+// a high rate stream. Hooks are always run and audit sinks still see every decision.
+// The cache is flushed whenever the policy is replaced (see opa.AuthzPolicy.Update).
+// Hits, misses and evictions are reported to any Metrics passed with MetricsHook
+// which also implement CacheMetrics.
+//
+// Caching means that policies depending on anything outside of the input (i.e. time)
+// may be applied up to `ttl` late.
+func DecisionCache(ttl time.Duration, maxEntries int) RPCAuthzHook {
+	return cacheHook{ttl: ttl, max: maxEntries}
+}
@@ -0,0 +1,48 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package rpcauth
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// dryRunHook is a no-op RPCAuthzHook used to carry the dry run setting
+// through the existing hook plumbing (i.e. server.Serve) to an Authorizer.
+type dryRunHook struct{}
+
+func (dryRunHook) Hook(context.Context, *RPCAuthInput) error {
+	return nil
+}
+
+// DryRun returns an RPCAuthzHook which, when passed to New or NewWithPolicy,
+// puts the Authorizer in audit-only mode: requests the policy denies are
+// logged, sent to audit sinks (with AuditEvent.DryRun set) and counted in
+// DryRunDenials but still permitted. This allows soaking a new, more
+// restrictive policy in production before enforcing it.
+//
+// Only policy denials are affected. Errors from hooks (i.e. a missing
+// justification) and policy evaluation errors are still returned.
+func DryRun() RPCAuthzHook {
+	return dryRunHook{}
+}
+
+// DryRunDenials returns the number of requests denied by policy which
+// were permitted because of dry run mode.
+func (g *Authorizer) DryRunDenials() uint64 {
+	return atomic.LoadUint64(&g.dryRunDenials)
+}
@@ -19,6 +19,7 @@ package rpcauth
 import (
 	"context"
 	"net"
+	"regexp"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -67,10 +68,50 @@ func HostNetHook(addr net.Addr) RPCAuthzHook {
 	})
 }
 
+// ProxyTargetHook returns an RPCAuthzHook that sets input.Host.Target from
+// the ProxyTargetKey metadata sent by a proxy. The key is removed from
+// input.Metadata.
+func ProxyTargetHook() RPCAuthzHook {
+	return RPCAuthzHookFunc(func(ctx context.Context, input *RPCAuthInput) error {
+		vals := input.Metadata.Get(ProxyTargetKey)
+		input.Metadata.Delete(ProxyTargetKey)
+		switch len(vals) {
+		case 0:
+			return nil
+		case 1:
+		default:
+			return status.Error(codes.InvalidArgument, "multiple proxy targets")
+		}
+		if input.Host == nil {
+			input.Host = &HostAuthInput{}
+		}
+		input.Host.Target = vals[0]
+		return nil
+	})
+}
+
+// CheckProxyTarget returns an error if `input` has a target (see
+// ProxyTargetHook) other than `target`, which a proxy is known to have
+// forwarded the request to. Otherwise the input target is set to it.
+func CheckProxyTarget(input *RPCAuthInput, target string) error {
+	if input.Host == nil {
+		input.Host = &HostAuthInput{}
+	}
+	if input.Host.Target != "" && input.Host.Target != target {
+		return status.Errorf(codes.Unauthenticated, "request target %s doesn't match proxy target %s", input.Host.Target, target)
+	}
+	input.Host.Target = target
+	return nil
+}
+
 const (
 	// ReqJustKey is the key name that must exist in the incoming
 	// context metadata if client side provided justification is required.
 	ReqJustKey = "sansshell-justification"
+
+	// ProxyTargetKey is the metadata key a proxy uses to pass the target
+	// a client asked for to the server it forwards requests to.
+	ProxyTargetKey = "sansshell-proxy-target"
 )
 
 var (
@@ -101,3 +142,16 @@ func JustificationHook(justificationFunc func(string) error) RPCAuthzHook {
 		return nil
 	})
 }
+
+// JustificationFormatHook returns an RPCAuthzHook which rejects requests
+// whose justification (if present) doesn't match `format`, i.e. a ticket ID
+// pattern. Unlike JustificationHook it doesn't require a justification, so
+// policies can decide which methods need one by checking input.justification.
+func JustificationFormatHook(format *regexp.Regexp) RPCAuthzHook {
+	return RPCAuthzHookFunc(func(ctx context.Context, input *RPCAuthInput) error {
+		if input.Justification != "" && !format.MatchString(input.Justification) {
+			return status.Errorf(codes.FailedPrecondition, "justification %q doesn't match required format %s", input.Justification, format)
+		}
+		return nil
+	})
+}
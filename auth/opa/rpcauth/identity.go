@@ -0,0 +1,149 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package rpcauth
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Types of PeerIdentity established by the builtin authenticators.
+const (
+	// IdentityTypeSPIFFE identities are the SPIFFE ID of a client certificate.
+	IdentityTypeSPIFFE = "spiffe"
+	// IdentityTypeMTLS identities are the subject common name of a client
+	// certificate without a SPIFFE ID.
+	IdentityTypeMTLS = "mtls"
+)
+
+// A PeerIdentity is an authenticated identity of the calling peer,
+// established from one of its credentials by an Authenticator. Policies,
+// audit sinks and anything else keyed by caller should use identities rather
+// than the credential specific fields of PeerAuthInput.
+type PeerIdentity struct {
+	// The identifier, i.e. a SPIFFE ID, certificate common name or
+	// token subject.
+	ID string `json:"id"`
+
+	// The kind of credential which established the identity, i.e.
+	// IdentityTypeSPIFFE or "oidc".
+	Type string `json:"type"`
+
+	// Who vouched for the identity (the certificate or token issuer), if known.
+	Issuer string `json:"issuer"`
+
+	// Any other authenticator specific attributes.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// An Authenticator establishes an identity for the peer of an RPC from one
+// kind of credential.
+type Authenticator interface {
+	// Authenticate returns the identity established by the peer's credential
+	// in `input`, or nil if it didn't present one of this kind. An error is
+	// returned if it presented an invalid one.
+	Authenticate(ctx context.Context, input *RPCAuthInput) (*PeerIdentity, error)
+}
+
+// AuthenticatorFunc is a func adapter for Authenticator
+type AuthenticatorFunc func(context.Context, *RPCAuthInput) (*PeerIdentity, error)
+
+// Authenticate implements Authenticator.Authenticate
+func (a AuthenticatorFunc) Authenticate(ctx context.Context, input *RPCAuthInput) (*PeerIdentity, error) {
+	return a(ctx, input)
+}
+
+// CertAuthenticator returns an Authenticator establishing identities from the
+// peer's certificate: its SPIFFE ID if it has one, otherwise its subject
+// common name. PeerInputFromContext always applies it, so it's only needed
+// to rebuild the identities of an input constructed by hand.
+func CertAuthenticator() Authenticator {
+	return AuthenticatorFunc(func(ctx context.Context, input *RPCAuthInput) (*PeerIdentity, error) {
+		if input.Peer == nil {
+			return nil, nil
+		}
+		return certIdentity(input.Peer.Cert), nil
+	})
+}
+
+// certIdentity returns the identity established by cert, if any.
+func certIdentity(cert *CertAuthInput) *PeerIdentity {
+	switch {
+	case cert == nil:
+		return nil
+	case cert.SPIFFEID != "":
+		return &PeerIdentity{
+			ID:     cert.SPIFFEID,
+			Type:   IdentityTypeSPIFFE,
+			Issuer: cert.Issuer.String(),
+		}
+	case cert.Subject.CommonName != "":
+		return &PeerIdentity{
+			ID:     cert.Subject.CommonName,
+			Type:   IdentityTypeMTLS,
+			Issuer: cert.Issuer.String(),
+		}
+	}
+	return nil
+}
+
+// AddIdentity makes `id` the primary identity of the peer in `input`,
+// ahead of any established earlier (i.e. from its certificate). An existing
+// identity with the same type and ID is replaced.
+func AddIdentity(input *RPCAuthInput, id *PeerIdentity) {
+	if input.Peer == nil {
+		input.Peer = &PeerAuthInput{}
+	}
+	ids := []*PeerIdentity{id}
+	for _, e := range input.Peer.Identities {
+		if e.Type != id.Type || e.ID != id.ID {
+			ids = append(ids, e)
+		}
+	}
+	input.Peer.Identities = ids
+	input.Peer.Identity = id
+}
+
+// IdentityHook returns an RPCAuthzHook which runs `authenticators` and adds
+// the identities they establish to the peer with AddIdentity. The identity
+// from the first authenticator to establish one becomes the primary
+// input.peer.identity, so list them in order of preference. Requests with an
+// invalid credential are rejected with Unauthenticated.
+func IdentityHook(authenticators ...Authenticator) RPCAuthzHook {
+	return RPCAuthzHookFunc(func(ctx context.Context, input *RPCAuthInput) error {
+		var ids []*PeerIdentity
+		for _, a := range authenticators {
+			id, err := a.Authenticate(ctx, input)
+			if err != nil {
+				if _, ok := status.FromError(err); ok {
+					return err
+				}
+				return status.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+			}
+			if id != nil {
+				ids = append(ids, id)
+			}
+		}
+		// Added in reverse so the first becomes primary.
+		for i := len(ids) - 1; i >= 0; i-- {
+			AddIdentity(input, ids[i])
+		}
+		return nil
+	})
+}
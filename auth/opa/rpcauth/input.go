@@ -18,9 +18,12 @@ package rpcauth
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/json"
 	"net"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -29,6 +32,8 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+
+	"github.com/Snowflake-Labs/sansshell/auth/redact"
 )
 
 // RPCAuthInput is used as policy input to validate Sansshell RPCs
@@ -36,24 +41,151 @@ type RPCAuthInput struct {
 	// The GRPC method name, as '/Package.Service/Method'
 	Method string `json:"method"`
 
-	// The request protocol buffer, serialized as JSON
+	// The request protocol buffer, serialized as JSON using protojson
+	// with the original proto field names (i.e. input.message.file.filename).
+	// As with protojson generally, fields with default values are omitted,
+	// 64 bit integers are encoded as strings and enums by their value names.
+	// Fields marked sensitive are redacted or hashed (see the redact package).
 	Message json.RawMessage `json:"message"`
 
+	// For messages with sensitive fields, the hex encoded SHA256 sum of the
+	// request before redaction, so it can be identified exactly (i.e. by
+	// approvals) without exposing them. It's not part of the policy input.
+	MessageDigest string `json:"-"`
+
 	// The message type as 'Package.Message'
 	MessageType string `json:"type"`
 
 	// Raw grpc metdata associated with this call.
 	Metadata metadata.MD `json:"metadata"`
 
+	// The justification (i.e. a ticket ID) the client supplied in the
+	// ReqJustKey metadata, if any. Policies can require it for some methods:
+	//
+	//	allow {
+	//	  input.method = "/Exec.Exec/Run"
+	//	  startswith(input.justification, "INC-")
+	//	}
+	Justification string `json:"justification"`
+
 	// Information about the calling peer, if available
 	Peer *PeerAuthInput `json:"peer"`
 
 	// Information about the host serving the RPC.
 	Host *HostAuthInput `json:"host"`
+
+	// The time the policy is evaluated, in RFC 3339 format when serialized.
+	// It's set by the Authorizer just before evaluation (after any decision
+	// cache lookup) and overrides any value set earlier.
+	Time time.Time `json:"time"`
+
+	// Unexpired time limited grants for the caller, as expiry times keyed
+	// by method (see the grants package).
+	Grants map[string]time.Time `json:"grants"`
+
+	// The decision of a proxy which authorized this request before
+	// forwarding it, if any and verified.
+	ProxyDecision *ProxyDecisionInput `json:"proxy_decision"`
+
+	// A verified delegation token minted by a proxy forwarding this
+	// request on behalf of a caller, if any.
+	Delegation *DelegationInput `json:"delegation"`
+
+	// Normalized fields decomposed from the request message of services
+	// which run commands or change the system (see the inspect package),
+	// so policies needn't parse input.message:
+	//
+	//	allow {
+	//	  input.request.unit = "nginx.service"
+	//	  input.request.action = "restart"
+	//	}
+	Request *RequestInput `json:"request"`
+
+	// The secondary check a service is making while handling the request,
+	// if any (see AuthorizeAction).
+	Action *ActionInput `json:"action"`
+}
+
+// RequestInput contains normalized fields from a request message. Fields
+// which don't apply to a request are empty.
+type RequestInput struct {
+	// What the request does, i.e. "run", "install" or "restart".
+	Action string `json:"action"`
+
+	// The absolute, clean path of a binary to run.
+	Binary string `json:"binary"`
+
+	// The arguments passed to Binary, not including the binary itself.
+	Args []string `json:"args"`
+
+	// The name of a package to install or update.
+	Package string `json:"package"`
+
+	// The package version to install, or update to.
+	Version string `json:"version"`
+
+	// The package version which must be installed for an update.
+	OldVersion string `json:"old_version"`
+
+	// A repo enabled to resolve a package.
+	Repo string `json:"repo"`
+
+	// A systemd unit, always including its type suffix (i.e. nginx.service).
+	Unit string `json:"unit"`
+}
+
+// ProxyDecisionInput contains a verified summary of the authorization
+// decision made by a proxy for a request it forwarded.
+type ProxyDecisionInput struct {
+	// The proxy which made the decision, as named by its signing key.
+	Issuer string `json:"issuer"`
+
+	// The target the proxy forwarded the request to.
+	Target string `json:"target"`
+
+	// The version of the policy the proxy evaluated (see opa.AuthzPolicy.Version).
+	PolicyVersion string `json:"policy_version"`
+
+	// The query the proxy evaluated to allow requests.
+	Query string `json:"query"`
+
+	// A hash of the proxy's input for the caller and method (excluding
+	// request messages).
+	InputHash string `json:"input_hash"`
+
+	// When the proxy made the decision, in RFC 3339 format when serialized.
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// DelegationInput contains the verified contents of a delegation token,
+// with which a proxy vouches that it forwarded a request for a caller.
+type DelegationInput struct {
+	// The proxy which minted the token, as named by its signing key.
+	Issuer string `json:"issuer"`
+
+	// The caller the proxy is acting for (see PrincipalID).
+	Subject string `json:"subject"`
+
+	// The target the token was minted for.
+	Target string `json:"target"`
+
+	// The methods the token may be used for.
+	Methods []string `json:"methods"`
+
+	// When the token expires, in RFC 3339 format when serialized.
+	Expires time.Time `json:"expires"`
 }
 
 // PeerAuthInput contains policy-relevant information about an RPC peer.
 type PeerAuthInput struct {
+	// The primary authenticated identity of the peer, if any. This is
+	// the first of Identities.
+	Identity *PeerIdentity `json:"identity"`
+
+	// All identities established for the peer by authenticators (see
+	// IdentityHook), in order of preference.
+	Identities []*PeerIdentity `json:"identities"`
+
 	// Network information about the peer
 	Net *NetAuthInput `json:"net"`
 
@@ -62,6 +194,22 @@ type PeerAuthInput struct {
 
 	// Information about the principal associated with the peer, if any
 	Principal *PrincipalAuthInput `json:"principal"`
+
+	// Information from a verified bearer token presented by the peer, if any
+	Token *TokenAuthInput `json:"token"`
+}
+
+// TokenAuthInput contains policy-relevant information from a verified
+// bearer token (such as an OIDC ID token).
+type TokenAuthInput struct {
+	// The token issuer ('iss' claim)
+	Issuer string `json:"issuer"`
+
+	// The token subject ('sub' claim)
+	Subject string `json:"subject"`
+
+	// All claims in the token
+	Claims map[string]interface{} `json:"claims"`
 }
 
 // NetAuthInput contains policy-relevant information related to a network endpoint
@@ -87,6 +235,14 @@ type HostAuthInput struct {
 
 	// Information about the principal associated with the host, if any
 	Principal *PrincipalAuthInput `json:"principal"`
+
+	// The target the client asked a proxy to send the request to (i.e.
+	// "canary1.example.com:50042"), if it was forwarded by one. On a server
+	// this is asserted by the peer (see ProxyTargetHook), so policies should
+	// only trust it if the peer is a trusted proxy. Hooks verifying a proxy
+	// decision hint or delegation token reject requests where it doesn't match
+	// their target.
+	Target string `json:"target"`
 }
 
 // CertAuthInput contains policy-relevant information derived from a certificate
@@ -100,6 +256,24 @@ type CertAuthInput struct {
 	// DNS names, from SubjectAlternativeName
 	DNSNames []string `json:"dnsnames"`
 
+	// URIs, from SubjectAlternativeName
+	URIs []string `json:"uris"`
+
+	// IP addresses, from SubjectAlternativeName
+	IPAddresses []string `json:"ipaddresses"`
+
+	// Email addresses, from SubjectAlternativeName
+	EmailAddresses []string `json:"emailaddresses"`
+
+	// The certificate serial number in decimal. Not to be confused
+	// with the (rarely set) serial number attribute in the subject.
+	SerialNumber string `json:"serialnumber"`
+
+	// The validity window of the certificate, in RFC 3339 format
+	// when serialized.
+	NotBefore time.Time `json:"notbefore"`
+	NotAfter  time.Time `json:"notafter"`
+
 	// The raw SPIFFE identifier, if present
 	SPIFFEID string `json:"spiffeid"`
 }
@@ -123,20 +297,76 @@ func NewRPCAuthInput(ctx context.Context, method string, req proto.Message) (*RP
 
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
 		out.Metadata = md
+		if j := md.Get(ReqJustKey); len(j) > 0 {
+			out.Justification = j[0]
+		}
 	}
 
 	if req != nil {
 		out.MessageType = string(proto.MessageName(req))
-		marshaled, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(req)
+		marshaled, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(redact.Message(req))
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "error marshalling request for auth: %v", err)
 		}
 		out.Message = json.RawMessage(marshaled)
+		if redact.HasSensitive(req.ProtoReflect().Descriptor()) {
+			b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "error marshalling request for auth: %v", err)
+			}
+			sum := sha256.Sum256(b)
+			out.MessageDigest = hex.EncodeToString(sum[:])
+		}
 	}
 	out.Peer = PeerInputFromContext(ctx)
 	return out, nil
 }
 
+// PrincipalID returns the identity of the caller in `input`. See PeerID.
+func PrincipalID(input *RPCAuthInput) string {
+	if input == nil {
+		return ""
+	}
+	return PeerID(input.Peer)
+}
+
+// PeerID returns the identity of `peer`: the principal ID if one has been set
+// (i.e. from a bearer token or a hook), otherwise the ID of its primary
+// identity. It returns an empty string if there's no identity.
+func PeerID(peer *PeerAuthInput) string {
+	if peer == nil {
+		return ""
+	}
+	if peer.Principal != nil && peer.Principal.ID != "" {
+		return peer.Principal.ID
+	}
+	if peer.Identity != nil {
+		return peer.Identity.ID
+	}
+	// Peers constructed without PeerInputFromContext may only have a
+	// certificate.
+	if id := certIdentity(peer.Cert); id != nil {
+		return id.ID
+	}
+	return ""
+}
+
+type inputKey struct{}
+
+// contextWithInput returns a copy of ctx carrying the input a request was
+// authorized with.
+func contextWithInput(ctx context.Context, input *RPCAuthInput) context.Context {
+	return context.WithValue(ctx, inputKey{}, input)
+}
+
+// InputFromContext returns the input (after any hooks have run) an Authorizer
+// allowed a unary request with, so handlers can see i.e. the principal a
+// hook determined. It returns nil if there's none.
+func InputFromContext(ctx context.Context) *RPCAuthInput {
+	input, _ := ctx.Value(inputKey{}).(*RPCAuthInput)
+	return input
+}
+
 // PeerInputFromContext populates peer information from the supplied
 // context, if available.
 func PeerInputFromContext(ctx context.Context) *PeerAuthInput {
@@ -147,6 +377,10 @@ func PeerInputFromContext(ctx context.Context) *PeerAuthInput {
 	}
 	out.Net = NetInputFromAddr(p.Addr)
 	out.Cert = CertInputFrom(p.AuthInfo)
+	if id := certIdentity(out.Cert); id != nil {
+		out.Identity = id
+		out.Identities = []*PeerIdentity{id}
+	}
 	return out
 }
 
@@ -187,6 +421,18 @@ func CertInputFrom(authInfo credentials.AuthInfo) *CertAuthInput {
 		out.Subject = cert.Subject
 		out.Issuer = cert.Issuer
 		out.DNSNames = cert.DNSNames
+		for _, u := range cert.URIs {
+			out.URIs = append(out.URIs, u.String())
+		}
+		for _, ip := range cert.IPAddresses {
+			out.IPAddresses = append(out.IPAddresses, ip.String())
+		}
+		out.EmailAddresses = cert.EmailAddresses
+		if cert.SerialNumber != nil {
+			out.SerialNumber = cert.SerialNumber.String()
+		}
+		out.NotBefore = cert.NotBefore
+		out.NotAfter = cert.NotAfter
 	}
 	return out
 }
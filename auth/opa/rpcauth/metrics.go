@@ -0,0 +1,106 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package rpcauth
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Decisions reported to Metrics.
+const (
+	// DecisionAllow is reported for permitted requests.
+	DecisionAllow = "allow"
+	// DecisionDeny is reported for requests rejected by the policy or a
+	// hook. Policy denials are still reported as DecisionDeny in dry run mode.
+	DecisionDeny = "deny"
+	// DecisionError is reported when a decision couldn't be made, i.e.
+	// because policy evaluation or a hook failed.
+	DecisionError = "error"
+)
+
+// Cache events reported to CacheMetrics.
+const (
+	// CacheHit is reported when a decision is answered from the cache.
+	CacheHit = "hit"
+	// CacheMiss is reported when the policy has to be evaluated.
+	CacheMiss = "miss"
+	// CacheEviction is reported for entries dropped from the cache because
+	// they expired, it was full or the policy changed.
+	CacheEviction = "eviction"
+)
+
+// Metrics receives a measurement for every authorization decision made by
+// an Authorizer it's attached to (see MetricsHook). As with AuditSink it's
+// called synchronously on the RPC path so implementations must be cheap.
+type Metrics interface {
+	// ObserveDecision records the decision (one of the Decision constants)
+	// for method made with the given policy version, and the time taken
+	// to make it including running hooks.
+	ObserveDecision(method string, decision string, policyVersion string, latency time.Duration)
+}
+
+// CacheMetrics may be implemented by Metrics to also count the events of
+// the Authorizer's decision cache (see DecisionCache).
+type CacheMetrics interface {
+	// ObserveCache records `count` occurrences of event, one of the
+	// Cache constants.
+	ObserveCache(event string, count int)
+}
+
+// MetricsFunc is a func adapter for Metrics
+type MetricsFunc func(string, string, string, time.Duration)
+
+// ObserveDecision implements Metrics.ObserveDecision
+func (m MetricsFunc) ObserveDecision(method string, decision string, policyVersion string, latency time.Duration) {
+	m(method, decision, policyVersion, latency)
+}
+
+// metricsHook is a no-op RPCAuthzHook used to carry Metrics through
+// the existing hook plumbing (i.e. server.Serve) to an Authorizer.
+type metricsHook struct {
+	m Metrics
+}
+
+func (metricsHook) Hook(context.Context, *RPCAuthInput) error {
+	return nil
+}
+
+// MetricsHook returns an RPCAuthzHook which, when passed to New or NewWithPolicy,
+// reports every authorization decision to `m`. The hook doesn't modify the
+// input itself and can appear anywhere in the list of hooks.
+func MetricsHook(m Metrics) RPCAuthzHook {
+	return metricsHook{m: m}
+}
+
+// decision returns the Decision constant describing the result of eval.
+func decision(denied bool, err error) string {
+	switch {
+	case denied:
+		return DecisionDeny
+	case err == nil:
+		return DecisionAllow
+	}
+	switch status.Code(err) {
+	case codes.Internal, codes.Unavailable, codes.Unknown:
+		return DecisionError
+	}
+	return DecisionDeny
+}
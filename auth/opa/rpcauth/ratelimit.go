@@ -0,0 +1,140 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package rpcauth
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa"
+)
+
+// maxLimiters is the number of callers tracked before idle ones are dropped.
+const maxLimiters = 10000
+
+// rateLimiter enforces policy rate limits with a token bucket for each
+// caller and key.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	rps    float64
+	burst  float64
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{buckets: make(map[string]*bucket)}
+}
+
+// refill adds the tokens accumulated since the bucket was last used.
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rps)
+	}
+	b.last = now
+}
+
+// take uses a token from the bucket for `key` with `limit`, returning zero
+// if one was available or otherwise how long until one will be.
+func (r *rateLimiter) take(key string, limit *opa.RateLimit, now time.Time) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.buckets[key]
+	if !ok {
+		if len(r.buckets) >= maxLimiters {
+			r.sweep(now)
+		}
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		r.buckets[key] = b
+	}
+	// The policy may have changed the limit since the bucket was created.
+	b.rps, b.burst = limit.RPS, float64(limit.Burst)
+	b.refill(now)
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / b.rps * float64(time.Second))
+	}
+	b.tokens--
+	return 0
+}
+
+// sweep drops buckets which have refilled, as a new bucket is equivalent.
+// Must be called with r.mu held.
+func (r *rateLimiter) sweep(now time.Time) {
+	for k, b := range r.buckets {
+		b.refill(now)
+		if b.tokens >= b.burst {
+			delete(r.buckets, k)
+		}
+	}
+}
+
+// rateLimitKey returns the bucket key for input with `limit`. Limits apply
+// to each principal (see PeerID), or for unauthenticated callers to each
+// network address.
+func rateLimitKey(input *RPCAuthInput, limit *opa.RateLimit) string {
+	caller := PeerID(input.Peer)
+	if caller == "" && input.Peer != nil && input.Peer.Net != nil {
+		caller = input.Peer.Net.Address
+	}
+	return caller + "\x00" + limit.Key
+}
+
+// rateLimitHook is a no-op RPCAuthzHook used to enable rate limits through
+// the existing hook plumbing (i.e. server.Serve) for an Authorizer.
+type rateLimitHook struct{}
+
+func (rateLimitHook) Hook(context.Context, *RPCAuthInput) error {
+	return nil
+}
+
+// RateLimits returns an RPCAuthzHook which, when passed to New or NewWithPolicy,
+// makes the Authorizer enforce any rate limit the policy sets for allowed
+// requests (see opa.RateLimit), i.e.
+//
+//	rate_limit = {"rps": 1, "burst": 5} {
+//	  input.type = "Exec.ExecRequest"
+//	}
+//
+// Each caller has a separate limit. Requests over it are rejected with
+// ResourceExhausted, even in dry run mode, with a RetryInfo detail saying
+// when to retry (see proxy.RetryDelay). Rate limited decisions are never
+// cached.
+func RateLimits() RPCAuthzHook {
+	return rateLimitHook{}
+}
+
+// rateLimitedError returns the error for a request over `limit`, which can be
+// retried after `wait`.
+func rateLimitedError(limit *opa.RateLimit, wait time.Duration) error {
+	s := status.Newf(codes.ResourceExhausted, "rate limit of %g requests/s exceeded, retry in %v", limit.RPS, wait.Round(time.Millisecond))
+	d, err := s.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)})
+	if err != nil {
+		return s.Err()
+	}
+	return d.Err()
+}
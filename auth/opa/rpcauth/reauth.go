@@ -0,0 +1,89 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package rpcauth
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/status"
+)
+
+// reauthHook is a no-op RPCAuthzHook used to carry the stream
+// re-authorization interval through the existing hook plumbing
+// (i.e. server.Serve) to an Authorizer.
+type reauthHook struct {
+	interval time.Duration
+}
+
+func (reauthHook) Hook(context.Context, *RPCAuthInput) error {
+	return nil
+}
+
+// ReauthorizeStreams returns an RPCAuthzHook which, when passed to New or
+// NewWithPolicy, makes streams authorized with AuthorizeStream re-evaluate
+// the input of their most recently received message every `interval`. Every
+// message a stream receives is always authorized, but a long lived stream
+// (i.e. tailing a file) may receive just one, so without this revoked
+// access or a more restrictive policy only affects new streams.
+//
+// A stream which is no longer allowed has its context canceled, and further
+// sends and receives fail with the denial. Rate limits and approvals aren't
+// applied again when re-evaluating.
+func ReauthorizeStreams(interval time.Duration) RPCAuthzHook {
+	return reauthHook{interval: interval}
+}
+
+// reauthorize re-evaluates the stream every interval until ctx is done,
+// revoking it and calling cancel if it's no longer allowed.
+func (e *wrappedStream) reauthorize(ctx context.Context, cancel context.CancelFunc, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		input := e.state.getInput()
+		if input == nil {
+			// Nothing has been received to authorize yet.
+			continue
+		}
+		if err := e.authz.evalAndRecord(ctx, copyInput(input), true); err != nil {
+			logr.FromContextOrDiscard(ctx).Info("stream no longer authorized", "method", e.info.FullMethod, "reason", status.Convert(err).Message())
+			e.revoke(status.Errorf(status.Code(err), "stream no longer authorized: %s", status.Convert(err).Message()))
+			cancel()
+			return
+		}
+	}
+}
+
+// revoke makes all further use of the stream fail with err.
+func (e *wrappedStream) revoke(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.revoked = err
+}
+
+// revokedErr returns the error the stream was revoked with, if any.
+func (e *wrappedStream) revokedErr() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.revoked
+}
@@ -21,6 +21,10 @@ package rpcauth
 import (
 	"context"
 	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-logr/logr"
 	"google.golang.org/grpc"
@@ -42,6 +46,30 @@ type Authorizer struct {
 
 	// Additional authorization hooks invoked before policy evaluation.
 	hooks []RPCAuthzHook
+
+	// Sinks which receive a record of every decision.
+	sinks []AuditSink
+
+	// If non-nil, recent allow decisions.
+	cache *decisionCache
+
+	// If true policy denials are only logged and audited, not enforced.
+	dryRun bool
+
+	// The number of requests denied by policy but permitted due to dryRun.
+	dryRunDenials uint64
+
+	// If non-nil, checks requests the policy requires approval for.
+	approvals ApprovalGate
+
+	// Recorders of decision counts and latency.
+	metrics []Metrics
+
+	// If non-nil, enforces rate limits set by the policy.
+	limiter *rateLimiter
+
+	// If non-zero, how often open streams are re-authorized.
+	reauthInterval time.Duration
 }
 
 // A RPCAuthzHook is invoked on populated RpcAuthInput prior to policy
@@ -53,8 +81,43 @@ type RPCAuthzHook interface {
 
 // New creates a new Authorizer from an opa.AuthzPolicy. Any supplied authorization
 // hooks will be executed, in the order provided, on each policy evauluation.
+// Hooks created with AuditHook are recorded as audit sinks rather than being run,
+// one created with DecisionCache enables caching, one created with DryRun
+// stops enforcing policy denials, one created with RequireApprovals
+// enables approvals, one created with RateLimits enforces rate limits, one
+// created with ReauthorizeStreams re-authorizes open streams and those
+// created with MetricsHook receive measurements (including cache events if
+// they implement CacheMetrics).
 func New(policy *opa.AuthzPolicy, authzHooks ...RPCAuthzHook) *Authorizer {
-	return &Authorizer{policy: policy, hooks: authzHooks}
+	a := &Authorizer{policy: policy}
+	for _, h := range authzHooks {
+		switch th := h.(type) {
+		case auditHook:
+			a.sinks = append(a.sinks, th.sink)
+		case cacheHook:
+			a.cache = newDecisionCache(th.ttl, th.max)
+		case dryRunHook:
+			a.dryRun = true
+		case approvalHook:
+			a.approvals = th.gate
+		case metricsHook:
+			a.metrics = append(a.metrics, th.m)
+		case rateLimitHook:
+			a.limiter = newRateLimiter()
+		case reauthHook:
+			a.reauthInterval = th.interval
+		default:
+			a.hooks = append(a.hooks, h)
+		}
+	}
+	if a.cache != nil {
+		for _, m := range a.metrics {
+			if cm, ok := m.(CacheMetrics); ok {
+				a.cache.metrics = append(a.cache.metrics, cm)
+			}
+		}
+	}
+	return a
 }
 
 // NewWithPolicy creates a new Authorizer from a policy string. Any supplied
@@ -72,8 +135,70 @@ func NewWithPolicy(ctx context.Context, policy string, authzHooks ...RPCAuthzHoo
 // nil iff policy evaulation was successful, and the request is permitted, or
 // an appropriate status.Error otherwise. Any input hooks will be executed
 // prior to policy evaluation, and may mutate `input`, regardless of the
-// the success or failure of policy.
+// the success or failure of policy. The decision is then sent to any audit sinks.
+//
+// In dry run mode (see DryRun) requests denied by the policy are permitted.
+// Allowed requests are then checked against any rate limit the policy sets
+// (see RateLimits), and those the policy requires approval for are then checked with
+// any ApprovalGate (see RequireApprovals). Finally the decision is reported
+// to any Metrics (see MetricsHook).
 func (g *Authorizer) Eval(ctx context.Context, input *RPCAuthInput) error {
+	return g.evalAndRecord(ctx, input, false)
+}
+
+// evalAndRecord performs the work of Eval. If recheck is set the input
+// was allowed before, so rate limits and approvals aren't applied again.
+func (g *Authorizer) evalAndRecord(ctx context.Context, input *RPCAuthInput, recheck bool) error {
+	start := time.Now()
+	denied, err := g.eval(ctx, input, recheck)
+	dryRun := denied && g.dryRun
+	if input != nil && len(g.sinks) > 0 {
+		g.audit(ctx, input, err, dryRun)
+	}
+	if input != nil && len(g.metrics) > 0 {
+		d, version, latency := decision(denied, err), g.policy.Version(), time.Since(start)
+		for _, m := range g.metrics {
+			m.ObserveDecision(input.Method, d, version, latency)
+		}
+	}
+	if dryRun {
+		atomic.AddUint64(&g.dryRunDenials, 1)
+		logr.FromContextOrDiscard(ctx).Info("dry run: permitting request denied by policy", "method", input.Method, "reason", status.Convert(err).Message())
+		return nil
+	}
+	return err
+}
+
+// audit sends the decision for input to all audit sinks. dryRun is set if
+// a denial isn't being enforced.
+func (g *Authorizer) audit(ctx context.Context, input *RPCAuthInput, decision error, dryRun bool) {
+	logger := logr.FromContextOrDiscard(ctx)
+	event := &AuditEvent{
+		Time:          time.Now(),
+		Method:        input.Method,
+		MessageType:   input.MessageType,
+		Peer:          input.Peer,
+		Host:          input.Host,
+		Justification: input.Justification,
+		InputHash:     HashInput(input),
+		Allowed:       decision == nil,
+		Query:         g.policy.AllowQuery(),
+		DryRun:        dryRun,
+		Input:         input,
+	}
+	if decision != nil {
+		event.Reason = status.Convert(decision).Message()
+	}
+	for _, s := range g.sinks {
+		if err := s.Audit(ctx, event); err != nil {
+			logger.Error(err, "audit sink")
+		}
+	}
+}
+
+// eval performs the evaluation for evalAndRecord, returning true along with
+// the error if the policy itself denied the request.
+func (g *Authorizer) eval(ctx context.Context, input *RPCAuthInput, recheck bool) (bool, error) {
 	logger := logr.FromContextOrDiscard(ctx)
 	if input != nil {
 		if logger.V(2).Enabled() {
@@ -86,15 +211,15 @@ func (g *Authorizer) Eval(ctx context.Context, input *RPCAuthInput) error {
 		}
 	}
 	if input == nil {
-		return status.Error(codes.InvalidArgument, "policy input cannot be nil")
+		return false, status.Error(codes.InvalidArgument, "policy input cannot be nil")
 	}
 	for _, hook := range g.hooks {
 		if err := hook.Hook(ctx, input); err != nil {
 			if _, ok := status.FromError(err); ok {
 				// error is already an appropriate status.Status
-				return err
+				return false, err
 			}
-			return status.Errorf(codes.Internal, "authz hook error: %v", err)
+			return false, status.Errorf(codes.Internal, "authz hook error: %v", err)
 		}
 	}
 	if logger.V(1).Enabled() {
@@ -105,15 +230,82 @@ func (g *Authorizer) Eval(ctx context.Context, input *RPCAuthInput) error {
 			logger.V(1).Info("evaluating authz policy post hooks", "input", string(b))
 		}
 	}
+	// The evaluation time is set below, after the cache lookup, so it
+	// doesn't defeat caching. Policies depending on it should only be
+	// used with short cache TTLs.
+	input.Time = time.Time{}
+	var cacheKey string
+	var generation uint64
+	if g.cache != nil {
+		cacheKey = HashInput(input)
+		generation = g.policy.Generation()
+		if cacheKey != "" && g.cache.allowed(cacheKey, generation, time.Now()) {
+			logger.V(1).Info("authz decision cached", "method", input.Method)
+			return false, nil
+		}
+	}
+	input.Time = time.Now()
 	allowed, err := g.policy.Eval(ctx, input)
 	if err != nil {
-		return status.Errorf(codes.Internal, "authz policy evaluation error: %v", err)
+		return false, status.Errorf(codes.Internal, "authz policy evaluation error: %v", err)
 	}
 	if !allowed {
-		logger.V(1).Info("permission denied")
-		return status.Errorf(codes.PermissionDenied, "OPA policy does not permit this request")
+		hints, err := g.policy.DenialHints(ctx, input)
+		if err != nil {
+			// Failing to explain a denial shouldn't change the outcome.
+			logger.V(1).Info("denial hints", "error", err)
+		}
+		logger.V(1).Info("permission denied", "method", input.Method, "query", g.policy.AllowQuery(), "hints", hints)
+		if len(hints) > 0 {
+			return true, status.Errorf(codes.PermissionDenied, "OPA policy does not permit this request: %s", strings.Join(hints, "; "))
+		}
+		return true, status.Errorf(codes.PermissionDenied, "OPA policy does not permit this request")
 	}
-	return nil
+	limited := false
+	if g.limiter != nil && !recheck {
+		limit, err := g.policy.RateLimit(ctx, input)
+		if err != nil {
+			return false, status.Errorf(codes.Internal, "authz rate limit evaluation error: %v", err)
+		}
+		if limit != nil {
+			limited = true
+			if wait := g.limiter.take(rateLimitKey(input, limit), limit, time.Now()); wait > 0 {
+				logger.V(1).Info("rate limited", "method", input.Method, "rps", limit.RPS, "key", limit.Key)
+				return false, rateLimitedError(limit, wait)
+			}
+		}
+	}
+	if g.approvals != nil && !recheck {
+		required, err := g.policy.RequiresApproval(ctx, input)
+		if err != nil {
+			return false, status.Errorf(codes.Internal, "authz approval evaluation error: %v", err)
+		}
+		if required {
+			// Never cached as approvals are single use.
+			if err := g.approvals.Check(ctx, input); err != nil {
+				if _, ok := status.FromError(err); ok {
+					return false, err
+				}
+				return false, status.Errorf(codes.Internal, "approval check error: %v", err)
+			}
+			return false, nil
+		}
+	}
+	if g.cache != nil && cacheKey != "" && !limited {
+		g.cache.add(cacheKey, generation, time.Now())
+	}
+	return false, nil
+}
+
+// PolicyVersion returns the version of the policy currently used for
+// decisions. See opa.AuthzPolicy.Version.
+func (g *Authorizer) PolicyVersion() string {
+	return g.policy.Version()
+}
+
+// AllowQuery returns the query used to make authorization decisions.
+func (g *Authorizer) AllowQuery() string {
+	return g.policy.AllowQuery()
 }
 
 // Authorize implements grpc.UnaryServerInterceptor
@@ -129,17 +321,30 @@ func (g *Authorizer) Authorize(ctx context.Context, req interface{}, info *grpc.
 	if err := g.Eval(ctx, authInput); err != nil {
 		return nil, err
 	}
-	return handler(ctx, req)
+	ctx = contextWithAuthz(ctx, &authzState{authz: g, method: info.FullMethod, input: authInput})
+	return handler(contextWithInput(ctx, authInput), req)
 }
 
 // AuthorizeStream implements grpc.StreamServerInterceptor
 func (g *Authorizer) AuthorizeStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, cancel := context.WithCancel(ss.Context())
+	defer cancel()
+	state := &authzState{authz: g, method: info.FullMethod}
 	wrapped := &wrappedStream{
 		ServerStream: ss,
 		info:         info,
 		authz:        g,
+		state:        state,
+		ctx:          contextWithAuthz(ctx, state),
 	}
-	return handler(srv, wrapped)
+	if g.reauthInterval > 0 {
+		go wrapped.reauthorize(ctx, cancel, g.reauthInterval)
+	}
+	err := handler(srv, wrapped)
+	if revoked := wrapped.revokedErr(); revoked != nil {
+		return revoked
+	}
+	return err
 }
 
 // wrappedStream wraps an existing grpc.ServerStream with authorization checking.
@@ -147,6 +352,26 @@ type wrappedStream struct {
 	grpc.ServerStream
 	info  *grpc.StreamServerInfo
 	authz *Authorizer
+	// The input of the last authorized message, for AuthorizeAction.
+	state *authzState
+	ctx   context.Context
+
+	mu sync.Mutex
+	// If set the stream is no longer authorized (see ReauthorizeStreams).
+	revoked error
+}
+
+// see: grpc.ServerStream.Context
+func (e *wrappedStream) Context() context.Context {
+	return e.ctx
+}
+
+// see: grpc.ServerStream.SendMsg
+func (e *wrappedStream) SendMsg(m interface{}) error {
+	if err := e.revokedErr(); err != nil {
+		return err
+	}
+	return e.ServerStream.SendMsg(m)
 }
 
 // see: grpc.ServerStream.RecvMsg
@@ -156,6 +381,9 @@ func (e *wrappedStream) RecvMsg(req interface{}) error {
 	// be filled by the stream.
 	// Therefore, in order to check the message against the policy, it
 	// first needs to be populated by receiving from the wire.
+	if err := e.revokedErr(); err != nil {
+		return err
+	}
 	if err := e.ServerStream.RecvMsg(req); err != nil {
 		return err
 	}
@@ -170,5 +398,6 @@ func (e *wrappedStream) RecvMsg(req interface{}) error {
 	if err := e.authz.Eval(ctx, authInput); err != nil {
 		return err
 	}
+	e.state.setInput(authInput)
 	return nil
 }
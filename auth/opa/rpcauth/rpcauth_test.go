@@ -20,14 +20,23 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"errors"
 	"log"
+	"math/big"
 	"net"
 	"net/url"
 	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -38,6 +47,8 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/Snowflake-Labs/sansshell/auth/opa"
+	execpb "github.com/Snowflake-Labs/sansshell/services/exec"
+	lfpb "github.com/Snowflake-Labs/sansshell/services/localfile"
 	"github.com/Snowflake-Labs/sansshell/testing/testutil"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/stdr"
@@ -326,6 +337,113 @@ func TestAuthzHook(t *testing.T) {
 	}
 }
 
+func TestInputTime(t *testing.T) {
+	ctx := context.Background()
+	authorizer, err := NewWithPolicy(ctx, `
+package sansshell.authz
+
+allow {
+  time.parse_rfc3339_ns(input.time) > time.parse_rfc3339_ns("2020-01-01T00:00:00Z")
+}
+`)
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+	input := &RPCAuthInput{Method: "/Foo/Bar", Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}
+	testutil.FatalOnErr("Eval", authorizer.Eval(ctx, input), t)
+	if time.Since(input.Time) > time.Minute {
+		t.Errorf("input.Time = %v, want about now", input.Time)
+	}
+}
+
+func TestJustificationFormatHook(t *testing.T) {
+	ctx := context.Background()
+	hook := JustificationFormatHook(regexp.MustCompile(`^INC-[0-9]+$`))
+	for _, tc := range []struct {
+		name          string
+		justification string
+		wantErr       bool
+	}{
+		{name: "none"},
+		{name: "matching", justification: "INC-123"},
+		{name: "not matching", justification: "because", wantErr: true},
+	} {
+		err := hook.Hook(ctx, &RPCAuthInput{Justification: tc.justification})
+		want := codes.OK
+		if tc.wantErr {
+			want = codes.FailedPrecondition
+		}
+		if got := status.Code(err); got != want {
+			t.Errorf("%s: Hook() = %v, want code %v", tc.name, err, want)
+		}
+	}
+}
+
+func TestProxyTargetHook(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name       string
+		md         metadata.MD
+		verified   string
+		wantCode   codes.Code
+		wantTarget string
+	}{
+		{
+			name: "not proxied",
+		},
+		{
+			name:       "proxied",
+			md:         metadata.Pairs(ProxyTargetKey, "canary1:50042"),
+			wantTarget: "canary1:50042",
+		},
+		{
+			name:     "multiple",
+			md:       metadata.Pairs(ProxyTargetKey, "canary1:50042", ProxyTargetKey, "prod1:50042"),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:       "verified",
+			md:         metadata.Pairs(ProxyTargetKey, "canary1:50042"),
+			verified:   "canary1:50042",
+			wantTarget: "canary1:50042",
+		},
+		{
+			name:       "verified without metadata",
+			verified:   "canary1:50042",
+			wantTarget: "canary1:50042",
+		},
+		{
+			name:     "verified mismatch",
+			md:       metadata.Pairs(ProxyTargetKey, "canary1:50042"),
+			verified: "prod1:50042",
+			wantCode: codes.Unauthenticated,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := &RPCAuthInput{Metadata: tc.md}
+			err := ProxyTargetHook().Hook(ctx, input)
+			if err == nil && tc.verified != "" {
+				err = CheckProxyTarget(input, tc.verified)
+			}
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("code = %v (%v), want %v", got, err, tc.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			var got string
+			if input.Host != nil {
+				got = input.Host.Target
+			}
+			if got != tc.wantTarget {
+				t.Errorf("Host.Target = %q, want %q", got, tc.wantTarget)
+			}
+			if len(input.Metadata.Get(ProxyTargetKey)) != 0 {
+				t.Error("target wasn't removed from metadata")
+			}
+		})
+	}
+}
+
 func TestNewWithPolicy(t *testing.T) {
 	_, err := NewWithPolicy(context.Background(), policyString)
 	testutil.FatalOnErr("NewWithPolicy valid", err, t)
@@ -376,6 +494,17 @@ func TestRpcAuthInput(t *testing.T) {
 				Peer:     &PeerAuthInput{},
 			},
 		},
+		{
+			name:   "method and justification",
+			ctx:    metadata.NewIncomingContext(context.Background(), metadata.Pairs(ReqJustKey, "ticket-1")),
+			method: "/AMethod",
+			compare: &RPCAuthInput{
+				Method:        "/AMethod",
+				Metadata:      metadata.Pairs(ReqJustKey, "ticket-1"),
+				Justification: "ticket-1",
+				Peer:          &PeerAuthInput{},
+			},
+		},
 		{
 			name:   "method and request",
 			ctx:    context.Background(),
@@ -457,7 +586,62 @@ func TestRpcAuthInput(t *testing.T) {
 						Address: "127.0.0.1",
 						Port:    "1",
 					},
-					Cert: &CertAuthInput{SPIFFEID: "/"},
+					Cert:       &CertAuthInput{SPIFFEID: "/"},
+					Identity:   &PeerIdentity{ID: "/", Type: IdentityTypeSPIFFE},
+					Identities: []*PeerIdentity{{ID: "/", Type: IdentityTypeSPIFFE}},
+				},
+			},
+		},
+		{
+			name: "method and a peer context with a full tls cert",
+			ctx: peer.NewContext(context.Background(), &peer.Peer{
+				Addr: tcp,
+				AuthInfo: credentials.TLSInfo{
+					State: tls.ConnectionState{
+						PeerCertificates: []*x509.Certificate{
+							{
+								Subject: pkix.Name{
+									CommonName:         "client",
+									OrganizationalUnit: []string{"infra"},
+								},
+								Issuer:         pkix.Name{CommonName: "root"},
+								DNSNames:       []string{"client.example.com"},
+								URIs:           []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/client"}},
+								IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+								EmailAddresses: []string{"client@example.com"},
+								SerialNumber:   big.NewInt(12345),
+								NotBefore:      time.Unix(1000, 0),
+								NotAfter:       time.Unix(2000, 0),
+							},
+						},
+					},
+				},
+			}),
+			method: "/AMethod",
+			compare: &RPCAuthInput{
+				Method: "/AMethod",
+				Peer: &PeerAuthInput{
+					Net: &NetAuthInput{
+						Network: "tcp",
+						Address: "127.0.0.1",
+						Port:    "1",
+					},
+					Cert: &CertAuthInput{
+						Subject: pkix.Name{
+							CommonName:         "client",
+							OrganizationalUnit: []string{"infra"},
+						},
+						Issuer:         pkix.Name{CommonName: "root"},
+						DNSNames:       []string{"client.example.com"},
+						URIs:           []string{"spiffe://example.com/client"},
+						IPAddresses:    []string{"10.0.0.1"},
+						EmailAddresses: []string{"client@example.com"},
+						SerialNumber:   "12345",
+						NotBefore:      time.Unix(1000, 0),
+						NotAfter:       time.Unix(2000, 0),
+					},
+					Identity:   &PeerIdentity{ID: "client", Type: IdentityTypeMTLS, Issuer: "CN=root"},
+					Identities: []*PeerIdentity{{ID: "client", Type: IdentityTypeMTLS, Issuer: "CN=root"}},
 				},
 			},
 		},
@@ -472,6 +656,688 @@ func TestRpcAuthInput(t *testing.T) {
 
 }
 
+func TestMessageInPolicy(t *testing.T) {
+	ctx := context.Background()
+	authorizer, err := NewWithPolicy(ctx, `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.type = "LocalFile.ReadActionRequest"
+  startswith(input.message.file.filename, "/var/log/")
+  not contains(input.message.file.filename, "..")
+}
+
+allow {
+  input.type = "LocalFile.ReadActionRequest"
+  input.message.tail.filename = "/var/log/messages"
+  to_number(input.message.tail.offset) >= -1024
+}
+
+allowed_commands := {"/bin/echo", "/usr/bin/uptime"}
+
+allow {
+  input.type = "Exec.ExecRequest"
+  allowed_commands[input.message.command]
+}
+`)
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+
+	for _, tc := range []struct {
+		name    string
+		req     proto.Message
+		allowed bool
+	}{
+		{
+			name:    "read under /var/log",
+			req:     &lfpb.ReadActionRequest{Request: &lfpb.ReadActionRequest_File{File: &lfpb.ReadRequest{Filename: "/var/log/syslog"}}},
+			allowed: true,
+		},
+		{
+			name: "read outside /var/log",
+			req:  &lfpb.ReadActionRequest{Request: &lfpb.ReadActionRequest_File{File: &lfpb.ReadRequest{Filename: "/etc/shadow"}}},
+		},
+		{
+			name: "read escaping /var/log",
+			req:  &lfpb.ReadActionRequest{Request: &lfpb.ReadActionRequest_File{File: &lfpb.ReadRequest{Filename: "/var/log/../../etc/shadow"}}},
+		},
+		{
+			name:    "tail within limit",
+			req:     &lfpb.ReadActionRequest{Request: &lfpb.ReadActionRequest_Tail{Tail: &lfpb.TailRequest{Filename: "/var/log/messages", Offset: -100}}},
+			allowed: true,
+		},
+		{
+			name: "tail past limit",
+			req:  &lfpb.ReadActionRequest{Request: &lfpb.ReadActionRequest_Tail{Tail: &lfpb.TailRequest{Filename: "/var/log/messages", Offset: -4096}}},
+		},
+		{
+			name:    "allowed command",
+			req:     &execpb.ExecRequest{Command: "/bin/echo", Args: []string{"hi"}},
+			allowed: true,
+		},
+		{
+			name: "disallowed command",
+			req:  &execpb.ExecRequest{Command: "/bin/rm", Args: []string{"-rf", "/"}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input, err := NewRPCAuthInput(ctx, "/Some/Method", tc.req)
+			testutil.FatalOnErr("NewRPCAuthInput", err, t)
+			err = authorizer.Eval(ctx, input)
+			testutil.WantErr(tc.name, err, !tc.allowed, t)
+		})
+	}
+}
+
+func TestDenialHints(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name    string
+		policy  string
+		wantMsg string
+	}{
+		{
+			name:    "no hints",
+			policy:  policyString,
+			wantMsg: "OPA policy does not permit this request",
+		},
+		{
+			name: "single reason",
+			policy: `
+package sansshell.authz
+
+default allow = false
+
+deny_reason = "only admins may call this"
+`,
+			wantMsg: "OPA policy does not permit this request: only admins may call this",
+		},
+		{
+			name: "set of reasons",
+			policy: `
+package sansshell.authz
+
+default allow = false
+
+deny_reason[msg] {
+  input.method = "/Foo/Baz"
+  msg := sprintf("method %s is not allowed", [input.method])
+}
+
+deny_reason[msg] {
+  not input.peer.principal
+  msg := "no principal"
+}
+`,
+			wantMsg: "OPA policy does not permit this request: method /Foo/Baz is not allowed; no principal",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			authorizer, err := NewWithPolicy(ctx, tc.policy)
+			testutil.FatalOnErr("NewWithPolicy", err, t)
+			err = authorizer.Eval(ctx, &RPCAuthInput{Method: "/Foo/Baz"})
+			if got, want := status.Code(err), codes.PermissionDenied; got != want {
+				t.Fatalf("Eval() code = %s, want %s", got, want)
+			}
+			if got := status.Convert(err).Message(); got != tc.wantMsg {
+				t.Fatalf("Eval() message = %q, want %q", got, tc.wantMsg)
+			}
+		})
+	}
+}
+
+func TestAuditHook(t *testing.T) {
+	ctx := context.Background()
+	var events []*AuditEvent
+	sink := AuditSinkFunc(func(ctx context.Context, e *AuditEvent) error {
+		events = append(events, e)
+		return errors.New("sink failure")
+	})
+	authorizer, err := NewWithPolicy(ctx, policyString, AuditHook(sink))
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+	if len(authorizer.hooks) != 0 {
+		t.Fatalf("audit hook was added as a regular hook: %+v", authorizer.hooks)
+	}
+
+	// A failing sink doesn't change the decision.
+	err = authorizer.Eval(ctx, &RPCAuthInput{Method: "/Foo/Bar", Justification: "ticket-1"})
+	testutil.FatalOnErr("Eval allowed", err, t)
+	err = authorizer.Eval(ctx, &RPCAuthInput{Method: "/Foo/Baz"})
+	testutil.FatalOnNoErr("Eval denied", err, t)
+	// Nil input is rejected before any decision so isn't audited.
+	err = authorizer.Eval(ctx, nil)
+	testutil.FatalOnNoErr("Eval nil", err, t)
+
+	if len(events) != 2 {
+		t.Fatalf("got %d audit events, want 2", len(events))
+	}
+	if !events[0].Allowed || events[0].Method != "/Foo/Bar" || events[0].Reason != "" || events[0].Justification != "ticket-1" {
+		t.Errorf("unexpected allowed event %+v", events[0])
+	}
+	if events[1].Allowed || events[1].Method != "/Foo/Baz" || events[1].Reason == "" {
+		t.Errorf("unexpected denied event %+v", events[1])
+	}
+	if events[0].InputHash == events[1].InputHash {
+		t.Error("different inputs produced the same hash")
+	}
+}
+
+// cacheCounts is a CacheMetrics which counts events.
+type cacheCounts map[string]int
+
+func (cacheCounts) ObserveDecision(string, string, string, time.Duration) {}
+
+func (c cacheCounts) ObserveCache(event string, count int) {
+	c[event] += count
+}
+
+func TestDecisionCache(t *testing.T) {
+	ctx := context.Background()
+	policy, err := opa.NewAuthzPolicy(ctx, policyString)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	denyHook := false
+	counts := cacheCounts{}
+	authorizer := New(policy, DecisionCache(time.Hour, 2), MetricsHook(counts), RPCAuthzHookFunc(func(ctx context.Context, input *RPCAuthInput) error {
+		if denyHook {
+			return status.Error(codes.FailedPrecondition, "hook denied")
+		}
+		return nil
+	}))
+	allowed := &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.BazRequest"}
+	denied := &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.OtherRequest"}
+
+	check := func(input *RPCAuthInput, wantCode codes.Code, want cacheCounts, wantEntries int) {
+		t.Helper()
+		err := authorizer.Eval(ctx, input)
+		if got := status.Code(err); got != wantCode {
+			t.Fatalf("Eval(%+v) = %v, want code %v", input, err, wantCode)
+		}
+		if diff := cmp.Diff(want, counts, cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("unexpected cache events (-want +got):\n%s", diff)
+		}
+		if got := len(authorizer.cache.entries); got != wantEntries {
+			t.Fatalf("cache entries = %d, want %d", got, wantEntries)
+		}
+	}
+	check(allowed, codes.OK, cacheCounts{CacheMiss: 1}, 1)
+	check(allowed, codes.OK, cacheCounts{CacheHit: 1, CacheMiss: 1}, 1)
+	// Denials are never cached.
+	check(denied, codes.PermissionDenied, cacheCounts{CacheHit: 1, CacheMiss: 2}, 1)
+	check(denied, codes.PermissionDenied, cacheCounts{CacheHit: 1, CacheMiss: 3}, 1)
+	// Hooks still run ahead of the cache.
+	denyHook = true
+	check(allowed, codes.FailedPrecondition, cacheCounts{CacheHit: 1, CacheMiss: 3}, 1)
+	denyHook = false
+
+	// The cache doesn't grow past its limit.
+	check(&RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.BazRequest", Metadata: metadata.Pairs("a", "1")}, codes.OK, cacheCounts{CacheHit: 1, CacheMiss: 4}, 2)
+	check(&RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.BazRequest", Metadata: metadata.Pairs("a", "2")}, codes.OK, cacheCounts{CacheHit: 1, CacheMiss: 5, CacheEviction: 1}, 2)
+
+	// A policy update flushes the cache and the new policy applies immediately.
+	err = policy.Update(ctx, `
+package sansshell.authz
+
+default allow = false
+`)
+	testutil.FatalOnErr("Update", err, t)
+	check(allowed, codes.PermissionDenied, cacheCounts{CacheHit: 1, CacheMiss: 6, CacheEviction: 3}, 0)
+
+	err = policy.Update(ctx, policyString)
+	testutil.FatalOnErr("Update", err, t)
+	check(allowed, codes.OK, cacheCounts{CacheHit: 1, CacheMiss: 7, CacheEviction: 3}, 1)
+
+	// Expired entries aren't used.
+	counts = cacheCounts{}
+	authorizer = New(policy, DecisionCache(0, 0), MetricsHook(counts))
+	check(allowed, codes.OK, cacheCounts{CacheMiss: 1}, 1)
+	check(allowed, codes.OK, cacheCounts{CacheMiss: 2, CacheEviction: 1}, 1)
+
+	// Without a cache nothing is reported.
+	counts = cacheCounts{}
+	testutil.FatalOnErr("Eval", New(policy, MetricsHook(counts)).Eval(ctx, allowed), t)
+	if len(counts) != 0 {
+		t.Errorf("cache events without cache = %v, want none", counts)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	policy, err := opa.NewAuthzPolicy(ctx, policyString)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	var events []*AuditEvent
+	sink := AuditSinkFunc(func(ctx context.Context, e *AuditEvent) error {
+		events = append(events, e)
+		return nil
+	})
+	denyHook := false
+	authorizer := New(policy, DryRun(), AuditHook(sink), RPCAuthzHookFunc(func(ctx context.Context, input *RPCAuthInput) error {
+		if denyHook {
+			return status.Error(codes.FailedPrecondition, "hook denied")
+		}
+		return nil
+	}))
+
+	for _, tc := range []struct {
+		name        string
+		input       *RPCAuthInput
+		denyHook    bool
+		wantCode    codes.Code
+		wantAllowed bool
+		wantDryRun  bool
+		wantDenials uint64
+	}{
+		{
+			name:        "allowed",
+			input:       &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.BazRequest"},
+			wantAllowed: true,
+		},
+		{
+			name:        "denied by policy",
+			input:       &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.OtherRequest"},
+			wantDryRun:  true,
+			wantDenials: 1,
+		},
+		{
+			name:        "denied by hook",
+			input:       &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.BazRequest"},
+			denyHook:    true,
+			wantCode:    codes.FailedPrecondition,
+			wantDenials: 1,
+		},
+	} {
+		events = nil
+		denyHook = tc.denyHook
+		err := authorizer.Eval(ctx, tc.input)
+		if got := status.Code(err); got != tc.wantCode {
+			t.Fatalf("%s: Eval() = %v, want code %v", tc.name, err, tc.wantCode)
+		}
+		if len(events) != 1 {
+			t.Fatalf("%s: got %d audit events, want 1", tc.name, len(events))
+		}
+		if events[0].Allowed != tc.wantAllowed || events[0].DryRun != tc.wantDryRun {
+			t.Errorf("%s: audit event allowed %t dry run %t, want %t %t", tc.name, events[0].Allowed, events[0].DryRun, tc.wantAllowed, tc.wantDryRun)
+		}
+		if got := authorizer.DryRunDenials(); got != tc.wantDenials {
+			t.Errorf("%s: DryRunDenials() = %d, want %d", tc.name, got, tc.wantDenials)
+		}
+	}
+}
+
+func TestMetricsHook(t *testing.T) {
+	ctx := context.Background()
+	policy, err := opa.NewAuthzPolicy(ctx, policyString)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	type observation struct {
+		method, decision, version string
+	}
+	var got []observation
+	m := MetricsFunc(func(method, decision, version string, latency time.Duration) {
+		if latency < 0 {
+			t.Errorf("%s: negative latency %v", method, latency)
+		}
+		got = append(got, observation{method, decision, version})
+	})
+	var hookErr error
+	authorizer := New(policy, MetricsHook(m), RPCAuthzHookFunc(func(ctx context.Context, input *RPCAuthInput) error {
+		return hookErr
+	}))
+
+	for _, tc := range []struct {
+		name    string
+		input   *RPCAuthInput
+		hookErr error
+		want    string
+	}{
+		{
+			name:  "allowed",
+			input: &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.BazRequest"},
+			want:  DecisionAllow,
+		},
+		{
+			name:  "denied by policy",
+			input: &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.OtherRequest"},
+			want:  DecisionDeny,
+		},
+		{
+			name:    "denied by hook",
+			input:   &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.BazRequest"},
+			hookErr: status.Error(codes.Unauthenticated, "no"),
+			want:    DecisionDeny,
+		},
+		{
+			name:    "hook failure",
+			input:   &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.BazRequest"},
+			hookErr: errors.New("broken"),
+			want:    DecisionError,
+		},
+	} {
+		got = nil
+		hookErr = tc.hookErr
+		_ = authorizer.Eval(ctx, tc.input)
+		want := []observation{{tc.input.Method, tc.want, policy.Version()}}
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(observation{})); diff != "" {
+			t.Errorf("%s: observations mismatch (-want +got):\n%s", tc.name, diff)
+		}
+	}
+
+	// Dry run denials are still reported as denials.
+	got = nil
+	hookErr = nil
+	authorizer = New(policy, DryRun(), MetricsHook(m))
+	testutil.FatalOnErr("Eval", authorizer.Eval(ctx, &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.OtherRequest"}), t)
+	if len(got) != 1 || got[0].decision != DecisionDeny {
+		t.Errorf("dry run: got observations %v, want one %s", got, DecisionDeny)
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	ctx := context.Background()
+	policy, err := opa.NewAuthzPolicy(ctx, `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.method = "/Foo.Bar/Baz"
+}
+
+allow {
+  input.method = "/Foo.Bar/Unlimited"
+}
+
+rate_limit = {"rps": 0.001, "burst": 2} {
+  input.method = "/Foo.Bar/Baz"
+}
+`)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	authorizer := New(policy, RateLimits(), DecisionCache(time.Hour, 0))
+	input := func(principal string, method string) *RPCAuthInput {
+		return &RPCAuthInput{
+			Method: method,
+			Peer:   &PeerAuthInput{Principal: &PrincipalAuthInput{ID: principal}},
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		input    *RPCAuthInput
+		wantCode codes.Code
+	}{
+		{name: "first", input: input("alice", "/Foo.Bar/Baz")},
+		{name: "burst", input: input("alice", "/Foo.Bar/Baz")},
+		{name: "over limit", input: input("alice", "/Foo.Bar/Baz"), wantCode: codes.ResourceExhausted},
+		{name: "another principal", input: input("bob", "/Foo.Bar/Baz")},
+		{name: "unlimited method", input: input("alice", "/Foo.Bar/Unlimited")},
+		{name: "unlimited method again", input: input("alice", "/Foo.Bar/Unlimited")},
+		{name: "still over limit", input: input("alice", "/Foo.Bar/Baz"), wantCode: codes.ResourceExhausted},
+		{name: "denied", input: input("alice", "/Foo.Bar/Other"), wantCode: codes.PermissionDenied},
+	} {
+		err := authorizer.Eval(ctx, tc.input)
+		if got := status.Code(err); got != tc.wantCode {
+			t.Fatalf("%s: Eval() = %v, want code %v", tc.name, err, tc.wantCode)
+		}
+		if tc.wantCode != codes.ResourceExhausted {
+			continue
+		}
+		var retry *errdetails.RetryInfo
+		for _, d := range status.Convert(err).Details() {
+			if ri, ok := d.(*errdetails.RetryInfo); ok {
+				retry = ri
+			}
+		}
+		if retry == nil {
+			t.Fatalf("%s: Eval() = %v, want a RetryInfo detail", tc.name, err)
+		}
+		// One token every 1000s.
+		if d := retry.GetRetryDelay().AsDuration(); d <= 0 || d > 1000*time.Second {
+			t.Errorf("%s: retry delay = %v, want (0, 1000s]", tc.name, d)
+		}
+	}
+	// Only the unlimited decision was cached.
+	if got := len(authorizer.cache.entries); got != 1 {
+		t.Errorf("cache entries = %d, want 1", got)
+	}
+
+	// Without RateLimits limits aren't enforced.
+	authorizer = New(policy)
+	for i := 0; i < 5; i++ {
+		testutil.FatalOnErr("Eval", authorizer.Eval(ctx, input("alice", "/Foo.Bar/Baz")), t)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	r := newRateLimiter()
+	limit := &opa.RateLimit{RPS: 2, Burst: 1}
+	now := time.Now()
+	if wait := r.take("a", limit, now); wait != 0 {
+		t.Fatalf("first take waits %v, want 0", wait)
+	}
+	if wait := r.take("a", limit, now); wait != 500*time.Millisecond {
+		t.Fatalf("second take waits %v, want 500ms", wait)
+	}
+	if wait := r.take("a", limit, now.Add(250*time.Millisecond)); wait != 250*time.Millisecond {
+		t.Fatalf("take after 250ms waits %v, want 250ms", wait)
+	}
+	if wait := r.take("a", limit, now.Add(500*time.Millisecond)); wait != 0 {
+		t.Fatalf("take after 500ms waits %v, want 0", wait)
+	}
+	// Full buckets are dropped by a sweep.
+	r.sweep(now.Add(time.Hour))
+	if len(r.buckets) != 0 {
+		t.Errorf("%d buckets after sweep, want 0", len(r.buckets))
+	}
+}
+
+// approvalGate is an ApprovalGate allowing requests with an approval ID of "ok".
+type approvalGate struct {
+	checks int
+}
+
+func (g *approvalGate) Check(ctx context.Context, input *RPCAuthInput) error {
+	g.checks++
+	if ids := input.Metadata.Get(ApprovalIDKey); len(ids) == 1 && ids[0] == "ok" {
+		return nil
+	}
+	return status.Error(codes.FailedPrecondition, "needs approval")
+}
+
+func TestRequireApprovals(t *testing.T) {
+	ctx := context.Background()
+	policy, err := opa.NewAuthzPolicy(ctx, policyString+`
+require_approval {
+  input.method = "/Foo.Bar/Baz"
+}
+`)
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	gate := &approvalGate{}
+	authorizer := New(policy, RequireApprovals(gate), DecisionCache(time.Minute, 0))
+
+	for _, tc := range []struct {
+		name       string
+		input      *RPCAuthInput
+		wantCode   codes.Code
+		wantChecks int
+	}{
+		{
+			name:  "no approval required",
+			input: &RPCAuthInput{Method: "/Foo/Bar"},
+		},
+		{
+			name:       "not approved",
+			input:      &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.BazRequest"},
+			wantCode:   codes.FailedPrecondition,
+			wantChecks: 1,
+		},
+		{
+			name:       "approved",
+			input:      &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.BazRequest", Metadata: metadata.Pairs(ApprovalIDKey, "ok")},
+			wantChecks: 1,
+		},
+		{
+			name:     "denied by policy",
+			input:    &RPCAuthInput{Method: "/Foo.Bar/Baz", MessageType: "Foo.OtherRequest"},
+			wantCode: codes.PermissionDenied,
+		},
+	} {
+		// Run each twice to check approvals aren't cached.
+		gate.checks = 0
+		for i := 0; i < 2; i++ {
+			err := authorizer.Eval(ctx, tc.input)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("%s: Eval() = %v, want code %v", tc.name, err, tc.wantCode)
+			}
+		}
+		if gate.checks != 2*tc.wantChecks {
+			t.Errorf("%s: gate checked %d times, want %d", tc.name, gate.checks, 2*tc.wantChecks)
+		}
+	}
+}
+
+func TestInputFromContext(t *testing.T) {
+	if got := InputFromContext(context.Background()); got != nil {
+		t.Errorf("InputFromContext() = %+v, want nil", got)
+	}
+	authorizer, err := NewWithPolicy(context.Background(), policyString, RPCAuthzHookFunc(func(ctx context.Context, input *RPCAuthInput) error {
+		input.Peer.Principal = &PrincipalAuthInput{ID: "someone"}
+		return nil
+	}))
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+	var got string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got = PrincipalID(InputFromContext(ctx))
+		return nil, nil
+	}
+	_, err = authorizer.Authorize(context.Background(), &emptypb.Empty{}, &grpc.UnaryServerInfo{FullMethod: "/Foo/Bar"}, handler)
+	testutil.FatalOnErr("Authorize", err, t)
+	if got != "someone" {
+		t.Errorf("principal of input in handler context = %q, want someone", got)
+	}
+}
+
+func TestIdentityHook(t *testing.T) {
+	ctx := context.Background()
+	policy := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.peer.identity.type = "test"
+  input.peer.identity.id = "alice"
+}
+`
+	cert := &PeerIdentity{ID: "spiffe://example.com/client", Type: IdentityTypeSPIFFE}
+	authn := func(id string) Authenticator {
+		return AuthenticatorFunc(func(ctx context.Context, input *RPCAuthInput) (*PeerIdentity, error) {
+			switch id {
+			case "":
+				return nil, nil
+			case "bad":
+				return nil, errors.New("bad credential")
+			}
+			return &PeerIdentity{ID: id, Type: "test"}, nil
+		})
+	}
+	for _, tc := range []struct {
+		name           string
+		authenticators []Authenticator
+		wantCode       codes.Code
+		wantIdentities []*PeerIdentity
+	}{
+		{
+			name:           "first authenticator wins",
+			authenticators: []Authenticator{authn(""), authn("alice"), authn("bob")},
+			wantIdentities: []*PeerIdentity{{ID: "alice", Type: "test"}, {ID: "bob", Type: "test"}, cert},
+		},
+		{
+			name:           "other identity",
+			authenticators: []Authenticator{authn("bob"), authn("alice")},
+			wantCode:       codes.PermissionDenied,
+			wantIdentities: []*PeerIdentity{{ID: "bob", Type: "test"}, {ID: "alice", Type: "test"}, cert},
+		},
+		{
+			name:           "no credentials",
+			authenticators: []Authenticator{authn("")},
+			wantCode:       codes.PermissionDenied,
+			wantIdentities: []*PeerIdentity{cert},
+		},
+		{
+			name:           "invalid credential",
+			authenticators: []Authenticator{authn("alice"), authn("bad")},
+			wantCode:       codes.Unauthenticated,
+			wantIdentities: []*PeerIdentity{cert},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			authz, err := NewWithPolicy(ctx, policy, IdentityHook(tc.authenticators...))
+			testutil.FatalOnErr("NewWithPolicy", err, t)
+			input := &RPCAuthInput{
+				Method: "/Foo.Bar/Baz",
+				Peer: &PeerAuthInput{
+					Cert:       &CertAuthInput{SPIFFEID: cert.ID},
+					Identity:   cert,
+					Identities: []*PeerIdentity{cert},
+				},
+			}
+			err = authz.Eval(ctx, input)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Eval() code = %v (%v), want %v", got, err, tc.wantCode)
+			}
+			testutil.DiffErr(tc.name, input.Peer.Identities, tc.wantIdentities, t)
+			testutil.DiffErr(tc.name, input.Peer.Identity, tc.wantIdentities[0], t)
+		})
+	}
+}
+
+func TestPeerID(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		peer *PeerAuthInput
+		want string
+	}{
+		{
+			name: "no peer",
+		},
+		{
+			name: "principal",
+			peer: &PeerAuthInput{
+				Principal: &PrincipalAuthInput{ID: "alice"},
+				Identity:  &PeerIdentity{ID: "bob"},
+			},
+			want: "alice",
+		},
+		{
+			name: "identity",
+			peer: &PeerAuthInput{
+				Identity: &PeerIdentity{ID: "bob"},
+				Cert:     &CertAuthInput{Subject: pkix.Name{CommonName: "carol"}},
+			},
+			want: "bob",
+		},
+		{
+			name: "certificate only",
+			peer: &PeerAuthInput{
+				Cert: &CertAuthInput{Subject: pkix.Name{CommonName: "carol"}},
+			},
+			want: "carol",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PeerID(tc.peer); got != tc.want {
+				t.Errorf("PeerID() = %q, want %q", got, tc.want)
+			}
+			if got := PrincipalID(&RPCAuthInput{Peer: tc.peer}); got != tc.want {
+				t.Errorf("PrincipalID() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestAuthorize(t *testing.T) {
 	req := &emptypb.Empty{}
 	info := &grpc.UnaryServerInfo{
@@ -517,6 +1383,10 @@ func (*fakeServerStream) RecvMsg(req interface{}) error {
 	return nil
 }
 
+func (*fakeServerStream) SendMsg(interface{}) error {
+	return nil
+}
+
 func (f *fakeServerStream) Context() context.Context {
 	return f.Ctx
 }
@@ -557,3 +1427,209 @@ func TestAuthorizeStream(t *testing.T) {
 	err = authorizer.AuthorizeStream(req, fake, info, handler)
 	testutil.FatalOnNoErr("AuthorizeStream with failing hook", err, t)
 }
+
+func TestAuthorizeAction(t *testing.T) {
+	ctx := context.Background()
+	policy := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.method = "/Foo/Bar"
+  input.action == null
+}
+
+allow {
+  input.method = "/Foo/Bar"
+  input.action.name = "read"
+  startswith(input.action.resource, "/var/log/")
+  input.action.extra.size < 100
+}
+`
+	authorizer, err := NewWithPolicy(ctx, policy)
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+
+	testutil.FatalOnErr("AuthorizeAction without authorizer", AuthorizeAction(ctx, "read", "/etc/shadow", nil), t)
+
+	for _, tc := range []struct {
+		name     string
+		resource string
+		size     int
+		wantErr  bool
+	}{
+		{name: "allowed", resource: "/var/log/messages", size: 1},
+		{name: "denied resource", resource: "/etc/shadow", size: 1, wantErr: true},
+		{name: "denied extra", resource: "/var/log/messages", size: 1000, wantErr: true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			extra := map[string]interface{}{"size": tc.size}
+			var input *RPCAuthInput
+			unary := func(ctx context.Context, req interface{}) (interface{}, error) {
+				input = InputFromContext(ctx)
+				return nil, AuthorizeAction(ctx, "read", tc.resource, extra)
+			}
+			_, err := authorizer.Authorize(ctx, &emptypb.Empty{}, &grpc.UnaryServerInfo{FullMethod: "/Foo/Bar"}, unary)
+			testutil.WantErr("unary", err, tc.wantErr, t)
+			if tc.wantErr && status.Code(err) != codes.PermissionDenied {
+				t.Errorf("unary: got code %s, want PermissionDenied", status.Code(err))
+			}
+			if input == nil || input.Action != nil {
+				t.Errorf("unary: request input %+v changed by AuthorizeAction", input)
+			}
+
+			stream := func(srv interface{}, stream grpc.ServerStream) error {
+				// Before any message the check has no request message.
+				if err := AuthorizeAction(stream.Context(), "read", tc.resource, extra); err != nil {
+					return err
+				}
+				if err := stream.RecvMsg(srv); err != nil {
+					return err
+				}
+				return AuthorizeAction(stream.Context(), "read", tc.resource, extra)
+			}
+			err = authorizer.AuthorizeStream(&emptypb.Empty{}, &fakeServerStream{Ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/Foo/Bar"}, stream)
+			testutil.WantErr("stream", err, tc.wantErr, t)
+		})
+	}
+}
+
+func TestRedactedInput(t *testing.T) {
+	req := &lfpb.WriteRequest{Request: &lfpb.WriteRequest_Contents{Contents: []byte("secret")}}
+	input, err := NewRPCAuthInput(context.Background(), "/LocalFile.LocalFile/Write", req)
+	testutil.FatalOnErr("NewRPCAuthInput", err, t)
+	var got map[string]string
+	testutil.FatalOnErr("json.Unmarshal", json.Unmarshal(input.Message, &got), t)
+	// "REDACTED", base64 encoded.
+	if want := "UkVEQUNURUQ="; got["contents"] != want {
+		t.Errorf("input.Message = %s, want contents %s", input.Message, want)
+	}
+	if string(req.GetContents()) != "secret" {
+		t.Errorf("request contents changed to %q", req.GetContents())
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	ctx := context.Background()
+	policy := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.method = "/Foo/Bar"
+}
+
+rate_limit = {"rps": 0.001, "burst": 1}
+
+require_approval {
+  input.peer.principal.id = "reviewed"
+}
+
+deny_reason["not /Foo/Bar"] {
+  input.method != "/Foo/Bar"
+}
+`
+	audited := 0
+	sink := AuditSinkFunc(func(ctx context.Context, e *AuditEvent) error {
+		audited++
+		return nil
+	})
+	authorizer, err := NewWithPolicy(ctx, policy, AuditHook(sink), RateLimits(), DryRun(), RPCAuthzHookFunc(func(ctx context.Context, input *RPCAuthInput) error {
+		if input.Method == "/Foo/Hook" {
+			return status.Error(codes.PermissionDenied, "hook says no")
+		}
+		return nil
+	}))
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+
+	for _, tc := range []struct {
+		name         string
+		method       string
+		principal    string
+		wantAllowed  bool
+		wantApproval bool
+		wantReasons  []string
+	}{
+		{name: "allowed", method: "/Foo/Bar", wantAllowed: true},
+		// Repeated to check the rate limit isn't consumed.
+		{name: "allowed again", method: "/Foo/Bar", wantAllowed: true},
+		{name: "approval", method: "/Foo/Bar", principal: "reviewed", wantAllowed: true, wantApproval: true},
+		{name: "denied", method: "/Foo/Baz", wantReasons: []string{"not /Foo/Bar"}},
+		{name: "hook", method: "/Foo/Hook", wantReasons: []string{"hook says no"}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := &RPCAuthInput{Method: tc.method, Peer: &PeerAuthInput{}}
+			if tc.principal != "" {
+				input.Peer.Principal = &PrincipalAuthInput{ID: tc.principal}
+			}
+			d, err := authorizer.Simulate(ctx, input)
+			testutil.FatalOnErr("Simulate", err, t)
+			if d.Allowed != tc.wantAllowed || d.RequiresApproval != tc.wantApproval {
+				t.Errorf("Simulate() = allowed %t approval %t, want %t %t", d.Allowed, d.RequiresApproval, tc.wantAllowed, tc.wantApproval)
+			}
+			testutil.DiffErr("reasons", d.Reasons, tc.wantReasons, t)
+			if tc.wantAllowed && (d.RateLimit == nil || len(d.MatchedRules) != 1) {
+				t.Errorf("Simulate() = %+v, want a rate limit and matched rule", d)
+			}
+		})
+	}
+	if audited != 0 {
+		t.Errorf("Simulate() audited %d decisions, want none", audited)
+	}
+	// The rate limit allows one request, which Simulate mustn't have used.
+	err = authorizer.Eval(ctx, &RPCAuthInput{Method: "/Foo/Bar"})
+	testutil.FatalOnErr("Eval after Simulate", err, t)
+}
+
+func TestReauthorizeStreams(t *testing.T) {
+	var revoked int32
+	revoke := RPCAuthzHookFunc(func(ctx context.Context, input *RPCAuthInput) error {
+		if atomic.LoadInt32(&revoked) != 0 {
+			return status.Error(codes.PermissionDenied, "access revoked")
+		}
+		return nil
+	})
+	authorizer, err := NewWithPolicy(context.Background(), policyString, revoke, ReauthorizeStreams(10*time.Millisecond))
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+
+	sent := make(chan struct{})
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		if err := stream.RecvMsg(srv); err != nil {
+			return err
+		}
+		close(sent)
+		// Stream until the context is canceled, as i.e. a tail would.
+		for {
+			select {
+			case <-stream.Context().Done():
+				return stream.SendMsg(&emptypb.Empty{})
+			case <-time.After(time.Millisecond):
+				if err := stream.SendMsg(&emptypb.Empty{}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- authorizer.AuthorizeStream(&emptypb.Empty{}, &fakeServerStream{Ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/Foo/Bar"}, handler)
+	}()
+	<-sent
+	select {
+	case err := <-errc:
+		t.Fatalf("stream ended before access was revoked: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	atomic.StoreInt32(&revoked, 1)
+	select {
+	case err := <-errc:
+		if status.Code(err) != codes.PermissionDenied || !strings.Contains(err.Error(), "access revoked") {
+			t.Errorf("AuthorizeStream() = %v, want PermissionDenied for revoked access", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("stream wasn't ended after access was revoked")
+	}
+}
@@ -0,0 +1,93 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package rpcauth
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa"
+)
+
+// A Decision is the outcome of evaluating a request with Simulate.
+type Decision struct {
+	// Allowed is true if the policy permits the request.
+	Allowed bool
+	// Reasons explain a denial: the error of a hook which rejected the
+	// request, or any denial hints from the policy.
+	Reasons []string
+	// MatchedRules are the rules satisfied while evaluating the request.
+	// See opa.AuthzPolicy.MatchedRules.
+	MatchedRules []string
+	// RequiresApproval is true if the policy allows the request but
+	// requires it to be approved before it runs.
+	RequiresApproval bool
+	// RateLimit is the rate limit the policy sets for the request, if any.
+	RateLimit *opa.RateLimit
+	// PolicyVersion identifies the policy evaluated (see opa.AuthzPolicy.Version).
+	PolicyVersion string
+	// Input is the policy input after any hooks ran.
+	Input *RPCAuthInput
+}
+
+// Simulate evaluates the policy for `input` as Eval would, running hooks
+// first, but only reports the decision so policies can be debugged.
+// Nothing is audited or recorded in metrics, dry run mode is ignored and
+// rate limits, approvals and the decision cache are neither checked nor
+// changed. A hook returning an error denies the request as in Eval, with
+// the error as the reason. Errors are only returned if the policy can't be
+// evaluated.
+func (g *Authorizer) Simulate(ctx context.Context, input *RPCAuthInput) (*Decision, error) {
+	if input == nil {
+		return nil, status.Error(codes.InvalidArgument, "policy input cannot be nil")
+	}
+	d := &Decision{
+		PolicyVersion: g.policy.Version(),
+		Input:         input,
+	}
+	for _, hook := range g.hooks {
+		if err := hook.Hook(ctx, input); err != nil {
+			d.Reasons = []string{status.Convert(err).Message()}
+			return d, nil
+		}
+	}
+	input.Time = time.Now()
+	allowed, err := g.policy.Eval(ctx, input)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "authz policy evaluation error: %v", err)
+	}
+	d.Allowed = allowed
+	if d.MatchedRules, err = g.policy.MatchedRules(ctx, input); err != nil {
+		return nil, status.Errorf(codes.Internal, "authz policy evaluation error: %v", err)
+	}
+	if !allowed {
+		if d.Reasons, err = g.policy.DenialHints(ctx, input); err != nil {
+			return nil, status.Errorf(codes.Internal, "authz denial hints evaluation error: %v", err)
+		}
+		return d, nil
+	}
+	if d.RateLimit, err = g.policy.RateLimit(ctx, input); err != nil {
+		return nil, status.Errorf(codes.Internal, "authz rate limit evaluation error: %v", err)
+	}
+	if d.RequiresApproval, err = g.policy.RequiresApproval(ctx, input); err != nil {
+		return nil, status.Errorf(codes.Internal, "authz approval evaluation error: %v", err)
+	}
+	return d, nil
+}
@@ -0,0 +1,207 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package signature verifies detached signatures on sansshell authz policies
+// before they're used, so a compromised config distribution path (a config
+// management system, a web server) can't silently replace or weaken a policy.
+//
+// The signature for a policy is read from the same place with ".sig"
+// appended, i.e. /etc/sansshell/policy.rego.sig or
+// https://policy.example.com/bundle.tar.gz.sig. It covers the exact bytes
+// loaded (for remote bundles, the gzipped bundle itself) and may be either raw
+// or base64 encoded. ECDSA and RSA (PKCS #1 v1.5) signatures are over the
+// SHA-256 digest and Ed25519 ones over the content, which matches:
+//
+//	openssl dgst -sha256 -sign key.pem policy.rego | base64 > policy.rego.sig
+//	cosign sign-blob --key key.pem policy.rego > policy.rego.sig
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// Suffix is appended to a policy's file name or URL to find its signature.
+	Suffix = ".sig"
+
+	// Signatures larger than this are rejected.
+	maxSignatureSize = 64 * 1024
+)
+
+// A Verifier checks policies against a public key. It implements
+// opa.PolicyVerifier.
+type Verifier struct {
+	key    crypto.PublicKey
+	client *http.Client
+}
+
+// An Option controls the behavior of a Verifier.
+type Option interface {
+	apply(*Verifier)
+}
+
+type optionFunc func(*Verifier)
+
+func (o optionFunc) apply(v *Verifier) {
+	o(v)
+}
+
+// WithHTTPClient returns an option to use `client` to fetch signatures of
+// remote policies rather than http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return optionFunc(func(v *Verifier) {
+		v.client = client
+	})
+}
+
+// NewVerifier returns a Verifier accepting policies signed by the private
+// key corresponding to `key`.
+func NewVerifier(key crypto.PublicKey, opts ...Option) (*Verifier, error) {
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	v := &Verifier{
+		key:    key,
+		client: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt.apply(v)
+	}
+	return v, nil
+}
+
+// LoadVerifier is NewVerifier with the key read from a file containing either
+// a PEM encoded public key or certificate.
+func LoadVerifier(keyFile string, opts ...Option) (*Verifier, error) {
+	b, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", keyFile)
+	}
+	var key crypto.PublicKey
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse certificate in %s: %w", keyFile, err)
+		}
+		key = cert.PublicKey
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse public key in %s: %w", keyFile, err)
+		}
+	}
+	return NewVerifier(key, opts...)
+}
+
+// Verify checks that `sig` is a valid signature of `data`. sig may be raw or
+// base64 encoded.
+func (v *Verifier) Verify(data []byte, sig []byte) error {
+	if trimmed := bytes.TrimSpace(sig); len(trimmed) > 0 {
+		if dec, err := base64.StdEncoding.DecodeString(string(trimmed)); err == nil {
+			sig = dec
+		}
+	}
+	if len(sig) == 0 {
+		return errors.New("empty signature")
+	}
+	digest := sha256.Sum256(data)
+	switch k := v.key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest[:], sig) {
+			return errors.New("invalid ECDSA signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig); err != nil {
+			return fmt.Errorf("invalid RSA signature: %w", err)
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, data, sig) {
+			return errors.New("invalid Ed25519 signature")
+		}
+	}
+	return nil
+}
+
+// VerifyPolicy implements opa.PolicyVerifier, checking `policy` against the
+// signature found at `source` with Suffix appended.
+func (v *Verifier) VerifyPolicy(ctx context.Context, source string, policy []byte) error {
+	sigSource := source + Suffix
+	sig, err := v.fetch(ctx, sigSource)
+	if err != nil {
+		return fmt.Errorf("can't read policy signature %s: %w", sigSource, err)
+	}
+	if err := v.Verify(policy, sig); err != nil {
+		return fmt.Errorf("policy %s: %w", source, err)
+	}
+	return nil
+}
+
+// fetch reads the signature from a file or HTTP(S) URL.
+func (v *Verifier) fetch(ctx context.Context, source string) ([]byte, error) {
+	var r io.Reader
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := v.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch returned %s", resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	b, err := io.ReadAll(io.LimitReader(r, maxSignatureSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxSignatureSize {
+		return nil, fmt.Errorf("larger than %d bytes", maxSignatureSize)
+	}
+	return b, nil
+}
@@ -0,0 +1,201 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const policy = `
+package sansshell.authz
+
+allow {
+  input.foo = "bar"
+}
+`
+
+// sign returns the signature of data with key as produced by openssl dgst
+// (or pkeyutl -rawin for Ed25519).
+func sign(t *testing.T, key crypto.Signer, data []byte) []byte {
+	t.Helper()
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		sig, err := key.Sign(rand.Reader, data, crypto.Hash(0))
+		testutil.FatalOnErr("Sign", err, t)
+		return sig
+	}
+	digest := sha256.Sum256(data)
+	sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	testutil.FatalOnErr("Sign", err, t)
+	return sig
+}
+
+func TestVerify(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("ecdsa.GenerateKey", err, t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	testutil.FatalOnErr("rsa.GenerateKey", err, t)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	testutil.FatalOnErr("ed25519.GenerateKey", err, t)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("ecdsa.GenerateKey", err, t)
+
+	data := []byte(policy)
+	for _, tc := range []struct {
+		name    string
+		key     crypto.Signer
+		sig     []byte
+		wantErr bool
+	}{
+		{
+			name: "ecdsa",
+			key:  ecKey,
+			sig:  sign(t, ecKey, data),
+		},
+		{
+			name: "ecdsa base64",
+			key:  ecKey,
+			sig:  []byte(base64.StdEncoding.EncodeToString(sign(t, ecKey, data)) + "\n"),
+		},
+		{
+			name: "rsa",
+			key:  rsaKey,
+			sig:  sign(t, rsaKey, data),
+		},
+		{
+			name: "ed25519",
+			key:  edKey,
+			sig:  sign(t, edKey, data),
+		},
+		{
+			name:    "wrong key",
+			key:     ecKey,
+			sig:     sign(t, otherKey, data),
+			wantErr: true,
+		},
+		{
+			name:    "different data",
+			key:     rsaKey,
+			sig:     sign(t, rsaKey, []byte("package sansshell.authz\n\nallow = true\n")),
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			key:     edKey,
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := NewVerifier(tc.key.Public())
+			testutil.FatalOnErr("NewVerifier", err, t)
+			if err := v.Verify(data, tc.sig); (err != nil) != tc.wantErr {
+				t.Fatalf("Verify() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyPolicy(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("ecdsa.GenerateKey", err, t)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	testutil.FatalOnErr("MarshalPKIXPublicKey", err, t)
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.pem")
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644)
+	testutil.FatalOnErr("WriteFile", err, t)
+
+	policyFile := filepath.Join(dir, "policy.rego")
+	err = os.WriteFile(policyFile+Suffix, []byte(base64.StdEncoding.EncodeToString(sign(t, key, []byte(policy)))), 0644)
+	testutil.FatalOnErr("WriteFile", err, t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/policy.rego"+Suffix {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, policyFile+Suffix)
+	}))
+	defer srv.Close()
+
+	v, err := LoadVerifier(keyFile, WithHTTPClient(srv.Client()))
+	testutil.FatalOnErr("LoadVerifier", err, t)
+
+	for _, tc := range []struct {
+		name    string
+		source  string
+		policy  string
+		wantErr bool
+	}{
+		{
+			name:   "file",
+			source: policyFile,
+			policy: policy,
+		},
+		{
+			name:   "url",
+			source: srv.URL + "/policy.rego",
+			policy: policy,
+		},
+		{
+			name:    "modified policy",
+			source:  policyFile,
+			policy:  policy + "\nallow = true\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing signature file",
+			source:  filepath.Join(dir, "other.rego"),
+			policy:  policy,
+			wantErr: true,
+		},
+		{
+			name:    "missing signature url",
+			source:  srv.URL + "/other.rego",
+			policy:  policy,
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if err := v.VerifyPolicy(ctx, tc.source, []byte(tc.policy)); (err != nil) != tc.wantErr {
+				t.Fatalf("VerifyPolicy() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	_, err = LoadVerifier(policyFile + Suffix)
+	testutil.FatalOnNoErr("LoadVerifier of non PEM file", err, t)
+}
@@ -0,0 +1,93 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package opa
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// DefaultWatchInterval is a reasonable interval for checking a policy file for changes.
+const DefaultWatchInterval = 5 * time.Second
+
+// A PolicyVerifier checks a policy loaded from `source` (a file name or URL)
+// before it's compiled, i.e. by verifying a signature, returning an error if
+// it shouldn't be used.
+type PolicyVerifier interface {
+	VerifyPolicy(ctx context.Context, source string, policy []byte) error
+}
+
+// WatchFile checks `filename` every `interval` until `ctx` is done and, if the
+// file has changed since the last check, recompiles it and swaps it into `policy`.
+// Policies which fail to compile are logged and rejected leaving the current
+// policy in effect. This blocks so is generally run in its own goroutine.
+func WatchFile(ctx context.Context, filename string, interval time.Duration, policy *AuthzPolicy) {
+	WatchFileVerified(ctx, filename, interval, policy, nil)
+}
+
+// WatchFileVerified is WatchFile with changed policies also rejected unless
+// `verifier` accepts them. Rejected policies are verified again on the next
+// check, as a signature is often updated just after the policy it covers.
+// If verifier is nil it's identical to WatchFile.
+func WatchFileVerified(ctx context.Context, filename string, interval time.Duration, policy *AuthzPolicy, verifier PolicyVerifier) {
+	logger := logr.FromContextOrDiscard(ctx).WithValues("file", filename)
+
+	var lastMod time.Time
+	var lastSize int64
+	if fi, err := os.Stat(filename); err == nil {
+		lastMod, lastSize = fi.ModTime(), fi.Size()
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		fi, err := os.Stat(filename)
+		if err != nil {
+			logger.Error(err, "os.Stat")
+			continue
+		}
+		if fi.ModTime().Equal(lastMod) && fi.Size() == lastSize {
+			continue
+		}
+		lastMod, lastSize = fi.ModTime(), fi.Size()
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			logger.Error(err, "os.ReadFile")
+			continue
+		}
+		if verifier != nil {
+			if err := verifier.VerifyPolicy(ctx, filename, b); err != nil {
+				logger.Error(err, "rejecting unverified policy")
+				lastMod, lastSize = time.Time{}, 0
+				continue
+			}
+		}
+		if err := policy.Update(ctx, string(b)); err != nil {
+			logger.Error(err, "rejecting updated policy")
+			continue
+		}
+		logger.Info("reloaded policy")
+	}
+}
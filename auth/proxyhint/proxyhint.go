@@ -0,0 +1,307 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package proxyhint lets a proxy pass a signed summary of its authorization
+// decision to the servers it forwards requests to, so their policies can
+// require that a trusted proxy already vetted a request (defense in depth)
+// without duplicating all of the proxy's policy data on every host.
+//
+// The proxy signs a hint with a Signer when it opens a stream to a target and
+// sends it in the stream metadata. As gRPC sends metadata before any requests,
+// the hint covers the stream rather than an individual request: it records the
+// caller and method the proxy's policy is evaluated for, and the proxy only
+// forwards requests on the stream once its policy has allowed them.
+//
+// On the server Hook verifies the hint and exposes it to policy as
+// input.proxy_decision, i.e.
+//
+//	allow {
+//	  input.proxy_decision.issuer == "proxy.example.com"
+//	  ...
+//	}
+package proxyhint
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+const (
+	// MetadataKey is the gRPC metadata key carrying the hint.
+	MetadataKey = "sansshell-proxy-decision"
+
+	// DefaultTTL is how long hints are valid for unless changed with
+	// NewSigner. Requests on a stream after its hint expires are rejected
+	// by servers requiring hints.
+	DefaultTTL = time.Hour
+
+	// Allow this much difference between proxy and server clocks.
+	clockSkew = time.Minute
+)
+
+var validMethods = []string{"ES256", "ES384", "ES512", "RS256", "EdDSA"}
+
+// A Decision summarizes the proxy's authorization of a stream.
+type Decision struct {
+	// Target is the server the stream is opened to.
+	Target string
+	// Method is the full method name of the stream.
+	Method string
+	// PolicyVersion is the version of the proxy's policy
+	// (see rpcauth.Authorizer.PolicyVersion).
+	PolicyVersion string
+	// Query is the query the proxy evaluates to allow requests.
+	Query string
+	// InputHash is a hash of the proxy's authorization input for the caller
+	// and method (see rpcauth.HashInput).
+	InputHash string
+}
+
+// claims are the JWT claims of a hint. The target is the audience.
+type claims struct {
+	jwt.RegisteredClaims
+	Method        string `json:"method"`
+	PolicyVersion string `json:"policy_version"`
+	Query         string `json:"query"`
+	InputHash     string `json:"input_hash"`
+}
+
+// A Signer creates hints on behalf of a proxy.
+type Signer struct {
+	issuer string
+	key    crypto.PrivateKey
+	method jwt.SigningMethod
+	ttl    time.Duration
+}
+
+// NewSigner returns a Signer which signs hints as `issuer` using `key`, which
+// must be an ECDSA (P-256, P-384 or P-521), RSA or Ed25519 private key.
+// If ttl is zero DefaultTTL is used.
+func NewSigner(issuer string, key crypto.PrivateKey, ttl time.Duration) (*Signer, error) {
+	if issuer == "" {
+		return nil, errors.New("issuer must be set")
+	}
+	var method jwt.SigningMethod
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			method = jwt.SigningMethodES256
+		case elliptic.P384():
+			method = jwt.SigningMethodES384
+		case elliptic.P521():
+			method = jwt.SigningMethodES512
+		default:
+			return nil, fmt.Errorf("unsupported ECDSA curve %s", k.Curve.Params().Name)
+		}
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	case ed25519.PrivateKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	return &Signer{
+		issuer: issuer,
+		key:    key,
+		method: method,
+		ttl:    ttl,
+	}, nil
+}
+
+// LoadSigner is NewSigner with a PEM encoded private key read from keyFile.
+func LoadSigner(issuer string, keyFile string, ttl time.Duration) (*Signer, error) {
+	b, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", keyFile)
+	}
+	var key crypto.PrivateKey
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't parse private key in %s: %w", keyFile, err)
+	}
+	return NewSigner(issuer, key, ttl)
+}
+
+// Sign returns a hint for `d`.
+func (s *Signer) Sign(d Decision) (string, error) {
+	now := time.Now()
+	c := &claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Audience:  jwt.ClaimStrings{d.Target},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.ttl)),
+		},
+		Method:        d.Method,
+		PolicyVersion: d.PolicyVersion,
+		Query:         d.Query,
+		InputHash:     d.InputHash,
+	}
+	return jwt.NewWithClaims(s.method, c).SignedString(s.key)
+}
+
+// A Verifier checks hints against the public keys of trusted proxies.
+type Verifier struct {
+	keys map[string]crypto.PublicKey
+}
+
+// NewVerifier returns a Verifier which accepts hints from the given issuers,
+// each of which must be signed with the corresponding public key.
+func NewVerifier(keys map[string]crypto.PublicKey) (*Verifier, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no trusted proxy keys")
+	}
+	out := make(map[string]crypto.PublicKey, len(keys))
+	for iss, k := range keys {
+		switch k.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		default:
+			return nil, fmt.Errorf("unsupported key type %T for %s", k, iss)
+		}
+		out[iss] = k
+	}
+	return &Verifier{keys: out}, nil
+}
+
+// LoadVerifier is NewVerifier with keys read from files (keyed by issuer)
+// containing either a PEM encoded public key or certificate.
+func LoadVerifier(keyFiles map[string]string) (*Verifier, error) {
+	keys := make(map[string]crypto.PublicKey, len(keyFiles))
+	for iss, f := range keyFiles {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM data in %s", f)
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("can't parse certificate in %s: %w", f, err)
+			}
+			keys[iss] = cert.PublicKey
+		default:
+			k, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("can't parse public key in %s: %w", f, err)
+			}
+			keys[iss] = k
+		}
+	}
+	return NewVerifier(keys)
+}
+
+// Verify checks the signature and validity of `hint` and that it was made for
+// `method`, returning its contents.
+func (v *Verifier) Verify(hint string, method string) (*rpcauth.ProxyDecisionInput, error) {
+	c := &claims{}
+	// Times are checked below to allow for clock skew.
+	p := jwt.NewParser(jwt.WithValidMethods(validMethods), jwt.WithoutClaimsValidation())
+	_, err := p.ParseWithClaims(hint, c, func(t *jwt.Token) (interface{}, error) {
+		k, ok := v.keys[c.Issuer]
+		if !ok {
+			return nil, fmt.Errorf("untrusted issuer %q", c.Issuer)
+		}
+		return k, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !c.VerifyExpiresAt(now.Add(-clockSkew), true) {
+		return nil, errors.New("hint expired")
+	}
+	if !c.VerifyIssuedAt(now.Add(clockSkew), true) {
+		return nil, errors.New("hint issued in the future")
+	}
+	if c.Method != method {
+		return nil, fmt.Errorf("hint is for method %s", c.Method)
+	}
+	if len(c.Audience) != 1 {
+		return nil, errors.New("hint must have one target")
+	}
+	return &rpcauth.ProxyDecisionInput{
+		Issuer:        c.Issuer,
+		Target:        c.Audience[0],
+		PolicyVersion: c.PolicyVersion,
+		Query:         c.Query,
+		InputHash:     c.InputHash,
+		IssuedAt:      c.IssuedAt.Time,
+	}, nil
+}
+
+// Hook returns an RPCAuthzHook which verifies any hint in the request
+// metadata with `v` and sets input.ProxyDecision. The hint is removed from
+// input.Metadata. Requests with an invalid hint are rejected, as are ones
+// without a hint if required is set.
+func Hook(v *Verifier, required bool) rpcauth.RPCAuthzHook {
+	return rpcauth.RPCAuthzHookFunc(func(ctx context.Context, input *rpcauth.RPCAuthInput) error {
+		vals := input.Metadata.Get(MetadataKey)
+		input.Metadata.Delete(MetadataKey)
+		if len(vals) == 0 {
+			if required {
+				return status.Error(codes.PermissionDenied, "request must be authorized by a trusted proxy")
+			}
+			return nil
+		}
+		if len(vals) > 1 {
+			return status.Error(codes.Unauthenticated, "multiple proxy decision hints")
+		}
+		d, err := v.Verify(vals[0], input.Method)
+		if err != nil {
+			return status.Errorf(codes.Unauthenticated, "invalid proxy decision hint: %v", err)
+		}
+		if err := rpcauth.CheckProxyTarget(input, d.Target); err != nil {
+			return err
+		}
+		input.ProxyDecision = d
+		return nil
+	})
+}
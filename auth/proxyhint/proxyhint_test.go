@@ -0,0 +1,217 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package proxyhint
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const method = "/Foo.Bar/Baz"
+
+var decision = Decision{
+	Target:        "host:50042",
+	Method:        method,
+	PolicyVersion: "v1",
+	Query:         "data.sansshell.authz.allow",
+	InputHash:     "abc",
+}
+
+func TestSignVerify(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	testutil.FatalOnErr("ecdsa.GenerateKey", err, t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	testutil.FatalOnErr("rsa.GenerateKey", err, t)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	testutil.FatalOnErr("ed25519.GenerateKey", err, t)
+
+	for _, tc := range []struct {
+		name string
+		key  crypto.Signer
+	}{
+		{"ecdsa", ecKey},
+		{"rsa", rsaKey},
+		{"ed25519", edKey},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := NewSigner("proxy", tc.key, 0)
+			testutil.FatalOnErr("NewSigner", err, t)
+			hint, err := s.Sign(decision)
+			testutil.FatalOnErr("Sign", err, t)
+			v, err := NewVerifier(map[string]crypto.PublicKey{"proxy": tc.key.Public()})
+			testutil.FatalOnErr("NewVerifier", err, t)
+			got, err := v.Verify(hint, method)
+			testutil.FatalOnErr("Verify", err, t)
+			want := &rpcauth.ProxyDecisionInput{
+				Issuer:        "proxy",
+				Target:        decision.Target,
+				PolicyVersion: decision.PolicyVersion,
+				Query:         decision.Query,
+				InputHash:     decision.InputHash,
+			}
+			want.IssuedAt = got.IssuedAt
+			if *got != *want {
+				t.Errorf("Verify = %+v, want %+v", got, want)
+			}
+			if time.Since(got.IssuedAt) > time.Minute {
+				t.Errorf("IssuedAt = %v, want about now", got.IssuedAt)
+			}
+
+			_, err = v.Verify(hint, "/Foo.Bar/Other")
+			testutil.FatalOnNoErr("other method", err, t)
+
+			// A hint signed by another key, or for an unknown issuer, is rejected.
+			other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			testutil.FatalOnErr("ecdsa.GenerateKey", err, t)
+			bad, err := NewSigner("proxy", other, 0)
+			testutil.FatalOnErr("NewSigner", err, t)
+			hint, err = bad.Sign(decision)
+			testutil.FatalOnErr("Sign", err, t)
+			_, err = v.Verify(hint, method)
+			testutil.FatalOnNoErr("wrong key", err, t)
+			bad, err = NewSigner("other-proxy", tc.key, 0)
+			testutil.FatalOnErr("NewSigner", err, t)
+			hint, err = bad.Sign(decision)
+			testutil.FatalOnErr("Sign", err, t)
+			_, err = v.Verify(hint, method)
+			testutil.FatalOnNoErr("unknown issuer", err, t)
+		})
+	}
+
+	// Expired hints are rejected.
+	s, err := NewSigner("proxy", ecKey, -2*clockSkew)
+	testutil.FatalOnErr("NewSigner", err, t)
+	hint, err := s.Sign(decision)
+	testutil.FatalOnErr("Sign", err, t)
+	v, err := NewVerifier(map[string]crypto.PublicKey{"proxy": ecKey.Public()})
+	testutil.FatalOnErr("NewVerifier", err, t)
+	_, err = v.Verify(hint, method)
+	testutil.FatalOnNoErr("expired", err, t)
+
+	_, err = NewSigner("", ecKey, 0)
+	testutil.FatalOnNoErr("no issuer", err, t)
+	_, err = NewSigner("proxy", "key", 0)
+	testutil.FatalOnNoErr("bad key", err, t)
+	_, err = NewVerifier(nil)
+	testutil.FatalOnNoErr("no keys", err, t)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	testutil.FatalOnErr("MarshalPKCS8PrivateKey", err, t)
+	keyFile := filepath.Join(dir, "key.pem")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600), t)
+	der, err = x509.MarshalPKIXPublicKey(key.Public())
+	testutil.FatalOnErr("MarshalPKIXPublicKey", err, t)
+	pubFile := filepath.Join(dir, "pub.pem")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(pubFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600), t)
+
+	s, err := LoadSigner("proxy", keyFile, 0)
+	testutil.FatalOnErr("LoadSigner", err, t)
+	v, err := LoadVerifier(map[string]string{"proxy": pubFile})
+	testutil.FatalOnErr("LoadVerifier", err, t)
+	hint, err := s.Sign(decision)
+	testutil.FatalOnErr("Sign", err, t)
+	_, err = v.Verify(hint, method)
+	testutil.FatalOnErr("Verify", err, t)
+
+	_, err = LoadSigner("proxy", pubFile, 0)
+	testutil.FatalOnNoErr("LoadSigner with public key", err, t)
+	_, err = LoadVerifier(map[string]string{"proxy": filepath.Join(dir, "missing")})
+	testutil.FatalOnNoErr("LoadVerifier with missing file", err, t)
+}
+
+func TestHook(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("GenerateKey", err, t)
+	s, err := NewSigner("proxy", key, 0)
+	testutil.FatalOnErr("NewSigner", err, t)
+	hint, err := s.Sign(decision)
+	testutil.FatalOnErr("Sign", err, t)
+	v, err := NewVerifier(map[string]crypto.PublicKey{"proxy": key.Public()})
+	testutil.FatalOnErr("NewVerifier", err, t)
+
+	for _, tc := range []struct {
+		name     string
+		md       metadata.MD
+		required bool
+		wantCode codes.Code
+		wantHint bool
+	}{
+		{
+			name:     "valid",
+			md:       metadata.Pairs(MetadataKey, hint),
+			wantHint: true,
+		},
+		{
+			name: "missing",
+		},
+		{
+			name:     "missing but required",
+			required: true,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "invalid",
+			md:       metadata.Pairs(MetadataKey, "bogus"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "multiple",
+			md:       metadata.Pairs(MetadataKey, hint, MetadataKey, hint),
+			wantCode: codes.Unauthenticated,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := &rpcauth.RPCAuthInput{Method: method, Metadata: tc.md}
+			err := Hook(v, tc.required).Hook(ctx, input)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Hook() code = %v, want %v (err %v)", got, tc.wantCode, err)
+			}
+			if got := input.ProxyDecision != nil; got != tc.wantHint {
+				t.Errorf("ProxyDecision set = %t, want %t", got, tc.wantHint)
+			}
+			if len(input.Metadata.Get(MetadataKey)) != 0 {
+				t.Error("hint wasn't removed from metadata")
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package redact removes fields marked sensitive with the (Redact.sensitive)
+// field option from request messages, so they don't appear in policy input
+// or audit logs. To mark a field import "auth/redact/redact.proto" (adding
+// the repository root to the protoc include path) and annotate it:
+//
+//	bytes contents = 2 [(Redact.sensitive) = MODE_REDACT];
+package redact
+
+// To regenerate the proto headers if the proto changes, just run go generate
+// and this encodes the necessary magic:
+//go:generate protoc --go_out=. --go_opt=paths=source_relative redact.proto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Redacted replaces the value of redacted string and bytes fields.
+const Redacted = "REDACTED"
+
+// FieldMode returns how `fd` is treated, which is MODE_UNKNOWN unless it's
+// marked sensitive.
+func FieldMode(fd protoreflect.FieldDescriptor) Mode {
+	opts := fd.Options()
+	if opts == nil {
+		return Mode_MODE_UNKNOWN
+	}
+	m, _ := proto.GetExtension(opts, E_Sensitive).(Mode)
+	return m
+}
+
+// sensitive caches whether a message type contains any sensitive fields.
+var sensitive sync.Map // map[protoreflect.FullName]bool
+
+// HasSensitive returns true if messages described by `md` contain any
+// sensitive fields, directly or in nested messages.
+func HasSensitive(md protoreflect.MessageDescriptor) bool {
+	if v, ok := sensitive.Load(md.FullName()); ok {
+		return v.(bool)
+	}
+	has := hasSensitive(md, make(map[protoreflect.FullName]bool))
+	sensitive.Store(md.FullName(), has)
+	return has
+}
+
+func hasSensitive(md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) bool {
+	if seen[md.FullName()] {
+		return false
+	}
+	seen[md.FullName()] = true
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if FieldMode(fd) != Mode_MODE_UNKNOWN {
+			return true
+		}
+		if fd.IsMap() {
+			fd = fd.MapValue()
+		}
+		if fd.Message() != nil && hasSensitive(fd.Message(), seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// Message returns `msg` with its sensitive fields redacted or hashed. If
+// there are none it's returned as is, otherwise a redacted copy is
+// returned and `msg` is unchanged.
+func Message(msg proto.Message) proto.Message {
+	if msg == nil || !HasSensitive(msg.ProtoReflect().Descriptor()) {
+		return msg
+	}
+	out := proto.Clone(msg)
+	redact(out.ProtoReflect())
+	return out
+}
+
+// redact changes sensitive fields of `m` in place.
+func redact(m protoreflect.Message) {
+	// Fields are collected first as they can't be changed during Range.
+	var fields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	for _, fd := range fields {
+		if mode := FieldMode(fd); mode != Mode_MODE_UNKNOWN {
+			redactField(m, fd, mode)
+			continue
+		}
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			l := m.Get(fd).List()
+			for i := 0; i < l.Len(); i++ {
+				redact(l.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			m.Get(fd).Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				redact(v.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			redact(m.Get(fd).Message())
+		}
+	}
+}
+
+// redactField replaces the value of `fd` in `m` according to `mode`.
+func redactField(m protoreflect.Message, fd protoreflect.FieldDescriptor, mode Mode) {
+	if fd.IsMap() || (fd.Kind() != protoreflect.StringKind && fd.Kind() != protoreflect.BytesKind) {
+		m.Clear(fd)
+		return
+	}
+	if !fd.IsList() {
+		m.Set(fd, value(fd, m.Get(fd), mode))
+		return
+	}
+	l := m.Mutable(fd).List()
+	for i := 0; i < l.Len(); i++ {
+		l.Set(i, value(fd, l.Get(i), mode))
+	}
+}
+
+// value returns the replacement for the string or bytes `v`.
+func value(fd protoreflect.FieldDescriptor, v protoreflect.Value, mode Mode) protoreflect.Value {
+	var b []byte
+	if fd.Kind() == protoreflect.StringKind {
+		b = []byte(v.String())
+	} else {
+		b = v.Bytes()
+	}
+	out := Redacted
+	if mode == Mode_MODE_HASH {
+		h := sha256.Sum256(b)
+		out = hex.EncodeToString(h[:])
+	}
+	if fd.Kind() == protoreflect.StringKind {
+		return protoreflect.ValueOfString(out)
+	}
+	return protoreflect.ValueOfBytes([]byte(out))
+}
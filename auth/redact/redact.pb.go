@@ -0,0 +1,188 @@
+// Copyright (c) 2019 Snowflake Inc. All rights reserved.
+//
+//Licensed under the Apache License, Version 2.0 (the
+//"License"); you may not use this file except in compliance
+//with the License.  You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing,
+//software distributed under the License is distributed on an
+//"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+//KIND, either express or implied.  See the License for the
+//specific language governing permissions and limitations
+//under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: redact.proto
+
+package redact
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Mode is how a sensitive field is treated.
+type Mode int32
+
+const (
+	// The field isn't sensitive.
+	Mode_MODE_UNKNOWN Mode = 0
+	// The value is replaced with REDACTED (or cleared if it isn't a string
+	// or bytes).
+	Mode_MODE_REDACT Mode = 1
+	// The value is replaced with its hex encoded SHA256 hash so it can still be matched
+	// against known values (or cleared if it isn't a string or bytes).
+	Mode_MODE_HASH Mode = 2
+)
+
+// Enum value maps for Mode.
+var (
+	Mode_name = map[int32]string{
+		0: "MODE_UNKNOWN",
+		1: "MODE_REDACT",
+		2: "MODE_HASH",
+	}
+	Mode_value = map[string]int32{
+		"MODE_UNKNOWN": 0,
+		"MODE_REDACT":  1,
+		"MODE_HASH":    2,
+	}
+)
+
+func (x Mode) Enum() *Mode {
+	p := new(Mode)
+	*p = x
+	return p
+}
+
+func (x Mode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Mode) Descriptor() protoreflect.EnumDescriptor {
+	return file_redact_proto_enumTypes[0].Descriptor()
+}
+
+func (Mode) Type() protoreflect.EnumType {
+	return &file_redact_proto_enumTypes[0]
+}
+
+func (x Mode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Mode.Descriptor instead.
+func (Mode) EnumDescriptor() ([]byte, []int) {
+	return file_redact_proto_rawDescGZIP(), []int{0}
+}
+
+var file_redact_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*Mode)(nil),
+		Field:         51820,
+		Name:          "Redact.sensitive",
+		Tag:           "varint,51820,opt,name=sensitive,enum=Redact.Mode",
+		Filename:      "redact.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// Marks a field as sensitive (i.e. passwords, tokens or file contents)
+	// so it's removed from policy input and audit logs:
+	//
+	//   bytes contents = 2 [(Redact.sensitive) = MODE_REDACT];
+	//
+	// optional Redact.Mode sensitive = 51820;
+	E_Sensitive = &file_redact_proto_extTypes[0]
+)
+
+var File_redact_proto protoreflect.FileDescriptor
+
+var file_redact_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x72, 0x65, 0x64, 0x61, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x52, 0x65, 0x64, 0x61, 0x63, 0x74, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2a, 0x38, 0x0a, 0x04, 0x4d, 0x6f, 0x64, 0x65,
+	0x12, 0x10, 0x0a, 0x0c, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
+	0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x52, 0x45, 0x44, 0x41, 0x43,
+	0x54, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x48, 0x41, 0x53, 0x48,
+	0x10, 0x02, 0x3a, 0x4b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x12,
+	0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xec,
+	0x94, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x52, 0x65, 0x64, 0x61, 0x63, 0x74, 0x2e,
+	0x4d, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x42,
+	0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x53, 0x6e,
+	0x6f, 0x77, 0x66, 0x6c, 0x61, 0x6b, 0x65, 0x2d, 0x4c, 0x61, 0x62, 0x73, 0x2f, 0x73, 0x61, 0x6e,
+	0x73, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x2f, 0x72, 0x65, 0x64, 0x61,
+	0x63, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_redact_proto_rawDescOnce sync.Once
+	file_redact_proto_rawDescData = file_redact_proto_rawDesc
+)
+
+func file_redact_proto_rawDescGZIP() []byte {
+	file_redact_proto_rawDescOnce.Do(func() {
+		file_redact_proto_rawDescData = protoimpl.X.CompressGZIP(file_redact_proto_rawDescData)
+	})
+	return file_redact_proto_rawDescData
+}
+
+var file_redact_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_redact_proto_goTypes = []interface{}{
+	(Mode)(0),                         // 0: Redact.Mode
+	(*descriptorpb.FieldOptions)(nil), // 1: google.protobuf.FieldOptions
+}
+var file_redact_proto_depIdxs = []int32{
+	1, // 0: Redact.sensitive:extendee -> google.protobuf.FieldOptions
+	0, // 1: Redact.sensitive:type_name -> Redact.Mode
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	1, // [1:2] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_redact_proto_init() }
+func file_redact_proto_init() {
+	if File_redact_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_redact_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_redact_proto_goTypes,
+		DependencyIndexes: file_redact_proto_depIdxs,
+		EnumInfos:         file_redact_proto_enumTypes,
+		ExtensionInfos:    file_redact_proto_extTypes,
+	}.Build()
+	File_redact_proto = out.File
+	file_redact_proto_rawDesc = nil
+	file_redact_proto_goTypes = nil
+	file_redact_proto_depIdxs = nil
+}
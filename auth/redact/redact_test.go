@@ -0,0 +1,185 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package redact_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Snowflake-Labs/sansshell/auth/redact"
+	"github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/services/process"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestMessage(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		msg  proto.Message
+		want proto.Message
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "no sensitive fields",
+			msg:  &localfile.ReadActionRequest{Request: &localfile.ReadActionRequest_File{File: &localfile.ReadRequest{Filename: "/etc/hosts"}}},
+			want: &localfile.ReadActionRequest{Request: &localfile.ReadActionRequest_File{File: &localfile.ReadRequest{Filename: "/etc/hosts"}}},
+		},
+		{
+			name: "contents",
+			msg:  &localfile.WriteRequest{Request: &localfile.WriteRequest_Contents{Contents: []byte("secret")}},
+			want: &localfile.WriteRequest{Request: &localfile.WriteRequest_Contents{Contents: []byte(redact.Redacted)}},
+		},
+		{
+			name: "unset sensitive field",
+			msg:  &localfile.CopyRequest{Bucket: "file:///tmp", Key: "file"},
+			want: &localfile.CopyRequest{Bucket: "file:///tmp", Key: "file"},
+		},
+		{
+			name: "nested",
+			msg: &process.GetMemoryDumpRequest{Pid: 1, Destination: &process.GetMemoryDumpRequest_Url{Url: &process.DumpDestinationUrl{
+				Url:      "s3://bucket",
+				BlobData: []byte("token"),
+			}}},
+			want: &process.GetMemoryDumpRequest{Pid: 1, Destination: &process.GetMemoryDumpRequest_Url{Url: &process.DumpDestinationUrl{
+				Url:      "s3://bucket",
+				BlobData: []byte(redact.Redacted),
+			}}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var orig proto.Message
+			if tc.msg != nil {
+				orig = proto.Clone(tc.msg)
+			}
+			got := redact.Message(tc.msg)
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("Message() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(orig, tc.msg, protocmp.Transform()); diff != "" {
+				t.Errorf("Message() changed its argument (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// testDescriptor returns a message type with fields of each kind marked
+// sensitive.
+func testDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	opts := func(m redact.Mode) *descriptorpb.FieldOptions {
+		o := &descriptorpb.FieldOptions{}
+		proto.SetExtension(o, redact.E_Sensitive, m)
+		return o
+	}
+	field := func(name string, num int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, o *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(num),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+			Options:  o,
+		}
+		if typ == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+			f.TypeName = proto.String(".Test.Secrets")
+		}
+		return f
+	}
+	optional, repeated := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("redact_test.proto"),
+		Package: proto.String("Test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Secrets"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("token", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, opts(redact.Mode_MODE_HASH)),
+				field("keys", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated, opts(redact.Mode_MODE_REDACT)),
+				field("pin", 3, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, opts(redact.Mode_MODE_REDACT)),
+				field("name", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, nil),
+				field("children", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, nil),
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	testutil.FatalOnErr("NewFile", err, t)
+	return fd.Messages().Get(0)
+}
+
+func TestModes(t *testing.T) {
+	md := testDescriptor(t)
+	if !redact.HasSensitive(md) {
+		t.Fatalf("HasSensitive(%s) = false, want true", md.FullName())
+	}
+	if redact.HasSensitive((&localfile.ReadRequest{}).ProtoReflect().Descriptor()) {
+		t.Errorf("HasSensitive(ReadRequest) = true, want false")
+	}
+
+	fields := md.Fields()
+	newSecrets := func(token string) *dynamicpb.Message {
+		m := dynamicpb.NewMessage(md)
+		m.Set(fields.ByName("token"), protoreflect.ValueOfString(token))
+		keys := m.Mutable(fields.ByName("keys")).List()
+		keys.Append(protoreflect.ValueOfString("a"))
+		keys.Append(protoreflect.ValueOfString("b"))
+		m.Set(fields.ByName("pin"), protoreflect.ValueOfInt64(1234))
+		m.Set(fields.ByName("name"), protoreflect.ValueOfString("name"))
+		return m
+	}
+	msg := newSecrets("parent")
+	msg.Mutable(fields.ByName("children")).List().Append(protoreflect.ValueOfMessage(newSecrets("child")))
+
+	got := redact.Message(msg).ProtoReflect()
+	hash := func(s string) string {
+		h := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(h[:])
+	}
+	check := func(m protoreflect.Message, token string) {
+		t.Helper()
+		if got, want := m.Get(fields.ByName("token")).String(), hash(token); got != want {
+			t.Errorf("token = %q, want %q", got, want)
+		}
+		keys := m.Get(fields.ByName("keys")).List()
+		if keys.Len() != 2 || keys.Get(0).String() != redact.Redacted || keys.Get(1).String() != redact.Redacted {
+			t.Errorf("keys weren't redacted")
+		}
+		if m.Has(fields.ByName("pin")) {
+			t.Errorf("pin = %d, want it cleared", m.Get(fields.ByName("pin")).Int())
+		}
+		if got := m.Get(fields.ByName("name")).String(); got != "name" {
+			t.Errorf("name = %q, want it unchanged", got)
+		}
+	}
+	check(got, "parent")
+	check(got.Get(fields.ByName("children")).List().Get(0).Message(), "child")
+	if msg.Get(fields.ByName("token")).String() != "parent" {
+		t.Errorf("Message() changed its argument")
+	}
+}
@@ -16,16 +16,77 @@
 
 // Package client provides utility functions for gluing new commands
 // easily into sanssh.
+//
+// Each service's client package registers a top level command for the
+// service from init() with RegisterSubpackage, so importing it (sanssh
+// imports every package matching services/*/client) is all that's needed to
+// make its commands available.
 package client
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/google/subcommands"
 )
 
+var (
+	subpackageMu sync.Mutex
+	subpackages  = make(map[string]bool)
+)
+
+// RegisterSubpackage registers a top level command `name` with
+// subcommands.DefaultCommander whose subcommands are those setup registers
+// in the Commander it returns (see SetupSubpackage). setup is called each
+// time the command is used, so it should register new instances of them.
+// It's typically called from init() and panics if name is registered twice.
+func RegisterSubpackage(name string, setup func(*flag.FlagSet) *subcommands.Commander) {
+	subpackageMu.Lock()
+	defer subpackageMu.Unlock()
+	if subpackages[name] {
+		panic("duplicate registration of sanssh subpackage " + name)
+	}
+	subpackages[name] = true
+	subcommands.Register(&subpackageCmd{name: name, setup: setup}, name)
+}
+
+// Subpackages returns the names of all registered subpackages as a sorted
+// list.
+func Subpackages() []string {
+	subpackageMu.Lock()
+	defer subpackageMu.Unlock()
+	var out []string
+	for name := range subpackages {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// subpackageCmd is the top level command of a subpackage.
+type subpackageCmd struct {
+	name  string
+	setup func(*flag.FlagSet) *subcommands.Commander
+}
+
+func (p *subpackageCmd) Name() string { return p.name }
+func (p *subpackageCmd) Synopsis() string {
+	return GenerateSynopsis(p.setup(flag.NewFlagSet("", flag.ContinueOnError)))
+}
+func (p *subpackageCmd) Usage() string {
+	return GenerateUsage(p.name, p.Synopsis())
+}
+func (*subpackageCmd) SetFlags(f *flag.FlagSet) {}
+
+func (p *subpackageCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	c := p.setup(f)
+	return c.Execute(ctx, args...)
+}
+
 // SetupSubpackage is a helper to create a Commander to hold the actual
 // commands run inside of a top-level command. The returned Commander should
 // then have the relevant sub-commands registered within it.
@@ -13,6 +13,13 @@ allow {
 	input.method = "/Proxy.Proxy/Proxy"
 }
 
+# Allow anyone to ask the proxy to copy files between targets. The
+# LocalFile.Read and LocalFile.Write calls it makes are authorized
+# as if made by the caller.
+allow {
+	input.method = "/LocalFile.Transfer/CopyFile"
+}
+
 # Allow people to run reflection against the proxy
 allow {
 	input.method = "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"
@@ -25,6 +32,12 @@ allow {
 	input.method = "/HealthCheck.HealthCheck/Ok"
 }
 
+# Allow anyone to ask any host (or the proxy) what it supports, which
+# sanssh --skip-unimplemented relies on
+allow {
+	input.method = "/Sansshell.Capabilities/GetCapabilities"
+}
+
 # Allow anyone to read /etc/hosts on any host
 allow {
 	input.method = "/LocalFile.LocalFile/Read"
@@ -24,25 +24,59 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/Snowflake-Labs/sansshell/auth/delegation"
+	"github.com/Snowflake-Labs/sansshell/auth/enroll"
+	"github.com/Snowflake-Labs/sansshell/auth/groups"
+	"github.com/Snowflake-Labs/sansshell/auth/inspect"
 	"github.com/Snowflake-Labs/sansshell/auth/mtls"
 	mtlsFlags "github.com/Snowflake-Labs/sansshell/auth/mtls/flags"
 	"github.com/Snowflake-Labs/sansshell/auth/opa"
 	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/auth/proxyhint"
 	"github.com/Snowflake-Labs/sansshell/cmd/proxy-server/server"
 	"github.com/Snowflake-Labs/sansshell/cmd/util"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/stdr"
 
+	// Group providers, selectable with --groups-provider.
+	_ "github.com/Snowflake-Labs/sansshell/auth/groups/file"
+	_ "github.com/Snowflake-Labs/sansshell/auth/groups/google"
+	_ "github.com/Snowflake-Labs/sansshell/auth/groups/ldap"
+
+	// Additional credential sources, selectable with --credential-source.
+	// The pkcs11 source needs cgo so it's imported in pkcs11.go.
+	_ "github.com/Snowflake-Labs/sansshell/auth/mtls/gcpkms"
+	_ "github.com/Snowflake-Labs/sansshell/auth/mtls/kubernetes"
+	_ "github.com/Snowflake-Labs/sansshell/auth/mtls/vault"
+
 	// Import services here to make them proxy-able
 	_ "github.com/Snowflake-Labs/sansshell/services/ansible"
+	_ "github.com/Snowflake-Labs/sansshell/services/approvals"
+	_ "github.com/Snowflake-Labs/sansshell/services/certs"
+	_ "github.com/Snowflake-Labs/sansshell/services/configdeploy"
+	_ "github.com/Snowflake-Labs/sansshell/services/cron"
 	_ "github.com/Snowflake-Labs/sansshell/services/exec"
+	_ "github.com/Snowflake-Labs/sansshell/services/fdb"
+	_ "github.com/Snowflake-Labs/sansshell/services/firewall"
 	_ "github.com/Snowflake-Labs/sansshell/services/healthcheck"
+	_ "github.com/Snowflake-Labs/sansshell/services/httpoverrpc"
+	_ "github.com/Snowflake-Labs/sansshell/services/ipmi"
+	_ "github.com/Snowflake-Labs/sansshell/services/k8snode"
 	_ "github.com/Snowflake-Labs/sansshell/services/localfile"
+	_ "github.com/Snowflake-Labs/sansshell/services/mac"
+	_ "github.com/Snowflake-Labs/sansshell/services/network"
 	_ "github.com/Snowflake-Labs/sansshell/services/packages"
+	_ "github.com/Snowflake-Labs/sansshell/services/policy"
+	_ "github.com/Snowflake-Labs/sansshell/services/power"
 	_ "github.com/Snowflake-Labs/sansshell/services/process"
 	_ "github.com/Snowflake-Labs/sansshell/services/sansshell/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/scripts"
 	_ "github.com/Snowflake-Labs/sansshell/services/service"
+	_ "github.com/Snowflake-Labs/sansshell/services/sysctl"
+	_ "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	_ "github.com/Snowflake-Labs/sansshell/services/users"
 )
 
 var (
@@ -50,12 +84,47 @@ var (
 	defaultPolicy string
 
 	policyFlag    = flag.String("policy", defaultPolicy, "Local OPA policy governing access.  If empty, use builtin policy.")
-	policyFile    = flag.String("policy-file", "", "Path to a file with an OPA policy.  If empty, uses --policy.")
+	policyFile    = flag.String("policy-file", "", "Path to a file with an OPA policy.  If empty, uses --policy. The file is watched and the policy reloaded when it changes.")
+	policyKey     = flag.String("policy-public-key", "", "If set, a file with the public key (or certificate) the policy from --policy-file must be signed with. The detached signature is read from the same location with .sig appended. Changed policies with an invalid signature are rejected.")
+	policyData    = flag.String("policy-data", "", "Comma separated list of name=source entries of JSON data documents (i.e. oncall=https://example.com/oncall.json) available to the policy as data.external.<name>. Each source is a file or HTTP(S) URL.")
+	policyDataRef = flag.Duration("policy-data-refresh", opa.DefaultDataRefreshInterval, "How often to reload --policy-data documents.")
 	hostport      = flag.String("hostport", "localhost:50043", "Where to listen for connections.")
 	credSource    = flag.String("credential-source", mtlsFlags.Name(), fmt.Sprintf("Method used to obtain mTLS creds (one of [%s])", strings.Join(mtls.Loaders(), ",")))
+	tlsMinVersion = flag.String("tls-min-version", "1.3", "Minimum TLS version to negotiate (1.2 or 1.3).")
+	tlsCiphers    = flag.String("tls-cipher-suites", "", "Comma separated list of allowed TLS 1.2 cipher suites (i.e. TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384). If empty Go's defaults are used.")
+	tlsCurves     = flag.String("tls-curves", "", "Comma separated list of key exchange curves in preference order (X25519, P256, P384, P521). If empty Go's defaults are used.")
+	backendSANs   = flag.String("backend-sans", "", "Comma separated list of target=san patterns. Backends dialed with a name matching target must present a certificate with a SAN (i.e. a SPIFFE ID) matching one of the sans given for it, i.e. *.db.example.com=spiffe://example.com/db/*")
 	verbosity     = flag.Int("v", 0, "Verbosity level. > 0 indicates more extensive logging")
 	validate      = flag.Bool("validate", false, "If true will evaluate the policy and then exit (non-zero on error)")
+	auditFile     = flag.String("audit-file", "", "If set, append a JSON record of every authorization decision to this file.")
+	auditSyslog   = flag.String("audit-syslog-tag", "", "If set, send a JSON record of every authorization decision to syslog (LOG_AUTHPRIV) with this tag.")
+	decisionLog   = flag.String("decision-log-url", "", "If set, upload a record of every authorization decision in OPA decision log format to this URL (i.e. https://example.com/logs).")
+	decisionToken = flag.String("decision-log-token-file", "", "File containing a bearer token for --decision-log-url.")
+	authzCacheTTL = flag.Duration("authz-cache-ttl", 0, "If non-zero, cache allow decisions for identical requests for this long. The cache is flushed when the policy changes.")
+	streamReauth  = flag.Duration("authz-stream-reauth-interval", 0, "If non-zero, re-evaluate the policy for open streams this often, ending those no longer allowed. Every message received on a stream is always authorized.")
+	rateLimits    = flag.Bool("authz-rate-limits", false, "If true, enforce per caller rate limits set by the policy's rate_limit rule (i.e. rate_limit = {\"rps\": 2, \"burst\": 5}).")
+	metricsAddr   = flag.String("metrics-addr", "", "If set, serve Prometheus metrics (including authorization decision counts and latency) at /metrics on this host:port.")
+	authzDryRun   = flag.Bool("authz-dry-run", false, "If true, requests denied by the policy are logged and audited but still permitted. For soaking a new policy before enforcing it.")
 	justification = flag.Bool("justification", false, "If true then justification (which is logged and possibly validated) must be passed along in the client context Metadata with the key '"+rpcauth.ReqJustKey+"'")
+	justFormat    = flag.String("justification-format", "", "If set, a regular expression (i.e. a ticket ID pattern) justifications must match. Requests with a non-matching justification are rejected. Policy can require a justification for specific methods with input.justification.")
+	oidcIssuers   = flag.String("oidc-issuers", "", "Comma separated list of OIDC issuer URLs whose ID tokens are accepted as bearer tokens. Token claims are available to policy as input.peer.token.")
+	oidcAudience  = flag.String("oidc-audience", "sansshell", "Audience OIDC bearer tokens must be issued for.")
+	oidcRequired  = flag.Bool("oidc-required", false, "If true RPCs without a valid OIDC bearer token are rejected.")
+	groupsProv    = flag.String("groups-provider", "", fmt.Sprintf("If set, the provider used to look up the groups of the principal for policy input.peer.principal.groups (one of [%s])", strings.Join(groups.Providers(), ",")))
+	groupsTTL     = flag.Duration("groups-cache-ttl", 5*time.Minute, "How long to cache group lookups for. Zero disables caching.")
+	grantsSource  = flag.String("grants-source", "", "File or HTTP(S) URL of a JSON document of time limited grants (principal -> method -> RFC 3339 expiry). The caller's unexpired grants are available to policy as input.grants.")
+	grantsRefresh = flag.Duration("grants-refresh", time.Minute, "How often to reload --grants-source.")
+	hintKey       = flag.String("decision-hint-key", "", "If set, a PEM private key file used to sign a summary of the proxy's authorization sent to targets, for use by their policies.")
+	hintIssuer    = flag.String("decision-hint-issuer", "", "Name of this proxy in decision hints. Defaults to the hostname.")
+	hintTTL       = flag.Duration("decision-hint-ttl", proxyhint.DefaultTTL, "How long decision hints are valid for. Requests on longer lived streams will be rejected by targets requiring hints.")
+	delegKey      = flag.String("delegation-key", "", "If set, a PEM private key file used to sign short lived tokens sent to targets binding the caller, target and method, so targets can verify who the proxy is acting for.")
+	delegIssuer   = flag.String("delegation-issuer", "", "Name of this proxy in delegation tokens. Defaults to the hostname.")
+	delegTTL      = flag.Duration("delegation-ttl", delegation.DefaultTTL, "How long delegation tokens are valid for. Requests on longer lived streams will be rejected by targets requiring tokens.")
+	enrollAddr    = flag.String("enroll-hostport", "", "If set, serve certificate enrollment over HTTPS on this host:port. Callers with a valid --oidc-issuers token are issued a short lived client certificate for their identity.")
+	enrollCACert  = flag.String("enroll-ca-cert", "", "PEM CA certificate used to sign enrolled client certificates. Servers must trust it for clients (i.e. include it in --root-ca).")
+	enrollCAKey   = flag.String("enroll-ca-key", "", "PEM private key of --enroll-ca-cert.")
+	enrollTTL     = flag.Duration("enroll-ttl", enroll.DefaultTTL, "How long enrolled client certificates are valid for.")
+	pluginDescs   = flag.String("plugin-descriptors", "", "Comma separated list of files containing FileDescriptorSets (i.e. from protoc --include_imports --descriptor_set_out) of plugin services to proxy.")
 )
 
 func main() {
@@ -67,8 +136,17 @@ func main() {
 
 	policy := util.ChoosePolicy(logger, defaultPolicy, *policyFlag, *policyFile)
 	ctx := logr.NewContext(context.Background(), logger)
+	if *policyKey != "" && *policyFile == "" {
+		log.Fatal("--policy-public-key requires --policy-file")
+	}
+	verifier := util.PolicyVerifier(logger, *policyKey)
 
 	if *validate {
+		if verifier != nil {
+			if err := verifier.VerifyPolicy(ctx, *policyFile, []byte(policy)); err != nil {
+				log.Fatalf("Invalid policy signature: %v\n", err)
+			}
+		}
 		_, err := opa.NewAuthzPolicy(ctx, policy)
 		if err != nil {
 			log.Fatalf("Invalid policy: %v\n", err)
@@ -78,11 +156,32 @@ func main() {
 	}
 
 	rs := server.RunState{
-		Logger:        logger,
-		Policy:        policy,
-		CredSource:    *credSource,
-		Hostport:      *hostport,
-		Justification: *justification,
+		Logger:              logger,
+		Policy:              policy,
+		PolicyFile:          *policyFile,
+		PolicyVerifier:      verifier,
+		PolicyData:          util.PolicyData(logger, *policyData),
+		PolicyDataRefresh:   *policyDataRef,
+		CredSource:          *credSource,
+		TLSOptions:          util.TLSOptions(logger, *tlsMinVersion, *tlsCiphers, *tlsCurves),
+		BackendSANs:         util.BackendSANs(logger, *backendSANs),
+		Hostport:            *hostport,
+		Justification:       *justification,
+		JustificationFormat: util.JustificationFormat(logger, *justFormat),
+		AuditSinks:          util.AuditSinks(logger, *auditFile, *auditSyslog, *decisionLog, *decisionToken),
+		AuthzCacheTTL:       *authzCacheTTL,
+		AuthzDryRun:         *authzDryRun,
+		AuthzStreamReauth:   *streamReauth,
+		AuthzRateLimits:     *rateLimits,
+		AuthzMetrics:        util.AuthzMetrics(ctx, logger, *metricsAddr),
+		DecisionHints:       util.DecisionHintSigner(logger, *hintKey, *hintIssuer, *hintTTL),
+		Delegation:          util.DelegationMinter(logger, *delegKey, *delegIssuer, *delegTTL),
 	}
-	server.Run(ctx, rs)
+	util.Enrollment(ctx, logger, *credSource, *enrollAddr, *enrollCACert, *enrollCAKey, *enrollTTL, *oidcIssuers, *oidcAudience)
+	hooks := util.OIDCHooks(logger, *oidcIssuers, *oidcAudience, *oidcRequired)
+	hooks = append(hooks, util.GroupHooks(logger, *groupsProv, *groupsTTL)...)
+	hooks = append(hooks, util.GrantsHooks(ctx, logger, *grantsSource, *grantsRefresh)...)
+	hooks = append(hooks, inspect.Hook())
+	util.PluginDescriptors(logger, *pluginDescs)
+	server.Run(ctx, rs, hooks...)
 }
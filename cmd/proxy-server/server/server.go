@@ -22,12 +22,20 @@ package server
 
 import (
 	"context"
+	"errors"
 	"net"
+	"net/http"
 	"os"
+	"regexp"
+	"time"
 
+	"github.com/Snowflake-Labs/sansshell/auth/delegation"
 	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+	"github.com/Snowflake-Labs/sansshell/auth/opa"
 	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/auth/proxyhint"
 	"github.com/Snowflake-Labs/sansshell/proxy/server"
+	"github.com/Snowflake-Labs/sansshell/services/localfile/transfer"
 	ss "github.com/Snowflake-Labs/sansshell/services/sansshell/server"
 	"github.com/Snowflake-Labs/sansshell/telemetry"
 	"github.com/go-logr/logr"
@@ -42,8 +50,28 @@ type RunState struct {
 	Logger logr.Logger
 	// Policy is an OPA policy for determining authz decisions.
 	Policy string
+	// PolicyFile if set is the file Policy was read from. It will be watched
+	// for changes and the policy reloaded when it's updated.
+	PolicyFile string
+	// PolicyVerifier if set must accept the policy from PolicyFile (and
+	// any later changes to it) before it's used. It's an error to set it
+	// without PolicyFile.
+	PolicyVerifier opa.PolicyVerifier
+	// PolicyData are external JSON documents (i.e. an on-call roster) keyed
+	// by name, each a file or HTTP(S) URL. They're available to the policy
+	// as data.external.<name> and reloaded every PolicyDataRefresh.
+	PolicyData map[string]string
+	// PolicyDataRefresh is how often to reload PolicyData. If unset
+	// opa.DefaultDataRefreshInterval is used.
+	PolicyDataRefresh time.Duration
 	// CredSource is a registered credential source with the mtls package.
 	CredSource string
+	// TLSOptions adjust the TLS versions, cipher suites and curves used
+	// with the credentials from CredSource.
+	TLSOptions []mtls.TLSOption
+	// BackendSANs if set are the identities backend servers must present,
+	// by target. See mtls.WithExpectedSANs.
+	BackendSANs []mtls.SANRule
 	// Hostport is the host:port to run the server.
 	Hostport string
 	// Justification if true requires justification to be set in the
@@ -53,18 +81,47 @@ type RunState struct {
 	// entry is found. The supplied function can then do any validation it wants
 	// in order to ensure it's compliant.
 	JustificationFunc func(string) error
+	// JustificationFormat if set rejects requests with a justification
+	// which doesn't match it. See rpcauth.JustificationFormatHook.
+	JustificationFormat *regexp.Regexp
+	// AuditSinks receive a record of every authorization decision.
+	AuditSinks []rpcauth.AuditSink
+	// AuthzCacheTTL if non-zero caches allow decisions for this long.
+	// See rpcauth.DecisionCache.
+	AuthzCacheTTL time.Duration
+	// AuthzStreamReauth if non-zero re-authorizes open streams this often.
+	// See rpcauth.ReauthorizeStreams.
+	AuthzStreamReauth time.Duration
+	// AuthzDryRun if true logs and audits policy denials without enforcing
+	// them. See rpcauth.DryRun.
+	AuthzDryRun bool
+	// AuthzRateLimits if true enforces rate limits set by the policy.
+	// See rpcauth.RateLimits.
+	AuthzRateLimits bool
+	// AuthzMetrics if set records every authorization decision.
+	AuthzMetrics rpcauth.Metrics
+	// DecisionHints if set signs a summary of the proxy's authorization
+	// sent with each stream to targets. See the proxyhint package.
+	DecisionHints *proxyhint.Signer
+	// Delegation if set mints a token sent with each stream to targets
+	// binding the caller, target and method. See the delegation package.
+	Delegation *delegation.Minter
 }
 
 // Run takes the given context and RunState along with any authz hooks and starts up a sansshell proxy server
 // using the flags above to provide credentials. An address hook (based on the remote host) with always be added.
 // As this is intended to be called from main() it doesn't return errors and will instead exit on any errors.
 func Run(ctx context.Context, rs RunState, hooks ...rpcauth.RPCAuthzHook) {
-	serverCreds, err := mtls.LoadServerCredentials(ctx, rs.CredSource)
+	serverCreds, err := mtls.LoadServerCredentials(ctx, rs.CredSource, rs.TLSOptions...)
 	if err != nil {
 		rs.Logger.Error(err, "mtls.LoadServerCredentials", "credsource", rs.CredSource)
 		os.Exit(1)
 	}
-	clientCreds, err := mtls.LoadClientCredentials(ctx, rs.CredSource)
+	clientOpts := rs.TLSOptions
+	if len(rs.BackendSANs) > 0 {
+		clientOpts = append(clientOpts[:len(clientOpts):len(clientOpts)], mtls.WithExpectedSANs(rs.BackendSANs...))
+	}
+	clientCreds, err := mtls.LoadClientCredentials(ctx, rs.CredSource, clientOpts...)
 	if err != nil {
 		rs.Logger.Error(err, "mtls.LoadClientCredentials", "credsource", rs.CredSource)
 		os.Exit(1)
@@ -85,12 +142,67 @@ func Run(ctx context.Context, rs RunState, hooks ...rpcauth.RPCAuthzHook) {
 	})
 
 	h := []rpcauth.RPCAuthzHook{addressHook, justificationHook}
+	if rs.JustificationFormat != nil {
+		h = append(h, rpcauth.JustificationFormatHook(rs.JustificationFormat))
+	}
 	h = append(h, hooks...)
-	authz, err := rpcauth.NewWithPolicy(ctx, rs.Policy, h...)
+	for _, s := range rs.AuditSinks {
+		h = append(h, rpcauth.AuditHook(s))
+	}
+	if rs.AuthzCacheTTL > 0 {
+		h = append(h, rpcauth.DecisionCache(rs.AuthzCacheTTL, 0))
+	}
+	if rs.AuthzStreamReauth > 0 {
+		h = append(h, rpcauth.ReauthorizeStreams(rs.AuthzStreamReauth))
+	}
+	if rs.AuthzDryRun {
+		rs.Logger.Info("authz dry run: policy denials will be logged but not enforced")
+		h = append(h, rpcauth.DryRun())
+	}
+	if rs.AuthzRateLimits {
+		h = append(h, rpcauth.RateLimits())
+	}
+	if rs.AuthzMetrics != nil {
+		h = append(h, rpcauth.MetricsHook(rs.AuthzMetrics))
+	}
+	if rs.PolicyVerifier != nil {
+		if rs.PolicyFile == "" {
+			rs.Logger.Error(errors.New("only policies from a file can be verified"), "policy verification")
+			os.Exit(1)
+		}
+		if err := rs.PolicyVerifier.VerifyPolicy(ctx, rs.PolicyFile, []byte(rs.Policy)); err != nil {
+			rs.Logger.Error(err, "policy verification", "file", rs.PolicyFile)
+			os.Exit(1)
+		}
+	}
+	authzPolicy, err := opa.NewAuthzPolicy(ctx, rs.Policy)
 	if err != nil {
-		rs.Logger.Error(err, "rpcauth.NewWithPolicy")
+		rs.Logger.Error(err, "opa.NewAuthzPolicy")
 		os.Exit(1)
 	}
+	if len(rs.PolicyData) > 0 {
+		client := &http.Client{Timeout: 30 * time.Second}
+		refresh := rs.PolicyDataRefresh
+		if refresh == 0 {
+			refresh = opa.DefaultDataRefreshInterval
+		}
+		for name, source := range rs.PolicyData {
+			doc, err := opa.LoadData(ctx, client, source)
+			if err != nil {
+				rs.Logger.Error(err, "opa.LoadData", "data", name, "source", source)
+				os.Exit(1)
+			}
+			if err := authzPolicy.SetData(ctx, name, doc); err != nil {
+				rs.Logger.Error(err, "SetData", "data", name)
+				os.Exit(1)
+			}
+			go opa.PollData(ctx, client, name, source, refresh, authzPolicy)
+		}
+	}
+	if rs.PolicyFile != "" {
+		go opa.WatchFileVerified(ctx, rs.PolicyFile, opa.DefaultWatchInterval, authzPolicy, rs.PolicyVerifier)
+	}
+	authz := rpcauth.New(authzPolicy, h...)
 
 	dialOpts := []grpc.DialOption{
 		grpc.WithTransportCredentials(clientCreds),
@@ -100,7 +212,14 @@ func Run(ctx context.Context, rs RunState, hooks ...rpcauth.RPCAuthzHook) {
 
 	svcMap := server.LoadGlobalServiceMap()
 	rs.Logger.Info("loaded service map", "serviceMap", svcMap)
-	server := server.New(targetDialer, authz)
+	var proxyOpts []server.Option
+	if rs.DecisionHints != nil {
+		proxyOpts = append(proxyOpts, server.WithDecisionHints(rs.DecisionHints))
+	}
+	if rs.Delegation != nil {
+		proxyOpts = append(proxyOpts, server.WithDelegation(rs.Delegation))
+	}
+	server := server.New(targetDialer, authz, proxyOpts...)
 
 	serverOpts := []grpc.ServerOption{
 		grpc.Creds(serverCreds),
@@ -116,6 +235,8 @@ func Run(ctx context.Context, rs RunState, hooks ...rpcauth.RPCAuthzHook) {
 	// Create a an instance of logging for the proxy server itself.
 	s := &ss.Server{}
 	s.Register(g)
+	// Copies between targets are also run by the proxy.
+	transfer.New(server).Register(g)
 	rs.Logger.Info("initialized proxy service", "credsource", rs.CredSource)
 	rs.Logger.Info("serving..")
 
@@ -0,0 +1,87 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	pb "github.com/Snowflake-Labs/sansshell/services/sansshell"
+)
+
+const getCapabilitiesMethod = "/Sansshell.Capabilities/GetCapabilities"
+
+// capabilityFilter returns a proxy.MethodFilter which asks each target for
+// its capabilities (once) and reports whether it implements the method.
+// Targets whose capabilities can't be fetched (i.e. older servers) are
+// assumed to implement everything. Skipped targets are noted in their
+// entry of `errs`.
+func capabilityFilter(conn *proxy.Conn, errs []io.Writer) proxy.MethodFilter {
+	var once sync.Once
+	// Indexed like conn.Targets. A nil entry means the methods are unknown.
+	var methods []map[string]bool
+	return func(ctx context.Context, method string) []bool {
+		if method == getCapabilitiesMethod {
+			return nil
+		}
+		once.Do(func() { methods = fetchMethods(ctx, conn) })
+		implements := make([]bool, len(conn.Targets))
+		some := false
+		for i := range implements {
+			implements[i] = methods[i] == nil || methods[i][method]
+			some = some || implements[i]
+		}
+		if !some {
+			// Let the call fail everywhere so the errors are reported.
+			return nil
+		}
+		for i, ok := range implements {
+			if !ok {
+				fmt.Fprintf(errs[i], "Skipping target %s (%d) as it doesn't implement %s\n", conn.Targets[i], i, method)
+			}
+		}
+		return implements
+	}
+}
+
+// fetchMethods returns the set of methods each of conn.Targets implements,
+// or nil entries where they couldn't be determined.
+func fetchMethods(ctx context.Context, conn *proxy.Conn) []map[string]bool {
+	out := make([]map[string]bool, len(conn.Targets))
+	respChan, err := pb.NewCapabilitiesClientProxy(conn).GetCapabilitiesOneMany(ctx, &emptypb.Empty{})
+	if err != nil {
+		return out
+	}
+	for r := range respChan {
+		if r.Error != nil {
+			continue
+		}
+		m := make(map[string]bool)
+		for _, s := range r.Resp.Services {
+			for _, method := range s.Methods {
+				m[method] = true
+			}
+		}
+		out[r.Index] = m
+	}
+	return out
+}
@@ -22,11 +22,14 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+	"github.com/Snowflake-Labs/sansshell/auth/oidc"
 	"github.com/Snowflake-Labs/sansshell/proxy/proxy"
 	"github.com/google/subcommands"
 	"google.golang.org/grpc"
@@ -46,11 +49,16 @@ type RunState struct {
 	// Proxy is an optional proxy server to route requests.
 	Proxy string
 	// Targets is a list of remote targets to use when a proxy
-	// is in use. For non proxy must be 1 entry.
+	// is in use. For non proxy must be 1 entry. Entries may also be
+	// @groups and @files, see ExpandTargets.
 	Targets []string
+	// TargetGroups if set is a file defining named groups of targets,
+	// see LoadTargetGroups.
+	TargetGroups string
 	// Outputs must map 1:1 with Targets indicating where to emit
 	// output from commands. If the list is empty or a single entry
 	// set to - then stdout/stderr will be used for all outputs.
+	// Outputs of @groups and @files are expanded, see ExpandOutputs.
 	Outputs []string
 	// OutputsDir defines a directory to place outputs instead of
 	// specifying then in Outputs. The files will be names 0.output,
@@ -58,17 +66,86 @@ type RunState struct {
 	OutputsDir string
 	// CredSource is a registered credential source with the mtls package.
 	CredSource string
+	// TLSOptions adjust the TLS versions, cipher suites and curves used
+	// with the credentials from CredSource.
+	TLSOptions []mtls.TLSOption
 	// Timeout is the duration to place on the context when making RPC calls.
 	Timeout time.Duration
+	// TokenFile if set is a file containing an OIDC ID token to send as a
+	// bearer token with every RPC. It's re-read for each RPC so external
+	// tooling can refresh it.
+	TokenFile string
+	// ThrottleRetries is how many times to retry calls to targets which
+	// rate limit them, waiting as long as asked up to MaxRetryDelay.
+	// See proxy.Conn.SetThrottleRetries.
+	ThrottleRetries int
+	// MaxRetryDelay is the longest a target may ask to wait before a
+	// throttled call is retried.
+	MaxRetryDelay time.Duration
+	// SkipUnimplemented if set asks targets for their capabilities first
+	// and skips those which don't implement the method being called.
+	// Only used with a proxy. See proxy.Conn.SetMethodFilter.
+	SkipUnimplemented bool
+	// Format is how output is written, one of Formats(). FormatRaw (or
+	// empty) is the output of the command itself. The others are structured
+	// records of every response and error written to stdout instead, which
+	// can't be combined with Outputs or OutputsDir.
+	Format string
+	// MaxInflight if non-zero limits how many targets are called at once.
+	// See proxy.Conn.SetMaxInflight.
+	MaxInflight int
+	// Progress if set displays on stderr how many targets have finished
+	// each call. Only used with a proxy.
+	Progress bool
 }
 
 const (
 	defaultOutput = "-"
+
+	// progressInterval is how often the progress display is redrawn.
+	progressInterval = 500 * time.Millisecond
 )
 
 // Run takes the given context and RunState and executes the command passed in after parsing with flags.
 // As this is intended to be called from main() it doesn't return errors and will instead exit on any errors.
 func Run(ctx context.Context, rs RunState) {
+	var groups map[string][]string
+	if rs.TargetGroups != "" {
+		var err error
+		groups, err = LoadTargetGroups(rs.TargetGroups)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Can't load target groups: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	targets, err := ExpandTargets(rs.Targets, groups)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid targets: %v\n", err)
+		os.Exit(1)
+	}
+	// Outputs map to the targets as given, so expand them the same way
+	// unless everything is going to stdout/stderr.
+	if len(rs.Outputs) > 1 || (len(rs.Outputs) == 1 && rs.Outputs[0] != defaultOutput) {
+		rs.Outputs, err = ExpandOutputs(rs.Targets, rs.Outputs, groups)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid outputs: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	rs.Targets = targets
+
+	var results *formatter
+	if rs.Format != "" && rs.Format != FormatRaw {
+		if rs.OutputsDir != "" || len(rs.Outputs) > 1 || (len(rs.Outputs) == 1 && rs.Outputs[0] != defaultOutput) {
+			fmt.Fprintf(os.Stderr, "Can't set outputs or output-dir with format %s\n", rs.Format)
+			os.Exit(1)
+		}
+		results, err = newFormatter(rs.Format, os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
 
 	// Bunch of flag sanity checking
 	if len(rs.Targets) == 0 {
@@ -124,14 +201,22 @@ func Run(ctx context.Context, rs RunState) {
 			}
 		}
 	}
-	creds, err := mtls.LoadClientCredentials(ctx, rs.CredSource)
+	creds, err := mtls.LoadClientCredentials(ctx, rs.CredSource, rs.TLSOptions...)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Could not load creds from %s - %v\n", rs.CredSource, err)
 		os.Exit(1)
 	}
 
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if rs.TokenFile != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(oidc.NewPerRPCCredentials(func() (string, error) {
+			b, err := os.ReadFile(rs.TokenFile)
+			return strings.TrimSpace(string(b)), err
+		})))
+	}
+
 	// Set up a connection to the sansshell-server (possibly via proxy).
-	conn, err := proxy.Dial(rs.Proxy, rs.Targets, grpc.WithTransportCredentials(creds))
+	conn, err := proxy.Dial(rs.Proxy, rs.Targets, opts...)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Could not connect to proxy %q node(s) %v: %v\n", rs.Proxy, rs.Targets, err)
 		os.Exit(1)
@@ -141,6 +226,8 @@ func Run(ctx context.Context, rs RunState) {
 			fmt.Fprintf(os.Stderr, "error closing connection - %v\n", err)
 		}
 	}()
+	conn.SetThrottleRetries(rs.ThrottleRetries, rs.MaxRetryDelay)
+	conn.SetMaxInflight(rs.MaxInflight)
 
 	state := &util.ExecuteState{
 		Conn: conn,
@@ -168,11 +255,46 @@ func Run(ctx context.Context, rs RunState) {
 		state.Out = append(state.Out, file)
 		state.Err = append(state.Err, errF)
 	}
+	if rs.SkipUnimplemented && !conn.Direct() {
+		conn.SetMethodFilter(capabilityFilter(conn, state.Err))
+	}
+	var resultFuncs []proxy.ResultFunc
+	if results != nil {
+		// The records replace the command's own output. Errors are still
+		// written to stderr.
+		resultFuncs = append(resultFuncs, results.result)
+		for i := range state.Out {
+			state.Out[i] = io.Discard
+		}
+	}
+	var progress *fanoutProgress
+	if rs.Progress && !conn.Direct() {
+		progress = newFanoutProgress(os.Stderr, len(rs.Targets))
+		resultFuncs = append(resultFuncs, progress.result)
+		progress.run(progressInterval)
+	}
+	if len(resultFuncs) > 0 {
+		conn.SetResultFunc(func(r proxy.Result) {
+			for _, f := range resultFuncs {
+				f(r)
+			}
+		})
+	}
 
 	ctx, cancel := context.WithTimeout(ctx, rs.Timeout)
 	defer cancel()
 
 	// Invoke the subcommand, passing the dialed connection object
 	// TODO(jchacon): Pass a struct instead of 3 args.
-	os.Exit(int(subcommands.Execute(ctx, state)))
+	exit := subcommands.Execute(ctx, state)
+	if progress != nil {
+		progress.finish()
+	}
+	if results != nil {
+		if err := results.flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "Can't write output: %v\n", err)
+			exit = subcommands.ExitFailure
+		}
+	}
+	os.Exit(int(exit))
 }
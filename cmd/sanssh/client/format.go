@@ -0,0 +1,175 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/ghodss/yaml"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/Snowflake-Labs/sansshell/proxy/proxy"
+)
+
+// Output formats, see RunState.Format.
+const (
+	// FormatRaw is the output of each command as it writes it.
+	FormatRaw = "raw"
+	// FormatJSON is a JSON object per line for each response or error.
+	FormatJSON = "json"
+	// FormatYAML is a YAML document for each response or error.
+	FormatYAML = "yaml"
+	// FormatTable is a table with a row for each response or error.
+	FormatTable = "table"
+)
+
+// Formats returns the valid output formats.
+func Formats() []string {
+	return []string{FormatRaw, FormatJSON, FormatYAML, FormatTable}
+}
+
+// record is a single response, or final status, from a target as output
+// by the structured formats.
+type record struct {
+	Target string `json:"target"`
+	Index  int    `json:"index"`
+	Method string `json:"method"`
+	// Code is the name of the status code, i.e. OK or NotFound.
+	Code     string          `json:"code"`
+	Error    string          `json:"error,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// formatter writes the results of calls in one of the structured formats.
+// Each response is a record, as is the final status of each call unless it
+// succeeded after returning responses.
+type formatter struct {
+	format string
+
+	mu sync.Mutex
+	w  io.Writer
+	tw *tabwriter.Writer
+	// responded tracks the calls (by method and index) which have returned
+	// responses.
+	responded map[string]bool
+	err       error
+}
+
+// newFormatter returns a formatter writing `format` to w.
+func newFormatter(format string, w io.Writer) (*formatter, error) {
+	f := &formatter{
+		format:    format,
+		w:         w,
+		responded: make(map[string]bool),
+	}
+	switch format {
+	case FormatJSON, FormatYAML:
+	case FormatTable:
+		f.tw = tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(f.tw, "TARGET\tINDEX\tMETHOD\tCODE\tRESULT")
+	default:
+		return nil, fmt.Errorf("invalid format %q (must be one of [%s])", format, strings.Join(Formats(), ","))
+	}
+	return f, nil
+}
+
+// result implements proxy.ResultFunc.
+func (f *formatter) result(r proxy.Result) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	call := fmt.Sprintf("%s %d", r.Method, r.Index)
+	if r.Done {
+		responded := f.responded[call]
+		delete(f.responded, call)
+		if r.Error == nil && responded {
+			return
+		}
+	} else {
+		f.responded[call] = true
+	}
+
+	rec := &record{
+		Target: r.Target,
+		Index:  r.Index,
+		Method: r.Method,
+		Code:   status.Code(r.Error).String(),
+	}
+	if r.Error != nil {
+		rec.Error = status.Convert(r.Error).Message()
+	}
+	if r.Resp != nil {
+		// protojson output isn't stable so compact it for tables.
+		b, err := protojson.Marshal(r.Resp)
+		buf := &bytes.Buffer{}
+		if err == nil {
+			err = json.Compact(buf, b)
+		}
+		if err != nil {
+			rec.Code = "Internal"
+			rec.Error = fmt.Sprintf("can't marshal response: %v", err)
+		} else {
+			rec.Response = buf.Bytes()
+		}
+	}
+	if err := f.write(rec); err != nil && f.err == nil {
+		f.err = err
+	}
+}
+
+// write outputs rec. Must be called with f.mu held.
+func (f *formatter) write(rec *record) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	switch f.format {
+	case FormatJSON:
+		_, err = fmt.Fprintf(f.w, "%s\n", b)
+	case FormatYAML:
+		var y []byte
+		y, err = yaml.JSONToYAML(b)
+		if err == nil {
+			_, err = fmt.Fprintf(f.w, "---\n%s", y)
+		}
+	case FormatTable:
+		res := rec.Error
+		if rec.Response != nil {
+			res = string(rec.Response)
+		}
+		_, err = fmt.Fprintf(f.tw, "%s\t%d\t%s\t%s\t%s\n", rec.Target, rec.Index, rec.Method, rec.Code, res)
+	}
+	return err
+}
+
+// flush writes anything buffered and returns the first error from writing.
+func (f *formatter) flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.tw != nil {
+		if err := f.tw.Flush(); err != nil && f.err == nil {
+			f.err = err
+		}
+	}
+	return f.err
+}
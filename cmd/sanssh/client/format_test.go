@@ -0,0 +1,115 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	tdpb "github.com/Snowflake-Labs/sansshell/proxy/testdata"
+)
+
+func TestFormatter(t *testing.T) {
+	const method = "/Testdata.TestService/TestServerStream"
+	results := []proxy.Result{
+		{Target: "a:1", Index: 0, Method: method, Resp: &tdpb.TestResponse{Output: "one"}},
+		{Target: "b:1", Index: 1, Method: method, Done: true, Error: status.Error(codes.NotFound, "no such thing")},
+		{Target: "a:1", Index: 0, Method: method, Resp: &tdpb.TestResponse{Output: "two"}},
+		// Not output as the target already responded.
+		{Target: "a:1", Index: 0, Method: method, Done: true},
+		// Output as a new call which returned nothing.
+		{Target: "a:1", Index: 0, Method: method, Done: true},
+	}
+	for _, tc := range []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatJSON,
+			want: `{"target":"a:1","index":0,"method":"/Testdata.TestService/TestServerStream","code":"OK","response":{"output":"one"}}
+{"target":"b:1","index":1,"method":"/Testdata.TestService/TestServerStream","code":"NotFound","error":"no such thing"}
+{"target":"a:1","index":0,"method":"/Testdata.TestService/TestServerStream","code":"OK","response":{"output":"two"}}
+{"target":"a:1","index":0,"method":"/Testdata.TestService/TestServerStream","code":"OK"}
+`,
+		},
+		{
+			format: FormatYAML,
+			want: `---
+code: OK
+index: 0
+method: /Testdata.TestService/TestServerStream
+response:
+  output: one
+target: a:1
+---
+code: NotFound
+error: no such thing
+index: 1
+method: /Testdata.TestService/TestServerStream
+target: b:1
+---
+code: OK
+index: 0
+method: /Testdata.TestService/TestServerStream
+response:
+  output: two
+target: a:1
+---
+code: OK
+index: 0
+method: /Testdata.TestService/TestServerStream
+target: a:1
+`,
+		},
+		{
+			format: FormatTable,
+			want: `TARGET  INDEX  METHOD                                  CODE      RESULT
+a:1     0      /Testdata.TestService/TestServerStream  OK        {"output":"one"}
+b:1     1      /Testdata.TestService/TestServerStream  NotFound  no such thing
+a:1     0      /Testdata.TestService/TestServerStream  OK        {"output":"two"}
+a:1     0      /Testdata.TestService/TestServerStream  OK        
+`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			f, err := newFormatter(tc.format, buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range results {
+				f.result(r)
+			}
+			if err := f.flush(); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, buf.String()); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := newFormatter("xml", &bytes.Buffer{}); err == nil {
+		t.Error("newFormatter didn't fail for an invalid format")
+	}
+}
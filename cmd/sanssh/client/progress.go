@@ -0,0 +1,147 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/proxy/proxy"
+)
+
+// fanoutProgress displays how many targets have finished the current call,
+// rewriting a single status line on an interval.
+type fanoutProgress struct {
+	w     io.Writer
+	total int
+	now   func() time.Time
+
+	mu     sync.Mutex
+	method string
+	// start is when the current call is taken to have started, which is
+	// when the previous one finished.
+	start  time.Time
+	last   time.Time
+	done   map[int]bool
+	failed int
+	shown  bool
+
+	stop     chan struct{}
+	finished chan struct{}
+}
+
+// newFanoutProgress returns a fanoutProgress for calls to `total` targets
+// writing to w.
+func newFanoutProgress(w io.Writer, total int) *fanoutProgress {
+	f := &fanoutProgress{
+		w:     w,
+		total: total,
+		now:   time.Now,
+		done:  make(map[int]bool),
+	}
+	f.start = f.now()
+	f.last = f.start
+	return f
+}
+
+// result is a proxy.ResultFunc counting the targets which have finished.
+// A result for another method, or from a target which already finished,
+// starts counting a new call.
+func (f *fanoutProgress) result(r proxy.Result) {
+	if !r.Done {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	if r.Method != f.method || f.done[r.Index] {
+		f.method = r.Method
+		f.start = f.last
+		f.done = make(map[int]bool)
+		f.failed = 0
+	}
+	f.done[r.Index] = true
+	if r.Error != nil {
+		f.failed++
+	}
+	f.last = now
+}
+
+// line returns the current status.
+func (f *fanoutProgress) line() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	method := f.method
+	if i := strings.LastIndex(method, "/"); i >= 0 {
+		method = method[i+1:]
+	}
+	if method == "" {
+		method = "waiting"
+	}
+	completed := len(f.done)
+	pending := f.total - completed
+	if pending < 0 {
+		pending = 0
+	}
+	eta := "?"
+	if completed > 0 {
+		elapsed := f.now().Sub(f.start)
+		eta = (elapsed / time.Duration(completed) * time.Duration(pending)).Round(time.Second).String()
+	}
+	return fmt.Sprintf("%s: %d/%d done, %d failed, %d pending, ETA %s", method, completed, f.total, f.failed, pending, eta)
+}
+
+// draw rewrites the status line.
+func (f *fanoutProgress) draw() {
+	fmt.Fprintf(f.w, "\r%s\x1b[K", f.line())
+	f.shown = true
+}
+
+// run redraws the status line every interval until stopped.
+func (f *fanoutProgress) run(interval time.Duration) {
+	f.stop = make(chan struct{})
+	f.finished = make(chan struct{})
+	go func() {
+		defer close(f.finished)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				f.draw()
+			case <-f.stop:
+				return
+			}
+		}
+	}()
+}
+
+// finish stops redrawing and, if anything was drawn, leaves the final
+// status on its own line.
+func (f *fanoutProgress) finish() {
+	if f.stop != nil {
+		close(f.stop)
+		<-f.finished
+	}
+	if f.shown {
+		f.draw()
+		fmt.Fprintln(f.w)
+	}
+}
@@ -0,0 +1,77 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	tdpb "github.com/Snowflake-Labs/sansshell/proxy/testdata"
+)
+
+func TestFanoutProgress(t *testing.T) {
+	const method = "/Testdata.TestService/TestUnary"
+	var buf bytes.Buffer
+	p := newFanoutProgress(&buf, 4)
+	now := p.start
+	p.now = func() time.Time { return now }
+
+	check := func(want string) {
+		t.Helper()
+		if got := p.line(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+	check("waiting: 0/4 done, 0 failed, 4 pending, ETA ?")
+
+	now = now.Add(10 * time.Second)
+	// Responses before the final status aren't counted.
+	p.result(proxy.Result{Index: 0, Method: method, Resp: &tdpb.TestResponse{Output: "one"}})
+	p.result(proxy.Result{Index: 0, Method: method, Done: true})
+	check("TestUnary: 1/4 done, 0 failed, 3 pending, ETA 30s")
+
+	now = now.Add(10 * time.Second)
+	p.result(proxy.Result{Index: 2, Method: method, Done: true, Error: status.Error(codes.Unavailable, "down")})
+	check("TestUnary: 2/4 done, 1 failed, 2 pending, ETA 20s")
+
+	// A target finishing again is a new call, timed from the end of the
+	// last one.
+	now = now.Add(5 * time.Second)
+	p.result(proxy.Result{Index: 2, Method: method, Done: true})
+	check("TestUnary: 1/4 done, 0 failed, 3 pending, ETA 15s")
+
+	// As is another method.
+	now = now.Add(5 * time.Second)
+	p.result(proxy.Result{Index: 1, Method: "/Testdata.TestService/TestServerStream", Done: true, Error: status.Error(codes.Unavailable, "down")})
+	check("TestServerStream: 1/4 done, 1 failed, 3 pending, ETA 15s")
+
+	// Nothing is written unless drawn.
+	p.finish()
+	if buf.Len() != 0 {
+		t.Errorf("got output %q before drawing", buf.String())
+	}
+	p.draw()
+	p.finish()
+	if got, want := buf.String(), "\rTestServerStream: 1/4 done, 1 failed, 3 pending, ETA 15s\x1b[K\rTestServerStream: 1/4 done, 1 failed, 3 pending, ETA 15s\x1b[K\n"; got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,218 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// parseEntries returns the entries in `file`, split into groups if
+// sections is true.
+func parseEntries(file string, sections bool) (map[string][]string, []string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	groups := make(map[string][]string)
+	var entries []string
+	group := ""
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		// Bracketed IPv6 addresses are only allowed with a port so they
+		// aren't confused with these.
+		if sections && strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			group = strings.TrimSpace(line[1 : len(line)-1])
+			if group == "" {
+				return nil, nil, fmt.Errorf("%s:%d: empty group name", file, n)
+			}
+			if _, ok := groups[group]; ok {
+				return nil, nil, fmt.Errorf("%s:%d: group %s is already defined", file, n, group)
+			}
+			groups[group] = nil
+			continue
+		}
+		if sections && group == "" {
+			return nil, nil, fmt.Errorf("%s:%d: target %q isn't in a group", file, n, line)
+		}
+		for _, e := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			if sections {
+				groups[group] = append(groups[group], e)
+			} else {
+				entries = append(entries, e)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return groups, entries, nil
+}
+
+// resolveEntries makes the @file entries read from `file` relative to its
+// directory. References to `groups` are left alone.
+func resolveEntries(entries []string, file string, groups map[string][]string) {
+	for i, e := range entries {
+		name := strings.TrimPrefix(e, "@")
+		if name == e || name == "" || filepath.IsAbs(name) {
+			continue
+		}
+		if _, ok := groups[name]; ok {
+			continue
+		}
+		entries[i] = "@" + filepath.Join(filepath.Dir(file), name)
+	}
+}
+
+// LoadTargetGroups returns the groups of targets defined in `file`. See
+// ExpandTargets for its format.
+func LoadTargetGroups(file string) (map[string][]string, error) {
+	groups, _, err := parseEntries(file, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, entries := range groups {
+		resolveEntries(entries, file, groups)
+	}
+	return groups, nil
+}
+
+// ExpandTargets returns the targets named by `targets`, expanding @files
+// and references to `groups`. Duplicates after the first are removed.
+// Each entry can be:
+//
+//	host:port        a single target
+//	a,b,c            a list of any of these
+//	@name            the targets of the group `name`, if one is defined
+//	@hosts.txt       otherwise the targets listed in a file
+//
+// A file with the same name as a group can be given as @./name. Files list
+// targets (or further @files and @groups) separated by commas, whitespace
+// or newlines, with # starting a comment. Groups are defined in a file of
+// the same format split into sections, i.e.
+//
+//	# Comments are ignored.
+//	[db]
+//	db1.example.com:50042
+//	db2.example.com:50042
+//
+//	[web]
+//	@web-hosts.txt
+//
+//	[all]
+//	@db, @web
+//
+// Relative @file paths in a file are relative to the directory it's in.
+func ExpandTargets(targets []string, groups map[string][]string) ([]string, error) {
+	var out []string
+	seen := make(map[string]bool)
+	// expanding holds the groups and files currently being expanded, to
+	// catch cycles.
+	expanding := make(map[string]bool)
+	var expand func(entries []string) error
+	expand = func(entries []string) error {
+		for _, e := range entries {
+			e = strings.TrimSpace(e)
+			if e == "" {
+				continue
+			}
+			if !strings.HasPrefix(e, "@") {
+				if !seen[e] {
+					seen[e] = true
+					out = append(out, e)
+				}
+				continue
+			}
+			name := e[1:]
+			if name == "" {
+				return fmt.Errorf("no group or file given with @")
+			}
+			members, ok := groups[name]
+			if expanding[e] {
+				if ok {
+					return fmt.Errorf("group %s includes itself", name)
+				}
+				return fmt.Errorf("%s includes itself", name)
+			}
+			if !ok {
+				_, entries, err := parseEntries(name, false)
+				if err != nil {
+					return err
+				}
+				resolveEntries(entries, name, groups)
+				members = entries
+			}
+			expanding[e] = true
+			if err := expand(members); err != nil {
+				return err
+			}
+			delete(expanding, e)
+		}
+		return nil
+	}
+	if err := expand(targets); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExpandOutputs returns `outputs` expanded alongside `targets` by
+// ExpandTargets, which it must map 1:1 with. The output of a single target
+// is kept as is, while that of an @group or @file becomes <output>.N for
+// the Nth target it expands to. As with targets duplicates are removed, and
+// a target named more than once must have the same output each time.
+func ExpandOutputs(targets []string, outputs []string, groups map[string][]string) ([]string, error) {
+	if len(outputs) != len(targets) {
+		return nil, fmt.Errorf("outputs and targets must contain the same number of entries")
+	}
+	var out []string
+	seen := make(map[string]string)
+	for i, t := range targets {
+		expanded, err := ExpandTargets([]string{t}, groups)
+		if err != nil {
+			return nil, err
+		}
+		for j, target := range expanded {
+			o := outputs[i]
+			if strings.HasPrefix(strings.TrimSpace(t), "@") && o != defaultOutput {
+				o = fmt.Sprintf("%s.%d", o, j)
+			}
+			if prev, ok := seen[target]; ok {
+				if prev != o {
+					return nil, fmt.Errorf("target %s has outputs %s and %s", target, prev, o)
+				}
+				continue
+			}
+			seen[target] = o
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
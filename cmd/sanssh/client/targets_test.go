@@ -0,0 +1,281 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestExpandTargets(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) string {
+		f := filepath.Join(dir, name)
+		testutil.FatalOnErr("WriteFile", os.WriteFile(f, []byte(contents), 0644), t)
+		return f
+	}
+	hosts := write("hosts.txt", `
+# Comments and blank lines are ignored.
+a:1
+b:1, c:1   # trailing comment
+[::1]:50042
+a:1
+`)
+	write("web.txt", "w1:1\nw2:1\n")
+	write("loop.txt", "@loop.txt\n")
+	write("group.txt", "[db]\nd1:1\n")
+	groupsFile := write("groups", `
+[db]
+d1:1 d2:1
+
+[web]
+@web.txt  # relative to this file
+
+[all]
+@db, @web
+a:1
+`)
+	groups, err := LoadTargetGroups(groupsFile)
+	testutil.FatalOnErr("LoadTargetGroups", err, t)
+	groups["self"] = []string{"@self"}
+	groups["loop1"] = []string{"@loop2"}
+	groups["loop2"] = []string{"@loop1"}
+	groups["hosts.txt"] = []string{"h:1"}
+
+	for _, tc := range []struct {
+		name    string
+		targets []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "plain",
+			targets: []string{"a:1", "b:1", "a:1", ""},
+			want:    []string{"a:1", "b:1"},
+		},
+		{
+			name:    "file",
+			targets: []string{"@" + hosts, "z:1"},
+			want:    []string{"a:1", "b:1", "c:1", "[::1]:50042", "z:1"},
+		},
+		{
+			name:    "groups",
+			targets: []string{"@all", "d1:1"},
+			want:    []string{"d1:1", "d2:1", "w1:1", "w2:1", "a:1"},
+		},
+		{
+			name:    "group name is a target",
+			targets: []string{"db"},
+			want:    []string{"db"},
+		},
+		{
+			name:    "undefined group is a file",
+			targets: []string{"@other"},
+			wantErr: true,
+		},
+		{
+			name:    "group shadows file",
+			targets: []string{"@hosts.txt"},
+			want:    []string{"h:1"},
+		},
+		{
+			name:    "no name",
+			targets: []string{"@"},
+			wantErr: true,
+		},
+		{
+			name:    "group including itself",
+			targets: []string{"@self"},
+			wantErr: true,
+		},
+		{
+			name:    "group cycle",
+			targets: []string{"@loop1"},
+			wantErr: true,
+		},
+		{
+			name:    "file including itself",
+			targets: []string{"@" + filepath.Join(dir, "loop.txt")},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			targets: []string{"@" + filepath.Join(dir, "missing.txt")},
+			wantErr: true,
+		},
+		{
+			name:    "sections are targets in a targets file",
+			targets: []string{"@" + filepath.Join(dir, "group.txt")},
+			want:    []string{"[db]", "d1:1"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ExpandTargets(tc.targets, groups)
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("unexpected error state. got %t want %t err %v", got, want, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected targets (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExpandOutputs(t *testing.T) {
+	dir := t.TempDir()
+	hosts := filepath.Join(dir, "hosts.txt")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(hosts, []byte("a:1\nb:1\n"), 0644), t)
+	groups := map[string][]string{
+		"db":    {"d1:1", "d2:1", "d3:1"},
+		"ab":    {"a:1", "b:1"},
+		"empty": nil,
+	}
+
+	for _, tc := range []struct {
+		name    string
+		targets []string
+		outputs []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "plain",
+			targets: []string{"a:1", "b:1"},
+			outputs: []string{"x", "y"},
+			want:    []string{"x", "y"},
+		},
+		{
+			name:    "duplicate",
+			targets: []string{"a:1", "b:1", "a:1"},
+			outputs: []string{"x", "y", "x"},
+			want:    []string{"x", "y"},
+		},
+		{
+			name:    "duplicate with another output",
+			targets: []string{"a:1", "a:1"},
+			outputs: []string{"x", "y"},
+			wantErr: true,
+		},
+		{
+			name:    "target in a group and a file",
+			targets: []string{"@" + hosts, "@ab"},
+			outputs: []string{"hosts", "ab"},
+			wantErr: true,
+		},
+		{
+			name:    "duplicates to stdout",
+			targets: []string{"@" + hosts, "@ab"},
+			outputs: []string{"-", "-"},
+			want:    []string{"-", "-"},
+		},
+		{
+			name:    "group and file",
+			targets: []string{"z:1", "@db", "@" + hosts, "@empty"},
+			outputs: []string{"z", "db", "hosts", "empty"},
+			want:    []string{"z", "db.0", "db.1", "db.2", "hosts.0", "hosts.1"},
+		},
+		{
+			name:    "stdout",
+			targets: []string{"@db"},
+			outputs: []string{"-"},
+			want:    []string{"-", "-", "-"},
+		},
+		{
+			name:    "count mismatch",
+			targets: []string{"@db", "a:1"},
+			outputs: []string{"db"},
+			wantErr: true,
+		},
+		{
+			name:    "bad target",
+			targets: []string{"@" + filepath.Join(dir, "missing.txt")},
+			outputs: []string{"x"},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ExpandOutputs(tc.targets, tc.outputs, groups)
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("unexpected error state. got %t want %t err %v", got, want, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected outputs (-want +got):\n%s", diff)
+			}
+			if tc.wantErr {
+				return
+			}
+			targets, err := ExpandTargets(tc.targets, groups)
+			testutil.FatalOnErr("ExpandTargets", err, t)
+			if len(targets) != len(got) {
+				t.Errorf("got %d outputs for %d targets", len(got), len(targets))
+			}
+		})
+	}
+}
+
+func TestLoadTargetGroups(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		contents string
+		want     map[string][]string
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			contents: "# hosts\n[a]\nx:1, y:1\n[b]\n[c] # comment\nz:1\n",
+			want:     map[string][]string{"a": {"x:1", "y:1"}, "b": nil, "c": {"z:1"}},
+		},
+		{
+			name:     "target outside group",
+			contents: "x:1\n[a]\n",
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate group",
+			contents: "[a]\nx:1\n[a]\n",
+			wantErr:  true,
+		},
+		{
+			name:     "empty group name",
+			contents: "[ ]\nx:1\n",
+			wantErr:  true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			f := filepath.Join(t.TempDir(), "groups")
+			testutil.FatalOnErr("WriteFile", os.WriteFile(f, []byte(tc.contents), 0644), t)
+			got, err := LoadTargetGroups(f)
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("unexpected error state. got %t want %t err %v", got, want, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected groups (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+//go:build ignore
+// +build ignore
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// gen_services writes services.go importing the client package of every
+// service under services/ so their commands are available in sanssh. Run it
+// with go generate after adding a service.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const modulePath = "github.com/Snowflake-Labs/sansshell"
+
+func main() {
+	dirs, err := filepath.Glob("../../services/*/client")
+	if err != nil {
+		log.Fatal(err)
+	}
+	b := &bytes.Buffer{}
+	fmt.Fprintln(b, "// Code generated by gen_services.go. DO NOT EDIT.")
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "package main")
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "import (")
+	fmt.Fprintln(b, "\t// Import services here to make them accessible for CLI")
+	for _, dir := range dirs {
+		rel, err := filepath.Rel("../..", dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(b, "\t_ %q\n", modulePath+"/"+filepath.ToSlash(rel))
+	}
+	fmt.Fprintln(b, ")")
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("services.go", out, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -15,12 +15,21 @@
 */
 
 // Package main implements the SansShell CLI client.
+//
+// Every service's client package is imported by services.go, which is
+// generated from the contents of services/.
 package main
 
+//go:generate go run gen_services.go
+
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -31,27 +40,34 @@ import (
 	"github.com/Snowflake-Labs/sansshell/services/util"
 	"github.com/google/subcommands"
 	"google.golang.org/grpc/metadata"
-
-	// Import services here to make them accessible for CLI
-	_ "github.com/Snowflake-Labs/sansshell/services/ansible/client"
-	_ "github.com/Snowflake-Labs/sansshell/services/exec/client"
-	_ "github.com/Snowflake-Labs/sansshell/services/healthcheck/client"
-	_ "github.com/Snowflake-Labs/sansshell/services/localfile/client"
-	_ "github.com/Snowflake-Labs/sansshell/services/packages/client"
-	_ "github.com/Snowflake-Labs/sansshell/services/process/client"
-	_ "github.com/Snowflake-Labs/sansshell/services/sansshell/client"
-	_ "github.com/Snowflake-Labs/sansshell/services/service/client"
 )
 
 var (
 	defaultAddress = "localhost:50042"
 	defaultTimeout = 3 * time.Second
 
+	// defaultTargetGroups is set to target-groups in ~/.sansshell.
+	defaultTargetGroups string
+
 	proxyAddr     = flag.String("proxy", "", "Address to contact for proxy to sansshell-server. If blank a direct connection to the first entry in --targets will be made")
 	timeout       = flag.Duration("timeout", defaultTimeout, "How long to wait for the command to complete")
 	credSource    = flag.String("credential-source", mtlsFlags.Name(), fmt.Sprintf("Method used to obtain mTLS credentials (one of [%s])", strings.Join(mtls.Loaders(), ",")))
+	tlsMinVersion = flag.String("tls-min-version", "1.3", "Minimum TLS version to negotiate (1.2 or 1.3).")
+	tlsCiphers    = flag.String("tls-cipher-suites", "", "Comma separated list of allowed TLS 1.2 cipher suites (i.e. TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384). If empty Go's defaults are used.")
+	tlsCurves     = flag.String("tls-curves", "", "Comma separated list of key exchange curves in preference order (X25519, P256, P384, P521). If empty Go's defaults are used.")
 	outputsDir    = flag.String("output-dir", "", "If set defines a directory to emit output/errors from commands. Files will be generated based on target as destination/0 destination/0.error, etc.")
 	justification = flag.String("justification", "", "If non-empty will add the key '"+rpcauth.ReqJustKey+"' to the outgoing context Metadata to be passed along to the server for possbile validation and logging.")
+	approvalID    = flag.String("approval-id", "", "If non-empty will add the key '"+rpcauth.ApprovalIDKey+"' to the outgoing context Metadata, to retry a request once it's been approved.")
+	tokenFile     = flag.String("token-file", "", "If set, a file containing an OIDC ID token to send as a bearer token with every RPC.")
+	retries       = flag.Int("throttle-retries", 3, "How many times to retry a call to a target which rate limits it, after waiting as long as it asks.")
+	maxRetryDelay = flag.Duration("max-retry-delay", 30*time.Second, "Calls are only retried if the target asks to wait no longer than this.")
+	skipUnimpl    = flag.Bool("skip-unimplemented", false, "If set, targets are asked for their capabilities first and those which don't implement the method being called are skipped rather than returning errors. Only used with --proxy.")
+	format        = flag.String("format", client.FormatRaw, fmt.Sprintf("Output format (one of [%s]). raw is the command's own output, the others are a record of the target, index, method, status code and response (as JSON) or error of every response written to stdout instead.", strings.Join(client.Formats(), ",")))
+	maxInflight   = flag.Int("max-inflight", 0, "If non-zero, the most targets to call at once. Others are called as earlier ones finish, so --timeout must allow for all of them. Only used with --proxy.")
+	progress      = flag.Bool("progress", false, "If set, display how many targets have finished, failed or are pending and an estimate of the time remaining on stderr. Only used with --proxy.")
+
+	// targetGroups is bound to --target-groups.
+	targetGroups string
 
 	// targets will be bound to --targets for sending a single request to N nodes.
 	targetsFlag util.StringSliceFlag
@@ -65,34 +81,69 @@ func init() {
 	// Setup an empty slice so it can be deref'd below regardless of user input.
 	outputsFlag.Target = &[]string{}
 
-	flag.Var(&targetsFlag, "targets", "List of targets (separated by commas) to apply RPC against. If --proxy is not set must be one entry only.")
+	flag.Var(&targetsFlag, "targets", `List of targets (separated by commas) to apply RPC against. If --proxy is not set must be one entry only.
+    Entries may also be @group to use a group from --target-groups, or otherwise @file to read targets (one per line, # comments) from a file.
+    Duplicate targets are removed.`)
+	if home, err := os.UserHomeDir(); err == nil {
+		defaultTargetGroups = filepath.Join(home, ".sansshell", "target-groups")
+	}
+	flag.StringVar(&targetGroups, "target-groups", defaultTargetGroups, `File defining named groups of targets usable with --targets, i.e.
+    [db]
+    db1.example.com:50042
+    @db-hosts.txt
+    It's ignored if it's the default and doesn't exist.`)
 	flag.Var(&outputsFlag, "outputs", `List of output destinations (separated by commas) to direct output into.
     Use - to indicated stdout/stderr (default if nothing else is set). Using - does not have to be repeated per target.
 	Errors will be emitted to <destination>.error separately from command/execution output which will be in the destination file.
-	NOTE: This must map 1:1 with --targets except in the '-' case.`)
+	NOTE: This must map 1:1 with --targets except in the '-' case. The output of an @group or @file entry is used as <destination>.N for its Nth target, and a target named more than once must have the same output each time.`)
 
 	subcommands.ImportantFlag("credential-source")
 	subcommands.ImportantFlag("proxy")
 	subcommands.ImportantFlag("targets")
+	subcommands.ImportantFlag("target-groups")
 	subcommands.ImportantFlag("outputs")
 	subcommands.ImportantFlag("output-dir")
 	subcommands.ImportantFlag("justification")
+	subcommands.ImportantFlag("format")
 }
 
 func main() {
 	flag.Parse()
 
+	tlsOpts, err := mtls.ParseTLSOptions(*tlsMinVersion, *tlsCiphers, *tlsCurves)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid TLS flags: %v\n", err)
+		os.Exit(1)
+	}
+	groupsFile := targetGroups
+	if groupsFile == defaultTargetGroups {
+		if _, err := os.Stat(groupsFile); errors.Is(err, fs.ErrNotExist) {
+			groupsFile = ""
+		}
+	}
 	rs := client.RunState{
-		Proxy:      *proxyAddr,
-		Targets:    *targetsFlag.Target,
-		Outputs:    *outputsFlag.Target,
-		OutputsDir: *outputsDir,
-		CredSource: *credSource,
-		Timeout:    *timeout,
+		Proxy:             *proxyAddr,
+		Targets:           *targetsFlag.Target,
+		TargetGroups:      groupsFile,
+		Outputs:           *outputsFlag.Target,
+		OutputsDir:        *outputsDir,
+		CredSource:        *credSource,
+		TLSOptions:        tlsOpts,
+		Timeout:           *timeout,
+		TokenFile:         *tokenFile,
+		ThrottleRetries:   *retries,
+		MaxRetryDelay:     *maxRetryDelay,
+		SkipUnimplemented: *skipUnimpl,
+		Format:            *format,
+		MaxInflight:       *maxInflight,
+		Progress:          *progress,
 	}
 	ctx := context.Background()
 	if *justification != "" {
 		ctx = metadata.AppendToOutgoingContext(ctx, rpcauth.ReqJustKey, *justification)
 	}
+	if *approvalID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, rpcauth.ApprovalIDKey, *approvalID)
+	}
 	client.Run(ctx, rs)
 }
@@ -0,0 +1,32 @@
+// Code generated by gen_services.go. DO NOT EDIT.
+
+package main
+
+import (
+	// Import services here to make them accessible for CLI
+	_ "github.com/Snowflake-Labs/sansshell/services/ansible/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/approvals/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/certs/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/configdeploy/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/cron/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/exec/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/fdb/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/firewall/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/healthcheck/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/httpoverrpc/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/ipmi/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/k8snode/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/localfile/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/mac/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/network/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/packages/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/policy/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/power/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/process/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/sansshell/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/scripts/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/service/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/sysctl/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/sysinfo/client"
+	_ "github.com/Snowflake-Labs/sansshell/services/users/client"
+)
@@ -0,0 +1,64 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+)
+
+func TestServicesImported(t *testing.T) {
+	dirs, err := filepath.Glob("../../services/*/client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for _, dir := range dirs {
+		rel, err := filepath.Rel("../..", dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, "github.com/Snowflake-Labs/sansshell/"+filepath.ToSlash(rel))
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "services.go", nil, parser.ImportsOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, imp := range f.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, path)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("services.go is out of date, run go generate (-want +got):\n%s", diff)
+	}
+
+	// Each service registers a single top level command.
+	if got, want := len(client.Subpackages()), len(dirs); got != want {
+		t.Errorf("got %d subpackages %v, want %d", got, client.Subpackages(), want)
+	}
+}
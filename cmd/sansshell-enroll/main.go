@@ -0,0 +1,83 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package main implements sansshell-enroll, which obtains a short lived
+// sansshell client certificate from the enrollment endpoint of a proxy
+// (see --enroll-hostport) in exchange for an OIDC ID token.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Snowflake-Labs/sansshell/auth/enroll"
+	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+)
+
+var (
+	enrollURL = flag.String("url", "", "URL of the enrollment endpoint, i.e. https://proxy.example.com:50044/")
+	tokenFile = flag.String("token-file", "", "File containing an OIDC ID token proving the identity to enroll")
+	certFile  = flag.String("client-cert", filepath.Join(os.Getenv("HOME"), ".sansshell/client.pem"), "File to write the issued certificate to")
+	keyFile   = flag.String("client-key", filepath.Join(os.Getenv("HOME"), ".sansshell/client.key"), "File to write the new private key to")
+	rootCA    = flag.String("root-ca", "", "CA bundle used to verify the enrollment endpoint. If empty the system roots are used.")
+	timeout   = flag.Duration("timeout", 30*time.Second, "How long to wait for enrollment to complete")
+)
+
+func main() {
+	flag.Parse()
+
+	if *enrollURL == "" {
+		log.Fatal("--url must be set")
+	}
+	if *tokenFile == "" {
+		log.Fatal("--token-file must be set")
+	}
+	token, err := os.ReadFile(*tokenFile)
+	if err != nil {
+		log.Fatalf("can't read token: %v", err)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if *rootCA != "" {
+		pool, err := mtls.LoadRootOfTrust(*rootCA)
+		if err != nil {
+			log.Fatalf("can't load root CA: %v", err)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	if err := os.MkdirAll(filepath.Dir(*certFile), 0755); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(*keyFile), 0700); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if err := enroll.EnrollToFiles(ctx, client, *enrollURL, strings.TrimSpace(string(token)), *certFile, *keyFile); err != nil {
+		log.Fatalf("enrollment failed: %v", err)
+	}
+	log.Printf("wrote certificate to %s and key to %s", *certFile, *keyFile)
+}
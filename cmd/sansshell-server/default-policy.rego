@@ -2,9 +2,9 @@ package sansshell.authz
 
 default allow = false
 
-allow {
-	input.method = "/HealthCheck.HealthCheck/Ok"
-}
+# Services may ship their own policy fragments (i.e. HealthCheck allows
+# everyone to call Ok) which are combined with this policy unless
+# disabled with --policy-fragments.
 
 # Allow people to run reflection against the server
 allow {
@@ -59,3 +59,196 @@ allow {
 allow {
 	input.type = "Service.StatusRequest"
 }
+
+# Request arguments are available under input.message (as protojson
+# using the proto field names) so policies can constrain them. For example
+# to allow reading any file under /var/log:
+#
+# allow {
+#	input.type = "LocalFile.ReadActionRequest"
+#	startswith(input.message.file.filename, "/var/log/")
+#	not contains(input.message.file.filename, "..")
+# }
+
+# HTTPOverRPC requests give the host, port and path separately so they can
+# be limited. Redirects aren't followed. For example to allow fetching
+# debug pages from a local service:
+#
+# allow {
+#	input.type = "HTTPOverRPC.HTTPRequest"
+#	input.message.host = "localhost"
+#	input.message.port = 8080
+#	startswith(input.message.path, "/debug/")
+#	not contains(input.message.path, "..")
+# }
+
+# Firewall.InsertTemporaryRule also needs --firewall-allow-temporary-rules.
+# For example to let "sre" temporarily drop traffic during an incident:
+#
+# allow {
+#	input.type = "Firewall.InsertTemporaryRuleRequest"
+#	"sre" in input.peer.principal.groups
+#	input.message.rule[count(input.message.rule)-1] = "DROP"
+# }
+
+# Sysctl.Set gives the key and value so changes can be limited to known
+# tunables. For example:
+#
+# allow {
+#	input.type = "Sysctl.SetRequest"
+#	input.message.key = "vm.swappiness"
+#	to_number(input.message.value) <= 60
+# }
+
+# Power.Reboot and Power.Poweroff require a reason, which can be checked
+# against a ticket format. For example to let "sre" reboot for changes:
+#
+# allow {
+#	input.type = "Power.ShutdownRequest"
+#	input.method = "/Power.Power/Reboot"
+#	"sre" in input.peer.principal.groups
+#	regex.match(`^CHG-[0-9]+`, input.message.reason)
+# }
+
+# MAC.SetEnforce can turn off SELinux enforcement so should be limited,
+# i.e. to only allow returning to enforcing mode:
+#
+# allow {
+#	input.type = "MAC.SetEnforceRequest"
+#	input.message.enforcing = true
+# }
+
+# ConfigDeploy.Activate can replace any staged file so limit what can be
+# staged, i.e. to files under /etc/myapp:
+#
+# allow {
+#	input.type = "ConfigDeploy.StageRequest"
+#	count([f | f := input.message.files[_]; not startswith(f.path, "/etc/myapp/")]) == 0
+# }
+
+# Scripts.Run only runs scripts from the --scripts-catalog, whose parameters
+# are input.message.parameters, i.e. to allow a report on /var only:
+#
+# allow {
+#	input.type = "Scripts.RunRequest"
+#	input.message.name = "disk-report"
+#	input.message.parameters.mount = "/var"
+# }
+
+# IPMI.PowerControl can power off or reset this host or, with input.message.bmc
+# set, any host whose BMC it can reach, so it should be limited, i.e. to
+# soft shutdowns of this host (unset fields are omitted from the input):
+#
+# allow {
+#	input.type = "IPMI.PowerControlRequest"
+#	not input.message.bmc
+#	input.message.action = "POWER_ACTION_SOFT"
+# }
+
+# FDB.Status and FDB.Coordinators (without changes) are read only, while
+# Exclude, Include and changing coordinators alter the cluster, i.e. to
+# allow only reading coordinators:
+#
+# allow {
+#	input.type = "FDB.CoordinatorsRequest"
+#	not input.message.auto
+#	not input.message.addresses
+#	not input.message.description
+# }
+
+# Process.GetMemoryMap only reveals how memory is used, while ReadMemory
+# returns the contents of a process's memory (and is refused unless the
+# server sets --max-memory-read-size). Uint64 fields such as length are
+# strings in the input, i.e. to allow small reads for members of "sre":
+#
+# allow {
+#	input.type = "Process.ReadMemoryRequest"
+#	to_number(input.message.length) <= 1048576
+#	"sre" in input.peer.principal.groups
+# }
+
+# With --require-approvals, allowed requests matching require_approval must
+# also be approved by a different principal with the Approvals service
+# before they run. For example to require a second person for package
+# installs, and let members of "sre" review requests:
+#
+# require_approval {
+#	input.type = "Packages.InstallRequest"
+# }
+#
+# allow {
+#	startswith(input.method, "/Approvals.Approvals/")
+#	"sre" in input.peer.principal.groups
+# }
+
+# The Policy service evaluates this policy for a described request, for any
+# principal, without running it (i.e. sanssh policy simulate). As it reveals
+# what others may do, only allow it for administrators:
+#
+# allow {
+#	input.method = "/Policy.Policy/Simulate"
+#	"sre" in input.peer.principal.groups
+# }
+
+# Requests forwarded by a proxy carry the target the client asked for in
+# input.host.target. As it's asserted by the proxy, only trust it from
+# trusted proxies. For example to only let the canary deployer reach canary
+# hosts:
+#
+# allow {
+#	input.peer.cert.spiffeid = "spiffe://example.com/proxy"
+#	startswith(input.host.target, "canary")
+#	input.delegation.subject = "spiffe://example.com/deployer/canary"
+# }
+
+# Besides the standard Rego builtins, policies can use:
+#  - sansshell.target_in_cidr(target, cidr) to match IP targets by network.
+#  - sansshell.path_match(pattern, path) to match file paths against globs
+#    where ** matches any number of directories.
+#  - sansshell.shell_split(cmdline) to split a command line into words.
+# For example to allow tailing any log file:
+#
+# allow {
+#	input.type = "LocalFile.ReadActionRequest"
+#	sansshell.path_match("/var/log/**/*.log", input.message.file.filename)
+# }
+#
+# allow {
+#	input.type = "Exec.ExecRequest"
+#	some cmdline in {"uptime", "df -h", "systemctl status --no-pager sshd"}
+#	sansshell.shell_split(cmdline) == array.concat([input.message.command], input.message.args)
+# }
+
+# With --authz-rate-limits, allowed requests are limited to the rate given
+# by rate_limit for each caller. An optional key gives each distinct value
+# its own limit. For example to let each caller start one command a second
+# per host (with bursts of 5):
+#
+# rate_limit = {"rps": 1, "burst": 5, "key": input.host.target} {
+#	input.type = "Exec.ExecRequest"
+# }
+
+# Requests to run commands, install packages or change services are also
+# decomposed into input.request (see the inspect package), with the binary,
+# args, package, version and systemd unit normalized. For example to only
+# allow restarting nginx, either with the Service service or systemctl:
+#
+# allow {
+#	input.request.action = "restart"
+#	input.request.unit = "nginx.service"
+# }
+#
+# allow {
+#	input.request.binary = "/usr/bin/systemctl"
+#	input.request.args = ["restart", "nginx.service"]
+# }
+
+# JSON documents given with --policy-data name=source are available as
+# data.external.<name> and reloaded every --policy-data-refresh. For example
+# with --policy-data=oncall=https://example.com/oncall.json serving
+# {"sre": ["alice", "bob"]}, to let whoever is on call run commands:
+#
+# allow {
+#	input.type = "Exec.ExecRequest"
+#	input.peer.principal.id in data.external.oncall.sre
+# }
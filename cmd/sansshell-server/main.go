@@ -28,26 +28,58 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/stdr"
 
+	"github.com/Snowflake-Labs/sansshell/auth/groups"
+	"github.com/Snowflake-Labs/sansshell/auth/inspect"
 	"github.com/Snowflake-Labs/sansshell/auth/mtls"
 	mtlsFlags "github.com/Snowflake-Labs/sansshell/auth/mtls/flags"
 	"github.com/Snowflake-Labs/sansshell/auth/opa"
+	"github.com/Snowflake-Labs/sansshell/auth/opa/remote"
 	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
 	"github.com/Snowflake-Labs/sansshell/cmd/sansshell-server/server"
 	"github.com/Snowflake-Labs/sansshell/cmd/util"
+	approvals "github.com/Snowflake-Labs/sansshell/services/approvals/server"
+
+	// Group providers, selectable with --groups-provider.
+	_ "github.com/Snowflake-Labs/sansshell/auth/groups/file"
+	_ "github.com/Snowflake-Labs/sansshell/auth/groups/google"
+	_ "github.com/Snowflake-Labs/sansshell/auth/groups/ldap"
+
+	// Additional credential sources, selectable with --credential-source.
+	// The pkcs11 source needs cgo so it's imported in pkcs11.go.
+	_ "github.com/Snowflake-Labs/sansshell/auth/mtls/gcpkms"
+	_ "github.com/Snowflake-Labs/sansshell/auth/mtls/kubernetes"
+	_ "github.com/Snowflake-Labs/sansshell/auth/mtls/vault"
 
 	// Import the server modules you want to expose, they automatically register
 	_ "github.com/Snowflake-Labs/sansshell/services/ansible/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/certs/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/configdeploy/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/cron/server"
 	_ "github.com/Snowflake-Labs/sansshell/services/exec/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/fdb/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/firewall/server"
 	_ "github.com/Snowflake-Labs/sansshell/services/healthcheck/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/httpoverrpc/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/ipmi/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/k8snode/server"
 	_ "github.com/Snowflake-Labs/sansshell/services/localfile/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/mac/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/network/server"
 	_ "github.com/Snowflake-Labs/sansshell/services/packages/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/policy/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/power/server"
 	_ "github.com/Snowflake-Labs/sansshell/services/process/server"
 	_ "github.com/Snowflake-Labs/sansshell/services/sansshell/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/scripts/server"
 	_ "github.com/Snowflake-Labs/sansshell/services/service/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/sysctl/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/sysinfo/server"
+	_ "github.com/Snowflake-Labs/sansshell/services/users/server"
 )
 
 var (
@@ -55,12 +87,45 @@ var (
 	defaultPolicy string
 
 	policyFlag    = flag.String("policy", defaultPolicy, "Local OPA policy governing access.  If empty, use builtin policy.")
-	policyFile    = flag.String("policy-file", "", "Path to a file with an OPA policy.  If empty, uses --policy.")
+	policyFile    = flag.String("policy-file", "", "Path to a file with an OPA policy.  If empty, uses --policy. The file is watched and the policy reloaded when it changes.")
+	policyURL     = flag.String("policy-url", "", "HTTP(S) URL to fetch an OPA policy (or bundle) from. If set overrides --policy and --policy-file.")
+	policyRefresh = flag.Duration("policy-refresh", time.Minute, "How often to check --policy-url for changes.")
+	policyKey     = flag.String("policy-public-key", "", "If set, a file with the public key (or certificate) policies from --policy-file or --policy-url must be signed with. The detached signature is read from the same location with .sig appended. Changed policies with an invalid signature are rejected.")
+	policyFrags   = flag.String("policy-fragments", "*", "Comma separated list of service provided policy fragments to combine with the policy. \"*\" uses all of them, empty none.")
+	policyData    = flag.String("policy-data", "", "Comma separated list of name=source entries of JSON data documents (i.e. oncall=https://example.com/oncall.json) available to the policy as data.external.<name>. Each source is a file or HTTP(S) URL.")
+	policyDataRef = flag.Duration("policy-data-refresh", opa.DefaultDataRefreshInterval, "How often to reload --policy-data documents.")
 	hostport      = flag.String("hostport", "localhost:50042", "Where to listen for connections.")
 	credSource    = flag.String("credential-source", mtlsFlags.Name(), fmt.Sprintf("Method used to obtain mTLS credentials (one of [%s])", strings.Join(mtls.Loaders(), ",")))
+	tlsMinVersion = flag.String("tls-min-version", "1.3", "Minimum TLS version to negotiate (1.2 or 1.3).")
+	tlsCiphers    = flag.String("tls-cipher-suites", "", "Comma separated list of allowed TLS 1.2 cipher suites (i.e. TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384). If empty Go's defaults are used.")
+	tlsCurves     = flag.String("tls-curves", "", "Comma separated list of key exchange curves in preference order (X25519, P256, P384, P521). If empty Go's defaults are used.")
 	verbosity     = flag.Int("v", 0, "Verbosity level. > 0 indicates more extensive logging")
 	validate      = flag.Bool("validate", false, "If true will evaluate the policy and then exit (non-zero on error)")
+	auditFile     = flag.String("audit-file", "", "If set, append a JSON record of every authorization decision to this file.")
+	auditSyslog   = flag.String("audit-syslog-tag", "", "If set, send a JSON record of every authorization decision to syslog (LOG_AUTHPRIV) with this tag.")
+	decisionLog   = flag.String("decision-log-url", "", "If set, upload a record of every authorization decision in OPA decision log format to this URL (i.e. https://example.com/logs).")
+	decisionToken = flag.String("decision-log-token-file", "", "File containing a bearer token for --decision-log-url.")
+	authzCacheTTL = flag.Duration("authz-cache-ttl", 0, "If non-zero, cache allow decisions for identical requests for this long. The cache is flushed when the policy changes.")
+	streamReauth  = flag.Duration("authz-stream-reauth-interval", 0, "If non-zero, re-evaluate the policy for open streams this often, ending those no longer allowed. Every message received on a stream is always authorized.")
+	reqApprovals  = flag.Bool("require-approvals", false, "If true, RPCs matching the policy's require_approval rule must be approved by another principal with the Approvals service before they run.")
+	rateLimits    = flag.Bool("authz-rate-limits", false, "If true, enforce per caller rate limits set by the policy's rate_limit rule (i.e. rate_limit = {\"rps\": 2, \"burst\": 5}).")
+	metricsAddr   = flag.String("metrics-addr", "", "If set, serve Prometheus metrics (including authorization decision counts and latency) at /metrics on this host:port.")
+	authzDryRun   = flag.Bool("authz-dry-run", false, "If true, requests denied by the policy are logged and audited but still permitted. For soaking a new policy before enforcing it.")
 	justification = flag.Bool("justification", false, "If true then justification (which is logged and possibly validated) must be passed along in the client context Metadata with the key '"+rpcauth.ReqJustKey+"'")
+	justFormat    = flag.String("justification-format", "", "If set, a regular expression (i.e. a ticket ID pattern) justifications must match. Requests with a non-matching justification are rejected. Policy can require a justification for specific methods with input.justification.")
+	oidcIssuers   = flag.String("oidc-issuers", "", "Comma separated list of OIDC issuer URLs whose ID tokens are accepted as bearer tokens. Token claims are available to policy as input.peer.token.")
+	oidcAudience  = flag.String("oidc-audience", "sansshell", "Audience OIDC bearer tokens must be issued for.")
+	oidcRequired  = flag.Bool("oidc-required", false, "If true RPCs without a valid OIDC bearer token are rejected.")
+	groupsProv    = flag.String("groups-provider", "", fmt.Sprintf("If set, the provider used to look up the groups of the principal for policy input.peer.principal.groups (one of [%s])", strings.Join(groups.Providers(), ",")))
+	groupsTTL     = flag.Duration("groups-cache-ttl", 5*time.Minute, "How long to cache group lookups for. Zero disables caching.")
+	grantsSource  = flag.String("grants-source", "", "File or HTTP(S) URL of a JSON document of time limited grants (principal -> method -> RFC 3339 expiry). The caller's unexpired grants are available to policy as input.grants.")
+	grantsRefresh = flag.Duration("grants-refresh", time.Minute, "How often to reload --grants-source.")
+	hintKeys      = flag.String("decision-hint-keys", "", "Comma separated list of issuer=file entries with the public keys (or certificates) of proxies whose decision hints are trusted. Verified hints are available to policy as input.proxy_decision.")
+	hintRequired  = flag.Bool("decision-hint-required", false, "If true RPCs without a valid proxy decision hint are rejected.")
+	delegKeys     = flag.String("delegation-keys", "", "Comma separated list of issuer=file entries with the public keys (or certificates) of proxies whose delegation tokens are trusted. Verified tokens are available to policy as input.delegation.")
+	delegAudience = flag.String("delegation-audience", "", "If set, delegation tokens must have been minted for this target (as the proxy names it, i.e. host:port).")
+	delegRequired = flag.Bool("delegation-required", false, "If true RPCs without a valid delegation token are rejected.")
+	pluginSockets = flag.String("plugin-sockets", "", "Comma separated list of Unix sockets of plugins whose services are served (and authorized) alongside the builtin ones.")
 )
 
 func main() {
@@ -71,10 +136,38 @@ func main() {
 	stdr.SetVerbosity(*verbosity)
 
 	policy := util.ChoosePolicy(logger, defaultPolicy, *policyFlag, *policyFile)
+	fragments := util.PolicyFragments(logger, *policyFrags)
 	ctx := logr.NewContext(context.Background(), logger)
 
+	if *policyURL != "" {
+		if *policyFlag != defaultPolicy || *policyFile != "" {
+			log.Fatal("do not set --policy-url with --policy or --policy-file")
+		}
+		logger.Info("using policy from --policy-url", "url", *policyURL)
+	}
+	if *policyKey != "" && *policyURL == "" && *policyFile == "" {
+		log.Fatal("--policy-public-key requires --policy-file or --policy-url")
+	}
+	verifier := util.PolicyVerifier(logger, *policyKey)
+
 	if *validate {
-		_, err := opa.NewAuthzPolicy(ctx, policy)
+		switch {
+		case *policyURL != "":
+			var opts []remote.Option
+			if verifier != nil {
+				opts = append(opts, remote.WithVerifier(verifier))
+			}
+			var err error
+			policy, _, err = remote.New(*policyURL, opts...).Fetch(ctx)
+			if err != nil {
+				log.Fatalf("Can't fetch policy: %v\n", err)
+			}
+		case verifier != nil:
+			if err := verifier.VerifyPolicy(ctx, *policyFile, []byte(policy)); err != nil {
+				log.Fatalf("Invalid policy signature: %v\n", err)
+			}
+		}
+		_, err := opa.NewAuthzPolicy(ctx, policy, opa.WithFragments(fragments))
 		if err != nil {
 			log.Fatalf("Invalid policy: %v\n", err)
 		}
@@ -83,11 +176,36 @@ func main() {
 	}
 
 	rs := server.RunState{
-		Logger:        logger,
-		CredSource:    *credSource,
-		Hostport:      *hostport,
-		Policy:        policy,
-		Justification: *justification,
+		Logger:                logger,
+		CredSource:            *credSource,
+		TLSOptions:            util.TLSOptions(logger, *tlsMinVersion, *tlsCiphers, *tlsCurves),
+		Hostport:              *hostport,
+		Policy:                policy,
+		PolicyFile:            *policyFile,
+		PolicyURL:             *policyURL,
+		Justification:         *justification,
+		JustificationFormat:   util.JustificationFormat(logger, *justFormat),
+		PolicyRefreshInterval: *policyRefresh,
+		PolicyFragments:       fragments,
+		PolicyData:            util.PolicyData(logger, *policyData),
+		PolicyDataRefresh:     *policyDataRef,
+		PolicyVerifier:        verifier,
+		AuditSinks:            util.AuditSinks(logger, *auditFile, *auditSyslog, *decisionLog, *decisionToken),
+		AuthzCacheTTL:         *authzCacheTTL,
+		AuthzDryRun:           *authzDryRun,
+		AuthzStreamReauth:     *streamReauth,
+		AuthzRateLimits:       *rateLimits,
+		AuthzMetrics:          util.AuthzMetrics(ctx, logger, *metricsAddr),
+	}
+	if *reqApprovals {
+		rs.Approvals = approvals.Gate()
 	}
-	server.Run(ctx, rs)
+	hooks := util.OIDCHooks(logger, *oidcIssuers, *oidcAudience, *oidcRequired)
+	hooks = append(hooks, util.DecisionHintHooks(logger, *hintKeys, *hintRequired)...)
+	hooks = append(hooks, util.DelegationHooks(logger, *delegKeys, *delegAudience, *delegRequired)...)
+	hooks = append(hooks, util.GroupHooks(logger, *groupsProv, *groupsTTL)...)
+	hooks = append(hooks, util.GrantsHooks(ctx, logger, *grantsSource, *grantsRefresh)...)
+	hooks = append(hooks, inspect.Hook())
+	util.Plugins(ctx, logger, *pluginSockets)
+	server.Run(ctx, rs, hooks...)
 }
@@ -0,0 +1,24 @@
+//go:build cgo
+// +build cgo
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package main
+
+// The PKCS#11 credential source loads vendor modules at runtime which
+// requires cgo, so it's only available in cgo enabled builds.
+import _ "github.com/Snowflake-Labs/sansshell/auth/mtls/pkcs11"
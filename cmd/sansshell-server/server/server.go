@@ -21,9 +21,15 @@ package server
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+	"github.com/Snowflake-Labs/sansshell/auth/opa"
+	"github.com/Snowflake-Labs/sansshell/auth/opa/remote"
 	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
 	"github.com/Snowflake-Labs/sansshell/server"
 	"github.com/go-logr/logr"
@@ -34,10 +40,36 @@ type RunState struct {
 	Logger logr.Logger
 	// CredSource is a registered credential source with the mtls package.
 	CredSource string
+	// TLSOptions adjust the TLS versions, cipher suites and curves used
+	// with the credentials from CredSource.
+	TLSOptions []mtls.TLSOption
 	// Hostport is the host:port to run the server.
 	Hostport string
 	// Policy is an OPA policy for determining authz decisions.
 	Policy string
+	// PolicyFile if set is the file Policy was read from. It will be watched
+	// for changes and the policy reloaded when it's updated.
+	PolicyFile string
+	// PolicyURL if set is an HTTP(S) URL to fetch the OPA policy (or bundle)
+	// from instead of using Policy.
+	PolicyURL string
+	// PolicyRefreshInterval is how often to check PolicyURL for changes.
+	// If unset remote.DefaultRefreshInterval is used.
+	PolicyRefreshInterval time.Duration
+	// PolicyFragments are additional policy modules (usually from
+	// services.PolicyFragments) compiled together with the policy.
+	PolicyFragments map[string]string
+	// PolicyVerifier if set must accept the policy from PolicyFile or
+	// PolicyURL (and any later changes to it) before it's used. It's an
+	// error to set it without one of them.
+	PolicyVerifier opa.PolicyVerifier
+	// PolicyData are external JSON documents (i.e. an on-call roster) keyed
+	// by name, each a file or HTTP(S) URL. They're available to the policy
+	// as data.external.<name> and reloaded every PolicyDataRefresh.
+	PolicyData map[string]string
+	// PolicyDataRefresh is how often to reload PolicyData. If unset
+	// opa.DefaultDataRefreshInterval is used.
+	PolicyDataRefresh time.Duration
 	// Justification if true requires justification to be set in the
 	// incoming RPC context Metadata (to the key defined in the telemetry package).
 	Justification bool
@@ -45,21 +77,130 @@ type RunState struct {
 	// entry is found. The supplied function can then do any validation it wants
 	// in order to ensure it's compliant.
 	JustificationFunc func(string) error
+	// JustificationFormat if set rejects requests with a justification
+	// which doesn't match it. See rpcauth.JustificationFormatHook.
+	JustificationFormat *regexp.Regexp
+	// AuditSinks receive a record of every authorization decision.
+	AuditSinks []rpcauth.AuditSink
+	// AuthzCacheTTL if non-zero caches allow decisions for this long.
+	// See rpcauth.DecisionCache.
+	AuthzCacheTTL time.Duration
+	// AuthzStreamReauth if non-zero re-authorizes open streams this often.
+	// See rpcauth.ReauthorizeStreams.
+	AuthzStreamReauth time.Duration
+	// AuthzDryRun if true logs and audits policy denials without enforcing
+	// them. See rpcauth.DryRun.
+	AuthzDryRun bool
+	// AuthzRateLimits if true enforces rate limits set by the policy.
+	// See rpcauth.RateLimits.
+	AuthzRateLimits bool
+	// AuthzMetrics if set records every authorization decision.
+	AuthzMetrics rpcauth.Metrics
+	// Approvals if set checks requests the policy requires approval for.
+	// See rpcauth.RequireApprovals.
+	Approvals rpcauth.ApprovalGate
 }
 
 // Run takes the given context and RunState and starts up a sansshell server.
+// Any hooks passed are run on every authz decision after the builtin ones.
 // As this is intended to be called from main() it doesn't return errors and will instead exit on any errors.
-func Run(ctx context.Context, rs RunState) {
-	creds, err := mtls.LoadServerCredentials(ctx, rs.CredSource)
+func Run(ctx context.Context, rs RunState, hooks ...rpcauth.RPCAuthzHook) {
+	creds, err := mtls.LoadServerCredentials(ctx, rs.CredSource, rs.TLSOptions...)
 	if err != nil {
 		rs.Logger.Error(err, "mtls.LoadServerCredentials", "credsource", rs.CredSource)
 		os.Exit(1)
 	}
 
+	policy := rs.Policy
+	var fetcher *remote.Fetcher
+	switch {
+	case rs.PolicyURL != "":
+		var opts []remote.Option
+		if rs.PolicyVerifier != nil {
+			opts = append(opts, remote.WithVerifier(rs.PolicyVerifier))
+		}
+		fetcher = remote.New(rs.PolicyURL, opts...)
+		policy, _, err = fetcher.Fetch(ctx)
+		if err != nil {
+			rs.Logger.Error(err, "remote.Fetch", "url", rs.PolicyURL)
+			os.Exit(1)
+		}
+	case rs.PolicyVerifier == nil:
+	case rs.PolicyFile != "":
+		if err := rs.PolicyVerifier.VerifyPolicy(ctx, rs.PolicyFile, []byte(policy)); err != nil {
+			rs.Logger.Error(err, "policy verification", "file", rs.PolicyFile)
+			os.Exit(1)
+		}
+	default:
+		rs.Logger.Error(errors.New("only policies from a file or URL can be verified"), "policy verification")
+		os.Exit(1)
+	}
+	authzPolicy, err := opa.NewAuthzPolicy(ctx, policy, opa.WithFragments(rs.PolicyFragments))
+	if err != nil {
+		rs.Logger.Error(err, "opa.NewAuthzPolicy")
+		os.Exit(1)
+	}
+	if len(rs.PolicyData) > 0 {
+		client := &http.Client{Timeout: 30 * time.Second}
+		refresh := rs.PolicyDataRefresh
+		if refresh == 0 {
+			refresh = opa.DefaultDataRefreshInterval
+		}
+		for name, source := range rs.PolicyData {
+			doc, err := opa.LoadData(ctx, client, source)
+			if err != nil {
+				rs.Logger.Error(err, "opa.LoadData", "data", name, "source", source)
+				os.Exit(1)
+			}
+			if err := authzPolicy.SetData(ctx, name, doc); err != nil {
+				rs.Logger.Error(err, "SetData", "data", name)
+				os.Exit(1)
+			}
+			go opa.PollData(ctx, client, name, source, refresh, authzPolicy)
+		}
+	}
+	switch {
+	case fetcher != nil:
+		interval := rs.PolicyRefreshInterval
+		if interval == 0 {
+			interval = remote.DefaultRefreshInterval
+		}
+		go fetcher.Poll(ctx, interval, authzPolicy)
+	case rs.PolicyFile != "":
+		go opa.WatchFileVerified(ctx, rs.PolicyFile, opa.DefaultWatchInterval, authzPolicy, rs.PolicyVerifier)
+	}
+
 	justificationHook := rpcauth.HookIf(rpcauth.JustificationHook(rs.JustificationFunc), func(input *rpcauth.RPCAuthInput) bool {
 		return rs.Justification
 	})
-	if err := server.Serve(rs.Hostport, creds, rs.Policy, rs.Logger, justificationHook); err != nil {
+	h := []rpcauth.RPCAuthzHook{justificationHook}
+	if rs.JustificationFormat != nil {
+		h = append(h, rpcauth.JustificationFormatHook(rs.JustificationFormat))
+	}
+	h = append(h, hooks...)
+	for _, s := range rs.AuditSinks {
+		h = append(h, rpcauth.AuditHook(s))
+	}
+	if rs.AuthzCacheTTL > 0 {
+		h = append(h, rpcauth.DecisionCache(rs.AuthzCacheTTL, 0))
+	}
+	if rs.AuthzStreamReauth > 0 {
+		h = append(h, rpcauth.ReauthorizeStreams(rs.AuthzStreamReauth))
+	}
+	if rs.AuthzDryRun {
+		rs.Logger.Info("authz dry run: policy denials will be logged but not enforced")
+		h = append(h, rpcauth.DryRun())
+	}
+	if rs.AuthzRateLimits {
+		h = append(h, rpcauth.RateLimits())
+	}
+	if rs.AuthzMetrics != nil {
+		h = append(h, rpcauth.MetricsHook(rs.AuthzMetrics))
+	}
+	if rs.Approvals != nil {
+		h = append(h, rpcauth.RequireApprovals(rs.Approvals))
+	}
+	if err := server.ServeWithAuthzPolicy(rs.Hostport, creds, authzPolicy, rs.Logger, h...); err != nil {
 		rs.Logger.Error(err, "server.Serve", "hostport", rs.Hostport)
 		os.Exit(1)
 	}
@@ -18,10 +18,32 @@
 package util
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
+	"log/syslog"
+	"net/http"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+
+	"github.com/Snowflake-Labs/sansshell/auth/delegation"
+	"github.com/Snowflake-Labs/sansshell/auth/enroll"
+	"github.com/Snowflake-Labs/sansshell/auth/grants"
+	"github.com/Snowflake-Labs/sansshell/auth/groups"
+	"github.com/Snowflake-Labs/sansshell/auth/mtls"
+	"github.com/Snowflake-Labs/sansshell/auth/oidc"
+	"github.com/Snowflake-Labs/sansshell/auth/opa"
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth/audit"
+	"github.com/Snowflake-Labs/sansshell/auth/opa/signature"
+	"github.com/Snowflake-Labs/sansshell/auth/proxyhint"
+	"github.com/Snowflake-Labs/sansshell/plugin"
+	"github.com/Snowflake-Labs/sansshell/services"
+	"github.com/Snowflake-Labs/sansshell/telemetry/metrics"
 )
 
 // ChoosePolicy selects an OPA policy based on the flags, or calls log.Fatal if
@@ -51,3 +73,420 @@ func ChoosePolicy(logger logr.Logger, defaultPolicy string, policyFlag string, p
 	}
 	return policy
 }
+
+// PolicyVerifier returns a verifier accepting policies signed by the public
+// key (or certificate) in keyFile, or exits if it can't be loaded. If keyFile
+// is empty nil is returned. See the signature package.
+func PolicyVerifier(logger logr.Logger, keyFile string) opa.PolicyVerifier {
+	if keyFile == "" {
+		return nil
+	}
+	v, err := signature.LoadVerifier(keyFile, signature.WithHTTPClient(&http.Client{Timeout: 30 * time.Second}))
+	if err != nil {
+		logger.Error(err, "signature.LoadVerifier", "file", keyFile)
+		os.Exit(1)
+	}
+	logger.Info("verifying policy signatures", "key", keyFile)
+	return v
+}
+
+// PolicyFragments returns the registered service policy fragments selected by
+// the comma separated list of names in `selected`, or exits if one isn't
+// registered. "*" selects all of them and "" none.
+func PolicyFragments(logger logr.Logger, selected string) map[string]string {
+	all := services.PolicyFragments()
+	switch selected {
+	case "*":
+		return all
+	case "":
+		return nil
+	}
+	out := make(map[string]string)
+	for _, name := range strings.Split(selected, ",") {
+		f, ok := all[name]
+		if !ok {
+			logger.Error(errors.New("unknown policy fragment"), "policy fragment not registered", "name", name)
+			os.Exit(1)
+		}
+		out[name] = f
+	}
+	return out
+}
+
+// PolicyData returns the policy data documents given as a comma separated
+// list of name=source entries, where each source is a file or HTTP(S) URL,
+// or exits if they're invalid. See opa.LoadData.
+func PolicyData(logger logr.Logger, data string) map[string]string {
+	if data == "" {
+		return nil
+	}
+	out := make(map[string]string)
+	for _, kv := range strings.Split(data, ",") {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			logger.Error(errors.New("invalid policy data"), "must be name=source", "data", kv)
+			os.Exit(1)
+		}
+		out[parts[0]] = parts[1]
+	}
+	return out
+}
+
+// AuditSinks returns the authz audit sinks selected by flags, or exits if any
+// can't be created. If auditFile is set decisions are appended to it as JSON lines.
+// If syslogTag is set decisions are sent to syslog with that tag. If
+// decisionLogURL is set decisions are uploaded there in OPA decision log
+// format, authenticated with the bearer token in decisionLogToken if set.
+func AuditSinks(logger logr.Logger, auditFile string, syslogTag string, decisionLogURL string, decisionLogToken string) []rpcauth.AuditSink {
+	var sinks []rpcauth.AuditSink
+	if auditFile != "" {
+		s, err := audit.NewFileSink(auditFile)
+		if err != nil {
+			logger.Error(err, "audit.NewFileSink", "file", auditFile)
+			os.Exit(1)
+		}
+		logger.Info("auditing authz decisions", "file", auditFile)
+		sinks = append(sinks, s)
+	}
+	if syslogTag != "" {
+		s, err := audit.NewSyslogSink(syslog.LOG_AUTHPRIV, syslogTag)
+		if err != nil {
+			logger.Error(err, "audit.NewSyslogSink")
+			os.Exit(1)
+		}
+		logger.Info("auditing authz decisions to syslog", "tag", syslogTag)
+		sinks = append(sinks, s)
+	}
+	if decisionLogURL != "" {
+		var opts []audit.DecisionLogOption
+		if host, err := os.Hostname(); err == nil {
+			opts = append(opts, audit.WithLabels(map[string]string{"id": host}))
+		}
+		if decisionLogToken != "" {
+			opts = append(opts, audit.WithBearerTokenFile(decisionLogToken))
+		}
+		logger.Info("uploading authz decisions to decision log service", "url", decisionLogURL)
+		sinks = append(sinks, audit.NewDecisionLogSink(decisionLogURL, logger, opts...))
+	}
+	return sinks
+}
+
+// OIDCHooks returns the authz hooks needed to accept OIDC bearer tokens from
+// the given comma separated list of issuers for audience, or exits if the
+// combination is invalid. If issuers is empty no hooks are returned.
+// If required is set RPCs without a token are rejected.
+func OIDCHooks(logger logr.Logger, issuers string, audience string, required bool) []rpcauth.RPCAuthzHook {
+	if issuers == "" {
+		if required {
+			logger.Error(errors.New("invalid oidc flags"), "--oidc-required needs --oidc-issuers")
+			os.Exit(1)
+		}
+		return nil
+	}
+	v, err := oidc.NewVerifier(audience, strings.Split(issuers, ","))
+	if err != nil {
+		logger.Error(err, "oidc.NewVerifier")
+		os.Exit(1)
+	}
+	logger.Info("accepting OIDC bearer tokens", "issuers", issuers, "audience", audience, "required", required)
+	return []rpcauth.RPCAuthzHook{oidc.Hook(v, required)}
+}
+
+// Enrollment serves certificate enrollment (see the enroll package) over
+// HTTPS on hostport until ctx is done, issuing certificates valid for ttl
+// signed by the CA in caCert/caKey to callers with an OIDC ID token from the
+// comma separated list of issuers for audience. The server certificate is
+// loaded from credSource. It exits on any error. If hostport is empty
+// nothing is served.
+func Enrollment(ctx context.Context, logger logr.Logger, credSource string, hostport string, caCert string, caKey string, ttl time.Duration, issuers string, audience string) {
+	if hostport == "" {
+		return
+	}
+	if issuers == "" {
+		logger.Error(errors.New("invalid enrollment flags"), "enrollment needs --oidc-issuers")
+		os.Exit(1)
+	}
+	v, err := oidc.NewVerifier(audience, strings.Split(issuers, ","))
+	if err != nil {
+		logger.Error(err, "oidc.NewVerifier")
+		os.Exit(1)
+	}
+	issuer, err := enroll.LoadIssuer(caCert, caKey, enroll.OIDC(v), enroll.WithTTL(ttl))
+	if err != nil {
+		logger.Error(err, "enroll.LoadIssuer", "cert", caCert)
+		os.Exit(1)
+	}
+	loader, err := mtls.Loader(credSource)
+	if err != nil {
+		logger.Error(err, "mtls.Loader", "credsource", credSource)
+		os.Exit(1)
+	}
+	cert, err := loader.LoadServerCertificate(ctx)
+	if err != nil {
+		logger.Error(err, "LoadServerCertificate", "credsource", credSource)
+		os.Exit(1)
+	}
+	srv := &http.Server{
+		Addr:              hostport,
+		Handler:           issuer,
+		TLSConfig:         &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: mtls.DefaultMinTLSVersion},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	go func() {
+		if err := srv.ListenAndServeTLS("", ""); err != http.ErrServerClosed {
+			logger.Error(err, "enrollment server", "hostport", hostport)
+			os.Exit(1)
+		}
+	}()
+	logger.Info("serving certificate enrollment", "hostport", hostport, "ttl", ttl)
+}
+
+// TLSOptions returns the mtls.TLSOptions for the TLS flags, or exits if
+// any of them are invalid. See mtls.ParseTLSOptions.
+func TLSOptions(logger logr.Logger, minVersion string, cipherSuites string, curves string) []mtls.TLSOption {
+	opts, err := mtls.ParseTLSOptions(minVersion, cipherSuites, curves)
+	if err != nil {
+		logger.Error(err, "invalid TLS flags")
+		os.Exit(1)
+	}
+	return opts
+}
+
+// BackendSANs parses the expected backend identities given by spec (see
+// mtls.ParseSANRules), or exits if it's invalid.
+func BackendSANs(logger logr.Logger, spec string) []mtls.SANRule {
+	rules, err := mtls.ParseSANRules(spec)
+	if err != nil {
+		logger.Error(err, "invalid --backend-sans")
+		os.Exit(1)
+	}
+	return rules
+}
+
+// JustificationFormat compiles the regular expression justifications must
+// match, or exits if it's invalid. If format is empty nil is returned.
+func JustificationFormat(logger logr.Logger, format string) *regexp.Regexp {
+	if format == "" {
+		return nil
+	}
+	re, err := regexp.Compile(format)
+	if err != nil {
+		logger.Error(err, "invalid justification format", "format", format)
+		os.Exit(1)
+	}
+	return re
+}
+
+// DecisionHintSigner returns a signer for proxy decision hints using the
+// private key in keyFile, or exits if it can't be loaded. If keyFile is empty
+// nil is returned. An empty issuer defaults to the hostname.
+func DecisionHintSigner(logger logr.Logger, keyFile string, issuer string, ttl time.Duration) *proxyhint.Signer {
+	if keyFile == "" {
+		return nil
+	}
+	if issuer == "" {
+		var err error
+		issuer, err = os.Hostname()
+		if err != nil {
+			logger.Error(err, "os.Hostname")
+			os.Exit(1)
+		}
+	}
+	s, err := proxyhint.LoadSigner(issuer, keyFile, ttl)
+	if err != nil {
+		logger.Error(err, "proxyhint.LoadSigner", "file", keyFile)
+		os.Exit(1)
+	}
+	logger.Info("signing decision hints", "issuer", issuer)
+	return s
+}
+
+// DecisionHintHooks returns the authz hooks needed to verify proxy decision
+// hints signed by the keys given as a comma separated list of issuer=file
+// entries, or exits if they're invalid. If keys is empty no hooks are returned.
+// If required is set RPCs without a valid hint are rejected.
+func DecisionHintHooks(logger logr.Logger, keys string, required bool) []rpcauth.RPCAuthzHook {
+	if keys == "" {
+		if required {
+			logger.Error(errors.New("invalid decision hint flags"), "--decision-hint-required needs --decision-hint-keys")
+			os.Exit(1)
+		}
+		return nil
+	}
+	files := make(map[string]string)
+	for _, kv := range strings.Split(keys, ",") {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			logger.Error(errors.New("invalid decision hint key"), "must be issuer=file", "key", kv)
+			os.Exit(1)
+		}
+		files[parts[0]] = parts[1]
+	}
+	v, err := proxyhint.LoadVerifier(files)
+	if err != nil {
+		logger.Error(err, "proxyhint.LoadVerifier")
+		os.Exit(1)
+	}
+	logger.Info("verifying proxy decision hints", "keys", keys, "required", required)
+	return []rpcauth.RPCAuthzHook{proxyhint.Hook(v, required)}
+}
+
+// DelegationMinter returns a minter for delegation tokens using the private
+// key in keyFile, or exits if it can't be loaded. If keyFile is empty nil is
+// returned. An empty issuer defaults to the hostname.
+func DelegationMinter(logger logr.Logger, keyFile string, issuer string, ttl time.Duration) *delegation.Minter {
+	if keyFile == "" {
+		return nil
+	}
+	if issuer == "" {
+		var err error
+		issuer, err = os.Hostname()
+		if err != nil {
+			logger.Error(err, "os.Hostname")
+			os.Exit(1)
+		}
+	}
+	m, err := delegation.LoadMinter(issuer, keyFile, ttl)
+	if err != nil {
+		logger.Error(err, "delegation.LoadMinter", "file", keyFile)
+		os.Exit(1)
+	}
+	logger.Info("minting delegation tokens", "issuer", issuer, "ttl", ttl)
+	return m
+}
+
+// DelegationHooks returns the authz hooks needed to verify delegation tokens
+// signed by the keys given as a comma separated list of issuer=file entries,
+// or exits if they're invalid. If keys is empty no hooks are returned. If
+// audience is set tokens must be for it and if required is set RPCs without
+// a valid token are rejected.
+func DelegationHooks(logger logr.Logger, keys string, audience string, required bool) []rpcauth.RPCAuthzHook {
+	if keys == "" {
+		if required {
+			logger.Error(errors.New("invalid delegation flags"), "--delegation-required needs --delegation-keys")
+			os.Exit(1)
+		}
+		return nil
+	}
+	files := make(map[string]string)
+	for _, kv := range strings.Split(keys, ",") {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			logger.Error(errors.New("invalid delegation key"), "must be issuer=file", "key", kv)
+			os.Exit(1)
+		}
+		files[parts[0]] = parts[1]
+	}
+	v, err := delegation.LoadVerifier(files)
+	if err != nil {
+		logger.Error(err, "delegation.LoadVerifier")
+		os.Exit(1)
+	}
+	logger.Info("verifying delegation tokens", "keys", keys, "audience", audience, "required", required)
+	return []rpcauth.RPCAuthzHook{delegation.Hook(v, audience, required)}
+}
+
+// AuthzMetrics returns Metrics recording authorization decisions which are
+// served (along with Go runtime metrics) for Prometheus at /metrics on addr
+// until ctx is done, exiting if that fails. If addr is empty nil is returned.
+func AuthzMetrics(ctx context.Context, logger logr.Logger, addr string) rpcauth.Metrics {
+	if addr == "" {
+		return nil
+	}
+	reg := metrics.NewRegistry()
+	m, err := metrics.NewAuthzMetrics(reg)
+	if err != nil {
+		logger.Error(err, "metrics.NewAuthzMetrics")
+		os.Exit(1)
+	}
+	go func() {
+		if err := metrics.Serve(ctx, addr, reg); err != nil {
+			logger.Error(err, "metrics.Serve", "addr", addr)
+			os.Exit(1)
+		}
+	}()
+	logger.Info("serving metrics", "addr", addr)
+	return m
+}
+
+// GroupHooks returns the authz hooks needed to add the principal's groups
+// from the named groups.Provider to policy input, or exits if it isn't
+// registered. If provider is empty no hooks are returned. If cacheTTL is
+// non-zero lookups are cached for that long.
+func GroupHooks(logger logr.Logger, provider string, cacheTTL time.Duration) []rpcauth.RPCAuthzHook {
+	if provider == "" {
+		return nil
+	}
+	p, err := groups.Lookup(provider)
+	if err != nil {
+		logger.Error(err, "groups.Lookup")
+		os.Exit(1)
+	}
+	if cacheTTL > 0 {
+		p = groups.Cached(p, cacheTTL)
+	}
+	logger.Info("looking up principal groups", "provider", provider, "cachettl", cacheTTL)
+	return []rpcauth.RPCAuthzHook{groups.Hook(p)}
+}
+
+// GrantsHooks returns the authz hooks needed to add the caller's time limited
+// grants loaded from source (a file or URL) to policy input, or exits if they
+// can't be loaded. The grants are then reloaded every refresh until ctx is
+// done. If source is empty no hooks are returned.
+func GrantsHooks(ctx context.Context, logger logr.Logger, source string, refresh time.Duration) []rpcauth.RPCAuthzHook {
+	if source == "" {
+		return nil
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	g, err := grants.Load(ctx, client, source)
+	if err != nil {
+		logger.Error(err, "grants.Load", "source", source)
+		os.Exit(1)
+	}
+	s := &grants.Store{}
+	s.Set(g)
+	if refresh == 0 {
+		refresh = grants.DefaultRefreshInterval
+	}
+	go grants.Poll(ctx, client, source, refresh, s)
+	logger.Info("loaded access grants", "source", source, "refresh", refresh)
+	return []rpcauth.RPCAuthzHook{grants.Hook(s)}
+}
+
+// Plugins loads the plugins listening on the comma separated list of Unix
+// sockets and registers their services to be served. Plugins which can't be
+// loaded are logged and skipped so they can't prevent the server starting.
+func Plugins(ctx context.Context, logger logr.Logger, sockets string) {
+	if sockets == "" {
+		return
+	}
+	for _, socket := range strings.Split(sockets, ",") {
+		p, err := plugin.Load(ctx, socket)
+		if err != nil {
+			logger.Error(err, "plugin.Load", "socket", socket)
+			continue
+		}
+		services.RegisterSansShellService(p)
+		logger.Info("loaded plugin", "socket", socket, "services", p.Services())
+	}
+}
+
+// PluginDescriptors registers the plugin service definitions in the comma
+// separated list of FileDescriptorSet files so they can be proxied, or exits
+// if any can't be loaded.
+func PluginDescriptors(logger logr.Logger, files string) {
+	if files == "" {
+		return
+	}
+	for _, file := range strings.Split(files, ",") {
+		if err := plugin.LoadDescriptorSetFile(file); err != nil {
+			logger.Error(err, "plugin.LoadDescriptorSetFile", "file", file)
+			os.Exit(1)
+		}
+		logger.Info("loaded plugin descriptors", "file", file)
+	}
+}
@@ -0,0 +1,471 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package plugin lets services run as separate local binaries (plugins)
+// rather than being linked into the sansshell server, so they can be added
+// without forking or rebuilding it.
+//
+// A plugin serves its gRPC services on a Unix socket with Serve:
+//
+//	func main() {
+//		err := plugin.Serve(ctx, "/run/sansshell/plugins/foo.sock", func(s *grpc.Server) {
+//			foopb.RegisterFooServer(s, &fooServer{})
+//		})
+//		...
+//	}
+//
+// and the server is started with --plugin-sockets naming the socket. At
+// startup the server loads the plugin's service definitions from it with
+// gRPC reflection and re-exports its services, forwarding calls to the
+// plugin. Every request is authorized by the server's policy as for any
+// other service before it's forwarded. Plugins should be running before the
+// server starts, and the server restarted if their services change.
+//
+// Proxies don't talk to plugins so they need the definitions of plugin
+// services another way, given to proxy-server with --plugin-descriptors as
+// a FileDescriptorSet (i.e. from protoc --include_imports
+// --descriptor_set_out). Clients are built from the plugin's protos as for
+// any other service.
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Serve serves the services `register` adds to a gRPC server on the Unix
+// socket `socket` until ctx is done. Any existing file at socket is
+// replaced. The socket is only accessible by its owner (and root) as calls
+// made to it directly aren't authorized by the sansshell server.
+func Serve(ctx context.Context, socket string, register func(*grpc.Server), opts ...grpc.ServerOption) error {
+	if err := os.Remove(socket); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	lis, err := listen(socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socket)
+	s := grpc.NewServer(opts...)
+	register(s)
+	// The server finds out what the plugin provides with reflection.
+	reflection.Register(s)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.GracefulStop()
+		case <-done:
+		}
+	}()
+	if err := s.Serve(lis); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// listen listens on the Unix socket `socket`, which is created in a private
+// directory and then moved into place so it's never accessible by anyone
+// but its owner.
+func listen(socket string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(socket), ".plugin-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, filepath.Base(socket))
+	lis, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	// The socket is removed from its final path once served instead.
+	lis.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, 0600); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, socket); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	return lis, nil
+}
+
+// A Plugin is a connection to a plugin whose services can be registered
+// with a sansshell server. It implements services.SansShellRPCService.
+type Plugin struct {
+	socket   string
+	conn     *grpc.ClientConn
+	services []protoreflect.ServiceDescriptor
+	logger   logr.Logger
+}
+
+// Load connects to the plugin listening on `socket` and registers the
+// definitions of its services (and the messages they use) in
+// protoregistry.GlobalFiles (and their messages as dynamic types in
+// protoregistry.GlobalTypes). The connection is kept open until Close.
+func Load(ctx context.Context, socket string) (*Plugin, error) {
+	conn, err := grpc.DialContext(ctx, "unix:"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	names, files, err := describe(ctx, conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("can't describe plugin %s: %v", socket, err)
+	}
+	if err := registerFiles(protoregistry.GlobalFiles, protoregistry.GlobalTypes, files); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("can't register definitions of plugin %s: %v", socket, err)
+	}
+	p := &Plugin{
+		socket: socket,
+		conn:   conn,
+		logger: logr.FromContextOrDiscard(ctx).WithValues("plugin", socket),
+	}
+	for _, name := range names {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("plugin %s didn't describe its service %s: %v", socket, name, err)
+		}
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			conn.Close()
+			return nil, fmt.Errorf("plugin %s described %s as a %T rather than a service", socket, name, d)
+		}
+		p.services = append(p.services, sd)
+	}
+	return p, nil
+}
+
+// Services returns the full names of the plugin's services.
+func (p *Plugin) Services() []string {
+	var out []string
+	for _, sd := range p.services {
+		out = append(out, string(sd.FullName()))
+	}
+	return out
+}
+
+// Close closes the connection to the plugin.
+func (p *Plugin) Close() error {
+	return p.conn.Close()
+}
+
+// Register registers the plugin's services with gs, forwarding calls to the
+// plugin. Services gs already has are skipped.
+func (p *Plugin) Register(gs *grpc.Server) {
+	registered := gs.GetServiceInfo()
+	for _, sd := range p.services {
+		name := string(sd.FullName())
+		if _, ok := registered[name]; ok {
+			p.logger.Info("skipping plugin service which is already registered", "service", name)
+			continue
+		}
+		desc := &grpc.ServiceDesc{
+			ServiceName: name,
+			HandlerType: (*interface{})(nil),
+			Metadata:    sd.ParentFile().Path(),
+		}
+		methods := sd.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			md := methods.Get(i)
+			// Everything is registered as a stream (which is the same on the
+			// wire) so the request messages are read, and authorized, as
+			// they're forwarded.
+			desc.Streams = append(desc.Streams, grpc.StreamDesc{
+				StreamName:    string(md.Name()),
+				Handler:       p.forward(md),
+				ServerStreams: md.IsStreamingServer(),
+				ClientStreams: md.IsStreamingClient(),
+			})
+		}
+		gs.RegisterService(desc, p)
+		p.logger.Info("registered plugin service", "service", name)
+	}
+}
+
+// forward returns a handler passing calls of method md to the plugin.
+func (p *Plugin) forward(md protoreflect.MethodDescriptor) grpc.StreamHandler {
+	method := fmt.Sprintf("/%s/%s", md.Parent().FullName(), md.Name())
+	desc := &grpc.StreamDesc{
+		ServerStreams: md.IsStreamingServer(),
+		ClientStreams: md.IsStreamingClient(),
+	}
+	return func(srv interface{}, ss grpc.ServerStream) error {
+		ctx, cancel := context.WithCancel(ss.Context())
+		defer cancel()
+
+		// Only call the plugin once the first request has been received
+		// (and so authorized). A client streaming call ending without any
+		// requests is denied as there's nothing to authorize.
+		first := dynamicpb.NewMessage(md.Input())
+		if err := ss.RecvMsg(first); err != nil {
+			if err == io.EOF {
+				return status.Errorf(codes.PermissionDenied, "%s called without a request to authorize", method)
+			}
+			return err
+		}
+		cs, err := p.conn.NewStream(ctx, desc, method)
+		if err != nil {
+			return err
+		}
+		recvErr := make(chan error, 1)
+		go func() {
+			msg := first
+			for {
+				if err := cs.SendMsg(msg); err != nil {
+					// The real error comes from RecvMsg below.
+					recvErr <- nil
+					return
+				}
+				if !md.IsStreamingClient() {
+					recvErr <- cs.CloseSend()
+					return
+				}
+				msg = dynamicpb.NewMessage(md.Input())
+				if err := ss.RecvMsg(msg); err != nil {
+					if err == io.EOF {
+						recvErr <- cs.CloseSend()
+						return
+					}
+					// i.e. a later request wasn't authorized, which
+					// ends the call.
+					recvErr <- err
+					cancel()
+					return
+				}
+			}
+		}()
+
+		for header := true; ; header = false {
+			resp := dynamicpb.NewMessage(md.Output())
+			err := cs.RecvMsg(resp)
+			if header {
+				if h, err := cs.Header(); err == nil {
+					ss.SetHeader(h)
+				}
+			}
+			if err != nil {
+				ss.SetTrailer(cs.Trailer())
+				select {
+				case rerr := <-recvErr:
+					if rerr != nil {
+						return rerr
+					}
+				default:
+				}
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			if err := ss.SendMsg(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// describe uses reflection to find the services `conn` provides (other than
+// gRPC's own) and the definitions of everything they use.
+func describe(ctx context.Context, conn *grpc.ClientConn) ([]string, []*descriptorpb.FileDescriptorProto, error) {
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer stream.CloseSend()
+	call := func(req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+		if err := stream.Send(req); err != nil {
+			return nil, err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if e := resp.GetErrorResponse(); e != nil {
+			return nil, fmt.Errorf("reflection error %d: %s", e.ErrorCode, e.ErrorMessage)
+		}
+		return resp, nil
+	}
+
+	resp, err := call(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_ListServices{}})
+	if err != nil {
+		return nil, nil, err
+	}
+	var names []string
+	var files []*descriptorpb.FileDescriptorProto
+	seen := make(map[string]bool)
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		if strings.HasPrefix(s.Name, "grpc.") {
+			// Reflection, health checking etc.
+			continue
+		}
+		names = append(names, s.Name)
+		resp, err := call(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: s.Name}})
+		if err != nil {
+			return nil, nil, fmt.Errorf("describing %s: %v", s.Name, err)
+		}
+		// The file and any dependencies not already sent.
+		for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fdp := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(b, fdp); err != nil {
+				return nil, nil, fmt.Errorf("can't parse description of %s: %v", s.Name, err)
+			}
+			if !seen[fdp.GetName()] {
+				seen[fdp.GetName()] = true
+				files = append(files, fdp)
+			}
+		}
+	}
+	return names, files, nil
+}
+
+// RegisterDescriptorSet registers the files in set in
+// protoregistry.GlobalFiles and their messages as dynamic types in
+// protoregistry.GlobalTypes, i.e. for the proxy to learn of plugin services.
+// Files which are already registered (by path) are skipped. Anything else
+// conflicting with an existing definition is an error.
+func RegisterDescriptorSet(set *descriptorpb.FileDescriptorSet) error {
+	return registerFiles(protoregistry.GlobalFiles, protoregistry.GlobalTypes, set.GetFile())
+}
+
+// LoadDescriptorSetFile reads a serialized FileDescriptorSet from `file`
+// and registers it with RegisterDescriptorSet.
+func LoadDescriptorSetFile(file string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, set); err != nil {
+		return fmt.Errorf("can't parse %s: %v", file, err)
+	}
+	if err := RegisterDescriptorSet(set); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	return nil
+}
+
+// registerFiles registers fdps in `files`, in whatever order their
+// dependencies allow, and their messages in `types`.
+func registerFiles(files *protoregistry.Files, types *protoregistry.Types, fdps []*descriptorpb.FileDescriptorProto) error {
+	var pending []*descriptorpb.FileDescriptorProto
+	for _, fdp := range fdps {
+		if _, err := files.FindFileByPath(fdp.GetName()); err != nil {
+			pending = append(pending, fdp)
+		}
+	}
+	for len(pending) > 0 {
+		var next []*descriptorpb.FileDescriptorProto
+		var lastErr error
+		for _, fdp := range pending {
+			fd, err := protodesc.NewFile(fdp, files)
+			if err != nil {
+				// Most likely a dependency isn't registered yet.
+				next = append(next, fdp)
+				lastErr = err
+				continue
+			}
+			// Registering a conflicting file in GlobalFiles panics.
+			if err := conflicts(files, fd); err != nil {
+				return err
+			}
+			if err := files.RegisterFile(fd); err != nil {
+				return err
+			}
+			// The proxy needs types to unpack requests sent to it as Anys.
+			if err := registerMessages(types, fd.Messages()); err != nil {
+				return err
+			}
+		}
+		if len(next) == len(pending) {
+			return lastErr
+		}
+		pending = next
+	}
+	return nil
+}
+
+// registerMessages registers dynamic types for msgs and any nested messages
+// in `types`, skipping those which already have one.
+func registerMessages(types *protoregistry.Types, msgs protoreflect.MessageDescriptors) error {
+	for i := 0; i < msgs.Len(); i++ {
+		md := msgs.Get(i)
+		if _, err := types.FindMessageByName(md.FullName()); err != nil {
+			if err := types.RegisterMessage(dynamicpb.NewMessageType(md)); err != nil {
+				return err
+			}
+		}
+		if err := registerMessages(types, md.Messages()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conflicts returns an error if anything fd declares is already registered
+// in `files`.
+func conflicts(files *protoregistry.Files, fd protoreflect.FileDescriptor) error {
+	var names []protoreflect.FullName
+	for i := 0; i < fd.Messages().Len(); i++ {
+		names = append(names, fd.Messages().Get(i).FullName())
+	}
+	for i := 0; i < fd.Enums().Len(); i++ {
+		e := fd.Enums().Get(i)
+		names = append(names, e.FullName())
+		// Enum values are scoped alongside the enum itself.
+		for j := 0; j < e.Values().Len(); j++ {
+			names = append(names, e.Values().Get(j).FullName())
+		}
+	}
+	for i := 0; i < fd.Extensions().Len(); i++ {
+		names = append(names, fd.Extensions().Get(i).FullName())
+	}
+	for i := 0; i < fd.Services().Len(); i++ {
+		names = append(names, fd.Services().Get(i).FullName())
+	}
+	for _, name := range names {
+		if d, err := files.FindDescriptorByName(name); err == nil {
+			return fmt.Errorf("%s in %s is already defined in %s", name, fd.Path(), d.ParentFile().Path())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	hcpb "github.com/Snowflake-Labs/sansshell/services/healthcheck"
+	lfpb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const policy = `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.method = "/HealthCheck.HealthCheck/Ok"
+}
+
+allow {
+  input.method = "/LocalFile.LocalFile/Read"
+  input.message.file.filename = "/allowed"
+}
+
+allow {
+  input.method = "/LocalFile.LocalFile/Stat"
+  input.message.filename != "/denied"
+}
+`
+
+type healthServer struct {
+	hcpb.UnimplementedHealthCheckServer
+}
+
+func (healthServer) Ok(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+type fileServer struct {
+	lfpb.UnimplementedLocalFileServer
+}
+
+func (fileServer) Read(req *lfpb.ReadActionRequest, stream lfpb.LocalFile_ReadServer) error {
+	for _, s := range []string{"hello ", "world"} {
+		if err := stream.Send(&lfpb.ReadReply{Contents: []byte(s)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fileServer) Stat(stream lfpb.LocalFile_StatServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if req.Filename == "/missing" {
+			return status.Errorf(codes.NotFound, "%s not found", req.Filename)
+		}
+		if err := stream.Send(&lfpb.StatReply{Filename: req.Filename, Size: 1}); err != nil {
+			return err
+		}
+	}
+}
+
+// startPlugin serves the test services on a socket returned by Load.
+func startPlugin(t *testing.T) *Plugin {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	socket := filepath.Join(t.TempDir(), "plugin.sock")
+	served := make(chan error, 1)
+	ready := make(chan struct{})
+	go func() {
+		served <- Serve(ctx, socket, func(s *grpc.Server) {
+			hcpb.RegisterHealthCheckServer(s, healthServer{})
+			lfpb.RegisterLocalFileServer(s, fileServer{})
+			close(ready)
+		})
+	}()
+	<-ready
+	fi, err := os.Stat(socket)
+	testutil.FatalOnErr("Stat", err, t)
+	if got := fi.Mode().Perm(); got != 0600 {
+		t.Errorf("socket mode = %v, want 0600", got)
+	}
+	entries, err := os.ReadDir(filepath.Dir(socket))
+	testutil.FatalOnErr("ReadDir", err, t)
+	if len(entries) != 1 {
+		t.Errorf("socket directory has %d entries, want only the socket", len(entries))
+	}
+	t.Cleanup(func() {
+		cancel()
+		if err := <-served; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+	p, err := Load(ctx, socket)
+	testutil.FatalOnErr("Load", err, t)
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestPlugin(t *testing.T) {
+	ctx := context.Background()
+	p := startPlugin(t)
+	if got, want := p.Services(), []string{"HealthCheck.HealthCheck", "LocalFile.LocalFile"}; !cmp.Equal(got, want) {
+		t.Fatalf("Services() = %v, want %v", got, want)
+	}
+
+	authz, err := rpcauth.NewWithPolicy(ctx, policy)
+	testutil.FatalOnErr("NewWithPolicy", err, t)
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.StreamInterceptor(authz.AuthorizeStream), grpc.UnaryInterceptor(authz.Authorize))
+	p.Register(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("DialContext", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	_, err = hcpb.NewHealthCheckClient(conn).Ok(ctx, &emptypb.Empty{})
+	testutil.FatalOnErr("Ok", err, t)
+
+	lf := lfpb.NewLocalFileClient(conn)
+	read := func(filename string) (string, error) {
+		stream, err := lf.Read(ctx, &lfpb.ReadActionRequest{Request: &lfpb.ReadActionRequest_File{File: &lfpb.ReadRequest{Filename: filename}}})
+		if err != nil {
+			return "", err
+		}
+		var out string
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return out, nil
+			}
+			if err != nil {
+				return out, err
+			}
+			out += string(resp.Contents)
+		}
+	}
+	got, err := read("/allowed")
+	testutil.FatalOnErr("Read", err, t)
+	if got != "hello world" {
+		t.Errorf("Read = %q, want %q", got, "hello world")
+	}
+	if _, err := read("/other"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Read of /other: got %v, want PermissionDenied", err)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		filenames []string
+		want      []*lfpb.StatReply
+		wantCode  codes.Code
+	}{
+		{
+			name:      "allowed",
+			filenames: []string{"/a", "/b"},
+			want:      []*lfpb.StatReply{{Filename: "/a", Size: 1}, {Filename: "/b", Size: 1}},
+		},
+		{
+			name:      "none denied",
+			filenames: nil,
+			wantCode:  codes.PermissionDenied,
+		},
+		{
+			name:      "later request denied",
+			filenames: []string{"/a", "/denied"},
+			want:      []*lfpb.StatReply{{Filename: "/a", Size: 1}},
+			wantCode:  codes.PermissionDenied,
+		},
+		{
+			name:      "plugin error",
+			filenames: []string{"/missing"},
+			wantCode:  codes.NotFound,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := lf.Stat(ctx)
+			testutil.FatalOnErr("Stat", err, t)
+			var got []*lfpb.StatReply
+			for _, f := range tc.filenames {
+				if err := stream.Send(&lfpb.StatRequest{Filename: f}); err != nil {
+					break
+				}
+				resp, err := stream.Recv()
+				if err != nil {
+					break
+				}
+				got = append(got, resp)
+			}
+			stream.CloseSend()
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					err = nil
+				}
+				if err != nil || resp == nil {
+					if status.Code(err) != tc.wantCode {
+						t.Errorf("got error %v, want code %v", err, tc.wantCode)
+					}
+					break
+				}
+				got = append(got, resp)
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected replies (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegisterSkipsExisting(t *testing.T) {
+	p := startPlugin(t)
+	s := grpc.NewServer()
+	hcpb.RegisterHealthCheckServer(s, healthServer{})
+	// Would panic if HealthCheck were registered again.
+	p.Register(s)
+	if _, ok := s.GetServiceInfo()["LocalFile.LocalFile"]; !ok {
+		t.Errorf("LocalFile.LocalFile not registered: %v", s.GetServiceInfo())
+	}
+}
+
+func TestRegisterFiles(t *testing.T) {
+	dep := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("dep.proto"),
+		Package: proto.String("Dep"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Thing")},
+		},
+	}
+	svc := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("svc.proto"),
+		Package:    proto.String("Svc"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"dep.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("Svc"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("Get"), InputType: proto.String(".Dep.Thing"), OutputType: proto.String(".Dep.Thing")},
+				},
+			},
+		},
+	}
+	clash := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("clash.proto"),
+		Package: proto.String("Dep"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Thing")},
+		},
+	}
+	broken := proto.Clone(svc).(*descriptorpb.FileDescriptorProto)
+	broken.Name = proto.String("broken.proto")
+	broken.Dependency = []string{"missing.proto"}
+
+	files := &protoregistry.Files{}
+	types := &protoregistry.Types{}
+	// Dependencies are registered first regardless of order.
+	testutil.FatalOnErr("registerFiles", registerFiles(files, types, []*descriptorpb.FileDescriptorProto{svc, dep}), t)
+	if _, err := files.FindDescriptorByName("Svc.Svc"); err != nil {
+		t.Errorf("Svc.Svc not registered: %v", err)
+	}
+	if _, err := types.FindMessageByName("Dep.Thing"); err != nil {
+		t.Errorf("Dep.Thing type not registered: %v", err)
+	}
+	// Already registered files are skipped.
+	testutil.FatalOnErr("registerFiles again", registerFiles(files, types, []*descriptorpb.FileDescriptorProto{dep}), t)
+	for _, tc := range []struct {
+		name string
+		fdps []*descriptorpb.FileDescriptorProto
+	}{
+		{name: "conflict", fdps: []*descriptorpb.FileDescriptorProto{clash}},
+		{name: "missing dependency", fdps: []*descriptorpb.FileDescriptorProto{broken}},
+	} {
+		if err := registerFiles(files, types, tc.fdps); err == nil {
+			t.Errorf("%s: registerFiles didn't fail", tc.name)
+		}
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Load(ctx, filepath.Join(t.TempDir(), "none.sock")); err == nil {
+		t.Errorf("Load of missing socket didn't fail")
+	}
+}
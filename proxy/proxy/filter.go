@@ -0,0 +1,64 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package proxy
+
+import "context"
+
+// A MethodFilter reports which of a Conn's targets implement `method`,
+// i.e. based on their capabilities. The result is indexed like Conn.Targets.
+// A nil result means every target should be called.
+type MethodFilter func(ctx context.Context, method string) []bool
+
+// SetMethodFilter makes calls through the proxy skip targets which `f`
+// reports don't implement the method being called. Skipped targets aren't
+// sent the request and get no response, so `f` should record them if the
+// caller needs to report them. If no target implements the method it's sent
+// to all of them so callers see their errors. A nil filter (the default)
+// calls every target.
+func (p *Conn) SetMethodFilter(f MethodFilter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.methodFilter = f
+}
+
+func (p *Conn) methodFilterFunc() MethodFilter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.methodFilter
+}
+
+// targetsFor returns the indices of the targets to call `method` on.
+func (p *Conn) targetsFor(ctx context.Context, method string) []int {
+	f := p.methodFilterFunc()
+	if f == nil {
+		return p.allTargets()
+	}
+	implements := f(ctx, method)
+	if implements == nil {
+		return p.allTargets()
+	}
+	var out []int
+	for i := range p.Targets {
+		if i < len(implements) && implements[i] {
+			out = append(out, i)
+		}
+	}
+	if len(out) == 0 {
+		return p.allTargets()
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package proxy_test
+
+import (
+	"context"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	tdpb "github.com/Snowflake-Labs/sansshell/proxy/testdata"
+	"github.com/Snowflake-Labs/sansshell/proxy/testutil"
+	tu "github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestMethodFilter(t *testing.T) {
+	ctx := context.Background()
+	targets := []string{"foo:123", "bar:123", "baz:123"}
+	for _, tc := range []struct {
+		name        string
+		implements  []bool
+		wantTargets []string
+	}{
+		{
+			name:        "no filter",
+			wantTargets: targets,
+		},
+		{
+			name:        "some skipped",
+			implements:  []bool{true, false, true},
+			wantTargets: []string{"baz:123", "foo:123"},
+		},
+		{
+			name:        "none implement",
+			implements:  []bool{false, false, false},
+			wantTargets: targets,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			bufMap := testutil.StartTestDataServers(t, targets...)
+			for k, v := range startTestProxy(ctx, t, bufMap) {
+				bufMap[k] = v
+			}
+			conn, err := proxy.Dial("proxy", targets, testutil.WithBufDialer(bufMap), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			tu.FatalOnErr("Dial", err, t)
+			defer conn.Close()
+			var methods []string
+			if tc.implements != nil {
+				conn.SetMethodFilter(func(ctx context.Context, method string) []bool {
+					methods = append(methods, method)
+					return tc.implements
+				})
+			}
+			ts := tdpb.NewTestServiceClientProxy(conn)
+
+			resp, err := ts.TestUnaryOneMany(ctx, &tdpb.TestRequest{Input: "input"})
+			tu.FatalOnErr("TestUnaryOneMany", err, t)
+			var got []string
+			for r := range resp {
+				tu.FatalOnErr(r.Target, r.Error, t)
+				got = append(got, r.Target)
+			}
+			sort.Strings(got)
+			want := append([]string{}, tc.wantTargets...)
+			sort.Strings(want)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("unary: unexpected targets (-want +got):\n%s", diff)
+			}
+
+			stream, err := ts.TestServerStreamOneMany(ctx, &tdpb.TestRequest{Input: "input"})
+			tu.FatalOnErr("TestServerStreamOneMany", err, t)
+			seen := make(map[string]bool)
+			for {
+				rs, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				tu.FatalOnErr("Recv", err, t)
+				for _, r := range rs {
+					// Each target's stream ends with an EOF.
+					if r.Error != io.EOF {
+						tu.FatalOnErr(r.Target, r.Error, t)
+					}
+					seen[r.Target] = true
+				}
+			}
+			got = nil
+			for k := range seen {
+				got = append(got, k)
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("stream: unexpected targets (-want +got):\n%s", diff)
+			}
+
+			if tc.implements != nil {
+				wantMethods := []string{"/Testdata.TestService/TestUnary", "/Testdata.TestService/TestServerStream"}
+				if diff := cmp.Diff(wantMethods, methods); diff != "" {
+					t.Errorf("unexpected filtered methods (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// SetMaxInflight limits how many targets calls through the proxy are made
+// to at once, to throttle large fanouts. Unary and server streaming calls
+// are then made to each target on its own stream to the proxy, starting
+// the next as each finishes. Client and bidirectional streaming calls still
+// go to every target at once as each request is sent to all of them. Zero
+// (the default) calls every target at once.
+func (p *Conn) SetMaxInflight(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxInflight = n
+}
+
+// inflightLimit returns how many of `targets` targets to call at once, or
+// zero if they can all be called together.
+func (p *Conn) inflightLimit(targets int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.maxInflight <= 0 || p.maxInflight >= targets {
+		return 0
+	}
+	return p.maxInflight
+}
+
+// fanOut calls `call` for each of `targets` (indices into p.Targets), at
+// most `limit` at once, and returns a channel of the responses from all of
+// them. It's closed once every call has finished. Each call is passed a
+// context derived from ctx which is cancelled once its responses have been
+// read, ending its stream to the proxy.
+func (p *Conn) fanOut(ctx context.Context, targets []int, limit int, call func(context.Context, int) (<-chan *Ret, error)) <-chan *Ret {
+	out := make(chan *Ret)
+	sem := make(chan struct{}, limit)
+	go func() {
+		var wg sync.WaitGroup
+		for _, i := range targets {
+			// Calls end once the context is done so this never blocks
+			// for long after it.
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				retChan, err := call(ctx, i)
+				if err != nil {
+					out <- &Ret{Target: p.Targets[i], Index: i, Error: err}
+					return
+				}
+				for r := range retChan {
+					out <- r
+				}
+			}(i)
+		}
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// limitedStream is a grpc.ClientStream for server streaming calls through
+// the proxy which calls each target on its own stream, at most limit at
+// once. It behaves as a proxyStream.
+type limitedStream struct {
+	ctx     context.Context
+	p       *Conn
+	method  string
+	targets []int
+	limit   int
+	tracker *progressTracker
+	result  ResultFunc
+
+	req        proto.Message
+	sendClosed bool
+	rets       <-chan *Ret
+}
+
+// Header - see grpc.ClientStream
+func (l *limitedStream) Header() (metadata.MD, error) {
+	return nil, status.Error(codes.Unimplemented, "Not implemented for proxy")
+}
+
+// Trailer - see grpc.ClientStream
+func (l *limitedStream) Trailer() metadata.MD {
+	return nil
+}
+
+// CloseSend - see grpc.ClientStream
+func (l *limitedStream) CloseSend() error {
+	l.sendClosed = true
+	return nil
+}
+
+// Context - see grpc.ClientStream
+func (l *limitedStream) Context() context.Context {
+	return l.ctx
+}
+
+// see grpc.ClientStream
+func (l *limitedStream) SendMsg(args interface{}) error {
+	if l.sendClosed {
+		return status.Error(codes.FailedPrecondition, "sending on a closed connection")
+	}
+	if l.req != nil {
+		return status.Errorf(codes.FailedPrecondition, "only one request can be sent for %s", l.method)
+	}
+	m, ok := args.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "args for SendMsg must be a proto.Message %T", args)
+	}
+	l.req = m
+	return nil
+}
+
+// see grpc.ClientStream
+func (l *limitedStream) RecvMsg(m interface{}) error {
+	manyRet, ok := m.(*[]*Ret)
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "args for proxy RecvMsg must be a *[]*ProxyRet) - got %T", m)
+	}
+	if l.rets == nil {
+		if l.req == nil {
+			return status.Errorf(codes.FailedPrecondition, "no request sent for %s", l.method)
+		}
+		l.rets = l.p.fanOut(l.ctx, l.targets, l.limit, l.call)
+	}
+	r, ok := <-l.rets
+	if !ok {
+		return io.EOF
+	}
+	*manyRet = append(*manyRet, r)
+	return nil
+}
+
+// call makes the call to the target at index i, returning its responses.
+func (l *limitedStream) call(ctx context.Context, i int) (<-chan *Ret, error) {
+	out := make(chan *Ret)
+	go func() {
+		defer close(out)
+		fail := func(err error) {
+			r := &Ret{Target: l.p.Targets[i], Index: i, Error: err}
+			l.tracker.done(i)
+			reportRet(l.result, l.method, r, false)
+			out <- r
+		}
+		stream, ids, err := l.p.createStreams(ctx, l.method, []int{i})
+		if err != nil {
+			fail(err)
+			return
+		}
+		s := &proxyStream{
+			method:  l.method,
+			stream:  stream,
+			ids:     ids,
+			tracker: l.tracker,
+			result:  l.result,
+		}
+		if err := s.send(l.req); err != nil {
+			fail(err)
+			return
+		}
+		if err := s.closeClients(); err != nil {
+			fail(err)
+			return
+		}
+		if err := s.CloseSend(); err != nil {
+			fail(err)
+			return
+		}
+		for {
+			var rets []*Ret
+			err := s.RecvMsg(&rets)
+			for _, r := range rets {
+				// s reuses its Rets for each message.
+				cp := *r
+				out <- &cp
+			}
+			if err == io.EOF && len(s.ids) == 0 {
+				return
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = status.Errorf(codes.Internal, "stream for %s ended without a status", l.method)
+				}
+				fail(err)
+				return
+			}
+		}
+	}()
+	return out, nil
+}
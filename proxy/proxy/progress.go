@@ -0,0 +1,192 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package proxy
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// Progress describes the data received so far from a single target
+// on a stream. A copy is handed to the ProgressFunc registered on a Conn
+// every time a message arrives (and once more when the target finishes).
+type Progress struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	// Method is the full method name of the stream.
+	Method string
+	// Bytes is the total size of all messages received from this target.
+	Bytes int64
+	// Messages is the number of messages received from this target.
+	Messages int64
+	// Start is when the stream was created.
+	Start time.Time
+	// Last is when the most recent message (or final status) was received.
+	Last time.Time
+	// Done is true once the target has sent its final status.
+	Done bool
+}
+
+// Elapsed returns the time between the stream starting and the last update.
+func (p *Progress) Elapsed() time.Duration {
+	return p.Last.Sub(p.Start)
+}
+
+// MessagesPerSecond returns the average message rate for this target.
+func (p *Progress) MessagesPerSecond() float64 {
+	e := p.Elapsed().Seconds()
+	if e <= 0 {
+		return 0
+	}
+	return float64(p.Messages) / e
+}
+
+// BytesPerSecond returns the average byte rate for this target.
+func (p *Progress) BytesPerSecond() float64 {
+	e := p.Elapsed().Seconds()
+	if e <= 0 {
+		return 0
+	}
+	return float64(p.Bytes) / e
+}
+
+// ETA estimates the time remaining until `total` bytes have been received
+// based on the average rate so far. The caller must supply the expected total
+// (i.e. from a previous Stat call) as the stream itself has no notion of it.
+// Returns 0 if done (or past total) and -1 if no estimate can be made yet.
+func (p *Progress) ETA(total int64) time.Duration {
+	if p.Done || p.Bytes >= total {
+		return 0
+	}
+	rate := p.BytesPerSecond()
+	if rate <= 0 {
+		return -1
+	}
+	return time.Duration(float64(total-p.Bytes) / rate * float64(time.Second))
+}
+
+// Stalled returns true if nothing has been received from this target
+// for at least `d` as of `now` and it hasn't finished.
+func (p *Progress) Stalled(now time.Time, d time.Duration) bool {
+	return !p.Done && now.Sub(p.Last) >= d
+}
+
+// ProgressFunc is invoked with per-target progress for streaming calls.
+// It's called synchronously from the receive path so implementations
+// should return quickly.
+type ProgressFunc func(Progress)
+
+// SetProgressFunc registers a ProgressFunc which will be called for
+// every message received on streams created from this Conn after this
+// call. Passing nil disables progress reporting.
+func (p *Conn) SetProgressFunc(f ProgressFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress = f
+}
+
+func (p *Conn) progressFunc() ProgressFunc {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.progress
+}
+
+// progressTracker accumulates Progress for each target on a single stream.
+type progressTracker struct {
+	mu      sync.Mutex
+	fn      ProgressFunc
+	targets map[int]*Progress
+}
+
+func newProgressTracker(fn ProgressFunc, method string, targets []string) *progressTracker {
+	if fn == nil {
+		return nil
+	}
+	now := time.Now()
+	t := &progressTracker{
+		fn:      fn,
+		targets: make(map[int]*Progress),
+	}
+	for i, target := range targets {
+		t.targets[i] = &Progress{
+			Target: target,
+			Index:  i,
+			Method: method,
+			Start:  now,
+			Last:   now,
+		}
+	}
+	return t
+}
+
+// received records a message of `size` bytes from the target at `index`.
+// It's safe to call on a nil tracker.
+func (t *progressTracker) received(index int, size int) {
+	t.update(index, func(p *Progress) {
+		p.Bytes += int64(size)
+		p.Messages++
+	})
+}
+
+// done records the target at `index` as finished.
+// It's safe to call on a nil tracker.
+func (t *progressTracker) done(index int) {
+	t.update(index, func(p *Progress) {
+		p.Done = true
+	})
+}
+
+func (t *progressTracker) update(index int, f func(p *Progress)) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	p, ok := t.targets[index]
+	if !ok || p.Done {
+		t.mu.Unlock()
+		return
+	}
+	f(p)
+	p.Last = time.Now()
+	cp := *p
+	t.mu.Unlock()
+	t.fn(cp)
+}
+
+// progressClientStream wraps a direct grpc.ClientStream to report progress
+// on received messages.
+type progressClientStream struct {
+	grpc.ClientStream
+	tracker *progressTracker
+}
+
+// see grpc.ClientStream
+func (p *progressClientStream) RecvMsg(m interface{}) error {
+	err := p.ClientStream.RecvMsg(m)
+	if err != nil {
+		p.tracker.done(0)
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		p.tracker.received(0, proto.Size(msg))
+	}
+	return nil
+}
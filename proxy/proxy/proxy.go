@@ -23,6 +23,8 @@ import (
 	"context"
 	"io"
 	"log"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -45,6 +47,27 @@ type Conn struct {
 
 	// If this is true we're not proxy but instead direct connect.
 	direct bool
+
+	// Protects the fields below.
+	mu sync.Mutex
+
+	// If set, called as responses arrive on any stream. See SetProgressFunc.
+	progress ProgressFunc
+
+	// If set, called with every response and final status. See SetResultFunc.
+	result ResultFunc
+
+	// How many times, and for how long at most, to wait and retry unary
+	// calls to throttled targets. See SetThrottleRetries.
+	throttleRetries  int
+	maxThrottleDelay time.Duration
+
+	// If set, used to skip targets which don't implement a method.
+	// See SetMethodFilter.
+	methodFilter MethodFilter
+
+	// If positive, the most targets to call at once. See SetMaxInflight.
+	maxInflight int
 }
 
 // Ret defines the internal API for getting responses from the proxy.
@@ -76,13 +99,23 @@ type proxyStream struct {
 	stream     proxypb.Proxy_ProxyClient
 	ids        map[uint64]*Ret
 	sendClosed bool
+	tracker    *progressTracker
+	result     ResultFunc
 }
 
 // Invoke - see grpc.ClientConnInterface
 func (p *Conn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
 	if p.Direct() {
 		// TODO(jchacon): Add V1 style logging indicating pass through in use.
-		return p.cc.Invoke(ctx, method, args, reply, opts...)
+		err := p.invokeDirect(ctx, method, args, reply, opts...)
+		if fn := p.resultFunc(); fn != nil {
+			res := Result{Target: p.Targets[0], Method: method, Done: true, Error: err}
+			if msg, ok := reply.(proto.Message); ok && err == nil {
+				fn(Result{Target: p.Targets[0], Method: method, Resp: proto.Clone(msg)})
+			}
+			fn(res)
+		}
+		return err
 	}
 	if len(p.Targets) != 1 {
 		return status.Error(codes.InvalidArgument, "cannot invoke 1:1 RPC's with multiple targets")
@@ -135,18 +168,51 @@ func (p *Conn) Invoke(ctx context.Context, method string, args interface{}, repl
 func (p *Conn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
 	if p.direct {
 		// TODO(jchacon): Add V1 style logging indicating pass through in use.
-		return p.cc.NewStream(ctx, desc, method, opts...)
+		stream, err := p.cc.NewStream(ctx, desc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if fn := p.progressFunc(); fn != nil {
+			stream = &progressClientStream{
+				ClientStream: stream,
+				tracker:      newProgressTracker(fn, method, p.Targets),
+			}
+		}
+		if fn := p.resultFunc(); fn != nil {
+			stream = &resultClientStream{
+				ClientStream: stream,
+				fn:           fn,
+				method:       method,
+				target:       p.Targets[0],
+			}
+		}
+		return stream, nil
+	}
+
+	targets := p.targetsFor(ctx, method)
+	if limit := p.inflightLimit(len(targets)); limit > 0 && !desc.ClientStreams {
+		return &limitedStream{
+			ctx:     ctx,
+			p:       p,
+			method:  method,
+			targets: targets,
+			limit:   limit,
+			tracker: newProgressTracker(p.progressFunc(), method, p.Targets),
+			result:  p.resultFunc(),
+		}, nil
 	}
 
-	stream, streamIds, err := p.createStreams(ctx, method)
+	stream, streamIds, err := p.createStreams(ctx, method, targets)
 	if err != nil {
 		return nil, err
 	}
 
 	s := &proxyStream{
-		method: method,
-		stream: stream,
-		ids:    streamIds,
+		method:  method,
+		stream:  stream,
+		ids:     streamIds,
+		tracker: newProgressTracker(p.progressFunc(), method, p.Targets),
+		result:  p.resultFunc(),
 	}
 
 	return s, nil
@@ -283,11 +349,10 @@ func (p *proxyStream) RecvMsg(m interface{}) error {
 			p.ids[id].Resp = d.Payload
 			p.ids[id].Error = nil
 			*manyRet = append(*manyRet, p.ids[id])
+			p.tracker.received(p.ids[id].Index, len(d.Payload.GetValue()))
+			reportRet(p.result, p.method, p.ids[id], false)
 		}
 	case cl != nil:
-		code := codes.Code(cl.GetStatus().GetCode())
-		msg := cl.GetStatus().GetMessage()
-
 		// Do a one time check all the returned ids are ones we know.
 		for _, id := range cl.StreamIds {
 			if _, ok := p.ids[id]; !ok {
@@ -300,7 +365,7 @@ func (p *proxyStream) RecvMsg(m interface{}) error {
 
 		// A normal close actually returns this as an error so map it so clients know the stream closed.
 		closedErr := io.EOF
-		streamStatus := status.New(code, msg)
+		streamStatus := statusFromProto(cl.GetStatus())
 
 		if streamStatus.Code() != codes.OK {
 			closedErr = streamStatus.Err()
@@ -310,6 +375,8 @@ func (p *proxyStream) RecvMsg(m interface{}) error {
 			p.ids[id].Error = closedErr
 			p.ids[id].Resp = nil
 			*manyRet = append(*manyRet, p.ids[id])
+			p.tracker.done(p.ids[id].Index)
+			reportRet(p.result, p.method, p.ids[id], false)
 			delete(p.ids, id)
 		}
 	default:
@@ -319,9 +386,10 @@ func (p *proxyStream) RecvMsg(m interface{}) error {
 }
 
 // createStreams is a helper which does the heavy lifting of creating N tracked streams to the proxy
-// for later RPCs to flow across. It returns a proxy stream object (for clients), and a map of stream ids to prefilled ProxyRet
-// objects. These will have Index/Target already filled in so clients can map them to their requests.
-func (p *Conn) createStreams(ctx context.Context, method string) (proxypb.Proxy_ProxyClient, map[uint64]*Ret, error) {
+// for later RPCs to flow across, one for each index into p.Targets in `targets`. It returns a proxy stream object (for clients),
+// and a map of stream ids to prefilled ProxyRet objects. These will have Index/Target already filled in so clients can map them
+// to their requests.
+func (p *Conn) createStreams(ctx context.Context, method string, targets []int) (proxypb.Proxy_ProxyClient, map[uint64]*Ret, error) {
 	stream, err := proxypb.NewProxyClient(p.cc).Proxy(ctx)
 	if err != nil {
 		return nil, nil, status.Errorf(codes.Internal, "can't setup proxy stream - %v", err)
@@ -331,11 +399,11 @@ func (p *Conn) createStreams(ctx context.Context, method string) (proxypb.Proxy_
 
 	// For every target we have to send a separate StartStream (with a nonce which in our case is the target index so clients can map too).
 	// We then validate the nonce matches and record the stream ID so later processing can match responses to the right targets.
-	for i, t := range p.Targets {
+	for _, i := range targets {
 		req := &proxypb.ProxyRequest{
 			Request: &proxypb.ProxyRequest_StartStream{
 				StartStream: &proxypb.StartStream{
-					Target:     t,
+					Target:     p.Targets[i],
 					MethodName: method,
 					Nonce:      uint32(i),
 				},
@@ -385,22 +453,51 @@ func (p *Conn) createStreams(ctx context.Context, method string) (proxypb.Proxy_
 // This returns ProxyRet objects from the channel which contain anypb.Any so the caller (generally generated code)
 // will need to convert those to the proper expected specific types.
 //
+// Targets which are throttled may be retried, see SetThrottleRetries, and
+// targets not implementing the method may be skipped, see SetMethodFilter.
+//
 // NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
 func (p *Conn) InvokeOneMany(ctx context.Context, method string, args interface{}, opts ...grpc.CallOption) (<-chan *Ret, error) {
+	targets := p.targetsFor(ctx, method)
+	var retChan <-chan *Ret
+	if limit := p.inflightLimit(len(targets)); limit > 0 {
+		retChan = p.fanOut(ctx, targets, limit, func(ctx context.Context, i int) (<-chan *Ret, error) {
+			return p.invokeOneMany(ctx, method, args, []int{i}, opts...)
+		})
+	} else {
+		var err error
+		retChan, err = p.invokeOneMany(ctx, method, args, targets, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if attempts, _ := p.throttleRetryPolicy(); attempts != 0 {
+		retChan = p.retryThrottled(ctx, method, args, retChan, opts...)
+	}
+	if fn := p.resultFunc(); fn != nil {
+		retChan = reportOneMany(fn, method, retChan)
+	}
+	return retChan, nil
+}
+
+// invokeOneMany implements InvokeOneMany without retries for the given
+// indices into p.Targets.
+func (p *Conn) invokeOneMany(ctx context.Context, method string, args interface{}, targets []int, opts ...grpc.CallOption) (<-chan *Ret, error) {
 	requestMsg, ok := args.(proto.Message)
 	if !ok {
 		return nil, status.Error(codes.InvalidArgument, "args must be a proto.Message")
 	}
 
-	stream, streamIds, err := p.createStreams(ctx, method)
+	stream, streamIds, err := p.createStreams(ctx, method, targets)
 	if err != nil {
 		return nil, err
 	}
 
 	s := &proxyStream{
-		method: method,
-		stream: stream,
-		ids:    streamIds,
+		method:  method,
+		stream:  stream,
+		ids:     streamIds,
+		tracker: newProgressTracker(p.progressFunc(), method, p.Targets),
 	}
 	if err := s.send(requestMsg); err != nil {
 		return nil, err
@@ -443,11 +540,13 @@ func (p *Conn) InvokeOneMany(ctx context.Context, method string, args interface{
 						break processing
 					}
 					s.ids[id].Resp = d.Payload
+					s.tracker.received(s.ids[id].Index, len(d.Payload.GetValue()))
 					retChan <- s.ids[id]
 				}
 			case cl != nil:
 				code := codes.Code(cl.GetStatus().GetCode())
 				msg := cl.GetStatus().GetMessage()
+				closeStatus := statusFromProto(cl.GetStatus())
 
 				// Do a one time check all the returned ids are ones we know.
 				for _, id := range cl.StreamIds {
@@ -468,11 +567,12 @@ func (p *Conn) InvokeOneMany(ctx context.Context, method string, args interface{
 							break processing
 						}
 
-						s.ids[id].Error = status.New(code, msg).Err()
+						s.ids[id].Error = closeStatus.Err()
 						retChan <- s.ids[id]
 					}
 				}
 				for _, id := range cl.StreamIds {
+					s.tracker.done(s.ids[id].Index)
 					delete(s.ids, id)
 				}
 			default:
@@ -490,6 +590,7 @@ func (p *Conn) InvokeOneMany(ctx context.Context, method string, args interface{
 		// current chanErr down to them.
 		for _, msg := range s.ids {
 			msg.Error = chanErr
+			s.tracker.done(msg.Index)
 			retChan <- msg
 		}
 		close(retChan)
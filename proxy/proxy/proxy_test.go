@@ -24,7 +24,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"sync"
 	"testing"
+	"time"
 
 	proxypb "github.com/Snowflake-Labs/sansshell/proxy"
 	"github.com/Snowflake-Labs/sansshell/proxy/proxy"
@@ -315,6 +317,349 @@ func TestStreaming(t *testing.T) {
 
 }
 
+func TestProgress(t *testing.T) {
+	ctx := context.Background()
+	testServerMap := testutil.StartTestDataServers(t, "foo:123", "bar:123")
+	bufMap := startTestProxy(ctx, t, testServerMap)
+
+	// Combines the 2 maps so we can dial everything directly if needed.
+	for k, v := range testServerMap {
+		bufMap[k] = v
+	}
+
+	for _, tc := range []struct {
+		name    string
+		proxy   string
+		targets []string
+	}{
+		{
+			name:    "proxy N targets",
+			proxy:   "proxy",
+			targets: []string{"foo:123", "bar:123"},
+		},
+		{
+			name:    "no proxy 1 target",
+			targets: []string{"foo:123"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			conn, err := proxy.Dial(tc.proxy, tc.targets, testutil.WithBufDialer(bufMap), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			tu.FatalOnErr("Dial", err, t)
+			defer conn.Close()
+
+			var mu sync.Mutex
+			last := make(map[int]proxy.Progress)
+			conn.SetProgressFunc(func(p proxy.Progress) {
+				mu.Lock()
+				defer mu.Unlock()
+				if prev, ok := last[p.Index]; ok && p.Messages < prev.Messages {
+					t.Errorf("message count went backwards for %d: %+v -> %+v", p.Index, prev, p)
+				}
+				last[p.Index] = p
+			})
+
+			ts := tdpb.NewTestServiceClientProxy(conn)
+			stream, err := ts.TestServerStreamOneMany(ctx, &tdpb.TestRequest{Input: "input"})
+			tu.FatalOnErr("TestServerStreamOneMany", err, t)
+			for {
+				_, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				tu.FatalOnErr("Recv", err, t)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if got, want := len(last), len(tc.targets); got != want {
+				t.Fatalf("progress reported for %d targets, want %d", got, want)
+			}
+			for i, target := range tc.targets {
+				p := last[i]
+				if p.Target != target || !p.Done || p.Messages != 5 || p.Bytes == 0 {
+					t.Errorf("unexpected final progress for %s: %+v", target, p)
+				}
+				if got := p.ETA(p.Bytes); got != 0 {
+					t.Errorf("ETA for finished target %s = %v, want 0", target, got)
+				}
+			}
+		})
+	}
+}
+
+func TestResults(t *testing.T) {
+	ctx := context.Background()
+	testServerMap := testutil.StartTestDataServers(t, "foo:123", "bar:123")
+	bufMap := startTestProxy(ctx, t, testServerMap)
+
+	// Combines the 2 maps so we can dial everything directly if needed.
+	for k, v := range testServerMap {
+		bufMap[k] = v
+	}
+
+	type summary struct {
+		messages int
+		done     int
+		err      bool
+	}
+	for _, tc := range []struct {
+		name    string
+		proxy   string
+		targets []string
+	}{
+		{
+			name:    "proxy N targets",
+			proxy:   "proxy",
+			targets: []string{"foo:123", "bar:123"},
+		},
+		{
+			name:    "proxy 1 target",
+			proxy:   "proxy",
+			targets: []string{"foo:123"},
+		},
+		{
+			name:    "no proxy 1 target",
+			targets: []string{"foo:123"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			conn, err := proxy.Dial(tc.proxy, tc.targets, testutil.WithBufDialer(bufMap), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			tu.FatalOnErr("Dial", err, t)
+			defer conn.Close()
+
+			var mu sync.Mutex
+			results := make(map[string]map[int]*summary)
+			conn.SetResultFunc(func(r proxy.Result) {
+				mu.Lock()
+				defer mu.Unlock()
+				if r.Target != tc.targets[r.Index] {
+					t.Errorf("result for index %d has target %s, want %s", r.Index, r.Target, tc.targets[r.Index])
+				}
+				if results[r.Method] == nil {
+					results[r.Method] = make(map[int]*summary)
+				}
+				s := results[r.Method][r.Index]
+				if s == nil {
+					s = &summary{}
+					results[r.Method][r.Index] = s
+				}
+				if s.done > 0 {
+					t.Errorf("result for %s on %d after it was done: %+v", r.Method, r.Index, r)
+				}
+				switch {
+				case r.Done:
+					s.done++
+					s.err = r.Error != nil
+				case r.Resp != nil:
+					if _, ok := r.Resp.(*tdpb.TestResponse); !ok {
+						t.Errorf("response is a %T, want *TestResponse", r.Resp)
+					}
+					s.messages++
+				default:
+					t.Errorf("result with no response that isn't done: %+v", r)
+				}
+			})
+
+			// check verifies the results of a call of method, then resets them.
+			check := func(method string, want summary) {
+				t.Helper()
+				mu.Lock()
+				defer mu.Unlock()
+				for i := range tc.targets {
+					got := results[method][i]
+					if got == nil || *got != want {
+						t.Errorf("%s on %d: got %+v, want %+v", method, i, got, want)
+					}
+				}
+				results = make(map[string]map[int]*summary)
+			}
+
+			ts := tdpb.NewTestServiceClientProxy(conn)
+			for _, tc := range []struct {
+				input string
+				want  summary
+			}{
+				{input: "input", want: summary{messages: 1, done: 1}},
+				{input: "error", want: summary{done: 1, err: true}},
+			} {
+				resp, err := ts.TestUnaryOneMany(ctx, &tdpb.TestRequest{Input: tc.input})
+				tu.FatalOnErr("TestUnaryOneMany", err, t)
+				for range resp {
+				}
+				check("/Testdata.TestService/TestUnary", tc.want)
+			}
+
+			stream, err := ts.TestServerStreamOneMany(ctx, &tdpb.TestRequest{Input: "input"})
+			tu.FatalOnErr("TestServerStreamOneMany", err, t)
+			for {
+				_, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				tu.FatalOnErr("Recv", err, t)
+			}
+			check("/Testdata.TestService/TestServerStream", summary{messages: 5, done: 1})
+		})
+	}
+}
+
+// inflight records the most calls being handled at once across servers.
+type inflight struct {
+	mu       sync.Mutex
+	cur, max int
+}
+
+func (f *inflight) call(fn func() error) error {
+	f.mu.Lock()
+	f.cur++
+	if f.cur > f.max {
+		f.max = f.cur
+	}
+	f.mu.Unlock()
+	// Give other calls the chance to overlap.
+	time.Sleep(10 * time.Millisecond)
+	err := fn()
+	f.mu.Lock()
+	f.cur--
+	f.mu.Unlock()
+	return err
+}
+
+func (f *inflight) reset() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	max := f.max
+	f.max = 0
+	return max
+}
+
+func TestMaxInflight(t *testing.T) {
+	ctx := context.Background()
+	counter := &inflight{}
+	targets := []string{"a:1", "b:1", "c:1", "d:1", "e:1", "f:1"}
+	testServerMap := make(map[string]*bufconn.Listener)
+	for _, target := range targets {
+		lis := bufconn.Listen(testutil.BufSize)
+		s := grpc.NewServer(
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+				err = counter.call(func() error {
+					resp, err = handler(ctx, req)
+					return err
+				})
+				return resp, err
+			}),
+			grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				return counter.call(func() error { return handler(srv, ss) })
+			}),
+		)
+		tdpb.RegisterTestServiceServer(s, &testutil.EchoTestDataServer{})
+		go s.Serve(lis)
+		t.Cleanup(s.Stop)
+		testServerMap[target] = lis
+	}
+	bufMap := startTestProxy(ctx, t, testServerMap)
+
+	conn, err := proxy.Dial("proxy", targets, testutil.WithBufDialer(bufMap), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	tu.FatalOnErr("Dial", err, t)
+	defer conn.Close()
+	const limit = 2
+	conn.SetMaxInflight(limit)
+	var mu sync.Mutex
+	done := make(map[int]bool)
+	conn.SetResultFunc(func(r proxy.Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Done {
+			done[r.Index] = true
+		}
+	})
+	checkDone := func(name string) {
+		t.Helper()
+		mu.Lock()
+		defer mu.Unlock()
+		if len(done) != len(targets) {
+			t.Errorf("%s: results reported done for %v, want all %d targets", name, done, len(targets))
+		}
+		done = make(map[int]bool)
+	}
+
+	ts := tdpb.NewTestServiceClientProxy(conn)
+	resp, err := ts.TestUnaryOneMany(ctx, &tdpb.TestRequest{Input: "input"})
+	tu.FatalOnErr("TestUnaryOneMany", err, t)
+	got := make(map[int]int)
+	for r := range resp {
+		tu.FatalOnErr(fmt.Sprintf("TestUnary on %s", r.Target), r.Error, t)
+		got[r.Index]++
+	}
+	for i := range targets {
+		if got[i] != 1 {
+			t.Errorf("TestUnary: got %d responses from %d, want 1", got[i], i)
+		}
+	}
+	if max := counter.reset(); max > limit {
+		t.Errorf("TestUnary: %d calls at once, want at most %d", max, limit)
+	}
+	checkDone("TestUnary")
+
+	stream, err := ts.TestServerStreamOneMany(ctx, &tdpb.TestRequest{Input: "input"})
+	tu.FatalOnErr("TestServerStreamOneMany", err, t)
+	got = make(map[int]int)
+	eof := make(map[int]bool)
+	for {
+		rs, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		tu.FatalOnErr("Recv", err, t)
+		for _, r := range rs {
+			if r.Error == io.EOF {
+				eof[r.Index] = true
+				continue
+			}
+			tu.FatalOnErr(fmt.Sprintf("TestServerStream on %s", r.Target), r.Error, t)
+			if want := fmt.Sprintf(" %d input", got[r.Index]); r.Resp.Output != want {
+				t.Errorf("TestServerStream on %s: got %q, want %q", r.Target, r.Resp.Output, want)
+			}
+			got[r.Index]++
+		}
+	}
+	for i := range targets {
+		if got[i] != 5 || !eof[i] {
+			t.Errorf("TestServerStream: got %d responses from %d (closed %t), want 5", got[i], i, eof[i])
+		}
+	}
+	if max := counter.reset(); max > limit {
+		t.Errorf("TestServerStream: %d calls at once, want at most %d", max, limit)
+	}
+	checkDone("TestServerStream")
+
+	// Bidirectional streams go to every target at once.
+	bidi, err := ts.TestBidiStreamOneMany(ctx)
+	tu.FatalOnErr("TestBidiStreamOneMany", err, t)
+	tu.FatalOnErr("Send", bidi.Send(&tdpb.TestRequest{Input: "input"}), t)
+	tu.FatalOnErr("CloseSend", bidi.CloseSend(), t)
+	got = make(map[int]int)
+	for {
+		rs, err := bidi.Recv()
+		if err == io.EOF {
+			break
+		}
+		tu.FatalOnErr("Recv", err, t)
+		for _, r := range rs {
+			if r.Error == nil {
+				got[r.Index]++
+			}
+		}
+	}
+	if len(got) != len(targets) {
+		t.Errorf("TestBidiStream: got responses from %v, want all %d targets", got, len(targets))
+	}
+	counter.reset()
+	checkDone("TestBidiStream")
+}
+
 type fakeProxy struct {
 	action func(proxypb.Proxy_ProxyServer) error
 }
@@ -0,0 +1,145 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package proxy
+
+import (
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// A Result is a response, or the final status, received from a single
+// target. See SetResultFunc.
+type Result struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	// Method is the full method name of the call.
+	Method string
+	// Resp is the response received. It's nil for the final status.
+	Resp proto.Message
+	// Done is true for the final status of the target, after all of its
+	// responses.
+	Done bool
+	// Error is the error the target finished with, if any.
+	Error error
+}
+
+// ResultFunc is invoked with every Result received on calls made with a
+// Conn. It's called synchronously from the receive path (possibly from
+// several goroutines at once) so implementations should return quickly.
+type ResultFunc func(Result)
+
+// SetResultFunc registers a ResultFunc which will be called for every
+// response, and the final status of every target, on calls made with this
+// Conn after this call. This allows handling the results of any call made
+// by generated code (i.e. to log or format them) without it being aware.
+// Passing nil disables it.
+func (p *Conn) SetResultFunc(f ResultFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.result = f
+}
+
+func (p *Conn) resultFunc() ResultFunc {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.result
+}
+
+// reportRet passes the contents of r from a call of method to fn. Any
+// response is reported as received and, if unary or an error, the target
+// as done.
+func reportRet(fn ResultFunc, method string, r *Ret, unary bool) {
+	if fn == nil {
+		return
+	}
+	res := Result{
+		Target: r.Target,
+		Index:  r.Index,
+		Method: method,
+	}
+	if r.Error != nil {
+		res.Done = true
+		if r.Error != io.EOF {
+			res.Error = r.Error
+		}
+		fn(res)
+		return
+	}
+	if r.Resp != nil {
+		resp, err := anypb.UnmarshalNew(r.Resp, proto.UnmarshalOptions{})
+		if err != nil {
+			res.Done = true
+			res.Error = err
+			fn(res)
+			return
+		}
+		res.Resp = resp
+		fn(res)
+	}
+	if unary {
+		fn(Result{Target: r.Target, Index: r.Index, Method: method, Done: true})
+	}
+}
+
+// reportOneMany returns a channel passing on the responses from `retChan`
+// after reporting them to fn.
+func reportOneMany(fn ResultFunc, method string, retChan <-chan *Ret) <-chan *Ret {
+	out := make(chan *Ret)
+	go func() {
+		defer close(out)
+		for r := range retChan {
+			reportRet(fn, method, r, true)
+			out <- r
+		}
+	}()
+	return out
+}
+
+// resultClientStream wraps a direct grpc.ClientStream to report its
+// results.
+type resultClientStream struct {
+	grpc.ClientStream
+	fn     ResultFunc
+	method string
+	target string
+}
+
+// see grpc.ClientStream
+func (r *resultClientStream) RecvMsg(m interface{}) error {
+	err := r.ClientStream.RecvMsg(m)
+	res := Result{
+		Target: r.target,
+		Method: r.method,
+	}
+	if err != nil {
+		res.Done = true
+		if err != io.EOF {
+			res.Error = err
+		}
+		r.fn(res)
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		res.Resp = proto.Clone(msg)
+		r.fn(res)
+	}
+	return nil
+}
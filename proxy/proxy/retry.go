@@ -0,0 +1,164 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	proxypb "github.com/Snowflake-Labs/sansshell/proxy"
+)
+
+// RetryDelay returns how long the server asked the caller to wait before
+// retrying, if `err` is a ResourceExhausted or Unavailable status carrying
+// a RetryInfo detail (as sent when a caller is rate limited).
+func RetryDelay(err error) (time.Duration, bool) {
+	s, ok := status.FromError(err)
+	if !ok || (s.Code() != codes.ResourceExhausted && s.Code() != codes.Unavailable) {
+		return 0, false
+	}
+	for _, d := range s.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok && ri.GetRetryDelay() != nil {
+			return ri.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+// SetThrottleRetries makes unary calls retry targets which reject them with
+// a RetryDelay, up to `attempts` times, after waiting as long as the target
+// asked as long as that's no more than `maxDelay`. Streaming calls aren't
+// retried. Zero attempts (the default) disables retries.
+func (p *Conn) SetThrottleRetries(attempts int, maxDelay time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.throttleRetries = attempts
+	p.maxThrottleDelay = maxDelay
+}
+
+func (p *Conn) throttleRetryPolicy() (int, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.throttleRetries, p.maxThrottleDelay
+}
+
+// retryable returns how long to wait before retrying a call which failed
+// with `err`, or false if it shouldn't be retried.
+func (p *Conn) retryable(err error, attempt int) (time.Duration, bool) {
+	attempts, maxDelay := p.throttleRetryPolicy()
+	if attempt >= attempts {
+		return 0, false
+	}
+	d, ok := RetryDelay(err)
+	if !ok || d > maxDelay {
+		return 0, false
+	}
+	return d, true
+}
+
+// sleep waits for `d` or until ctx is done, returning false in the latter case.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// invokeDirect is Invoke for a direct connection, retrying throttled calls.
+func (p *Conn) invokeDirect(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	for attempt := 0; ; attempt++ {
+		err := p.cc.Invoke(ctx, method, args, reply, opts...)
+		d, ok := p.retryable(err, attempt)
+		if !ok || !sleep(ctx, d) {
+			return err
+		}
+	}
+}
+
+// retryThrottled returns a channel passing on the responses from `retChan`
+// except for throttled targets, which are retried as a new call once the
+// longest delay asked for has passed. If they can't be retried their
+// original responses are passed on.
+func (p *Conn) retryThrottled(ctx context.Context, method string, args interface{}, retChan <-chan *Ret, opts ...grpc.CallOption) <-chan *Ret {
+	out := make(chan *Ret)
+	go func() {
+		defer close(out)
+		for attempt := 0; ; attempt++ {
+			var held []*Ret
+			var wait time.Duration
+			for r := range retChan {
+				if d, ok := p.retryable(r.Error, attempt); ok {
+					held = append(held, r)
+					if d > wait {
+						wait = d
+					}
+					continue
+				}
+				out <- r
+			}
+			if len(held) == 0 {
+				return
+			}
+			var targets []int
+			for _, r := range held {
+				targets = append(targets, r.Index)
+			}
+			var err error
+			if sleep(ctx, wait) {
+				retChan, err = p.invokeOneMany(ctx, method, args, targets, opts...)
+			} else {
+				err = ctx.Err()
+			}
+			if err != nil {
+				for _, r := range held {
+					out <- r
+				}
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// allTargets returns the indices of all of p.Targets.
+func (p *Conn) allTargets() []int {
+	out := make([]int, len(p.Targets))
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
+
+// statusFromProto returns the status sent by the proxy for a target,
+// including any details.
+func statusFromProto(s *proxypb.Status) *status.Status {
+	return status.FromProto(&spb.Status{
+		Code:    s.GetCode(),
+		Message: s.GetMessage(),
+		Details: s.GetDetails(),
+	})
+}
@@ -0,0 +1,210 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package proxy_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	tdpb "github.com/Snowflake-Labs/sansshell/proxy/testdata"
+	"github.com/Snowflake-Labs/sansshell/proxy/testutil"
+	tu "github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func throttledError(delay time.Duration) error {
+	s, err := status.New(codes.ResourceExhausted, "slow down").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	if err != nil {
+		panic(err)
+	}
+	return s.Err()
+}
+
+// startThrottledServer starts a test data server which rejects the first
+// `throttled` unary calls asking the caller to retry after `delay`.
+func startThrottledServer(t *testing.T, throttled int, delay time.Duration) *bufconn.Listener {
+	t.Helper()
+	var mu sync.Mutex
+	calls := 0
+	lis := bufconn.Listen(testutil.BufSize)
+	s := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		if n <= throttled {
+			return nil, throttledError(delay)
+		}
+		return handler(ctx, req)
+	}))
+	tdpb.RegisterTestServiceServer(s, &testutil.EchoTestDataServer{})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis
+}
+
+func TestRetryDelay(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		err    error
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "retry info",
+			err:    throttledError(3 * time.Second),
+			want:   3 * time.Second,
+			wantOK: true,
+		},
+		{
+			name: "no details",
+			err:  status.Error(codes.ResourceExhausted, "slow down"),
+		},
+		{
+			name: "other code",
+			err:  status.Error(codes.PermissionDenied, "no"),
+		},
+		{
+			name: "not a status",
+			err:  errors.New("oops"),
+		},
+		{
+			name: "nil",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := proxy.RetryDelay(tc.err)
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("RetryDelay(%v) = %v, %t want %v, %t", tc.err, got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestThrottleRetries(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name      string
+		proxy     string
+		targets   []string
+		throttled int
+		delay     time.Duration
+		attempts  int
+		maxDelay  time.Duration
+		wantErr   bool
+	}{
+		{
+			name:      "proxy retried",
+			proxy:     "proxy",
+			targets:   []string{"throttled:123", "bar:123"},
+			throttled: 2,
+			delay:     10 * time.Millisecond,
+			attempts:  2,
+			maxDelay:  time.Second,
+		},
+		{
+			name:      "proxy without retries",
+			proxy:     "proxy",
+			targets:   []string{"throttled:123", "bar:123"},
+			throttled: 1,
+			delay:     10 * time.Millisecond,
+			wantErr:   true,
+		},
+		{
+			name:      "proxy too many attempts",
+			proxy:     "proxy",
+			targets:   []string{"throttled:123", "bar:123"},
+			throttled: 3,
+			delay:     10 * time.Millisecond,
+			attempts:  2,
+			maxDelay:  time.Second,
+			wantErr:   true,
+		},
+		{
+			name:      "proxy delay too long",
+			proxy:     "proxy",
+			targets:   []string{"throttled:123", "bar:123"},
+			throttled: 1,
+			delay:     time.Minute,
+			attempts:  2,
+			maxDelay:  time.Second,
+			wantErr:   true,
+		},
+		{
+			name:      "direct retried",
+			targets:   []string{"throttled:123"},
+			throttled: 2,
+			delay:     10 * time.Millisecond,
+			attempts:  2,
+			maxDelay:  time.Second,
+		},
+		{
+			name:      "direct without retries",
+			targets:   []string{"throttled:123"},
+			throttled: 1,
+			delay:     10 * time.Millisecond,
+			wantErr:   true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			bufMap := testutil.StartTestDataServers(t, "bar:123")
+			bufMap["throttled:123"] = startThrottledServer(t, tc.throttled, tc.delay)
+			for k, v := range startTestProxy(ctx, t, bufMap) {
+				bufMap[k] = v
+			}
+			conn, err := proxy.Dial(tc.proxy, tc.targets, testutil.WithBufDialer(bufMap), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			tu.FatalOnErr("Dial", err, t)
+			defer conn.Close()
+			conn.SetThrottleRetries(tc.attempts, tc.maxDelay)
+
+			resp, err := tdpb.NewTestServiceClientProxy(conn).TestUnaryOneMany(ctx, &tdpb.TestRequest{Input: "input"})
+			tu.FatalOnErr("TestUnaryOneMany", err, t)
+			got := 0
+			for r := range resp {
+				got++
+				if r.Target != "throttled:123" {
+					tu.FatalOnErr(r.Target, r.Error, t)
+					continue
+				}
+				if (r.Error != nil) != tc.wantErr {
+					t.Errorf("%s: got error %v, wantErr %t", r.Target, r.Error, tc.wantErr)
+				}
+				if r.Error != nil {
+					if _, ok := proxy.RetryDelay(r.Error); !ok {
+						t.Errorf("%s: error %v has no retry delay", r.Target, r.Error)
+					}
+				}
+			}
+			if got != len(tc.targets) {
+				t.Errorf("got %d responses, want %d", got, len(tc.targets))
+			}
+		})
+	}
+}
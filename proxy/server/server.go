@@ -26,7 +26,9 @@ import (
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 
+	"github.com/Snowflake-Labs/sansshell/auth/delegation"
 	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/auth/proxyhint"
 	pb "github.com/Snowflake-Labs/sansshell/proxy"
 )
 
@@ -65,6 +67,42 @@ type Server struct {
 
 	// A policy authorizer, for authorizing proxy -> target requests
 	authorizer *rpcauth.Authorizer
+
+	// If non-nil, signs decision hints sent to targets.
+	hints *proxyhint.Signer
+
+	// If non-nil, mints delegation tokens sent to targets.
+	delegation *delegation.Minter
+}
+
+// An Option controls the behavior of a Server
+type Option interface {
+	apply(*Server)
+}
+
+type optionFunc func(*Server)
+
+func (o optionFunc) apply(s *Server) {
+	o(s)
+}
+
+// WithDecisionHints returns an option to send a hint signed by `signer` with
+// every stream opened to a target, so target policies can check that this
+// proxy authorized the request. See the proxyhint package.
+func WithDecisionHints(signer *proxyhint.Signer) Option {
+	return optionFunc(func(s *Server) {
+		s.hints = signer
+	})
+}
+
+// WithDelegation returns an option to send a token minted by `minter` with
+// every stream opened to a target, binding the caller to the target and
+// method so target policies can check who this proxy is acting for. See the
+// delegation package.
+func WithDelegation(minter *delegation.Minter) Option {
+	return optionFunc(func(s *Server) {
+		s.delegation = minter
+	})
 }
 
 // Register registers this server with the given ServiceRegistrar
@@ -78,20 +116,34 @@ func (s *Server) Register(sr grpc.ServiceRegistrar) {
 // registry to resolve service methods
 // The supplied authorizer is used to authorize requests made
 // to targets.
-func New(dialer TargetDialer, authorizer *rpcauth.Authorizer) *Server {
-	return NewWithServiceMap(dialer, authorizer, LoadGlobalServiceMap())
+func New(dialer TargetDialer, authorizer *rpcauth.Authorizer, opts ...Option) *Server {
+	return NewWithServiceMap(dialer, authorizer, LoadGlobalServiceMap(), opts...)
 }
 
 // NewWithServiceMap create a new Server using the supplied TargetDialer
 // and service map.
 // The supplied authorizer is used to authorize requests made
 // to targets.
-func NewWithServiceMap(dialer TargetDialer, authorizer *rpcauth.Authorizer, serviceMap map[string]*ServiceMethod) *Server {
-	return &Server{
+func NewWithServiceMap(dialer TargetDialer, authorizer *rpcauth.Authorizer, serviceMap map[string]*ServiceMethod, opts ...Option) *Server {
+	s := &Server{
 		serviceMap: serviceMap,
 		dialer:     dialer,
 		authorizer: authorizer,
 	}
+	for _, o := range opts {
+		o.apply(s)
+	}
+	return s
+}
+
+// NewTargetStreamSet returns a TargetStreamSet which opens and authorizes
+// target streams as Proxy does. It's for services run on the proxy which
+// call targets on behalf of their caller.
+func (s *Server) NewTargetStreamSet() *TargetStreamSet {
+	streamSet := NewTargetStreamSet(s.serviceMap, s.dialer, s.authorizer)
+	streamSet.hints = s.hints
+	streamSet.delegation = s.delegation
+	return streamSet
 }
 
 // Proxy implements ProxyServer.Proxy to provide a single bidirectional
@@ -105,7 +157,7 @@ func (s *Server) Proxy(stream pb.Proxy_ProxyServer) error {
 
 	// create a new TargetStreamSet to manage the target streams
 	// associated with this proxy connection
-	streamSet := NewTargetStreamSet(s.serviceMap, s.dialer, s.authorizer)
+	streamSet := s.NewTargetStreamSet()
 
 	// A single go-routine for handling all sends to the reply
 	// channel
@@ -18,33 +18,46 @@ package server
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
+	"net"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/anypb"
 
+	"github.com/Snowflake-Labs/sansshell/auth/delegation"
 	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/auth/proxyhint"
 	pb "github.com/Snowflake-Labs/sansshell/proxy"
 	tdpb "github.com/Snowflake-Labs/sansshell/proxy/testdata"
 	"github.com/Snowflake-Labs/sansshell/proxy/testutil"
 	tu "github.com/Snowflake-Labs/sansshell/testing/testutil"
 )
 
-func startTestProxyWithAuthz(ctx context.Context, t *testing.T, targets map[string]*bufconn.Listener, authz *rpcauth.Authorizer) pb.Proxy_ProxyClient {
+func startTestProxyWithAuthz(ctx context.Context, t *testing.T, targets map[string]*bufconn.Listener, authz *rpcauth.Authorizer, opts ...Option) pb.Proxy_ProxyClient {
 	t.Helper()
 	targetDialer := NewDialer(testutil.WithBufDialer(targets), grpc.WithTransportCredentials(insecure.NewCredentials()))
 	lis := bufconn.Listen(testutil.BufSize)
 	grpcServer := grpc.NewServer(grpc.StreamInterceptor(authz.AuthorizeStream))
-	proxyServer := New(targetDialer, authz)
+	proxyServer := New(targetDialer, authz, opts...)
 	proxyServer.Register(grpcServer)
 	go func() {
 		// Don't care about errors here as they might come on shutdown and we
@@ -643,3 +656,246 @@ allow {
 		}
 	}
 }
+
+func TestProxyServerDecisionHints(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tu.FatalOnErr("GenerateKey", err, t)
+	signer, err := proxyhint.NewSigner("proxy", key, 0)
+	tu.FatalOnErr("NewSigner", err, t)
+	verifier, err := proxyhint.NewVerifier(map[string]crypto.PublicKey{"proxy": key.Public()})
+	tu.FatalOnErr("NewVerifier", err, t)
+
+	// The target only allows requests the proxy has vetted.
+	targetPolicy := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.proxy_decision.issuer = "proxy"
+  input.proxy_decision.target = "foo:123"
+}
+`
+	lis := bufconn.Listen(testutil.BufSize)
+	targetAuthz, err := rpcauth.NewWithPolicy(ctx, targetPolicy, proxyhint.Hook(verifier, true))
+	tu.FatalOnErr("NewWithPolicy", err, t)
+	target := grpc.NewServer(grpc.UnaryInterceptor(targetAuthz.Authorize))
+	tdpb.RegisterTestServiceServer(target, &testutil.EchoTestDataServer{})
+	go target.Serve(lis)
+	t.Cleanup(target.Stop)
+	targets := map[string]*bufconn.Listener{"foo:123": lis}
+
+	for _, tc := range []struct {
+		name     string
+		opts     []Option
+		wantCode codes.Code
+	}{
+		{
+			name: "with hints",
+			opts: []Option{WithDecisionHints(signer)},
+		},
+		{
+			name:     "without hints",
+			wantCode: codes.PermissionDenied,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			proxyStream := startTestProxyWithAuthz(ctx, t, targets, testutil.NewAllowAllRPCAuthorizer(ctx, t), tc.opts...)
+			streamID := testutil.MustStartStream(t, proxyStream, "foo:123", "/Testdata.TestService/TestUnary")
+			req := testutil.PackStreamData(t, &tdpb.TestRequest{Input: "Foo"}, streamID)
+			reply := testutil.Exchange(t, proxyStream, req)
+			if tc.wantCode == codes.OK {
+				testutil.UnpackStreamData(t, reply)
+				reply = testutil.Exchange(t, proxyStream, nil)
+			}
+			sc := reply.GetServerClose()
+			if sc == nil {
+				t.Fatalf("expected reply of type ServerClose, got %v", reply)
+			}
+			if got := codes.Code(sc.GetStatus().GetCode()); got != tc.wantCode {
+				t.Errorf("ServerClose.Status code = %v, want %v (%v)", got, tc.wantCode, sc.GetStatus())
+			}
+		})
+	}
+}
+
+func TestProxyServerForwardsJustification(t *testing.T) {
+	ctx := context.Background()
+	targetPolicy := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.justification = "ticket-1"
+}
+`
+	lis := bufconn.Listen(testutil.BufSize)
+	targetAuthz, err := rpcauth.NewWithPolicy(ctx, targetPolicy)
+	tu.FatalOnErr("NewWithPolicy", err, t)
+	target := grpc.NewServer(grpc.UnaryInterceptor(targetAuthz.Authorize))
+	tdpb.RegisterTestServiceServer(target, &testutil.EchoTestDataServer{})
+	go target.Serve(lis)
+	t.Cleanup(target.Stop)
+	targets := map[string]*bufconn.Listener{"foo:123": lis}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, rpcauth.ReqJustKey, "ticket-1")
+	proxyStream := startTestProxyWithAuthz(ctx, t, targets, testutil.NewAllowAllRPCAuthorizer(ctx, t))
+	streamID := testutil.MustStartStream(t, proxyStream, "foo:123", "/Testdata.TestService/TestUnary")
+	req := testutil.PackStreamData(t, &tdpb.TestRequest{Input: "Foo"}, streamID)
+	reply := testutil.Exchange(t, proxyStream, req)
+	testutil.UnpackStreamData(t, reply)
+	reply = testutil.Exchange(t, proxyStream, nil)
+	if code := codes.Code(reply.GetServerClose().GetStatus().GetCode()); code != codes.OK {
+		t.Fatalf("ServerClose = %v, want OK", reply)
+	}
+}
+
+// peerStream overrides the context of a stream to carry a TLS peer.
+type peerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (p *peerStream) Context() context.Context {
+	return p.ctx
+}
+
+func TestProxyServerDelegation(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tu.FatalOnErr("GenerateKey", err, t)
+	minter, err := delegation.NewMinter("proxy", key, 0)
+	tu.FatalOnErr("NewMinter", err, t)
+	verifier, err := delegation.NewVerifier(map[string]crypto.PublicKey{"proxy": key.Public()})
+	tu.FatalOnErr("NewVerifier", err, t)
+
+	// The target only allows alice via the proxy.
+	targetPolicy := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.delegation.issuer = "proxy"
+  input.delegation.subject = "alice"
+  input.delegation.target = "foo:123"
+}
+`
+	lis := bufconn.Listen(testutil.BufSize)
+	targetAuthz, err := rpcauth.NewWithPolicy(ctx, targetPolicy, delegation.Hook(verifier, "foo:123", true))
+	tu.FatalOnErr("NewWithPolicy", err, t)
+	target := grpc.NewServer(grpc.UnaryInterceptor(targetAuthz.Authorize))
+	tdpb.RegisterTestServiceServer(target, &testutil.EchoTestDataServer{})
+	go target.Serve(lis)
+	t.Cleanup(target.Stop)
+	targets := map[string]*bufconn.Listener{"foo:123": lis}
+
+	for _, tc := range []struct {
+		name     string
+		caller   string
+		opts     []Option
+		wantCode codes.Code
+	}{
+		{
+			name:   "alice with delegation",
+			caller: "alice",
+			opts:   []Option{WithDelegation(minter)},
+		},
+		{
+			name:     "bob with delegation",
+			caller:   "bob",
+			opts:     []Option{WithDelegation(minter)},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "alice without delegation",
+			caller:   "alice",
+			wantCode: codes.PermissionDenied,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// Make the caller appear to have authenticated with a certificate.
+			withPeer := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				p := &peer.Peer{
+					Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)},
+					AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+						PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: tc.caller}}},
+					}},
+				}
+				return handler(srv, &peerStream{ServerStream: ss, ctx: peer.NewContext(ss.Context(), p)})
+			}
+			authz := testutil.NewAllowAllRPCAuthorizer(ctx, t)
+			targetDialer := NewDialer(testutil.WithBufDialer(targets), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			proxyLis := bufconn.Listen(testutil.BufSize)
+			grpcServer := grpc.NewServer(grpc.ChainStreamInterceptor(withPeer, authz.AuthorizeStream))
+			New(targetDialer, authz, tc.opts...).Register(grpcServer)
+			go grpcServer.Serve(proxyLis)
+			t.Cleanup(grpcServer.Stop)
+			conn, err := grpc.DialContext(ctx, "proxy", testutil.WithBufDialer(map[string]*bufconn.Listener{"proxy": proxyLis}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			tu.FatalOnErr("DialContext(proxy)", err, t)
+			proxyStream, err := pb.NewProxyClient(conn).Proxy(ctx)
+			tu.FatalOnErr("proxy.Proxy()", err, t)
+
+			streamID := testutil.MustStartStream(t, proxyStream, "foo:123", "/Testdata.TestService/TestUnary")
+			req := testutil.PackStreamData(t, &tdpb.TestRequest{Input: "Foo"}, streamID)
+			reply := testutil.Exchange(t, proxyStream, req)
+			if tc.wantCode == codes.OK {
+				testutil.UnpackStreamData(t, reply)
+				reply = testutil.Exchange(t, proxyStream, nil)
+			}
+			sc := reply.GetServerClose()
+			if sc == nil {
+				t.Fatalf("expected reply of type ServerClose, got %v", reply)
+			}
+			if got := codes.Code(sc.GetStatus().GetCode()); got != tc.wantCode {
+				t.Errorf("ServerClose.Status code = %v, want %v (%v)", got, tc.wantCode, sc.GetStatus())
+			}
+		})
+	}
+}
+
+func TestProxyServerForwardsTarget(t *testing.T) {
+	ctx := context.Background()
+	targetPolicy := `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.host.target = "foo:123"
+}
+`
+	lis := bufconn.Listen(testutil.BufSize)
+	targetAuthz, err := rpcauth.NewWithPolicy(ctx, targetPolicy, rpcauth.ProxyTargetHook())
+	tu.FatalOnErr("NewWithPolicy", err, t)
+	target := grpc.NewServer(grpc.UnaryInterceptor(targetAuthz.Authorize))
+	tdpb.RegisterTestServiceServer(target, &testutil.EchoTestDataServer{})
+	go target.Serve(lis)
+	t.Cleanup(target.Stop)
+	// The same server is reachable by two names, only one of which is allowed.
+	targets := map[string]*bufconn.Listener{"foo:123": lis, "bar:123": lis}
+
+	proxyStream := startTestProxyWithAuthz(ctx, t, targets, testutil.NewAllowAllRPCAuthorizer(ctx, t))
+	for _, tc := range []struct {
+		target   string
+		wantCode codes.Code
+	}{
+		{target: "foo:123"},
+		{target: "bar:123", wantCode: codes.PermissionDenied},
+	} {
+		streamID := testutil.MustStartStream(t, proxyStream, tc.target, "/Testdata.TestService/TestUnary")
+		req := testutil.PackStreamData(t, &tdpb.TestRequest{Input: "Foo"}, streamID)
+		reply := testutil.Exchange(t, proxyStream, req)
+		if tc.wantCode == codes.OK {
+			testutil.UnpackStreamData(t, reply)
+			reply = testutil.Exchange(t, proxyStream, nil)
+		}
+		if code := codes.Code(reply.GetServerClose().GetStatus().GetCode()); code != tc.wantCode {
+			t.Errorf("%s: ServerClose = %v, want %v", tc.target, reply, tc.wantCode)
+		}
+	}
+}
@@ -28,11 +28,14 @@ import (
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
+	"github.com/Snowflake-Labs/sansshell/auth/delegation"
 	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/auth/proxyhint"
 	pb "github.com/Snowflake-Labs/sansshell/proxy"
 )
 
@@ -278,6 +281,12 @@ type TargetStreamSet struct {
 	// an Authorizer, for authorizing requests sent to targets.
 	authorizer *rpcauth.Authorizer
 
+	// If non-nil, signs decision hints sent to targets.
+	hints *proxyhint.Signer
+
+	// If non-nil, mints delegation tokens sent to targets.
+	delegation *delegation.Minter
+
 	// The set of streams managed by this set
 	streams map[uint64]*TargetStream
 
@@ -349,7 +358,39 @@ func (t *TargetStreamSet) Add(ctx context.Context, req *pb.StartStream, replyCha
 		return nil
 	}
 	// TODO(jallie): authorization check for opening new stream goes here
-	stream, err := NewTargetStream(ctx, req.GetTarget(), t.targetDialer, serviceMethod)
+	streamCtx := ctx
+	// Tell the target which target was asked for, so its policy can be
+	// scoped to it. Pass along any justification so targets can apply policy
+	// to and audit it as well.
+	streamCtx = metadata.AppendToOutgoingContext(streamCtx, rpcauth.ProxyTargetKey, req.GetTarget())
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if j := md.Get(rpcauth.ReqJustKey); len(j) > 0 {
+			streamCtx = metadata.AppendToOutgoingContext(streamCtx, rpcauth.ReqJustKey, j[0])
+		}
+	}
+	if t.hints != nil {
+		hint, err := t.decisionHint(ctx, req.GetTarget(), serviceMethod.FullName())
+		if err != nil {
+			reply.GetStartStreamReply().Reply = &pb.StartStreamReply_ErrorStatus{
+				ErrorStatus: convertStatus(status.Newf(codes.Internal, "can't sign decision hint: %v", err)),
+			}
+			sendReply(reply)
+			return nil
+		}
+		streamCtx = metadata.AppendToOutgoingContext(streamCtx, proxyhint.MetadataKey, hint)
+	}
+	if t.delegation != nil {
+		token, err := t.delegationToken(ctx, req.GetTarget(), serviceMethod.FullName())
+		if err != nil {
+			reply.GetStartStreamReply().Reply = &pb.StartStreamReply_ErrorStatus{
+				ErrorStatus: convertStatus(status.Newf(codes.Internal, "can't mint delegation token: %v", err)),
+			}
+			sendReply(reply)
+			return nil
+		}
+		streamCtx = metadata.AppendToOutgoingContext(streamCtx, delegation.MetadataKey, token)
+	}
+	stream, err := NewTargetStream(streamCtx, req.GetTarget(), t.targetDialer, serviceMethod)
 	if err != nil {
 		reply.GetStartStreamReply().Reply = &pb.StartStreamReply_ErrorStatus{
 			ErrorStatus: convertStatus(status.New(codes.Internal, err.Error())),
@@ -378,6 +419,36 @@ func (t *TargetStreamSet) Add(ctx context.Context, req *pb.StartStream, replyCha
 	return nil
 }
 
+// decisionHint returns a signed hint that requests for `method` from the
+// caller in ctx are authorized by this proxy before being sent to `target`.
+func (t *TargetStreamSet) decisionHint(ctx context.Context, target string, method string) (string, error) {
+	input, err := rpcauth.NewRPCAuthInput(ctx, method, nil)
+	if err != nil {
+		return "", err
+	}
+	return t.hints.Sign(proxyhint.Decision{
+		Target:        target,
+		Method:        method,
+		PolicyVersion: t.authorizer.PolicyVersion(),
+		Query:         t.authorizer.AllowQuery(),
+		InputHash:     rpcauth.HashInput(input),
+	})
+}
+
+// delegationToken returns a token permitting the caller in ctx (as
+// identified by its peer credentials) to call `method` on `target`.
+func (t *TargetStreamSet) delegationToken(ctx context.Context, target string, method string) (string, error) {
+	input, err := rpcauth.NewRPCAuthInput(ctx, method, nil)
+	if err != nil {
+		return "", err
+	}
+	return t.delegation.Mint(delegation.Grant{
+		Subject: rpcauth.PrincipalID(input),
+		Target:  target,
+		Methods: []string{method},
+	})
+}
+
 // Remove the stream corresponding to `streamid` from the
 // stream set. Future references to this stream will return
 // an error
@@ -465,7 +536,8 @@ func (t *TargetStreamSet) Send(ctx context.Context, req *pb.StreamData) error {
 		}
 		streamPeerInfo := stream.PeerAuthInfo()
 		authinput.Host = &rpcauth.HostAuthInput{
-			Net: streamPeerInfo.Net,
+			Net:    streamPeerInfo.Net,
+			Target: stream.Target(),
 		}
 
 		// If authz fails, close immediately with an error
@@ -28,6 +28,7 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/Snowflake-Labs/sansshell/auth/opa"
 	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
 	"github.com/Snowflake-Labs/sansshell/services"
 	"github.com/Snowflake-Labs/sansshell/telemetry"
@@ -40,24 +41,33 @@ var (
 )
 
 // Serve wraps up BuildServer in a succinct API for callers passing along various parameters. It will automatically add
-// an authz hook for HostNet based on the listener address. Additional hooks are passed along after this one.
+// an authz hook for HostNet based on the listener address and one for any proxy target (see rpcauth.ProxyTargetHook).
+// Additional hooks are passed along after these.
 func Serve(hostport string, c credentials.TransportCredentials, policy string, logger logr.Logger, authzHooks ...rpcauth.RPCAuthzHook) error {
+	p, err := opa.NewAuthzPolicy(context.Background(), policy)
+	if err != nil {
+		return err
+	}
+	return ServeWithAuthzPolicy(hostport, c, p, logger, authzHooks...)
+}
+
+// ServeWithAuthzPolicy is the same as Serve except it takes an already compiled policy.
+// This allows callers to update the policy in place (via AuthzPolicy.Update) while serving.
+func ServeWithAuthzPolicy(hostport string, c credentials.TransportCredentials, policy *opa.AuthzPolicy, logger logr.Logger, authzHooks ...rpcauth.RPCAuthzHook) error {
 	lis, err := net.Listen("tcp", hostport)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
 
 	mu.Lock()
-	h := []rpcauth.RPCAuthzHook{rpcauth.HostNetHook(lis.Addr())}
+	h := []rpcauth.RPCAuthzHook{rpcauth.HostNetHook(lis.Addr()), rpcauth.ProxyTargetHook()}
 	h = append(h, authzHooks...)
 
-	srv, err = BuildServer(c, policy, logger, h...)
+	srv = BuildServerWithAuthzPolicy(c, policy, logger, h...)
+	s := srv
 	mu.Unlock()
-	if err != nil {
-		return err
-	}
 
-	return srv.Serve(lis)
+	return s.Serve(lis)
 }
 
 // Test helper to get at srv
@@ -71,10 +81,16 @@ func getSrv() *grpc.Server {
 // registers all of the imported SansShell modules. Separating this from Serve
 // primarily facilitates testing.
 func BuildServer(c credentials.TransportCredentials, policy string, logger logr.Logger, authzHooks ...rpcauth.RPCAuthzHook) (*grpc.Server, error) {
-	authz, err := rpcauth.NewWithPolicy(context.Background(), policy, authzHooks...)
+	p, err := opa.NewAuthzPolicy(context.Background(), policy)
 	if err != nil {
 		return nil, err
 	}
+	return BuildServerWithAuthzPolicy(c, p, logger, authzHooks...), nil
+}
+
+// BuildServerWithAuthzPolicy is the same as BuildServer except it takes an already compiled policy.
+func BuildServerWithAuthzPolicy(c credentials.TransportCredentials, policy *opa.AuthzPolicy, logger logr.Logger, authzHooks ...rpcauth.RPCAuthzHook) *grpc.Server {
+	authz := rpcauth.New(policy, authzHooks...)
 	opts := []grpc.ServerOption{
 		grpc.Creds(c),
 		// NB: the order of chained interceptors is meaningful.
@@ -88,5 +104,5 @@ func BuildServer(c credentials.TransportCredentials, policy string, logger logr.
 	for _, sansShellService := range services.ListServices() {
 		sansShellService.Register(s)
 	}
-	return s, nil
+	return s
 }
@@ -20,6 +20,9 @@ const _ = grpc.SupportPackageIsVersion7
 type PlaybookClient interface {
 	// Will run ansible-playbook only on the local host using the args passed.
 	Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (*RunReply, error)
+	// StreamingRun is Run with the output and the result of each task streamed
+	// back as the playbook runs.
+	StreamingRun(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (Playbook_StreamingRunClient, error)
 }
 
 type playbookClient struct {
@@ -39,12 +42,47 @@ func (c *playbookClient) Run(ctx context.Context, in *RunRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *playbookClient) StreamingRun(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (Playbook_StreamingRunClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Playbook_ServiceDesc.Streams[0], "/Ansible.Playbook/StreamingRun", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &playbookStreamingRunClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Playbook_StreamingRunClient interface {
+	Recv() (*StreamingRunReply, error)
+	grpc.ClientStream
+}
+
+type playbookStreamingRunClient struct {
+	grpc.ClientStream
+}
+
+func (x *playbookStreamingRunClient) Recv() (*StreamingRunReply, error) {
+	m := new(StreamingRunReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PlaybookServer is the server API for Playbook service.
 // All implementations should embed UnimplementedPlaybookServer
 // for forward compatibility
 type PlaybookServer interface {
 	// Will run ansible-playbook only on the local host using the args passed.
 	Run(context.Context, *RunRequest) (*RunReply, error)
+	// StreamingRun is Run with the output and the result of each task streamed
+	// back as the playbook runs.
+	StreamingRun(*RunRequest, Playbook_StreamingRunServer) error
 }
 
 // UnimplementedPlaybookServer should be embedded to have forward compatible implementations.
@@ -54,6 +92,9 @@ type UnimplementedPlaybookServer struct {
 func (UnimplementedPlaybookServer) Run(context.Context, *RunRequest) (*RunReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Run not implemented")
 }
+func (UnimplementedPlaybookServer) StreamingRun(*RunRequest, Playbook_StreamingRunServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamingRun not implemented")
+}
 
 // UnsafePlaybookServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to PlaybookServer will
@@ -84,6 +125,27 @@ func _Playbook_Run_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Playbook_StreamingRun_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlaybookServer).StreamingRun(m, &playbookStreamingRunServer{stream})
+}
+
+type Playbook_StreamingRunServer interface {
+	Send(*StreamingRunReply) error
+	grpc.ServerStream
+}
+
+type playbookStreamingRunServer struct {
+	grpc.ServerStream
+}
+
+func (x *playbookStreamingRunServer) Send(m *StreamingRunReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Playbook_ServiceDesc is the grpc.ServiceDesc for Playbook service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -96,6 +158,12 @@ var Playbook_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Playbook_Run_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamingRun",
+			Handler:       _Playbook_StreamingRun_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "ansible.proto",
 }
@@ -21,7 +21,9 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 
 	"github.com/google/subcommands"
 
@@ -33,7 +35,7 @@ import (
 const subPackage = "ansible"
 
 func init() {
-	subcommands.Register(&ansibleCmd{}, subPackage)
+	client.RegisterSubpackage(subPackage, setup)
 }
 
 func setup(f *flag.FlagSet) *subcommands.Commander {
@@ -42,29 +44,15 @@ func setup(f *flag.FlagSet) *subcommands.Commander {
 	return c
 }
 
-type ansibleCmd struct{}
-
-func (*ansibleCmd) Name() string { return subPackage }
-func (p *ansibleCmd) Synopsis() string {
-	return client.GenerateSynopsis(setup(flag.NewFlagSet("", flag.ContinueOnError)))
-}
-func (p *ansibleCmd) Usage() string {
-	return client.GenerateUsage(subPackage, p.Synopsis())
-}
-func (*ansibleCmd) SetFlags(f *flag.FlagSet) {}
-
-func (p *ansibleCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
-	c := setup(f)
-	return c.Execute(ctx, args...)
-}
-
 type playbookCmd struct {
-	playbook string
-	vars     util.KeyValueSliceFlag
-	user     string
-	check    bool
-	diff     bool
-	verbose  bool
+	playbook     string
+	playbookFile string
+	vars         util.KeyValueSliceFlag
+	user         string
+	check        bool
+	diff         bool
+	verbose      bool
+	stream       bool
 }
 
 func (*playbookCmd) Name() string     { return "playbook" }
@@ -77,16 +65,18 @@ func (*playbookCmd) Usage() string {
 
 func (a *playbookCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&a.playbook, "playbook", "", "The absolute path to the playbook to execute on the remote server.")
+	f.StringVar(&a.playbookFile, "playbook-file", "", "A local playbook to send to the remote server and execute there, instead of --playbook")
 	f.Var(&a.vars, "vars", "Pass key=value (via -e) to ansible-playbook. Multiple values can be specified separated by commas")
 	f.StringVar(&a.user, "user", "", "Run the playbook as this user")
 	f.BoolVar(&a.check, "check", false, "If true the playbook will be run with --check passed as an argument")
 	f.BoolVar(&a.diff, "diff", false, "If true the playbook will be run with --diff passed as an argument")
 	f.BoolVar(&a.verbose, "verbose", false, "If true the playbook wiill be run with -vvv passed as an argument")
+	f.BoolVar(&a.stream, "stream", false, "If true stream output from the playbook as it runs, followed by a summary of task results")
 }
 
 func (a *playbookCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
-	if a.playbook == "" {
-		fmt.Fprintln(os.Stderr, "--playbook is required")
+	if (a.playbook == "") == (a.playbookFile == "") {
+		fmt.Fprintln(os.Stderr, "exactly one of --playbook or --playbook-file is required")
 		return subcommands.ExitFailure
 	}
 
@@ -101,6 +91,14 @@ func (a *playbookCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...inte
 		Diff:     a.diff,
 		Verbose:  a.verbose,
 	}
+	if a.playbookFile != "" {
+		contents, err := os.ReadFile(a.playbookFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can't read playbook: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		req.PlaybookContent = string(contents)
+	}
 	for _, kv := range a.vars {
 		req.Vars = append(req.Vars, &pb.Var{
 			Key:   kv.Key,
@@ -108,6 +106,10 @@ func (a *playbookCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...inte
 		})
 	}
 
+	if a.stream {
+		return a.streamRun(ctx, state, c, req)
+	}
+
 	resp, err := c.RunOneMany(ctx, req)
 	if err != nil {
 		// Emit this to every error file as it's not specific to a given target.
@@ -129,3 +131,70 @@ func (a *playbookCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...inte
 	}
 	return retCode
 }
+
+// streamRun runs req with StreamingRun, writing output for each target as it
+// arrives followed by the number of tasks with each result status.
+func (a *playbookCmd) streamRun(ctx context.Context, state *util.ExecuteState, c pb.PlaybookClientProxy, req *pb.RunRequest) subcommands.ExitStatus {
+	stream, err := c.StreamingRunOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "StreamingRun returned error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	counts := make(map[int]map[string]int)
+	failed := make(map[int]bool)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "StreamingRun returned error: %v\n", err)
+			}
+			return subcommands.ExitFailure
+		}
+		for _, r := range resp {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "Ansible for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+				failed[r.Index] = true
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			state.Out[r.Index].Write(r.Resp.Output)
+			if counts[r.Index] == nil {
+				counts[r.Index] = make(map[string]int)
+			}
+			for _, t := range r.Resp.Results {
+				counts[r.Index][t.Status]++
+			}
+			if r.Resp.Finished {
+				if r.Resp.ReturnCode != 0 {
+					retCode = subcommands.ExitFailure
+				}
+				fmt.Fprintf(state.Out[r.Index], "\nReturn code: %d\n", r.Resp.ReturnCode)
+			}
+		}
+	}
+	for idx, c := range counts {
+		if failed[idx] {
+			continue
+		}
+		var statuses []string
+		for s := range c {
+			statuses = append(statuses, s)
+		}
+		sort.Strings(statuses)
+		fmt.Fprint(state.Out[idx], "Task results:")
+		for _, s := range statuses {
+			fmt.Fprintf(state.Out[idx], " %s=%d", s, c[s])
+		}
+		fmt.Fprintln(state.Out[idx])
+	}
+	return retCode
+}
@@ -18,11 +18,14 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/Snowflake-Labs/sansshell/services"
 	pb "github.com/Snowflake-Labs/sansshell/services/ansible"
@@ -32,7 +35,11 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-var ansiblePlaybookBin = flag.String("ansible_playbook_bin", "/usr/bin/ansible-playbook", "Path to ansible-playbook binary")
+var (
+	ansiblePlaybookBin     = flag.String("ansible_playbook_bin", "/usr/bin/ansible-playbook", "Path to ansible-playbook binary")
+	playbookDirs           = flag.String("ansible_playbook_dirs", "", "Comma separated list of directories playbooks must be under. If empty any playbook may be run.")
+	allowSuppliedPlaybooks = flag.Bool("ansible_allow_supplied_playbooks", false, "If true requests may supply the playbook to run rather than a path to one")
+)
 
 // A test hook so we can take the args passed and transform them as needed.
 var cmdArgsTransform = func(input []string) []string {
@@ -44,20 +51,75 @@ type server struct{}
 
 var re = regexp.MustCompile("[^a-zA-Z0-9_/]+")
 
-func (s *server) Run(ctx context.Context, req *pb.RunRequest) (*pb.RunReply, error) {
-	// Basic sanity checking up front.
-	if req.Playbook == "" {
-		return nil, status.Error(codes.InvalidArgument, "playbook path must be filled in")
+// checkPlaybookDir returns an error if --ansible_playbook_dirs is set and
+// path (with symlinks resolved) isn't under one of them.
+func checkPlaybookDir(path string) error {
+	if *playbookDirs == "" {
+		return nil
 	}
-	if err := util.ValidPath(req.Playbook); err != nil {
-		return nil, err
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%s is not a valid file", path)
+	}
+	for _, d := range strings.Split(*playbookDirs, ",") {
+		if d == "" {
+			continue
+		}
+		dir, err := filepath.EvalSymlinks(d)
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(real, dir+string(filepath.Separator)) {
+			return nil
+		}
 	}
+	return status.Errorf(codes.PermissionDenied, "%s is not under an allowed playbook directory (see --ansible_playbook_dirs)", path)
+}
 
-	// Make sure it's a valid file and nothing something which might be malicious like
-	// /some/path && rm -rf /
-	stat, err := os.Stat(req.Playbook)
-	if err != nil || stat.IsDir() {
-		return nil, status.Errorf(codes.InvalidArgument, "%s is not a valid file", req.Playbook)
+// prepare validates req and returns the arguments to run ansible-playbook
+// with, and a func to call once it's done.
+func prepare(req *pb.RunRequest) ([]string, func(), error) {
+	cleanup := func() {}
+	playbook := req.Playbook
+	switch {
+	case req.PlaybookContent != "":
+		if playbook != "" {
+			return nil, nil, status.Error(codes.InvalidArgument, "only one of playbook and playbook_content can be set")
+		}
+		if !*allowSuppliedPlaybooks {
+			return nil, nil, status.Error(codes.FailedPrecondition, "supplied playbooks are disabled on this server (see --ansible_allow_supplied_playbooks)")
+		}
+		f, err := os.CreateTemp("", "sansshell-playbook-*.yml")
+		if err != nil {
+			return nil, nil, status.Errorf(codes.Internal, "can't create playbook file: %v", err)
+		}
+		cleanup = func() { os.Remove(f.Name()) }
+		_, err = f.WriteString(req.PlaybookContent)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			cleanup()
+			return nil, nil, status.Errorf(codes.Internal, "can't write playbook file: %v", err)
+		}
+		playbook = f.Name()
+	case playbook == "":
+		// Basic sanity checking up front.
+		return nil, nil, status.Error(codes.InvalidArgument, "playbook path must be filled in")
+	default:
+		if err := util.ValidPath(playbook); err != nil {
+			return nil, nil, err
+		}
+
+		// Make sure it's a valid file and nothing something which might be malicious like
+		// /some/path && rm -rf /
+		stat, err := os.Stat(playbook)
+		if err != nil || stat.IsDir() {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "%s is not a valid file", playbook)
+		}
+		if err := checkPlaybookDir(playbook); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	cmdArgs := []string{
@@ -68,7 +130,8 @@ func (s *server) Run(ctx context.Context, req *pb.RunRequest) (*pb.RunReply, err
 
 	for _, v := range req.Vars {
 		if v.Key != re.ReplaceAllString(v.Key, "") || v.Value != re.ReplaceAllString(v.Value, "") {
-			return nil, status.Errorf(codes.InvalidArgument, "vars must contain key/value that is only contains %s - '%s=%s' is invalid", re.String(), v.Key, v.Value)
+			cleanup()
+			return nil, nil, status.Errorf(codes.InvalidArgument, "vars must contain key/value that is only contains %s - '%s=%s' is invalid", re.String(), v.Key, v.Value)
 		}
 		cmdArgs = append(cmdArgs, "-e")
 		cmdArgs = append(cmdArgs, fmt.Sprintf("%s=%s", v.Key, v.Value))
@@ -76,7 +139,8 @@ func (s *server) Run(ctx context.Context, req *pb.RunRequest) (*pb.RunReply, err
 
 	if req.User != "" {
 		if req.User != re.ReplaceAllString(req.User, "") {
-			return nil, status.Errorf(codes.InvalidArgument, "user must only contain %s - %q is invalid", re.String(), req.User)
+			cleanup()
+			return nil, nil, status.Errorf(codes.InvalidArgument, "user must only contain %s - %q is invalid", re.String(), req.User)
 		}
 		cmdArgs = append(cmdArgs, "--become")
 		cmdArgs = append(cmdArgs, req.User)
@@ -94,9 +158,17 @@ func (s *server) Run(ctx context.Context, req *pb.RunRequest) (*pb.RunReply, err
 		cmdArgs = append(cmdArgs, "-vvv")
 	}
 
-	cmdArgs = append(cmdArgs, req.Playbook)
+	cmdArgs = append(cmdArgs, playbook)
 
-	cmdArgs = cmdArgsTransform(cmdArgs)
+	return cmdArgsTransform(cmdArgs), cleanup, nil
+}
+
+func (s *server) Run(ctx context.Context, req *pb.RunRequest) (*pb.RunReply, error) {
+	cmdArgs, cleanup, err := prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
 
 	run, err := util.RunCommand(ctx, *ansiblePlaybookBin, cmdArgs)
 	if err != nil {
@@ -110,6 +182,101 @@ func (s *server) Run(ctx context.Context, req *pb.RunRequest) (*pb.RunReply, err
 	}, nil
 }
 
+// resultRE matches the line ansible's default output has for the result
+// of a task on a host, i.e. "changed: [localhost]" or
+// "fatal: [localhost]: UNREACHABLE! => {...}".
+var resultRE = regexp.MustCompile(`^(ok|changed|skipping|failed|fatal): \[([^\]]+)\](: UNREACHABLE!)?`)
+
+// resultSender sends everything written to it as output on a stream,
+// along with the task results parsed from it. util.StreamOutput serializes
+// writes.
+type resultSender struct {
+	stream pb.Playbook_StreamingRunServer
+
+	// partial is the incomplete last line of output so far.
+	partial []byte
+	// The play and task whose results are being output.
+	play, task string
+}
+
+// parseLine updates the current play or task from a header line, or
+// returns the result a line reports.
+func (r *resultSender) parseLine(line string) *pb.TaskResult {
+	header := func(prefix string) (string, bool) {
+		if !strings.HasPrefix(line, prefix) {
+			return "", false
+		}
+		end := strings.LastIndex(line, "]")
+		if end < len(prefix) {
+			return "", false
+		}
+		return line[len(prefix):end], true
+	}
+	if play, ok := header("PLAY ["); ok {
+		r.play, r.task = play, ""
+		return nil
+	}
+	if task, ok := header("TASK ["); ok {
+		r.task = task
+		return nil
+	}
+	if task, ok := header("RUNNING HANDLER ["); ok {
+		r.task = task
+		return nil
+	}
+	m := resultRE.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	status := m[1]
+	if m[3] != "" {
+		status = "unreachable"
+	}
+	return &pb.TaskResult{Play: r.play, Task: r.task, Host: m[2], Status: status}
+}
+
+func (r *resultSender) Write(p []byte) (int, error) {
+	reply := &pb.StreamingRunReply{Output: p}
+	r.partial = append(r.partial, p...)
+	for {
+		i := bytes.IndexByte(r.partial, '\n')
+		if i < 0 {
+			break
+		}
+		if res := r.parseLine(string(r.partial[:i])); res != nil {
+			reply.Results = append(reply.Results, res)
+		}
+		r.partial = r.partial[i+1:]
+	}
+	if err := r.stream.Send(reply); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// StreamingRun implements pb.PlaybookServer.StreamingRun
+func (s *server) StreamingRun(req *pb.RunRequest, stream pb.Playbook_StreamingRunServer) error {
+	cmdArgs, cleanup, err := prepare(req)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	sender := &resultSender{stream: stream}
+	run, err := util.RunCommand(stream.Context(), *ansiblePlaybookBin, cmdArgs, util.StreamOutput(sender))
+	if err != nil {
+		return err
+	}
+	final := &pb.StreamingRunReply{Finished: true, ReturnCode: int32(run.ExitCode)}
+	if res := sender.parseLine(string(sender.partial)); res != nil {
+		final.Results = append(final.Results, res)
+	}
+	if err := stream.Send(final); err != nil {
+		return status.Errorf(codes.Internal, "can't send on stream: %v", err)
+	}
+	return nil
+}
+
 // Install is called to expose this handler to the gRPC server
 func (s *server) Register(gs *grpc.Server) {
 	pb.RegisterPlaybookServer(gs, s)
@@ -24,6 +24,7 @@ package server
 //       binary works as well. i.e. what testing/integrate.sh does.
 import (
 	"context"
+	"io"
 	"log"
 	"net"
 	"os"
@@ -34,8 +35,11 @@ import (
 	"github.com/Snowflake-Labs/sansshell/testing/testutil"
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 var (
@@ -301,3 +305,195 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestPlaybookSources(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	savedAnsiblePlaybookBin := *ansiblePlaybookBin
+	savedPlaybookDirs := *playbookDirs
+	savedAllowSuppliedPlaybooks := *allowSuppliedPlaybooks
+	savedCmdArgsTransform := cmdArgsTransform
+	t.Cleanup(func() {
+		*ansiblePlaybookBin = savedAnsiblePlaybookBin
+		*playbookDirs = savedPlaybookDirs
+		*allowSuppliedPlaybooks = savedAllowSuppliedPlaybooks
+		cmdArgsTransform = savedCmdArgsTransform
+	})
+
+	// Run cat on the playbook so we can see which one was used.
+	*ansiblePlaybookBin = testutil.ResolvePath(t, "cat")
+	cmdArgsTransform = func(input []string) []string {
+		return input[len(input)-1:]
+	}
+
+	client := pb.NewPlaybookClient(conn)
+
+	wd, err := os.Getwd()
+	testutil.FatalOnErr("can't get current working directory", err, t)
+	testdata := filepath.Join(wd, "testdata")
+	path := filepath.Join(testdata, "test.yml")
+	contents, err := os.ReadFile(path)
+	testutil.FatalOnErr("can't read playbook", err, t)
+
+	// A symlink in an allowed directory pointing outside of it.
+	allowed := t.TempDir()
+	link := filepath.Join(allowed, "link.yml")
+	testutil.FatalOnErr("symlink", os.Symlink(path, link), t)
+
+	for _, tc := range []struct {
+		name       string
+		dirs       string
+		allowInput bool
+		req        *pb.RunRequest
+		wantErr    codes.Code
+		stdout     string
+	}{
+		{
+			name:   "no allowlist",
+			req:    &pb.RunRequest{Playbook: path},
+			stdout: string(contents),
+		},
+		{
+			name:   "in allowed dir",
+			dirs:   allowed + "," + testdata,
+			req:    &pb.RunRequest{Playbook: path},
+			stdout: string(contents),
+		},
+		{
+			name:    "not in allowed dir",
+			dirs:    allowed,
+			req:     &pb.RunRequest{Playbook: path},
+			wantErr: codes.PermissionDenied,
+		},
+		{
+			name:    "symlink out of allowed dir",
+			dirs:    allowed,
+			req:     &pb.RunRequest{Playbook: link},
+			wantErr: codes.PermissionDenied,
+		},
+		{
+			name:    "allowed dir is only a prefix",
+			dirs:    filepath.Join(wd, "test"),
+			req:     &pb.RunRequest{Playbook: path},
+			wantErr: codes.PermissionDenied,
+		},
+		{
+			name:    "supplied playbook not allowed",
+			req:     &pb.RunRequest{PlaybookContent: "- hosts: localhost\n"},
+			wantErr: codes.FailedPrecondition,
+		},
+		{
+			name:       "supplied playbook",
+			allowInput: true,
+			dirs:       allowed,
+			req:        &pb.RunRequest{PlaybookContent: "- hosts: localhost\n"},
+			stdout:     "- hosts: localhost\n",
+		},
+		{
+			name:       "supplied playbook and path",
+			allowInput: true,
+			req:        &pb.RunRequest{Playbook: path, PlaybookContent: "- hosts: localhost\n"},
+			wantErr:    codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			*playbookDirs = tc.dirs
+			*allowSuppliedPlaybooks = tc.allowInput
+			resp, err := client.Run(ctx, tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("unexpected error. got %v want %v: %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := resp.Stdout, tc.stdout; got != want {
+				t.Fatalf("Stdout doesn't match. Want %q Got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestStreamingRun(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	savedAnsiblePlaybookBin := *ansiblePlaybookBin
+	savedCmdArgsTransform := cmdArgsTransform
+	t.Cleanup(func() {
+		*ansiblePlaybookBin = savedAnsiblePlaybookBin
+		cmdArgsTransform = savedCmdArgsTransform
+	})
+
+	output := `
+PLAY [First play] **************************************************************
+
+TASK [Gathering Facts] *********************************************************
+ok: [localhost]
+
+TASK [Write a file] ************************************************************
+changed: [localhost]
+
+RUNNING HANDLER [restart foo] **************************************************
+skipping: [localhost]
+
+PLAY [Second play] *************************************************************
+
+TASK [Broken] ******************************************************************
+fatal: [localhost]: FAILED! => {"changed": false, "msg": "oops"}
+
+TASK [Remote] ******************************************************************
+fatal: [otherhost]: UNREACHABLE! => {"changed": false, "unreachable": true}
+
+PLAY RECAP *********************************************************************
+localhost                  : ok=1    changed=1    unreachable=0    failed=1
+`
+	script := filepath.Join(t.TempDir(), "ansible-playbook")
+	testutil.FatalOnErr("write script", os.WriteFile(script, []byte("#!/bin/sh\ncat <<'EOF'"+output+"EOF\nexit 2\n"), 0755), t)
+	*ansiblePlaybookBin = script
+	cmdArgsTransform = func(input []string) []string {
+		return nil
+	}
+
+	wd, err := os.Getwd()
+	testutil.FatalOnErr("can't get current working directory", err, t)
+
+	client := pb.NewPlaybookClient(conn)
+	stream, err := client.StreamingRun(ctx, &pb.RunRequest{Playbook: filepath.Join(wd, "testdata", "test.yml")})
+	testutil.FatalOnErr("StreamingRun", err, t)
+
+	var gotOutput []byte
+	var gotResults []*pb.TaskResult
+	var last *pb.StreamingRunReply
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		testutil.FatalOnErr("Recv", err, t)
+		gotOutput = append(gotOutput, resp.Output...)
+		gotResults = append(gotResults, resp.Results...)
+		last = resp
+	}
+	if got, want := string(gotOutput), output[1:]; got != want {
+		t.Fatalf("output doesn't match. Want %q Got %q", want, got)
+	}
+	if last == nil || !last.Finished || last.ReturnCode != 2 {
+		t.Fatalf("last reply should be finished with return code 2: %+v", last)
+	}
+	wantResults := []*pb.TaskResult{
+		{Play: "First play", Task: "Gathering Facts", Host: "localhost", Status: "ok"},
+		{Play: "First play", Task: "Write a file", Host: "localhost", Status: "changed"},
+		{Play: "First play", Task: "restart foo", Host: "localhost", Status: "skipping"},
+		{Play: "Second play", Task: "Broken", Host: "localhost", Status: "fatal"},
+		{Play: "Second play", Task: "Remote", Host: "otherhost", Status: "unreachable"},
+	}
+	if diff := cmp.Diff(wantResults, gotResults, protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected results (-want, +got):\n%s", diff)
+	}
+}
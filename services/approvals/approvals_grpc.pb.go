@@ -0,0 +1,179 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package approvals
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ApprovalsClient is the client API for Approvals service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ApprovalsClient interface {
+	// List returns the requests which are pending or approved but not yet run.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListReply, error)
+	// Approve allows a pending request to run. Requesters can't approve
+	// their own requests.
+	Approve(ctx context.Context, in *ApproveRequest, opts ...grpc.CallOption) (*Approval, error)
+	// Deny rejects a pending request.
+	Deny(ctx context.Context, in *DenyRequest, opts ...grpc.CallOption) (*Approval, error)
+}
+
+type approvalsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewApprovalsClient(cc grpc.ClientConnInterface) ApprovalsClient {
+	return &approvalsClient{cc}
+}
+
+func (c *approvalsClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListReply, error) {
+	out := new(ListReply)
+	err := c.cc.Invoke(ctx, "/Approvals.Approvals/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *approvalsClient) Approve(ctx context.Context, in *ApproveRequest, opts ...grpc.CallOption) (*Approval, error) {
+	out := new(Approval)
+	err := c.cc.Invoke(ctx, "/Approvals.Approvals/Approve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *approvalsClient) Deny(ctx context.Context, in *DenyRequest, opts ...grpc.CallOption) (*Approval, error) {
+	out := new(Approval)
+	err := c.cc.Invoke(ctx, "/Approvals.Approvals/Deny", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ApprovalsServer is the server API for Approvals service.
+// All implementations should embed UnimplementedApprovalsServer
+// for forward compatibility
+type ApprovalsServer interface {
+	// List returns the requests which are pending or approved but not yet run.
+	List(context.Context, *ListRequest) (*ListReply, error)
+	// Approve allows a pending request to run. Requesters can't approve
+	// their own requests.
+	Approve(context.Context, *ApproveRequest) (*Approval, error)
+	// Deny rejects a pending request.
+	Deny(context.Context, *DenyRequest) (*Approval, error)
+}
+
+// UnimplementedApprovalsServer should be embedded to have forward compatible implementations.
+type UnimplementedApprovalsServer struct {
+}
+
+func (UnimplementedApprovalsServer) List(context.Context, *ListRequest) (*ListReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedApprovalsServer) Approve(context.Context, *ApproveRequest) (*Approval, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Approve not implemented")
+}
+func (UnimplementedApprovalsServer) Deny(context.Context, *DenyRequest) (*Approval, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deny not implemented")
+}
+
+// UnsafeApprovalsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ApprovalsServer will
+// result in compilation errors.
+type UnsafeApprovalsServer interface {
+	mustEmbedUnimplementedApprovalsServer()
+}
+
+func RegisterApprovalsServer(s grpc.ServiceRegistrar, srv ApprovalsServer) {
+	s.RegisterService(&Approvals_ServiceDesc, srv)
+}
+
+func _Approvals_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApprovalsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Approvals.Approvals/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApprovalsServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Approvals_Approve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApprovalsServer).Approve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Approvals.Approvals/Approve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApprovalsServer).Approve(ctx, req.(*ApproveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Approvals_Deny_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DenyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApprovalsServer).Deny(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Approvals.Approvals/Deny",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApprovalsServer).Deny(ctx, req.(*DenyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Approvals_ServiceDesc is the grpc.ServiceDesc for Approvals service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Approvals_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Approvals.Approvals",
+	HandlerType: (*ApprovalsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _Approvals_List_Handler,
+		},
+		{
+			MethodName: "Approve",
+			Handler:    _Approvals_Approve_Handler,
+		},
+		{
+			MethodName: "Deny",
+			Handler:    _Approvals_Deny_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "approvals.proto",
+}
@@ -0,0 +1,236 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package approvals
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// ApprovalsClientProxy is the superset of ApprovalsClient which additionally includes the OneMany proxy methods
+type ApprovalsClientProxy interface {
+	ApprovalsClient
+	ListOneMany(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (<-chan *ListManyResponse, error)
+	ApproveOneMany(ctx context.Context, in *ApproveRequest, opts ...grpc.CallOption) (<-chan *ApproveManyResponse, error)
+	DenyOneMany(ctx context.Context, in *DenyRequest, opts ...grpc.CallOption) (<-chan *DenyManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type approvalsClientProxy struct {
+	*approvalsClient
+}
+
+// NewApprovalsClientProxy creates a ApprovalsClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewApprovalsClientProxy(cc *proxy.Conn) ApprovalsClientProxy {
+	return &approvalsClientProxy{NewApprovalsClient(cc).(*approvalsClient)}
+}
+
+// ListManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ListManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ListReply
+	Error error
+}
+
+// ListOneMany provides the same API as List but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *approvalsClientProxy) ListOneMany(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (<-chan *ListManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ListManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ListManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ListReply{},
+			}
+			err := conn.Invoke(ctx, "/Approvals.Approvals/List", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Approvals.Approvals/List", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ListManyResponse{
+				Resp: &ListReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ApproveManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ApproveManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *Approval
+	Error error
+}
+
+// ApproveOneMany provides the same API as Approve but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *approvalsClientProxy) ApproveOneMany(ctx context.Context, in *ApproveRequest, opts ...grpc.CallOption) (<-chan *ApproveManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ApproveManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ApproveManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &Approval{},
+			}
+			err := conn.Invoke(ctx, "/Approvals.Approvals/Approve", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Approvals.Approvals/Approve", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ApproveManyResponse{
+				Resp: &Approval{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// DenyManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type DenyManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *Approval
+	Error error
+}
+
+// DenyOneMany provides the same API as Deny but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *approvalsClientProxy) DenyOneMany(ctx context.Context, in *DenyRequest, opts ...grpc.CallOption) (<-chan *DenyManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *DenyManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &DenyManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &Approval{},
+			}
+			err := conn.Invoke(ctx, "/Approvals.Approvals/Deny", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Approvals.Approvals/Deny", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &DenyManyResponse{
+				Resp: &Approval{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
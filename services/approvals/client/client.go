@@ -0,0 +1,188 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'approvals'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/approvals"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "approvals"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&listCmd{}, "")
+	c.Register(&approveCmd{}, "")
+	c.Register(&denyCmd{}, "")
+	return c
+}
+
+func stateString(s pb.State) string {
+	return strings.ToLower(strings.TrimPrefix(s.String(), "STATE_"))
+}
+
+// printApproval writes a human readable summary of `a`.
+func printApproval(out io.Writer, a *pb.Approval) error {
+	_, err := fmt.Fprintf(out, "%s %s %s requester=%s reviewer=%s created=%s expires=%s justification=%q message=%s\n",
+		a.Id, stateString(a.State), a.Method, a.Requester, a.Reviewer,
+		a.Created.AsTime().Format(time.RFC3339), a.Expires.AsTime().Format(time.RFC3339), a.Justification, a.Message)
+	return err
+}
+
+type listCmd struct{}
+
+func (*listCmd) Name() string     { return "list" }
+func (*listCmd) Synopsis() string { return "List requests awaiting or granted approval" }
+func (*listCmd) Usage() string {
+	return `list:
+    List the requests which are pending approval, or approved but not yet run.
+`
+}
+func (*listCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*listCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewApprovalsClientProxy(state.Conn)
+
+	respChan, err := c.ListOneMany(ctx, &pb.ListRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, a := range r.Resp.Approvals {
+			if err := printApproval(state.Out[r.Index], a); err != nil {
+				fmt.Fprintf(state.Err[r.Index], "target %s (%d) output write error: %v\n", r.Target, r.Index, err)
+				retCode = subcommands.ExitFailure
+			}
+		}
+	}
+	return retCode
+}
+
+type approveCmd struct{}
+
+func (*approveCmd) Name() string     { return "approve" }
+func (*approveCmd) Synopsis() string { return "Approve a request" }
+func (*approveCmd) Usage() string {
+	return `approve <id>:
+    Approve the pending request <id>, allowing its requester to run it once.
+`
+}
+func (*approveCmd) SetFlags(f *flag.FlagSet) {}
+
+func (p *approveCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify the request ID to approve.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, p)
+		return subcommands.ExitUsageError
+	}
+	c := pb.NewApprovalsClientProxy(state.Conn)
+	respChan, err := c.ApproveOneMany(ctx, &pb.ApproveRequest{Id: f.Arg(0)})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		if err := printApproval(state.Out[r.Index], r.Resp); err != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) output write error: %v\n", r.Target, r.Index, err)
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
+
+type denyCmd struct {
+	reason string
+}
+
+func (*denyCmd) Name() string     { return "deny" }
+func (*denyCmd) Synopsis() string { return "Deny a request" }
+func (*denyCmd) Usage() string {
+	return `deny [--reason <reason>] <id>:
+    Deny the pending request <id>.
+`
+}
+func (p *denyCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.reason, "reason", "", "Why the request is denied, reported to the requester")
+}
+
+func (p *denyCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify the request ID to deny.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, p)
+		return subcommands.ExitUsageError
+	}
+	c := pb.NewApprovalsClientProxy(state.Conn)
+	respChan, err := c.DenyOneMany(ctx, &pb.DenyRequest{Id: f.Arg(0), Reason: p.reason})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		if err := printApproval(state.Out[r.Index], r.Resp); err != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) output write error: %v\n", r.Target, r.Index, err)
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
@@ -0,0 +1,271 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'Approvals' service.
+//
+// The registered server is also an rpcauth.ApprovalGate (see Gate) which
+// holds requests the policy requires approval for until another principal
+// approves them with this service. Pending requests and approvals are only
+// kept in memory, so they're lost when the server restarts.
+//
+// Requesters and reviewers are identified by rpcauth.PrincipalID, so requests
+// forwarded by a proxy are attributed to the proxy unless a hook determines
+// the original caller (i.e. from a bearer token).
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/approvals"
+)
+
+// DefaultTTL is how long a request waits for approval, and then how long
+// an approval remains usable.
+const DefaultTTL = time.Hour
+
+// entry is a request requiring approval.
+type entry struct {
+	approval *pb.Approval
+	// fingerprint identifies the call (method, message and requester)
+	// the approval is for.
+	fingerprint string
+	expires     time.Time
+}
+
+// Server implements the Approvals service and rpcauth.ApprovalGate.
+type Server struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// New returns a Server whose requests and approvals lapse after `ttl`.
+func New(ttl time.Duration) *Server {
+	return &Server{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// defaultServer is the instance registered with the gRPC server.
+var defaultServer = New(DefaultTTL)
+
+// Gate returns the registered server as an rpcauth.ApprovalGate, to be passed
+// to rpcauth.RequireApprovals.
+func Gate() rpcauth.ApprovalGate {
+	return defaultServer
+}
+
+// fingerprint returns a digest of the call described by input made by requester.
+// It includes input.MessageDigest so fields redacted from input.Message (i.e.
+// file contents) still have to match.
+func fingerprint(input *rpcauth.RPCAuthInput, requester string) string {
+	h := sha256.New()
+	for _, s := range []string{input.Method, string(input.Message), input.MessageDigest, requester} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// expire removes lapsed entries. Must be called with s.mu held.
+func (s *Server) expire(now time.Time) {
+	for id, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, id)
+		}
+	}
+}
+
+func pendingError(id string) error {
+	return status.Errorf(codes.FailedPrecondition, "request requires approval: once another user approves request %s retry with %s=%s", id, rpcauth.ApprovalIDKey, id)
+}
+
+// Check implements rpcauth.ApprovalGate. Requests carrying the ID of an
+// approval for the same call by the same requester are allowed, consuming the
+// approval. Otherwise the request is recorded as pending (if it isn't already)
+// and rejected with FailedPrecondition.
+func (s *Server) Check(ctx context.Context, input *rpcauth.RPCAuthInput) error {
+	requester := rpcauth.PrincipalID(input)
+	if requester == "" {
+		return status.Error(codes.PermissionDenied, "requests requiring approval must come from an identified principal")
+	}
+	fp := fingerprint(input, requester)
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+	if ids := input.Metadata.Get(rpcauth.ApprovalIDKey); len(ids) > 0 {
+		id := ids[0]
+		e, ok := s.entries[id]
+		if !ok || e.fingerprint != fp {
+			return status.Errorf(codes.PermissionDenied, "no approval %s for this request", id)
+		}
+		switch e.approval.State {
+		case pb.State_STATE_APPROVED:
+			// Approvals are single use.
+			delete(s.entries, id)
+			logr.FromContextOrDiscard(ctx).Info("running approved request", "id", id, "method", input.Method, "requester", requester, "approver", e.approval.Reviewer)
+			return nil
+		case pb.State_STATE_DENIED:
+			return status.Errorf(codes.PermissionDenied, "request %s was denied by %s: %s", id, e.approval.Reviewer, e.approval.Reason)
+		default:
+			return pendingError(id)
+		}
+	}
+	for id, e := range s.entries {
+		if e.fingerprint == fp && e.approval.State == pb.State_STATE_PENDING {
+			return pendingError(id)
+		}
+	}
+
+	id, err := newID()
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't create request ID: %v", err)
+	}
+	expires := now.Add(s.ttl)
+	s.entries[id] = &entry{
+		approval: &pb.Approval{
+			Id:            id,
+			Method:        input.Method,
+			MessageType:   input.MessageType,
+			Message:       string(input.Message),
+			Requester:     requester,
+			Justification: input.Justification,
+			State:         pb.State_STATE_PENDING,
+			Created:       timestamppb.New(now),
+			Expires:       timestamppb.New(expires),
+		},
+		fingerprint: fp,
+		expires:     expires,
+	}
+	logr.FromContextOrDiscard(ctx).Info("request awaiting approval", "id", id, "method", input.Method, "requester", requester)
+	return pendingError(id)
+}
+
+// List returns all pending and approved requests, oldest first.
+func (s *Server) List(ctx context.Context, req *pb.ListRequest) (*pb.ListReply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(s.now())
+	reply := &pb.ListReply{}
+	for _, e := range s.entries {
+		if e.approval.State == pb.State_STATE_DENIED {
+			continue
+		}
+		reply.Approvals = append(reply.Approvals, proto.Clone(e.approval).(*pb.Approval))
+	}
+	sort.Slice(reply.Approvals, func(i, j int) bool {
+		a, b := reply.Approvals[i], reply.Approvals[j]
+		if !a.Created.AsTime().Equal(b.Created.AsTime()) {
+			return a.Created.AsTime().Before(b.Created.AsTime())
+		}
+		return a.Id < b.Id
+	})
+	return reply, nil
+}
+
+// Approve implements the Approvals Approve RPC.
+func (s *Server) Approve(ctx context.Context, req *pb.ApproveRequest) (*pb.Approval, error) {
+	return s.review(ctx, req.Id, pb.State_STATE_APPROVED, "")
+}
+
+// Deny implements the Approvals Deny RPC.
+func (s *Server) Deny(ctx context.Context, req *pb.DenyRequest) (*pb.Approval, error) {
+	return s.review(ctx, req.Id, pb.State_STATE_DENIED, req.Reason)
+}
+
+// reviewer returns the principal making an RPC, preferably as determined
+// when it was authorized.
+func reviewer(ctx context.Context) string {
+	input := rpcauth.InputFromContext(ctx)
+	if input == nil {
+		input = &rpcauth.RPCAuthInput{Peer: rpcauth.PeerInputFromContext(ctx)}
+	}
+	return rpcauth.PrincipalID(input)
+}
+
+// review moves the pending request `id` to `state`.
+func (s *Server) review(ctx context.Context, id string, state pb.State, reason string) (*pb.Approval, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id must be set")
+	}
+	who := reviewer(ctx)
+	if who == "" {
+		return nil, status.Error(codes.PermissionDenied, "reviewers must be identified principals")
+	}
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+	e, ok := s.entries[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no request %s", id)
+	}
+	if e.approval.State != pb.State_STATE_PENDING {
+		return nil, status.Errorf(codes.FailedPrecondition, "request %s is already %s", id, strings.ToLower(strings.TrimPrefix(e.approval.State.String(), "STATE_")))
+	}
+	if who == e.approval.Requester {
+		return nil, status.Error(codes.PermissionDenied, "requesters can't review their own requests")
+	}
+	e.approval.State = state
+	e.approval.Reviewer = who
+	e.approval.Reason = reason
+	if state == pb.State_STATE_APPROVED {
+		e.expires = now.Add(s.ttl)
+		e.approval.Expires = timestamppb.New(e.expires)
+	}
+	logr.FromContextOrDiscard(ctx).Info("request reviewed", "id", id, "method", e.approval.Method, "requester", e.approval.Requester, "reviewer", who, "state", state)
+	return proto.Clone(e.approval).(*pb.Approval), nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *Server) Register(gs *grpc.Server) {
+	pb.RegisterApprovalsServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(defaultServer)
+}
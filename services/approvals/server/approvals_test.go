@@ -0,0 +1,219 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	pb "github.com/Snowflake-Labs/sansshell/services/approvals"
+	execpb "github.com/Snowflake-Labs/sansshell/services/exec"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+var (
+	bufSize = 1024 * 1024
+	lis     *bufconn.Listener
+	srv     = New(time.Hour)
+	now     = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func bufDialer(context.Context, string) (net.Conn, error) {
+	return lis.Dial()
+}
+
+// principalHook sets the principal from the "principal" metadata.
+var principalHook = rpcauth.RPCAuthzHookFunc(func(ctx context.Context, input *rpcauth.RPCAuthInput) error {
+	if p := input.Metadata.Get("principal"); len(p) > 0 {
+		input.Peer.Principal = &rpcauth.PrincipalAuthInput{ID: p[0]}
+	}
+	return nil
+})
+
+func TestMain(m *testing.M) {
+	srv.now = func() time.Time { return now }
+	authz, err := rpcauth.NewWithPolicy(context.Background(), "package sansshell.authz\ndefault allow = true", principalHook)
+	if err != nil {
+		log.Fatalf("NewWithPolicy: %v", err)
+	}
+	lis = bufconn.Listen(bufSize)
+	s := grpc.NewServer(grpc.UnaryInterceptor(authz.Authorize))
+	srv.Register(s)
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			log.Fatalf("Server exited with error: %v", err)
+		}
+	}()
+	defer s.GracefulStop()
+
+	os.Exit(m.Run())
+}
+
+func input(principal string, message string, approvalID string) *rpcauth.RPCAuthInput {
+	in := &rpcauth.RPCAuthInput{
+		Method:        "/Exec.Exec/Run",
+		MessageType:   "Exec.ExecRequest",
+		Message:       []byte(message),
+		Metadata:      metadata.MD{},
+		Justification: "ticket-1",
+		Peer:          &rpcauth.PeerAuthInput{Principal: &rpcauth.PrincipalAuthInput{ID: principal}},
+	}
+	if approvalID != "" {
+		in.Metadata.Set(rpcauth.ApprovalIDKey, approvalID)
+	}
+	return in
+}
+
+// pendingID extracts the request ID from a pending approval error.
+func pendingID(t *testing.T, err error) string {
+	t.Helper()
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Check() = %v, want FailedPrecondition", err)
+	}
+	f := strings.Fields(status.Convert(err).Message())
+	for i, w := range f {
+		if w == "request" && i+1 < len(f) && f[i+1] != "requires" {
+			return f[i+1]
+		}
+	}
+	t.Fatalf("no request ID in %v", err)
+	return ""
+}
+
+func TestApprovals(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewApprovalsClient(conn)
+	as := func(principal string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "principal", principal)
+	}
+
+	const msg = `{"command":"/bin/rm"}`
+	err = srv.Check(ctx, &rpcauth.RPCAuthInput{Method: "/Exec.Exec/Run"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Check() without principal = %v, want PermissionDenied", err)
+	}
+	id := pendingID(t, srv.Check(ctx, input("alice", msg, "")))
+	if got := pendingID(t, srv.Check(ctx, input("alice", msg, ""))); got != id {
+		t.Errorf("repeated request got ID %s, want %s", got, id)
+	}
+	if got := pendingID(t, srv.Check(ctx, input("alice", msg, id))); got != id {
+		t.Errorf("retried pending request got ID %s, want %s", got, id)
+	}
+
+	list, err := client.List(as("bob"), &pb.ListRequest{})
+	testutil.FatalOnErr("List", err, t)
+	if len(list.Approvals) != 1 {
+		t.Fatalf("List() = %v, want 1 approval", list)
+	}
+	a := list.Approvals[0]
+	if a.Id != id || a.Requester != "alice" || a.Message != msg || a.Justification != "ticket-1" || a.State != pb.State_STATE_PENDING {
+		t.Errorf("List() returned %v", a)
+	}
+
+	_, err = client.Approve(as("alice"), &pb.ApproveRequest{Id: id})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("self approval = %v, want PermissionDenied", err)
+	}
+	_, err = client.Approve(as("bob"), &pb.ApproveRequest{Id: "nope"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("approving unknown request = %v, want NotFound", err)
+	}
+	a, err = client.Approve(as("bob"), &pb.ApproveRequest{Id: id})
+	testutil.FatalOnErr("Approve", err, t)
+	if a.State != pb.State_STATE_APPROVED || a.Reviewer != "bob" {
+		t.Errorf("Approve() = %v", a)
+	}
+	_, err = client.Deny(as("carol"), &pb.DenyRequest{Id: id})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("denying approved request = %v, want FailedPrecondition", err)
+	}
+
+	// Approvals only cover the same call by the same requester.
+	for _, in := range []*rpcauth.RPCAuthInput{input("bob", msg, id), input("alice", `{"command":"/bin/ls"}`, id)} {
+		if err := srv.Check(ctx, in); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Check(%v) = %v, want PermissionDenied", in, err)
+		}
+	}
+	testutil.FatalOnErr("approved Check", srv.Check(ctx, input("alice", msg, id)), t)
+	if err := srv.Check(ctx, input("alice", msg, id)); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("reusing approval = %v, want PermissionDenied", err)
+	}
+
+	id = pendingID(t, srv.Check(ctx, input("alice", msg, "")))
+	_, err = client.Deny(as("bob"), &pb.DenyRequest{Id: id, Reason: "too risky"})
+	testutil.FatalOnErr("Deny", err, t)
+	err = srv.Check(ctx, input("alice", msg, id))
+	if status.Code(err) != codes.PermissionDenied || !strings.Contains(err.Error(), "too risky") {
+		t.Errorf("Check() of denied request = %v, want PermissionDenied with reason", err)
+	}
+	list, err = client.List(as("bob"), &pb.ListRequest{})
+	testutil.FatalOnErr("List", err, t)
+	if len(list.Approvals) != 0 {
+		t.Errorf("List() = %v, want no approvals", list)
+	}
+
+	// Pending requests lapse.
+	id = pendingID(t, srv.Check(ctx, input("alice", `{"command":"/bin/true"}`, "")))
+	now = now.Add(2 * time.Hour)
+	_, err = client.Approve(as("bob"), &pb.ApproveRequest{Id: id})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("approving expired request = %v, want NotFound", err)
+	}
+}
+
+func TestApprovalsRedactedFields(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+	in := func(stdin string, approvalID string) *rpcauth.RPCAuthInput {
+		t.Helper()
+		in, err := rpcauth.NewRPCAuthInput(ctx, "/Exec.Exec/Run", &execpb.ExecInput{Input: &execpb.ExecInput_Stdin{Stdin: []byte(stdin)}})
+		testutil.FatalOnErr("NewRPCAuthInput", err, t)
+		in.Peer = &rpcauth.PeerAuthInput{Principal: &rpcauth.PrincipalAuthInput{ID: "alice"}}
+		in.Metadata = metadata.MD{}
+		if approvalID != "" {
+			in.Metadata.Set(rpcauth.ApprovalIDKey, approvalID)
+		}
+		return in
+	}
+	if a, b := in("reviewed", ""), in("other", ""); string(a.Message) != string(b.Message) {
+		t.Fatalf("stdin isn't redacted: %s and %s", a.Message, b.Message)
+	}
+
+	id := pendingID(t, srv.Check(ctx, in("reviewed", "")))
+	srv.mu.Lock()
+	srv.entries[id].approval.State = pb.State_STATE_APPROVED
+	srv.mu.Unlock()
+	if err := srv.Check(ctx, in("other", id)); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Check() with different redacted stdin = %v, want PermissionDenied", err)
+	}
+	testutil.FatalOnErr("approved Check", srv.Check(ctx, in("reviewed", id)), t)
+}
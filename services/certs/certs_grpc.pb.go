@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package certs
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// CertsClient is the client API for Certs service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CertsClient interface {
+	// InspectFiles parses the certificates in PEM or DER encoded files.
+	InspectFiles(ctx context.Context, in *InspectFilesRequest, opts ...grpc.CallOption) (*InspectFilesReply, error)
+	// InspectPort returns the certificate chain presented by a TLS server
+	// listening on a local port.
+	InspectPort(ctx context.Context, in *InspectPortRequest, opts ...grpc.CallOption) (*InspectPortReply, error)
+}
+
+type certsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCertsClient(cc grpc.ClientConnInterface) CertsClient {
+	return &certsClient{cc}
+}
+
+func (c *certsClient) InspectFiles(ctx context.Context, in *InspectFilesRequest, opts ...grpc.CallOption) (*InspectFilesReply, error) {
+	out := new(InspectFilesReply)
+	err := c.cc.Invoke(ctx, "/Certs.Certs/InspectFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certsClient) InspectPort(ctx context.Context, in *InspectPortRequest, opts ...grpc.CallOption) (*InspectPortReply, error) {
+	out := new(InspectPortReply)
+	err := c.cc.Invoke(ctx, "/Certs.Certs/InspectPort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CertsServer is the server API for Certs service.
+// All implementations should embed UnimplementedCertsServer
+// for forward compatibility
+type CertsServer interface {
+	// InspectFiles parses the certificates in PEM or DER encoded files.
+	InspectFiles(context.Context, *InspectFilesRequest) (*InspectFilesReply, error)
+	// InspectPort returns the certificate chain presented by a TLS server
+	// listening on a local port.
+	InspectPort(context.Context, *InspectPortRequest) (*InspectPortReply, error)
+}
+
+// UnimplementedCertsServer should be embedded to have forward compatible implementations.
+type UnimplementedCertsServer struct {
+}
+
+func (UnimplementedCertsServer) InspectFiles(context.Context, *InspectFilesRequest) (*InspectFilesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InspectFiles not implemented")
+}
+func (UnimplementedCertsServer) InspectPort(context.Context, *InspectPortRequest) (*InspectPortReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InspectPort not implemented")
+}
+
+// UnsafeCertsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CertsServer will
+// result in compilation errors.
+type UnsafeCertsServer interface {
+	mustEmbedUnimplementedCertsServer()
+}
+
+func RegisterCertsServer(s grpc.ServiceRegistrar, srv CertsServer) {
+	s.RegisterService(&Certs_ServiceDesc, srv)
+}
+
+func _Certs_InspectFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertsServer).InspectFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Certs.Certs/InspectFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertsServer).InspectFiles(ctx, req.(*InspectFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Certs_InspectPort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectPortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertsServer).InspectPort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Certs.Certs/InspectPort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertsServer).InspectPort(ctx, req.(*InspectPortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Certs_ServiceDesc is the grpc.ServiceDesc for Certs service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Certs_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Certs.Certs",
+	HandlerType: (*CertsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "InspectFiles",
+			Handler:    _Certs_InspectFiles_Handler,
+		},
+		{
+			MethodName: "InspectPort",
+			Handler:    _Certs_InspectPort_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "certs.proto",
+}
@@ -0,0 +1,168 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package certs
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// CertsClientProxy is the superset of CertsClient which additionally includes the OneMany proxy methods
+type CertsClientProxy interface {
+	CertsClient
+	InspectFilesOneMany(ctx context.Context, in *InspectFilesRequest, opts ...grpc.CallOption) (<-chan *InspectFilesManyResponse, error)
+	InspectPortOneMany(ctx context.Context, in *InspectPortRequest, opts ...grpc.CallOption) (<-chan *InspectPortManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type certsClientProxy struct {
+	*certsClient
+}
+
+// NewCertsClientProxy creates a CertsClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewCertsClientProxy(cc *proxy.Conn) CertsClientProxy {
+	return &certsClientProxy{NewCertsClient(cc).(*certsClient)}
+}
+
+// InspectFilesManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type InspectFilesManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *InspectFilesReply
+	Error error
+}
+
+// InspectFilesOneMany provides the same API as InspectFiles but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *certsClientProxy) InspectFilesOneMany(ctx context.Context, in *InspectFilesRequest, opts ...grpc.CallOption) (<-chan *InspectFilesManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *InspectFilesManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &InspectFilesManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &InspectFilesReply{},
+			}
+			err := conn.Invoke(ctx, "/Certs.Certs/InspectFiles", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Certs.Certs/InspectFiles", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &InspectFilesManyResponse{
+				Resp: &InspectFilesReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// InspectPortManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type InspectPortManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *InspectPortReply
+	Error error
+}
+
+// InspectPortOneMany provides the same API as InspectPort but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *certsClientProxy) InspectPortOneMany(ctx context.Context, in *InspectPortRequest, opts ...grpc.CallOption) (<-chan *InspectPortManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *InspectPortManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &InspectPortManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &InspectPortReply{},
+			}
+			err := conn.Invoke(ctx, "/Certs.Certs/InspectPort", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Certs.Certs/InspectPort", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &InspectPortManyResponse{
+				Resp: &InspectPortReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
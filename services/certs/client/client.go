@@ -0,0 +1,189 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'certs'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/certs"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "certs"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&filesCmd{}, "")
+	c.Register(&portCmd{}, "")
+	return c
+}
+
+// expiring reports whether c expires within d of now. A zero d matches
+// every certificate.
+func expiring(c *pb.Certificate, d time.Duration) bool {
+	return d == 0 || c.NotAfter.AsTime().Before(time.Now().Add(d))
+}
+
+// formatCert returns a one line summary of c, prefixed with name.
+func formatCert(name string, c *pb.Certificate) string {
+	notAfter := c.NotAfter.AsTime()
+	left := time.Until(notAfter)
+	expiry := fmt.Sprintf("expires in %dd", int(left.Hours()/24))
+	if left < 0 {
+		expiry = fmt.Sprintf("EXPIRED %dd ago", int(-left.Hours()/24))
+	}
+	sans := append(append(append(append([]string{}, c.DnsNames...), c.IpAddresses...), c.EmailAddresses...), c.Uris...)
+	out := fmt.Sprintf("%s: subject=%q issuer=%q serial=%s not_after=%s (%s)", name, c.Subject, c.Issuer, c.SerialNumber, notAfter.Format(time.RFC3339), expiry)
+	if len(sans) > 0 {
+		out += " sans=" + strings.Join(sans, ",")
+	}
+	if c.IsCa {
+		out += " ca"
+	}
+	return out
+}
+
+type filesCmd struct {
+	expiringWithin time.Duration
+}
+
+func (*filesCmd) Name() string     { return "files" }
+func (*filesCmd) Synopsis() string { return "Inspect certificates in files" }
+func (*filesCmd) Usage() string {
+	return `files [--expiring-within=duration] <path> [<path>...]:
+    Print the subject, issuer, expiry and SANs of each certificate in the
+    PEM or DER encoded files. Paths must be absolute and may contain shell
+    style wildcards (quote them), i.e. '/etc/pki/tls/certs/*.pem'.
+`
+}
+
+func (p *filesCmd) SetFlags(f *flag.FlagSet) {
+	f.DurationVar(&p.expiringWithin, "expiring-within", 0, "If set only certificates expiring within this duration (i.e. 720h) are printed")
+}
+
+func (p *filesCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() == 0 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify at least one path.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, p)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewCertsClientProxy(state.Conn)
+	respChan, err := c.InspectFilesOneMany(ctx, &pb.InspectFilesRequest{Paths: f.Args()})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'files': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, file := range resp.Resp.Files {
+			if file.Error != "" {
+				fmt.Fprintf(state.Err[resp.Index], "%s: %s\n", file.Path, file.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			for i, cert := range file.Certificates {
+				if expiring(cert, p.expiringWithin) {
+					fmt.Fprintln(state.Out[resp.Index], formatCert(fmt.Sprintf("%s[%d]", file.Path, i), cert))
+				}
+			}
+		}
+	}
+	return retCode
+}
+
+type portCmd struct {
+	serverName     string
+	expiringWithin time.Duration
+}
+
+func (*portCmd) Name() string     { return "port" }
+func (*portCmd) Synopsis() string { return "Inspect the certificates presented by a local TLS port" }
+func (*portCmd) Usage() string {
+	return `port [--server-name=name] [--expiring-within=duration] <port>:
+    Connect to the port on the remote host's localhost and print the
+    subject, issuer, expiry and SANs of each certificate in the chain it
+    presents. The chain isn't verified.
+`
+}
+
+func (p *portCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.serverName, "server-name", "", "If set sent as the TLS server name (SNI)")
+	f.DurationVar(&p.expiringWithin, "expiring-within", 0, "If set only certificates expiring within this duration (i.e. 720h) are printed")
+}
+
+func (p *portCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify a port.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, p)
+		return subcommands.ExitUsageError
+	}
+	port, err := strconv.ParseInt(f.Arg(0), 10, 32)
+	if err != nil {
+		fmt.Fprintf(subcommands.DefaultCommander.Error, "invalid port %s: %v\n", f.Arg(0), err)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewCertsClientProxy(state.Conn)
+	respChan, err := c.InspectPortOneMany(ctx, &pb.InspectPortRequest{Port: int32(port), ServerName: p.serverName})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'port': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for i, cert := range resp.Resp.Chain {
+			if expiring(cert, p.expiringWithin) {
+				fmt.Fprintln(state.Out[resp.Index], formatCert(fmt.Sprintf("port %d[%d]", port, i), cert))
+			}
+		}
+	}
+	return retCode
+}
@@ -0,0 +1,237 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'Certs' service.
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/certs"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+var (
+	// maxFileSize is the largest file InspectFiles will parse. CA bundles
+	// are typically a few hundred KB.
+	maxFileSize int64 = 4 * 1024 * 1024
+
+	// dialTimeout bounds the connection and handshake of InspectPort.
+	dialTimeout = 10 * time.Second
+)
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// certificate converts c into its proto form.
+func certificate(c *x509.Certificate) *pb.Certificate {
+	fingerprint := sha256.Sum256(c.Raw)
+	out := &pb.Certificate{
+		Subject:            c.Subject.String(),
+		Issuer:             c.Issuer.String(),
+		SerialNumber:       c.SerialNumber.Text(16),
+		NotBefore:          timestamppb.New(c.NotBefore),
+		NotAfter:           timestamppb.New(c.NotAfter),
+		DnsNames:           c.DNSNames,
+		EmailAddresses:     c.EmailAddresses,
+		IsCa:               c.IsCA,
+		SignatureAlgorithm: c.SignatureAlgorithm.String(),
+		PublicKeyAlgorithm: c.PublicKeyAlgorithm.String(),
+		Sha256Fingerprint:  hex.EncodeToString(fingerprint[:]),
+	}
+	for _, ip := range c.IPAddresses {
+		out.IpAddresses = append(out.IpAddresses, ip.String())
+	}
+	for _, u := range c.URIs {
+		out.Uris = append(out.Uris, u.String())
+	}
+	return out
+}
+
+// parseCertificates returns the certificates in the PEM blocks of data,
+// or if it has none parses it as a single DER encoded certificate.
+func parseCertificates(data []byte) ([]*pb.Certificate, error) {
+	var out []*pb.Certificate
+	rest := data
+	sawPEM := false
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		sawPEM = true
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse certificate %d: %v", len(out), err)
+		}
+		out = append(out, certificate(c))
+	}
+	if sawPEM {
+		if len(out) == 0 {
+			return nil, errors.New("no certificates found")
+		}
+		return out, nil
+	}
+	c, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, errors.New("no PEM certificates found and can't parse as DER")
+	}
+	return []*pb.Certificate{certificate(c)}, nil
+}
+
+// inspectFile returns the certificates in path, recording any error in the
+// result rather than returning it.
+func inspectFile(path string) *pb.CertificateFile {
+	out := &pb.CertificateFile{Path: path}
+	data, err := readFile(path)
+	if err == nil {
+		out.Certificates, err = parseCertificates(data)
+	}
+	if err != nil {
+		out.Error = err.Error()
+	}
+	return out
+}
+
+func readFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, errors.New("not a regular file")
+	}
+	if fi.Size() > maxFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxFileSize)
+	}
+	return io.ReadAll(io.LimitReader(f, maxFileSize))
+}
+
+// InspectFiles implements pb.CertsServer.InspectFiles
+func (s *server) InspectFiles(ctx context.Context, req *pb.InspectFilesRequest) (*pb.InspectFilesReply, error) {
+	if len(req.Paths) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one path must be specified")
+	}
+	var paths []string
+	for _, p := range req.Paths {
+		if err := util.ValidPath(p); err != nil {
+			return nil, err
+		}
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid pattern %s: %v", p, err)
+		}
+		// A path without wildcards which doesn't exist is reported rather
+		// than ignored.
+		if len(matches) == 0 && !hasMeta(p) {
+			matches = []string{p}
+		}
+		paths = append(paths, matches...)
+	}
+	reply := &pb.InspectFilesReply{}
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		reply.Files = append(reply.Files, inspectFile(p))
+	}
+	return reply, nil
+}
+
+// hasMeta reports whether path contains any of the characters recognized
+// by filepath.Match.
+func hasMeta(path string) bool {
+	for _, c := range path {
+		switch c {
+		case '*', '?', '[', '\\':
+			return true
+		}
+	}
+	return false
+}
+
+// InspectPort implements pb.CertsServer.InspectPort
+func (s *server) InspectPort(ctx context.Context, req *pb.InspectPortRequest) (*pb.InspectPortReply, error) {
+	if req.Port <= 0 || req.Port > 65535 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid port %d", req.Port)
+	}
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+	addr := net.JoinHostPort("localhost", strconv.Itoa(int(req.Port)))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "can't connect to %s: %v", addr, err)
+	}
+	defer conn.Close()
+	// The chain is returned for inspection rather than trusted, so it
+	// isn't verified. That also allows inspecting expired or self-signed
+	// certificates, which is the point.
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         req.ServerName,
+		InsecureSkipVerify: true,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "TLS handshake with %s failed: %v", addr, err)
+	}
+	reply := &pb.InspectPortReply{}
+	for _, c := range tlsConn.ConnectionState().PeerCertificates {
+		reply.Chain = append(reply.Chain, certificate(c))
+	}
+	return reply, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterCertsServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
@@ -0,0 +1,302 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/certs"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+var (
+	notBefore = time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	notAfter  = time.Date(2032, 3, 1, 0, 0, 0, 0, time.UTC)
+)
+
+type testCert struct {
+	der  []byte
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+}
+
+// newCert returns a certificate from template, signed by parent or self
+// signed if parent is nil.
+func newCert(t *testing.T, template *x509.Certificate, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testutil.FatalOnErr("generating key", err, t)
+	template.NotBefore, template.NotAfter = notBefore, notAfter
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	testutil.FatalOnErr("creating certificate", err, t)
+	cert, err := x509.ParseCertificate(der)
+	testutil.FatalOnErr("parsing certificate", err, t)
+	return &testCert{der: der, key: key, cert: cert}
+}
+
+func (c *testCert) pem() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der})
+}
+
+func (c *testCert) fingerprint() string {
+	f := sha256.Sum256(c.der)
+	return hex.EncodeToString(f[:])
+}
+
+func testCerts(t *testing.T) (ca *testCert, leaf *testCert, caProto *pb.Certificate, leafProto *pb.Certificate) {
+	ca = newCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA", Organization: []string{"Example"}},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	spiffe, err := url.Parse("spiffe://example.com/server")
+	testutil.FatalOnErr("parsing uri", err, t)
+	leaf = newCert(t, &x509.Certificate{
+		SerialNumber:   big.NewInt(0xabcdef),
+		Subject:        pkix.Name{CommonName: "server.example.com"},
+		DNSNames:       []string{"server.example.com", "localhost"},
+		IPAddresses:    []net.IP{net.ParseIP("127.0.0.1")},
+		EmailAddresses: []string{"admin@example.com"},
+		URIs:           []*url.URL{spiffe},
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	caProto = &pb.Certificate{
+		Subject:            "CN=Test CA,O=Example",
+		Issuer:             "CN=Test CA,O=Example",
+		SerialNumber:       "1",
+		NotBefore:          timestamppb.New(notBefore),
+		NotAfter:           timestamppb.New(notAfter),
+		IsCa:               true,
+		SignatureAlgorithm: "ECDSA-SHA256",
+		PublicKeyAlgorithm: "ECDSA",
+		Sha256Fingerprint:  ca.fingerprint(),
+	}
+	leafProto = &pb.Certificate{
+		Subject:            "CN=server.example.com",
+		Issuer:             "CN=Test CA,O=Example",
+		SerialNumber:       "abcdef",
+		NotBefore:          timestamppb.New(notBefore),
+		NotAfter:           timestamppb.New(notAfter),
+		DnsNames:           []string{"server.example.com", "localhost"},
+		IpAddresses:        []string{"127.0.0.1"},
+		EmailAddresses:     []string{"admin@example.com"},
+		Uris:               []string{"spiffe://example.com/server"},
+		SignatureAlgorithm: "ECDSA-SHA256",
+		PublicKeyAlgorithm: "ECDSA",
+		Sha256Fingerprint:  leaf.fingerprint(),
+	}
+	return ca, leaf, caProto, leafProto
+}
+
+func TestInspectFiles(t *testing.T) {
+	ca, leaf, caProto, leafProto := testCerts(t)
+	keyDER, err := x509.MarshalECPrivateKey(leaf.key)
+	testutil.FatalOnErr("marshaling key", err, t)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	dir := t.TempDir()
+	for name, contents := range map[string][]byte{
+		"chain.pem":  append(append(leaf.pem(), keyPEM...), ca.pem()...),
+		"ca.der":     ca.der,
+		"server.key": keyPEM,
+		"junk.pem":   []byte("not a certificate"),
+	} {
+		testutil.FatalOnErr("writing "+name, os.WriteFile(filepath.Join(dir, name), contents, 0644), t)
+	}
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	for _, tc := range []struct {
+		name    string
+		paths   []string
+		want    *pb.InspectFilesReply
+		wantErr codes.Code
+	}{
+		{
+			name:  "chain skipping key",
+			paths: []string{path("chain.pem")},
+			want: &pb.InspectFilesReply{Files: []*pb.CertificateFile{
+				{Path: path("chain.pem"), Certificates: []*pb.Certificate{leafProto, caProto}},
+			}},
+		},
+		{
+			name:  "der",
+			paths: []string{path("ca.der")},
+			want: &pb.InspectFilesReply{Files: []*pb.CertificateFile{
+				{Path: path("ca.der"), Certificates: []*pb.Certificate{caProto}},
+			}},
+		},
+		{
+			name:  "errors",
+			paths: []string{path("server.key"), path("junk.pem"), path("missing.pem"), dir},
+			want: &pb.InspectFilesReply{Files: []*pb.CertificateFile{
+				{Path: path("server.key"), Error: "no certificates found"},
+				{Path: path("junk.pem"), Error: "no PEM certificates found and can't parse as DER"},
+				{Path: path("missing.pem"), Error: "open " + path("missing.pem") + ": no such file or directory"},
+				{Path: dir, Error: "not a regular file"},
+			}},
+		},
+		{
+			name:  "glob",
+			paths: []string{path("*.pem"), path("chain.pem"), path("*.crt")},
+			want: &pb.InspectFilesReply{Files: []*pb.CertificateFile{
+				{Path: path("chain.pem"), Certificates: []*pb.Certificate{leafProto, caProto}},
+				{Path: path("junk.pem"), Error: "no PEM certificates found and can't parse as DER"},
+			}},
+		},
+		{
+			name:    "relative path",
+			paths:   []string{"chain.pem"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad pattern",
+			paths:   []string{path("[")},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "no paths",
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{}
+			got, err := s.InspectFiles(context.Background(), &pb.InspectFilesRequest{Paths: tc.paths})
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("InspectFiles: got error %v, want code %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
+
+func TestInspectPort(t *testing.T) {
+	ca, leaf, caProto, leafProto := testCerts(t)
+	other := newCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "other.example.com"},
+		DNSNames:     []string{"other.example.com"},
+	}, nil)
+
+	l, err := tls.Listen("tcp", "localhost:0", &tls.Config{
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if hello.ServerName == "other.example.com" {
+				return &tls.Certificate{Certificate: [][]byte{other.der}, PrivateKey: other.key}, nil
+			}
+			return &tls.Certificate{Certificate: [][]byte{leaf.der, ca.der}, PrivateKey: leaf.key}, nil
+		},
+	})
+	testutil.FatalOnErr("listen", err, t)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	port := int32(l.Addr().(*net.TCPAddr).Port)
+
+	// A port with nothing listening.
+	closed, err := net.Listen("tcp", "localhost:0")
+	testutil.FatalOnErr("listen", err, t)
+	closedPort := int32(closed.Addr().(*net.TCPAddr).Port)
+	closed.Close()
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.InspectPortRequest
+		want    *pb.InspectPortReply
+		wantErr codes.Code
+	}{
+		{
+			name: "chain",
+			req:  &pb.InspectPortRequest{Port: port},
+			want: &pb.InspectPortReply{Chain: []*pb.Certificate{leafProto, caProto}},
+		},
+		{
+			name: "server name",
+			req:  &pb.InspectPortRequest{Port: port, ServerName: "other.example.com"},
+			want: &pb.InspectPortReply{Chain: []*pb.Certificate{{
+				Subject:            "CN=other.example.com",
+				Issuer:             "CN=other.example.com",
+				SerialNumber:       "2",
+				NotBefore:          timestamppb.New(notBefore),
+				NotAfter:           timestamppb.New(notAfter),
+				DnsNames:           []string{"other.example.com"},
+				SignatureAlgorithm: "ECDSA-SHA256",
+				PublicKeyAlgorithm: "ECDSA",
+				Sha256Fingerprint:  other.fingerprint(),
+			}}},
+		},
+		{
+			name:    "nothing listening",
+			req:     &pb.InspectPortRequest{Port: closedPort},
+			wantErr: codes.Unavailable,
+		},
+		{
+			name:    "invalid port",
+			req:     &pb.InspectPortRequest{Port: 65536},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{}
+			got, err := s.InspectPort(context.Background(), tc.req)
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("InspectPort: got error %v, want code %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
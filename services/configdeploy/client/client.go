@@ -0,0 +1,320 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'configdeploy'
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/configdeploy"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "configdeploy"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&activateCmd{}, "")
+	c.Register(&listCmd{}, "")
+	c.Register(&rollbackCmd{}, "")
+	c.Register(&stageCmd{}, "")
+	c.Register(&validateCmd{}, "")
+	return c
+}
+
+type stageCmd struct {
+	id   string
+	uid  int
+	gid  int
+	mode int
+}
+
+func (*stageCmd) Name() string     { return "stage" }
+func (*stageCmd) Synopsis() string { return "Stage files for a new deployment" }
+func (*stageCmd) Usage() string {
+	return `stage --uid=X --gid=X --mode=X [--id=X] <local file>:<remote path> ...:
+    Stage local files to be deployed to the given remote paths. Nothing is
+    changed outside of the staging area until the deployment is validated and
+    activated. The deployment ID is printed. If --id isn't given one is
+    generated, the same for all targets.
+`
+}
+
+func (s *stageCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&s.id, "id", "", "ID for the deployment. Generated if empty.")
+	f.IntVar(&s.uid, "uid", -1, "The uid the deployed files will be owned by.")
+	f.IntVar(&s.gid, "gid", -1, "The gid the deployed files will be owned by.")
+	f.IntVar(&s.mode, "mode", -1, "The mode the deployed files will have, i.e. 0644.")
+}
+
+func (s *stageCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Please specify at least one <local file>:<remote path> to stage.")
+		return subcommands.ExitUsageError
+	}
+	if s.uid < 0 || s.gid < 0 || s.mode < 0 {
+		fmt.Fprintln(os.Stderr, "Must set --uid, --gid and --mode")
+		return subcommands.ExitUsageError
+	}
+	req := &pb.StageRequest{Id: s.id}
+	if req.Id == "" {
+		b := make([]byte, 4)
+		if _, err := rand.Read(b); err != nil {
+			fmt.Fprintf(os.Stderr, "can't generate deployment ID: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		req.Id = time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+	}
+	for _, arg := range f.Args() {
+		parts := strings.SplitN(arg, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			fmt.Fprintf(os.Stderr, "%s must be of the form <local file>:<remote path>\n", arg)
+			return subcommands.ExitUsageError
+		}
+		contents, err := os.ReadFile(parts[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can't read %s: %v\n", parts[0], err)
+			return subcommands.ExitFailure
+		}
+		req.Files = append(req.Files, &pb.StagedFile{
+			Path:     parts[1],
+			Contents: contents,
+			Uid:      uint32(s.uid),
+			Gid:      uint32(s.gid),
+			Mode:     uint32(s.mode),
+		})
+	}
+
+	c := pb.NewConfigDeployClientProxy(state.Conn)
+	respChan, err := c.StageOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "Stage for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintln(state.Out[r.Index], r.Resp.Id)
+	}
+	return retCode
+}
+
+type validateCmd struct{}
+
+func (*validateCmd) Name() string     { return "validate" }
+func (*validateCmd) Synopsis() string { return "Run the configured checks on a staged deployment" }
+func (*validateCmd) Usage() string {
+	return `validate <id>:
+    Run the checks configured on each target against the staged files of the
+    deployment, printing the output of any failing ones. Exits non-zero if
+    any check fails.
+`
+}
+
+func (*validateCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*validateCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Please specify a deployment ID.")
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewConfigDeployClientProxy(state.Conn)
+	respChan, err := c.ValidateOneMany(ctx, &pb.DeploymentRequest{Id: f.Arg(0)})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "Validate for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, c := range r.Resp.Results {
+			if c.ExitCode == 0 {
+				continue
+			}
+			fmt.Fprintf(state.Out[r.Index], "%s: check %q failed with exit code %d:\n%s\n", c.Path, strings.Join(c.Command, " "), c.ExitCode, c.Output)
+		}
+		if !r.Resp.Passed {
+			fmt.Fprintln(state.Out[r.Index], "FAILED")
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[r.Index], "PASSED (%d checks)\n", len(r.Resp.Results))
+	}
+	return retCode
+}
+
+type activateCmd struct{}
+
+func (*activateCmd) Name() string     { return "activate" }
+func (*activateCmd) Synopsis() string { return "Move the files of a validated deployment into place" }
+func (*activateCmd) Usage() string {
+	return `activate <id>:
+    Atomically replace each file with the staged version, keeping a backup to
+    roll back to. If any file can't be replaced those already done are
+    restored. The deployment must have been validated.
+`
+}
+
+func (*activateCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*activateCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Please specify a deployment ID.")
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewConfigDeployClientProxy(state.Conn)
+	respChan, err := c.ActivateOneMany(ctx, &pb.DeploymentRequest{Id: f.Arg(0)})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if !printDeployment(state, "Activate", r.Target, r.Index, r.Resp, r.Error) {
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
+
+type rollbackCmd struct{}
+
+func (*rollbackCmd) Name() string     { return "rollback" }
+func (*rollbackCmd) Synopsis() string { return "Restore the files replaced by an active deployment" }
+func (*rollbackCmd) Usage() string {
+	return `rollback <id>:
+    Restore the files an active deployment replaced, removing those which
+    didn't exist before. Fails if any of the files were changed since the
+    deployment was activated, i.e. by a later deployment.
+`
+}
+
+func (*rollbackCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*rollbackCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Please specify a deployment ID.")
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewConfigDeployClientProxy(state.Conn)
+	respChan, err := c.RollbackOneMany(ctx, &pb.DeploymentRequest{Id: f.Arg(0)})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if !printDeployment(state, "Rollback", r.Target, r.Index, r.Resp, r.Error) {
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
+
+// printDeployment prints a deployment or error returned by a target for
+// the named RPC, returning false on error.
+func printDeployment(state *util.ExecuteState, rpc string, target string, index int, d *pb.Deployment, err error) bool {
+	if err != nil {
+		fmt.Fprintf(state.Err[index], "%s for target %s (%d) returned error: %v\n", rpc, target, index, err)
+		return false
+	}
+	st := strings.TrimPrefix(d.State.String(), "DEPLOYMENT_STATE_")
+	fmt.Fprintf(state.Out[index], "%s %s created %s updated %s\n", d.Id, st, d.Created.AsTime().Local().Format(time.RFC3339), d.Updated.AsTime().Local().Format(time.RFC3339))
+	for _, file := range d.Files {
+		fmt.Fprintf(state.Out[index], "  %s %s uid=%d gid=%d mode=%#o\n", file.Path, file.Sha256, file.Uid, file.Gid, file.Mode)
+	}
+	return true
+}
+
+type listCmd struct{}
+
+func (*listCmd) Name() string     { return "list" }
+func (*listCmd) Synopsis() string { return "List deployments" }
+func (*listCmd) Usage() string {
+	return `list:
+    List the deployments on each target, oldest first, with their state and
+    files.
+`
+}
+
+func (*listCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*listCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewConfigDeployClientProxy(state.Conn)
+	respChan, err := c.ListOneMany(ctx, &pb.ListRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "List for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, d := range r.Resp.Deployments {
+			printDeployment(state, "List", r.Target, r.Index, d, nil)
+		}
+	}
+	return retCode
+}
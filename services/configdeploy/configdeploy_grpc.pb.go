@@ -0,0 +1,261 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package configdeploy
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ConfigDeployClient is the client API for ConfigDeploy service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConfigDeployClient interface {
+	// Stage writes files to a staging area for a new deployment. Nothing
+	// outside the staging area is changed.
+	Stage(ctx context.Context, in *StageRequest, opts ...grpc.CallOption) (*StageReply, error)
+	// Validate runs the configured checks against the staged files.
+	Validate(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (*ValidateReply, error)
+	// Activate moves the staged files of a validated deployment into place,
+	// keeping backups of the files they replace. If any file can't be moved
+	// the ones already moved are restored.
+	Activate(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (*Deployment, error)
+	// Rollback restores the files replaced by an active deployment (or removes
+	// them if they didn't exist).
+	Rollback(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (*Deployment, error)
+	// List returns known deployments, oldest first.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListReply, error)
+}
+
+type configDeployClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConfigDeployClient(cc grpc.ClientConnInterface) ConfigDeployClient {
+	return &configDeployClient{cc}
+}
+
+func (c *configDeployClient) Stage(ctx context.Context, in *StageRequest, opts ...grpc.CallOption) (*StageReply, error) {
+	out := new(StageReply)
+	err := c.cc.Invoke(ctx, "/ConfigDeploy.ConfigDeploy/Stage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configDeployClient) Validate(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (*ValidateReply, error) {
+	out := new(ValidateReply)
+	err := c.cc.Invoke(ctx, "/ConfigDeploy.ConfigDeploy/Validate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configDeployClient) Activate(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (*Deployment, error) {
+	out := new(Deployment)
+	err := c.cc.Invoke(ctx, "/ConfigDeploy.ConfigDeploy/Activate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configDeployClient) Rollback(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (*Deployment, error) {
+	out := new(Deployment)
+	err := c.cc.Invoke(ctx, "/ConfigDeploy.ConfigDeploy/Rollback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *configDeployClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListReply, error) {
+	out := new(ListReply)
+	err := c.cc.Invoke(ctx, "/ConfigDeploy.ConfigDeploy/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConfigDeployServer is the server API for ConfigDeploy service.
+// All implementations should embed UnimplementedConfigDeployServer
+// for forward compatibility
+type ConfigDeployServer interface {
+	// Stage writes files to a staging area for a new deployment. Nothing
+	// outside the staging area is changed.
+	Stage(context.Context, *StageRequest) (*StageReply, error)
+	// Validate runs the configured checks against the staged files.
+	Validate(context.Context, *DeploymentRequest) (*ValidateReply, error)
+	// Activate moves the staged files of a validated deployment into place,
+	// keeping backups of the files they replace. If any file can't be moved
+	// the ones already moved are restored.
+	Activate(context.Context, *DeploymentRequest) (*Deployment, error)
+	// Rollback restores the files replaced by an active deployment (or removes
+	// them if they didn't exist).
+	Rollback(context.Context, *DeploymentRequest) (*Deployment, error)
+	// List returns known deployments, oldest first.
+	List(context.Context, *ListRequest) (*ListReply, error)
+}
+
+// UnimplementedConfigDeployServer should be embedded to have forward compatible implementations.
+type UnimplementedConfigDeployServer struct {
+}
+
+func (UnimplementedConfigDeployServer) Stage(context.Context, *StageRequest) (*StageReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stage not implemented")
+}
+func (UnimplementedConfigDeployServer) Validate(context.Context, *DeploymentRequest) (*ValidateReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedConfigDeployServer) Activate(context.Context, *DeploymentRequest) (*Deployment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Activate not implemented")
+}
+func (UnimplementedConfigDeployServer) Rollback(context.Context, *DeploymentRequest) (*Deployment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rollback not implemented")
+}
+func (UnimplementedConfigDeployServer) List(context.Context, *ListRequest) (*ListReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+
+// UnsafeConfigDeployServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConfigDeployServer will
+// result in compilation errors.
+type UnsafeConfigDeployServer interface {
+	mustEmbedUnimplementedConfigDeployServer()
+}
+
+func RegisterConfigDeployServer(s grpc.ServiceRegistrar, srv ConfigDeployServer) {
+	s.RegisterService(&ConfigDeploy_ServiceDesc, srv)
+}
+
+func _ConfigDeploy_Stage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigDeployServer).Stage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigDeploy.ConfigDeploy/Stage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigDeployServer).Stage(ctx, req.(*StageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigDeploy_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigDeployServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigDeploy.ConfigDeploy/Validate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigDeployServer).Validate(ctx, req.(*DeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigDeploy_Activate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigDeployServer).Activate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigDeploy.ConfigDeploy/Activate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigDeployServer).Activate(ctx, req.(*DeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigDeploy_Rollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigDeployServer).Rollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigDeploy.ConfigDeploy/Rollback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigDeployServer).Rollback(ctx, req.(*DeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConfigDeploy_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigDeployServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ConfigDeploy.ConfigDeploy/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigDeployServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConfigDeploy_ServiceDesc is the grpc.ServiceDesc for ConfigDeploy service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConfigDeploy_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ConfigDeploy.ConfigDeploy",
+	HandlerType: (*ConfigDeployServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Stage",
+			Handler:    _ConfigDeploy_Stage_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _ConfigDeploy_Validate_Handler,
+		},
+		{
+			MethodName: "Activate",
+			Handler:    _ConfigDeploy_Activate_Handler,
+		},
+		{
+			MethodName: "Rollback",
+			Handler:    _ConfigDeploy_Rollback_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _ConfigDeploy_List_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "configdeploy.proto",
+}
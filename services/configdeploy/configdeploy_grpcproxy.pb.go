@@ -0,0 +1,372 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package configdeploy
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// ConfigDeployClientProxy is the superset of ConfigDeployClient which additionally includes the OneMany proxy methods
+type ConfigDeployClientProxy interface {
+	ConfigDeployClient
+	StageOneMany(ctx context.Context, in *StageRequest, opts ...grpc.CallOption) (<-chan *StageManyResponse, error)
+	ValidateOneMany(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (<-chan *ValidateManyResponse, error)
+	ActivateOneMany(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (<-chan *ActivateManyResponse, error)
+	RollbackOneMany(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (<-chan *RollbackManyResponse, error)
+	ListOneMany(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (<-chan *ListManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type configDeployClientProxy struct {
+	*configDeployClient
+}
+
+// NewConfigDeployClientProxy creates a ConfigDeployClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewConfigDeployClientProxy(cc *proxy.Conn) ConfigDeployClientProxy {
+	return &configDeployClientProxy{NewConfigDeployClient(cc).(*configDeployClient)}
+}
+
+// StageManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type StageManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *StageReply
+	Error error
+}
+
+// StageOneMany provides the same API as Stage but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *configDeployClientProxy) StageOneMany(ctx context.Context, in *StageRequest, opts ...grpc.CallOption) (<-chan *StageManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *StageManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &StageManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &StageReply{},
+			}
+			err := conn.Invoke(ctx, "/ConfigDeploy.ConfigDeploy/Stage", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/ConfigDeploy.ConfigDeploy/Stage", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &StageManyResponse{
+				Resp: &StageReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ValidateManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ValidateManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ValidateReply
+	Error error
+}
+
+// ValidateOneMany provides the same API as Validate but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *configDeployClientProxy) ValidateOneMany(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (<-chan *ValidateManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ValidateManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ValidateManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ValidateReply{},
+			}
+			err := conn.Invoke(ctx, "/ConfigDeploy.ConfigDeploy/Validate", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/ConfigDeploy.ConfigDeploy/Validate", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ValidateManyResponse{
+				Resp: &ValidateReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ActivateManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ActivateManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *Deployment
+	Error error
+}
+
+// ActivateOneMany provides the same API as Activate but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *configDeployClientProxy) ActivateOneMany(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (<-chan *ActivateManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ActivateManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ActivateManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &Deployment{},
+			}
+			err := conn.Invoke(ctx, "/ConfigDeploy.ConfigDeploy/Activate", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/ConfigDeploy.ConfigDeploy/Activate", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ActivateManyResponse{
+				Resp: &Deployment{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// RollbackManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type RollbackManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *Deployment
+	Error error
+}
+
+// RollbackOneMany provides the same API as Rollback but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *configDeployClientProxy) RollbackOneMany(ctx context.Context, in *DeploymentRequest, opts ...grpc.CallOption) (<-chan *RollbackManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *RollbackManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &RollbackManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &Deployment{},
+			}
+			err := conn.Invoke(ctx, "/ConfigDeploy.ConfigDeploy/Rollback", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/ConfigDeploy.ConfigDeploy/Rollback", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &RollbackManyResponse{
+				Resp: &Deployment{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ListManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ListManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ListReply
+	Error error
+}
+
+// ListOneMany provides the same API as List but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *configDeployClientProxy) ListOneMany(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (<-chan *ListManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ListManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ListManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ListReply{},
+			}
+			err := conn.Invoke(ctx, "/ConfigDeploy.ConfigDeploy/List", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/ConfigDeploy.ConfigDeploy/List", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ListManyResponse{
+				Resp: &ListReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
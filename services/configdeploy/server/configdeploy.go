@@ -0,0 +1,539 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'ConfigDeploy' service.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/configdeploy"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+var (
+	deployDir  = flag.String("config-deploy-dir", "/var/lib/sansshell/deployments", "Directory where ConfigDeploy keeps staged files, backups and deployment state")
+	checksFile = flag.String("config-deploy-checks", "", "JSON file listing the checks ConfigDeploy runs to validate staged files, i.e. [{\"pattern\": \"/etc/nginx/*.conf\", \"command\": [\"/usr/sbin/nginx\", \"-t\", \"-c\", \"{}\"]}]. {} is replaced by the staged file. Files matching no pattern aren't checked.")
+
+	validID = regexp.MustCompile(`^[0-9A-Za-z_-]{1,64}$`)
+
+	// mu serializes all operations so deployments can't race with each
+	// other on the same files.
+	mu sync.Mutex
+)
+
+const (
+	stateFile = "deployment.json"
+	stagedDir = "staged"
+	backupDir = "backup"
+
+	// The placeholder in check commands replaced by the staged file.
+	filePlaceholder = "{}"
+)
+
+// check is a command validating files whose destination matches pattern.
+type check struct {
+	Pattern string   `json:"pattern"`
+	Command []string `json:"command"`
+}
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// loadChecks reads the configured checks.
+func loadChecks() ([]check, error) {
+	if *checksFile == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(*checksFile)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read checks: %v", err)
+	}
+	var checks []check
+	if err := json.Unmarshal(b, &checks); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't parse checks file %s: %v", *checksFile, err)
+	}
+	for _, c := range checks {
+		if _, err := filepath.Match(c.Pattern, "/"); err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid check pattern %q: %v", c.Pattern, err)
+		}
+		if len(c.Command) == 0 {
+			return nil, status.Errorf(codes.Internal, "check for %q has no command", c.Pattern)
+		}
+	}
+	return checks, nil
+}
+
+// newID returns a deployment ID based on the current time.
+func newID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b), nil
+}
+
+// deploymentDir returns the directory holding deployment id.
+func deploymentDir(id string) (string, error) {
+	if !validID.MatchString(id) {
+		return "", status.Errorf(codes.InvalidArgument, "invalid deployment ID %q", id)
+	}
+	return filepath.Join(*deployDir, id), nil
+}
+
+// fileName returns the name of the staged or backup copy of the i'th file.
+func fileName(dir string, sub string, i int) string {
+	return filepath.Join(dir, sub, strconv.Itoa(i))
+}
+
+// load reads the state of deployment id.
+func load(id string) (*pb.Deployment, string, error) {
+	dir, err := deploymentDir(id)
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", status.Errorf(codes.NotFound, "no deployment %s", id)
+	}
+	if err != nil {
+		return nil, "", status.Errorf(codes.Internal, "can't read deployment %s: %v", id, err)
+	}
+	d := &pb.Deployment{}
+	if err := protojson.Unmarshal(b, d); err != nil {
+		return nil, "", status.Errorf(codes.Internal, "can't parse deployment %s: %v", id, err)
+	}
+	return d, dir, nil
+}
+
+// save updates the state of d in dir.
+func save(d *pb.Deployment, dir string) error {
+	d.Updated = timestamppb.Now()
+	b, err := protojson.Marshal(d)
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't marshal deployment: %v", err)
+	}
+	if err := writeFile(filepath.Join(dir, stateFile), b, 0600, -1, -1); err != nil {
+		return status.Errorf(codes.Internal, "can't save deployment %s: %v", d.Id, err)
+	}
+	return nil
+}
+
+// writeFile atomically replaces path with contents by writing a temporary
+// file in the same directory and renaming it. If uid or gid are -1 they're
+// left as is.
+func writeFile(path string, contents []byte, mode fs.FileMode, uid int, gid int) (retErr error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".sansshell-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if _, err := f.Write(contents); err != nil {
+		return err
+	}
+	if uid != -1 || gid != -1 {
+		if err := f.Chown(uid, gid); err != nil {
+			return err
+		}
+	}
+	// After chown, which may clear setuid/setgid.
+	if err := f.Chmod(mode); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// copyFile atomically replaces dst with a copy of src, including its
+// ownership and permissions.
+func copyFile(src string, dst string) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	uid, gid := owner(fi)
+	return writeFile(dst, b, fi.Mode().Perm()|fi.Mode()&(fs.ModeSetuid|fs.ModeSetgid|fs.ModeSticky), uid, gid)
+}
+
+// owner returns the uid and gid of the file described by fi, or -1 if they
+// aren't known on this platform.
+func owner(fi fs.FileInfo) (int, int) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok || st == nil {
+		return -1, -1
+	}
+	return int(st.Uid), int(st.Gid)
+}
+
+// fileMode converts mode bits as used by chmod(2) to a fs.FileMode.
+func fileMode(mode uint32) fs.FileMode {
+	m := fs.FileMode(mode & 0777)
+	if mode&04000 != 0 {
+		m |= fs.ModeSetuid
+	}
+	if mode&02000 != 0 {
+		m |= fs.ModeSetgid
+	}
+	if mode&01000 != 0 {
+		m |= fs.ModeSticky
+	}
+	return m
+}
+
+// fileSum returns the hex encoded SHA256 of path.
+func fileSum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Stage implements pb.ConfigDeployServer.Stage
+func (s *server) Stage(ctx context.Context, req *pb.StageRequest) (*pb.StageReply, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	if len(req.Files) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one file must be staged")
+	}
+	seen := make(map[string]bool)
+	for _, f := range req.Files {
+		if err := util.ValidPath(f.Path); err != nil {
+			return nil, err
+		}
+		if seen[f.Path] {
+			return nil, status.Errorf(codes.InvalidArgument, "%s is staged more than once", f.Path)
+		}
+		seen[f.Path] = true
+		if f.Mode&^07777 != 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid mode %o for %s", f.Mode, f.Path)
+		}
+	}
+	id := req.Id
+	if id == "" {
+		var err error
+		if id, err = newID(); err != nil {
+			return nil, status.Errorf(codes.Internal, "can't generate deployment ID: %v", err)
+		}
+	}
+	dir, err := deploymentDir(id)
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := os.MkdirAll(*deployDir, 0700); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't create %s: %v", *deployDir, err)
+	}
+	if err := os.Mkdir(dir, 0700); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return nil, status.Errorf(codes.AlreadyExists, "deployment %s already exists", id)
+		}
+		return nil, status.Errorf(codes.Internal, "can't create deployment: %v", err)
+	}
+	staged := false
+	defer func() {
+		if !staged {
+			os.RemoveAll(dir)
+		}
+	}()
+	for _, sub := range []string{stagedDir, backupDir} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0700); err != nil {
+			return nil, status.Errorf(codes.Internal, "can't create deployment: %v", err)
+		}
+	}
+
+	now := timestamppb.Now()
+	d := &pb.Deployment{
+		Id:      id,
+		State:   pb.DeploymentState_DEPLOYMENT_STATE_STAGED,
+		Created: now,
+	}
+	reply := &pb.StageReply{Id: id}
+	for i, f := range req.Files {
+		if err := os.WriteFile(fileName(dir, stagedDir, i), f.Contents, 0600); err != nil {
+			return nil, status.Errorf(codes.Internal, "can't stage %s: %v", f.Path, err)
+		}
+		sum := sha256.Sum256(f.Contents)
+		d.Files = append(d.Files, &pb.DeployedFile{
+			Path:   f.Path,
+			Sha256: hex.EncodeToString(sum[:]),
+			Uid:    f.Uid,
+			Gid:    f.Gid,
+			Mode:   f.Mode,
+		})
+		reply.Sha256 = append(reply.Sha256, hex.EncodeToString(sum[:]))
+	}
+	if err := save(d, dir); err != nil {
+		return nil, err
+	}
+	staged = true
+	logger.Info("staged deployment", "id", id, "files", len(d.Files))
+	return reply, nil
+}
+
+// Validate implements pb.ConfigDeployServer.Validate
+func (s *server) Validate(ctx context.Context, req *pb.DeploymentRequest) (*pb.ValidateReply, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	checks, err := loadChecks()
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	d, dir, err := load(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	switch d.State {
+	case pb.DeploymentState_DEPLOYMENT_STATE_STAGED, pb.DeploymentState_DEPLOYMENT_STATE_VALIDATED, pb.DeploymentState_DEPLOYMENT_STATE_VALIDATION_FAILED:
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "deployment %s is %s and can't be validated", d.Id, d.State)
+	}
+
+	reply := &pb.ValidateReply{Passed: true}
+	for i, f := range d.Files {
+		staged := fileName(dir, stagedDir, i)
+		for _, c := range checks {
+			if ok, _ := filepath.Match(c.Pattern, f.Path); !ok {
+				continue
+			}
+			var args []string
+			for _, a := range c.Command[1:] {
+				args = append(args, strings.ReplaceAll(a, filePlaceholder, staged))
+			}
+			var output strings.Builder
+			run, err := util.RunCommand(ctx, c.Command[0], args, util.StreamOutput(&output))
+			if err != nil {
+				return nil, err
+			}
+			result := &pb.CheckResult{
+				Path:     f.Path,
+				Command:  append([]string{c.Command[0]}, args...),
+				ExitCode: int32(run.ExitCode),
+				Output:   util.TrimString(output.String()),
+			}
+			if run.Error != nil && result.ExitCode == 0 {
+				// Couldn't be run at all.
+				result.ExitCode = -1
+				result.Output = util.TrimString(run.Error.Error())
+			}
+			if result.ExitCode != 0 {
+				reply.Passed = false
+			}
+			reply.Results = append(reply.Results, result)
+		}
+	}
+
+	d.Checks = reply.Results
+	d.State = pb.DeploymentState_DEPLOYMENT_STATE_VALIDATED
+	if !reply.Passed {
+		d.State = pb.DeploymentState_DEPLOYMENT_STATE_VALIDATION_FAILED
+	}
+	if err := save(d, dir); err != nil {
+		return nil, err
+	}
+	logger.Info("validated deployment", "id", d.Id, "passed", reply.Passed)
+	return reply, nil
+}
+
+// restore puts back the file d.Files[i] replaced.
+func restore(d *pb.Deployment, dir string, i int) error {
+	f := d.Files[i]
+	if f.Existed {
+		return copyFile(fileName(dir, backupDir, i), f.Path)
+	}
+	if err := os.Remove(f.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Activate implements pb.ConfigDeployServer.Activate
+func (s *server) Activate(ctx context.Context, req *pb.DeploymentRequest) (*pb.Deployment, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	mu.Lock()
+	defer mu.Unlock()
+	d, dir, err := load(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if d.State != pb.DeploymentState_DEPLOYMENT_STATE_VALIDATED {
+		return nil, status.Errorf(codes.FailedPrecondition, "deployment %s is %s, only validated deployments can be activated", d.Id, d.State)
+	}
+
+	// Backups are all taken first so a failure leaves nothing to undo.
+	for i, f := range d.Files {
+		fi, err := os.Lstat(f.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			f.Existed = false
+			continue
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't stat %s: %v", f.Path, err)
+		}
+		if !fi.Mode().IsRegular() {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s is not a regular file", f.Path)
+		}
+		if err := copyFile(f.Path, fileName(dir, backupDir, i)); err != nil {
+			return nil, status.Errorf(codes.Internal, "can't back up %s: %v", f.Path, err)
+		}
+		f.Existed = true
+	}
+
+	for i, f := range d.Files {
+		contents, err := os.ReadFile(fileName(dir, stagedDir, i))
+		if err == nil {
+			err = writeFile(f.Path, contents, fileMode(f.Mode), int(f.Uid), int(f.Gid))
+		}
+		if err != nil {
+			for j := i - 1; j >= 0; j-- {
+				if rerr := restore(d, dir, j); rerr != nil {
+					logger.Error(rerr, "can't restore file after failed activation", "id", d.Id, "path", d.Files[j].Path)
+				}
+			}
+			return nil, status.Errorf(codes.Internal, "can't activate %s (earlier files were restored): %v", f.Path, err)
+		}
+	}
+
+	d.State = pb.DeploymentState_DEPLOYMENT_STATE_ACTIVE
+	if err := save(d, dir); err != nil {
+		return nil, err
+	}
+	logger.Info("activated deployment", "id", d.Id)
+	return d, nil
+}
+
+// Rollback implements pb.ConfigDeployServer.Rollback
+func (s *server) Rollback(ctx context.Context, req *pb.DeploymentRequest) (*pb.Deployment, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	mu.Lock()
+	defer mu.Unlock()
+	d, dir, err := load(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if d.State != pb.DeploymentState_DEPLOYMENT_STATE_ACTIVE {
+		return nil, status.Errorf(codes.FailedPrecondition, "deployment %s is %s, only active deployments can be rolled back", d.Id, d.State)
+	}
+	// Don't clobber anything changed since, i.e. by a later deployment.
+	for _, f := range d.Files {
+		sum, err := fileSum(f.Path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, status.Errorf(codes.Internal, "can't read %s: %v", f.Path, err)
+		}
+		if sum != f.Sha256 {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s has changed since deployment %s was activated", f.Path, d.Id)
+		}
+	}
+	for i := len(d.Files) - 1; i >= 0; i-- {
+		if err := restore(d, dir, i); err != nil {
+			return nil, status.Errorf(codes.Internal, "can't restore %s: %v", d.Files[i].Path, err)
+		}
+	}
+	d.State = pb.DeploymentState_DEPLOYMENT_STATE_ROLLED_BACK
+	if err := save(d, dir); err != nil {
+		return nil, err
+	}
+	logger.Info("rolled back deployment", "id", d.Id)
+	return d, nil
+}
+
+// List implements pb.ConfigDeployServer.List
+func (s *server) List(ctx context.Context, req *pb.ListRequest) (*pb.ListReply, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	entries, err := os.ReadDir(*deployDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &pb.ListReply{}, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read %s: %v", *deployDir, err)
+	}
+	reply := &pb.ListReply{}
+	for _, e := range entries {
+		if !e.IsDir() || !validID.MatchString(e.Name()) {
+			continue
+		}
+		d, _, err := load(e.Name())
+		if status.Code(err) == codes.NotFound {
+			// Left over from a failed Stage.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		reply.Deployments = append(reply.Deployments, d)
+	}
+	sort.SliceStable(reply.Deployments, func(i, j int) bool {
+		return reply.Deployments[i].Created.AsTime().Before(reply.Deployments[j].Created.AsTime())
+	})
+	return reply, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterConfigDeployServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
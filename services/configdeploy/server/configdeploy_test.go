@@ -0,0 +1,308 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/configdeploy"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// setup points the server at a temporary deployment directory and a check
+// failing any file under the returned directory which doesn't contain "good".
+func setup(t *testing.T) string {
+	t.Helper()
+	savedDir, savedChecks := *deployDir, *checksFile
+	t.Cleanup(func() {
+		*deployDir = savedDir
+		*checksFile = savedChecks
+	})
+	tmp := t.TempDir()
+	*deployDir = filepath.Join(tmp, "deployments")
+	config := filepath.Join(tmp, "etc")
+	testutil.FatalOnErr("mkdir", os.Mkdir(config, 0755), t)
+	checks, err := json.Marshal([]check{
+		{Pattern: filepath.Join(config, "*.conf"), Command: []string{"/bin/sh", "-c", `grep good "$1" || { echo "$1 isn't good"; exit 3; }`, "sh", "{}"}},
+	})
+	testutil.FatalOnErr("marshal", err, t)
+	*checksFile = filepath.Join(tmp, "checks.json")
+	testutil.FatalOnErr("writing checks", os.WriteFile(*checksFile, checks, 0644), t)
+	return config
+}
+
+func stagedFile(path string, contents string) *pb.StagedFile {
+	return &pb.StagedFile{
+		Path:     path,
+		Contents: []byte(contents),
+		Uid:      uint32(os.Getuid()),
+		Gid:      uint32(os.Getgid()),
+		Mode:     0640,
+	}
+}
+
+func checkFile(t *testing.T, path string, want string, wantMode os.FileMode) {
+	t.Helper()
+	fi, err := os.Stat(path)
+	testutil.FatalOnErr("stat", err, t)
+	if got := fi.Mode().Perm(); got != wantMode {
+		t.Errorf("%s: mode %o, want %o", path, got, wantMode)
+	}
+	b, err := os.ReadFile(path)
+	testutil.FatalOnErr("read", err, t)
+	if got := string(b); got != want {
+		t.Errorf("%s: contents %q, want %q", path, got, want)
+	}
+}
+
+func TestStage(t *testing.T) {
+	config := setup(t)
+	ctx := context.Background()
+	s := &server{}
+
+	resp, err := s.Stage(ctx, &pb.StageRequest{Files: []*pb.StagedFile{stagedFile(filepath.Join(config, "a.conf"), "good")}})
+	testutil.FatalOnErr("Stage", err, t)
+	if !validID.MatchString(resp.Id) {
+		t.Errorf("generated invalid ID %q", resp.Id)
+	}
+	// sha256 of "good"
+	if want := "770e607624d689265ca6c44884d0807d9b054d23c473c106c72be9de08b7376c"; len(resp.Sha256) != 1 || resp.Sha256[0] != want {
+		t.Errorf("Sha256 = %v, want [%s]", resp.Sha256, want)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.StageRequest
+		wantErr codes.Code
+	}{
+		{
+			name:    "no files",
+			req:     &pb.StageRequest{},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "relative path",
+			req:     &pb.StageRequest{Files: []*pb.StagedFile{stagedFile("a.conf", "good")}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name: "duplicate path",
+			req: &pb.StageRequest{Files: []*pb.StagedFile{
+				stagedFile(filepath.Join(config, "a.conf"), "good"),
+				stagedFile(filepath.Join(config, "a.conf"), "good"),
+			}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name: "bad mode",
+			req: &pb.StageRequest{Files: []*pb.StagedFile{
+				{Path: filepath.Join(config, "a.conf"), Mode: 010644},
+			}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad id",
+			req:     &pb.StageRequest{Id: "../etc", Files: []*pb.StagedFile{stagedFile(filepath.Join(config, "a.conf"), "good")}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "existing id",
+			req:     &pb.StageRequest{Id: resp.Id, Files: []*pb.StagedFile{stagedFile(filepath.Join(config, "a.conf"), "good")}},
+			wantErr: codes.AlreadyExists,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.Stage(ctx, tc.req)
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	list, err := s.List(ctx, &pb.ListRequest{})
+	testutil.FatalOnErr("List", err, t)
+	if len(list.Deployments) != 1 || list.Deployments[0].Id != resp.Id || list.Deployments[0].State != pb.DeploymentState_DEPLOYMENT_STATE_STAGED {
+		t.Errorf("List returned %v, want only staged %s", list.Deployments, resp.Id)
+	}
+}
+
+func TestDeployment(t *testing.T) {
+	config := setup(t)
+	ctx := context.Background()
+	s := &server{}
+
+	existing := filepath.Join(config, "existing.conf")
+	testutil.FatalOnErr("writing file", os.WriteFile(existing, []byte("old"), 0600), t)
+	created := filepath.Join(config, "new.conf")
+	unchecked := filepath.Join(config, "unchecked")
+	_, err := s.Stage(ctx, &pb.StageRequest{
+		Id: "test-1",
+		Files: []*pb.StagedFile{
+			stagedFile(existing, "good\n"),
+			stagedFile(created, "also good\n"),
+			stagedFile(unchecked, "anything"),
+		},
+	})
+	testutil.FatalOnErr("Stage", err, t)
+	req := &pb.DeploymentRequest{Id: "test-1"}
+
+	_, err = s.Activate(ctx, req)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Activate before Validate: got %v, want FailedPrecondition", err)
+	}
+	_, err = s.Rollback(ctx, req)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Rollback before Activate: got %v, want FailedPrecondition", err)
+	}
+
+	v, err := s.Validate(ctx, req)
+	testutil.FatalOnErr("Validate", err, t)
+	if !v.Passed || len(v.Results) != 2 {
+		t.Fatalf("Validate = %v, want 2 passing checks", v)
+	}
+	for _, r := range v.Results {
+		if r.ExitCode != 0 || r.Output == "" {
+			t.Errorf("check of %s: exit code %d, output %q", r.Path, r.ExitCode, r.Output)
+		}
+	}
+
+	d, err := s.Activate(ctx, req)
+	testutil.FatalOnErr("Activate", err, t)
+	if d.State != pb.DeploymentState_DEPLOYMENT_STATE_ACTIVE {
+		t.Errorf("state after Activate is %v", d.State)
+	}
+	if !d.Files[0].Existed || d.Files[1].Existed || d.Files[2].Existed {
+		t.Errorf("Existed is wrong: %v", d.Files)
+	}
+	checkFile(t, existing, "good\n", 0640)
+	checkFile(t, created, "also good\n", 0640)
+	checkFile(t, unchecked, "anything", 0640)
+
+	_, err = s.Activate(ctx, req)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("second Activate: got %v, want FailedPrecondition", err)
+	}
+
+	d, err = s.Rollback(ctx, req)
+	testutil.FatalOnErr("Rollback", err, t)
+	if d.State != pb.DeploymentState_DEPLOYMENT_STATE_ROLLED_BACK {
+		t.Errorf("state after Rollback is %v", d.State)
+	}
+	checkFile(t, existing, "old", 0600)
+	for _, f := range []string{created, unchecked} {
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after rollback: %v", f, err)
+		}
+	}
+
+	list, err := s.List(ctx, &pb.ListRequest{})
+	testutil.FatalOnErr("List", err, t)
+	if len(list.Deployments) != 1 || list.Deployments[0].State != pb.DeploymentState_DEPLOYMENT_STATE_ROLLED_BACK || len(list.Deployments[0].Checks) != 2 {
+		t.Errorf("List returned %v", list.Deployments)
+	}
+
+	for _, id := range []string{"missing", "../x"} {
+		_, err := s.Validate(ctx, &pb.DeploymentRequest{Id: id})
+		if status.Code(err) == codes.OK {
+			t.Errorf("Validate of %q succeeded", id)
+		}
+	}
+}
+
+func TestValidateFails(t *testing.T) {
+	config := setup(t)
+	ctx := context.Background()
+	s := &server{}
+
+	path := filepath.Join(config, "a.conf")
+	_, err := s.Stage(ctx, &pb.StageRequest{Id: "bad", Files: []*pb.StagedFile{stagedFile(path, "bad")}})
+	testutil.FatalOnErr("Stage", err, t)
+	req := &pb.DeploymentRequest{Id: "bad"}
+	v, err := s.Validate(ctx, req)
+	testutil.FatalOnErr("Validate", err, t)
+	if v.Passed || len(v.Results) != 1 || v.Results[0].ExitCode != 3 || v.Results[0].Path != path {
+		t.Fatalf("Validate = %v, want failure with exit code 3", v)
+	}
+	_, err = s.Activate(ctx, req)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Activate after failed validation: got %v, want FailedPrecondition", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s was written: %v", path, err)
+	}
+}
+
+func TestActivateRestores(t *testing.T) {
+	config := setup(t)
+	ctx := context.Background()
+	s := &server{}
+
+	existing := filepath.Join(config, "existing.conf")
+	testutil.FatalOnErr("writing file", os.WriteFile(existing, []byte("old"), 0644), t)
+	created := filepath.Join(config, "new.conf")
+	_, err := s.Stage(ctx, &pb.StageRequest{
+		Id: "partial",
+		Files: []*pb.StagedFile{
+			stagedFile(existing, "good"),
+			stagedFile(created, "good"),
+			// Its directory doesn't exist so this can't be activated.
+			stagedFile(filepath.Join(config, "missing", "x"), "good"),
+		},
+	})
+	testutil.FatalOnErr("Stage", err, t)
+	req := &pb.DeploymentRequest{Id: "partial"}
+	_, err = s.Validate(ctx, req)
+	testutil.FatalOnErr("Validate", err, t)
+	_, err = s.Activate(ctx, req)
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("Activate: got %v, want Internal", err)
+	}
+	checkFile(t, existing, "old", 0644)
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("%s still exists after failed activation: %v", created, err)
+	}
+}
+
+func TestRollbackChanged(t *testing.T) {
+	config := setup(t)
+	ctx := context.Background()
+	s := &server{}
+
+	path := filepath.Join(config, "a.conf")
+	_, err := s.Stage(ctx, &pb.StageRequest{Id: "changed", Files: []*pb.StagedFile{stagedFile(path, "good")}})
+	testutil.FatalOnErr("Stage", err, t)
+	req := &pb.DeploymentRequest{Id: "changed"}
+	_, err = s.Validate(ctx, req)
+	testutil.FatalOnErr("Validate", err, t)
+	_, err = s.Activate(ctx, req)
+	testutil.FatalOnErr("Activate", err, t)
+
+	testutil.FatalOnErr("writing file", os.WriteFile(path, []byte("edited"), 0644), t)
+	_, err = s.Rollback(ctx, req)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Rollback of changed file: got %v, want FailedPrecondition", err)
+	}
+	checkFile(t, path, "edited", 0640)
+}
@@ -0,0 +1,146 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'cron'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/cron"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "cron"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&crontabsCmd{}, "")
+	c.Register(&timersCmd{}, "")
+	return c
+}
+
+// formatTime formats t for output, or - if it's unset.
+func formatTime(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return "-"
+	}
+	return t.AsTime().Local().Format(time.RFC3339)
+}
+
+type crontabsCmd struct {
+	user  string
+	match string
+}
+
+func (*crontabsCmd) Name() string     { return "crontabs" }
+func (*crontabsCmd) Synopsis() string { return "List crontab entries" }
+func (*crontabsCmd) Usage() string {
+	return `crontabs [--user=X] [--match=X]:
+    List the entries of the system crontab, /etc/cron.d and user crontabs as
+    user, schedule, next run, file:line and command. Entries which can't be
+    parsed are listed with the error.
+`
+}
+
+func (c *crontabsCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.user, "user", "", "Only list entries run as this user")
+	f.StringVar(&c.match, "match", "", "Only list entries whose command contains this")
+}
+
+func (c *crontabsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	proxy := pb.NewCronClientProxy(state.Conn)
+	respChan, err := proxy.ListCrontabsOneMany(ctx, &pb.ListCrontabsRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "ListCrontabs for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, e := range r.Resp.Entries {
+			if (c.user != "" && e.User != c.user) || !strings.Contains(e.Command, c.match) {
+				continue
+			}
+			if e.Error != "" {
+				fmt.Fprintf(state.Out[r.Index], "%s\t%s:%d\terror: %s: %s\n", e.User, e.File, e.Line, e.Error, e.Command)
+				continue
+			}
+			fmt.Fprintf(state.Out[r.Index], "%s\t%s\t%s\t%s:%d\t%s\n", e.User, e.Schedule, formatTime(e.NextRun), e.File, e.Line, e.Command)
+		}
+	}
+	return retCode
+}
+
+type timersCmd struct{}
+
+func (*timersCmd) Name() string     { return "timers" }
+func (*timersCmd) Synopsis() string { return "List systemd timers" }
+func (*timersCmd) Usage() string {
+	return `timers:
+    List the loaded systemd timers as unit, state, next run, last run, the
+    unit activated and schedules.
+`
+}
+
+func (*timersCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*timersCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	proxy := pb.NewCronClientProxy(state.Conn)
+	respChan, err := proxy.ListTimersOneMany(ctx, &pb.ListTimersRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "ListTimers for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, t := range r.Resp.Timers {
+			fmt.Fprintf(state.Out[r.Index], "%s\t%s\t%s\t%s\t%s\t%s\n", t.Unit, t.ActiveState, formatTime(t.NextRun), formatTime(t.LastRun), t.Activates, strings.Join(t.Schedules, " "))
+		}
+	}
+	return retCode
+}
@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package cron
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// CronClient is the client API for Cron service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CronClient interface {
+	// ListCrontabs returns the entries of the system crontab, /etc/cron.d
+	// and all user crontabs.
+	ListCrontabs(ctx context.Context, in *ListCrontabsRequest, opts ...grpc.CallOption) (*ListCrontabsReply, error)
+	// ListTimers returns the systemd timers currently loaded.
+	ListTimers(ctx context.Context, in *ListTimersRequest, opts ...grpc.CallOption) (*ListTimersReply, error)
+}
+
+type cronClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCronClient(cc grpc.ClientConnInterface) CronClient {
+	return &cronClient{cc}
+}
+
+func (c *cronClient) ListCrontabs(ctx context.Context, in *ListCrontabsRequest, opts ...grpc.CallOption) (*ListCrontabsReply, error) {
+	out := new(ListCrontabsReply)
+	err := c.cc.Invoke(ctx, "/Cron.Cron/ListCrontabs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cronClient) ListTimers(ctx context.Context, in *ListTimersRequest, opts ...grpc.CallOption) (*ListTimersReply, error) {
+	out := new(ListTimersReply)
+	err := c.cc.Invoke(ctx, "/Cron.Cron/ListTimers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CronServer is the server API for Cron service.
+// All implementations should embed UnimplementedCronServer
+// for forward compatibility
+type CronServer interface {
+	// ListCrontabs returns the entries of the system crontab, /etc/cron.d
+	// and all user crontabs.
+	ListCrontabs(context.Context, *ListCrontabsRequest) (*ListCrontabsReply, error)
+	// ListTimers returns the systemd timers currently loaded.
+	ListTimers(context.Context, *ListTimersRequest) (*ListTimersReply, error)
+}
+
+// UnimplementedCronServer should be embedded to have forward compatible implementations.
+type UnimplementedCronServer struct {
+}
+
+func (UnimplementedCronServer) ListCrontabs(context.Context, *ListCrontabsRequest) (*ListCrontabsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCrontabs not implemented")
+}
+func (UnimplementedCronServer) ListTimers(context.Context, *ListTimersRequest) (*ListTimersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTimers not implemented")
+}
+
+// UnsafeCronServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CronServer will
+// result in compilation errors.
+type UnsafeCronServer interface {
+	mustEmbedUnimplementedCronServer()
+}
+
+func RegisterCronServer(s grpc.ServiceRegistrar, srv CronServer) {
+	s.RegisterService(&Cron_ServiceDesc, srv)
+}
+
+func _Cron_ListCrontabs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCrontabsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CronServer).ListCrontabs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Cron.Cron/ListCrontabs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CronServer).ListCrontabs(ctx, req.(*ListCrontabsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cron_ListTimers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTimersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CronServer).ListTimers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Cron.Cron/ListTimers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CronServer).ListTimers(ctx, req.(*ListTimersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Cron_ServiceDesc is the grpc.ServiceDesc for Cron service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Cron_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Cron.Cron",
+	HandlerType: (*CronServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCrontabs",
+			Handler:    _Cron_ListCrontabs_Handler,
+		},
+		{
+			MethodName: "ListTimers",
+			Handler:    _Cron_ListTimers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cron.proto",
+}
@@ -0,0 +1,168 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package cron
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// CronClientProxy is the superset of CronClient which additionally includes the OneMany proxy methods
+type CronClientProxy interface {
+	CronClient
+	ListCrontabsOneMany(ctx context.Context, in *ListCrontabsRequest, opts ...grpc.CallOption) (<-chan *ListCrontabsManyResponse, error)
+	ListTimersOneMany(ctx context.Context, in *ListTimersRequest, opts ...grpc.CallOption) (<-chan *ListTimersManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type cronClientProxy struct {
+	*cronClient
+}
+
+// NewCronClientProxy creates a CronClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewCronClientProxy(cc *proxy.Conn) CronClientProxy {
+	return &cronClientProxy{NewCronClient(cc).(*cronClient)}
+}
+
+// ListCrontabsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ListCrontabsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ListCrontabsReply
+	Error error
+}
+
+// ListCrontabsOneMany provides the same API as ListCrontabs but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *cronClientProxy) ListCrontabsOneMany(ctx context.Context, in *ListCrontabsRequest, opts ...grpc.CallOption) (<-chan *ListCrontabsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ListCrontabsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ListCrontabsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ListCrontabsReply{},
+			}
+			err := conn.Invoke(ctx, "/Cron.Cron/ListCrontabs", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Cron.Cron/ListCrontabs", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ListCrontabsManyResponse{
+				Resp: &ListCrontabsReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ListTimersManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ListTimersManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ListTimersReply
+	Error error
+}
+
+// ListTimersOneMany provides the same API as ListTimers but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *cronClientProxy) ListTimersOneMany(ctx context.Context, in *ListTimersRequest, opts ...grpc.CallOption) (<-chan *ListTimersManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ListTimersManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ListTimersManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ListTimersReply{},
+			}
+			err := conn.Invoke(ctx, "/Cron.Cron/ListTimers", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Cron.Cron/ListTimers", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ListTimersManyResponse{
+				Resp: &ListTimersReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
@@ -0,0 +1,377 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'Cron' service.
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/cron"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+var (
+	systemCrontab = flag.String("cron-system-crontab", "/etc/crontab", "The system crontab, which has a user field")
+	systemDir     = flag.String("cron-system-dir", "/etc/cron.d", "Directory of crontabs which have a user field, as with the system crontab")
+	userDirs      = []string{"/var/spool/cron/crontabs", "/var/spool/cron"}
+
+	// envRE matches crontab lines setting environment variables.
+	envRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\s*=`)
+
+	// now is the time next runs are calculated from, replaced in tests.
+	now = time.Now
+)
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// macros are the shorthand schedules cron supports.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	dayNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// field describes a crontab schedule field.
+type field struct {
+	name     string
+	min, max int
+	// names if set are accepted for min, min+1, ...
+	names []string
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: monthNames},
+	// 7 is also Sunday.
+	{name: "day of week", min: 0, max: 7, names: dayNames},
+}
+
+// schedule is a parsed crontab schedule. Each field is a bitmask of the
+// values it matches.
+type schedule struct {
+	minute, hour, dom, month, dow uint64
+	// Whether the day of month or week fields are '*', as if both are
+	// restricted a day matching either matches.
+	domStar, dowStar bool
+}
+
+// value parses a single value of f.
+func (f field) value(s string) (int, error) {
+	for i, n := range f.names {
+		if strings.EqualFold(s, n) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	return v, nil
+}
+
+// parse returns the bitmask of values s matches for f.
+func (f field) parse(s string) (uint64, error) {
+	var mask uint64
+	for _, item := range strings.Split(s, ",") {
+		step := 1
+		parts := strings.SplitN(item, "/", 2)
+		if len(parts) == 2 {
+			var err error
+			if step, err = strconv.Atoi(parts[1]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in %s %q", f.name, item)
+			}
+		}
+		lo, hi := f.min, f.max
+		switch r := strings.SplitN(parts[0], "-", 2); {
+		case r[0] == "*" && len(r) == 1:
+		case len(r) == 2:
+			var err error
+			if lo, err = f.value(r[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(r[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid %s range %q", f.name, parts[0])
+			}
+		default:
+			var err error
+			if lo, err = f.value(r[0]); err != nil {
+				return 0, err
+			}
+			// A single value with a step runs to the end of the range.
+			if len(parts) == 1 {
+				hi = lo
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+// parseSchedule parses a 5 field crontab schedule or one of the macros.
+func parseSchedule(spec string) (*schedule, error) {
+	if strings.HasPrefix(spec, "@") {
+		m, ok := macros[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown schedule %s", spec)
+		}
+		spec = m
+	}
+	f := strings.Fields(spec)
+	if len(f) != len(fields) {
+		return nil, fmt.Errorf("schedule %q doesn't have %d fields", spec, len(fields))
+	}
+	var masks []uint64
+	for i, field := range fields {
+		m, err := field.parse(f[i])
+		if err != nil {
+			return nil, err
+		}
+		masks = append(masks, m)
+	}
+	// Sunday can be 0 or 7.
+	if masks[4]&(1<<7) != 0 {
+		masks[4] |= 1
+	}
+	return &schedule{
+		minute:  masks[0],
+		hour:    masks[1],
+		dom:     masks[2],
+		month:   masks[3],
+		dow:     masks[4],
+		domStar: strings.HasPrefix(f[2], "*"),
+		dowStar: strings.HasPrefix(f[4], "*"),
+	}, nil
+}
+
+// dayMatches reports whether the day of t matches s.
+func (s *schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next returns the first time after t which s matches, or the zero time if
+// there isn't one in the next 5 years (i.e. for February 30th).
+func (s *schedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// splitFields splits the first n whitespace separated fields from line,
+// returning them and the rest of the line.
+func splitFields(line string, n int) ([]string, string, bool) {
+	var out []string
+	for i := 0; i < n; i++ {
+		line = strings.TrimLeft(line, " \t")
+		end := strings.IndexAny(line, " \t")
+		if end == -1 {
+			return nil, "", false
+		}
+		out = append(out, line[:end])
+		line = line[end:]
+	}
+	return out, strings.TrimLeft(line, " \t"), true
+}
+
+// parseLine parses a crontab line into an entry, returning nil for lines
+// which aren't entries. If user is empty the line has a user field.
+func parseLine(file string, n int, line string, user string) *pb.CronEntry {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || envRE.MatchString(line) {
+		return nil
+	}
+	entry := &pb.CronEntry{File: file, Line: int32(n), User: user}
+	scheduleFields := len(fields)
+	if strings.HasPrefix(line, "@") {
+		scheduleFields = 1
+	}
+	count := scheduleFields
+	if user == "" {
+		count++
+	}
+	f, rest, ok := splitFields(line, count)
+	if !ok || rest == "" {
+		entry.Command = line
+		entry.Error = "too few fields"
+		return entry
+	}
+	entry.Schedule = strings.Join(f[:scheduleFields], " ")
+	if user == "" {
+		entry.User = f[scheduleFields]
+	}
+	entry.Command = rest
+	if strings.EqualFold(entry.Schedule, "@reboot") {
+		return entry
+	}
+	s, err := parseSchedule(entry.Schedule)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	if next := s.next(now()); !next.IsZero() {
+		entry.NextRun = timestamppb.New(next)
+	}
+	return entry
+}
+
+// readCrontab returns the entries of file, or none if it doesn't exist.
+func readCrontab(file string, user string) ([]*pb.CronEntry, error) {
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't open %s: %v", file, err)
+	}
+	defer f.Close()
+	var entries []*pb.CronEntry
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if e := parseLine(file, n, scanner.Text(), user); e != nil {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read %s: %v", file, err)
+	}
+	return entries, nil
+}
+
+// crontabs returns the regular files in dir, skipping hidden and backup
+// files as cron does. A missing dir has none.
+func crontabs(dir string) ([]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read %s: %v", dir, err)
+	}
+	var out []string
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") || strings.HasSuffix(e.Name(), "~") {
+			continue
+		}
+		out = append(out, e.Name())
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// ListCrontabs implements pb.CronServer.ListCrontabs
+func (s *server) ListCrontabs(ctx context.Context, req *pb.ListCrontabsRequest) (*pb.ListCrontabsReply, error) {
+	reply := &pb.ListCrontabsReply{}
+	if *systemCrontab != "" {
+		entries, err := readCrontab(*systemCrontab, "")
+		if err != nil {
+			return nil, err
+		}
+		reply.Entries = append(reply.Entries, entries...)
+	}
+	names, err := crontabs(*systemDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range names {
+		entries, err := readCrontab(filepath.Join(*systemDir, n), "")
+		if err != nil {
+			return nil, err
+		}
+		reply.Entries = append(reply.Entries, entries...)
+	}
+	for _, dir := range userDirs {
+		names, err := crontabs(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range names {
+			entries, err := readCrontab(filepath.Join(dir, n), n)
+			if err != nil {
+				return nil, err
+			}
+			reply.Entries = append(reply.Entries, entries...)
+		}
+	}
+	return reply, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterCronServer(gs, s)
+}
+
+func init() {
+	flag.Var(&util.StringSliceFlag{Target: &userDirs}, "cron-user-dirs", "Comma separated directories of user crontabs, named for the user. Missing ones are skipped.")
+	services.RegisterSansShellService(&server{})
+}
@@ -0,0 +1,120 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/cron"
+)
+
+// a subset of dbus.Conn used to mock for testing
+type systemdConnection interface {
+	ListUnitsContext(ctx context.Context) ([]dbus.UnitStatus, error)
+	GetUnitTypePropertiesContext(ctx context.Context, unit string, unitType string) (map[string]interface{}, error)
+	Close()
+}
+
+// dialSystemd is the function used to create connections to systemd.
+var dialSystemd = func(ctx context.Context) (systemdConnection, error) {
+	return dbus.NewSystemdConnectionContext(ctx)
+}
+
+// usecTime converts a timestamp in microseconds since the epoch, where 0
+// means unset, as systemd uses.
+func usecTime(v interface{}) *timestamppb.Timestamp {
+	usec, ok := v.(uint64)
+	if !ok || usec == 0 || usec == ^uint64(0) {
+		return nil
+	}
+	return timestamppb.New(time.Unix(0, 0).Add(time.Duration(usec) * time.Microsecond))
+}
+
+// timerSchedules converts the TimersCalendar (a(sst)) and TimersMonotonic
+// (a(stt)) properties of a timer to unit file syntax.
+func timerSchedules(props map[string]interface{}) []string {
+	var out []string
+	if cal, ok := props["TimersCalendar"].([][]interface{}); ok {
+		for _, c := range cal {
+			if len(c) >= 2 {
+				out = append(out, fmt.Sprintf("%v=%v", c[0], c[1]))
+			}
+		}
+	}
+	if mono, ok := props["TimersMonotonic"].([][]interface{}); ok {
+		for _, m := range mono {
+			if len(m) < 2 {
+				continue
+			}
+			usec, ok := m[1].(uint64)
+			if !ok {
+				continue
+			}
+			// i.e. OnBootUSec, written OnBootSec in unit files.
+			base := strings.TrimSuffix(fmt.Sprint(m[0]), "USec") + "Sec"
+			out = append(out, fmt.Sprintf("%s=%v", base, time.Duration(usec)*time.Microsecond))
+		}
+	}
+	return out
+}
+
+// ListTimers implements pb.CronServer.ListTimers
+func (s *server) ListTimers(ctx context.Context, req *pb.ListTimersRequest) (*pb.ListTimersReply, error) {
+	conn, err := dialSystemd(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to connect to systemd: %v", err)
+	}
+	defer conn.Close()
+	units, err := conn.ListUnitsContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list systemd units: %v", err)
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].Name < units[j].Name })
+	reply := &pb.ListTimersReply{}
+	for _, u := range units {
+		if !strings.HasSuffix(u.Name, ".timer") {
+			continue
+		}
+		props, err := conn.GetUnitTypePropertiesContext(ctx, u.Name, "Timer")
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't get properties of %s: %v", u.Name, err)
+		}
+		t := &pb.Timer{
+			Unit:        u.Name,
+			Description: u.Description,
+			ActiveState: u.ActiveState,
+			Schedules:   timerSchedules(props),
+			NextRun:     usecTime(props["NextElapseUSecRealtime"]),
+			LastRun:     usecTime(props["LastTriggerUSec"]),
+		}
+		t.Activates, _ = props["Unit"].(string)
+		reply.Timers = append(reply.Timers, t)
+	}
+	return reply, nil
+}
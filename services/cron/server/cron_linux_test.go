@@ -0,0 +1,141 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/cron"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// fakeConn is a systemdConnection returning units and their timer
+// properties. If err is set all calls return it.
+type fakeConn struct {
+	units []dbus.UnitStatus
+	props map[string]map[string]interface{}
+	err   error
+}
+
+func (f *fakeConn) ListUnitsContext(context.Context) ([]dbus.UnitStatus, error) {
+	return f.units, f.err
+}
+func (f *fakeConn) GetUnitTypePropertiesContext(_ context.Context, unit string, unitType string) (map[string]interface{}, error) {
+	if unitType != "Timer" {
+		return nil, errors.New("not a timer")
+	}
+	return f.props[unit], f.err
+}
+func (*fakeConn) Close() {}
+
+func withConn(t *testing.T, conn systemdConnection, err error) {
+	t.Helper()
+	saved := dialSystemd
+	t.Cleanup(func() { dialSystemd = saved })
+	dialSystemd = func(context.Context) (systemdConnection, error) { return conn, err }
+}
+
+func TestListTimers(t *testing.T) {
+	next := time.Date(2022, time.June, 16, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2022, time.June, 15, 0, 0, 3, 0, time.UTC)
+	withConn(t, &fakeConn{
+		units: []dbus.UnitStatus{
+			{Name: "sshd.service", Description: "OpenSSH server", ActiveState: "active"},
+			{Name: "logrotate.timer", Description: "Daily rotation of log files", ActiveState: "active"},
+			{Name: "fstrim.timer", Description: "Discard unused blocks", ActiveState: "inactive"},
+		},
+		props: map[string]map[string]interface{}{
+			"logrotate.timer": {
+				"Unit":                   "logrotate.service",
+				"TimersCalendar":         [][]interface{}{{"OnCalendar", "*-*-* 00:00:00", uint64(0)}},
+				"NextElapseUSecRealtime": uint64(next.UnixNano() / 1000),
+				"LastTriggerUSec":        uint64(last.UnixNano() / 1000),
+			},
+			"fstrim.timer": {
+				"Unit":                   "fstrim.service",
+				"TimersMonotonic":        [][]interface{}{{"OnBootUSec", uint64(15 * time.Minute / time.Microsecond), uint64(0)}},
+				"NextElapseUSecRealtime": uint64(0),
+				"LastTriggerUSec":        uint64(0),
+			},
+		},
+	}, nil)
+
+	got, err := (&server{}).ListTimers(context.Background(), &pb.ListTimersRequest{})
+	testutil.FatalOnErr("ListTimers", err, t)
+	want := &pb.ListTimersReply{
+		Timers: []*pb.Timer{
+			{
+				Unit:        "fstrim.timer",
+				Description: "Discard unused blocks",
+				Activates:   "fstrim.service",
+				ActiveState: "inactive",
+				Schedules:   []string{"OnBootSec=15m0s"},
+			},
+			{
+				Unit:        "logrotate.timer",
+				Description: "Daily rotation of log files",
+				Activates:   "logrotate.service",
+				ActiveState: "active",
+				Schedules:   []string{"OnCalendar=*-*-* 00:00:00"},
+				NextRun:     timestamppb.New(next),
+				LastRun:     timestamppb.New(last),
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestListTimersErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		conn    systemdConnection
+		dialErr error
+	}{
+		{
+			name:    "dial",
+			dialErr: errors.New("no systemd"),
+		},
+		{
+			name: "list",
+			conn: &fakeConn{err: errors.New("list failed")},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			withConn(t, tc.conn, tc.dialErr)
+			_, err := (&server{}).ListTimers(context.Background(), &pb.ListTimersRequest{})
+			if status.Code(err) != codes.Internal {
+				t.Fatalf("unexpected error: got %v, want Internal", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/cron"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestNext(t *testing.T) {
+	// A Wednesday.
+	from := time.Date(2022, time.June, 15, 10, 30, 45, 0, time.UTC)
+	for _, tc := range []struct {
+		spec    string
+		want    time.Time
+		wantErr bool
+	}{
+		{spec: "* * * * *", want: time.Date(2022, time.June, 15, 10, 31, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", want: time.Date(2022, time.June, 15, 10, 45, 0, 0, time.UTC)},
+		{spec: "0 3 * * *", want: time.Date(2022, time.June, 16, 3, 0, 0, 0, time.UTC)},
+		{spec: "@daily", want: time.Date(2022, time.June, 16, 0, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", want: time.Date(2022, time.June, 15, 11, 0, 0, 0, time.UTC)},
+		{spec: "@yearly", want: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "30 10 * * mon-fri", want: time.Date(2022, time.June, 16, 10, 30, 0, 0, time.UTC)},
+		{spec: "0 0 * * 7", want: time.Date(2022, time.June, 19, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * sun", want: time.Date(2022, time.June, 19, 0, 0, 0, 0, time.UTC)},
+		{spec: "5-10/5 12 1 Jan,jul *", want: time.Date(2022, time.July, 1, 12, 5, 0, 0, time.UTC)},
+		// Either the day of month or the day of week.
+		{spec: "0 0 20 * 5", want: time.Date(2022, time.June, 17, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 1,16 * 0", want: time.Date(2022, time.June, 16, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 30 2 *", want: time.Time{}},
+		{spec: "* * * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "* * 0 * *", wantErr: true},
+		{spec: "5-1 * * * *", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "* * * foo *", wantErr: true},
+		{spec: "@sometimes", wantErr: true},
+	} {
+		tc := tc
+		t.Run(tc.spec, func(t *testing.T) {
+			s, err := parseSchedule(tc.spec)
+			if got := err != nil; got != tc.wantErr {
+				t.Fatalf("parseSchedule: unexpected error state. got %v want %v err %v", got, tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got := s.next(from); !got.Equal(tc.want) {
+				t.Errorf("next(%v) = %v, want %v", from, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestListCrontabs(t *testing.T) {
+	from := time.Date(2022, time.June, 15, 10, 30, 45, 0, time.Local)
+	savedNow, savedCrontab, savedDir, savedUserDirs := now, *systemCrontab, *systemDir, userDirs
+	t.Cleanup(func() {
+		now = savedNow
+		*systemCrontab = savedCrontab
+		*systemDir = savedDir
+		userDirs = savedUserDirs
+	})
+	now = func() time.Time { return from }
+
+	tmp := t.TempDir()
+	*systemCrontab = filepath.Join(tmp, "crontab")
+	*systemDir = filepath.Join(tmp, "cron.d")
+	users := filepath.Join(tmp, "crontabs")
+	userDirs = []string{users, filepath.Join(tmp, "missing")}
+	for _, d := range []string{*systemDir, users} {
+		testutil.FatalOnErr("mkdir", os.Mkdir(d, 0755), t)
+	}
+	files := map[string]string{
+		*systemCrontab: `SHELL=/bin/sh
+PATH = /usr/local/sbin:/usr/bin
+
+# m h dom mon dow user	command
+17 *	* * *	root    cd / && run-parts --report /etc/cron.hourly
+`,
+		filepath.Join(*systemDir, "cleanup"):  "@daily nobody /usr/local/bin/legacy-cleanup  --all\n",
+		filepath.Join(*systemDir, ".hidden"):  "* * * * * root /bin/hidden\n",
+		filepath.Join(*systemDir, "cleanup~"): "* * * * * root /bin/backup\n",
+		filepath.Join(users, "alice"):         "@reboot /home/alice/start\n*/5 * * * *\n61 * * * * /bin/true\n",
+	}
+	for f, contents := range files {
+		testutil.FatalOnErr("writing crontab", os.WriteFile(f, []byte(contents), 0644), t)
+	}
+
+	got, err := (&server{}).ListCrontabs(context.Background(), &pb.ListCrontabsRequest{})
+	testutil.FatalOnErr("ListCrontabs", err, t)
+	want := &pb.ListCrontabsReply{
+		Entries: []*pb.CronEntry{
+			{
+				File:     *systemCrontab,
+				Line:     5,
+				User:     "root",
+				Schedule: "17 * * * *",
+				Command:  "cd / && run-parts --report /etc/cron.hourly",
+				NextRun:  timestamppb.New(time.Date(2022, time.June, 15, 11, 17, 0, 0, time.Local)),
+			},
+			{
+				File:     filepath.Join(*systemDir, "cleanup"),
+				Line:     1,
+				User:     "nobody",
+				Schedule: "@daily",
+				Command:  "/usr/local/bin/legacy-cleanup  --all",
+				NextRun:  timestamppb.New(time.Date(2022, time.June, 16, 0, 0, 0, 0, time.Local)),
+			},
+			{
+				File:     filepath.Join(users, "alice"),
+				Line:     1,
+				User:     "alice",
+				Schedule: "@reboot",
+				Command:  "/home/alice/start",
+			},
+			{
+				File:    filepath.Join(users, "alice"),
+				Line:    2,
+				User:    "alice",
+				Command: "*/5 * * * *",
+				Error:   "too few fields",
+			},
+			{
+				File:     filepath.Join(users, "alice"),
+				Line:     3,
+				User:     "alice",
+				Schedule: "61 * * * *",
+				Command:  "/bin/true",
+				Error:    `invalid minute "61"`,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected diff (-want +got):\n%s", diff)
+	}
+}
@@ -21,18 +21,21 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/Snowflake-Labs/sansshell/client"
 	pb "github.com/Snowflake-Labs/sansshell/services/exec"
 	"github.com/Snowflake-Labs/sansshell/services/util"
 	"github.com/google/subcommands"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 const subPackage = "exec"
 
 func init() {
-	subcommands.Register(&execCmd{}, subPackage)
+	client.RegisterSubpackage(subPackage, setup)
 }
 
 func setup(f *flag.FlagSet) *subcommands.Commander {
@@ -41,24 +44,18 @@ func setup(f *flag.FlagSet) *subcommands.Commander {
 	return c
 }
 
-type execCmd struct{}
-
-func (*execCmd) Name() string { return subPackage }
-func (p *execCmd) Synopsis() string {
-	return client.GenerateSynopsis(setup(flag.NewFlagSet("", flag.ContinueOnError)))
-}
-func (p *execCmd) Usage() string {
-	return client.GenerateUsage(subPackage, p.Synopsis())
-}
-func (*execCmd) SetFlags(f *flag.FlagSet) {}
-
-func (p *execCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
-	c := setup(f)
-	return c.Execute(ctx, args...)
+type runCmd struct {
+	stream       bool
+	toFile       bool
+	stdin        string
+	timeout      time.Duration
+	maxOutput    int64
+	nice         int
+	cgroupCPU    int64
+	cgroupMemory int64
+	user         string
 }
 
-type runCmd struct{}
-
 func (*runCmd) Name() string     { return "run" }
 func (*runCmd) Synopsis() string { return "Run provided command and return a response." }
 func (*runCmd) Usage() string {
@@ -67,11 +64,46 @@ func (*runCmd) Usage() string {
 
 	Note: This is not optimized for large output or long running commands.  If
 	the output doesn't fit in memory in a single proto message or if it doesnt
-	complete within the timeout, you'll have a bad time.
+	complete within the timeout, you'll have a bad time. Use --stream for those
+	which returns output as it's produced. With --stdin the given input is sent
+	to the command on every target. With --to-file output is instead written
+	to files on each target whose paths, sizes and checksums are printed, so
+	large output can be fetched selectively later (i.e. with file read).
 `
 }
 
-func (p *runCmd) SetFlags(f *flag.FlagSet) {}
+func (p *runCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&p.stream, "stream", false, "If true stream output back as it's produced rather than once the command completes")
+	f.BoolVar(&p.toFile, "to-file", false, "If true write output to files on the target and print their paths rather than returning it")
+	f.StringVar(&p.stdin, "stdin", "", "If set send the contents of this file (or - for standard input) as stdin of the command. Implies --stream.")
+	f.DurationVar(&p.timeout, "timeout", 0, "If set kill the command if it runs longer than this")
+	f.Int64Var(&p.maxOutput, "max-output", 0, "If positive kill the command once it writes more than this many bytes of output")
+	f.IntVar(&p.nice, "nice", 0, "Niceness to run the command with (-20 to 19)")
+	f.Int64Var(&p.cgroupCPU, "cgroup-cpu-millis", 0, "If positive limit the command to this many thousandths of a CPU using a cgroup")
+	f.Int64Var(&p.cgroupMemory, "cgroup-memory", 0, "If positive limit the command to this many bytes of memory using a cgroup")
+	f.StringVar(&p.user, "user", "", "If set run the command as this user (name or uid) on the target rather than the server's user")
+}
+
+// request returns the ExecRequest for running args with the limits from flags.
+func (p *runCmd) request(args []string) *pb.ExecRequest {
+	req := &pb.ExecRequest{
+		Command:        args[0],
+		Args:           args[1:],
+		MaxOutputBytes: p.maxOutput,
+		Nice:           int32(p.nice),
+		User:           p.user,
+	}
+	if p.timeout > 0 {
+		req.Timeout = durationpb.New(p.timeout)
+	}
+	if p.cgroupCPU > 0 || p.cgroupMemory > 0 {
+		req.Cgroup = &pb.CgroupLimits{
+			CpuMillis:   p.cgroupCPU,
+			MemoryBytes: p.cgroupMemory,
+		}
+	}
+	return req
+}
 
 func (p *runCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
 	state := args[0].(*util.ExecuteState)
@@ -80,9 +112,33 @@ func (p *runCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface
 		return subcommands.ExitUsageError
 	}
 
+	if p.toFile && (p.stream || p.stdin != "") {
+		fmt.Fprintln(os.Stderr, "--to-file can't be combined with --stream or --stdin.")
+		return subcommands.ExitUsageError
+	}
+
 	c := pb.NewExecClientProxy(state.Conn)
+	if p.toFile {
+		return runToFile(ctx, c, state, p.request(f.Args()))
+	}
+	if p.stdin != "" {
+		in := os.Stdin
+		if p.stdin != "-" {
+			var err error
+			in, err = os.Open(p.stdin)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Can't open %s - %v\n", p.stdin, err)
+				return subcommands.ExitFailure
+			}
+			defer in.Close()
+		}
+		return streamingRunWithInput(ctx, c, state, p.request(f.Args()), in)
+	}
+	if p.stream {
+		return streamingRun(ctx, c, state, p.request(f.Args()))
+	}
 
-	resp, err := c.RunOneMany(ctx, &pb.ExecRequest{Command: f.Args()[0], Args: f.Args()[1:]})
+	resp, err := c.RunOneMany(ctx, p.request(f.Args()))
 	if err != nil {
 		// Emit this to every error file as it's not specific to a given target.
 		for _, e := range state.Err {
@@ -105,3 +161,187 @@ func (p *runCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface
 	}
 	return returnCode
 }
+
+// runToFile runs req with RunToFile, printing where each target wrote
+// the output.
+func runToFile(ctx context.Context, c pb.ExecClientProxy, state *util.ExecuteState, req *pb.ExecRequest) subcommands.ExitStatus {
+	resp, err := c.RunToFileOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute due to likely program failure: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	returnCode := subcommands.ExitSuccess
+	for r := range resp {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "Command execution failure for target %s (%d) - error - %v\n", r.Target, r.Index, r.Error)
+			returnCode = subcommands.ExitFailure
+			continue
+		}
+		for _, f := range []struct {
+			name string
+			file *pb.OutputFile
+		}{
+			{"stdout", r.Resp.Stdout},
+			{"stderr", r.Resp.Stderr},
+		} {
+			fmt.Fprintf(state.Out[r.Index], "%s: %s %d bytes sha256 %s\n", f.name, f.file.GetPath(), f.file.GetSize(), f.file.GetSha256())
+		}
+		if r.Resp.RetCode != 0 {
+			fmt.Fprintf(state.Err[r.Index], "Command for target %s (%d) exited with code %d\n", r.Target, r.Index, r.Resp.RetCode)
+		}
+	}
+	return returnCode
+}
+
+// streamOutput writes the output of a streaming command for each target
+// as it arrives.
+type streamOutput struct {
+	state      *util.ExecuteState
+	returnCode subcommands.ExitStatus
+	// The last response from each target has its exit code.
+	last    map[int]*pb.ExecResponse
+	targets map[int]string
+}
+
+func newStreamOutput(state *util.ExecuteState) *streamOutput {
+	return &streamOutput{
+		state:      state,
+		returnCode: subcommands.ExitSuccess,
+		last:       make(map[int]*pb.ExecResponse),
+		targets:    make(map[int]string),
+	}
+}
+
+// add processes a single response from a target.
+func (o *streamOutput) add(idx int, target string, resp *pb.ExecResponse, err error) {
+	if err != nil && err != io.EOF {
+		fmt.Fprintf(o.state.Err[idx], "Command execution failure for target %s (%d) - error - %v\n", target, idx, err)
+		delete(o.last, idx)
+		o.returnCode = subcommands.ExitFailure
+		return
+	}
+	if resp == nil {
+		return
+	}
+	o.state.Err[idx].Write(resp.Stderr)
+	o.state.Out[idx].Write(resp.Stdout)
+	o.last[idx] = resp
+	o.targets[idx] = target
+}
+
+// streamError reports an error for the whole stream.
+func (o *streamOutput) streamError(err error) {
+	// Emit this to every error file as it's not specific to a given target.
+	for _, e := range o.state.Err {
+		fmt.Fprintf(e, "Stream error: %v\n", err)
+	}
+	o.returnCode = subcommands.ExitFailure
+}
+
+// finish reports non-zero exit codes and returns the overall status.
+func (o *streamOutput) finish() subcommands.ExitStatus {
+	for idx, r := range o.last {
+		if r.RetCode != 0 {
+			fmt.Fprintf(o.state.Err[idx], "Command for target %s (%d) exited with code %d\n", o.targets[idx], idx, r.RetCode)
+		}
+	}
+	return o.returnCode
+}
+
+// streamingRun runs req with StreamingRun, writing output for each target as
+// it arrives.
+func streamingRun(ctx context.Context, c pb.ExecClientProxy, state *util.ExecuteState, req *pb.ExecRequest) subcommands.ExitStatus {
+	stream, err := c.StreamingRunOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute due to likely program failure: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	out := newStreamOutput(state)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			out.streamError(err)
+			break
+		}
+		for _, r := range resp {
+			out.add(r.Index, r.Target, r.Resp, r.Error)
+		}
+	}
+	return out.finish()
+}
+
+// streamingRunWithInput runs req with StreamingRunWithInput sending the
+// contents of stdin to every target.
+func streamingRunWithInput(ctx context.Context, c pb.ExecClientProxy, state *util.ExecuteState, req *pb.ExecRequest, stdin io.Reader) subcommands.ExitStatus {
+	stream, err := c.StreamingRunWithInputOneMany(ctx)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute due to likely program failure: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	out := newStreamOutput(state)
+	if err := stream.Send(&pb.ExecInput{Input: &pb.ExecInput_Request{Request: req}}); err != nil {
+		out.streamError(err)
+		return out.finish()
+	}
+	// Send stdin while receiving output so neither side can block the other.
+	sendErr := make(chan error, 1)
+	go func() {
+		buf := make([]byte, util.StreamingChunkSize)
+		for {
+			n, err := stdin.Read(buf)
+			if n > 0 {
+				if err := stream.Send(&pb.ExecInput{Input: &pb.ExecInput_Stdin{Stdin: buf[:n]}}); err != nil {
+					sendErr <- err
+					return
+				}
+			}
+			if err == io.EOF {
+				sendErr <- stream.CloseSend()
+				return
+			}
+			if err != nil {
+				sendErr <- fmt.Errorf("can't read stdin: %v", err)
+				return
+			}
+		}
+	}()
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			out.streamError(err)
+			break
+		}
+		for _, r := range resp {
+			out.add(r.Index, r.Target, r.Resp, r.Error)
+		}
+	}
+	select {
+	case err := <-sendErr:
+		// Sending stops once every target is done (i.e. the command
+		// exited without reading everything) which isn't an error.
+		if err != nil && err != io.EOF {
+			out.streamError(err)
+		}
+	default:
+	}
+	return out.finish()
+}
@@ -20,6 +20,20 @@ const _ = grpc.SupportPackageIsVersion7
 type ExecClient interface {
 	// Run takes input, executes it and returns result of input execution
 	Run(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*ExecResponse, error)
+	// StreamingRun takes input, executes it and streams back its output as
+	// it's produced. Each response contains a chunk of stdout and/or stderr
+	// and the final one contains the exit code.
+	StreamingRun(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (Exec_StreamingRunClient, error)
+	// StreamingRunWithInput is StreamingRun but also streams stdin for the
+	// command from the client. The first message must be the request and any
+	// following ones contain stdin. Closing the stream closes stdin.
+	StreamingRunWithInput(ctx context.Context, opts ...grpc.CallOption) (Exec_StreamingRunWithInputClient, error)
+	// RunToFile executes the command writing its stdout and stderr to files
+	// on the target (under the server's --exec-output-dir) rather than
+	// returning them, for commands with too much output to stream. The files
+	// can be fetched selectively later, i.e. with LocalFile.Read, and must be
+	// removed by the caller once no longer needed.
+	RunToFile(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*RunToFileResponse, error)
 }
 
 type execClient struct {
@@ -39,12 +53,98 @@ func (c *execClient) Run(ctx context.Context, in *ExecRequest, opts ...grpc.Call
 	return out, nil
 }
 
+func (c *execClient) StreamingRun(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (Exec_StreamingRunClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Exec_ServiceDesc.Streams[0], "/Exec.Exec/StreamingRun", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &execStreamingRunClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Exec_StreamingRunClient interface {
+	Recv() (*ExecResponse, error)
+	grpc.ClientStream
+}
+
+type execStreamingRunClient struct {
+	grpc.ClientStream
+}
+
+func (x *execStreamingRunClient) Recv() (*ExecResponse, error) {
+	m := new(ExecResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *execClient) StreamingRunWithInput(ctx context.Context, opts ...grpc.CallOption) (Exec_StreamingRunWithInputClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Exec_ServiceDesc.Streams[1], "/Exec.Exec/StreamingRunWithInput", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &execStreamingRunWithInputClient{stream}
+	return x, nil
+}
+
+type Exec_StreamingRunWithInputClient interface {
+	Send(*ExecInput) error
+	Recv() (*ExecResponse, error)
+	grpc.ClientStream
+}
+
+type execStreamingRunWithInputClient struct {
+	grpc.ClientStream
+}
+
+func (x *execStreamingRunWithInputClient) Send(m *ExecInput) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *execStreamingRunWithInputClient) Recv() (*ExecResponse, error) {
+	m := new(ExecResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *execClient) RunToFile(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*RunToFileResponse, error) {
+	out := new(RunToFileResponse)
+	err := c.cc.Invoke(ctx, "/Exec.Exec/RunToFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ExecServer is the server API for Exec service.
 // All implementations should embed UnimplementedExecServer
 // for forward compatibility
 type ExecServer interface {
 	// Run takes input, executes it and returns result of input execution
 	Run(context.Context, *ExecRequest) (*ExecResponse, error)
+	// StreamingRun takes input, executes it and streams back its output as
+	// it's produced. Each response contains a chunk of stdout and/or stderr
+	// and the final one contains the exit code.
+	StreamingRun(*ExecRequest, Exec_StreamingRunServer) error
+	// StreamingRunWithInput is StreamingRun but also streams stdin for the
+	// command from the client. The first message must be the request and any
+	// following ones contain stdin. Closing the stream closes stdin.
+	StreamingRunWithInput(Exec_StreamingRunWithInputServer) error
+	// RunToFile executes the command writing its stdout and stderr to files
+	// on the target (under the server's --exec-output-dir) rather than
+	// returning them, for commands with too much output to stream. The files
+	// can be fetched selectively later, i.e. with LocalFile.Read, and must be
+	// removed by the caller once no longer needed.
+	RunToFile(context.Context, *ExecRequest) (*RunToFileResponse, error)
 }
 
 // UnimplementedExecServer should be embedded to have forward compatible implementations.
@@ -54,6 +154,15 @@ type UnimplementedExecServer struct {
 func (UnimplementedExecServer) Run(context.Context, *ExecRequest) (*ExecResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Run not implemented")
 }
+func (UnimplementedExecServer) StreamingRun(*ExecRequest, Exec_StreamingRunServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamingRun not implemented")
+}
+func (UnimplementedExecServer) StreamingRunWithInput(Exec_StreamingRunWithInputServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamingRunWithInput not implemented")
+}
+func (UnimplementedExecServer) RunToFile(context.Context, *ExecRequest) (*RunToFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunToFile not implemented")
+}
 
 // UnsafeExecServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to ExecServer will
@@ -84,6 +193,71 @@ func _Exec_Run_Handler(srv interface{}, ctx context.Context, dec func(interface{
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Exec_StreamingRun_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExecServer).StreamingRun(m, &execStreamingRunServer{stream})
+}
+
+type Exec_StreamingRunServer interface {
+	Send(*ExecResponse) error
+	grpc.ServerStream
+}
+
+type execStreamingRunServer struct {
+	grpc.ServerStream
+}
+
+func (x *execStreamingRunServer) Send(m *ExecResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Exec_StreamingRunWithInput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ExecServer).StreamingRunWithInput(&execStreamingRunWithInputServer{stream})
+}
+
+type Exec_StreamingRunWithInputServer interface {
+	Send(*ExecResponse) error
+	Recv() (*ExecInput, error)
+	grpc.ServerStream
+}
+
+type execStreamingRunWithInputServer struct {
+	grpc.ServerStream
+}
+
+func (x *execStreamingRunWithInputServer) Send(m *ExecResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *execStreamingRunWithInputServer) Recv() (*ExecInput, error) {
+	m := new(ExecInput)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Exec_RunToFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecServer).RunToFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Exec.Exec/RunToFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecServer).RunToFile(ctx, req.(*ExecRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Exec_ServiceDesc is the grpc.ServiceDesc for Exec service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -95,7 +269,23 @@ var Exec_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Run",
 			Handler:    _Exec_Run_Handler,
 		},
+		{
+			MethodName: "RunToFile",
+			Handler:    _Exec_RunToFile_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamingRun",
+			Handler:       _Exec_StreamingRun_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamingRunWithInput",
+			Handler:       _Exec_StreamingRunWithInput_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "exec.proto",
 }
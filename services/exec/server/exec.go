@@ -19,11 +19,30 @@ package server
 
 import (
 	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"sync"
+	"syscall"
 
 	"github.com/Snowflake-Labs/sansshell/services"
 	pb "github.com/Snowflake-Labs/sansshell/services/exec"
 	"github.com/Snowflake-Labs/sansshell/services/util"
+	"github.com/go-logr/logr"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// cpuPeriod is the cpu.max period (in microseconds) limits are expressed in.
+	cpuPeriod = 100000
+	// minCPUQuota is the smallest cpu.max quota (in microseconds) the kernel
+	// accepts.
+	minCPUQuota = 1000
 )
 
 // server is used to implement the gRPC server
@@ -31,20 +50,308 @@ type server struct{}
 
 // Run executes command and returns result
 func (s *server) Run(ctx context.Context, req *pb.ExecRequest) (res *pb.ExecResponse, err error) {
-	run, err := util.RunCommand(ctx, req.Command, req.Args)
+	stdout := util.NewLimitedBuffer(util.DefRunBufLimit)
+	stderr := util.NewLimitedBuffer(util.DefRunBufLimit)
+	exitCode, err := runCommand(ctx, req, stdout, stderr, nil)
 	if err != nil {
 		return nil, err
 	}
+	return &pb.ExecResponse{Stderr: stderr.Bytes(), Stdout: stdout.Bytes(), RetCode: int32(exitCode)}, nil
+}
+
+// responseSender is the common part of the Exec streaming servers.
+type responseSender interface {
+	Send(*pb.ExecResponse) error
+}
+
+// streamWriter sends everything written to it as stdout or stderr
+// on an ExecResponse stream. The mutex is shared between the stdout and
+// stderr writers of a command as a stream can't be sent on concurrently.
+type streamWriter struct {
+	mu     *sync.Mutex
+	stream responseSender
+	stderr bool
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	resp := &pb.ExecResponse{Stdout: p}
+	if w.stderr {
+		resp = &pb.ExecResponse{Stderr: p}
+	}
+	if err := w.stream.Send(resp); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// StreamingRun executes command and streams back its output as it's produced.
+func (s *server) StreamingRun(req *pb.ExecRequest, stream pb.Exec_StreamingRunServer) error {
+	return streamingRun(stream.Context(), req, stream, nil)
+}
+
+// StreamingRunWithInput is StreamingRun with stdin also streamed from the client.
+func (s *server) StreamingRunWithInput(stream pb.Exec_StreamingRunWithInputServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	in, err := stream.Recv()
+	if err != nil {
+		return status.Errorf(codes.Internal, "recv error %v", err)
+	}
+	req := in.GetRequest()
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "must send a request first")
+	}
 
-	if err := run.Error; err != nil {
-		return &pb.ExecResponse{
-			Stdout:  run.Stdout.Bytes(),
-			Stderr:  run.Stderr.Bytes(),
-			RetCode: int32(run.ExitCode),
-		}, nil
+	// Anything wrong with the input kills the command and is returned
+	// instead of its exit code.
+	inputErr := make(chan error, 1)
+	stdin := func(w io.WriteCloser) {
+		defer w.Close()
+		for {
+			in, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				inputErr <- status.Errorf(codes.Internal, "recv error %v", err)
+				cancel()
+				return
+			}
+			if in.GetRequest() != nil {
+				inputErr <- status.Error(codes.InvalidArgument, "can't send multiple requests")
+				cancel()
+				return
+			}
+			// The command may exit without reading everything. That isn't
+			// an error but there's no point sending more.
+			if _, err := w.Write(in.GetStdin()); err != nil {
+				return
+			}
+		}
 	}
+	err = streamingRun(ctx, req, stream, stdin)
+	select {
+	case ierr := <-inputErr:
+		return ierr
+	default:
+	}
+	return err
+}
+
+// streamingRun runs req sending its output on stream as it's produced. If
+// stdin is non-nil it's run in a goroutine with the command's stdin, which
+// it must close when done.
+func streamingRun(ctx context.Context, req *pb.ExecRequest, stream responseSender, stdin func(io.WriteCloser)) error {
+	mu := &sync.Mutex{}
+	exitCode, err := runCommand(ctx, req, &streamWriter{mu: mu, stream: stream}, &streamWriter{mu: mu, stream: stream, stderr: true}, stdin)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&pb.ExecResponse{RetCode: int32(exitCode)}); err != nil {
+		return status.Errorf(codes.Internal, "can't send exit code: %v", err)
+	}
+	return nil
+}
+
+// outputLimiter counts the bytes written through it (by any number of
+// writers) and cancels the command once there are more than max.
+type outputLimiter struct {
+	max    int64
+	cancel func()
+
+	mu       sync.Mutex
+	n        int64
+	exceeded bool
+}
+
+type limitedWriter struct {
+	l *outputLimiter
+	w io.Writer
+}
 
-	return &pb.ExecResponse{Stderr: run.Stderr.Bytes(), Stdout: run.Stdout.Bytes(), RetCode: 0}, nil
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	l := lw.l
+	l.mu.Lock()
+	l.n += int64(len(p))
+	if l.n > l.max {
+		l.exceeded = true
+	}
+	exceeded := l.exceeded
+	l.mu.Unlock()
+	if exceeded {
+		l.cancel()
+		return 0, errOutputLimit
+	}
+	return lw.w.Write(p)
+}
+
+var errOutputLimit = errors.New("output limit exceeded")
+
+// validateRequest checks the limits in req are sane.
+func validateRequest(req *pb.ExecRequest) error {
+	if err := util.ValidPath(req.Command); err != nil {
+		return err
+	}
+	if req.Timeout != nil {
+		if err := req.Timeout.CheckValid(); err != nil || req.Timeout.AsDuration() <= 0 {
+			return status.Errorf(codes.InvalidArgument, "invalid timeout %v", req.Timeout.AsDuration())
+		}
+	}
+	if req.MaxOutputBytes < 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid max_output_bytes %d", req.MaxOutputBytes)
+	}
+	if req.Nice < -20 || req.Nice > 19 {
+		return status.Errorf(codes.InvalidArgument, "nice %d must be between -20 and 19", req.Nice)
+	}
+	if c := req.Cgroup; c != nil {
+		if c.CpuMillis < 0 || c.MemoryBytes < 0 {
+			return status.Errorf(codes.InvalidArgument, "invalid cgroup limits %v", c)
+		}
+		if c.CpuMillis > 0 && c.CpuMillis*cpuPeriod/1000 < minCPUQuota {
+			return status.Errorf(codes.InvalidArgument, "cpu_millis %d is below the minimum of %d", c.CpuMillis, minCPUQuota*1000/cpuPeriod)
+		}
+	}
+	return nil
+}
+
+// credential returns the credential to run a command as username (or
+// numeric uid) with. It's nil if that's the user the server is already
+// running as so non-root servers can still run commands as themselves.
+func credential(username string) (*syscall.Credential, error) {
+	u, err := user.Lookup(username)
+	if _, ok := err.(user.UnknownUserError); ok {
+		u, err = user.LookupId(username)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown user %s: %v", username, err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't parse uid %s of %s: %v", u.Uid, username, err)
+	}
+	if int(uid) == os.Getuid() {
+		return nil, nil
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't parse gid %s of %s: %v", u.Gid, username, err)
+	}
+	groupIds, err := u.GroupIds()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't get groups of %s: %v", username, err)
+	}
+	cred := &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}
+	for _, g := range groupIds {
+		id, err := strconv.ParseUint(g, 10, 32)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't parse group %s of %s: %v", g, username, err)
+		}
+		cred.Groups = append(cred.Groups, uint32(id))
+	}
+	return cred, nil
+}
+
+// runCommand runs req with the given stdout and stderr, applying any
+// limits in the request, and returns its exit code. If stdin is non-nil
+// it's run in a goroutine with the command's stdin, which it must close
+// when done.
+//
+// As with util.RunCommand failures to start or wait on the command are only
+// reflected in the exit code. Errors are returned for invalid requests,
+// exceeded limits and cancellation.
+func runCommand(ctx context.Context, req *pb.ExecRequest, stdout, stderr io.Writer, stdin func(io.WriteCloser)) (int, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	if err := validateRequest(req); err != nil {
+		return 0, err
+	}
+
+	cmdCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if req.Timeout != nil {
+		cmdCtx, cancel = context.WithTimeout(cmdCtx, req.Timeout.AsDuration())
+		defer cancel()
+	}
+	var limiter *outputLimiter
+	if req.MaxOutputBytes > 0 {
+		limiter = &outputLimiter{max: req.MaxOutputBytes, cancel: cancel}
+		stdout = &limitedWriter{l: limiter, w: stdout}
+		stderr = &limitedWriter{l: limiter, w: stderr}
+	}
+
+	var cred *syscall.Credential
+	if req.User != "" {
+		var err error
+		if cred, err = credential(req.User); err != nil {
+			return 0, err
+		}
+	}
+
+	var cg *cgroup
+	if req.Cgroup != nil {
+		var err error
+		if cg, err = newCgroup(req.Cgroup); err != nil {
+			return 0, err
+		}
+		defer func() {
+			if err := cg.remove(); err != nil {
+				logger.Error(err, "can't remove cgroup")
+			}
+		}()
+	}
+
+	cmd := exec.CommandContext(cmdCtx, req.Command, req.Args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Set to an empty slice to get an empty environment. Nil means inherit.
+	cmd.Env = []string{}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Credential: cred}
+	if cg != nil {
+		cg.attach(cmd.SysProcAttr)
+	}
+	if stdin != nil {
+		// Use a pipe rather than setting Stdin so Wait doesn't wait on
+		// the client to finish sending input the command won't read.
+		w, err := cmd.StdinPipe()
+		if err != nil {
+			return 0, status.Errorf(codes.Internal, "can't create stdin pipe: %v", err)
+		}
+		defer w.Close()
+		go stdin(w)
+	}
+
+	logger.Info("executing local command", "cmd", cmd.String(), "user", req.User)
+	// Nice and (where attach is supported) the cgroup apply from the moment
+	// the process is created so nothing the command runs escapes them.
+	if err := startCommand(cmd, int(req.Nice)); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return 0, err
+		}
+		if cred != nil && errors.Is(err, syscall.EPERM) {
+			return 0, status.Errorf(codes.PermissionDenied, "can't run command as %s: %v", req.User, err)
+		}
+		return cmd.ProcessState.ExitCode(), nil
+	}
+	if cg != nil {
+		if err := cg.add(cmd.Process.Pid); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return 0, err
+		}
+	}
+	cmd.Wait()
+
+	switch {
+	case limiter != nil && limiter.exceeded:
+		return 0, status.Errorf(codes.ResourceExhausted, "command output exceeded %d bytes", req.MaxOutputBytes)
+	case ctx.Err() != nil:
+		return 0, status.FromContextError(ctx.Err()).Err()
+	case cmdCtx.Err() == context.DeadlineExceeded:
+		return 0, status.Errorf(codes.DeadlineExceeded, "command timed out after %v", req.Timeout.AsDuration())
+	}
+	return cmd.ProcessState.ExitCode(), nil
 }
 
 // Register is called to expose this handler to the gRPC server
@@ -0,0 +1,62 @@
+//go:build !linux
+// +build !linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/exec"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// startCommand starts cmd with the given niceness. There's no way to nice
+// only the new process before it runs here, so it's run with nice(1).
+func startCommand(cmd *exec.Cmd, nice int) error {
+	if nice == 0 {
+		return cmd.Start()
+	}
+	path, err := exec.LookPath("nice")
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't find nice: %v", err)
+	}
+	cmd.Args = append([]string{path, "-n", strconv.Itoa(nice), "--", cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = path
+	return cmd.Start()
+}
+
+// cgroup is unsupported on this platform.
+type cgroup struct{}
+
+func newCgroup(limits *pb.CgroupLimits) (*cgroup, error) {
+	return nil, status.Error(codes.Unimplemented, "cgroup limits not supported")
+}
+
+func (c *cgroup) attach(attr *syscall.SysProcAttr) {}
+
+func (c *cgroup) add(pid int) error {
+	return nil
+}
+
+func (c *cgroup) remove() error {
+	return nil
+}
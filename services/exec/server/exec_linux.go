@@ -0,0 +1,141 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/exec"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// cgroupRemoveTimeout is how long remove waits for a cgroup's
+	// processes to exit once they've been killed.
+	cgroupRemoveTimeout = 5 * time.Second
+	// cgroupRemoveInterval is how often remove retries meanwhile.
+	cgroupRemoveInterval = 10 * time.Millisecond
+)
+
+var (
+	cgroupParent = flag.String("exec-cgroup-parent", "", "A cgroup v2 directory (i.e. /sys/fs/cgroup/sansshell) delegated to the server, under which commands with cgroup limits are run. If empty such commands are rejected.")
+
+	cgroupCount uint64
+)
+
+// startCommand starts cmd with the given niceness. On Linux niceness
+// belongs to a thread and is inherited by processes it creates, so it's set
+// on a thread used only to start cmd. The command and everything it runs
+// are niced from the start without affecting the server.
+func startCommand(cmd *exec.Cmd, nice int) error {
+	if nice == 0 {
+		return cmd.Start()
+	}
+	errc := make(chan error, 1)
+	go func() {
+		// The thread is never unlocked so it exits with this goroutine
+		// rather than running others with the changed niceness.
+		runtime.LockOSThread()
+		if err := unix.Setpriority(unix.PRIO_PROCESS, 0, nice); err != nil {
+			errc <- status.Errorf(codes.Internal, "can't set nice %d: %v", nice, err)
+			return
+		}
+		errc <- cmd.Start()
+	}()
+	return <-errc
+}
+
+// cgroup is a cgroup v2 group created for a single command.
+type cgroup struct {
+	dir string
+	// f is the open group, which commands are created in where supported
+	// (see attach).
+	f *os.File
+}
+
+// newCgroup creates a cgroup under --exec-cgroup-parent with the given limits.
+func newCgroup(limits *pb.CgroupLimits) (*cgroup, error) {
+	if *cgroupParent == "" {
+		return nil, status.Error(codes.FailedPrecondition, "cgroup limits requested but no --exec-cgroup-parent is configured")
+	}
+	name := fmt.Sprintf("exec-%d-%d", os.Getpid(), atomic.AddUint64(&cgroupCount, 1))
+	cg := &cgroup{dir: filepath.Join(*cgroupParent, name)}
+	if err := os.Mkdir(cg.dir, 0755); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't create cgroup: %v", err)
+	}
+	if limits.CpuMillis > 0 {
+		quota := limits.CpuMillis * cpuPeriod / 1000
+		if err := cg.write("cpu.max", fmt.Sprintf("%d %d", quota, cpuPeriod)); err != nil {
+			cg.remove()
+			return nil, err
+		}
+	}
+	if limits.MemoryBytes > 0 {
+		if err := cg.write("memory.max", strconv.FormatInt(limits.MemoryBytes, 10)); err != nil {
+			cg.remove()
+			return nil, err
+		}
+	}
+	f, err := os.Open(cg.dir)
+	if err != nil {
+		cg.remove()
+		return nil, status.Errorf(codes.Internal, "can't open cgroup: %v", err)
+	}
+	cg.f = f
+	return cg, nil
+}
+
+func (c *cgroup) write(file string, value string) error {
+	if err := os.WriteFile(filepath.Join(c.dir, file), []byte(value), 0); err != nil {
+		return status.Errorf(codes.Internal, "can't set cgroup %s: %v", file, err)
+	}
+	return nil
+}
+
+// remove kills anything left in the cgroup (i.e. children the command left
+// running) and removes it.
+func (c *cgroup) remove() error {
+	if c.f != nil {
+		c.f.Close()
+	}
+	c.write("cgroup.kill", "1")
+	// Killing is asynchronous and the group can't be removed until
+	// everything in it has exited.
+	deadline := time.Now().Add(cgroupRemoveTimeout)
+	for {
+		err := os.Remove(c.dir)
+		if err == nil || !errors.Is(err, syscall.EBUSY) || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(cgroupRemoveInterval)
+	}
+}
@@ -0,0 +1,92 @@
+//go:build linux && go1.20
+// +build linux,go1.20
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/exec"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestCgroupPlacement(t *testing.T) {
+	// This needs a real cgroup v2 hierarchy the test can create groups in.
+	var parent string
+	for _, root := range []string{"/sys/fs/cgroup", "/sys/fs/cgroup/unified"} {
+		if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err != nil {
+			continue
+		}
+		dir := filepath.Join(root, fmt.Sprintf("sansshell-test-%d", os.Getpid()))
+		if err := os.Mkdir(dir, 0755); err == nil {
+			parent = dir
+			break
+		}
+	}
+	if parent == "" {
+		t.Skip("no writable cgroup v2 hierarchy")
+	}
+	t.Cleanup(func() { os.Remove(parent) })
+	saved := *cgroupParent
+	*cgroupParent = parent
+	t.Cleanup(func() { *cgroupParent = saved })
+
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewExecClient(conn)
+	// The command is in the group from the start, so even the first thing
+	// it does runs there.
+	resp, err := client.Run(ctx, &pb.ExecRequest{
+		Command: testutil.ResolvePath(t, "cat"),
+		Args:    []string{"/proc/self/cgroup"},
+		Cgroup:  &pb.CgroupLimits{},
+	})
+	testutil.FatalOnErr("Run", err, t)
+	want := regexp.MustCompile(`(?m)^0::.*/` + regexp.QuoteMeta(filepath.Base(parent)) + `/exec-[0-9]+-[0-9]+$`)
+	if !want.Match(resp.Stdout) {
+		t.Errorf("command ran in cgroups %q, want match for %s", resp.Stdout, want)
+	}
+
+	// Anything the command leaves running is killed so the group can be
+	// removed.
+	_, err = client.Run(ctx, &pb.ExecRequest{
+		Command: testutil.ResolvePath(t, "sh"),
+		Args:    []string{"-c", "sleep 100 >/dev/null 2>&1 &"},
+		Cgroup:  &pb.CgroupLimits{},
+	})
+	testutil.FatalOnErr("Run", err, t)
+	groups, err := os.ReadDir(parent)
+	testutil.FatalOnErr("ReadDir", err, t)
+	for _, g := range groups {
+		if g.IsDir() {
+			t.Errorf("cgroup %s wasn't removed", g.Name())
+		}
+	}
+}
@@ -0,0 +1,35 @@
+//go:build linux && !go1.20
+// +build linux,!go1.20
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"strconv"
+	"syscall"
+)
+
+// attach does nothing as processes can only be created in a cgroup from
+// Go 1.20. They're moved there by add once started instead, so anything
+// the command does before then isn't limited.
+func (c *cgroup) attach(attr *syscall.SysProcAttr) {}
+
+// add moves pid into the cgroup.
+func (c *cgroup) add(pid int) error {
+	return c.write("cgroup.procs", strconv.Itoa(pid))
+}
@@ -0,0 +1,46 @@
+//go:build linux && !go1.20
+// +build linux,!go1.20
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/exec"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestCgroupAdd(t *testing.T) {
+	saved := *cgroupParent
+	*cgroupParent = t.TempDir()
+	t.Cleanup(func() { *cgroupParent = saved })
+
+	cg, err := newCgroup(&pb.CgroupLimits{})
+	testutil.FatalOnErr("newCgroup", err, t)
+	t.Cleanup(cg.remove)
+	testutil.FatalOnErr("add", cg.add(1234), t)
+	b, err := os.ReadFile(filepath.Join(cg.dir, "cgroup.procs"))
+	testutil.FatalOnErr("ReadFile", err, t)
+	if got, want := string(b), strconv.Itoa(1234); got != want {
+		t.Errorf("cgroup.procs contains %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/exec"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestCgroupLimits(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewExecClient(conn)
+	req := &pb.ExecRequest{
+		Command: testutil.ResolvePath(t, "true"),
+		Cgroup:  &pb.CgroupLimits{CpuMillis: 500, MemoryBytes: 1 << 20},
+	}
+
+	_, err = client.Run(ctx, req)
+	if got, want := status.Code(err), codes.FailedPrecondition; got != want {
+		t.Fatalf("unexpected code without a parent. got %v want %v err %v", got, want, err)
+	}
+
+	// A plain directory stands in for cgroupfs. As removing the group
+	// fails on a normal filesystem what was written can be checked.
+	parent := t.TempDir()
+	saved := *cgroupParent
+	*cgroupParent = parent
+	t.Cleanup(func() { *cgroupParent = saved })
+
+	cg, err := newCgroup(req.Cgroup)
+	testutil.FatalOnErr("newCgroup", err, t)
+	cg.remove()
+	for file, want := range map[string]string{
+		"cpu.max":     "^50000 100000$",
+		"memory.max":  "^1048576$",
+		"cgroup.kill": "^1$",
+	} {
+		b, err := os.ReadFile(filepath.Join(cg.dir, file))
+		testutil.FatalOnErr("ReadFile", err, t)
+		if !regexp.MustCompile(want).Match(b) {
+			t.Errorf("%s contains %q, want match for %s", file, b, want)
+		}
+	}
+}
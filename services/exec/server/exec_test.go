@@ -17,17 +17,28 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"log"
 	"net"
 	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	pb "github.com/Snowflake-Labs/sansshell/services/exec"
 	"github.com/Snowflake-Labs/sansshell/testing/testutil"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 var (
@@ -115,3 +126,491 @@ func TestExec(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamingRun(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewExecClient(conn)
+	sh := testutil.ResolvePath(t, "sh")
+
+	for _, tc := range []struct {
+		name       string
+		bin        string
+		args       []string
+		wantErr    bool
+		returnCode int32
+		stdout     string
+		stderr     string
+	}{
+		{
+			name:       "stdout, stderr and exit code",
+			bin:        sh,
+			args:       []string{"-c", "echo out; echo err >&2; exit 3"},
+			returnCode: 3,
+			stdout:     "out\n",
+			stderr:     "err\n",
+		},
+		{
+			name:       "Non-existant program",
+			bin:        "/something/non-existant",
+			returnCode: -1,
+		},
+		{
+			name:    "non-absolute path",
+			bin:     "foo",
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := client.StreamingRun(ctx, &pb.ExecRequest{
+				Command: tc.bin,
+				Args:    tc.args,
+			})
+			testutil.FatalOnErr("StreamingRun", err, t)
+			var stdout, stderr bytes.Buffer
+			var last *pb.ExecResponse
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if tc.wantErr {
+					testutil.WantErr(tc.name, err, tc.wantErr, t)
+					return
+				}
+				testutil.FatalOnErr("Recv", err, t)
+				stdout.Write(resp.Stdout)
+				stderr.Write(resp.Stderr)
+				last = resp
+			}
+			if tc.wantErr {
+				t.Fatal("expected an error, got none")
+			}
+			if got, want := stdout.String(), tc.stdout; got != want {
+				t.Fatalf("stdout doesn't match. Want %q Got %q", want, got)
+			}
+			if got, want := stderr.String(), tc.stderr; got != want {
+				t.Fatalf("stderr doesn't match. Want %q Got %q", want, got)
+			}
+			if last == nil || last.RetCode != tc.returnCode {
+				t.Fatalf("final response %+v, want return code %d", last, tc.returnCode)
+			}
+		})
+	}
+}
+
+func TestStreamingRunIsIncremental(t *testing.T) {
+	var err error
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewExecClient(conn)
+	// The command doesn't exit until it's killed so the output has to be
+	// sent before it does.
+	stream, err := client.StreamingRun(ctx, &pb.ExecRequest{
+		Command: testutil.ResolvePath(t, "sh"),
+		Args:    []string{"-c", "echo first; exec sleep 60"},
+	})
+	testutil.FatalOnErr("StreamingRun", err, t)
+	resp, err := stream.Recv()
+	testutil.FatalOnErr("Recv", err, t)
+	if got, want := string(resp.Stdout), "first\n"; got != want {
+		t.Fatalf("first response stdout %q, want %q", got, want)
+	}
+	cancel()
+	for {
+		if _, err := stream.Recv(); err != nil {
+			if status.Code(err) != codes.Canceled {
+				t.Fatalf("got %v after cancel, want Canceled", err)
+			}
+			break
+		}
+	}
+}
+
+func TestStreamingRunWithInput(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewExecClient(conn)
+	request := func(bin string, args ...string) *pb.ExecInput {
+		return &pb.ExecInput{Input: &pb.ExecInput_Request{Request: &pb.ExecRequest{Command: bin, Args: args}}}
+	}
+	stdin := func(s string) *pb.ExecInput {
+		return &pb.ExecInput{Input: &pb.ExecInput_Stdin{Stdin: []byte(s)}}
+	}
+
+	for _, tc := range []struct {
+		name string
+		in   []*pb.ExecInput
+		// If true the stream is left open for sending.
+		noClose    bool
+		wantCode   codes.Code
+		returnCode int32
+		stdout     string
+	}{
+		{
+			name:   "stdin to stdout",
+			in:     []*pb.ExecInput{request(testutil.ResolvePath(t, "cat")), stdin("hello\n"), stdin("world\n")},
+			stdout: "hello\nworld\n",
+		},
+		{
+			name: "exit status",
+			in: []*pb.ExecInput{
+				request(testutil.ResolvePath(t, "sh"), "-c", "read x; echo got $x; exit 2"),
+				stdin("input\n"),
+			},
+			returnCode: 2,
+			stdout:     "got input\n",
+		},
+		{
+			name:    "command doesn't read stdin",
+			in:      []*pb.ExecInput{request(testutil.ResolvePath(t, "echo"), "done"), stdin("ignored")},
+			noClose: true,
+			stdout:  "done\n",
+		},
+		{
+			name:     "stdin first",
+			in:       []*pb.ExecInput{stdin("hello")},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "multiple requests",
+			in:       []*pb.ExecInput{request(testutil.ResolvePath(t, "cat")), request(testutil.ResolvePath(t, "cat"))},
+			noClose:  true,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "non-absolute path",
+			in:       []*pb.ExecInput{request("cat")},
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			stream, err := client.StreamingRunWithInput(ctx)
+			testutil.FatalOnErr("StreamingRunWithInput", err, t)
+			for _, in := range tc.in {
+				if err := stream.Send(in); err != nil {
+					// The server may have already returned an error.
+					break
+				}
+			}
+			if !tc.noClose {
+				testutil.FatalOnErr("CloseSend", stream.CloseSend(), t)
+			}
+			var stdout bytes.Buffer
+			var last *pb.ExecResponse
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if got, want := status.Code(err), tc.wantCode; got != want {
+						t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+					}
+					return
+				}
+				stdout.Write(resp.Stdout)
+				last = resp
+			}
+			if tc.wantCode != codes.OK {
+				t.Fatalf("expected code %v, got success", tc.wantCode)
+			}
+			if got, want := stdout.String(), tc.stdout; got != want {
+				t.Fatalf("stdout doesn't match. Want %q Got %q", want, got)
+			}
+			if last == nil || last.RetCode != tc.returnCode {
+				t.Fatalf("final response %+v, want return code %d", last, tc.returnCode)
+			}
+		})
+	}
+}
+
+func TestLimits(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewExecClient(conn)
+	sh := testutil.ResolvePath(t, "sh")
+	spew := []string{"-c", "while true; do echo 0123456789; done"}
+
+	for _, tc := range []struct {
+		name      string
+		req       *pb.ExecRequest
+		streaming bool
+		wantCode  codes.Code
+		stdout    string
+	}{
+		{
+			name:     "timeout",
+			req:      &pb.ExecRequest{Command: sh, Args: []string{"-c", "exec sleep 60"}, Timeout: durationpb.New(100 * time.Millisecond)},
+			wantCode: codes.DeadlineExceeded,
+		},
+		{
+			name:      "timeout streaming",
+			req:       &pb.ExecRequest{Command: sh, Args: []string{"-c", "exec sleep 60"}, Timeout: durationpb.New(100 * time.Millisecond)},
+			streaming: true,
+			wantCode:  codes.DeadlineExceeded,
+		},
+		{
+			name:   "within timeout",
+			req:    &pb.ExecRequest{Command: sh, Args: []string{"-c", "echo ok"}, Timeout: durationpb.New(time.Minute)},
+			stdout: "ok\n",
+		},
+		{
+			name:     "negative timeout",
+			req:      &pb.ExecRequest{Command: sh, Timeout: durationpb.New(-time.Second)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "output limit",
+			req:      &pb.ExecRequest{Command: sh, Args: spew, MaxOutputBytes: 1000},
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name:      "output limit streaming",
+			req:       &pb.ExecRequest{Command: sh, Args: spew, MaxOutputBytes: 1000},
+			streaming: true,
+			wantCode:  codes.ResourceExhausted,
+		},
+		{
+			name:   "within output limit",
+			req:    &pb.ExecRequest{Command: sh, Args: []string{"-c", "echo ok; echo err >&2"}, MaxOutputBytes: 7},
+			stdout: "ok\n",
+		},
+		{
+			name:   "nice",
+			req:    &pb.ExecRequest{Command: testutil.ResolvePath(t, "nice"), Nice: 5},
+			stdout: "5\n",
+		},
+		{
+			name:   "nice inherited",
+			req:    &pb.ExecRequest{Command: sh, Args: []string{"-c", "nice; true"}, Nice: 5},
+			stdout: "5\n",
+		},
+		{
+			name:     "invalid nice",
+			req:      &pb.ExecRequest{Command: sh, Nice: 20},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "cpu limit below minimum",
+			req:      &pb.ExecRequest{Command: sh, Cgroup: &pb.CgroupLimits{CpuMillis: 5}},
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Now()
+			var stdout []byte
+			if tc.streaming {
+				stream, err := client.StreamingRun(ctx, tc.req)
+				testutil.FatalOnErr("StreamingRun", err, t)
+				for {
+					resp, err := stream.Recv()
+					if err != nil {
+						if err == io.EOF {
+							err = nil
+						}
+						if got, want := status.Code(err), tc.wantCode; got != want {
+							t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+						}
+						break
+					}
+					stdout = append(stdout, resp.Stdout...)
+				}
+			} else {
+				resp, err := client.Run(ctx, tc.req)
+				if got, want := status.Code(err), tc.wantCode; got != want {
+					t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+				}
+				stdout = resp.GetStdout()
+			}
+			if time.Since(start) > 30*time.Second {
+				t.Fatalf("command took %v, limits weren't enforced", time.Since(start))
+			}
+			if tc.wantCode == codes.OK {
+				if got, want := string(stdout), tc.stdout; got != want {
+					t.Fatalf("stdout doesn't match. Want %q Got %q", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestUser(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewExecClient(conn)
+	id := testutil.ResolvePath(t, "id")
+	current, err := user.Current()
+	testutil.FatalOnErr("user.Current", err, t)
+
+	for _, tc := range []struct {
+		name     string
+		user     string
+		wantCode codes.Code
+		wantUID  string
+	}{
+		{
+			name:    "current user by name",
+			user:    current.Username,
+			wantUID: current.Uid,
+		},
+		{
+			name:    "current user by uid",
+			user:    current.Uid,
+			wantUID: current.Uid,
+		},
+		{
+			name:     "unknown user",
+			user:     "sansshell-no-such-user",
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := client.Run(ctx, &pb.ExecRequest{Command: id, Args: []string{"-u"}, User: tc.user})
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+			}
+			if tc.wantCode != codes.OK {
+				return
+			}
+			if got, want := string(resp.Stdout), tc.wantUID+"\n"; got != want {
+				t.Fatalf("wrong uid. Want %q Got %q", want, got)
+			}
+		})
+	}
+
+	// Actually switching users needs root.
+	if os.Getuid() != 0 {
+		return
+	}
+	nobody, err := user.Lookup("nobody")
+	if err != nil {
+		t.Skipf("no nobody user: %v", err)
+	}
+	resp, err := client.Run(ctx, &pb.ExecRequest{Command: id, Args: []string{"-u"}, User: "nobody"})
+	testutil.FatalOnErr("Run as nobody", err, t)
+	if got, want := string(resp.Stdout), nobody.Uid+"\n"; got != want {
+		t.Fatalf("wrong uid running as nobody. Want %q Got %q", want, got)
+	}
+}
+
+func TestRunToFile(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	savedOutputDir := *outputDir
+	t.Cleanup(func() { *outputDir = savedOutputDir })
+	*outputDir = filepath.Join(t.TempDir(), "output")
+
+	client := pb.NewExecClient(conn)
+	sh := testutil.ResolvePath(t, "sh")
+	sum := func(s string) string {
+		h := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(h[:])
+	}
+
+	for _, tc := range []struct {
+		name     string
+		req      *pb.ExecRequest
+		stdout   string
+		stderr   string
+		retCode  int32
+		wantCode codes.Code
+	}{
+		{
+			name:   "output",
+			req:    &pb.ExecRequest{Command: sh, Args: []string{"-c", "echo out; echo err >&2"}},
+			stdout: "out\n",
+			stderr: "err\n",
+		},
+		{
+			name:    "exit code",
+			req:     &pb.ExecRequest{Command: sh, Args: []string{"-c", "echo out; exit 3"}},
+			stdout:  "out\n",
+			retCode: 3,
+		},
+		{
+			name:     "output limit",
+			req:      &pb.ExecRequest{Command: sh, Args: []string{"-c", "while true; do echo 0123456789; done"}, MaxOutputBytes: 100},
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name:     "invalid request",
+			req:      &pb.ExecRequest{Command: "sh"},
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			before, _ := os.ReadDir(*outputDir)
+			resp, err := client.RunToFile(ctx, tc.req)
+			if status.Code(err) != tc.wantCode {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.wantCode)
+			}
+			if err != nil {
+				// Nothing is left behind on failure.
+				if after, _ := os.ReadDir(*outputDir); len(after) != len(before) {
+					t.Errorf("output directory has %d entries after failure, want %d", len(after), len(before))
+				}
+				return
+			}
+			if resp.RetCode != tc.retCode {
+				t.Errorf("got exit code %d want %d", resp.RetCode, tc.retCode)
+			}
+			for _, f := range []struct {
+				name string
+				got  *pb.OutputFile
+				want string
+			}{
+				{"stdout", resp.Stdout, tc.stdout},
+				{"stderr", resp.Stderr, tc.stderr},
+			} {
+				if !strings.HasPrefix(f.got.Path, *outputDir+"/") {
+					t.Errorf("%s path %s isn't under %s", f.name, f.got.Path, *outputDir)
+				}
+				contents, err := os.ReadFile(f.got.Path)
+				testutil.FatalOnErr("reading "+f.name, err, t)
+				if string(contents) != f.want {
+					t.Errorf("%s contains %q want %q", f.name, contents, f.want)
+				}
+				if f.got.Size != int64(len(f.want)) || f.got.Sha256 != sum(f.want) {
+					t.Errorf("%s described as %d bytes with sum %s, want %d bytes with sum %s", f.name, f.got.Size, f.got.Sha256, len(f.want), sum(f.want))
+				}
+				fi, err := os.Stat(f.got.Path)
+				testutil.FatalOnErr("stat "+f.name, err, t)
+				if got := fi.Mode().Perm(); got != 0600 {
+					t.Errorf("%s has mode %v, want 0600", f.name, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"hash"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/exec"
+)
+
+var outputDir = flag.String("exec-output-dir", "/var/lib/sansshell/exec-output", "Directory Exec.RunToFile writes command output under, one subdirectory per command.")
+
+// outputFile writes command output to a file while hashing it.
+type outputFile struct {
+	f   *os.File
+	h   hash.Hash
+	n   int64
+	err error
+}
+
+func newOutputFile(name string) (*outputFile, error) {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't create output file: %v", err)
+	}
+	return &outputFile{f: f, h: sha256.New()}, nil
+}
+
+func (o *outputFile) Write(p []byte) (int, error) {
+	n, err := o.f.Write(p)
+	o.h.Write(p[:n])
+	o.n += int64(n)
+	if err != nil && o.err == nil {
+		o.err = err
+	}
+	return n, err
+}
+
+// finish closes the file and returns its description.
+func (o *outputFile) finish() (*pb.OutputFile, error) {
+	// A write error (i.e. a full disk) means output is missing.
+	if o.err != nil {
+		return nil, status.Errorf(codes.Internal, "can't write %s: %v", o.f.Name(), o.err)
+	}
+	if err := o.f.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't write %s: %v", o.f.Name(), err)
+	}
+	return &pb.OutputFile{
+		Path:   o.f.Name(),
+		Size:   o.n,
+		Sha256: hex.EncodeToString(o.h.Sum(nil)),
+	}, nil
+}
+
+// RunToFile executes command writing its output to files under --exec-output-dir.
+func (s *server) RunToFile(ctx context.Context, req *pb.ExecRequest) (_ *pb.RunToFileResponse, retErr error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	// Don't leave anything behind for invalid requests.
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(*outputDir, 0700); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't create output directory: %v", err)
+	}
+	dir, err := os.MkdirTemp(*outputDir, "run-")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't create output directory: %v", err)
+	}
+	defer func() {
+		// Output of failed commands isn't returned so remove it.
+		if retErr != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+	stdout, err := newOutputFile(filepath.Join(dir, "stdout"))
+	if err != nil {
+		return nil, err
+	}
+	defer stdout.f.Close()
+	stderr, err := newOutputFile(filepath.Join(dir, "stderr"))
+	if err != nil {
+		return nil, err
+	}
+	defer stderr.f.Close()
+
+	logger.Info("writing command output", "dir", dir)
+	exitCode, err := runCommand(ctx, req, stdout, stderr, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.RunToFileResponse{RetCode: int32(exitCode)}
+	if resp.Stdout, err = stdout.finish(); err != nil {
+		return nil, err
+	}
+	if resp.Stderr, err = stderr.finish(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,256 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'fdb'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/subcommands"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/fdb"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "fdb"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&coordinatorsCmd{}, "")
+	c.Register(&excludeCmd{}, "")
+	c.Register(&includeCmd{}, "")
+	c.Register(&statusCmd{}, "")
+	return c
+}
+
+type statusCmd struct {
+	json bool
+}
+
+func (*statusCmd) Name() string     { return "status" }
+func (*statusCmd) Synopsis() string { return "Print the cluster status" }
+func (*statusCmd) Usage() string {
+	return `status [--json]:
+    Print a summary of the cluster status as seen from the target, or with
+    --json the full output of status json.
+`
+}
+
+func (s *statusCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&s.json, "json", false, "Print the full status json")
+}
+
+func (s *statusCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewFDBClientProxy(state.Conn)
+	respChan, err := c.StatusOneMany(ctx, &pb.StatusRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "Status for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[r.Index]
+		if s.json {
+			fmt.Fprintln(out, strings.TrimSpace(r.Resp.Json))
+			continue
+		}
+		fmt.Fprintf(out, "database available: %t\n", r.Resp.DatabaseAvailable)
+		fmt.Fprintf(out, "healthy: %t\n", r.Resp.Healthy)
+		fmt.Fprintf(out, "data state: %s\n", r.Resp.DataState)
+		fmt.Fprintf(out, "redundancy mode: %s\n", r.Resp.RedundancyMode)
+		fmt.Fprintf(out, "machines: %d\n", r.Resp.Machines)
+		fmt.Fprintf(out, "processes: %d\n", r.Resp.Processes)
+		fmt.Fprintf(out, "coordinators quorum reachable: %t\n", r.Resp.CoordinatorsQuorumReachable)
+		if len(r.Resp.ExcludedServers) > 0 {
+			fmt.Fprintf(out, "excluded servers: %s\n", strings.Join(r.Resp.ExcludedServers, " "))
+		}
+		if len(r.Resp.Messages) > 0 {
+			fmt.Fprintf(out, "messages: %s\n", strings.Join(r.Resp.Messages, " "))
+		}
+	}
+	return retCode
+}
+
+type coordinatorsCmd struct {
+	auto        bool
+	description string
+}
+
+func (*coordinatorsCmd) Name() string     { return "coordinators" }
+func (*coordinatorsCmd) Synopsis() string { return "Print or change the cluster coordinators" }
+func (*coordinatorsCmd) Usage() string {
+	return `coordinators [--auto] [--description=X] [address...]:
+    Print the cluster description and coordinators. If --auto, addresses or
+    --description are given the coordinators (and/or description) are changed
+    first. Only target one host in the cluster when changing them.
+`
+}
+
+func (c *coordinatorsCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&c.auto, "auto", false, "Let the cluster choose a new set of coordinators")
+	f.StringVar(&c.description, "description", "", "A new cluster description")
+}
+
+func (c *coordinatorsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if c.auto && f.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "--auto can't be combined with addresses.")
+		return subcommands.ExitUsageError
+	}
+	proxy := pb.NewFDBClientProxy(state.Conn)
+	req := &pb.CoordinatorsRequest{
+		Addresses:   f.Args(),
+		Auto:        c.auto,
+		Description: c.description,
+	}
+	respChan, err := proxy.CoordinatorsOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "Coordinators for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[r.Index], "description: %s\n", r.Resp.Description)
+		fmt.Fprintf(state.Out[r.Index], "coordinators: %s\n", strings.Join(r.Resp.Addresses, ","))
+	}
+	return retCode
+}
+
+type excludeCmd struct {
+	failed bool
+	noWait bool
+	force  bool
+}
+
+func (*excludeCmd) Name() string     { return "exclude" }
+func (*excludeCmd) Synopsis() string { return "Exclude servers from the cluster" }
+func (*excludeCmd) Usage() string {
+	return `exclude [--failed] [--no-wait] [--force] address [address...]:
+    Exclude the servers at the given addresses (ip or ip:port) so data is
+    moved off them. Without --no-wait this waits for data movement to finish,
+    so consider raising --timeout.
+`
+}
+
+func (e *excludeCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&e.failed, "failed", false, "The servers are permanently failed. Their data isn't copied off them first.")
+	f.BoolVar(&e.noWait, "no-wait", false, "Return without waiting for data movement")
+	f.BoolVar(&e.force, "force", false, "Exclude even if it may reduce fault tolerance or lose data")
+}
+
+func (e *excludeCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Please specify at least one address.")
+		return subcommands.ExitUsageError
+	}
+	c := pb.NewFDBClientProxy(state.Conn)
+	req := &pb.ExcludeRequest{
+		Addresses: f.Args(),
+		Failed:    e.failed,
+		NoWait:    e.noWait,
+		Force:     e.force,
+	}
+	respChan, err := c.ExcludeOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "Exclude for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprint(state.Out[r.Index], r.Resp.Output)
+	}
+	return retCode
+}
+
+type includeCmd struct {
+	all bool
+}
+
+func (*includeCmd) Name() string     { return "include" }
+func (*includeCmd) Synopsis() string { return "Include previously excluded servers" }
+func (*includeCmd) Usage() string {
+	return `include --all | address [address...]:
+    Remove the given addresses, or with --all every address, from the
+    exclusion list so they can hold data again.
+`
+}
+
+func (i *includeCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&i.all, "all", false, "Include all excluded servers")
+}
+
+func (i *includeCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if i.all == (f.NArg() > 0) {
+		fmt.Fprintln(os.Stderr, "Please specify either --all or addresses.")
+		return subcommands.ExitUsageError
+	}
+	c := pb.NewFDBClientProxy(state.Conn)
+	respChan, err := c.IncludeOneMany(ctx, &pb.IncludeRequest{Addresses: f.Args(), All: i.all})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "Include for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprint(state.Out[r.Index], r.Resp.Output)
+	}
+	return retCode
+}
@@ -0,0 +1,219 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package fdb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// FDBClient is the client API for FDB service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FDBClient interface {
+	// Status returns the output of `status json` and a summary of it.
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error)
+	// Coordinators returns the cluster's coordinators, first changing them if
+	// requested.
+	Coordinators(ctx context.Context, in *CoordinatorsRequest, opts ...grpc.CallOption) (*CoordinatorsReply, error)
+	// Exclude excludes servers from the cluster, by default waiting for data
+	// to be moved off them.
+	Exclude(ctx context.Context, in *ExcludeRequest, opts ...grpc.CallOption) (*ExcludeReply, error)
+	// Include includes previously excluded servers.
+	Include(ctx context.Context, in *IncludeRequest, opts ...grpc.CallOption) (*IncludeReply, error)
+}
+
+type fDBClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFDBClient(cc grpc.ClientConnInterface) FDBClient {
+	return &fDBClient{cc}
+}
+
+func (c *fDBClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error) {
+	out := new(StatusReply)
+	err := c.cc.Invoke(ctx, "/FDB.FDB/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fDBClient) Coordinators(ctx context.Context, in *CoordinatorsRequest, opts ...grpc.CallOption) (*CoordinatorsReply, error) {
+	out := new(CoordinatorsReply)
+	err := c.cc.Invoke(ctx, "/FDB.FDB/Coordinators", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fDBClient) Exclude(ctx context.Context, in *ExcludeRequest, opts ...grpc.CallOption) (*ExcludeReply, error) {
+	out := new(ExcludeReply)
+	err := c.cc.Invoke(ctx, "/FDB.FDB/Exclude", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fDBClient) Include(ctx context.Context, in *IncludeRequest, opts ...grpc.CallOption) (*IncludeReply, error) {
+	out := new(IncludeReply)
+	err := c.cc.Invoke(ctx, "/FDB.FDB/Include", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FDBServer is the server API for FDB service.
+// All implementations should embed UnimplementedFDBServer
+// for forward compatibility
+type FDBServer interface {
+	// Status returns the output of `status json` and a summary of it.
+	Status(context.Context, *StatusRequest) (*StatusReply, error)
+	// Coordinators returns the cluster's coordinators, first changing them if
+	// requested.
+	Coordinators(context.Context, *CoordinatorsRequest) (*CoordinatorsReply, error)
+	// Exclude excludes servers from the cluster, by default waiting for data
+	// to be moved off them.
+	Exclude(context.Context, *ExcludeRequest) (*ExcludeReply, error)
+	// Include includes previously excluded servers.
+	Include(context.Context, *IncludeRequest) (*IncludeReply, error)
+}
+
+// UnimplementedFDBServer should be embedded to have forward compatible implementations.
+type UnimplementedFDBServer struct {
+}
+
+func (UnimplementedFDBServer) Status(context.Context, *StatusRequest) (*StatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedFDBServer) Coordinators(context.Context, *CoordinatorsRequest) (*CoordinatorsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Coordinators not implemented")
+}
+func (UnimplementedFDBServer) Exclude(context.Context, *ExcludeRequest) (*ExcludeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Exclude not implemented")
+}
+func (UnimplementedFDBServer) Include(context.Context, *IncludeRequest) (*IncludeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Include not implemented")
+}
+
+// UnsafeFDBServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FDBServer will
+// result in compilation errors.
+type UnsafeFDBServer interface {
+	mustEmbedUnimplementedFDBServer()
+}
+
+func RegisterFDBServer(s grpc.ServiceRegistrar, srv FDBServer) {
+	s.RegisterService(&FDB_ServiceDesc, srv)
+}
+
+func _FDB_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FDBServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/FDB.FDB/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FDBServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FDB_Coordinators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FDBServer).Coordinators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/FDB.FDB/Coordinators",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FDBServer).Coordinators(ctx, req.(*CoordinatorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FDB_Exclude_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExcludeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FDBServer).Exclude(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/FDB.FDB/Exclude",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FDBServer).Exclude(ctx, req.(*ExcludeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FDB_Include_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncludeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FDBServer).Include(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/FDB.FDB/Include",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FDBServer).Include(ctx, req.(*IncludeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FDB_ServiceDesc is the grpc.ServiceDesc for FDB service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FDB_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "FDB.FDB",
+	HandlerType: (*FDBServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Status",
+			Handler:    _FDB_Status_Handler,
+		},
+		{
+			MethodName: "Coordinators",
+			Handler:    _FDB_Coordinators_Handler,
+		},
+		{
+			MethodName: "Exclude",
+			Handler:    _FDB_Exclude_Handler,
+		},
+		{
+			MethodName: "Include",
+			Handler:    _FDB_Include_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "fdb.proto",
+}
@@ -0,0 +1,304 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package fdb
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// FDBClientProxy is the superset of FDBClient which additionally includes the OneMany proxy methods
+type FDBClientProxy interface {
+	FDBClient
+	StatusOneMany(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (<-chan *StatusManyResponse, error)
+	CoordinatorsOneMany(ctx context.Context, in *CoordinatorsRequest, opts ...grpc.CallOption) (<-chan *CoordinatorsManyResponse, error)
+	ExcludeOneMany(ctx context.Context, in *ExcludeRequest, opts ...grpc.CallOption) (<-chan *ExcludeManyResponse, error)
+	IncludeOneMany(ctx context.Context, in *IncludeRequest, opts ...grpc.CallOption) (<-chan *IncludeManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type fDBClientProxy struct {
+	*fDBClient
+}
+
+// NewFDBClientProxy creates a FDBClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewFDBClientProxy(cc *proxy.Conn) FDBClientProxy {
+	return &fDBClientProxy{NewFDBClient(cc).(*fDBClient)}
+}
+
+// StatusManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type StatusManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *StatusReply
+	Error error
+}
+
+// StatusOneMany provides the same API as Status but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *fDBClientProxy) StatusOneMany(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (<-chan *StatusManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *StatusManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &StatusManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &StatusReply{},
+			}
+			err := conn.Invoke(ctx, "/FDB.FDB/Status", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/FDB.FDB/Status", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &StatusManyResponse{
+				Resp: &StatusReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// CoordinatorsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type CoordinatorsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *CoordinatorsReply
+	Error error
+}
+
+// CoordinatorsOneMany provides the same API as Coordinators but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *fDBClientProxy) CoordinatorsOneMany(ctx context.Context, in *CoordinatorsRequest, opts ...grpc.CallOption) (<-chan *CoordinatorsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *CoordinatorsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &CoordinatorsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &CoordinatorsReply{},
+			}
+			err := conn.Invoke(ctx, "/FDB.FDB/Coordinators", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/FDB.FDB/Coordinators", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &CoordinatorsManyResponse{
+				Resp: &CoordinatorsReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ExcludeManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ExcludeManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ExcludeReply
+	Error error
+}
+
+// ExcludeOneMany provides the same API as Exclude but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *fDBClientProxy) ExcludeOneMany(ctx context.Context, in *ExcludeRequest, opts ...grpc.CallOption) (<-chan *ExcludeManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ExcludeManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ExcludeManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ExcludeReply{},
+			}
+			err := conn.Invoke(ctx, "/FDB.FDB/Exclude", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/FDB.FDB/Exclude", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ExcludeManyResponse{
+				Resp: &ExcludeReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// IncludeManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type IncludeManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *IncludeReply
+	Error error
+}
+
+// IncludeOneMany provides the same API as Include but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *fDBClientProxy) IncludeOneMany(ctx context.Context, in *IncludeRequest, opts ...grpc.CallOption) (<-chan *IncludeManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *IncludeManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &IncludeManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &IncludeReply{},
+			}
+			err := conn.Invoke(ctx, "/FDB.FDB/Include", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/FDB.FDB/Include", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &IncludeManyResponse{
+				Resp: &IncludeReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
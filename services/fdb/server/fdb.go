@@ -0,0 +1,276 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'FDB' service.
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/fdb"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+var (
+	fdbcliBin   = flag.String("fdbcli-bin", "/usr/bin/fdbcli", "Path to the fdbcli binary")
+	clusterFile = flag.String("fdb-cluster-file", "/etc/foundationdb/fdb.cluster", "The cluster file fdbcli connects with")
+	timeout     = flag.Duration("fdbcli-timeout", 30*time.Second, "Timeout passed to fdbcli for each command")
+
+	// validAddress matches server addresses: an IPv4 address or bracketed
+	// IPv6 one, with an optional port and :tls suffix.
+	validAddress = regexp.MustCompile(`^(\[[0-9A-Fa-f:.]+\]|[0-9.]+)(:[0-9]+(:tls)?)?$`)
+	// validDescription matches cluster descriptions.
+	validDescription = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+	// i.e. Cluster coordinators (3): 10.0.0.1:4500,10.0.0.2:4500,10.0.0.3:4500
+	coordinatorsRE = regexp.MustCompile(`^Cluster coordinators \(\d+\): (.*)$`)
+)
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// fdbcli runs command (with arguments already validated) with fdbcli and
+// returns its output.
+func fdbcli(ctx context.Context, command ...string) (string, error) {
+	args := []string{
+		"-C", *clusterFile,
+		"--timeout", strconv.Itoa(int((*timeout + time.Second - 1) / time.Second)),
+		"--exec", strings.Join(command, " "),
+	}
+	logr.FromContextOrDiscard(ctx).Info("running fdbcli", "command", command)
+	run, err := util.RunCommand(ctx, *fdbcliBin, args)
+	if err != nil {
+		return "", err
+	}
+	if err := run.Error; run.ExitCode != 0 || err != nil {
+		// fdbcli reports most errors on stdout.
+		out := strings.TrimSpace(run.Stdout.String() + "\n" + run.Stderr.String())
+		return "", status.Errorf(codes.Internal, "fdbcli %s failed: %v: %s", command[0], err, util.TrimString(out))
+	}
+	return run.Stdout.String(), nil
+}
+
+// checkAddresses validates server addresses passed to fdbcli.
+func checkAddresses(addresses []string) error {
+	for _, a := range addresses {
+		if !validAddress.MatchString(a) {
+			return status.Errorf(codes.InvalidArgument, "invalid address %q", a)
+		}
+	}
+	return nil
+}
+
+// clusterStatus is the subset of status json summarized in the reply.
+type clusterStatus struct {
+	Client struct {
+		DatabaseStatus struct {
+			Available bool `json:"available"`
+			Healthy   bool `json:"healthy"`
+		} `json:"database_status"`
+		Coordinators struct {
+			QuorumReachable bool `json:"quorum_reachable"`
+		} `json:"coordinators"`
+		Messages []struct {
+			Name string `json:"name"`
+		} `json:"messages"`
+	} `json:"client"`
+	Cluster struct {
+		Data struct {
+			State struct {
+				Name string `json:"name"`
+			} `json:"state"`
+		} `json:"data"`
+		Configuration struct {
+			RedundancyMode  string `json:"redundancy_mode"`
+			ExcludedServers []struct {
+				Address string `json:"address"`
+			} `json:"excluded_servers"`
+		} `json:"configuration"`
+		Machines  map[string]json.RawMessage `json:"machines"`
+		Processes map[string]json.RawMessage `json:"processes"`
+		Messages  []struct {
+			Name string `json:"name"`
+		} `json:"messages"`
+	} `json:"cluster"`
+}
+
+// parseStatus summarizes the output of status json.
+func parseStatus(out string) (*pb.StatusReply, error) {
+	var s clusterStatus
+	if err := json.Unmarshal([]byte(out), &s); err != nil {
+		return nil, fmt.Errorf("can't parse status json: %v", err)
+	}
+	reply := &pb.StatusReply{
+		Json:                        out,
+		DatabaseAvailable:           s.Client.DatabaseStatus.Available,
+		Healthy:                     s.Client.DatabaseStatus.Healthy,
+		DataState:                   s.Cluster.Data.State.Name,
+		RedundancyMode:              s.Cluster.Configuration.RedundancyMode,
+		Machines:                    int32(len(s.Cluster.Machines)),
+		Processes:                   int32(len(s.Cluster.Processes)),
+		CoordinatorsQuorumReachable: s.Client.Coordinators.QuorumReachable,
+	}
+	for _, e := range s.Cluster.Configuration.ExcludedServers {
+		reply.ExcludedServers = append(reply.ExcludedServers, e.Address)
+	}
+	for _, m := range s.Client.Messages {
+		reply.Messages = append(reply.Messages, m.Name)
+	}
+	for _, m := range s.Cluster.Messages {
+		reply.Messages = append(reply.Messages, m.Name)
+	}
+	return reply, nil
+}
+
+// Status implements pb.FDBServer.Status
+func (s *server) Status(ctx context.Context, req *pb.StatusRequest) (*pb.StatusReply, error) {
+	out, err := fdbcli(ctx, "status", "json")
+	if err != nil {
+		return nil, err
+	}
+	reply, err := parseStatus(out)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return reply, nil
+}
+
+// parseCoordinators parses the output of the coordinators command.
+func parseCoordinators(out string) (*pb.CoordinatorsReply, error) {
+	reply := &pb.CoordinatorsReply{}
+	found := false
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "Cluster description:") {
+			reply.Description = strings.TrimSpace(strings.TrimPrefix(line, "Cluster description:"))
+		}
+		if m := coordinatorsRE.FindStringSubmatch(line); m != nil {
+			found = true
+			for _, a := range strings.Split(m[1], ",") {
+				if a = strings.TrimSpace(a); a != "" {
+					reply.Addresses = append(reply.Addresses, a)
+				}
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no coordinators in output %q", util.TrimString(out))
+	}
+	return reply, nil
+}
+
+// Coordinators implements pb.FDBServer.Coordinators
+func (s *server) Coordinators(ctx context.Context, req *pb.CoordinatorsRequest) (*pb.CoordinatorsReply, error) {
+	if req.Auto && len(req.Addresses) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "auto and addresses are mutually exclusive")
+	}
+	if err := checkAddresses(req.Addresses); err != nil {
+		return nil, err
+	}
+	if req.Description != "" && !validDescription.MatchString(req.Description) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid description %q", req.Description)
+	}
+	if req.Auto || len(req.Addresses) > 0 || req.Description != "" {
+		cmd := []string{"coordinators"}
+		if req.Auto {
+			cmd = append(cmd, "auto")
+		}
+		cmd = append(cmd, req.Addresses...)
+		if req.Description != "" {
+			cmd = append(cmd, "description="+req.Description)
+		}
+		if _, err := fdbcli(ctx, cmd...); err != nil {
+			return nil, err
+		}
+	}
+	out, err := fdbcli(ctx, "coordinators")
+	if err != nil {
+		return nil, err
+	}
+	reply, err := parseCoordinators(out)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return reply, nil
+}
+
+// Exclude implements pb.FDBServer.Exclude
+func (s *server) Exclude(ctx context.Context, req *pb.ExcludeRequest) (*pb.ExcludeReply, error) {
+	if len(req.Addresses) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one address must be given")
+	}
+	if err := checkAddresses(req.Addresses); err != nil {
+		return nil, err
+	}
+	cmd := []string{"exclude"}
+	if req.Force {
+		cmd = append(cmd, "FORCE")
+	}
+	if req.Failed {
+		cmd = append(cmd, "failed")
+	}
+	if req.NoWait {
+		cmd = append(cmd, "no_wait")
+	}
+	out, err := fdbcli(ctx, append(cmd, req.Addresses...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ExcludeReply{Output: out}, nil
+}
+
+// Include implements pb.FDBServer.Include
+func (s *server) Include(ctx context.Context, req *pb.IncludeRequest) (*pb.IncludeReply, error) {
+	if req.All == (len(req.Addresses) > 0) {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of all or addresses must be given")
+	}
+	if err := checkAddresses(req.Addresses); err != nil {
+		return nil, err
+	}
+	cmd := []string{"include"}
+	if req.All {
+		cmd = append(cmd, "all")
+	}
+	out, err := fdbcli(ctx, append(cmd, req.Addresses...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.IncludeReply{Output: out}, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterFDBServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
@@ -0,0 +1,294 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/fdb"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const (
+	statusJSON = `{
+  "client": {
+    "coordinators": {"quorum_reachable": true},
+    "database_status": {"available": true, "healthy": false},
+    "messages": []
+  },
+  "cluster": {
+    "configuration": {
+      "redundancy_mode": "triple",
+      "excluded_servers": [{"address": "10.0.0.4:4500"}]
+    },
+    "data": {"state": {"healthy": false, "name": "healing"}},
+    "machines": {"a": {}, "b": {}, "c": {}},
+    "processes": {"p1": {}, "p2": {}, "p3": {}, "p4": {}},
+    "messages": [{"name": "unreachable_processes"}]
+  }
+}`
+	coordinatorsOutput = `Cluster description: mycluster
+Cluster coordinators (3): 10.0.0.1:4500,10.0.0.2:4500,10.0.0.3:4500
+`
+)
+
+// fakeFDBCLI installs an fdbcli script appending the command it's asked to
+// execute to the returned file, one per line. It prints canned output for
+// status json and coordinators and fails for commands naming 10.0.0.99.
+func fakeFDBCLI(t *testing.T) string {
+	t.Helper()
+	saved := *fdbcliBin
+	t.Cleanup(func() { *fdbcliBin = saved })
+	dir := t.TempDir()
+	commands := filepath.Join(dir, "commands")
+	write := func(name, contents string) string {
+		f := filepath.Join(dir, name)
+		testutil.FatalOnErr("writing "+name, os.WriteFile(f, []byte(contents), 0644), t)
+		return f
+	}
+	status := write("status", statusJSON)
+	coordinators := write("coordinators", coordinatorsOutput)
+	script := fmt.Sprintf(`#!/bin/sh
+while [ $# -gt 0 ]; do
+  if [ "$1" = "--exec" ]; then cmd="$2"; fi
+  shift
+done
+echo "$cmd" >> %s
+case "$cmd" in
+*10.0.0.99*) echo "ERROR: it failed"; exit 1 ;;
+"status json") cat %s ;;
+coordinators) cat %s ;;
+*) echo "done: $cmd" ;;
+esac
+`, commands, status, coordinators)
+	*fdbcliBin = write("fdbcli", script)
+	testutil.FatalOnErr("chmod", os.Chmod(*fdbcliBin, 0755), t)
+	return commands
+}
+
+func readCommands(t *testing.T, file string) []string {
+	t.Helper()
+	b, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	testutil.FatalOnErr("reading commands", err, t)
+	return strings.Split(strings.TrimSpace(string(b)), "\n")
+}
+
+func TestStatus(t *testing.T) {
+	commands := fakeFDBCLI(t)
+	s := &server{}
+	got, err := s.Status(context.Background(), &pb.StatusRequest{})
+	testutil.FatalOnErr("Status", err, t)
+	want := &pb.StatusReply{
+		Json:                        statusJSON,
+		DatabaseAvailable:           true,
+		DataState:                   "healing",
+		RedundancyMode:              "triple",
+		Machines:                    3,
+		Processes:                   4,
+		CoordinatorsQuorumReachable: true,
+		ExcludedServers:             []string{"10.0.0.4:4500"},
+		Messages:                    []string{"unreachable_processes"},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected reply (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"status json"}, readCommands(t, commands)); diff != "" {
+		t.Errorf("unexpected commands (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseStatusBadJSON(t *testing.T) {
+	if _, err := parseStatus("Unable to communicate with the cluster"); err == nil {
+		t.Error("expected error parsing bad output")
+	}
+}
+
+func TestCoordinators(t *testing.T) {
+	want := &pb.CoordinatorsReply{
+		Description: "mycluster",
+		Addresses:   []string{"10.0.0.1:4500", "10.0.0.2:4500", "10.0.0.3:4500"},
+	}
+	for _, tc := range []struct {
+		name         string
+		req          *pb.CoordinatorsRequest
+		wantCommands []string
+		wantErr      codes.Code
+	}{
+		{
+			name:         "list",
+			req:          &pb.CoordinatorsRequest{},
+			wantCommands: []string{"coordinators"},
+		},
+		{
+			name:         "auto",
+			req:          &pb.CoordinatorsRequest{Auto: true},
+			wantCommands: []string{"coordinators auto", "coordinators"},
+		},
+		{
+			name: "addresses and description",
+			req: &pb.CoordinatorsRequest{
+				Addresses:   []string{"10.0.0.1:4500", "[::1]:4500:tls"},
+				Description: "new_name",
+			},
+			wantCommands: []string{"coordinators 10.0.0.1:4500 [::1]:4500:tls description=new_name", "coordinators"},
+		},
+		{
+			name:    "auto and addresses",
+			req:     &pb.CoordinatorsRequest{Auto: true, Addresses: []string{"10.0.0.1:4500"}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad address",
+			req:     &pb.CoordinatorsRequest{Addresses: []string{"10.0.0.1:4500; configure"}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad description",
+			req:     &pb.CoordinatorsRequest{Description: "a b"},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			commands := fakeFDBCLI(t)
+			s := &server{}
+			got, err := s.Coordinators(context.Background(), tc.req)
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.wantCommands, readCommands(t, commands)); diff != "" {
+				t.Errorf("unexpected commands (-want +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected reply (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExcludeInclude(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		exclude     *pb.ExcludeRequest
+		include     *pb.IncludeRequest
+		wantCommand string
+		wantErr     codes.Code
+	}{
+		{
+			name:        "exclude",
+			exclude:     &pb.ExcludeRequest{Addresses: []string{"10.0.0.4:4500", "10.0.0.5"}},
+			wantCommand: "exclude 10.0.0.4:4500 10.0.0.5",
+		},
+		{
+			name:        "exclude with options",
+			exclude:     &pb.ExcludeRequest{Addresses: []string{"10.0.0.4:4500"}, Force: true, Failed: true, NoWait: true},
+			wantCommand: "exclude FORCE failed no_wait 10.0.0.4:4500",
+		},
+		{
+			name:    "exclude nothing",
+			exclude: &pb.ExcludeRequest{},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "exclude bad address",
+			exclude: &pb.ExcludeRequest{Addresses: []string{"all"}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:        "include",
+			include:     &pb.IncludeRequest{Addresses: []string{"10.0.0.4:4500"}},
+			wantCommand: "include 10.0.0.4:4500",
+		},
+		{
+			name:        "include all",
+			include:     &pb.IncludeRequest{All: true},
+			wantCommand: "include all",
+		},
+		{
+			name:    "include all and addresses",
+			include: &pb.IncludeRequest{All: true, Addresses: []string{"10.0.0.4:4500"}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "include nothing",
+			include: &pb.IncludeRequest{},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			commands := fakeFDBCLI(t)
+			s := &server{}
+			var output string
+			var err error
+			if tc.exclude != nil {
+				var resp *pb.ExcludeReply
+				resp, err = s.Exclude(context.Background(), tc.exclude)
+				output = resp.GetOutput()
+			} else {
+				var resp *pb.IncludeReply
+				resp, err = s.Include(context.Background(), tc.include)
+				output = resp.GetOutput()
+			}
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				if got := readCommands(t, commands); got != nil {
+					t.Errorf("fdbcli ran %v for invalid request", got)
+				}
+				return
+			}
+			if diff := cmp.Diff([]string{tc.wantCommand}, readCommands(t, commands)); diff != "" {
+				t.Errorf("unexpected commands (-want +got):\n%s", diff)
+			}
+			if want := "done: " + tc.wantCommand + "\n"; output != want {
+				t.Errorf("unexpected output: got %q want %q", output, want)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	fakeFDBCLI(t)
+	s := &server{}
+	_, err := s.Exclude(context.Background(), &pb.ExcludeRequest{Addresses: []string{"10.0.0.99"}})
+	if status.Code(err) != codes.Internal || !strings.Contains(err.Error(), "it failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	*fdbcliBin = filepath.Join(t.TempDir(), "missing")
+	if _, err := s.Status(context.Background(), &pb.StatusRequest{}); err == nil {
+		t.Error("expected error with missing fdbcli")
+	}
+}
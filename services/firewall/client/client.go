@@ -0,0 +1,219 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'firewall'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/firewall"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "firewall"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&insertTemporaryCmd{}, "")
+	c.Register(&rulesCmd{}, "")
+	return c
+}
+
+func flagToBackend(val string) (pb.Backend, error) {
+	if val == "" {
+		return pb.Backend_BACKEND_UNKNOWN, nil
+	}
+	v := fmt.Sprintf("BACKEND_%s", strings.ToUpper(val))
+	i, ok := pb.Backend_value[v]
+	if !ok || i == int32(pb.Backend_BACKEND_UNKNOWN) {
+		return pb.Backend_BACKEND_UNKNOWN, fmt.Errorf("no such backend %s", val)
+	}
+	return pb.Backend(i), nil
+}
+
+func countersString(c *pb.Counters) string {
+	if c == nil {
+		return ""
+	}
+	return fmt.Sprintf("[%d:%d]", c.Packets, c.Bytes)
+}
+
+type rulesCmd struct {
+	backend string
+	table   string
+}
+
+func (*rulesCmd) Name() string     { return "rules" }
+func (*rulesCmd) Synopsis() string { return "List the firewall rules on the remote host" }
+func (*rulesCmd) Usage() string {
+	return `rules [--backend <iptables|nftables>] [--table <table>]:
+    List the firewall rules with their counters, in a format similar to
+    iptables-save. For nftables each rule is its JSON expression list.
+`
+}
+
+func (r *rulesCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&r.backend, "backend", "", "The firewall to list (iptables or nftables). If unset the remote side picks (iptables)")
+	f.StringVar(&r.table, "table", "", "If set only list this table")
+}
+
+func printTable(out io.Writer, t *pb.Table) {
+	fmt.Fprintf(out, "*%s %s\n", t.Family, t.Name)
+	for _, c := range t.Chains {
+		policy := c.Policy
+		if policy == "" {
+			policy = "-"
+		}
+		line := fmt.Sprintf(":%s %s", c.Name, policy)
+		if c.Counters != nil {
+			line += " " + countersString(c.Counters)
+		}
+		if c.Type != "" {
+			line += fmt.Sprintf(" # type %s hook %s priority %d", c.Type, c.Hook, c.Priority)
+		}
+		fmt.Fprintln(out, line)
+	}
+	for _, c := range t.Chains {
+		for _, r := range c.Rules {
+			line := fmt.Sprintf("-A %s %s", c.Name, r.Spec)
+			if r.Counters != nil {
+				line = countersString(r.Counters) + " " + line
+			}
+			if r.Handle != 0 {
+				line += fmt.Sprintf(" # handle %d", r.Handle)
+			}
+			if r.TemporaryId != "" {
+				line += fmt.Sprintf(" # temporary %s expires %s", r.TemporaryId, r.Expires.AsTime().Local().Format(time.RFC3339))
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+}
+
+func (r *rulesCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+	backend, err := flagToBackend(r.backend)
+	if err != nil {
+		fmt.Fprintln(errWriter, err)
+		subcommands.DefaultCommander.ExplainCommand(errWriter, r)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewFirewallClientProxy(state.Conn)
+	respChan, err := c.ListRulesOneMany(ctx, &pb.ListRulesRequest{Backend: backend, Table: r.table})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'rules': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, t := range resp.Resp.Tables {
+			printTable(state.Out[resp.Index], t)
+		}
+	}
+	return retCode
+}
+
+type insertTemporaryCmd struct {
+	ipv6  bool
+	table string
+	chain string
+	ttl   time.Duration
+}
+
+func (*insertTemporaryCmd) Name() string { return "insert-temporary" }
+func (*insertTemporaryCmd) Synopsis() string {
+	return "Insert an iptables rule which is removed after a time"
+}
+func (*insertTemporaryCmd) Usage() string {
+	return `insert-temporary [--ipv6] [--table <table>] --chain <chain> --ttl <duration> -- <rule>...:
+    Insert an iptables rule at the start of the chain, i.e.
+
+      insert-temporary --chain INPUT --ttl 1h -- -s 192.0.2.1 -j DROP
+
+    The rule is removed by the remote side once the ttl (at most 24h) has
+    passed. The remote server must allow temporary rules.
+`
+}
+
+func (i *insertTemporaryCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&i.ipv6, "ipv6", false, "If true insert the rule with ip6tables")
+	f.StringVar(&i.table, "table", "filter", "The table to insert the rule in")
+	f.StringVar(&i.chain, "chain", "", "The chain to insert the rule in")
+	f.DurationVar(&i.ttl, "ttl", 0, "How long until the rule is removed")
+}
+
+func (i *insertTemporaryCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+	if i.chain == "" || i.ttl <= 0 || f.NArg() == 0 {
+		fmt.Fprintln(errWriter, "Please specify a chain, ttl and rule.")
+		subcommands.DefaultCommander.ExplainCommand(errWriter, i)
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.InsertTemporaryRuleRequest{
+		Ipv6:  i.ipv6,
+		Table: i.table,
+		Chain: i.chain,
+		Rule:  f.Args(),
+		Ttl:   durationpb.New(i.ttl),
+	}
+	c := pb.NewFirewallClientProxy(state.Conn)
+	respChan, err := c.InsertTemporaryRuleOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'insert-temporary': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[resp.Index], "inserted rule %s, expires %s\n", resp.Resp.Id, resp.Resp.Expires.AsTime().Local().Format(time.RFC3339))
+	}
+	return retCode
+}
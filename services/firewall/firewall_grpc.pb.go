@@ -0,0 +1,149 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package firewall
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// FirewallClient is the client API for Firewall service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FirewallClient interface {
+	// ListRules returns the firewall ruleset with counters.
+	ListRules(ctx context.Context, in *ListRulesRequest, opts ...grpc.CallOption) (*ListRulesReply, error)
+	// InsertTemporaryRule inserts an iptables rule at the start of a chain
+	// which is removed once its TTL passes (i.e. to block an attacker during
+	// an incident). It's only allowed if the server is started with
+	// --firewall-allow-temporary-rules. Rules left from a previous run of
+	// the server are removed when next seen by ListRules or
+	// InsertTemporaryRule.
+	InsertTemporaryRule(ctx context.Context, in *InsertTemporaryRuleRequest, opts ...grpc.CallOption) (*InsertTemporaryRuleReply, error)
+}
+
+type firewallClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFirewallClient(cc grpc.ClientConnInterface) FirewallClient {
+	return &firewallClient{cc}
+}
+
+func (c *firewallClient) ListRules(ctx context.Context, in *ListRulesRequest, opts ...grpc.CallOption) (*ListRulesReply, error) {
+	out := new(ListRulesReply)
+	err := c.cc.Invoke(ctx, "/Firewall.Firewall/ListRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *firewallClient) InsertTemporaryRule(ctx context.Context, in *InsertTemporaryRuleRequest, opts ...grpc.CallOption) (*InsertTemporaryRuleReply, error) {
+	out := new(InsertTemporaryRuleReply)
+	err := c.cc.Invoke(ctx, "/Firewall.Firewall/InsertTemporaryRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FirewallServer is the server API for Firewall service.
+// All implementations should embed UnimplementedFirewallServer
+// for forward compatibility
+type FirewallServer interface {
+	// ListRules returns the firewall ruleset with counters.
+	ListRules(context.Context, *ListRulesRequest) (*ListRulesReply, error)
+	// InsertTemporaryRule inserts an iptables rule at the start of a chain
+	// which is removed once its TTL passes (i.e. to block an attacker during
+	// an incident). It's only allowed if the server is started with
+	// --firewall-allow-temporary-rules. Rules left from a previous run of
+	// the server are removed when next seen by ListRules or
+	// InsertTemporaryRule.
+	InsertTemporaryRule(context.Context, *InsertTemporaryRuleRequest) (*InsertTemporaryRuleReply, error)
+}
+
+// UnimplementedFirewallServer should be embedded to have forward compatible implementations.
+type UnimplementedFirewallServer struct {
+}
+
+func (UnimplementedFirewallServer) ListRules(context.Context, *ListRulesRequest) (*ListRulesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRules not implemented")
+}
+func (UnimplementedFirewallServer) InsertTemporaryRule(context.Context, *InsertTemporaryRuleRequest) (*InsertTemporaryRuleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InsertTemporaryRule not implemented")
+}
+
+// UnsafeFirewallServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FirewallServer will
+// result in compilation errors.
+type UnsafeFirewallServer interface {
+	mustEmbedUnimplementedFirewallServer()
+}
+
+func RegisterFirewallServer(s grpc.ServiceRegistrar, srv FirewallServer) {
+	s.RegisterService(&Firewall_ServiceDesc, srv)
+}
+
+func _Firewall_ListRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).ListRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Firewall.Firewall/ListRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).ListRules(ctx, req.(*ListRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Firewall_InsertTemporaryRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertTemporaryRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FirewallServer).InsertTemporaryRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Firewall.Firewall/InsertTemporaryRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FirewallServer).InsertTemporaryRule(ctx, req.(*InsertTemporaryRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Firewall_ServiceDesc is the grpc.ServiceDesc for Firewall service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Firewall_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Firewall.Firewall",
+	HandlerType: (*FirewallServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRules",
+			Handler:    _Firewall_ListRules_Handler,
+		},
+		{
+			MethodName: "InsertTemporaryRule",
+			Handler:    _Firewall_InsertTemporaryRule_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "firewall.proto",
+}
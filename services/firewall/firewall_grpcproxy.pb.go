@@ -0,0 +1,168 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package firewall
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// FirewallClientProxy is the superset of FirewallClient which additionally includes the OneMany proxy methods
+type FirewallClientProxy interface {
+	FirewallClient
+	ListRulesOneMany(ctx context.Context, in *ListRulesRequest, opts ...grpc.CallOption) (<-chan *ListRulesManyResponse, error)
+	InsertTemporaryRuleOneMany(ctx context.Context, in *InsertTemporaryRuleRequest, opts ...grpc.CallOption) (<-chan *InsertTemporaryRuleManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type firewallClientProxy struct {
+	*firewallClient
+}
+
+// NewFirewallClientProxy creates a FirewallClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewFirewallClientProxy(cc *proxy.Conn) FirewallClientProxy {
+	return &firewallClientProxy{NewFirewallClient(cc).(*firewallClient)}
+}
+
+// ListRulesManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ListRulesManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ListRulesReply
+	Error error
+}
+
+// ListRulesOneMany provides the same API as ListRules but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *firewallClientProxy) ListRulesOneMany(ctx context.Context, in *ListRulesRequest, opts ...grpc.CallOption) (<-chan *ListRulesManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ListRulesManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ListRulesManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ListRulesReply{},
+			}
+			err := conn.Invoke(ctx, "/Firewall.Firewall/ListRules", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Firewall.Firewall/ListRules", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ListRulesManyResponse{
+				Resp: &ListRulesReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// InsertTemporaryRuleManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type InsertTemporaryRuleManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *InsertTemporaryRuleReply
+	Error error
+}
+
+// InsertTemporaryRuleOneMany provides the same API as InsertTemporaryRule but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *firewallClientProxy) InsertTemporaryRuleOneMany(ctx context.Context, in *InsertTemporaryRuleRequest, opts ...grpc.CallOption) (<-chan *InsertTemporaryRuleManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *InsertTemporaryRuleManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &InsertTemporaryRuleManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &InsertTemporaryRuleReply{},
+			}
+			err := conn.Invoke(ctx, "/Firewall.Firewall/InsertTemporaryRule", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Firewall.Firewall/InsertTemporaryRule", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &InsertTemporaryRuleManyResponse{
+				Resp: &InsertTemporaryRuleReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
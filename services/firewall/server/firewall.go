@@ -0,0 +1,518 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'Firewall' service.
+package server
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/firewall"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const (
+	// maxRuleTTL is the longest a temporary rule can last.
+	maxRuleTTL = 24 * time.Hour
+	// iptablesParameterProblem is the exit code from iptables for
+	// invalid arguments.
+	iptablesParameterProblem = 2
+)
+
+var (
+	// Temporary rules are commented with their ID and when they expire
+	// (in unix seconds) so they can still be removed after a restart.
+	temporaryCommentRE = regexp.MustCompile(`sansshell-temporary id=([0-9a-f]+) expires=(\d+)`)
+
+	tableRE = regexp.MustCompile(`^[a-z]+$`)
+	chainRE = regexp.MustCompile(`^[A-Za-z0-9_.][A-Za-z0-9_.-]*$`)
+
+	// Arguments which would make a temporary rule do more than insert
+	// a rule.
+	forbiddenRuleArgs = map[string]bool{
+		"-A": true, "--append": true, "-C": true, "--check": true,
+		"-D": true, "--delete": true, "-I": true, "--insert": true,
+		"-R": true, "--replace": true, "-L": true, "--list": true,
+		"-S": true, "--list-rules": true, "-F": true, "--flush": true,
+		"-Z": true, "--zero": true, "-N": true, "--new-chain": true,
+		"-X": true, "--delete-chain": true, "-P": true, "--policy": true,
+		"-E": true, "--rename-chain": true, "-t": true, "--table": true,
+		"-M": true, "--modprobe": true,
+	}
+)
+
+// server is used to implement the gRPC server
+type server struct {
+	mu sync.Mutex
+	// timers remove temporary rules once they expire, keyed by rule ID.
+	timers map[string]*time.Timer
+}
+
+// family is an iptables address family.
+type family struct {
+	name    string
+	bin     *string
+	saveBin *string
+}
+
+func families() []family {
+	return []family{
+		{name: "ip", bin: iptablesBin, saveBin: iptablesSaveBin},
+		{name: "ip6", bin: ip6tablesBin, saveBin: ip6tablesSaveBin},
+	}
+}
+
+// parseCounters parses iptables-save counters, i.e. [10:600].
+func parseCounters(s string) (*pb.Counters, error) {
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"), ":")
+	if len(parts) != 2 || !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("invalid counters %q", s)
+	}
+	packets, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid counters %q: %v", s, err)
+	}
+	bytes, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid counters %q: %v", s, err)
+	}
+	return &pb.Counters{Packets: packets, Bytes: bytes}, nil
+}
+
+// parseIPTablesSave parses the output of iptables-save, optionally with
+// counters (-c), which looks like:
+//
+//	*filter
+//	:INPUT ACCEPT [1234:567890]
+//	:DOCKER - [0:0]
+//	[100:6000] -A INPUT -i lo -j ACCEPT
+//	COMMIT
+func parseIPTablesSave(familyName string, out string) ([]*pb.Table, error) {
+	var tables []*pb.Table
+	var table *pb.Table
+	chains := make(map[string]*pb.Chain)
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "*"):
+			table = &pb.Table{Family: familyName, Name: line[1:]}
+			tables = append(tables, table)
+			chains = make(map[string]*pb.Chain)
+			continue
+		case line == "COMMIT":
+			table = nil
+			continue
+		case table == nil:
+			return nil, fmt.Errorf("%q outside of a table", line)
+		}
+
+		if strings.HasPrefix(line, ":") {
+			fields := strings.Fields(line[1:])
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("invalid chain %q", line)
+			}
+			counters, err := parseCounters(fields[2])
+			if err != nil {
+				return nil, err
+			}
+			chain := &pb.Chain{Name: fields[0], Counters: counters}
+			if fields[1] != "-" {
+				chain.Policy = fields[1]
+			}
+			chains[chain.Name] = chain
+			table.Chains = append(table.Chains, chain)
+			continue
+		}
+
+		rule := &pb.Rule{}
+		if strings.HasPrefix(line, "[") {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid rule %q", line)
+			}
+			var err error
+			if rule.Counters, err = parseCounters(line[:end+1]); err != nil {
+				return nil, err
+			}
+			line = strings.TrimSpace(line[end+1:])
+		}
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) < 2 || fields[0] != "-A" {
+			return nil, fmt.Errorf("invalid rule %q", line)
+		}
+		chain, ok := chains[fields[1]]
+		if !ok {
+			return nil, fmt.Errorf("rule for unknown chain %q", line)
+		}
+		if len(fields) == 3 {
+			rule.Spec = fields[2]
+		}
+		if m := temporaryCommentRE.FindStringSubmatch(rule.Spec); m != nil {
+			expires, _ := strconv.ParseInt(m[2], 10, 64)
+			rule.TemporaryId = m[1]
+			rule.Expires = timestamppb.New(time.Unix(expires, 0))
+		}
+		chain.Rules = append(chain.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// splitSaveArgs splits a rule printed by iptables-save back into arguments.
+// Arguments containing spaces or quotes are double quoted with " and \
+// escaped by a backslash.
+func splitSaveArgs(spec string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg, quoted := false, false
+	for i := 0; i < len(spec); i++ {
+		c := spec[i]
+		switch {
+		case c == '\\' && quoted && i+1 < len(spec):
+			i++
+			arg.WriteByte(spec[i])
+		case c == '"':
+			quoted = !quoted
+			inArg = true
+		case c == ' ' && !quoted:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", spec)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// nftables JSON objects, see libnftables-json(5).
+type nftTable struct {
+	Family string
+	Name   string
+}
+
+type nftChain struct {
+	Family string
+	Table  string
+	Name   string
+	Type   string
+	Hook   string
+	Prio   int32
+	Policy string
+}
+
+type nftRule struct {
+	Family string
+	Table  string
+	Chain  string
+	Handle int64
+	Expr   []json.RawMessage
+}
+
+// parseNftJSON parses the output of nft -j list ruleset.
+func parseNftJSON(out []byte) ([]*pb.Table, error) {
+	var doc struct {
+		Nftables []struct {
+			Table *nftTable
+			Chain *nftChain
+			Rule  *nftRule
+		}
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		return nil, fmt.Errorf("can't parse nft output: %v", err)
+	}
+	var tables []*pb.Table
+	tableByName := make(map[string]*pb.Table)
+	chainByName := make(map[string]*pb.Chain)
+	for _, obj := range doc.Nftables {
+		switch {
+		case obj.Table != nil:
+			t := &pb.Table{Family: obj.Table.Family, Name: obj.Table.Name}
+			tables = append(tables, t)
+			tableByName[t.Family+" "+t.Name] = t
+		case obj.Chain != nil:
+			c := obj.Chain
+			t, ok := tableByName[c.Family+" "+c.Table]
+			if !ok {
+				return nil, fmt.Errorf("chain %s in unknown table %s %s", c.Name, c.Family, c.Table)
+			}
+			chain := &pb.Chain{Name: c.Name, Policy: c.Policy, Type: c.Type, Hook: c.Hook, Priority: c.Prio}
+			t.Chains = append(t.Chains, chain)
+			chainByName[c.Family+" "+c.Table+" "+c.Name] = chain
+		case obj.Rule != nil:
+			r := obj.Rule
+			chain, ok := chainByName[r.Family+" "+r.Table+" "+r.Chain]
+			if !ok {
+				return nil, fmt.Errorf("rule %d in unknown chain %s %s %s", r.Handle, r.Family, r.Table, r.Chain)
+			}
+			spec, err := json.Marshal(r.Expr)
+			if err != nil {
+				return nil, err
+			}
+			rule := &pb.Rule{Spec: string(spec), Handle: r.Handle}
+			for _, e := range r.Expr {
+				var expr struct {
+					Counter *pb.Counters
+				}
+				// Named counters are references which don't parse.
+				if err := json.Unmarshal(e, &expr); err == nil && expr.Counter != nil {
+					rule.Counters = expr.Counter
+				}
+			}
+			chain.Rules = append(chain.Rules, rule)
+		}
+	}
+	return tables, nil
+}
+
+// iptablesRules returns the iptables and ip6tables rules, or only those
+// in table if it's set. With temporary rules enabled it also arranges for
+// any temporary rules found to expire, as they may be left from a
+// previous run.
+func (s *server) iptablesRules(ctx context.Context, table string) ([]*pb.Table, error) {
+	var tables []*pb.Table
+	for _, f := range families() {
+		if *f.saveBin == "" {
+			return nil, status.Error(codes.Unimplemented, "iptables is not supported on this platform")
+		}
+		args := []string{"-c"}
+		if table != "" {
+			args = append(args, "-t", table)
+		}
+		run, err := util.RunCommand(ctx, *f.saveBin, args)
+		if err != nil {
+			return nil, err
+		}
+		if err := run.Error; run.ExitCode != 0 || err != nil {
+			return nil, status.Errorf(codes.Internal, "error from %s: %v\nstderr:\n%s", *f.saveBin, err, util.TrimString(run.Stderr.String()))
+		}
+		t, err := parseIPTablesSave(f.name, run.Stdout.String())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't parse %s output: %v", *f.saveBin, err)
+		}
+		if *allowTemporaryRules {
+			s.scheduleTemporary(ctx, f, t)
+		}
+		tables = append(tables, t...)
+	}
+	return tables, nil
+}
+
+// ListRules implements pb.FirewallServer.ListRules
+func (s *server) ListRules(ctx context.Context, req *pb.ListRulesRequest) (*pb.ListRulesReply, error) {
+	if req.Table != "" && !tableRE.MatchString(req.Table) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid table %q", req.Table)
+	}
+	switch req.Backend {
+	case pb.Backend_BACKEND_UNKNOWN, pb.Backend_BACKEND_IPTABLES:
+		tables, err := s.iptablesRules(ctx, req.Table)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.ListRulesReply{Backend: pb.Backend_BACKEND_IPTABLES, Tables: tables}, nil
+	case pb.Backend_BACKEND_NFTABLES:
+		if *nftBin == "" {
+			return nil, status.Error(codes.Unimplemented, "nftables is not supported on this platform")
+		}
+		run, err := util.RunCommand(ctx, *nftBin, []string{"-j", "list", "ruleset"})
+		if err != nil {
+			return nil, err
+		}
+		if err := run.Error; run.ExitCode != 0 || err != nil {
+			return nil, status.Errorf(codes.Internal, "error from nft: %v\nstderr:\n%s", err, util.TrimString(run.Stderr.String()))
+		}
+		tables, err := parseNftJSON(run.Stdout.Bytes())
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		reply := &pb.ListRulesReply{Backend: pb.Backend_BACKEND_NFTABLES}
+		for _, t := range tables {
+			if req.Table == "" || t.Name == req.Table {
+				reply.Tables = append(reply.Tables, t)
+			}
+		}
+		return reply, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid backend %d", req.Backend)
+	}
+}
+
+// removeTemporary deletes a temporary rule. It's run from a timer so
+// errors can only be logged.
+func (s *server) removeTemporary(logger logr.Logger, bin string, table string, chain string, spec []string, id string) {
+	s.mu.Lock()
+	delete(s.timers, id)
+	s.mu.Unlock()
+
+	args := append([]string{"-w", "-t", table, "-D", chain}, spec...)
+	run, err := util.RunCommand(context.Background(), bin, args)
+	if err == nil && (run.ExitCode != 0 || run.Error != nil) {
+		err = fmt.Errorf("%v: %s", run.Error, util.TrimString(run.Stderr.String()))
+	}
+	if err != nil {
+		logger.Error(err, "can't remove temporary firewall rule", "id", id, "table", table, "chain", chain)
+		return
+	}
+	logger.Info("removed temporary firewall rule", "id", id, "table", table, "chain", chain)
+}
+
+// schedule arranges for a temporary rule to be removed at expires, if it
+// isn't already.
+func (s *server) schedule(ctx context.Context, bin string, table string, chain string, spec []string, id string, expires time.Time) {
+	logger := logr.FromContextOrDiscard(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timers == nil {
+		s.timers = make(map[string]*time.Timer)
+	}
+	if _, ok := s.timers[id]; ok {
+		return
+	}
+	s.timers[id] = time.AfterFunc(time.Until(expires), func() {
+		s.removeTemporary(logger, bin, table, chain, spec, id)
+	})
+}
+
+// scheduleTemporary schedules removal of the temporary rules in tables.
+// Expired ones are removed immediately.
+func (s *server) scheduleTemporary(ctx context.Context, f family, tables []*pb.Table) {
+	logger := logr.FromContextOrDiscard(ctx)
+	for _, t := range tables {
+		for _, c := range t.Chains {
+			for _, r := range c.Rules {
+				if r.TemporaryId == "" {
+					continue
+				}
+				spec, err := splitSaveArgs(r.Spec)
+				if err != nil {
+					logger.Error(err, "can't parse temporary firewall rule", "id", r.TemporaryId)
+					continue
+				}
+				s.schedule(ctx, *f.bin, t.Name, c.Name, spec, r.TemporaryId, r.Expires.AsTime())
+			}
+		}
+	}
+}
+
+// InsertTemporaryRule implements pb.FirewallServer.InsertTemporaryRule
+func (s *server) InsertTemporaryRule(ctx context.Context, req *pb.InsertTemporaryRuleRequest) (*pb.InsertTemporaryRuleReply, error) {
+	if !*allowTemporaryRules {
+		return nil, status.Error(codes.FailedPrecondition, "temporary rules are disabled on this server (see --firewall-allow-temporary-rules)")
+	}
+	table := req.Table
+	if table == "" {
+		table = "filter"
+	}
+	if !tableRE.MatchString(table) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid table %q", table)
+	}
+	if !chainRE.MatchString(req.Chain) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid chain %q", req.Chain)
+	}
+	if len(req.Rule) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "rule must be set")
+	}
+	for _, a := range req.Rule {
+		if forbiddenRuleArgs[a] {
+			return nil, status.Errorf(codes.InvalidArgument, "%s can't be used in a rule", a)
+		}
+	}
+	if req.Ttl == nil {
+		return nil, status.Error(codes.InvalidArgument, "ttl must be set")
+	}
+	if err := req.Ttl.CheckValid(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid ttl: %v", err)
+	}
+	ttl := req.Ttl.AsDuration()
+	if ttl < time.Second || ttl > maxRuleTTL {
+		return nil, status.Errorf(codes.InvalidArgument, "ttl must be between 1s and %v", maxRuleTTL)
+	}
+	f := families()[0]
+	if req.Ipv6 {
+		f = families()[1]
+	}
+	if *f.bin == "" {
+		return nil, status.Error(codes.Unimplemented, "iptables is not supported on this platform")
+	}
+
+	// Pick up temporary rules from previous runs before adding more.
+	if _, err := s.iptablesRules(ctx, table); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't generate rule ID: %v", err)
+	}
+	id := hex.EncodeToString(b)
+	// Only second precision is recorded, so round up.
+	expires := time.Now().Add(ttl).Truncate(time.Second).Add(time.Second)
+	spec := append(append([]string{}, req.Rule...), "-m", "comment", "--comment", fmt.Sprintf("sansshell-temporary id=%s expires=%d", id, expires.Unix()))
+	args := append([]string{"-w", "-t", table, "-I", req.Chain}, spec...)
+	run, err := util.RunCommand(ctx, *f.bin, args)
+	if err != nil {
+		return nil, err
+	}
+	if run.ExitCode == iptablesParameterProblem {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid rule: %s", util.TrimString(run.Stderr.String()))
+	}
+	if err := run.Error; run.ExitCode != 0 || err != nil {
+		return nil, status.Errorf(codes.Internal, "error from %s: %v\nstderr:\n%s", *f.bin, err, util.TrimString(run.Stderr.String()))
+	}
+	logr.FromContextOrDiscard(ctx).Info("inserted temporary firewall rule", "id", id, "table", table, "chain", req.Chain, "expires", expires)
+	s.schedule(ctx, *f.bin, table, req.Chain, spec, id, expires)
+	return &pb.InsertTemporaryRuleReply{Id: id, Expires: timestamppb.New(expires)}, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterFirewallServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
@@ -0,0 +1,33 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"flag"
+)
+
+var (
+	iptablesBin         = flag.String("iptables-bin", "/usr/sbin/iptables", "Path to the iptables binary")
+	iptablesSaveBin     = flag.String("iptables-save-bin", "/usr/sbin/iptables-save", "Path to the iptables-save binary")
+	ip6tablesBin        = flag.String("ip6tables-bin", "/usr/sbin/ip6tables", "Path to the ip6tables binary")
+	ip6tablesSaveBin    = flag.String("ip6tables-save-bin", "/usr/sbin/ip6tables-save", "Path to the ip6tables-save binary")
+	nftBin              = flag.String("nft-bin", "/usr/sbin/nft", "Path to the nft binary")
+	allowTemporaryRules = flag.Bool("firewall-allow-temporary-rules", false, "If true allow Firewall.InsertTemporaryRule (subject to policy)")
+)
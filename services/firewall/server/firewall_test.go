@@ -0,0 +1,458 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/firewall"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestParseIPTablesSave(t *testing.T) {
+	out, err := os.ReadFile("./testdata/iptables-save.out")
+	testutil.FatalOnErr("reading testdata", err, t)
+	got, err := parseIPTablesSave("ip", string(out))
+	testutil.FatalOnErr("parseIPTablesSave", err, t)
+	want := []*pb.Table{
+		{
+			Family: "ip",
+			Name:   "filter",
+			Chains: []*pb.Chain{
+				{
+					Name:     "INPUT",
+					Policy:   "ACCEPT",
+					Counters: &pb.Counters{Packets: 1234, Bytes: 567890},
+					Rules: []*pb.Rule{
+						{Spec: "-i lo -j ACCEPT", Counters: &pb.Counters{Packets: 100, Bytes: 6000}},
+						{
+							Spec:        `-s 10.0.0.1/32 -p tcp -m tcp --dport 22 -m comment --comment "sansshell-temporary id=0123456789abcdef expires=1646128800" -j DROP`,
+							Counters:    &pb.Counters{Packets: 5, Bytes: 300},
+							TemporaryId: "0123456789abcdef",
+							Expires:     timestamppb.New(time.Unix(1646128800, 0)),
+						},
+					},
+				},
+				{
+					Name:     "FORWARD",
+					Policy:   "DROP",
+					Counters: &pb.Counters{},
+					Rules:    []*pb.Rule{{Spec: "-j DOCKER", Counters: &pb.Counters{}}},
+				},
+				{Name: "OUTPUT", Policy: "ACCEPT", Counters: &pb.Counters{Packets: 4321, Bytes: 98765}},
+				{Name: "DOCKER", Counters: &pb.Counters{}},
+			},
+		},
+		{
+			Family: "ip",
+			Name:   "nat",
+			Chains: []*pb.Chain{
+				{Name: "PREROUTING", Policy: "ACCEPT", Counters: &pb.Counters{Packets: 10, Bytes: 600}},
+				{
+					Name:     "POSTROUTING",
+					Policy:   "ACCEPT",
+					Counters: &pb.Counters{Packets: 20, Bytes: 1200},
+					Rules:    []*pb.Rule{{Spec: "-s 172.17.0.0/16 ! -o docker0 -j MASQUERADE", Counters: &pb.Counters{Packets: 3, Bytes: 180}}},
+				},
+			},
+		},
+	}
+	testutil.DiffErr("parseIPTablesSave", got, want, t)
+
+	for _, bad := range []string{
+		"-A INPUT -j ACCEPT\n",
+		"*filter\n:INPUT ACCEPT\n",
+		"*filter\n:INPUT ACCEPT [1:x]\n",
+		"*filter\n-A INPUT -j ACCEPT\n",
+		"*filter\n:INPUT ACCEPT [0:0]\n-I INPUT -j ACCEPT\n",
+		"*filter\n:INPUT ACCEPT [0:0]\n[1:2 -A INPUT -j ACCEPT\n",
+	} {
+		if _, err := parseIPTablesSave("ip", bad); err == nil {
+			t.Errorf("parseIPTablesSave(%q) didn't fail", bad)
+		}
+	}
+}
+
+func TestSplitSaveArgs(t *testing.T) {
+	for _, tc := range []struct {
+		spec    string
+		want    []string
+		wantErr bool
+	}{
+		{spec: "-i lo -j ACCEPT", want: []string{"-i", "lo", "-j", "ACCEPT"}},
+		{spec: `-m comment --comment "a \"quoted\" comment" -j DROP`, want: []string{"-m", "comment", "--comment", `a "quoted" comment`, "-j", "DROP"}},
+		{spec: `--comment ""`, want: []string{"--comment", ""}},
+		{spec: `--comment "open`, wantErr: true},
+	} {
+		got, err := splitSaveArgs(tc.spec)
+		testutil.WantErr(tc.spec, err, tc.wantErr, t)
+		if !tc.wantErr {
+			testutil.DiffErr(tc.spec, got, tc.want, t)
+		}
+	}
+}
+
+func TestParseNftJSON(t *testing.T) {
+	out, err := os.ReadFile("./testdata/nft.json")
+	testutil.FatalOnErr("reading testdata", err, t)
+	got, err := parseNftJSON(out)
+	testutil.FatalOnErr("parseNftJSON", err, t)
+	want := []*pb.Table{
+		{
+			Family: "inet",
+			Name:   "filter",
+			Chains: []*pb.Chain{
+				{
+					Name:   "input",
+					Policy: "accept",
+					Type:   "filter",
+					Hook:   "input",
+					Rules: []*pb.Rule{
+						{
+							Spec:     `[{"match":{"op":"==","left":{"meta":{"key":"iifname"}},"right":"lo"}},{"counter":{"packets":10,"bytes":800}},{"accept":null}]`,
+							Counters: &pb.Counters{Packets: 10, Bytes: 800},
+							Handle:   4,
+						},
+					},
+				},
+				{
+					Name:  "blocked",
+					Rules: []*pb.Rule{{Spec: `[{"drop":null}]`, Handle: 5}},
+				},
+			},
+		},
+		{
+			Family: "ip",
+			Name:   "nat",
+			Chains: []*pb.Chain{
+				{
+					Name:     "postrouting",
+					Policy:   "accept",
+					Type:     "nat",
+					Hook:     "postrouting",
+					Priority: 100,
+					Rules:    []*pb.Rule{{Spec: `[{"counter":"named"},{"masquerade":null}]`, Handle: 2}},
+				},
+			},
+		},
+	}
+	testutil.DiffErr("parseNftJSON", got, want, t)
+
+	for _, bad := range []string{
+		"not json",
+		`{"nftables": [{"chain": {"family": "ip", "table": "missing", "name": "input"}}]}`,
+		`{"nftables": [{"rule": {"family": "ip", "table": "missing", "chain": "input"}}]}`,
+	} {
+		if _, err := parseNftJSON([]byte(bad)); err == nil {
+			t.Errorf("parseNftJSON(%q) didn't fail", bad)
+		}
+	}
+}
+
+// fakeCommand writes a script to dir which appends its arguments to
+// dir/name.args, prints file (if set) and exits with code.
+func fakeCommand(t *testing.T, dir string, name string, file string, code int) string {
+	t.Helper()
+	script := fmt.Sprintf("#!/bin/sh\nprintf '%%s\\n' \"$*\" >> %s\n", filepath.Join(dir, name+".args"))
+	if file != "" {
+		out, err := filepath.Abs(file)
+		testutil.FatalOnErr("output path", err, t)
+		script += fmt.Sprintf("%s %s\n", testutil.ResolvePath(t, "cat"), out)
+	}
+	script += fmt.Sprintf("exit %d\n", code)
+	bin := filepath.Join(dir, name)
+	testutil.FatalOnErr("writing command", os.WriteFile(bin, []byte(script), 0755), t)
+	return bin
+}
+
+// readArgs returns the arguments each run of the fake command name was
+// given.
+func readArgs(t *testing.T, dir string, name string) []string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, name+".args"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	testutil.FatalOnErr("reading args", err, t)
+	args := strings.TrimSpace(string(b))
+	// The file is created before the command writes to it.
+	if args == "" {
+		return nil
+	}
+	return strings.Split(args, "\n")
+}
+
+// saveFlags restores the firewall flags after the test.
+func saveFlags(t *testing.T) {
+	flags := []*string{iptablesBin, iptablesSaveBin, ip6tablesBin, ip6tablesSaveBin, nftBin}
+	var saved []string
+	for _, f := range flags {
+		saved = append(saved, *f)
+	}
+	savedAllow := *allowTemporaryRules
+	t.Cleanup(func() {
+		for i, f := range flags {
+			*f = saved[i]
+		}
+		*allowTemporaryRules = savedAllow
+	})
+}
+
+// stopTimers cancels any pending removals of temporary rules.
+func stopTimers(t *testing.T, s *server) {
+	t.Cleanup(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, timer := range s.timers {
+			timer.Stop()
+		}
+	})
+}
+
+func TestListRules(t *testing.T) {
+	saveFlags(t)
+	*allowTemporaryRules = false
+
+	for _, tc := range []struct {
+		name       string
+		req        *pb.ListRulesRequest
+		code       int
+		wantTables []string
+		wantArgs   string
+		wantErr    codes.Code
+	}{
+		{
+			name:       "iptables by default",
+			req:        &pb.ListRulesRequest{},
+			wantTables: []string{"ip filter", "ip nat", "ip6 filter"},
+			wantArgs:   "-c",
+		},
+		{
+			name:       "iptables table",
+			req:        &pb.ListRulesRequest{Backend: pb.Backend_BACKEND_IPTABLES, Table: "nat"},
+			wantTables: []string{"ip filter", "ip nat", "ip6 filter"},
+			wantArgs:   "-c -t nat",
+		},
+		{
+			name:       "nftables",
+			req:        &pb.ListRulesRequest{Backend: pb.Backend_BACKEND_NFTABLES},
+			wantTables: []string{"inet filter", "ip nat"},
+		},
+		{
+			name:       "nftables table",
+			req:        &pb.ListRulesRequest{Backend: pb.Backend_BACKEND_NFTABLES, Table: "nat"},
+			wantTables: []string{"ip nat"},
+		},
+		{
+			name:    "failure",
+			req:     &pb.ListRulesRequest{},
+			code:    1,
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "nftables failure",
+			req:     &pb.ListRulesRequest{Backend: pb.Backend_BACKEND_NFTABLES},
+			code:    1,
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "bad table",
+			req:     &pb.ListRulesRequest{Table: "-F"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad backend",
+			req:     &pb.ListRulesRequest{Backend: 99},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			*iptablesSaveBin = fakeCommand(t, dir, "iptables-save", "./testdata/iptables-save.out", tc.code)
+			*ip6tablesSaveBin = fakeCommand(t, dir, "ip6tables-save", "./testdata/ip6tables-save.out", tc.code)
+			*nftBin = fakeCommand(t, dir, "nft", "./testdata/nft.json", tc.code)
+			resp, err := (&server{}).ListRules(context.Background(), tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			var tables []string
+			for _, table := range resp.Tables {
+				tables = append(tables, table.Family+" "+table.Name)
+			}
+			testutil.DiffErr("tables", tables, tc.wantTables, t)
+			if tc.wantArgs != "" {
+				testutil.DiffErr("iptables-save args", readArgs(t, dir, "iptables-save"), []string{tc.wantArgs}, t)
+				testutil.DiffErr("ip6tables-save args", readArgs(t, dir, "ip6tables-save"), []string{tc.wantArgs}, t)
+			}
+		})
+	}
+}
+
+func TestListRulesRemovesExpired(t *testing.T) {
+	saveFlags(t)
+	*allowTemporaryRules = true
+	dir := t.TempDir()
+	*iptablesSaveBin = fakeCommand(t, dir, "iptables-save", "./testdata/iptables-save.out", 0)
+	*ip6tablesSaveBin = fakeCommand(t, dir, "ip6tables-save", "./testdata/ip6tables-save.out", 0)
+	*iptablesBin = fakeCommand(t, dir, "iptables", "", 0)
+
+	s := &server{}
+	stopTimers(t, s)
+	_, err := s.ListRules(context.Background(), &pb.ListRulesRequest{})
+	testutil.FatalOnErr("ListRules", err, t)
+
+	// The rule in testdata expired long ago so is removed immediately.
+	want := []string{"-w -t filter -D INPUT -s 10.0.0.1/32 -p tcp -m tcp --dport 22 -m comment --comment sansshell-temporary id=0123456789abcdef expires=1646128800 -j DROP"}
+	deadline := time.Now().Add(10 * time.Second)
+	for len(readArgs(t, dir, "iptables")) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	testutil.DiffErr("iptables args", readArgs(t, dir, "iptables"), want, t)
+}
+
+func TestInsertTemporaryRule(t *testing.T) {
+	saveFlags(t)
+
+	for _, tc := range []struct {
+		name     string
+		req      *pb.InsertTemporaryRuleRequest
+		disabled bool
+		code     int
+		wantBin  string
+		wantArgs string
+		wantErr  codes.Code
+	}{
+		{
+			name:     "insert",
+			req:      &pb.InsertTemporaryRuleRequest{Chain: "INPUT", Rule: []string{"-s", "10.0.0.1", "-j", "DROP"}, Ttl: durationpb.New(time.Hour)},
+			wantBin:  "iptables",
+			wantArgs: `^-w -t filter -I INPUT -s 10.0.0.1 -j DROP -m comment --comment sansshell-temporary id=[0-9a-f]{16} expires=\d+$`,
+		},
+		{
+			name:     "insert ipv6 table",
+			req:      &pb.InsertTemporaryRuleRequest{Ipv6: true, Table: "raw", Chain: "PREROUTING", Rule: []string{"-j", "NOTRACK"}, Ttl: durationpb.New(time.Minute)},
+			wantBin:  "ip6tables",
+			wantArgs: `^-w -t raw -I PREROUTING -j NOTRACK -m comment --comment sansshell-temporary id=[0-9a-f]{16} expires=\d+$`,
+		},
+		{
+			name:     "disabled",
+			req:      &pb.InsertTemporaryRuleRequest{Chain: "INPUT", Rule: []string{"-j", "DROP"}, Ttl: durationpb.New(time.Hour)},
+			disabled: true,
+			wantErr:  codes.FailedPrecondition,
+		},
+		{
+			name:    "invalid rule",
+			req:     &pb.InsertTemporaryRuleRequest{Chain: "INPUT", Rule: []string{"-j", "NOPE"}, Ttl: durationpb.New(time.Hour)},
+			code:    iptablesParameterProblem,
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "failure",
+			req:     &pb.InsertTemporaryRuleRequest{Chain: "INPUT", Rule: []string{"-j", "DROP"}, Ttl: durationpb.New(time.Hour)},
+			code:    4,
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "bad table",
+			req:     &pb.InsertTemporaryRuleRequest{Table: "filter -F", Chain: "INPUT", Rule: []string{"-j", "DROP"}, Ttl: durationpb.New(time.Hour)},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad chain",
+			req:     &pb.InsertTemporaryRuleRequest{Chain: "-F", Rule: []string{"-j", "DROP"}, Ttl: durationpb.New(time.Hour)},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "no rule",
+			req:     &pb.InsertTemporaryRuleRequest{Chain: "INPUT", Ttl: durationpb.New(time.Hour)},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "flush",
+			req:     &pb.InsertTemporaryRuleRequest{Chain: "INPUT", Rule: []string{"-j", "DROP", "-F"}, Ttl: durationpb.New(time.Hour)},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "other table",
+			req:     &pb.InsertTemporaryRuleRequest{Chain: "INPUT", Rule: []string{"-t", "nat", "-j", "DROP"}, Ttl: durationpb.New(time.Hour)},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "no ttl",
+			req:     &pb.InsertTemporaryRuleRequest{Chain: "INPUT", Rule: []string{"-j", "DROP"}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "long ttl",
+			req:     &pb.InsertTemporaryRuleRequest{Chain: "INPUT", Rule: []string{"-j", "DROP"}, Ttl: durationpb.New(maxRuleTTL + time.Second)},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			*allowTemporaryRules = !tc.disabled
+			// Neither has temporary rules to remove.
+			*iptablesSaveBin = fakeCommand(t, dir, "iptables-save", "./testdata/ip6tables-save.out", 0)
+			*ip6tablesSaveBin = fakeCommand(t, dir, "ip6tables-save", "./testdata/ip6tables-save.out", 0)
+			*iptablesBin = fakeCommand(t, dir, "iptables", "", tc.code)
+			*ip6tablesBin = fakeCommand(t, dir, "ip6tables", "", tc.code)
+			s := &server{}
+			stopTimers(t, s)
+			start := time.Now()
+			resp, err := s.InsertTemporaryRule(context.Background(), tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			args := readArgs(t, dir, tc.wantBin)
+			if len(args) != 1 || !regexp.MustCompile(tc.wantArgs).MatchString(args[0]) {
+				t.Fatalf("got %s args %q want %s", tc.wantBin, args, tc.wantArgs)
+			}
+			if !strings.Contains(args[0], fmt.Sprintf("id=%s expires=%d", resp.Id, resp.Expires.AsTime().Unix())) {
+				t.Errorf("%s args %q don't match reply %v", tc.wantBin, args[0], resp)
+			}
+			if exp := resp.Expires.AsTime(); exp.Before(start.Add(tc.req.Ttl.AsDuration())) || exp.After(time.Now().Add(tc.req.Ttl.AsDuration()+time.Second)) {
+				t.Errorf("got expiry %v for ttl %v at %v", exp, tc.req.Ttl.AsDuration(), start)
+			}
+			s.mu.Lock()
+			_, ok := s.timers[resp.Id]
+			s.mu.Unlock()
+			if !ok {
+				t.Errorf("removal of %s isn't scheduled", resp.Id)
+			}
+		})
+	}
+}
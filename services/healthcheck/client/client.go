@@ -34,7 +34,7 @@ import (
 const subPackage = "healthcheck"
 
 func init() {
-	subcommands.Register(&healthcheckCmd{}, subPackage)
+	client.RegisterSubpackage(subPackage, setup)
 }
 
 func setup(f *flag.FlagSet) *subcommands.Commander {
@@ -43,22 +43,6 @@ func setup(f *flag.FlagSet) *subcommands.Commander {
 	return c
 }
 
-type healthcheckCmd struct{}
-
-func (*healthcheckCmd) Name() string { return subPackage }
-func (p *healthcheckCmd) Synopsis() string {
-	return client.GenerateSynopsis(setup(flag.NewFlagSet("", flag.ContinueOnError)))
-}
-func (p *healthcheckCmd) Usage() string {
-	return client.GenerateUsage(subPackage, p.Synopsis())
-}
-func (*healthcheckCmd) SetFlags(f *flag.FlagSet) {}
-
-func (p *healthcheckCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
-	c := setup(f)
-	return c.Execute(ctx, args...)
-}
-
 type validateCmd struct{}
 
 func (*validateCmd) Name() string     { return "validate" }
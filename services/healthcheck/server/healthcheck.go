@@ -26,6 +26,16 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// policy permits health checks from anyone who can connect. Servers include
+// it by default (see services.RegisterPolicyFragment).
+const policy = `
+package sansshell.authz
+
+allow {
+	input.method = "/HealthCheck.HealthCheck/Ok"
+}
+`
+
 // server is used to implement the gRPC server
 type server struct{}
 
@@ -41,4 +51,5 @@ func (s *server) Register(gs *grpc.Server) {
 
 func init() {
 	services.RegisterSansShellService(&server{})
+	services.RegisterPolicyFragment("healthcheck", policy)
 }
@@ -23,6 +23,8 @@ import (
 	"os"
 	"testing"
 
+	"github.com/Snowflake-Labs/sansshell/auth/opa"
+	"github.com/Snowflake-Labs/sansshell/services"
 	pb "github.com/Snowflake-Labs/sansshell/services/healthcheck"
 	"github.com/Snowflake-Labs/sansshell/testing/testutil"
 	"google.golang.org/grpc"
@@ -67,3 +69,22 @@ func TestRead(t *testing.T) {
 	_, err = client.Ok(ctx, &emptypb.Empty{})
 	testutil.FatalOnErr("HealthCheck failed", err, t)
 }
+
+func TestPolicyFragment(t *testing.T) {
+	ctx := context.Background()
+	fragments := services.PolicyFragments()
+	if _, ok := fragments["healthcheck"]; !ok {
+		t.Fatalf("healthcheck fragment not registered: %v", fragments)
+	}
+	policy, err := opa.NewAuthzPolicy(ctx, `
+package sansshell.authz
+
+default allow = false
+`, opa.WithFragments(fragments))
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	allowed, err := policy.Eval(ctx, map[string]string{"method": "/HealthCheck.HealthCheck/Ok"})
+	testutil.FatalOnErr("Eval", err, t)
+	if !allowed {
+		t.Error("health check not allowed by fragment")
+	}
+}
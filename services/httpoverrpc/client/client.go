@@ -0,0 +1,181 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'httpoverrpc'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/httpoverrpc"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "http"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&requestCmd{}, "")
+	return c
+}
+
+// headerFlag is a repeatable flag of "Key: value" headers.
+type headerFlag []*pb.Header
+
+func (h *headerFlag) String() string {
+	var out []string
+	for _, hdr := range *h {
+		for _, v := range hdr.Values {
+			out = append(out, hdr.Key+": "+v)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+func (h *headerFlag) Set(val string) error {
+	kv := strings.SplitN(val, ":", 2)
+	if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+		return fmt.Errorf("bad header %q, must be Key: value", val)
+	}
+	*h = append(*h, &pb.Header{Key: strings.TrimSpace(kv[0]), Values: []string{strings.TrimSpace(kv[1])}})
+	return nil
+}
+
+type requestCmd struct {
+	method      string
+	tls         bool
+	insecure    bool
+	host        string
+	headers     headerFlag
+	bodyFile    string
+	maxBodySize int64
+	timeout     time.Duration
+	showHeaders bool
+}
+
+func (*requestCmd) Name() string     { return "request" }
+func (*requestCmd) Synopsis() string { return "Make an HTTP request from the remote host" }
+func (*requestCmd) Usage() string {
+	return `request [--method <method>] [--tls] [--host <host>] [--header <Key: value>]... [--body-file <file>] <port> <path>:
+    Make an HTTP request to host:port (localhost by default) from the remote
+    host and print the response body. The path may include a query string.
+`
+}
+
+func (r *requestCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&r.method, "method", "GET", "The HTTP method")
+	f.BoolVar(&r.tls, "tls", false, "If true use HTTPS")
+	f.BoolVar(&r.insecure, "insecure", false, "If true don't verify the server's certificate with --tls")
+	f.StringVar(&r.host, "host", "localhost", "The host to send the request to")
+	f.Var(&r.headers, "header", "A header to send as \"Key: value\". Can be repeated.")
+	f.StringVar(&r.bodyFile, "body-file", "", "If set send the contents of this local file as the body")
+	f.Int64Var(&r.maxBodySize, "max-body-size", 0, "If set return at most this much of the response body. Can't exceed the remote side's limit.")
+	f.DurationVar(&r.timeout, "timeout", 0, "If set how long to wait for the response. Otherwise the remote side picks (30s)")
+	f.BoolVar(&r.showHeaders, "show-headers", false, "If true print the status code and response headers before the body")
+}
+
+func (r *requestCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+	if f.NArg() != 2 {
+		fmt.Fprintln(errWriter, "Please specify a port and path.")
+		subcommands.DefaultCommander.ExplainCommand(errWriter, r)
+		return subcommands.ExitUsageError
+	}
+	port, err := strconv.ParseUint(f.Arg(0), 10, 16)
+	if err != nil {
+		fmt.Fprintf(errWriter, "invalid port %s\n", f.Arg(0))
+		subcommands.DefaultCommander.ExplainCommand(errWriter, r)
+		return subcommands.ExitUsageError
+	}
+	path, query := f.Arg(1), ""
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path, query = path[:i], path[i+1:]
+	}
+
+	req := &pb.HTTPRequest{
+		Method:             r.method,
+		Tls:                r.tls,
+		Host:               r.host,
+		Port:               uint32(port),
+		Path:               path,
+		Query:              query,
+		Headers:            r.headers,
+		InsecureSkipVerify: r.insecure,
+		MaxBodySize:        r.maxBodySize,
+	}
+	if r.timeout != 0 {
+		req.Timeout = durationpb.New(r.timeout)
+	}
+	if r.bodyFile != "" {
+		req.Body, err = os.ReadFile(r.bodyFile)
+		if err != nil {
+			fmt.Fprintf(errWriter, "can't read body: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	c := pb.NewHTTPOverRPCClientProxy(state.Conn)
+	respChan, err := c.RequestOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'request': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[resp.Index]
+		if r.showHeaders {
+			fmt.Fprintf(out, "HTTP %d\n", resp.Resp.StatusCode)
+			for _, h := range resp.Resp.Headers {
+				for _, v := range h.Values {
+					fmt.Fprintf(out, "%s: %s\n", h.Key, v)
+				}
+			}
+			fmt.Fprintln(out)
+		}
+		if _, err := out.Write(resp.Resp.Body); err != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) output write error: %v\n", resp.Target, resp.Index, err)
+			retCode = subcommands.ExitFailure
+		}
+		if resp.Resp.BodyTruncated {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) response body truncated\n", resp.Target, resp.Index)
+		}
+	}
+	return retCode
+}
@@ -0,0 +1,105 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package httpoverrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// HTTPOverRPCClient is the client API for HTTPOverRPC service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HTTPOverRPCClient interface {
+	// Request makes an HTTP request from the host. Redirects aren't followed
+	// so a request can't reach anywhere other than the host, port and path
+	// policy allowed.
+	Request(ctx context.Context, in *HTTPRequest, opts ...grpc.CallOption) (*HTTPReply, error)
+}
+
+type hTTPOverRPCClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHTTPOverRPCClient(cc grpc.ClientConnInterface) HTTPOverRPCClient {
+	return &hTTPOverRPCClient{cc}
+}
+
+func (c *hTTPOverRPCClient) Request(ctx context.Context, in *HTTPRequest, opts ...grpc.CallOption) (*HTTPReply, error) {
+	out := new(HTTPReply)
+	err := c.cc.Invoke(ctx, "/HTTPOverRPC.HTTPOverRPC/Request", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HTTPOverRPCServer is the server API for HTTPOverRPC service.
+// All implementations should embed UnimplementedHTTPOverRPCServer
+// for forward compatibility
+type HTTPOverRPCServer interface {
+	// Request makes an HTTP request from the host. Redirects aren't followed
+	// so a request can't reach anywhere other than the host, port and path
+	// policy allowed.
+	Request(context.Context, *HTTPRequest) (*HTTPReply, error)
+}
+
+// UnimplementedHTTPOverRPCServer should be embedded to have forward compatible implementations.
+type UnimplementedHTTPOverRPCServer struct {
+}
+
+func (UnimplementedHTTPOverRPCServer) Request(context.Context, *HTTPRequest) (*HTTPReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Request not implemented")
+}
+
+// UnsafeHTTPOverRPCServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HTTPOverRPCServer will
+// result in compilation errors.
+type UnsafeHTTPOverRPCServer interface {
+	mustEmbedUnimplementedHTTPOverRPCServer()
+}
+
+func RegisterHTTPOverRPCServer(s grpc.ServiceRegistrar, srv HTTPOverRPCServer) {
+	s.RegisterService(&HTTPOverRPC_ServiceDesc, srv)
+}
+
+func _HTTPOverRPC_Request_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HTTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HTTPOverRPCServer).Request(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/HTTPOverRPC.HTTPOverRPC/Request",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HTTPOverRPCServer).Request(ctx, req.(*HTTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HTTPOverRPC_ServiceDesc is the grpc.ServiceDesc for HTTPOverRPC service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HTTPOverRPC_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "HTTPOverRPC.HTTPOverRPC",
+	HandlerType: (*HTTPOverRPCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Request",
+			Handler:    _HTTPOverRPC_Request_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "httpoverrpc.proto",
+}
@@ -0,0 +1,168 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'HTTPOverRPC' service.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/httpoverrpc"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	maxTimeout     = 5 * time.Minute
+)
+
+var maxBodySize = flag.Int64("http-max-body-size", 1024*1024, "The largest response body HTTPOverRPC returns. Longer ones are truncated.")
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// headers returns h as protos, sorted by key.
+func headers(h http.Header) []*pb.Header {
+	var keys []string
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var out []*pb.Header
+	for _, k := range keys {
+		out = append(out, &pb.Header{Key: k, Values: h[k]})
+	}
+	return out
+}
+
+// Request implements pb.HTTPOverRPCServer.Request
+func (s *server) Request(ctx context.Context, req *pb.HTTPRequest) (*pb.HTTPReply, error) {
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	host := req.Host
+	if host == "" {
+		host = "localhost"
+	}
+	if req.Port == 0 || req.Port > 65535 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid port %d", req.Port)
+	}
+	if !strings.HasPrefix(req.Path, "/") {
+		return nil, status.Errorf(codes.InvalidArgument, "path %q must start with /", req.Path)
+	}
+	maxBody := *maxBodySize
+	if req.MaxBodySize < 0 || req.MaxBodySize > maxBody {
+		return nil, status.Errorf(codes.InvalidArgument, "max_body_size must be between 0 and %d", maxBody)
+	}
+	if req.MaxBodySize > 0 {
+		maxBody = req.MaxBodySize
+	}
+	timeout := defaultTimeout
+	if req.Timeout != nil {
+		if err := req.Timeout.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid timeout: %v", err)
+		}
+		timeout = req.Timeout.AsDuration()
+		if timeout <= 0 || timeout > maxTimeout {
+			return nil, status.Errorf(codes.InvalidArgument, "timeout must be between 0 and %v", maxTimeout)
+		}
+	}
+
+	scheme := "http"
+	if req.Tls {
+		scheme = "https"
+	}
+	u := &url.URL{
+		Scheme:   scheme,
+		Host:     net.JoinHostPort(host, strconv.Itoa(int(req.Port))),
+		Path:     req.Path,
+		RawQuery: req.Query,
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	for _, h := range req.Headers {
+		for _, v := range h.Values {
+			httpReq.Header.Add(h.Key, v)
+		}
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			// Never go through a proxy from the environment.
+			Proxy:           nil,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: req.InsecureSkipVerify},
+		},
+		// Following redirects could reach somewhere policy didn't allow.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	defer client.CloseIdleConnections()
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, status.Errorf(codes.DeadlineExceeded, "request timed out: %v", err)
+		}
+		return nil, status.Errorf(codes.Unavailable, "request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody+1))
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "can't read response body: %v", err)
+	}
+	reply := &pb.HTTPReply{
+		StatusCode: int32(resp.StatusCode),
+		Headers:    headers(resp.Header),
+		Body:       body,
+	}
+	if int64(len(body)) > maxBody {
+		reply.Body = body[:maxBody]
+		reply.BodyTruncated = true
+	}
+	return reply, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterHTTPOverRPCServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
@@ -0,0 +1,198 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/httpoverrpc"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func testHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/echo":
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Query", r.URL.RawQuery)
+		w.Header()["X-Test"] = r.Header["X-Test"]
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write(body)
+	case "/redirect":
+		http.Redirect(w, r, "/echo", http.StatusFound)
+	case "/slow":
+		time.Sleep(time.Second)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// hostPort returns the host and port of a test server.
+func hostPort(t *testing.T, s *httptest.Server) (string, uint32) {
+	t.Helper()
+	u, err := url.Parse(s.URL)
+	testutil.FatalOnErr("parsing url", err, t)
+	host, port, err := net.SplitHostPort(u.Host)
+	testutil.FatalOnErr("splitting host", err, t)
+	p, err := strconv.Atoi(port)
+	testutil.FatalOnErr("parsing port", err, t)
+	return host, uint32(p)
+}
+
+func TestRequest(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(testHandler))
+	t.Cleanup(plain.Close)
+	secure := httptest.NewTLSServer(http.HandlerFunc(testHandler))
+	t.Cleanup(secure.Close)
+	host, port := hostPort(t, plain)
+	tlsHost, tlsPort := hostPort(t, secure)
+
+	savedMax := *maxBodySize
+	t.Cleanup(func() { *maxBodySize = savedMax })
+	*maxBodySize = 16
+
+	echoHeaders := func(method, query string, test ...string) map[string][]string {
+		h := map[string][]string{"X-Method": {method}, "X-Query": {query}}
+		if len(test) > 0 {
+			h["X-Test"] = test
+		}
+		return h
+	}
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.HTTPRequest
+		want    *pb.HTTPReply
+		headers map[string][]string
+		wantErr codes.Code
+	}{
+		{
+			name:    "get",
+			req:     &pb.HTTPRequest{Host: host, Port: port, Path: "/echo", Query: "a=b"},
+			want:    &pb.HTTPReply{StatusCode: http.StatusAccepted},
+			headers: echoHeaders("GET", "a=b"),
+		},
+		{
+			name: "post with headers",
+			req: &pb.HTTPRequest{
+				Method:  "POST",
+				Host:    host,
+				Port:    port,
+				Path:    "/echo",
+				Headers: []*pb.Header{{Key: "X-Test", Values: []string{"one", "two"}}},
+				Body:    []byte("hello"),
+			},
+			want:    &pb.HTTPReply{StatusCode: http.StatusAccepted, Body: []byte("hello")},
+			headers: echoHeaders("POST", "", "one", "two"),
+		},
+		{
+			name:    "truncated",
+			req:     &pb.HTTPRequest{Method: "POST", Host: host, Port: port, Path: "/echo", Body: []byte("0123456789abcdefXYZ")},
+			want:    &pb.HTTPReply{StatusCode: http.StatusAccepted, Body: []byte("0123456789abcdef"), BodyTruncated: true},
+			headers: echoHeaders("POST", ""),
+		},
+		{
+			name:    "smaller limit",
+			req:     &pb.HTTPRequest{Method: "POST", Host: host, Port: port, Path: "/echo", Body: []byte("hello"), MaxBodySize: 2},
+			want:    &pb.HTTPReply{StatusCode: http.StatusAccepted, Body: []byte("he"), BodyTruncated: true},
+			headers: echoHeaders("POST", ""),
+		},
+		{
+			name: "redirects not followed",
+			req:  &pb.HTTPRequest{Method: "POST", Host: host, Port: port, Path: "/redirect"},
+			want: &pb.HTTPReply{StatusCode: http.StatusFound},
+		},
+		{
+			name:    "tls",
+			req:     &pb.HTTPRequest{Tls: true, InsecureSkipVerify: true, Host: tlsHost, Port: tlsPort, Path: "/echo"},
+			want:    &pb.HTTPReply{StatusCode: http.StatusAccepted},
+			headers: echoHeaders("GET", ""),
+		},
+		{
+			name:    "tls unverified",
+			req:     &pb.HTTPRequest{Tls: true, Host: tlsHost, Port: tlsPort, Path: "/echo"},
+			wantErr: codes.Unavailable,
+		},
+		{
+			name:    "timeout",
+			req:     &pb.HTTPRequest{Host: host, Port: port, Path: "/slow", Timeout: durationpb.New(100 * time.Millisecond)},
+			wantErr: codes.DeadlineExceeded,
+		},
+		{
+			name:    "bad port",
+			req:     &pb.HTTPRequest{Host: host, Path: "/echo"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad path",
+			req:     &pb.HTTPRequest{Host: host, Port: port, Path: "echo"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad method",
+			req:     &pb.HTTPRequest{Method: "GET /", Host: host, Port: port, Path: "/echo"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "large limit",
+			req:     &pb.HTTPRequest{Host: host, Port: port, Path: "/echo", MaxBodySize: 17},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad timeout",
+			req:     &pb.HTTPRequest{Host: host, Port: port, Path: "/echo", Timeout: durationpb.New(time.Hour)},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&server{}).Request(context.Background(), tc.req)
+			if c := status.Code(err); c != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", c, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			// Only check the headers set by the handler.
+			gotHeaders := make(map[string][]string)
+			for _, h := range got.Headers {
+				if strings.HasPrefix(h.Key, "X-") {
+					gotHeaders[h.Key] = h.Values
+				}
+			}
+			if tc.headers == nil {
+				tc.headers = map[string][]string{}
+			}
+			testutil.DiffErr(tc.name+" headers", gotHeaders, tc.headers, t)
+			got.Headers = nil
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'ipmi'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/subcommands"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/ipmi"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "ipmi"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&powerCmd{}, "")
+	c.Register(&selCmd{}, "")
+	c.Register(&sensorsCmd{}, "")
+	return c
+}
+
+const bmcHelp = "The BMC (host name or IP address) for the target to manage over the network. If empty the target's own BMC is used."
+
+type powerCmd struct {
+	bmc string
+}
+
+func (*powerCmd) Name() string     { return "power" }
+func (*powerCmd) Synopsis() string { return "Get or change the chassis power state" }
+func (*powerCmd) Usage() string {
+	return `power [--bmc=X] status|on|off|cycle|reset|soft:
+    Report or change the chassis power state. off, cycle and reset are hard
+    and don't wait for the OS; soft asks it to shut down. To recover a hung
+    host, target a healthy host which can reach its BMC and pass --bmc.
+`
+}
+
+func (p *powerCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.bmc, "bmc", "", bmcHelp)
+}
+
+func (p *powerCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Please specify status or an action.")
+		return subcommands.ExitUsageError
+	}
+	c := pb.NewIPMIClientProxy(state.Conn)
+	retCode := subcommands.ExitSuccess
+
+	if f.Arg(0) == "status" {
+		respChan, err := c.PowerStatusOneMany(ctx, &pb.PowerStatusRequest{Bmc: p.bmc})
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "Could not execute: %v\n", err)
+			}
+			return subcommands.ExitFailure
+		}
+		for r := range respChan {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "PowerStatus for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			power := "off"
+			if r.Resp.On {
+				power = "on"
+			}
+			fmt.Fprintf(state.Out[r.Index], "power is %s\n", power)
+		}
+		return retCode
+	}
+
+	action, ok := pb.PowerAction_value["POWER_ACTION_"+strings.ToUpper(f.Arg(0))]
+	if !ok || action == int32(pb.PowerAction_POWER_ACTION_UNKNOWN) {
+		fmt.Fprintf(os.Stderr, "Unknown action %s\n", f.Arg(0))
+		return subcommands.ExitUsageError
+	}
+	respChan, err := c.PowerControlOneMany(ctx, &pb.PowerControlRequest{Bmc: p.bmc, Action: pb.PowerAction(action)})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "PowerControl for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
+
+type selCmd struct {
+	bmc        string
+	maxEntries uint
+}
+
+func (*selCmd) Name() string     { return "sel" }
+func (*selCmd) Synopsis() string { return "Print the System Event Log" }
+func (*selCmd) Usage() string {
+	return `sel [--bmc=X] [--max-entries=N]:
+    Print the most recent entries of the BMC's System Event Log.
+`
+}
+
+func (s *selCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&s.bmc, "bmc", "", bmcHelp)
+	f.UintVar(&s.maxEntries, "max-entries", 0, "How many entries to print. If unset the remote side picks (100)")
+}
+
+func (s *selCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewIPMIClientProxy(state.Conn)
+	respChan, err := c.SELOneMany(ctx, &pb.SELRequest{Bmc: s.bmc, MaxEntries: uint32(s.maxEntries)})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "SEL for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, e := range r.Resp.Entries {
+			fmt.Fprintf(state.Out[r.Index], "%s | %s | %s | %s | %s\n", e.Id, e.Timestamp, e.Sensor, e.Event, e.Direction)
+		}
+	}
+	return retCode
+}
+
+type sensorsCmd struct {
+	bmc string
+}
+
+func (*sensorsCmd) Name() string     { return "sensors" }
+func (*sensorsCmd) Synopsis() string { return "Print sensor readings" }
+func (*sensorsCmd) Usage() string {
+	return `sensors [--bmc=X]:
+    Print the BMC's sensor readings as name, value, unit and status.
+`
+}
+
+func (s *sensorsCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&s.bmc, "bmc", "", bmcHelp)
+}
+
+func (s *sensorsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewIPMIClientProxy(state.Conn)
+	respChan, err := c.SensorsOneMany(ctx, &pb.SensorsRequest{Bmc: s.bmc})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "Sensors for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, s := range r.Resp.Sensors {
+			value := s.Value
+			if value == "" {
+				value = "-"
+			}
+			fmt.Fprintf(state.Out[r.Index], "%s\t%s\t%s\t%s\n", s.Name, value, s.Unit, s.Status)
+		}
+	}
+	return retCode
+}
@@ -0,0 +1,915 @@
+// Copyright (c) 2019 Snowflake Inc. All rights reserved.
+//
+//Licensed under the Apache License, Version 2.0 (the
+//"License"); you may not use this file except in compliance
+//with the License.  You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing,
+//software distributed under the License is distributed on an
+//"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+//KIND, either express or implied.  See the License for the
+//specific language governing permissions and limitations
+//under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: ipmi.proto
+
+package ipmi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PowerAction int32
+
+const (
+	PowerAction_POWER_ACTION_UNKNOWN PowerAction = 0
+	PowerAction_POWER_ACTION_ON      PowerAction = 1
+	// Hard power off, without waiting for the OS.
+	PowerAction_POWER_ACTION_OFF PowerAction = 2
+	// Off and on again.
+	PowerAction_POWER_ACTION_CYCLE PowerAction = 3
+	// Hard reset.
+	PowerAction_POWER_ACTION_RESET PowerAction = 4
+	// Ask the OS to shut down (i.e. by ACPI).
+	PowerAction_POWER_ACTION_SOFT PowerAction = 5
+)
+
+// Enum value maps for PowerAction.
+var (
+	PowerAction_name = map[int32]string{
+		0: "POWER_ACTION_UNKNOWN",
+		1: "POWER_ACTION_ON",
+		2: "POWER_ACTION_OFF",
+		3: "POWER_ACTION_CYCLE",
+		4: "POWER_ACTION_RESET",
+		5: "POWER_ACTION_SOFT",
+	}
+	PowerAction_value = map[string]int32{
+		"POWER_ACTION_UNKNOWN": 0,
+		"POWER_ACTION_ON":      1,
+		"POWER_ACTION_OFF":     2,
+		"POWER_ACTION_CYCLE":   3,
+		"POWER_ACTION_RESET":   4,
+		"POWER_ACTION_SOFT":    5,
+	}
+)
+
+func (x PowerAction) Enum() *PowerAction {
+	p := new(PowerAction)
+	*p = x
+	return p
+}
+
+func (x PowerAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PowerAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_ipmi_proto_enumTypes[0].Descriptor()
+}
+
+func (PowerAction) Type() protoreflect.EnumType {
+	return &file_ipmi_proto_enumTypes[0]
+}
+
+func (x PowerAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PowerAction.Descriptor instead.
+func (PowerAction) EnumDescriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{0}
+}
+
+type PowerStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The BMC (host name or IP address) to query over the network. If empty
+	// the local BMC is queried in-band.
+	Bmc string `protobuf:"bytes,1,opt,name=bmc,proto3" json:"bmc,omitempty"`
+}
+
+func (x *PowerStatusRequest) Reset() {
+	*x = PowerStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipmi_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PowerStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerStatusRequest) ProtoMessage() {}
+
+func (x *PowerStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ipmi_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerStatusRequest.ProtoReflect.Descriptor instead.
+func (*PowerStatusRequest) Descriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PowerStatusRequest) GetBmc() string {
+	if x != nil {
+		return x.Bmc
+	}
+	return ""
+}
+
+type PowerStatusReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	On bool `protobuf:"varint,1,opt,name=on,proto3" json:"on,omitempty"`
+}
+
+func (x *PowerStatusReply) Reset() {
+	*x = PowerStatusReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipmi_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PowerStatusReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerStatusReply) ProtoMessage() {}
+
+func (x *PowerStatusReply) ProtoReflect() protoreflect.Message {
+	mi := &file_ipmi_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerStatusReply.ProtoReflect.Descriptor instead.
+func (*PowerStatusReply) Descriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PowerStatusReply) GetOn() bool {
+	if x != nil {
+		return x.On
+	}
+	return false
+}
+
+type PowerControlRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// As with PowerStatusRequest.
+	Bmc    string      `protobuf:"bytes,1,opt,name=bmc,proto3" json:"bmc,omitempty"`
+	Action PowerAction `protobuf:"varint,2,opt,name=action,proto3,enum=IPMI.PowerAction" json:"action,omitempty"`
+}
+
+func (x *PowerControlRequest) Reset() {
+	*x = PowerControlRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipmi_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PowerControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerControlRequest) ProtoMessage() {}
+
+func (x *PowerControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ipmi_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerControlRequest.ProtoReflect.Descriptor instead.
+func (*PowerControlRequest) Descriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PowerControlRequest) GetBmc() string {
+	if x != nil {
+		return x.Bmc
+	}
+	return ""
+}
+
+func (x *PowerControlRequest) GetAction() PowerAction {
+	if x != nil {
+		return x.Action
+	}
+	return PowerAction_POWER_ACTION_UNKNOWN
+}
+
+type PowerControlReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PowerControlReply) Reset() {
+	*x = PowerControlReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipmi_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PowerControlReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerControlReply) ProtoMessage() {}
+
+func (x *PowerControlReply) ProtoReflect() protoreflect.Message {
+	mi := &file_ipmi_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerControlReply.ProtoReflect.Descriptor instead.
+func (*PowerControlReply) Descriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{3}
+}
+
+type SELRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// As with PowerStatusRequest.
+	Bmc string `protobuf:"bytes,1,opt,name=bmc,proto3" json:"bmc,omitempty"`
+	// Only the most recent this many entries are returned. Defaults to 100.
+	MaxEntries uint32 `protobuf:"varint,2,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+}
+
+func (x *SELRequest) Reset() {
+	*x = SELRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipmi_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SELRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SELRequest) ProtoMessage() {}
+
+func (x *SELRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ipmi_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SELRequest.ProtoReflect.Descriptor instead.
+func (*SELRequest) Descriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SELRequest) GetBmc() string {
+	if x != nil {
+		return x.Bmc
+	}
+	return ""
+}
+
+func (x *SELRequest) GetMaxEntries() uint32 {
+	if x != nil {
+		return x.MaxEntries
+	}
+	return 0
+}
+
+type SELEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The record ID.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The date and time as the BMC reports it, which is usually in its own
+	// local time.
+	Timestamp string `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// i.e. "Power Supply #0x51"
+	Sensor string `protobuf:"bytes,3,opt,name=sensor,proto3" json:"sensor,omitempty"`
+	// i.e. "Failure detected"
+	Event string `protobuf:"bytes,4,opt,name=event,proto3" json:"event,omitempty"`
+	// Asserted or Deasserted.
+	Direction string `protobuf:"bytes,5,opt,name=direction,proto3" json:"direction,omitempty"`
+}
+
+func (x *SELEntry) Reset() {
+	*x = SELEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipmi_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SELEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SELEntry) ProtoMessage() {}
+
+func (x *SELEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_ipmi_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SELEntry.ProtoReflect.Descriptor instead.
+func (*SELEntry) Descriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SELEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SELEntry) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *SELEntry) GetSensor() string {
+	if x != nil {
+		return x.Sensor
+	}
+	return ""
+}
+
+func (x *SELEntry) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *SELEntry) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+type SELReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*SELEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *SELReply) Reset() {
+	*x = SELReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipmi_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SELReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SELReply) ProtoMessage() {}
+
+func (x *SELReply) ProtoReflect() protoreflect.Message {
+	mi := &file_ipmi_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SELReply.ProtoReflect.Descriptor instead.
+func (*SELReply) Descriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SELReply) GetEntries() []*SELEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type SensorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// As with PowerStatusRequest.
+	Bmc string `protobuf:"bytes,1,opt,name=bmc,proto3" json:"bmc,omitempty"`
+}
+
+func (x *SensorsRequest) Reset() {
+	*x = SensorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipmi_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SensorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SensorsRequest) ProtoMessage() {}
+
+func (x *SensorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ipmi_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SensorsRequest.ProtoReflect.Descriptor instead.
+func (*SensorsRequest) Descriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SensorsRequest) GetBmc() string {
+	if x != nil {
+		return x.Bmc
+	}
+	return ""
+}
+
+type Sensor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The reading, i.e. "45.000", or empty if there isn't one.
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// i.e. "degrees C" or "discrete".
+	Unit string `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+	// The state as ipmitool reports it, i.e. ok, nc (non-critical), cr
+	// (critical) or nr (non-recoverable), or a hex value for discrete sensors.
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *Sensor) Reset() {
+	*x = Sensor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipmi_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Sensor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sensor) ProtoMessage() {}
+
+func (x *Sensor) ProtoReflect() protoreflect.Message {
+	mi := &file_ipmi_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sensor.ProtoReflect.Descriptor instead.
+func (*Sensor) Descriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Sensor) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Sensor) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Sensor) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Sensor) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type SensorsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sensors []*Sensor `protobuf:"bytes,1,rep,name=sensors,proto3" json:"sensors,omitempty"`
+}
+
+func (x *SensorsReply) Reset() {
+	*x = SensorsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipmi_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SensorsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SensorsReply) ProtoMessage() {}
+
+func (x *SensorsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_ipmi_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SensorsReply.ProtoReflect.Descriptor instead.
+func (*SensorsReply) Descriptor() ([]byte, []int) {
+	return file_ipmi_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SensorsReply) GetSensors() []*Sensor {
+	if x != nil {
+		return x.Sensors
+	}
+	return nil
+}
+
+var File_ipmi_proto protoreflect.FileDescriptor
+
+var file_ipmi_proto_rawDesc = []byte{
+	0x0a, 0x0a, 0x69, 0x70, 0x6d, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x49, 0x50,
+	0x4d, 0x49, 0x22, 0x26, 0x0a, 0x12, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x6d, 0x63, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x62, 0x6d, 0x63, 0x22, 0x22, 0x0a, 0x10, 0x50, 0x6f,
+	0x77, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x0e,
+	0x0a, 0x02, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x6f, 0x6e, 0x22, 0x52,
+	0x0a, 0x13, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x6d, 0x63, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x62, 0x6d, 0x63, 0x12, 0x29, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x49, 0x50, 0x4d, 0x49, 0x2e, 0x50,
+	0x6f, 0x77, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x13, 0x0a, 0x11, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x3f, 0x0a, 0x0a, 0x53, 0x45, 0x4c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x6d, 0x63, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x62, 0x6d, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x65,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x6d, 0x61,
+	0x78, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x08, 0x53, 0x45, 0x4c,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0x34, 0x0a, 0x08, 0x53, 0x45, 0x4c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x28, 0x0a, 0x07, 0x65,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x49,
+	0x50, 0x4d, 0x49, 0x2e, 0x53, 0x45, 0x4c, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e,
+	0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x22, 0x0a, 0x0e, 0x53, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x6d, 0x63, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x62, 0x6d, 0x63, 0x22, 0x5e, 0x0a, 0x06, 0x53, 0x65, 0x6e,
+	0x73, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x36, 0x0a, 0x0c, 0x53, 0x65, 0x6e,
+	0x73, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x26, 0x0a, 0x07, 0x73, 0x65, 0x6e,
+	0x73, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x49, 0x50, 0x4d,
+	0x49, 0x2e, 0x53, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x52, 0x07, 0x73, 0x65, 0x6e, 0x73, 0x6f, 0x72,
+	0x73, 0x2a, 0x99, 0x01, 0x0a, 0x0b, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x4f, 0x57, 0x45, 0x52, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f,
+	0x4e, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x50,
+	0x4f, 0x57, 0x45, 0x52, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x4e, 0x10, 0x01,
+	0x12, 0x14, 0x0a, 0x10, 0x50, 0x4f, 0x57, 0x45, 0x52, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x4f, 0x46, 0x46, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x50, 0x4f, 0x57, 0x45, 0x52, 0x5f,
+	0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x59, 0x43, 0x4c, 0x45, 0x10, 0x03, 0x12, 0x16,
+	0x0a, 0x12, 0x50, 0x4f, 0x57, 0x45, 0x52, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52,
+	0x45, 0x53, 0x45, 0x54, 0x10, 0x04, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x4f, 0x57, 0x45, 0x52, 0x5f,
+	0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x4f, 0x46, 0x54, 0x10, 0x05, 0x32, 0xf1, 0x01,
+	0x0a, 0x04, 0x49, 0x50, 0x4d, 0x49, 0x12, 0x41, 0x0a, 0x0b, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x49, 0x50, 0x4d, 0x49, 0x2e, 0x50, 0x6f, 0x77,
+	0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x49, 0x50, 0x4d, 0x49, 0x2e, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x0c, 0x50, 0x6f, 0x77,
+	0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x19, 0x2e, 0x49, 0x50, 0x4d, 0x49,
+	0x2e, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x49, 0x50, 0x4d, 0x49, 0x2e, 0x50, 0x6f, 0x77, 0x65,
+	0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
+	0x29, 0x0a, 0x03, 0x53, 0x45, 0x4c, 0x12, 0x10, 0x2e, 0x49, 0x50, 0x4d, 0x49, 0x2e, 0x53, 0x45,
+	0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x49, 0x50, 0x4d, 0x49, 0x2e,
+	0x53, 0x45, 0x4c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x07, 0x53, 0x65,
+	0x6e, 0x73, 0x6f, 0x72, 0x73, 0x12, 0x14, 0x2e, 0x49, 0x50, 0x4d, 0x49, 0x2e, 0x53, 0x65, 0x6e,
+	0x73, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x49, 0x50,
+	0x4d, 0x49, 0x2e, 0x53, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
+	0x00, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x53, 0x6e, 0x6f, 0x77, 0x66, 0x6c, 0x61, 0x6b, 0x65, 0x2d, 0x4c, 0x61, 0x62, 0x73, 0x2f, 0x73,
+	0x61, 0x6e, 0x73, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2f, 0x69, 0x70, 0x6d, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_ipmi_proto_rawDescOnce sync.Once
+	file_ipmi_proto_rawDescData = file_ipmi_proto_rawDesc
+)
+
+func file_ipmi_proto_rawDescGZIP() []byte {
+	file_ipmi_proto_rawDescOnce.Do(func() {
+		file_ipmi_proto_rawDescData = protoimpl.X.CompressGZIP(file_ipmi_proto_rawDescData)
+	})
+	return file_ipmi_proto_rawDescData
+}
+
+var file_ipmi_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_ipmi_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_ipmi_proto_goTypes = []interface{}{
+	(PowerAction)(0),            // 0: IPMI.PowerAction
+	(*PowerStatusRequest)(nil),  // 1: IPMI.PowerStatusRequest
+	(*PowerStatusReply)(nil),    // 2: IPMI.PowerStatusReply
+	(*PowerControlRequest)(nil), // 3: IPMI.PowerControlRequest
+	(*PowerControlReply)(nil),   // 4: IPMI.PowerControlReply
+	(*SELRequest)(nil),          // 5: IPMI.SELRequest
+	(*SELEntry)(nil),            // 6: IPMI.SELEntry
+	(*SELReply)(nil),            // 7: IPMI.SELReply
+	(*SensorsRequest)(nil),      // 8: IPMI.SensorsRequest
+	(*Sensor)(nil),              // 9: IPMI.Sensor
+	(*SensorsReply)(nil),        // 10: IPMI.SensorsReply
+}
+var file_ipmi_proto_depIdxs = []int32{
+	0,  // 0: IPMI.PowerControlRequest.action:type_name -> IPMI.PowerAction
+	6,  // 1: IPMI.SELReply.entries:type_name -> IPMI.SELEntry
+	9,  // 2: IPMI.SensorsReply.sensors:type_name -> IPMI.Sensor
+	1,  // 3: IPMI.IPMI.PowerStatus:input_type -> IPMI.PowerStatusRequest
+	3,  // 4: IPMI.IPMI.PowerControl:input_type -> IPMI.PowerControlRequest
+	5,  // 5: IPMI.IPMI.SEL:input_type -> IPMI.SELRequest
+	8,  // 6: IPMI.IPMI.Sensors:input_type -> IPMI.SensorsRequest
+	2,  // 7: IPMI.IPMI.PowerStatus:output_type -> IPMI.PowerStatusReply
+	4,  // 8: IPMI.IPMI.PowerControl:output_type -> IPMI.PowerControlReply
+	7,  // 9: IPMI.IPMI.SEL:output_type -> IPMI.SELReply
+	10, // 10: IPMI.IPMI.Sensors:output_type -> IPMI.SensorsReply
+	7,  // [7:11] is the sub-list for method output_type
+	3,  // [3:7] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_ipmi_proto_init() }
+func file_ipmi_proto_init() {
+	if File_ipmi_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_ipmi_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PowerStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipmi_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PowerStatusReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipmi_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PowerControlRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipmi_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PowerControlReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipmi_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SELRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipmi_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SELEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipmi_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SELReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipmi_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SensorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipmi_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Sensor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipmi_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SensorsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_ipmi_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ipmi_proto_goTypes,
+		DependencyIndexes: file_ipmi_proto_depIdxs,
+		EnumInfos:         file_ipmi_proto_enumTypes,
+		MessageInfos:      file_ipmi_proto_msgTypes,
+	}.Build()
+	File_ipmi_proto = out.File
+	file_ipmi_proto_rawDesc = nil
+	file_ipmi_proto_goTypes = nil
+	file_ipmi_proto_depIdxs = nil
+}
@@ -0,0 +1,215 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package ipmi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// IPMIClient is the client API for IPMI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type IPMIClient interface {
+	// PowerStatus returns whether the chassis is powered on.
+	PowerStatus(ctx context.Context, in *PowerStatusRequest, opts ...grpc.CallOption) (*PowerStatusReply, error)
+	// PowerControl changes the chassis power state.
+	PowerControl(ctx context.Context, in *PowerControlRequest, opts ...grpc.CallOption) (*PowerControlReply, error)
+	// SEL returns entries from the System Event Log.
+	SEL(ctx context.Context, in *SELRequest, opts ...grpc.CallOption) (*SELReply, error)
+	// Sensors returns sensor readings.
+	Sensors(ctx context.Context, in *SensorsRequest, opts ...grpc.CallOption) (*SensorsReply, error)
+}
+
+type iPMIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIPMIClient(cc grpc.ClientConnInterface) IPMIClient {
+	return &iPMIClient{cc}
+}
+
+func (c *iPMIClient) PowerStatus(ctx context.Context, in *PowerStatusRequest, opts ...grpc.CallOption) (*PowerStatusReply, error) {
+	out := new(PowerStatusReply)
+	err := c.cc.Invoke(ctx, "/IPMI.IPMI/PowerStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iPMIClient) PowerControl(ctx context.Context, in *PowerControlRequest, opts ...grpc.CallOption) (*PowerControlReply, error) {
+	out := new(PowerControlReply)
+	err := c.cc.Invoke(ctx, "/IPMI.IPMI/PowerControl", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iPMIClient) SEL(ctx context.Context, in *SELRequest, opts ...grpc.CallOption) (*SELReply, error) {
+	out := new(SELReply)
+	err := c.cc.Invoke(ctx, "/IPMI.IPMI/SEL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iPMIClient) Sensors(ctx context.Context, in *SensorsRequest, opts ...grpc.CallOption) (*SensorsReply, error) {
+	out := new(SensorsReply)
+	err := c.cc.Invoke(ctx, "/IPMI.IPMI/Sensors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IPMIServer is the server API for IPMI service.
+// All implementations should embed UnimplementedIPMIServer
+// for forward compatibility
+type IPMIServer interface {
+	// PowerStatus returns whether the chassis is powered on.
+	PowerStatus(context.Context, *PowerStatusRequest) (*PowerStatusReply, error)
+	// PowerControl changes the chassis power state.
+	PowerControl(context.Context, *PowerControlRequest) (*PowerControlReply, error)
+	// SEL returns entries from the System Event Log.
+	SEL(context.Context, *SELRequest) (*SELReply, error)
+	// Sensors returns sensor readings.
+	Sensors(context.Context, *SensorsRequest) (*SensorsReply, error)
+}
+
+// UnimplementedIPMIServer should be embedded to have forward compatible implementations.
+type UnimplementedIPMIServer struct {
+}
+
+func (UnimplementedIPMIServer) PowerStatus(context.Context, *PowerStatusRequest) (*PowerStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PowerStatus not implemented")
+}
+func (UnimplementedIPMIServer) PowerControl(context.Context, *PowerControlRequest) (*PowerControlReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PowerControl not implemented")
+}
+func (UnimplementedIPMIServer) SEL(context.Context, *SELRequest) (*SELReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SEL not implemented")
+}
+func (UnimplementedIPMIServer) Sensors(context.Context, *SensorsRequest) (*SensorsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sensors not implemented")
+}
+
+// UnsafeIPMIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IPMIServer will
+// result in compilation errors.
+type UnsafeIPMIServer interface {
+	mustEmbedUnimplementedIPMIServer()
+}
+
+func RegisterIPMIServer(s grpc.ServiceRegistrar, srv IPMIServer) {
+	s.RegisterService(&IPMI_ServiceDesc, srv)
+}
+
+func _IPMI_PowerStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PowerStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPMIServer).PowerStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/IPMI.IPMI/PowerStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPMIServer).PowerStatus(ctx, req.(*PowerStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IPMI_PowerControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PowerControlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPMIServer).PowerControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/IPMI.IPMI/PowerControl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPMIServer).PowerControl(ctx, req.(*PowerControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IPMI_SEL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SELRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPMIServer).SEL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/IPMI.IPMI/SEL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPMIServer).SEL(ctx, req.(*SELRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IPMI_Sensors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SensorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPMIServer).Sensors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/IPMI.IPMI/Sensors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPMIServer).Sensors(ctx, req.(*SensorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IPMI_ServiceDesc is the grpc.ServiceDesc for IPMI service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IPMI_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "IPMI.IPMI",
+	HandlerType: (*IPMIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PowerStatus",
+			Handler:    _IPMI_PowerStatus_Handler,
+		},
+		{
+			MethodName: "PowerControl",
+			Handler:    _IPMI_PowerControl_Handler,
+		},
+		{
+			MethodName: "SEL",
+			Handler:    _IPMI_SEL_Handler,
+		},
+		{
+			MethodName: "Sensors",
+			Handler:    _IPMI_Sensors_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ipmi.proto",
+}
@@ -0,0 +1,304 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package ipmi
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// IPMIClientProxy is the superset of IPMIClient which additionally includes the OneMany proxy methods
+type IPMIClientProxy interface {
+	IPMIClient
+	PowerStatusOneMany(ctx context.Context, in *PowerStatusRequest, opts ...grpc.CallOption) (<-chan *PowerStatusManyResponse, error)
+	PowerControlOneMany(ctx context.Context, in *PowerControlRequest, opts ...grpc.CallOption) (<-chan *PowerControlManyResponse, error)
+	SELOneMany(ctx context.Context, in *SELRequest, opts ...grpc.CallOption) (<-chan *SELManyResponse, error)
+	SensorsOneMany(ctx context.Context, in *SensorsRequest, opts ...grpc.CallOption) (<-chan *SensorsManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type iPMIClientProxy struct {
+	*iPMIClient
+}
+
+// NewIPMIClientProxy creates a IPMIClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewIPMIClientProxy(cc *proxy.Conn) IPMIClientProxy {
+	return &iPMIClientProxy{NewIPMIClient(cc).(*iPMIClient)}
+}
+
+// PowerStatusManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type PowerStatusManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *PowerStatusReply
+	Error error
+}
+
+// PowerStatusOneMany provides the same API as PowerStatus but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *iPMIClientProxy) PowerStatusOneMany(ctx context.Context, in *PowerStatusRequest, opts ...grpc.CallOption) (<-chan *PowerStatusManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *PowerStatusManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &PowerStatusManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &PowerStatusReply{},
+			}
+			err := conn.Invoke(ctx, "/IPMI.IPMI/PowerStatus", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/IPMI.IPMI/PowerStatus", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &PowerStatusManyResponse{
+				Resp: &PowerStatusReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// PowerControlManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type PowerControlManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *PowerControlReply
+	Error error
+}
+
+// PowerControlOneMany provides the same API as PowerControl but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *iPMIClientProxy) PowerControlOneMany(ctx context.Context, in *PowerControlRequest, opts ...grpc.CallOption) (<-chan *PowerControlManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *PowerControlManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &PowerControlManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &PowerControlReply{},
+			}
+			err := conn.Invoke(ctx, "/IPMI.IPMI/PowerControl", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/IPMI.IPMI/PowerControl", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &PowerControlManyResponse{
+				Resp: &PowerControlReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// SELManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type SELManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *SELReply
+	Error error
+}
+
+// SELOneMany provides the same API as SEL but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *iPMIClientProxy) SELOneMany(ctx context.Context, in *SELRequest, opts ...grpc.CallOption) (<-chan *SELManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *SELManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &SELManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &SELReply{},
+			}
+			err := conn.Invoke(ctx, "/IPMI.IPMI/SEL", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/IPMI.IPMI/SEL", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &SELManyResponse{
+				Resp: &SELReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// SensorsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type SensorsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *SensorsReply
+	Error error
+}
+
+// SensorsOneMany provides the same API as Sensors but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *iPMIClientProxy) SensorsOneMany(ctx context.Context, in *SensorsRequest, opts ...grpc.CallOption) (<-chan *SensorsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *SensorsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &SensorsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &SensorsReply{},
+			}
+			err := conn.Invoke(ctx, "/IPMI.IPMI/Sensors", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/IPMI.IPMI/Sensors", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &SensorsManyResponse{
+				Resp: &SensorsReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
@@ -0,0 +1,211 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'IPMI' service.
+package server
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/ipmi"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+var (
+	ipmiInterface    = flag.String("ipmi-interface", "lanplus", "The ipmitool interface used to talk to remote BMCs")
+	ipmiUser         = flag.String("ipmi-user", "", "The user to authenticate to remote BMCs as. If empty only the local BMC can be used.")
+	ipmiPasswordFile = flag.String("ipmi-password-file", "", "File containing the password for --ipmi-user")
+
+	// validBMC matches host names and IP addresses.
+	validBMC = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.:-]*$`)
+
+	powerActions = map[pb.PowerAction]string{
+		pb.PowerAction_POWER_ACTION_ON:    "on",
+		pb.PowerAction_POWER_ACTION_OFF:   "off",
+		pb.PowerAction_POWER_ACTION_CYCLE: "cycle",
+		pb.PowerAction_POWER_ACTION_RESET: "reset",
+		pb.PowerAction_POWER_ACTION_SOFT:  "soft",
+	}
+)
+
+// defaultSELEntries is how many SEL entries are returned by default.
+const defaultSELEntries = 100
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// ipmitool runs ipmitool with args against bmc (or the local BMC if empty)
+// and returns its output.
+func ipmitool(ctx context.Context, bmc string, args ...string) (string, error) {
+	if *ipmitoolBin == "" {
+		return "", status.Error(codes.Unimplemented, "IPMI is not supported on this platform")
+	}
+	if bmc != "" {
+		if !validBMC.MatchString(bmc) {
+			return "", status.Errorf(codes.InvalidArgument, "invalid BMC %q", bmc)
+		}
+		if *ipmiUser == "" || *ipmiPasswordFile == "" {
+			return "", status.Error(codes.FailedPrecondition, "no credentials are configured for remote BMCs")
+		}
+		// -f so the password isn't visible in the process list.
+		args = append([]string{"-I", *ipmiInterface, "-H", bmc, "-U", *ipmiUser, "-f", *ipmiPasswordFile}, args...)
+	}
+	run, err := util.RunCommand(ctx, *ipmitoolBin, args)
+	if err != nil {
+		return "", err
+	}
+	if err := run.Error; run.ExitCode != 0 || err != nil {
+		stderr := strings.TrimSpace(util.TrimString(run.Stderr.String()))
+		if bmc != "" && strings.Contains(stderr, "Unable to establish") {
+			return "", status.Errorf(codes.Unavailable, "can't connect to BMC %s: %s", bmc, stderr)
+		}
+		return "", status.Errorf(codes.Internal, "ipmitool failed: %v: %s", err, stderr)
+	}
+	return run.Stdout.String(), nil
+}
+
+// splitColumns splits a line of ipmitool's | separated output.
+func splitColumns(line string) []string {
+	cols := strings.Split(line, "|")
+	for i := range cols {
+		cols[i] = strings.TrimSpace(cols[i])
+	}
+	return cols
+}
+
+// PowerStatus implements pb.IPMIServer.PowerStatus
+func (s *server) PowerStatus(ctx context.Context, req *pb.PowerStatusRequest) (*pb.PowerStatusReply, error) {
+	out, err := ipmitool(ctx, req.Bmc, "chassis", "power", "status")
+	if err != nil {
+		return nil, err
+	}
+	// i.e. Chassis Power is on
+	switch out = strings.TrimSpace(out); {
+	case strings.HasSuffix(out, " on"):
+		return &pb.PowerStatusReply{On: true}, nil
+	case strings.HasSuffix(out, " off"):
+		return &pb.PowerStatusReply{On: false}, nil
+	}
+	return nil, status.Errorf(codes.Internal, "unexpected power status %q", util.TrimString(out))
+}
+
+// PowerControl implements pb.IPMIServer.PowerControl
+func (s *server) PowerControl(ctx context.Context, req *pb.PowerControlRequest) (*pb.PowerControlReply, error) {
+	action, ok := powerActions[req.Action]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid power action %v", req.Action)
+	}
+	logr.FromContextOrDiscard(ctx).Info("chassis power control", "bmc", req.Bmc, "action", action)
+	if _, err := ipmitool(ctx, req.Bmc, "chassis", "power", action); err != nil {
+		return nil, err
+	}
+	return &pb.PowerControlReply{}, nil
+}
+
+// parseSEL parses the output of ipmitool sel elist, i.e.
+//
+//	1 | 06/15/2022 | 10:30:45 | Power Supply #0x51 | Failure detected | Asserted
+func parseSEL(out string) []*pb.SELEntry {
+	var entries []*pb.SELEntry
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		cols := splitColumns(scanner.Text())
+		if len(cols) < 5 {
+			continue
+		}
+		e := &pb.SELEntry{
+			Id:        cols[0],
+			Timestamp: strings.TrimSpace(cols[1] + " " + cols[2]),
+			Sensor:    cols[3],
+			Event:     cols[4],
+		}
+		if len(cols) > 5 {
+			e.Direction = cols[5]
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// SEL implements pb.IPMIServer.SEL
+func (s *server) SEL(ctx context.Context, req *pb.SELRequest) (*pb.SELReply, error) {
+	max := int(req.MaxEntries)
+	if max == 0 {
+		max = defaultSELEntries
+	}
+	out, err := ipmitool(ctx, req.Bmc, "sel", "elist")
+	if err != nil {
+		return nil, err
+	}
+	entries := parseSEL(out)
+	if len(entries) > max {
+		entries = entries[len(entries)-max:]
+	}
+	return &pb.SELReply{Entries: entries}, nil
+}
+
+// parseSensors parses the output of ipmitool sensor, i.e.
+//
+//	CPU Temp         | 45.000     | degrees C  | ok    | na | 0.000 | ...
+func parseSensors(out string) []*pb.Sensor {
+	var sensors []*pb.Sensor
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		cols := splitColumns(scanner.Text())
+		if len(cols) < 4 || cols[0] == "" {
+			continue
+		}
+		s := &pb.Sensor{
+			Name:   cols[0],
+			Value:  cols[1],
+			Unit:   cols[2],
+			Status: cols[3],
+		}
+		if s.Value == "na" {
+			s.Value = ""
+		}
+		sensors = append(sensors, s)
+	}
+	return sensors
+}
+
+// Sensors implements pb.IPMIServer.Sensors
+func (s *server) Sensors(ctx context.Context, req *pb.SensorsRequest) (*pb.SensorsReply, error) {
+	out, err := ipmitool(ctx, req.Bmc, "sensor")
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SensorsReply{Sensors: parseSensors(out)}, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterIPMIServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
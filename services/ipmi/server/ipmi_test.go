@@ -0,0 +1,236 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/ipmi"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const (
+	selOutput = `   1 | 06/14/2022 | 08:00:01 | Event Logging Disabled #0x07 | Log area reset/cleared | Asserted
+   2 | 06/15/2022 | 10:30:45 | Power Supply #0x51 | Failure detected | Asserted
+   3 | Pre-Init  |  0000001234 | System Event #0x83 | Timestamp Clock Sync
+`
+	sensorOutput = `CPU Temp         | 45.000     | degrees C  | ok    | na        | 0.000     | 5.000     | 90.000    | 95.000    | na
+FAN1             | na         | RPM        | na    | na        | na        | na        | na        | na        | na
+PS1 Status       | 0x1        | discrete   | 0x0100| na        | na        | na        | na        | na        | na
+`
+)
+
+// fakeIPMITool installs an ipmitool script recording its arguments to the
+// returned file, one per line. It prints canned output for known commands
+// and otherwise fails with stderr.
+func fakeIPMITool(t *testing.T, stderr string) string {
+	t.Helper()
+	saved := *ipmitoolBin
+	t.Cleanup(func() { *ipmitoolBin = saved })
+	dir := t.TempDir()
+	args := filepath.Join(dir, "args")
+	write := func(name, contents string) string {
+		f := filepath.Join(dir, name)
+		testutil.FatalOnErr("writing "+name, os.WriteFile(f, []byte(contents), 0644), t)
+		return f
+	}
+	sel := write("sel", selOutput)
+	sensor := write("sensor", sensorOutput)
+	script := fmt.Sprintf(`#!/bin/sh
+printf '%%s\n' "$@" > %s
+case "$*" in
+*"chassis power status") echo "Chassis Power is on" ;;
+*"chassis power "*) echo "Chassis Power Control: Up/On" ;;
+*"sel elist") cat %s ;;
+*sensor) cat %s ;;
+*) echo %q >&2; exit 1 ;;
+esac
+`, args, sel, sensor, stderr)
+	*ipmitoolBin = write("ipmitool", script)
+	testutil.FatalOnErr("chmod", os.Chmod(*ipmitoolBin, 0755), t)
+	return args
+}
+
+// withCredentials sets the remote BMC credentials flags.
+func withCredentials(t *testing.T, user string, passwordFile string) {
+	t.Helper()
+	savedUser, savedFile := *ipmiUser, *ipmiPasswordFile
+	t.Cleanup(func() {
+		*ipmiUser = savedUser
+		*ipmiPasswordFile = savedFile
+	})
+	*ipmiUser = user
+	*ipmiPasswordFile = passwordFile
+}
+
+func readArgs(t *testing.T, file string) []string {
+	t.Helper()
+	b, err := os.ReadFile(file)
+	testutil.FatalOnErr("reading args", err, t)
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+func TestPower(t *testing.T) {
+	args := fakeIPMITool(t, "")
+	withCredentials(t, "admin", "/etc/sansshell/ipmi-password")
+	s := &server{}
+	ctx := context.Background()
+
+	resp, err := s.PowerStatus(ctx, &pb.PowerStatusRequest{})
+	testutil.FatalOnErr("PowerStatus", err, t)
+	if !resp.On {
+		t.Error("PowerStatus returned off, want on")
+	}
+	if got, want := readArgs(t, args), []string{"chassis", "power", "status"}; !cmp.Equal(got, want) {
+		t.Errorf("ipmitool args %q, want %q", got, want)
+	}
+
+	_, err = s.PowerControl(ctx, &pb.PowerControlRequest{Bmc: "bmc-1.example.com", Action: pb.PowerAction_POWER_ACTION_CYCLE})
+	testutil.FatalOnErr("PowerControl", err, t)
+	want := []string{"-I", "lanplus", "-H", "bmc-1.example.com", "-U", "admin", "-f", "/etc/sansshell/ipmi-password", "chassis", "power", "cycle"}
+	if got := readArgs(t, args); !cmp.Equal(got, want) {
+		t.Errorf("ipmitool args %q, want %q", got, want)
+	}
+
+	for _, tc := range []struct {
+		name string
+		req  *pb.PowerControlRequest
+	}{
+		{name: "no action", req: &pb.PowerControlRequest{}},
+		{name: "bad bmc", req: &pb.PowerControlRequest{Bmc: "-H", Action: pb.PowerAction_POWER_ACTION_ON}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.PowerControl(ctx, tc.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("unexpected error: got %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestSEL(t *testing.T) {
+	fakeIPMITool(t, "")
+	all := []*pb.SELEntry{
+		{Id: "1", Timestamp: "06/14/2022 08:00:01", Sensor: "Event Logging Disabled #0x07", Event: "Log area reset/cleared", Direction: "Asserted"},
+		{Id: "2", Timestamp: "06/15/2022 10:30:45", Sensor: "Power Supply #0x51", Event: "Failure detected", Direction: "Asserted"},
+		{Id: "3", Timestamp: "Pre-Init 0000001234", Sensor: "System Event #0x83", Event: "Timestamp Clock Sync"},
+	}
+	for _, tc := range []struct {
+		name string
+		req  *pb.SELRequest
+		want []*pb.SELEntry
+	}{
+		{name: "all", req: &pb.SELRequest{}, want: all},
+		{name: "most recent", req: &pb.SELRequest{MaxEntries: 2}, want: all[1:]},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&server{}).SEL(context.Background(), tc.req)
+			testutil.FatalOnErr("SEL", err, t)
+			if diff := cmp.Diff(&pb.SELReply{Entries: tc.want}, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSensors(t *testing.T) {
+	fakeIPMITool(t, "")
+	got, err := (&server{}).Sensors(context.Background(), &pb.SensorsRequest{})
+	testutil.FatalOnErr("Sensors", err, t)
+	want := &pb.SensorsReply{
+		Sensors: []*pb.Sensor{
+			{Name: "CPU Temp", Value: "45.000", Unit: "degrees C", Status: "ok"},
+			{Name: "FAN1", Unit: "RPM", Status: "na"},
+			{Name: "PS1 Status", Value: "0x1", Unit: "discrete", Status: "0x0100"},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name         string
+		bin          bool
+		stderr       string
+		user         string
+		bmc          string
+		wantErr      codes.Code
+		wantContains string
+	}{
+		{
+			name:    "unsupported",
+			wantErr: codes.Unimplemented,
+		},
+		{
+			name:    "no credentials",
+			bin:     true,
+			bmc:     "10.0.0.1",
+			wantErr: codes.FailedPrecondition,
+		},
+		{
+			name:         "unreachable",
+			bin:          true,
+			stderr:       "Error: Unable to establish IPMI v2 / RMCP+ session",
+			user:         "admin",
+			bmc:          "10.0.0.1",
+			wantErr:      codes.Unavailable,
+			wantContains: "Unable to establish",
+		},
+		{
+			name:         "failure",
+			bin:          true,
+			stderr:       "Could not open device at /dev/ipmi0",
+			wantErr:      codes.Internal,
+			wantContains: "/dev/ipmi0",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.bin {
+				fakeIPMITool(t, tc.stderr)
+			} else {
+				saved := *ipmitoolBin
+				t.Cleanup(func() { *ipmitoolBin = saved })
+				*ipmitoolBin = ""
+			}
+			withCredentials(t, tc.user, "/dev/null")
+			// The fake fails for commands it doesn't know.
+			_, err := ipmitool(ctx, tc.bmc, "bogus")
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want %v", err, tc.wantErr)
+			}
+			if !strings.Contains(fmt.Sprint(err), tc.wantContains) {
+				t.Errorf("error %v doesn't contain %q", err, tc.wantContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'k8snode'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/subcommands"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/k8snode"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "k8snode"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&cniCmd{}, "")
+	c.Register(&healthCmd{}, "")
+	c.Register(&runtimeCmd{}, "")
+	c.Register(&staticPodsCmd{}, "")
+	return c
+}
+
+type healthCmd struct {
+	verbose bool
+}
+
+func (*healthCmd) Name() string     { return "health" }
+func (*healthCmd) Synopsis() string { return "Check the kubelet's health" }
+func (*healthCmd) Usage() string {
+	return `health [--verbose]:
+    Query the kubelet's local healthz endpoint, printing healthy or
+    unhealthy with the response.
+`
+}
+
+func (h *healthCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&h.verbose, "verbose", false, "If true the result of each health check is printed")
+}
+
+func (h *healthCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewK8SNodeClientProxy(state.Conn)
+	respChan, err := c.KubeletHealthOneMany(ctx, &pb.KubeletHealthRequest{Verbose: h.verbose})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'health': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		health := "healthy"
+		if !resp.Resp.Healthy {
+			health = fmt.Sprintf("unhealthy (HTTP %d)", resp.Resp.StatusCode)
+			retCode = subcommands.ExitFailure
+		}
+		fmt.Fprintf(state.Out[resp.Index], "kubelet %s: %s\n", health, resp.Resp.Body)
+	}
+	return retCode
+}
+
+// printFiles writes the files in reply to out, or only their names and
+// modification times if list is set.
+func printFiles(out io.Writer, reply *pb.FilesReply, list bool) {
+	if len(reply.Files) == 0 {
+		fmt.Fprintf(out, "no files in %s\n", reply.Directory)
+		return
+	}
+	for _, f := range reply.Files {
+		if list {
+			fmt.Fprintf(out, "%s %s\n", f.Modified.AsTime().Local().Format(time.RFC3339), f.Path)
+			continue
+		}
+		fmt.Fprintf(out, "==> %s <==\n", f.Path)
+		out.Write(f.Contents)
+		if n := len(f.Contents); n > 0 && f.Contents[n-1] != '\n' {
+			fmt.Fprintln(out)
+		}
+		if f.Truncated {
+			fmt.Fprintln(out, "(truncated)")
+		}
+	}
+}
+
+type staticPodsCmd struct {
+	list bool
+}
+
+func (*staticPodsCmd) Name() string     { return "static-pods" }
+func (*staticPodsCmd) Synopsis() string { return "Print the kubelet's static pod manifests" }
+func (*staticPodsCmd) Usage() string {
+	return `static-pods [--list]:
+    Print the static pod manifests the kubelet runs from its manifest
+    directory.
+`
+}
+
+func (s *staticPodsCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&s.list, "list", false, "If true only the manifest names and modification times are printed")
+}
+
+func (s *staticPodsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewK8SNodeClientProxy(state.Conn)
+	respChan, err := c.StaticPodsOneMany(ctx, &pb.StaticPodsRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'static-pods': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		printFiles(state.Out[resp.Index], resp.Resp, s.list)
+	}
+	return retCode
+}
+
+type cniCmd struct {
+	list bool
+}
+
+func (*cniCmd) Name() string     { return "cni" }
+func (*cniCmd) Synopsis() string { return "Print the CNI network configuration" }
+func (*cniCmd) Usage() string {
+	return `cni [--list]:
+    Print the CNI network configuration files the container runtime loads.
+`
+}
+
+func (c *cniCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&c.list, "list", false, "If true only the file names and modification times are printed")
+}
+
+func (c *cniCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	proxy := pb.NewK8SNodeClientProxy(state.Conn)
+	respChan, err := proxy.CNIConfigOneMany(ctx, &pb.CNIConfigRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'cni': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		printFiles(state.Out[resp.Index], resp.Resp, c.list)
+	}
+	return retCode
+}
+
+type runtimeCmd struct {
+	info bool
+}
+
+func (*runtimeCmd) Name() string     { return "runtime" }
+func (*runtimeCmd) Synopsis() string { return "Print the container runtime status" }
+func (*runtimeCmd) Usage() string {
+	return `runtime [--info]:
+    Print the container runtime's version and status conditions as
+    reported over CRI.
+`
+}
+
+func (r *runtimeCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&r.info, "info", false, "If true the full output of crictl info is printed, including the runtime configuration")
+}
+
+func (r *runtimeCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewK8SNodeClientProxy(state.Conn)
+	respChan, err := c.RuntimeStatusOneMany(ctx, &pb.RuntimeStatusRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'runtime': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[resp.Index]
+		if r.info {
+			fmt.Fprintln(out, resp.Resp.Info)
+			continue
+		}
+		ready := "ready"
+		if !resp.Resp.Ready {
+			ready = "not ready"
+		}
+		fmt.Fprintf(out, "%s %s (CRI %s): %s\n", resp.Resp.RuntimeName, resp.Resp.RuntimeVersion, resp.Resp.RuntimeApiVersion, ready)
+		for _, c := range resp.Resp.Conditions {
+			fmt.Fprintf(out, "  %s=%t", c.Type, c.Status)
+			if c.Reason != "" || c.Message != "" {
+				fmt.Fprintf(out, " %s: %s", c.Reason, c.Message)
+			}
+			fmt.Fprintln(out)
+		}
+	}
+	return retCode
+}
@@ -0,0 +1,217 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package k8snode
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// K8SNodeClient is the client API for K8SNode service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type K8SNodeClient interface {
+	// KubeletHealth queries the kubelet's local healthz endpoint.
+	KubeletHealth(ctx context.Context, in *KubeletHealthRequest, opts ...grpc.CallOption) (*KubeletHealthReply, error)
+	// StaticPods returns the static pod manifests the kubelet runs.
+	StaticPods(ctx context.Context, in *StaticPodsRequest, opts ...grpc.CallOption) (*FilesReply, error)
+	// RuntimeStatus returns the status of the container runtime as reported
+	// over CRI by crictl.
+	RuntimeStatus(ctx context.Context, in *RuntimeStatusRequest, opts ...grpc.CallOption) (*RuntimeStatusReply, error)
+	// CNIConfig returns the CNI network configuration files.
+	CNIConfig(ctx context.Context, in *CNIConfigRequest, opts ...grpc.CallOption) (*FilesReply, error)
+}
+
+type k8SNodeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewK8SNodeClient(cc grpc.ClientConnInterface) K8SNodeClient {
+	return &k8SNodeClient{cc}
+}
+
+func (c *k8SNodeClient) KubeletHealth(ctx context.Context, in *KubeletHealthRequest, opts ...grpc.CallOption) (*KubeletHealthReply, error) {
+	out := new(KubeletHealthReply)
+	err := c.cc.Invoke(ctx, "/K8sNode.K8sNode/KubeletHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *k8SNodeClient) StaticPods(ctx context.Context, in *StaticPodsRequest, opts ...grpc.CallOption) (*FilesReply, error) {
+	out := new(FilesReply)
+	err := c.cc.Invoke(ctx, "/K8sNode.K8sNode/StaticPods", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *k8SNodeClient) RuntimeStatus(ctx context.Context, in *RuntimeStatusRequest, opts ...grpc.CallOption) (*RuntimeStatusReply, error) {
+	out := new(RuntimeStatusReply)
+	err := c.cc.Invoke(ctx, "/K8sNode.K8sNode/RuntimeStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *k8SNodeClient) CNIConfig(ctx context.Context, in *CNIConfigRequest, opts ...grpc.CallOption) (*FilesReply, error) {
+	out := new(FilesReply)
+	err := c.cc.Invoke(ctx, "/K8sNode.K8sNode/CNIConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// K8SNodeServer is the server API for K8SNode service.
+// All implementations should embed UnimplementedK8SNodeServer
+// for forward compatibility
+type K8SNodeServer interface {
+	// KubeletHealth queries the kubelet's local healthz endpoint.
+	KubeletHealth(context.Context, *KubeletHealthRequest) (*KubeletHealthReply, error)
+	// StaticPods returns the static pod manifests the kubelet runs.
+	StaticPods(context.Context, *StaticPodsRequest) (*FilesReply, error)
+	// RuntimeStatus returns the status of the container runtime as reported
+	// over CRI by crictl.
+	RuntimeStatus(context.Context, *RuntimeStatusRequest) (*RuntimeStatusReply, error)
+	// CNIConfig returns the CNI network configuration files.
+	CNIConfig(context.Context, *CNIConfigRequest) (*FilesReply, error)
+}
+
+// UnimplementedK8SNodeServer should be embedded to have forward compatible implementations.
+type UnimplementedK8SNodeServer struct {
+}
+
+func (UnimplementedK8SNodeServer) KubeletHealth(context.Context, *KubeletHealthRequest) (*KubeletHealthReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KubeletHealth not implemented")
+}
+func (UnimplementedK8SNodeServer) StaticPods(context.Context, *StaticPodsRequest) (*FilesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StaticPods not implemented")
+}
+func (UnimplementedK8SNodeServer) RuntimeStatus(context.Context, *RuntimeStatusRequest) (*RuntimeStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RuntimeStatus not implemented")
+}
+func (UnimplementedK8SNodeServer) CNIConfig(context.Context, *CNIConfigRequest) (*FilesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CNIConfig not implemented")
+}
+
+// UnsafeK8SNodeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to K8SNodeServer will
+// result in compilation errors.
+type UnsafeK8SNodeServer interface {
+	mustEmbedUnimplementedK8SNodeServer()
+}
+
+func RegisterK8SNodeServer(s grpc.ServiceRegistrar, srv K8SNodeServer) {
+	s.RegisterService(&K8SNode_ServiceDesc, srv)
+}
+
+func _K8SNode_KubeletHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KubeletHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(K8SNodeServer).KubeletHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/K8sNode.K8sNode/KubeletHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(K8SNodeServer).KubeletHealth(ctx, req.(*KubeletHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _K8SNode_StaticPods_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StaticPodsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(K8SNodeServer).StaticPods(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/K8sNode.K8sNode/StaticPods",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(K8SNodeServer).StaticPods(ctx, req.(*StaticPodsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _K8SNode_RuntimeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RuntimeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(K8SNodeServer).RuntimeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/K8sNode.K8sNode/RuntimeStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(K8SNodeServer).RuntimeStatus(ctx, req.(*RuntimeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _K8SNode_CNIConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CNIConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(K8SNodeServer).CNIConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/K8sNode.K8sNode/CNIConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(K8SNodeServer).CNIConfig(ctx, req.(*CNIConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// K8SNode_ServiceDesc is the grpc.ServiceDesc for K8SNode service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var K8SNode_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "K8sNode.K8sNode",
+	HandlerType: (*K8SNodeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "KubeletHealth",
+			Handler:    _K8SNode_KubeletHealth_Handler,
+		},
+		{
+			MethodName: "StaticPods",
+			Handler:    _K8SNode_StaticPods_Handler,
+		},
+		{
+			MethodName: "RuntimeStatus",
+			Handler:    _K8SNode_RuntimeStatus_Handler,
+		},
+		{
+			MethodName: "CNIConfig",
+			Handler:    _K8SNode_CNIConfig_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "k8snode.proto",
+}
@@ -0,0 +1,304 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package k8snode
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// K8SNodeClientProxy is the superset of K8SNodeClient which additionally includes the OneMany proxy methods
+type K8SNodeClientProxy interface {
+	K8SNodeClient
+	KubeletHealthOneMany(ctx context.Context, in *KubeletHealthRequest, opts ...grpc.CallOption) (<-chan *KubeletHealthManyResponse, error)
+	StaticPodsOneMany(ctx context.Context, in *StaticPodsRequest, opts ...grpc.CallOption) (<-chan *StaticPodsManyResponse, error)
+	RuntimeStatusOneMany(ctx context.Context, in *RuntimeStatusRequest, opts ...grpc.CallOption) (<-chan *RuntimeStatusManyResponse, error)
+	CNIConfigOneMany(ctx context.Context, in *CNIConfigRequest, opts ...grpc.CallOption) (<-chan *CNIConfigManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type k8SNodeClientProxy struct {
+	*k8SNodeClient
+}
+
+// NewK8SNodeClientProxy creates a K8SNodeClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewK8SNodeClientProxy(cc *proxy.Conn) K8SNodeClientProxy {
+	return &k8SNodeClientProxy{NewK8SNodeClient(cc).(*k8SNodeClient)}
+}
+
+// KubeletHealthManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type KubeletHealthManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *KubeletHealthReply
+	Error error
+}
+
+// KubeletHealthOneMany provides the same API as KubeletHealth but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *k8SNodeClientProxy) KubeletHealthOneMany(ctx context.Context, in *KubeletHealthRequest, opts ...grpc.CallOption) (<-chan *KubeletHealthManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *KubeletHealthManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &KubeletHealthManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &KubeletHealthReply{},
+			}
+			err := conn.Invoke(ctx, "/K8sNode.K8sNode/KubeletHealth", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/K8sNode.K8sNode/KubeletHealth", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &KubeletHealthManyResponse{
+				Resp: &KubeletHealthReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// StaticPodsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type StaticPodsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *FilesReply
+	Error error
+}
+
+// StaticPodsOneMany provides the same API as StaticPods but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *k8SNodeClientProxy) StaticPodsOneMany(ctx context.Context, in *StaticPodsRequest, opts ...grpc.CallOption) (<-chan *StaticPodsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *StaticPodsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &StaticPodsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &FilesReply{},
+			}
+			err := conn.Invoke(ctx, "/K8sNode.K8sNode/StaticPods", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/K8sNode.K8sNode/StaticPods", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &StaticPodsManyResponse{
+				Resp: &FilesReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// RuntimeStatusManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type RuntimeStatusManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *RuntimeStatusReply
+	Error error
+}
+
+// RuntimeStatusOneMany provides the same API as RuntimeStatus but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *k8SNodeClientProxy) RuntimeStatusOneMany(ctx context.Context, in *RuntimeStatusRequest, opts ...grpc.CallOption) (<-chan *RuntimeStatusManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *RuntimeStatusManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &RuntimeStatusManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &RuntimeStatusReply{},
+			}
+			err := conn.Invoke(ctx, "/K8sNode.K8sNode/RuntimeStatus", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/K8sNode.K8sNode/RuntimeStatus", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &RuntimeStatusManyResponse{
+				Resp: &RuntimeStatusReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// CNIConfigManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type CNIConfigManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *FilesReply
+	Error error
+}
+
+// CNIConfigOneMany provides the same API as CNIConfig but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *k8SNodeClientProxy) CNIConfigOneMany(ctx context.Context, in *CNIConfigRequest, opts ...grpc.CallOption) (<-chan *CNIConfigManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *CNIConfigManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &CNIConfigManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &FilesReply{},
+			}
+			err := conn.Invoke(ctx, "/K8sNode.K8sNode/CNIConfig", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/K8sNode.K8sNode/CNIConfig", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &CNIConfigManyResponse{
+				Resp: &FilesReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
@@ -0,0 +1,269 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'K8sNode' service.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/k8snode"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+var (
+	// maxFileSize is the most of each file StaticPods and CNIConfig return.
+	maxFileSize int64 = 1024 * 1024
+
+	// maxHealthzBody is the most of the healthz response returned.
+	maxHealthzBody int64 = 64 * 1024
+
+	// healthzTimeout bounds the healthz request so a hung kubelet is
+	// reported as unavailable rather than stalling the RPC.
+	healthzTimeout = 10 * time.Second
+
+	// cniExtensions are the files libcni loads network configuration from.
+	cniExtensions = []string{".conf", ".conflist", ".json"}
+)
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// KubeletHealth implements pb.K8SNodeServer.KubeletHealth
+func (s *server) KubeletHealth(ctx context.Context, req *pb.KubeletHealthRequest) (*pb.KubeletHealthReply, error) {
+	if *kubeletHealthzURL == "" {
+		return nil, status.Error(codes.Unimplemented, "kubelet health is not supported on this platform")
+	}
+	url := *kubeletHealthzURL
+	if req.Verbose {
+		url += "?verbose"
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthzTimeout)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid healthz url %s: %v", url, err)
+	}
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "can't reach kubelet: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHealthzBody))
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "reading kubelet healthz response: %v", err)
+	}
+	return &pb.KubeletHealthReply{
+		Healthy:    resp.StatusCode == http.StatusOK,
+		StatusCode: int32(resp.StatusCode),
+		Body:       string(body),
+	}, nil
+}
+
+// readDir returns the regular files in dir, skipping hidden ones. If exts
+// is set only files with one of those extensions are returned.
+func readDir(dir string, exts []string) (*pb.FilesReply, error) {
+	if dir == "" {
+		return nil, status.Error(codes.Unimplemented, "not supported on this platform")
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, status.Errorf(codes.NotFound, "%s doesn't exist, is this a kubernetes node?", dir)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read %s: %v", dir, err)
+	}
+	reply := &pb.FilesReply{Directory: dir}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") || !hasExtension(e.Name(), exts) {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		// Stat rather than use the entry so symlinks to files are followed.
+		fi, err := os.Stat(path)
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		f, err := readFile(path)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't read %s: %v", path, err)
+		}
+		f.Modified = timestamppb.New(fi.ModTime())
+		reply.Files = append(reply.Files, f)
+	}
+	sort.Slice(reply.Files, func(i, j int) bool { return reply.Files[i].Path < reply.Files[j].Path })
+	return reply, nil
+}
+
+func hasExtension(name string, exts []string) bool {
+	if len(exts) == 0 {
+		return true
+	}
+	for _, e := range exts {
+		if filepath.Ext(name) == e {
+			return true
+		}
+	}
+	return false
+}
+
+// readFile returns up to maxFileSize bytes of path.
+func readFile(path string) (*pb.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	// Read one extra byte to tell if the file was truncated.
+	contents, err := io.ReadAll(io.LimitReader(f, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	out := &pb.File{Path: path}
+	if int64(len(contents)) > maxFileSize {
+		contents = contents[:maxFileSize]
+		out.Truncated = true
+	}
+	out.Contents = contents
+	return out, nil
+}
+
+// StaticPods implements pb.K8SNodeServer.StaticPods
+func (s *server) StaticPods(ctx context.Context, req *pb.StaticPodsRequest) (*pb.FilesReply, error) {
+	return readDir(*staticPodDir, nil)
+}
+
+// CNIConfig implements pb.K8SNodeServer.CNIConfig
+func (s *server) CNIConfig(ctx context.Context, req *pb.CNIConfigRequest) (*pb.FilesReply, error) {
+	return readDir(*cniConfDir, cniExtensions)
+}
+
+// crictl runs crictl with args, returning its stdout.
+func crictl(ctx context.Context, args ...string) (string, error) {
+	run, err := util.RunCommand(ctx, *crictlBin, args)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "can't run crictl: %v", err)
+	}
+	if err := run.Error; run.ExitCode != 0 || err != nil {
+		return "", status.Errorf(codes.Unavailable, "crictl %s failed: %v: %s", args[0], err, strings.TrimSpace(util.TrimString(run.Stderr.String())))
+	}
+	return run.Stdout.String(), nil
+}
+
+// parseCrictlVersion parses the output of crictl version, i.e.
+//
+//	Version:  0.1.0
+//	RuntimeName:  containerd
+//	RuntimeVersion:  v1.6.8
+//	RuntimeApiVersion:  v1
+func parseCrictlVersion(out string, reply *pb.RuntimeStatusReply) {
+	for _, l := range strings.Split(out, "\n") {
+		kv := strings.SplitN(l, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		v := strings.TrimSpace(kv[1])
+		switch strings.TrimSpace(kv[0]) {
+		case "RuntimeName":
+			reply.RuntimeName = v
+		case "RuntimeVersion":
+			reply.RuntimeVersion = v
+		case "RuntimeApiVersion":
+			reply.RuntimeApiVersion = v
+		}
+	}
+}
+
+// parseCrictlInfo parses the runtime conditions from the JSON output of
+// crictl info.
+func parseCrictlInfo(out string, reply *pb.RuntimeStatusReply) error {
+	var info struct {
+		Status struct {
+			Conditions []struct {
+				Type    string `json:"type"`
+				Status  bool   `json:"status"`
+				Reason  string `json:"reason"`
+				Message string `json:"message"`
+			} `json:"conditions"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		return fmt.Errorf("can't parse crictl info: %v", err)
+	}
+	reply.Ready = len(info.Status.Conditions) > 0
+	for _, c := range info.Status.Conditions {
+		reply.Conditions = append(reply.Conditions, &pb.RuntimeCondition{
+			Type:    c.Type,
+			Status:  c.Status,
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+		if !c.Status {
+			reply.Ready = false
+		}
+	}
+	reply.Info = out
+	return nil
+}
+
+// RuntimeStatus implements pb.K8SNodeServer.RuntimeStatus
+func (s *server) RuntimeStatus(ctx context.Context, req *pb.RuntimeStatusRequest) (*pb.RuntimeStatusReply, error) {
+	if *crictlBin == "" {
+		return nil, status.Error(codes.Unimplemented, "runtime status is not supported on this platform")
+	}
+	if _, err := os.Stat(*crictlBin); errors.Is(err, os.ErrNotExist) {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s isn't installed", *crictlBin)
+	}
+	reply := &pb.RuntimeStatusReply{}
+	out, err := crictl(ctx, "version")
+	if err != nil {
+		return nil, err
+	}
+	parseCrictlVersion(out, reply)
+	out, err = crictl(ctx, "info")
+	if err != nil {
+		return nil, err
+	}
+	if err := parseCrictlInfo(out, reply); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return reply, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterK8SNodeServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
@@ -0,0 +1,31 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"flag"
+)
+
+var (
+	kubeletHealthzURL = flag.String("kubelet-healthz-url", "http://127.0.0.1:10248/healthz", "URL of the kubelet's healthz endpoint")
+	staticPodDir      = flag.String("static-pod-dir", "/etc/kubernetes/manifests", "Directory containing the kubelet's static pod manifests")
+	crictlBin         = flag.String("crictl-bin", "/usr/bin/crictl", "Path to the crictl binary used to query the container runtime")
+	cniConfDir        = flag.String("cni-conf-dir", "/etc/cni/net.d", "Directory containing CNI network configuration")
+)
@@ -0,0 +1,264 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/k8snode"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestKubeletHealth(t *testing.T) {
+	savedURL := *kubeletHealthzURL
+	t.Cleanup(func() { *kubeletHealthzURL = savedURL })
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["verbose"]; ok {
+			fmt.Fprint(w, "[+]ping ok\n[+]syncloop ok\nhealthz check passed\n")
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(healthy.Close)
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, "[+]ping ok\n[-]syncloop failed: reason withheld\nhealthz check failed\n")
+	}))
+	t.Cleanup(unhealthy.Close)
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	for _, tc := range []struct {
+		name    string
+		url     string
+		req     *pb.KubeletHealthRequest
+		want    *pb.KubeletHealthReply
+		wantErr codes.Code
+	}{
+		{
+			name: "healthy",
+			url:  healthy.URL + "/healthz",
+			req:  &pb.KubeletHealthRequest{},
+			want: &pb.KubeletHealthReply{Healthy: true, StatusCode: 200, Body: "ok"},
+		},
+		{
+			name: "verbose",
+			url:  healthy.URL + "/healthz",
+			req:  &pb.KubeletHealthRequest{Verbose: true},
+			want: &pb.KubeletHealthReply{Healthy: true, StatusCode: 200, Body: "[+]ping ok\n[+]syncloop ok\nhealthz check passed\n"},
+		},
+		{
+			name: "unhealthy",
+			url:  unhealthy.URL + "/healthz",
+			req:  &pb.KubeletHealthRequest{},
+			want: &pb.KubeletHealthReply{StatusCode: 500, Body: "[+]ping ok\n[-]syncloop failed: reason withheld\nhealthz check failed\n"},
+		},
+		{
+			name:    "kubelet down",
+			url:     down.URL + "/healthz",
+			req:     &pb.KubeletHealthRequest{},
+			wantErr: codes.Unavailable,
+		},
+		{
+			name:    "unsupported",
+			req:     &pb.KubeletHealthRequest{},
+			wantErr: codes.Unimplemented,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			*kubeletHealthzURL = tc.url
+			s := &server{}
+			got, err := s.KubeletHealth(context.Background(), tc.req)
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("KubeletHealth: got error %v, want code %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	savedStaticPodDir, savedCNIConfDir, savedMax := *staticPodDir, *cniConfDir, maxFileSize
+	t.Cleanup(func() {
+		*staticPodDir, *cniConfDir, maxFileSize = savedStaticPodDir, savedCNIConfDir, savedMax
+	})
+
+	dir := t.TempDir()
+	modified := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	for name, contents := range map[string]string{
+		"kube-apiserver.yaml":   "apiVersion: v1\nkind: Pod\n",
+		"etcd.yaml":             "apiVersion: v1\nkind: Pod\nmetadata:\n  name: etcd\n",
+		".kube-proxy.yaml.swp":  "editor junk",
+		"10-calico.conflist":    `{"cniVersion": "0.3.1", "name": "k8s-pod-network"}`,
+		"99-loopback.conf":      `{"cniVersion": "0.3.1", "type": "loopback"}`,
+		"calico-kubeconfig.bak": "old",
+	} {
+		path := filepath.Join(dir, name)
+		testutil.FatalOnErr("writing "+name, os.WriteFile(path, []byte(contents), 0644), t)
+		testutil.FatalOnErr("chtimes "+name, os.Chtimes(path, modified, modified), t)
+	}
+	testutil.FatalOnErr("mkdir", os.Mkdir(filepath.Join(dir, "subdir.conf"), 0755), t)
+	testutil.FatalOnErr("symlink", os.Symlink(filepath.Join(dir, "99-loopback.conf"), filepath.Join(dir, "00-link.json")), t)
+	testutil.FatalOnErr("dangling symlink", os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "dangling.yaml")), t)
+
+	file := func(name string, contents string) *pb.File {
+		return &pb.File{
+			Path:     filepath.Join(dir, name),
+			Contents: []byte(contents),
+			Modified: timestamppb.New(modified),
+		}
+	}
+	s := &server{}
+	*staticPodDir = dir
+	got, err := s.StaticPods(context.Background(), &pb.StaticPodsRequest{})
+	testutil.FatalOnErr("StaticPods", err, t)
+	testutil.DiffErr("StaticPods", got, &pb.FilesReply{
+		Directory: dir,
+		Files: []*pb.File{
+			file("00-link.json", `{"cniVersion": "0.3.1", "type": "loopback"}`),
+			file("10-calico.conflist", `{"cniVersion": "0.3.1", "name": "k8s-pod-network"}`),
+			file("99-loopback.conf", `{"cniVersion": "0.3.1", "type": "loopback"}`),
+			file("calico-kubeconfig.bak", "old"),
+			file("etcd.yaml", "apiVersion: v1\nkind: Pod\nmetadata:\n  name: etcd\n"),
+			file("kube-apiserver.yaml", "apiVersion: v1\nkind: Pod\n"),
+		},
+	}, t)
+
+	*cniConfDir = dir
+	maxFileSize = 20
+	got, err = s.CNIConfig(context.Background(), &pb.CNIConfigRequest{})
+	testutil.FatalOnErr("CNIConfig", err, t)
+	truncated := func(f *pb.File) *pb.File {
+		f.Contents = f.Contents[:maxFileSize]
+		f.Truncated = true
+		return f
+	}
+	testutil.DiffErr("CNIConfig", got, &pb.FilesReply{
+		Directory: dir,
+		Files: []*pb.File{
+			truncated(file("00-link.json", `{"cniVersion": "0.3.1", "type": "loopback"}`)),
+			truncated(file("10-calico.conflist", `{"cniVersion": "0.3.1", "name": "k8s-pod-network"}`)),
+			truncated(file("99-loopback.conf", `{"cniVersion": "0.3.1", "type": "loopback"}`)),
+		},
+	}, t)
+
+	*cniConfDir = filepath.Join(dir, "missing")
+	_, err = s.CNIConfig(context.Background(), &pb.CNIConfigRequest{})
+	if got, want := status.Code(err), codes.NotFound; got != want {
+		t.Errorf("CNIConfig of missing dir: got error %v, want code %v", err, want)
+	}
+	*staticPodDir = ""
+	_, err = s.StaticPods(context.Background(), &pb.StaticPodsRequest{})
+	if got, want := status.Code(err), codes.Unimplemented; got != want {
+		t.Errorf("StaticPods unsupported: got error %v, want code %v", err, want)
+	}
+}
+
+func TestRuntimeStatus(t *testing.T) {
+	savedCrictl := *crictlBin
+	t.Cleanup(func() { *crictlBin = savedCrictl })
+
+	dir := t.TempDir()
+	version, err := filepath.Abs("./testdata/crictl-version.out")
+	testutil.FatalOnErr("version path", err, t)
+	infoFile, err := filepath.Abs("./testdata/crictl-info.json")
+	testutil.FatalOnErr("info path", err, t)
+	info, err := os.ReadFile(infoFile)
+	testutil.FatalOnErr("reading info", err, t)
+	cat := testutil.ResolvePath(t, "cat")
+	working := filepath.Join(dir, "crictl")
+	script := fmt.Sprintf("#!/bin/sh\ncase $1 in\nversion) %s %s ;;\ninfo) %s %s ;;\n*) exit 1 ;;\nesac\n", cat, version, cat, infoFile)
+	testutil.FatalOnErr("writing crictl", os.WriteFile(working, []byte(script), 0755), t)
+	failing := filepath.Join(dir, "crictl-failing")
+	script = "#!/bin/sh\necho 'failed to connect: connection error: dial unix /run/containerd/containerd.sock: connect: no such file or directory' >&2\nexit 1\n"
+	testutil.FatalOnErr("writing failing crictl", os.WriteFile(failing, []byte(script), 0755), t)
+
+	for _, tc := range []struct {
+		name    string
+		bin     string
+		want    *pb.RuntimeStatusReply
+		wantErr codes.Code
+	}{
+		{
+			name: "containerd",
+			bin:  working,
+			want: &pb.RuntimeStatusReply{
+				RuntimeName:       "containerd",
+				RuntimeVersion:    "v1.6.8",
+				RuntimeApiVersion: "v1",
+				Conditions: []*pb.RuntimeCondition{
+					{Type: "RuntimeReady", Status: true},
+					{Type: "NetworkReady", Reason: "NetworkPluginNotReady", Message: "Network plugin returns error: cni plugin not initialized"},
+				},
+				Info: string(info),
+			},
+		},
+		{
+			name:    "runtime down",
+			bin:     failing,
+			wantErr: codes.Unavailable,
+		},
+		{
+			name:    "not installed",
+			bin:     filepath.Join(dir, "missing"),
+			wantErr: codes.FailedPrecondition,
+		},
+		{
+			name:    "unsupported",
+			wantErr: codes.Unimplemented,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			*crictlBin = tc.bin
+			s := &server{}
+			got, err := s.RuntimeStatus(context.Background(), &pb.RuntimeStatusRequest{})
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("RuntimeStatus: got error %v, want code %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+
+	reply := &pb.RuntimeStatusReply{}
+	testutil.FatalOnErr("all ready", parseCrictlInfo(`{"status":{"conditions":[{"type":"RuntimeReady","status":true},{"type":"NetworkReady","status":true}]}}`, reply), t)
+	if !reply.Ready {
+		t.Errorf("runtime with all conditions true isn't ready: %v", reply)
+	}
+	if err := parseCrictlInfo("not json", reply); err == nil {
+		t.Error("parsing invalid crictl info didn't fail")
+	}
+}
@@ -18,17 +18,24 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"flag"
 	"fmt"
+	"hash"
 	"io"
 	"io/fs"
 	"os"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/subcommands"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/Snowflake-Labs/sansshell/client"
 	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
@@ -38,45 +45,39 @@ import (
 const subPackage = "file"
 
 func init() {
-	subcommands.Register(&fileCmd{}, subPackage)
+	client.RegisterSubpackage(subPackage, setup)
 }
 
 func setup(f *flag.FlagSet) *subcommands.Commander {
 	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&appendOnlyCmd{}, "")
+	c.Register(&archiveCmd{}, "")
 	c.Register(&chgrpCmd{}, "")
 	c.Register(&chmodCmd{}, "")
 	c.Register(&chownCmd{}, "")
 	c.Register(&cpCmd{}, "")
+	c.Register(&getXattrCmd{}, "")
+	c.Register(&grepCmd{}, "")
 	c.Register(&immutableCmd{}, "")
+	c.Register(&lnCmd{}, "")
 	c.Register(&lsCmd{}, "")
 	c.Register(&readCmd{}, "")
+	c.Register(&readlinkCmd{}, "")
 	c.Register(&rmCmd{}, "")
 	c.Register(&rmdirCmd{}, "")
+	c.Register(&setXattrCmd{}, "")
 	c.Register(&statCmd{}, "")
 	c.Register(&sumCmd{}, "")
 	c.Register(&tailCmd{}, "")
+	c.Register(&utimesCmd{}, "")
+	c.Register(&watchCmd{}, "")
 	return c
 }
 
-type fileCmd struct{}
-
-func (*fileCmd) Name() string { return subPackage }
-func (p *fileCmd) Synopsis() string {
-	return client.GenerateSynopsis(setup(flag.NewFlagSet("", flag.ContinueOnError)))
-}
-func (p *fileCmd) Usage() string {
-	return client.GenerateUsage(subPackage, p.Synopsis())
-}
-func (*fileCmd) SetFlags(f *flag.FlagSet) {}
-
-func (p *fileCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
-	c := setup(f)
-	return c.Execute(ctx, args...)
-}
-
 type readCmd struct {
-	offset int64
-	length int64
+	offset    int64
+	length    int64
+	chunkSize int64
 }
 
 func (*readCmd) Name() string     { return "read" }
@@ -84,12 +85,16 @@ func (*readCmd) Synopsis() string { return "Read a file." }
 func (*readCmd) Usage() string {
 	return `read <path>:
   Read from the remote file named by <path> and write it to the appropriate --output destination.
+  If a read fails part way the offset reached is reported so it can be resumed with --offset.
+  With --chunk-size the file is read a chunk at a time and each chunk is verified against a
+  manifest of SHA256 sums computed by the target before it's written out.
 `
 }
 
 func (p *readCmd) SetFlags(f *flag.FlagSet) {
 	f.Int64Var(&p.offset, "offset", 0, "If positive bytes to skip before reading. If negative apply from the end of the file")
 	f.Int64Var(&p.length, "length", 0, "If positive the maximum number of bytes to read")
+	f.Int64Var(&p.chunkSize, "chunk-size", 0, "If positive read and verify the file in chunks of this many bytes. --offset must then be a multiple of it.")
 }
 
 func (p *readCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -100,6 +105,13 @@ func (p *readCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfac
 	}
 
 	filename := f.Args()[0]
+	if p.chunkSize > 0 {
+		if p.offset < 0 || p.offset%p.chunkSize != 0 || p.length != 0 {
+			fmt.Fprintln(os.Stderr, "--chunk-size requires a non-negative --offset which is a multiple of it and no --length")
+			return subcommands.ExitUsageError
+		}
+		return readChunked(ctx, state, filename, p.offset, p.chunkSize)
+	}
 	req := &pb.ReadActionRequest{
 		Request: &pb.ReadActionRequest_File{
 			File: &pb.ReadRequest{
@@ -126,6 +138,8 @@ func readFile(ctx context.Context, state *util.ExecuteState, req *pb.ReadActionR
 	}
 
 	exit := subcommands.ExitSuccess
+	// The offset reached per target, so failed reads can be resumed.
+	offsets := make(map[int]int64)
 	for {
 		resp, err := stream.Recv()
 		if err == io.EOF {
@@ -143,9 +157,13 @@ func readFile(ctx context.Context, state *util.ExecuteState, req *pb.ReadActionR
 			contents := r.Resp.Contents
 			if r.Error != nil && r.Error != io.EOF {
 				fmt.Fprintf(state.Err[r.Index], "Target %s (%d) returned error - %v\n", r.Target, r.Index, r.Error)
+				if off, ok := offsets[r.Index]; ok && req.GetFile() != nil {
+					fmt.Fprintf(state.Err[r.Index], "Target %s (%d) read up to offset %d, resume with --offset=%d\n", r.Target, r.Index, off, off)
+				}
 				exit = subcommands.ExitFailure
 				continue
 			}
+			offsets[r.Index] = r.Resp.Offset
 			n, err := state.Out[r.Index].Write(contents)
 			if got, want := n, len(contents); got != want {
 				fmt.Fprintf(state.Err[r.Index], "can't write into buffer at correct length. Got %d want %d\n", got, want)
@@ -160,6 +178,106 @@ func readFile(ctx context.Context, state *util.ExecuteState, req *pb.ReadActionR
 	return exit
 }
 
+// readChunked reads filename starting at offset a chunk at a time,
+// verifying each chunk against the manifest returned by each target.
+func readChunked(ctx context.Context, state *util.ExecuteState, filename string, offset int64, chunkSize int64) subcommands.ExitStatus {
+	c := pb.NewLocalFileClientProxy(state.Conn)
+	respChan, err := c.ManifestOneMany(ctx, &pb.ManifestRequest{Filename: filename, ChunkSize: chunkSize})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not get manifest for %s: %v\n", filename, err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	exit := subcommands.ExitSuccess
+	// The manifests of targets still being read.
+	manifests := make(map[int]*pb.FileManifest)
+	targets := make(map[int]string)
+	chunks := 0
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "Target %s (%d) returned error - %v\n", r.Target, r.Index, r.Error)
+			exit = subcommands.ExitFailure
+			continue
+		}
+		manifests[r.Index] = r.Resp
+		targets[r.Index] = r.Target
+		if n := len(r.Resp.ChunkSha256); n > chunks {
+			chunks = n
+		}
+	}
+
+	failTarget := func(idx int, chunk int, err string) {
+		fmt.Fprintf(state.Err[idx], "Target %s (%d) %s, resume with --offset=%d\n", targets[idx], idx, err, int64(chunk)*chunkSize)
+		delete(manifests, idx)
+		exit = subcommands.ExitFailure
+	}
+
+	for i := int(offset / chunkSize); i < chunks && len(manifests) > 0; i++ {
+		stream, err := c.ReadOneMany(ctx, &pb.ReadActionRequest{
+			Request: &pb.ReadActionRequest_File{
+				File: &pb.ReadRequest{
+					Filename: filename,
+					Offset:   int64(i) * chunkSize,
+					Length:   chunkSize,
+				},
+			},
+		})
+		if err != nil {
+			for idx := range manifests {
+				failTarget(idx, i, fmt.Sprintf("read error - %v", err))
+			}
+			break
+		}
+		bufs := make(map[int]*bytes.Buffer)
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				for idx := range manifests {
+					failTarget(idx, i, fmt.Sprintf("stream error - %v", err))
+				}
+				break
+			}
+			for _, r := range resp {
+				if _, ok := manifests[r.Index]; !ok {
+					continue
+				}
+				if r.Error != nil && r.Error != io.EOF {
+					failTarget(r.Index, i, fmt.Sprintf("returned error - %v", r.Error))
+					continue
+				}
+				if bufs[r.Index] == nil {
+					bufs[r.Index] = &bytes.Buffer{}
+				}
+				bufs[r.Index].Write(r.Resp.Contents)
+			}
+		}
+		for idx, m := range manifests {
+			if i >= len(m.ChunkSha256) {
+				continue
+			}
+			var data []byte
+			if bufs[idx] != nil {
+				data = bufs[idx].Bytes()
+			}
+			h := sha256.Sum256(data)
+			if got, want := hex.EncodeToString(h[:]), m.ChunkSha256[i]; got != want {
+				failTarget(idx, i, fmt.Sprintf("chunk %d has SHA256 %s, expected %s", i, got, want))
+				continue
+			}
+			if _, err := state.Out[idx].Write(data); err != nil {
+				failTarget(idx, i, fmt.Sprintf("error writing output - %v", err))
+			}
+		}
+	}
+	return exit
+}
+
 type tailCmd struct {
 	offset int64
 }
@@ -169,7 +287,8 @@ func (*tailCmd) Synopsis() string { return "Tail a file." }
 func (*tailCmd) Usage() string {
 	return `tail <path>:
   Tail the remote file named by <path> and write it to the appropriate --output destination. This
-  will continue to block and read until cancelled (as tail -f would do locally).
+  will continue to block and read until cancelled (as tail -F would do locally), following the file
+  if it's rotated or truncated.
 `
 }
 
@@ -197,6 +316,116 @@ func (p *tailCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfac
 	return readFile(ctx, state, req)
 }
 
+type grepCmd struct {
+	ignoreCase bool
+	invert     bool
+	before     uint
+	after      uint
+	context    uint
+	maxCount   uint
+	maxBytes   int64
+}
+
+func (*grepCmd) Name() string     { return "grep" }
+func (*grepCmd) Synopsis() string { return "Search remote files for lines matching a pattern." }
+func (*grepCmd) Usage() string {
+	return `grep [--ignore-case] [--invert] [--before=N] [--after=N] [--context=N] [--max-count=N] [--max-bytes=N] <pattern> <path> [<path>...]:
+  Search the remote files for lines matching the regular expression (RE2 syntax) and print them as
+  grep would, with matches as path:line:text and context lines as path-line-text. Paths may be
+  patterns such as /var/log/messages* which are expanded on the target.
+`
+}
+
+func (g *grepCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&g.ignoreCase, "ignore-case", false, "If true match case insensitively")
+	f.BoolVar(&g.invert, "invert", false, "If true print lines which don't match instead")
+	f.UintVar(&g.before, "before", 0, "Print this many lines before each match")
+	f.UintVar(&g.after, "after", 0, "Print this many lines after each match")
+	f.UintVar(&g.context, "context", 0, "Print this many lines before and after each match, unless --before or --after are set")
+	f.UintVar(&g.maxCount, "max-count", 0, "If non-zero stop after this many matching lines on each target")
+	f.Int64Var(&g.maxBytes, "max-bytes", 0, "If non-zero stop after reading this many bytes on each target")
+}
+
+func (g *grepCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "please specify a pattern and at least one path to search")
+		return subcommands.ExitUsageError
+	}
+	before, after := g.before, g.after
+	if before == 0 {
+		before = g.context
+	}
+	if after == 0 {
+		after = g.context
+	}
+	req := &pb.GrepRequest{
+		Pattern:       f.Arg(0),
+		Paths:         f.Args()[1:],
+		IgnoreCase:    g.ignoreCase,
+		Invert:        g.invert,
+		BeforeContext: uint32(before),
+		AfterContext:  uint32(after),
+		MaxMatches:    uint32(g.maxCount),
+		MaxBytes:      g.maxBytes,
+	}
+
+	client := pb.NewLocalFileClientProxy(state.Conn)
+	stream, err := client.GrepOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "grep client error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	// The last line printed for each target, to separate groups of lines
+	// which aren't adjacent with -- as grep does.
+	type position struct {
+		path string
+		line int64
+	}
+	last := make(map[int]position)
+	retCode := subcommands.ExitSuccess
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "grep: receive error: %v\n", err)
+			}
+			return subcommands.ExitFailure
+		}
+		for _, r := range resp {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "grep for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			for _, l := range r.Resp.Lines {
+				p, ok := last[r.Index]
+				if ok && (before > 0 || after > 0) && (p.path != l.Path || p.line+1 != l.LineNumber) {
+					fmt.Fprintln(state.Out[r.Index], "--")
+				}
+				last[r.Index] = position{l.Path, l.LineNumber}
+				sep := ":"
+				if l.Context {
+					sep = "-"
+				}
+				fmt.Fprintf(state.Out[r.Index], "%s%s%d%s%s\n", l.Path, sep, l.LineNumber, sep, l.Line)
+			}
+			if r.Resp.Truncated {
+				fmt.Fprintf(state.Err[r.Index], "grep for target %s (%d) stopped early at --max-count or --max-bytes\n", r.Target, r.Index)
+			}
+		}
+	}
+	return retCode
+}
+
 type statCmd struct{}
 
 func (*statCmd) Name() string     { return "stat" }
@@ -272,8 +501,11 @@ func (s *statCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfac
 				continue
 			}
 			mode := os.FileMode(r.Resp.Mode)
-			outTmpl := "File: %s\nSize: %d\nType: %s\nAccess: %s Uid: %d Gid: %d\nModify: %s\nImmutable: %t\n"
-			fmt.Fprintf(state.Out[r.Index], outTmpl, r.Resp.Filename, r.Resp.Size, fileTypeString(mode), mode, r.Resp.Uid, r.Resp.Gid, r.Resp.Modtime.AsTime(), r.Resp.Immutable)
+			outTmpl := "File: %s\nSize: %d\nType: %s\nAccess: %s Uid: %d Gid: %d\nModify: %s\nImmutable: %t\nAppend-only: %t\n"
+			fmt.Fprintf(state.Out[r.Index], outTmpl, r.Resp.Filename, r.Resp.Size, fileTypeString(mode), mode, r.Resp.Uid, r.Resp.Gid, r.Resp.Modtime.AsTime(), r.Resp.Immutable, r.Resp.AppendOnly)
+			if r.Resp.SymlinkTarget != "" {
+				fmt.Fprintf(state.Out[r.Index], "Symlink: %s\n", r.Resp.SymlinkTarget)
+			}
 		}
 	}
 	for err := range waitc {
@@ -311,13 +543,15 @@ func fileTypeString(mode os.FileMode) string {
 
 type sumCmd struct {
 	sumType string
+	size    bool
 }
 
 func (*sumCmd) Name() string     { return "sum" }
 func (*sumCmd) Synopsis() string { return "Calculate file sums" }
 func (*sumCmd) Usage() string {
-	return `sum [--sumtype type ] <path> [<path>...]
+	return `sum [--sumtype type ] [--size] <path> [<path>...]
   Calculate sums for one or more remotes paths and print the result (as a hexidecimal string) to stdout.
+  With --size the number of bytes summed is printed between the sum and the path.
   `
 }
 
@@ -337,6 +571,7 @@ func (s *sumCmd) SetFlags(f *flag.FlagSet) {
 	}
 	sort.Strings(shortNames)
 	f.StringVar(&s.sumType, "sumtype", "SHA256", fmt.Sprintf("Type of sum to calculate (one of: [%s])", strings.Join(shortNames, ",")))
+	f.BoolVar(&s.size, "size", false, "If true also print the size of each file")
 }
 
 func (s *sumCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -406,6 +641,10 @@ func (s *sumCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface
 				}
 				continue
 			}
+			if s.size {
+				fmt.Fprintf(state.Out[r.Index], "%s %d %s\n", r.Resp.Sum, r.Resp.Size, r.Resp.Filename)
+				continue
+			}
 			fmt.Fprintf(state.Out[r.Index], "%s %s\n", r.Resp.Sum, r.Resp.Filename)
 		}
 	}
@@ -654,24 +893,264 @@ func (i *immutableCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...int
 	return retCode
 }
 
+type appendOnlyCmd struct {
+	appendOnly bool
+}
+
+func (*appendOnlyCmd) Name() string { return "appendonly" }
+func (*appendOnlyCmd) Synopsis() string {
+	return "Set or clear the append-only bit on a file/directory."
+}
+func (*appendOnlyCmd) Usage() string {
+	return `appendonly --state=X <path>:
+  Set or clears the append-only bit on a file/directory.
+  `
+}
+
+func (a *appendOnlyCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&a.appendOnly, "state", false, "Sets or clears the append-only bit on a file/directory")
+}
+
+func (a *appendOnlyCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "please specify a filename to change append-only state")
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.SetFileAttributesRequest{
+		Attrs: &pb.FileAttributes{
+			Filename: f.Args()[0],
+			Attributes: []*pb.FileAttribute{
+				{
+					Value: &pb.FileAttribute_AppendOnly{
+						AppendOnly: a.appendOnly,
+					},
+				},
+			},
+		},
+	}
+	client := pb.NewLocalFileClientProxy(state.Conn)
+	respChan, err := client.SetFileAttributesOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "appendonly client error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "appendonly client error: %v\n", r.Error)
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
+
+type watchCmd struct {
+	recursive bool
+	debounce  time.Duration
+	duration  time.Duration
+}
+
+func (*watchCmd) Name() string     { return "watch" }
+func (*watchCmd) Synopsis() string { return "Watch files and directories for changes." }
+func (*watchCmd) Usage() string {
+	return `watch [--recursive] [--debounce=X] [--duration=X] <path> [<path>...]:
+  Print changes to the remote paths as they happen, one per line with the time, type of change
+  and path. For a directory changes to its entries are printed. A file doesn't need to exist yet
+  but its directory does. This continues until cancelled or --duration has passed.
+`
+}
+
+func (w *watchCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&w.recursive, "recursive", false, "If true also watch subdirectories of directories")
+	f.DurationVar(&w.debounce, "debounce", 0, "If set print changes to a path once it hasn't changed for this long, as a single change")
+	f.DurationVar(&w.duration, "duration", 0, "If set stop watching after this long")
+}
+
+func (w *watchCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "please specify at least one path to watch")
+		return subcommands.ExitUsageError
+	}
+
+	if w.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.duration)
+		defer cancel()
+	}
+	req := &pb.WatchRequest{
+		Paths:     f.Args(),
+		Recursive: w.recursive,
+	}
+	if w.debounce > 0 {
+		req.Debounce = durationpb.New(w.debounce)
+	}
+
+	client := pb.NewLocalFileClientProxy(state.Conn)
+	stream, err := client.WatchOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "watch client error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	// Running out of --duration is how watches normally end.
+	expired := func() bool {
+		return w.duration > 0 && ctx.Err() == context.DeadlineExceeded
+	}
+	retCode := subcommands.ExitSuccess
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if expired() {
+				break
+			}
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "watch: receive error: %v\n", err)
+			}
+			return subcommands.ExitFailure
+		}
+		for _, r := range resp {
+			if r.Error != nil {
+				if expired() {
+					continue
+				}
+				fmt.Fprintf(state.Err[r.Index], "watch for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			for _, e := range r.Resp.Events {
+				typ := strings.TrimPrefix(e.Type.String(), "WATCH_EVENT_TYPE_")
+				if e.IsDir {
+					typ += ",DIR"
+				}
+				fmt.Fprintf(state.Out[r.Index], "%s %s %s\n", e.Time.AsTime().Local().Format(time.RFC3339Nano), typ, e.Path)
+			}
+		}
+	}
+	return retCode
+}
+
+type utimesCmd struct {
+	atime string
+	mtime string
+}
+
+func (*utimesCmd) Name() string     { return "utimes" }
+func (*utimesCmd) Synopsis() string { return "Change access/modification times on a file/directory" }
+func (*utimesCmd) Usage() string {
+	return `utimes [--atime=X] [--mtime=X] <path>:
+  Change the access and/or modification times on a file/directory. Times are in RFC3339 format
+  (i.e. 2006-01-02T15:04:05Z) or "now". Any time not given is left unchanged.
+  `
+}
+
+func (u *utimesCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&u.atime, "atime", "", "Sets the access time of the file/directory")
+	f.StringVar(&u.mtime, "mtime", "", "Sets the modification time of the file/directory")
+}
+
+// parseTime parses a utimes flag value.
+func parseTime(v string) (*timestamppb.Timestamp, error) {
+	if v == "now" {
+		return timestamppb.Now(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return nil, err
+	}
+	return timestamppb.New(t), nil
+}
+
+func (u *utimesCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "please specify a filename to change times on")
+		return subcommands.ExitUsageError
+	}
+	if u.atime == "" && u.mtime == "" {
+		fmt.Fprintln(os.Stderr, "--atime and/or --mtime must be set")
+		return subcommands.ExitUsageError
+	}
+
+	var attrs []*pb.FileAttribute
+	if u.atime != "" {
+		t, err := parseTime(u.atime)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --atime: %v\n", err)
+			return subcommands.ExitUsageError
+		}
+		attrs = append(attrs, &pb.FileAttribute{Value: &pb.FileAttribute_Atime{Atime: t}})
+	}
+	if u.mtime != "" {
+		t, err := parseTime(u.mtime)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --mtime: %v\n", err)
+			return subcommands.ExitUsageError
+		}
+		attrs = append(attrs, &pb.FileAttribute{Value: &pb.FileAttribute_Mtime{Mtime: t}})
+	}
+	req := &pb.SetFileAttributesRequest{
+		Attrs: &pb.FileAttributes{
+			Filename:   f.Args()[0],
+			Attributes: attrs,
+		},
+	}
+
+	client := pb.NewLocalFileClientProxy(state.Conn)
+	respChan, err := client.SetFileAttributesOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "utimes client error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "utimes client error: %v\n", r.Error)
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
+
 type lsCmd struct {
 	long      bool
 	directory bool
+	depth     uint
+	glob      string
 }
 
 func (*lsCmd) Name() string     { return "ls" }
 func (*lsCmd) Synopsis() string { return "List a file or directory" }
 func (*lsCmd) Usage() string {
-	return `ls [--long] [--directory] <path>:
+	return `ls [--long] [--directory] [--depth=N] [--glob=pattern] <path>:
   List the path given printing out each entry (N if it's a directory). Use --long to get ls -l style output.
   If the entry is a directory it will be suppressed from the output unless --directory is set (ls -d style).
-  NOTE: Only expands one level of a directory.
+  By default only one level of a directory is expanded. Use --depth to descend into subdirectories.
 `
 }
 
 func (p *lsCmd) SetFlags(f *flag.FlagSet) {
 	f.BoolVar(&p.long, "long", false, "If true prints out as ls -l would have done")
 	f.BoolVar(&p.directory, "directory", false, "If true prints out the entry if it was a directory and nothing else (as ls -d)")
+	f.UintVar(&p.depth, "depth", 0, "How many levels of subdirectories to also list")
+	f.StringVar(&p.glob, "glob", "", "If set only list entries whose name matches this pattern (i.e. *.log)")
 }
 
 func (p *lsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -687,6 +1166,8 @@ func (p *lsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{
 	for _, filename := range f.Args() {
 		req := &pb.ListRequest{
 			Entry: filename,
+			Depth: uint32(p.depth),
+			Glob:  p.glob,
 		}
 
 		stream, err := c.ListOneMany(ctx, req)
@@ -755,7 +1236,11 @@ func (p *lsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{
 				}
 				t := fs.FileMode(r.Resp.Entry.Mode).String()
 				mod := r.Resp.Entry.Modtime.AsTime().Format(time.UnixDate)
-				fmt.Fprintf(state.Out[r.Index], "%-11s  - %8d %8d %16d %s %s\n", t, r.Resp.Entry.Uid, r.Resp.Entry.Gid, r.Resp.Entry.Size, mod, r.Resp.Entry.Filename)
+				name := r.Resp.Entry.Filename
+				if r.Resp.Entry.SymlinkTarget != "" {
+					name += " -> " + r.Resp.Entry.SymlinkTarget
+				}
+				fmt.Fprintf(state.Out[r.Index], "%-11s  - %8d %8d %16d %s %s\n", t, r.Resp.Entry.Uid, r.Resp.Entry.Gid, r.Resp.Entry.Size, mod, name)
 			}
 		}
 	}
@@ -763,30 +1248,50 @@ func (p *lsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{
 }
 
 type cpCmd struct {
-	bucket    string
-	overwrite bool
-	uid       int
-	gid       int
-	mode      int
-	immutable bool
+	bucket         string
+	sourceTarget   string
+	overwrite      bool
+	appendFile     bool
+	expectedSHA256 string
+	chunkSize      int64
+	uid            int
+	gid            int
+	mode           int
+	immutable      bool
+	lock           string
+	lockPath       string
+	lockTimeout    time.Duration
 }
 
 func (*cpCmd) Name() string     { return "cp" }
 func (*cpCmd) Synopsis() string { return "Copy a file onto a remote machine." }
 func (*cpCmd) Usage() string {
-	return `cp [--bucket=XXX] [--overwrite] --uid=X --gid=X --mode=X [--immutable] <source> <remote destination>
+	return `cp [--bucket=XXX|--source-target=X] [--overwrite|--append] [--expected-sha256=X] [--chunk-size=X] [--lock=flock|fcntl [--lock-path=X] [--lock-timeout=X]] --uid=X --gid=X --mode=X [--immutable] <source> <remote destination>
   Copy the source file (which can be local or a URL such as s3://bucket/source) to the target(s)
-  placing it into the remote destination.
+  placing it into the remote destination. The remote file is replaced atomically so readers
+  see either the old or the new contents. With --chunk-size a local source is sent with a
+  manifest of per chunk SHA256 sums which each target verifies before replacing the file.
+  With --source-target the source is a file on that target which the proxy copies to each
+  target directly, rather than through this client. With --lock an advisory lock is held on
+  the remote destination (or --lock-path) while it's written so concurrent writers can't
+  interleave their changes.
 `
 }
 
 func (p *cpCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&p.bucket, "bucket", "", "If set to a valid prefix will copy from this bucket with the key being the source provided")
+	f.StringVar(&p.sourceTarget, "source-target", "", "If set the source is a file on this target, copied to the target(s) by the proxy")
 	f.BoolVar(&p.overwrite, "overwrite", false, "If true will overwrite the remote file. Otherwise the file pre-existing is an error.")
+	f.BoolVar(&p.appendFile, "append", false, "If true appends to the remote file (creating it if needed) rather than replacing its contents.")
+	f.StringVar(&p.expectedSHA256, "expected-sha256", "", "If set the remote file must have this SHA256 sum when replaced, to avoid overwriting concurrent changes. Requires --overwrite or --append.")
+	f.Int64Var(&p.chunkSize, "chunk-size", 0, "If positive send a manifest of SHA256 sums of chunks of this many bytes which targets verify the written file against. Not supported with --bucket or --append.")
 	f.IntVar(&p.uid, "uid", -1, "The uid the remote file will be set via chown.")
 	f.IntVar(&p.gid, "gid", -1, "The gid the remote file will be set via chown.")
 	f.IntVar(&p.mode, "mode", -1, "The mode the remote file will be set via chmod.")
 	f.BoolVar(&p.immutable, "immutable", false, "If true sets the remote file to immutable after being written.")
+	f.StringVar(&p.lock, "lock", "", "If set the type of advisory lock (flock or fcntl) to hold on the remote file while writing it.")
+	f.StringVar(&p.lockPath, "lock-path", "", "If set the remote file to lock instead of the destination, created if needed. Required with --lock if the destination may not exist.")
+	f.DurationVar(&p.lockTimeout, "lock-timeout", 0, "How long to wait for the lock. If zero fail immediately if it's held.")
 }
 
 var validOutputPrefixes = []string{
@@ -805,6 +1310,38 @@ func (p *cpCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{
 		fmt.Fprintln(os.Stderr, "Must set --uid, --gid and --mode")
 		return subcommands.ExitUsageError
 	}
+	if p.chunkSize > 0 && (p.bucket != "" || p.sourceTarget != "" || p.appendFile) {
+		fmt.Fprintln(os.Stderr, "--chunk-size can't be used with --bucket, --source-target or --append")
+		return subcommands.ExitUsageError
+	}
+	if p.bucket != "" && p.sourceTarget != "" {
+		fmt.Fprintln(os.Stderr, "--bucket and --source-target are mutually exclusive")
+		return subcommands.ExitUsageError
+	}
+	if p.sourceTarget != "" && state.Conn.Direct() {
+		fmt.Fprintln(os.Stderr, "--source-target requires a proxy")
+		return subcommands.ExitUsageError
+	}
+
+	var lock *pb.FileLock
+	if p.lock != "" {
+		lock = &pb.FileLock{Path: p.lockPath}
+		switch p.lock {
+		case "flock":
+			lock.Type = pb.LockType_LOCK_TYPE_FLOCK
+		case "fcntl":
+			lock.Type = pb.LockType_LOCK_TYPE_FCNTL
+		default:
+			fmt.Fprintln(os.Stderr, "--lock must be flock or fcntl")
+			return subcommands.ExitUsageError
+		}
+		if p.lockTimeout != 0 {
+			lock.Timeout = durationpb.New(p.lockTimeout)
+		}
+	} else if p.lockPath != "" || p.lockTimeout != 0 {
+		fmt.Fprintln(os.Stderr, "--lock-path and --lock-timeout require --lock")
+		return subcommands.ExitUsageError
+	}
 
 	c := pb.NewLocalFileClientProxy(state.Conn)
 	source := f.Args()[0]
@@ -853,7 +1390,10 @@ func (p *cpCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{
 				},
 			},
 		},
-		Overwrite: p.overwrite,
+		Overwrite:      p.overwrite,
+		Append:         p.appendFile,
+		ExpectedSha256: p.expectedSHA256,
+		Lock:           lock,
 	}
 
 	// Copy case (simpler)
@@ -882,6 +1422,10 @@ func (p *cpCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{
 		return retCode
 	}
 
+	if p.sourceTarget != "" {
+		return p.transfer(ctx, state, source, descr)
+	}
+
 	// Write case (have to send over the local file).
 	f1, err := os.Open(source)
 	if err != nil {
@@ -890,6 +1434,15 @@ func (p *cpCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{
 	}
 	defer f1.Close()
 
+	if p.chunkSize > 0 {
+		m, err := manifest(f1, p.chunkSize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Can't compute manifest of %s - %v\n", source, err)
+			return subcommands.ExitFailure
+		}
+		descr.Manifest = m
+	}
+
 	stream, err := c.WriteOneMany(ctx)
 	if err != nil {
 		// Emit this to every error file as it's not specific to a given target.
@@ -955,18 +1508,82 @@ func (p *cpCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{
 	return retCode
 }
 
+// transfer has the proxy copy source on --source-target to each target,
+// in parallel.
+func (p *cpCmd) transfer(ctx context.Context, state *util.ExecuteState, source string, descr *pb.FileWrite) subcommands.ExitStatus {
+	// Get a real connection to the proxy
+	c := pb.NewTransferClient(state.Conn.Proxy())
+
+	errs := make([]error, len(state.Conn.Targets))
+	replies := make([]*pb.TransferReply, len(state.Conn.Targets))
+	var wg sync.WaitGroup
+	for i, target := range state.Conn.Targets {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			replies[i], errs[i] = c.CopyFile(ctx, &pb.TransferRequest{
+				SourceTarget:      p.sourceTarget,
+				Source:            source,
+				DestinationTarget: target,
+				Destination:       descr,
+			})
+		}(i, target)
+	}
+	wg.Wait()
+
+	retCode := subcommands.ExitSuccess
+	for i, target := range state.Conn.Targets {
+		if errs[i] != nil {
+			fmt.Fprintf(state.Err[i], "Copy from %s to target %s (%d) returned error: %v\n", p.sourceTarget, target, i, errs[i])
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[i], "Copied %d bytes (sha256 %s) from %s\n", replies[i].Bytes, replies[i].Sha256, p.sourceTarget)
+	}
+	return retCode
+}
+
+// manifest returns the manifest of f using chunks of chunkSize bytes and
+// then rewinds it.
+func manifest(f *os.File, chunkSize int64) (*pb.FileManifest, error) {
+	m := &pb.FileManifest{ChunkSize: chunkSize}
+	total := sha256.New()
+	for {
+		chunk := sha256.New()
+		n, err := io.CopyN(io.MultiWriter(chunk, total), f, chunkSize)
+		if n > 0 {
+			m.ChunkSha256 = append(m.ChunkSha256, hex.EncodeToString(chunk.Sum(nil)))
+			m.Size += n
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.Sha256 = hex.EncodeToString(total.Sum(nil))
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 type rmCmd struct {
+	expectedTarget string
 }
 
 func (*rmCmd) Name() string     { return "rm" }
 func (*rmCmd) Synopsis() string { return "Remove a file." }
 func (*rmCmd) Usage() string {
-	return `rm <filename>:
+	return `rm [--expected-target=X] <filename>:
   Remove the given filename.
   `
 }
 
-func (i *rmCmd) SetFlags(f *flag.FlagSet) {}
+func (i *rmCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&i.expectedTarget, "expected-target", "", "If set the file must be a symlink to this target or it isn't removed.")
+}
 
 func (i *rmCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
 	state := args[0].(*util.ExecuteState)
@@ -976,7 +1593,8 @@ func (i *rmCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{
 	}
 
 	req := &pb.RmRequest{
-		Filename: f.Args()[0],
+		Filename:       f.Args()[0],
+		ExpectedTarget: i.expectedTarget,
 	}
 	client := pb.NewLocalFileClientProxy(state.Conn)
 	respChan, err := client.RmOneMany(ctx, req)
@@ -1040,3 +1658,315 @@ func (i *rmdirCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfa
 	}
 	return retCode
 }
+
+type archiveCmd struct {
+	gzip bool
+}
+
+func (*archiveCmd) Name() string     { return "archive" }
+func (*archiveCmd) Synopsis() string { return "Fetch a directory as a tar archive." }
+func (*archiveCmd) Usage() string {
+	return `archive [--gzip] <directory>:
+  Archive the remote directory as a tar file (optionally gzipped) and write it to the appropriate --output destination.
+  The archive is checked against the checksum sent by each target once complete.
+`
+}
+
+func (a *archiveCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&a.gzip, "gzip", false, "If true gzip the archive")
+}
+
+func (a *archiveCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "please specify a directory to archive")
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.ArchiveRequest{
+		Directory: f.Args()[0],
+		Gzip:      a.gzip,
+	}
+	client := pb.NewLocalFileClientProxy(state.Conn)
+	stream, err := client.ArchiveOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "archive client error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	exit := subcommands.ExitSuccess
+	hashers := make(map[int]hash.Hash)
+	failed := make(map[int]bool)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "Stream error: %v\n", err)
+			}
+			exit = subcommands.ExitFailure
+			break
+		}
+		for _, r := range resp {
+			if failed[r.Index] {
+				continue
+			}
+			if r.Error != nil && r.Error != io.EOF {
+				fmt.Fprintf(state.Err[r.Index], "Target %s (%d) returned error - %v\n", r.Target, r.Index, r.Error)
+				failed[r.Index] = true
+				exit = subcommands.ExitFailure
+				continue
+			}
+			if r.Resp == nil {
+				continue
+			}
+			h, ok := hashers[r.Index]
+			if !ok {
+				h = sha256.New()
+				hashers[r.Index] = h
+			}
+			if r.Resp.Sha256 != "" {
+				if got := hex.EncodeToString(h.Sum(nil)); got != r.Resp.Sha256 {
+					fmt.Fprintf(state.Err[r.Index], "Target %s (%d) archive checksum mismatch: got %s want %s\n", r.Target, r.Index, got, r.Resp.Sha256)
+					failed[r.Index] = true
+					exit = subcommands.ExitFailure
+				}
+				continue
+			}
+			h.Write(r.Resp.Contents)
+			if _, err := state.Out[r.Index].Write(r.Resp.Contents); err != nil {
+				fmt.Fprintf(state.Err[r.Index], "error writing output to output index %d - %v\n", r.Index, err)
+				failed[r.Index] = true
+				exit = subcommands.ExitFailure
+			}
+		}
+	}
+	return exit
+}
+
+type lnCmd struct {
+	symbolic       bool
+	replace        bool
+	expectedTarget string
+}
+
+func (*lnCmd) Name() string     { return "ln" }
+func (*lnCmd) Synopsis() string { return "Create a link." }
+func (*lnCmd) Usage() string {
+	return `ln [-s [--replace] [--expected-target=X]] <target> <linkname>:
+  Create a hard link (or with -s a symbolic link) named linkname pointing to target.
+  With --replace an existing linkname is atomically replaced, i.e. to flip a "current" symlink.
+`
+}
+
+func (l *lnCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&l.symbolic, "s", false, "If true create a symbolic link instead of a hard link")
+	f.BoolVar(&l.replace, "replace", false, "If true atomically replace an existing linkname. Symbolic links only.")
+	f.StringVar(&l.expectedTarget, "expected-target", "", "If set linkname must currently be a symlink to this target. Requires --replace.")
+}
+
+func (l *lnCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "please specify a target and linkname")
+		return subcommands.ExitUsageError
+	}
+	if !l.symbolic && (l.replace || l.expectedTarget != "") {
+		fmt.Fprintln(os.Stderr, "--replace and --expected-target require -s")
+		return subcommands.ExitUsageError
+	}
+
+	client := pb.NewLocalFileClientProxy(state.Conn)
+	if l.symbolic {
+		respChan, err := client.SymlinkOneMany(ctx, &pb.SymlinkRequest{
+			Target:         f.Args()[0],
+			Linkname:       f.Args()[1],
+			Replace:        l.replace,
+			ExpectedTarget: l.expectedTarget,
+		})
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "ln client error: %v\n", err)
+			}
+			return subcommands.ExitFailure
+		}
+		retCode := subcommands.ExitSuccess
+		for r := range respChan {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "ln client error: %v\n", r.Error)
+				retCode = subcommands.ExitFailure
+			}
+		}
+		return retCode
+	}
+
+	respChan, err := client.LinkOneMany(ctx, &pb.LinkRequest{
+		Target:   f.Args()[0],
+		Linkname: f.Args()[1],
+	})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "ln client error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "ln client error: %v\n", r.Error)
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
+
+type readlinkCmd struct{}
+
+func (*readlinkCmd) Name() string     { return "readlink" }
+func (*readlinkCmd) Synopsis() string { return "Print the target of a symbolic link." }
+func (*readlinkCmd) Usage() string {
+	return `readlink <path>:
+  Print the target of the symbolic link at path.
+`
+}
+
+func (*readlinkCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*readlinkCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "please specify a link to read")
+		return subcommands.ExitUsageError
+	}
+
+	client := pb.NewLocalFileClientProxy(state.Conn)
+	respChan, err := client.ReadlinkOneMany(ctx, &pb.ReadlinkRequest{Filename: f.Args()[0]})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "readlink client error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "readlink client error: %v\n", r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintln(state.Out[r.Index], r.Resp.Target)
+	}
+	return retCode
+}
+
+type getXattrCmd struct{}
+
+func (*getXattrCmd) Name() string     { return "getxattr" }
+func (*getXattrCmd) Synopsis() string { return "Print extended attributes of a file." }
+func (*getXattrCmd) Usage() string {
+	return `getxattr <path> [name ...]:
+  Print the named extended attributes (or all of them if none are named) of path as name="value".
+`
+}
+
+func (*getXattrCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*getXattrCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "please specify a filename to get xattrs of")
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.GetXattrsRequest{
+		Filename: f.Args()[0],
+		Names:    f.Args()[1:],
+	}
+	client := pb.NewLocalFileClientProxy(state.Conn)
+	respChan, err := client.GetXattrsOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "getxattr client error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "getxattr client error: %v\n", r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, x := range r.Resp.Xattrs {
+			fmt.Fprintf(state.Out[r.Index], "%s=%q\n", x.Name, x.Value)
+		}
+	}
+	return retCode
+}
+
+type setXattrCmd struct {
+	remove []string
+}
+
+func (*setXattrCmd) Name() string     { return "setxattr" }
+func (*setXattrCmd) Synopsis() string { return "Set or remove extended attributes of a file." }
+func (*setXattrCmd) Usage() string {
+	return `setxattr [--remove=name,...] <path> [name=value ...]:
+  Set each given extended attribute of path and remove those named by --remove.
+`
+}
+
+func (s *setXattrCmd) SetFlags(f *flag.FlagSet) {
+	f.Var(&util.StringSliceFlag{Target: &s.remove}, "remove", "Comma separated list of extended attributes to remove")
+}
+
+func (s *setXattrCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() == 0 || (f.NArg() == 1 && len(s.remove) == 0) {
+		fmt.Fprintln(os.Stderr, "please specify a filename and extended attributes to set or remove")
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.SetXattrsRequest{
+		Filename: f.Args()[0],
+		Remove:   s.remove,
+	}
+	for _, a := range f.Args()[1:] {
+		kv := strings.SplitN(a, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			fmt.Fprintf(os.Stderr, "invalid extended attribute %q, must be name=value\n", a)
+			return subcommands.ExitUsageError
+		}
+		req.Set = append(req.Set, &pb.Xattr{Name: kv[0], Value: []byte(kv[1])})
+	}
+	client := pb.NewLocalFileClientProxy(state.Conn)
+	respChan, err := client.SetXattrsOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "setxattr client error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "setxattr client error: %v\n", r.Error)
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
@@ -38,6 +38,28 @@ type LocalFileClient interface {
 	Rm(ctx context.Context, in *RmRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Rmdir removes the given directory (must be empty).
 	Rmdir(ctx context.Context, in *RmdirRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Archive returns a tar archive of a directory tree in chunks.
+	Archive(ctx context.Context, in *ArchiveRequest, opts ...grpc.CallOption) (LocalFile_ArchiveClient, error)
+	// Manifest returns the SHA256 sums of each fixed size chunk of a file so
+	// large transfers can be verified (and resumed) a chunk at a time.
+	Manifest(ctx context.Context, in *ManifestRequest, opts ...grpc.CallOption) (*FileManifest, error)
+	// Symlink creates a symbolic link, optionally replacing an existing one
+	// atomically (i.e. to flip a "current" link during a deploy).
+	Symlink(ctx context.Context, in *SymlinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Readlink returns the target of a symbolic link.
+	Readlink(ctx context.Context, in *ReadlinkRequest, opts ...grpc.CallOption) (*ReadlinkReply, error)
+	// Link creates a hard link to an existing file.
+	Link(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetXattrs returns the extended attributes of a file.
+	GetXattrs(ctx context.Context, in *GetXattrsRequest, opts ...grpc.CallOption) (*GetXattrsReply, error)
+	// SetXattrs sets and/or removes extended attributes of a file.
+	SetXattrs(ctx context.Context, in *SetXattrsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Watch streams changes to files and directories as they happen, until
+	// the caller cancels.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (LocalFile_WatchClient, error)
+	// Grep searches files for lines matching a regular expression, returning
+	// only the matches (and context lines around them).
+	Grep(ctx context.Context, in *GrepRequest, opts ...grpc.CallOption) (LocalFile_GrepClient, error)
 }
 
 type localFileClient struct {
@@ -244,6 +266,156 @@ func (c *localFileClient) Rmdir(ctx context.Context, in *RmdirRequest, opts ...g
 	return out, nil
 }
 
+func (c *localFileClient) Archive(ctx context.Context, in *ArchiveRequest, opts ...grpc.CallOption) (LocalFile_ArchiveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LocalFile_ServiceDesc.Streams[5], "/LocalFile.LocalFile/Archive", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &localFileArchiveClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LocalFile_ArchiveClient interface {
+	Recv() (*ArchiveReply, error)
+	grpc.ClientStream
+}
+
+type localFileArchiveClient struct {
+	grpc.ClientStream
+}
+
+func (x *localFileArchiveClient) Recv() (*ArchiveReply, error) {
+	m := new(ArchiveReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *localFileClient) Manifest(ctx context.Context, in *ManifestRequest, opts ...grpc.CallOption) (*FileManifest, error) {
+	out := new(FileManifest)
+	err := c.cc.Invoke(ctx, "/LocalFile.LocalFile/Manifest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localFileClient) Symlink(ctx context.Context, in *SymlinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/LocalFile.LocalFile/Symlink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localFileClient) Readlink(ctx context.Context, in *ReadlinkRequest, opts ...grpc.CallOption) (*ReadlinkReply, error) {
+	out := new(ReadlinkReply)
+	err := c.cc.Invoke(ctx, "/LocalFile.LocalFile/Readlink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localFileClient) Link(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/LocalFile.LocalFile/Link", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localFileClient) GetXattrs(ctx context.Context, in *GetXattrsRequest, opts ...grpc.CallOption) (*GetXattrsReply, error) {
+	out := new(GetXattrsReply)
+	err := c.cc.Invoke(ctx, "/LocalFile.LocalFile/GetXattrs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localFileClient) SetXattrs(ctx context.Context, in *SetXattrsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/LocalFile.LocalFile/SetXattrs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *localFileClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (LocalFile_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LocalFile_ServiceDesc.Streams[6], "/LocalFile.LocalFile/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &localFileWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LocalFile_WatchClient interface {
+	Recv() (*WatchReply, error)
+	grpc.ClientStream
+}
+
+type localFileWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *localFileWatchClient) Recv() (*WatchReply, error) {
+	m := new(WatchReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *localFileClient) Grep(ctx context.Context, in *GrepRequest, opts ...grpc.CallOption) (LocalFile_GrepClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LocalFile_ServiceDesc.Streams[7], "/LocalFile.LocalFile/Grep", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &localFileGrepClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LocalFile_GrepClient interface {
+	Recv() (*GrepReply, error)
+	grpc.ClientStream
+}
+
+type localFileGrepClient struct {
+	grpc.ClientStream
+}
+
+func (x *localFileGrepClient) Recv() (*GrepReply, error) {
+	m := new(GrepReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // LocalFileServer is the server API for LocalFile service.
 // All implementations should embed UnimplementedLocalFileServer
 // for forward compatibility
@@ -267,6 +439,28 @@ type LocalFileServer interface {
 	Rm(context.Context, *RmRequest) (*emptypb.Empty, error)
 	// Rmdir removes the given directory (must be empty).
 	Rmdir(context.Context, *RmdirRequest) (*emptypb.Empty, error)
+	// Archive returns a tar archive of a directory tree in chunks.
+	Archive(*ArchiveRequest, LocalFile_ArchiveServer) error
+	// Manifest returns the SHA256 sums of each fixed size chunk of a file so
+	// large transfers can be verified (and resumed) a chunk at a time.
+	Manifest(context.Context, *ManifestRequest) (*FileManifest, error)
+	// Symlink creates a symbolic link, optionally replacing an existing one
+	// atomically (i.e. to flip a "current" link during a deploy).
+	Symlink(context.Context, *SymlinkRequest) (*emptypb.Empty, error)
+	// Readlink returns the target of a symbolic link.
+	Readlink(context.Context, *ReadlinkRequest) (*ReadlinkReply, error)
+	// Link creates a hard link to an existing file.
+	Link(context.Context, *LinkRequest) (*emptypb.Empty, error)
+	// GetXattrs returns the extended attributes of a file.
+	GetXattrs(context.Context, *GetXattrsRequest) (*GetXattrsReply, error)
+	// SetXattrs sets and/or removes extended attributes of a file.
+	SetXattrs(context.Context, *SetXattrsRequest) (*emptypb.Empty, error)
+	// Watch streams changes to files and directories as they happen, until
+	// the caller cancels.
+	Watch(*WatchRequest, LocalFile_WatchServer) error
+	// Grep searches files for lines matching a regular expression, returning
+	// only the matches (and context lines around them).
+	Grep(*GrepRequest, LocalFile_GrepServer) error
 }
 
 // UnimplementedLocalFileServer should be embedded to have forward compatible implementations.
@@ -300,6 +494,33 @@ func (UnimplementedLocalFileServer) Rm(context.Context, *RmRequest) (*emptypb.Em
 func (UnimplementedLocalFileServer) Rmdir(context.Context, *RmdirRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Rmdir not implemented")
 }
+func (UnimplementedLocalFileServer) Archive(*ArchiveRequest, LocalFile_ArchiveServer) error {
+	return status.Errorf(codes.Unimplemented, "method Archive not implemented")
+}
+func (UnimplementedLocalFileServer) Manifest(context.Context, *ManifestRequest) (*FileManifest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Manifest not implemented")
+}
+func (UnimplementedLocalFileServer) Symlink(context.Context, *SymlinkRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Symlink not implemented")
+}
+func (UnimplementedLocalFileServer) Readlink(context.Context, *ReadlinkRequest) (*ReadlinkReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Readlink not implemented")
+}
+func (UnimplementedLocalFileServer) Link(context.Context, *LinkRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Link not implemented")
+}
+func (UnimplementedLocalFileServer) GetXattrs(context.Context, *GetXattrsRequest) (*GetXattrsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetXattrs not implemented")
+}
+func (UnimplementedLocalFileServer) SetXattrs(context.Context, *SetXattrsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetXattrs not implemented")
+}
+func (UnimplementedLocalFileServer) Watch(*WatchRequest, LocalFile_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedLocalFileServer) Grep(*GrepRequest, LocalFile_GrepServer) error {
+	return status.Errorf(codes.Unimplemented, "method Grep not implemented")
+}
 
 // UnsafeLocalFileServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to LocalFileServer will
@@ -504,6 +725,177 @@ func _LocalFile_Rmdir_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LocalFile_Archive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ArchiveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LocalFileServer).Archive(m, &localFileArchiveServer{stream})
+}
+
+type LocalFile_ArchiveServer interface {
+	Send(*ArchiveReply) error
+	grpc.ServerStream
+}
+
+type localFileArchiveServer struct {
+	grpc.ServerStream
+}
+
+func (x *localFileArchiveServer) Send(m *ArchiveReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LocalFile_Manifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ManifestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocalFileServer).Manifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/LocalFile.LocalFile/Manifest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocalFileServer).Manifest(ctx, req.(*ManifestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocalFile_Symlink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SymlinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocalFileServer).Symlink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/LocalFile.LocalFile/Symlink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocalFileServer).Symlink(ctx, req.(*SymlinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocalFile_Readlink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadlinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocalFileServer).Readlink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/LocalFile.LocalFile/Readlink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocalFileServer).Readlink(ctx, req.(*ReadlinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocalFile_Link_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocalFileServer).Link(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/LocalFile.LocalFile/Link",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocalFileServer).Link(ctx, req.(*LinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocalFile_GetXattrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetXattrsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocalFileServer).GetXattrs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/LocalFile.LocalFile/GetXattrs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocalFileServer).GetXattrs(ctx, req.(*GetXattrsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocalFile_SetXattrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetXattrsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocalFileServer).SetXattrs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/LocalFile.LocalFile/SetXattrs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocalFileServer).SetXattrs(ctx, req.(*SetXattrsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocalFile_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LocalFileServer).Watch(m, &localFileWatchServer{stream})
+}
+
+type LocalFile_WatchServer interface {
+	Send(*WatchReply) error
+	grpc.ServerStream
+}
+
+type localFileWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *localFileWatchServer) Send(m *WatchReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LocalFile_Grep_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GrepRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LocalFileServer).Grep(m, &localFileGrepServer{stream})
+}
+
+type LocalFile_GrepServer interface {
+	Send(*GrepReply) error
+	grpc.ServerStream
+}
+
+type localFileGrepServer struct {
+	grpc.ServerStream
+}
+
+func (x *localFileGrepServer) Send(m *GrepReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // LocalFile_ServiceDesc is the grpc.ServiceDesc for LocalFile service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -527,6 +919,30 @@ var LocalFile_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Rmdir",
 			Handler:    _LocalFile_Rmdir_Handler,
 		},
+		{
+			MethodName: "Manifest",
+			Handler:    _LocalFile_Manifest_Handler,
+		},
+		{
+			MethodName: "Symlink",
+			Handler:    _LocalFile_Symlink_Handler,
+		},
+		{
+			MethodName: "Readlink",
+			Handler:    _LocalFile_Readlink_Handler,
+		},
+		{
+			MethodName: "Link",
+			Handler:    _LocalFile_Link_Handler,
+		},
+		{
+			MethodName: "GetXattrs",
+			Handler:    _LocalFile_GetXattrs_Handler,
+		},
+		{
+			MethodName: "SetXattrs",
+			Handler:    _LocalFile_SetXattrs_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -556,6 +972,107 @@ var LocalFile_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _LocalFile_List_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Archive",
+			Handler:       _LocalFile_Archive_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _LocalFile_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Grep",
+			Handler:       _LocalFile_Grep_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "localfile.proto",
+}
+
+// TransferClient is the client API for Transfer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TransferClient interface {
+	// CopyFile copies a file from one target to another.
+	CopyFile(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferReply, error)
+}
+
+type transferClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransferClient(cc grpc.ClientConnInterface) TransferClient {
+	return &transferClient{cc}
+}
+
+func (c *transferClient) CopyFile(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferReply, error) {
+	out := new(TransferReply)
+	err := c.cc.Invoke(ctx, "/LocalFile.Transfer/CopyFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TransferServer is the server API for Transfer service.
+// All implementations should embed UnimplementedTransferServer
+// for forward compatibility
+type TransferServer interface {
+	// CopyFile copies a file from one target to another.
+	CopyFile(context.Context, *TransferRequest) (*TransferReply, error)
+}
+
+// UnimplementedTransferServer should be embedded to have forward compatible implementations.
+type UnimplementedTransferServer struct {
+}
+
+func (UnimplementedTransferServer) CopyFile(context.Context, *TransferRequest) (*TransferReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CopyFile not implemented")
+}
+
+// UnsafeTransferServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransferServer will
+// result in compilation errors.
+type UnsafeTransferServer interface {
+	mustEmbedUnimplementedTransferServer()
+}
+
+func RegisterTransferServer(s grpc.ServiceRegistrar, srv TransferServer) {
+	s.RegisterService(&Transfer_ServiceDesc, srv)
+}
+
+func _Transfer_CopyFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransferServer).CopyFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/LocalFile.Transfer/CopyFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransferServer).CopyFile(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Transfer_ServiceDesc is the grpc.ServiceDesc for Transfer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Transfer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "LocalFile.Transfer",
+	HandlerType: (*TransferServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CopyFile",
+			Handler:    _Transfer_CopyFile_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "localfile.proto",
 }
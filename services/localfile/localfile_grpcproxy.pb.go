@@ -29,6 +29,15 @@ type LocalFileClientProxy interface {
 	SetFileAttributesOneMany(ctx context.Context, in *SetFileAttributesRequest, opts ...grpc.CallOption) (<-chan *SetFileAttributesManyResponse, error)
 	RmOneMany(ctx context.Context, in *RmRequest, opts ...grpc.CallOption) (<-chan *RmManyResponse, error)
 	RmdirOneMany(ctx context.Context, in *RmdirRequest, opts ...grpc.CallOption) (<-chan *RmdirManyResponse, error)
+	ArchiveOneMany(ctx context.Context, in *ArchiveRequest, opts ...grpc.CallOption) (LocalFile_ArchiveClientProxy, error)
+	ManifestOneMany(ctx context.Context, in *ManifestRequest, opts ...grpc.CallOption) (<-chan *ManifestManyResponse, error)
+	SymlinkOneMany(ctx context.Context, in *SymlinkRequest, opts ...grpc.CallOption) (<-chan *SymlinkManyResponse, error)
+	ReadlinkOneMany(ctx context.Context, in *ReadlinkRequest, opts ...grpc.CallOption) (<-chan *ReadlinkManyResponse, error)
+	LinkOneMany(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (<-chan *LinkManyResponse, error)
+	GetXattrsOneMany(ctx context.Context, in *GetXattrsRequest, opts ...grpc.CallOption) (<-chan *GetXattrsManyResponse, error)
+	SetXattrsOneMany(ctx context.Context, in *SetXattrsRequest, opts ...grpc.CallOption) (<-chan *SetXattrsManyResponse, error)
+	WatchOneMany(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (LocalFile_WatchClientProxy, error)
+	GrepOneMany(ctx context.Context, in *GrepRequest, opts ...grpc.CallOption) (LocalFile_GrepClientProxy, error)
 }
 
 // Embed the original client inside of this so we get the other generated methods automatically.
@@ -766,3 +775,753 @@ func (c *localFileClientProxy) RmdirOneMany(ctx context.Context, in *RmdirReques
 
 	return ret, nil
 }
+
+// ArchiveManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ArchiveManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ArchiveReply
+	Error error
+}
+
+type LocalFile_ArchiveClientProxy interface {
+	Recv() ([]*ArchiveManyResponse, error)
+	grpc.ClientStream
+}
+
+type localFileClientArchiveClientProxy struct {
+	cc         *proxy.Conn
+	directDone bool
+	grpc.ClientStream
+}
+
+func (x *localFileClientArchiveClientProxy) Recv() ([]*ArchiveManyResponse, error) {
+	var ret []*ArchiveManyResponse
+	// If this is a direct connection the RecvMsg call is to a standard grpc.ClientStream
+	// and not our proxy based one. This means we need to receive a typed response and
+	// convert it into a single slice entry return. This ensures the OneMany style calls
+	// can be used by proxy with 1:N targets and non proxy with 1 target without client changes.
+	if x.cc.Direct() {
+		// Check if we're done. Just return EOF now. Any real error was already sent inside
+		// of a ManyResponse.
+		if x.directDone {
+			return nil, io.EOF
+		}
+		m := &ArchiveReply{}
+		err := x.ClientStream.RecvMsg(m)
+		ret = append(ret, &ArchiveManyResponse{
+			Resp:   m,
+			Error:  err,
+			Target: x.cc.Targets[0],
+			Index:  0,
+		})
+		// An error means we're done so set things so a later call now gets an EOF.
+		if err != nil {
+			x.directDone = true
+		}
+		return ret, nil
+	}
+
+	m := []*proxy.Ret{}
+	if err := x.ClientStream.RecvMsg(&m); err != nil {
+		return nil, err
+	}
+	for _, r := range m {
+		typedResp := &ArchiveManyResponse{
+			Resp: &ArchiveReply{},
+		}
+		typedResp.Target = r.Target
+		typedResp.Index = r.Index
+		typedResp.Error = r.Error
+		if r.Error == nil {
+			if err := r.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+				typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, r.Error)
+			}
+		}
+		ret = append(ret, typedResp)
+	}
+	return ret, nil
+}
+
+// ArchiveOneMany provides the same API as Archive but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *localFileClientProxy) ArchiveOneMany(ctx context.Context, in *ArchiveRequest, opts ...grpc.CallOption) (LocalFile_ArchiveClientProxy, error) {
+	stream, err := c.cc.NewStream(ctx, &LocalFile_ServiceDesc.Streams[5], "/LocalFile.LocalFile/Archive", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &localFileClientArchiveClientProxy{c.cc.(*proxy.Conn), false, stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// ManifestManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ManifestManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *FileManifest
+	Error error
+}
+
+// ManifestOneMany provides the same API as Manifest but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *localFileClientProxy) ManifestOneMany(ctx context.Context, in *ManifestRequest, opts ...grpc.CallOption) (<-chan *ManifestManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ManifestManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ManifestManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &FileManifest{},
+			}
+			err := conn.Invoke(ctx, "/LocalFile.LocalFile/Manifest", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/LocalFile.LocalFile/Manifest", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ManifestManyResponse{
+				Resp: &FileManifest{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// SymlinkManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type SymlinkManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *emptypb.Empty
+	Error error
+}
+
+// SymlinkOneMany provides the same API as Symlink but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *localFileClientProxy) SymlinkOneMany(ctx context.Context, in *SymlinkRequest, opts ...grpc.CallOption) (<-chan *SymlinkManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *SymlinkManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &SymlinkManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &emptypb.Empty{},
+			}
+			err := conn.Invoke(ctx, "/LocalFile.LocalFile/Symlink", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/LocalFile.LocalFile/Symlink", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &SymlinkManyResponse{
+				Resp: &emptypb.Empty{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ReadlinkManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ReadlinkManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ReadlinkReply
+	Error error
+}
+
+// ReadlinkOneMany provides the same API as Readlink but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *localFileClientProxy) ReadlinkOneMany(ctx context.Context, in *ReadlinkRequest, opts ...grpc.CallOption) (<-chan *ReadlinkManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ReadlinkManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ReadlinkManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ReadlinkReply{},
+			}
+			err := conn.Invoke(ctx, "/LocalFile.LocalFile/Readlink", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/LocalFile.LocalFile/Readlink", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ReadlinkManyResponse{
+				Resp: &ReadlinkReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// LinkManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type LinkManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *emptypb.Empty
+	Error error
+}
+
+// LinkOneMany provides the same API as Link but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *localFileClientProxy) LinkOneMany(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (<-chan *LinkManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *LinkManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &LinkManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &emptypb.Empty{},
+			}
+			err := conn.Invoke(ctx, "/LocalFile.LocalFile/Link", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/LocalFile.LocalFile/Link", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &LinkManyResponse{
+				Resp: &emptypb.Empty{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// GetXattrsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type GetXattrsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *GetXattrsReply
+	Error error
+}
+
+// GetXattrsOneMany provides the same API as GetXattrs but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *localFileClientProxy) GetXattrsOneMany(ctx context.Context, in *GetXattrsRequest, opts ...grpc.CallOption) (<-chan *GetXattrsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *GetXattrsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &GetXattrsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &GetXattrsReply{},
+			}
+			err := conn.Invoke(ctx, "/LocalFile.LocalFile/GetXattrs", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/LocalFile.LocalFile/GetXattrs", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &GetXattrsManyResponse{
+				Resp: &GetXattrsReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// SetXattrsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type SetXattrsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *emptypb.Empty
+	Error error
+}
+
+// SetXattrsOneMany provides the same API as SetXattrs but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *localFileClientProxy) SetXattrsOneMany(ctx context.Context, in *SetXattrsRequest, opts ...grpc.CallOption) (<-chan *SetXattrsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *SetXattrsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &SetXattrsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &emptypb.Empty{},
+			}
+			err := conn.Invoke(ctx, "/LocalFile.LocalFile/SetXattrs", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/LocalFile.LocalFile/SetXattrs", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &SetXattrsManyResponse{
+				Resp: &emptypb.Empty{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// WatchManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type WatchManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *WatchReply
+	Error error
+}
+
+type LocalFile_WatchClientProxy interface {
+	Recv() ([]*WatchManyResponse, error)
+	grpc.ClientStream
+}
+
+type localFileClientWatchClientProxy struct {
+	cc         *proxy.Conn
+	directDone bool
+	grpc.ClientStream
+}
+
+func (x *localFileClientWatchClientProxy) Recv() ([]*WatchManyResponse, error) {
+	var ret []*WatchManyResponse
+	// If this is a direct connection the RecvMsg call is to a standard grpc.ClientStream
+	// and not our proxy based one. This means we need to receive a typed response and
+	// convert it into a single slice entry return. This ensures the OneMany style calls
+	// can be used by proxy with 1:N targets and non proxy with 1 target without client changes.
+	if x.cc.Direct() {
+		// Check if we're done. Just return EOF now. Any real error was already sent inside
+		// of a ManyResponse.
+		if x.directDone {
+			return nil, io.EOF
+		}
+		m := &WatchReply{}
+		err := x.ClientStream.RecvMsg(m)
+		ret = append(ret, &WatchManyResponse{
+			Resp:   m,
+			Error:  err,
+			Target: x.cc.Targets[0],
+			Index:  0,
+		})
+		// An error means we're done so set things so a later call now gets an EOF.
+		if err != nil {
+			x.directDone = true
+		}
+		return ret, nil
+	}
+
+	m := []*proxy.Ret{}
+	if err := x.ClientStream.RecvMsg(&m); err != nil {
+		return nil, err
+	}
+	for _, r := range m {
+		typedResp := &WatchManyResponse{
+			Resp: &WatchReply{},
+		}
+		typedResp.Target = r.Target
+		typedResp.Index = r.Index
+		typedResp.Error = r.Error
+		if r.Error == nil {
+			if err := r.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+				typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, r.Error)
+			}
+		}
+		ret = append(ret, typedResp)
+	}
+	return ret, nil
+}
+
+// WatchOneMany provides the same API as Watch but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *localFileClientProxy) WatchOneMany(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (LocalFile_WatchClientProxy, error) {
+	stream, err := c.cc.NewStream(ctx, &LocalFile_ServiceDesc.Streams[6], "/LocalFile.LocalFile/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &localFileClientWatchClientProxy{c.cc.(*proxy.Conn), false, stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// GrepManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type GrepManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *GrepReply
+	Error error
+}
+
+type LocalFile_GrepClientProxy interface {
+	Recv() ([]*GrepManyResponse, error)
+	grpc.ClientStream
+}
+
+type localFileClientGrepClientProxy struct {
+	cc         *proxy.Conn
+	directDone bool
+	grpc.ClientStream
+}
+
+func (x *localFileClientGrepClientProxy) Recv() ([]*GrepManyResponse, error) {
+	var ret []*GrepManyResponse
+	// If this is a direct connection the RecvMsg call is to a standard grpc.ClientStream
+	// and not our proxy based one. This means we need to receive a typed response and
+	// convert it into a single slice entry return. This ensures the OneMany style calls
+	// can be used by proxy with 1:N targets and non proxy with 1 target without client changes.
+	if x.cc.Direct() {
+		// Check if we're done. Just return EOF now. Any real error was already sent inside
+		// of a ManyResponse.
+		if x.directDone {
+			return nil, io.EOF
+		}
+		m := &GrepReply{}
+		err := x.ClientStream.RecvMsg(m)
+		ret = append(ret, &GrepManyResponse{
+			Resp:   m,
+			Error:  err,
+			Target: x.cc.Targets[0],
+			Index:  0,
+		})
+		// An error means we're done so set things so a later call now gets an EOF.
+		if err != nil {
+			x.directDone = true
+		}
+		return ret, nil
+	}
+
+	m := []*proxy.Ret{}
+	if err := x.ClientStream.RecvMsg(&m); err != nil {
+		return nil, err
+	}
+	for _, r := range m {
+		typedResp := &GrepManyResponse{
+			Resp: &GrepReply{},
+		}
+		typedResp.Target = r.Target
+		typedResp.Index = r.Index
+		typedResp.Error = r.Error
+		if r.Error == nil {
+			if err := r.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+				typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, r.Error)
+			}
+		}
+		ret = append(ret, typedResp)
+	}
+	return ret, nil
+}
+
+// GrepOneMany provides the same API as Grep but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *localFileClientProxy) GrepOneMany(ctx context.Context, in *GrepRequest, opts ...grpc.CallOption) (LocalFile_GrepClientProxy, error) {
+	stream, err := c.cc.NewStream(ctx, &LocalFile_ServiceDesc.Streams[7], "/LocalFile.LocalFile/Grep", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &localFileClientGrepClientProxy{c.cc.(*proxy.Conn), false, stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// TransferClientProxy is the superset of TransferClient which additionally includes the OneMany proxy methods
+type TransferClientProxy interface {
+	TransferClient
+	CopyFileOneMany(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (<-chan *CopyFileManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type transferClientProxy struct {
+	*transferClient
+}
+
+// NewTransferClientProxy creates a TransferClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewTransferClientProxy(cc *proxy.Conn) TransferClientProxy {
+	return &transferClientProxy{NewTransferClient(cc).(*transferClient)}
+}
+
+// CopyFileManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type CopyFileManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *TransferReply
+	Error error
+}
+
+// CopyFileOneMany provides the same API as CopyFile but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *transferClientProxy) CopyFileOneMany(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (<-chan *CopyFileManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *CopyFileManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &CopyFileManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &TransferReply{},
+			}
+			err := conn.Invoke(ctx, "/LocalFile.Transfer/CopyFile", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/LocalFile.Transfer/CopyFile", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &CopyFileManyResponse{
+				Resp: &TransferReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
@@ -0,0 +1,178 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+// archiveSender sends each Write as an ArchiveReply.
+type archiveSender struct {
+	stream pb.LocalFile_ArchiveServer
+}
+
+func (a *archiveSender) Write(p []byte) (int, error) {
+	if err := a.stream.Send(&pb.ArchiveReply{Contents: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *server) Archive(req *pb.ArchiveRequest, stream pb.LocalFile_ArchiveServer) error {
+	ctx := stream.Context()
+	logger := logr.FromContextOrDiscard(ctx)
+	logger.Info("archive request", "directory", req.Directory, "gzip", req.Gzip)
+	if err := util.ValidPath(req.Directory); err != nil {
+		return err
+	}
+	if err := checkPath(req.Directory); err != nil {
+		return err
+	}
+	fi, err := os.Stat(req.Directory)
+	if err != nil {
+		return status.Errorf(codes.Internal, "stat: %v", err)
+	}
+	if !fi.IsDir() {
+		return status.Errorf(codes.InvalidArgument, "%s is not a directory", req.Directory)
+	}
+
+	// The archive is written through a buffer so it's sent in
+	// StreamingChunkSize chunks, hashing what's sent along the way.
+	bw := bufio.NewWriterSize(&archiveSender{stream: stream}, util.StreamingChunkSize)
+	hasher := sha256.New()
+	var out io.Writer = io.MultiWriter(hasher, bw)
+	var gz *gzip.Writer
+	if req.Gzip {
+		gz = gzip.NewWriter(out)
+		out = gz
+	}
+	tw := tar.NewWriter(out)
+
+	err = filepath.WalkDir(req.Directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can come and go while we walk (i.e. log rotation) which isn't an error.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Entries which aren't allowed (see checkPath) are left out.
+		if checkPath(path) != nil {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return addToArchive(tw, req.Directory, path, d)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return status.Errorf(codes.Internal, "can't archive %s: %v", req.Directory, err)
+	}
+	if err := tw.Close(); err != nil {
+		return status.Errorf(codes.Internal, "can't finish archive: %v", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return status.Errorf(codes.Internal, "can't finish archive: %v", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return status.Errorf(codes.Internal, "can't send archive: %v", err)
+	}
+	if err := stream.Send(&pb.ArchiveReply{Sha256: hex.EncodeToString(hasher.Sum(nil))}); err != nil {
+		return status.Errorf(codes.Internal, "can't send archive: %v", err)
+	}
+	return nil
+}
+
+// addToArchive writes the entry at path (found under root) to tw.
+func addToArchive(tw *tar.Writer, root string, path string, d fs.DirEntry) error {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return err
+	}
+	if rel == "." {
+		return nil
+	}
+	fi, err := d.Info()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// There's no tar representation for sockets.
+	if fi.Mode()&fs.ModeSocket != 0 {
+		return nil
+	}
+	var link string
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+	hdr, err := tar.FileInfoHeader(fi, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = filepath.ToSlash(rel)
+	if fi.IsDir() {
+		hdr.Name += "/"
+	}
+	if !fi.Mode().IsRegular() {
+		return tw.WriteHeader(hdr)
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	// The header has the size from when we looked so only copy that much
+	// even if the file is growing. A file which shrinks can't be archived
+	// consistently so that's an error.
+	_, err = io.CopyN(tw, f, hdr.Size)
+	return err
+}
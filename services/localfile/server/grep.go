@@ -0,0 +1,231 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+// maxGrepLineLength is how much of a line Grep returns.
+var maxGrepLineLength = 64 * 1024
+
+// grepper searches files for a Grep request, sending lines in batches.
+type grepper struct {
+	req    *pb.GrepRequest
+	re     *regexp.Regexp
+	stream pb.LocalFile_GrepServer
+
+	batch      []*pb.GrepLine
+	batchBytes int
+	matches    uint32
+	scanned    int64
+}
+
+// send sends the current batch.
+func (g *grepper) send(truncated bool) error {
+	if len(g.batch) == 0 && !truncated {
+		return nil
+	}
+	if err := g.stream.Send(&pb.GrepReply{Lines: g.batch, Truncated: truncated}); err != nil {
+		return status.Errorf(codes.Internal, "grep: send error %v", err)
+	}
+	g.batch, g.batchBytes = nil, 0
+	return nil
+}
+
+func (g *grepper) emit(l *pb.GrepLine) error {
+	g.batch = append(g.batch, l)
+	g.batchBytes += len(l.Line)
+	if g.batchBytes >= util.StreamingChunkSize {
+		return g.send(false)
+	}
+	return nil
+}
+
+// readLine returns the next line from r without its newline, truncated to
+// maxGrepLineLength, and how many bytes were read.
+func readLine(r *bufio.Reader) (string, int64, error) {
+	var line []byte
+	var n int64
+	for {
+		chunk, err := r.ReadSlice('\n')
+		n += int64(len(chunk))
+		if room := maxGrepLineLength - len(line); room > 0 {
+			if len(chunk) > room {
+				chunk = chunk[:room]
+			}
+			line = append(line, chunk...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return string(bytes.TrimSuffix(line, []byte("\n"))), n, err
+	}
+}
+
+// search greps a single file, returning true if a limit was reached.
+func (g *grepper) search(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "can't open file %s: %v", path, err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var before []*pb.GrepLine
+	var afterLeft uint32
+	var lineNumber int64
+	for {
+		if err := g.stream.Context().Err(); err != nil {
+			return false, err
+		}
+		if g.req.MaxBytes > 0 && g.scanned >= g.req.MaxBytes {
+			return true, nil
+		}
+		text, n, err := readLine(r)
+		if err != nil && err != io.EOF {
+			return false, status.Errorf(codes.Internal, "can't read file %s: %v", path, err)
+		}
+		if n == 0 {
+			return false, nil
+		}
+		g.scanned += n
+		lineNumber++
+		line := &pb.GrepLine{Path: path, LineNumber: lineNumber, Line: text}
+
+		switch {
+		case g.re.MatchString(text) != g.req.Invert:
+			for _, l := range before {
+				if err := g.emit(l); err != nil {
+					return false, err
+				}
+			}
+			before = before[:0]
+			if err := g.emit(line); err != nil {
+				return false, err
+			}
+			g.matches++
+			if g.req.MaxMatches > 0 && g.matches >= g.req.MaxMatches {
+				return true, nil
+			}
+			afterLeft = g.req.AfterContext
+		case afterLeft > 0:
+			line.Context = true
+			if err := g.emit(line); err != nil {
+				return false, err
+			}
+			afterLeft--
+		case g.req.BeforeContext > 0:
+			line.Context = true
+			if uint32(len(before)) == g.req.BeforeContext {
+				before = before[1:]
+			}
+			before = append(before, line)
+		}
+		if err == io.EOF {
+			return false, nil
+		}
+	}
+}
+
+// grepFiles returns the files to search for path, expanding it if it's a
+// pattern. Only matches allowed by checkPath are returned.
+func grepFiles(path string) ([]string, error) {
+	if err := util.ValidPath(path); err != nil {
+		return nil, err
+	}
+	if !strings.ContainsAny(path, `*?[\`) {
+		if err := checkPath(path); err != nil {
+			return nil, err
+		}
+		if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+			return nil, status.Errorf(codes.InvalidArgument, "%s is a directory", path)
+		}
+		return []string{path}, nil
+	}
+	matches, err := filepath.Glob(path)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pattern %s: %v", path, err)
+	}
+	var out []string
+	for _, m := range matches {
+		// Matches which aren't allowed are skipped like directories.
+		if checkPath(m) != nil {
+			continue
+		}
+		if fi, err := os.Stat(m); err == nil && !fi.IsDir() {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// Grep implements pb.LocalFileServer.Grep
+func (s *server) Grep(req *pb.GrepRequest, stream pb.LocalFile_GrepServer) error {
+	logger := logr.FromContextOrDiscard(stream.Context())
+	if len(req.Paths) == 0 {
+		return status.Error(codes.InvalidArgument, "must specify at least one path")
+	}
+	if req.Pattern == "" {
+		return status.Error(codes.InvalidArgument, "pattern must be set")
+	}
+	if req.MaxBytes < 0 {
+		return status.Error(codes.InvalidArgument, "max_bytes can't be negative")
+	}
+	pattern := req.Pattern
+	if req.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid pattern: %v", err)
+	}
+	var files []string
+	for _, p := range req.Paths {
+		f, err := grepFiles(p)
+		if err != nil {
+			return err
+		}
+		files = append(files, f...)
+	}
+	logger.Info("grep request", "files", files, "pattern", req.Pattern)
+
+	g := &grepper{req: req, re: re, stream: stream}
+	for _, f := range files {
+		truncated, err := g.search(f)
+		if err != nil {
+			return err
+		}
+		if truncated {
+			return g.send(true)
+		}
+	}
+	return g.send(false)
+}
@@ -0,0 +1,225 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestGrep(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewLocalFileClient(conn)
+
+	savedMaxGrepLineLength := maxGrepLineLength
+	maxGrepLineLength = 16
+	t.Cleanup(func() { maxGrepLineLength = savedMaxGrepLineLength })
+
+	temp := t.TempDir()
+	log := filepath.Join(temp, "app.log")
+	rotated := filepath.Join(temp, "app.log.1")
+	testutil.FatalOnErr("write", os.WriteFile(log, []byte("one\nERROR two\nthree\nfour\nfive\nerror six\nseven\na very long error line indeed\nno newline"), 0644), t)
+	testutil.FatalOnErr("write", os.WriteFile(rotated, []byte("old\nold ERROR\n"), 0644), t)
+	testutil.FatalOnErr("mkdir", os.Mkdir(filepath.Join(temp, "app.log.d"), 0755), t)
+
+	match := func(path string, n int64, line string) *pb.GrepLine {
+		return &pb.GrepLine{Path: path, LineNumber: n, Line: line}
+	}
+	context := func(path string, n int64, line string) *pb.GrepLine {
+		return &pb.GrepLine{Path: path, LineNumber: n, Line: line, Context: true}
+	}
+
+	for _, tc := range []struct {
+		name          string
+		req           *pb.GrepRequest
+		wantErr       codes.Code
+		want          []*pb.GrepLine
+		wantTruncated bool
+	}{
+		{
+			name: "match",
+			req:  &pb.GrepRequest{Paths: []string{log}, Pattern: "ERROR"},
+			want: []*pb.GrepLine{match(log, 2, "ERROR two")},
+		},
+		{
+			name: "ignore case",
+			req:  &pb.GrepRequest{Paths: []string{log}, Pattern: "error", IgnoreCase: true},
+			want: []*pb.GrepLine{
+				match(log, 2, "ERROR two"),
+				match(log, 6, "error six"),
+				// Lines are truncated before matching.
+			},
+		},
+		{
+			name: "invert",
+			req:  &pb.GrepRequest{Paths: []string{log}, Pattern: "e", IgnoreCase: true, Invert: true},
+			want: []*pb.GrepLine{
+				match(log, 4, "four"),
+			},
+		},
+		{
+			name: "long line",
+			req:  &pb.GrepRequest{Paths: []string{log}, Pattern: "^a very"},
+			want: []*pb.GrepLine{match(log, 8, "a very long erro")},
+		},
+		{
+			name: "last line without newline",
+			req:  &pb.GrepRequest{Paths: []string{log}, Pattern: "newline$"},
+			want: []*pb.GrepLine{match(log, 9, "no newline")},
+		},
+		{
+			name: "context",
+			req:  &pb.GrepRequest{Paths: []string{log}, Pattern: "two|six", BeforeContext: 2, AfterContext: 1},
+			want: []*pb.GrepLine{
+				context(log, 1, "one"),
+				match(log, 2, "ERROR two"),
+				context(log, 3, "three"),
+				context(log, 4, "four"),
+				context(log, 5, "five"),
+				match(log, 6, "error six"),
+				context(log, 7, "seven"),
+			},
+		},
+		{
+			name: "pattern",
+			req:  &pb.GrepRequest{Paths: []string{filepath.Join(temp, "app.log*")}, Pattern: "ERROR"},
+			want: []*pb.GrepLine{
+				match(log, 2, "ERROR two"),
+				match(rotated, 2, "old ERROR"),
+			},
+		},
+		{
+			name:          "max matches",
+			req:           &pb.GrepRequest{Paths: []string{log, rotated}, Pattern: "(?i)error", MaxMatches: 2},
+			want:          []*pb.GrepLine{match(log, 2, "ERROR two"), match(log, 6, "error six")},
+			wantTruncated: true,
+		},
+		{
+			name:          "max bytes",
+			req:           &pb.GrepRequest{Paths: []string{log, rotated}, Pattern: "o", MaxBytes: 10},
+			want:          []*pb.GrepLine{match(log, 1, "one"), match(log, 2, "ERROR two")},
+			wantTruncated: true,
+		},
+		{
+			name: "no matches",
+			req:  &pb.GrepRequest{Paths: []string{log}, Pattern: "nothing"},
+		},
+		{
+			name:    "no paths",
+			req:     &pb.GrepRequest{Pattern: "x"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "no pattern",
+			req:     &pb.GrepRequest{Paths: []string{log}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad pattern",
+			req:     &pb.GrepRequest{Paths: []string{log}, Pattern: "("},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "relative path",
+			req:     &pb.GrepRequest{Paths: []string{"app.log"}, Pattern: "x"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "directory",
+			req:     &pb.GrepRequest{Paths: []string{temp}, Pattern: "x"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "missing file",
+			req:     &pb.GrepRequest{Paths: []string{filepath.Join(temp, "missing")}, Pattern: "x"},
+			wantErr: codes.Internal,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := client.Grep(ctx, tc.req)
+			testutil.FatalOnErr("Grep", err, t)
+			var got []*pb.GrepLine
+			var truncated bool
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if got := status.Code(err); got != tc.wantErr {
+					t.Fatalf("unexpected error. got %v want %v: %v", got, tc.wantErr, err)
+				}
+				if err != nil {
+					return
+				}
+				if truncated {
+					t.Fatal("got a reply after one marked truncated")
+				}
+				got = append(got, resp.Lines...)
+				truncated = resp.Truncated
+			}
+			if tc.wantErr != codes.OK {
+				t.Fatalf("didn't get error %v", tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Fatalf("unexpected lines (-want, +got):\n%s", diff)
+			}
+			if truncated != tc.wantTruncated {
+				t.Fatalf("truncated: got %v want %v", truncated, tc.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestReadLine(t *testing.T) {
+	savedMaxGrepLineLength := maxGrepLineLength
+	maxGrepLineLength = 4
+	t.Cleanup(func() { maxGrepLineLength = savedMaxGrepLineLength })
+
+	// Lines longer than the reader's buffer are still read whole.
+	long := strings.Repeat("x", 100)
+	r := bufio.NewReaderSize(strings.NewReader(long+"\nab\n"), 16)
+	line, n, err := readLine(r)
+	if line != "xxxx" || n != 101 || err != nil {
+		t.Fatalf("readLine: got %q, %d, %v", line, n, err)
+	}
+	line, n, err = readLine(r)
+	if line != "ab" || n != 3 || err != nil {
+		t.Fatalf("readLine: got %q, %d, %v", line, n, err)
+	}
+	if _, n, err = readLine(r); n != 0 || err != io.EOF {
+		t.Fatalf("readLine at end: got %d, %v", n, err)
+	}
+}
@@ -0,0 +1,163 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"flag"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// These limits are enforced by the service itself regardless of policy, as a
+// backstop should the policy be too permissive.
+var (
+	allowPaths  = flag.String("localfile-allow-paths", "", "Comma separated list of path prefixes the LocalFile service may access. If empty any path may be accessed (subject to policy and --localfile-deny-paths).")
+	denyPaths   = flag.String("localfile-deny-paths", "", "Comma separated list of path prefixes the LocalFile service may never access, even if under --localfile-allow-paths, i.e. /etc/shadow.")
+	maxReadSize = flag.Int64("localfile-max-read-size", 0, "If non-zero the most bytes a single LocalFile.Read (not tail) may return. Larger reads must be split with offset and length.")
+	maxTailRate = flag.Int64("localfile-max-tail-rate", 0, "If non-zero the most bytes per second a LocalFile.Read tail will send.")
+)
+
+// splitPaths returns the cleaned paths in a comma separated list.
+func splitPaths(list string) []string {
+	var out []string
+	for _, p := range strings.Split(list, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, filepath.Clean(p))
+		}
+	}
+	return out
+}
+
+// underPrefix reports whether path is prefix or is inside it.
+func underPrefix(path string, prefix string) bool {
+	return path == prefix || prefix == "/" || strings.HasPrefix(path, prefix+"/")
+}
+
+// pathAllowed reports whether path passes the allow and deny lists.
+func pathAllowed(path string, allow []string, deny []string) bool {
+	for _, d := range deny {
+		if underPrefix(path, d) {
+			return false
+		}
+	}
+	if len(allow) == 0 {
+		return true
+	}
+	for _, a := range allow {
+		if underPrefix(path, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath returns path with any symlinks evaluated. If path doesn't
+// exist (i.e. a file about to be written) its deepest existing parent is
+// evaluated instead.
+func resolvePath(path string) string {
+	var rest []string
+	for p := path; ; p = filepath.Dir(p) {
+		if r, err := filepath.EvalSymlinks(p); err == nil {
+			return filepath.Join(append([]string{r}, rest...)...)
+		}
+		if p == filepath.Dir(p) {
+			return path
+		}
+		rest = append([]string{filepath.Base(p)}, rest...)
+	}
+}
+
+// checkPath returns PermissionDenied unless path, and what it resolves to
+// through symlinks, are allowed by --localfile-allow-paths and
+// --localfile-deny-paths. Callers must have validated path with
+// util.ValidPath. Operations on directories (i.e. Grep and Archive) also
+// check each file they read, while listings only check the path asked for.
+func checkPath(path string) error {
+	allow, deny := splitPaths(*allowPaths), splitPaths(*denyPaths)
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil
+	}
+	if !pathAllowed(path, allow, deny) {
+		return status.Errorf(codes.PermissionDenied, "%s isn't an allowed path for this server", path)
+	}
+	if r := resolvePath(path); r != path && !pathAllowed(r, allow, deny) {
+		return status.Errorf(codes.PermissionDenied, "%s resolves to %s which isn't an allowed path for this server", path, r)
+	}
+	return nil
+}
+
+// checkReadSize returns FailedPrecondition if reading `n` bytes exceeds
+// --localfile-max-read-size.
+func checkReadSize(filename string, n int64) error {
+	if *maxReadSize > 0 && n > *maxReadSize {
+		return status.Errorf(codes.FailedPrecondition, "reading %d bytes of %s exceeds the server limit of %d bytes, use offset and length to read less", n, filename, *maxReadSize)
+	}
+	return nil
+}
+
+// rateLimiter delays a stream so no more than rate bytes per second are
+// sent on average. Credit for idle time is limited to a second so a tail
+// which has been waiting for data can't then burst.
+type rateLimiter struct {
+	rate  int64
+	start time.Time
+	sent  int64
+}
+
+// newRateLimiter returns a rateLimiter for --localfile-max-tail-rate, or nil
+// if there's no limit.
+func newRateLimiter() *rateLimiter {
+	if *maxTailRate <= 0 {
+		return nil
+	}
+	return &rateLimiter{rate: *maxTailRate, start: time.Now()}
+}
+
+// due returns when everything sent so far may have been sent.
+func (r *rateLimiter) due() time.Time {
+	return r.start.Add(time.Duration(float64(r.sent) / float64(r.rate) * float64(time.Second)))
+}
+
+// wait records that n bytes were sent and sleeps until sending them is
+// within the rate, or ctx is done.
+func (r *rateLimiter) wait(ctx context.Context, n int) error {
+	if r == nil {
+		return nil
+	}
+	now := time.Now()
+	if r.due().Before(now.Add(-time.Second)) {
+		r.start, r.sent = now, 0
+	}
+	r.sent += int64(n)
+	d := time.Until(r.due())
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,304 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// setLimits sets the limit flags for the duration of the test.
+func setLimits(t *testing.T, allow string, deny string, readSize int64, tailRate int64) {
+	t.Helper()
+	savedAllow, savedDeny, savedRead, savedTail := *allowPaths, *denyPaths, *maxReadSize, *maxTailRate
+	t.Cleanup(func() {
+		*allowPaths, *denyPaths, *maxReadSize, *maxTailRate = savedAllow, savedDeny, savedRead, savedTail
+	})
+	*allowPaths, *denyPaths, *maxReadSize, *maxTailRate = allow, deny, readSize, tailRate
+}
+
+func TestCheckPath(t *testing.T) {
+	temp := t.TempDir()
+	allowed := filepath.Join(temp, "allowed")
+	secret := filepath.Join(allowed, "secret")
+	other := filepath.Join(temp, "other")
+	for _, d := range []string{allowed, other} {
+		testutil.FatalOnErr("mkdir", os.Mkdir(d, 0755), t)
+	}
+	testutil.FatalOnErr("write", os.WriteFile(secret, nil, 0644), t)
+	testutil.FatalOnErr("write", os.WriteFile(filepath.Join(other, "file"), nil, 0644), t)
+	testutil.FatalOnErr("symlink", os.Symlink(other, filepath.Join(allowed, "escape")), t)
+
+	for _, tc := range []struct {
+		name  string
+		allow string
+		deny  string
+		path  string
+		want  codes.Code
+	}{
+		{
+			name: "no limits",
+			path: filepath.Join(other, "file"),
+		},
+		{
+			name:  "allowed",
+			allow: allowed,
+			path:  filepath.Join(allowed, "file"),
+		},
+		{
+			name:  "allowed prefix itself",
+			allow: allowed + "/",
+			path:  allowed,
+		},
+		{
+			name:  "not allowed",
+			allow: allowed,
+			path:  filepath.Join(other, "file"),
+			want:  codes.PermissionDenied,
+		},
+		{
+			name:  "prefix of a name isn't enough",
+			allow: allowed,
+			path:  allowed + "2",
+			want:  codes.PermissionDenied,
+		},
+		{
+			name:  "one of several",
+			allow: other + "," + allowed,
+			path:  filepath.Join(allowed, "file"),
+		},
+		{
+			name:  "denied",
+			allow: allowed,
+			deny:  secret,
+			path:  secret,
+			want:  codes.PermissionDenied,
+		},
+		{
+			name: "denied without allow list",
+			deny: allowed,
+			path: filepath.Join(allowed, "new", "file"),
+			want: codes.PermissionDenied,
+		},
+		{
+			name:  "symlink out of allowed",
+			allow: allowed,
+			path:  filepath.Join(allowed, "escape", "file"),
+			want:  codes.PermissionDenied,
+		},
+		{
+			name:  "new file under symlink out of allowed",
+			allow: allowed,
+			path:  filepath.Join(allowed, "escape", "new"),
+			want:  codes.PermissionDenied,
+		},
+		{
+			name: "symlink into denied",
+			deny: other,
+			path: filepath.Join(allowed, "escape", "file"),
+			want: codes.PermissionDenied,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			setLimits(t, tc.allow, tc.deny, 0, 0)
+			if err := checkPath(tc.path); status.Code(err) != tc.want {
+				t.Errorf("checkPath(%s) = %v, want %v", tc.path, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestLimitsEnforced(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewLocalFileClient(conn)
+
+	temp := t.TempDir()
+	secret := filepath.Join(temp, "secret")
+	log := filepath.Join(temp, "app.log")
+	testutil.FatalOnErr("write", os.WriteFile(secret, []byte("password ERROR\n"), 0644), t)
+	testutil.FatalOnErr("write", os.WriteFile(log, []byte("ERROR one\n"), 0644), t)
+	setLimits(t, temp, secret, 0, 0)
+
+	read := func(filename string) error {
+		stream, err := client.Read(ctx, &pb.ReadActionRequest{Request: &pb.ReadActionRequest_File{File: &pb.ReadRequest{Filename: filename}}})
+		testutil.FatalOnErr("Read", err, t)
+		for {
+			if _, err := stream.Recv(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	testutil.FatalOnErr("read allowed", read(log), t)
+	if err := read(secret); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("read of denied file: got %v, want PermissionDenied", err)
+	}
+	if err := read("/etc/hosts"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("read outside allowed paths: got %v, want PermissionDenied", err)
+	}
+	if _, err := client.Rm(ctx, &pb.RmRequest{Filename: secret}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("rm of denied file: got %v, want PermissionDenied", err)
+	}
+
+	// Globs skip denied files.
+	grep, err := client.Grep(ctx, &pb.GrepRequest{Paths: []string{filepath.Join(temp, "*")}, Pattern: "ERROR"})
+	testutil.FatalOnErr("Grep", err, t)
+	for {
+		resp, err := grep.Recv()
+		if err == io.EOF {
+			break
+		}
+		testutil.FatalOnErr("Grep Recv", err, t)
+		for _, l := range resp.Lines {
+			if l.Path != log {
+				t.Errorf("grep returned line from %s", l.Path)
+			}
+		}
+	}
+
+	// Archives leave out denied files.
+	archive, err := client.Archive(ctx, &pb.ArchiveRequest{Directory: temp})
+	testutil.FatalOnErr("Archive", err, t)
+	var tarball bytes.Buffer
+	for {
+		resp, err := archive.Recv()
+		if err == io.EOF {
+			break
+		}
+		testutil.FatalOnErr("Archive Recv", err, t)
+		tarball.Write(resp.Contents)
+	}
+	if bytes.Contains(tarball.Bytes(), []byte("password")) {
+		t.Error("archive contains denied file")
+	}
+}
+
+func TestMaxReadSize(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewLocalFileClient(conn)
+
+	name := filepath.Join(t.TempDir(), "file")
+	testutil.FatalOnErr("write", os.WriteFile(name, []byte("0123456789"), 0644), t)
+	setLimits(t, "", "", 5, 0)
+
+	for _, tc := range []struct {
+		name    string
+		offset  int64
+		length  int64
+		want    string
+		wantErr codes.Code
+	}{
+		{
+			name:    "whole file",
+			wantErr: codes.FailedPrecondition,
+		},
+		{
+			name:   "within limit",
+			length: 5,
+			want:   "01234",
+		},
+		{
+			name:   "rest of file within limit",
+			offset: 6,
+			want:   "6789",
+		},
+		{
+			name:    "length over limit",
+			offset:  1,
+			length:  6,
+			wantErr: codes.FailedPrecondition,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := client.Read(ctx, &pb.ReadActionRequest{
+				Request: &pb.ReadActionRequest_File{File: &pb.ReadRequest{Filename: name, Offset: tc.offset, Length: tc.length}},
+			})
+			testutil.FatalOnErr("Read", err, t)
+			var got []byte
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if status.Code(err) != tc.wantErr {
+					t.Fatalf("unexpected error: got %v want %v", err, tc.wantErr)
+				}
+				if err != nil {
+					return
+				}
+				got = append(got, resp.Contents...)
+			}
+			if tc.wantErr != codes.OK {
+				t.Fatalf("read succeeded, want %v", tc.wantErr)
+			}
+			if string(got) != tc.want {
+				t.Errorf("got %q want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	setLimits(t, "", "", 0, 0)
+	if l := newRateLimiter(); l != nil {
+		t.Fatalf("got limiter %+v with no rate set", l)
+	}
+	// A nil limiter never waits.
+	var none *rateLimiter
+	testutil.FatalOnErr("nil wait", none.wait(ctx, 1<<30), t)
+
+	setLimits(t, "", "", 0, 1000)
+	l := newRateLimiter()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		testutil.FatalOnErr("wait", l.wait(ctx, 100), t)
+	}
+	// 300 bytes at 1000 bytes/s
+	if got := time.Since(start); got < 250*time.Millisecond {
+		t.Errorf("sending 300 bytes took %v, want at least 300ms", got)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.wait(cctx, 10000); status.Code(err) != codes.Canceled {
+		t.Errorf("wait with cancelled context: got %v want Canceled", err)
+	}
+}
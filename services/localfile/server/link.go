@@ -0,0 +1,153 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+// checkLinkTarget returns FailedPrecondition unless filename is a symlink
+// to want.
+func checkLinkTarget(filename string, want string) error {
+	got, err := os.Readlink(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return status.Errorf(codes.FailedPrecondition, "%s doesn't exist but expected a link to %s", filename, want)
+	}
+	if err != nil {
+		return status.Errorf(codes.FailedPrecondition, "%s isn't a link to %s: %v", filename, want, err)
+	}
+	if got != want {
+		return status.Errorf(codes.FailedPrecondition, "%s links to %s, expected %s", filename, got, want)
+	}
+	return nil
+}
+
+// tempSymlink creates a symlink to target with a unique name in dir and
+// returns that name.
+func tempSymlink(target string, dir string, base string) (string, error) {
+	var err error
+	for i := 0; i < 10000; i++ {
+		name := filepath.Join(dir, base+strconv.FormatUint(uint64(rand.Uint32()), 10))
+		err = os.Symlink(target, name)
+		if err == nil {
+			return name, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", err
+		}
+	}
+	return "", err
+}
+
+func (s *server) Symlink(ctx context.Context, req *pb.SymlinkRequest) (*emptypb.Empty, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	logger.Info("symlink request", "target", req.Target, "linkname", req.Linkname, "replace", req.Replace)
+	if err := util.ValidPath(req.Linkname); err != nil {
+		return nil, err
+	}
+	if err := checkPath(req.Linkname); err != nil {
+		return nil, err
+	}
+	if req.Target == "" {
+		return nil, status.Error(codes.InvalidArgument, "target must be filled in")
+	}
+	if req.ExpectedTarget != "" && !req.Replace {
+		return nil, status.Error(codes.InvalidArgument, "expected_target requires replace")
+	}
+
+	if !req.Replace {
+		if err := os.Symlink(req.Target, req.Linkname); err != nil {
+			if errors.Is(err, fs.ErrExist) {
+				return nil, status.Errorf(codes.AlreadyExists, "%s exists and replace set to false", req.Linkname)
+			}
+			return nil, status.Errorf(codes.Internal, "symlink error: %v", err)
+		}
+		return &emptypb.Empty{}, nil
+	}
+
+	// Create the link under a temporary name and rename it into place so
+	// there's never a moment where linkname doesn't exist.
+	tmp, err := tempSymlink(req.Target, filepath.Dir(req.Linkname), filepath.Base(req.Linkname))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't create tmp link: %v", err)
+	}
+	if req.ExpectedTarget != "" {
+		if err := checkLinkTarget(req.Linkname, req.ExpectedTarget); err != nil {
+			os.Remove(tmp)
+			return nil, err
+		}
+	}
+	if err := os.Rename(tmp, req.Linkname); err != nil {
+		os.Remove(tmp)
+		return nil, status.Errorf(codes.Internal, "error renaming %s -> %s - %v", tmp, req.Linkname, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) Readlink(ctx context.Context, req *pb.ReadlinkRequest) (*pb.ReadlinkReply, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	logger.Info("readlink request", "filename", req.Filename)
+	if err := util.ValidPath(req.Filename); err != nil {
+		return nil, err
+	}
+	if err := checkPath(req.Filename); err != nil {
+		return nil, err
+	}
+	target, err := os.Readlink(req.Filename)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "readlink error: %v", err)
+	}
+	return &pb.ReadlinkReply{Target: target}, nil
+}
+
+func (s *server) Link(ctx context.Context, req *pb.LinkRequest) (*emptypb.Empty, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	logger.Info("link request", "target", req.Target, "linkname", req.Linkname)
+	if err := util.ValidPath(req.Target); err != nil {
+		return nil, err
+	}
+	if err := checkPath(req.Target); err != nil {
+		return nil, err
+	}
+	if err := util.ValidPath(req.Linkname); err != nil {
+		return nil, err
+	}
+	if err := checkPath(req.Linkname); err != nil {
+		return nil, err
+	}
+	if err := os.Link(req.Target, req.Linkname); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s exists", req.Linkname)
+		}
+		return nil, status.Errorf(codes.Internal, "link error: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -23,6 +23,7 @@ import (
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"hash"
 	"hash/crc32"
@@ -31,6 +32,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gocloud.dev/blob"
@@ -49,6 +51,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var (
@@ -56,8 +59,9 @@ var (
 	AbsolutePathError = status.Error(codes.InvalidArgument, "filename path must be absolute and clean")
 
 	// For testing since otherwise tests have to run as root for these.
-	chown             = unix.Chown
-	changeImmutableOS = changeImmutable
+	chown              = unix.Chown
+	changeImmutableOS  = changeImmutable
+	changeAppendOnlyOS = changeAppendOnly
 
 	// ReadTimeout is how long tail should wait on a given poll call
 	// before checking context.Err() and possibly looping.
@@ -96,6 +100,9 @@ func (s *server) Read(req *pb.ReadActionRequest, stream pb.LocalFile_ReadServer)
 	if err := util.ValidPath(file); err != nil {
 		return err
 	}
+	if err := checkPath(file); err != nil {
+		return err
+	}
 	f, err := os.Open(file)
 	if err != nil {
 		return status.Errorf(codes.Internal, "can't open file %s: %v", file, err)
@@ -107,15 +114,21 @@ func (s *server) Read(req *pb.ReadActionRequest, stream pb.LocalFile_ReadServer)
 		}
 	}()
 
-	// Seek forward if requested
+	// Seek forward if requested, remembering where we are so replies can
+	// report the absolute offset reached.
+	var pos int64
 	if offset != 0 {
 		whence := 0
 		// If negative we're tailing from the end so
 		// negate the sign and set whence.
 		if offset < 0 {
 			whence = 2
+			// Asking for the last N bytes of a shorter file returns all of it.
+			if fi, err := f.Stat(); err == nil && -offset > fi.Size() {
+				offset, whence = 0, 0
+			}
 		}
-		if _, err := f.Seek(offset, whence); err != nil {
+		if pos, err = f.Seek(offset, whence); err != nil {
 			return status.Errorf(codes.Internal, "can't seek for file %s: %v", file, err)
 		}
 	}
@@ -124,8 +137,32 @@ func (s *server) Read(req *pb.ReadActionRequest, stream pb.LocalFile_ReadServer)
 	if max == 0 {
 		max = math.MaxInt64
 	}
+	if r != nil && *maxReadSize > 0 {
+		fi, err := f.Stat()
+		if err != nil {
+			return status.Errorf(codes.Internal, "can't stat file %s: %v", file, err)
+		}
+		n := fi.Size() - pos
+		if length != 0 && length < n {
+			n = length
+		}
+		if err := checkReadSize(file, n); err != nil {
+			return err
+		}
+		// Don't exceed the limit if the file grows while being read.
+		if max > *maxReadSize {
+			max = *maxReadSize
+		}
+	}
 
 	buf := make([]byte, util.StreamingChunkSize)
+	var limiter *rateLimiter
+	if t != nil {
+		if limiter = newRateLimiter(); limiter != nil && limiter.rate < int64(len(buf)) {
+			// Send smaller chunks more often rather than a chunk at a time.
+			buf = buf[:limiter.rate]
+		}
+	}
 
 	reader := io.LimitReader(f, max)
 
@@ -133,7 +170,8 @@ func (s *server) Read(req *pb.ReadActionRequest, stream pb.LocalFile_ReadServer)
 	if err != nil {
 		return err
 	}
-	defer closer()
+	// closer changes if we follow a rotated file.
+	defer func() { closer() }()
 
 	for {
 		n, err := reader.Read(buf)
@@ -143,6 +181,25 @@ func (s *server) Read(req *pb.ReadActionRequest, stream pb.LocalFile_ReadServer)
 			if r != nil {
 				break
 			}
+			nf, npos, err := followFile(f, file, pos)
+			if err != nil {
+				return err
+			}
+			if nf != f {
+				logger.Info("tail following replaced file", "filename", file)
+				closer()
+				f.Close()
+				f, reader = nf, io.LimitReader(nf, max)
+				if td, closer, err = dataPrep(f); err != nil {
+					closer = func() {}
+					return err
+				}
+			}
+			// Read the new file or truncated one from the start right away.
+			if nf != f || npos != pos {
+				pos = npos
+				continue
+			}
 			if err := dataReady(td, stream); err != nil {
 				return err
 			}
@@ -155,9 +212,13 @@ func (s *server) Read(req *pb.ReadActionRequest, stream pb.LocalFile_ReadServer)
 
 		// Only send over the number of bytes we actually read or
 		// else we'll send over garbage in the last packet potentially.
-		if err := stream.Send(&pb.ReadReply{Contents: buf[:n]}); err != nil {
+		pos += int64(n)
+		if err := stream.Send(&pb.ReadReply{Contents: buf[:n], Offset: pos}); err != nil {
 			return status.Errorf(codes.Internal, "can't send on stream for file %s: %v", file, err)
 		}
+		if err := limiter.wait(stream.Context(), n); err != nil {
+			return err
+		}
 
 		// If we got back less than a full chunk we're done for non-tail cases.
 		if n < util.StreamingChunkSize {
@@ -169,6 +230,33 @@ func (s *server) Read(req *pb.ReadActionRequest, stream pb.LocalFile_ReadServer)
 	return nil
 }
 
+// followFile is called when a tail of filename reaches EOF at pos in f. It
+// returns a newly opened file (and position 0) if filename has been replaced
+// (i.e. by log rotation) and f has nothing more to read, or f rewound to 0 if
+// it has been truncated. Otherwise f and pos are returned as is. A missing
+// filename isn't an error as a rotated file may not have been recreated yet.
+func followFile(f *os.File, filename string, pos int64) (*os.File, int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, 0, status.Errorf(codes.Internal, "can't stat file %s: %v", filename, err)
+	}
+	if fi.Size() < pos {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, status.Errorf(codes.Internal, "can't seek for file %s: %v", filename, err)
+		}
+		return f, 0, nil
+	}
+	cur, err := os.Stat(filename)
+	if err != nil || os.SameFile(fi, cur) || fi.Size() > pos {
+		return f, pos, nil
+	}
+	nf, err := os.Open(filename)
+	if err != nil {
+		return f, pos, nil
+	}
+	return nf, 0, nil
+}
+
 func (s *server) Stat(stream pb.LocalFile_StatServer) error {
 	logger := logr.FromContextOrDiscard(stream.Context())
 	for {
@@ -184,7 +272,10 @@ func (s *server) Stat(stream pb.LocalFile_StatServer) error {
 		if err := util.ValidPath(req.Filename); err != nil {
 			return AbsolutePathError
 		}
-		resp, err := osStat(req.Filename)
+		if err := checkPath(req.Filename); err != nil {
+			return err
+		}
+		resp, err := statEntry(req.Filename)
 		if err != nil {
 			return err
 		}
@@ -208,6 +299,9 @@ func (s *server) Sum(stream pb.LocalFile_SumServer) error {
 		if err := util.ValidPath(req.Filename); err != nil {
 			return AbsolutePathError
 		}
+		if err := checkPath(req.Filename); err != nil {
+			return err
+		}
 		out := &pb.SumReply{
 			SumType:  req.SumType,
 			Filename: req.Filename,
@@ -222,8 +316,12 @@ func (s *server) Sum(stream pb.LocalFile_SumServer) error {
 			hasher = md5.New()
 		case pb.SumType_SUM_TYPE_SHA512_256:
 			hasher = sha512.New512_256()
+		case pb.SumType_SUM_TYPE_SHA512:
+			hasher = sha512.New()
 		case pb.SumType_SUM_TYPE_CRC32IEEE:
 			hasher = crc32.NewIEEE()
+		case pb.SumType_SUM_TYPE_CRC32C:
+			hasher = crc32.New(crc32.MakeTable(crc32.Castagnoli))
 		default:
 			return status.Errorf(codes.InvalidArgument, "invalid sum type value %d", req.SumType)
 		}
@@ -233,11 +331,13 @@ func (s *server) Sum(stream pb.LocalFile_SumServer) error {
 				return err
 			}
 			defer f.Close()
-			if _, err := io.Copy(hasher, f); err != nil {
+			n, err := io.Copy(hasher, f)
+			if err != nil {
 				logger.Error(err, "io.Copy", "file", req.Filename)
 				return status.Errorf(codes.Internal, "copy/read error: %v", err)
 			}
 			out.Sum = hex.EncodeToString(hasher.Sum(nil))
+			out.Size = n
 			return nil
 		}(); err != nil {
 			return status.Errorf(codes.Internal, "can't create sum: %v", err)
@@ -248,13 +348,30 @@ func (s *server) Sum(stream pb.LocalFile_SumServer) error {
 	}
 }
 
-func setupOutput(a *pb.FileAttributes) (*os.File, *immutableState, error) {
+func setupOutput(d *pb.FileWrite) (*os.File, *immutableState, error) {
 	// Validate path. We'll go ahead and write the data to a tmpfile and
 	// do the overwrite check when we rename below.
+	a := d.Attrs
 	filename := a.Filename
 	if err := util.ValidPath(filename); err != nil {
 		return nil, nil, err
 	}
+	if err := checkPath(filename); err != nil {
+		return nil, nil, err
+	}
+	if d.ExpectedSha256 != "" {
+		if b, err := hex.DecodeString(d.ExpectedSha256); err != nil || len(b) != sha256.Size {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "expected_sha256 %q isn't a hex encoded SHA256 sum", d.ExpectedSha256)
+		}
+		if !d.Overwrite && !d.Append {
+			return nil, nil, status.Error(codes.InvalidArgument, "expected_sha256 requires overwrite or append")
+		}
+	}
+	if d.Manifest != nil {
+		if err := validateManifest(d.Manifest); err != nil {
+			return nil, nil, err
+		}
+	}
 
 	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename))
 	if err != nil {
@@ -265,26 +382,169 @@ func setupOutput(a *pb.FileAttributes) (*os.File, *immutableState, error) {
 	// to accidentally leave this in another otherwise default state.
 	// Except we don't trigger immutable now or we won't be able to write to it.
 	immutable, err := validateAndSetAttrs(f.Name(), a.Attributes, false)
-	return f, immutable, err
+	if err != nil {
+		return f, immutable, err
+	}
+	return f, immutable, nil
 }
 
-func finalizeFile(d *pb.FileWrite, f *os.File, filename string, immutable *immutableState) error {
+// checkExpectedSum returns FailedPrecondition unless the contents of
+// filename, read from r, have the hex encoded SHA256 sum `want`.
+func checkExpectedSum(r io.Reader, filename string, want string) error {
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return status.Errorf(codes.Internal, "can't read %s: %v", filename, err)
+	}
+	if got := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(got, want) {
+		return status.Errorf(codes.FailedPrecondition, "%s has SHA256 %s, expected %s", filename, got, want)
+	}
+	return nil
+}
+
+func finalizeFile(ctx context.Context, d *pb.FileWrite, f *os.File, filename string, immutable *immutableState) error {
+	// Make sure the data is on disk before it can replace anything.
+	if err := f.Sync(); err != nil {
+		return status.Errorf(codes.Internal, "error syncing %s - %v", f.Name(), err)
+	}
 	if err := f.Close(); err != nil {
 		return status.Errorf(codes.Internal, "error closing %s - %v", f.Name(), err)
 	}
+	if d.Append {
+		return appendFile(ctx, d, f.Name(), filename, immutable)
+	}
+	if d.Manifest != nil {
+		if err := checkManifest(f.Name(), d.Manifest); err != nil {
+			return err
+		}
+	}
+	if err := setTimes(f.Name(), d.Attrs.Attributes); err != nil {
+		return err
+	}
 
 	// Do one final check (though racy) to see if the file exists.
 	_, err := os.Stat(filename)
 	if err == nil && !d.Overwrite {
 		return status.Errorf(codes.Internal, "file %s exists and overwrite set to false", filename)
 	}
+	if d.ExpectedSha256 != "" {
+		// Held until the rename so no other write can change the file
+		// after it's checked.
+		dst, err := lockDestination(ctx, filename, os.O_RDONLY, holdsDestinationLock(d))
+		if err != nil {
+			return err
+		}
+		if dst == nil {
+			return status.Errorf(codes.FailedPrecondition, "%s doesn't exist but expected SHA256 %s", filename, d.ExpectedSha256)
+		}
+		defer dst.Close()
+		if err := checkExpectedSum(dst, filename, d.ExpectedSha256); err != nil {
+			return err
+		}
+	}
 
 	// Rename tmp file to real destination.
 	if err := os.Rename(f.Name(), filename); err != nil {
 		return status.Errorf(codes.Internal, "error renaming %s -> %s - %v", f.Name(), filename, err)
 	}
+	return finishFile(filename, immutable)
+}
+
+// appendFile appends the contents of tmp to filename, and removes tmp. An
+// existing file is appended to in place, so it keeps its inode and any hard
+// links or processes with it open see the new contents. It's locked
+// meanwhile so concurrent appends (and expected sum checks) don't interfere.
+// A missing file is created from tmp as for any other write.
+func appendFile(ctx context.Context, d *pb.FileWrite, tmp string, filename string, immutable *immutableState) error {
+	defer os.Remove(tmp)
+	for {
+		dst, err := lockDestination(ctx, filename, os.O_RDWR|os.O_APPEND, holdsDestinationLock(d))
+		if err != nil {
+			return err
+		}
+		if dst != nil {
+			defer dst.Close()
+			return appendTo(dst, d, tmp, filename, immutable)
+		}
+		if d.ExpectedSha256 != "" {
+			return status.Errorf(codes.FailedPrecondition, "%s doesn't exist but expected SHA256 %s", filename, d.ExpectedSha256)
+		}
+		if d.Manifest != nil {
+			if err := checkManifest(tmp, d.Manifest); err != nil {
+				return err
+			}
+		}
+		if err := setTimes(tmp, d.Attrs.Attributes); err != nil {
+			return err
+		}
+		// Link rather than rename so a file created meanwhile is appended
+		// to instead of replaced.
+		err = os.Link(tmp, filename)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "error linking %s -> %s - %v", tmp, filename, err)
+		}
+		return finishFile(filename, immutable)
+	}
+}
+
+// appendTo appends the contents of tmp to dst, the open and locked file
+// filename, after checking the combined contents against d.
+func appendTo(dst *os.File, d *pb.FileWrite, tmp string, filename string, immutable *immutableState) error {
+	fi, err := dst.Stat()
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't stat %s: %v", filename, err)
+	}
+	size := fi.Size()
+	if d.ExpectedSha256 != "" {
+		if err := checkExpectedSum(io.NewSectionReader(dst, 0, size), filename, d.ExpectedSha256); err != nil {
+			return err
+		}
+	}
+	src, err := os.Open(tmp)
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't open %s: %v", tmp, err)
+	}
+	defer src.Close()
+	if d.Manifest != nil {
+		if err := matchManifest(io.MultiReader(io.NewSectionReader(dst, 0, size), src), filename, d.Manifest); err != nil {
+			return err
+		}
+		if _, err := src.Seek(0, io.SeekStart); err != nil {
+			return status.Errorf(codes.Internal, "can't seek %s: %v", tmp, err)
+		}
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		// Don't leave part of the new contents behind.
+		dst.Truncate(size)
+		return status.Errorf(codes.Internal, "can't append to %s: %v", filename, err)
+	}
+	if err := dst.Sync(); err != nil {
+		return status.Errorf(codes.Internal, "error syncing %s - %v", filename, err)
+	}
+	if _, err := validateAndSetAttrs(filename, d.Attrs.Attributes, false); err != nil {
+		return err
+	}
+	if err := setTimes(filename, d.Attrs.Attributes); err != nil {
+		return err
+	}
+	return finishFile(filename, immutable)
+}
 
-	// Now set immutable if requested.
+// finishFile persists the new entry for filename and then sets append-only
+// and immutable if requested.
+func finishFile(filename string, immutable *immutableState) error {
+	// Not every platform supports syncing a directory so this is best effort.
+	if dir, err := os.Open(filepath.Dir(filename)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	if immutable.setAppendOnly && immutable.appendOnly {
+		if err := changeAppendOnlyOS(filename, immutable.appendOnly); err != nil {
+			return err
+		}
+	}
 	if immutable.setImmutable && immutable.immutable {
 		if err := changeImmutableOS(filename, immutable.immutable); err != nil {
 			return err
@@ -330,7 +590,12 @@ func (s *server) Write(stream pb.LocalFile_WriteServer) (retErr error) {
 	}
 	filename = a.Filename
 	logger.Info("write file", filename)
-	f, immutable, err = setupOutput(a)
+	lock, err := acquireLock(stream.Context(), d)
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+	f, immutable, err = setupOutput(d)
 	if err != nil {
 		return err
 	}
@@ -361,7 +626,7 @@ func (s *server) Write(stream pb.LocalFile_WriteServer) (retErr error) {
 	}
 
 	// Finalize to the final destination and possibly set immutable.
-	if err := finalizeFile(d, f, filename, immutable); err != nil {
+	if err := finalizeFile(stream.Context(), d, f, filename, immutable); err != nil {
 		return err
 	}
 	return nil
@@ -388,10 +653,12 @@ func (s *server) Copy(ctx context.Context, req *pb.CopyRequest) (_ *emptypb.Empt
 	}
 	filename := a.Filename
 	logger.Info("copy file", filename)
-	f, immutable, err := setupOutput(a)
+	lock, err := acquireLock(ctx, d)
 	if err != nil {
 		return nil, err
 	}
+	defer lock.release()
+	f, immutable, err := setupOutput(d)
 	cleanup := func() {
 		if retErr != nil {
 			if f != nil {
@@ -402,6 +669,9 @@ func (s *server) Copy(ctx context.Context, req *pb.CopyRequest) (_ *emptypb.Empt
 		}
 	}
 	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
 
 	// Copy file over
 	b, err := blob.OpenBucket(ctx, req.Bucket)
@@ -434,7 +704,7 @@ func (s *server) Copy(ctx context.Context, req *pb.CopyRequest) (_ *emptypb.Empt
 	}
 
 	// Finalize to the final destination and possibly set immutable.
-	if err := finalizeFile(d, f, filename, immutable); err != nil {
+	if err := finalizeFile(ctx, d, f, filename, immutable); err != nil {
 		return nil, err
 	}
 	return &emptypb.Empty{}, nil
@@ -448,10 +718,18 @@ func (s *server) List(req *pb.ListRequest, server pb.LocalFile_ListServer) error
 	if err := util.ValidPath(req.Entry); err != nil {
 		return err
 	}
+	if err := checkPath(req.Entry); err != nil {
+		return err
+	}
+	if req.Glob != "" {
+		if _, err := filepath.Match(req.Glob, ""); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid glob %q: %v", req.Glob, err)
+		}
+	}
 
 	// We always send back the entry first.
 	logger.Info("ls", "filename", req.Entry)
-	resp, err := osStat(req.Entry)
+	resp, err := statEntry(req.Entry)
 	if err != nil {
 		return err
 	}
@@ -461,15 +739,29 @@ func (s *server) List(req *pb.ListRequest, server pb.LocalFile_ListServer) error
 
 	// If it's directory we'll open it and go over its entries.
 	if fs.FileMode(resp.Mode).IsDir() {
-		entries, err := os.ReadDir(req.Entry)
-		if err != nil {
-			return status.Errorf(codes.Internal, "readdir: %v", err)
+		return listDir(req, req.Entry, 0, server)
+	}
+	return nil
+}
+
+// listDir sends the entries of dir matching req.Glob, descending into
+// subdirectories until req.Depth levels below req.Entry.
+func listDir(req *pb.ListRequest, dir string, level uint32, server pb.LocalFile_ListServer) error {
+	logger := logr.FromContextOrDiscard(server.Context())
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return status.Errorf(codes.Internal, "readdir: %v", err)
+	}
+	for _, e := range entries {
+		if err := server.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
 		}
-		// Only do one level so iterate these and we're done.
-		for _, e := range entries {
-			name := filepath.Join(req.Entry, e.Name())
+		name := filepath.Join(dir, e.Name())
+		// Already validated in List.
+		match, _ := filepath.Match(req.Glob, e.Name())
+		if req.Glob == "" || match {
 			logger.Info("ls", "filename", name)
-			resp, err := osStat(name)
+			resp, err := statEntry(name)
 			if err != nil {
 				return err
 			}
@@ -477,19 +769,46 @@ func (s *server) List(req *pb.ListRequest, server pb.LocalFile_ListServer) error
 				return status.Errorf(codes.Internal, "list: send error %v", err)
 			}
 		}
+		// DirEntry doesn't follow symlinks so this won't loop.
+		if e.IsDir() && level < req.Depth {
+			if err := listDir(req, name, level+1, server); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
-// immutableState tracks the parsed state of immutable from the slice of FileAttribute.
+// statEntry returns osStat for path along with the target if it's a symlink.
+func statEntry(path string) (*pb.StatReply, error) {
+	resp, err := osStat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "readlink: %v", err)
+		}
+		resp.SymlinkTarget = target
+	}
+	return resp, nil
+}
+
+// immutableState tracks the parsed state of immutable (and append-only) from the
+// slice of FileAttribute.
 type immutableState struct {
-	setImmutable bool // Whether immutable was set (so we should change state).
-	immutable    bool // The immutable value (only applies if setImmutable is true).
+	setImmutable  bool // Whether immutable was set (so we should change state).
+	immutable     bool // The immutable value (only applies if setImmutable is true).
+	setAppendOnly bool // Whether append-only was set (so we should change state).
+	appendOnly    bool // The append-only value (only applies if setAppendOnly is true).
 }
 
 func validateAndSetAttrs(filename string, attrs []*pb.FileAttribute, doImmutable bool) (*immutableState, error) {
 	uid, gid := int(-1), int(-1)
 	setMode, setImmutable, immutable := false, false, false
+	setAppendOnly, appendOnly := false, false
+	setAtime, setMtime := false, false
 	mode := uint32(0)
 
 	for _, attr := range attrs {
@@ -516,6 +835,28 @@ func validateAndSetAttrs(filename string, attrs []*pb.FileAttribute, doImmutable
 			}
 			immutable = a.Immutable
 			setImmutable = true
+		case *pb.FileAttribute_AppendOnly:
+			if setAppendOnly {
+				return nil, status.Error(codes.InvalidArgument, "cannot set append_only more than once")
+			}
+			appendOnly = a.AppendOnly
+			setAppendOnly = true
+		case *pb.FileAttribute_Atime:
+			if setAtime {
+				return nil, status.Error(codes.InvalidArgument, "cannot set atime more than once")
+			}
+			if err := a.Atime.CheckValid(); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid atime: %v", err)
+			}
+			setAtime = true
+		case *pb.FileAttribute_Mtime:
+			if setMtime {
+				return nil, status.Error(codes.InvalidArgument, "cannot set mtime more than once")
+			}
+			if err := a.Mtime.CheckValid(); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid mtime: %v", err)
+			}
+			setMtime = true
 		}
 	}
 
@@ -531,17 +872,64 @@ func validateAndSetAttrs(filename string, attrs []*pb.FileAttribute, doImmutable
 		}
 	}
 
-	if doImmutable && setImmutable {
-		if err := changeImmutableOS(filename, immutable); err != nil {
+	// Like immutable, times on a file still being written would be lost so
+	// finalizeFile sets them instead.
+	if doImmutable {
+		if err := setTimes(filename, attrs); err != nil {
 			return nil, err
 		}
+		if setAppendOnly {
+			if err := changeAppendOnlyOS(filename, appendOnly); err != nil {
+				return nil, err
+			}
+		}
+		if setImmutable {
+			if err := changeImmutableOS(filename, immutable); err != nil {
+				return nil, err
+			}
+		}
 	}
 	return &immutableState{
-		setImmutable: setImmutable,
-		immutable:    immutable,
+		setImmutable:  setImmutable,
+		immutable:     immutable,
+		setAppendOnly: setAppendOnly,
+		appendOnly:    appendOnly,
 	}, nil
 }
 
+// setTimes sets the access and modification times of filename if either is
+// in attrs, keeping the current value of the other. attrs must have been
+// checked by validateAndSetAttrs.
+func setTimes(filename string, attrs []*pb.FileAttribute) error {
+	var atime, mtime *timestamppb.Timestamp
+	for _, attr := range attrs {
+		switch a := attr.Value.(type) {
+		case *pb.FileAttribute_Atime:
+			atime = a.Atime
+		case *pb.FileAttribute_Mtime:
+			mtime = a.Mtime
+		}
+	}
+	if atime == nil && mtime == nil {
+		return nil
+	}
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't stat %s: %v", filename, err)
+	}
+	at, mt := accessTime(fi), fi.ModTime()
+	if atime != nil {
+		at = atime.AsTime()
+	}
+	if mtime != nil {
+		mt = mtime.AsTime()
+	}
+	if err := os.Chtimes(filename, at, mt); err != nil {
+		return status.Errorf(codes.Internal, "error from chtimes: %v", err)
+	}
+	return nil
+}
+
 func (s *server) SetFileAttributes(ctx context.Context, req *pb.SetFileAttributesRequest) (*emptypb.Empty, error) {
 	if req.Attrs == nil {
 		return nil, status.Error(codes.InvalidArgument, "attrs must be filled in")
@@ -553,6 +941,9 @@ func (s *server) SetFileAttributes(ctx context.Context, req *pb.SetFileAttribute
 	if err := util.ValidPath(p); err != nil {
 		return nil, err
 	}
+	if err := checkPath(p); err != nil {
+		return nil, err
+	}
 
 	// Don't care about immutable state as we set it if it came across.
 	if _, err := validateAndSetAttrs(p, req.Attrs.Attributes, true); err != nil {
@@ -567,6 +958,14 @@ func (s *server) Rm(ctx context.Context, req *pb.RmRequest) (*emptypb.Empty, err
 	if err := util.ValidPath(req.Filename); err != nil {
 		return nil, err
 	}
+	if err := checkPath(req.Filename); err != nil {
+		return nil, err
+	}
+	if req.ExpectedTarget != "" {
+		if err := checkLinkTarget(req.Filename, req.ExpectedTarget); err != nil {
+			return nil, err
+		}
+	}
 	err := unix.Unlink(req.Filename)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "unlink error: %v", err)
@@ -580,6 +979,9 @@ func (s *server) Rmdir(ctx context.Context, req *pb.RmdirRequest) (*emptypb.Empt
 	if err := util.ValidPath(req.Directory); err != nil {
 		return nil, err
 	}
+	if err := checkPath(req.Directory); err != nil {
+		return nil, err
+	}
 	err := unix.Rmdir(req.Directory)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "rmdir error: %v", err)
@@ -22,6 +22,7 @@ package server
 import (
 	"os"
 	"syscall"
+	"time"
 
 	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
 	"golang.org/x/sys/unix"
@@ -47,6 +48,9 @@ const (
 
 	// SF_IMMUTABLE is the system immutable flag.
 	SF_IMMUTABLE = uint32(0x00020000)
+
+	// SF_APPEND is the system append-only flag.
+	SF_APPEND = uint32(0x00040000)
 )
 
 var (
@@ -67,32 +71,53 @@ func darwinOsStat(path string) (*pb.StatReply, error) {
 	// Darwin supports stat so we can blindly convert.
 	stat_t := stat.Sys().(*syscall.Stat_t)
 	resp := &pb.StatReply{
-		Filename:  path,
-		Size:      stat.Size(),
-		Mode:      uint32(stat.Mode()),
-		Modtime:   timestamppb.New(stat.ModTime()),
-		Uid:       stat_t.Uid,
-		Gid:       stat_t.Gid,
-		Immutable: (stat_t.Flags & SF_IMMUTABLE) != 0,
+		Filename:   path,
+		Size:       stat.Size(),
+		Mode:       uint32(stat.Mode()),
+		Modtime:    timestamppb.New(stat.ModTime()),
+		Uid:        stat_t.Uid,
+		Gid:        stat_t.Gid,
+		Immutable:  (stat_t.Flags & SF_IMMUTABLE) != 0,
+		AppendOnly: (stat_t.Flags & SF_APPEND) != 0,
 	}
 	return resp, nil
 }
 
+// accessTime returns the last access time of the file described by fi.
+func accessTime(fi os.FileInfo) time.Time {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fi.ModTime()
+	}
+	return time.Unix(st.Atimespec.Unix())
+}
+
 // changeImmutable is the Darwin specific implementation for changing
 // the immutable (system only) bit.
 func changeImmutable(path string, immutable bool) error {
+	return changeFlag(path, SF_IMMUTABLE, immutable)
+}
+
+// changeAppendOnly is the Darwin specific implementation for changing
+// the append-only (system only) bit.
+func changeAppendOnly(path string, appendOnly bool) error {
+	return changeFlag(path, SF_APPEND, appendOnly)
+}
+
+// changeFlag sets or clears the given flag on path.
+func changeFlag(path string, flag uint32, set bool) error {
 	stat, err := os.Stat(path)
 	if err != nil {
 		return status.Errorf(codes.Internal, "stat: os.Stat error %v", err)
 	}
 	// Darwin supports stat so we can blindly convert.
 	stat_t := stat.Sys().(*syscall.Stat_t)
-	// Mask to what we can set, turn off immutable and then
+	// Mask to what we can set, turn off the flag and then
 	// possibly back on.
 	flags := stat_t.Flags & (UF_SETTABLE | SF_SETTABLE)
-	flags &= ^SF_IMMUTABLE
-	if immutable {
-		flags |= SF_IMMUTABLE
+	flags &= ^flag
+	if set {
+		flags |= flag
 	}
 	return unix.Chflags(path, int(flags))
 }
@@ -127,42 +152,39 @@ func dataPrep(f *os.File) (interface{}, func(), error) {
 }
 
 // dataReady is the OS specific version to indicate the given
-// file has more data. With Darwin we use kqueue to watch the file
-// Assuming the file was already at EOF.
+// file may have more data or been rotated. With Darwin we use kqueue to
+// watch the file Assuming the file was already at EOF. It also returns
+// after ReadTimeout without any events so the caller can check for rotation.
 func dataReady(kq interface{}, stream pb.LocalFile_ReadServer) error {
 	kqf := kq.(*kqueueFile)
 
 	changes := make([]unix.Kevent_t, 1)
 	events := make([]unix.Kevent_t, 1)
 
-	for {
-		if stream.Context().Err() != nil {
-			return stream.Context().Err()
-		}
-		unix.SetKevent(&changes[0], int(kqf.fileFD), unix.EVFILT_VNODE, unix.EV_ADD|unix.EV_CLEAR|unix.EV_ENABLE)
-		changes[0].Fflags = unix.NOTE_WRITE
-		ret, err := kevent(kqf.kqFD, changes, nil, nil)
-		if err != nil || ret < 0 {
-			return status.Errorf(codes.Internal, "can't register kqueue: ret %d err %v", ret, err)
-		}
+	if stream.Context().Err() != nil {
+		return stream.Context().Err()
+	}
+	unix.SetKevent(&changes[0], int(kqf.fileFD), unix.EVFILT_VNODE, unix.EV_ADD|unix.EV_CLEAR|unix.EV_ENABLE)
+	changes[0].Fflags = unix.NOTE_WRITE | unix.NOTE_DELETE | unix.NOTE_RENAME
+	ret, err := kevent(kqf.kqFD, changes, nil, nil)
+	if err != nil || ret < 0 {
+		return status.Errorf(codes.Internal, "can't register kqueue: ret %d err %v", ret, err)
+	}
 
-		// Wait 10s in between requests so we can check the stream context too.
-		ts := &unix.Timespec{
-			Sec: int64(ReadTimeout.Seconds()),
-		}
-		n, err := kevent(kqf.kqFD, nil, events, ts)
-		if err != nil {
-			return status.Errorf(codes.Internal, "can't get kqueue events: %v", err)
-		}
-		// Something happened
-		if n == 1 {
-			// Generally this indicates an error.
-			if events[0].Filter != unix.EVFILT_VNODE {
-				return status.Errorf(codes.Internal, "got incorrect kevent back: %+v", events[0])
-			}
-			break
+	// Wait 10s in between requests so we can check the stream context too.
+	ts := &unix.Timespec{
+		Sec: int64(ReadTimeout.Seconds()),
+	}
+	n, err := kevent(kqf.kqFD, nil, events, ts)
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't get kqueue events: %v", err)
+	}
+	// Something happened. Otherwise we timed out and the caller checks the file.
+	if n == 1 {
+		// Generally this indicates an error.
+		if events[0].Filter != unix.EVFILT_VNODE {
+			return status.Errorf(codes.Internal, "got incorrect kevent back: %+v", events[0])
 		}
-		// Nothing (timed out) so just loop
 	}
 	return nil
 }
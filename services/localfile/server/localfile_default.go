@@ -22,6 +22,7 @@ package server
 import (
 	"os"
 	"syscall"
+	"time"
 
 	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
 	"google.golang.org/grpc/codes"
@@ -53,12 +54,48 @@ func osStat(path string) (*pb.StatReply, error) {
 	return resp, nil
 }
 
+// accessTime returns the last access time of the file described by fi.
+// There's no portable way to get it so modification time is used instead.
+func accessTime(fi os.FileInfo) time.Time {
+	return fi.ModTime()
+}
+
 // changeImmutable is the default implementation for changing
 // immutable bits (which is unsupported).
 func changeImmutable(path string, immutable bool) error {
 	return status.Error(codes.Unimplemented, "immutable not supported")
 }
 
+// changeAppendOnly is the default implementation for changing
+// append-only bits (which is unsupported).
+func changeAppendOnly(path string, appendOnly bool) error {
+	return status.Error(codes.Unimplemented, "append-only not supported")
+}
+
+// listXattrs is the default implementation for listing extended
+// attributes (which is unsupported).
+func listXattrs(path string) ([]string, error) {
+	return nil, status.Error(codes.Unimplemented, "xattrs not supported")
+}
+
+// getXattr is the default implementation for getting an extended
+// attribute (which is unsupported).
+func getXattr(path string, name string) ([]byte, error) {
+	return nil, status.Error(codes.Unimplemented, "xattrs not supported")
+}
+
+// setXattr is the default implementation for setting an extended
+// attribute (which is unsupported).
+func setXattr(path string, name string, value []byte) error {
+	return status.Error(codes.Unimplemented, "xattrs not supported")
+}
+
+// removeXattr is the default implementation for removing an extended
+// attribute (which is unsupported).
+func removeXattr(path string, name string) error {
+	return status.Error(codes.Unimplemented, "xattrs not supported")
+}
+
 // dataPrep should be called before entering a loop watching a file.
 // It returns an opaque object to pass to dataReady() and a function
 // which should be run on exit (i.e. defer it).
@@ -71,14 +108,14 @@ func dataPrep(f *os.File) (interface{}, func(), error) {
 // stream and then return no matter what (assuming the file was already
 // at EOF).
 func dataReady(_ interface{}, stream pb.LocalFile_ReadServer) error {
-	// We sleep for READ_TIMEOUT_SEC between calls as there's no good
+	// We sleep for ReadTimeout between calls as there's no good
 	// way to poll on a file. Once it reaches EOF it's always readable
 	// (you just get EOF). We have to poll like this so we can check
 	// the context state and return if it's canclled.
 	if stream.Context().Err() != nil {
 		return stream.Context().Err()
 	}
-	time.Sleep(READ_TIMEOUT_SEC * time.Second)
+	time.Sleep(ReadTimeout)
 	// Time to try again.
 	return nil
 }
@@ -22,6 +22,7 @@ package server
 import (
 	"os"
 	"syscall"
+	"time"
 
 	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
 	"golang.org/x/sys/unix"
@@ -37,6 +38,10 @@ const (
 	// the ioctl route has to be taken.
 	FS_IMMUTABLE_FL = int(0x00000010)
 
+	// FS_APPEND_FL is the flag which masks append-only state. Like
+	// FS_IMMUTABLE_FL it isn't mapped by x/sys/unix.
+	FS_APPEND_FL = int(0x00000020)
+
 	// FS_FL_USER_MODIFIABLE is the flag mask of flags that are user modifiable.
 	// The ioctl can return more when querying but setting should mask with this first.
 	FS_FL_USER_MODIFIABLE = int(0x000380FF)
@@ -78,7 +83,9 @@ func linuxOsStat(path string) (*pb.StatReply, error) {
 
 	statx := &unix.Statx_t{}
 	err = unix.Statx(0, path, unix.AT_STATX_SYNC_AS_STAT, unix.STATX_ALL, statx)
-	resp.Immutable = (statx.Attributes_mask & unix.STATX_ATTR_IMMUTABLE) != 0 // Can just assign now. If there was an error it gets fixed below.
+	// Can just assign now. If there was an error it gets fixed below.
+	resp.Immutable = (statx.Attributes & unix.STATX_ATTR_IMMUTABLE) != 0
+	resp.AppendOnly = (statx.Attributes & unix.STATX_ATTR_APPEND) != 0
 	if err != nil {
 		if err.(syscall.Errno) != syscall.ENOSYS {
 			return nil, status.Errorf(codes.Internal, "stat: os.Stat error %v", err)
@@ -89,8 +96,10 @@ func linuxOsStat(path string) (*pb.StatReply, error) {
 		if err != nil {
 			// If we can't get attributes just mark it as immutable=false
 			resp.Immutable = false
+			resp.AppendOnly = false
 		} else {
 			resp.Immutable = (attrs & FS_IMMUTABLE_FL) != 0
+			resp.AppendOnly = (attrs & FS_APPEND_FL) != 0
 		}
 	}
 	return resp, nil
@@ -110,9 +119,29 @@ func getFlags(path string) (int, error) {
 	return attrs, nil
 }
 
+// accessTime returns the last access time of the file described by fi.
+func accessTime(fi os.FileInfo) time.Time {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fi.ModTime()
+	}
+	return time.Unix(st.Atim.Unix())
+}
+
 // changeImmutable is the Linux specific implementation for changing
 // the immutable bit.
 func changeImmutable(path string, immutable bool) error {
+	return changeFlag(path, FS_IMMUTABLE_FL, immutable)
+}
+
+// changeAppendOnly is the Linux specific implementation for changing
+// the append-only bit.
+func changeAppendOnly(path string, appendOnly bool) error {
+	return changeFlag(path, FS_APPEND_FL, appendOnly)
+}
+
+// changeFlag sets or clears the given FS_*_FL flag on path.
+func changeFlag(path string, flag int, set bool) error {
 	attrs, err := getFlags(path)
 	if err != nil {
 		return err
@@ -120,10 +149,10 @@ func changeImmutable(path string, immutable bool) error {
 	// Need to make off to only the ones we can set.
 	attrs &= FS_FL_USER_MODIFIABLE
 
-	// Clear immutable and then possibly set it.
-	attrs &= ^FS_IMMUTABLE_FL
-	if immutable {
-		attrs |= FS_IMMUTABLE_FL
+	// Clear the flag and then possibly set it.
+	attrs &= ^flag
+	if set {
+		attrs |= flag
 	}
 
 	f1, err := os.Open(path)
@@ -176,7 +205,9 @@ func dataPrep(f *os.File) (obj interface{}, closer func(), retErr error) {
 
 	// NOTE: This is *not* a file descriptor but an internal descriptor for inotify to
 	//       use when pushing data through iFD above. Do not close() on it.
-	in.watchFD, err = inotifyAddWatch(in.iFD, in.file, unix.IN_MODIFY)
+	// Besides writes watch for the file being moved or deleted so a tail
+	// notices rotation promptly.
+	in.watchFD, err = inotifyAddWatch(in.iFD, in.file, unix.IN_MODIFY|unix.IN_ATTRIB|unix.IN_MOVE_SELF|unix.IN_DELETE_SELF)
 	if err != nil {
 		return nil, closer, status.Errorf(codes.Internal, "can't setup inotify watch: %v", err)
 	}
@@ -198,14 +229,15 @@ func dataPrep(f *os.File) (obj interface{}, closer func(), retErr error) {
 }
 
 // dataReady is the OS specific version to indicate the given
-// file has more data. With Linux we use inotify to watch the file and
-// assuming the file was already at EOF.
+// file may have more data or been rotated. With Linux we use inotify to
+// watch the file and assuming the file was already at EOF. It also returns
+// after ReadTimeout without any events so the caller can check for rotation.
 func dataReady(fd interface{}, stream pb.LocalFile_ReadServer) error {
 	inotify := fd.(*inotify)
 
 	events := make([]unix.EpollEvent, 1)
 
-	// Loop until we either get new data or the context gets cancalled.
+	// Loop until we either get an event, time out or the context gets cancalled.
 	for {
 		if stream.Context().Err() != nil {
 			return stream.Context().Err()
@@ -224,11 +256,13 @@ func dataReady(fd interface{}, stream pb.LocalFile_ReadServer) error {
 			if events[0].Fd != int32(inotify.iFD) {
 				return status.Errorf(codes.Internal, "epoll event for wrong FD? got %+v", events[0])
 			}
-			// In theory we should read the event from the inotify FD and interpret it but we're only
-			// monitoring one thing and there's no errors that report in-band (just via errno).
-			break
+			// We only monitor one thing so the events themselves don't matter
+			// but they have to be consumed or epoll will keep returning at once.
+			// Errors don't report in-band (just via errno) and the caller
+			// checks the file state anyways so they're ignored.
+			buf := make([]byte, unix.SizeofInotifyEvent+unix.NAME_MAX+1)
+			unix.Read(inotify.iFD, buf)
 		}
-		// Nothing (timed out) so just loop
+		return nil
 	}
-	return nil
 }
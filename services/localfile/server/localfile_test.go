@@ -17,8 +17,12 @@
 package server
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -27,18 +31,25 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
 	"github.com/Snowflake-Labs/sansshell/services/util"
 	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+	"github.com/google/go-cmp/cmp"
 	_ "gocloud.dev/blob/fileblob"
 	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var (
@@ -51,6 +62,10 @@ func bufDialer(context.Context, string) (net.Conn, error) {
 }
 
 func TestMain(m *testing.M) {
+	// Tails poll at this interval so keep it short. It's set once here as
+	// streams from earlier tests may still be reading it.
+	ReadTimeout = 1 * time.Second
+
 	lis = bufconn.Listen(bufSize)
 	s := grpc.NewServer()
 	lfs := &server{}
@@ -156,6 +171,7 @@ func TestRead(t *testing.T) {
 			testutil.FatalOnErr("Read failed", err, t)
 
 			buf := &bytes.Buffer{}
+			var offset int64
 			for {
 				resp, err := stream.Recv()
 				if err == io.EOF {
@@ -174,17 +190,21 @@ func TestRead(t *testing.T) {
 					t.Fatalf("Can't write into buffer at correct length. Got %d want %d", got, want)
 				}
 				testutil.FatalOnErr("can't write into buffer", err, t)
+				offset = resp.Offset
 			}
 			contents, err := os.ReadFile(tc.filename)
 			testutil.FatalOnErr("reading test data", err, t)
+			start := 0
+			if tc.offset > 0 {
+				start = int(tc.offset)
+			}
+			if tc.offset < 0 {
+				start = len(contents) + int(tc.offset)
+			}
+			if got, want := offset, int64(start+buf.Len()); got != want {
+				t.Fatalf("offset reached: got %d want %d", got, want)
+			}
 			if tc.offset != 0 || tc.length != 0 {
-				start := 0
-				if tc.offset > 0 {
-					start = int(tc.offset)
-				}
-				if tc.offset < 0 {
-					start = len(contents) + int(tc.offset)
-				}
 				length := 0
 				if tc.length != 0 {
 					length = int(tc.length)
@@ -198,14 +218,60 @@ func TestRead(t *testing.T) {
 	}
 }
 
+func TestReadResume(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	want, err := os.ReadFile("/etc/hosts")
+	testutil.FatalOnErr("reading test data", err, t)
+
+	// Read in small ranges, each one resuming from the offset the previous
+	// one reached, until a read returns nothing.
+	client := pb.NewLocalFileClient(conn)
+	buf := &bytes.Buffer{}
+	var offset int64
+	for reads := 0; ; reads++ {
+		if reads > len(want) {
+			t.Fatalf("read didn't finish after %d ranges", reads)
+		}
+		stream, err := client.Read(ctx, &pb.ReadActionRequest{
+			Request: &pb.ReadActionRequest_File{
+				File: &pb.ReadRequest{
+					Filename: "/etc/hosts",
+					Offset:   offset,
+					Length:   7,
+				},
+			},
+		})
+		testutil.FatalOnErr("Read failed", err, t)
+		got := 0
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			testutil.FatalOnErr("Recv", err, t)
+			buf.Write(resp.Contents)
+			got += len(resp.Contents)
+			offset = resp.Offset
+		}
+		if got == 0 {
+			break
+		}
+	}
+	if got := buf.Bytes(); !bytes.Equal(got, want) {
+		t.Fatalf("contents do not match. Got:\n%s\n\nWant:\n%s", got, want)
+	}
+}
+
 func TestTail(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
 	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
 	t.Cleanup(func() { conn.Close() })
 
-	ReadTimeout = 1 * time.Second
-
 	// Create a file with some initial data.
 	temp := t.TempDir()
 	f1, err := os.CreateTemp(temp, "testfile.*")
@@ -263,6 +329,73 @@ func TestTail(t *testing.T) {
 	testutil.FatalOnNoErr(fmt.Sprintf("recv with cancelled context - resp %v", resp), err, t)
 }
 
+func TestTailRotation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	name := filepath.Join(t.TempDir(), "log")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(name, []byte("0123456789first\n"), 0644), t)
+	appendData := func(data string) {
+		f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY, 0644)
+		testutil.FatalOnErr("can't open for adding data", err, t)
+		_, err = f.WriteString(data)
+		testutil.FatalOnErr("WriteString", err, t)
+		testutil.FatalOnErr("Close", f.Close(), t)
+	}
+
+	client := pb.NewLocalFileClient(conn)
+	// Start with the last 6 bytes.
+	stream, err := client.Read(ctx, &pb.ReadActionRequest{
+		Request: &pb.ReadActionRequest_Tail{
+			Tail: &pb.TailRequest{
+				Filename: name,
+				Offset:   -6,
+			},
+		},
+	})
+	testutil.FatalOnErr("error from read", err, t)
+	expect := func(want string) {
+		t.Helper()
+		buf := &bytes.Buffer{}
+		for buf.Len() < len(want) {
+			resp, err := stream.Recv()
+			testutil.FatalOnErr("error reading from stream", err, t)
+			buf.Write(resp.Contents)
+		}
+		if got := buf.String(); got != want {
+			t.Fatalf("tail data: got %q want %q", got, want)
+		}
+	}
+	expect("first\n")
+
+	// Rotate by moving the file away and creating a new one. Data appended
+	// to the old one before the new one is written is still returned.
+	testutil.FatalOnErr("Rename", os.Rename(name, name+".1"), t)
+	f, err := os.OpenFile(name+".1", os.O_APPEND|os.O_WRONLY, 0644)
+	testutil.FatalOnErr("OpenFile", err, t)
+	_, err = f.WriteString("late\n")
+	testutil.FatalOnErr("WriteString", err, t)
+	testutil.FatalOnErr("Close", f.Close(), t)
+	expect("late\n")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(name, []byte("rotated\n"), 0644), t)
+	expect("rotated\n")
+	appendData("more\n")
+	expect("more\n")
+
+	// Truncating starts again from the beginning.
+	testutil.FatalOnErr("Truncate", os.Truncate(name, 0), t)
+	appendData("new\n")
+	expect("new\n")
+
+	// Make sure the server is done with the stream (which reads ReadTimeout
+	// and the fakeable functions) before other tests run.
+	cancel()
+	time.Sleep(ReadTimeout + 1*time.Second)
+}
+
 func TestStat(t *testing.T) {
 	ctx := context.Background()
 	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -423,6 +556,7 @@ func TestSum(t *testing.T) {
 				Filename: tempfile.Name(),
 				SumType:  pb.SumType_SUM_TYPE_SHA256,
 				Sum:      "3115e68dae98b7c1093fbcb4173483c4af25fd7167169be1b50d9798f4e9229f",
+				Size:     1354,
 			},
 			sendErrFunc: testutil.FatalOnErr,
 			recvErrFunc: testutil.FatalOnErr,
@@ -445,6 +579,7 @@ func TestSum(t *testing.T) {
 				Filename: tempfile.Name(),
 				SumType:  pb.SumType_SUM_TYPE_SHA256,
 				Sum:      "3115e68dae98b7c1093fbcb4173483c4af25fd7167169be1b50d9798f4e9229f",
+				Size:     1354,
 			},
 			sendErrFunc: testutil.FatalOnErr,
 			recvErrFunc: testutil.FatalOnErr,
@@ -456,6 +591,7 @@ func TestSum(t *testing.T) {
 				Filename: tempfile.Name(),
 				SumType:  pb.SumType_SUM_TYPE_SHA512_256,
 				Sum:      "f28247cd3fb739c77014b33f3aff1e48e7dc3674c46c10498dc8d25f4b3405a1",
+				Size:     1354,
 			},
 			sendErrFunc: testutil.FatalOnErr,
 			recvErrFunc: testutil.FatalOnErr,
@@ -467,6 +603,31 @@ func TestSum(t *testing.T) {
 				Filename: tempfile.Name(),
 				SumType:  pb.SumType_SUM_TYPE_MD5,
 				Sum:      "485032cb71937bed2d371731498d20d3",
+				Size:     1354,
+			},
+			sendErrFunc: testutil.FatalOnErr,
+			recvErrFunc: testutil.FatalOnErr,
+		},
+		{
+			name: "sha512",
+			req:  &pb.SumRequest{Filename: tempfile.Name(), SumType: pb.SumType_SUM_TYPE_SHA512},
+			reply: &pb.SumReply{
+				Filename: tempfile.Name(),
+				SumType:  pb.SumType_SUM_TYPE_SHA512,
+				Sum:      "ef6f31fd6919542f1015b0bce3bbe386160207f6a2330dbfd94bf5d124840db6534f5d9d3854d31b8bf4d3584796642d7b142de273291bb0d7e3f5e9d5bdf2b2",
+				Size:     1354,
+			},
+			sendErrFunc: testutil.FatalOnErr,
+			recvErrFunc: testutil.FatalOnErr,
+		},
+		{
+			name: "crc32c",
+			req:  &pb.SumRequest{Filename: tempfile.Name(), SumType: pb.SumType_SUM_TYPE_CRC32C},
+			reply: &pb.SumReply{
+				Filename: tempfile.Name(),
+				SumType:  pb.SumType_SUM_TYPE_CRC32C,
+				Sum:      "56caf44e",
+				Size:     1354,
 			},
 			sendErrFunc: testutil.FatalOnErr,
 			recvErrFunc: testutil.FatalOnErr,
@@ -478,6 +639,7 @@ func TestSum(t *testing.T) {
 				Filename: tempfile.Name(),
 				SumType:  pb.SumType_SUM_TYPE_CRC32IEEE,
 				Sum:      "01df4a25",
+				Size:     1354,
 			},
 			sendErrFunc: testutil.FatalOnErr,
 			recvErrFunc: testutil.FatalOnErr,
@@ -858,6 +1020,115 @@ func TestSetFileAttributes(t *testing.T) {
 	}
 }
 
+func TestSetFileTimes(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewLocalFileClient(conn)
+	temp := t.TempDir()
+	oldAtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	oldMtime := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	newTime := time.Date(2022, 6, 7, 8, 9, 10, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name      string
+		attrs     []*pb.FileAttribute
+		wantErr   bool
+		wantAtime time.Time
+		wantMtime time.Time
+	}{
+		{
+			name:      "mtime only",
+			attrs:     []*pb.FileAttribute{{Value: &pb.FileAttribute_Mtime{Mtime: timestamppb.New(newTime)}}},
+			wantAtime: oldAtime,
+			wantMtime: newTime,
+		},
+		{
+			name:      "atime only",
+			attrs:     []*pb.FileAttribute{{Value: &pb.FileAttribute_Atime{Atime: timestamppb.New(newTime)}}},
+			wantAtime: newTime,
+			wantMtime: oldMtime,
+		},
+		{
+			name: "both",
+			attrs: []*pb.FileAttribute{
+				{Value: &pb.FileAttribute_Atime{Atime: timestamppb.New(newTime)}},
+				{Value: &pb.FileAttribute_Mtime{Mtime: timestamppb.New(newTime)}},
+			},
+			wantAtime: newTime,
+			wantMtime: newTime,
+		},
+		{
+			name: "mtime twice",
+			attrs: []*pb.FileAttribute{
+				{Value: &pb.FileAttribute_Mtime{Mtime: timestamppb.New(newTime)}},
+				{Value: &pb.FileAttribute_Mtime{Mtime: timestamppb.New(newTime)}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid mtime",
+			attrs:   []*pb.FileAttribute{{Value: &pb.FileAttribute_Mtime{Mtime: &timestamppb.Timestamp{Nanos: -1}}}},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			name := filepath.Join(temp, strings.ReplaceAll(tc.name, " ", "-"))
+			testutil.FatalOnErr("WriteFile", os.WriteFile(name, nil, 0644), t)
+			testutil.FatalOnErr("Chtimes", os.Chtimes(name, oldAtime, oldMtime), t)
+
+			_, err := client.SetFileAttributes(ctx, &pb.SetFileAttributesRequest{
+				Attrs: &pb.FileAttributes{
+					Filename:   name,
+					Attributes: tc.attrs,
+				},
+			})
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if tc.wantErr {
+				return
+			}
+			fi, err := os.Stat(name)
+			testutil.FatalOnErr("Stat", err, t)
+			if got, want := fi.ModTime(), tc.wantMtime; !got.Equal(want) {
+				t.Errorf("mtime: got %v want %v", got, want)
+			}
+			if got, want := accessTime(fi), tc.wantAtime; !got.Equal(want) {
+				t.Errorf("atime: got %v want %v", got, want)
+			}
+		})
+	}
+
+	// Times given to Write apply to the final file, not the one being written.
+	name := filepath.Join(temp, "written")
+	stream, err := client.Write(ctx)
+	testutil.FatalOnErr("Write", err, t)
+	err = stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Description{Description: &pb.FileWrite{
+		Attrs: &pb.FileAttributes{
+			Filename: name,
+			Attributes: []*pb.FileAttribute{
+				{Value: &pb.FileAttribute_Uid{Uid: uint32(os.Getuid())}},
+				{Value: &pb.FileAttribute_Gid{Gid: uint32(os.Getgid())}},
+				{Value: &pb.FileAttribute_Mode{Mode: 0644}},
+				{Value: &pb.FileAttribute_Mtime{Mtime: timestamppb.New(newTime)}},
+			},
+		},
+	}}})
+	testutil.FatalOnErr("Write send", err, t)
+	err = stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Contents{Contents: []byte("contents")}})
+	testutil.FatalOnErr("Write send", err, t)
+	if _, err := stream.CloseAndRecv(); err != nil && err != io.EOF {
+		t.Fatalf("Write: %v", err)
+	}
+	fi, err := os.Stat(name)
+	testutil.FatalOnErr("Stat", err, t)
+	if got, want := fi.ModTime(), newTime; !got.Equal(want) {
+		t.Errorf("written file mtime: got %v want %v", got, want)
+	}
+}
+
 func TestList(t *testing.T) {
 	ctx := context.Background()
 	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -986,6 +1257,88 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestListDepthAndGlob(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	root := t.TempDir()
+	for _, d := range []string{"sub", "sub/deeper"} {
+		testutil.FatalOnErr("Mkdir", os.Mkdir(filepath.Join(root, d), 0755), t)
+	}
+	for _, f := range []string{"a.log", "b.txt", "sub/c.log", "sub/deeper/d.log"} {
+		testutil.FatalOnErr("WriteFile", os.WriteFile(filepath.Join(root, f), nil, 0644), t)
+	}
+	// A symlink back to the root must not be followed.
+	testutil.FatalOnErr("Symlink", os.Symlink(root, filepath.Join(root, "sub", "loop")), t)
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.ListRequest
+		wantErr bool
+		want    []string
+	}{
+		{
+			name: "default depth",
+			req:  &pb.ListRequest{Entry: root},
+			want: []string{"", "a.log", "b.txt", "sub"},
+		},
+		{
+			name: "recursive",
+			req:  &pb.ListRequest{Entry: root, Depth: 5},
+			want: []string{"", "a.log", "b.txt", "sub", "sub/c.log", "sub/deeper", "sub/deeper/d.log", "sub/loop"},
+		},
+		{
+			name: "depth 1 with glob",
+			req:  &pb.ListRequest{Entry: root, Depth: 1, Glob: "*.log"},
+			want: []string{"", "a.log", "sub/c.log"},
+		},
+		{
+			name: "glob matching nothing",
+			req:  &pb.ListRequest{Entry: root, Glob: "*.gz"},
+			want: []string{""},
+		},
+		{
+			name:    "bad glob",
+			req:     &pb.ListRequest{Entry: root, Glob: "["},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := pb.NewLocalFileClient(conn)
+			stream, err := client.List(ctx, tc.req)
+			testutil.FatalOnErr("List", err, t)
+			var got []string
+			var gotErr error
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					gotErr = err
+					break
+				}
+				rel, err := filepath.Rel(root, resp.Entry.Filename)
+				testutil.FatalOnErr("Rel", err, t)
+				if rel == "." {
+					rel = ""
+				}
+				if rel == "sub/loop" && resp.Entry.SymlinkTarget != root {
+					t.Errorf("symlink target: got %q want %q", resp.Entry.SymlinkTarget, root)
+				}
+				got = append(got, rel)
+			}
+			testutil.WantErr(tc.name, gotErr, tc.wantErr, t)
+			if !tc.wantErr {
+				testutil.DiffErr(tc.name, got, tc.want, t)
+			}
+		})
+	}
+}
+
 func TestWrite(t *testing.T) {
 	ctx := context.Background()
 	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -1460,6 +1813,240 @@ func TestWrite(t *testing.T) {
 	}
 }
 
+func TestWriteAppendAndExpectedSum(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	uid, gid := os.Getuid(), os.Getgid()
+	sum := func(s string) string {
+		h := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(h[:])
+	}
+
+	for _, tc := range []struct {
+		name     string
+		existing string // If non-empty the file is created with these contents first.
+		write    *pb.FileWrite
+		contents string
+		wantCode codes.Code
+		validate string
+	}{
+		{
+			name:     "append to existing",
+			existing: "hello ",
+			write:    &pb.FileWrite{Append: true},
+			contents: "world",
+			validate: "hello world",
+		},
+		{
+			name:     "append to missing",
+			write:    &pb.FileWrite{Append: true},
+			contents: "world",
+			validate: "world",
+		},
+		{
+			name:     "append with matching sum",
+			existing: "hello ",
+			write:    &pb.FileWrite{Append: true, ExpectedSha256: sum("hello ")},
+			contents: "world",
+			validate: "hello world",
+		},
+		{
+			name:     "overwrite with matching sum",
+			existing: "old",
+			write:    &pb.FileWrite{Overwrite: true, ExpectedSha256: strings.ToUpper(sum("old"))},
+			contents: "new",
+			validate: "new",
+		},
+		{
+			name:     "overwrite with mismatched sum",
+			existing: "changed",
+			write:    &pb.FileWrite{Overwrite: true, ExpectedSha256: sum("old")},
+			contents: "new",
+			wantCode: codes.FailedPrecondition,
+			validate: "changed",
+		},
+		{
+			name:     "expected sum of missing file",
+			write:    &pb.FileWrite{Overwrite: true, ExpectedSha256: sum("old")},
+			contents: "new",
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "expected sum without overwrite",
+			existing: "old",
+			write:    &pb.FileWrite{ExpectedSha256: sum("old")},
+			contents: "new",
+			wantCode: codes.InvalidArgument,
+			validate: "old",
+		},
+		{
+			name:     "invalid expected sum",
+			existing: "old",
+			write:    &pb.FileWrite{Overwrite: true, ExpectedSha256: "abc"},
+			contents: "new",
+			wantCode: codes.InvalidArgument,
+			validate: "old",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			filename := filepath.Join(temp, strings.ReplaceAll(tc.name, " ", "-"))
+			if tc.existing != "" {
+				testutil.FatalOnErr("WriteFile", os.WriteFile(filename, []byte(tc.existing), 0644), t)
+			}
+			tc.write.Attrs = &pb.FileAttributes{
+				Filename: filename,
+				Attributes: []*pb.FileAttribute{
+					{Value: &pb.FileAttribute_Uid{Uid: uint32(uid)}},
+					{Value: &pb.FileAttribute_Gid{Gid: uint32(gid)}},
+					{Value: &pb.FileAttribute_Mode{Mode: 0644}},
+				},
+			}
+			client := pb.NewLocalFileClient(conn)
+			stream, err := client.Write(ctx)
+			testutil.FatalOnErr("Write", err, t)
+			err = stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Description{Description: tc.write}})
+			testutil.FatalOnErr("Write send", err, t)
+			err = stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Contents{Contents: []byte(tc.contents)}})
+			if err != io.EOF {
+				testutil.FatalOnErr("Write send", err, t)
+			}
+			_, err = stream.CloseAndRecv()
+			if err == io.EOF {
+				err = nil
+			}
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+			}
+
+			c, err := os.ReadFile(filename)
+			if tc.validate == "" {
+				if err == nil {
+					t.Fatalf("%s was created: %q", filename, c)
+				}
+				return
+			}
+			testutil.FatalOnErr("ReadFile()", err, t)
+			if got, want := string(c), tc.validate; got != want {
+				t.Fatalf("contents not equal.\nGot : %s\nWant: %s", got, want)
+			}
+			// No temp files should be left behind either way.
+			entries, err := os.ReadDir(temp)
+			testutil.FatalOnErr("ReadDir", err, t)
+			for _, e := range entries {
+				if strings.HasPrefix(e.Name(), filepath.Base(filename)) && e.Name() != filepath.Base(filename) {
+					t.Fatalf("temp file %s left behind", e.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestWriteAppendInPlace(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	uid, gid := os.Getuid(), os.Getgid()
+	write := func(filename string, d *pb.FileWrite, contents string) error {
+		d.Attrs = &pb.FileAttributes{
+			Filename: filename,
+			Attributes: []*pb.FileAttribute{
+				{Value: &pb.FileAttribute_Uid{Uid: uint32(uid)}},
+				{Value: &pb.FileAttribute_Gid{Gid: uint32(gid)}},
+				{Value: &pb.FileAttribute_Mode{Mode: 0644}},
+			},
+		}
+		stream, err := pb.NewLocalFileClient(conn).Write(ctx)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Description{Description: d}}); err != nil && err != io.EOF {
+			return err
+		}
+		if err := stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Contents{Contents: []byte(contents)}}); err != nil && err != io.EOF {
+			return err
+		}
+		_, err = stream.CloseAndRecv()
+		if err == io.EOF {
+			err = nil
+		}
+		return err
+	}
+
+	// Appending keeps the file, so hard links and open files see it.
+	filename := filepath.Join(temp, "log")
+	link := filepath.Join(temp, "link")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(filename, []byte("one\n"), 0644), t)
+	testutil.FatalOnErr("Link", os.Link(filename, link), t)
+	open, err := os.Open(filename)
+	testutil.FatalOnErr("Open", err, t)
+	defer open.Close()
+	testutil.FatalOnErr("Write", write(filename, &pb.FileWrite{Append: true}, "two\n"), t)
+	for _, name := range []string{filename, link} {
+		c, err := os.ReadFile(name)
+		testutil.FatalOnErr("ReadFile", err, t)
+		if got, want := string(c), "one\ntwo\n"; got != want {
+			t.Errorf("%s contains %q, want %q", name, got, want)
+		}
+	}
+	c, err := io.ReadAll(open)
+	testutil.FatalOnErr("ReadAll", err, t)
+	if got, want := string(c), "one\ntwo\n"; got != want {
+		t.Errorf("open file contains %q, want %q", got, want)
+	}
+
+	// Concurrent appends are all kept.
+	const writers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := write(filename, &pb.FileWrite{Append: true}, fmt.Sprintf("append %d\n", i)); err != nil {
+				t.Errorf("append %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	c, err = os.ReadFile(filename)
+	testutil.FatalOnErr("ReadFile", err, t)
+	if got, want := strings.Count(string(c), "append"), writers; got != want {
+		t.Errorf("got %d appends, want %d: %q", got, want, c)
+	}
+
+	// Of concurrent writes expecting the same contents only one succeeds.
+	sum := sha256.Sum256(c)
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := write(filename, &pb.FileWrite{Overwrite: true, ExpectedSha256: hex.EncodeToString(sum[:])}, fmt.Sprintf("overwrite %d\n", i))
+			switch status.Code(err) {
+			case codes.OK:
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			case codes.FailedPrecondition:
+			default:
+				t.Errorf("overwrite %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if succeeded != 1 {
+		t.Errorf("%d overwrites succeeded, want 1", succeeded)
+	}
+}
+
 func TestCopy(t *testing.T) {
 	ctx := context.Background()
 	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -1757,3 +2344,768 @@ func TestRmdir(t *testing.T) {
 		})
 	}
 }
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	want := map[string]string{
+		"a":       "contents of a",
+		"sub/":    "",
+		"sub/b":   strings.Repeat("b", 3*util.StreamingChunkSize),
+		"sub/c/":  "",
+		"sub/c/d": "",
+		"link":    "-> sub/b",
+	}
+	var names []string
+	for name := range want {
+		names = append(names, name)
+	}
+	// Sorted so directories are created before their contents.
+	sort.Strings(names)
+	for _, name := range names {
+		contents := want[name]
+		path := filepath.Join(temp, filepath.FromSlash(name))
+		var err error
+		switch {
+		case strings.HasSuffix(name, "/"):
+			err = os.MkdirAll(path, 0755)
+		case strings.HasPrefix(contents, "-> "):
+			err = os.Symlink(strings.TrimPrefix(contents, "-> "), path)
+		default:
+			err = os.WriteFile(path, []byte(contents), 0644)
+		}
+		testutil.FatalOnErr("setup "+name, err, t)
+	}
+	file := filepath.Join(temp, "a")
+
+	client := pb.NewLocalFileClient(conn)
+	for _, tc := range []struct {
+		name    string
+		req     *pb.ArchiveRequest
+		wantErr bool
+	}{
+		{
+			name:    "non-absolute path",
+			req:     &pb.ArchiveRequest{Directory: "../relative"},
+			wantErr: true,
+		},
+		{
+			name:    "not a directory",
+			req:     &pb.ArchiveRequest{Directory: file},
+			wantErr: true,
+		},
+		{
+			name: "tar",
+			req:  &pb.ArchiveRequest{Directory: temp},
+		},
+		{
+			name: "tar.gz",
+			req:  &pb.ArchiveRequest{Directory: temp, Gzip: true},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := client.Archive(ctx, tc.req)
+			testutil.FatalOnErr("Archive", err, t)
+			var buf bytes.Buffer
+			var sum string
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if tc.wantErr {
+					testutil.FatalOnNoErr("Recv", err, t)
+					return
+				}
+				testutil.FatalOnErr("Recv", err, t)
+				if sum != "" {
+					t.Fatal("got reply after the checksum")
+				}
+				buf.Write(resp.Contents)
+				sum = resp.Sha256
+			}
+			if tc.wantErr {
+				t.Fatal("expected an error, got none")
+			}
+			h := sha256.Sum256(buf.Bytes())
+			if got, want := hex.EncodeToString(h[:]), sum; got != want {
+				t.Fatalf("archive sha256 = %s, trailing checksum %s", got, want)
+			}
+
+			var r io.Reader = &buf
+			if tc.req.Gzip {
+				r, err = gzip.NewReader(r)
+				testutil.FatalOnErr("gzip.NewReader", err, t)
+			}
+			tr := tar.NewReader(r)
+			got := make(map[string]string)
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				testutil.FatalOnErr("tar.Next", err, t)
+				switch hdr.Typeflag {
+				case tar.TypeSymlink:
+					got[hdr.Name] = "-> " + hdr.Linkname
+				default:
+					b, err := io.ReadAll(tr)
+					testutil.FatalOnErr("read "+hdr.Name, err, t)
+					got[hdr.Name] = string(b)
+				}
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected archive contents (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// manifestOf independently computes the expected manifest for data.
+func manifestOf(data []byte, chunkSize int64) *pb.FileManifest {
+	m := &pb.FileManifest{Size: int64(len(data)), ChunkSize: chunkSize}
+	for off := int64(0); off < int64(len(data)); off += chunkSize {
+		end := off + chunkSize
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+		h := sha256.Sum256(data[off:end])
+		m.ChunkSha256 = append(m.ChunkSha256, hex.EncodeToString(h[:]))
+	}
+	h := sha256.Sum256(data)
+	m.Sha256 = hex.EncodeToString(h[:])
+	return m
+}
+
+func TestManifest(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	var data []byte
+	for i := 0; len(data) < 3*MinManifestChunkSize+100; i++ {
+		data = append(data, []byte(fmt.Sprintf("line %d\n", i))...)
+	}
+	file := filepath.Join(temp, "file")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(file, data, 0644), t)
+	empty := filepath.Join(temp, "empty")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(empty, nil, 0644), t)
+
+	for _, tc := range []struct {
+		name     string
+		req      *pb.ManifestRequest
+		want     *pb.FileManifest
+		wantCode codes.Code
+	}{
+		{
+			name: "default chunk size",
+			req:  &pb.ManifestRequest{Filename: file},
+			want: manifestOf(data, DefaultManifestChunkSize),
+		},
+		{
+			name: "small chunks",
+			req:  &pb.ManifestRequest{Filename: file, ChunkSize: MinManifestChunkSize},
+			want: manifestOf(data, MinManifestChunkSize),
+		},
+		{
+			name: "empty file",
+			req:  &pb.ManifestRequest{Filename: empty},
+			want: manifestOf(nil, DefaultManifestChunkSize),
+		},
+		{
+			name:     "chunk size too small",
+			req:      &pb.ManifestRequest{Filename: file, ChunkSize: 1024},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "directory",
+			req:      &pb.ManifestRequest{Filename: temp},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "non-absolute path",
+			req:      &pb.ManifestRequest{Filename: "../relative"},
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := pb.NewLocalFileClient(conn)
+			got, err := client.Manifest(ctx, tc.req)
+			if c := status.Code(err); c != tc.wantCode {
+				t.Fatalf("unexpected code. got %v want %v err %v", c, tc.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Fatalf("unexpected manifest (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	uid, gid := os.Getuid(), os.Getgid()
+	data := bytes.Repeat([]byte("0123456789abcdef"), 3*MinManifestChunkSize/16+10)
+	corrupt := append([]byte{}, data...)
+	corrupt[2*MinManifestChunkSize+5] ^= 0xff
+
+	for _, tc := range []struct {
+		name     string
+		existing []byte
+		append   bool
+		manifest *pb.FileManifest
+		contents []byte
+		wantCode codes.Code
+		wantErr  string
+	}{
+		{
+			name:     "matches",
+			manifest: manifestOf(data, MinManifestChunkSize),
+			contents: data,
+		},
+		{
+			name:     "append matches",
+			existing: data[:MinManifestChunkSize+7],
+			append:   true,
+			manifest: manifestOf(data, MinManifestChunkSize),
+			contents: data[MinManifestChunkSize+7:],
+		},
+		{
+			name:     "corrupt chunk",
+			manifest: manifestOf(data, MinManifestChunkSize),
+			contents: corrupt,
+			wantCode: codes.DataLoss,
+			wantErr:  "chunk 2",
+		},
+		{
+			name:     "truncated",
+			manifest: manifestOf(data, MinManifestChunkSize),
+			contents: data[:len(data)-1],
+			wantCode: codes.DataLoss,
+		},
+		{
+			name:     "chunk size too small",
+			manifest: manifestOf(data, 1024),
+			contents: data,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "inconsistent chunk count",
+			manifest: func() *pb.FileManifest {
+				m := manifestOf(data, MinManifestChunkSize)
+				m.ChunkSha256 = m.ChunkSha256[1:]
+				return m
+			}(),
+			contents: data,
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			filename := filepath.Join(temp, strings.ReplaceAll(tc.name, " ", "-"))
+			if tc.existing != nil {
+				testutil.FatalOnErr("WriteFile", os.WriteFile(filename, tc.existing, 0644), t)
+			}
+			client := pb.NewLocalFileClient(conn)
+			stream, err := client.Write(ctx)
+			testutil.FatalOnErr("Write", err, t)
+			err = stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Description{Description: &pb.FileWrite{
+				Attrs: &pb.FileAttributes{
+					Filename: filename,
+					Attributes: []*pb.FileAttribute{
+						{Value: &pb.FileAttribute_Uid{Uid: uint32(uid)}},
+						{Value: &pb.FileAttribute_Gid{Gid: uint32(gid)}},
+						{Value: &pb.FileAttribute_Mode{Mode: 0644}},
+					},
+				},
+				Append:   tc.append,
+				Manifest: tc.manifest,
+			}}})
+			testutil.FatalOnErr("Write send", err, t)
+			for off := 0; off < len(tc.contents); off += util.StreamingChunkSize {
+				end := off + util.StreamingChunkSize
+				if end > len(tc.contents) {
+					end = len(tc.contents)
+				}
+				if err := stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Contents{Contents: tc.contents[off:end]}}); err != nil {
+					if err == io.EOF {
+						break
+					}
+					testutil.FatalOnErr("Write send", err, t)
+				}
+			}
+			_, err = stream.CloseAndRecv()
+			if err == io.EOF {
+				err = nil
+			}
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+			}
+			if tc.wantErr != "" && !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error %v doesn't contain %q", err, tc.wantErr)
+			}
+
+			c, err := os.ReadFile(filename)
+			if tc.wantCode != codes.OK {
+				if tc.existing == nil && err == nil {
+					t.Fatalf("%s was created", filename)
+				}
+				return
+			}
+			testutil.FatalOnErr("ReadFile()", err, t)
+			if !bytes.Equal(c, data) {
+				t.Fatalf("contents of %s don't match what was written", filename)
+			}
+		})
+	}
+}
+
+func TestSymlink(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	current := filepath.Join(temp, "current")
+	testutil.FatalOnErr("Symlink", os.Symlink("releases/v1", current), t)
+	file := filepath.Join(temp, "file")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(file, []byte("contents"), 0644), t)
+
+	for _, tc := range []struct {
+		name     string
+		req      *pb.SymlinkRequest
+		wantCode codes.Code
+		want     string // The expected target of linkname afterwards.
+	}{
+		{
+			name: "new link",
+			req:  &pb.SymlinkRequest{Target: "/etc/hosts", Linkname: filepath.Join(temp, "new")},
+			want: "/etc/hosts",
+		},
+		{
+			name:     "exists without replace",
+			req:      &pb.SymlinkRequest{Target: "releases/v2", Linkname: current},
+			wantCode: codes.AlreadyExists,
+			want:     "releases/v1",
+		},
+		{
+			name:     "replace with wrong expected target",
+			req:      &pb.SymlinkRequest{Target: "releases/v2", Linkname: current, Replace: true, ExpectedTarget: "releases/v0"},
+			wantCode: codes.FailedPrecondition,
+			want:     "releases/v1",
+		},
+		{
+			name: "replace with expected target",
+			req:  &pb.SymlinkRequest{Target: "releases/v2", Linkname: current, Replace: true, ExpectedTarget: "releases/v1"},
+			want: "releases/v2",
+		},
+		{
+			name: "replace",
+			req:  &pb.SymlinkRequest{Target: "releases/v3", Linkname: current, Replace: true},
+			want: "releases/v3",
+		},
+		{
+			name: "replace file",
+			req:  &pb.SymlinkRequest{Target: "releases/v3", Linkname: file, Replace: true},
+			want: "releases/v3",
+		},
+		{
+			name:     "expected target without replace",
+			req:      &pb.SymlinkRequest{Target: "releases/v4", Linkname: current, ExpectedTarget: "releases/v3"},
+			wantCode: codes.InvalidArgument,
+			want:     "releases/v3",
+		},
+		{
+			name:     "no target",
+			req:      &pb.SymlinkRequest{Linkname: filepath.Join(temp, "empty")},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "non-absolute path",
+			req:      &pb.SymlinkRequest{Target: "/etc/hosts", Linkname: "../relative"},
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := pb.NewLocalFileClient(conn)
+			_, err := client.Symlink(ctx, tc.req)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+			}
+			if tc.want == "" {
+				return
+			}
+			got, err := os.Readlink(tc.req.Linkname)
+			testutil.FatalOnErr("Readlink", err, t)
+			if got != tc.want {
+				t.Fatalf("%s links to %s, want %s", tc.req.Linkname, got, tc.want)
+			}
+		})
+	}
+	// No temporary links should be left behind.
+	entries, err := os.ReadDir(temp)
+	testutil.FatalOnErr("ReadDir", err, t)
+	for _, e := range entries {
+		switch e.Name() {
+		case "current", "file", "new":
+		default:
+			t.Fatalf("unexpected entry %s left behind", e.Name())
+		}
+	}
+}
+
+func TestReadlink(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	link := filepath.Join(temp, "link")
+	testutil.FatalOnErr("Symlink", os.Symlink("releases/v1", link), t)
+
+	for _, tc := range []struct {
+		name     string
+		filename string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "link",
+			filename: link,
+			want:     "releases/v1",
+		},
+		{
+			name:     "not a link",
+			filename: temp,
+			wantErr:  true,
+		},
+		{
+			name:     "missing",
+			filename: filepath.Join(temp, "missing"),
+			wantErr:  true,
+		},
+		{
+			name:     "bad path",
+			filename: "/tmp/foo/../../etc/passwd",
+			wantErr:  true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := pb.NewLocalFileClient(conn)
+			resp, err := client.Readlink(ctx, &pb.ReadlinkRequest{Filename: tc.filename})
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if tc.wantErr {
+				return
+			}
+			if got := resp.Target; got != tc.want {
+				t.Fatalf("got target %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLink(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	file := filepath.Join(temp, "file")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(file, []byte("contents"), 0644), t)
+
+	for _, tc := range []struct {
+		name     string
+		req      *pb.LinkRequest
+		wantCode codes.Code
+	}{
+		{
+			name: "new link",
+			req:  &pb.LinkRequest{Target: file, Linkname: filepath.Join(temp, "link")},
+		},
+		{
+			name:     "exists",
+			req:      &pb.LinkRequest{Target: file, Linkname: filepath.Join(temp, "link")},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "missing target",
+			req:      &pb.LinkRequest{Target: filepath.Join(temp, "missing"), Linkname: filepath.Join(temp, "link2")},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "relative target",
+			req:      &pb.LinkRequest{Target: "file", Linkname: filepath.Join(temp, "link3")},
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := pb.NewLocalFileClient(conn)
+			_, err := client.Link(ctx, tc.req)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+			}
+			if err != nil {
+				return
+			}
+			want, err := os.Stat(tc.req.Target)
+			testutil.FatalOnErr("Stat", err, t)
+			got, err := os.Stat(tc.req.Linkname)
+			testutil.FatalOnErr("Stat", err, t)
+			if !os.SameFile(got, want) {
+				t.Fatalf("%s isn't a hard link to %s", tc.req.Linkname, tc.req.Target)
+			}
+		})
+	}
+}
+
+func TestRmExpectedTarget(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	link := filepath.Join(temp, "link")
+	testutil.FatalOnErr("Symlink", os.Symlink("releases/v1", link), t)
+	file := filepath.Join(temp, "file")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(file, []byte("contents"), 0644), t)
+
+	for _, tc := range []struct {
+		name       string
+		req        *pb.RmRequest
+		wantCode   codes.Code
+		wantExists bool
+	}{
+		{
+			name:       "wrong target",
+			req:        &pb.RmRequest{Filename: link, ExpectedTarget: "releases/v2"},
+			wantCode:   codes.FailedPrecondition,
+			wantExists: true,
+		},
+		{
+			name:       "not a link",
+			req:        &pb.RmRequest{Filename: file, ExpectedTarget: "releases/v1"},
+			wantCode:   codes.FailedPrecondition,
+			wantExists: true,
+		},
+		{
+			name:     "missing",
+			req:      &pb.RmRequest{Filename: filepath.Join(temp, "missing"), ExpectedTarget: "releases/v1"},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "expected target",
+			req:  &pb.RmRequest{Filename: link, ExpectedTarget: "releases/v1"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := pb.NewLocalFileClient(conn)
+			_, err := client.Rm(ctx, tc.req)
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+			}
+			_, err = os.Lstat(tc.req.Filename)
+			if got := err == nil; got != tc.wantExists {
+				t.Fatalf("%s exists: %t, want %t", tc.req.Filename, got, tc.wantExists)
+			}
+		})
+	}
+}
+
+func TestSetAppendOnly(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	file := filepath.Join(temp, "file")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(file, []byte("contents"), 0644), t)
+
+	// Setting the flag for real needs root (and a supporting filesystem) so
+	// just record what's requested.
+	var calls []string
+	savedChangeAppendOnlyOS := changeAppendOnlyOS
+	changeAppendOnlyOS = func(path string, appendOnly bool) error {
+		calls = append(calls, fmt.Sprintf("%s %t", path, appendOnly))
+		return nil
+	}
+	t.Cleanup(func() { changeAppendOnlyOS = savedChangeAppendOnlyOS })
+
+	client := pb.NewLocalFileClient(conn)
+	for _, tc := range []struct {
+		name      string
+		attrs     []*pb.FileAttribute
+		wantErr   bool
+		wantCalls []string
+	}{
+		{
+			name:      "set",
+			attrs:     []*pb.FileAttribute{{Value: &pb.FileAttribute_AppendOnly{AppendOnly: true}}},
+			wantCalls: []string{file + " true"},
+		},
+		{
+			name:      "clear",
+			attrs:     []*pb.FileAttribute{{Value: &pb.FileAttribute_AppendOnly{}}},
+			wantCalls: []string{file + " false"},
+		},
+		{
+			name: "set twice",
+			attrs: []*pb.FileAttribute{
+				{Value: &pb.FileAttribute_AppendOnly{AppendOnly: true}},
+				{Value: &pb.FileAttribute_AppendOnly{}},
+			},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			calls = nil
+			_, err := client.SetFileAttributes(ctx, &pb.SetFileAttributesRequest{
+				Attrs: &pb.FileAttributes{Filename: file, Attributes: tc.attrs},
+			})
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if diff := cmp.Diff(tc.wantCalls, calls); diff != "" {
+				t.Fatalf("unexpected calls (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// A written file only gets the flag once it's in place.
+	calls = nil
+	written := filepath.Join(temp, "written")
+	stream, err := client.Write(ctx)
+	testutil.FatalOnErr("Write", err, t)
+	err = stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Description{Description: &pb.FileWrite{
+		Attrs: &pb.FileAttributes{
+			Filename: written,
+			Attributes: []*pb.FileAttribute{
+				{Value: &pb.FileAttribute_Uid{Uid: uint32(os.Getuid())}},
+				{Value: &pb.FileAttribute_Gid{Gid: uint32(os.Getgid())}},
+				{Value: &pb.FileAttribute_Mode{Mode: 0644}},
+				{Value: &pb.FileAttribute_AppendOnly{AppendOnly: true}},
+			},
+		},
+	}}})
+	testutil.FatalOnErr("Write send", err, t)
+	err = stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Contents{Contents: []byte("contents")}})
+	testutil.FatalOnErr("Write send", err, t)
+	if _, err := stream.CloseAndRecv(); err != nil && err != io.EOF {
+		t.Fatalf("Write: %v", err)
+	}
+	if diff := cmp.Diff([]string{written + " true"}, calls); diff != "" {
+		t.Fatalf("unexpected calls (-want +got):\n%s", diff)
+	}
+}
+
+func TestXattrs(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	temp := t.TempDir()
+	file := filepath.Join(temp, "file")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(file, []byte("contents"), 0644), t)
+	if err := setXattr(file, "user.probe", nil); err != nil {
+		t.Skipf("xattrs not supported in %s: %v", temp, err)
+	}
+	testutil.FatalOnErr("removeXattr", removeXattr(file, "user.probe"), t)
+
+	client := pb.NewLocalFileClient(conn)
+	for _, tc := range []struct {
+		name    string
+		set     *pb.SetXattrsRequest
+		get     *pb.GetXattrsRequest
+		wantErr bool
+		want    []*pb.Xattr
+	}{
+		{
+			name: "set",
+			set: &pb.SetXattrsRequest{Filename: file, Set: []*pb.Xattr{
+				{Name: "user.b", Value: []byte("2")},
+				{Name: "user.a", Value: []byte("1")},
+			}},
+			get: &pb.GetXattrsRequest{Filename: file},
+			want: []*pb.Xattr{
+				{Name: "user.a", Value: []byte("1")},
+				{Name: "user.b", Value: []byte("2")},
+			},
+		},
+		{
+			name: "replace and remove",
+			set: &pb.SetXattrsRequest{
+				Filename: file,
+				Set:      []*pb.Xattr{{Name: "user.a", Value: []byte("one")}},
+				Remove:   []string{"user.b"},
+			},
+			get:  &pb.GetXattrsRequest{Filename: file},
+			want: []*pb.Xattr{{Name: "user.a", Value: []byte("one")}},
+		},
+		{
+			name: "get by name",
+			get:  &pb.GetXattrsRequest{Filename: file, Names: []string{"user.a"}},
+			want: []*pb.Xattr{{Name: "user.a", Value: []byte("one")}},
+		},
+		{
+			name:    "get missing",
+			get:     &pb.GetXattrsRequest{Filename: file, Names: []string{"user.b"}},
+			wantErr: true,
+		},
+		{
+			name:    "remove missing",
+			set:     &pb.SetXattrsRequest{Filename: file, Remove: []string{"user.b"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			set:     &pb.SetXattrsRequest{Filename: file, Set: []*pb.Xattr{{Value: []byte("1")}}},
+			wantErr: true,
+		},
+		{
+			name:    "bad path",
+			get:     &pb.GetXattrsRequest{Filename: "/tmp/foo/../../etc/passwd"},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.set != nil {
+				_, err := client.SetXattrs(ctx, tc.set)
+				if tc.get == nil {
+					testutil.WantErr(tc.name, err, tc.wantErr, t)
+					return
+				}
+				testutil.FatalOnErr("SetXattrs", err, t)
+			}
+			resp, err := client.GetXattrs(ctx, tc.get)
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if tc.wantErr {
+				return
+			}
+			testutil.DiffErr(tc.name, resp, &pb.GetXattrsReply{Xattrs: tc.want}, t)
+		})
+	}
+}
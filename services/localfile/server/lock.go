@@ -0,0 +1,180 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+// lockRetryInterval is how often a held lock is retried until the timeout.
+var lockRetryInterval = 100 * time.Millisecond
+
+// fileLock is an advisory lock acquired with acquireLock.
+type fileLock struct {
+	f *os.File
+}
+
+// release drops the lock. It's safe to call on a nil lock.
+func (l *fileLock) release() {
+	if l != nil {
+		// Closing the file releases both kinds of lock.
+		l.f.Close()
+	}
+}
+
+// acquireLock takes the lock described by d.Lock, if any, waiting up to its
+// timeout. The caller must release it once the write is complete.
+func acquireLock(ctx context.Context, d *pb.FileWrite) (*fileLock, error) {
+	l := d.Lock
+	if l == nil {
+		return nil, nil
+	}
+	if l.Type != pb.LockType_LOCK_TYPE_FLOCK && l.Type != pb.LockType_LOCK_TYPE_FCNTL {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid lock type %v", l.Type)
+	}
+	var timeout time.Duration
+	if l.Timeout != nil {
+		if err := l.Timeout.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid lock timeout: %v", err)
+		}
+		if timeout = l.Timeout.AsDuration(); timeout < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid lock timeout %v", timeout)
+		}
+	}
+
+	// The destination has to exist already as creating it would be visible
+	// to readers (and break the overwrite check), while a separate lock file
+	// is created as needed.
+	path, flags := d.Attrs.GetFilename(), os.O_RDWR
+	if l.Path != "" {
+		path, flags = l.Path, os.O_RDWR|os.O_CREATE
+	}
+	if err := util.ValidPath(path); err != nil {
+		return nil, err
+	}
+	if err := checkPath(path); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(path, flags, 0600)
+		if errors.Is(err, fs.ErrNotExist) && l.Path == "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "can't lock %s as it doesn't exist, a separate lock path is needed", path)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't open %s to lock: %v", path, err)
+		}
+		locked, err := tryLock(f, l.Type)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if locked {
+			// Whoever held the lock may have replaced the file (i.e. by
+			// writing the destination), in which case lock the new one.
+			fi, err := f.Stat()
+			if err != nil {
+				f.Close()
+				return nil, status.Errorf(codes.Internal, "can't stat %s: %v", path, err)
+			}
+			if current, err := os.Stat(path); err == nil && os.SameFile(fi, current) {
+				return &fileLock{f: f}, nil
+			}
+			f.Close()
+			continue
+		}
+
+		holders := lockHolders(f)
+		f.Close()
+		if !time.Now().Before(deadline) {
+			if len(holders) == 0 {
+				return nil, status.Errorf(codes.Aborted, "%s is locked", path)
+			}
+			return nil, status.Errorf(codes.Aborted, "%s is locked by %s", path, strings.Join(holders, ", "))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// holdsDestinationLock returns true if the lock described by d.Lock is an
+// flock on the destination itself, which a second flock (i.e. from
+// lockDestination) would wait on forever.
+func holdsDestinationLock(d *pb.FileWrite) bool {
+	return d.Lock != nil && d.Lock.Path == "" && d.Lock.Type == pb.LockType_LOCK_TYPE_FLOCK
+}
+
+// lockDestination opens filename with flag and takes an exclusive flock on
+// it, waiting until ctx is done for any other holder. Writes which depend on
+// the existing contents hold this from checking them until their change is
+// made so concurrent writers can't lose each other's updates. If the file is
+// replaced while waiting the new one is locked instead. If `held` the caller
+// already holds the lock and the file is only opened. Returns a nil file if
+// filename doesn't exist.
+func lockDestination(ctx context.Context, filename string, flag int, held bool) (*os.File, error) {
+	for {
+		f, err := os.OpenFile(filename, flag, 0)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't open %s: %v", filename, err)
+		}
+		if held {
+			return f, nil
+		}
+		err = unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+		if err == unix.EWOULDBLOCK {
+			f.Close()
+			select {
+			case <-ctx.Done():
+				return nil, status.FromContextError(ctx.Err()).Err()
+			case <-time.After(lockRetryInterval):
+			}
+			continue
+		}
+		if err != nil {
+			f.Close()
+			return nil, status.Errorf(codes.Internal, "can't lock %s: %v", filename, err)
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, status.Errorf(codes.Internal, "can't stat %s: %v", filename, err)
+		}
+		if current, err := os.Stat(filename); err == nil && os.SameFile(fi, current) {
+			return f, nil
+		}
+		f.Close()
+	}
+}
@@ -0,0 +1,39 @@
+//go:build !linux
+// +build !linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+)
+
+// tryLock is unimplemented on this platform.
+func tryLock(f *os.File, t pb.LockType) (bool, error) {
+	return false, status.Error(codes.Unimplemented, "locking files is not supported on this platform")
+}
+
+// lockHolders is unimplemented on this platform.
+func lockHolders(f *os.File) []string {
+	return nil
+}
@@ -0,0 +1,107 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+)
+
+// procLocks lists the locks held on the system, see proc(5).
+var procLocks = "/proc/locks"
+
+// tryLock takes an exclusive lock of type t on f without blocking,
+// returning false if it's held elsewhere.
+//
+// fcntl locks are taken as open file description locks rather than
+// traditional POSIX ones so they exclude other writes by this process
+// too. They still conflict with POSIX locks held by other processes.
+func tryLock(f *os.File, t pb.LockType) (bool, error) {
+	var err error
+	switch t {
+	case pb.LockType_LOCK_TYPE_FLOCK:
+		err = unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+	case pb.LockType_LOCK_TYPE_FCNTL:
+		lk := &unix.Flock_t{Type: unix.F_WRLCK, Whence: 0}
+		err = unix.FcntlFlock(f.Fd(), unix.F_OFD_SETLK, lk)
+	}
+	switch err {
+	case nil:
+		return true, nil
+	case unix.EWOULDBLOCK, unix.EACCES:
+		// EACCES is what fcntl may return for a held lock.
+		return false, nil
+	}
+	return false, status.Errorf(codes.Internal, "can't lock %s: %v", f.Name(), err)
+}
+
+// lockHolders returns the processes holding locks on f, as found in
+// procLocks, i.e. "pid 1234 (vim)". Locks without an owning process, such
+// as open file description locks on older kernels, aren't included.
+func lockHolders(f *os.File) []string {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	// Files are named as major:minor:inode with the device in hex.
+	id := fmt.Sprintf("%02x:%02x:%d", unix.Major(uint64(st.Dev)), unix.Minor(uint64(st.Dev)), st.Ino)
+
+	locks, err := os.Open(procLocks)
+	if err != nil {
+		return nil
+	}
+	defer locks.Close()
+	var out []string
+	seen := make(map[int64]bool)
+	scanner := bufio.NewScanner(locks)
+	for scanner.Scan() {
+		// 1: FLOCK  ADVISORY  WRITE 1234 08:01:5678 0 EOF
+		// Blocked waiters have "->" after the number and are skipped.
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[1] == "->" || fields[5] != id {
+			continue
+		}
+		pid, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil || pid <= 0 || seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		holder := fmt.Sprintf("pid %d", pid)
+		if comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid)); err == nil {
+			holder += fmt.Sprintf(" (%s)", strings.TrimSpace(string(comm)))
+		}
+		out = append(out, holder)
+	}
+	return out
+}
@@ -0,0 +1,248 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// holdLock locks path as another writer would, returning a func to
+// release it.
+func holdLock(t *testing.T, path string, lockType pb.LockType) func() {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	testutil.FatalOnErr("open", err, t)
+	t.Cleanup(func() { f.Close() })
+	switch lockType {
+	case pb.LockType_LOCK_TYPE_FLOCK:
+		err = unix.Flock(int(f.Fd()), unix.LOCK_EX)
+	case pb.LockType_LOCK_TYPE_FCNTL:
+		err = unix.FcntlFlock(f.Fd(), unix.F_OFD_SETLK, &unix.Flock_t{Type: unix.F_WRLCK})
+	}
+	testutil.FatalOnErr("lock", err, t)
+	return func() { f.Close() }
+}
+
+func TestWriteLock(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	savedInterval := lockRetryInterval
+	lockRetryInterval = 10 * time.Millisecond
+	t.Cleanup(func() { lockRetryInterval = savedInterval })
+
+	temp := t.TempDir()
+	uid, gid := os.Getuid(), os.Getgid()
+	holder := fmt.Sprintf("pid %d", os.Getpid())
+
+	for _, tc := range []struct {
+		name     string
+		existing bool
+		lock     *pb.FileLock
+		// If set called with the destination before writing, returning a
+		// func to call once the write is under way.
+		setup    func(t *testing.T, filename string) func()
+		wantCode codes.Code
+		wantErr  string
+	}{
+		{
+			name:     "flock",
+			existing: true,
+			lock:     &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FLOCK},
+		},
+		{
+			name:     "fcntl",
+			existing: true,
+			lock:     &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FCNTL},
+		},
+		{
+			name:     "flock held",
+			existing: true,
+			lock:     &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FLOCK},
+			setup: func(t *testing.T, filename string) func() {
+				holdLock(t, filename, pb.LockType_LOCK_TYPE_FLOCK)
+				return func() {}
+			},
+			wantCode: codes.Aborted,
+			wantErr:  holder,
+		},
+		{
+			name:     "fcntl held",
+			existing: true,
+			lock:     &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FCNTL, Timeout: durationpb.New(50 * time.Millisecond)},
+			setup: func(t *testing.T, filename string) func() {
+				holdLock(t, filename, pb.LockType_LOCK_TYPE_FCNTL)
+				return func() {}
+			},
+			wantCode: codes.Aborted,
+		},
+		{
+			name:     "flock and fcntl don't conflict",
+			existing: true,
+			lock:     &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FCNTL},
+			setup: func(t *testing.T, filename string) func() {
+				holdLock(t, filename, pb.LockType_LOCK_TYPE_FLOCK)
+				return func() {}
+			},
+		},
+		{
+			name:     "released while waiting",
+			existing: true,
+			lock:     &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FLOCK, Timeout: durationpb.New(10 * time.Second)},
+			setup: func(t *testing.T, filename string) func() {
+				release := holdLock(t, filename, pb.LockType_LOCK_TYPE_FLOCK)
+				return func() {
+					time.Sleep(50 * time.Millisecond)
+					release()
+				}
+			},
+		},
+		{
+			name:     "replaced while waiting",
+			existing: true,
+			lock:     &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FLOCK, Timeout: durationpb.New(10 * time.Second)},
+			setup: func(t *testing.T, filename string) func() {
+				release := holdLock(t, filename, pb.LockType_LOCK_TYPE_FLOCK)
+				return func() {
+					time.Sleep(50 * time.Millisecond)
+					// As another writer of the destination would.
+					testutil.FatalOnErr("WriteFile", os.WriteFile(filename+".new", []byte("other"), 0644), t)
+					testutil.FatalOnErr("Rename", os.Rename(filename+".new", filename), t)
+					release()
+				}
+			},
+		},
+		{
+			name: "lock path",
+			lock: &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FLOCK, Path: filepath.Join(temp, "lock-path.lock")},
+		},
+		{
+			name: "lock path held",
+			lock: &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FLOCK, Path: filepath.Join(temp, "lock-path-held.lock")},
+			setup: func(t *testing.T, filename string) func() {
+				holdLock(t, filename+".lock", pb.LockType_LOCK_TYPE_FLOCK)
+				return func() {}
+			},
+			wantCode: codes.Aborted,
+			wantErr:  holder,
+		},
+		{
+			name:     "missing destination",
+			lock:     &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FLOCK},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "bad type",
+			existing: true,
+			lock:     &pb.FileLock{},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "relative lock path",
+			lock:     &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FLOCK, Path: "foo.lock"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "negative timeout",
+			existing: true,
+			lock:     &pb.FileLock{Type: pb.LockType_LOCK_TYPE_FLOCK, Timeout: durationpb.New(-time.Second)},
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			filename := filepath.Join(temp, strings.ReplaceAll(tc.name, " ", "-"))
+			if tc.existing {
+				testutil.FatalOnErr("WriteFile", os.WriteFile(filename, []byte("old"), 0644), t)
+			}
+			started := func() {}
+			if tc.setup != nil {
+				started = tc.setup(t, filename)
+			}
+
+			client := pb.NewLocalFileClient(conn)
+			stream, err := client.Write(ctx)
+			testutil.FatalOnErr("Write", err, t)
+			err = stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Description{Description: &pb.FileWrite{
+				Attrs: &pb.FileAttributes{
+					Filename: filename,
+					Attributes: []*pb.FileAttribute{
+						{Value: &pb.FileAttribute_Uid{Uid: uint32(uid)}},
+						{Value: &pb.FileAttribute_Gid{Gid: uint32(gid)}},
+						{Value: &pb.FileAttribute_Mode{Mode: 0644}},
+					},
+				},
+				Overwrite: true,
+				Lock:      tc.lock,
+			}}})
+			testutil.FatalOnErr("Write send", err, t)
+			started()
+			err = stream.Send(&pb.WriteRequest{Request: &pb.WriteRequest_Contents{Contents: []byte("new")}})
+			if err != io.EOF {
+				testutil.FatalOnErr("Write send", err, t)
+			}
+			_, err = stream.CloseAndRecv()
+			if err == io.EOF {
+				err = nil
+			}
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error %v doesn't contain %q", err, tc.wantErr)
+			}
+
+			want := "new"
+			if tc.wantCode != codes.OK {
+				want = "old"
+			}
+			c, err := os.ReadFile(filename)
+			if !tc.existing && tc.wantCode != codes.OK {
+				if err == nil {
+					t.Fatalf("%s was created: %q", filename, c)
+				}
+				return
+			}
+			testutil.FatalOnErr("ReadFile", err, t)
+			if got := string(c); got != want {
+				t.Fatalf("contents not equal. got %q want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestLockDestination(t *testing.T) {
+	saved := lockRetryInterval
+	lockRetryInterval = time.Millisecond
+	t.Cleanup(func() { lockRetryInterval = saved })
+
+	temp := t.TempDir()
+	filename := filepath.Join(temp, "file")
+
+	f, err := lockDestination(context.Background(), filename, os.O_RDONLY, false)
+	testutil.FatalOnErr("lockDestination", err, t)
+	if f != nil {
+		t.Fatalf("got a file for missing %s", filename)
+	}
+
+	testutil.FatalOnErr("WriteFile", os.WriteFile(filename, []byte("old"), 0644), t)
+	held, err := os.Open(filename)
+	testutil.FatalOnErr("Open", err, t)
+	defer held.Close()
+	testutil.FatalOnErr("Flock", unix.Flock(int(held.Fd()), unix.LOCK_EX), t)
+
+	// Someone else holding it means waiting.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = lockDestination(ctx, filename, os.O_RDONLY, false)
+	if got, want := status.Code(err), codes.DeadlineExceeded; got != want {
+		t.Fatalf("got code %v while locked, want %v err %v", got, want, err)
+	}
+
+	// Unless the caller holds it already.
+	f, err = lockDestination(context.Background(), filename, os.O_RDONLY, true)
+	testutil.FatalOnErr("lockDestination held", err, t)
+	f.Close()
+
+	// Replacing the file while it's held means the new one is locked.
+	done := make(chan *os.File)
+	go func() {
+		f, err := lockDestination(context.Background(), filename, os.O_RDONLY, false)
+		if err != nil {
+			t.Errorf("lockDestination: %v", err)
+		}
+		done <- f
+	}()
+	replacement := filepath.Join(temp, "new")
+	testutil.FatalOnErr("WriteFile", os.WriteFile(replacement, []byte("new"), 0644), t)
+	testutil.FatalOnErr("Rename", os.Rename(replacement, filename), t)
+	held.Close()
+	f = <-done
+	if f == nil {
+		t.Fatal("didn't get a file")
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	testutil.FatalOnErr("Stat", err, t)
+	current, err := os.Stat(filename)
+	testutil.FatalOnErr("Stat", err, t)
+	if !os.SameFile(fi, current) {
+		t.Error("locked the replaced file")
+	}
+}
@@ -0,0 +1,153 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const (
+	// DefaultManifestChunkSize is the chunk size used for manifests
+	// when a request doesn't specify one.
+	DefaultManifestChunkSize = 4 * 1024 * 1024
+
+	// MinManifestChunkSize is the smallest chunk size accepted. This bounds
+	// the size of a manifest for any reasonable file.
+	MinManifestChunkSize = 64 * 1024
+)
+
+// computeManifest returns the manifest of the data in r using chunks of
+// chunkSize bytes.
+func computeManifest(r io.Reader, chunkSize int64) (*pb.FileManifest, error) {
+	m := &pb.FileManifest{ChunkSize: chunkSize}
+	total := sha256.New()
+	for {
+		chunk := sha256.New()
+		n, err := io.CopyN(io.MultiWriter(chunk, total), r, chunkSize)
+		if n > 0 {
+			m.ChunkSha256 = append(m.ChunkSha256, hex.EncodeToString(chunk.Sum(nil)))
+			m.Size += n
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.Sha256 = hex.EncodeToString(total.Sum(nil))
+	return m, nil
+}
+
+// validateManifest checks a manifest supplied by a client is self consistent.
+func validateManifest(m *pb.FileManifest) error {
+	if m.ChunkSize < MinManifestChunkSize {
+		return status.Errorf(codes.InvalidArgument, "manifest chunk size %d is less than the minimum %d", m.ChunkSize, MinManifestChunkSize)
+	}
+	if m.Size < 0 {
+		return status.Errorf(codes.InvalidArgument, "manifest size %d is negative", m.Size)
+	}
+	if got, want := int64(len(m.ChunkSha256)), (m.Size+m.ChunkSize-1)/m.ChunkSize; got != want {
+		return status.Errorf(codes.InvalidArgument, "manifest has %d chunks, expected %d for size %d", got, want, m.Size)
+	}
+	for _, s := range append([]string{m.Sha256}, m.ChunkSha256...) {
+		if b, err := hex.DecodeString(s); err != nil || len(b) != sha256.Size {
+			return status.Errorf(codes.InvalidArgument, "manifest sum %q isn't a hex encoded SHA256 sum", s)
+		}
+	}
+	return nil
+}
+
+// checkManifest returns DataLoss unless filename matches the manifest `want`.
+func checkManifest(filename string, want *pb.FileManifest) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't open %s: %v", filename, err)
+	}
+	defer f.Close()
+	return matchManifest(f, filename, want)
+}
+
+// matchManifest returns DataLoss unless the contents of filename, read
+// from r, match the manifest `want`.
+func matchManifest(r io.Reader, filename string, want *pb.FileManifest) error {
+	got, err := computeManifest(r, want.ChunkSize)
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't read %s: %v", filename, err)
+	}
+	for i, sum := range got.ChunkSha256 {
+		if i >= len(want.ChunkSha256) {
+			break
+		}
+		if sum != want.ChunkSha256[i] {
+			return status.Errorf(codes.DataLoss, "chunk %d (offset %d) has SHA256 %s, expected %s", i, int64(i)*want.ChunkSize, sum, want.ChunkSha256[i])
+		}
+	}
+	if got.Size != want.Size {
+		return status.Errorf(codes.DataLoss, "file is %d bytes, expected %d", got.Size, want.Size)
+	}
+	if got.Sha256 != want.Sha256 {
+		return status.Errorf(codes.DataLoss, "file has SHA256 %s, expected %s", got.Sha256, want.Sha256)
+	}
+	return nil
+}
+
+func (s *server) Manifest(ctx context.Context, req *pb.ManifestRequest) (*pb.FileManifest, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	logger.Info("manifest request", "file", req.Filename, "chunksize", req.ChunkSize)
+	if err := util.ValidPath(req.Filename); err != nil {
+		return nil, AbsolutePathError
+	}
+	if err := checkPath(req.Filename); err != nil {
+		return nil, err
+	}
+	chunkSize := req.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = DefaultManifestChunkSize
+	}
+	if chunkSize < MinManifestChunkSize {
+		return nil, status.Errorf(codes.InvalidArgument, "chunk size %d is less than the minimum %d", chunkSize, MinManifestChunkSize)
+	}
+	f, err := os.Open(req.Filename)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't open %s: %v", req.Filename, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't stat %s: %v", req.Filename, err)
+	}
+	if fi.IsDir() {
+		return nil, status.Errorf(codes.InvalidArgument, "%s is a directory", req.Filename)
+	}
+	m, err := computeManifest(f, chunkSize)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read %s: %v", req.Filename, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,178 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+// mergeEvents returns the type of a single event describing a change of
+// type old followed by one of type new.
+func mergeEvents(old, new pb.WatchEventType) pb.WatchEventType {
+	switch {
+	case new == pb.WatchEventType_WATCH_EVENT_TYPE_DELETE:
+		return new
+	case old == pb.WatchEventType_WATCH_EVENT_TYPE_DELETE:
+		// Removed and then replaced.
+		return pb.WatchEventType_WATCH_EVENT_TYPE_MODIFY
+	case old == pb.WatchEventType_WATCH_EVENT_TYPE_CREATE, old == pb.WatchEventType_WATCH_EVENT_TYPE_MODIFY:
+		return old
+	default:
+		return new
+	}
+}
+
+// debouncer holds events until their path hasn't changed for delay,
+// merging them into one.
+type debouncer struct {
+	delay time.Duration
+
+	pending map[string]*pb.WatchEvent
+	due     map[string]time.Time
+}
+
+func newDebouncer(delay time.Duration) *debouncer {
+	return &debouncer{
+		delay:   delay,
+		pending: make(map[string]*pb.WatchEvent),
+		due:     make(map[string]time.Time),
+	}
+}
+
+// add queues events seen at now.
+func (d *debouncer) add(events []*pb.WatchEvent, now time.Time) {
+	for _, e := range events {
+		if p, ok := d.pending[e.Path]; ok {
+			e.Type = mergeEvents(p.Type, e.Type)
+		}
+		d.pending[e.Path] = e
+		d.due[e.Path] = now.Add(d.delay)
+	}
+}
+
+// wait returns how long until the next event is due, and false if none
+// are pending.
+func (d *debouncer) wait(now time.Time) (time.Duration, bool) {
+	var next time.Time
+	for _, t := range d.due {
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	if next.IsZero() {
+		return 0, false
+	}
+	return next.Sub(now), true
+}
+
+// ready returns the events due by now, oldest first.
+func (d *debouncer) ready(now time.Time) []*pb.WatchEvent {
+	var out []*pb.WatchEvent
+	for path, t := range d.due {
+		if t.After(now) {
+			continue
+		}
+		out = append(out, d.pending[path])
+		delete(d.pending, path)
+		delete(d.due, path)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if ti, tj := out[i].Time.AsTime(), out[j].Time.AsTime(); !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return out[i].Path < out[j].Path
+	})
+	return out
+}
+
+// Watch implements pb.LocalFileServer.Watch
+func (s *server) Watch(req *pb.WatchRequest, stream pb.LocalFile_WatchServer) error {
+	ctx := stream.Context()
+	logger := logr.FromContextOrDiscard(ctx)
+	if len(req.Paths) == 0 {
+		return status.Error(codes.InvalidArgument, "must specify at least one path")
+	}
+	for _, p := range req.Paths {
+		if err := util.ValidPath(p); err != nil {
+			return err
+		}
+		if err := checkPath(p); err != nil {
+			return err
+		}
+	}
+	var delay time.Duration
+	if req.Debounce != nil {
+		if err := req.Debounce.CheckValid(); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid debounce: %v", err)
+		}
+		delay = req.Debounce.AsDuration()
+		if delay < 0 {
+			return status.Error(codes.InvalidArgument, "debounce can't be negative")
+		}
+	}
+
+	w, err := newWatcher(req.Paths, req.Recursive)
+	if err != nil {
+		return err
+	}
+	defer w.close()
+	logger.Info("watching", "paths", req.Paths, "recursive", req.Recursive)
+
+	// Let the caller know changes from here on will be seen.
+	if err := stream.Send(&pb.WatchReply{}); err != nil {
+		return status.Errorf(codes.Internal, "watch: send error %v", err)
+	}
+
+	d := newDebouncer(delay)
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Wake up at least every ReadTimeout to check the context.
+		timeout := ReadTimeout
+		if t, ok := d.wait(time.Now()); ok && t < timeout {
+			timeout = t
+		}
+		events, err := w.next(timeout)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, e := range events {
+			e.Time = timestamppb.New(now)
+		}
+		ready := events
+		if delay > 0 {
+			d.add(events, now)
+			ready = d.ready(now)
+		}
+		if len(ready) > 0 {
+			if err := stream.Send(&pb.WatchReply{Events: ready}); err != nil {
+				return status.Errorf(codes.Internal, "watch: send error %v", err)
+			}
+		}
+	}
+}
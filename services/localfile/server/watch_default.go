@@ -0,0 +1,44 @@
+//go:build !linux
+// +build !linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+)
+
+// watcher is unsupported on this platform.
+type watcher struct{}
+
+// newWatcher is the default implementation of watching paths (which is
+// unsupported).
+func newWatcher(paths []string, recursive bool) (*watcher, error) {
+	return nil, status.Error(codes.Unimplemented, "watch not supported")
+}
+
+func (w *watcher) next(timeout time.Duration) ([]*pb.WatchEvent, error) {
+	return nil, status.Error(codes.Unimplemented, "watch not supported")
+}
+
+func (w *watcher) close() {}
@@ -0,0 +1,285 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+)
+
+// watchMask is the inotify events watched for on each directory.
+const watchMask = unix.IN_CREATE | unix.IN_MODIFY | unix.IN_ATTRIB | unix.IN_DELETE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF | unix.IN_ONLYDIR
+
+// watchedDir is a directory with an inotify watch on it. Files are watched
+// through their directory so replacing them (i.e. renaming a new version over
+// them) is seen.
+type watchedDir struct {
+	path string
+	// If true changes to all entries are reported, otherwise only those in
+	// names.
+	all   bool
+	names map[string]bool
+	// If true it was asked for, so removing it is reported.
+	self bool
+}
+
+// watcher reports changes with inotify.
+type watcher struct {
+	iFD       int
+	epoll     int
+	recursive bool
+	dirs      map[int]*watchedDir
+	buf       []byte
+}
+
+// newWatcher returns a watcher for paths, and if recursive the directories
+// under them.
+func newWatcher(paths []string, recursive bool) (_ *watcher, retErr error) {
+	w := &watcher{
+		iFD:       -1,
+		epoll:     -1,
+		recursive: recursive,
+		dirs:      make(map[int]*watchedDir),
+		buf:       make([]byte, 64*1024),
+	}
+	defer func() {
+		if retErr != nil {
+			w.close()
+		}
+	}()
+
+	var err error
+	w.iFD, err = inotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't allocate inotify fd: %v", err)
+	}
+	w.epoll, err = epollCreate(1)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't create epoll: %v", err)
+	}
+	event := &unix.EpollEvent{
+		Events: unix.EPOLLIN,
+		Fd:     int32(w.iFD),
+	}
+	if err := epollCtl(w.epoll, unix.EPOLL_CTL_ADD, w.iFD, event); err != nil {
+		return nil, status.Errorf(codes.Internal, "epollctl failed: %v", err)
+	}
+
+	for _, p := range paths {
+		p = filepath.Clean(p)
+		fi, err := os.Stat(p)
+		switch {
+		case err == nil && fi.IsDir():
+			d, err := w.add(p)
+			if err != nil {
+				return nil, err
+			}
+			d.all, d.self = true, true
+			if recursive {
+				if err := w.addTree(p); err != nil {
+					return nil, err
+				}
+			}
+		case err == nil || errors.Is(err, fs.ErrNotExist):
+			d, err := w.add(filepath.Dir(p))
+			if err != nil {
+				return nil, err
+			}
+			if d.names == nil {
+				d.names = make(map[string]bool)
+			}
+			d.names[filepath.Base(p)] = true
+		default:
+			return nil, status.Errorf(codes.Internal, "can't stat %s: %v", p, err)
+		}
+	}
+	return w, nil
+}
+
+// add starts watching dir, returning the existing watch if there is one.
+func (w *watcher) add(dir string) (*watchedDir, error) {
+	wd, err := inotifyAddWatch(w.iFD, dir, watchMask)
+	switch {
+	case errors.Is(err, unix.ENOENT), errors.Is(err, unix.ENOTDIR):
+		return nil, status.Errorf(codes.NotFound, "can't watch %s: %v", dir, err)
+	case errors.Is(err, unix.ENOSPC):
+		return nil, status.Errorf(codes.ResourceExhausted, "can't watch %s: too many watches (see fs.inotify.max_user_watches)", dir)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "can't setup inotify watch on %s: %v", dir, err)
+	}
+	// The same directory may be reached through different paths (i.e. with
+	// symlinks) but will get the same watch.
+	if d, ok := w.dirs[wd]; ok {
+		return d, nil
+	}
+	d := &watchedDir{path: dir}
+	w.dirs[wd] = d
+	return d, nil
+}
+
+// addTree watches all the directories beneath dir, reporting changes to all
+// of their entries.
+func (w *watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, de fs.DirEntry, err error) error {
+		// Directories may be removed as we go, which isn't an error.
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "can't walk %s: %v", path, err)
+		}
+		if !de.IsDir() || path == dir {
+			return nil
+		}
+		d, err := w.add(path)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		d.all = true
+		return nil
+	})
+}
+
+// eventType returns the type of change an inotify mask describes.
+func eventType(mask uint32) pb.WatchEventType {
+	switch {
+	case mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+		return pb.WatchEventType_WATCH_EVENT_TYPE_CREATE
+	case mask&unix.IN_MODIFY != 0:
+		return pb.WatchEventType_WATCH_EVENT_TYPE_MODIFY
+	case mask&(unix.IN_DELETE|unix.IN_MOVED_FROM|unix.IN_DELETE_SELF|unix.IN_MOVE_SELF) != 0:
+		return pb.WatchEventType_WATCH_EVENT_TYPE_DELETE
+	case mask&unix.IN_ATTRIB != 0:
+		return pb.WatchEventType_WATCH_EVENT_TYPE_ATTRIB
+	}
+	return pb.WatchEventType_WATCH_EVENT_TYPE_UNKNOWN
+}
+
+// next waits up to timeout for changes, returning any seen.
+func (w *watcher) next(timeout time.Duration) ([]*pb.WatchEvent, error) {
+	if timeout < 0 {
+		timeout = 0
+	}
+	events := make([]unix.EpollEvent, 1)
+	n, err := epollWait(w.epoll, events, int(timeout.Milliseconds()))
+	if err != nil {
+		// If we got EINTR the caller will just call again.
+		if err == unix.EINTR {
+			return nil, nil
+		}
+		return nil, status.Errorf(codes.Internal, "epoll error: %v", err)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	var out []*pb.WatchEvent
+	for {
+		n, err := unix.Read(w.iFD, w.buf)
+		if err == unix.EAGAIN {
+			return out, nil
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "inotify read error: %v", err)
+		}
+		if n == 0 {
+			return out, nil
+		}
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			raw := (*unix.InotifyEvent)(unsafe.Pointer(&w.buf[off]))
+			start := off + unix.SizeofInotifyEvent
+			off = start + int(raw.Len)
+			if off > n {
+				break
+			}
+			name := strings.TrimRight(string(w.buf[start:off]), "\x00")
+			e, err := w.event(int(raw.Wd), raw.Mask, name)
+			if err != nil {
+				return nil, err
+			}
+			if e != nil {
+				out = append(out, e)
+			}
+		}
+	}
+}
+
+// event handles an inotify event, returning the change it reports (if any
+// that was asked for).
+func (w *watcher) event(wd int, mask uint32, name string) (*pb.WatchEvent, error) {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		return nil, status.Error(codes.ResourceExhausted, "too many changes to watch, some were lost")
+	}
+	d, ok := w.dirs[wd]
+	if !ok {
+		return nil, nil
+	}
+	if mask&unix.IN_IGNORED != 0 {
+		// The directory is gone, so is the watch.
+		delete(w.dirs, wd)
+		return nil, nil
+	}
+	isDir := mask&unix.IN_ISDIR != 0
+	if name == "" {
+		// An event for the directory itself. Only its removal matters as
+		// changes to it are seen as changes to its entries.
+		if !d.self || mask&(unix.IN_DELETE_SELF|unix.IN_MOVE_SELF) == 0 {
+			return nil, nil
+		}
+		return &pb.WatchEvent{Path: d.path, Type: pb.WatchEventType_WATCH_EVENT_TYPE_DELETE, IsDir: true}, nil
+	}
+	if !d.all && !d.names[name] {
+		return nil, nil
+	}
+	path := filepath.Join(d.path, name)
+	if w.recursive && d.all && isDir && mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+		// Watch new directories too. Anything created in them before the
+		// watch was added is missed.
+		nd, err := w.add(path)
+		if err == nil {
+			nd.all = true
+			err = w.addTree(path)
+		}
+		if err != nil && status.Code(err) != codes.NotFound {
+			return nil, err
+		}
+	}
+	return &pb.WatchEvent{Path: path, Type: eventType(mask), IsDir: isDir}, nil
+}
+
+// close releases the watcher's resources.
+func (w *watcher) close() {
+	unix.Close(w.iFD)
+	unix.Close(w.epoll)
+}
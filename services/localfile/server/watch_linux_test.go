@@ -0,0 +1,212 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// watchStream starts a watch, waiting until it's established.
+func watchStream(ctx context.Context, t *testing.T, req *pb.WatchRequest) *eventStream {
+	t.Helper()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+	stream, err := pb.NewLocalFileClient(conn).Watch(ctx, req)
+	testutil.FatalOnErr("Watch", err, t)
+	resp, err := stream.Recv()
+	testutil.FatalOnErr("Watch first reply", err, t)
+	if len(resp.Events) != 0 {
+		t.Fatalf("first reply has events: %v", resp)
+	}
+	return &eventStream{stream: stream}
+}
+
+type pathEvent struct {
+	path string
+	typ  pb.WatchEventType
+}
+
+// eventStream returns events from a watch one at a time.
+type eventStream struct {
+	stream  pb.LocalFile_WatchClient
+	pending []*pb.WatchEvent
+}
+
+func (s *eventStream) next(t *testing.T) pathEvent {
+	t.Helper()
+	for len(s.pending) == 0 {
+		resp, err := s.stream.Recv()
+		testutil.FatalOnErr("Watch Recv", err, t)
+		s.pending = resp.Events
+	}
+	e := s.pending[0]
+	s.pending = s.pending[1:]
+	t.Logf("event: %s %v", e.Path, e.Type)
+	return pathEvent{e.Path, e.Type}
+}
+
+// waitFor receives events until want is seen, failing if an event for a
+// path other than want's or those in allowed is seen first.
+func (s *eventStream) waitFor(t *testing.T, want pathEvent, allowed ...string) {
+	t.Helper()
+	for {
+		got := s.next(t)
+		if got == want {
+			return
+		}
+		ok := got.path == want.path
+		for _, a := range allowed {
+			ok = ok || got.path == a
+		}
+		if !ok {
+			t.Fatalf("unexpected event %v waiting for %v", got, want)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	temp := t.TempDir()
+	conf := filepath.Join(temp, "app.conf")
+	sub := filepath.Join(temp, "sub")
+	testutil.FatalOnErr("mkdir", os.Mkdir(sub, 0755), t)
+
+	stream := watchStream(ctx, t, &pb.WatchRequest{
+		Paths:     []string{conf, sub},
+		Recursive: true,
+	})
+
+	// Not being watched.
+	testutil.FatalOnErr("write", os.WriteFile(filepath.Join(temp, "other"), []byte("x"), 0644), t)
+
+	testutil.FatalOnErr("write", os.WriteFile(conf, []byte("x"), 0644), t)
+	stream.waitFor(t, pathEvent{conf, pb.WatchEventType_WATCH_EVENT_TYPE_CREATE})
+	stream.waitFor(t, pathEvent{conf, pb.WatchEventType_WATCH_EVENT_TYPE_MODIFY})
+
+	// A new directory is watched once it's seen.
+	nested := filepath.Join(sub, "nested")
+	testutil.FatalOnErr("mkdir", os.Mkdir(nested, 0755), t)
+	stream.waitFor(t, pathEvent{nested, pb.WatchEventType_WATCH_EVENT_TYPE_CREATE})
+	file := filepath.Join(nested, "file")
+	testutil.FatalOnErr("write", os.WriteFile(file, []byte("x"), 0644), t)
+	stream.waitFor(t, pathEvent{file, pb.WatchEventType_WATCH_EVENT_TYPE_CREATE})
+
+	testutil.FatalOnErr("chmod", os.Chmod(conf, 0600), t)
+	stream.waitFor(t, pathEvent{conf, pb.WatchEventType_WATCH_EVENT_TYPE_ATTRIB}, file)
+	testutil.FatalOnErr("rm", os.Remove(conf), t)
+	stream.waitFor(t, pathEvent{conf, pb.WatchEventType_WATCH_EVENT_TYPE_DELETE})
+	testutil.FatalOnErr("rm", os.RemoveAll(sub), t)
+	stream.waitFor(t, pathEvent{sub, pb.WatchEventType_WATCH_EVENT_TYPE_DELETE}, nested, file)
+}
+
+func TestWatchDebounce(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	temp := t.TempDir()
+	conf := filepath.Join(temp, "app.conf")
+	stream := watchStream(ctx, t, &pb.WatchRequest{
+		Paths:    []string{conf},
+		Debounce: durationpb.New(200 * time.Millisecond),
+	})
+
+	f, err := os.Create(conf)
+	testutil.FatalOnErr("create", err, t)
+	for i := 0; i < 3; i++ {
+		_, err := f.WriteString("some data\n")
+		testutil.FatalOnErr("write", err, t)
+	}
+	testutil.FatalOnErr("close", f.Close(), t)
+
+	if got, want := stream.next(t), (pathEvent{conf, pb.WatchEventType_WATCH_EVENT_TYPE_CREATE}); got != want || len(stream.pending) != 0 {
+		t.Fatalf("want a single event %v, got %v and %v", want, got, stream.pending)
+	}
+
+	// Replace it as a config push might.
+	tmp := conf + ".tmp"
+	testutil.FatalOnErr("write", os.WriteFile(tmp, []byte("new data\n"), 0644), t)
+	testutil.FatalOnErr("rename", os.Rename(tmp, conf), t)
+	if got, want := stream.next(t), (pathEvent{conf, pb.WatchEventType_WATCH_EVENT_TYPE_CREATE}); got != want || len(stream.pending) != 0 {
+		t.Fatalf("want a single event %v, got %v and %v", want, got, stream.pending)
+	}
+}
+
+func TestWatchErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("grpc.DialContext(bufnet)", err, t)
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewLocalFileClient(conn)
+
+	temp := t.TempDir()
+	for _, tc := range []struct {
+		name    string
+		req     *pb.WatchRequest
+		wantErr codes.Code
+	}{
+		{
+			name:    "no paths",
+			req:     &pb.WatchRequest{},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "relative path",
+			req:     &pb.WatchRequest{Paths: []string{"foo"}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "negative debounce",
+			req:     &pb.WatchRequest{Paths: []string{temp}, Debounce: durationpb.New(-time.Second)},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "missing directory",
+			req:     &pb.WatchRequest{Paths: []string{filepath.Join(temp, "missing", "file")}},
+			wantErr: codes.NotFound,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := client.Watch(ctx, tc.req)
+			testutil.FatalOnErr("Watch", err, t)
+			_, err = stream.Recv()
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("unexpected error. got %v want %v: %v", got, tc.wantErr, err)
+			}
+		})
+	}
+}
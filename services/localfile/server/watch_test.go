@@ -0,0 +1,97 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+)
+
+func TestDebouncer(t *testing.T) {
+	const (
+		create = pb.WatchEventType_WATCH_EVENT_TYPE_CREATE
+		modify = pb.WatchEventType_WATCH_EVENT_TYPE_MODIFY
+		del    = pb.WatchEventType_WATCH_EVENT_TYPE_DELETE
+		attrib = pb.WatchEventType_WATCH_EVENT_TYPE_ATTRIB
+	)
+	start := time.Unix(1000, 0)
+	event := func(path string, typ pb.WatchEventType, at time.Duration) *pb.WatchEvent {
+		return &pb.WatchEvent{Path: path, Type: typ, Time: timestamppb.New(start.Add(at))}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		events []*pb.WatchEvent
+		want   []*pb.WatchEvent
+	}{
+		{
+			name:   "create and write",
+			events: []*pb.WatchEvent{event("/a", create, 0), event("/a", modify, 1), event("/a", attrib, 2)},
+			want:   []*pb.WatchEvent{event("/a", create, 2)},
+		},
+		{
+			name:   "write and chmod",
+			events: []*pb.WatchEvent{event("/a", attrib, 0), event("/a", modify, 1), event("/a", attrib, 2)},
+			want:   []*pb.WatchEvent{event("/a", modify, 2)},
+		},
+		{
+			name:   "replaced",
+			events: []*pb.WatchEvent{event("/a", del, 0), event("/a", create, 1)},
+			want:   []*pb.WatchEvent{event("/a", modify, 1)},
+		},
+		{
+			name:   "created and removed",
+			events: []*pb.WatchEvent{event("/a", create, 0), event("/a", modify, 1), event("/a", del, 2)},
+			want:   []*pb.WatchEvent{event("/a", del, 2)},
+		},
+		{
+			name:   "several paths",
+			events: []*pb.WatchEvent{event("/b", modify, 0), event("/a", create, 1), event("/b", modify, 2), event("/c", del, 2)},
+			want:   []*pb.WatchEvent{event("/a", create, 1), event("/b", modify, 2), event("/c", del, 2)},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			d := newDebouncer(time.Second)
+			for _, e := range tc.events {
+				now := e.Time.AsTime()
+				d.add([]*pb.WatchEvent{e}, now)
+				if got := d.ready(now); len(got) != 0 {
+					t.Fatalf("events ready before delay: %v", got)
+				}
+			}
+			last := tc.events[len(tc.events)-1].Time.AsTime()
+			wait, ok := d.wait(last)
+			if !ok || wait <= 0 || wait > time.Second {
+				t.Fatalf("unexpected wait %v (%v)", wait, ok)
+			}
+			got := d.ready(last.Add(time.Second))
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Fatalf("unexpected events (-want, +got):\n%s", diff)
+			}
+			if _, ok := d.wait(last); ok {
+				t.Fatal("events still pending")
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+func (s *server) GetXattrs(ctx context.Context, req *pb.GetXattrsRequest) (*pb.GetXattrsReply, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	logger.Info("getxattrs request", "filename", req.Filename, "names", req.Names)
+	if err := util.ValidPath(req.Filename); err != nil {
+		return nil, err
+	}
+	if err := checkPath(req.Filename); err != nil {
+		return nil, err
+	}
+	names := req.Names
+	if len(names) == 0 {
+		var err error
+		names, err = listXattrs(req.Filename)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't list xattrs of %s: %v", req.Filename, err)
+		}
+		sort.Strings(names)
+	}
+	resp := &pb.GetXattrsReply{}
+	for _, n := range names {
+		v, err := getXattr(req.Filename, n)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't get xattr %s of %s: %v", n, req.Filename, err)
+		}
+		resp.Xattrs = append(resp.Xattrs, &pb.Xattr{Name: n, Value: v})
+	}
+	return resp, nil
+}
+
+func (s *server) SetXattrs(ctx context.Context, req *pb.SetXattrsRequest) (*emptypb.Empty, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	var set []string
+	for _, x := range req.Set {
+		set = append(set, x.Name)
+	}
+	logger.Info("setxattrs request", "filename", req.Filename, "set", set, "remove", req.Remove)
+	if err := util.ValidPath(req.Filename); err != nil {
+		return nil, err
+	}
+	if err := checkPath(req.Filename); err != nil {
+		return nil, err
+	}
+	for _, x := range req.Set {
+		if x.Name == "" {
+			return nil, status.Error(codes.InvalidArgument, "xattr name must be filled in")
+		}
+	}
+	for _, x := range req.Set {
+		if err := setXattr(req.Filename, x.Name, x.Value); err != nil {
+			return nil, status.Errorf(codes.Internal, "can't set xattr %s of %s: %v", x.Name, req.Filename, err)
+		}
+	}
+	for _, n := range req.Remove {
+		if err := removeXattr(req.Filename, n); err != nil {
+			return nil, status.Errorf(codes.Internal, "can't remove xattr %s of %s: %v", n, req.Filename, err)
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,81 @@
+//go:build linux || darwin
+// +build linux darwin
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// sizedRead calls f with a buffer large enough for the result, as
+// reported by calling it with a nil buffer first. The size is rechecked if
+// it grows in between.
+func sizedRead(f func([]byte) (int, error)) ([]byte, error) {
+	for {
+		sz, err := f(nil)
+		if err != nil {
+			return nil, err
+		}
+		if sz == 0 {
+			return nil, nil
+		}
+		buf := make([]byte, sz)
+		sz, err = f(buf)
+		if errors.Is(err, unix.ERANGE) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return buf[:sz], nil
+	}
+}
+
+// listXattrs returns the names of all extended attributes of path.
+func listXattrs(path string) ([]string, error) {
+	buf, err := sizedRead(func(b []byte) (int, error) { return unix.Listxattr(path, b) })
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, n := range bytes.Split(buf, []byte{0}) {
+		if len(n) > 0 {
+			names = append(names, string(n))
+		}
+	}
+	return names, nil
+}
+
+// getXattr returns the value of the extended attribute name of path.
+func getXattr(path string, name string) ([]byte, error) {
+	return sizedRead(func(b []byte) (int, error) { return unix.Getxattr(path, name, b) })
+}
+
+// setXattr creates or replaces the extended attribute name of path.
+func setXattr(path string, name string, value []byte) error {
+	return unix.Setxattr(path, name, value, 0)
+}
+
+// removeXattr removes the extended attribute name of path.
+func removeXattr(path string, name string) error {
+	return unix.Removexattr(path, name)
+}
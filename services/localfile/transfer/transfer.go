@@ -0,0 +1,236 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package transfer implements the LocalFile.Transfer service, which runs on
+// the proxy and copies files between targets by piping a LocalFile.Read on
+// one into a LocalFile.Write on another.
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	proxypb "github.com/Snowflake-Labs/sansshell/proxy"
+	"github.com/Snowflake-Labs/sansshell/proxy/server"
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const (
+	readMethod  = "/LocalFile.LocalFile/Read"
+	writeMethod = "/LocalFile.LocalFile/Write"
+
+	// Nonces for the two streams of a copy.
+	sourceNonce      = 1
+	destinationNonce = 2
+)
+
+// Server implements pb.TransferServer
+type Server struct {
+	proxy *server.Server
+}
+
+// New returns a Server opening target streams with `proxy`, so they're
+// authorized (and sent hints or delegation tokens) as if the caller had
+// made them through it.
+func New(proxy *server.Server) *Server {
+	return &Server{proxy: proxy}
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *Server) Register(gs *grpc.Server) {
+	pb.RegisterTransferServer(gs, s)
+}
+
+// closeError returns the error a ServerClose status represents, if any.
+func closeError(s *proxypb.Status, format string, args ...interface{}) error {
+	if s == nil || codes.Code(s.Code) == codes.OK {
+		return nil
+	}
+	args = append(args, s.Message)
+	return status.Errorf(codes.Code(s.Code), format+": %s", args...)
+}
+
+// CopyFile implements pb.TransferServer.CopyFile
+func (s *Server) CopyFile(ctx context.Context, req *pb.TransferRequest) (*pb.TransferReply, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	if req.SourceTarget == "" || req.DestinationTarget == "" {
+		return nil, status.Error(codes.InvalidArgument, "source_target and destination_target must be set")
+	}
+	if err := util.ValidPath(req.Source); err != nil {
+		return nil, err
+	}
+	if req.Destination == nil {
+		return nil, status.Error(codes.InvalidArgument, "destination must be set")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	streamSet := s.proxy.NewTargetStreamSet()
+	// Each stream sends its StartStreamReply, and possibly a ServerClose if it
+	// fails straight away, before we send it anything. Buffering those means
+	// adding the streams can't block waiting for us to read replies.
+	replyChan := make(chan *proxypb.ProxyReply, 4)
+	doneChan := make(chan uint64, 2)
+	defer func() {
+		// Streams block sending replies, so read them until all have exited.
+		cancel()
+		go func() {
+			streamSet.Wait()
+			close(replyChan)
+		}()
+		for range replyChan {
+		}
+	}()
+
+	for _, start := range []*proxypb.StartStream{
+		{Target: req.SourceTarget, MethodName: readMethod, Nonce: sourceNonce},
+		{Target: req.DestinationTarget, MethodName: writeMethod, Nonce: destinationNonce},
+	} {
+		if err := streamSet.Add(ctx, start, replyChan, doneChan); err != nil {
+			return nil, err
+		}
+	}
+
+	// Wait for both streams to start, keeping anything else which arrives
+	// meanwhile to handle afterwards.
+	var sourceID, destinationID uint64
+	var pending []*proxypb.ProxyReply
+	for started := 0; started < 2; {
+		var reply *proxypb.ProxyReply
+		select {
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		case reply = <-replyChan:
+		}
+		r := reply.GetStartStreamReply()
+		if r == nil {
+			pending = append(pending, reply)
+			continue
+		}
+		if err := closeError(r.GetErrorStatus(), "can't start stream to %s", r.Target); err != nil {
+			return nil, err
+		}
+		if r.Nonce == sourceNonce {
+			sourceID = r.GetStreamId()
+		} else {
+			destinationID = r.GetStreamId()
+		}
+		started++
+	}
+
+	send := func(id uint64, msg proto.Message) error {
+		payload, err := anypb.New(msg)
+		if err != nil {
+			return status.Errorf(codes.Internal, "can't marshal request: %v", err)
+		}
+		return streamSet.Send(ctx, &proxypb.StreamData{StreamIds: []uint64{id}, Payload: payload})
+	}
+
+	// Describe the destination first so the write is authorized before
+	// anything is read.
+	if err := send(destinationID, &pb.WriteRequest{Request: &pb.WriteRequest_Description{Description: req.Destination}}); err != nil {
+		return nil, err
+	}
+	if err := send(sourceID, &pb.ReadActionRequest{Request: &pb.ReadActionRequest_File{File: &pb.ReadRequest{Filename: req.Source}}}); err != nil {
+		return nil, err
+	}
+	if err := streamSet.ClientClose(&proxypb.ClientClose{StreamIds: []uint64{sourceID}}); err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	var written int64
+	var sourceDone bool
+	// If sending to the destination fails its ServerClose has the reason,
+	// but if not this is returned.
+	var sendErr error
+	for {
+		var reply *proxypb.ProxyReply
+		if len(pending) > 0 {
+			reply, pending = pending[0], pending[1:]
+		} else {
+			select {
+			case <-ctx.Done():
+				return nil, status.FromContextError(ctx.Err()).Err()
+			case reply = <-replyChan:
+			}
+		}
+
+		if d := reply.GetStreamData(); d != nil {
+			// Write has a single (empty) reply so only the source's
+			// data matters.
+			if len(d.StreamIds) != 1 || d.StreamIds[0] != sourceID || sendErr != nil {
+				continue
+			}
+			var data pb.ReadReply
+			if err := d.Payload.UnmarshalTo(&data); err != nil {
+				return nil, status.Errorf(codes.Internal, "can't unmarshal reply from %s: %v", req.SourceTarget, err)
+			}
+			if err := send(destinationID, &pb.WriteRequest{Request: &pb.WriteRequest_Contents{Contents: data.Contents}}); err != nil {
+				sendErr = err
+				streamSet.ClientCancel(&proxypb.ClientCancel{StreamIds: []uint64{sourceID}})
+				continue
+			}
+			hash.Write(data.Contents)
+			written += int64(len(data.Contents))
+			continue
+		}
+
+		cl := reply.GetServerClose()
+		if cl == nil {
+			continue
+		}
+		for _, id := range cl.StreamIds {
+			switch id {
+			case sourceID:
+				if sendErr != nil {
+					continue
+				}
+				if err := closeError(cl.Status, "reading %s on %s", req.Source, req.SourceTarget); err != nil {
+					return nil, err
+				}
+				sourceDone = true
+				// Everything has been sent so the write can complete.
+				if err := streamSet.ClientClose(&proxypb.ClientClose{StreamIds: []uint64{destinationID}}); err != nil {
+					return nil, err
+				}
+			case destinationID:
+				if err := closeError(cl.Status, "writing %s on %s", req.Destination.GetAttrs().GetFilename(), req.DestinationTarget); err != nil {
+					return nil, err
+				}
+				if sendErr != nil {
+					return nil, sendErr
+				}
+				if !sourceDone {
+					return nil, status.Errorf(codes.Internal, "write on %s finished before the read on %s", req.DestinationTarget, req.SourceTarget)
+				}
+				logger.Info("copied file", "source", req.SourceTarget+":"+req.Source, "destination", req.DestinationTarget+":"+req.Destination.GetAttrs().GetFilename(), "bytes", written)
+				return &pb.TransferReply{
+					Bytes:  written,
+					Sha256: hex.EncodeToString(hash.Sum(nil)),
+				}, nil
+			}
+		}
+	}
+}
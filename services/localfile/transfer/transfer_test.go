@@ -0,0 +1,262 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Snowflake-Labs/sansshell/proxy/server"
+	proxytestutil "github.com/Snowflake-Labs/sansshell/proxy/testutil"
+	pb "github.com/Snowflake-Labs/sansshell/services/localfile"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// fakeFiles is a LocalFile server with files in memory.
+type fakeFiles struct {
+	pb.UnimplementedLocalFileServer
+
+	mu     sync.Mutex
+	files  map[string][]byte
+	writes []*pb.FileWrite
+}
+
+func (f *fakeFiles) Read(req *pb.ReadActionRequest, stream pb.LocalFile_ReadServer) error {
+	f.mu.Lock()
+	contents, ok := f.files[req.GetFile().GetFilename()]
+	f.mu.Unlock()
+	if !ok {
+		return status.Errorf(codes.NotFound, "%s not found", req.GetFile().GetFilename())
+	}
+	// Send in small chunks so copies take several messages.
+	for i := 0; i < len(contents); i += 3 {
+		end := i + 3
+		if end > len(contents) {
+			end = len(contents)
+		}
+		if err := stream.Send(&pb.ReadReply{Contents: contents[i:end], Offset: int64(end)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeFiles) Write(stream pb.LocalFile_WriteServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	desc := req.GetDescription()
+	if desc == nil {
+		return status.Error(codes.InvalidArgument, "must send description first")
+	}
+	var contents []byte
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		contents = append(contents, req.GetContents()...)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[desc.GetAttrs().GetFilename()] = contents
+	f.writes = append(f.writes, desc)
+	return stream.SendAndClose(&emptypb.Empty{})
+}
+
+func startFakeFiles(t *testing.T, files map[string][]byte) (*fakeFiles, *bufconn.Listener) {
+	t.Helper()
+	f := &fakeFiles{files: files}
+	lis := bufconn.Listen(proxytestutil.BufSize)
+	s := grpc.NewServer()
+	pb.RegisterLocalFileServer(s, f)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return f, lis
+}
+
+func TestCopyFile(t *testing.T) {
+	ctx := context.Background()
+	contents := []byte("some file contents\n")
+	sum := sha256.Sum256(contents)
+
+	for _, tc := range []struct {
+		name    string
+		policy  string
+		req     *pb.TransferRequest
+		wantErr codes.Code
+		want    *pb.TransferReply
+	}{
+		{
+			name: "copy",
+			req: &pb.TransferRequest{
+				SourceTarget:      "a",
+				Source:            "/src",
+				DestinationTarget: "b",
+				Destination:       &pb.FileWrite{Attrs: &pb.FileAttributes{Filename: "/dst"}},
+			},
+			want: &pb.TransferReply{Bytes: int64(len(contents)), Sha256: hex.EncodeToString(sum[:])},
+		},
+		{
+			name: "empty file",
+			req: &pb.TransferRequest{
+				SourceTarget:      "a",
+				Source:            "/empty",
+				DestinationTarget: "b",
+				Destination:       &pb.FileWrite{Attrs: &pb.FileAttributes{Filename: "/dst"}},
+			},
+			want: &pb.TransferReply{Sha256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		},
+		{
+			name: "missing source",
+			req: &pb.TransferRequest{
+				SourceTarget:      "a",
+				Source:            "/missing",
+				DestinationTarget: "b",
+				Destination:       &pb.FileWrite{Attrs: &pb.FileAttributes{Filename: "/dst"}},
+			},
+			wantErr: codes.NotFound,
+		},
+		{
+			name: "write denied",
+			policy: `
+package sansshell.authz
+default allow = false
+allow {
+  input.method != "/LocalFile.LocalFile/Write"
+}
+`,
+			req: &pb.TransferRequest{
+				SourceTarget:      "a",
+				Source:            "/src",
+				DestinationTarget: "b",
+				Destination:       &pb.FileWrite{Attrs: &pb.FileAttributes{Filename: "/dst"}},
+			},
+			wantErr: codes.PermissionDenied,
+		},
+		{
+			name: "read denied",
+			policy: `
+package sansshell.authz
+default allow = false
+allow {
+  input.method != "/LocalFile.LocalFile/Read"
+}
+`,
+			req: &pb.TransferRequest{
+				SourceTarget:      "a",
+				Source:            "/src",
+				DestinationTarget: "b",
+				Destination:       &pb.FileWrite{Attrs: &pb.FileAttributes{Filename: "/dst"}},
+			},
+			wantErr: codes.PermissionDenied,
+		},
+		{
+			name: "no destination target",
+			req: &pb.TransferRequest{
+				SourceTarget: "a",
+				Source:       "/src",
+				Destination:  &pb.FileWrite{Attrs: &pb.FileAttributes{Filename: "/dst"}},
+			},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name: "relative source",
+			req: &pb.TransferRequest{
+				SourceTarget:      "a",
+				Source:            "src",
+				DestinationTarget: "b",
+				Destination:       &pb.FileWrite{Attrs: &pb.FileAttributes{Filename: "/dst"}},
+			},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name: "no destination",
+			req: &pb.TransferRequest{
+				SourceTarget:      "a",
+				Source:            "/src",
+				DestinationTarget: "b",
+			},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, srcLis := startFakeFiles(t, map[string][]byte{"/src": contents, "/empty": nil})
+			dst, dstLis := startFakeFiles(t, map[string][]byte{})
+			targets := map[string]*bufconn.Listener{"a": srcLis, "b": dstLis}
+
+			authz := proxytestutil.NewAllowAllRPCAuthorizer(ctx, t)
+			if tc.policy != "" {
+				authz = proxytestutil.NewRPCAuthorizer(ctx, t, tc.policy)
+			}
+			dialer := server.NewDialer(proxytestutil.WithBufDialer(targets), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			lis := bufconn.Listen(proxytestutil.BufSize)
+			s := grpc.NewServer(grpc.UnaryInterceptor(authz.Authorize))
+			New(server.New(dialer, authz)).Register(s)
+			go s.Serve(lis)
+			t.Cleanup(s.Stop)
+
+			conn, err := grpc.DialContext(ctx, "proxy", grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+				return lis.Dial()
+			}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			testutil.FatalOnErr("DialContext", err, t)
+			t.Cleanup(func() { conn.Close() })
+
+			resp, err := pb.NewTransferClient(conn).CopyFile(ctx, tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("unexpected error. got %v want %v: %v", got, tc.wantErr, err)
+			}
+			dst.mu.Lock()
+			defer dst.mu.Unlock()
+			if err != nil {
+				if len(dst.writes) != 0 {
+					t.Fatalf("destination was written after error: %v", dst.writes)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want, resp, protocmp.Transform()); diff != "" {
+				t.Fatalf("unexpected reply (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff([]*pb.FileWrite{tc.req.Destination}, dst.writes, protocmp.Transform()); diff != "" {
+				t.Fatalf("unexpected writes (-want, +got):\n%s", diff)
+			}
+			if got, want := string(dst.files["/dst"]), string(contents[:tc.want.Bytes]); got != want {
+				t.Fatalf("destination contents: got %q want %q", got, want)
+			}
+		})
+	}
+}
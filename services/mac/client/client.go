@@ -0,0 +1,258 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'mac'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/mac"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "mac"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&apparmorCmd{}, "")
+	c.Register(&denialsCmd{}, "")
+	c.Register(&selinuxCmd{}, "")
+	c.Register(&setenforceCmd{}, "")
+	return c
+}
+
+// modeName returns the SELinux mode as sestatus prints it.
+func modeName(m pb.SELinuxMode) string {
+	return strings.ToLower(strings.TrimPrefix(m.String(), "SELINUX_MODE_"))
+}
+
+func emitRPCError(state *util.ExecuteState, cmd string, err error) {
+	// Emit this to every error file as it's not specific to a given target.
+	for _, e := range state.Err {
+		fmt.Fprintf(e, "error executing '%s': %v\n", cmd, err)
+	}
+}
+
+type selinuxCmd struct{}
+
+func (*selinuxCmd) Name() string     { return "selinux" }
+func (*selinuxCmd) Synopsis() string { return "Print the SELinux status" }
+func (*selinuxCmd) Usage() string {
+	return `selinux:
+    Print the current and configured SELinux modes and the loaded policy,
+    as sestatus does.
+`
+}
+
+func (*selinuxCmd) SetFlags(f *flag.FlagSet) {}
+
+func (s *selinuxCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewMACClientProxy(state.Conn)
+	respChan, err := c.SELinuxStatusOneMany(ctx, &pb.SELinuxStatusRequest{})
+	if err != nil {
+		emitRPCError(state, "selinux", err)
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		r, out := resp.Resp, state.Out[resp.Index]
+		fmt.Fprintf(out, "Current mode: %s\n", modeName(r.Mode))
+		fmt.Fprintf(out, "Mode from config file: %s\n", modeName(r.ConfigMode))
+		fmt.Fprintf(out, "Loaded policy name: %s\n", r.PolicyName)
+		fmt.Fprintf(out, "Policy version: %d\n", r.PolicyVersion)
+		fmt.Fprintf(out, "Max kernel policy version: %d\n", r.MaxKernelPolicyVersion)
+		fmt.Fprintf(out, "Policy MLS status: %t\n", r.Mls)
+	}
+	return retCode
+}
+
+type denialsCmd struct {
+	since string
+	limit uint
+}
+
+func (*denialsCmd) Name() string     { return "denials" }
+func (*denialsCmd) Synopsis() string { return "Print recent SELinux AVC denials" }
+func (*denialsCmd) Usage() string {
+	return `denials [--since <time>] [--limit <n>]:
+    Print recent SELinux denials from the audit log, most recent first.
+    Times are in RFC3339 format or a duration before now (i.e. 24h).
+`
+}
+
+func (d *denialsCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&d.since, "since", "", "If set only print denials since this time")
+	f.UintVar(&d.limit, "limit", 0, "Print at most this many denials. If unset the remote side picks (100)")
+}
+
+// parseTime parses an RFC3339 time or a duration before now.
+func parseTime(val string) (*timestamppb.Timestamp, error) {
+	if val == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(val); err == nil {
+		return timestamppb.New(time.Now().Add(-d)), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return nil, fmt.Errorf("%s isn't an RFC3339 time or a duration", val)
+	}
+	return timestamppb.New(t), nil
+}
+
+func (d *denialsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	since, err := parseTime(d.since)
+	if err != nil {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, err)
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, d)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewMACClientProxy(state.Conn)
+	respChan, err := c.AVCDenialsOneMany(ctx, &pb.AVCDenialsRequest{Since: since, Limit: uint32(d.limit)})
+	if err != nil {
+		emitRPCError(state, "denials", err)
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, a := range resp.Resp.Denials {
+			line := fmt.Sprintf("%s denied { %s } pid=%d comm=%q name=%q scontext=%s tcontext=%s tclass=%s",
+				a.Time.AsTime().Local().Format(time.RFC3339), strings.Join(a.Permissions, " "), a.Pid, a.Comm, a.Name, a.Scontext, a.Tcontext, a.Tclass)
+			if a.Permissive {
+				line += " (permissive)"
+			}
+			fmt.Fprintln(state.Out[resp.Index], line)
+		}
+	}
+	return retCode
+}
+
+type setenforceCmd struct{}
+
+func (*setenforceCmd) Name() string     { return "setenforce" }
+func (*setenforceCmd) Synopsis() string { return "Switch SELinux between enforcing and permissive" }
+func (*setenforceCmd) Usage() string {
+	return `setenforce enforcing|permissive:
+    Set the SELinux mode, as setenforce does. 1 and 0 are also accepted.
+    The change doesn't persist across reboots.
+`
+}
+
+func (*setenforceCmd) SetFlags(f *flag.FlagSet) {}
+
+func (s *setenforceCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	var enforcing bool
+	switch strings.ToLower(f.Arg(0)) {
+	case "enforcing", "1":
+		enforcing = true
+	case "permissive", "0":
+	default:
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify enforcing or permissive.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, s)
+		return subcommands.ExitUsageError
+	}
+	if f.NArg() != 1 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify only a mode.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, s)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewMACClientProxy(state.Conn)
+	respChan, err := c.SetEnforceOneMany(ctx, &pb.SetEnforceRequest{Enforcing: enforcing})
+	if err != nil {
+		emitRPCError(state, "setenforce", err)
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[resp.Index], "%s (was %s)\n", modeName(resp.Resp.Mode), modeName(resp.Resp.OldMode))
+	}
+	return retCode
+}
+
+type apparmorCmd struct{}
+
+func (*apparmorCmd) Name() string     { return "apparmor" }
+func (*apparmorCmd) Synopsis() string { return "Print the loaded AppArmor profiles" }
+func (*apparmorCmd) Usage() string {
+	return `apparmor:
+    Print each loaded AppArmor profile and its mode, as aa-status does.
+`
+}
+
+func (*apparmorCmd) SetFlags(f *flag.FlagSet) {}
+
+func (a *apparmorCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewMACClientProxy(state.Conn)
+	respChan, err := c.AppArmorStatusOneMany(ctx, &pb.AppArmorStatusRequest{})
+	if err != nil {
+		emitRPCError(state, "apparmor", err)
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		if !resp.Resp.Enabled {
+			fmt.Fprintln(state.Out[resp.Index], "apparmor is disabled")
+			continue
+		}
+		for _, p := range resp.Resp.Profiles {
+			fmt.Fprintf(state.Out[resp.Index], "%s (%s)\n", p.Name, p.Mode)
+		}
+	}
+	return retCode
+}
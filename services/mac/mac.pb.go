@@ -0,0 +1,1010 @@
+// Copyright (c) 2019 Snowflake Inc. All rights reserved.
+//
+//Licensed under the Apache License, Version 2.0 (the
+//"License"); you may not use this file except in compliance
+//with the License.  You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing,
+//software distributed under the License is distributed on an
+//"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+//KIND, either express or implied.  See the License for the
+//specific language governing permissions and limitations
+//under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: mac.proto
+
+package mac
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SELinuxMode int32
+
+const (
+	SELinuxMode_SELINUX_MODE_UNKNOWN    SELinuxMode = 0
+	SELinuxMode_SELINUX_MODE_DISABLED   SELinuxMode = 1
+	SELinuxMode_SELINUX_MODE_PERMISSIVE SELinuxMode = 2
+	SELinuxMode_SELINUX_MODE_ENFORCING  SELinuxMode = 3
+)
+
+// Enum value maps for SELinuxMode.
+var (
+	SELinuxMode_name = map[int32]string{
+		0: "SELINUX_MODE_UNKNOWN",
+		1: "SELINUX_MODE_DISABLED",
+		2: "SELINUX_MODE_PERMISSIVE",
+		3: "SELINUX_MODE_ENFORCING",
+	}
+	SELinuxMode_value = map[string]int32{
+		"SELINUX_MODE_UNKNOWN":    0,
+		"SELINUX_MODE_DISABLED":   1,
+		"SELINUX_MODE_PERMISSIVE": 2,
+		"SELINUX_MODE_ENFORCING":  3,
+	}
+)
+
+func (x SELinuxMode) Enum() *SELinuxMode {
+	p := new(SELinuxMode)
+	*p = x
+	return p
+}
+
+func (x SELinuxMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SELinuxMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_mac_proto_enumTypes[0].Descriptor()
+}
+
+func (SELinuxMode) Type() protoreflect.EnumType {
+	return &file_mac_proto_enumTypes[0]
+}
+
+func (x SELinuxMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SELinuxMode.Descriptor instead.
+func (SELinuxMode) EnumDescriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{0}
+}
+
+type SELinuxStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SELinuxStatusRequest) Reset() {
+	*x = SELinuxStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mac_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SELinuxStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SELinuxStatusRequest) ProtoMessage() {}
+
+func (x *SELinuxStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mac_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SELinuxStatusRequest.ProtoReflect.Descriptor instead.
+func (*SELinuxStatusRequest) Descriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{0}
+}
+
+type SELinuxStatusReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// DISABLED if SELinux isn't enabled in the kernel.
+	Mode SELinuxMode `protobuf:"varint,1,opt,name=mode,proto3,enum=MAC.SELinuxMode" json:"mode,omitempty"`
+	// The mode from /etc/selinux/config which applies at boot, or UNKNOWN if
+	// there's no config.
+	ConfigMode SELinuxMode `protobuf:"varint,2,opt,name=config_mode,json=configMode,proto3,enum=MAC.SELinuxMode" json:"config_mode,omitempty"`
+	// The configured policy, i.e. targeted.
+	PolicyName string `protobuf:"bytes,3,opt,name=policy_name,json=policyName,proto3" json:"policy_name,omitempty"`
+	// The version of the newest policy file for policy_name, which is the
+	// one loaded.
+	PolicyVersion int32 `protobuf:"varint,4,opt,name=policy_version,json=policyVersion,proto3" json:"policy_version,omitempty"`
+	// The newest policy version the kernel supports.
+	MaxKernelPolicyVersion int32 `protobuf:"varint,5,opt,name=max_kernel_policy_version,json=maxKernelPolicyVersion,proto3" json:"max_kernel_policy_version,omitempty"`
+	// True if the policy uses multi-level security.
+	Mls bool `protobuf:"varint,6,opt,name=mls,proto3" json:"mls,omitempty"`
+}
+
+func (x *SELinuxStatusReply) Reset() {
+	*x = SELinuxStatusReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mac_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SELinuxStatusReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SELinuxStatusReply) ProtoMessage() {}
+
+func (x *SELinuxStatusReply) ProtoReflect() protoreflect.Message {
+	mi := &file_mac_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SELinuxStatusReply.ProtoReflect.Descriptor instead.
+func (*SELinuxStatusReply) Descriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SELinuxStatusReply) GetMode() SELinuxMode {
+	if x != nil {
+		return x.Mode
+	}
+	return SELinuxMode_SELINUX_MODE_UNKNOWN
+}
+
+func (x *SELinuxStatusReply) GetConfigMode() SELinuxMode {
+	if x != nil {
+		return x.ConfigMode
+	}
+	return SELinuxMode_SELINUX_MODE_UNKNOWN
+}
+
+func (x *SELinuxStatusReply) GetPolicyName() string {
+	if x != nil {
+		return x.PolicyName
+	}
+	return ""
+}
+
+func (x *SELinuxStatusReply) GetPolicyVersion() int32 {
+	if x != nil {
+		return x.PolicyVersion
+	}
+	return 0
+}
+
+func (x *SELinuxStatusReply) GetMaxKernelPolicyVersion() int32 {
+	if x != nil {
+		return x.MaxKernelPolicyVersion
+	}
+	return 0
+}
+
+func (x *SELinuxStatusReply) GetMls() bool {
+	if x != nil {
+		return x.Mls
+	}
+	return false
+}
+
+type AVCDenialsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set only denials after this time are returned.
+	Since *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	// The most to return, the most recent first. Defaults to 100.
+	Limit uint32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *AVCDenialsRequest) Reset() {
+	*x = AVCDenialsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mac_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AVCDenialsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AVCDenialsRequest) ProtoMessage() {}
+
+func (x *AVCDenialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mac_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AVCDenialsRequest.ProtoReflect.Descriptor instead.
+func (*AVCDenialsRequest) Descriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AVCDenialsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *AVCDenialsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type AVCDenial struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// The denied permissions, i.e. read and open.
+	Permissions []string `protobuf:"bytes,2,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	Pid         int64    `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	Comm        string   `protobuf:"bytes,4,opt,name=comm,proto3" json:"comm,omitempty"`
+	// The name or path of the object, if logged.
+	Name string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	// The source (process) and target (object) security contexts.
+	Scontext string `protobuf:"bytes,6,opt,name=scontext,proto3" json:"scontext,omitempty"`
+	Tcontext string `protobuf:"bytes,7,opt,name=tcontext,proto3" json:"tcontext,omitempty"`
+	// The object class, i.e. file.
+	Tclass string `protobuf:"bytes,8,opt,name=tclass,proto3" json:"tclass,omitempty"`
+	// True if the access was allowed anyway as the domain or host was
+	// permissive.
+	Permissive bool `protobuf:"varint,9,opt,name=permissive,proto3" json:"permissive,omitempty"`
+	// The log line as written by auditd.
+	Raw string `protobuf:"bytes,10,opt,name=raw,proto3" json:"raw,omitempty"`
+}
+
+func (x *AVCDenial) Reset() {
+	*x = AVCDenial{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mac_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AVCDenial) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AVCDenial) ProtoMessage() {}
+
+func (x *AVCDenial) ProtoReflect() protoreflect.Message {
+	mi := &file_mac_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AVCDenial.ProtoReflect.Descriptor instead.
+func (*AVCDenial) Descriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AVCDenial) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *AVCDenial) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+func (x *AVCDenial) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *AVCDenial) GetComm() string {
+	if x != nil {
+		return x.Comm
+	}
+	return ""
+}
+
+func (x *AVCDenial) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AVCDenial) GetScontext() string {
+	if x != nil {
+		return x.Scontext
+	}
+	return ""
+}
+
+func (x *AVCDenial) GetTcontext() string {
+	if x != nil {
+		return x.Tcontext
+	}
+	return ""
+}
+
+func (x *AVCDenial) GetTclass() string {
+	if x != nil {
+		return x.Tclass
+	}
+	return ""
+}
+
+func (x *AVCDenial) GetPermissive() bool {
+	if x != nil {
+		return x.Permissive
+	}
+	return false
+}
+
+func (x *AVCDenial) GetRaw() string {
+	if x != nil {
+		return x.Raw
+	}
+	return ""
+}
+
+type AVCDenialsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Denials []*AVCDenial `protobuf:"bytes,1,rep,name=denials,proto3" json:"denials,omitempty"`
+}
+
+func (x *AVCDenialsReply) Reset() {
+	*x = AVCDenialsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mac_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AVCDenialsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AVCDenialsReply) ProtoMessage() {}
+
+func (x *AVCDenialsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_mac_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AVCDenialsReply.ProtoReflect.Descriptor instead.
+func (*AVCDenialsReply) Descriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AVCDenialsReply) GetDenials() []*AVCDenial {
+	if x != nil {
+		return x.Denials
+	}
+	return nil
+}
+
+type SetEnforceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// True for enforcing, false for permissive.
+	Enforcing bool `protobuf:"varint,1,opt,name=enforcing,proto3" json:"enforcing,omitempty"`
+}
+
+func (x *SetEnforceRequest) Reset() {
+	*x = SetEnforceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mac_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetEnforceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEnforceRequest) ProtoMessage() {}
+
+func (x *SetEnforceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mac_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEnforceRequest.ProtoReflect.Descriptor instead.
+func (*SetEnforceRequest) Descriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetEnforceRequest) GetEnforcing() bool {
+	if x != nil {
+		return x.Enforcing
+	}
+	return false
+}
+
+type SetEnforceReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OldMode SELinuxMode `protobuf:"varint,1,opt,name=old_mode,json=oldMode,proto3,enum=MAC.SELinuxMode" json:"old_mode,omitempty"`
+	Mode    SELinuxMode `protobuf:"varint,2,opt,name=mode,proto3,enum=MAC.SELinuxMode" json:"mode,omitempty"`
+}
+
+func (x *SetEnforceReply) Reset() {
+	*x = SetEnforceReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mac_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetEnforceReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEnforceReply) ProtoMessage() {}
+
+func (x *SetEnforceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_mac_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEnforceReply.ProtoReflect.Descriptor instead.
+func (*SetEnforceReply) Descriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetEnforceReply) GetOldMode() SELinuxMode {
+	if x != nil {
+		return x.OldMode
+	}
+	return SELinuxMode_SELINUX_MODE_UNKNOWN
+}
+
+func (x *SetEnforceReply) GetMode() SELinuxMode {
+	if x != nil {
+		return x.Mode
+	}
+	return SELinuxMode_SELINUX_MODE_UNKNOWN
+}
+
+type AppArmorStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AppArmorStatusRequest) Reset() {
+	*x = AppArmorStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mac_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppArmorStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppArmorStatusRequest) ProtoMessage() {}
+
+func (x *AppArmorStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mac_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppArmorStatusRequest.ProtoReflect.Descriptor instead.
+func (*AppArmorStatusRequest) Descriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{7}
+}
+
+type AppArmorProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// i.e. enforce, complain or kill.
+	Mode string `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+}
+
+func (x *AppArmorProfile) Reset() {
+	*x = AppArmorProfile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mac_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppArmorProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppArmorProfile) ProtoMessage() {}
+
+func (x *AppArmorProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_mac_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppArmorProfile.ProtoReflect.Descriptor instead.
+func (*AppArmorProfile) Descriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AppArmorProfile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AppArmorProfile) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+type AppArmorStatusReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// False if AppArmor isn't enabled in the kernel.
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Sorted by name.
+	Profiles []*AppArmorProfile `protobuf:"bytes,2,rep,name=profiles,proto3" json:"profiles,omitempty"`
+}
+
+func (x *AppArmorStatusReply) Reset() {
+	*x = AppArmorStatusReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mac_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppArmorStatusReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppArmorStatusReply) ProtoMessage() {}
+
+func (x *AppArmorStatusReply) ProtoReflect() protoreflect.Message {
+	mi := &file_mac_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppArmorStatusReply.ProtoReflect.Descriptor instead.
+func (*AppArmorStatusReply) Descriptor() ([]byte, []int) {
+	return file_mac_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AppArmorStatusReply) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *AppArmorStatusReply) GetProfiles() []*AppArmorProfile {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+var File_mac_proto protoreflect.FileDescriptor
+
+var file_mac_proto_rawDesc = []byte{
+	0x0a, 0x09, 0x6d, 0x61, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x4d, 0x41, 0x43,
+	0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x16, 0x0a, 0x14, 0x53, 0x45, 0x4c, 0x69, 0x6e, 0x75, 0x78, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x82, 0x02, 0x0a, 0x12, 0x53, 0x45,
+	0x4c, 0x69, 0x6e, 0x75, 0x78, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x24, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10,
+	0x2e, 0x4d, 0x41, 0x43, 0x2e, 0x53, 0x45, 0x4c, 0x69, 0x6e, 0x75, 0x78, 0x4d, 0x6f, 0x64, 0x65,
+	0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x31, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x4d, 0x41,
+	0x43, 0x2e, 0x53, 0x45, 0x4c, 0x69, 0x6e, 0x75, 0x78, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0a, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0d, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x39, 0x0a, 0x19, 0x6d, 0x61, 0x78, 0x5f, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x5f,
+	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x16, 0x6d, 0x61, 0x78, 0x4b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03,
+	0x6d, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x6d, 0x6c, 0x73, 0x22, 0x5b,
+	0x0a, 0x11, 0x41, 0x56, 0x43, 0x44, 0x65, 0x6e, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05,
+	0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x99, 0x02, 0x0a, 0x09,
+	0x41, 0x56, 0x43, 0x44, 0x65, 0x6e, 0x69, 0x61, 0x6c, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x65, 0x72,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b,
+	0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x70,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x6f, 0x6d, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x6d,
+	0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x74, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74,
+	0x63, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x76, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x70, 0x65, 0x72, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x76, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x72, 0x61, 0x77, 0x22, 0x3b, 0x0a, 0x0f, 0x41, 0x56, 0x43, 0x44, 0x65,
+	0x6e, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x28, 0x0a, 0x07, 0x64, 0x65,
+	0x6e, 0x69, 0x61, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x4d, 0x41,
+	0x43, 0x2e, 0x41, 0x56, 0x43, 0x44, 0x65, 0x6e, 0x69, 0x61, 0x6c, 0x52, 0x07, 0x64, 0x65, 0x6e,
+	0x69, 0x61, 0x6c, 0x73, 0x22, 0x31, 0x0a, 0x11, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x66, 0x6f, 0x72,
+	0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x6e, 0x66,
+	0x6f, 0x72, 0x63, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x65, 0x6e,
+	0x66, 0x6f, 0x72, 0x63, 0x69, 0x6e, 0x67, 0x22, 0x64, 0x0a, 0x0f, 0x53, 0x65, 0x74, 0x45, 0x6e,
+	0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x08, 0x6f, 0x6c,
+	0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x4d,
+	0x41, 0x43, 0x2e, 0x53, 0x45, 0x4c, 0x69, 0x6e, 0x75, 0x78, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x07,
+	0x6f, 0x6c, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x4d, 0x41, 0x43, 0x2e, 0x53, 0x45, 0x4c, 0x69,
+	0x6e, 0x75, 0x78, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0x17, 0x0a,
+	0x15, 0x41, 0x70, 0x70, 0x41, 0x72, 0x6d, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x39, 0x0a, 0x0f, 0x41, 0x70, 0x70, 0x41, 0x72, 0x6d,
+	0x6f, 0x72, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x22, 0x61, 0x0a, 0x13, 0x41, 0x70, 0x70, 0x41, 0x72, 0x6d, 0x6f, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x12, 0x30, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x4d, 0x41, 0x43, 0x2e, 0x41, 0x70, 0x70, 0x41, 0x72,
+	0x6d, 0x6f, 0x72, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x2a, 0x7b, 0x0a, 0x0b, 0x53, 0x45, 0x4c, 0x69, 0x6e, 0x75, 0x78, 0x4d,
+	0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x45, 0x4c, 0x49, 0x4e, 0x55, 0x58, 0x5f, 0x4d,
+	0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x19, 0x0a,
+	0x15, 0x53, 0x45, 0x4c, 0x49, 0x4e, 0x55, 0x58, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x44, 0x49,
+	0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x01, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x45, 0x4c, 0x49,
+	0x4e, 0x55, 0x58, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x50, 0x45, 0x52, 0x4d, 0x49, 0x53, 0x53,
+	0x49, 0x56, 0x45, 0x10, 0x02, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x45, 0x4c, 0x49, 0x4e, 0x55, 0x58,
+	0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x45, 0x4e, 0x46, 0x4f, 0x52, 0x43, 0x49, 0x4e, 0x47, 0x10,
+	0x03, 0x32, 0x92, 0x02, 0x0a, 0x03, 0x4d, 0x41, 0x43, 0x12, 0x45, 0x0a, 0x0d, 0x53, 0x45, 0x4c,
+	0x69, 0x6e, 0x75, 0x78, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x19, 0x2e, 0x4d, 0x41, 0x43,
+	0x2e, 0x53, 0x45, 0x4c, 0x69, 0x6e, 0x75, 0x78, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x4d, 0x41, 0x43, 0x2e, 0x53, 0x45, 0x4c, 0x69,
+	0x6e, 0x75, 0x78, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x3c, 0x0a, 0x0a, 0x41, 0x56, 0x43, 0x44, 0x65, 0x6e, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x16,
+	0x2e, 0x4d, 0x41, 0x43, 0x2e, 0x41, 0x56, 0x43, 0x44, 0x65, 0x6e, 0x69, 0x61, 0x6c, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x4d, 0x41, 0x43, 0x2e, 0x41, 0x56, 0x43,
+	0x44, 0x65, 0x6e, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3c,
+	0x0a, 0x0a, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x12, 0x16, 0x2e, 0x4d,
+	0x41, 0x43, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x4d, 0x41, 0x43, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x6e,
+	0x66, 0x6f, 0x72, 0x63, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0e,
+	0x41, 0x70, 0x70, 0x41, 0x72, 0x6d, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1a,
+	0x2e, 0x4d, 0x41, 0x43, 0x2e, 0x41, 0x70, 0x70, 0x41, 0x72, 0x6d, 0x6f, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x4d, 0x41, 0x43,
+	0x2e, 0x41, 0x70, 0x70, 0x41, 0x72, 0x6d, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x53, 0x6e, 0x6f, 0x77, 0x66, 0x6c, 0x61, 0x6b, 0x65, 0x2d, 0x4c,
+	0x61, 0x62, 0x73, 0x2f, 0x73, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x2f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2f, 0x6d, 0x61, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_mac_proto_rawDescOnce sync.Once
+	file_mac_proto_rawDescData = file_mac_proto_rawDesc
+)
+
+func file_mac_proto_rawDescGZIP() []byte {
+	file_mac_proto_rawDescOnce.Do(func() {
+		file_mac_proto_rawDescData = protoimpl.X.CompressGZIP(file_mac_proto_rawDescData)
+	})
+	return file_mac_proto_rawDescData
+}
+
+var file_mac_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_mac_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_mac_proto_goTypes = []interface{}{
+	(SELinuxMode)(0),              // 0: MAC.SELinuxMode
+	(*SELinuxStatusRequest)(nil),  // 1: MAC.SELinuxStatusRequest
+	(*SELinuxStatusReply)(nil),    // 2: MAC.SELinuxStatusReply
+	(*AVCDenialsRequest)(nil),     // 3: MAC.AVCDenialsRequest
+	(*AVCDenial)(nil),             // 4: MAC.AVCDenial
+	(*AVCDenialsReply)(nil),       // 5: MAC.AVCDenialsReply
+	(*SetEnforceRequest)(nil),     // 6: MAC.SetEnforceRequest
+	(*SetEnforceReply)(nil),       // 7: MAC.SetEnforceReply
+	(*AppArmorStatusRequest)(nil), // 8: MAC.AppArmorStatusRequest
+	(*AppArmorProfile)(nil),       // 9: MAC.AppArmorProfile
+	(*AppArmorStatusReply)(nil),   // 10: MAC.AppArmorStatusReply
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+}
+var file_mac_proto_depIdxs = []int32{
+	0,  // 0: MAC.SELinuxStatusReply.mode:type_name -> MAC.SELinuxMode
+	0,  // 1: MAC.SELinuxStatusReply.config_mode:type_name -> MAC.SELinuxMode
+	11, // 2: MAC.AVCDenialsRequest.since:type_name -> google.protobuf.Timestamp
+	11, // 3: MAC.AVCDenial.time:type_name -> google.protobuf.Timestamp
+	4,  // 4: MAC.AVCDenialsReply.denials:type_name -> MAC.AVCDenial
+	0,  // 5: MAC.SetEnforceReply.old_mode:type_name -> MAC.SELinuxMode
+	0,  // 6: MAC.SetEnforceReply.mode:type_name -> MAC.SELinuxMode
+	9,  // 7: MAC.AppArmorStatusReply.profiles:type_name -> MAC.AppArmorProfile
+	1,  // 8: MAC.MAC.SELinuxStatus:input_type -> MAC.SELinuxStatusRequest
+	3,  // 9: MAC.MAC.AVCDenials:input_type -> MAC.AVCDenialsRequest
+	6,  // 10: MAC.MAC.SetEnforce:input_type -> MAC.SetEnforceRequest
+	8,  // 11: MAC.MAC.AppArmorStatus:input_type -> MAC.AppArmorStatusRequest
+	2,  // 12: MAC.MAC.SELinuxStatus:output_type -> MAC.SELinuxStatusReply
+	5,  // 13: MAC.MAC.AVCDenials:output_type -> MAC.AVCDenialsReply
+	7,  // 14: MAC.MAC.SetEnforce:output_type -> MAC.SetEnforceReply
+	10, // 15: MAC.MAC.AppArmorStatus:output_type -> MAC.AppArmorStatusReply
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_mac_proto_init() }
+func file_mac_proto_init() {
+	if File_mac_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mac_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SELinuxStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mac_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SELinuxStatusReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mac_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AVCDenialsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mac_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AVCDenial); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mac_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AVCDenialsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mac_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetEnforceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mac_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetEnforceReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mac_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppArmorStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mac_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppArmorProfile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mac_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppArmorStatusReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mac_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_mac_proto_goTypes,
+		DependencyIndexes: file_mac_proto_depIdxs,
+		EnumInfos:         file_mac_proto_enumTypes,
+		MessageInfos:      file_mac_proto_msgTypes,
+	}.Build()
+	File_mac_proto = out.File
+	file_mac_proto_rawDesc = nil
+	file_mac_proto_goTypes = nil
+	file_mac_proto_depIdxs = nil
+}
@@ -0,0 +1,219 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package mac
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MACClient is the client API for MAC service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MACClient interface {
+	// SELinuxStatus returns the current and configured SELinux modes and
+	// the loaded policy.
+	SELinuxStatus(ctx context.Context, in *SELinuxStatusRequest, opts ...grpc.CallOption) (*SELinuxStatusReply, error)
+	// AVCDenials returns recent SELinux denials from the audit log.
+	AVCDenials(ctx context.Context, in *AVCDenialsRequest, opts ...grpc.CallOption) (*AVCDenialsReply, error)
+	// SetEnforce switches SELinux between enforcing and permissive, as
+	// setenforce(8) does. The change doesn't persist across reboots.
+	SetEnforce(ctx context.Context, in *SetEnforceRequest, opts ...grpc.CallOption) (*SetEnforceReply, error)
+	// AppArmorStatus returns the loaded AppArmor profiles and their modes.
+	AppArmorStatus(ctx context.Context, in *AppArmorStatusRequest, opts ...grpc.CallOption) (*AppArmorStatusReply, error)
+}
+
+type mACClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMACClient(cc grpc.ClientConnInterface) MACClient {
+	return &mACClient{cc}
+}
+
+func (c *mACClient) SELinuxStatus(ctx context.Context, in *SELinuxStatusRequest, opts ...grpc.CallOption) (*SELinuxStatusReply, error) {
+	out := new(SELinuxStatusReply)
+	err := c.cc.Invoke(ctx, "/MAC.MAC/SELinuxStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mACClient) AVCDenials(ctx context.Context, in *AVCDenialsRequest, opts ...grpc.CallOption) (*AVCDenialsReply, error) {
+	out := new(AVCDenialsReply)
+	err := c.cc.Invoke(ctx, "/MAC.MAC/AVCDenials", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mACClient) SetEnforce(ctx context.Context, in *SetEnforceRequest, opts ...grpc.CallOption) (*SetEnforceReply, error) {
+	out := new(SetEnforceReply)
+	err := c.cc.Invoke(ctx, "/MAC.MAC/SetEnforce", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mACClient) AppArmorStatus(ctx context.Context, in *AppArmorStatusRequest, opts ...grpc.CallOption) (*AppArmorStatusReply, error) {
+	out := new(AppArmorStatusReply)
+	err := c.cc.Invoke(ctx, "/MAC.MAC/AppArmorStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MACServer is the server API for MAC service.
+// All implementations should embed UnimplementedMACServer
+// for forward compatibility
+type MACServer interface {
+	// SELinuxStatus returns the current and configured SELinux modes and
+	// the loaded policy.
+	SELinuxStatus(context.Context, *SELinuxStatusRequest) (*SELinuxStatusReply, error)
+	// AVCDenials returns recent SELinux denials from the audit log.
+	AVCDenials(context.Context, *AVCDenialsRequest) (*AVCDenialsReply, error)
+	// SetEnforce switches SELinux between enforcing and permissive, as
+	// setenforce(8) does. The change doesn't persist across reboots.
+	SetEnforce(context.Context, *SetEnforceRequest) (*SetEnforceReply, error)
+	// AppArmorStatus returns the loaded AppArmor profiles and their modes.
+	AppArmorStatus(context.Context, *AppArmorStatusRequest) (*AppArmorStatusReply, error)
+}
+
+// UnimplementedMACServer should be embedded to have forward compatible implementations.
+type UnimplementedMACServer struct {
+}
+
+func (UnimplementedMACServer) SELinuxStatus(context.Context, *SELinuxStatusRequest) (*SELinuxStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SELinuxStatus not implemented")
+}
+func (UnimplementedMACServer) AVCDenials(context.Context, *AVCDenialsRequest) (*AVCDenialsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AVCDenials not implemented")
+}
+func (UnimplementedMACServer) SetEnforce(context.Context, *SetEnforceRequest) (*SetEnforceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEnforce not implemented")
+}
+func (UnimplementedMACServer) AppArmorStatus(context.Context, *AppArmorStatusRequest) (*AppArmorStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppArmorStatus not implemented")
+}
+
+// UnsafeMACServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MACServer will
+// result in compilation errors.
+type UnsafeMACServer interface {
+	mustEmbedUnimplementedMACServer()
+}
+
+func RegisterMACServer(s grpc.ServiceRegistrar, srv MACServer) {
+	s.RegisterService(&MAC_ServiceDesc, srv)
+}
+
+func _MAC_SELinuxStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SELinuxStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MACServer).SELinuxStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MAC.MAC/SELinuxStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MACServer).SELinuxStatus(ctx, req.(*SELinuxStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MAC_AVCDenials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AVCDenialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MACServer).AVCDenials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MAC.MAC/AVCDenials",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MACServer).AVCDenials(ctx, req.(*AVCDenialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MAC_SetEnforce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEnforceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MACServer).SetEnforce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MAC.MAC/SetEnforce",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MACServer).SetEnforce(ctx, req.(*SetEnforceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MAC_AppArmorStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppArmorStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MACServer).AppArmorStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MAC.MAC/AppArmorStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MACServer).AppArmorStatus(ctx, req.(*AppArmorStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MAC_ServiceDesc is the grpc.ServiceDesc for MAC service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MAC_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "MAC.MAC",
+	HandlerType: (*MACServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SELinuxStatus",
+			Handler:    _MAC_SELinuxStatus_Handler,
+		},
+		{
+			MethodName: "AVCDenials",
+			Handler:    _MAC_AVCDenials_Handler,
+		},
+		{
+			MethodName: "SetEnforce",
+			Handler:    _MAC_SetEnforce_Handler,
+		},
+		{
+			MethodName: "AppArmorStatus",
+			Handler:    _MAC_AppArmorStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mac.proto",
+}
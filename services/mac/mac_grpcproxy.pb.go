@@ -0,0 +1,304 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package mac
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// MACClientProxy is the superset of MACClient which additionally includes the OneMany proxy methods
+type MACClientProxy interface {
+	MACClient
+	SELinuxStatusOneMany(ctx context.Context, in *SELinuxStatusRequest, opts ...grpc.CallOption) (<-chan *SELinuxStatusManyResponse, error)
+	AVCDenialsOneMany(ctx context.Context, in *AVCDenialsRequest, opts ...grpc.CallOption) (<-chan *AVCDenialsManyResponse, error)
+	SetEnforceOneMany(ctx context.Context, in *SetEnforceRequest, opts ...grpc.CallOption) (<-chan *SetEnforceManyResponse, error)
+	AppArmorStatusOneMany(ctx context.Context, in *AppArmorStatusRequest, opts ...grpc.CallOption) (<-chan *AppArmorStatusManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type mACClientProxy struct {
+	*mACClient
+}
+
+// NewMACClientProxy creates a MACClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewMACClientProxy(cc *proxy.Conn) MACClientProxy {
+	return &mACClientProxy{NewMACClient(cc).(*mACClient)}
+}
+
+// SELinuxStatusManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type SELinuxStatusManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *SELinuxStatusReply
+	Error error
+}
+
+// SELinuxStatusOneMany provides the same API as SELinuxStatus but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *mACClientProxy) SELinuxStatusOneMany(ctx context.Context, in *SELinuxStatusRequest, opts ...grpc.CallOption) (<-chan *SELinuxStatusManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *SELinuxStatusManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &SELinuxStatusManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &SELinuxStatusReply{},
+			}
+			err := conn.Invoke(ctx, "/MAC.MAC/SELinuxStatus", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/MAC.MAC/SELinuxStatus", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &SELinuxStatusManyResponse{
+				Resp: &SELinuxStatusReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// AVCDenialsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type AVCDenialsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *AVCDenialsReply
+	Error error
+}
+
+// AVCDenialsOneMany provides the same API as AVCDenials but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *mACClientProxy) AVCDenialsOneMany(ctx context.Context, in *AVCDenialsRequest, opts ...grpc.CallOption) (<-chan *AVCDenialsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *AVCDenialsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &AVCDenialsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &AVCDenialsReply{},
+			}
+			err := conn.Invoke(ctx, "/MAC.MAC/AVCDenials", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/MAC.MAC/AVCDenials", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &AVCDenialsManyResponse{
+				Resp: &AVCDenialsReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// SetEnforceManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type SetEnforceManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *SetEnforceReply
+	Error error
+}
+
+// SetEnforceOneMany provides the same API as SetEnforce but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *mACClientProxy) SetEnforceOneMany(ctx context.Context, in *SetEnforceRequest, opts ...grpc.CallOption) (<-chan *SetEnforceManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *SetEnforceManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &SetEnforceManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &SetEnforceReply{},
+			}
+			err := conn.Invoke(ctx, "/MAC.MAC/SetEnforce", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/MAC.MAC/SetEnforce", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &SetEnforceManyResponse{
+				Resp: &SetEnforceReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// AppArmorStatusManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type AppArmorStatusManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *AppArmorStatusReply
+	Error error
+}
+
+// AppArmorStatusOneMany provides the same API as AppArmorStatus but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *mACClientProxy) AppArmorStatusOneMany(ctx context.Context, in *AppArmorStatusRequest, opts ...grpc.CallOption) (<-chan *AppArmorStatusManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *AppArmorStatusManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &AppArmorStatusManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &AppArmorStatusReply{},
+			}
+			err := conn.Invoke(ctx, "/MAC.MAC/AppArmorStatus", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/MAC.MAC/AppArmorStatus", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &AppArmorStatusManyResponse{
+				Resp: &AppArmorStatusReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
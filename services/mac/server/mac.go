@@ -0,0 +1,348 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'MAC' service.
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/mac"
+)
+
+const defaultDenialsLimit = 100
+
+var (
+	// auditTimeRE matches the timestamp of an audit record, i.e.
+	// msg=audit(1646128800.123:456):
+	auditTimeRE = regexp.MustCompile(`audit\((\d+)\.(\d+):\d+\)`)
+	// avcDeniedRE matches the denied permissions of an AVC record.
+	avcDeniedRE = regexp.MustCompile(`avc:\s+denied\s+\{([^}]*)\}`)
+	// auditFieldRE matches a key=value field of an audit record.
+	auditFieldRE = regexp.MustCompile(`(\w+)=("[^"]*"|\S+)`)
+	// policyFileRE matches versioned SELinux policy files.
+	policyFileRE = regexp.MustCompile(`^policy\.(\d+)$`)
+)
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// readValue returns the trimmed contents of path.
+func readValue(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// fileError converts an error accessing a MAC file into a status.
+func fileError(path string, err error) error {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return status.Errorf(codes.NotFound, "%s doesn't exist", path)
+	case errors.Is(err, os.ErrPermission):
+		return status.Errorf(codes.PermissionDenied, "can't access %s: %v", path, err)
+	}
+	return status.Errorf(codes.Internal, "can't access %s: %v", path, err)
+}
+
+// selinuxMode returns the current SELinux mode, which is disabled if
+// selinuxfs isn't mounted.
+func selinuxMode() (pb.SELinuxMode, error) {
+	path := filepath.Join(selinuxfsDir, "enforce")
+	v, err := readValue(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return pb.SELinuxMode_SELINUX_MODE_DISABLED, nil
+	}
+	if err != nil {
+		return pb.SELinuxMode_SELINUX_MODE_UNKNOWN, fileError(path, err)
+	}
+	if v == "1" {
+		return pb.SELinuxMode_SELINUX_MODE_ENFORCING, nil
+	}
+	return pb.SELinuxMode_SELINUX_MODE_PERMISSIVE, nil
+}
+
+// parseSELinuxConfig sets the configured mode and policy from the
+// contents of /etc/selinux/config.
+func parseSELinuxConfig(config string, reply *pb.SELinuxStatusReply) {
+	for _, l := range strings.Split(config, "\n") {
+		kv := strings.SplitN(strings.TrimSpace(l), "=", 2)
+		if len(kv) != 2 || strings.HasPrefix(kv[0], "#") {
+			continue
+		}
+		v := strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		switch strings.TrimSpace(kv[0]) {
+		case "SELINUX":
+			switch strings.ToLower(v) {
+			case "enforcing":
+				reply.ConfigMode = pb.SELinuxMode_SELINUX_MODE_ENFORCING
+			case "permissive":
+				reply.ConfigMode = pb.SELinuxMode_SELINUX_MODE_PERMISSIVE
+			case "disabled":
+				reply.ConfigMode = pb.SELinuxMode_SELINUX_MODE_DISABLED
+			}
+		case "SELINUXTYPE":
+			reply.PolicyName = v
+		}
+	}
+}
+
+// policyVersion returns the newest version of the named policy, which is
+// the one the kernel loads, or 0 if there are none.
+func policyVersion(name string) int32 {
+	entries, err := os.ReadDir(filepath.Join(selinuxConfigDir, name, "policy"))
+	if err != nil {
+		return 0
+	}
+	var newest int32
+	for _, e := range entries {
+		m := policyFileRE.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		if v, err := strconv.ParseInt(m[1], 10, 32); err == nil && int32(v) > newest {
+			newest = int32(v)
+		}
+	}
+	return newest
+}
+
+// SELinuxStatus implements pb.MACServer.SELinuxStatus
+func (s *server) SELinuxStatus(ctx context.Context, req *pb.SELinuxStatusRequest) (*pb.SELinuxStatusReply, error) {
+	if selinuxfsDir == "" {
+		return nil, status.Error(codes.Unimplemented, "SELinux is not supported on this platform")
+	}
+	mode, err := selinuxMode()
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.SELinuxStatusReply{Mode: mode}
+	configPath := filepath.Join(selinuxConfigDir, "config")
+	config, err := os.ReadFile(configPath)
+	switch {
+	case err == nil:
+		parseSELinuxConfig(string(config), reply)
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fileError(configPath, err)
+	}
+	if reply.PolicyName != "" {
+		reply.PolicyVersion = policyVersion(reply.PolicyName)
+	}
+	if mode == pb.SELinuxMode_SELINUX_MODE_DISABLED {
+		return reply, nil
+	}
+	if v, err := readValue(filepath.Join(selinuxfsDir, "policyvers")); err == nil {
+		if n, err := strconv.ParseInt(v, 10, 32); err == nil {
+			reply.MaxKernelPolicyVersion = int32(n)
+		}
+	}
+	if v, err := readValue(filepath.Join(selinuxfsDir, "mls")); err == nil {
+		reply.Mls = v == "1"
+	}
+	return reply, nil
+}
+
+// auditValue returns the value of an audit field with the quotes removed.
+// Unquoted values of fields which are normally quoted are hex encoded by
+// auditd as they contain spaces or unprintable characters.
+func auditValue(v string) string {
+	if strings.HasPrefix(v, `"`) {
+		return strings.Trim(v, `"`)
+	}
+	if b, err := hex.DecodeString(v); err == nil && len(v) > 0 {
+		return string(b)
+	}
+	return v
+}
+
+// parseAVCDenial parses an audit log line, returning nil if it isn't an
+// AVC denial.
+func parseAVCDenial(line string) *pb.AVCDenial {
+	perms := avcDeniedRE.FindStringSubmatchIndex(line)
+	if perms == nil {
+		return nil
+	}
+	d := &pb.AVCDenial{
+		Permissions: strings.Fields(line[perms[2]:perms[3]]),
+		Raw:         line,
+	}
+	if m := auditTimeRE.FindStringSubmatch(line); m != nil {
+		sec, _ := strconv.ParseInt(m[1], 10, 64)
+		ms, _ := strconv.ParseInt(m[2], 10, 64)
+		d.Time = timestamppb.New(time.Unix(sec, ms*int64(time.Millisecond)))
+	}
+	// Fields before the denial belong to the process logging it (i.e.
+	// dbus for USER_AVC), not the one denied.
+	for _, f := range auditFieldRE.FindAllStringSubmatch(line[perms[1]:], -1) {
+		switch f[1] {
+		case "pid":
+			d.Pid, _ = strconv.ParseInt(f[2], 10, 64)
+		case "comm":
+			d.Comm = auditValue(f[2])
+		case "name", "path":
+			d.Name = auditValue(f[2])
+		case "scontext":
+			d.Scontext = f[2]
+		case "tcontext":
+			d.Tcontext = f[2]
+		case "tclass":
+			d.Tclass = f[2]
+		case "permissive":
+			d.Permissive = f[2] == "1"
+		}
+	}
+	return d
+}
+
+// AVCDenials implements pb.MACServer.AVCDenials
+func (s *server) AVCDenials(ctx context.Context, req *pb.AVCDenialsRequest) (*pb.AVCDenialsReply, error) {
+	if *auditLog == "" {
+		return nil, status.Error(codes.Unimplemented, "AVC denials are not supported on this platform")
+	}
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultDenialsLimit
+	}
+	var since time.Time
+	if req.Since != nil {
+		since = req.Since.AsTime()
+	}
+	f, err := os.Open(*auditLog)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s doesn't exist, is auditd running?", *auditLog)
+		}
+		return nil, fileError(*auditLog, err)
+	}
+	defer f.Close()
+
+	// Keep the last limit denials as the log is oldest first.
+	var denials []*pb.AVCDenial
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		d := parseAVCDenial(scanner.Text())
+		if d == nil || (d.Time != nil && d.Time.AsTime().Before(since)) {
+			continue
+		}
+		denials = append(denials, d)
+		if len(denials) > limit {
+			denials = denials[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "reading %s: %v", *auditLog, err)
+	}
+	reply := &pb.AVCDenialsReply{}
+	for i := len(denials) - 1; i >= 0; i-- {
+		reply.Denials = append(reply.Denials, denials[i])
+	}
+	return reply, nil
+}
+
+// SetEnforce implements pb.MACServer.SetEnforce
+func (s *server) SetEnforce(ctx context.Context, req *pb.SetEnforceRequest) (*pb.SetEnforceReply, error) {
+	if selinuxfsDir == "" {
+		return nil, status.Error(codes.Unimplemented, "SELinux is not supported on this platform")
+	}
+	old, err := selinuxMode()
+	if err != nil {
+		return nil, err
+	}
+	if old == pb.SELinuxMode_SELINUX_MODE_DISABLED {
+		return nil, status.Error(codes.FailedPrecondition, "SELinux is disabled")
+	}
+	v, mode := "0", pb.SELinuxMode_SELINUX_MODE_PERMISSIVE
+	if req.Enforcing {
+		v, mode = "1", pb.SELinuxMode_SELINUX_MODE_ENFORCING
+	}
+	logr.FromContextOrDiscard(ctx).Info("setting SELinux mode", "old", old, "mode", mode)
+	path := filepath.Join(selinuxfsDir, "enforce")
+	if err := os.WriteFile(path, []byte(v), 0); err != nil {
+		return nil, fileError(path, err)
+	}
+	return &pb.SetEnforceReply{OldMode: old, Mode: mode}, nil
+}
+
+// parseAppArmorProfiles parses the securityfs profiles file, which has a
+// line per profile such as "/usr/sbin/cupsd (enforce)".
+func parseAppArmorProfiles(profiles string) []*pb.AppArmorProfile {
+	var out []*pb.AppArmorProfile
+	for _, l := range strings.Split(profiles, "\n") {
+		l = strings.TrimSpace(l)
+		i := strings.LastIndex(l, " (")
+		if i < 0 || !strings.HasSuffix(l, ")") {
+			continue
+		}
+		out = append(out, &pb.AppArmorProfile{
+			Name: l[:i],
+			Mode: l[i+2 : len(l)-1],
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// AppArmorStatus implements pb.MACServer.AppArmorStatus
+func (s *server) AppArmorStatus(ctx context.Context, req *pb.AppArmorStatusRequest) (*pb.AppArmorStatusReply, error) {
+	if apparmorDir == "" {
+		return nil, status.Error(codes.Unimplemented, "AppArmor is not supported on this platform")
+	}
+	enabled, err := readValue(apparmorEnabledFile)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && enabled != "Y") {
+		return &pb.AppArmorStatusReply{}, nil
+	}
+	if err != nil {
+		return nil, fileError(apparmorEnabledFile, err)
+	}
+	path := filepath.Join(apparmorDir, "profiles")
+	profiles, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fileError(path, err)
+	}
+	return &pb.AppArmorStatusReply{
+		Enabled:  true,
+		Profiles: parseAppArmorProfiles(string(profiles)),
+	}, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterMACServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
@@ -0,0 +1,34 @@
+//go:build !linux
+// +build !linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"flag"
+)
+
+var (
+	auditLog = flag.String("audit-log", "", "Path to the audit log MAC.AVCDenials reads (NOTE: no support on this platform)")
+
+	// SELinux and AppArmor are only supported on Linux.
+	selinuxfsDir        = ""
+	selinuxConfigDir    = ""
+	apparmorEnabledFile = ""
+	apparmorDir         = ""
+)
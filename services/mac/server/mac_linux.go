@@ -0,0 +1,37 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"flag"
+)
+
+var (
+	auditLog = flag.String("audit-log", "/var/log/audit/audit.log", "Path to the audit log MAC.AVCDenials reads")
+
+	// selinuxfsDir is where selinuxfs is mounted.
+	selinuxfsDir = "/sys/fs/selinux"
+	// selinuxConfigDir contains the SELinux config and policies.
+	selinuxConfigDir = "/etc/selinux"
+	// apparmorEnabledFile is Y if AppArmor is enabled.
+	apparmorEnabledFile = "/sys/module/apparmor/parameters/enabled"
+	// apparmorDir is the AppArmor securityfs directory.
+	apparmorDir = "/sys/kernel/security/apparmor"
+)
@@ -0,0 +1,235 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/mac"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// fakeFS points the server at empty directories standing in for
+// selinuxfs, /etc/selinux and securityfs, returning the root they're in.
+func fakeFS(t *testing.T) string {
+	t.Helper()
+	savedSelinuxfs, savedConfig, savedEnabled, savedApparmor := selinuxfsDir, selinuxConfigDir, apparmorEnabledFile, apparmorDir
+	t.Cleanup(func() {
+		selinuxfsDir, selinuxConfigDir, apparmorEnabledFile, apparmorDir = savedSelinuxfs, savedConfig, savedEnabled, savedApparmor
+	})
+	root := t.TempDir()
+	selinuxfsDir = filepath.Join(root, "selinuxfs")
+	selinuxConfigDir = filepath.Join(root, "etc")
+	apparmorEnabledFile = filepath.Join(root, "apparmor-enabled")
+	apparmorDir = filepath.Join(root, "apparmor")
+	for _, d := range []string{selinuxfsDir, selinuxConfigDir, apparmorDir} {
+		testutil.FatalOnErr("mkdir", os.MkdirAll(d, 0755), t)
+	}
+	return root
+}
+
+func writeFile(t *testing.T, path string, contents string) {
+	t.Helper()
+	testutil.FatalOnErr("mkdir", os.MkdirAll(filepath.Dir(path), 0755), t)
+	testutil.FatalOnErr("writing "+path, os.WriteFile(path, []byte(contents), 0644), t)
+}
+
+func TestSELinuxStatus(t *testing.T) {
+	fakeFS(t)
+	s := &server{}
+
+	got, err := s.SELinuxStatus(context.Background(), &pb.SELinuxStatusRequest{})
+	testutil.FatalOnErr("SELinuxStatus without selinux", err, t)
+	testutil.DiffErr("disabled", got, &pb.SELinuxStatusReply{Mode: pb.SELinuxMode_SELINUX_MODE_DISABLED}, t)
+
+	config, err := os.ReadFile("./testdata/selinux-config")
+	testutil.FatalOnErr("reading config", err, t)
+	writeFile(t, filepath.Join(selinuxConfigDir, "config"), string(config))
+	for _, v := range []string{"31", "33", "32"} {
+		writeFile(t, filepath.Join(selinuxConfigDir, "targeted", "policy", "policy."+v), "")
+	}
+	writeFile(t, filepath.Join(selinuxConfigDir, "targeted", "policy", "policy.33.bak"), "")
+	writeFile(t, filepath.Join(selinuxfsDir, "enforce"), "0")
+	writeFile(t, filepath.Join(selinuxfsDir, "policyvers"), "33\n")
+	writeFile(t, filepath.Join(selinuxfsDir, "mls"), "1")
+	got, err = s.SELinuxStatus(context.Background(), &pb.SELinuxStatusRequest{})
+	testutil.FatalOnErr("SELinuxStatus", err, t)
+	testutil.DiffErr("permissive", got, &pb.SELinuxStatusReply{
+		Mode:                   pb.SELinuxMode_SELINUX_MODE_PERMISSIVE,
+		ConfigMode:             pb.SELinuxMode_SELINUX_MODE_ENFORCING,
+		PolicyName:             "targeted",
+		PolicyVersion:          33,
+		MaxKernelPolicyVersion: 33,
+		Mls:                    true,
+	}, t)
+
+	selinuxfsDir = ""
+	_, err = s.SELinuxStatus(context.Background(), &pb.SELinuxStatusRequest{})
+	if got, want := status.Code(err), codes.Unimplemented; got != want {
+		t.Errorf("SELinuxStatus unsupported: got error %v, want code %v", err, want)
+	}
+}
+
+func TestSetEnforce(t *testing.T) {
+	fakeFS(t)
+	s := &server{}
+	_, err := s.SetEnforce(context.Background(), &pb.SetEnforceRequest{Enforcing: true})
+	if got, want := status.Code(err), codes.FailedPrecondition; got != want {
+		t.Errorf("SetEnforce while disabled: got error %v, want code %v", err, want)
+	}
+
+	enforce := filepath.Join(selinuxfsDir, "enforce")
+	writeFile(t, enforce, "0")
+	got, err := s.SetEnforce(context.Background(), &pb.SetEnforceRequest{Enforcing: true})
+	testutil.FatalOnErr("SetEnforce", err, t)
+	testutil.DiffErr("enforcing", got, &pb.SetEnforceReply{
+		OldMode: pb.SELinuxMode_SELINUX_MODE_PERMISSIVE,
+		Mode:    pb.SELinuxMode_SELINUX_MODE_ENFORCING,
+	}, t)
+	v, err := os.ReadFile(enforce)
+	testutil.FatalOnErr("reading enforce", err, t)
+	if string(v) != "1" {
+		t.Errorf("enforce is %q, want 1", v)
+	}
+
+	got, err = s.SetEnforce(context.Background(), &pb.SetEnforceRequest{})
+	testutil.FatalOnErr("SetEnforce", err, t)
+	testutil.DiffErr("permissive", got, &pb.SetEnforceReply{
+		OldMode: pb.SELinuxMode_SELINUX_MODE_ENFORCING,
+		Mode:    pb.SELinuxMode_SELINUX_MODE_PERMISSIVE,
+	}, t)
+}
+
+func TestAVCDenials(t *testing.T) {
+	savedAuditLog := *auditLog
+	t.Cleanup(func() { *auditLog = savedAuditLog })
+	*auditLog = "./testdata/audit.log"
+
+	httpd := &pb.AVCDenial{
+		Time:        timestamppb.New(time.Unix(1646128800, 123000000)),
+		Permissions: []string{"read"},
+		Pid:         1234,
+		Comm:        "httpd",
+		Name:        "index.html",
+		Scontext:    "system_u:system_r:httpd_t:s0",
+		Tcontext:    "unconfined_u:object_r:user_home_t:s0",
+		Tclass:      "file",
+		Raw:         `type=AVC msg=audit(1646128800.123:456): avc:  denied  { read } for  pid=1234 comm="httpd" name="index.html" dev="dm-0" ino=123 scontext=system_u:system_r:httpd_t:s0 tcontext=unconfined_u:object_r:user_home_t:s0 tclass=file permissive=0`,
+	}
+	dbus := &pb.AVCDenial{
+		Time:        timestamppb.New(time.Unix(1646129000, 250000000)),
+		Permissions: []string{"send_msg"},
+		Name:        "/org/freedesktop/DBus",
+		Scontext:    "system_u:system_r:sshd_t:s0",
+		Tcontext:    "system_u:system_r:system_dbusd_t:s0",
+		Tclass:      "dbus",
+		Permissive:  true,
+		Raw:         `type=USER_AVC msg=audit(1646129000.250:470): pid=700 uid=81 auid=4294967295 ses=4294967295 subj=system_u:system_r:system_dbusd_t:s0-s0:c0.c1023 msg='avc:  denied  { send_msg } for msgtype=method_call interface=org.freedesktop.DBus path=/org/freedesktop/DBus member=Hello dest=org.freedesktop.DBus spid=2000 scontext=system_u:system_r:sshd_t:s0 tcontext=system_u:system_r:system_dbusd_t:s0 tclass=dbus permissive=1  exe="/usr/bin/dbus-daemon" sauid=81 hostname=? addr=? terminal=?'UID="dbus" AUID="unset" SAUID="dbus"`,
+	}
+	cron := &pb.AVCDenial{
+		Time:        timestamppb.New(time.Unix(1646129100, 750000000)),
+		Permissions: []string{"write", "open"},
+		Pid:         4321,
+		Comm:        "my scrip",
+		Name:        "/tmp/a b.txt",
+		Scontext:    "system_u:system_r:cron_t:s0",
+		Tcontext:    "system_u:object_r:tmp_t:s0",
+		Tclass:      "file",
+		Permissive:  true,
+		Raw:         `type=AVC msg=audit(1646129100.750:480): avc:  denied  { write open } for  pid=4321 comm=6D79207363726970 path=2F746D702F6120622E747874 dev="tmpfs" ino=42 scontext=system_u:system_r:cron_t:s0 tcontext=system_u:object_r:tmp_t:s0 tclass=file permissive=1`,
+	}
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.AVCDenialsRequest
+		want    []*pb.AVCDenial
+		wantErr codes.Code
+	}{
+		{
+			name: "all",
+			req:  &pb.AVCDenialsRequest{},
+			want: []*pb.AVCDenial{cron, dbus, httpd},
+		},
+		{
+			name: "limit",
+			req:  &pb.AVCDenialsRequest{Limit: 2},
+			want: []*pb.AVCDenial{cron, dbus},
+		},
+		{
+			name: "since",
+			req:  &pb.AVCDenialsRequest{Since: timestamppb.New(time.Unix(1646128900, 0))},
+			want: []*pb.AVCDenial{cron, dbus},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{}
+			got, err := s.AVCDenials(context.Background(), tc.req)
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("AVCDenials: got error %v, want code %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, &pb.AVCDenialsReply{Denials: tc.want}, t)
+		})
+	}
+
+	*auditLog = filepath.Join(t.TempDir(), "audit.log")
+	s := &server{}
+	_, err := s.AVCDenials(context.Background(), &pb.AVCDenialsRequest{})
+	if got, want := status.Code(err), codes.FailedPrecondition; got != want {
+		t.Errorf("AVCDenials without a log: got error %v, want code %v", err, want)
+	}
+}
+
+func TestAppArmorStatus(t *testing.T) {
+	fakeFS(t)
+	s := &server{}
+	got, err := s.AppArmorStatus(context.Background(), &pb.AppArmorStatusRequest{})
+	testutil.FatalOnErr("AppArmorStatus without apparmor", err, t)
+	testutil.DiffErr("not loaded", got, &pb.AppArmorStatusReply{}, t)
+
+	writeFile(t, apparmorEnabledFile, "N\n")
+	got, err = s.AppArmorStatus(context.Background(), &pb.AppArmorStatusRequest{})
+	testutil.FatalOnErr("AppArmorStatus disabled", err, t)
+	testutil.DiffErr("disabled", got, &pb.AppArmorStatusReply{}, t)
+
+	writeFile(t, apparmorEnabledFile, "Y\n")
+	profiles, err := os.ReadFile("./testdata/profiles")
+	testutil.FatalOnErr("reading profiles", err, t)
+	writeFile(t, filepath.Join(apparmorDir, "profiles"), string(profiles))
+	got, err = s.AppArmorStatus(context.Background(), &pb.AppArmorStatusRequest{})
+	testutil.FatalOnErr("AppArmorStatus", err, t)
+	testutil.DiffErr("enabled", got, &pb.AppArmorStatusReply{
+		Enabled: true,
+		Profiles: []*pb.AppArmorProfile{
+			{Name: "/usr/bin/man", Mode: "complain"},
+			{Name: "/usr/lib/snapd/snap-confine//mount-namespace-capture-helper", Mode: "enforce"},
+			{Name: "/usr/sbin/cupsd", Mode: "enforce"},
+			{Name: "docker-default", Mode: "enforce"},
+		},
+	}, t)
+}
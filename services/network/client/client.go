@@ -0,0 +1,442 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'network'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/network"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "network"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&connectCmd{}, "")
+	c.Register(&dnsCmd{}, "")
+	c.Register(&pingCmd{}, "")
+	c.Register(&socketsCmd{}, "")
+	c.Register(&tracerouteCmd{}, "")
+	return c
+}
+
+func recordTypeString(t pb.RecordType) string {
+	return strings.TrimPrefix(t.String(), "RECORD_TYPE_")
+}
+
+func flagToRecordType(val string) (pb.RecordType, error) {
+	v := fmt.Sprintf("RECORD_TYPE_%s", strings.ToUpper(val))
+	i, ok := pb.RecordType_value[v]
+	if !ok || i == int32(pb.RecordType_RECORD_TYPE_UNKNOWN) {
+		return pb.RecordType_RECORD_TYPE_UNKNOWN, fmt.Errorf("no such record type %s", val)
+	}
+	return pb.RecordType(i), nil
+}
+
+type dnsCmd struct {
+	recordType string
+	server     string
+}
+
+func (*dnsCmd) Name() string     { return "dns" }
+func (*dnsCmd) Synopsis() string { return "Resolve a name from the remote host" }
+func (*dnsCmd) Usage() string {
+	return `dns [--type <type>] [--server <host[:port]>] <name>:
+    Resolve a name as the remote host sees it, using its resolver
+    configuration unless --server is given.
+`
+}
+
+func (d *dnsCmd) SetFlags(f *flag.FlagSet) {
+	var types []string
+	for k := range pb.RecordType_name {
+		if t := pb.RecordType(k); t != pb.RecordType_RECORD_TYPE_UNKNOWN {
+			types = append(types, strings.ToLower(recordTypeString(t)))
+		}
+	}
+	sort.Strings(types)
+	f.StringVar(&d.recordType, "type", "a", fmt.Sprintf("The record type to look up (one of: [%s])", strings.Join(types, ",")))
+	f.StringVar(&d.server, "server", "", "If set query this DNS server rather than the remote host's configured ones")
+}
+
+func (d *dnsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+	if f.NArg() != 1 {
+		fmt.Fprintln(errWriter, "Please specify a name to look up.")
+		subcommands.DefaultCommander.ExplainCommand(errWriter, d)
+		return subcommands.ExitUsageError
+	}
+	recordType, err := flagToRecordType(d.recordType)
+	if err != nil {
+		fmt.Fprintln(errWriter, err)
+		subcommands.DefaultCommander.ExplainCommand(errWriter, d)
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.DNSLookupRequest{
+		Name:   f.Arg(0),
+		Type:   recordType,
+		Server: d.server,
+	}
+	c := pb.NewNetworkClientProxy(state.Conn)
+	respChan, err := c.DNSLookupOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'dns': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[r.Index]
+		rt := recordTypeString(recordType)
+		for _, rec := range r.Resp.Records {
+			switch v := rec.Record.(type) {
+			case *pb.DNSRecord_Address:
+				fmt.Fprintf(out, "%s %s %s\n", req.Name, rt, v.Address)
+			case *pb.DNSRecord_Srv:
+				fmt.Fprintf(out, "%s %s %d %d %d %s\n", req.Name, rt, v.Srv.Priority, v.Srv.Weight, v.Srv.Port, v.Srv.Target)
+			case *pb.DNSRecord_Txt:
+				fmt.Fprintf(out, "%s %s %q\n", req.Name, rt, v.Txt)
+			}
+		}
+		fmt.Fprintf(out, "# lookup took %v\n", r.Resp.Duration.AsDuration())
+	}
+	return retCode
+}
+
+// optionalDuration returns d as a proto, or nil if it's unset so the
+// remote side picks a default.
+func optionalDuration(d time.Duration) *durationpb.Duration {
+	if d == 0 {
+		return nil
+	}
+	return durationpb.New(d)
+}
+
+type connectCmd struct {
+	timeout time.Duration
+}
+
+func (*connectCmd) Name() string { return "connect" }
+func (*connectCmd) Synopsis() string {
+	return "Check a TCP connection can be made from the remote host"
+}
+func (*connectCmd) Usage() string {
+	return `connect [--timeout <duration>] <host> <port>:
+    Connect to host:port from the remote host and report how long it took.
+`
+}
+
+func (c *connectCmd) SetFlags(f *flag.FlagSet) {
+	f.DurationVar(&c.timeout, "timeout", 0, "How long to wait for the connection. If unset the remote side picks (5s)")
+}
+
+func (c *connectCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+	if f.NArg() != 2 {
+		fmt.Fprintln(errWriter, "Please specify a host and port.")
+		subcommands.DefaultCommander.ExplainCommand(errWriter, c)
+		return subcommands.ExitUsageError
+	}
+	port, err := strconv.ParseUint(f.Arg(1), 10, 16)
+	if err != nil {
+		fmt.Fprintf(errWriter, "invalid port %s\n", f.Arg(1))
+		subcommands.DefaultCommander.ExplainCommand(errWriter, c)
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.TCPConnectRequest{
+		Host:    f.Arg(0),
+		Port:    uint32(port),
+		Timeout: optionalDuration(c.timeout),
+	}
+	proxy := pb.NewNetworkClientProxy(state.Conn)
+	respChan, err := proxy.TCPConnectOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'connect': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[r.Index], "connected to %s in %v\n", r.Resp.Address, r.Resp.Duration.AsDuration())
+	}
+	return retCode
+}
+
+type pingCmd struct {
+	count    int
+	interval time.Duration
+	timeout  time.Duration
+}
+
+func (*pingCmd) Name() string     { return "ping" }
+func (*pingCmd) Synopsis() string { return "Ping a host from the remote host" }
+func (*pingCmd) Usage() string {
+	return `ping [--count <n>] [--interval <duration>] [--timeout <duration>] <host>:
+    Send ICMP echo requests from the remote host and print the replies and
+    a summary.
+`
+}
+
+func (p *pingCmd) SetFlags(f *flag.FlagSet) {
+	f.IntVar(&p.count, "count", 0, "How many echo requests to send. If unset the remote side picks (3)")
+	f.DurationVar(&p.interval, "interval", 0, "Time between requests. If unset the remote side picks (1s)")
+	f.DurationVar(&p.timeout, "timeout", 0, "How long to wait for each reply. If unset the remote side picks (1s)")
+}
+
+func (p *pingCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+	if f.NArg() != 1 {
+		fmt.Fprintln(errWriter, "Please specify a host to ping.")
+		subcommands.DefaultCommander.ExplainCommand(errWriter, p)
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.PingRequest{
+		Host:     f.Arg(0),
+		Count:    int32(p.count),
+		Interval: optionalDuration(p.interval),
+		Timeout:  optionalDuration(p.timeout),
+	}
+	proxy := pb.NewNetworkClientProxy(state.Conn)
+	respChan, err := proxy.PingOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'ping': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[r.Index]
+		for _, resp := range r.Resp.Responses {
+			fmt.Fprintf(out, "reply from %s: seq=%d ttl=%d time=%v\n", r.Resp.Address, resp.Sequence, resp.Ttl, resp.Rtt.AsDuration())
+		}
+		fmt.Fprintf(out, "%s: %d transmitted, %d received", r.Resp.Address, r.Resp.Transmitted, r.Resp.Received)
+		if r.Resp.Received > 0 {
+			fmt.Fprintf(out, ", min/avg/max %v/%v/%v", r.Resp.MinRtt.AsDuration(), r.Resp.AvgRtt.AsDuration(), r.Resp.MaxRtt.AsDuration())
+		}
+		fmt.Fprintln(out)
+	}
+	return retCode
+}
+
+type socketsCmd struct {
+	tcp       bool
+	udp       bool
+	listening bool
+	states    []string
+	port      uint
+}
+
+func (*socketsCmd) Name() string { return "sockets" }
+func (*socketsCmd) Synopsis() string {
+	return "List TCP and UDP sockets on the remote host"
+}
+func (*socketsCmd) Usage() string {
+	return `sockets [--tcp] [--udp] [--listening] [--states=X,Y] [--port=N]:
+    List sockets on the remote host as ss(8) would, with the processes which
+    have them open, i.e. to find what is listening on a port:
+
+      sockets --listening --port=8443
+`
+}
+
+func (s *socketsCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&s.tcp, "tcp", false, "List TCP sockets. If neither --tcp nor --udp are set both are listed.")
+	f.BoolVar(&s.udp, "udp", false, "List UDP sockets. If neither --tcp nor --udp are set both are listed.")
+	f.BoolVar(&s.listening, "listening", false, "Only list listening sockets (LISTEN for TCP and UNCONN for UDP). Same as --states=LISTEN,UNCONN")
+	f.Var(&util.StringSliceFlag{Target: &s.states}, "states", "Comma separated list of states to list, named as ss does (i.e. LISTEN, ESTAB, TIME-WAIT)")
+	f.UintVar(&s.port, "port", 0, "Only list sockets with this local or remote port")
+}
+
+// socketAddress formats an address and port as ss does.
+func socketAddress(addr string, port uint32) string {
+	p := "*"
+	if port != 0 {
+		p = strconv.Itoa(int(port))
+	}
+	return net.JoinHostPort(addr, p)
+}
+
+func (s *socketsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+	if f.NArg() != 0 || s.port > 65535 || (s.listening && len(s.states) > 0) {
+		fmt.Fprintln(errWriter, "Please specify a valid port, and only one of --listening or --states.")
+		subcommands.DefaultCommander.ExplainCommand(errWriter, s)
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.ListSocketsRequest{
+		States: s.states,
+		Port:   uint32(s.port),
+	}
+	if s.tcp {
+		req.Protocols = append(req.Protocols, pb.SocketProtocol_SOCKET_PROTOCOL_TCP)
+	}
+	if s.udp {
+		req.Protocols = append(req.Protocols, pb.SocketProtocol_SOCKET_PROTOCOL_UDP)
+	}
+	if s.listening {
+		req.States = []string{"LISTEN", "UNCONN"}
+	}
+	proxy := pb.NewNetworkClientProxy(state.Conn)
+	respChan, err := proxy.ListSocketsOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'sockets': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, sock := range r.Resp.Sockets {
+			var users []string
+			for _, p := range sock.Processes {
+				users = append(users, fmt.Sprintf("(%q,pid=%d)", p.Command, p.Pid))
+			}
+			proto := strings.ToLower(strings.TrimPrefix(sock.Protocol.String(), "SOCKET_PROTOCOL_"))
+			fmt.Fprintf(state.Out[r.Index], "%s\t%s\t%s\t%s\tuid=%d\tusers:(%s)\n", proto, sock.State, socketAddress(sock.LocalAddress, sock.LocalPort), socketAddress(sock.RemoteAddress, sock.RemotePort), sock.Uid, strings.Join(users, ","))
+		}
+	}
+	return retCode
+}
+
+type tracerouteCmd struct {
+	maxHops int
+	queries int
+	timeout time.Duration
+}
+
+func (*tracerouteCmd) Name() string     { return "traceroute" }
+func (*tracerouteCmd) Synopsis() string { return "Trace the route to a host from the remote host" }
+func (*tracerouteCmd) Usage() string {
+	return `traceroute [--max-hops <n>] [--queries <n>] [--timeout <duration>] <host>:
+    Trace the route to a host from the remote host with UDP probes.
+`
+}
+
+func (t *tracerouteCmd) SetFlags(f *flag.FlagSet) {
+	f.IntVar(&t.maxHops, "max-hops", 0, "The most hops to probe. If unset the remote side picks (30)")
+	f.IntVar(&t.queries, "queries", 0, "Probes per hop. If unset the remote side picks (3)")
+	f.DurationVar(&t.timeout, "timeout", 0, "How long to wait for each probe. If unset the remote side picks (2s)")
+}
+
+func (t *tracerouteCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+	if f.NArg() != 1 {
+		fmt.Fprintln(errWriter, "Please specify a host to trace.")
+		subcommands.DefaultCommander.ExplainCommand(errWriter, t)
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.TracerouteRequest{
+		Host:    f.Arg(0),
+		MaxHops: int32(t.maxHops),
+		Queries: int32(t.queries),
+		Timeout: optionalDuration(t.timeout),
+	}
+	proxy := pb.NewNetworkClientProxy(state.Conn)
+	respChan, err := proxy.TracerouteOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'traceroute': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[r.Index]
+		fmt.Fprintf(out, "traceroute to %s\n", r.Resp.Address)
+		for _, hop := range r.Resp.Hops {
+			var probes []string
+			for _, p := range hop.Probes {
+				if p.Address == "" {
+					probes = append(probes, "*")
+					continue
+				}
+				probe := fmt.Sprintf("%s %v", p.Address, p.Rtt.AsDuration())
+				if p.Annotation != "" {
+					probe += " " + p.Annotation
+				}
+				probes = append(probes, probe)
+			}
+			fmt.Fprintf(out, "%2d  %s\n", hop.Hop, strings.Join(probes, "  "))
+		}
+	}
+	return retCode
+}
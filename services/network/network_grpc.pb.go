@@ -0,0 +1,263 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package network
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// NetworkClient is the client API for Network service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type NetworkClient interface {
+	// DNSLookup resolves a name from the host, using its resolver
+	// configuration (i.e. resolv.conf) unless a server is given.
+	DNSLookup(ctx context.Context, in *DNSLookupRequest, opts ...grpc.CallOption) (*DNSLookupReply, error)
+	// TCPConnect checks whether a TCP connection can be made from the host.
+	// Failures are returned as errors (i.e. Unavailable if refused,
+	// DeadlineExceeded on timeout).
+	TCPConnect(ctx context.Context, in *TCPConnectRequest, opts ...grpc.CallOption) (*TCPConnectReply, error)
+	// Ping sends ICMP echo requests from the host. Lost packets aren't an
+	// error.
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingReply, error)
+	// Traceroute traces the route from the host with UDP probes.
+	Traceroute(ctx context.Context, in *TracerouteRequest, opts ...grpc.CallOption) (*TracerouteReply, error)
+	// ListSockets returns the host's TCP and UDP sockets with the processes
+	// which have them open, as ss(8) or netstat would.
+	ListSockets(ctx context.Context, in *ListSocketsRequest, opts ...grpc.CallOption) (*ListSocketsReply, error)
+}
+
+type networkClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNetworkClient(cc grpc.ClientConnInterface) NetworkClient {
+	return &networkClient{cc}
+}
+
+func (c *networkClient) DNSLookup(ctx context.Context, in *DNSLookupRequest, opts ...grpc.CallOption) (*DNSLookupReply, error) {
+	out := new(DNSLookupReply)
+	err := c.cc.Invoke(ctx, "/Network.Network/DNSLookup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkClient) TCPConnect(ctx context.Context, in *TCPConnectRequest, opts ...grpc.CallOption) (*TCPConnectReply, error) {
+	out := new(TCPConnectReply)
+	err := c.cc.Invoke(ctx, "/Network.Network/TCPConnect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingReply, error) {
+	out := new(PingReply)
+	err := c.cc.Invoke(ctx, "/Network.Network/Ping", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkClient) Traceroute(ctx context.Context, in *TracerouteRequest, opts ...grpc.CallOption) (*TracerouteReply, error) {
+	out := new(TracerouteReply)
+	err := c.cc.Invoke(ctx, "/Network.Network/Traceroute", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkClient) ListSockets(ctx context.Context, in *ListSocketsRequest, opts ...grpc.CallOption) (*ListSocketsReply, error) {
+	out := new(ListSocketsReply)
+	err := c.cc.Invoke(ctx, "/Network.Network/ListSockets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NetworkServer is the server API for Network service.
+// All implementations should embed UnimplementedNetworkServer
+// for forward compatibility
+type NetworkServer interface {
+	// DNSLookup resolves a name from the host, using its resolver
+	// configuration (i.e. resolv.conf) unless a server is given.
+	DNSLookup(context.Context, *DNSLookupRequest) (*DNSLookupReply, error)
+	// TCPConnect checks whether a TCP connection can be made from the host.
+	// Failures are returned as errors (i.e. Unavailable if refused,
+	// DeadlineExceeded on timeout).
+	TCPConnect(context.Context, *TCPConnectRequest) (*TCPConnectReply, error)
+	// Ping sends ICMP echo requests from the host. Lost packets aren't an
+	// error.
+	Ping(context.Context, *PingRequest) (*PingReply, error)
+	// Traceroute traces the route from the host with UDP probes.
+	Traceroute(context.Context, *TracerouteRequest) (*TracerouteReply, error)
+	// ListSockets returns the host's TCP and UDP sockets with the processes
+	// which have them open, as ss(8) or netstat would.
+	ListSockets(context.Context, *ListSocketsRequest) (*ListSocketsReply, error)
+}
+
+// UnimplementedNetworkServer should be embedded to have forward compatible implementations.
+type UnimplementedNetworkServer struct {
+}
+
+func (UnimplementedNetworkServer) DNSLookup(context.Context, *DNSLookupRequest) (*DNSLookupReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DNSLookup not implemented")
+}
+func (UnimplementedNetworkServer) TCPConnect(context.Context, *TCPConnectRequest) (*TCPConnectReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TCPConnect not implemented")
+}
+func (UnimplementedNetworkServer) Ping(context.Context, *PingRequest) (*PingReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedNetworkServer) Traceroute(context.Context, *TracerouteRequest) (*TracerouteReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Traceroute not implemented")
+}
+func (UnimplementedNetworkServer) ListSockets(context.Context, *ListSocketsRequest) (*ListSocketsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSockets not implemented")
+}
+
+// UnsafeNetworkServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NetworkServer will
+// result in compilation errors.
+type UnsafeNetworkServer interface {
+	mustEmbedUnimplementedNetworkServer()
+}
+
+func RegisterNetworkServer(s grpc.ServiceRegistrar, srv NetworkServer) {
+	s.RegisterService(&Network_ServiceDesc, srv)
+}
+
+func _Network_DNSLookup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DNSLookupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServer).DNSLookup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Network.Network/DNSLookup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServer).DNSLookup(ctx, req.(*DNSLookupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Network_TCPConnect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TCPConnectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServer).TCPConnect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Network.Network/TCPConnect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServer).TCPConnect(ctx, req.(*TCPConnectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Network_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Network.Network/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Network_Traceroute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TracerouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServer).Traceroute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Network.Network/Traceroute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServer).Traceroute(ctx, req.(*TracerouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Network_ListSockets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSocketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServer).ListSockets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Network.Network/ListSockets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServer).ListSockets(ctx, req.(*ListSocketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Network_ServiceDesc is the grpc.ServiceDesc for Network service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Network_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Network.Network",
+	HandlerType: (*NetworkServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DNSLookup",
+			Handler:    _Network_DNSLookup_Handler,
+		},
+		{
+			MethodName: "TCPConnect",
+			Handler:    _Network_TCPConnect_Handler,
+		},
+		{
+			MethodName: "Ping",
+			Handler:    _Network_Ping_Handler,
+		},
+		{
+			MethodName: "Traceroute",
+			Handler:    _Network_Traceroute_Handler,
+		},
+		{
+			MethodName: "ListSockets",
+			Handler:    _Network_ListSockets_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "network.proto",
+}
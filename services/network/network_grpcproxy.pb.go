@@ -0,0 +1,372 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package network
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// NetworkClientProxy is the superset of NetworkClient which additionally includes the OneMany proxy methods
+type NetworkClientProxy interface {
+	NetworkClient
+	DNSLookupOneMany(ctx context.Context, in *DNSLookupRequest, opts ...grpc.CallOption) (<-chan *DNSLookupManyResponse, error)
+	TCPConnectOneMany(ctx context.Context, in *TCPConnectRequest, opts ...grpc.CallOption) (<-chan *TCPConnectManyResponse, error)
+	PingOneMany(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (<-chan *PingManyResponse, error)
+	TracerouteOneMany(ctx context.Context, in *TracerouteRequest, opts ...grpc.CallOption) (<-chan *TracerouteManyResponse, error)
+	ListSocketsOneMany(ctx context.Context, in *ListSocketsRequest, opts ...grpc.CallOption) (<-chan *ListSocketsManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type networkClientProxy struct {
+	*networkClient
+}
+
+// NewNetworkClientProxy creates a NetworkClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewNetworkClientProxy(cc *proxy.Conn) NetworkClientProxy {
+	return &networkClientProxy{NewNetworkClient(cc).(*networkClient)}
+}
+
+// DNSLookupManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type DNSLookupManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *DNSLookupReply
+	Error error
+}
+
+// DNSLookupOneMany provides the same API as DNSLookup but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *networkClientProxy) DNSLookupOneMany(ctx context.Context, in *DNSLookupRequest, opts ...grpc.CallOption) (<-chan *DNSLookupManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *DNSLookupManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &DNSLookupManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &DNSLookupReply{},
+			}
+			err := conn.Invoke(ctx, "/Network.Network/DNSLookup", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Network.Network/DNSLookup", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &DNSLookupManyResponse{
+				Resp: &DNSLookupReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// TCPConnectManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type TCPConnectManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *TCPConnectReply
+	Error error
+}
+
+// TCPConnectOneMany provides the same API as TCPConnect but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *networkClientProxy) TCPConnectOneMany(ctx context.Context, in *TCPConnectRequest, opts ...grpc.CallOption) (<-chan *TCPConnectManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *TCPConnectManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &TCPConnectManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &TCPConnectReply{},
+			}
+			err := conn.Invoke(ctx, "/Network.Network/TCPConnect", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Network.Network/TCPConnect", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &TCPConnectManyResponse{
+				Resp: &TCPConnectReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// PingManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type PingManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *PingReply
+	Error error
+}
+
+// PingOneMany provides the same API as Ping but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *networkClientProxy) PingOneMany(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (<-chan *PingManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *PingManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &PingManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &PingReply{},
+			}
+			err := conn.Invoke(ctx, "/Network.Network/Ping", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Network.Network/Ping", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &PingManyResponse{
+				Resp: &PingReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// TracerouteManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type TracerouteManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *TracerouteReply
+	Error error
+}
+
+// TracerouteOneMany provides the same API as Traceroute but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *networkClientProxy) TracerouteOneMany(ctx context.Context, in *TracerouteRequest, opts ...grpc.CallOption) (<-chan *TracerouteManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *TracerouteManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &TracerouteManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &TracerouteReply{},
+			}
+			err := conn.Invoke(ctx, "/Network.Network/Traceroute", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Network.Network/Traceroute", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &TracerouteManyResponse{
+				Resp: &TracerouteReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ListSocketsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ListSocketsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ListSocketsReply
+	Error error
+}
+
+// ListSocketsOneMany provides the same API as ListSockets but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *networkClientProxy) ListSocketsOneMany(ctx context.Context, in *ListSocketsRequest, opts ...grpc.CallOption) (<-chan *ListSocketsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ListSocketsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ListSocketsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ListSocketsReply{},
+			}
+			err := conn.Invoke(ctx, "/Network.Network/ListSockets", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Network.Network/ListSockets", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ListSocketsManyResponse{
+				Resp: &ListSocketsReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
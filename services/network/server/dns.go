@@ -0,0 +1,121 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/network"
+)
+
+// resolver returns a resolver querying server, or the system resolver if
+// it's empty.
+func resolver(server string) (*net.Resolver, error) {
+	if server == "" {
+		return net.DefaultResolver, nil
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(strings.Trim(server, "[]"), "53")
+	}
+	host, port, err := net.SplitHostPort(server)
+	if err != nil || host == "" || port == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid DNS server %q", server)
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}, nil
+}
+
+// lookupError converts an error from a lookup of name into a status.
+func lookupError(name string, err error) error {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		switch {
+		case dnsErr.IsNotFound:
+			return status.Errorf(codes.NotFound, "%s: %v", name, err)
+		case dnsErr.IsTimeout:
+			return status.Errorf(codes.DeadlineExceeded, "%s: %v", name, err)
+		}
+	}
+	return status.Errorf(codes.Unavailable, "%s: %v", name, err)
+}
+
+// DNSLookup implements pb.NetworkServer.DNSLookup
+func (s *server) DNSLookup(ctx context.Context, req *pb.DNSLookupRequest) (*pb.DNSLookupReply, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name must be set")
+	}
+	r, err := resolver(req.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &pb.DNSLookupReply{}
+	start := time.Now()
+	switch req.Type {
+	case pb.RecordType_RECORD_TYPE_A, pb.RecordType_RECORD_TYPE_AAAA:
+		network := "ip4"
+		if req.Type == pb.RecordType_RECORD_TYPE_AAAA {
+			network = "ip6"
+		}
+		ips, err := r.LookupIP(ctx, network, req.Name)
+		if err != nil {
+			return nil, lookupError(req.Name, err)
+		}
+		for _, ip := range ips {
+			reply.Records = append(reply.Records, &pb.DNSRecord{Record: &pb.DNSRecord_Address{Address: ip.String()}})
+		}
+	case pb.RecordType_RECORD_TYPE_SRV:
+		// With no service and proto name is looked up as is.
+		_, srvs, err := r.LookupSRV(ctx, "", "", req.Name)
+		if err != nil {
+			return nil, lookupError(req.Name, err)
+		}
+		for _, srv := range srvs {
+			reply.Records = append(reply.Records, &pb.DNSRecord{Record: &pb.DNSRecord_Srv{Srv: &pb.SRVRecord{
+				Target:   srv.Target,
+				Port:     uint32(srv.Port),
+				Priority: uint32(srv.Priority),
+				Weight:   uint32(srv.Weight),
+			}}})
+		}
+	case pb.RecordType_RECORD_TYPE_TXT:
+		txts, err := r.LookupTXT(ctx, req.Name)
+		if err != nil {
+			return nil, lookupError(req.Name, err)
+		}
+		for _, txt := range txts {
+			reply.Records = append(reply.Records, &pb.DNSRecord{Record: &pb.DNSRecord_Txt{Txt: txt}})
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported record type %v", req.Type)
+	}
+	reply.Duration = durationpb.New(time.Since(start))
+	return reply, nil
+}
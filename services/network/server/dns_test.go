@@ -0,0 +1,203 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/network"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// fakeDNS starts a DNS server answering a few fixed queries and returns
+// its address.
+func fakeDNS(t *testing.T) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	testutil.FatalOnErr("listen", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var p dnsmessage.Parser
+			h, err := p.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			q, err := p.Question()
+			if err != nil {
+				continue
+			}
+			rcode := dnsmessage.RCodeSuccess
+			switch q.Name.String() {
+			case "servfail.example.com.":
+				rcode = dnsmessage.RCodeServerFailure
+			case "host.example.com.", "_http._tcp.example.com.", "text.example.com.":
+			default:
+				rcode = dnsmessage.RCodeNameError
+			}
+			b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: h.ID, Response: true, Authoritative: true, RCode: rcode})
+			if err := b.StartQuestions(); err != nil {
+				continue
+			}
+			if err := b.Question(q); err != nil {
+				continue
+			}
+			if err := b.StartAnswers(); err != nil {
+				continue
+			}
+			rh := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 60}
+			if rcode == dnsmessage.RCodeSuccess {
+				switch q.Type {
+				case dnsmessage.TypeA:
+					if q.Name.String() == "host.example.com." {
+						_ = b.AResource(rh, dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}})
+						_ = b.AResource(rh, dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}})
+					}
+				case dnsmessage.TypeAAAA:
+					if q.Name.String() == "host.example.com." {
+						_ = b.AAAAResource(rh, dnsmessage.AAAAResource{AAAA: [16]byte{0xfd, 15: 1}})
+					}
+				case dnsmessage.TypeSRV:
+					if q.Name.String() == "_http._tcp.example.com." {
+						_ = b.SRVResource(rh, dnsmessage.SRVResource{Target: dnsmessage.MustNewName("web.example.com."), Port: 8080, Priority: 10, Weight: 5})
+					}
+				case dnsmessage.TypeTXT:
+					if q.Name.String() == "text.example.com." {
+						_ = b.TXTResource(rh, dnsmessage.TXTResource{TXT: []string{"v=spf1 -all"}})
+					}
+				}
+			}
+			msg, err := b.Finish()
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(msg, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestDNSLookup(t *testing.T) {
+	dns := fakeDNS(t)
+	address := func(a string) *pb.DNSRecord { return &pb.DNSRecord{Record: &pb.DNSRecord_Address{Address: a}} }
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.DNSLookupRequest
+		want    []*pb.DNSRecord
+		wantErr codes.Code
+	}{
+		{
+			name: "system resolver",
+			req:  &pb.DNSLookupRequest{Name: "localhost", Type: pb.RecordType_RECORD_TYPE_A},
+			want: []*pb.DNSRecord{address("127.0.0.1")},
+		},
+		{
+			name: "A",
+			req:  &pb.DNSLookupRequest{Name: "host.example.com", Type: pb.RecordType_RECORD_TYPE_A, Server: dns},
+			want: []*pb.DNSRecord{address("10.0.0.1"), address("10.0.0.2")},
+		},
+		{
+			name: "AAAA",
+			req:  &pb.DNSLookupRequest{Name: "host.example.com", Type: pb.RecordType_RECORD_TYPE_AAAA, Server: dns},
+			want: []*pb.DNSRecord{address("fd00::1")},
+		},
+		{
+			name: "SRV",
+			req:  &pb.DNSLookupRequest{Name: "_http._tcp.example.com", Type: pb.RecordType_RECORD_TYPE_SRV, Server: dns},
+			want: []*pb.DNSRecord{{Record: &pb.DNSRecord_Srv{Srv: &pb.SRVRecord{Target: "web.example.com.", Port: 8080, Priority: 10, Weight: 5}}}},
+		},
+		{
+			name: "TXT",
+			req:  &pb.DNSLookupRequest{Name: "text.example.com", Type: pb.RecordType_RECORD_TYPE_TXT, Server: dns},
+			want: []*pb.DNSRecord{{Record: &pb.DNSRecord_Txt{Txt: "v=spf1 -all"}}},
+		},
+		{
+			name:    "NXDOMAIN",
+			req:     &pb.DNSLookupRequest{Name: "missing.example.com", Type: pb.RecordType_RECORD_TYPE_A, Server: dns},
+			wantErr: codes.NotFound,
+		},
+		{
+			name:    "SERVFAIL",
+			req:     &pb.DNSLookupRequest{Name: "servfail.example.com", Type: pb.RecordType_RECORD_TYPE_TXT, Server: dns},
+			wantErr: codes.Unavailable,
+		},
+		{
+			name:    "no name",
+			req:     &pb.DNSLookupRequest{Type: pb.RecordType_RECORD_TYPE_A},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "no type",
+			req:     &pb.DNSLookupRequest{Name: "host.example.com"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad server",
+			req:     &pb.DNSLookupRequest{Name: "host.example.com", Type: pb.RecordType_RECORD_TYPE_A, Server: "127.0.0.1:"},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := (&server{}).DNSLookup(context.Background(), tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if resp.Duration == nil {
+				t.Error("duration not set")
+			}
+			testutil.DiffErr(tc.name, resp.Records, tc.want, t, protocmp.SortRepeated(func(a, b *pb.DNSRecord) bool { return a.String() < b.String() }))
+		})
+	}
+}
+
+func TestResolverServer(t *testing.T) {
+	for _, tc := range []struct {
+		server  string
+		wantErr bool
+	}{
+		{server: ""},
+		{server: "10.0.0.53"},
+		{server: "10.0.0.53:5353"},
+		{server: "::1"},
+		{server: "[::1]"},
+		{server: "[::1]:5353"},
+		{server: "dns.example.com"},
+		{server: ":53", wantErr: true},
+		{server: "10.0.0.53:", wantErr: true},
+	} {
+		_, err := resolver(tc.server)
+		testutil.WantErr(tc.server, err, tc.wantErr, t)
+	}
+}
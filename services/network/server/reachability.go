@@ -0,0 +1,342 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/network"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const (
+	defaultConnectTimeout = 5 * time.Second
+	maxTimeout            = time.Minute
+
+	defaultPingCount    = 3
+	maxPingCount        = 100
+	defaultPingInterval = time.Second
+	// minPingInterval is also the smallest ping allows unprivileged users.
+	minPingInterval    = 200 * time.Millisecond
+	defaultPingTimeout = time.Second
+
+	defaultMaxHops      = 30
+	maxMaxHops          = 64
+	defaultQueries      = 3
+	maxQueries          = 10
+	defaultProbeTimeout = 2 * time.Second
+)
+
+// checkHost validates a host to pass to a command, which mustn't look
+// like a flag.
+func checkHost(host string) error {
+	if host == "" {
+		return status.Error(codes.InvalidArgument, "host must be set")
+	}
+	if strings.HasPrefix(host, "-") {
+		return status.Errorf(codes.InvalidArgument, "invalid host %q", host)
+	}
+	return nil
+}
+
+// durationOrDefault returns d, or def if it's unset, checking it's between
+// min and maxTimeout.
+func durationOrDefault(name string, d *durationpb.Duration, def, min time.Duration) (time.Duration, error) {
+	if d == nil {
+		return def, nil
+	}
+	if err := d.CheckValid(); err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid %s: %v", name, err)
+	}
+	v := d.AsDuration()
+	if v < min || v > maxTimeout {
+		return 0, status.Errorf(codes.InvalidArgument, "%s must be between %v and %v", name, min, maxTimeout)
+	}
+	return v, nil
+}
+
+// intOrDefault returns v, or def if it's unset, checking it's no more than max.
+func intOrDefault(name string, v int32, def, max int) (int, error) {
+	if v == 0 {
+		return def, nil
+	}
+	if v < 0 || int(v) > max {
+		return 0, status.Errorf(codes.InvalidArgument, "%s must be between 1 and %d", name, max)
+	}
+	return int(v), nil
+}
+
+// seconds formats d for the command line.
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// parseMillis parses a time in milliseconds as printed by ping and
+// traceroute.
+func parseMillis(s string) (time.Duration, error) {
+	ms, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %v", s, err)
+	}
+	return time.Duration(ms * float64(time.Millisecond)), nil
+}
+
+// TCPConnect implements pb.NetworkServer.TCPConnect
+func (s *server) TCPConnect(ctx context.Context, req *pb.TCPConnectRequest) (*pb.TCPConnectReply, error) {
+	if req.Host == "" {
+		return nil, status.Error(codes.InvalidArgument, "host must be set")
+	}
+	if req.Port == 0 || req.Port > 65535 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid port %d", req.Port)
+	}
+	timeout, err := durationOrDefault("timeout", req.Timeout, defaultConnectTimeout, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	d := net.Dialer{Timeout: timeout}
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(req.Host, strconv.Itoa(int(req.Port))))
+	duration := time.Since(start)
+	if err != nil {
+		var dnsErr *net.DNSError
+		var netErr net.Error
+		switch {
+		case errors.As(err, &dnsErr):
+			return nil, lookupError(req.Host, err)
+		case errors.As(err, &netErr) && netErr.Timeout():
+			return nil, status.Errorf(codes.DeadlineExceeded, "connect timed out after %v: %v", duration, err)
+		}
+		return nil, status.Errorf(codes.Unavailable, "can't connect: %v", err)
+	}
+	defer conn.Close()
+	return &pb.TCPConnectReply{
+		Address:  conn.RemoteAddr().String(),
+		Duration: durationpb.New(duration),
+	}, nil
+}
+
+var (
+	// i.e. PING example.com (93.184.216.34) 56(84) bytes of data.
+	// or PING ::1(::1) 56 data bytes
+	pingHeaderRE = regexp.MustCompile(`^PING [^(]*\(([^)]+)\)`)
+	// i.e. 64 bytes from 93.184.216.34: icmp_seq=1 ttl=56 time=11.6 ms
+	pingResponseRE = regexp.MustCompile(`icmp_seq=(\d+) (?:ttl|hlim)=(\d+) time=([\d.]+) ms`)
+	// i.e. 3 packets transmitted, 2 received, 33.3333% packet loss, time 2003ms
+	pingStatsRE = regexp.MustCompile(`^(\d+) packets transmitted, (\d+) (?:packets )?received`)
+)
+
+// parsePing parses the output of iputils ping.
+func parsePing(out string) (*pb.PingReply, error) {
+	reply := &pb.PingReply{}
+	var total time.Duration
+	foundStats := false
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := pingHeaderRE.FindStringSubmatch(line); m != nil {
+			reply.Address = m[1]
+			continue
+		}
+		if m := pingResponseRE.FindStringSubmatch(line); m != nil {
+			seq, _ := strconv.Atoi(m[1])
+			ttl, _ := strconv.Atoi(m[2])
+			rtt, err := parseMillis(m[3])
+			if err != nil {
+				return nil, err
+			}
+			reply.Responses = append(reply.Responses, &pb.PingResponse{Sequence: int32(seq), Ttl: int32(ttl), Rtt: durationpb.New(rtt)})
+			total += rtt
+			if reply.MinRtt == nil || rtt < reply.MinRtt.AsDuration() {
+				reply.MinRtt = durationpb.New(rtt)
+			}
+			if reply.MaxRtt == nil || rtt > reply.MaxRtt.AsDuration() {
+				reply.MaxRtt = durationpb.New(rtt)
+			}
+			continue
+		}
+		if m := pingStatsRE.FindStringSubmatch(line); m != nil {
+			transmitted, _ := strconv.Atoi(m[1])
+			received, _ := strconv.Atoi(m[2])
+			reply.Transmitted, reply.Received = int32(transmitted), int32(received)
+			foundStats = true
+		}
+	}
+	if !foundStats {
+		return nil, errors.New("no statistics in ping output")
+	}
+	if len(reply.Responses) > 0 {
+		reply.AvgRtt = durationpb.New(total / time.Duration(len(reply.Responses)))
+	}
+	return reply, nil
+}
+
+// Ping implements pb.NetworkServer.Ping
+func (s *server) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingReply, error) {
+	if *pingBin == "" {
+		return nil, status.Error(codes.Unimplemented, "ping is not supported on this platform")
+	}
+	if err := checkHost(req.Host); err != nil {
+		return nil, err
+	}
+	count, err := intOrDefault("count", req.Count, defaultPingCount, maxPingCount)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := durationOrDefault("interval", req.Interval, defaultPingInterval, minPingInterval)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := durationOrDefault("timeout", req.Timeout, defaultPingTimeout, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"-n", "-c", strconv.Itoa(count), "-i", seconds(interval), "-W", seconds(timeout), req.Host}
+	run, err := util.RunCommand(ctx, *pingBin, args)
+	if err != nil {
+		return nil, err
+	}
+	// Exit code 1 means some replies weren't received, which is still a result.
+	if run.ExitCode != 0 && run.ExitCode != 1 {
+		return nil, status.Errorf(codes.Internal, "error from ping: %v\nstderr:\n%s", run.Error, util.TrimString(run.Stderr.String()))
+	}
+	reply, err := parsePing(run.Stdout.String())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't parse ping output: %v", err)
+	}
+	return reply, nil
+}
+
+var (
+	// i.e. traceroute to example.com (93.184.216.34), 30 hops max, 60 byte packets
+	tracerouteHeaderRE = regexp.MustCompile(`^traceroute to \S+ \(([^)]+)\)`)
+)
+
+// parseTracerouteHop parses one hop line of traceroute -n output, such as:
+//
+//	3  10.1.1.1  1.200 ms 10.1.1.2  1.500 ms *
+//
+// Each probe's time follows the address replying to it, which is only
+// printed when it differs from the previous probe's. Timeouts are * and
+// any annotation (i.e. !H) follows the time it applies to.
+func parseTracerouteHop(line string) (*pb.TracerouteHop, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, errors.New("empty hop")
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hop %q", line)
+	}
+	hop := &pb.TracerouteHop{Hop: int32(n)}
+	address := ""
+	for i := 1; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case f == "*":
+			hop.Probes = append(hop.Probes, &pb.TracerouteProbe{})
+		case strings.HasPrefix(f, "!"):
+			if len(hop.Probes) == 0 {
+				return nil, fmt.Errorf("annotation before any probe in %q", line)
+			}
+			hop.Probes[len(hop.Probes)-1].Annotation = f
+		case net.ParseIP(f) != nil:
+			address = f
+		case i+1 < len(fields) && fields[i+1] == "ms":
+			rtt, err := parseMillis(f)
+			if err != nil {
+				return nil, err
+			}
+			hop.Probes = append(hop.Probes, &pb.TracerouteProbe{Address: address, Rtt: durationpb.New(rtt)})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected %q in hop %q", f, line)
+		}
+	}
+	return hop, nil
+}
+
+// parseTraceroute parses the output of traceroute -n.
+func parseTraceroute(out string) (*pb.TracerouteReply, error) {
+	reply := &pb.TracerouteReply{}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := tracerouteHeaderRE.FindStringSubmatch(line); m != nil {
+			reply.Address = m[1]
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		hop, err := parseTracerouteHop(line)
+		if err != nil {
+			return nil, err
+		}
+		reply.Hops = append(reply.Hops, hop)
+	}
+	return reply, nil
+}
+
+// Traceroute implements pb.NetworkServer.Traceroute
+func (s *server) Traceroute(ctx context.Context, req *pb.TracerouteRequest) (*pb.TracerouteReply, error) {
+	if *tracerouteBin == "" {
+		return nil, status.Error(codes.Unimplemented, "traceroute is not supported on this platform")
+	}
+	if err := checkHost(req.Host); err != nil {
+		return nil, err
+	}
+	maxHops, err := intOrDefault("max_hops", req.MaxHops, defaultMaxHops, maxMaxHops)
+	if err != nil {
+		return nil, err
+	}
+	queries, err := intOrDefault("queries", req.Queries, defaultQueries, maxQueries)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := durationOrDefault("timeout", req.Timeout, defaultProbeTimeout, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"-n", "-m", strconv.Itoa(maxHops), "-q", strconv.Itoa(queries), "-w", seconds(timeout), req.Host}
+	run, err := util.RunCommand(ctx, *tracerouteBin, args)
+	if err != nil {
+		return nil, err
+	}
+	if err := run.Error; run.ExitCode != 0 || err != nil {
+		return nil, status.Errorf(codes.Internal, "error from traceroute: %v\nstderr:\n%s", err, util.TrimString(run.Stderr.String()))
+	}
+	reply, err := parseTraceroute(run.Stdout.String())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't parse traceroute output: %v", err)
+	}
+	return reply, nil
+}
@@ -0,0 +1,364 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/network"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestTCPConnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.FatalOnErr("listen", err, t)
+	addr := l.Addr().(*net.TCPAddr)
+	// Find a closed port by closing a listener.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.FatalOnErr("listen", err, t)
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+	t.Cleanup(func() { l.Close() })
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.TCPConnectRequest
+		want    string
+		wantErr codes.Code
+	}{
+		{
+			name: "connects",
+			req:  &pb.TCPConnectRequest{Host: "127.0.0.1", Port: uint32(addr.Port)},
+			want: addr.String(),
+		},
+		{
+			name:    "refused",
+			req:     &pb.TCPConnectRequest{Host: "127.0.0.1", Port: uint32(closedPort), Timeout: durationpb.New(time.Second)},
+			wantErr: codes.Unavailable,
+		},
+		{
+			name:    "no host",
+			req:     &pb.TCPConnectRequest{Port: 80},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad port",
+			req:     &pb.TCPConnectRequest{Host: "127.0.0.1", Port: 65536},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad timeout",
+			req:     &pb.TCPConnectRequest{Host: "127.0.0.1", Port: 80, Timeout: durationpb.New(time.Hour)},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := (&server{}).TCPConnect(context.Background(), tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if resp.Address != tc.want {
+				t.Errorf("got address %s want %s", resp.Address, tc.want)
+			}
+			if resp.Duration == nil {
+				t.Error("duration not set")
+			}
+		})
+	}
+}
+
+func TestParsePing(t *testing.T) {
+	ms := func(f float64) *durationpb.Duration {
+		return durationpb.New(time.Duration(f * float64(time.Millisecond)))
+	}
+	for _, tc := range []struct {
+		name    string
+		file    string
+		input   string
+		want    *pb.PingReply
+		wantErr bool
+	}{
+		{
+			name: "partial loss",
+			file: "./testdata/ping.out",
+			want: &pb.PingReply{
+				Address:     "93.184.216.34",
+				Transmitted: 3,
+				Received:    2,
+				Responses: []*pb.PingResponse{
+					{Sequence: 1, Ttl: 56, Rtt: ms(11.6)},
+					{Sequence: 3, Ttl: 56, Rtt: ms(12.4)},
+				},
+				MinRtt: ms(11.6),
+				AvgRtt: ms(12),
+				MaxRtt: ms(12.4),
+			},
+		},
+		{
+			name: "all lost",
+			file: "./testdata/ping-lost.out",
+			want: &pb.PingReply{
+				Address:     "::1",
+				Transmitted: 2,
+			},
+		},
+		{
+			name:    "no statistics",
+			input:   "PING example.com (93.184.216.34) 56(84) bytes of data.\n",
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := tc.input
+			if tc.file != "" {
+				b, err := os.ReadFile(tc.file)
+				testutil.FatalOnErr("reading "+tc.file, err, t)
+				input = string(b)
+			}
+			got, err := parsePing(input)
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
+
+func TestParseTraceroute(t *testing.T) {
+	ms := func(f float64) *durationpb.Duration {
+		return durationpb.New(time.Duration(f * float64(time.Millisecond)))
+	}
+	b, err := os.ReadFile("./testdata/traceroute.out")
+	testutil.FatalOnErr("reading traceroute output", err, t)
+	got, err := parseTraceroute(string(b))
+	testutil.FatalOnErr("parseTraceroute", err, t)
+	want := &pb.TracerouteReply{
+		Address: "93.184.216.34",
+		Hops: []*pb.TracerouteHop{
+			{Hop: 1, Probes: []*pb.TracerouteProbe{
+				{Address: "10.0.0.1", Rtt: ms(0.345)},
+				{Address: "10.0.0.1", Rtt: ms(0.3)},
+				{Address: "10.0.0.1", Rtt: ms(0.29)},
+			}},
+			{Hop: 2, Probes: []*pb.TracerouteProbe{{}, {}, {}}},
+			{Hop: 3, Probes: []*pb.TracerouteProbe{
+				{Address: "10.1.1.1", Rtt: ms(1.2)},
+				{Address: "10.1.1.2", Rtt: ms(1.5)},
+				{},
+			}},
+			{Hop: 4, Probes: []*pb.TracerouteProbe{
+				{Address: "93.184.216.34", Rtt: ms(10.1), Annotation: "!H"},
+				{Address: "93.184.216.34", Rtt: ms(10.2), Annotation: "!H"},
+				{Address: "93.184.216.34", Rtt: ms(10.3), Annotation: "!H"},
+			}},
+		},
+	}
+	testutil.DiffErr("parseTraceroute", got, want, t)
+
+	for _, bad := range []string{
+		"x  10.0.0.1  0.345 ms",
+		" 1  !H",
+		" 1  10.0.0.1  fast ms",
+		" 1  10.0.0.1  surprise",
+	} {
+		if _, err := parseTraceroute(bad); err == nil {
+			t.Errorf("parseTraceroute(%q) didn't fail", bad)
+		}
+	}
+}
+
+// fakeCommand writes a script to dir which records its arguments in
+// dir/args, prints file and exits with code.
+func fakeCommand(t *testing.T, dir string, file string, code int) string {
+	t.Helper()
+	out, err := filepath.Abs(file)
+	testutil.FatalOnErr("output path", err, t)
+	script := fmt.Sprintf("#!/bin/sh\nprintf '%%s\\n' \"$*\" > %s\n%s %s\nexit %d\n", filepath.Join(dir, "args"), testutil.ResolvePath(t, "cat"), out, code)
+	bin := filepath.Join(dir, "cmd")
+	testutil.FatalOnErr("writing command", os.WriteFile(bin, []byte(script), 0755), t)
+	return bin
+}
+
+func TestPing(t *testing.T) {
+	saved := *pingBin
+	t.Cleanup(func() { *pingBin = saved })
+
+	for _, tc := range []struct {
+		name     string
+		req      *pb.PingRequest
+		output   string
+		code     int
+		wantArgs string
+		wantErr  codes.Code
+	}{
+		{
+			name:     "defaults",
+			req:      &pb.PingRequest{Host: "example.com"},
+			output:   "./testdata/ping.out",
+			wantArgs: "-n -c 3 -i 1 -W 1 example.com",
+		},
+		{
+			name:     "options and loss",
+			req:      &pb.PingRequest{Host: "example.com", Count: 10, Interval: durationpb.New(250 * time.Millisecond), Timeout: durationpb.New(3 * time.Second)},
+			output:   "./testdata/ping.out",
+			code:     1,
+			wantArgs: "-n -c 10 -i 0.25 -W 3 example.com",
+		},
+		{
+			name:    "failure",
+			req:     &pb.PingRequest{Host: "example.com"},
+			output:  "./testdata/ping.out",
+			code:    2,
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "bad output",
+			req:     &pb.PingRequest{Host: "example.com"},
+			output:  "./testdata/traceroute.out",
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "flag host",
+			req:     &pb.PingRequest{Host: "-f"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad count",
+			req:     &pb.PingRequest{Host: "example.com", Count: maxPingCount + 1},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "fast interval",
+			req:     &pb.PingRequest{Host: "example.com", Interval: durationpb.New(time.Millisecond)},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			*pingBin = fakeCommand(t, dir, tc.output, tc.code)
+			resp, err := (&server{}).Ping(context.Background(), tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if resp.Received != 2 {
+				t.Errorf("got %d received want 2", resp.Received)
+			}
+			args, err := os.ReadFile(filepath.Join(dir, "args"))
+			testutil.FatalOnErr("reading args", err, t)
+			if got := strings.TrimSpace(string(args)); got != tc.wantArgs {
+				t.Errorf("got args %q want %q", got, tc.wantArgs)
+			}
+		})
+	}
+}
+
+func TestTraceroute(t *testing.T) {
+	saved := *tracerouteBin
+	t.Cleanup(func() { *tracerouteBin = saved })
+
+	for _, tc := range []struct {
+		name     string
+		req      *pb.TracerouteRequest
+		output   string
+		code     int
+		wantArgs string
+		wantErr  codes.Code
+	}{
+		{
+			name:     "defaults",
+			req:      &pb.TracerouteRequest{Host: "example.com"},
+			output:   "./testdata/traceroute.out",
+			wantArgs: "-n -m 30 -q 3 -w 2 example.com",
+		},
+		{
+			name:     "options",
+			req:      &pb.TracerouteRequest{Host: "example.com", MaxHops: 5, Queries: 1, Timeout: durationpb.New(500 * time.Millisecond)},
+			output:   "./testdata/traceroute.out",
+			wantArgs: "-n -m 5 -q 1 -w 0.5 example.com",
+		},
+		{
+			name:    "failure",
+			req:     &pb.TracerouteRequest{Host: "example.com"},
+			output:  "./testdata/traceroute.out",
+			code:    2,
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "bad output",
+			req:     &pb.TracerouteRequest{Host: "example.com"},
+			output:  "./testdata/ping.out",
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "no host",
+			req:     &pb.TracerouteRequest{},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad hops",
+			req:     &pb.TracerouteRequest{Host: "example.com", MaxHops: maxMaxHops + 1},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad queries",
+			req:     &pb.TracerouteRequest{Host: "example.com", Queries: -1},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			*tracerouteBin = fakeCommand(t, dir, tc.output, tc.code)
+			resp, err := (&server{}).Traceroute(context.Background(), tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(resp.Hops) != 4 {
+				t.Errorf("got %d hops want 4", len(resp.Hops))
+			}
+			args, err := os.ReadFile(filepath.Join(dir, "args"))
+			testutil.FatalOnErr("reading args", err, t)
+			if got := strings.TrimSpace(string(args)); got != tc.wantArgs {
+				t.Errorf("got args %q want %q", got, tc.wantArgs)
+			}
+		})
+	}
+}
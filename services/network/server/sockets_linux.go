@@ -0,0 +1,245 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/network"
+)
+
+// procDir is where proc(5) is mounted.
+var procDir = "/proc"
+
+// nativeEndian is the byte order of the host, which /proc/net prints
+// addresses in.
+var nativeEndian = func() binary.ByteOrder {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+// tcpStates are the names ss(8) uses for the states in /proc/net/tcp.
+var tcpStates = map[uint64]string{
+	0x01: "ESTAB",
+	0x02: "SYN-SENT",
+	0x03: "SYN-RECV",
+	0x04: "FIN-WAIT-1",
+	0x05: "FIN-WAIT-2",
+	0x06: "TIME-WAIT",
+	0x07: "UNCONN",
+	0x08: "CLOSE-WAIT",
+	0x09: "LAST-ACK",
+	0x0A: "LISTEN",
+	0x0B: "CLOSING",
+	0x0C: "SYN-RECV",
+}
+
+// socketFiles are the files in /proc/net listing sockets of each protocol.
+var socketFiles = []struct {
+	protocol pb.SocketProtocol
+	file     string
+}{
+	{pb.SocketProtocol_SOCKET_PROTOCOL_TCP, "tcp"},
+	{pb.SocketProtocol_SOCKET_PROTOCOL_TCP, "tcp6"},
+	{pb.SocketProtocol_SOCKET_PROTOCOL_UDP, "udp"},
+	{pb.SocketProtocol_SOCKET_PROTOCOL_UDP, "udp6"},
+}
+
+// parseSocketAddress parses an address as /proc/net prints it, i.e.
+// 0100007F:1F90 for 127.0.0.1:8080. The address is hex encoded 32 bit
+// words in host byte order.
+func parseSocketAddress(s string) (string, uint32, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return "", 0, fmt.Errorf("invalid address %q", s)
+	}
+	b, err := hex.DecodeString(parts[0])
+	if err != nil || (len(b) != net.IPv4len && len(b) != net.IPv6len) {
+		return "", 0, fmt.Errorf("invalid address %q", s)
+	}
+	ip := make(net.IP, len(b))
+	for i := 0; i < len(b); i += 4 {
+		binary.BigEndian.PutUint32(ip[i:], nativeEndian.Uint32(b[i:]))
+	}
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in %q", s)
+	}
+	return ip.String(), uint32(port), nil
+}
+
+// parseSockets parses a /proc/net/{tcp,udp}{,6} file.
+func parseSockets(protocol pb.SocketProtocol, contents string) ([]*pb.Socket, error) {
+	var out []*pb.Socket
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	// Skip the header.
+	scanner.Scan()
+	for scanner.Scan() {
+		f := strings.Fields(scanner.Text())
+		if len(f) < 10 {
+			return nil, fmt.Errorf("invalid line %q", scanner.Text())
+		}
+		s := &pb.Socket{Protocol: protocol}
+		var err error
+		if s.LocalAddress, s.LocalPort, err = parseSocketAddress(f[1]); err != nil {
+			return nil, err
+		}
+		if s.RemoteAddress, s.RemotePort, err = parseSocketAddress(f[2]); err != nil {
+			return nil, err
+		}
+		st, err := strconv.ParseUint(f[3], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state in %q", scanner.Text())
+		}
+		s.State = tcpStates[st]
+		if s.State == "" {
+			s.State = "UNKNOWN"
+		}
+		uid, err := strconv.ParseUint(f[7], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid uid in %q", scanner.Text())
+		}
+		s.Uid = uint32(uid)
+		if s.Inode, err = strconv.ParseUint(f[9], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid inode in %q", scanner.Text())
+		}
+		out = append(out, s)
+	}
+	return out, scanner.Err()
+}
+
+// socketProcesses returns the processes with each socket inode open.
+// Processes which can't be inspected (or exit while being read) are
+// skipped.
+func socketProcesses() (map[uint64][]*pb.SocketProcess, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[uint64][]*pb.SocketProcess)
+	for _, e := range entries {
+		pid, err := strconv.ParseInt(e.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join(procDir, e.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		var proc *pb.SocketProcess
+		seen := make(map[uint64]bool)
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]"), 10, 64)
+			if err != nil || seen[inode] {
+				continue
+			}
+			seen[inode] = true
+			if proc == nil {
+				proc = &pb.SocketProcess{Pid: pid}
+				if comm, err := os.ReadFile(filepath.Join(procDir, e.Name(), "comm")); err == nil {
+					proc.Command = strings.TrimSpace(string(comm))
+				}
+			}
+			out[inode] = append(out[inode], proc)
+		}
+	}
+	return out, nil
+}
+
+// ListSockets implements pb.NetworkServer.ListSockets
+func (s *server) ListSockets(ctx context.Context, req *pb.ListSocketsRequest) (*pb.ListSocketsReply, error) {
+	if req.Port > 65535 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid port %d", req.Port)
+	}
+	protocols := make(map[pb.SocketProtocol]bool)
+	for _, p := range req.Protocols {
+		if p != pb.SocketProtocol_SOCKET_PROTOCOL_TCP && p != pb.SocketProtocol_SOCKET_PROTOCOL_UDP {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid protocol %v", p)
+		}
+		protocols[p] = true
+	}
+	states := make(map[string]bool)
+	for _, st := range req.States {
+		states[strings.ToUpper(st)] = true
+	}
+
+	reply := &pb.ListSocketsReply{}
+	for _, sf := range socketFiles {
+		if len(protocols) > 0 && !protocols[sf.protocol] {
+			continue
+		}
+		file := filepath.Join(procDir, "net", sf.file)
+		b, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			// i.e. IPv6 is disabled.
+			continue
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't read %s: %v", file, err)
+		}
+		sockets, err := parseSockets(sf.protocol, string(b))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't parse %s: %v", file, err)
+		}
+		for _, sock := range sockets {
+			if len(states) > 0 && !states[sock.State] {
+				continue
+			}
+			if req.Port != 0 && sock.LocalPort != req.Port && sock.RemotePort != req.Port {
+				continue
+			}
+			reply.Sockets = append(reply.Sockets, sock)
+		}
+	}
+	if len(reply.Sockets) == 0 {
+		return reply, nil
+	}
+	procs, err := socketProcesses()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't find socket owners: %v", err)
+	}
+	for _, sock := range reply.Sockets {
+		sock.Processes = procs[sock.Inode]
+	}
+	return reply, nil
+}
@@ -0,0 +1,185 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/network"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const (
+	header = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+	procTCP = header +
+		// 0.0.0.0:22 listening, and an established connection to it.
+		"   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0\n" +
+		"   1: 0500000A:0016 0900000A:C738 01 00000000:00000000 02:000A7A3F 00000000     0        0 1002 4 0000000000000000 20 4 29 10 -1\n" +
+		// 127.0.0.1:8443 listening and a closed connection from it.
+		"   2: 0100007F:20FB 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1003 1 0000000000000000 100 0 0 10 0\n" +
+		"   3: 0100007F:20FB 0100007F:D431 06 00000000:00000000 03:00000A2B 00000000     0        0 0 3 0000000000000000\n"
+	procTCP6 = header +
+		// [::1]:8443 listening.
+		"   0: 00000000000000000000000001000000:20FB 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1004 1 0000000000000000 100 0 0 10 0\n"
+	procUDP = "   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops\n" +
+		// 127.0.0.53:53
+		"  100: 3500007F:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000   101        0 1005 2 0000000000000000 0\n"
+)
+
+// fakeProc creates a proc(5) tree with the sockets above, and processes
+// with some of them open.
+func fakeProc(t *testing.T) {
+	t.Helper()
+	saved := procDir
+	t.Cleanup(func() { procDir = saved })
+	procDir = t.TempDir()
+	testutil.FatalOnErr("mkdir", os.Mkdir(filepath.Join(procDir, "net"), 0755), t)
+	for f, contents := range map[string]string{"tcp": procTCP, "tcp6": procTCP6, "udp": procUDP} {
+		testutil.FatalOnErr("writing "+f, os.WriteFile(filepath.Join(procDir, "net", f), []byte(contents), 0644), t)
+	}
+	for pid, p := range map[string]struct {
+		comm string
+		fds  map[string]string
+	}{
+		"1":    {comm: "systemd", fds: map[string]string{"0": "/dev/null"}},
+		"700":  {comm: "sshd", fds: map[string]string{"3": "socket:[1001]", "4": "socket:[1001]"}},
+		"900":  {comm: "sshd", fds: map[string]string{"3": "socket:[1001]", "5": "socket:[1002]"}},
+		"1200": {comm: "envoy", fds: map[string]string{"10": "socket:[1003]", "11": "socket:[1004]", "12": "pipe:[77]"}},
+	} {
+		fdDir := filepath.Join(procDir, pid, "fd")
+		testutil.FatalOnErr("mkdir", os.MkdirAll(fdDir, 0755), t)
+		testutil.FatalOnErr("writing comm", os.WriteFile(filepath.Join(procDir, pid, "comm"), []byte(p.comm+"\n"), 0644), t)
+		for fd, target := range p.fds {
+			testutil.FatalOnErr("symlink", os.Symlink(target, filepath.Join(fdDir, fd)), t)
+		}
+	}
+	// Not a process.
+	testutil.FatalOnErr("mkdir", os.Mkdir(filepath.Join(procDir, "sys"), 0755), t)
+}
+
+func TestListSockets(t *testing.T) {
+	fakeProc(t)
+	sshd := &pb.SocketProcess{Pid: 700, Command: "sshd"}
+	sshdChild := &pb.SocketProcess{Pid: 900, Command: "sshd"}
+	envoy := &pb.SocketProcess{Pid: 1200, Command: "envoy"}
+	tcpListen := &pb.Socket{Protocol: pb.SocketProtocol_SOCKET_PROTOCOL_TCP, LocalAddress: "0.0.0.0", LocalPort: 22, RemoteAddress: "0.0.0.0", State: "LISTEN", Inode: 1001, Processes: []*pb.SocketProcess{sshd, sshdChild}}
+	tcpEstab := &pb.Socket{Protocol: pb.SocketProtocol_SOCKET_PROTOCOL_TCP, LocalAddress: "10.0.0.5", LocalPort: 22, RemoteAddress: "10.0.0.9", RemotePort: 51000, State: "ESTAB", Inode: 1002, Processes: []*pb.SocketProcess{sshdChild}}
+	envoyListen := &pb.Socket{Protocol: pb.SocketProtocol_SOCKET_PROTOCOL_TCP, LocalAddress: "127.0.0.1", LocalPort: 8443, RemoteAddress: "0.0.0.0", State: "LISTEN", Uid: 1000, Inode: 1003, Processes: []*pb.SocketProcess{envoy}}
+	timeWait := &pb.Socket{Protocol: pb.SocketProtocol_SOCKET_PROTOCOL_TCP, LocalAddress: "127.0.0.1", LocalPort: 8443, RemoteAddress: "127.0.0.1", RemotePort: 54321, State: "TIME-WAIT"}
+	envoyListen6 := &pb.Socket{Protocol: pb.SocketProtocol_SOCKET_PROTOCOL_TCP, LocalAddress: "::1", LocalPort: 8443, RemoteAddress: "::", State: "LISTEN", Uid: 1000, Inode: 1004, Processes: []*pb.SocketProcess{envoy}}
+	dns := &pb.Socket{Protocol: pb.SocketProtocol_SOCKET_PROTOCOL_UDP, LocalAddress: "127.0.0.53", LocalPort: 53, RemoteAddress: "0.0.0.0", State: "UNCONN", Uid: 101, Inode: 1005}
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.ListSocketsRequest
+		want    []*pb.Socket
+		wantErr codes.Code
+	}{
+		{
+			name: "all",
+			req:  &pb.ListSocketsRequest{},
+			want: []*pb.Socket{tcpListen, tcpEstab, envoyListen, timeWait, envoyListen6, dns},
+		},
+		{
+			name: "listening on 8443",
+			req:  &pb.ListSocketsRequest{Port: 8443, States: []string{"listen"}},
+			want: []*pb.Socket{envoyListen, envoyListen6},
+		},
+		{
+			name: "udp",
+			req:  &pb.ListSocketsRequest{Protocols: []pb.SocketProtocol{pb.SocketProtocol_SOCKET_PROTOCOL_UDP}},
+			want: []*pb.Socket{dns},
+		},
+		{
+			name: "remote port",
+			req:  &pb.ListSocketsRequest{Port: 51000},
+			want: []*pb.Socket{tcpEstab},
+		},
+		{
+			name: "no match",
+			req:  &pb.ListSocketsRequest{Port: 1},
+		},
+		{
+			name:    "bad port",
+			req:     &pb.ListSocketsRequest{Port: 65536},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad protocol",
+			req:     &pb.ListSocketsRequest{Protocols: []pb.SocketProtocol{pb.SocketProtocol_SOCKET_PROTOCOL_UNKNOWN}},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&server{}).ListSockets(context.Background(), tc.req)
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			want := &pb.ListSocketsReply{Sockets: tc.want}
+			if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.SortRepeated(func(a, b *pb.SocketProcess) bool { return a.Pid < b.Pid })); diff != "" {
+				t.Errorf("unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseSockets(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		contents string
+	}{
+		{
+			name:     "short line",
+			contents: header + "   0: 00000000:0016 00000000:0000 0A\n",
+		},
+		{
+			name:     "bad address",
+			contents: header + "   0: 0000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1\n",
+		},
+		{
+			name:     "bad port",
+			contents: header + "   0: 00000000:XYZ 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1\n",
+		},
+		{
+			name:     "bad inode",
+			contents: header + "   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 x 1\n",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := parseSockets(pb.SocketProtocol_SOCKET_PROTOCOL_TCP, tc.contents); err == nil {
+				t.Error("parseSockets succeeded, want error")
+			}
+		})
+	}
+}
@@ -21,9 +21,11 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/subcommands"
 
@@ -35,34 +37,23 @@ import (
 const subPackage = "packages"
 
 func init() {
-	subcommands.Register(&packagesCmd{}, subPackage)
+	client.RegisterSubpackage(subPackage, setup)
 }
 
 func setup(f *flag.FlagSet) *subcommands.Commander {
 	c := client.SetupSubpackage(subPackage, f)
 	c.Register(&installCmd{}, "")
+	c.Register(&cleanCacheCmd{}, "")
+	c.Register(&repoStatusCmd{enable: false}, "")
+	c.Register(&repoStatusCmd{enable: true}, "")
 	c.Register(&listCmd{}, "")
+	c.Register(&removeCmd{}, "")
 	c.Register(&repoListCmd{}, "")
 	c.Register(&updateCmd{}, "")
+	c.Register(&updateAllCmd{}, "")
 	return c
 }
 
-type packagesCmd struct{}
-
-func (*packagesCmd) Name() string { return subPackage }
-func (p *packagesCmd) Synopsis() string {
-	return client.GenerateSynopsis(setup(flag.NewFlagSet("", flag.ContinueOnError)))
-}
-func (p *packagesCmd) Usage() string {
-	return client.GenerateUsage(subPackage, p.Synopsis())
-}
-func (*packagesCmd) SetFlags(f *flag.FlagSet) {}
-
-func (p *packagesCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
-	c := setup(f)
-	return c.Execute(ctx, args...)
-}
-
 func flagToType(val string) (pb.PackageSystem, error) {
 	v := fmt.Sprintf("PACKAGE_SYSTEM_%s", strings.ToUpper(val))
 	i, ok := pb.PackageSystem_value[v]
@@ -81,11 +72,75 @@ func shortPackageSystemNames() []string {
 	return shortNames
 }
 
+// printPackages writes one line per package with its name, version, repo
+// and install time.
+func printPackages(w io.Writer, packages []*pb.PackageInfo) {
+	for _, pkg := range packages {
+		// Print the package name, version, repo and install time with some reasonable spacing.
+		var installed string
+		if pkg.InstallTime != nil {
+			installed = pkg.InstallTime.AsTime().Local().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%40s %16s %32s %25s\n", pkg.Name, pkg.Version, pkg.Repo, installed)
+	}
+}
+
+// transactionResponse is the common form of the ManyResponse types
+// returned by the streaming transaction RPCs.
+type transactionResponse struct {
+	target string
+	index  int
+	resp   *pb.TransactionReply
+	err    error
+}
+
+// streamTransaction receives a transaction stream with recv, writing output
+// for each target as it arrives followed by the resulting packages.
+func streamTransaction(state *util.ExecuteState, name string, recv func() ([]transactionResponse, error)) subcommands.ExitStatus {
+	retCode := subcommands.ExitSuccess
+	packages := make(map[int][]*pb.PackageInfo)
+	failed := make(map[int]bool)
+	for {
+		resp, err := recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "%s returned error: %v\n", name, err)
+			}
+			return subcommands.ExitFailure
+		}
+		for _, r := range resp {
+			if r.err != nil {
+				fmt.Fprintf(state.Err[r.index], "%s for target %s (%d) returned error: %v\n", name, r.target, r.index, r.err)
+				failed[r.index] = true
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			state.Out[r.index].Write(r.resp.Output)
+			if len(r.resp.Packages) > 0 {
+				packages[r.index] = r.resp.Packages
+			}
+		}
+	}
+	for idx, p := range packages {
+		if failed[idx] {
+			continue
+		}
+		fmt.Fprint(state.Out[idx], "\nResulting Packages\n")
+		printPackages(state.Out[idx], p)
+	}
+	return retCode
+}
+
 type installCmd struct {
 	packageSystem string
 	name          string
 	version       string
 	repo          string
+	stream        bool
 }
 
 func (*installCmd) Name() string     { return "install" }
@@ -101,6 +156,7 @@ func (i *installCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&i.name, "name", "", "Name of package to install")
 	f.StringVar(&i.version, "version", "", "Version of package to install. For YUM this must be a full nevra version")
 	f.StringVar(&i.repo, "repo", "", "If set also enable this repo when resolving packages.")
+	f.BoolVar(&i.stream, "stream", false, "If true stream output from the installation as it runs")
 }
 
 func (i *installCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -120,6 +176,25 @@ func (i *installCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...inter
 		Repo:          i.repo,
 	}
 
+	if i.stream {
+		stream, err := c.StreamingInstallOneMany(ctx, req)
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "Install returned error: %v\n", err)
+			}
+			return subcommands.ExitFailure
+		}
+		return streamTransaction(state, "Install", func() ([]transactionResponse, error) {
+			resp, err := stream.Recv()
+			var out []transactionResponse
+			for _, r := range resp {
+				out = append(out, transactionResponse{r.Target, r.Index, r.Resp, r.Error})
+			}
+			return out, err
+		})
+	}
+
 	resp, err := c.InstallOneMany(ctx, req)
 	if err != nil {
 		// Emit this to every error file as it's not specific to a given target.
@@ -137,6 +212,10 @@ func (i *installCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...inter
 			continue
 		}
 		fmt.Fprintf(state.Out[r.Index], "Success!\n\nOutput from installation:\n%s\n", r.Resp.DebugOutput)
+		if len(r.Resp.Packages) > 0 {
+			fmt.Fprint(state.Out[r.Index], "Resulting Packages\n")
+			printPackages(state.Out[r.Index], r.Resp.Packages)
+		}
 	}
 	return retCode
 }
@@ -147,6 +226,7 @@ type updateCmd struct {
 	oldVersion    string
 	newVersion    string
 	repo          string
+	stream        bool
 }
 
 func (*updateCmd) Name() string     { return "update" }
@@ -163,6 +243,7 @@ func (u *updateCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&u.oldVersion, "old_version", "", "Old version of package which must be on the system. For YUM this must be a full nevra version")
 	f.StringVar(&u.newVersion, "new_version", "", "New version of package to update. For YUM this must be a full nevra version")
 	f.StringVar(&u.repo, "repo", "", "If set also enable this repo when resolving packages.")
+	f.BoolVar(&u.stream, "stream", false, "If true stream output from the update as it runs")
 }
 
 func (u *updateCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -188,6 +269,25 @@ func (u *updateCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 		Repo:          u.repo,
 	}
 
+	if u.stream {
+		stream, err := c.StreamingUpdateOneMany(ctx, req)
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "Update returned error: %v\n", err)
+			}
+			return subcommands.ExitFailure
+		}
+		return streamTransaction(state, "Update", func() ([]transactionResponse, error) {
+			resp, err := stream.Recv()
+			var out []transactionResponse
+			for _, r := range resp {
+				out = append(out, transactionResponse{r.Target, r.Index, r.Resp, r.Error})
+			}
+			return out, err
+		})
+	}
+
 	resp, err := c.UpdateOneMany(ctx, req)
 	if err != nil {
 		// Emit this to every error file as it's not specific to a given target.
@@ -205,24 +305,138 @@ func (u *updateCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 			continue
 		}
 		fmt.Fprintf(state.Out[r.Index], "Success!\n\nOutput from update:\n%s\n", r.Resp.DebugOutput)
+		if len(r.Resp.Packages) > 0 {
+			fmt.Fprint(state.Out[r.Index], "Resulting Packages\n")
+			printPackages(state.Out[r.Index], r.Resp.Packages)
+		}
 	}
 	return retCode
 }
 
+type removeCmd struct {
+	packageSystem string
+	name          string
+	version       string
+}
+
+func (*removeCmd) Name() string     { return "remove" }
+func (*removeCmd) Synopsis() string { return "Remove an installed package" }
+func (*removeCmd) Usage() string {
+	return `remove:
+  Remove a package from the remote machine. If --version is set the package
+  is only removed if it's installed at that version.
+`
+}
+
+func (r *removeCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&r.packageSystem, "package-system", "YUM", fmt.Sprintf("Package system to use(one of: [%s])", strings.Join(shortPackageSystemNames(), ",")))
+	f.StringVar(&r.name, "name", "", "Name of package to remove")
+	f.StringVar(&r.version, "version", "", "If set the version the package must be installed at. For YUM this must be a full nevra version")
+}
+
+func (r *removeCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	if r.name == "" {
+		fmt.Fprintln(os.Stderr, "--name must be supplied")
+		return subcommands.ExitFailure
+	}
+
+	ps, err := flagToType(r.packageSystem)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't parse package system for --package-system: %s invalid\n", r.packageSystem)
+		return subcommands.ExitFailure
+	}
+
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewPackagesClientProxy(state.Conn)
+
+	stream, err := c.RemoveOneMany(ctx, &pb.RemoveRequest{
+		PackageSystem: ps,
+		Name:          r.name,
+		Version:       r.version,
+	})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Remove returned error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	return streamTransaction(state, "Remove", func() ([]transactionResponse, error) {
+		resp, err := stream.Recv()
+		var out []transactionResponse
+		for _, r := range resp {
+			out = append(out, transactionResponse{r.Target, r.Index, r.Resp, r.Error})
+		}
+		return out, err
+	})
+}
+
+type updateAllCmd struct {
+	packageSystem string
+	repo          string
+}
+
+func (*updateAllCmd) Name() string     { return "update-all" }
+func (*updateAllCmd) Synopsis() string { return "Update all installed packages" }
+func (*updateAllCmd) Usage() string {
+	return `update-all:
+  Update every installed package on the remote machine to the latest available version.
+`
+}
+
+func (u *updateAllCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&u.packageSystem, "package-system", "YUM", fmt.Sprintf("Package system to use(one of: [%s])", strings.Join(shortPackageSystemNames(), ",")))
+	f.StringVar(&u.repo, "repo", "", "If set also enable this repo (for APT the target release) when resolving packages.")
+}
+
+func (u *updateAllCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	ps, err := flagToType(u.packageSystem)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't parse package system for --package-system: %s invalid\n", u.packageSystem)
+		return subcommands.ExitFailure
+	}
+
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewPackagesClientProxy(state.Conn)
+
+	stream, err := c.UpdateAllOneMany(ctx, &pb.UpdateAllRequest{
+		PackageSystem: ps,
+		Repo:          u.repo,
+	})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "UpdateAll returned error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	return streamTransaction(state, "UpdateAll", func() ([]transactionResponse, error) {
+		resp, err := stream.Recv()
+		var out []transactionResponse
+		for _, r := range resp {
+			out = append(out, transactionResponse{r.Target, r.Index, r.Resp, r.Error})
+		}
+		return out, err
+	})
+}
+
 type listCmd struct {
 	packageSystem string
+	name          string
 }
 
 func (*listCmd) Name() string     { return "list" }
 func (*listCmd) Synopsis() string { return "List installed packages" }
 func (*listCmd) Usage() string {
-	return `list:
-  List the installed packages on the remote machine.
+	return `list [--name=<pattern>]:
+  List the installed packages on the remote machine. With --name only
+  packages matching the pattern (which may contain * and ?) are listed.
 `
 }
 
 func (l *listCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&l.packageSystem, "package-system", "YUM", fmt.Sprintf("Package system to use(one of: [%s])", strings.Join(shortPackageSystemNames(), ",")))
+	f.StringVar(&l.name, "name", "", "If set only list packages matching this name pattern")
 }
 
 func (l *listCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -237,6 +451,7 @@ func (l *listCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfac
 
 	resp, err := c.ListInstalledOneMany(ctx, &pb.ListInstalledRequest{
 		PackageSystem: ps,
+		Name:          l.name,
 	})
 	if err != nil {
 		// Emit this to every error file as it's not specific to a given target.
@@ -254,10 +469,7 @@ func (l *listCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfac
 			continue
 		}
 		fmt.Fprint(state.Out[r.Index], "Installed Packages\n")
-		for _, pkg := range r.Resp.Packages {
-			// Print the package name, version and repo with some reasonable spacing.
-			fmt.Fprintf(state.Out[r.Index], "%40s %16s %32s\n", pkg.Name, pkg.Version, pkg.Repo)
-		}
+		printPackages(state.Out[r.Index], r.Resp.Packages)
 	}
 	return retCode
 }
@@ -330,6 +542,130 @@ func (r *repoListCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...inte
 	return retCode
 }
 
+// repoStatusCmd implements both enablerepo and disablerepo.
+type repoStatusCmd struct {
+	enable        bool
+	packageSystem string
+}
+
+func (r *repoStatusCmd) Name() string {
+	if r.enable {
+		return "enablerepo"
+	}
+	return "disablerepo"
+}
+func (r *repoStatusCmd) Synopsis() string {
+	if r.enable {
+		return "Enable a repo"
+	}
+	return "Disable a repo"
+}
+func (r *repoStatusCmd) Usage() string {
+	return fmt.Sprintf(`%s <repo id>:
+  %s the repo on the remote machine persistently and show its resulting status.
+`, r.Name(), r.Synopsis())
+}
+
+func (r *repoStatusCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&r.packageSystem, "package-system", "YUM", fmt.Sprintf("Package system to use(one of: [%s])", strings.Join(shortPackageSystemNames(), ",")))
+}
+
+func (r *repoStatusCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	if f.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Please specify a single repo id")
+		return subcommands.ExitUsageError
+	}
+	ps, err := flagToType(r.packageSystem)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't parse package system for --package-system: %s invalid\n", r.packageSystem)
+		return subcommands.ExitFailure
+	}
+
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewPackagesClientProxy(state.Conn)
+
+	req := &pb.SetRepoStatusRequest{
+		PackageSystem: ps,
+		Id:            f.Arg(0),
+		Status:        pb.RepoStatus_REPO_STATUS_DISABLED,
+	}
+	if r.enable {
+		req.Status = pb.RepoStatus_REPO_STATUS_ENABLED
+	}
+	resp, err := c.SetRepoStatusOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "SetRepoStatus returned error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for s := range resp {
+		if s.Error != nil {
+			fmt.Fprintf(state.Err[s.Index], "SetRepoStatus for target %s (%d) returned error: %v\n", s.Target, s.Index, s.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[s.Index], "%s %s\n", s.Resp.Repo.Id, getStatus(s.Resp.Repo.Status))
+	}
+	return retCode
+}
+
+type cleanCacheCmd struct {
+	packageSystem string
+	refresh       bool
+}
+
+func (*cleanCacheCmd) Name() string     { return "cleancache" }
+func (*cleanCacheCmd) Synopsis() string { return "Clean the package cache" }
+func (*cleanCacheCmd) Usage() string {
+	return `cleancache [--refresh]:
+  Clean cached package metadata and packages on the remote machine. With
+  --refresh metadata is then fetched again from all enabled repos.
+`
+}
+
+func (c *cleanCacheCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.packageSystem, "package-system", "YUM", fmt.Sprintf("Package system to use(one of: [%s])", strings.Join(shortPackageSystemNames(), ",")))
+	f.BoolVar(&c.refresh, "refresh", false, "If true fetch fresh metadata after cleaning")
+}
+
+func (c *cleanCacheCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	ps, err := flagToType(c.packageSystem)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't parse package system for --package-system: %s invalid\n", c.packageSystem)
+		return subcommands.ExitFailure
+	}
+
+	state := args[0].(*util.ExecuteState)
+	proxy := pb.NewPackagesClientProxy(state.Conn)
+
+	resp, err := proxy.CleanCacheOneMany(ctx, &pb.CleanCacheRequest{
+		PackageSystem: ps,
+		Refresh:       c.refresh,
+	})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "CleanCache returned error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range resp {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "CleanCache for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[r.Index], "Success!\n\nOutput from clean:\n%s\n", r.Resp.DebugOutput)
+	}
+	return retCode
+}
+
 func getStatus(s pb.RepoStatus) string {
 	status := "unknown"
 	switch s {
@@ -22,6 +22,22 @@ type PackagesClient interface {
 	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateReply, error)
 	ListInstalled(ctx context.Context, in *ListInstalledRequest, opts ...grpc.CallOption) (*ListInstalledReply, error)
 	RepoList(ctx context.Context, in *RepoListRequest, opts ...grpc.CallOption) (*RepoListReply, error)
+	// StreamingInstall is Install but streams the output of the package
+	// manager as it runs.
+	StreamingInstall(ctx context.Context, in *InstallRequest, opts ...grpc.CallOption) (Packages_StreamingInstallClient, error)
+	// StreamingUpdate is Update but streams the output of the package
+	// manager as it runs.
+	StreamingUpdate(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (Packages_StreamingUpdateClient, error)
+	// Remove uninstalls a package.
+	Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (Packages_RemoveClient, error)
+	// UpdateAll updates every installed package to the latest version
+	// available.
+	UpdateAll(ctx context.Context, in *UpdateAllRequest, opts ...grpc.CallOption) (Packages_UpdateAllClient, error)
+	// SetRepoStatus enables or disables a repo persistently.
+	SetRepoStatus(ctx context.Context, in *SetRepoStatusRequest, opts ...grpc.CallOption) (*SetRepoStatusReply, error)
+	// CleanCache removes cached package metadata and packages, optionally
+	// fetching fresh metadata afterwards.
+	CleanCache(ctx context.Context, in *CleanCacheRequest, opts ...grpc.CallOption) (*CleanCacheReply, error)
 }
 
 type packagesClient struct {
@@ -68,6 +84,152 @@ func (c *packagesClient) RepoList(ctx context.Context, in *RepoListRequest, opts
 	return out, nil
 }
 
+func (c *packagesClient) StreamingInstall(ctx context.Context, in *InstallRequest, opts ...grpc.CallOption) (Packages_StreamingInstallClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Packages_ServiceDesc.Streams[0], "/Packages.Packages/StreamingInstall", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &packagesStreamingInstallClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Packages_StreamingInstallClient interface {
+	Recv() (*TransactionReply, error)
+	grpc.ClientStream
+}
+
+type packagesStreamingInstallClient struct {
+	grpc.ClientStream
+}
+
+func (x *packagesStreamingInstallClient) Recv() (*TransactionReply, error) {
+	m := new(TransactionReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *packagesClient) StreamingUpdate(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (Packages_StreamingUpdateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Packages_ServiceDesc.Streams[1], "/Packages.Packages/StreamingUpdate", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &packagesStreamingUpdateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Packages_StreamingUpdateClient interface {
+	Recv() (*TransactionReply, error)
+	grpc.ClientStream
+}
+
+type packagesStreamingUpdateClient struct {
+	grpc.ClientStream
+}
+
+func (x *packagesStreamingUpdateClient) Recv() (*TransactionReply, error) {
+	m := new(TransactionReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *packagesClient) Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (Packages_RemoveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Packages_ServiceDesc.Streams[2], "/Packages.Packages/Remove", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &packagesRemoveClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Packages_RemoveClient interface {
+	Recv() (*TransactionReply, error)
+	grpc.ClientStream
+}
+
+type packagesRemoveClient struct {
+	grpc.ClientStream
+}
+
+func (x *packagesRemoveClient) Recv() (*TransactionReply, error) {
+	m := new(TransactionReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *packagesClient) UpdateAll(ctx context.Context, in *UpdateAllRequest, opts ...grpc.CallOption) (Packages_UpdateAllClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Packages_ServiceDesc.Streams[3], "/Packages.Packages/UpdateAll", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &packagesUpdateAllClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Packages_UpdateAllClient interface {
+	Recv() (*TransactionReply, error)
+	grpc.ClientStream
+}
+
+type packagesUpdateAllClient struct {
+	grpc.ClientStream
+}
+
+func (x *packagesUpdateAllClient) Recv() (*TransactionReply, error) {
+	m := new(TransactionReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *packagesClient) SetRepoStatus(ctx context.Context, in *SetRepoStatusRequest, opts ...grpc.CallOption) (*SetRepoStatusReply, error) {
+	out := new(SetRepoStatusReply)
+	err := c.cc.Invoke(ctx, "/Packages.Packages/SetRepoStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packagesClient) CleanCache(ctx context.Context, in *CleanCacheRequest, opts ...grpc.CallOption) (*CleanCacheReply, error) {
+	out := new(CleanCacheReply)
+	err := c.cc.Invoke(ctx, "/Packages.Packages/CleanCache", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PackagesServer is the server API for Packages service.
 // All implementations should embed UnimplementedPackagesServer
 // for forward compatibility
@@ -76,6 +238,22 @@ type PackagesServer interface {
 	Update(context.Context, *UpdateRequest) (*UpdateReply, error)
 	ListInstalled(context.Context, *ListInstalledRequest) (*ListInstalledReply, error)
 	RepoList(context.Context, *RepoListRequest) (*RepoListReply, error)
+	// StreamingInstall is Install but streams the output of the package
+	// manager as it runs.
+	StreamingInstall(*InstallRequest, Packages_StreamingInstallServer) error
+	// StreamingUpdate is Update but streams the output of the package
+	// manager as it runs.
+	StreamingUpdate(*UpdateRequest, Packages_StreamingUpdateServer) error
+	// Remove uninstalls a package.
+	Remove(*RemoveRequest, Packages_RemoveServer) error
+	// UpdateAll updates every installed package to the latest version
+	// available.
+	UpdateAll(*UpdateAllRequest, Packages_UpdateAllServer) error
+	// SetRepoStatus enables or disables a repo persistently.
+	SetRepoStatus(context.Context, *SetRepoStatusRequest) (*SetRepoStatusReply, error)
+	// CleanCache removes cached package metadata and packages, optionally
+	// fetching fresh metadata afterwards.
+	CleanCache(context.Context, *CleanCacheRequest) (*CleanCacheReply, error)
 }
 
 // UnimplementedPackagesServer should be embedded to have forward compatible implementations.
@@ -94,6 +272,24 @@ func (UnimplementedPackagesServer) ListInstalled(context.Context, *ListInstalled
 func (UnimplementedPackagesServer) RepoList(context.Context, *RepoListRequest) (*RepoListReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RepoList not implemented")
 }
+func (UnimplementedPackagesServer) StreamingInstall(*InstallRequest, Packages_StreamingInstallServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamingInstall not implemented")
+}
+func (UnimplementedPackagesServer) StreamingUpdate(*UpdateRequest, Packages_StreamingUpdateServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamingUpdate not implemented")
+}
+func (UnimplementedPackagesServer) Remove(*RemoveRequest, Packages_RemoveServer) error {
+	return status.Errorf(codes.Unimplemented, "method Remove not implemented")
+}
+func (UnimplementedPackagesServer) UpdateAll(*UpdateAllRequest, Packages_UpdateAllServer) error {
+	return status.Errorf(codes.Unimplemented, "method UpdateAll not implemented")
+}
+func (UnimplementedPackagesServer) SetRepoStatus(context.Context, *SetRepoStatusRequest) (*SetRepoStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRepoStatus not implemented")
+}
+func (UnimplementedPackagesServer) CleanCache(context.Context, *CleanCacheRequest) (*CleanCacheReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CleanCache not implemented")
+}
 
 // UnsafePackagesServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to PackagesServer will
@@ -178,6 +374,126 @@ func _Packages_RepoList_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Packages_StreamingInstall_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InstallRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PackagesServer).StreamingInstall(m, &packagesStreamingInstallServer{stream})
+}
+
+type Packages_StreamingInstallServer interface {
+	Send(*TransactionReply) error
+	grpc.ServerStream
+}
+
+type packagesStreamingInstallServer struct {
+	grpc.ServerStream
+}
+
+func (x *packagesStreamingInstallServer) Send(m *TransactionReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Packages_StreamingUpdate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UpdateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PackagesServer).StreamingUpdate(m, &packagesStreamingUpdateServer{stream})
+}
+
+type Packages_StreamingUpdateServer interface {
+	Send(*TransactionReply) error
+	grpc.ServerStream
+}
+
+type packagesStreamingUpdateServer struct {
+	grpc.ServerStream
+}
+
+func (x *packagesStreamingUpdateServer) Send(m *TransactionReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Packages_Remove_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RemoveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PackagesServer).Remove(m, &packagesRemoveServer{stream})
+}
+
+type Packages_RemoveServer interface {
+	Send(*TransactionReply) error
+	grpc.ServerStream
+}
+
+type packagesRemoveServer struct {
+	grpc.ServerStream
+}
+
+func (x *packagesRemoveServer) Send(m *TransactionReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Packages_UpdateAll_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UpdateAllRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PackagesServer).UpdateAll(m, &packagesUpdateAllServer{stream})
+}
+
+type Packages_UpdateAllServer interface {
+	Send(*TransactionReply) error
+	grpc.ServerStream
+}
+
+type packagesUpdateAllServer struct {
+	grpc.ServerStream
+}
+
+func (x *packagesUpdateAllServer) Send(m *TransactionReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Packages_SetRepoStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRepoStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackagesServer).SetRepoStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Packages.Packages/SetRepoStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackagesServer).SetRepoStatus(ctx, req.(*SetRepoStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Packages_CleanCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CleanCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PackagesServer).CleanCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Packages.Packages/CleanCache",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PackagesServer).CleanCache(ctx, req.(*CleanCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Packages_ServiceDesc is the grpc.ServiceDesc for Packages service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -201,7 +517,36 @@ var Packages_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RepoList",
 			Handler:    _Packages_RepoList_Handler,
 		},
+		{
+			MethodName: "SetRepoStatus",
+			Handler:    _Packages_SetRepoStatus_Handler,
+		},
+		{
+			MethodName: "CleanCache",
+			Handler:    _Packages_CleanCache_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamingInstall",
+			Handler:       _Packages_StreamingInstall_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamingUpdate",
+			Handler:       _Packages_StreamingUpdate_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Remove",
+			Handler:       _Packages_Remove_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UpdateAll",
+			Handler:       _Packages_UpdateAll_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "packages.proto",
 }
@@ -13,6 +13,7 @@ import (
 
 import (
 	"fmt"
+	"io"
 )
 
 // PackagesClientProxy is the superset of PackagesClient which additionally includes the OneMany proxy methods
@@ -22,6 +23,12 @@ type PackagesClientProxy interface {
 	UpdateOneMany(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (<-chan *UpdateManyResponse, error)
 	ListInstalledOneMany(ctx context.Context, in *ListInstalledRequest, opts ...grpc.CallOption) (<-chan *ListInstalledManyResponse, error)
 	RepoListOneMany(ctx context.Context, in *RepoListRequest, opts ...grpc.CallOption) (<-chan *RepoListManyResponse, error)
+	StreamingInstallOneMany(ctx context.Context, in *InstallRequest, opts ...grpc.CallOption) (Packages_StreamingInstallClientProxy, error)
+	StreamingUpdateOneMany(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (Packages_StreamingUpdateClientProxy, error)
+	RemoveOneMany(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (Packages_RemoveClientProxy, error)
+	UpdateAllOneMany(ctx context.Context, in *UpdateAllRequest, opts ...grpc.CallOption) (Packages_UpdateAllClientProxy, error)
+	SetRepoStatusOneMany(ctx context.Context, in *SetRepoStatusRequest, opts ...grpc.CallOption) (<-chan *SetRepoStatusManyResponse, error)
+	CleanCacheOneMany(ctx context.Context, in *CleanCacheRequest, opts ...grpc.CallOption) (<-chan *CleanCacheManyResponse, error)
 }
 
 // Embed the original client inside of this so we get the other generated methods automatically.
@@ -302,3 +309,489 @@ func (c *packagesClientProxy) RepoListOneMany(ctx context.Context, in *RepoListR
 
 	return ret, nil
 }
+
+// StreamingInstallManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type StreamingInstallManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *TransactionReply
+	Error error
+}
+
+type Packages_StreamingInstallClientProxy interface {
+	Recv() ([]*StreamingInstallManyResponse, error)
+	grpc.ClientStream
+}
+
+type packagesClientStreamingInstallClientProxy struct {
+	cc         *proxy.Conn
+	directDone bool
+	grpc.ClientStream
+}
+
+func (x *packagesClientStreamingInstallClientProxy) Recv() ([]*StreamingInstallManyResponse, error) {
+	var ret []*StreamingInstallManyResponse
+	// If this is a direct connection the RecvMsg call is to a standard grpc.ClientStream
+	// and not our proxy based one. This means we need to receive a typed response and
+	// convert it into a single slice entry return. This ensures the OneMany style calls
+	// can be used by proxy with 1:N targets and non proxy with 1 target without client changes.
+	if x.cc.Direct() {
+		// Check if we're done. Just return EOF now. Any real error was already sent inside
+		// of a ManyResponse.
+		if x.directDone {
+			return nil, io.EOF
+		}
+		m := &TransactionReply{}
+		err := x.ClientStream.RecvMsg(m)
+		ret = append(ret, &StreamingInstallManyResponse{
+			Resp:   m,
+			Error:  err,
+			Target: x.cc.Targets[0],
+			Index:  0,
+		})
+		// An error means we're done so set things so a later call now gets an EOF.
+		if err != nil {
+			x.directDone = true
+		}
+		return ret, nil
+	}
+
+	m := []*proxy.Ret{}
+	if err := x.ClientStream.RecvMsg(&m); err != nil {
+		return nil, err
+	}
+	for _, r := range m {
+		typedResp := &StreamingInstallManyResponse{
+			Resp: &TransactionReply{},
+		}
+		typedResp.Target = r.Target
+		typedResp.Index = r.Index
+		typedResp.Error = r.Error
+		if r.Error == nil {
+			if err := r.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+				typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, r.Error)
+			}
+		}
+		ret = append(ret, typedResp)
+	}
+	return ret, nil
+}
+
+// StreamingInstallOneMany provides the same API as StreamingInstall but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *packagesClientProxy) StreamingInstallOneMany(ctx context.Context, in *InstallRequest, opts ...grpc.CallOption) (Packages_StreamingInstallClientProxy, error) {
+	stream, err := c.cc.NewStream(ctx, &Packages_ServiceDesc.Streams[0], "/Packages.Packages/StreamingInstall", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &packagesClientStreamingInstallClientProxy{c.cc.(*proxy.Conn), false, stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// StreamingUpdateManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type StreamingUpdateManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *TransactionReply
+	Error error
+}
+
+type Packages_StreamingUpdateClientProxy interface {
+	Recv() ([]*StreamingUpdateManyResponse, error)
+	grpc.ClientStream
+}
+
+type packagesClientStreamingUpdateClientProxy struct {
+	cc         *proxy.Conn
+	directDone bool
+	grpc.ClientStream
+}
+
+func (x *packagesClientStreamingUpdateClientProxy) Recv() ([]*StreamingUpdateManyResponse, error) {
+	var ret []*StreamingUpdateManyResponse
+	// If this is a direct connection the RecvMsg call is to a standard grpc.ClientStream
+	// and not our proxy based one. This means we need to receive a typed response and
+	// convert it into a single slice entry return. This ensures the OneMany style calls
+	// can be used by proxy with 1:N targets and non proxy with 1 target without client changes.
+	if x.cc.Direct() {
+		// Check if we're done. Just return EOF now. Any real error was already sent inside
+		// of a ManyResponse.
+		if x.directDone {
+			return nil, io.EOF
+		}
+		m := &TransactionReply{}
+		err := x.ClientStream.RecvMsg(m)
+		ret = append(ret, &StreamingUpdateManyResponse{
+			Resp:   m,
+			Error:  err,
+			Target: x.cc.Targets[0],
+			Index:  0,
+		})
+		// An error means we're done so set things so a later call now gets an EOF.
+		if err != nil {
+			x.directDone = true
+		}
+		return ret, nil
+	}
+
+	m := []*proxy.Ret{}
+	if err := x.ClientStream.RecvMsg(&m); err != nil {
+		return nil, err
+	}
+	for _, r := range m {
+		typedResp := &StreamingUpdateManyResponse{
+			Resp: &TransactionReply{},
+		}
+		typedResp.Target = r.Target
+		typedResp.Index = r.Index
+		typedResp.Error = r.Error
+		if r.Error == nil {
+			if err := r.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+				typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, r.Error)
+			}
+		}
+		ret = append(ret, typedResp)
+	}
+	return ret, nil
+}
+
+// StreamingUpdateOneMany provides the same API as StreamingUpdate but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *packagesClientProxy) StreamingUpdateOneMany(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (Packages_StreamingUpdateClientProxy, error) {
+	stream, err := c.cc.NewStream(ctx, &Packages_ServiceDesc.Streams[1], "/Packages.Packages/StreamingUpdate", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &packagesClientStreamingUpdateClientProxy{c.cc.(*proxy.Conn), false, stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// RemoveManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type RemoveManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *TransactionReply
+	Error error
+}
+
+type Packages_RemoveClientProxy interface {
+	Recv() ([]*RemoveManyResponse, error)
+	grpc.ClientStream
+}
+
+type packagesClientRemoveClientProxy struct {
+	cc         *proxy.Conn
+	directDone bool
+	grpc.ClientStream
+}
+
+func (x *packagesClientRemoveClientProxy) Recv() ([]*RemoveManyResponse, error) {
+	var ret []*RemoveManyResponse
+	// If this is a direct connection the RecvMsg call is to a standard grpc.ClientStream
+	// and not our proxy based one. This means we need to receive a typed response and
+	// convert it into a single slice entry return. This ensures the OneMany style calls
+	// can be used by proxy with 1:N targets and non proxy with 1 target without client changes.
+	if x.cc.Direct() {
+		// Check if we're done. Just return EOF now. Any real error was already sent inside
+		// of a ManyResponse.
+		if x.directDone {
+			return nil, io.EOF
+		}
+		m := &TransactionReply{}
+		err := x.ClientStream.RecvMsg(m)
+		ret = append(ret, &RemoveManyResponse{
+			Resp:   m,
+			Error:  err,
+			Target: x.cc.Targets[0],
+			Index:  0,
+		})
+		// An error means we're done so set things so a later call now gets an EOF.
+		if err != nil {
+			x.directDone = true
+		}
+		return ret, nil
+	}
+
+	m := []*proxy.Ret{}
+	if err := x.ClientStream.RecvMsg(&m); err != nil {
+		return nil, err
+	}
+	for _, r := range m {
+		typedResp := &RemoveManyResponse{
+			Resp: &TransactionReply{},
+		}
+		typedResp.Target = r.Target
+		typedResp.Index = r.Index
+		typedResp.Error = r.Error
+		if r.Error == nil {
+			if err := r.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+				typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, r.Error)
+			}
+		}
+		ret = append(ret, typedResp)
+	}
+	return ret, nil
+}
+
+// RemoveOneMany provides the same API as Remove but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *packagesClientProxy) RemoveOneMany(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (Packages_RemoveClientProxy, error) {
+	stream, err := c.cc.NewStream(ctx, &Packages_ServiceDesc.Streams[2], "/Packages.Packages/Remove", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &packagesClientRemoveClientProxy{c.cc.(*proxy.Conn), false, stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// UpdateAllManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type UpdateAllManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *TransactionReply
+	Error error
+}
+
+type Packages_UpdateAllClientProxy interface {
+	Recv() ([]*UpdateAllManyResponse, error)
+	grpc.ClientStream
+}
+
+type packagesClientUpdateAllClientProxy struct {
+	cc         *proxy.Conn
+	directDone bool
+	grpc.ClientStream
+}
+
+func (x *packagesClientUpdateAllClientProxy) Recv() ([]*UpdateAllManyResponse, error) {
+	var ret []*UpdateAllManyResponse
+	// If this is a direct connection the RecvMsg call is to a standard grpc.ClientStream
+	// and not our proxy based one. This means we need to receive a typed response and
+	// convert it into a single slice entry return. This ensures the OneMany style calls
+	// can be used by proxy with 1:N targets and non proxy with 1 target without client changes.
+	if x.cc.Direct() {
+		// Check if we're done. Just return EOF now. Any real error was already sent inside
+		// of a ManyResponse.
+		if x.directDone {
+			return nil, io.EOF
+		}
+		m := &TransactionReply{}
+		err := x.ClientStream.RecvMsg(m)
+		ret = append(ret, &UpdateAllManyResponse{
+			Resp:   m,
+			Error:  err,
+			Target: x.cc.Targets[0],
+			Index:  0,
+		})
+		// An error means we're done so set things so a later call now gets an EOF.
+		if err != nil {
+			x.directDone = true
+		}
+		return ret, nil
+	}
+
+	m := []*proxy.Ret{}
+	if err := x.ClientStream.RecvMsg(&m); err != nil {
+		return nil, err
+	}
+	for _, r := range m {
+		typedResp := &UpdateAllManyResponse{
+			Resp: &TransactionReply{},
+		}
+		typedResp.Target = r.Target
+		typedResp.Index = r.Index
+		typedResp.Error = r.Error
+		if r.Error == nil {
+			if err := r.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+				typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, r.Error)
+			}
+		}
+		ret = append(ret, typedResp)
+	}
+	return ret, nil
+}
+
+// UpdateAllOneMany provides the same API as UpdateAll but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *packagesClientProxy) UpdateAllOneMany(ctx context.Context, in *UpdateAllRequest, opts ...grpc.CallOption) (Packages_UpdateAllClientProxy, error) {
+	stream, err := c.cc.NewStream(ctx, &Packages_ServiceDesc.Streams[3], "/Packages.Packages/UpdateAll", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &packagesClientUpdateAllClientProxy{c.cc.(*proxy.Conn), false, stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// SetRepoStatusManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type SetRepoStatusManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *SetRepoStatusReply
+	Error error
+}
+
+// SetRepoStatusOneMany provides the same API as SetRepoStatus but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *packagesClientProxy) SetRepoStatusOneMany(ctx context.Context, in *SetRepoStatusRequest, opts ...grpc.CallOption) (<-chan *SetRepoStatusManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *SetRepoStatusManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &SetRepoStatusManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &SetRepoStatusReply{},
+			}
+			err := conn.Invoke(ctx, "/Packages.Packages/SetRepoStatus", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Packages.Packages/SetRepoStatus", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &SetRepoStatusManyResponse{
+				Resp: &SetRepoStatusReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// CleanCacheManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type CleanCacheManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *CleanCacheReply
+	Error error
+}
+
+// CleanCacheOneMany provides the same API as CleanCache but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *packagesClientProxy) CleanCacheOneMany(ctx context.Context, in *CleanCacheRequest, opts ...grpc.CallOption) (<-chan *CleanCacheManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *CleanCacheManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &CleanCacheManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &CleanCacheReply{},
+			}
+			err := conn.Invoke(ctx, "/Packages.Packages/CleanCache", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Packages.Packages/CleanCache", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &CleanCacheManyResponse{
+				Resp: &CleanCacheReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
@@ -23,41 +23,85 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Snowflake-Labs/sansshell/services"
 	pb "github.com/Snowflake-Labs/sansshell/services/packages"
 	"github.com/Snowflake-Labs/sansshell/services/util"
+	"github.com/go-logr/logr"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// Internal helper to generate the command list. The map must contain the enum.
+// Internal helper to generate the command list. Package systems not in the
+// map are unimplemented.
 func genCmd(p pb.PackageSystem, m map[pb.PackageSystem][]string) ([]string, error) {
+	opts, ok := m[p]
 	var out []string
-	switch p {
-	case pb.PackageSystem_PACKAGE_SYSTEM_YUM:
+	switch {
+	case ok && p == pb.PackageSystem_PACKAGE_SYSTEM_YUM:
 		out = append(out, *yumBin)
-		out = append(out, m[p]...)
+	case ok && p == pb.PackageSystem_PACKAGE_SYSTEM_APT:
+		// Wait for anything else using dpkg rather than failing immediately.
+		out = append(out, *aptGetBin, "-o", fmt.Sprintf("DPkg::Lock::Timeout=%d", int(aptLockTimeout.Seconds())))
 	default:
 		return nil, status.Errorf(codes.Unimplemented, "no support for package system enum %d", p)
 	}
+	out = append(out, opts...)
 	return out, nil
 }
 
+// Optionally add the repo arg to the list.
+func addRepo(out []string, p pb.PackageSystem, repo string) []string {
+	if repo != "" {
+		switch p {
+		case pb.PackageSystem_PACKAGE_SYSTEM_YUM:
+			out = append(out, fmt.Sprintf("--enablerepo=%s", repo))
+		case pb.PackageSystem_PACKAGE_SYSTEM_APT:
+			out = append(out, "-t", repo)
+		}
+	}
+	return out
+}
+
 // Optionally add the repo arg and then append the full package name to the list.
+// If version is empty only the name is added.
 func addRepoAndPackage(out []string, p pb.PackageSystem, name string, version string, repo string) []string {
-	if repo != "" && p == pb.PackageSystem_PACKAGE_SYSTEM_YUM {
-		out = append(out, fmt.Sprintf("--enablerepo=%s", repo))
-	}
+	out = addRepo(out, p, repo)
 	// Tack the fully qualfied package name on. This assumes any vetting of args has already been done.
-	out = append(out, fmt.Sprintf("%s-%s", name, version))
+	switch {
+	case version == "":
+		out = append(out, name)
+	case p == pb.PackageSystem_PACKAGE_SYSTEM_APT:
+		out = append(out, fmt.Sprintf("%s=%s", name, version))
+	default:
+		out = append(out, fmt.Sprintf("%s-%s", name, version))
+	}
 	return out
 }
 
+// commandOptions returns the options to run package system commands with.
+func commandOptions(p pb.PackageSystem) []util.Option {
+	if p == pb.PackageSystem_PACKAGE_SYSTEM_APT {
+		// Maintainer scripts need a PATH and there's no one to answer questions.
+		return []util.Option{
+			util.EnvVar("DEBIAN_FRONTEND", "noninteractive"),
+			util.EnvVar("PATH", "/usr/sbin:/usr/bin:/sbin:/bin"),
+		}
+	}
+	return nil
+}
+
+// aptLockTimeout is how long apt-get waits for the dpkg lock.
+const aptLockTimeout = 5 * time.Minute
+
 var (
-	inputValidateRe = regexp.MustCompile("[^a-zA-Z0-9_.:-]+")
+	inputValidateRe   = regexp.MustCompile("[^a-zA-Z0-9_.:+~-]+")
+	patternValidateRe = regexp.MustCompile("[^a-zA-Z0-9_.:+*?-]+")
 
 	// These are vars for testing to be able to replace them.
 	generateInstall = func(p *pb.InstallRequest) ([]string, error) {
@@ -66,6 +110,10 @@ var (
 				"install-nevra",
 				"-y",
 			},
+			pb.PackageSystem_PACKAGE_SYSTEM_APT: {
+				"install",
+				"-y",
+			},
 		}
 		out, err := genCmd(p.PackageSystem, installOpts)
 		if err != nil {
@@ -74,18 +122,23 @@ var (
 		return addRepoAndPackage(out, p.PackageSystem, p.Name, p.Version, p.Repo), nil
 	}
 
-	generateValidate = func(p *pb.UpdateRequest) ([]string, error) {
+	// The command returned succeeds if the given version is installed. For
+	// APT it prints the installed version which must also be checked.
+	generateValidate = func(p pb.PackageSystem, name string, version string) ([]string, error) {
+		if p == pb.PackageSystem_PACKAGE_SYSTEM_APT {
+			return []string{*dpkgQueryBin, "-W", "-f=${Version}", name}, nil
+		}
 		validateOpts := map[pb.PackageSystem][]string{
 			pb.PackageSystem_PACKAGE_SYSTEM_YUM: {
 				"list",
 				"installed",
 			},
 		}
-		out, err := genCmd(p.PackageSystem, validateOpts)
+		out, err := genCmd(p, validateOpts)
 		if err != nil {
 			return nil, err
 		}
-		return addRepoAndPackage(out, p.PackageSystem, p.Name, p.OldVersion, ""), nil
+		return addRepoAndPackage(out, p, name, version, ""), nil
 	}
 
 	generateUpdate = func(p *pb.UpdateRequest) ([]string, error) {
@@ -94,6 +147,11 @@ var (
 				"update-to",
 				"-y",
 			},
+			pb.PackageSystem_PACKAGE_SYSTEM_APT: {
+				"install",
+				"-y",
+				"--only-upgrade",
+			},
 		}
 		out, err := genCmd(p.PackageSystem, updateOpts)
 		if err != nil {
@@ -102,14 +160,79 @@ var (
 		return addRepoAndPackage(out, p.PackageSystem, p.Name, p.NewVersion, p.Repo), nil
 	}
 
-	generateListInstalled = func(p pb.PackageSystem) ([]string, error) {
-		listOpts := map[pb.PackageSystem][]string{
+	generateRemove = func(p *pb.RemoveRequest) ([]string, error) {
+		removeOpts := map[pb.PackageSystem][]string{
 			pb.PackageSystem_PACKAGE_SYSTEM_YUM: {
-				"list",
-				"installed",
+				"remove",
+				"-y",
+			},
+			pb.PackageSystem_PACKAGE_SYSTEM_APT: {
+				"remove",
+				"-y",
 			},
 		}
-		return genCmd(p, listOpts)
+		out, err := genCmd(p.PackageSystem, removeOpts)
+		if err != nil {
+			return nil, err
+		}
+		return addRepoAndPackage(out, p.PackageSystem, p.Name, p.Version, ""), nil
+	}
+
+	// This returns the commands to run in order. apt needs its package
+	// lists refreshed first where yum does that itself.
+	generateUpdateAll = func(p *pb.UpdateAllRequest) ([][]string, error) {
+		updateOpts := map[pb.PackageSystem][]string{
+			pb.PackageSystem_PACKAGE_SYSTEM_YUM: {
+				"update",
+				"-y",
+			},
+			pb.PackageSystem_PACKAGE_SYSTEM_APT: {
+				"upgrade",
+				"-y",
+			},
+		}
+		out, err := genCmd(p.PackageSystem, updateOpts)
+		if err != nil {
+			return nil, err
+		}
+		out = addRepo(out, p.PackageSystem, p.Repo)
+		if p.PackageSystem == pb.PackageSystem_PACKAGE_SYSTEM_APT {
+			refresh, err := genCmd(p.PackageSystem, map[pb.PackageSystem][]string{p.PackageSystem: {"update"}})
+			if err != nil {
+				return nil, err
+			}
+			return [][]string{refresh, out}, nil
+		}
+		return [][]string{out}, nil
+	}
+
+	generateListInstalled = func(p pb.PackageSystem, name string) ([]string, error) {
+		var out []string
+		switch p {
+		case pb.PackageSystem_PACKAGE_SYSTEM_APT:
+			out = []string{*dpkgQueryBin, "-W", "-f=${binary:Package}\t${Version}\t${db:Status-Abbrev}\t${db-fsys:Last-Modified}\n"}
+		default:
+			listOpts := map[pb.PackageSystem][]string{
+				pb.PackageSystem_PACKAGE_SYSTEM_YUM: {
+					"list",
+					"installed",
+				},
+			}
+			var err error
+			if out, err = genCmd(p, listOpts); err != nil {
+				return nil, err
+			}
+		}
+		if name != "" {
+			out = append(out, name)
+		}
+		return out, nil
+	}
+
+	// yum doesn't report install times so they come from the rpm database.
+	// Epochs are formatted as yum does to match up with its output.
+	generateInstallTimes = func() []string {
+		return []string{*rpmBin, "-qa", "--queryformat", "%{NAME}.%{ARCH}\t%|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE}\t%{INSTALLTIME}\n"}
 	}
 
 	generateRepoList = func(p pb.PackageSystem) ([]string, error) {
@@ -121,6 +244,56 @@ var (
 		}
 		return genCmd(p, repoOpts)
 	}
+
+	generateSetRepoStatus = func(p pb.PackageSystem, id string, st pb.RepoStatus) ([]string, error) {
+		if p != pb.PackageSystem_PACKAGE_SYSTEM_YUM {
+			return nil, status.Errorf(codes.Unimplemented, "no support for setting repo status for package system enum %d", p)
+		}
+		var opt string
+		switch st {
+		case pb.RepoStatus_REPO_STATUS_ENABLED:
+			opt = "--enable"
+		case pb.RepoStatus_REPO_STATUS_DISABLED:
+			opt = "--disable"
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid repo status %d", st)
+		}
+		return []string{*yumConfigManagerBin, opt, id}, nil
+	}
+
+	// The commands returned are run in order.
+	generateCleanCache = func(p pb.PackageSystem, refresh bool) ([][]string, error) {
+		cleanOpts := map[pb.PackageSystem][]string{
+			pb.PackageSystem_PACKAGE_SYSTEM_YUM: {
+				"clean",
+				"all",
+			},
+			pb.PackageSystem_PACKAGE_SYSTEM_APT: {
+				"clean",
+			},
+		}
+		refreshOpts := map[pb.PackageSystem][]string{
+			pb.PackageSystem_PACKAGE_SYSTEM_YUM: {
+				"makecache",
+			},
+			pb.PackageSystem_PACKAGE_SYSTEM_APT: {
+				"update",
+			},
+		}
+		clean, err := genCmd(p, cleanOpts)
+		if err != nil {
+			return nil, err
+		}
+		out := [][]string{clean}
+		if refresh {
+			r, err := genCmd(p, refreshOpts)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, r)
+		}
+		return out, nil
+	}
 )
 
 // server is used to implement the gRPC server
@@ -134,17 +307,30 @@ func validateField(param string, name string) error {
 		return status.Errorf(codes.InvalidArgument, "package %s %q invalid. Cannot start with a dash", param, name)
 	}
 	if name != inputValidateRe.ReplaceAllString(name, "") {
-		return status.Errorf(codes.InvalidArgument, "package %s %q invalid. Must contain only [a-zA-Z0-9_.:-]", param, name)
+		return status.Errorf(codes.InvalidArgument, "package %s %q invalid. Must contain only [a-zA-Z0-9_.:+~-]", param, name)
 	}
 	return nil
 }
 
 func (s *server) Install(ctx context.Context, req *pb.InstallRequest) (*pb.InstallReply, error) {
-	if err := validateField("name", req.Name); err != nil {
+	output, packages, err := install(ctx, req)
+	if err != nil {
 		return nil, err
 	}
+	return &pb.InstallReply{
+		DebugOutput: output,
+		Packages:    packages,
+	}, nil
+}
+
+// install implements Install, running commands with the given extra options
+// and returning stdout and the resulting versions of the package.
+func install(ctx context.Context, req *pb.InstallRequest, opts ...util.Option) (string, []*pb.PackageInfo, error) {
+	if err := validateField("name", req.Name); err != nil {
+		return "", nil, err
+	}
 	if err := validateField("version", req.Version); err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
 	// Unset means YUM.
@@ -153,36 +339,71 @@ func (s *server) Install(ctx context.Context, req *pb.InstallRequest) (*pb.Insta
 	}
 	command, err := generateInstall(req)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
-	run, err := util.RunCommand(ctx, command[0], command[1:])
+	unlock, err := lockTransactions(ctx)
 	if err != nil {
-		return nil, err
+		return "", nil, err
+	}
+	defer unlock()
+
+	run, err := util.RunCommand(ctx, command[0], command[1:], append(commandOptions(req.PackageSystem), opts...)...)
+	if err != nil {
+		return "", nil, err
 	}
 
 	if err := run.Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "error from running - %v\nstdout:\n%s\nstderr:\n%s", err, util.TrimString(run.Stdout.String()), util.TrimString(run.Stderr.String()))
+		return "", nil, status.Errorf(codes.Internal, "error from running - %v\nstdout:\n%s\nstderr:\n%s", err, util.TrimString(run.Stdout.String()), util.TrimString(run.Stderr.String()))
 	}
 
 	// This may return stderr output about repos but unless return code was non-zero we don't care.
-	return &pb.InstallReply{
-		DebugOutput: run.Stdout.String(),
-	}, nil
+	return run.Stdout.String(), installedVersions(ctx, req.PackageSystem, req.Name), nil
 }
 
 // Nevra is of the form n-e:v-r.a (where n is optional since e can be 0 for no epoch).
 var nevraRe = regexp.MustCompile(`^([^-]+-)?[^:]+:[^-]+-[^\.]+\..+$`)
 
 func (s *server) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.UpdateReply, error) {
-	if err := validateField("name", req.Name); err != nil {
+	output, packages, err := update(ctx, req)
+	if err != nil {
 		return nil, err
 	}
+	return &pb.UpdateReply{
+		DebugOutput: output,
+		Packages:    packages,
+	}, nil
+}
+
+// checkInstalled returns an error unless version of package name is installed.
+func checkInstalled(ctx context.Context, p pb.PackageSystem, name string, version string) error {
+	validateCommand, err := generateValidate(p, name, version)
+	if err != nil {
+		return err
+	}
+	run, err := util.RunCommand(ctx, validateCommand[0], validateCommand[1:], commandOptions(p)...)
+	if err != nil {
+		return err
+	}
+	if err := run.Error; err != nil {
+		return status.Errorf(codes.Internal, "package %s at version %s doesn't appear to be installed.\nStderr:\n%s", name, version, util.TrimString(run.Stderr.String()))
+	}
+	if p == pb.PackageSystem_PACKAGE_SYSTEM_APT && run.Stdout.String() != version {
+		return status.Errorf(codes.Internal, "package %s at version %s doesn't appear to be installed. Installed version is %q", name, version, util.TrimString(run.Stdout.String()))
+	}
+	return nil
+}
+
+// update implements Update in the same way as install.
+func update(ctx context.Context, req *pb.UpdateRequest, opts ...util.Option) (string, []*pb.PackageInfo, error) {
+	if err := validateField("name", req.Name); err != nil {
+		return "", nil, err
+	}
 	if err := validateField("old_version", req.OldVersion); err != nil {
-		return nil, err
+		return "", nil, err
 	}
 	if err := validateField("new_version", req.NewVersion); err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
 	// Unset means YUM.
@@ -191,47 +412,49 @@ func (s *server) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.UpdateR
 	}
 
 	// Update doesn't require nevra but we do so validate each version is nevra.
-	if !nevraRe.MatchString(req.OldVersion) {
-		return nil, status.Errorf(codes.Internal, "old_version %q not in nevra format (n-e:v-r.a)", req.OldVersion)
-	}
-	if !nevraRe.MatchString(req.NewVersion) {
-		return nil, status.Errorf(codes.Internal, "new_version %q not in nevra format (n-e:v-r.a)", req.NewVersion)
+	if req.PackageSystem == pb.PackageSystem_PACKAGE_SYSTEM_YUM {
+		if !nevraRe.MatchString(req.OldVersion) {
+			return "", nil, status.Errorf(codes.Internal, "old_version %q not in nevra format (n-e:v-r.a)", req.OldVersion)
+		}
+		if !nevraRe.MatchString(req.NewVersion) {
+			return "", nil, status.Errorf(codes.Internal, "new_version %q not in nevra format (n-e:v-r.a)", req.NewVersion)
+		}
 	}
 
-	// We can generate both commands since errors duplicate here.
-	validateCommand, valErr := generateValidate(req)
-	updateCommand, upErr := generateUpdate(req)
-	if valErr != nil || upErr != nil {
-		return nil, fmt.Errorf("%v %v", valErr, upErr)
+	updateCommand, err := generateUpdate(req)
+	if err != nil {
+		return "", nil, err
 	}
 
-	// First need to validate the old version is what we expect.
-	run, err := util.RunCommand(ctx, validateCommand[0], validateCommand[1:])
+	// Hold the lock across validation so nothing changes in between.
+	unlock, err := lockTransactions(ctx)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
-	if err := run.Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "package %s at version %s doesn't appear to be installed.\nStderr:\n%s", req.Name, req.OldVersion, util.TrimString(run.Stderr.String()))
+	defer unlock()
+
+	// First need to validate the old version is what we expect.
+	if err := checkInstalled(ctx, req.PackageSystem, req.Name, req.OldVersion); err != nil {
+		return "", nil, err
 	}
 
 	// A 0 return means we're ok to proceed.
-	run, err = util.RunCommand(ctx, updateCommand[0], updateCommand[1:])
+	run, err := util.RunCommand(ctx, updateCommand[0], updateCommand[1:], append(commandOptions(req.PackageSystem), opts...)...)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 	if err := run.Error; err != nil {
-		return nil, status.Errorf(codes.Internal, "error from running %q: %v", updateCommand, err)
+		return "", nil, status.Errorf(codes.Internal, "error from running %q: %v", updateCommand, err)
 	}
 
 	// This may return stderr output about repos but unless return code was non-zero we don't care.
-	return &pb.UpdateReply{
-		DebugOutput: run.Stdout.String(),
-	}, nil
+	return run.Stdout.String(), installedVersions(ctx, req.PackageSystem, req.Name), nil
 }
 
 func parseListInstallOutput(p pb.PackageSystem, r io.Reader) (*pb.ListInstalledReply, error) {
 	parsers := map[pb.PackageSystem]func(r io.Reader) (*pb.ListInstalledReply, error){
 		pb.PackageSystem_PACKAGE_SYSTEM_YUM: parseYumListInstallOutput,
+		pb.PackageSystem_PACKAGE_SYSTEM_APT: parseDpkgListInstallOutput,
 	}
 	parser, ok := parsers[p]
 	if !ok {
@@ -299,13 +522,108 @@ func parseYumListInstallOutput(r io.Reader) (*pb.ListInstalledReply, error) {
 	return reply, nil
 }
 
+// parseDpkgListInstallOutput parses the output of dpkg-query as generated
+// by generateListInstalled, skipping anything not actually installed.
+func parseDpkgListInstallOutput(r io.Reader) (*pb.ListInstalledReply, error) {
+	scanner := bufio.NewScanner(r)
+	reply := &pb.ListInstalledReply{}
+	for scanner.Scan() {
+		text := scanner.Text()
+		fields := strings.Split(text, "\t")
+		if len(fields) != 4 {
+			return nil, status.Errorf(codes.Internal, "invalid input line. Expecting 4 fields and got %q", text)
+		}
+		// The status is want/status/error flags and only "ii" is installed.
+		if !strings.HasPrefix(fields[2], "ii") {
+			continue
+		}
+		info := &pb.PackageInfo{
+			Name:    fields[0],
+			Version: fields[1],
+		}
+		if fields[3] != "" {
+			secs, err := strconv.ParseInt(fields[3], 10, 64)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "invalid install time in line %q: %v", text, err)
+			}
+			info.InstallTime = timestamppb.New(time.Unix(secs, 0))
+		}
+		reply.Packages = append(reply.Packages, info)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "parsing error:\n%v", err)
+	}
+	return reply, nil
+}
+
+// addYumInstallTimes sets the install time of each package in reply from
+// the rpm database. Packages rpm doesn't know about are left without one.
+func addYumInstallTimes(ctx context.Context, reply *pb.ListInstalledReply) error {
+	command := generateInstallTimes()
+	run, err := util.RunCommand(ctx, command[0], command[1:])
+	if err != nil {
+		return err
+	}
+	if err := run.Error; err != nil {
+		return status.Errorf(codes.Internal, "error from running %q: %v", command, err)
+	}
+
+	times := make(map[string]*timestamppb.Timestamp)
+	scanner := bufio.NewScanner(run.Stdout)
+	for scanner.Scan() {
+		text := scanner.Text()
+		fields := strings.Split(text, "\t")
+		if len(fields) != 3 {
+			return status.Errorf(codes.Internal, "invalid rpm output line %q", text)
+		}
+		secs, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return status.Errorf(codes.Internal, "invalid install time in rpm output line %q: %v", text, err)
+		}
+		// yum doesn't show zero epochs.
+		version := strings.TrimPrefix(fields[1], "0:")
+		times[fields[0]+" "+version] = timestamppb.New(time.Unix(secs, 0))
+	}
+	if err := scanner.Err(); err != nil {
+		return status.Errorf(codes.Internal, "parsing error:\n%v", err)
+	}
+	for _, p := range reply.Packages {
+		p.InstallTime = times[p.Name+" "+p.Version]
+	}
+	return nil
+}
+
+// noMatches returns true if run failed only because nothing matched the
+// name in a ListInstalled request.
+func noMatches(p pb.PackageSystem, run *util.CommandRun) bool {
+	msgs := map[pb.PackageSystem]string{
+		pb.PackageSystem_PACKAGE_SYSTEM_YUM: "No matching Packages",
+		pb.PackageSystem_PACKAGE_SYSTEM_APT: "no packages found matching",
+	}
+	return strings.Contains(run.Stderr.String(), msgs[p])
+}
+
 func (s *server) ListInstalled(ctx context.Context, req *pb.ListInstalledRequest) (*pb.ListInstalledReply, error) {
 	// Unset means YUM.
 	if req.PackageSystem == pb.PackageSystem_PACKAGE_SYSTEM_UNKNOWN {
 		req.PackageSystem = pb.PackageSystem_PACKAGE_SYSTEM_YUM
 	}
+	if req.Name != "" {
+		if strings.HasPrefix(req.Name, "-") {
+			return nil, status.Errorf(codes.InvalidArgument, "package name %q invalid. Cannot start with a dash", req.Name)
+		}
+		if req.Name != patternValidateRe.ReplaceAllString(req.Name, "") {
+			return nil, status.Errorf(codes.InvalidArgument, "package name %q invalid. Must contain only [a-zA-Z0-9_.:+*?-]", req.Name)
+		}
+	}
 
-	command, err := generateListInstalled(req.PackageSystem)
+	return listInstalled(ctx, req.PackageSystem, req.Name)
+}
+
+// listInstalled returns the installed packages matching name (or all of
+// them if it's empty).
+func listInstalled(ctx context.Context, p pb.PackageSystem, name string) (*pb.ListInstalledReply, error) {
+	command, err := generateListInstalled(p, name)
 	if err != nil {
 		return nil, err
 	}
@@ -316,10 +634,34 @@ func (s *server) ListInstalled(ctx context.Context, req *pb.ListInstalledRequest
 		return nil, err
 	}
 	if err := run.Error; err != nil {
+		if name != "" && noMatches(p, run) {
+			return &pb.ListInstalledReply{}, nil
+		}
 		return nil, status.Errorf(codes.Internal, "error from running %q: %v", command, err)
 	}
 
-	return parseListInstallOutput(req.PackageSystem, run.Stdout)
+	reply, err := parseListInstallOutput(p, run.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	if p == pb.PackageSystem_PACKAGE_SYSTEM_YUM {
+		if err := addYumInstallTimes(ctx, reply); err != nil {
+			return nil, err
+		}
+	}
+	return reply, nil
+}
+
+// installedVersions returns the installed packages matching name after a
+// transaction. As the transaction has already happened failures are only
+// logged and nil returned.
+func installedVersions(ctx context.Context, p pb.PackageSystem, name string) []*pb.PackageInfo {
+	reply, err := listInstalled(ctx, p, name)
+	if err != nil {
+		logr.FromContextOrDiscard(ctx).Error(err, "can't list installed packages", "name", name)
+		return nil
+	}
+	return reply.Packages
 }
 
 func parseRepoListOutput(p pb.PackageSystem, r io.Reader) (*pb.RepoListReply, error) {
@@ -409,8 +751,16 @@ func (s *server) RepoList(ctx context.Context, req *pb.RepoListRequest) (*pb.Rep
 	if req.PackageSystem == pb.PackageSystem_PACKAGE_SYSTEM_UNKNOWN {
 		req.PackageSystem = pb.PackageSystem_PACKAGE_SYSTEM_YUM
 	}
+	return repoList(ctx, req.PackageSystem)
+}
+
+func repoList(ctx context.Context, p pb.PackageSystem) (*pb.RepoListReply, error) {
+	// APT has no command to list sources so the files are read directly.
+	if p == pb.PackageSystem_PACKAGE_SYSTEM_APT {
+		return aptRepoList()
+	}
 
-	command, err := generateRepoList(req.PackageSystem)
+	command, err := generateRepoList(p)
 	if err != nil {
 		return nil, err
 	}
@@ -423,7 +773,7 @@ func (s *server) RepoList(ctx context.Context, req *pb.RepoListRequest) (*pb.Rep
 		return nil, status.Errorf(codes.Internal, "error from running %q: %v\nstdout:\n%s\nstderr:\n%s", command, err, util.TrimString(run.Stdout.String()), util.TrimString(run.Stderr.String()))
 	}
 
-	return parseRepoListOutput(req.PackageSystem, run.Stdout)
+	return parseRepoListOutput(p, run.Stdout)
 }
 
 // Install is called to expose this handler to the gRPC server
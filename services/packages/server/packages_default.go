@@ -23,4 +23,13 @@ import (
 	"flag"
 )
 
-var yumBin = flag.String("yum-bin", "false", "Path to yum binary (NOTE: no support on this platform)")
+var (
+	yumBin       = flag.String("yum-bin", "false", "Path to yum binary (NOTE: no support on this platform)")
+	rpmBin       = flag.String("rpm-bin", "false", "Path to rpm binary (NOTE: no support on this platform)")
+	dpkgQueryBin = flag.String("dpkg-query-bin", "false", "Path to dpkg-query binary (NOTE: no support on this platform)")
+	aptGetBin    = flag.String("apt-get-bin", "false", "Path to apt-get binary (NOTE: no support on this platform)")
+
+	yumConfigManagerBin = flag.String("yum-config-manager-bin", "false", "Path to yum-config-manager binary (NOTE: no support on this platform)")
+	aptSourcesList      = flag.String("apt-sources-list", "", "Path to the APT sources list (NOTE: no support on this platform)")
+	aptSourcesDir       = flag.String("apt-sources-dir", "", "Path to the directory of additional APT sources (NOTE: no support on this platform)")
+)
@@ -23,4 +23,13 @@ import (
 	"flag"
 )
 
-var yumBin = flag.String("yum-bin", "/usr/bin/yum", "Path to yum binary")
+var (
+	yumBin       = flag.String("yum-bin", "/usr/bin/yum", "Path to yum binary")
+	rpmBin       = flag.String("rpm-bin", "/usr/bin/rpm", "Path to rpm binary")
+	dpkgQueryBin = flag.String("dpkg-query-bin", "/usr/bin/dpkg-query", "Path to dpkg-query binary")
+	aptGetBin    = flag.String("apt-get-bin", "/usr/bin/apt-get", "Path to apt-get binary")
+
+	yumConfigManagerBin = flag.String("yum-config-manager-bin", "/usr/bin/yum-config-manager", "Path to yum-config-manager binary")
+	aptSourcesList      = flag.String("apt-sources-list", "/etc/apt/sources.list", "Path to the APT sources list")
+	aptSourcesDir       = flag.String("apt-sources-dir", "/etc/apt/sources.list.d", "Path to the directory of additional APT sources")
+)
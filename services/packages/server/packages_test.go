@@ -19,16 +19,20 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	pb "github.com/Snowflake-Labs/sansshell/services/packages"
 	"github.com/Snowflake-Labs/sansshell/testing/testutil"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/testing/protocmp"
@@ -90,7 +94,7 @@ func TestInstall(t *testing.T) {
 		{
 			name: "bad package system",
 			req: &pb.InstallRequest{
-				PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_YUM + 1,
+				PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT + 1,
 				Name:          "package",
 				Version:       "1.2.3",
 			},
@@ -214,18 +218,18 @@ func TestUpdate(t *testing.T) {
 	savedGenerateValidate := generateValidate
 	savedGenerateUpdate := generateUpdate
 	var cmdLine, validateCmdLine string
-	generateValidate = func(u *pb.UpdateRequest) ([]string, error) {
+	generateValidate = func(p pb.PackageSystem, name string, version string) ([]string, error) {
 		// Capture what was generated so we can validate it.
-		out, err := savedGenerateValidate(u)
+		out, err := savedGenerateValidate(p, name, version)
 		if err != nil {
 			return nil, err
 		}
 		validateCmdLine = strings.Join(out, " ")
 		return []string{testutil.ResolvePath(t, "echo"), "-n", testdataInput}, nil
 	}
-	badValidate := func(u *pb.UpdateRequest) ([]string, error) {
+	badValidate := func(p pb.PackageSystem, name string, version string) ([]string, error) {
 		// Capture what was generated so we can validate it.
-		out, err := savedGenerateValidate(u)
+		out, err := savedGenerateValidate(p, name, version)
 		if err != nil {
 			return nil, err
 		}
@@ -255,7 +259,7 @@ func TestUpdate(t *testing.T) {
 		{
 			name: "bad package system",
 			req: &pb.UpdateRequest{
-				PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_YUM + 1,
+				PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT + 1,
 				Name:          "package",
 				OldVersion:    "0:1-1.2.3",
 				NewVersion:    "0:1-4.5.6",
@@ -264,7 +268,7 @@ func TestUpdate(t *testing.T) {
 		{
 			name: "bad old version - nevra",
 			req: &pb.UpdateRequest{
-				PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_YUM + 1,
+				PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT + 1,
 				Name:          "package",
 				OldVersion:    "1.2.3",
 				NewVersion:    "0:1-4.5.6",
@@ -273,7 +277,7 @@ func TestUpdate(t *testing.T) {
 		{
 			name: "bad new version - nevra",
 			req: &pb.UpdateRequest{
-				PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_YUM + 1,
+				PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT + 1,
 				Name:          "package",
 				OldVersion:    "0:1-1.2.3",
 				NewVersion:    "4.5.6",
@@ -395,7 +399,7 @@ func TestUpdate(t *testing.T) {
 	for _, tc := range []struct {
 		name     string
 		generate func(*pb.UpdateRequest) ([]string, error)
-		validate func(*pb.UpdateRequest) ([]string, error)
+		validate func(pb.PackageSystem, string, string) ([]string, error)
 	}{
 		{
 			name: "bad command",
@@ -405,7 +409,7 @@ func TestUpdate(t *testing.T) {
 		},
 		{
 			name: "bad path - validate",
-			validate: func(*pb.UpdateRequest) ([]string, error) {
+			validate: func(pb.PackageSystem, string, string) ([]string, error) {
 				return []string{"bad path"}, nil
 			},
 		},
@@ -457,18 +461,23 @@ func TestListInstalled(t *testing.T) {
 	testdataGolden := "./testdata/yum-installed.textproto"
 
 	savedGenerateListInstalled := generateListInstalled
+	savedGenerateInstallTimes := generateInstallTimes
 	var cmdLine string
-	generateListInstalled = func(p pb.PackageSystem) ([]string, error) {
+	generateListInstalled = func(p pb.PackageSystem, name string) ([]string, error) {
 		// Capture what was generated so we can validate it.
-		out, err := savedGenerateListInstalled(p)
+		out, err := savedGenerateListInstalled(p, name)
 		if err != nil {
 			return nil, err
 		}
 		cmdLine = strings.Join(out, " ")
 		return []string{testutil.ResolvePath(t, "cat"), testdataInput}, nil
 	}
+	generateInstallTimes = func() []string {
+		return []string{testutil.ResolvePath(t, "cat"), "./testdata/rpm-installtimes.out"}
+	}
 	t.Cleanup(func() {
 		generateListInstalled = savedGenerateListInstalled
+		generateInstallTimes = savedGenerateInstallTimes
 	})
 
 	input, err := os.ReadFile(testdataGolden)
@@ -485,7 +494,7 @@ func TestListInstalled(t *testing.T) {
 
 	// Test 0: Specify a bad package system and get an error.
 	resp, err := client.ListInstalled(ctx, &pb.ListInstalledRequest{
-		PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_YUM + 1,
+		PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT + 1,
 	})
 	if err == nil {
 		t.Fatalf("didn't get an error as expected for a bad package enum. Instead got %+v", resp)
@@ -514,9 +523,25 @@ func TestListInstalled(t *testing.T) {
 
 	testutil.DiffErr("basic package list request yum", resp, testdata, t, sortEntries)
 
+	// Test 2a: Search for a name.
+	wantCmdLine = fmt.Sprintf("%s list installed java*", *yumBin)
+	_, err = client.ListInstalled(ctx, &pb.ListInstalledRequest{
+		Name: "java*",
+	})
+	testutil.FatalOnErr("package search request", err, t)
+	if got, want := cmdLine, wantCmdLine; got != want {
+		t.Fatalf("command lines differ. Got %q Want %q", got, want)
+	}
+	for _, name := range []string{"-java", "java;rm", "java x"} {
+		resp, err = client.ListInstalled(ctx, &pb.ListInstalledRequest{
+			Name: name,
+		})
+		testutil.FatalOnNoErr(fmt.Sprintf("bad name %q - resp %v", name, resp), err, t)
+	}
+
 	// Test 3: Now try with bad input. Should error out.
 	for _, b := range []string{testdataInputBad, testdataInputBad2, testdataInputBad3} {
-		generateListInstalled = func(pb.PackageSystem) ([]string, error) {
+		generateListInstalled = func(pb.PackageSystem, string) ([]string, error) {
 			return []string{testutil.ResolvePath(t, "cat"), b}, nil
 		}
 		resp, err = client.ListInstalled(ctx, &pb.ListInstalledRequest{
@@ -529,23 +554,23 @@ func TestListInstalled(t *testing.T) {
 	// Test 4: Permutations of bad commands/exit codes, stderr output.
 	for _, tc := range []struct {
 		name     string
-		generate func(pb.PackageSystem) ([]string, error)
+		generate func(pb.PackageSystem, string) ([]string, error)
 	}{
 		{
 			name: "non-existant binary",
-			generate: func(pb.PackageSystem) ([]string, error) {
+			generate: func(pb.PackageSystem, string) ([]string, error) {
 				return []string{"/non-existant-binary"}, nil
 			},
 		},
 		{
 			name: "bad path",
-			generate: func(pb.PackageSystem) ([]string, error) {
+			generate: func(pb.PackageSystem, string) ([]string, error) {
 				return []string{"non-existant-binary"}, nil
 			},
 		},
 		{
 			name: "non-zero exit",
-			generate: func(pb.PackageSystem) ([]string, error) {
+			generate: func(pb.PackageSystem, string) ([]string, error) {
 				return []string{testutil.ResolvePath(t, "false")}, nil
 			},
 		},
@@ -562,6 +587,93 @@ func TestListInstalled(t *testing.T) {
 	}
 }
 
+func TestListInstalledAPT(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewPackagesClient(conn)
+
+	input, err := os.ReadFile("./testdata/dpkg-installed.textproto")
+	testutil.FatalOnErr("can't read testdata golden", err, t)
+	golden := &pb.ListInstalledReply{}
+	err = prototext.Unmarshal(input, golden)
+	testutil.FatalOnErr("Can't unmarshall test data", err, t)
+
+	savedGenerateListInstalled := generateListInstalled
+	t.Cleanup(func() {
+		generateListInstalled = savedGenerateListInstalled
+	})
+	sh := testutil.ResolvePath(t, "sh")
+
+	for _, tc := range []struct {
+		name        string
+		req         *pb.ListInstalledRequest
+		command     []string
+		wantCmdLine string
+		want        *pb.ListInstalledReply
+		wantErr     bool
+	}{
+		{
+			name:        "list",
+			req:         &pb.ListInstalledRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT},
+			command:     []string{testutil.ResolvePath(t, "cat"), "./testdata/dpkg-installed.out"},
+			wantCmdLine: fmt.Sprintf("%s -W -f=${binary:Package}\t${Version}\t${db:Status-Abbrev}\t${db-fsys:Last-Modified}\n", *dpkgQueryBin),
+			want:        golden,
+		},
+		{
+			name:        "search",
+			req:         &pb.ListInstalledRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "libc6*"},
+			command:     []string{testutil.ResolvePath(t, "cat"), "./testdata/dpkg-installed.out"},
+			wantCmdLine: fmt.Sprintf("%s -W -f=${binary:Package}\t${Version}\t${db:Status-Abbrev}\t${db-fsys:Last-Modified}\n libc6*", *dpkgQueryBin),
+			want:        golden,
+		},
+		{
+			name:    "search without matches",
+			req:     &pb.ListInstalledRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "nosuchpackage"},
+			command: []string{sh, "-c", "echo 'dpkg-query: no packages found matching nosuchpackage' >&2; exit 1"},
+			want:    &pb.ListInstalledReply{},
+		},
+		{
+			name:    "other failure",
+			req:     &pb.ListInstalledRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "libc6"},
+			command: []string{sh, "-c", "echo 'dpkg-query: database is locked' >&2; exit 1"},
+			wantErr: true,
+		},
+		{
+			name:    "bad output",
+			req:     &pb.ListInstalledRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT},
+			command: []string{testutil.ResolvePath(t, "cat"), "./testdata/dpkg-installed-bad.out"},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var cmdLine string
+			generateListInstalled = func(p pb.PackageSystem, name string) ([]string, error) {
+				out, err := savedGenerateListInstalled(p, name)
+				if err != nil {
+					return nil, err
+				}
+				cmdLine = strings.Join(out, " ")
+				return tc.command, nil
+			}
+			resp, err := client.ListInstalled(ctx, tc.req)
+			if tc.wantErr {
+				testutil.FatalOnNoErr(fmt.Sprintf("%s - resp %v", tc.name, resp), err, t)
+				return
+			}
+			testutil.FatalOnErr(tc.name, err, t)
+			testutil.DiffErr(tc.name, resp, tc.want, t)
+			if tc.wantCmdLine != "" && cmdLine != tc.wantCmdLine {
+				t.Fatalf("command lines differ. Got %q Want %q", cmdLine, tc.wantCmdLine)
+			}
+		})
+	}
+}
+
 func TestRepoList(t *testing.T) {
 	var err error
 	ctx := context.Background()
@@ -602,7 +714,7 @@ func TestRepoList(t *testing.T) {
 
 	// Test 0: Specify a bad package system and get an error.
 	resp, err := client.RepoList(ctx, &pb.RepoListRequest{
-		PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_YUM + 1,
+		PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT + 1,
 	})
 	testutil.FatalOnNoErr(fmt.Sprintf("bad package enum - resp %v", resp), err, t)
 	t.Log(err)
@@ -663,3 +775,471 @@ func TestRepoList(t *testing.T) {
 		generateRepoList = saveGenerate
 	}
 }
+
+func TestTransactionCommands(t *testing.T) {
+	apt := fmt.Sprintf("%s -o DPkg::Lock::Timeout=300", *aptGetBin)
+	for _, tc := range []struct {
+		name     string
+		generate func() ([]string, error)
+		want     string
+	}{
+		{
+			name: "apt install",
+			generate: func() ([]string, error) {
+				return generateInstall(&pb.InstallRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "openssl", Version: "3.0.17-1~deb12u2", Repo: "bookworm-backports"})
+			},
+			want: apt + " install -y -t bookworm-backports openssl=3.0.17-1~deb12u2",
+		},
+		{
+			name: "apt update",
+			generate: func() ([]string, error) {
+				return generateUpdate(&pb.UpdateRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "openssl", OldVersion: "3.0.16", NewVersion: "3.0.17"})
+			},
+			want: apt + " install -y --only-upgrade openssl=3.0.17",
+		},
+		{
+			name: "apt validate",
+			generate: func() ([]string, error) {
+				return generateValidate(pb.PackageSystem_PACKAGE_SYSTEM_APT, "openssl", "3.0.16")
+			},
+			want: *dpkgQueryBin + " -W -f=${Version} openssl",
+		},
+		{
+			name: "yum remove",
+			generate: func() ([]string, error) {
+				return generateRemove(&pb.RemoveRequest{Name: "openssl", Version: "1:1.0.2k-25.el7_9.x86_64", PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_YUM})
+			},
+			want: *yumBin + " remove -y openssl-1:1.0.2k-25.el7_9.x86_64",
+		},
+		{
+			name: "apt remove",
+			generate: func() ([]string, error) {
+				return generateRemove(&pb.RemoveRequest{Name: "openssl", PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT})
+			},
+			want: apt + " remove -y openssl",
+		},
+		{
+			name: "yum update all",
+			generate: func() ([]string, error) {
+				cmds, err := generateUpdateAll(&pb.UpdateAllRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_YUM, Repo: "updates"})
+				if err != nil {
+					return nil, err
+				}
+				return strings.Split(strings.Join(cmds[0], " "), " "), nil
+			},
+			want: *yumBin + " update -y --enablerepo=updates",
+		},
+		{
+			name: "apt update all",
+			generate: func() ([]string, error) {
+				cmds, err := generateUpdateAll(&pb.UpdateAllRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT})
+				if err != nil {
+					return nil, err
+				}
+				var out []string
+				for _, c := range cmds {
+					out = append(out, strings.Join(c, " "))
+				}
+				return []string{strings.Join(out, " && ")}, nil
+			},
+			want: apt + " update && " + apt + " upgrade -y",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := tc.generate()
+			testutil.FatalOnErr(tc.name, err, t)
+			if got := strings.Join(out, " "); got != tc.want {
+				t.Fatalf("command lines differ. Got %q Want %q", got, tc.want)
+			}
+		})
+	}
+
+	_, err := generateUpdateAll(&pb.UpdateAllRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT + 1})
+	testutil.FatalOnNoErr("update all bad package system", err, t)
+	_, err = generateRemove(&pb.RemoveRequest{Name: "openssl", PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT + 1})
+	testutil.FatalOnNoErr("remove bad package system", err, t)
+}
+
+func TestTransactions(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewPackagesClient(conn)
+
+	input, err := os.ReadFile("./testdata/dpkg-installed.textproto")
+	testutil.FatalOnErr("can't read testdata golden", err, t)
+	golden := &pb.ListInstalledReply{}
+	err = prototext.Unmarshal(input, golden)
+	testutil.FatalOnErr("Can't unmarshall test data", err, t)
+
+	sh := testutil.ResolvePath(t, "sh")
+	transaction := []string{sh, "-c", "echo running; echo warning >&2"}
+	savedInstall, savedUpdate, savedValidate := generateInstall, generateUpdate, generateValidate
+	savedRemove, savedUpdateAll, savedList := generateRemove, generateUpdateAll, generateListInstalled
+	t.Cleanup(func() {
+		generateInstall, generateUpdate, generateValidate = savedInstall, savedUpdate, savedValidate
+		generateRemove, generateUpdateAll, generateListInstalled = savedRemove, savedUpdateAll, savedList
+	})
+	generateInstall = func(*pb.InstallRequest) ([]string, error) { return transaction, nil }
+	generateUpdate = func(*pb.UpdateRequest) ([]string, error) { return transaction, nil }
+	generateRemove = func(*pb.RemoveRequest) ([]string, error) { return transaction, nil }
+	generateUpdateAll = func(*pb.UpdateAllRequest) ([][]string, error) {
+		return [][]string{{sh, "-c", "echo refreshing"}, transaction}, nil
+	}
+	// The installed version is always 1.0.
+	generateValidate = func(pb.PackageSystem, string, string) ([]string, error) {
+		return []string{testutil.ResolvePath(t, "printf"), "1.0"}, nil
+	}
+	generateListInstalled = func(pb.PackageSystem, string) ([]string, error) {
+		return []string{testutil.ResolvePath(t, "cat"), "./testdata/dpkg-installed.out"}, nil
+	}
+
+	// Collects a transaction stream, returning all the output and the final reply.
+	type stream interface {
+		Recv() (*pb.TransactionReply, error)
+	}
+	collect := func(s stream, err error) (string, *pb.TransactionReply, error) {
+		if err != nil {
+			return "", nil, err
+		}
+		var output string
+		var last *pb.TransactionReply
+		for {
+			resp, err := s.Recv()
+			if err == io.EOF {
+				return output, last, nil
+			}
+			if err != nil {
+				return output, nil, err
+			}
+			output += string(resp.Output)
+			last = resp
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		run        func() (string, *pb.TransactionReply, error)
+		wantOutput []string
+		wantErr    bool
+	}{
+		{
+			name: "install",
+			run: func() (string, *pb.TransactionReply, error) {
+				return collect(client.StreamingInstall(ctx, &pb.InstallRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "libc6", Version: "2.0"}))
+			},
+			wantOutput: []string{"running\n", "warning\n"},
+		},
+		{
+			name: "update",
+			run: func() (string, *pb.TransactionReply, error) {
+				return collect(client.StreamingUpdate(ctx, &pb.UpdateRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "libc6", OldVersion: "1.0", NewVersion: "2.0"}))
+			},
+			wantOutput: []string{"running\n", "warning\n"},
+		},
+		{
+			name: "update wrong old version",
+			run: func() (string, *pb.TransactionReply, error) {
+				return collect(client.StreamingUpdate(ctx, &pb.UpdateRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "libc6", OldVersion: "0.9", NewVersion: "2.0"}))
+			},
+			wantErr: true,
+		},
+		{
+			name: "remove",
+			run: func() (string, *pb.TransactionReply, error) {
+				return collect(client.Remove(ctx, &pb.RemoveRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "libc6", Version: "1.0"}))
+			},
+			wantOutput: []string{"running\n", "warning\n"},
+		},
+		{
+			name: "remove wrong version",
+			run: func() (string, *pb.TransactionReply, error) {
+				return collect(client.Remove(ctx, &pb.RemoveRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "libc6", Version: "0.9"}))
+			},
+			wantErr: true,
+		},
+		{
+			name: "remove bad name",
+			run: func() (string, *pb.TransactionReply, error) {
+				return collect(client.Remove(ctx, &pb.RemoveRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "-libc6"}))
+			},
+			wantErr: true,
+		},
+		{
+			name: "update all",
+			run: func() (string, *pb.TransactionReply, error) {
+				return collect(client.UpdateAll(ctx, &pb.UpdateAllRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT}))
+			},
+			wantOutput: []string{"refreshing\n", "running\n", "warning\n"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			output, last, err := tc.run()
+			if tc.wantErr {
+				testutil.FatalOnNoErr(tc.name, err, t)
+				return
+			}
+			testutil.FatalOnErr(tc.name, err, t)
+			for _, want := range tc.wantOutput {
+				if !strings.Contains(output, want) {
+					t.Fatalf("output %q doesn't contain %q", output, want)
+				}
+			}
+			testutil.DiffErr(tc.name, last, &pb.TransactionReply{Packages: golden.Packages}, t)
+		})
+	}
+
+	// The unary RPCs return the resulting versions too.
+	resp, err := client.Install(ctx, &pb.InstallRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "libc6", Version: "2.0"})
+	testutil.FatalOnErr("Install", err, t)
+	testutil.DiffErr("Install", resp, &pb.InstallReply{DebugOutput: "running\n", Packages: golden.Packages}, t)
+
+	// Transactions wait for each other, but not past the deadline. This
+	// calls remove directly rather than through the server, whose handler
+	// could otherwise still be running when the generators are restored.
+	unlock, err := lockTransactions(ctx)
+	testutil.FatalOnErr("lockTransactions", err, t)
+	tctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, _, err = remove(tctx, &pb.RemoveRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Name: "libc6"})
+	unlock()
+	if got, want := status.Code(err), codes.DeadlineExceeded; got != want {
+		t.Fatalf("unexpected code waiting for lock. got %v want %v err %v", got, want, err)
+	}
+}
+
+func TestRepoListAPT(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewPackagesClient(conn)
+
+	savedList, savedDir := *aptSourcesList, *aptSourcesDir
+	t.Cleanup(func() { *aptSourcesList, *aptSourcesDir = savedList, savedDir })
+
+	input, err := os.ReadFile("./testdata/apt-repolist.textproto")
+	testutil.FatalOnErr("can't read testdata golden", err, t)
+	golden := &pb.RepoListReply{}
+	err = prototext.Unmarshal(input, golden)
+	testutil.FatalOnErr("can't unmarshal test data", err, t)
+
+	for _, tc := range []struct {
+		name    string
+		list    string
+		dir     string
+		want    *pb.RepoListReply
+		wantErr bool
+	}{
+		{
+			name: "sources",
+			list: "./testdata/apt/sources.list",
+			dir:  "./testdata/apt/sources.list.d",
+			want: golden,
+		},
+		{
+			name: "no sources",
+			list: "./testdata/apt/missing.list",
+			dir:  "./testdata/apt/missing.d",
+			want: &pb.RepoListReply{},
+		},
+		{
+			name:    "bad source",
+			list:    "./testdata/apt-bad/sources.list",
+			dir:     "./testdata/apt/missing.d",
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			*aptSourcesList, *aptSourcesDir = tc.list, tc.dir
+			resp, err := client.RepoList(ctx, &pb.RepoListRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT})
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if tc.wantErr {
+				return
+			}
+			testutil.DiffErr(tc.name, resp, tc.want, t)
+		})
+	}
+}
+
+func TestSetRepoStatus(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewPackagesClient(conn)
+
+	input, err := os.ReadFile("./testdata/yum-repolist.textproto")
+	testutil.FatalOnErr("can't read testdata golden", err, t)
+	golden := &pb.RepoListReply{}
+	err = prototext.Unmarshal(input, golden)
+	testutil.FatalOnErr("can't unmarshal test data", err, t)
+	repos := make(map[string]*pb.Repo)
+	for _, r := range golden.Repos {
+		repos[r.Id] = r
+	}
+
+	savedSetRepoStatus, savedRepoList := generateSetRepoStatus, generateRepoList
+	t.Cleanup(func() { generateSetRepoStatus, generateRepoList = savedSetRepoStatus, savedRepoList })
+	var cmdLine string
+	generateSetRepoStatus = func(p pb.PackageSystem, id string, st pb.RepoStatus) ([]string, error) {
+		out, err := savedSetRepoStatus(p, id, st)
+		if err != nil {
+			return nil, err
+		}
+		cmdLine = strings.Join(out, " ")
+		return []string{testutil.ResolvePath(t, "true")}, nil
+	}
+	// The listing never changes so only requests matching it succeed.
+	generateRepoList = func(p pb.PackageSystem) ([]string, error) {
+		return []string{testutil.ResolvePath(t, "cat"), "./testdata/yum-repolist.out"}, nil
+	}
+
+	for _, tc := range []struct {
+		name        string
+		req         *pb.SetRepoStatusRequest
+		wantCmdLine string
+		want        *pb.Repo
+		wantErr     codes.Code
+	}{
+		{
+			name:        "disable",
+			req:         &pb.SetRepoStatusRequest{Id: "C7.0.1406-base", Status: pb.RepoStatus_REPO_STATUS_DISABLED},
+			wantCmdLine: *yumConfigManagerBin + " --disable C7.0.1406-base",
+			want:        repos["C7.0.1406-base/x86_64"],
+		},
+		{
+			name:        "enable",
+			req:         &pb.SetRepoStatusRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_YUM, Id: "adoptopenjdk", Status: pb.RepoStatus_REPO_STATUS_ENABLED},
+			wantCmdLine: *yumConfigManagerBin + " --enable adoptopenjdk",
+			want:        repos["adoptopenjdk"],
+		},
+		{
+			name:    "status not applied",
+			req:     &pb.SetRepoStatusRequest{Id: "adoptopenjdk", Status: pb.RepoStatus_REPO_STATUS_DISABLED},
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "unknown repo",
+			req:     &pb.SetRepoStatusRequest{Id: "C7.0.1406", Status: pb.RepoStatus_REPO_STATUS_ENABLED},
+			wantErr: codes.NotFound,
+		},
+		{
+			name:    "no id",
+			req:     &pb.SetRepoStatusRequest{Status: pb.RepoStatus_REPO_STATUS_ENABLED},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad id",
+			req:     &pb.SetRepoStatusRequest{Id: "--setopt=foo", Status: pb.RepoStatus_REPO_STATUS_ENABLED},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "no status",
+			req:     &pb.SetRepoStatusRequest{Id: "adoptopenjdk"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "apt",
+			req:     &pb.SetRepoStatusRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Id: "adoptopenjdk", Status: pb.RepoStatus_REPO_STATUS_ENABLED},
+			wantErr: codes.Unimplemented,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cmdLine = ""
+			resp, err := client.SetRepoStatus(ctx, tc.req)
+			if got, want := status.Code(err), tc.wantErr; got != want {
+				t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, resp, &pb.SetRepoStatusReply{Repo: tc.want}, t)
+			if got, want := cmdLine, tc.wantCmdLine; got != want {
+				t.Fatalf("command lines differ. Got %q Want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCleanCache(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewPackagesClient(conn)
+
+	savedCleanCache := generateCleanCache
+	t.Cleanup(func() { generateCleanCache = savedCleanCache })
+	var cmdLines []string
+	generateCleanCache = func(p pb.PackageSystem, refresh bool) ([][]string, error) {
+		out, err := savedCleanCache(p, refresh)
+		if err != nil {
+			return nil, err
+		}
+		var cmds [][]string
+		for _, c := range out {
+			cmdLines = append(cmdLines, strings.Join(c, " "))
+			cmds = append(cmds, []string{testutil.ResolvePath(t, "echo"), c[len(c)-1]})
+		}
+		return cmds, nil
+	}
+
+	apt := fmt.Sprintf("%s -o DPkg::Lock::Timeout=300", *aptGetBin)
+	for _, tc := range []struct {
+		name         string
+		req          *pb.CleanCacheRequest
+		wantCmdLines []string
+		wantOutput   string
+		wantErr      bool
+	}{
+		{
+			name:         "yum",
+			req:          &pb.CleanCacheRequest{},
+			wantCmdLines: []string{*yumBin + " clean all"},
+			wantOutput:   "all\n",
+		},
+		{
+			name:         "yum refresh",
+			req:          &pb.CleanCacheRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_YUM, Refresh: true},
+			wantCmdLines: []string{*yumBin + " clean all", *yumBin + " makecache"},
+			wantOutput:   "all\nmakecache\n",
+		},
+		{
+			name:         "apt refresh",
+			req:          &pb.CleanCacheRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT, Refresh: true},
+			wantCmdLines: []string{apt + " clean", apt + " update"},
+			wantOutput:   "clean\nupdate\n",
+		},
+		{
+			name:    "bad package system",
+			req:     &pb.CleanCacheRequest{PackageSystem: pb.PackageSystem_PACKAGE_SYSTEM_APT + 1},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cmdLines = nil
+			resp, err := client.CleanCache(ctx, tc.req)
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if tc.wantErr {
+				return
+			}
+			testutil.DiffErr(tc.name, resp, &pb.CleanCacheReply{DebugOutput: tc.wantOutput}, t)
+			testutil.DiffErr(tc.name, cmdLines, tc.wantCmdLines, t)
+		})
+	}
+}
@@ -0,0 +1,264 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/packages"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetRepoStatus enables or disables a repo and returns it as listed
+// afterwards so the change is verified.
+func (s *server) SetRepoStatus(ctx context.Context, req *pb.SetRepoStatusRequest) (*pb.SetRepoStatusReply, error) {
+	if err := validateField("repo id", req.Id); err != nil {
+		return nil, err
+	}
+	// Unset means YUM.
+	if req.PackageSystem == pb.PackageSystem_PACKAGE_SYSTEM_UNKNOWN {
+		req.PackageSystem = pb.PackageSystem_PACKAGE_SYSTEM_YUM
+	}
+	command, err := generateSetRepoStatus(req.PackageSystem, req.Id, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := lockTransactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	logr.FromContextOrDiscard(ctx).Info("setting repo status", "id", req.Id, "status", req.Status)
+	run, err := util.RunCommand(ctx, command[0], command[1:])
+	if err != nil {
+		return nil, err
+	}
+	if err := run.Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "error from running %q: %v\nstderr:\n%s", command, err, util.TrimString(run.Stderr.String()))
+	}
+
+	repos, err := repoList(ctx, req.PackageSystem)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range repos.Repos {
+		// yum lists ids with the arch appended (i.e. base/x86_64).
+		if r.Id != req.Id && !strings.HasPrefix(r.Id, req.Id+"/") {
+			continue
+		}
+		if r.Status != req.Status {
+			return nil, status.Errorf(codes.Internal, "repo %s is %s after setting it to %s", r.Id, r.Status, req.Status)
+		}
+		return &pb.SetRepoStatusReply{Repo: r}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "no repo with id %s", req.Id)
+}
+
+// CleanCache cleans the package system's cache and optionally refreshes
+// the metadata.
+func (s *server) CleanCache(ctx context.Context, req *pb.CleanCacheRequest) (*pb.CleanCacheReply, error) {
+	// Unset means YUM.
+	if req.PackageSystem == pb.PackageSystem_PACKAGE_SYSTEM_UNKNOWN {
+		req.PackageSystem = pb.PackageSystem_PACKAGE_SYSTEM_YUM
+	}
+	commands, err := generateCleanCache(req.PackageSystem, req.Refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := lockTransactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var output strings.Builder
+	for _, command := range commands {
+		run, err := util.RunCommand(ctx, command[0], command[1:], commandOptions(req.PackageSystem)...)
+		if err != nil {
+			return nil, err
+		}
+		if err := run.Error; err != nil {
+			return nil, status.Errorf(codes.Internal, "error from running %q: %v\nstderr:\n%s", command, err, util.TrimString(run.Stderr.String()))
+		}
+		output.WriteString(run.Stdout.String())
+	}
+	return &pb.CleanCacheReply{DebugOutput: output.String()}, nil
+}
+
+// aptRepoList returns the entries in the APT sources list and the *.list
+// and *.sources files in the sources directory. Commented out entries are
+// returned as disabled.
+func aptRepoList() (*pb.RepoListReply, error) {
+	files := []string{*aptSourcesList}
+	for _, pattern := range []string{"*.list", "*.sources"} {
+		matches, err := filepath.Glob(filepath.Join(*aptSourcesDir, pattern))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't list %s: %v", *aptSourcesDir, err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files[1:])
+
+	reply := &pb.RepoListReply{}
+	for _, file := range files {
+		f, err := os.Open(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't open %s: %v", file, err)
+		}
+		var repos []*pb.Repo
+		if strings.HasSuffix(file, ".sources") {
+			repos, err = parseDeb822Sources(f)
+		} else {
+			repos, err = parseOneLineSources(f)
+		}
+		f.Close()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't parse %s: %v", file, err)
+		}
+		for _, r := range repos {
+			r.Filename = file
+		}
+		reply.Repos = append(reply.Repos, repos...)
+	}
+	return reply, nil
+}
+
+// aptRepo returns the Repo for one source.
+func aptRepo(typ, uri, suite string, components []string, st pb.RepoStatus) *pb.Repo {
+	id := fmt.Sprintf("%s %s %s", typ, uri, suite)
+	return &pb.Repo{
+		Id:     id,
+		Name:   strings.TrimSpace(id + " " + strings.Join(components, " ")),
+		Status: st,
+		Url:    uri,
+	}
+}
+
+// parseOneLineSources parses the one line per source format of
+// sources.list, i.e.
+//
+// deb [arch=amd64] http://deb.debian.org/debian bookworm main contrib
+func parseOneLineSources(r io.Reader) ([]*pb.Repo, error) {
+	var out []*pb.Repo
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		st := pb.RepoStatus_REPO_STATUS_ENABLED
+		if strings.HasPrefix(text, "#") {
+			// A commented out source is disabled, any other comment is ignored.
+			text = strings.TrimSpace(strings.TrimLeft(text, "#"))
+			st = pb.RepoStatus_REPO_STATUS_DISABLED
+		}
+		// Options can contain spaces so drop them before splitting.
+		if start := strings.Index(text, "["); start != -1 {
+			if end := strings.Index(text, "]"); end > start {
+				text = text[:start] + text[end+1:]
+			}
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 3 || (fields[0] != "deb" && fields[0] != "deb-src") {
+			if st == pb.RepoStatus_REPO_STATUS_ENABLED && len(fields) > 0 {
+				return nil, fmt.Errorf("invalid source %q", scanner.Text())
+			}
+			continue
+		}
+		out = append(out, aptRepo(fields[0], fields[1], fields[2], fields[3:], st))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// parseDeb822Sources parses the deb822 format of *.sources files, i.e.
+//
+// Types: deb deb-src
+// URIs: http://deb.debian.org/debian
+// Suites: bookworm bookworm-updates
+// Components: main
+// Enabled: no
+//
+// with a blank line between each stanza. Each combination of type, URI and
+// suite is returned as a separate repo.
+func parseDeb822Sources(r io.Reader) ([]*pb.Repo, error) {
+	var out []*pb.Repo
+	fields := make(map[string]string)
+	var last string
+	flush := func() {
+		if len(fields) == 0 {
+			return
+		}
+		st := pb.RepoStatus_REPO_STATUS_ENABLED
+		if strings.EqualFold(fields["enabled"], "no") {
+			st = pb.RepoStatus_REPO_STATUS_DISABLED
+		}
+		components := strings.Fields(fields["components"])
+		for _, typ := range strings.Fields(fields["types"]) {
+			for _, uri := range strings.Fields(fields["uris"]) {
+				for _, suite := range strings.Fields(fields["suites"]) {
+					out = append(out, aptRepo(typ, uri, suite, components, st))
+				}
+			}
+		}
+		fields = make(map[string]string)
+		last = ""
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.TrimSpace(text) == "":
+			flush()
+		case strings.HasPrefix(text, "#"):
+		case text[0] == ' ' || text[0] == '\t':
+			// A continuation of the previous field (i.e. a Signed-By key).
+			if last == "" {
+				return nil, fmt.Errorf("invalid continuation line %q", text)
+			}
+			fields[last] += " " + strings.TrimSpace(text)
+		default:
+			parts := strings.SplitN(text, ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid line %q", text)
+			}
+			last = strings.ToLower(strings.TrimSpace(parts[0]))
+			fields[last] = strings.TrimSpace(parts[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return out, nil
+}
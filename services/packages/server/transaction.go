@@ -0,0 +1,171 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"strings"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/packages"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// transactionLock serializes package transactions run by this server. The
+// package managers have their own locks but they aren't held across the
+// separate validation and update steps of Update.
+var transactionLock = make(chan struct{}, 1)
+
+// lockTransactions waits for any other transaction to finish and returns a
+// func to unlock once this one has.
+func lockTransactions(ctx context.Context) (func(), error) {
+	select {
+	case transactionLock <- struct{}{}:
+		return func() { <-transactionLock }, nil
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// transactionStream is the common part of the transaction servers.
+type transactionStream interface {
+	Send(*pb.TransactionReply) error
+}
+
+// outputSender sends everything written to it as output on a transaction
+// stream. util.StreamOutput serializes writes.
+type outputSender struct {
+	stream transactionStream
+}
+
+func (o *outputSender) Write(p []byte) (int, error) {
+	if err := o.stream.Send(&pb.TransactionReply{Output: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// runTransaction runs f streaming its output and then sends the resulting
+// package versions it returns.
+func runTransaction(stream transactionStream, f func(...util.Option) (string, []*pb.PackageInfo, error)) error {
+	_, packages, err := f(util.StreamOutput(&outputSender{stream: stream}))
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&pb.TransactionReply{Packages: packages}); err != nil {
+		return status.Errorf(codes.Internal, "can't send on stream: %v", err)
+	}
+	return nil
+}
+
+func (s *server) StreamingInstall(req *pb.InstallRequest, stream pb.Packages_StreamingInstallServer) error {
+	return runTransaction(stream, func(opts ...util.Option) (string, []*pb.PackageInfo, error) {
+		return install(stream.Context(), req, opts...)
+	})
+}
+
+func (s *server) StreamingUpdate(req *pb.UpdateRequest, stream pb.Packages_StreamingUpdateServer) error {
+	return runTransaction(stream, func(opts ...util.Option) (string, []*pb.PackageInfo, error) {
+		return update(stream.Context(), req, opts...)
+	})
+}
+
+func (s *server) Remove(req *pb.RemoveRequest, stream pb.Packages_RemoveServer) error {
+	return runTransaction(stream, func(opts ...util.Option) (string, []*pb.PackageInfo, error) {
+		return remove(stream.Context(), req, opts...)
+	})
+}
+
+func (s *server) UpdateAll(req *pb.UpdateAllRequest, stream pb.Packages_UpdateAllServer) error {
+	return runTransaction(stream, func(opts ...util.Option) (string, []*pb.PackageInfo, error) {
+		return updateAll(stream.Context(), req, opts...)
+	})
+}
+
+// remove implements Remove in the same way as install.
+func remove(ctx context.Context, req *pb.RemoveRequest, opts ...util.Option) (string, []*pb.PackageInfo, error) {
+	if err := validateField("name", req.Name); err != nil {
+		return "", nil, err
+	}
+	if req.Version != "" {
+		if err := validateField("version", req.Version); err != nil {
+			return "", nil, err
+		}
+	}
+
+	// Unset means YUM.
+	if req.PackageSystem == pb.PackageSystem_PACKAGE_SYSTEM_UNKNOWN {
+		req.PackageSystem = pb.PackageSystem_PACKAGE_SYSTEM_YUM
+	}
+	command, err := generateRemove(req)
+	if err != nil {
+		return "", nil, err
+	}
+
+	unlock, err := lockTransactions(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer unlock()
+
+	if req.Version != "" {
+		if err := checkInstalled(ctx, req.PackageSystem, req.Name, req.Version); err != nil {
+			return "", nil, err
+		}
+	}
+
+	run, err := util.RunCommand(ctx, command[0], command[1:], append(commandOptions(req.PackageSystem), opts...)...)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := run.Error; err != nil {
+		return "", nil, status.Errorf(codes.Internal, "error from running %q: %v\nstderr:\n%s", command, err, util.TrimString(run.Stderr.String()))
+	}
+	return run.Stdout.String(), installedVersions(ctx, req.PackageSystem, req.Name), nil
+}
+
+// updateAll implements UpdateAll in the same way as install.
+func updateAll(ctx context.Context, req *pb.UpdateAllRequest, opts ...util.Option) (string, []*pb.PackageInfo, error) {
+	// Unset means YUM.
+	if req.PackageSystem == pb.PackageSystem_PACKAGE_SYSTEM_UNKNOWN {
+		req.PackageSystem = pb.PackageSystem_PACKAGE_SYSTEM_YUM
+	}
+	commands, err := generateUpdateAll(req)
+	if err != nil {
+		return "", nil, err
+	}
+
+	unlock, err := lockTransactions(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer unlock()
+
+	var output strings.Builder
+	for _, command := range commands {
+		run, err := util.RunCommand(ctx, command[0], command[1:], append(commandOptions(req.PackageSystem), opts...)...)
+		if err != nil {
+			return "", nil, err
+		}
+		if err := run.Error; err != nil {
+			return "", nil, status.Errorf(codes.Internal, "error from running %q: %v\nstderr:\n%s", command, err, util.TrimString(run.Stderr.String()))
+		}
+		output.WriteString(run.Stdout.String())
+	}
+	return output.String(), installedVersions(ctx, req.PackageSystem, ""), nil
+}
@@ -0,0 +1,133 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'policy'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/subcommands"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/policy"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "policy"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&simulateCmd{}, "")
+	return c
+}
+
+type simulateCmd struct {
+	message       string
+	principal     string
+	groups        []string
+	justification string
+	showInput     bool
+}
+
+func (*simulateCmd) Name() string     { return "simulate" }
+func (*simulateCmd) Synopsis() string { return "Check whether the policy would allow a request" }
+func (*simulateCmd) Usage() string {
+	return `simulate [--message <json>] [--principal <id>] [--groups <list>] [--justification <text>] [--show-input] <method>:
+    Evaluate the target's policy for a call of <method> (i.e. /Exec.Exec/Run)
+    without running it, and print the decision and the policy rules it matched.
+`
+}
+
+func (p *simulateCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.message, "message", "", "The request message as JSON, i.e. '{\"command\": \"/bin/true\"}'")
+	f.StringVar(&p.principal, "principal", "", "The principal to evaluate the request for. If empty your own identity is used.")
+	f.Var(&util.StringSliceFlag{Target: &p.groups}, "groups", "Comma separated list of groups the principal is in, in addition to those the server finds")
+	f.StringVar(&p.justification, "justification", "", "The justification to evaluate the request with")
+	f.BoolVar(&p.showInput, "show-input", false, "If true also print the complete policy input")
+}
+
+// printDecision writes a human readable summary of `r`.
+func printDecision(out io.Writer, r *pb.SimulateReply, showInput bool) error {
+	decision := "denied"
+	switch {
+	case r.Allowed && r.RequiresApproval:
+		decision = "allowed with approval"
+	case r.Allowed:
+		decision = "allowed"
+	}
+	lines := []string{fmt.Sprintf("%s (policy %s)", decision, r.PolicyVersion)}
+	for _, reason := range r.Reasons {
+		lines = append(lines, "  reason: "+reason)
+	}
+	for _, rule := range r.MatchedRules {
+		lines = append(lines, "  matched: "+rule)
+	}
+	if l := r.RateLimit; l != nil {
+		lines = append(lines, fmt.Sprintf("  rate limit: %g rps burst %d key %q", l.Rps, l.Burst, l.Key))
+	}
+	if showInput {
+		lines = append(lines, "  input: "+r.Input)
+	}
+	_, err := fmt.Fprintln(out, strings.Join(lines, "\n"))
+	return err
+}
+
+func (p *simulateCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify the method to simulate.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, p)
+		return subcommands.ExitUsageError
+	}
+	c := pb.NewPolicyClientProxy(state.Conn)
+	req := &pb.SimulateRequest{
+		Method:        f.Arg(0),
+		Message:       p.message,
+		Principal:     p.principal,
+		Groups:        p.groups,
+		Justification: p.justification,
+	}
+	respChan, err := c.SimulateOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		if err := printDecision(state.Out[r.Index], r.Resp, p.showInput); err != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) output write error: %v\n", r.Target, r.Index, err)
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
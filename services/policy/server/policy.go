@@ -0,0 +1,142 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'Policy' service.
+//
+// Requests are evaluated with the Authorizer which allowed the Simulate
+// call itself, so the service only works on servers with a policy.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/policy"
+)
+
+// Server is used to implement the gRPC Server
+type Server struct{}
+
+// requestMessage returns the request message for `method` parsed from the
+// protojson `message`. The method must be one this server knows.
+func requestMessage(method string, message string) (proto.Message, error) {
+	name := strings.Replace(strings.TrimPrefix(method, "/"), "/", ".", 1)
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown method %s", method)
+	}
+	md, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "%s isn't a method", method)
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown request type %s for %s", md.Input().FullName(), method)
+	}
+	msg := mt.New().Interface()
+	if message != "" {
+		if err := protojson.Unmarshal([]byte(message), msg); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "can't parse %s message: %v", md.Input().FullName(), err)
+		}
+	}
+	return msg, nil
+}
+
+// Simulate evaluates the policy for the described request.
+func (s *Server) Simulate(ctx context.Context, req *pb.SimulateRequest) (*pb.SimulateReply, error) {
+	authz := rpcauth.AuthorizerFromContext(ctx)
+	if authz == nil {
+		return nil, status.Error(codes.FailedPrecondition, "server has no authorization policy")
+	}
+	msg, err := requestMessage(req.Method, req.Message)
+	if err != nil {
+		return nil, err
+	}
+	input, err := rpcauth.NewRPCAuthInput(ctx, req.Method, msg)
+	if err != nil {
+		return nil, err
+	}
+	if req.Principal != "" {
+		// None of the caller's identity (including any bearer token in
+		// its metadata) applies to another principal.
+		input.Peer = &rpcauth.PeerAuthInput{
+			Principal: &rpcauth.PrincipalAuthInput{ID: req.Principal},
+		}
+		input.Metadata = metadata.MD{}
+	} else {
+		input.Metadata = input.Metadata.Copy()
+		if input.Metadata == nil {
+			input.Metadata = metadata.MD{}
+		}
+	}
+	if len(req.Groups) > 0 {
+		if input.Peer.Principal == nil {
+			input.Peer.Principal = &rpcauth.PrincipalAuthInput{ID: rpcauth.PrincipalID(input)}
+		}
+		input.Peer.Principal.Groups = append(input.Peer.Principal.Groups, req.Groups...)
+	}
+	input.Justification = req.Justification
+	input.Metadata.Delete(rpcauth.ReqJustKey)
+	if req.Justification != "" {
+		input.Metadata.Set(rpcauth.ReqJustKey, req.Justification)
+	}
+
+	d, err := authz.Simulate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(d.Input)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't marshal input: %v", err)
+	}
+	reply := &pb.SimulateReply{
+		Allowed:          d.Allowed,
+		Reasons:          d.Reasons,
+		MatchedRules:     d.MatchedRules,
+		RequiresApproval: d.RequiresApproval,
+		PolicyVersion:    d.PolicyVersion,
+		Input:            string(b),
+	}
+	if d.RateLimit != nil {
+		reply.RateLimit = &pb.RateLimit{
+			Rps:   d.RateLimit.RPS,
+			Burst: int32(d.RateLimit.Burst),
+			Key:   d.RateLimit.Key,
+		}
+	}
+	return reply, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *Server) Register(gs *grpc.Server) {
+	pb.RegisterPolicyServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&Server{})
+}
@@ -0,0 +1,180 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	_ "github.com/Snowflake-Labs/sansshell/services/exec"
+	pb "github.com/Snowflake-Labs/sansshell/services/policy"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+var (
+	bufSize = 1024 * 1024
+	lis     *bufconn.Listener
+)
+
+const policy = `
+package sansshell.authz
+
+default allow = false
+
+allow {
+  input.method = "/Policy.Policy/Simulate"
+}
+
+allow {
+  input.method = "/Exec.Exec/Run"
+  input.message.command = "/bin/true"
+  input.peer.principal.id = "alice"
+}
+
+allow {
+  input.method = "/Exec.Exec/Run"
+  input.message.command = "/bin/false"
+  "admins" in input.peer.principal.groups
+  input.justification != ""
+}
+
+require_approval {
+  input.method = "/Exec.Exec/Run"
+  input.message.command = "/bin/false"
+}
+
+deny_reason[r] {
+  input.method = "/Exec.Exec/Run"
+  input.peer.principal.id != "alice"
+  r := "only alice can run commands"
+}
+`
+
+func bufDialer(context.Context, string) (net.Conn, error) {
+	return lis.Dial()
+}
+
+func TestMain(m *testing.M) {
+	lis = bufconn.Listen(bufSize)
+	authz, err := rpcauth.NewWithPolicy(context.Background(), policy)
+	if err != nil {
+		log.Fatalf("NewWithPolicy: %v", err)
+	}
+	s := grpc.NewServer(grpc.UnaryInterceptor(authz.Authorize))
+	srv := &Server{}
+	srv.Register(s)
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			log.Fatalf("Server exited with error: %v", err)
+		}
+	}()
+	defer s.GracefulStop()
+
+	os.Exit(m.Run())
+}
+
+func TestSimulate(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewPolicyClient(conn)
+
+	for _, tc := range []struct {
+		name         string
+		req          *pb.SimulateRequest
+		wantErr      codes.Code
+		wantAllowed  bool
+		wantApproval bool
+		wantReasons  []string
+		wantRules    int
+	}{
+		{
+			name:        "allowed",
+			req:         &pb.SimulateRequest{Method: "/Exec.Exec/Run", Message: `{"command": "/bin/true"}`, Principal: "alice"},
+			wantAllowed: true,
+			wantRules:   1,
+		},
+		{
+			name:        "denied with reason",
+			req:         &pb.SimulateRequest{Method: "/Exec.Exec/Run", Message: `{"command": "/bin/true"}`, Principal: "bob"},
+			wantReasons: []string{"only alice can run commands"},
+		},
+		{
+			name:         "groups and justification",
+			req:          &pb.SimulateRequest{Method: "/Exec.Exec/Run", Message: `{"command": "/bin/false"}`, Principal: "alice", Groups: []string{"admins"}, Justification: "INC-1"},
+			wantAllowed:  true,
+			wantApproval: true,
+			wantRules:    1,
+		},
+		{
+			name: "missing justification",
+			req:  &pb.SimulateRequest{Method: "/Exec.Exec/Run", Message: `{"command": "/bin/false"}`, Principal: "alice", Groups: []string{"admins"}},
+		},
+		{
+			name:    "unknown method",
+			req:     &pb.SimulateRequest{Method: "/Foo.Foo/Bar"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad message",
+			req:     &pb.SimulateRequest{Method: "/Exec.Exec/Run", Message: `{"nonexistent": 1}`},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := client.Simulate(ctx, tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("Simulate() error %v, want code %s", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if resp.Allowed != tc.wantAllowed || resp.RequiresApproval != tc.wantApproval {
+				t.Errorf("Simulate() = allowed %t approval %t, want %t %t", resp.Allowed, resp.RequiresApproval, tc.wantAllowed, tc.wantApproval)
+			}
+			if strings.Join(resp.Reasons, ",") != strings.Join(tc.wantReasons, ",") {
+				t.Errorf("reasons = %q, want %q", resp.Reasons, tc.wantReasons)
+			}
+			if len(resp.MatchedRules) != tc.wantRules {
+				t.Errorf("matched rules = %q, want %d", resp.MatchedRules, tc.wantRules)
+			}
+			if resp.PolicyVersion == "" || !strings.Contains(resp.Input, tc.req.Method) {
+				t.Errorf("reply %+v is missing the policy version or input", resp)
+			}
+		})
+	}
+}
+
+func TestSimulateWithoutPolicy(t *testing.T) {
+	_, err := (&Server{}).Simulate(context.Background(), &pb.SimulateRequest{Method: "/Exec.Exec/Run"})
+	if got, want := status.Code(err), codes.FailedPrecondition; got != want {
+		t.Errorf("Simulate() without policy = %v, want code %s", err, want)
+	}
+}
@@ -0,0 +1,164 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'power'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/google/subcommands"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/power"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "power"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&cancelCmd{}, "")
+	c.Register(&shutdownCmd{action: "poweroff"}, "")
+	c.Register(&shutdownCmd{action: "reboot"}, "")
+	return c
+}
+
+// shutdownCmd implements both reboot and poweroff.
+type shutdownCmd struct {
+	action string
+	delay  time.Duration
+	reason string
+}
+
+func (s *shutdownCmd) Name() string { return s.action }
+func (s *shutdownCmd) Synopsis() string {
+	if s.action == "reboot" {
+		return "Reboot the remote host"
+	}
+	return "Power off the remote host"
+}
+func (s *shutdownCmd) Usage() string {
+	return fmt.Sprintf(`%s --reason=reason [--delay=duration]:
+    Schedule the host to %s with shutdown(8), warning logged in users. The
+    delay is rounded up to whole minutes and defaults to none. Use cancel
+    to stop a pending %s.
+`, s.action, s.action, s.action)
+}
+
+func (s *shutdownCmd) SetFlags(f *flag.FlagSet) {
+	f.DurationVar(&s.delay, "delay", 0, "How long to wait, i.e. 10m")
+	f.StringVar(&s.reason, "reason", "", "Why, i.e. a ticket. Required.")
+}
+
+func (s *shutdownCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if s.reason == "" || f.NArg() != 0 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify a --reason.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, s)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewPowerClientProxy(state.Conn)
+	req := &pb.ShutdownRequest{Delay: durationpb.New(s.delay), Reason: s.reason}
+	// The replies are the same but the generated response types differ.
+	var err error
+	retCode := subcommands.ExitSuccess
+	if s.action == "reboot" {
+		var respChan <-chan *pb.RebootManyResponse
+		if respChan, err = c.RebootOneMany(ctx, req); err == nil {
+			for resp := range respChan {
+				if !s.printReply(state, resp.Target, resp.Index, resp.Resp, resp.Error) {
+					retCode = subcommands.ExitFailure
+				}
+			}
+		}
+	} else {
+		var respChan <-chan *pb.PoweroffManyResponse
+		if respChan, err = c.PoweroffOneMany(ctx, req); err == nil {
+			for resp := range respChan {
+				if !s.printReply(state, resp.Target, resp.Index, resp.Resp, resp.Error) {
+					retCode = subcommands.ExitFailure
+				}
+			}
+		}
+	}
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing '%s': %v\n", s.action, err)
+		}
+		return subcommands.ExitFailure
+	}
+	return retCode
+}
+
+// printReply prints the reply or error from a target, returning false
+// on error.
+func (s *shutdownCmd) printReply(state *util.ExecuteState, target string, index int, reply *pb.ShutdownReply, err error) bool {
+	if err != nil {
+		fmt.Fprintf(state.Err[index], "target %s (%d) error: %v\n", target, index, err)
+		return false
+	}
+	fmt.Fprintf(state.Out[index], "%s scheduled for %s\n", s.action, reply.When.AsTime().Local().Format(time.RFC3339))
+	return true
+}
+
+type cancelCmd struct {
+	reason string
+}
+
+func (*cancelCmd) Name() string     { return "cancel" }
+func (*cancelCmd) Synopsis() string { return "Cancel a pending reboot or poweroff" }
+func (*cancelCmd) Usage() string {
+	return `cancel [--reason=reason]:
+    Cancel a pending reboot or poweroff, however it was scheduled.
+`
+}
+
+func (c *cancelCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.reason, "reason", "", "Why, sent to logged in users")
+}
+
+func (c *cancelCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	proxy := pb.NewPowerClientProxy(state.Conn)
+	respChan, err := proxy.CancelOneMany(ctx, &pb.CancelRequest{Reason: c.reason})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'cancel': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
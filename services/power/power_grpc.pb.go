@@ -0,0 +1,177 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package power
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PowerClient is the client API for Power service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PowerClient interface {
+	// Reboot schedules a reboot.
+	Reboot(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownReply, error)
+	// Poweroff schedules the host to halt and power off.
+	Poweroff(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownReply, error)
+	// Cancel cancels a pending reboot or poweroff.
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelReply, error)
+}
+
+type powerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPowerClient(cc grpc.ClientConnInterface) PowerClient {
+	return &powerClient{cc}
+}
+
+func (c *powerClient) Reboot(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownReply, error) {
+	out := new(ShutdownReply)
+	err := c.cc.Invoke(ctx, "/Power.Power/Reboot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *powerClient) Poweroff(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownReply, error) {
+	out := new(ShutdownReply)
+	err := c.cc.Invoke(ctx, "/Power.Power/Poweroff", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *powerClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelReply, error) {
+	out := new(CancelReply)
+	err := c.cc.Invoke(ctx, "/Power.Power/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PowerServer is the server API for Power service.
+// All implementations should embed UnimplementedPowerServer
+// for forward compatibility
+type PowerServer interface {
+	// Reboot schedules a reboot.
+	Reboot(context.Context, *ShutdownRequest) (*ShutdownReply, error)
+	// Poweroff schedules the host to halt and power off.
+	Poweroff(context.Context, *ShutdownRequest) (*ShutdownReply, error)
+	// Cancel cancels a pending reboot or poweroff.
+	Cancel(context.Context, *CancelRequest) (*CancelReply, error)
+}
+
+// UnimplementedPowerServer should be embedded to have forward compatible implementations.
+type UnimplementedPowerServer struct {
+}
+
+func (UnimplementedPowerServer) Reboot(context.Context, *ShutdownRequest) (*ShutdownReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reboot not implemented")
+}
+func (UnimplementedPowerServer) Poweroff(context.Context, *ShutdownRequest) (*ShutdownReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Poweroff not implemented")
+}
+func (UnimplementedPowerServer) Cancel(context.Context, *CancelRequest) (*CancelReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+
+// UnsafePowerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PowerServer will
+// result in compilation errors.
+type UnsafePowerServer interface {
+	mustEmbedUnimplementedPowerServer()
+}
+
+func RegisterPowerServer(s grpc.ServiceRegistrar, srv PowerServer) {
+	s.RegisterService(&Power_ServiceDesc, srv)
+}
+
+func _Power_Reboot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PowerServer).Reboot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Power.Power/Reboot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PowerServer).Reboot(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Power_Poweroff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PowerServer).Poweroff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Power.Power/Poweroff",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PowerServer).Poweroff(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Power_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PowerServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Power.Power/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PowerServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Power_ServiceDesc is the grpc.ServiceDesc for Power service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Power_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Power.Power",
+	HandlerType: (*PowerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Reboot",
+			Handler:    _Power_Reboot_Handler,
+		},
+		{
+			MethodName: "Poweroff",
+			Handler:    _Power_Poweroff_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Power_Cancel_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "power.proto",
+}
@@ -0,0 +1,132 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'Power' service.
+package server
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/power"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// validReason checks reason can be passed to shutdown as a wall message.
+func validReason(reason string) error {
+	for _, r := range reason {
+		if unicode.IsControl(r) {
+			return status.Error(codes.InvalidArgument, "reason can't contain control characters")
+		}
+	}
+	return nil
+}
+
+// shutdown runs shutdown(8) with args.
+func shutdown(ctx context.Context, args ...string) error {
+	if *shutdownBin == "" {
+		return status.Error(codes.Unimplemented, "power operations are not supported on this platform")
+	}
+	run, err := util.RunCommand(ctx, *shutdownBin, args)
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't run shutdown: %v", err)
+	}
+	if err := run.Error; run.ExitCode != 0 || err != nil {
+		return status.Errorf(codes.Internal, "shutdown failed: %v: %s", err, strings.TrimSpace(util.TrimString(run.Stderr.String())))
+	}
+	return nil
+}
+
+// schedule runs shutdown with mode (-r or -P) after the requested delay.
+func schedule(ctx context.Context, action string, mode string, req *pb.ShutdownRequest) (*pb.ShutdownReply, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "a reason is required to %s", action)
+	}
+	if err := validReason(req.Reason); err != nil {
+		return nil, err
+	}
+	var delay time.Duration
+	if req.Delay != nil {
+		if err := req.Delay.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid delay: %v", err)
+		}
+		delay = req.Delay.AsDuration()
+	}
+	if delay < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "delay %v can't be negative", delay)
+	}
+	// Round up to whole minutes, the only unit shutdown supports.
+	minutes := int64((delay + time.Minute - 1) / time.Minute)
+	when := "now"
+	if minutes > 0 {
+		when = "+" + strconv.FormatInt(minutes, 10)
+	}
+	scheduled := time.Now().Add(time.Duration(minutes) * time.Minute)
+	logr.FromContextOrDiscard(ctx).Info("scheduling "+action, "when", scheduled, "reason", req.Reason)
+	// -- so a reason starting with - isn't taken as an option.
+	if err := shutdown(ctx, mode, "--", when, req.Reason); err != nil {
+		return nil, err
+	}
+	return &pb.ShutdownReply{When: timestamppb.New(scheduled)}, nil
+}
+
+// Reboot implements pb.PowerServer.Reboot
+func (s *server) Reboot(ctx context.Context, req *pb.ShutdownRequest) (*pb.ShutdownReply, error) {
+	return schedule(ctx, "reboot", "-r", req)
+}
+
+// Poweroff implements pb.PowerServer.Poweroff
+func (s *server) Poweroff(ctx context.Context, req *pb.ShutdownRequest) (*pb.ShutdownReply, error) {
+	return schedule(ctx, "poweroff", "-P", req)
+}
+
+// Cancel implements pb.PowerServer.Cancel
+func (s *server) Cancel(ctx context.Context, req *pb.CancelRequest) (*pb.CancelReply, error) {
+	if err := validReason(req.Reason); err != nil {
+		return nil, err
+	}
+	logr.FromContextOrDiscard(ctx).Info("cancelling pending shutdown", "reason", req.Reason)
+	args := []string{"-c"}
+	if req.Reason != "" {
+		args = append(args, "--", req.Reason)
+	}
+	if err := shutdown(ctx, args...); err != nil {
+		return nil, err
+	}
+	return &pb.CancelReply{}, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterPowerServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
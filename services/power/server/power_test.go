@@ -0,0 +1,189 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/power"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// fakeShutdown installs a shutdown script recording its arguments to the
+// returned file, one per line, and exiting with code.
+func fakeShutdown(t *testing.T, code int) string {
+	t.Helper()
+	saved := *shutdownBin
+	t.Cleanup(func() { *shutdownBin = saved })
+	dir := t.TempDir()
+	args := filepath.Join(dir, "args")
+	script := fmt.Sprintf("#!/bin/sh\nprintf '%%s\\n' \"$@\" > %s\necho 'Failed to talk to shutdownd' >&2\nexit %d\n", args, code)
+	*shutdownBin = filepath.Join(dir, "shutdown")
+	testutil.FatalOnErr("writing shutdown", os.WriteFile(*shutdownBin, []byte(script), 0755), t)
+	return args
+}
+
+func TestShutdown(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		poweroff bool
+		req      *pb.ShutdownRequest
+		code     int
+		wantArgs []string
+		wantIn   time.Duration
+		wantErr  codes.Code
+	}{
+		{
+			name:     "reboot now",
+			req:      &pb.ShutdownRequest{Reason: "kernel upgrade"},
+			wantArgs: []string{"-r", "--", "now", "kernel upgrade"},
+		},
+		{
+			name:     "reboot rounds up",
+			req:      &pb.ShutdownRequest{Delay: durationpb.New(90 * time.Second), Reason: "OPS-1234"},
+			wantArgs: []string{"-r", "--", "+2", "OPS-1234"},
+			wantIn:   2 * time.Minute,
+		},
+		{
+			name:     "poweroff",
+			poweroff: true,
+			req:      &pb.ShutdownRequest{Delay: durationpb.New(time.Hour), Reason: "-decommission"},
+			wantArgs: []string{"-P", "--", "+60", "-decommission"},
+			wantIn:   time.Hour,
+		},
+		{
+			name:    "no reason",
+			req:     &pb.ShutdownRequest{Reason: " "},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "control characters",
+			req:     &pb.ShutdownRequest{Reason: "upgrade\x1b[2J"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "negative delay",
+			req:     &pb.ShutdownRequest{Delay: durationpb.New(-time.Minute), Reason: "upgrade"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:     "shutdown fails",
+			req:      &pb.ShutdownRequest{Reason: "upgrade"},
+			code:     1,
+			wantArgs: []string{"-r", "--", "now", "upgrade"},
+			wantErr:  codes.Internal,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			args := fakeShutdown(t, tc.code)
+			s := &server{}
+			call := s.Reboot
+			if tc.poweroff {
+				call = s.Poweroff
+			}
+			start := time.Now()
+			got, err := call(context.Background(), tc.req)
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("got error %v, want code %v", err, tc.wantErr)
+			}
+			gotArgs, readErr := os.ReadFile(args)
+			if tc.wantArgs == nil {
+				if readErr == nil {
+					t.Fatalf("shutdown was run with %q", gotArgs)
+				}
+				return
+			}
+			testutil.FatalOnErr("reading args", readErr, t)
+			testutil.DiffErr(tc.name, strings.Split(strings.TrimSuffix(string(gotArgs), "\n"), "\n"), tc.wantArgs, t)
+			if err != nil {
+				return
+			}
+			if when := got.When.AsTime(); when.Before(start.Add(tc.wantIn)) || when.After(time.Now().Add(tc.wantIn)) {
+				t.Errorf("scheduled for %v, want %v after %v", when, tc.wantIn, start)
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		reason   string
+		code     int
+		wantArgs []string
+		wantErr  codes.Code
+	}{
+		{
+			name:     "no reason",
+			wantArgs: []string{"-c"},
+		},
+		{
+			name:     "reason",
+			reason:   "upgrade postponed",
+			wantArgs: []string{"-c", "--", "upgrade postponed"},
+		},
+		{
+			name:    "control characters",
+			reason:  "\a",
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:     "shutdown fails",
+			code:     1,
+			wantArgs: []string{"-c"},
+			wantErr:  codes.Internal,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			args := fakeShutdown(t, tc.code)
+			s := &server{}
+			_, err := s.Cancel(context.Background(), &pb.CancelRequest{Reason: tc.reason})
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("got error %v, want code %v", err, tc.wantErr)
+			}
+			gotArgs, readErr := os.ReadFile(args)
+			if tc.wantArgs == nil {
+				if readErr == nil {
+					t.Fatalf("shutdown was run with %q", gotArgs)
+				}
+				return
+			}
+			testutil.FatalOnErr("reading args", readErr, t)
+			testutil.DiffErr(tc.name, strings.Split(strings.TrimSuffix(string(gotArgs), "\n"), "\n"), tc.wantArgs, t)
+		})
+	}
+
+	saved := *shutdownBin
+	t.Cleanup(func() { *shutdownBin = saved })
+	*shutdownBin = ""
+	s := &server{}
+	if _, err := s.Cancel(context.Background(), &pb.CancelRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Cancel without shutdown: got error %v, want Unimplemented", err)
+	}
+}
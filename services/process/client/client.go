@@ -24,6 +24,7 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/google/subcommands"
@@ -36,34 +37,22 @@ import (
 const subPackage = "process"
 
 func init() {
-	subcommands.Register(&processCmd{}, subPackage)
+	client.RegisterSubpackage(subPackage, setup)
 }
 
 func setup(f *flag.FlagSet) *subcommands.Commander {
 	c := client.SetupSubpackage(subPackage, f)
 	c.Register(&dumpCmd{}, "")
 	c.Register(&jstackCmd{}, "")
+	c.Register(&lsofCmd{}, "")
+	c.Register(&memmapCmd{}, "")
 	c.Register(&psCmd{}, "")
 	c.Register(&pstackCmd{}, "")
+	c.Register(&readmemCmd{}, "")
+	c.Register(&signalCmd{}, "")
 	return c
 }
 
-type processCmd struct{}
-
-func (*processCmd) Name() string { return subPackage }
-func (p *processCmd) Synopsis() string {
-	return client.GenerateSynopsis(setup(flag.NewFlagSet("", flag.ContinueOnError)))
-}
-func (p *processCmd) Usage() string {
-	return client.GenerateUsage(subPackage, p.Synopsis())
-}
-func (*processCmd) SetFlags(f *flag.FlagSet) {}
-
-func (p *processCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
-	c := setup(f)
-	return c.Execute(ctx, args...)
-}
-
 func outputEntryHeader(out io.Writer, target string, index int) {
 	fmt.Fprintf(out, "\nTarget: %s Index: %d \n\n", target, index)
 }
@@ -251,6 +240,138 @@ func (p *pstackCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 	return retCode
 }
 
+type signalCmd struct {
+	pid          int64
+	pidfile      string
+	signal       string
+	expectedName string
+}
+
+func (*signalCmd) Name() string     { return "signal" }
+func (*signalCmd) Synopsis() string { return "Send a signal to a process." }
+func (*signalCmd) Usage() string {
+	return "signal --pid=<pid>|--pidfile=<file> [--signal=<name>] [--expected-name=<name>]: Send a signal (SIGTERM by default) to a process.\n"
+}
+
+func (p *signalCmd) SetFlags(f *flag.FlagSet) {
+	f.Int64Var(&p.pid, "pid", 0, "Process to signal.")
+	f.StringVar(&p.pidfile, "pidfile", "", "File on the target containing the pid of the process to signal.")
+	f.StringVar(&p.signal, "signal", "SIGTERM", "Name of the signal to send.")
+	f.StringVar(&p.expectedName, "expected-name", "", "If set only signal the process if its command name matches this.")
+}
+
+func (p *signalCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	if (p.pid <= 0) == (p.pidfile == "") {
+		fmt.Fprintln(os.Stderr, "exactly one of --pid or --pidfile must be specified")
+		return subcommands.ExitFailure
+	}
+
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewProcessClientProxy(state.Conn)
+
+	req := &pb.SignalRequest{
+		Process:      &pb.SignalRequest_Pid{Pid: p.pid},
+		Signal:       strings.ToUpper(p.signal),
+		ExpectedName: p.expectedName,
+	}
+	if p.pidfile != "" {
+		req.Process = &pb.SignalRequest_Pidfile{Pidfile: p.pidfile}
+	}
+
+	respChan, err := c.SignalOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Signal returned error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "Got error from target %s (%d) - %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[resp.Index], "Sent %s to pid %d\n", req.Signal, resp.Resp.Pid)
+	}
+	return retCode
+}
+
+type lsofCmd struct {
+	pids util.IntSliceFlags
+	path string
+}
+
+func (*lsofCmd) Name() string     { return "lsof" }
+func (*lsofCmd) Synopsis() string { return "List open files." }
+func (*lsofCmd) Usage() string {
+	return "lsof [--pids=<pid>,...] [--path=<path>]: List the files, sockets and pipes processes have open and the files they have mapped.\n"
+}
+
+func (p *lsofCmd) SetFlags(f *flag.FlagSet) {
+	f.Var(&p.pids, "pids", "Restrict to only pids listed (separated by comma)")
+	f.StringVar(&p.path, "path", "", "If set only list this file, or files under it if it's a directory. i.e. to find who has a file open.")
+}
+
+func (p *lsofCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewProcessClientProxy(state.Conn)
+
+	req := &pb.ListOpenFilesRequest{Path: p.path}
+	for _, pid := range p.pids {
+		req.Pids = append(req.Pids, pid)
+	}
+
+	respChan, err := c.ListOpenFilesOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "ListOpenFiles returned error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "Got error from target %s (%d) - %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[resp.Index]
+		fmt.Fprintf(out, "%-16s %8s %5s %-7s %12s %s\n", "COMMAND", "PID", "FD", "TYPE", "NODE", "NAME")
+		for _, file := range resp.Resp.Files {
+			name := file.Path
+			if file.Deleted {
+				name += " (deleted)"
+			}
+			fmt.Fprintf(out, "%-16s %8d %5s %-7s %12d %s\n", file.Command, file.Pid, file.Fd, fileType(file.Type), file.Inode, name)
+		}
+	}
+	return retCode
+}
+
+// fileType returns the name lsof uses for a type of file.
+func fileType(t pb.OpenFileType) string {
+	switch t {
+	case pb.OpenFileType_OPEN_FILE_TYPE_REGULAR:
+		return "REG"
+	case pb.OpenFileType_OPEN_FILE_TYPE_DIRECTORY:
+		return "DIR"
+	case pb.OpenFileType_OPEN_FILE_TYPE_DEVICE:
+		return "DEV"
+	case pb.OpenFileType_OPEN_FILE_TYPE_SOCKET:
+		return "sock"
+	case pb.OpenFileType_OPEN_FILE_TYPE_PIPE:
+		return "FIFO"
+	case pb.OpenFileType_OPEN_FILE_TYPE_ANON_INODE:
+		return "a_inode"
+	}
+	return "unknown"
+}
+
 type jstackCmd struct {
 	pid int64
 }
@@ -345,6 +466,7 @@ type dumpCmd struct {
 	pid      int64
 	dumpType string
 	output   string
+	maxSize  int64
 }
 
 func (*dumpCmd) Name() string     { return "dump" }
@@ -356,6 +478,7 @@ func (*dumpCmd) Usage() string {
 func (p *dumpCmd) SetFlags(f *flag.FlagSet) {
 	f.Int64Var(&p.pid, "pid", 0, "Process to generate a core dump against.")
 	f.StringVar(&p.dumpType, "dump-type", "GCORE", fmt.Sprintf("Dump type to use(one of: [%s])", strings.Join(shortDumpTypeNames(), ",")))
+	f.Int64Var(&p.maxSize, "max-size", 0, "If positive fail rather than return a dump larger than this many bytes")
 	f.StringVar(&p.output, "output", "", `Output to write data remotely. Leave blank and --outputs will be used for local destinations.
 
 This will also accept URL options of the form:
@@ -391,6 +514,7 @@ func (p *dumpCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfac
 		Pid:         p.pid,
 		DumpType:    dt,
 		Destination: &pb.GetMemoryDumpRequest_Stream{},
+		MaxSize:     p.maxSize,
 	}
 
 	for _, pre := range validOutputPrefixes {
@@ -446,3 +570,137 @@ func (p *dumpCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfac
 	}
 	return retCode
 }
+
+type memmapCmd struct {
+	pid int64
+}
+
+func (*memmapCmd) Name() string     { return "memmap" }
+func (*memmapCmd) Synopsis() string { return "Retrieve the memory mappings of a process." }
+func (*memmapCmd) Usage() string {
+	return "memmap --pid=<pid>: List the memory mappings of a process and how much of each is resident, swapped etc. Sizes are in KiB.\n"
+}
+
+func (p *memmapCmd) SetFlags(f *flag.FlagSet) {
+	f.Int64Var(&p.pid, "pid", 0, "Process to list the mappings of.")
+}
+
+func (p *memmapCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	if p.pid <= 0 {
+		fmt.Fprintln(os.Stderr, "--pid must be specified")
+		return subcommands.ExitFailure
+	}
+
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewProcessClientProxy(state.Conn)
+
+	respChan, err := c.GetMemoryMapOneMany(ctx, &pb.GetMemoryMapRequest{Pid: p.pid})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "GetMemoryMap returned error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "Got error from target %s (%d) - %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[resp.Index]
+		fmtHeader := "%-33s %4s %10s %10s %10s %10s %10s %10s %s\n"
+		fmtEntry := "%016x-%016x %4s %10d %10d %10d %10d %10d %10d %s\n"
+		fmt.Fprintf(out, fmtHeader, "ADDRESS", "PERM", "SIZE", "RSS", "PSS", "DIRTY", "ANON", "SWAP", "MAPPING")
+		for _, m := range resp.Resp.Mappings {
+			fmt.Fprintf(out, fmtEntry, m.Start, m.End, m.Permissions, m.Size>>10, m.Rss>>10, m.Pss>>10, (m.SharedDirty+m.PrivateDirty)>>10, m.Anonymous>>10, m.Swap>>10, m.Path)
+		}
+		if t := resp.Resp.Total; t != nil {
+			fmt.Fprintf(out, "%-33s %4s %10d %10d %10d %10d %10d %10d\n", "total", "", t.Size>>10, t.Rss>>10, t.Pss>>10, (t.SharedDirty+t.PrivateDirty)>>10, t.Anonymous>>10, t.Swap>>10)
+		}
+	}
+	return retCode
+}
+
+type readmemCmd struct {
+	pid     int64
+	address string
+	length  uint64
+}
+
+func (*readmemCmd) Name() string     { return "readmem" }
+func (*readmemCmd) Synopsis() string { return "Read a region of the memory of a process." }
+func (*readmemCmd) Usage() string {
+	return `readmem --pid=<pid> --address=<address> --length=<bytes>:
+  Read a region of the memory of a process, which must lie within a single
+  readable mapping (see memmap). The region is written gzip compressed to
+  --outputs. Targets only allow reads up to their --max-memory-read-size.
+`
+}
+
+func (p *readmemCmd) SetFlags(f *flag.FlagSet) {
+	f.Int64Var(&p.pid, "pid", 0, "Process to read the memory of.")
+	f.StringVar(&p.address, "address", "", "Address to start reading at, i.e. 0x7f0000001000")
+	f.Uint64Var(&p.length, "length", 0, "Number of bytes to read.")
+}
+
+func (p *readmemCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	if p.pid <= 0 {
+		fmt.Fprintln(os.Stderr, "--pid must be specified")
+		return subcommands.ExitFailure
+	}
+	address, err := strconv.ParseUint(p.address, 0, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't parse --address: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	if p.length == 0 {
+		fmt.Fprintln(os.Stderr, "--length must be specified")
+		return subcommands.ExitFailure
+	}
+
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewProcessClientProxy(state.Conn)
+
+	stream, err := c.ReadMemoryOneMany(ctx, &pb.ReadMemoryRequest{Pid: p.pid, Address: address, Length: p.length})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "ReadMemory returned error: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "Receive error: %v\n", err)
+			}
+			retCode = subcommands.ExitFailure
+			break
+		}
+		for _, r := range resp {
+			if r.Error != nil && r.Error != io.EOF {
+				fmt.Fprintf(state.Err[r.Index], "Error for target %s (%d): %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			if r.Resp == nil {
+				continue
+			}
+			if _, err := state.Out[r.Index].Write(r.Resp.Data); err != nil {
+				fmt.Fprintf(state.Err[r.Index], "Error writing output for target %s (%d): %v\n", r.Target, r.Index, err)
+				retCode = subcommands.ExitFailure
+			}
+		}
+	}
+	return retCode
+}
@@ -36,6 +36,23 @@ type ProcessClient interface {
 	// NOTE: Enough disk space is required to hold the dump file before streaming
 	//       the response.
 	GetMemoryDump(ctx context.Context, in *GetMemoryDumpRequest, opts ...grpc.CallOption) (Process_GetMemoryDumpClient, error)
+	// Signal sends a signal to a process, i.e. to kill it or have it reload
+	// its configuration.
+	Signal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SignalReply, error)
+	// ListOpenFiles returns the files, sockets and pipes processes have open
+	// along with the files they have memory mapped, as lsof would. This is
+	// useful for finding who is holding a deleted file open or has a socket
+	// open (see Network.ListSockets to map sockets to ports).
+	ListOpenFiles(ctx context.Context, in *ListOpenFilesRequest, opts ...grpc.CallOption) (*ListOpenFilesReply, error)
+	// GetMemoryMap returns the memory mappings of a process and how much of
+	// each is resident, shared, swapped etc. as in /proc/<pid>/smaps. This is
+	// useful for finding what is using memory without a full dump.
+	GetMemoryMap(ctx context.Context, in *GetMemoryMapRequest, opts ...grpc.CallOption) (*GetMemoryMapReply, error)
+	// ReadMemory returns a region of the memory of a process, which needs to
+	// lie within a single readable mapping (see GetMemoryMap). Like
+	// GetMemoryDump this 100% has sensitive data contained within it. Servers
+	// only allow reads up to a configured size and by default none at all.
+	ReadMemory(ctx context.Context, in *ReadMemoryRequest, opts ...grpc.CallOption) (Process_ReadMemoryClient, error)
 }
 
 type processClient struct {
@@ -105,6 +122,65 @@ func (x *processGetMemoryDumpClient) Recv() (*GetMemoryDumpReply, error) {
 	return m, nil
 }
 
+func (c *processClient) Signal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SignalReply, error) {
+	out := new(SignalReply)
+	err := c.cc.Invoke(ctx, "/Process.Process/Signal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processClient) ListOpenFiles(ctx context.Context, in *ListOpenFilesRequest, opts ...grpc.CallOption) (*ListOpenFilesReply, error) {
+	out := new(ListOpenFilesReply)
+	err := c.cc.Invoke(ctx, "/Process.Process/ListOpenFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processClient) GetMemoryMap(ctx context.Context, in *GetMemoryMapRequest, opts ...grpc.CallOption) (*GetMemoryMapReply, error) {
+	out := new(GetMemoryMapReply)
+	err := c.cc.Invoke(ctx, "/Process.Process/GetMemoryMap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processClient) ReadMemory(ctx context.Context, in *ReadMemoryRequest, opts ...grpc.CallOption) (Process_ReadMemoryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Process_ServiceDesc.Streams[1], "/Process.Process/ReadMemory", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &processReadMemoryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Process_ReadMemoryClient interface {
+	Recv() (*ReadMemoryReply, error)
+	grpc.ClientStream
+}
+
+type processReadMemoryClient struct {
+	grpc.ClientStream
+}
+
+func (x *processReadMemoryClient) Recv() (*ReadMemoryReply, error) {
+	m := new(ReadMemoryReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ProcessServer is the server API for Process service.
 // All implementations should embed UnimplementedProcessServer
 // for forward compatibility
@@ -127,6 +203,23 @@ type ProcessServer interface {
 	// NOTE: Enough disk space is required to hold the dump file before streaming
 	//       the response.
 	GetMemoryDump(*GetMemoryDumpRequest, Process_GetMemoryDumpServer) error
+	// Signal sends a signal to a process, i.e. to kill it or have it reload
+	// its configuration.
+	Signal(context.Context, *SignalRequest) (*SignalReply, error)
+	// ListOpenFiles returns the files, sockets and pipes processes have open
+	// along with the files they have memory mapped, as lsof would. This is
+	// useful for finding who is holding a deleted file open or has a socket
+	// open (see Network.ListSockets to map sockets to ports).
+	ListOpenFiles(context.Context, *ListOpenFilesRequest) (*ListOpenFilesReply, error)
+	// GetMemoryMap returns the memory mappings of a process and how much of
+	// each is resident, shared, swapped etc. as in /proc/<pid>/smaps. This is
+	// useful for finding what is using memory without a full dump.
+	GetMemoryMap(context.Context, *GetMemoryMapRequest) (*GetMemoryMapReply, error)
+	// ReadMemory returns a region of the memory of a process, which needs to
+	// lie within a single readable mapping (see GetMemoryMap). Like
+	// GetMemoryDump this 100% has sensitive data contained within it. Servers
+	// only allow reads up to a configured size and by default none at all.
+	ReadMemory(*ReadMemoryRequest, Process_ReadMemoryServer) error
 }
 
 // UnimplementedProcessServer should be embedded to have forward compatible implementations.
@@ -145,6 +238,18 @@ func (UnimplementedProcessServer) GetJavaStacks(context.Context, *GetJavaStacksR
 func (UnimplementedProcessServer) GetMemoryDump(*GetMemoryDumpRequest, Process_GetMemoryDumpServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetMemoryDump not implemented")
 }
+func (UnimplementedProcessServer) Signal(context.Context, *SignalRequest) (*SignalReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Signal not implemented")
+}
+func (UnimplementedProcessServer) ListOpenFiles(context.Context, *ListOpenFilesRequest) (*ListOpenFilesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOpenFiles not implemented")
+}
+func (UnimplementedProcessServer) GetMemoryMap(context.Context, *GetMemoryMapRequest) (*GetMemoryMapReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemoryMap not implemented")
+}
+func (UnimplementedProcessServer) ReadMemory(*ReadMemoryRequest, Process_ReadMemoryServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadMemory not implemented")
+}
 
 // UnsafeProcessServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to ProcessServer will
@@ -232,6 +337,81 @@ func (x *processGetMemoryDumpServer) Send(m *GetMemoryDumpReply) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Process_Signal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessServer).Signal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Process.Process/Signal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessServer).Signal(ctx, req.(*SignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Process_ListOpenFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOpenFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessServer).ListOpenFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Process.Process/ListOpenFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessServer).ListOpenFiles(ctx, req.(*ListOpenFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Process_GetMemoryMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemoryMapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessServer).GetMemoryMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Process.Process/GetMemoryMap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessServer).GetMemoryMap(ctx, req.(*GetMemoryMapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Process_ReadMemory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadMemoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProcessServer).ReadMemory(m, &processReadMemoryServer{stream})
+}
+
+type Process_ReadMemoryServer interface {
+	Send(*ReadMemoryReply) error
+	grpc.ServerStream
+}
+
+type processReadMemoryServer struct {
+	grpc.ServerStream
+}
+
+func (x *processReadMemoryServer) Send(m *ReadMemoryReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Process_ServiceDesc is the grpc.ServiceDesc for Process service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -251,6 +431,18 @@ var Process_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetJavaStacks",
 			Handler:    _Process_GetJavaStacks_Handler,
 		},
+		{
+			MethodName: "Signal",
+			Handler:    _Process_Signal_Handler,
+		},
+		{
+			MethodName: "ListOpenFiles",
+			Handler:    _Process_ListOpenFiles_Handler,
+		},
+		{
+			MethodName: "GetMemoryMap",
+			Handler:    _Process_GetMemoryMap_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -258,6 +450,11 @@ var Process_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Process_GetMemoryDump_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ReadMemory",
+			Handler:       _Process_ReadMemory_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "process.proto",
 }
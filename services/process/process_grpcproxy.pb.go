@@ -23,6 +23,10 @@ type ProcessClientProxy interface {
 	GetStacksOneMany(ctx context.Context, in *GetStacksRequest, opts ...grpc.CallOption) (<-chan *GetStacksManyResponse, error)
 	GetJavaStacksOneMany(ctx context.Context, in *GetJavaStacksRequest, opts ...grpc.CallOption) (<-chan *GetJavaStacksManyResponse, error)
 	GetMemoryDumpOneMany(ctx context.Context, in *GetMemoryDumpRequest, opts ...grpc.CallOption) (Process_GetMemoryDumpClientProxy, error)
+	SignalOneMany(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (<-chan *SignalManyResponse, error)
+	ListOpenFilesOneMany(ctx context.Context, in *ListOpenFilesRequest, opts ...grpc.CallOption) (<-chan *ListOpenFilesManyResponse, error)
+	GetMemoryMapOneMany(ctx context.Context, in *GetMemoryMapRequest, opts ...grpc.CallOption) (<-chan *GetMemoryMapManyResponse, error)
+	ReadMemoryOneMany(ctx context.Context, in *ReadMemoryRequest, opts ...grpc.CallOption) (Process_ReadMemoryClientProxy, error)
 }
 
 // Embed the original client inside of this so we get the other generated methods automatically.
@@ -324,3 +328,292 @@ func (c *processClientProxy) GetMemoryDumpOneMany(ctx context.Context, in *GetMe
 	}
 	return x, nil
 }
+
+// SignalManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type SignalManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *SignalReply
+	Error error
+}
+
+// SignalOneMany provides the same API as Signal but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *processClientProxy) SignalOneMany(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (<-chan *SignalManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *SignalManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &SignalManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &SignalReply{},
+			}
+			err := conn.Invoke(ctx, "/Process.Process/Signal", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Process.Process/Signal", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &SignalManyResponse{
+				Resp: &SignalReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ListOpenFilesManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ListOpenFilesManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ListOpenFilesReply
+	Error error
+}
+
+// ListOpenFilesOneMany provides the same API as ListOpenFiles but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *processClientProxy) ListOpenFilesOneMany(ctx context.Context, in *ListOpenFilesRequest, opts ...grpc.CallOption) (<-chan *ListOpenFilesManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ListOpenFilesManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ListOpenFilesManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ListOpenFilesReply{},
+			}
+			err := conn.Invoke(ctx, "/Process.Process/ListOpenFiles", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Process.Process/ListOpenFiles", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ListOpenFilesManyResponse{
+				Resp: &ListOpenFilesReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// GetMemoryMapManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type GetMemoryMapManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *GetMemoryMapReply
+	Error error
+}
+
+// GetMemoryMapOneMany provides the same API as GetMemoryMap but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *processClientProxy) GetMemoryMapOneMany(ctx context.Context, in *GetMemoryMapRequest, opts ...grpc.CallOption) (<-chan *GetMemoryMapManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *GetMemoryMapManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &GetMemoryMapManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &GetMemoryMapReply{},
+			}
+			err := conn.Invoke(ctx, "/Process.Process/GetMemoryMap", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Process.Process/GetMemoryMap", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &GetMemoryMapManyResponse{
+				Resp: &GetMemoryMapReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ReadMemoryManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ReadMemoryManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ReadMemoryReply
+	Error error
+}
+
+type Process_ReadMemoryClientProxy interface {
+	Recv() ([]*ReadMemoryManyResponse, error)
+	grpc.ClientStream
+}
+
+type processClientReadMemoryClientProxy struct {
+	cc         *proxy.Conn
+	directDone bool
+	grpc.ClientStream
+}
+
+func (x *processClientReadMemoryClientProxy) Recv() ([]*ReadMemoryManyResponse, error) {
+	var ret []*ReadMemoryManyResponse
+	// If this is a direct connection the RecvMsg call is to a standard grpc.ClientStream
+	// and not our proxy based one. This means we need to receive a typed response and
+	// convert it into a single slice entry return. This ensures the OneMany style calls
+	// can be used by proxy with 1:N targets and non proxy with 1 target without client changes.
+	if x.cc.Direct() {
+		// Check if we're done. Just return EOF now. Any real error was already sent inside
+		// of a ManyResponse.
+		if x.directDone {
+			return nil, io.EOF
+		}
+		m := &ReadMemoryReply{}
+		err := x.ClientStream.RecvMsg(m)
+		ret = append(ret, &ReadMemoryManyResponse{
+			Resp:   m,
+			Error:  err,
+			Target: x.cc.Targets[0],
+			Index:  0,
+		})
+		// An error means we're done so set things so a later call now gets an EOF.
+		if err != nil {
+			x.directDone = true
+		}
+		return ret, nil
+	}
+
+	m := []*proxy.Ret{}
+	if err := x.ClientStream.RecvMsg(&m); err != nil {
+		return nil, err
+	}
+	for _, r := range m {
+		typedResp := &ReadMemoryManyResponse{
+			Resp: &ReadMemoryReply{},
+		}
+		typedResp.Target = r.Target
+		typedResp.Index = r.Index
+		typedResp.Error = r.Error
+		if r.Error == nil {
+			if err := r.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+				typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, r.Error)
+			}
+		}
+		ret = append(ret, typedResp)
+	}
+	return ret, nil
+}
+
+// ReadMemoryOneMany provides the same API as ReadMemory but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *processClientProxy) ReadMemoryOneMany(ctx context.Context, in *ReadMemoryRequest, opts ...grpc.CallOption) (Process_ReadMemoryClientProxy, error) {
+	stream, err := c.cc.NewStream(ctx, &Process_ServiceDesc.Streams[1], "/Process.Process/ReadMemory", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &processClientReadMemoryClientProxy{c.cc.(*proxy.Conn), false, stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
@@ -0,0 +1,247 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/process"
+)
+
+// memoryChunkSize is the most compressed data sent in each ReadMemoryReply.
+const memoryChunkSize = 64 * 1024
+
+// parseMappingHeader parses the line describing a mapping in maps or smaps:
+//
+//	address perms offset dev inode path
+func parseMappingHeader(line string) (*pb.MemoryMapping, bool) {
+	fields := strings.SplitN(line, " ", 6)
+	if len(fields) < 5 {
+		return nil, false
+	}
+	addrs := strings.SplitN(fields[0], "-", 2)
+	if len(addrs) != 2 {
+		return nil, false
+	}
+	start, err := strconv.ParseUint(addrs[0], 16, 64)
+	if err != nil {
+		return nil, false
+	}
+	end, err := strconv.ParseUint(addrs[1], 16, 64)
+	if err != nil {
+		return nil, false
+	}
+	offset, err := strconv.ParseUint(fields[2], 16, 64)
+	if err != nil {
+		return nil, false
+	}
+	m := &pb.MemoryMapping{
+		Start:       start,
+		End:         end,
+		Permissions: fields[1],
+		Offset:      offset,
+	}
+	if len(fields) == 6 {
+		m.Path = strings.TrimLeft(fields[5], " ")
+	}
+	return m, true
+}
+
+// parseSmaps parses the contents of /proc/<pid>/smaps, or maps in which
+// case only the address, permissions, offset and path of mappings are set.
+func parseSmaps(contents string) ([]*pb.MemoryMapping, error) {
+	var out []*pb.MemoryMapping
+	var cur *pb.MemoryMapping
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if !strings.HasSuffix(fields[0], ":") {
+			m, ok := parseMappingHeader(line)
+			if !ok {
+				return nil, fmt.Errorf("can't parse mapping %q", line)
+			}
+			out = append(out, m)
+			cur = m
+			continue
+		}
+		if cur == nil {
+			return nil, fmt.Errorf("%q before the first mapping", line)
+		}
+		// Everything we want is of the form "Rss: 40 kB".
+		if len(fields) != 3 || fields[2] != "kB" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %q: %v", line, err)
+		}
+		size := kb * 1024
+		switch fields[0] {
+		case "Size:":
+			cur.Size = size
+		case "Rss:":
+			cur.Rss = size
+		case "Pss:":
+			cur.Pss = size
+		case "Shared_Clean:":
+			cur.SharedClean = size
+		case "Shared_Dirty:":
+			cur.SharedDirty = size
+		case "Private_Clean:":
+			cur.PrivateClean = size
+		case "Private_Dirty:":
+			cur.PrivateDirty = size
+		case "Anonymous:":
+			cur.Anonymous = size
+		case "Swap:":
+			cur.Swap = size
+		}
+	}
+	return out, scanner.Err()
+}
+
+// readMappings parses /proc/<pid>/<file> with parseSmaps.
+func readMappings(pid int64, file string) ([]*pb.MemoryMapping, error) {
+	path := filepath.Join(procDir, strconv.FormatInt(pid, 10), file)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, status.Errorf(codes.NotFound, "no such process %d", pid)
+		}
+		return nil, status.Errorf(codes.Internal, "can't read %s: %v", path, err)
+	}
+	mappings, err := parseSmaps(string(b))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't parse %s: %v", path, err)
+	}
+	return mappings, nil
+}
+
+// GetMemoryMap implements pb.ProcessServer.GetMemoryMap
+func (s *server) GetMemoryMap(ctx context.Context, req *pb.GetMemoryMapRequest) (*pb.GetMemoryMapReply, error) {
+	if req.Pid <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "pid must be non-zero and positive")
+	}
+	mappings, err := readMappings(req.Pid, "smaps")
+	if err != nil {
+		return nil, err
+	}
+	total := &pb.MemoryMapping{}
+	for _, m := range mappings {
+		total.Size += m.Size
+		total.Rss += m.Rss
+		total.Pss += m.Pss
+		total.SharedClean += m.SharedClean
+		total.SharedDirty += m.SharedDirty
+		total.PrivateClean += m.PrivateClean
+		total.PrivateDirty += m.PrivateDirty
+		total.Anonymous += m.Anonymous
+		total.Swap += m.Swap
+	}
+	return &pb.GetMemoryMapReply{Mappings: mappings, Total: total}, nil
+}
+
+// memoryWriter sends everything written to it on a ReadMemory stream.
+type memoryWriter struct {
+	stream pb.Process_ReadMemoryServer
+}
+
+func (w *memoryWriter) Write(b []byte) (int, error) {
+	if err := w.stream.Send(&pb.ReadMemoryReply{Data: b}); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// ReadMemory implements pb.ProcessServer.ReadMemory
+func (s *server) ReadMemory(req *pb.ReadMemoryRequest, stream pb.Process_ReadMemoryServer) error {
+	if req.Pid <= 0 {
+		return status.Error(codes.InvalidArgument, "pid must be non-zero and positive")
+	}
+	if req.Length == 0 {
+		return status.Error(codes.InvalidArgument, "length must be non-zero")
+	}
+	if *maxMemoryRead <= 0 {
+		return status.Error(codes.PermissionDenied, "reading process memory is disabled on this server")
+	}
+	if req.Length > uint64(*maxMemoryRead) {
+		return status.Errorf(codes.ResourceExhausted, "can't read %d bytes, the limit is %d", req.Length, *maxMemoryRead)
+	}
+	end := req.Address + req.Length
+	if end < req.Address || end > math.MaxInt64 {
+		return status.Errorf(codes.InvalidArgument, "invalid region %#x+%d", req.Address, req.Length)
+	}
+
+	mappings, err := readMappings(req.Pid, "maps")
+	if err != nil {
+		return err
+	}
+	readable := false
+	for _, m := range mappings {
+		if m.Start <= req.Address && end <= m.End {
+			readable = strings.HasPrefix(m.Permissions, "r")
+			break
+		}
+	}
+	if !readable {
+		return status.Errorf(codes.InvalidArgument, "%#x-%#x isn't within a single readable mapping of process %d", req.Address, end, req.Pid)
+	}
+
+	ctx := stream.Context()
+	logr.FromContextOrDiscard(ctx).Info("reading process memory", "pid", req.Pid, "address", req.Address, "length", req.Length)
+	path := filepath.Join(procDir, strconv.FormatInt(req.Pid, 10), "mem")
+	f, err := os.Open(path)
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriterSize(&memoryWriter{stream: stream}, memoryChunkSize)
+	gz := gzip.NewWriter(w)
+	if _, err := io.Copy(gz, io.NewSectionReader(f, int64(req.Address), int64(req.Length))); err != nil {
+		return status.Errorf(codes.Internal, "reading memory of process %d failed: %v", req.Pid, err)
+	}
+	if err := gz.Close(); err != nil {
+		return status.Errorf(codes.Internal, "can't send on stream: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		return status.Errorf(codes.Internal, "can't send on stream: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/process"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+const testSmaps = `55d1c0a00000-55d1c0a20000 r-xp 00001000 fd:01 1234                       /usr/bin/cat
+Size:                128 kB
+KernelPageSize:        4 kB
+MMUPageSize:           4 kB
+Rss:                  96 kB
+Pss:                  48 kB
+Shared_Clean:         96 kB
+Shared_Dirty:          0 kB
+Private_Clean:         0 kB
+Private_Dirty:         0 kB
+Referenced:           96 kB
+Anonymous:             0 kB
+Swap:                  0 kB
+THPeligible:    0
+VmFlags: rd ex mr mw me dw
+55d1c1000000-55d1c1021000 rw-p 00000000 00:00 0                          [heap]
+Size:                132 kB
+Rss:                  12 kB
+Pss:                  12 kB
+Private_Dirty:        12 kB
+Anonymous:            12 kB
+Swap:                  8 kB
+VmFlags: rd wr mr mw me ac
+7f00001bd000-7f00001c0000 rw-p 00000000 00:00 0
+Size:                 12 kB
+Rss:                   4 kB
+Pss:                   4 kB
+Private_Dirty:         4 kB
+Anonymous:             4 kB
+VmFlags: rd wr mr mw me ac
+`
+
+func TestGetMemoryMap(t *testing.T) {
+	saved := procDir
+	t.Cleanup(func() { procDir = saved })
+	procDir = t.TempDir()
+	for pid, smaps := range map[string]string{
+		"100": testSmaps,
+		"200": "not a mapping\n",
+	} {
+		testutil.FatalOnErr("mkdir", os.Mkdir(filepath.Join(procDir, pid), 0755), t)
+		testutil.FatalOnErr("writing smaps", os.WriteFile(filepath.Join(procDir, pid, "smaps"), []byte(smaps), 0644), t)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		pid     int64
+		want    *pb.GetMemoryMapReply
+		wantErr codes.Code
+	}{
+		{
+			name: "smaps",
+			pid:  100,
+			want: &pb.GetMemoryMapReply{
+				Mappings: []*pb.MemoryMapping{
+					{Start: 0x55d1c0a00000, End: 0x55d1c0a20000, Permissions: "r-xp", Offset: 0x1000, Path: "/usr/bin/cat", Size: 128 << 10, Rss: 96 << 10, Pss: 48 << 10, SharedClean: 96 << 10},
+					{Start: 0x55d1c1000000, End: 0x55d1c1021000, Permissions: "rw-p", Path: "[heap]", Size: 132 << 10, Rss: 12 << 10, Pss: 12 << 10, PrivateDirty: 12 << 10, Anonymous: 12 << 10, Swap: 8 << 10},
+					{Start: 0x7f00001bd000, End: 0x7f00001c0000, Permissions: "rw-p", Size: 12 << 10, Rss: 4 << 10, Pss: 4 << 10, PrivateDirty: 4 << 10, Anonymous: 4 << 10},
+				},
+				Total: &pb.MemoryMapping{Size: 272 << 10, Rss: 112 << 10, Pss: 64 << 10, SharedClean: 96 << 10, PrivateDirty: 16 << 10, Anonymous: 16 << 10, Swap: 8 << 10},
+			},
+		},
+		{
+			name:    "bad smaps",
+			pid:     200,
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "no such process",
+			pid:     300,
+			wantErr: codes.NotFound,
+		},
+		{
+			name:    "bad pid",
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{}
+			resp, err := s.GetMemoryMap(context.Background(), &pb.GetMemoryMapRequest{Pid: tc.pid})
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("GetMemoryMap(%d) = %v, want code %v", tc.pid, err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, resp, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected reply (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadMemory(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewProcessClient(conn)
+
+	savedMax := *maxMemoryRead
+	t.Cleanup(func() { *maxMemoryRead = savedMax })
+
+	// Read a buffer of this test's own memory.
+	buf := bytes.Repeat([]byte("sansshell"), 1000)
+	defer runtime.KeepAlive(buf)
+	address := uint64(uintptr(unsafe.Pointer(&buf[0])))
+	pid := int64(os.Getpid())
+
+	for _, tc := range []struct {
+		name    string
+		max     int64
+		req     *pb.ReadMemoryRequest
+		want    []byte
+		wantErr codes.Code
+	}{
+		{
+			name: "read",
+			max:  1 << 20,
+			req:  &pb.ReadMemoryRequest{Pid: pid, Address: address, Length: uint64(len(buf))},
+			want: buf,
+		},
+		{
+			name:    "disabled",
+			req:     &pb.ReadMemoryRequest{Pid: pid, Address: address, Length: uint64(len(buf))},
+			wantErr: codes.PermissionDenied,
+		},
+		{
+			name:    "too large",
+			max:     100,
+			req:     &pb.ReadMemoryRequest{Pid: pid, Address: address, Length: uint64(len(buf))},
+			wantErr: codes.ResourceExhausted,
+		},
+		{
+			name:    "unmapped",
+			max:     1 << 20,
+			req:     &pb.ReadMemoryRequest{Pid: pid, Address: 0, Length: 4096},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "overflow",
+			max:     1 << 20,
+			req:     &pb.ReadMemoryRequest{Pid: pid, Address: ^uint64(0) - 10, Length: 100},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "no length",
+			max:     1 << 20,
+			req:     &pb.ReadMemoryRequest{Pid: pid, Address: address},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "no such process",
+			max:     1 << 20,
+			req:     &pb.ReadMemoryRequest{Pid: 1 << 30, Address: address, Length: 10},
+			wantErr: codes.NotFound,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			*maxMemoryRead = tc.max
+			stream, err := client.ReadMemory(ctx, tc.req)
+			testutil.FatalOnErr("ReadMemory", err, t)
+			var compressed []byte
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if got := status.Code(err); got != tc.wantErr {
+					t.Fatalf("ReadMemory(%v) = %v, want code %v", tc.req, err, tc.wantErr)
+				}
+				if err != nil {
+					return
+				}
+				compressed = append(compressed, resp.Data...)
+			}
+			if tc.wantErr != codes.OK {
+				t.Fatalf("ReadMemory(%v) succeeded, want code %v", tc.req, tc.wantErr)
+			}
+			r, err := gzip.NewReader(bytes.NewReader(compressed))
+			testutil.FatalOnErr("gzip reader", err, t)
+			got, err := io.ReadAll(r)
+			testutil.FatalOnErr("decompressing", err, t)
+			if !bytes.Equal(got, tc.want) {
+				t.Errorf("read %d bytes which don't match what's in memory", len(got))
+			}
+		})
+	}
+}
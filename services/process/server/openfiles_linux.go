@@ -0,0 +1,268 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/process"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+// procDir is where proc(5) is mounted.
+var procDir = "/proc"
+
+// deletedSuffix is appended by the kernel to the names of deleted files.
+const deletedSuffix = " (deleted)"
+
+// specialFiles are the links in /proc/<pid> to files a process has open
+// other than through a descriptor, and what lsof calls them.
+var specialFiles = []struct {
+	link string
+	fd   string
+}{
+	{"cwd", "cwd"},
+	{"root", "rtd"},
+	{"exe", "txt"},
+}
+
+// inodeName parses names such as socket:[1234] returning the inode.
+func inodeName(link string, prefix string) (uint64, bool) {
+	if !strings.HasPrefix(link, prefix+":[") || !strings.HasSuffix(link, "]") {
+		return 0, false
+	}
+	inode, err := strconv.ParseUint(link[len(prefix)+2:len(link)-1], 10, 64)
+	return inode, err == nil
+}
+
+// openFile returns the entry for `link`, the target of the link `file` in
+// /proc/<pid>.
+func openFile(file string, link string) *pb.OpenFile {
+	f := &pb.OpenFile{Path: link}
+	if inode, ok := inodeName(link, "socket"); ok {
+		f.Type, f.Inode = pb.OpenFileType_OPEN_FILE_TYPE_SOCKET, inode
+		return f
+	}
+	if inode, ok := inodeName(link, "pipe"); ok {
+		f.Type, f.Inode = pb.OpenFileType_OPEN_FILE_TYPE_PIPE, inode
+		return f
+	}
+	if strings.HasPrefix(link, "anon_inode:") {
+		f.Type = pb.OpenFileType_OPEN_FILE_TYPE_ANON_INODE
+		return f
+	}
+	if strings.HasSuffix(link, deletedSuffix) {
+		f.Path, f.Deleted = strings.TrimSuffix(link, deletedSuffix), true
+	}
+	// Stat through the link as that works even if the file was deleted.
+	fi, err := os.Stat(file)
+	if err != nil {
+		return f
+	}
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		f.Inode = st.Ino
+	}
+	switch {
+	case fi.Mode().IsRegular():
+		f.Type = pb.OpenFileType_OPEN_FILE_TYPE_REGULAR
+	case fi.IsDir():
+		f.Type = pb.OpenFileType_OPEN_FILE_TYPE_DIRECTORY
+	case fi.Mode()&fs.ModeDevice != 0:
+		f.Type = pb.OpenFileType_OPEN_FILE_TYPE_DEVICE
+	case fi.Mode()&fs.ModeNamedPipe != 0:
+		f.Type = pb.OpenFileType_OPEN_FILE_TYPE_PIPE
+	case fi.Mode()&fs.ModeSocket != 0:
+		f.Type = pb.OpenFileType_OPEN_FILE_TYPE_SOCKET
+	}
+	return f
+}
+
+// mappedFiles parses /proc/<pid>/maps returning the files mapped, once each,
+// in the order they're first mapped.
+func mappedFiles(contents string) []*pb.OpenFile {
+	var out []*pb.OpenFile
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		// address perms offset dev inode path
+		fields := strings.SplitN(scanner.Text(), " ", 6)
+		if len(fields) != 6 {
+			continue
+		}
+		path := strings.TrimLeft(fields[5], " ")
+		inode, err := strconv.ParseUint(fields[4], 10, 64)
+		// Anonymous mappings and ones like [heap] have no inode.
+		if err != nil || inode == 0 || !strings.HasPrefix(path, "/") || seen[path] {
+			continue
+		}
+		seen[path] = true
+		f := &pb.OpenFile{
+			Fd:    "mem",
+			Type:  pb.OpenFileType_OPEN_FILE_TYPE_REGULAR,
+			Path:  path,
+			Inode: inode,
+		}
+		if strings.HasSuffix(path, deletedSuffix) {
+			f.Path, f.Deleted = strings.TrimSuffix(path, deletedSuffix), true
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// processFiles returns the files process `pid` has open.
+func processFiles(pid int64) ([]*pb.OpenFile, error) {
+	dir := filepath.Join(procDir, strconv.FormatInt(pid, 10))
+	comm, err := os.ReadFile(filepath.Join(dir, "comm"))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*pb.OpenFile
+	exe := ""
+	for _, s := range specialFiles {
+		file := filepath.Join(dir, s.link)
+		link, err := os.Readlink(file)
+		if err != nil {
+			// Kernel threads have no executable.
+			continue
+		}
+		f := openFile(file, link)
+		f.Fd = s.fd
+		if s.link == "exe" {
+			exe = f.Path
+		}
+		out = append(out, f)
+	}
+
+	fdDir := filepath.Join(dir, "fd")
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return nil, err
+	}
+	var fds []int
+	for _, e := range entries {
+		if fd, err := strconv.Atoi(e.Name()); err == nil {
+			fds = append(fds, fd)
+		}
+	}
+	sort.Ints(fds)
+	for _, fd := range fds {
+		file := filepath.Join(fdDir, strconv.Itoa(fd))
+		link, err := os.Readlink(file)
+		if err != nil {
+			// Closed since the directory was read.
+			continue
+		}
+		f := openFile(file, link)
+		f.Fd = strconv.Itoa(fd)
+		out = append(out, f)
+	}
+
+	// Kernel threads have an empty maps file, and it can't be read at all
+	// once a process is a zombie.
+	if maps, err := os.ReadFile(filepath.Join(dir, "maps")); err == nil {
+		for _, f := range mappedFiles(string(maps)) {
+			// The executable is already listed as txt.
+			if f.Path != exe {
+				out = append(out, f)
+			}
+		}
+	}
+
+	for _, f := range out {
+		f.Pid = pid
+		f.Command = strings.TrimSpace(string(comm))
+	}
+	return out, nil
+}
+
+// underPath returns whether file is path or, if path is a directory, in it.
+func underPath(file string, path string) bool {
+	return file == path || strings.HasPrefix(file, strings.TrimSuffix(path, "/")+"/")
+}
+
+// ListOpenFiles implements pb.ProcessServer.ListOpenFiles
+func (s *server) ListOpenFiles(ctx context.Context, req *pb.ListOpenFilesRequest) (*pb.ListOpenFilesReply, error) {
+	for _, pid := range req.Pids {
+		if pid <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid pid %d", pid)
+		}
+	}
+	path := req.Path
+	if path != "" {
+		if err := util.ValidPath(path); err != nil {
+			return nil, err
+		}
+		// The kernel reports files by their real path.
+		if p, err := filepath.EvalSymlinks(path); err == nil {
+			path = p
+		}
+	}
+
+	pids := req.Pids
+	if len(pids) == 0 {
+		entries, err := os.ReadDir(procDir)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't list processes: %v", err)
+		}
+		for _, e := range entries {
+			if pid, err := strconv.ParseInt(e.Name(), 10, 64); err == nil {
+				pids = append(pids, pid)
+			}
+		}
+	}
+
+	reply := &pb.ListOpenFilesReply{}
+	for _, pid := range pids {
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		files, err := processFiles(pid)
+		if err != nil {
+			if len(req.Pids) == 0 {
+				// Processes may exit while being examined.
+				continue
+			}
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, status.Errorf(codes.NotFound, "no such process %d", pid)
+			}
+			return nil, status.Errorf(codes.Internal, "can't read open files of process %d: %v", pid, err)
+		}
+		for _, f := range files {
+			if path == "" || underPath(f.Path, path) {
+				reply.Files = append(reply.Files, f)
+			}
+		}
+	}
+	return reply, nil
+}
@@ -0,0 +1,228 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/process"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func inodeOf(t *testing.T, path string) uint64 {
+	t.Helper()
+	fi, err := os.Stat(path)
+	testutil.FatalOnErr("stat "+path, err, t)
+	return fi.Sys().(*syscall.Stat_t).Ino
+}
+
+func TestListOpenFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"srv/data", "usr/lib"} {
+		testutil.FatalOnErr("mkdir", os.MkdirAll(filepath.Join(root, d), 0755), t)
+	}
+	sshd := filepath.Join(root, "sshd")
+	data := filepath.Join(root, "srv/data/db")
+	for _, f := range []string{sshd, data} {
+		testutil.FatalOnErr("write", os.WriteFile(f, nil, 0644), t)
+	}
+	deleted := filepath.Join(root, "srv/old.log")
+	libc := filepath.Join(root, "usr/lib/libc.so.6")
+
+	saved := procDir
+	t.Cleanup(func() { procDir = saved })
+	procDir = t.TempDir()
+	for pid, p := range map[string]struct {
+		comm  string
+		links map[string]string
+		maps  string
+	}{
+		"700": {
+			comm: "sshd",
+			links: map[string]string{
+				"cwd":  root,
+				"root": root,
+				"exe":  sshd,
+				"fd/0": "/dev/null",
+				"fd/3": "socket:[1001]",
+				"fd/4": "pipe:[77]",
+				"fd/5": "anon_inode:[eventpoll]",
+				"fd/6": deleted + " (deleted)",
+			},
+			maps: "55d1c0a00000-55d1c0a20000 r--p 00000000 fd:01 1234     " + sshd + "\n" +
+				"55d1c0a20000-55d1c0a80000 r-xp 00020000 fd:01 1234     " + sshd + "\n" +
+				"55d1c1000000-55d1c1021000 rw-p 00000000 00:00 0        [heap]\n" +
+				"7f0000000000-7f0000028000 r--p 00000000 fd:01 5678     " + libc + "\n" +
+				"7f0000028000-7f00001bd000 r-xp 00028000 fd:01 5678     " + libc + "\n" +
+				"7f00001bd000-7f00001c0000 rw-p 00000000 00:00 0 \n" +
+				"7f0000200000-7f0000201000 r--s 00000000 00:05 9999     /dev/shm/gone (deleted)\n",
+		},
+		"900": {
+			comm: "postgres",
+			links: map[string]string{
+				"fd/10": data,
+				"fd/2":  "/dev/null",
+			},
+		},
+		// A kernel thread.
+		"2": {
+			comm: "kthreadd",
+			links: map[string]string{
+				"cwd":  "/",
+				"root": "/",
+			},
+		},
+	} {
+		dir := filepath.Join(procDir, pid)
+		testutil.FatalOnErr("mkdir", os.MkdirAll(filepath.Join(dir, "fd"), 0755), t)
+		testutil.FatalOnErr("writing comm", os.WriteFile(filepath.Join(dir, "comm"), []byte(p.comm+"\n"), 0644), t)
+		testutil.FatalOnErr("writing maps", os.WriteFile(filepath.Join(dir, "maps"), []byte(p.maps), 0644), t)
+		for l, target := range p.links {
+			testutil.FatalOnErr("symlink", os.Symlink(target, filepath.Join(dir, l)), t)
+		}
+	}
+	// Not a process.
+	testutil.FatalOnErr("mkdir", os.Mkdir(filepath.Join(procDir, "sys"), 0755), t)
+
+	rootInode, devNullInode := inodeOf(t, root), inodeOf(t, "/dev/null")
+	sshdFiles := []*pb.OpenFile{
+		{Fd: "cwd", Type: pb.OpenFileType_OPEN_FILE_TYPE_DIRECTORY, Path: root, Inode: rootInode},
+		{Fd: "rtd", Type: pb.OpenFileType_OPEN_FILE_TYPE_DIRECTORY, Path: root, Inode: rootInode},
+		{Fd: "txt", Type: pb.OpenFileType_OPEN_FILE_TYPE_REGULAR, Path: sshd, Inode: inodeOf(t, sshd)},
+		{Fd: "0", Type: pb.OpenFileType_OPEN_FILE_TYPE_DEVICE, Path: "/dev/null", Inode: devNullInode},
+		{Fd: "3", Type: pb.OpenFileType_OPEN_FILE_TYPE_SOCKET, Path: "socket:[1001]", Inode: 1001},
+		{Fd: "4", Type: pb.OpenFileType_OPEN_FILE_TYPE_PIPE, Path: "pipe:[77]", Inode: 77},
+		{Fd: "5", Type: pb.OpenFileType_OPEN_FILE_TYPE_ANON_INODE, Path: "anon_inode:[eventpoll]"},
+		// The fake link can't be followed as a real one to a deleted file can.
+		{Fd: "6", Type: pb.OpenFileType_OPEN_FILE_TYPE_UNKNOWN, Path: deleted, Deleted: true},
+		{Fd: "mem", Type: pb.OpenFileType_OPEN_FILE_TYPE_REGULAR, Path: libc, Inode: 5678},
+		{Fd: "mem", Type: pb.OpenFileType_OPEN_FILE_TYPE_REGULAR, Path: "/dev/shm/gone", Inode: 9999, Deleted: true},
+	}
+	for _, f := range sshdFiles {
+		f.Pid, f.Command = 700, "sshd"
+	}
+	// Descriptors are sorted numerically.
+	postgresFiles := []*pb.OpenFile{
+		{Pid: 900, Command: "postgres", Fd: "2", Type: pb.OpenFileType_OPEN_FILE_TYPE_DEVICE, Path: "/dev/null", Inode: devNullInode},
+		{Pid: 900, Command: "postgres", Fd: "10", Type: pb.OpenFileType_OPEN_FILE_TYPE_REGULAR, Path: data, Inode: inodeOf(t, data)},
+	}
+	kthreadFiles := []*pb.OpenFile{
+		{Pid: 2, Command: "kthreadd", Fd: "cwd", Type: pb.OpenFileType_OPEN_FILE_TYPE_DIRECTORY, Path: "/", Inode: inodeOf(t, "/")},
+		{Pid: 2, Command: "kthreadd", Fd: "rtd", Type: pb.OpenFileType_OPEN_FILE_TYPE_DIRECTORY, Path: "/", Inode: inodeOf(t, "/")},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.ListOpenFilesRequest
+		want    []*pb.OpenFile
+		wantErr codes.Code
+	}{
+		{
+			name: "pid",
+			req:  &pb.ListOpenFilesRequest{Pids: []int64{700}},
+			want: sshdFiles,
+		},
+		{
+			name: "all",
+			req:  &pb.ListOpenFilesRequest{},
+			want: append(append(append([]*pb.OpenFile{}, kthreadFiles...), sshdFiles...), postgresFiles...),
+		},
+		{
+			name: "file",
+			req:  &pb.ListOpenFilesRequest{Path: "/dev/null"},
+			want: []*pb.OpenFile{sshdFiles[3], postgresFiles[0]},
+		},
+		{
+			name: "directory",
+			req:  &pb.ListOpenFilesRequest{Path: filepath.Join(root, "srv")},
+			want: []*pb.OpenFile{sshdFiles[7], postgresFiles[1]},
+		},
+		{
+			name: "path and pid",
+			req:  &pb.ListOpenFilesRequest{Pids: []int64{900, 2}, Path: root},
+			want: []*pb.OpenFile{postgresFiles[1]},
+		},
+		{
+			name: "nothing matches",
+			req:  &pb.ListOpenFilesRequest{Path: "/nonexistent"},
+		},
+		{
+			name:    "no such process",
+			req:     &pb.ListOpenFilesRequest{Pids: []int64{700, 12345}},
+			wantErr: codes.NotFound,
+		},
+		{
+			name:    "bad pid",
+			req:     &pb.ListOpenFilesRequest{Pids: []int64{-1}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "relative path",
+			req:     &pb.ListOpenFilesRequest{Path: "srv/data"},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{}
+			resp, err := s.ListOpenFiles(context.Background(), tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("ListOpenFiles(%v) = %v, want code %v", tc.req, err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, resp.Files, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected files (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestListOpenFilesSelf(t *testing.T) {
+	// Use the real /proc to find a file this test has open.
+	name := filepath.Join(t.TempDir(), "open")
+	f, err := os.Create(name)
+	testutil.FatalOnErr("create", err, t)
+	t.Cleanup(func() { f.Close() })
+
+	s := &server{}
+	resp, err := s.ListOpenFiles(context.Background(), &pb.ListOpenFilesRequest{Pids: []int64{int64(os.Getpid())}, Path: name})
+	testutil.FatalOnErr("ListOpenFiles", err, t)
+	if len(resp.Files) != 1 {
+		t.Fatalf("got %d files, want 1: %v", len(resp.Files), resp.Files)
+	}
+	if got, want := resp.Files[0].Fd, strconv.Itoa(int(f.Fd())); got != want {
+		t.Errorf("got fd %s, want %s", got, want)
+	}
+	if got, want := resp.Files[0].Type, pb.OpenFileType_OPEN_FILE_TYPE_REGULAR; got != want {
+		t.Errorf("got type %v, want %v", got, want)
+	}
+}
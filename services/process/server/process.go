@@ -25,6 +25,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"gocloud.dev/blob"
@@ -50,6 +51,10 @@ var (
 	// These are effectively platform agnostic so they can here vs the architecture specific files.
 	jstackBin = flag.String("jstack-bin", "/usr/lib/jvm/adoptopenjdk-11-hotspot/bin/jstack", "Path to the jstack binary")
 	jmapBin   = flag.String("jmap-bin", "/usr/lib/jvm/adoptopenjdk-11-hotspot/bin/jmap", "Path to the jmap binary")
+	detectJDK = flag.Bool("detect-jdk-tools", true, "If true run jstack and jmap from the JDK of the target JVM when it can be found rather than --jstack-bin and --jmap-bin")
+
+	maxDumpSize   = flag.Int64("max-memory-dump-size", 0, "If positive memory dumps larger than this many bytes are rejected rather than returned")
+	maxMemoryRead = flag.Int64("max-memory-read-size", 0, "Maximum number of bytes of process memory ReadMemory may return in one request. Reads are rejected unless positive.")
 )
 
 // Vars so we can replace for testing.
@@ -102,6 +107,30 @@ var (
 	}
 )
 
+// jdkTool returns the path to the JDK tool `name` (i.e. jstack) from the
+// same JDK as the JVM running as pid. The tools have to match the target's
+// version to attach to it so this is preferred over a single configured
+// path. If it can't be found (or --detect-jdk-tools is off) fallback is
+// returned.
+func jdkTool(pid int64, name string, fallback string) string {
+	if !*detectJDK {
+		return fallback
+	}
+	java, err := javaBinary(pid)
+	if err != nil || filepath.Base(java) != "java" {
+		return fallback
+	}
+	dir := filepath.Dir(java)
+	// JDK 8 and earlier put java in jre/bin with the tools in bin.
+	for _, d := range []string{dir, filepath.Join(dir, "..", "..", "bin")} {
+		tool := filepath.Join(d, name)
+		if fi, err := os.Stat(tool); err == nil && fi.Mode().IsRegular() && fi.Mode()&0111 != 0 {
+			return tool
+		}
+	}
+	return fallback
+}
+
 func (s *server) List(ctx context.Context, req *pb.ListRequest) (*pb.ListReply, error) {
 	cmdName := *psBin
 	options := psOptions()
@@ -213,16 +242,15 @@ func (s *server) GetStacks(ctx context.Context, req *pb.GetStacksRequest) (*pb.G
 }
 
 func (s *server) GetJavaStacks(ctx context.Context, req *pb.GetJavaStacksRequest) (*pb.GetJavaStacksReply, error) {
-	// This is tied to pstack so either an OS provides it or it doesn't.
-	if *jstackBin == "" {
-		return nil, status.Error(codes.Unimplemented, "not implemented")
-	}
-
 	if req.Pid <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "pid must be non-zero and positive")
 	}
 
-	cmdName := *jstackBin
+	// This is tied to jstack so either an OS provides it or it doesn't.
+	cmdName := jdkTool(req.Pid, "jstack", *jstackBin)
+	if cmdName == "" {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
 	options := jstackOptions(req)
 
 	// jstack emits stderr output related to environment vars. So only complain on a non-zero exit.
@@ -353,8 +381,18 @@ func (s *server) GetMemoryDump(req *pb.GetMemoryDumpRequest, stream pb.Process_G
 	if req.Pid <= 0 {
 		return status.Error(codes.InvalidArgument, "pid must be non-zero and positive")
 	}
+	if req.MaxSize < 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid max_size %d", req.MaxSize)
+	}
+	maxSize := req.MaxSize
+	if *maxDumpSize > 0 && (maxSize == 0 || *maxDumpSize < maxSize) {
+		maxSize = *maxDumpSize
+	}
 
 	var dest io.WriteCloser
+	// Canceling this aborts writing to a URL destination.
+	blobCtx, blobCancel := context.WithCancel(stream.Context())
+	defer blobCancel()
 	p, ok := peer.FromContext(stream.Context())
 	if !ok {
 		return status.Error(codes.Internal, "can't get peer from context")
@@ -375,10 +413,10 @@ func (s *server) GetMemoryDump(req *pb.GetMemoryDumpRequest, stream pb.Process_G
 		bucketFile = fmt.Sprintf("%s-core.%d", p.Addr.String(), req.Pid)
 	case pb.DumpType_DUMP_TYPE_JMAP:
 		// This is tied to jmap so either an OS provides it or it doesn't.
-		if *jmapBin == "" {
+		cmdName = jdkTool(req.Pid, "jmap", *jmapBin)
+		if cmdName == "" {
 			return status.Error(codes.Unimplemented, "not implemented")
 		}
-		cmdName = *jmapBin
 		options, file, err = jmapOptionsAndLocation(req)
 		bucketFile = fmt.Sprintf("%s-heapdump.%d", p.Addr.String(), req.Pid)
 	default:
@@ -395,7 +433,7 @@ func (s *server) GetMemoryDump(req *pb.GetMemoryDumpRequest, stream pb.Process_G
 		// Nothing to do here, we just send it back.
 	case *pb.GetMemoryDumpRequest_Url:
 		// Take the URL and append a filename composed above (either heap or core).
-		dest, err = openBlobForWriting(stream.Context(), req.GetUrl().Url, bucketFile)
+		dest, err = openBlobForWriting(blobCtx, req.GetUrl().Url, bucketFile)
 		if err != nil {
 			return status.Errorf(codes.InvalidArgument, "can't open blob %s in bucket %s for writing: %v", bucketFile, req.GetUrl().Url, err)
 		}
@@ -421,6 +459,14 @@ func (s *server) GetMemoryDump(req *pb.GetMemoryDumpRequest, stream pb.Process_G
 		return status.Errorf(codes.Internal, "can't open %s for processing: %v", file, err)
 	}
 	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't stat dump file %s - %v", file, err)
+	}
+	if maxSize > 0 && fi.Size() > maxSize {
+		blobCancel()
+		return status.Errorf(codes.ResourceExhausted, "dump is %d bytes which is larger than the limit of %d", fi.Size(), maxSize)
+	}
 
 	b := make([]byte, util.StreamingChunkSize)
 
@@ -429,10 +475,6 @@ func (s *server) GetMemoryDump(req *pb.GetMemoryDumpRequest, stream pb.Process_G
 		if err != nil {
 			return status.Errorf(codes.Internal, "can't copy to remote URL %s - %v", req.GetUrl().Url, err)
 		}
-		fi, err := f.Stat()
-		if err != nil {
-			return status.Errorf(codes.Internal, "can't stat dump file %s - %v", file, err)
-		}
 		if got, want := written, fi.Size(); got != want {
 			return status.Errorf(codes.Internal, "didn't write correct bytes to URL %s. Expected %d and wrote %d", req.GetUrl().Url, want, got)
 		}
@@ -463,6 +505,34 @@ func (s *server) GetMemoryDump(req *pb.GetMemoryDumpRequest, stream pb.Process_G
 	return nil
 }
 
+func (s *server) Signal(ctx context.Context, req *pb.SignalRequest) (*pb.SignalReply, error) {
+	pid := req.GetPid()
+	if pidfile := req.GetPidfile(); pidfile != "" {
+		if err := util.ValidPath(pidfile); err != nil {
+			return nil, err
+		}
+		b, err := os.ReadFile(pidfile)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "can't read pidfile: %v", err)
+		}
+		if pid, err = strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "can't parse pidfile %s: %v", pidfile, err)
+		}
+	}
+	if pid <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "pid must be non-zero and positive")
+	}
+	if req.Signal == "" {
+		return nil, status.Error(codes.InvalidArgument, "must specify a signal")
+	}
+
+	logr.FromContextOrDiscard(ctx).Info("signalling process", "pid", pid, "signal", req.Signal, "expected_name", req.ExpectedName)
+	if err := sendSignal(ctx, pid, req.Signal, req.ExpectedName); err != nil {
+		return nil, err
+	}
+	return &pb.SignalReply{Pid: pid}, nil
+}
+
 // Register is called to expose this handler to the gRPC server
 func (s *server) Register(gs *grpc.Server) {
 	pb.RegisterProcessServer(gs, s)
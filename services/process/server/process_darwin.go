@@ -25,12 +25,16 @@ package server
 
 import (
 	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 
 	pb "github.com/Snowflake-Labs/sansshell/services/process"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+	"golang.org/x/sys/unix"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -312,3 +316,35 @@ func parser(r io.Reader) (map[int64]*pb.ProcessEntry, error) {
 
 	return entries, nil
 }
+
+// javaBinary isn't implemented on darwin so the configured tools are always used.
+func javaBinary(pid int64) (string, error) {
+	return "", status.Error(codes.Unimplemented, "not implemented")
+}
+
+// sendSignal sends the named signal to pid, checking its command name first
+// if expectedName is set. Unlike Linux there's no way to stop the pid being
+// reused between the check and the signal but the window is small.
+func sendSignal(ctx context.Context, pid int64, signal string, expectedName string) error {
+	sig := unix.SignalNum(signal)
+	if sig == 0 {
+		return status.Errorf(codes.InvalidArgument, "unknown signal %s", signal)
+	}
+	if expectedName != "" {
+		run, err := util.RunCommand(ctx, *psBin, []string{"-o", "comm=", "-p", fmt.Sprintf("%d", pid)})
+		if err != nil {
+			return err
+		}
+		// ps exits non-zero if there's no such process.
+		if run.Error != nil {
+			return status.Errorf(codes.NotFound, "no such process %d", pid)
+		}
+		if name := filepath.Base(strings.TrimSpace(run.Stdout.String())); name != expectedName {
+			return status.Errorf(codes.FailedPrecondition, "process %d is %s, not %s", pid, name, expectedName)
+		}
+	}
+	if err := unix.Kill(int(pid), sig); err != nil {
+		return signalError(pid, err)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@
 package server
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -39,3 +40,11 @@ var (
 func parser(r io.Reader) (map[int64]*ProcessEntry, error) {
 	return nil, fmt.Errorf("No support for OS %s", runtime.GOOS)
 }
+
+func javaBinary(pid int64) (string, error) {
+	return "", fmt.Errorf("No support for OS %s", runtime.GOOS)
+}
+
+func sendSignal(ctx context.Context, pid int64, signal string, expectedName string) error {
+	return fmt.Errorf("No support for OS %s", runtime.GOOS)
+}
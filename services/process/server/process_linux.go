@@ -25,12 +25,17 @@ package server
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
+	"os"
 	"strings"
 
 	pb "github.com/Snowflake-Labs/sansshell/services/process"
+	"golang.org/x/sys/unix"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -357,3 +362,60 @@ func parser(r io.Reader) (map[int64]*pb.ProcessEntry, error) {
 
 	return entries, nil
 }
+
+// javaBinary returns the path of the executable running as pid.
+func javaBinary(pid int64) (string, error) {
+	return os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+}
+
+// maxCommLen is the length command names are truncated to by the kernel.
+const maxCommLen = 15
+
+// sendSignal sends the named signal to pid, checking its command name first
+// if expectedName is set.
+func sendSignal(ctx context.Context, pid int64, signal string, expectedName string) error {
+	sig := unix.SignalNum(signal)
+	if sig == 0 {
+		return status.Errorf(codes.InvalidArgument, "unknown signal %s", signal)
+	}
+
+	// Signalling through a pidfd means the name check below can't race with
+	// the pid being reused since if the process exits the signal fails.
+	// Kernels before 5.3 don't have pidfds so fall back to kill.
+	fd, err := unix.PidfdOpen(int(pid), 0)
+	switch {
+	case err == unix.ENOSYS:
+		fd = -1
+	case err != nil:
+		return signalError(pid, err)
+	default:
+		defer unix.Close(fd)
+	}
+
+	if expectedName != "" {
+		comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return status.Errorf(codes.NotFound, "no such process %d", pid)
+			}
+			return status.Errorf(codes.Internal, "can't read name of process %d: %v", pid, err)
+		}
+		name, want := strings.TrimSuffix(string(comm), "\n"), expectedName
+		if len(want) > maxCommLen {
+			want = want[:maxCommLen]
+		}
+		if name != want {
+			return status.Errorf(codes.FailedPrecondition, "process %d is %s, not %s", pid, name, expectedName)
+		}
+	}
+
+	if fd == -1 {
+		err = unix.Kill(int(pid), sig)
+	} else if _, _, errno := unix.Syscall6(unix.SYS_PIDFD_SEND_SIGNAL, uintptr(fd), uintptr(sig), 0, 0, 0, 0); errno != 0 {
+		err = errno
+	}
+	if err != nil {
+		return signalError(pid, err)
+	}
+	return nil
+}
@@ -19,6 +19,15 @@
 
 package server
 
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
 // OS specific locations for finding test data.
 var (
 	testdataPsTextProto = "./testdata/linux_testdata.ps.textproto"
@@ -39,3 +48,98 @@ var (
 	testdataPstackThreadsBadThreadID     = "./testdata/linux_pstack_threads_bad_thread_id.txt"
 	testdataPstackThreadsBadLwp          = "./testdata/linux_pstack_threads_bad_lwp.txt"
 )
+
+// startFakeJava starts a copy of sleep named java under dir/javaDir and
+// returns its pid.
+func startFakeJava(t *testing.T, dir string, javaDir string) int64 {
+	t.Helper()
+	sleep, err := os.ReadFile(testutil.ResolvePath(t, "sleep"))
+	testutil.FatalOnErr("read sleep", err, t)
+	java := filepath.Join(dir, javaDir, "java")
+	testutil.FatalOnErr("mkdir", os.MkdirAll(filepath.Dir(java), 0755), t)
+	testutil.FatalOnErr("write java", os.WriteFile(java, sleep, 0755), t)
+	cmd := exec.Command(java, "60")
+	testutil.FatalOnErr("start java", cmd.Start(), t)
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	return int64(cmd.Process.Pid)
+}
+
+func TestJDKTool(t *testing.T) {
+	savedDetect := *detectJDK
+	t.Cleanup(func() { *detectJDK = savedDetect })
+
+	// A modern JDK with java and the tools together and a JDK 8 one
+	// with java in the JRE.
+	jdk := t.TempDir()
+	jdkPid := startFakeJava(t, jdk, "bin")
+	jdk8 := t.TempDir()
+	jdk8Pid := startFakeJava(t, jdk8, "jre/bin")
+	for _, d := range []string{filepath.Join(jdk, "bin"), filepath.Join(jdk8, "bin")} {
+		testutil.FatalOnErr("mkdir", os.MkdirAll(d, 0755), t)
+		testutil.FatalOnErr("write jstack", os.WriteFile(filepath.Join(d, "jstack"), nil, 0755), t)
+		testutil.FatalOnErr("write jmap", os.WriteFile(filepath.Join(d, "jmap"), nil, 0644), t)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		pid     int64
+		tool    string
+		disable bool
+		want    string
+	}{
+		{
+			name: "jdk",
+			pid:  jdkPid,
+			tool: "jstack",
+			want: filepath.Join(jdk, "bin", "jstack"),
+		},
+		{
+			name: "jdk 8",
+			pid:  jdk8Pid,
+			tool: "jstack",
+			want: filepath.Join(jdk8, "bin", "jstack"),
+		},
+		{
+			name:    "detection disabled",
+			pid:     jdkPid,
+			tool:    "jstack",
+			disable: true,
+			want:    "fallback",
+		},
+		{
+			name: "not executable",
+			pid:  jdkPid,
+			tool: "jmap",
+			want: "fallback",
+		},
+		{
+			name: "missing tool",
+			pid:  jdkPid,
+			tool: "jcmd",
+			want: "fallback",
+		},
+		{
+			name: "not java",
+			pid:  int64(os.Getpid()),
+			tool: "jstack",
+			want: "fallback",
+		},
+		{
+			name: "no such process",
+			pid:  -1,
+			tool: "jstack",
+			want: "fallback",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			*detectJDK = !tc.disable
+			if got := jdkTool(tc.pid, tc.tool, "fallback"); got != tc.want {
+				t.Fatalf("wrong tool. Want %q Got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -25,8 +25,10 @@ import (
 	"log"
 	"net"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
 
 	pb "github.com/Snowflake-Labs/sansshell/services/process"
@@ -34,7 +36,9 @@ import (
 	"gocloud.dev/blob"
 	_ "gocloud.dev/blob/fileblob"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/testing/protocmp"
@@ -593,6 +597,9 @@ func TestMemoryDump(t *testing.T) {
 	testdir, err := os.MkdirTemp("", "tests")
 	testutil.FatalOnErr("can't create temp dir", err, t)
 	t.Cleanup(func() { os.RemoveAll(testdir) })
+	limitdir := t.TempDir()
+	savedMaxDumpSize := *maxDumpSize
+	t.Cleanup(func() { *maxDumpSize = savedMaxDumpSize })
 
 	for _, tc := range []struct {
 		name     string
@@ -601,7 +608,9 @@ func TestMemoryDump(t *testing.T) {
 		options  func(req *pb.GetMemoryDumpRequest) ([]string, string, error)
 		req      *pb.GetMemoryDumpRequest
 		noOutput bool
-		wantErr  bool
+		// If set the server limit on dump size.
+		serverMax int64
+		wantErr   bool
 	}{
 		{
 			name:    "basic contents check",
@@ -640,6 +649,76 @@ func TestMemoryDump(t *testing.T) {
 				DumpType: pb.DumpType_DUMP_TYPE_GCORE,
 			},
 		},
+		{
+			name:    "within size limit",
+			command: testutil.ResolvePath(t, "cat"),
+			options: goodGcoreOptions,
+			input:   "./testdata/core.test",
+			req: &pb.GetMemoryDumpRequest{
+				Pid:         1,
+				Destination: &pb.GetMemoryDumpRequest_Stream{},
+				DumpType:    pb.DumpType_DUMP_TYPE_GCORE,
+				MaxSize:     42,
+			},
+			serverMax: 100,
+		},
+		{
+			name:    "over size limit",
+			command: testutil.ResolvePath(t, "cat"),
+			options: goodGcoreOptions,
+			input:   "./testdata/core.test",
+			req: &pb.GetMemoryDumpRequest{
+				Pid:         1,
+				Destination: &pb.GetMemoryDumpRequest_Stream{},
+				DumpType:    pb.DumpType_DUMP_TYPE_GCORE,
+				MaxSize:     10,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "over server size limit",
+			command: testutil.ResolvePath(t, "cat"),
+			options: goodGcoreOptions,
+			input:   "./testdata/core.test",
+			req: &pb.GetMemoryDumpRequest{
+				Pid:         1,
+				Destination: &pb.GetMemoryDumpRequest_Stream{},
+				DumpType:    pb.DumpType_DUMP_TYPE_GCORE,
+				MaxSize:     100,
+			},
+			serverMax: 10,
+			wantErr:   true,
+		},
+		{
+			name:    "over size limit - url",
+			command: testutil.ResolvePath(t, "cat"),
+			options: goodGcoreOptions,
+			input:   "./testdata/core.test",
+			req: &pb.GetMemoryDumpRequest{
+				Pid: 1,
+				Destination: &pb.GetMemoryDumpRequest_Url{
+					Url: &pb.DumpDestinationUrl{
+						Url: fmt.Sprintf("file://%s", limitdir),
+					},
+				},
+				DumpType: pb.DumpType_DUMP_TYPE_GCORE,
+				MaxSize:  10,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Negative size limit",
+			command: testutil.ResolvePath(t, "cat"),
+			options: goodGcoreOptions,
+			input:   "./testdata/core.test",
+			req: &pb.GetMemoryDumpRequest{
+				Pid:         1,
+				Destination: &pb.GetMemoryDumpRequest_Stream{},
+				DumpType:    pb.DumpType_DUMP_TYPE_GCORE,
+				MaxSize:     -1,
+			},
+			wantErr: true,
+		},
 		{
 			name:    "No command",
 			input:   "./testdata/core.test",
@@ -764,6 +843,7 @@ func TestMemoryDump(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			*gcoreBin = tc.command
+			*maxDumpSize = tc.serverMax
 			gcoreOptionsAndLocation = tc.options
 			*jmapBin = tc.command
 			jmapOptionsAndLocation = tc.options
@@ -818,6 +898,11 @@ func TestMemoryDump(t *testing.T) {
 				t.Cleanup(func() { bucket.Close() })
 			}
 
+			// Nothing should be left behind when a dump is rejected.
+			if entries, err := os.ReadDir(limitdir); err != nil || len(entries) != 0 {
+				t.Fatalf("%s: unexpected contents of %s: %v %v", tc.name, limitdir, entries, err)
+			}
+
 			if !tc.wantErr {
 				if !bytes.Equal(testdata, data) {
 					t.Fatalf("%s: Responses differ.\nGot\n%+v\n\nWant\n%+v", tc.name, data, testdata)
@@ -826,3 +911,130 @@ func TestMemoryDump(t *testing.T) {
 		})
 	}
 }
+
+func TestSignal(t *testing.T) {
+	var err error
+	ctx := context.Background()
+	conn, err = grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewProcessClient(conn)
+	sleep := testutil.ResolvePath(t, "sleep")
+	pidfile := func(t *testing.T, contents string) string {
+		f := filepath.Join(t.TempDir(), "pid")
+		testutil.FatalOnErr("write pidfile", os.WriteFile(f, []byte(contents), 0644), t)
+		return f
+	}
+
+	for _, tc := range []struct {
+		name string
+		// Called with the pid of a running sleep to generate the request.
+		req        func(t *testing.T, pid int64) *pb.SignalRequest
+		exited     bool
+		wantCode   codes.Code
+		wantSignal syscall.Signal
+	}{
+		{
+			name: "pid",
+			req: func(t *testing.T, pid int64) *pb.SignalRequest {
+				return &pb.SignalRequest{Process: &pb.SignalRequest_Pid{Pid: pid}, Signal: "SIGTERM"}
+			},
+			wantSignal: syscall.SIGTERM,
+		},
+		{
+			name: "pidfile and name",
+			req: func(t *testing.T, pid int64) *pb.SignalRequest {
+				return &pb.SignalRequest{
+					Process:      &pb.SignalRequest_Pidfile{Pidfile: pidfile(t, fmt.Sprintf("%d\n", pid))},
+					Signal:       "SIGKILL",
+					ExpectedName: "sleep",
+				}
+			},
+			wantSignal: syscall.SIGKILL,
+		},
+		{
+			name: "wrong name",
+			req: func(t *testing.T, pid int64) *pb.SignalRequest {
+				return &pb.SignalRequest{Process: &pb.SignalRequest_Pid{Pid: pid}, Signal: "SIGTERM", ExpectedName: "sshd"}
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "exited process",
+			req: func(t *testing.T, pid int64) *pb.SignalRequest {
+				return &pb.SignalRequest{Process: &pb.SignalRequest_Pid{Pid: pid}, Signal: "SIGTERM"}
+			},
+			exited:   true,
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "unknown signal",
+			req: func(t *testing.T, pid int64) *pb.SignalRequest {
+				return &pb.SignalRequest{Process: &pb.SignalRequest_Pid{Pid: pid}, Signal: "SIGBOGUS"}
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "no signal",
+			req: func(t *testing.T, pid int64) *pb.SignalRequest {
+				return &pb.SignalRequest{Process: &pb.SignalRequest_Pid{Pid: pid}}
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "no process",
+			req: func(t *testing.T, pid int64) *pb.SignalRequest {
+				return &pb.SignalRequest{Signal: "SIGTERM"}
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "bad pidfile contents",
+			req: func(t *testing.T, pid int64) *pb.SignalRequest {
+				return &pb.SignalRequest{Process: &pb.SignalRequest_Pidfile{Pidfile: pidfile(t, "sleep")}, Signal: "SIGTERM"}
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "relative pidfile",
+			req: func(t *testing.T, pid int64) *pb.SignalRequest {
+				return &pb.SignalRequest{Process: &pb.SignalRequest_Pidfile{Pidfile: "run/sleep.pid"}, Signal: "SIGTERM"}
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command(sleep, "60")
+			testutil.FatalOnErr("start sleep", cmd.Start(), t)
+			pid := int64(cmd.Process.Pid)
+			if tc.exited {
+				cmd.Process.Kill()
+				cmd.Wait()
+			} else {
+				t.Cleanup(func() {
+					cmd.Process.Kill()
+					cmd.Wait()
+				})
+			}
+
+			resp, err := client.Signal(ctx, tc.req(t, pid))
+			if got, want := status.Code(err), tc.wantCode; got != want {
+				t.Fatalf("unexpected code. got %v want %v err %v", got, want, err)
+			}
+			if tc.wantCode != codes.OK {
+				return
+			}
+			if got, want := resp.Pid, pid; got != want {
+				t.Fatalf("wrong pid signalled. got %d want %d", got, want)
+			}
+			// Wait returns an error for anything but a clean exit.
+			err = cmd.Wait()
+			ws, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+			if !ok || !ws.Signaled() || ws.Signal() != tc.wantSignal {
+				t.Fatalf("sleep wasn't killed by %v: %v", tc.wantSignal, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+//go:build linux || darwin
+// +build linux darwin
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// signalError converts an error from signalling pid to a status.
+func signalError(pid int64, err error) error {
+	switch err {
+	case unix.ESRCH:
+		return status.Errorf(codes.NotFound, "no such process %d", pid)
+	case unix.EPERM:
+		return status.Errorf(codes.PermissionDenied, "can't signal process %d: %v", pid, err)
+	}
+	return status.Errorf(codes.Internal, "can't signal process %d: %v", pid, err)
+}
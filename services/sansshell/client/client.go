@@ -14,13 +14,14 @@
    under the License.
 */
 
-// Package client provides the client interface for 'Logging'
+// Package client provides the client interface for 'Logging' and 'Capabilities'
 package client
 
 import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
@@ -35,7 +36,7 @@ import (
 const subPackage = "sansshell"
 
 func init() {
-	subcommands.Register(&sansshellCmd{}, subPackage)
+	client.RegisterSubpackage(subPackage, setup)
 }
 
 func setup(f *flag.FlagSet) *subcommands.Commander {
@@ -44,25 +45,11 @@ func setup(f *flag.FlagSet) *subcommands.Commander {
 	c.Register(&getVerbosityCmd{}, "")
 	c.Register(&setProxyVerbosityCmd{}, "")
 	c.Register(&getProxyVerbosityCmd{}, "")
+	c.Register(&capabilitiesCmd{}, "")
+	c.Register(&proxyCapabilitiesCmd{}, "")
 	return c
 }
 
-type sansshellCmd struct{}
-
-func (*sansshellCmd) Name() string { return subPackage }
-func (p *sansshellCmd) Synopsis() string {
-	return client.GenerateSynopsis(setup(flag.NewFlagSet("", flag.ContinueOnError)))
-}
-func (p *sansshellCmd) Usage() string {
-	return client.GenerateUsage(subPackage, p.Synopsis())
-}
-func (*sansshellCmd) SetFlags(f *flag.FlagSet) {}
-
-func (p *sansshellCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
-	c := setup(f)
-	return c.Execute(ctx, args...)
-}
-
 type setVerbosityCmd struct {
 	level int
 }
@@ -212,3 +199,86 @@ func (g *getProxyVerbosityCmd) Execute(ctx context.Context, f *flag.FlagSet, arg
 	fmt.Fprintf(state.Out[0], "Proxy current logging level %d\n", resp.Level)
 	return subcommands.ExitSuccess
 }
+
+// printCapabilities writes the version, platform and methods in `c` to `w`.
+func printCapabilities(w io.Writer, c *pb.CapabilitiesReply) {
+	fmt.Fprintf(w, "version: %s\n", c.Version)
+	fmt.Fprintf(w, "platform: %s/%s (%s)\n", c.Os, c.Arch, c.GoVersion)
+	for _, s := range c.Services {
+		fmt.Fprintf(w, "%s\n", s.Name)
+		for _, m := range s.Methods {
+			fmt.Fprintf(w, "  %s\n", m)
+		}
+	}
+}
+
+type capabilitiesCmd struct{}
+
+func (*capabilitiesCmd) Name() string { return "capabilities" }
+func (*capabilitiesCmd) Synopsis() string {
+	return "Get the version, platform and methods of the server."
+}
+func (*capabilitiesCmd) Usage() string {
+	return `capabilities:
+  Prints the version and platform of each target and the services and methods it implements.
+`
+}
+
+func (*capabilitiesCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*capabilitiesCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewCapabilitiesClientProxy(state.Conn)
+	respChan, err := c.GetCapabilitiesOneMany(ctx, &emptypb.Empty{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "GetCapabilities for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		printCapabilities(state.Out[r.Index], r.Resp)
+	}
+	return retCode
+}
+
+type proxyCapabilitiesCmd struct{}
+
+func (*proxyCapabilitiesCmd) Name() string { return "proxy-capabilities" }
+func (*proxyCapabilitiesCmd) Synopsis() string {
+	return "Get the version, platform and methods of the proxy."
+}
+func (*proxyCapabilitiesCmd) Usage() string {
+	return `proxy-capabilities:
+  Prints the version and platform of the proxy server and the services and methods it implements.
+`
+}
+
+func (*proxyCapabilitiesCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*proxyCapabilitiesCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if state.Conn.Direct() {
+		fmt.Fprintln(os.Stderr, "proxy-capabilities requires --proxy")
+		return subcommands.ExitUsageError
+	}
+	// Get a real connection to the proxy
+	c := pb.NewCapabilitiesClient(state.Conn.Proxy())
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	resp, err := c.GetCapabilities(ctx, &emptypb.Empty{})
+	if err != nil {
+		fmt.Fprintf(state.Err[0], "Could not get proxy capabilities: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	printCapabilities(state.Out[0], resp)
+	return subcommands.ExitSuccess
+}
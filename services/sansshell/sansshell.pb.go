@@ -130,6 +130,146 @@ func (x *VerbosityReply) GetLevel() int32 {
 	return 0
 }
 
+type ServiceCapability struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The fully qualified service name, i.e. LocalFile.LocalFile
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The full method names, i.e. /LocalFile.LocalFile/Read
+	Methods []string `protobuf:"bytes,2,rep,name=methods,proto3" json:"methods,omitempty"`
+}
+
+func (x *ServiceCapability) Reset() {
+	*x = ServiceCapability{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sansshell_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceCapability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceCapability) ProtoMessage() {}
+
+func (x *ServiceCapability) ProtoReflect() protoreflect.Message {
+	mi := &file_sansshell_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceCapability.ProtoReflect.Descriptor instead.
+func (*ServiceCapability) Descriptor() ([]byte, []int) {
+	return file_sansshell_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ServiceCapability) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceCapability) GetMethods() []string {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+type CapabilitiesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The version of the server binary, if known.
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// The Go version the server was built with.
+	GoVersion string `protobuf:"bytes,2,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	// The OS and architecture the server runs on, i.e. linux and amd64.
+	Os   string `protobuf:"bytes,3,opt,name=os,proto3" json:"os,omitempty"`
+	Arch string `protobuf:"bytes,4,opt,name=arch,proto3" json:"arch,omitempty"`
+	// The registered services, sorted by name.
+	Services []*ServiceCapability `protobuf:"bytes,5,rep,name=services,proto3" json:"services,omitempty"`
+}
+
+func (x *CapabilitiesReply) Reset() {
+	*x = CapabilitiesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sansshell_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CapabilitiesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapabilitiesReply) ProtoMessage() {}
+
+func (x *CapabilitiesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sansshell_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapabilitiesReply.ProtoReflect.Descriptor instead.
+func (*CapabilitiesReply) Descriptor() ([]byte, []int) {
+	return file_sansshell_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CapabilitiesReply) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *CapabilitiesReply) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+func (x *CapabilitiesReply) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *CapabilitiesReply) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *CapabilitiesReply) GetServices() []*ServiceCapability {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
 var File_sansshell_proto protoreflect.FileDescriptor
 
 var file_sansshell_proto_rawDesc = []byte{
@@ -141,7 +281,22 @@ var file_sansshell_proto_rawDesc = []byte{
 	0x12, 0x14, 0x0a, 0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
 	0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x26, 0x0a, 0x0e, 0x56, 0x65, 0x72, 0x62, 0x6f, 0x73,
 	0x69, 0x74, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x65, 0x76, 0x65,
-	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x32, 0x9b,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x41,
+	0x0a, 0x11, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64,
+	0x73, 0x22, 0xaa, 0x01, 0x0a, 0x11, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x6f, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x6f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x63, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x61, 0x72, 0x63, 0x68, 0x12, 0x38, 0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x53, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x65,
+	0x6c, 0x6c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x32, 0x9b,
 	0x01, 0x0a, 0x07, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x12, 0x4b, 0x0a, 0x0c, 0x53, 0x65,
 	0x74, 0x56, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x12, 0x1e, 0x2e, 0x53, 0x61, 0x6e,
 	0x73, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x2e, 0x53, 0x65, 0x74, 0x56, 0x65, 0x72, 0x62, 0x6f, 0x73,
@@ -151,11 +306,16 @@ var file_sansshell_proto_rawDesc = []byte{
 	0x72, 0x62, 0x6f, 0x73, 0x69, 0x74, 0x79, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
 	0x19, 0x2e, 0x53, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x2e, 0x56, 0x65, 0x72, 0x62,
-	0x6f, 0x73, 0x69, 0x74, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x2f, 0x5a, 0x2d,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x53, 0x6e, 0x6f, 0x77, 0x66,
-	0x6c, 0x61, 0x6b, 0x65, 0x2d, 0x4c, 0x61, 0x62, 0x73, 0x2f, 0x73, 0x61, 0x6e, 0x73, 0x73, 0x68,
-	0x65, 0x6c, 0x6c, 0x2f, 0x73, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x73, 0x69, 0x74, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x32, 0x59, 0x0a, 0x0c,
+	0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x49, 0x0a, 0x0f,
+	0x47, 0x65, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1c, 0x2e, 0x53, 0x61, 0x6e, 0x73, 0x73, 0x68,
+	0x65, 0x6c, 0x6c, 0x2e, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x53, 0x6e, 0x6f, 0x77, 0x66, 0x6c, 0x61, 0x6b, 0x65, 0x2d,
+	0x4c, 0x61, 0x62, 0x73, 0x2f, 0x73, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x2f, 0x73,
+	0x61, 0x6e, 0x73, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -170,22 +330,27 @@ func file_sansshell_proto_rawDescGZIP() []byte {
 	return file_sansshell_proto_rawDescData
 }
 
-var file_sansshell_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_sansshell_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_sansshell_proto_goTypes = []interface{}{
 	(*SetVerbosityRequest)(nil), // 0: Sansshell.SetVerbosityRequest
 	(*VerbosityReply)(nil),      // 1: Sansshell.VerbosityReply
-	(*emptypb.Empty)(nil),       // 2: google.protobuf.Empty
+	(*ServiceCapability)(nil),   // 2: Sansshell.ServiceCapability
+	(*CapabilitiesReply)(nil),   // 3: Sansshell.CapabilitiesReply
+	(*emptypb.Empty)(nil),       // 4: google.protobuf.Empty
 }
 var file_sansshell_proto_depIdxs = []int32{
-	0, // 0: Sansshell.Logging.SetVerbosity:input_type -> Sansshell.SetVerbosityRequest
-	2, // 1: Sansshell.Logging.GetVerbosity:input_type -> google.protobuf.Empty
-	1, // 2: Sansshell.Logging.SetVerbosity:output_type -> Sansshell.VerbosityReply
-	1, // 3: Sansshell.Logging.GetVerbosity:output_type -> Sansshell.VerbosityReply
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: Sansshell.CapabilitiesReply.services:type_name -> Sansshell.ServiceCapability
+	0, // 1: Sansshell.Logging.SetVerbosity:input_type -> Sansshell.SetVerbosityRequest
+	4, // 2: Sansshell.Logging.GetVerbosity:input_type -> google.protobuf.Empty
+	4, // 3: Sansshell.Capabilities.GetCapabilities:input_type -> google.protobuf.Empty
+	1, // 4: Sansshell.Logging.SetVerbosity:output_type -> Sansshell.VerbosityReply
+	1, // 5: Sansshell.Logging.GetVerbosity:output_type -> Sansshell.VerbosityReply
+	3, // 6: Sansshell.Capabilities.GetCapabilities:output_type -> Sansshell.CapabilitiesReply
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_sansshell_proto_init() }
@@ -218,6 +383,30 @@ func file_sansshell_proto_init() {
 				return nil
 			}
 		}
+		file_sansshell_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServiceCapability); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sansshell_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CapabilitiesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -225,9 +414,9 @@ func file_sansshell_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_sansshell_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_sansshell_proto_goTypes,
 		DependencyIndexes: file_sansshell_proto_depIdxs,
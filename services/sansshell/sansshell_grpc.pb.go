@@ -146,3 +146,91 @@ var Logging_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "sansshell.proto",
 }
+
+// CapabilitiesClient is the client API for Capabilities service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CapabilitiesClient interface {
+	// GetCapabilities returns the server version, platform and the services
+	// and methods it has registered.
+	GetCapabilities(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CapabilitiesReply, error)
+}
+
+type capabilitiesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCapabilitiesClient(cc grpc.ClientConnInterface) CapabilitiesClient {
+	return &capabilitiesClient{cc}
+}
+
+func (c *capabilitiesClient) GetCapabilities(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CapabilitiesReply, error) {
+	out := new(CapabilitiesReply)
+	err := c.cc.Invoke(ctx, "/Sansshell.Capabilities/GetCapabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CapabilitiesServer is the server API for Capabilities service.
+// All implementations should embed UnimplementedCapabilitiesServer
+// for forward compatibility
+type CapabilitiesServer interface {
+	// GetCapabilities returns the server version, platform and the services
+	// and methods it has registered.
+	GetCapabilities(context.Context, *emptypb.Empty) (*CapabilitiesReply, error)
+}
+
+// UnimplementedCapabilitiesServer should be embedded to have forward compatible implementations.
+type UnimplementedCapabilitiesServer struct {
+}
+
+func (UnimplementedCapabilitiesServer) GetCapabilities(context.Context, *emptypb.Empty) (*CapabilitiesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+
+// UnsafeCapabilitiesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CapabilitiesServer will
+// result in compilation errors.
+type UnsafeCapabilitiesServer interface {
+	mustEmbedUnimplementedCapabilitiesServer()
+}
+
+func RegisterCapabilitiesServer(s grpc.ServiceRegistrar, srv CapabilitiesServer) {
+	s.RegisterService(&Capabilities_ServiceDesc, srv)
+}
+
+func _Capabilities_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CapabilitiesServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Sansshell.Capabilities/GetCapabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CapabilitiesServer).GetCapabilities(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Capabilities_ServiceDesc is the grpc.ServiceDesc for Capabilities service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Capabilities_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Sansshell.Capabilities",
+	HandlerType: (*CapabilitiesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _Capabilities_GetCapabilities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sansshell.proto",
+}
@@ -167,3 +167,87 @@ func (c *loggingClientProxy) GetVerbosityOneMany(ctx context.Context, in *emptyp
 
 	return ret, nil
 }
+
+// CapabilitiesClientProxy is the superset of CapabilitiesClient which additionally includes the OneMany proxy methods
+type CapabilitiesClientProxy interface {
+	CapabilitiesClient
+	GetCapabilitiesOneMany(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (<-chan *GetCapabilitiesManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type capabilitiesClientProxy struct {
+	*capabilitiesClient
+}
+
+// NewCapabilitiesClientProxy creates a CapabilitiesClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewCapabilitiesClientProxy(cc *proxy.Conn) CapabilitiesClientProxy {
+	return &capabilitiesClientProxy{NewCapabilitiesClient(cc).(*capabilitiesClient)}
+}
+
+// GetCapabilitiesManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type GetCapabilitiesManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *CapabilitiesReply
+	Error error
+}
+
+// GetCapabilitiesOneMany provides the same API as GetCapabilities but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *capabilitiesClientProxy) GetCapabilitiesOneMany(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (<-chan *GetCapabilitiesManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *GetCapabilitiesManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &GetCapabilitiesManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &CapabilitiesReply{},
+			}
+			err := conn.Invoke(ctx, "/Sansshell.Capabilities/GetCapabilities", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Sansshell.Capabilities/GetCapabilities", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &GetCapabilitiesManyResponse{
+				Resp: &CapabilitiesReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
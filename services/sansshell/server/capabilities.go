@@ -0,0 +1,100 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"runtime"
+	"runtime/debug"
+	"sort"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/sansshell"
+)
+
+// Version is reported by GetCapabilities. It's intended to be set at link
+// time, i.e.
+//
+//	go build -ldflags "-X github.com/Snowflake-Labs/sansshell/services/sansshell/server.Version=v1.2.3"
+//
+// If unset the main module version from the build info is used.
+var Version string
+
+// capabilitiesPolicy permits anyone to ask what a server supports, so
+// clients can filter fanouts by it. Servers include it by default
+// (see services.RegisterPolicyFragment).
+const capabilitiesPolicy = `
+package sansshell.authz
+
+allow {
+	input.method = "/Sansshell.Capabilities/GetCapabilities"
+}
+`
+
+// capabilities implements the Capabilities service.
+type capabilities struct {
+	// The server this is registered with, whose services are reported.
+	gs *grpc.Server
+}
+
+// version returns Version or the main module version.
+func version() string {
+	if Version != "" {
+		return Version
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Version
+	}
+	return ""
+}
+
+// GetCapabilities returns the version, platform and registered services and methods.
+func (c *capabilities) GetCapabilities(ctx context.Context, req *emptypb.Empty) (*pb.CapabilitiesReply, error) {
+	reply := &pb.CapabilitiesReply{
+		Version:   version(),
+		GoVersion: runtime.Version(),
+		Os:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if c.gs == nil {
+		return reply, nil
+	}
+	for name, info := range c.gs.GetServiceInfo() {
+		svc := &pb.ServiceCapability{Name: name}
+		for _, m := range info.Methods {
+			svc.Methods = append(svc.Methods, "/"+name+"/"+m.Name)
+		}
+		sort.Strings(svc.Methods)
+		reply.Services = append(reply.Services, svc)
+	}
+	sort.Slice(reply.Services, func(i, j int) bool { return reply.Services[i].Name < reply.Services[j].Name })
+	return reply, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (c *capabilities) Register(gs *grpc.Server) {
+	c.gs = gs
+	pb.RegisterCapabilitiesServer(gs, c)
+}
+
+func init() {
+	services.RegisterSansShellService(&capabilities{})
+	services.RegisterPolicyFragment("capabilities", capabilitiesPolicy)
+}
@@ -0,0 +1,87 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa"
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/sansshell"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestGetCapabilities(t *testing.T) {
+	saved := Version
+	t.Cleanup(func() { Version = saved })
+	Version = "v1.2.3"
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.FatalOnErr("Failed to dial bufnet", err, t)
+	t.Cleanup(func() { conn.Close() })
+
+	client := pb.NewCapabilitiesClient(conn)
+	got, err := client.GetCapabilities(ctx, &emptypb.Empty{})
+	testutil.FatalOnErr("GetCapabilities", err, t)
+	want := &pb.CapabilitiesReply{
+		Version:   "v1.2.3",
+		GoVersion: runtime.Version(),
+		Os:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Services: []*pb.ServiceCapability{
+			{
+				Name:    "Sansshell.Capabilities",
+				Methods: []string{"/Sansshell.Capabilities/GetCapabilities"},
+			},
+			{
+				Name:    "Sansshell.Logging",
+				Methods: []string{"/Sansshell.Logging/GetVerbosity", "/Sansshell.Logging/SetVerbosity"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected reply (-want +got):\n%s", diff)
+	}
+}
+
+func TestCapabilitiesPolicyFragment(t *testing.T) {
+	ctx := context.Background()
+	fragments := services.PolicyFragments()
+	if _, ok := fragments["capabilities"]; !ok {
+		t.Fatalf("capabilities fragment not registered: %v", fragments)
+	}
+	policy, err := opa.NewAuthzPolicy(ctx, `
+package sansshell.authz
+
+default allow = false
+`, opa.WithFragments(fragments))
+	testutil.FatalOnErr("NewAuthzPolicy", err, t)
+	allowed, err := policy.Eval(ctx, map[string]string{"method": "/Sansshell.Capabilities/GetCapabilities"})
+	testutil.FatalOnErr("Eval", err, t)
+	if !allowed {
+		t.Error("GetCapabilities not allowed by fragment")
+	}
+}
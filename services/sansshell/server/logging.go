@@ -14,7 +14,7 @@
    under the License.
 */
 
-// Package server implements the sansshell 'Logging' service.
+// Package server implements the sansshell 'Logging' and 'Capabilities' services.
 package server
 
 import (
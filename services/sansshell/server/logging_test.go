@@ -46,6 +46,7 @@ func TestMain(m *testing.M) {
 	s := grpc.NewServer()
 	lfs := &Server{}
 	lfs.Register(s)
+	(&capabilities{}).Register(s)
 	go func() {
 		if err := s.Serve(lis); err != nil {
 			log.Fatalf("Server exited with error: %v", err)
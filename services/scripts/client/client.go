@@ -0,0 +1,151 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'scripts'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/subcommands"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/scripts"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "scripts"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&listCmd{}, "")
+	c.Register(&runCmd{}, "")
+	return c
+}
+
+type listCmd struct{}
+
+func (*listCmd) Name() string     { return "list" }
+func (*listCmd) Synopsis() string { return "List the scripts in the remote catalog" }
+func (*listCmd) Usage() string {
+	return `list:
+    List the scripts each target's catalog allows running, with their
+    parameters.
+`
+}
+
+func (*listCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*listCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewScriptsClientProxy(state.Conn)
+	resp, err := c.ListOneMany(ctx, &pb.ListRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range resp {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "List for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, s := range r.Resp.Scripts {
+			fmt.Fprintf(state.Out[r.Index], "%s: %s (timeout %v, sha256 %s)\n", s.Name, s.Description, s.Timeout.AsDuration(), s.Sha256)
+			for _, p := range s.Parameters {
+				var attrs []string
+				if p.Required {
+					attrs = append(attrs, "required")
+				}
+				if p.DefaultValue != "" {
+					attrs = append(attrs, fmt.Sprintf("default %q", p.DefaultValue))
+				}
+				attrs = append(attrs, fmt.Sprintf("matching %q", p.Pattern))
+				fmt.Fprintf(state.Out[r.Index], "  %s: %s (%s)\n", p.Name, p.Description, strings.Join(attrs, ", "))
+			}
+		}
+	}
+	return retCode
+}
+
+type runCmd struct{}
+
+func (*runCmd) Name() string     { return "run" }
+func (*runCmd) Synopsis() string { return "Run a script from the remote catalog" }
+func (*runCmd) Usage() string {
+	return `run <script> [name=value ...]:
+    Run a script from each target's catalog with the given parameters,
+    printing its output. Exits non-zero if the script does on any target.
+`
+}
+
+func (*runCmd) SetFlags(f *flag.FlagSet) {}
+
+func (*runCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Please specify a script to run.")
+		return subcommands.ExitUsageError
+	}
+	req := &pb.RunRequest{Name: f.Arg(0), Parameters: make(map[string]string)}
+	for _, a := range f.Args()[1:] {
+		kv := strings.SplitN(a, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			fmt.Fprintf(os.Stderr, "Parameter %q must be of the form name=value\n", a)
+			return subcommands.ExitUsageError
+		}
+		req.Parameters[kv[0]] = kv[1]
+	}
+
+	c := pb.NewScriptsClientProxy(state.Conn)
+	resp, err := c.RunOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "Could not execute: %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range resp {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "Run for target %s (%d) returned error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		state.Err[r.Index].Write(r.Resp.Stderr)
+		state.Out[r.Index].Write(r.Resp.Stdout)
+		if r.Resp.ExitCode != 0 {
+			fmt.Fprintf(state.Err[r.Index], "Script for target %s (%d) exited with code %d\n", r.Target, r.Index, r.Resp.ExitCode)
+			retCode = subcommands.ExitFailure
+		}
+	}
+	return retCode
+}
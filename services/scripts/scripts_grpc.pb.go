@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package scripts
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ScriptsClient is the client API for Scripts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScriptsClient interface {
+	// List returns the scripts in the catalog.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListReply, error)
+	// Run runs a script from the catalog. Its parameters are checked against
+	// its schema and its contents against the pinned checksum first.
+	Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (*RunReply, error)
+}
+
+type scriptsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScriptsClient(cc grpc.ClientConnInterface) ScriptsClient {
+	return &scriptsClient{cc}
+}
+
+func (c *scriptsClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListReply, error) {
+	out := new(ListReply)
+	err := c.cc.Invoke(ctx, "/Scripts.Scripts/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scriptsClient) Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (*RunReply, error) {
+	out := new(RunReply)
+	err := c.cc.Invoke(ctx, "/Scripts.Scripts/Run", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScriptsServer is the server API for Scripts service.
+// All implementations should embed UnimplementedScriptsServer
+// for forward compatibility
+type ScriptsServer interface {
+	// List returns the scripts in the catalog.
+	List(context.Context, *ListRequest) (*ListReply, error)
+	// Run runs a script from the catalog. Its parameters are checked against
+	// its schema and its contents against the pinned checksum first.
+	Run(context.Context, *RunRequest) (*RunReply, error)
+}
+
+// UnimplementedScriptsServer should be embedded to have forward compatible implementations.
+type UnimplementedScriptsServer struct {
+}
+
+func (UnimplementedScriptsServer) List(context.Context, *ListRequest) (*ListReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedScriptsServer) Run(context.Context, *RunRequest) (*RunReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Run not implemented")
+}
+
+// UnsafeScriptsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScriptsServer will
+// result in compilation errors.
+type UnsafeScriptsServer interface {
+	mustEmbedUnimplementedScriptsServer()
+}
+
+func RegisterScriptsServer(s grpc.ServiceRegistrar, srv ScriptsServer) {
+	s.RegisterService(&Scripts_ServiceDesc, srv)
+}
+
+func _Scripts_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScriptsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Scripts.Scripts/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScriptsServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scripts_Run_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScriptsServer).Run(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Scripts.Scripts/Run",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScriptsServer).Run(ctx, req.(*RunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Scripts_ServiceDesc is the grpc.ServiceDesc for Scripts service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Scripts_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Scripts.Scripts",
+	HandlerType: (*ScriptsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _Scripts_List_Handler,
+		},
+		{
+			MethodName: "Run",
+			Handler:    _Scripts_Run_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "scripts.proto",
+}
@@ -0,0 +1,286 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'Scripts' service.
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/scripts"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+var (
+	catalogFile = flag.String("scripts-catalog", "", `JSON file listing the scripts the Scripts service may run, i.e. [{"name": "disk-report", "path": "/usr/local/libexec/disk-report", "sha256": "...", "timeout": "1m", "parameters": [{"name": "mount", "required": true, "pattern": "/[a-z0-9/]*"}]}]. It's re-read for every request. If empty no scripts can be run.`)
+
+	// validParamName matches allowed parameter names, which are used in
+	// environment variable names.
+	validParamName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+)
+
+const (
+	// defaultTimeout is how long scripts may run if the catalog
+	// doesn't say.
+	defaultTimeout = 5 * time.Minute
+
+	// defaultPattern is the pattern for parameters without one, which
+	// doesn't allow whitespace or shell metacharacters.
+	defaultPattern = `[A-Za-z0-9_.,:/@+=-]*`
+
+	// scriptPath is the PATH scripts are run with.
+	scriptPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+)
+
+// parameter is a parameter of a script in the catalog.
+type parameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Pattern     string `json:"pattern"`
+	Default     string `json:"default"`
+
+	re *regexp.Regexp
+}
+
+// script is a catalog entry.
+type script struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Path        string       `json:"path"`
+	SHA256      string       `json:"sha256"`
+	Timeout     string       `json:"timeout"`
+	Parameters  []*parameter `json:"parameters"`
+
+	timeout time.Duration
+}
+
+// loadCatalog reads and validates the catalog.
+func loadCatalog() (map[string]*script, error) {
+	if *catalogFile == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(*catalogFile)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read script catalog: %v", err)
+	}
+	var scripts []*script
+	if err := json.Unmarshal(b, &scripts); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't parse script catalog %s: %v", *catalogFile, err)
+	}
+	catalog := make(map[string]*script)
+	for _, s := range scripts {
+		if err := s.init(); err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid script %q in catalog %s: %v", s.Name, *catalogFile, err)
+		}
+		if catalog[s.Name] != nil {
+			return nil, status.Errorf(codes.Internal, "script %q is in catalog %s more than once", s.Name, *catalogFile)
+		}
+		catalog[s.Name] = s
+	}
+	return catalog, nil
+}
+
+// init validates s and fills in its parsed fields.
+func (s *script) init() error {
+	if s.Name == "" {
+		return errors.New("no name")
+	}
+	if err := util.ValidPath(s.Path); err != nil {
+		return err
+	}
+	if b, err := hex.DecodeString(s.SHA256); err != nil || len(b) != sha256.Size {
+		return fmt.Errorf("invalid sha256 %q", s.SHA256)
+	}
+	s.SHA256 = strings.ToLower(s.SHA256)
+	s.timeout = defaultTimeout
+	if s.Timeout != "" {
+		d, err := time.ParseDuration(s.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q", s.Timeout)
+		}
+		s.timeout = d
+	}
+	seen := make(map[string]bool)
+	for _, p := range s.Parameters {
+		if !validParamName.MatchString(p.Name) {
+			return fmt.Errorf("invalid parameter name %q", p.Name)
+		}
+		// Names map to environment variables so can't differ only by case.
+		upper := strings.ToUpper(p.Name)
+		if seen[upper] {
+			return fmt.Errorf("parameter %q is defined more than once", p.Name)
+		}
+		seen[upper] = true
+		pattern := p.Pattern
+		if pattern == "" {
+			pattern = defaultPattern
+		}
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid pattern for parameter %q: %v", p.Name, err)
+		}
+		p.re = re
+		if p.Default != "" && !re.MatchString(p.Default) {
+			return fmt.Errorf("default for parameter %q doesn't match its pattern", p.Name)
+		}
+	}
+	return nil
+}
+
+// env returns options setting the environment to run s with for the given
+// parameters, validating them against the schema.
+func (s *script) env(params map[string]string) ([]util.Option, error) {
+	known := make(map[string]bool)
+	env := []util.Option{util.EnvVar("PATH", scriptPath)}
+	for _, p := range s.Parameters {
+		known[p.Name] = true
+		v, ok := params[p.Name]
+		if !ok {
+			v = p.Default
+		}
+		if p.Required && v == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "parameter %s is required", p.Name)
+		}
+		if !p.re.MatchString(v) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid value for parameter %s: must match %s", p.Name, p.re)
+		}
+		env = append(env, util.EnvVar("PARAM_"+strings.ToUpper(p.Name), v))
+	}
+	for name := range params {
+		if !known[name] {
+			return nil, status.Errorf(codes.InvalidArgument, "script %s has no parameter %s", s.Name, name)
+		}
+	}
+	return env, nil
+}
+
+// verify checks the script's contents match the pinned checksum.
+func (s *script) verify() error {
+	b, err := os.ReadFile(s.Path)
+	if err != nil {
+		return status.Errorf(codes.FailedPrecondition, "can't read script %s: %v", s.Name, err)
+	}
+	sum := sha256.Sum256(b)
+	if got := hex.EncodeToString(sum[:]); got != s.SHA256 {
+		return status.Errorf(codes.FailedPrecondition, "script %s has sha256 %s, catalog requires %s", s.Name, got, s.SHA256)
+	}
+	return nil
+}
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// List implements pb.ScriptsServer.List
+func (s *server) List(ctx context.Context, req *pb.ListRequest) (*pb.ListReply, error) {
+	catalog, err := loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.ListReply{}
+	for _, sc := range catalog {
+		out := &pb.Script{
+			Name:        sc.Name,
+			Description: sc.Description,
+			Sha256:      sc.SHA256,
+			Timeout:     durationpb.New(sc.timeout),
+		}
+		for _, p := range sc.Parameters {
+			pattern := p.Pattern
+			if pattern == "" {
+				pattern = defaultPattern
+			}
+			out.Parameters = append(out.Parameters, &pb.Parameter{
+				Name:         p.Name,
+				Description:  p.Description,
+				Required:     p.Required,
+				Pattern:      pattern,
+				DefaultValue: p.Default,
+			})
+		}
+		reply.Scripts = append(reply.Scripts, out)
+	}
+	sort.Slice(reply.Scripts, func(i, j int) bool { return reply.Scripts[i].Name < reply.Scripts[j].Name })
+	return reply, nil
+}
+
+// Run implements pb.ScriptsServer.Run
+func (s *server) Run(ctx context.Context, req *pb.RunRequest) (*pb.RunReply, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	catalog, err := loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	sc := catalog[req.Name]
+	if sc == nil {
+		return nil, status.Errorf(codes.NotFound, "no script %q in the catalog", req.Name)
+	}
+	env, err := sc.env(req.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	// The catalog's scripts should only be writable by root, or this
+	// can race with the script being replaced.
+	if err := sc.verify(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sc.timeout)
+	defer cancel()
+	logger.Info("running script", "name", sc.Name, "parameters", req.Parameters)
+	run, err := util.RunCommand(ctx, sc.Path, nil, env...)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, status.Errorf(codes.DeadlineExceeded, "script %s didn't finish in %v", sc.Name, sc.timeout)
+	}
+	if run.Error != nil && run.ExitCode < 0 {
+		return nil, status.Errorf(codes.Internal, "can't run script %s: %v", sc.Name, run.Error)
+	}
+	return &pb.RunReply{
+		Stdout:   run.Stdout.Bytes(),
+		Stderr:   run.Stderr.Bytes(),
+		ExitCode: int32(run.ExitCode),
+	}, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterScriptsServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
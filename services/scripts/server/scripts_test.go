@@ -0,0 +1,237 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/scripts"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// writeScript writes an executable script to dir, returning its path and
+// SHA256.
+func writeScript(t *testing.T, dir string, name string, contents string) (string, string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	testutil.FatalOnErr("writing script", os.WriteFile(path, []byte(contents), 0755), t)
+	sum := sha256.Sum256([]byte(contents))
+	return path, hex.EncodeToString(sum[:])
+}
+
+// writeCatalog configures the server with a catalog of scripts.
+func writeCatalog(t *testing.T, scripts []*script) {
+	t.Helper()
+	saved := *catalogFile
+	t.Cleanup(func() { *catalogFile = saved })
+	b, err := json.Marshal(scripts)
+	testutil.FatalOnErr("marshal", err, t)
+	*catalogFile = filepath.Join(t.TempDir(), "catalog.json")
+	testutil.FatalOnErr("writing catalog", os.WriteFile(*catalogFile, b, 0644), t)
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	report, reportSum := writeScript(t, dir, "report", "#!/bin/sh\necho \"mount=$PARAM_MOUNT depth=$PARAM_DEPTH path=$PATH\"\necho warning >&2\nexit 2\n")
+	slow, slowSum := writeScript(t, dir, "slow", "#!/bin/sh\nexec sleep 10\n")
+	changed, _ := writeScript(t, dir, "changed", "#!/bin/sh\necho hi\n")
+	writeCatalog(t, []*script{
+		{
+			Name:   "report",
+			Path:   report,
+			SHA256: reportSum,
+			Parameters: []*parameter{
+				{Name: "mount", Required: true, Pattern: "/[a-z/]*"},
+				{Name: "depth", Default: "1", Pattern: "[0-9]+"},
+			},
+		},
+		{Name: "slow", Path: slow, SHA256: slowSum, Timeout: "100ms"},
+		{Name: "changed", Path: changed, SHA256: reportSum},
+	})
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.RunRequest
+		want    *pb.RunReply
+		wantErr codes.Code
+	}{
+		{
+			name: "defaults",
+			req:  &pb.RunRequest{Name: "report", Parameters: map[string]string{"mount": "/var"}},
+			want: &pb.RunReply{
+				Stdout:   []byte("mount=/var depth=1 path=" + scriptPath + "\n"),
+				Stderr:   []byte("warning\n"),
+				ExitCode: 2,
+			},
+		},
+		{
+			name: "all parameters",
+			req:  &pb.RunRequest{Name: "report", Parameters: map[string]string{"mount": "/", "depth": "3"}},
+			want: &pb.RunReply{
+				Stdout:   []byte("mount=/ depth=3 path=" + scriptPath + "\n"),
+				Stderr:   []byte("warning\n"),
+				ExitCode: 2,
+			},
+		},
+		{
+			name:    "missing required",
+			req:     &pb.RunRequest{Name: "report"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "pattern mismatch",
+			req:     &pb.RunRequest{Name: "report", Parameters: map[string]string{"mount": "/var; rm -rf /"}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "unknown parameter",
+			req:     &pb.RunRequest{Name: "report", Parameters: map[string]string{"mount": "/", "MOUNT": "/"}},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "unknown script",
+			req:     &pb.RunRequest{Name: "rm"},
+			wantErr: codes.NotFound,
+		},
+		{
+			name:    "checksum mismatch",
+			req:     &pb.RunRequest{Name: "changed"},
+			wantErr: codes.FailedPrecondition,
+		},
+		{
+			name:    "timeout",
+			req:     &pb.RunRequest{Name: "slow"},
+			wantErr: codes.DeadlineExceeded,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&server{}).Run(context.Background(), tc.req)
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	sum := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	writeCatalog(t, []*script{
+		{
+			Name:        "report",
+			Description: "Disk usage report",
+			Path:        "/usr/local/libexec/report",
+			SHA256:      sum,
+			Timeout:     "1m",
+			Parameters: []*parameter{
+				{Name: "mount", Description: "Mount point", Required: true, Pattern: "/[a-z/]*"},
+				{Name: "depth", Default: "1"},
+			},
+		},
+		{Name: "boot-log", Path: "/usr/local/libexec/boot-log", SHA256: sum},
+	})
+	got, err := (&server{}).List(context.Background(), &pb.ListRequest{})
+	testutil.FatalOnErr("List", err, t)
+	want := &pb.ListReply{
+		Scripts: []*pb.Script{
+			{Name: "boot-log", Sha256: sum, Timeout: durationpb.New(defaultTimeout)},
+			{
+				Name:        "report",
+				Description: "Disk usage report",
+				Sha256:      sum,
+				Timeout:     durationpb.New(time.Minute),
+				Parameters: []*pb.Parameter{
+					{Name: "mount", Description: "Mount point", Required: true, Pattern: "/[a-z/]*"},
+					{Name: "depth", Pattern: defaultPattern, DefaultValue: "1"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestInvalidCatalog(t *testing.T) {
+	sum := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	for _, tc := range []struct {
+		name    string
+		scripts []*script
+	}{
+		{
+			name:    "no name",
+			scripts: []*script{{Path: "/bin/true", SHA256: sum}},
+		},
+		{
+			name:    "relative path",
+			scripts: []*script{{Name: "a", Path: "true", SHA256: sum}},
+		},
+		{
+			name:    "bad sha256",
+			scripts: []*script{{Name: "a", Path: "/bin/true", SHA256: "abc"}},
+		},
+		{
+			name:    "bad timeout",
+			scripts: []*script{{Name: "a", Path: "/bin/true", SHA256: sum, Timeout: "-1s"}},
+		},
+		{
+			name:    "duplicate",
+			scripts: []*script{{Name: "a", Path: "/bin/true", SHA256: sum}, {Name: "a", Path: "/bin/false", SHA256: sum}},
+		},
+		{
+			name:    "bad parameter name",
+			scripts: []*script{{Name: "a", Path: "/bin/true", SHA256: sum, Parameters: []*parameter{{Name: "a-b"}}}},
+		},
+		{
+			name:    "parameters differing by case",
+			scripts: []*script{{Name: "a", Path: "/bin/true", SHA256: sum, Parameters: []*parameter{{Name: "x"}, {Name: "X"}}}},
+		},
+		{
+			name:    "bad pattern",
+			scripts: []*script{{Name: "a", Path: "/bin/true", SHA256: sum, Parameters: []*parameter{{Name: "x", Pattern: "("}}}},
+		},
+		{
+			name:    "default doesn't match",
+			scripts: []*script{{Name: "a", Path: "/bin/true", SHA256: sum, Parameters: []*parameter{{Name: "x", Pattern: "[0-9]+", Default: "x"}}}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			writeCatalog(t, tc.scripts)
+			_, err := (&server{}).List(context.Background(), &pb.ListRequest{})
+			if status.Code(err) != codes.Internal {
+				t.Fatalf("unexpected error: got %v, want Internal", err)
+			}
+		})
+	}
+}
@@ -35,36 +35,23 @@ import (
 const subPackage = "service"
 
 func init() {
-	subcommands.Register(&serviceCmd{}, subPackage)
+	client.RegisterSubpackage(subPackage, setup)
 }
 
 func setup(f *flag.FlagSet) *subcommands.Commander {
 	c := client.SetupSubpackage(subPackage, f)
 	initSystemTypes()
+	c.Register(&actionCmd{action: pb.Action_ACTION_DISABLE}, "")
+	c.Register(&actionCmd{action: pb.Action_ACTION_ENABLE}, "")
 	c.Register(&listCmd{}, "")
 	c.Register(&actionCmd{action: pb.Action_ACTION_RESTART}, "")
 	c.Register(&actionCmd{action: pb.Action_ACTION_START}, "")
 	c.Register(&statusCmd{}, "")
 	c.Register(&actionCmd{action: pb.Action_ACTION_STOP}, "")
+	c.Register(&unitsCmd{}, "")
 	return c
 }
 
-type serviceCmd struct{}
-
-func (*serviceCmd) Name() string { return subPackage }
-func (p *serviceCmd) Synopsis() string {
-	return client.GenerateSynopsis(setup(flag.NewFlagSet("", flag.ContinueOnError)))
-}
-func (p *serviceCmd) Usage() string {
-	return client.GenerateUsage(subPackage, p.Synopsis())
-}
-func (*serviceCmd) SetFlags(f *flag.FlagSet) {}
-
-func (p *serviceCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
-	c := setup(f)
-	return c.Execute(ctx, args...)
-}
-
 var systemTypes []string
 var systemTypeHelp string
 
@@ -86,7 +73,22 @@ func flagToSystemType(val string) (pb.SystemType, error) {
 }
 
 func systemTypeFlag(f *flag.FlagSet, p *string) {
-	f.StringVar(p, "system-type", "systemd", systemTypeHelp)
+	f.StringVar(p, "system-type", "unknown", systemTypeHelp+". unknown lets the remote side pick (systemd if running, otherwise sysv)")
+}
+
+func journalLinesFlag(f *flag.FlagSet, p *int) {
+	f.IntVar(p, "journal-lines", 0, "If set also show up to this many recent journal lines for the service")
+}
+
+// printDetails writes the detailed state of a service and any journal
+// lines indented under its status.
+func printDetails(w io.Writer, state *pb.UnitState, journal []string) {
+	if state != nil {
+		fmt.Fprintf(w, "  load: %s active: %s sub: %s unit file: %s\n", state.LoadState, state.ActiveState, state.SubState, state.UnitFileState)
+	}
+	for _, l := range journal {
+		fmt.Fprintf(w, "  %s\n", l)
+	}
 }
 
 func systemTypeString(t pb.SystemType) string {
@@ -98,8 +100,9 @@ func statusString(s pb.Status) string {
 }
 
 type actionCmd struct {
-	action     pb.Action
-	systemType string
+	action       pb.Action
+	systemType   string
+	journalLines int
 }
 
 func (a *actionCmd) actionString() string {
@@ -120,6 +123,7 @@ func (a *actionCmd) Usage() string {
 
 func (a *actionCmd) SetFlags(f *flag.FlagSet) {
 	systemTypeFlag(f, &a.systemType)
+	journalLinesFlag(f, &a.journalLines)
 }
 
 func (a *actionCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -141,9 +145,10 @@ func (a *actionCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 
 	serviceName := f.Args()[0]
 	req := &pb.ActionRequest{
-		SystemType:  system,
-		ServiceName: serviceName,
-		Action:      a.action,
+		SystemType:   system,
+		ServiceName:  serviceName,
+		Action:       a.action,
+		JournalLines: int32(a.journalLines),
 	}
 
 	c := pb.NewServiceClientProxy(state.Conn)
@@ -166,16 +171,21 @@ func (a *actionCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 	var lastErr error
 	for resp := range respChan {
 		out := state.Out[resp.Index]
-		output := fmt.Sprintf("[%s] %s %v: OK", systemTypeString(system), serviceName, as)
 		if resp.Error != nil && err != io.EOF {
 			lastErr = fmt.Errorf("target %s (%d) returned error %w", resp.Target, resp.Index, resp.Error)
 			fmt.Fprint(state.Err[resp.Index], lastErr)
 			continue
 		}
+		output := fmt.Sprintf("[%s] %s %v: OK", systemTypeString(resp.Resp.GetSystemType()), serviceName, as)
+		if st := resp.Resp.GetServiceStatus(); st != nil {
+			output += fmt.Sprintf(" (now %s)", statusString(st.GetStatus()))
+		}
 		if _, err := fmt.Fprintln(out, output); err != nil {
 			lastErr = fmt.Errorf("target %s (%d) output write error %w", resp.Target, resp.Index, err)
 			fmt.Fprint(state.Err[resp.Index], lastErr)
+			continue
 		}
+		printDetails(out, resp.Resp.GetUnitState(), resp.Resp.GetJournal())
 	}
 	if lastErr != nil {
 		return subcommands.ExitFailure
@@ -185,7 +195,8 @@ func (a *actionCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 }
 
 type statusCmd struct {
-	systemType string
+	systemType   string
+	journalLines int
 }
 
 func (*statusCmd) Name() string     { return "status" }
@@ -197,6 +208,7 @@ func (*statusCmd) Usage() string {
 }
 func (s *statusCmd) SetFlags(f *flag.FlagSet) {
 	systemTypeFlag(f, &s.systemType)
+	journalLinesFlag(f, &s.journalLines)
 }
 
 func (s *statusCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -217,8 +229,9 @@ func (s *statusCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 	}
 
 	req := &pb.StatusRequest{
-		SystemType:  system,
-		ServiceName: serviceName,
+		SystemType:   system,
+		ServiceName:  serviceName,
+		JournalLines: int32(s.journalLines),
 	}
 	c := pb.NewServiceClientProxy(state.Conn)
 
@@ -252,7 +265,9 @@ func (s *statusCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interf
 		if _, err := fmt.Fprintln(out, output); err != nil {
 			lastErr = fmt.Errorf("target %s [%d] write error: %w", resp.Target, resp.Index, err)
 			fmt.Fprint(state.Err[resp.Index], lastErr)
+			continue
 		}
+		printDetails(out, resp.Resp.GetUnitState(), resp.Resp.GetJournal())
 	}
 	if lastErr != nil {
 		return subcommands.ExitFailure
@@ -329,3 +344,78 @@ func (l *listCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interfac
 	}
 	return subcommands.ExitSuccess
 }
+
+type unitsCmd struct {
+	systemType string
+	pattern    string
+	failed     bool
+}
+
+func (*unitsCmd) Name() string     { return "units" }
+func (*unitsCmd) Synopsis() string { return "list all units with their state" }
+func (*unitsCmd) Usage() string {
+	return `units [--system-type <type>] [--failed] [--pattern <glob>]
+    list every unit (not just services) with its load, active and sub state
+    and, for failed units, why it failed
+  `
+}
+
+func (u *unitsCmd) SetFlags(f *flag.FlagSet) {
+	systemTypeFlag(f, &u.systemType)
+	f.StringVar(&u.pattern, "pattern", "", "If set only list units matching this glob (i.e. *.service)")
+	f.BoolVar(&u.failed, "failed", false, "If true only list failed units")
+}
+
+func (u *unitsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+
+	system, err := flagToSystemType(u.systemType)
+	if err != nil {
+		fmt.Fprintln(errWriter, err)
+		subcommands.DefaultCommander.ExplainCommand(errWriter, u)
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.ListUnitsRequest{
+		SystemType: system,
+		Pattern:    u.pattern,
+		FailedOnly: u.failed,
+	}
+	c := pb.NewServiceClientProxy(state.Conn)
+
+	stream, err := c.ListUnitsOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'units': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "error receiving units: %v\n", err)
+			}
+			return subcommands.ExitFailure
+		}
+		for _, r := range resp {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			unit := r.Resp.GetUnit()
+			st := unit.GetState()
+			fmt.Fprintf(state.Out[r.Index], "%-50s %-10s %-10s %-10s %-15s %s\n", unit.GetName(), st.GetLoadState(), st.GetActiveState(), st.GetSubState(), unit.GetResult(), unit.GetDescription())
+		}
+	}
+	return retCode
+}
@@ -21,16 +21,29 @@ package server
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path"
 	"sort"
 	"strings"
 
 	"github.com/coreos/go-systemd/v22/dbus"
+	"github.com/go-logr/logr"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	pb "github.com/Snowflake-Labs/sansshell/services/service"
+	"github.com/Snowflake-Labs/sansshell/services/util"
 )
 
+var (
+	journalctlBin = flag.String("journalctl-bin", "/usr/bin/journalctl", "Path to journalctl binary")
+)
+
+// maxJournalLines is the most journal lines a request can ask for.
+const maxJournalLines = 1000
+
 // Systemd deals in 'units', which might be services, devices, sockets,
 // or a variety of other types.
 // Each unit has several associated fields which collectively describe
@@ -53,6 +66,7 @@ const (
 // Only a subset of the possible values are defined here.
 const (
 	activeStateActive = "active"
+	activeStateFailed = "failed"
 )
 
 // A unit's sub-state provides more granular status of the unit
@@ -68,6 +82,19 @@ const (
 	unitSuffixService = ".service"
 )
 
+// resultTypes maps unit suffixes to the unit type interface which records
+// the Result (i.e. why the unit failed) for units of that type.
+var resultTypes = map[string]string{
+	".automount": "Automount",
+	".mount":     "Mount",
+	".path":      "Path",
+	".scope":     "Scope",
+	".service":   "Service",
+	".socket":    "Socket",
+	".swap":      "Swap",
+	".timer":     "Timer",
+}
+
 // SystemD operations on units can take several 'modes', which
 // determine how the operation should interact with other
 // in-flight operations, or an operation's effect on other
@@ -102,12 +129,27 @@ func unitStateToStatus(u dbus.UnitStatus) pb.Status {
 	}
 }
 
+// convert a dbus.UnitStatus to a UnitState. The unit file state isn't
+// part of the status so must be filled in separately.
+func unitStateFromStatus(u dbus.UnitStatus) *pb.UnitState {
+	return &pb.UnitState{
+		LoadState:   u.LoadState,
+		ActiveState: u.ActiveState,
+		SubState:    u.SubState,
+	}
+}
+
 // a subset of dbus.Conn used to mock for testing
 type systemdConnection interface {
 	ListUnitsContext(ctx context.Context) ([]dbus.UnitStatus, error)
 	StartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
 	StopUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
 	RestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	EnableUnitFilesContext(ctx context.Context, files []string, runtime bool, force bool) (bool, []dbus.EnableUnitFileChange, error)
+	DisableUnitFilesContext(ctx context.Context, files []string, runtime bool) ([]dbus.DisableUnitFileChange, error)
+	ReloadContext(ctx context.Context) error
+	GetUnitPropertyContext(ctx context.Context, unit string, propertyName string) (*dbus.Property, error)
+	GetUnitTypePropertyContext(ctx context.Context, unit string, unitType string, propertyName string) (*dbus.Property, error)
 	Close()
 }
 
@@ -115,6 +157,11 @@ type systemdConnection interface {
 type server struct {
 	// dialSystemd is the function used to create connections to systemd.
 	dialSystemd func(context.Context) (systemdConnection, error)
+	// systemdRunning reports whether systemd is the init system, which
+	// decides what SYSTEM_TYPE_UNKNOWN means. If nil it's assumed to be.
+	systemdRunning func() bool
+	// sysv implements requests for SysV init scripts.
+	sysv *sysv
 }
 
 func dialSystemd(ctx context.Context) (systemdConnection, error) {
@@ -125,8 +172,18 @@ func dialSystemd(ctx context.Context) (systemdConnection, error) {
 	return conn, nil
 }
 
+// systemdRunning is the check sd_booted(3) does.
+func systemdRunning() bool {
+	fi, err := os.Lstat("/run/systemd/system")
+	return err == nil && fi.IsDir()
+}
+
 func createServer() pb.ServiceServer {
-	return &server{dialSystemd: dialSystemd}
+	return &server{
+		dialSystemd:    dialSystemd,
+		systemdRunning: systemdRunning,
+		sysv:           newSysv(),
+	}
 }
 
 // implement sort.Interface for UnitStatus slices, so that List can return
@@ -139,18 +196,100 @@ func (s byName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 
 func checkSupportedSystem(t pb.SystemType) error {
 	switch t {
-	case pb.SystemType_SYSTEM_TYPE_UNKNOWN, pb.SystemType_SYSTEM_TYPE_SYSTEMD:
+	case pb.SystemType_SYSTEM_TYPE_UNKNOWN, pb.SystemType_SYSTEM_TYPE_SYSTEMD, pb.SystemType_SYSTEM_TYPE_SYSV:
 		return nil
 	default:
 		return status.Errorf(codes.InvalidArgument, "unsupported system type %s", t)
 	}
 }
 
+// systemType returns the system a request for `t` should be handled by.
+func (s *server) systemType(t pb.SystemType) (pb.SystemType, error) {
+	if err := checkSupportedSystem(t); err != nil {
+		return t, err
+	}
+	if t != pb.SystemType_SYSTEM_TYPE_UNKNOWN {
+		return t, nil
+	}
+	if s.systemdRunning == nil || s.systemdRunning() {
+		return pb.SystemType_SYSTEM_TYPE_SYSTEMD, nil
+	}
+	return pb.SystemType_SYSTEM_TYPE_SYSV, nil
+}
+
+func checkJournalLines(n int32) error {
+	if n < 0 || n > maxJournalLines {
+		return status.Errorf(codes.InvalidArgument, "journal lines must be between 0 and %d", maxJournalLines)
+	}
+	return nil
+}
+
+// journal returns up to n of the most recent journal lines for unit.
+func journal(ctx context.Context, unit string, n int32) ([]string, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	command := []string{*journalctlBin, "--quiet", "--no-pager", "--output=short-iso", fmt.Sprintf("--lines=%d", n), "--unit", unit}
+	run, err := util.RunCommand(ctx, command[0], command[1:])
+	if err != nil {
+		return nil, err
+	}
+	if err := run.Error; err != nil {
+		return nil, status.Errorf(codes.Internal, "error from running %q: %v\nstderr:\n%s", command, err, util.TrimString(run.Stderr.String()))
+	}
+	out := strings.TrimSuffix(run.Stdout.String(), "\n")
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// unitFileState returns whether unit is enabled, disabled, etc.
+func unitFileState(ctx context.Context, conn systemdConnection, unit string) (string, error) {
+	p, err := conn.GetUnitPropertyContext(ctx, unit, "UnitFileState")
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "can't get unit file state of %s: %v", unit, err)
+	}
+	state, ok := p.Value.Value().(string)
+	if !ok {
+		return "", status.Errorf(codes.Internal, "unexpected unit file state %v for %s", p.Value, unit)
+	}
+	return state, nil
+}
+
+// unitStatus returns the status and state of unit. Units systemd doesn't
+// have loaded are NotFound.
+func unitStatus(ctx context.Context, conn systemdConnection, unit string) (pb.Status, *pb.UnitState, error) {
+	// NB: ideally we'd use ListUnitsByNamesContext, but older versions of systemd
+	// do not support this method, so the most failsafe method that works on all systemd
+	// versions is to retrieve the full list of units, and filter here.
+	units, err := conn.ListUnitsContext(ctx)
+	if err != nil {
+		return pb.Status_STATUS_UNKNOWN, nil, status.Errorf(codes.Internal, "systemd status error %v", err)
+	}
+	for _, u := range units {
+		if u.Name != unit {
+			continue
+		}
+		state := unitStateFromStatus(u)
+		state.UnitFileState, err = unitFileState(ctx, conn, unit)
+		if err != nil {
+			return pb.Status_STATUS_UNKNOWN, nil, err
+		}
+		return unitStateToStatus(u), state, nil
+	}
+	return pb.Status_STATUS_UNKNOWN, nil, status.Errorf(codes.NotFound, "service %s was not found", unit)
+}
+
 // See: pb.ServiceServer.List
 func (s *server) List(ctx context.Context, req *pb.ListRequest) (*pb.ListReply, error) {
-	if err := checkSupportedSystem(req.SystemType); err != nil {
+	system, err := s.systemType(req.SystemType)
+	if err != nil {
 		return nil, err
 	}
+	if system == pb.SystemType_SYSTEM_TYPE_SYSV {
+		return nil, status.Error(codes.Unimplemented, "listing SysV services is not supported")
+	}
 
 	conn, err := s.dialSystemd(ctx)
 	if err != nil {
@@ -184,9 +323,66 @@ func (s *server) List(ctx context.Context, req *pb.ListRequest) (*pb.ListReply,
 	return resp, nil
 }
 
+// See: pb.ServiceServer.ListUnits
+func (s *server) ListUnits(req *pb.ListUnitsRequest, stream pb.Service_ListUnitsServer) error {
+	ctx := stream.Context()
+	system, err := s.systemType(req.SystemType)
+	if err != nil {
+		return err
+	}
+	if system == pb.SystemType_SYSTEM_TYPE_SYSV {
+		return status.Error(codes.Unimplemented, "listing SysV units is not supported")
+	}
+	if _, err := path.Match(req.Pattern, ""); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid pattern %q: %v", req.Pattern, err)
+	}
+
+	conn, err := s.dialSystemd(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "error establishing systemd connection: %v", err)
+	}
+	defer conn.Close()
+
+	// As for List older versions of systemd don't support filtering so
+	// it's done here.
+	units, err := conn.ListUnitsContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "systemd list error %v", err)
+	}
+	sort.Sort(byName(units))
+
+	for _, u := range units {
+		if req.FailedOnly && u.ActiveState != activeStateFailed {
+			continue
+		}
+		if req.Pattern != "" {
+			if ok, _ := path.Match(req.Pattern, u.Name); !ok {
+				continue
+			}
+		}
+		unit := &pb.Unit{
+			Name:        u.Name,
+			Description: u.Description,
+			State:       unitStateFromStatus(u),
+		}
+		if t, ok := resultTypes[path.Ext(u.Name)]; ok && u.ActiveState == activeStateFailed {
+			p, err := conn.GetUnitTypePropertyContext(ctx, u.Name, t, "Result")
+			if err != nil {
+				return status.Errorf(codes.Internal, "can't get result of %s: %v", u.Name, err)
+			}
+			unit.Result, _ = p.Value.Value().(string)
+		}
+		if err := stream.Send(&pb.ListUnitsReply{Unit: unit}); err != nil {
+			return status.Errorf(codes.Internal, "can't send on stream: %v", err)
+		}
+	}
+	return nil
+}
+
 // See: pb.ServiceServer.Status
 func (s *server) Status(ctx context.Context, req *pb.StatusRequest) (*pb.StatusReply, error) {
-	if err := checkSupportedSystem(req.SystemType); err != nil {
+	system, err := s.systemType(req.SystemType)
+	if err != nil {
 		return nil, err
 	}
 
@@ -194,6 +390,24 @@ func (s *server) Status(ctx context.Context, req *pb.StatusRequest) (*pb.StatusR
 	if len(unitName) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "service name is required")
 	}
+	if err := checkJournalLines(req.JournalLines); err != nil {
+		return nil, err
+	}
+
+	if system == pb.SystemType_SYSTEM_TYPE_SYSV {
+		st, state, err := s.sysv.status(ctx, unitName)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.StatusReply{
+			SystemType: system,
+			ServiceStatus: &pb.ServiceStatus{
+				ServiceName: req.GetServiceName(),
+				Status:      st,
+			},
+			UnitState: state,
+		}, nil
+	}
 
 	// Accept either 'foo' or 'foo.service'
 	if !strings.HasSuffix(unitName, unitSuffixService) {
@@ -206,30 +420,32 @@ func (s *server) Status(ctx context.Context, req *pb.StatusRequest) (*pb.StatusR
 	}
 	defer conn.Close()
 
-	// NB: ideally we'd use ListUnitsByNamesContext, but older versions of systemd
-	// do not support this method, so the most failsafe method that works on all systemd
-	// versions is to retrieve the full list of units, and filter here.
-	units, err := conn.ListUnitsContext(ctx)
+	st, state, err := unitStatus(ctx, conn, unitName)
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Errorf(codes.NotFound, "service %s was not found", req.GetServiceName())
+	}
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "systemd status error %v", err)
+		return nil, err
 	}
-	for _, u := range units {
-		if u.Name == unitName {
-			return &pb.StatusReply{
-				SystemType: pb.SystemType_SYSTEM_TYPE_SYSTEMD,
-				ServiceStatus: &pb.ServiceStatus{
-					ServiceName: req.GetServiceName(),
-					Status:      unitStateToStatus(u),
-				},
-			}, nil
-		}
+	lines, err := journal(ctx, unitName, req.JournalLines)
+	if err != nil {
+		return nil, err
 	}
-	return nil, status.Errorf(codes.NotFound, "service %s was not found", req.GetServiceName())
+	return &pb.StatusReply{
+		SystemType: pb.SystemType_SYSTEM_TYPE_SYSTEMD,
+		ServiceStatus: &pb.ServiceStatus{
+			ServiceName: req.GetServiceName(),
+			Status:      st,
+		},
+		UnitState: state,
+		Journal:   lines,
+	}, nil
 }
 
 // See: pb.ServiceServer.Action
 func (s *server) Action(ctx context.Context, req *pb.ActionRequest) (*pb.ActionReply, error) {
-	if err := checkSupportedSystem(req.SystemType); err != nil {
+	system, err := s.systemType(req.SystemType)
+	if err != nil {
 		return nil, err
 	}
 
@@ -237,6 +453,30 @@ func (s *server) Action(ctx context.Context, req *pb.ActionRequest) (*pb.ActionR
 	if len(unitName) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "service name is required")
 	}
+	if err := checkJournalLines(req.JournalLines); err != nil {
+		return nil, err
+	}
+	logr.FromContextOrDiscard(ctx).Info("service action", "service", unitName, "action", req.Action, "system", system)
+
+	if system == pb.SystemType_SYSTEM_TYPE_SYSV {
+		if err := s.sysv.action(ctx, unitName, req.Action); err != nil {
+			return nil, err
+		}
+		st, state, err := s.sysv.status(ctx, unitName)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.ActionReply{
+			SystemType:  system,
+			ServiceName: req.GetServiceName(),
+			ServiceStatus: &pb.ServiceStatus{
+				ServiceName: req.GetServiceName(),
+				Status:      st,
+			},
+			UnitState: state,
+		}, nil
+	}
+
 	// Accept either 'foo' or 'foo.service'
 	if !strings.HasSuffix(unitName, unitSuffixService) {
 		unitName = unitName + unitSuffixService
@@ -256,6 +496,19 @@ func (s *server) Action(ctx context.Context, req *pb.ActionRequest) (*pb.ActionR
 		_, err = conn.RestartUnitContext(ctx, unitName, modeReplace, resultChan)
 	case pb.Action_ACTION_STOP:
 		_, err = conn.StopUnitContext(ctx, unitName, modeReplace, resultChan)
+	case pb.Action_ACTION_ENABLE:
+		// Like systemctl enable, which reloads so the change takes effect.
+		_, _, err = conn.EnableUnitFilesContext(ctx, []string{unitName}, false, false)
+		if err == nil {
+			err = conn.ReloadContext(ctx)
+		}
+		resultChan = nil
+	case pb.Action_ACTION_DISABLE:
+		_, err = conn.DisableUnitFilesContext(ctx, []string{unitName}, false)
+		if err == nil {
+			err = conn.ReloadContext(ctx)
+		}
+		resultChan = nil
 	default:
 		return nil, status.Errorf(codes.InvalidArgument, "invalid action type %v", req.Action)
 	}
@@ -263,15 +516,36 @@ func (s *server) Action(ctx context.Context, req *pb.ActionRequest) (*pb.ActionR
 		return nil, status.Errorf(codes.Internal, "error performing action %v: %v", req.Action, err)
 	}
 
-	// NB: delivery of a value on resultchan respects context cancellation, and will
-	// deliver a value of 'cancelled' if the ctx is cancelled by a client disconnect,
-	// so it's safe to do a simple recv.
-	result := <-resultChan
-	if result != operationResultDone {
-		return nil, status.Errorf(codes.Internal, "error performing action %v: %v", req.Action, result)
+	// Enabling and disabling are synchronous so there's no job to wait for.
+	if resultChan != nil {
+		// NB: delivery of a value on resultchan respects context cancellation, and will
+		// deliver a value of 'cancelled' if the ctx is cancelled by a client disconnect,
+		// so it's safe to do a simple recv.
+		result := <-resultChan
+		if result != operationResultDone {
+			return nil, status.Errorf(codes.Internal, "error performing action %v: %v", req.Action, result)
+		}
 	}
-	return &pb.ActionReply{
+
+	reply := &pb.ActionReply{
 		SystemType:  pb.SystemType_SYSTEM_TYPE_SYSTEMD,
 		ServiceName: req.GetServiceName(),
-	}, nil
+	}
+	st, state, err := unitStatus(ctx, conn, unitName)
+	switch {
+	case status.Code(err) == codes.NotFound:
+		// Unloaded, which is expected for some stopped services.
+	case err != nil:
+		return nil, err
+	default:
+		reply.ServiceStatus = &pb.ServiceStatus{
+			ServiceName: req.GetServiceName(),
+			Status:      st,
+		}
+		reply.UnitState = state
+	}
+	if reply.Journal, err = journal(ctx, unitName, req.JournalLines); err != nil {
+		return nil, err
+	}
+	return reply, nil
 }
@@ -22,10 +22,14 @@ package server
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/coreos/go-systemd/v22/dbus"
+	godbus "github.com/godbus/dbus/v5"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -48,6 +52,21 @@ func (e errConn) StopUnitContext(context.Context, string, string, chan<- string)
 func (e errConn) RestartUnitContext(context.Context, string, string, chan<- string) (int, error) {
 	return 0, errors.New(string(e))
 }
+func (e errConn) EnableUnitFilesContext(context.Context, []string, bool, bool) (bool, []dbus.EnableUnitFileChange, error) {
+	return false, nil, errors.New(string(e))
+}
+func (e errConn) DisableUnitFilesContext(context.Context, []string, bool) ([]dbus.DisableUnitFileChange, error) {
+	return nil, errors.New(string(e))
+}
+func (e errConn) ReloadContext(context.Context) error {
+	return errors.New(string(e))
+}
+func (e errConn) GetUnitPropertyContext(context.Context, string, string) (*dbus.Property, error) {
+	return nil, errors.New(string(e))
+}
+func (e errConn) GetUnitTypePropertyContext(context.Context, string, string, string) (*dbus.Property, error) {
+	return nil, errors.New(string(e))
+}
 func (errConn) Close() {}
 
 func TestDialError(t *testing.T) {
@@ -97,6 +116,23 @@ func (l listConn) StopUnitContext(context.Context, string, string, chan<- string
 func (l listConn) RestartUnitContext(context.Context, string, string, chan<- string) (int, error) {
 	return 0, notImplementedError
 }
+func (l listConn) EnableUnitFilesContext(context.Context, []string, bool, bool) (bool, []dbus.EnableUnitFileChange, error) {
+	return false, nil, notImplementedError
+}
+func (l listConn) DisableUnitFilesContext(context.Context, []string, bool) ([]dbus.DisableUnitFileChange, error) {
+	return nil, notImplementedError
+}
+func (l listConn) ReloadContext(context.Context) error {
+	return notImplementedError
+}
+
+// All units are enabled.
+func (l listConn) GetUnitPropertyContext(_ context.Context, unit string, name string) (*dbus.Property, error) {
+	return &dbus.Property{Name: name, Value: godbus.MakeVariant("enabled")}, nil
+}
+func (l listConn) GetUnitTypePropertyContext(context.Context, string, string, string) (*dbus.Property, error) {
+	return nil, notImplementedError
+}
 func (listConn) Close() {}
 
 func wantStatusErr(code codes.Code, message string) func(string, error, *testing.T) {
@@ -262,6 +298,12 @@ func TestStatus(t *testing.T) {
 					ServiceName: "foo.service",
 					Status:      pb.Status_STATUS_RUNNING,
 				},
+				UnitState: &pb.UnitState{
+					LoadState:     loadStateLoaded,
+					ActiveState:   activeStateActive,
+					SubState:      substateRunning,
+					UnitFileState: "enabled",
+				},
 			},
 			errFunc: testutil.FatalOnErr,
 		},
@@ -290,6 +332,12 @@ func TestStatus(t *testing.T) {
 					ServiceName: "foo",
 					Status:      pb.Status_STATUS_RUNNING,
 				},
+				UnitState: &pb.UnitState{
+					LoadState:     loadStateLoaded,
+					ActiveState:   activeStateActive,
+					SubState:      substateRunning,
+					UnitFileState: "enabled",
+				},
 			},
 			errFunc: testutil.FatalOnErr,
 		},
@@ -318,6 +366,12 @@ func TestStatus(t *testing.T) {
 					ServiceName: "foo",
 					Status:      pb.Status_STATUS_STOPPED,
 				},
+				UnitState: &pb.UnitState{
+					LoadState:     loadStateLoaded,
+					ActiveState:   activeStateActive,
+					SubState:      "dead",
+					UnitFileState: "enabled",
+				},
 			},
 			errFunc: testutil.FatalOnErr,
 		},
@@ -362,6 +416,27 @@ func (a actionConn) RestartUnitContext(ctx context.Context, name string, mode st
 	}()
 	return 1, nil
 }
+func (a actionConn) EnableUnitFilesContext(context.Context, []string, bool, bool) (bool, []dbus.EnableUnitFileChange, error) {
+	if a != operationResultDone {
+		return false, nil, errors.New(string(a))
+	}
+	return false, nil, nil
+}
+func (a actionConn) DisableUnitFilesContext(context.Context, []string, bool) ([]dbus.DisableUnitFileChange, error) {
+	if a != operationResultDone {
+		return nil, errors.New(string(a))
+	}
+	return nil, nil
+}
+func (a actionConn) ReloadContext(context.Context) error {
+	return nil
+}
+func (a actionConn) GetUnitPropertyContext(context.Context, string, string) (*dbus.Property, error) {
+	return nil, notImplementedError
+}
+func (a actionConn) GetUnitTypePropertyContext(context.Context, string, string, string) (*dbus.Property, error) {
+	return nil, notImplementedError
+}
 func (actionConn) Close() {}
 
 func TestAction(t *testing.T) {
@@ -414,7 +489,7 @@ func TestAction(t *testing.T) {
 			conn: errConn("not returned"),
 			req: &pb.ActionRequest{
 				ServiceName: "foo",
-				Action:      pb.Action(5),
+				Action:      pb.Action_ACTION_DISABLE + 1,
 			},
 			want:    nil,
 			errFunc: wantStatusErr(codes.InvalidArgument, "action"),
@@ -488,6 +563,63 @@ func TestAction(t *testing.T) {
 			},
 			errFunc: testutil.FatalOnErr,
 		},
+		{
+			name: "enable failed",
+			conn: actionConn("failed"),
+			req: &pb.ActionRequest{
+				ServiceName: "foo",
+				Action:      pb.Action_ACTION_ENABLE,
+			},
+			want:    nil,
+			errFunc: wantStatusErr(codes.Internal, "error performing action"),
+		},
+		{
+			name: "disable failed",
+			conn: actionConn("failed"),
+			req: &pb.ActionRequest{
+				ServiceName: "foo",
+				Action:      pb.Action_ACTION_DISABLE,
+			},
+			want:    nil,
+			errFunc: wantStatusErr(codes.Internal, "error performing action"),
+		},
+		{
+			name: "enable success",
+			conn: actionConn(operationResultDone),
+			req: &pb.ActionRequest{
+				ServiceName: "foo",
+				Action:      pb.Action_ACTION_ENABLE,
+			},
+			want: &pb.ActionReply{
+				SystemType:  pb.SystemType_SYSTEM_TYPE_SYSTEMD,
+				ServiceName: "foo",
+			},
+			errFunc: testutil.FatalOnErr,
+		},
+		{
+			name: "disable success",
+			conn: actionConn(operationResultDone),
+			req: &pb.ActionRequest{
+				ServiceName: "foo",
+				Action:      pb.Action_ACTION_DISABLE,
+			},
+			want: &pb.ActionReply{
+				SystemType:  pb.SystemType_SYSTEM_TYPE_SYSTEMD,
+				ServiceName: "foo",
+			},
+			errFunc: testutil.FatalOnErr,
+		},
+		{
+			name: "too many journal lines",
+			conn: actionConn(operationResultDone),
+			req: &pb.ActionRequest{
+				ServiceName:  "foo",
+				Action:       pb.Action_ACTION_START,
+				JournalLines: maxJournalLines + 1,
+			},
+			want:    nil,
+			errFunc: wantStatusErr(codes.InvalidArgument, "journal lines"),
+		},
 	} {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
@@ -503,3 +635,291 @@ func TestAction(t *testing.T) {
 		})
 	}
 }
+
+func TestJournal(t *testing.T) {
+	// A journalctl which prints its arguments.
+	journalctl := filepath.Join(t.TempDir(), "journalctl")
+	err := os.WriteFile(journalctl, []byte("#!/bin/sh\necho \"$@\"\necho last line\n"), 0755)
+	testutil.FatalOnErr("writing journalctl", err, t)
+	saved := *journalctlBin
+	*journalctlBin = journalctl
+	t.Cleanup(func() { *journalctlBin = saved })
+
+	s := &server{
+		dialSystemd: func(context.Context) (systemdConnection, error) {
+			return listConn([]dbus.UnitStatus{
+				{
+					Name:        "foo.service",
+					LoadState:   loadStateLoaded,
+					ActiveState: activeStateActive,
+					SubState:    substateRunning,
+				},
+			}), nil
+		},
+	}
+	for _, tc := range []struct {
+		name    string
+		lines   int32
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "no lines",
+		},
+		{
+			name:  "lines",
+			lines: 5,
+			want:  []string{"--quiet --no-pager --output=short-iso --lines=5 --unit foo.service", "last line"},
+		},
+		{
+			name:    "negative lines",
+			lines:   -1,
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := s.Status(context.Background(), &pb.StatusRequest{ServiceName: "foo", JournalLines: tc.lines})
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if tc.wantErr {
+				return
+			}
+			testutil.DiffErr(tc.name, got.Journal, tc.want, t)
+		})
+	}
+}
+
+func TestSystemType(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		running func() bool
+		req     pb.SystemType
+		want    pb.SystemType
+		wantErr bool
+	}{
+		{
+			name: "unknown defaults to systemd",
+			req:  pb.SystemType_SYSTEM_TYPE_UNKNOWN,
+			want: pb.SystemType_SYSTEM_TYPE_SYSTEMD,
+		},
+		{
+			name:    "unknown with systemd running",
+			running: func() bool { return true },
+			req:     pb.SystemType_SYSTEM_TYPE_UNKNOWN,
+			want:    pb.SystemType_SYSTEM_TYPE_SYSTEMD,
+		},
+		{
+			name:    "unknown without systemd",
+			running: func() bool { return false },
+			req:     pb.SystemType_SYSTEM_TYPE_UNKNOWN,
+			want:    pb.SystemType_SYSTEM_TYPE_SYSV,
+		},
+		{
+			name:    "explicit systemd",
+			running: func() bool { return false },
+			req:     pb.SystemType_SYSTEM_TYPE_SYSTEMD,
+			want:    pb.SystemType_SYSTEM_TYPE_SYSTEMD,
+		},
+		{
+			name:    "explicit sysv",
+			running: func() bool { return true },
+			req:     pb.SystemType_SYSTEM_TYPE_SYSV,
+			want:    pb.SystemType_SYSTEM_TYPE_SYSV,
+		},
+		{
+			name:    "bad system",
+			req:     pb.SystemType_SYSTEM_TYPE_SYSV + 1,
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{systemdRunning: tc.running}
+			got, err := s.systemType(tc.req)
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if !tc.wantErr && got != tc.want {
+				t.Fatalf("got system type %v want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// resultConn is a listConn which also has results for failed units.
+type resultConn struct {
+	listConn
+	results map[string]string
+}
+
+func (r resultConn) GetUnitTypePropertyContext(_ context.Context, unit string, unitType string, name string) (*dbus.Property, error) {
+	res, ok := r.results[unit]
+	if !ok || name != "Result" {
+		return nil, errors.New("no result")
+	}
+	return &dbus.Property{Name: name, Value: godbus.MakeVariant(res)}, nil
+}
+
+// listUnitsStream collects the units sent on a ListUnits stream.
+type listUnitsStream struct {
+	grpc.ServerStream
+	units []*pb.Unit
+}
+
+func (l *listUnitsStream) Context() context.Context { return context.Background() }
+
+func (l *listUnitsStream) Send(r *pb.ListUnitsReply) error {
+	l.units = append(l.units, r.Unit)
+	return nil
+}
+
+func TestListUnits(t *testing.T) {
+	units := resultConn{
+		listConn: listConn([]dbus.UnitStatus{
+			{
+				Name:        "foo.service",
+				Description: "Foo",
+				LoadState:   loadStateLoaded,
+				ActiveState: activeStateActive,
+				SubState:    substateRunning,
+			},
+			{
+				Name:        "bar.service",
+				Description: "Bar",
+				LoadState:   loadStateLoaded,
+				ActiveState: activeStateFailed,
+				SubState:    "failed",
+			},
+			{
+				Name:        "baz.socket",
+				Description: "Baz",
+				LoadState:   loadStateLoaded,
+				ActiveState: activeStateFailed,
+				SubState:    "failed",
+			},
+			{
+				Name:        "dev-sda.device",
+				LoadState:   loadStateLoaded,
+				ActiveState: activeStateFailed,
+				SubState:    "dead",
+			},
+		}),
+		results: map[string]string{
+			"bar.service": "exit-code",
+			"baz.socket":  "resources",
+		},
+	}
+	foo := &pb.Unit{
+		Name:        "foo.service",
+		Description: "Foo",
+		State:       &pb.UnitState{LoadState: loadStateLoaded, ActiveState: activeStateActive, SubState: substateRunning},
+	}
+	bar := &pb.Unit{
+		Name:        "bar.service",
+		Description: "Bar",
+		State:       &pb.UnitState{LoadState: loadStateLoaded, ActiveState: activeStateFailed, SubState: "failed"},
+		Result:      "exit-code",
+	}
+	baz := &pb.Unit{
+		Name:        "baz.socket",
+		Description: "Baz",
+		State:       &pb.UnitState{LoadState: loadStateLoaded, ActiveState: activeStateFailed, SubState: "failed"},
+		Result:      "resources",
+	}
+	// Devices don't record a result.
+	dev := &pb.Unit{
+		Name:  "dev-sda.device",
+		State: &pb.UnitState{LoadState: loadStateLoaded, ActiveState: activeStateFailed, SubState: "dead"},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		conn    systemdConnection
+		running func() bool
+		req     *pb.ListUnitsRequest
+		want    []*pb.Unit
+		errFunc func(string, error, *testing.T)
+	}{
+		{
+			name:    "all",
+			conn:    units,
+			req:     &pb.ListUnitsRequest{},
+			want:    []*pb.Unit{bar, baz, dev, foo},
+			errFunc: testutil.FatalOnErr,
+		},
+		{
+			name:    "failed",
+			conn:    units,
+			req:     &pb.ListUnitsRequest{FailedOnly: true},
+			want:    []*pb.Unit{bar, baz, dev},
+			errFunc: testutil.FatalOnErr,
+		},
+		{
+			name:    "pattern",
+			conn:    units,
+			req:     &pb.ListUnitsRequest{Pattern: "*.service"},
+			want:    []*pb.Unit{bar, foo},
+			errFunc: testutil.FatalOnErr,
+		},
+		{
+			name:    "failed and pattern",
+			conn:    units,
+			req:     &pb.ListUnitsRequest{Pattern: "ba?.*", FailedOnly: true},
+			want:    []*pb.Unit{bar, baz},
+			errFunc: testutil.FatalOnErr,
+		},
+		{
+			name:    "no matches",
+			conn:    units,
+			req:     &pb.ListUnitsRequest{Pattern: "qux*"},
+			errFunc: testutil.FatalOnErr,
+		},
+		{
+			name:    "bad pattern",
+			conn:    units,
+			req:     &pb.ListUnitsRequest{Pattern: "[foo"},
+			errFunc: wantStatusErr(codes.InvalidArgument, "pattern"),
+		},
+		{
+			name:    "list error",
+			conn:    errConn("sentinel"),
+			req:     &pb.ListUnitsRequest{},
+			errFunc: wantStatusErr(codes.Internal, "sentinel"),
+		},
+		{
+			name: "result error",
+			conn: listConn([]dbus.UnitStatus{
+				{
+					Name:        "bar.service",
+					LoadState:   loadStateLoaded,
+					ActiveState: activeStateFailed,
+				},
+			}),
+			req:     &pb.ListUnitsRequest{},
+			errFunc: wantStatusErr(codes.Internal, "result"),
+		},
+		{
+			name:    "sysv",
+			conn:    units,
+			running: func() bool { return false },
+			req:     &pb.ListUnitsRequest{},
+			errFunc: wantStatusErr(codes.Unimplemented, "SysV"),
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s := &server{
+				dialSystemd: func(context.Context) (systemdConnection, error) {
+					return tc.conn, nil
+				},
+				systemdRunning: tc.running,
+			}
+			stream := &listUnitsStream{}
+			err := s.ListUnits(tc.req, stream)
+			tc.errFunc("ListUnits", err, t)
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, stream.units, tc.want, t)
+		})
+	}
+}
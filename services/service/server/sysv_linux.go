@@ -0,0 +1,178 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/service"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+var (
+	initDir      = flag.String("init-dir", "/etc/init.d", "Directory containing SysV init scripts")
+	chkconfigBin = flag.String("chkconfig-bin", "/sbin/chkconfig", "Path to chkconfig binary, used to enable and disable SysV services")
+)
+
+// LSB exit codes for the status action of init scripts.
+const (
+	lsbStatusRunning      = 0
+	lsbStatusDeadPidFile  = 1
+	lsbStatusDeadLockFile = 2
+	lsbStatusNotRunning   = 3
+	lsbStatusUnknown      = 4
+)
+
+// The UnitState values reported for SysV services, named as systemd would.
+const (
+	sysvSubStateRunning   = "running"
+	sysvSubStateDead      = "dead"
+	sysvSubStateFailed    = "failed"
+	sysvUnitStateEnabled  = "enabled"
+	sysvUnitStateDisabled = "disabled"
+)
+
+// sysvPath is the PATH init scripts run with.
+const sysvPath = "/usr/sbin:/usr/bin:/sbin:/bin"
+
+// sysv manages services with SysV init scripts, as service(8) does.
+type sysv struct {
+	// initDir and chkconfig are pointers so flag values can be used once
+	// parsed.
+	initDir   *string
+	chkconfig *string
+}
+
+func newSysv() *sysv {
+	return &sysv{initDir: initDir, chkconfig: chkconfigBin}
+}
+
+// script returns the init script for service, which must exist.
+func (s *sysv) script(service string) (string, error) {
+	if strings.ContainsRune(service, '/') || strings.HasPrefix(service, ".") {
+		return "", status.Errorf(codes.InvalidArgument, "invalid service name %q", service)
+	}
+	script := filepath.Join(*s.initDir, service)
+	fi, err := os.Stat(script)
+	if os.IsNotExist(err) {
+		return "", status.Errorf(codes.NotFound, "service %s was not found", service)
+	}
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "can't stat %s: %v", script, err)
+	}
+	if !fi.Mode().IsRegular() || fi.Mode()&0111 == 0 {
+		return "", status.Errorf(codes.FailedPrecondition, "%s is not an executable file", script)
+	}
+	return script, nil
+}
+
+// run runs the init script for service with the given action. Like
+// service(8) scripts get a minimal environment.
+func (s *sysv) run(ctx context.Context, service string, action string) (*util.CommandRun, error) {
+	script, err := s.script(service)
+	if err != nil {
+		return nil, err
+	}
+	return util.RunCommand(ctx, script, []string{action}, util.EnvVar("PATH", sysvPath))
+}
+
+// status returns the status of service from its init script's status action
+// and chkconfig.
+func (s *sysv) status(ctx context.Context, service string) (pb.Status, *pb.UnitState, error) {
+	run, err := s.run(ctx, service, "status")
+	if err != nil {
+		return pb.Status_STATUS_UNKNOWN, nil, err
+	}
+	st, state := pb.Status_STATUS_UNKNOWN, &pb.UnitState{}
+	switch run.ExitCode {
+	case lsbStatusRunning:
+		st, state.SubState = pb.Status_STATUS_RUNNING, sysvSubStateRunning
+	case lsbStatusNotRunning:
+		st, state.SubState = pb.Status_STATUS_STOPPED, sysvSubStateDead
+	case lsbStatusDeadPidFile, lsbStatusDeadLockFile:
+		st, state.SubState = pb.Status_STATUS_STOPPED, sysvSubStateFailed
+	case lsbStatusUnknown:
+	default:
+		if run.ExitCode < 0 {
+			return pb.Status_STATUS_UNKNOWN, nil, status.Errorf(codes.Internal, "error running %s status: %v", service, run.Error)
+		}
+	}
+
+	// chkconfig <service> succeeds if it's enabled in the current runlevel.
+	command := []string{*s.chkconfig, service}
+	run, err = util.RunCommand(ctx, command[0], command[1:], util.EnvVar("PATH", sysvPath))
+	if err != nil {
+		return pb.Status_STATUS_UNKNOWN, nil, err
+	}
+	switch run.ExitCode {
+	case 0:
+		state.UnitFileState = sysvUnitStateEnabled
+	case 1:
+		state.UnitFileState = sysvUnitStateDisabled
+	default:
+		return pb.Status_STATUS_UNKNOWN, nil, status.Errorf(codes.Internal, "error from running %q: %v\nstderr:\n%s", command, run.Error, util.TrimString(run.Stderr.String()))
+	}
+	return st, state, nil
+}
+
+// action performs action on service.
+func (s *sysv) action(ctx context.Context, service string, action pb.Action) error {
+	var command []string
+	switch action {
+	case pb.Action_ACTION_START:
+		command = []string{"start"}
+	case pb.Action_ACTION_STOP:
+		command = []string{"stop"}
+	case pb.Action_ACTION_RESTART:
+		command = []string{"restart"}
+	case pb.Action_ACTION_ENABLE:
+		command = []string{*s.chkconfig, service, "on"}
+	case pb.Action_ACTION_DISABLE:
+		command = []string{*s.chkconfig, service, "off"}
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid action type %v", action)
+	}
+
+	var run *util.CommandRun
+	var err error
+	if len(command) == 1 {
+		run, err = s.run(ctx, service, command[0])
+	} else {
+		// Make sure there's a script so chkconfig gets a valid service.
+		if _, err := s.script(service); err != nil {
+			return err
+		}
+		run, err = util.RunCommand(ctx, command[0], command[1:], util.EnvVar("PATH", sysvPath))
+	}
+	if err != nil {
+		return err
+	}
+	if err := run.Error; err != nil {
+		return status.Errorf(codes.Internal, "error performing action %v: %v\nstdout:\n%s\nstderr:\n%s", action, err, util.TrimString(run.Stdout.String()), util.TrimString(run.Stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/service"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// An init script keeping its state in a file next to the init directory.
+const initScript = `#!/bin/sh
+state=$(dirname $0)/../$(basename $0).running
+case "$1" in
+start|restart) touch $state ;;
+stop) rm -f $state ;;
+status) [ -f $state ] && exit 0; exit 3 ;;
+*) exit 2 ;;
+esac
+`
+
+// A chkconfig keeping whether services are enabled in files.
+const chkconfigScript = `#!/bin/sh
+enabled=$(dirname $0)/$1.enabled
+case "$2" in
+on) touch $enabled ;;
+off) rm -f $enabled ;;
+"") [ -f $enabled ] ;;
+*) exit 2 ;;
+esac
+`
+
+func TestSysV(t *testing.T) {
+	dir := t.TempDir()
+	initDir := filepath.Join(dir, "init.d")
+	chkconfig := filepath.Join(dir, "chkconfig")
+	testutil.FatalOnErr("mkdir", os.Mkdir(initDir, 0755), t)
+	for _, f := range []struct {
+		name     string
+		contents string
+		mode     os.FileMode
+	}{
+		{filepath.Join(initDir, "foo"), initScript, 0755},
+		{filepath.Join(initDir, "broken"), "#!/bin/sh\n[ \"$1\" = status ] && exit 4\necho broken >&2\nexit 1\n", 0755},
+		{filepath.Join(initDir, "functions"), "", 0644},
+		{chkconfig, chkconfigScript, 0755},
+	} {
+		testutil.FatalOnErr(f.name, os.WriteFile(f.name, []byte(f.contents), f.mode), t)
+	}
+
+	s := &server{
+		systemdRunning: func() bool { return false },
+		sysv:           &sysv{initDir: &initDir, chkconfig: &chkconfig},
+	}
+	ctx := context.Background()
+	state := func(st pb.Status, sub string, enabled string) (*pb.ServiceStatus, *pb.UnitState) {
+		return &pb.ServiceStatus{ServiceName: "foo", Status: st}, &pb.UnitState{SubState: sub, UnitFileState: enabled}
+	}
+
+	// Actions are applied in order.
+	for _, tc := range []struct {
+		name       string
+		action     pb.Action
+		wantStatus pb.Status
+		wantSub    string
+		wantFile   string
+	}{
+		{"initial", pb.Action_ACTION_UNKNOWN, pb.Status_STATUS_STOPPED, "dead", "disabled"},
+		{"start", pb.Action_ACTION_START, pb.Status_STATUS_RUNNING, "running", "disabled"},
+		{"enable", pb.Action_ACTION_ENABLE, pb.Status_STATUS_RUNNING, "running", "enabled"},
+		{"stop", pb.Action_ACTION_STOP, pb.Status_STATUS_STOPPED, "dead", "enabled"},
+		{"restart", pb.Action_ACTION_RESTART, pb.Status_STATUS_RUNNING, "running", "enabled"},
+		{"disable", pb.Action_ACTION_DISABLE, pb.Status_STATUS_RUNNING, "running", "disabled"},
+	} {
+		wantStatus, wantState := state(tc.wantStatus, tc.wantSub, tc.wantFile)
+		if tc.action != pb.Action_ACTION_UNKNOWN {
+			got, err := s.Action(ctx, &pb.ActionRequest{ServiceName: "foo", Action: tc.action})
+			testutil.FatalOnErr(tc.name, err, t)
+			testutil.DiffErr(tc.name, got, &pb.ActionReply{
+				SystemType:    pb.SystemType_SYSTEM_TYPE_SYSV,
+				ServiceName:   "foo",
+				ServiceStatus: wantStatus,
+				UnitState:     wantState,
+			}, t)
+		}
+		got, err := s.Status(ctx, &pb.StatusRequest{ServiceName: "foo"})
+		testutil.FatalOnErr(tc.name, err, t)
+		testutil.DiffErr(tc.name, got, &pb.StatusReply{
+			SystemType:    pb.SystemType_SYSTEM_TYPE_SYSV,
+			ServiceStatus: wantStatus,
+			UnitState:     wantState,
+		}, t)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		service string
+		action  pb.Action
+		want    codes.Code
+	}{
+		{"missing", "bar", pb.Action_ACTION_START, codes.NotFound},
+		{"missing enable", "bar", pb.Action_ACTION_ENABLE, codes.NotFound},
+		{"not executable", "functions", pb.Action_ACTION_START, codes.FailedPrecondition},
+		{"path", "../chkconfig", pb.Action_ACTION_START, codes.InvalidArgument},
+		{"failed start", "broken", pb.Action_ACTION_START, codes.Internal},
+		{"bad action", "foo", pb.Action_ACTION_DISABLE + 1, codes.InvalidArgument},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.Action(ctx, &pb.ActionRequest{ServiceName: tc.service, Action: tc.action})
+			if got := status.Code(err); got != tc.want {
+				t.Fatalf("got code %v want %v err %v", got, tc.want, err)
+			}
+		})
+	}
+
+	// An unknown status isn't an error.
+	got, err := s.Status(ctx, &pb.StatusRequest{ServiceName: "broken"})
+	testutil.FatalOnErr("broken status", err, t)
+	testutil.DiffErr("broken status", got.ServiceStatus.Status, pb.Status_STATUS_UNKNOWN, t)
+
+	// Listing isn't supported.
+	_, err = s.List(ctx, &pb.ListRequest{})
+	if got, want := status.Code(err), codes.Unimplemented; got != want {
+		t.Fatalf("List got code %v want %v err %v", got, want, err)
+	}
+}
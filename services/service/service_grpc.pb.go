@@ -23,7 +23,13 @@ type ServiceClient interface {
 	// Status requests the status of a single service.
 	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error)
 	// Action alters the status of a single service.
+	//
+	// As the service name is part of the request policies can allow actions
+	// on a per service basis.
 	Action(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*ActionReply, error)
+	// ListUnits streams every unit (not just services) with its detailed
+	// state, in name order. Only supported for systemd.
+	ListUnits(ctx context.Context, in *ListUnitsRequest, opts ...grpc.CallOption) (Service_ListUnitsClient, error)
 }
 
 type serviceClient struct {
@@ -61,6 +67,38 @@ func (c *serviceClient) Action(ctx context.Context, in *ActionRequest, opts ...g
 	return out, nil
 }
 
+func (c *serviceClient) ListUnits(ctx context.Context, in *ListUnitsRequest, opts ...grpc.CallOption) (Service_ListUnitsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Service_ServiceDesc.Streams[0], "/Service.Service/ListUnits", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &serviceListUnitsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Service_ListUnitsClient interface {
+	Recv() (*ListUnitsReply, error)
+	grpc.ClientStream
+}
+
+type serviceListUnitsClient struct {
+	grpc.ClientStream
+}
+
+func (x *serviceListUnitsClient) Recv() (*ListUnitsReply, error) {
+	m := new(ListUnitsReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ServiceServer is the server API for Service service.
 // All implementations should embed UnimplementedServiceServer
 // for forward compatibility
@@ -70,7 +108,13 @@ type ServiceServer interface {
 	// Status requests the status of a single service.
 	Status(context.Context, *StatusRequest) (*StatusReply, error)
 	// Action alters the status of a single service.
+	//
+	// As the service name is part of the request policies can allow actions
+	// on a per service basis.
 	Action(context.Context, *ActionRequest) (*ActionReply, error)
+	// ListUnits streams every unit (not just services) with its detailed
+	// state, in name order. Only supported for systemd.
+	ListUnits(*ListUnitsRequest, Service_ListUnitsServer) error
 }
 
 // UnimplementedServiceServer should be embedded to have forward compatible implementations.
@@ -86,6 +130,9 @@ func (UnimplementedServiceServer) Status(context.Context, *StatusRequest) (*Stat
 func (UnimplementedServiceServer) Action(context.Context, *ActionRequest) (*ActionReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Action not implemented")
 }
+func (UnimplementedServiceServer) ListUnits(*ListUnitsRequest, Service_ListUnitsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListUnits not implemented")
+}
 
 // UnsafeServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to ServiceServer will
@@ -152,6 +199,27 @@ func _Service_Action_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Service_ListUnits_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListUnitsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ServiceServer).ListUnits(m, &serviceListUnitsServer{stream})
+}
+
+type Service_ListUnitsServer interface {
+	Send(*ListUnitsReply) error
+	grpc.ServerStream
+}
+
+type serviceListUnitsServer struct {
+	grpc.ServerStream
+}
+
+func (x *serviceListUnitsServer) Send(m *ListUnitsReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Service_ServiceDesc is the grpc.ServiceDesc for Service service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -172,6 +240,12 @@ var Service_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Service_Action_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListUnits",
+			Handler:       _Service_ListUnits_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "service.proto",
 }
@@ -13,6 +13,7 @@ import (
 
 import (
 	"fmt"
+	"io"
 )
 
 // ServiceClientProxy is the superset of ServiceClient which additionally includes the OneMany proxy methods
@@ -21,6 +22,7 @@ type ServiceClientProxy interface {
 	ListOneMany(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (<-chan *ListManyResponse, error)
 	StatusOneMany(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (<-chan *StatusManyResponse, error)
 	ActionOneMany(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (<-chan *ActionManyResponse, error)
+	ListUnitsOneMany(ctx context.Context, in *ListUnitsRequest, opts ...grpc.CallOption) (Service_ListUnitsClientProxy, error)
 }
 
 // Embed the original client inside of this so we get the other generated methods automatically.
@@ -234,3 +236,91 @@ func (c *serviceClientProxy) ActionOneMany(ctx context.Context, in *ActionReques
 
 	return ret, nil
 }
+
+// ListUnitsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ListUnitsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ListUnitsReply
+	Error error
+}
+
+type Service_ListUnitsClientProxy interface {
+	Recv() ([]*ListUnitsManyResponse, error)
+	grpc.ClientStream
+}
+
+type serviceClientListUnitsClientProxy struct {
+	cc         *proxy.Conn
+	directDone bool
+	grpc.ClientStream
+}
+
+func (x *serviceClientListUnitsClientProxy) Recv() ([]*ListUnitsManyResponse, error) {
+	var ret []*ListUnitsManyResponse
+	// If this is a direct connection the RecvMsg call is to a standard grpc.ClientStream
+	// and not our proxy based one. This means we need to receive a typed response and
+	// convert it into a single slice entry return. This ensures the OneMany style calls
+	// can be used by proxy with 1:N targets and non proxy with 1 target without client changes.
+	if x.cc.Direct() {
+		// Check if we're done. Just return EOF now. Any real error was already sent inside
+		// of a ManyResponse.
+		if x.directDone {
+			return nil, io.EOF
+		}
+		m := &ListUnitsReply{}
+		err := x.ClientStream.RecvMsg(m)
+		ret = append(ret, &ListUnitsManyResponse{
+			Resp:   m,
+			Error:  err,
+			Target: x.cc.Targets[0],
+			Index:  0,
+		})
+		// An error means we're done so set things so a later call now gets an EOF.
+		if err != nil {
+			x.directDone = true
+		}
+		return ret, nil
+	}
+
+	m := []*proxy.Ret{}
+	if err := x.ClientStream.RecvMsg(&m); err != nil {
+		return nil, err
+	}
+	for _, r := range m {
+		typedResp := &ListUnitsManyResponse{
+			Resp: &ListUnitsReply{},
+		}
+		typedResp.Target = r.Target
+		typedResp.Index = r.Index
+		typedResp.Error = r.Error
+		if r.Error == nil {
+			if err := r.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+				typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, r.Error)
+			}
+		}
+		ret = append(ret, typedResp)
+	}
+	return ret, nil
+}
+
+// ListUnitsOneMany provides the same API as ListUnits but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *serviceClientProxy) ListUnitsOneMany(ctx context.Context, in *ListUnitsRequest, opts ...grpc.CallOption) (Service_ListUnitsClientProxy, error) {
+	stream, err := c.cc.NewStream(ctx, &Service_ServiceDesc.Streams[0], "/Service.Service/ListUnits", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &serviceClientListUnitsClientProxy{c.cc.(*proxy.Conn), false, stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
@@ -25,8 +25,9 @@ import (
 )
 
 var (
-	mu          sync.RWMutex
-	rpcServices []SansShellRPCService
+	mu              sync.RWMutex
+	rpcServices     []SansShellRPCService
+	policyFragments = make(map[string]string)
 )
 
 // SansShellRPCService provides an interface for services to implement
@@ -49,3 +50,24 @@ func ListServices() []SansShellRPCService {
 	defer mu.RUnlock()
 	return rpcServices
 }
+
+// RegisterPolicyFragment allows a service to ship a default OPA policy fragment
+// (see opa.WithFragments) which servers may compose with their own policy.
+// Fragments should only grant access which is safe for any deployment
+// (i.e. health checks). Registering the same name twice replaces the fragment.
+func RegisterPolicyFragment(name string, policy string) {
+	mu.Lock()
+	defer mu.Unlock()
+	policyFragments[name] = policy
+}
+
+// PolicyFragments returns a copy of the registered policy fragments keyed by name.
+func PolicyFragments() map[string]string {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make(map[string]string, len(policyFragments))
+	for k, v := range policyFragments {
+		out[k] = v
+	}
+	return out
+}
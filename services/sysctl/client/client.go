@@ -0,0 +1,172 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'sysctl'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/google/subcommands"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/sysctl"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "sysctl"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&allowlistedCmd{}, "")
+	c.Register(&getCmd{}, "")
+	c.Register(&setCmd{}, "")
+	return c
+}
+
+type getCmd struct{}
+
+func (*getCmd) Name() string     { return "get" }
+func (*getCmd) Synopsis() string { return "Get the value of sysctls" }
+func (*getCmd) Usage() string {
+	return `get <key> [<key>...]:
+    Print the value of each sysctl, i.e. net.ipv4.ip_forward.
+`
+}
+
+func (*getCmd) SetFlags(f *flag.FlagSet) {}
+
+func (g *getCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() == 0 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify at least one sysctl.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, g)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewSysctlClientProxy(state.Conn)
+	retCode := subcommands.ExitSuccess
+	for _, key := range f.Args() {
+		respChan, err := c.GetOneMany(ctx, &pb.GetRequest{Key: key})
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "error executing 'get' for %s: %v\n", key, err)
+			}
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for resp := range respChan {
+			if resp.Error != nil {
+				fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			fmt.Fprintf(state.Out[resp.Index], "%s = %s\n", resp.Resp.Key, resp.Resp.Value)
+		}
+	}
+	return retCode
+}
+
+type setCmd struct{}
+
+func (*setCmd) Name() string     { return "set" }
+func (*setCmd) Synopsis() string { return "Set the value of a sysctl" }
+func (*setCmd) Usage() string {
+	return `set <key> <value>:
+    Set the sysctl to value, printing the old and new values. The change
+    doesn't persist across reboots.
+`
+}
+
+func (*setCmd) SetFlags(f *flag.FlagSet) {}
+
+func (s *setCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 2 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify a sysctl and value.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, s)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewSysctlClientProxy(state.Conn)
+	respChan, err := c.SetOneMany(ctx, &pb.SetRequest{Key: f.Arg(0), Value: f.Arg(1)})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'set': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[resp.Index], "%s = %s (was %s)\n", resp.Resp.Key, resp.Resp.Value, resp.Resp.OldValue)
+	}
+	return retCode
+}
+
+type allowlistedCmd struct{}
+
+func (*allowlistedCmd) Name() string { return "allowlisted" }
+func (*allowlistedCmd) Synopsis() string {
+	return "Get the sysctls the remote host is configured to report"
+}
+func (*allowlistedCmd) Usage() string {
+	return `allowlisted:
+    Print the values of the sysctls in the remote server's
+    --sysctl-allowlist, i.e. to audit kernel tunables across hosts.
+`
+}
+
+func (*allowlistedCmd) SetFlags(f *flag.FlagSet) {}
+
+func (a *allowlistedCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewSysctlClientProxy(state.Conn)
+	respChan, err := c.GetAllowlistedOneMany(ctx, &pb.GetAllowlistedRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'allowlisted': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for resp := range respChan {
+		if resp.Error != nil {
+			fmt.Fprintf(state.Err[resp.Index], "target %s (%d) error: %v\n", resp.Target, resp.Index, resp.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, v := range resp.Resp.Values {
+			fmt.Fprintf(state.Out[resp.Index], "%s = %s\n", v.Key, v.Value)
+		}
+	}
+	return retCode
+}
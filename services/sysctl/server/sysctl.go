@@ -0,0 +1,203 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'Sysctl' service.
+package server
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/sysctl"
+)
+
+var allowlist = flag.String("sysctl-allowlist", "", "Comma separated list of sysctls returned by Sysctl.GetAllowlisted. Entries may contain shell style wildcards, i.e. net.ipv4.tcp_*")
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// sysctlPath returns the file under procSysDir for key. As with the dotted
+// form of sysctl(8) names are separated by dots and a / stands for a dot
+// within a name, i.e. net.ipv4.conf.eth0/100.forwarding. Each sysctl so has
+// a single spelling, which policy can rely on.
+func sysctlPath(key string) (string, error) {
+	if procSysDir == "" {
+		return "", status.Error(codes.Unimplemented, "sysctl is not supported on this platform")
+	}
+	parts := strings.Split(key, ".")
+	for i, p := range parts {
+		p = strings.ReplaceAll(p, "/", ".")
+		if p == "" || strings.HasPrefix(p, ".") || strings.ContainsRune(p, 0) {
+			return "", status.Errorf(codes.InvalidArgument, "invalid sysctl %q", key)
+		}
+		parts[i] = p
+	}
+	return filepath.Join(append([]string{procSysDir}, parts...)...), nil
+}
+
+// pathKey is the inverse of sysctlPath.
+func pathKey(path string) (string, error) {
+	rel, err := filepath.Rel(procSysDir, path)
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(p, ".", "/")
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// fileError converts an error accessing a sysctl into a status.
+func fileError(key string, err error) error {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return status.Errorf(codes.NotFound, "no such sysctl %s", key)
+	case errors.Is(err, os.ErrPermission):
+		return status.Errorf(codes.PermissionDenied, "can't access sysctl %s: %v", key, err)
+	case errors.Is(err, syscall.EINVAL):
+		return status.Errorf(codes.InvalidArgument, "invalid value for sysctl %s: %v", key, err)
+	default:
+		return status.Errorf(codes.Internal, "can't access sysctl %s: %v", key, err)
+	}
+}
+
+// read returns the value of the sysctl in path.
+func read(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// lookup returns the path for key after checking it's a sysctl.
+func lookup(key string) (string, error) {
+	path, err := sysctlPath(key)
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", fileError(key, err)
+	}
+	if fi.IsDir() {
+		return "", status.Errorf(codes.InvalidArgument, "%s is a group of sysctls, not a sysctl", key)
+	}
+	return path, nil
+}
+
+// Get implements pb.SysctlServer.Get
+func (s *server) Get(ctx context.Context, req *pb.GetRequest) (*pb.Value, error) {
+	path, err := lookup(req.Key)
+	if err != nil {
+		return nil, err
+	}
+	v, err := read(path)
+	if err != nil {
+		return nil, fileError(req.Key, err)
+	}
+	return &pb.Value{Key: req.Key, Value: v}, nil
+}
+
+// Set implements pb.SysctlServer.Set
+func (s *server) Set(ctx context.Context, req *pb.SetRequest) (*pb.SetReply, error) {
+	if req.Value == "" || strings.ContainsAny(req.Value, "\n\x00") {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid value %q", req.Value)
+	}
+	path, err := lookup(req.Key)
+	if err != nil {
+		return nil, err
+	}
+	// Some sysctls (i.e. vm.drop_caches) are write only.
+	old, _ := read(path)
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fileError(req.Key, err)
+	}
+	// The kernel parses the value in a single write.
+	_, err = f.Write([]byte(req.Value))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, fileError(req.Key, err)
+	}
+	v, _ := read(path)
+	return &pb.SetReply{Key: req.Key, OldValue: old, Value: v}, nil
+}
+
+// GetAllowlisted implements pb.SysctlServer.GetAllowlisted
+func (s *server) GetAllowlisted(ctx context.Context, req *pb.GetAllowlistedRequest) (*pb.GetAllowlistedReply, error) {
+	if procSysDir == "" {
+		return nil, status.Error(codes.Unimplemented, "sysctl is not supported on this platform")
+	}
+	values := make(map[string]string)
+	for _, entry := range strings.Split(*allowlist, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pattern, err := sysctlPath(entry)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "invalid --sysctl-allowlist entry %q", entry)
+		}
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "invalid --sysctl-allowlist entry %q: %v", entry, err)
+		}
+		for _, path := range paths {
+			key, err := pathKey(path)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "can't get sysctl name of %s: %v", path, err)
+			}
+			if fi, err := os.Stat(path); err != nil || fi.IsDir() {
+				continue
+			}
+			v, err := read(path)
+			if err != nil {
+				continue
+			}
+			values[key] = v
+		}
+	}
+	reply := &pb.GetAllowlistedReply{}
+	for k, v := range values {
+		reply.Values = append(reply.Values, &pb.Value{Key: k, Value: v})
+	}
+	sort.Slice(reply.Values, func(i, j int) bool { return reply.Values[i].Key < reply.Values[j].Key })
+	return reply, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterSysctlServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
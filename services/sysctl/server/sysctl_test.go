@@ -0,0 +1,201 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysctl"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// fakeProcSys points procSysDir at a temporary directory containing the
+// given sysctls (as paths relative to it) and returns it.
+func fakeProcSys(t *testing.T, sysctls map[string]string) string {
+	t.Helper()
+	saved := procSysDir
+	t.Cleanup(func() { procSysDir = saved })
+	procSysDir = t.TempDir()
+	for k, v := range sysctls {
+		path := filepath.Join(procSysDir, k)
+		testutil.FatalOnErr("mkdir", os.MkdirAll(filepath.Dir(path), 0755), t)
+		testutil.FatalOnErr("writing sysctl", os.WriteFile(path, []byte(v), 0644), t)
+	}
+	return procSysDir
+}
+
+var testSysctls = map[string]string{
+	"net/ipv4/ip_forward":                   "1\n",
+	"net/ipv4/tcp_rmem":                     "4096\t131072\t6291456\n",
+	"net/ipv4/tcp_syncookies":               "1\n",
+	"net/ipv4/conf/eth0.100/forwarding":     "0\n",
+	"kernel/hostname":                       "host\n",
+	"kernel/random/boot_id":                 "abcd\n",
+	"vm/swappiness":                         "60\n",
+	"net/ipv4/conf/all/accept_source_route": "0\n",
+}
+
+func TestGet(t *testing.T) {
+	fakeProcSys(t, testSysctls)
+	for _, tc := range []struct {
+		name    string
+		key     string
+		want    string
+		wantErr codes.Code
+	}{
+		{name: "dotted", key: "net.ipv4.ip_forward", want: "1"},
+		{name: "several values", key: "net.ipv4.tcp_rmem", want: "4096\t131072\t6291456"},
+		{name: "dot in name", key: "net.ipv4.conf.eth0/100.forwarding", want: "0"},
+		// Only the dotted form names a sysctl, so policy on it can't be
+		// bypassed with another spelling.
+		{name: "slashes", key: "kernel/hostname", wantErr: codes.NotFound},
+		{name: "slashes path", key: "net/ipv4/conf/eth0.100/forwarding", wantErr: codes.NotFound},
+		{name: "missing", key: "net.ipv4.nope", wantErr: codes.NotFound},
+		{name: "group", key: "net.ipv4", wantErr: codes.InvalidArgument},
+		{name: "empty", key: "", wantErr: codes.InvalidArgument},
+		{name: "escape", key: "../../etc/passwd", wantErr: codes.InvalidArgument},
+		{name: "escape dotted", key: "net...", wantErr: codes.InvalidArgument},
+		{name: "absolute", key: "/etc/passwd", wantErr: codes.InvalidArgument},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := (&server{}).Get(context.Background(), &pb.GetRequest{Key: tc.key})
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, resp, &pb.Value{Key: tc.key, Value: tc.want}, t)
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		req     *pb.SetRequest
+		want    *pb.SetReply
+		wantErr codes.Code
+	}{
+		{
+			name: "set",
+			req:  &pb.SetRequest{Key: "vm.swappiness", Value: "10"},
+			want: &pb.SetReply{Key: "vm.swappiness", OldValue: "60", Value: "10"},
+		},
+		{
+			name: "set dot in name",
+			req:  &pb.SetRequest{Key: "net.ipv4.conf.eth0/100.forwarding", Value: "1"},
+			want: &pb.SetReply{Key: "net.ipv4.conf.eth0/100.forwarding", OldValue: "0", Value: "1"},
+		},
+		{
+			name:    "set slashes",
+			req:     &pb.SetRequest{Key: "kernel/hostname", Value: "other"},
+			wantErr: codes.NotFound,
+		},
+		{
+			name:    "missing",
+			req:     &pb.SetRequest{Key: "vm.nope", Value: "1"},
+			wantErr: codes.NotFound,
+		},
+		{
+			name:    "group",
+			req:     &pb.SetRequest{Key: "vm", Value: "1"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "no value",
+			req:     &pb.SetRequest{Key: "vm.swappiness"},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "newline",
+			req:     &pb.SetRequest{Key: "vm.swappiness", Value: "1\n2"},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fakeProcSys(t, testSysctls)
+			resp, err := (&server{}).Set(context.Background(), tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, resp, tc.want, t)
+		})
+	}
+}
+
+func TestGetAllowlisted(t *testing.T) {
+	saved := *allowlist
+	t.Cleanup(func() { *allowlist = saved })
+	fakeProcSys(t, testSysctls)
+
+	for _, tc := range []struct {
+		name      string
+		allowlist string
+		want      []*pb.Value
+		wantErr   codes.Code
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:      "keys and wildcards",
+			allowlist: "vm.swappiness, net.ipv4.tcp_*,net.ipv4.conf.*.forwarding,kernel.*,net.ipv4.missing",
+			want: []*pb.Value{
+				{Key: "kernel.hostname", Value: "host"},
+				{Key: "net.ipv4.conf.eth0/100.forwarding", Value: "0"},
+				{Key: "net.ipv4.tcp_rmem", Value: "4096\t131072\t6291456"},
+				{Key: "net.ipv4.tcp_syncookies", Value: "1"},
+				{Key: "vm.swappiness", Value: "60"},
+			},
+		},
+		{
+			name:      "bad entry",
+			allowlist: "vm.swappiness,..",
+			wantErr:   codes.FailedPrecondition,
+		},
+		{
+			name:      "bad pattern",
+			allowlist: "net.ipv4.[",
+			wantErr:   codes.FailedPrecondition,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			*allowlist = tc.allowlist
+			resp, err := (&server{}).GetAllowlisted(context.Background(), &pb.GetAllowlistedRequest{})
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, resp, &pb.GetAllowlistedReply{Values: tc.want}, t)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package sysctl
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// SysctlClient is the client API for Sysctl service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SysctlClient interface {
+	// Get returns the value of a sysctl.
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Value, error)
+	// Set changes the value of a sysctl. The change doesn't persist across
+	// reboots.
+	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetReply, error)
+	// GetAllowlisted returns the values of the sysctls the server is
+	// configured (with --sysctl-allowlist) to report, for auditing.
+	GetAllowlisted(ctx context.Context, in *GetAllowlistedRequest, opts ...grpc.CallOption) (*GetAllowlistedReply, error)
+}
+
+type sysctlClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSysctlClient(cc grpc.ClientConnInterface) SysctlClient {
+	return &sysctlClient{cc}
+}
+
+func (c *sysctlClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Value, error) {
+	out := new(Value)
+	err := c.cc.Invoke(ctx, "/Sysctl.Sysctl/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sysctlClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetReply, error) {
+	out := new(SetReply)
+	err := c.cc.Invoke(ctx, "/Sysctl.Sysctl/Set", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sysctlClient) GetAllowlisted(ctx context.Context, in *GetAllowlistedRequest, opts ...grpc.CallOption) (*GetAllowlistedReply, error) {
+	out := new(GetAllowlistedReply)
+	err := c.cc.Invoke(ctx, "/Sysctl.Sysctl/GetAllowlisted", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SysctlServer is the server API for Sysctl service.
+// All implementations should embed UnimplementedSysctlServer
+// for forward compatibility
+type SysctlServer interface {
+	// Get returns the value of a sysctl.
+	Get(context.Context, *GetRequest) (*Value, error)
+	// Set changes the value of a sysctl. The change doesn't persist across
+	// reboots.
+	Set(context.Context, *SetRequest) (*SetReply, error)
+	// GetAllowlisted returns the values of the sysctls the server is
+	// configured (with --sysctl-allowlist) to report, for auditing.
+	GetAllowlisted(context.Context, *GetAllowlistedRequest) (*GetAllowlistedReply, error)
+}
+
+// UnimplementedSysctlServer should be embedded to have forward compatible implementations.
+type UnimplementedSysctlServer struct {
+}
+
+func (UnimplementedSysctlServer) Get(context.Context, *GetRequest) (*Value, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedSysctlServer) Set(context.Context, *SetRequest) (*SetReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
+}
+func (UnimplementedSysctlServer) GetAllowlisted(context.Context, *GetAllowlistedRequest) (*GetAllowlistedReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllowlisted not implemented")
+}
+
+// UnsafeSysctlServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SysctlServer will
+// result in compilation errors.
+type UnsafeSysctlServer interface {
+	mustEmbedUnimplementedSysctlServer()
+}
+
+func RegisterSysctlServer(s grpc.ServiceRegistrar, srv SysctlServer) {
+	s.RegisterService(&Sysctl_ServiceDesc, srv)
+}
+
+func _Sysctl_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SysctlServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Sysctl.Sysctl/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SysctlServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sysctl_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SysctlServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Sysctl.Sysctl/Set",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SysctlServer).Set(ctx, req.(*SetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sysctl_GetAllowlisted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllowlistedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SysctlServer).GetAllowlisted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Sysctl.Sysctl/GetAllowlisted",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SysctlServer).GetAllowlisted(ctx, req.(*GetAllowlistedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Sysctl_ServiceDesc is the grpc.ServiceDesc for Sysctl service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Sysctl_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Sysctl.Sysctl",
+	HandlerType: (*SysctlServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _Sysctl_Get_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _Sysctl_Set_Handler,
+		},
+		{
+			MethodName: "GetAllowlisted",
+			Handler:    _Sysctl_GetAllowlisted_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sysctl.proto",
+}
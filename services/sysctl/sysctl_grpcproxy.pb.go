@@ -0,0 +1,236 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package sysctl
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// SysctlClientProxy is the superset of SysctlClient which additionally includes the OneMany proxy methods
+type SysctlClientProxy interface {
+	SysctlClient
+	GetOneMany(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (<-chan *GetManyResponse, error)
+	SetOneMany(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (<-chan *SetManyResponse, error)
+	GetAllowlistedOneMany(ctx context.Context, in *GetAllowlistedRequest, opts ...grpc.CallOption) (<-chan *GetAllowlistedManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type sysctlClientProxy struct {
+	*sysctlClient
+}
+
+// NewSysctlClientProxy creates a SysctlClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewSysctlClientProxy(cc *proxy.Conn) SysctlClientProxy {
+	return &sysctlClientProxy{NewSysctlClient(cc).(*sysctlClient)}
+}
+
+// GetManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type GetManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *Value
+	Error error
+}
+
+// GetOneMany provides the same API as Get but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *sysctlClientProxy) GetOneMany(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (<-chan *GetManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *GetManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &GetManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &Value{},
+			}
+			err := conn.Invoke(ctx, "/Sysctl.Sysctl/Get", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Sysctl.Sysctl/Get", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &GetManyResponse{
+				Resp: &Value{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// SetManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type SetManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *SetReply
+	Error error
+}
+
+// SetOneMany provides the same API as Set but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *sysctlClientProxy) SetOneMany(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (<-chan *SetManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *SetManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &SetManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &SetReply{},
+			}
+			err := conn.Invoke(ctx, "/Sysctl.Sysctl/Set", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Sysctl.Sysctl/Set", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &SetManyResponse{
+				Resp: &SetReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// GetAllowlistedManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type GetAllowlistedManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *GetAllowlistedReply
+	Error error
+}
+
+// GetAllowlistedOneMany provides the same API as GetAllowlisted but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *sysctlClientProxy) GetAllowlistedOneMany(ctx context.Context, in *GetAllowlistedRequest, opts ...grpc.CallOption) (<-chan *GetAllowlistedManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *GetAllowlistedManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &GetAllowlistedManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &GetAllowlistedReply{},
+			}
+			err := conn.Invoke(ctx, "/Sysctl.Sysctl/GetAllowlisted", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Sysctl.Sysctl/GetAllowlisted", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &GetAllowlistedManyResponse{
+				Resp: &GetAllowlistedReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
@@ -0,0 +1,623 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'sysinfo'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "sysinfo"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&dfCmd{}, "")
+	c.Register(&dmesgCmd{}, "")
+	c.Register(&duCmd{}, "")
+	c.Register(&gpusCmd{}, "")
+	c.Register(&infoCmd{}, "")
+	c.Register(&journalCmd{}, "")
+	c.Register(&timesyncCmd{}, "")
+	return c
+}
+
+func priorityString(p pb.Priority) string {
+	return strings.ToLower(strings.TrimPrefix(p.String(), "PRIORITY_"))
+}
+
+func facilityString(f pb.Facility) string {
+	return strings.ToLower(strings.TrimPrefix(f.String(), "FACILITY_"))
+}
+
+func priorityFlag(f *flag.FlagSet, p *string, what string) {
+	var priorities []string
+	for k := range pb.Priority_name {
+		if p := pb.Priority(k); p != pb.Priority_PRIORITY_UNKNOWN {
+			priorities = append(priorities, priorityString(p))
+		}
+	}
+	sort.Strings(priorities)
+	f.StringVar(p, "priority", "", fmt.Sprintf("If set only show %s of this priority or more important (one of: [%s])", what, strings.Join(priorities, ",")))
+}
+
+func flagToPriority(val string) (pb.Priority, error) {
+	if val == "" {
+		return pb.Priority_PRIORITY_UNKNOWN, nil
+	}
+	v := fmt.Sprintf("PRIORITY_%s", strings.ToUpper(val))
+	i, ok := pb.Priority_value[v]
+	if !ok || i == int32(pb.Priority_PRIORITY_UNKNOWN) {
+		return pb.Priority_PRIORITY_UNKNOWN, fmt.Errorf("no such priority %s", val)
+	}
+	return pb.Priority(i), nil
+}
+
+// parseTime parses a time given either in RFC3339 format or as a duration
+// before now (i.e. 1h).
+func parseTime(val string) (*timestamppb.Timestamp, error) {
+	if val == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(val); err == nil {
+		return timestamppb.New(time.Now().Add(-d)), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return nil, fmt.Errorf("%s isn't an RFC3339 time or a duration", val)
+	}
+	return timestamppb.New(t), nil
+}
+
+type infoCmd struct{}
+
+func (*infoCmd) Name() string     { return "info" }
+func (*infoCmd) Synopsis() string { return "Print basic system information" }
+func (*infoCmd) Usage() string {
+	return `info:
+    Print uptime, kernel and OS versions, CPU count, load averages and memory.
+`
+}
+
+func (*infoCmd) SetFlags(f *flag.FlagSet) {}
+
+// mib formats a size in bytes as MiB.
+func mib(b uint64) string {
+	return fmt.Sprintf("%d MiB", b/(1024*1024))
+}
+
+func (i *infoCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+
+	c := pb.NewSysInfoClientProxy(state.Conn)
+	respChan, err := c.InfoOneMany(ctx, &pb.InfoRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'info': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[r.Index]
+		info := r.Resp
+		fmt.Fprintf(out, "uptime: %v (booted %s)\n", info.Uptime.AsDuration().Round(time.Second), info.BootTime.AsTime().Local().Format(time.RFC3339))
+		fmt.Fprintf(out, "kernel: %s %s %s %s\n", info.KernelName, info.KernelRelease, info.KernelVersion, info.Architecture)
+		if info.OsRelease != nil {
+			fmt.Fprintf(out, "os: %s\n", info.OsRelease.PrettyName)
+		}
+		fmt.Fprintf(out, "cpus: %d\n", info.CpuCount)
+		load := info.LoadAverage
+		fmt.Fprintf(out, "load average: %.2f %.2f %.2f\n", load.GetOneMinute(), load.GetFiveMinutes(), load.GetFifteenMinutes())
+		fmt.Fprintf(out, "memory: total %s free %s available %s\n", mib(info.MemoryTotal), mib(info.MemoryFree), mib(info.MemoryAvailable))
+	}
+	return retCode
+}
+
+type journalCmd struct {
+	unit      string
+	priority  string
+	since     string
+	until     string
+	grep      string
+	limit     int
+	follow    bool
+	allFields bool
+}
+
+func (*journalCmd) Name() string     { return "journal" }
+func (*journalCmd) Synopsis() string { return "Query the systemd journal" }
+func (*journalCmd) Usage() string {
+	return `journal [--unit <unit>] [--priority <priority>] [--since <time>] [--until <time>] [--grep <regexp>] [--limit <n>] [--follow] [--all-fields]:
+    Print journal entries, oldest first. Times are in RFC3339 format or a
+    duration before now (i.e. 1h). With --follow new entries are printed as
+    they're written until interrupted.
+`
+}
+
+func (j *journalCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&j.unit, "unit", "", "If set only show entries for this unit")
+	priorityFlag(f, &j.priority, "entries")
+	f.StringVar(&j.since, "since", "", "If set only show entries written at or after this time")
+	f.StringVar(&j.until, "until", "", "If set only show entries written at or before this time")
+	f.StringVar(&j.grep, "grep", "", "If set only show entries with messages matching this regular expression")
+	f.IntVar(&j.limit, "limit", 0, "Show at most this many of the most recent entries. If unset the remote side picks (100, or none when following)")
+	f.BoolVar(&j.follow, "follow", false, "If true keep printing new entries as they're written")
+	f.BoolVar(&j.allFields, "all-fields", false, "If true also print every field of each entry")
+}
+
+// printRecord writes a journal entry like journalctl's short-iso format,
+// followed by all of its fields if they were returned.
+func printRecord(w io.Writer, r *pb.JournalRecord) {
+	ident := r.SyslogIdentifier
+	if r.Pid != 0 {
+		ident = fmt.Sprintf("%s[%d]", ident, r.Pid)
+	}
+	fmt.Fprintf(w, "%s %s %s %s: %s\n", r.RealtimeTimestamp.AsTime().Local().Format(time.RFC3339), r.Hostname, priorityString(r.Priority), ident, r.Message)
+	var keys []string
+	for k := range r.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "    %s=%q\n", k, r.Fields[k])
+	}
+}
+
+func (j *journalCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+
+	priority, err := flagToPriority(j.priority)
+	if err != nil {
+		fmt.Fprintln(errWriter, err)
+		subcommands.DefaultCommander.ExplainCommand(errWriter, j)
+		return subcommands.ExitUsageError
+	}
+	since, err := parseTime(j.since)
+	if err != nil {
+		fmt.Fprintln(errWriter, err)
+		subcommands.DefaultCommander.ExplainCommand(errWriter, j)
+		return subcommands.ExitUsageError
+	}
+	until, err := parseTime(j.until)
+	if err != nil {
+		fmt.Fprintln(errWriter, err)
+		subcommands.DefaultCommander.ExplainCommand(errWriter, j)
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.JournalRequest{
+		Unit:      j.unit,
+		Priority:  priority,
+		Since:     since,
+		Until:     until,
+		Grep:      j.grep,
+		Limit:     int32(j.limit),
+		Follow:    j.follow,
+		AllFields: j.allFields,
+	}
+	c := pb.NewSysInfoClientProxy(state.Conn)
+
+	stream, err := c.JournalOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'journal': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "error receiving journal: %v\n", err)
+			}
+			return subcommands.ExitFailure
+		}
+		for _, r := range resp {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			printRecord(state.Out[r.Index], r.Resp.GetRecord())
+		}
+	}
+	return retCode
+}
+
+type dmesgCmd struct {
+	priority string
+	since    string
+	grep     string
+	follow   bool
+}
+
+func (*dmesgCmd) Name() string     { return "dmesg" }
+func (*dmesgCmd) Synopsis() string { return "Print the kernel ring buffer" }
+func (*dmesgCmd) Usage() string {
+	return `dmesg [--priority <priority>] [--since <time>] [--grep <regexp>] [--follow]:
+    Print kernel ring buffer records, oldest first, with their facility and
+    priority. Times are in RFC3339 format or a duration before now (i.e. 1h).
+    With --follow new records are printed as they're logged until interrupted.
+`
+}
+
+func (d *dmesgCmd) SetFlags(f *flag.FlagSet) {
+	priorityFlag(f, &d.priority, "records")
+	f.StringVar(&d.since, "since", "", "If set only show records logged at or after this time")
+	f.StringVar(&d.grep, "grep", "", "If set only show records with messages matching this regular expression")
+	f.BoolVar(&d.follow, "follow", false, "If true keep printing new records as they're logged")
+}
+
+func (d *dmesgCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	errWriter := subcommands.DefaultCommander.Error
+
+	priority, err := flagToPriority(d.priority)
+	if err != nil {
+		fmt.Fprintln(errWriter, err)
+		subcommands.DefaultCommander.ExplainCommand(errWriter, d)
+		return subcommands.ExitUsageError
+	}
+	since, err := parseTime(d.since)
+	if err != nil {
+		fmt.Fprintln(errWriter, err)
+		subcommands.DefaultCommander.ExplainCommand(errWriter, d)
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.DmesgRequest{
+		Priority: priority,
+		Since:    since,
+		Grep:     d.grep,
+		Follow:   d.follow,
+	}
+	c := pb.NewSysInfoClientProxy(state.Conn)
+
+	stream, err := c.DmesgOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'dmesg': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Emit this to every error file as it's not specific to a given target.
+			for _, e := range state.Err {
+				fmt.Fprintf(e, "error receiving dmesg: %v\n", err)
+			}
+			return subcommands.ExitFailure
+		}
+		for _, r := range resp {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			rec := r.Resp.GetRecord()
+			fmt.Fprintf(state.Out[r.Index], "%s %-6s %-7s [%12.6f] %s\n", rec.Timestamp.AsTime().Local().Format(time.RFC3339), facilityString(rec.Facility), priorityString(rec.Priority), rec.Uptime.AsDuration().Seconds(), rec.Message)
+		}
+	}
+	return retCode
+}
+
+// humanSize formats a size in bytes with a binary unit, as df -h does.
+func humanSize(b uint64) string {
+	const units = "KMGTPE"
+	if b < 1024 {
+		return fmt.Sprintf("%dB", b)
+	}
+	v := float64(b) / 1024
+	i := 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f%c", v, units[i])
+}
+
+type dfCmd struct {
+	all    bool
+	inodes bool
+}
+
+func (*dfCmd) Name() string     { return "df" }
+func (*dfCmd) Synopsis() string { return "Print mounted filesystems and their usage" }
+func (*dfCmd) Usage() string {
+	return `df [--all] [--inodes]:
+    Print each mounted filesystem with its size, usage and mount options,
+    as df(1) does.
+`
+}
+
+func (d *dfCmd) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&d.all, "all", false, "If true include filesystems with no blocks such as proc")
+	f.BoolVar(&d.inodes, "inodes", false, "If true print inode rather than block usage")
+}
+
+func (d *dfCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+
+	c := pb.NewSysInfoClientProxy(state.Conn)
+	respChan, err := c.MountsOneMany(ctx, &pb.MountsRequest{All: d.all})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'df': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[r.Index]
+		if d.inodes {
+			fmt.Fprintf(out, "%-20s %-8s %10s %10s %10s %5s %s\n", "Filesystem", "Type", "Inodes", "IUsed", "IFree", "IUse%", "Mounted on")
+		} else {
+			fmt.Fprintf(out, "%-20s %-8s %10s %10s %10s %5s %s\n", "Filesystem", "Type", "Size", "Used", "Avail", "Use%", "Mounted on")
+		}
+		for _, m := range r.Resp.Mounts {
+			u := m.Usage
+			if u == nil {
+				fmt.Fprintf(out, "%-20s %-8s %10s %10s %10s %5s %s (%s)\n", m.Source, m.FsType, "-", "-", "-", "-", m.MountPoint, m.UsageError)
+				continue
+			}
+			total, used, free := u.Size, u.Used, u.Available
+			if d.inodes {
+				total, used, free = u.Inodes, u.InodesUsed, u.InodesFree
+			}
+			pct := "-"
+			// As with df the percentage is of the space usable by
+			// unprivileged users.
+			if used+free > 0 {
+				pct = fmt.Sprintf("%d%%", (used*100+used+free-1)/(used+free))
+			}
+			if d.inodes {
+				fmt.Fprintf(out, "%-20s %-8s %10d %10d %10d %5s %s\n", m.Source, m.FsType, total, used, free, pct, m.MountPoint)
+			} else {
+				fmt.Fprintf(out, "%-20s %-8s %10s %10s %10s %5s %s\n", m.Source, m.FsType, humanSize(total), humanSize(used), humanSize(free), pct, m.MountPoint)
+			}
+			fmt.Fprintf(out, "    options: %s\n", strings.Join(m.Options, ","))
+		}
+	}
+	return retCode
+}
+
+type duCmd struct {
+	maxDepth      uint
+	oneFileSystem bool
+	apparent      bool
+}
+
+func (*duCmd) Name() string     { return "du" }
+func (*duCmd) Synopsis() string { return "Print the disk space used under a path" }
+func (*duCmd) Usage() string {
+	return `du [--max-depth <n>] [--one-file-system] [--apparent-size] <path>:
+    Print the space used under path, and by each directory down to
+    --max-depth below it, as du(1) does.
+`
+}
+
+func (d *duCmd) SetFlags(f *flag.FlagSet) {
+	f.UintVar(&d.maxDepth, "max-depth", 0, "Also print the usage of directories this far below path")
+	f.BoolVar(&d.oneFileSystem, "one-file-system", false, "If true skip directories on other filesystems")
+	f.BoolVar(&d.apparent, "apparent-size", false, "If true print the sum of file sizes rather than the space allocated")
+}
+
+func (d *duCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify a path.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, d)
+		return subcommands.ExitUsageError
+	}
+
+	req := &pb.DiskUsageRequest{
+		Path:          f.Arg(0),
+		MaxDepth:      uint32(d.maxDepth),
+		OneFileSystem: d.oneFileSystem,
+	}
+	c := pb.NewSysInfoClientProxy(state.Conn)
+	respChan, err := c.DiskUsageOneMany(ctx, req)
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'du': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		for _, e := range r.Resp.Entries {
+			size := e.Size
+			if d.apparent {
+				size = e.ApparentSize
+			}
+			fmt.Fprintf(state.Out[r.Index], "%s\t%s\n", humanSize(size), e.Path)
+		}
+		if r.Resp.Errors > 0 {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d): %d files or directories couldn't be read\n", r.Target, r.Index, r.Resp.Errors)
+		}
+	}
+	return retCode
+}
+
+type timesyncCmd struct{}
+
+func (*timesyncCmd) Name() string     { return "timesync" }
+func (*timesyncCmd) Synopsis() string { return "Print clock synchronization status" }
+func (*timesyncCmd) Usage() string {
+	return `timesync:
+    Print whether the clock is synchronized, its offset, stratum, source and
+    last update from chrony, ntpd or systemd-timesyncd (whichever is running).
+`
+}
+
+func (*timesyncCmd) SetFlags(f *flag.FlagSet) {}
+
+func (ts *timesyncCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+
+	c := pb.NewSysInfoClientProxy(state.Conn)
+	respChan, err := c.TimeSyncOneMany(ctx, &pb.TimeSyncRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'timesync': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[r.Index]
+		ts := r.Resp
+		if ts.Daemon == pb.TimeDaemon_TIME_DAEMON_NONE {
+			fmt.Fprintln(out, "daemon: none")
+			continue
+		}
+		fmt.Fprintf(out, "daemon: %s\n", strings.ToLower(strings.TrimPrefix(ts.Daemon.String(), "TIME_DAEMON_")))
+		fmt.Fprintf(out, "synchronized: %t\n", ts.Synchronized)
+		fmt.Fprintf(out, "offset: %v\n", ts.Offset.AsDuration())
+		fmt.Fprintf(out, "stratum: %d\n", ts.Stratum)
+		fmt.Fprintf(out, "source: %s\n", ts.Source)
+		if ts.LastSync != nil {
+			fmt.Fprintf(out, "last sync: %s\n", ts.LastSync.AsTime().Local().Format(time.RFC3339))
+		}
+	}
+	return retCode
+}
+
+type gpusCmd struct{}
+
+func (*gpusCmd) Name() string     { return "gpus" }
+func (*gpusCmd) Synopsis() string { return "Print GPU status" }
+func (*gpusCmd) Usage() string {
+	return `gpus:
+    Print the utilization, memory, temperature, power draw and ECC errors
+    (corrected/uncorrected) of each GPU from nvidia-smi or rocm-smi, with
+    n/a for anything the GPU doesn't report.
+`
+}
+
+func (*gpusCmd) SetFlags(f *flag.FlagSet) {}
+
+// gpuValue formats v with unit, or n/a if it's negative (not reported).
+func gpuValue(v float64, unit string) string {
+	if v < 0 {
+		return "n/a"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64) + unit
+}
+
+func (g *gpusCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+
+	c := pb.NewSysInfoClientProxy(state.Conn)
+	respChan, err := c.GPUsOneMany(ctx, &pb.GPUsRequest{})
+	if err != nil {
+		// Emit this to every error file as it's not specific to a given target.
+		for _, e := range state.Err {
+			fmt.Fprintf(e, "error executing 'gpus': %v\n", err)
+		}
+		return subcommands.ExitFailure
+	}
+
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[r.Index]
+		if r.Resp.Tool == pb.GPUTool_GPU_TOOL_NONE {
+			fmt.Fprintln(out, "tool: none")
+			continue
+		}
+		fmt.Fprintf(out, "tool: %s driver: %s\n", strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(r.Resp.Tool.String(), "GPU_TOOL_"), "_", "-")), r.Resp.DriverVersion)
+		for _, gpu := range r.Resp.Gpus {
+			mem := "n/a"
+			if gpu.MemoryTotal > 0 {
+				mem = humanSize(gpu.MemoryUsed) + "/" + humanSize(gpu.MemoryTotal)
+			}
+			ecc := "n/a"
+			if gpu.EccErrors != nil {
+				ecc = fmt.Sprintf("%d/%d", gpu.EccErrors.Corrected, gpu.EccErrors.Uncorrected)
+			}
+			fmt.Fprintf(out, "%d %s (%s) util=%s mem=%s temp=%s power=%s ecc=%s\n", gpu.Index, gpu.Name, gpu.PciBusId,
+				gpuValue(float64(gpu.Utilization), "%"), mem, gpuValue(gpu.Temperature, "C"), gpuValue(gpu.PowerDraw, "W"), ecc)
+		}
+	}
+	return retCode
+}
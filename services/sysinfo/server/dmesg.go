@@ -0,0 +1,193 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+)
+
+const (
+	// maxKmsgRecordSize is the buffer used to read a record. Reads with
+	// smaller buffers than a record fail.
+	maxKmsgRecordSize = 8192
+	// kmsgPollInterval is how often the RPC context is checked while
+	// waiting for new records.
+	kmsgPollInterval = 500 * time.Millisecond
+)
+
+// kmsg is an open kernel ring buffer (i.e. /dev/kmsg).
+type kmsg interface {
+	// read returns the next record or nil if there isn't one yet.
+	read() ([]byte, error)
+	// wait returns once a record may be available or timeout passes.
+	wait(timeout time.Duration) error
+	Close() error
+}
+
+var (
+	// openKmsg opens the kernel ring buffer at the given path.
+	openKmsg = openKmsgDevice
+	// bootTime returns when the system booted.
+	bootTime = systemBootTime
+)
+
+// unescapeKmsg reverses the \xNN escaping the kernel applies to
+// unprintable characters and backslashes in records.
+func unescapeKmsg(s string) string {
+	if !strings.Contains(s, `\x`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) && s[i+1] == 'x' {
+			if v, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// parseKmsg parses a record read from /dev/kmsg, which looks like:
+//
+//	6,339,5140900,-;NET: Registered protocol family 10
+//	 SUBSYSTEM=net
+//	 DEVICE=n2
+//
+// The prefix is the syslog facility and level, sequence number and
+// microseconds since boot, followed by flags and possibly more fields in
+// future kernels. Any dictionary properties follow on indented lines.
+func parseKmsg(b []byte, boot time.Time) (*pb.DmesgRecord, error) {
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	i := strings.IndexByte(lines[0], ';')
+	if i < 0 {
+		return nil, fmt.Errorf("record has no prefix: %q", lines[0])
+	}
+	prefix := strings.Split(lines[0][:i], ",")
+	if len(prefix) < 4 {
+		return nil, fmt.Errorf("invalid record prefix %q", lines[0][:i])
+	}
+	prival, err := strconv.ParseUint(prefix[0], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid record priority %q: %v", prefix[0], err)
+	}
+	seq, err := strconv.ParseUint(prefix[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid record sequence %q: %v", prefix[1], err)
+	}
+	usec, err := strconv.ParseInt(prefix[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid record timestamp %q: %v", prefix[2], err)
+	}
+	uptime := time.Duration(usec) * time.Microsecond
+	rec := &pb.DmesgRecord{
+		Timestamp: timestamppb.New(boot.Add(uptime)),
+		Uptime:    durationpb.New(uptime),
+		Facility:  pb.Facility(prival >> 3),
+		Priority:  pb.Priority(prival&7 + 1),
+		Sequence:  seq,
+		Message:   unescapeKmsg(lines[0][i+1:]),
+	}
+	for _, l := range lines[1:] {
+		kv := strings.SplitN(strings.TrimPrefix(l, " "), "=", 2)
+		if !strings.HasPrefix(l, " ") || len(kv) != 2 {
+			return nil, fmt.Errorf("invalid record property %q", l)
+		}
+		if rec.Fields == nil {
+			rec.Fields = make(map[string]string)
+		}
+		rec.Fields[kv[0]] = unescapeKmsg(kv[1])
+	}
+	return rec, nil
+}
+
+// Dmesg implements pb.SysInfoServer.Dmesg
+func (s *server) Dmesg(req *pb.DmesgRequest, stream pb.SysInfo_DmesgServer) error {
+	ctx := stream.Context()
+	if *kmsgPath == "" {
+		return status.Error(codes.Unimplemented, "reading the kernel ring buffer is not supported on this platform")
+	}
+	if _, ok := pb.Priority_name[int32(req.Priority)]; !ok {
+		return status.Errorf(codes.InvalidArgument, "invalid priority %d", req.Priority)
+	}
+	if err := req.Since.CheckValid(); req.Since != nil && err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid since: %v", err)
+	}
+	grep, err := regexp.Compile(req.Grep)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid grep expression: %v", err)
+	}
+
+	boot, err := bootTime()
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't determine boot time: %v", err)
+	}
+	k, err := openKmsg(*kmsgPath)
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't open %s: %v", *kmsgPath, err)
+	}
+	defer k.Close()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		b, err := k.read()
+		if err != nil {
+			return status.Errorf(codes.Internal, "can't read %s: %v", *kmsgPath, err)
+		}
+		if b == nil {
+			if !req.Follow {
+				return nil
+			}
+			if err := k.wait(kmsgPollInterval); err != nil {
+				return status.Errorf(codes.Internal, "can't wait for %s: %v", *kmsgPath, err)
+			}
+			continue
+		}
+		rec, err := parseKmsg(b, boot)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if req.Priority != pb.Priority_PRIORITY_UNKNOWN && rec.Priority > req.Priority {
+			continue
+		}
+		if req.Since != nil && rec.Timestamp.AsTime().Before(req.Since.AsTime()) {
+			continue
+		}
+		if !grep.MatchString(rec.Message) {
+			continue
+		}
+		if err := stream.Send(&pb.DmesgReply{Record: rec}); err != nil {
+			return status.Errorf(codes.Internal, "can't send on stream: %v", err)
+		}
+	}
+}
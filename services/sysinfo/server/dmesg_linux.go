@@ -0,0 +1,81 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// kmsgDevice reads records from /dev/kmsg without blocking.
+type kmsgDevice struct {
+	fd  int
+	buf []byte
+}
+
+func openKmsgDevice(path string) (kmsg, error) {
+	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &kmsgDevice{fd: fd, buf: make([]byte, maxKmsgRecordSize)}, nil
+}
+
+func (k *kmsgDevice) read() ([]byte, error) {
+	for {
+		n, err := unix.Read(k.fd, k.buf)
+		switch err {
+		case nil:
+			return k.buf[:n], nil
+		case unix.EAGAIN:
+			return nil, nil
+		case unix.EPIPE, unix.EINTR:
+			// EPIPE means the next record was overwritten before it was
+			// read. Reading again returns the oldest one remaining and
+			// the gap shows in the sequence numbers.
+			continue
+		default:
+			return nil, err
+		}
+	}
+}
+
+func (k *kmsgDevice) wait(timeout time.Duration) error {
+	fds := []unix.PollFd{{Fd: int32(k.fd), Events: unix.POLLIN}}
+	if _, err := unix.Poll(fds, int(timeout.Milliseconds())); err != nil && err != unix.EINTR {
+		return err
+	}
+	return nil
+}
+
+func (k *kmsgDevice) Close() error {
+	return unix.Close(k.fd)
+}
+
+// systemBootTime returns when the system booted as dmesg does, which
+// means it's off by any time spent suspended.
+func systemBootTime() (time.Time, error) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_BOOTTIME, &ts); err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(-time.Duration(ts.Nano())), nil
+}
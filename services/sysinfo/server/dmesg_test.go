@@ -0,0 +1,276 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// fakeKmsg returns records, then once waited on any in follow.
+type fakeKmsg struct {
+	records []string
+	follow  []string
+	readErr error
+}
+
+func (f *fakeKmsg) read() ([]byte, error) {
+	if f.readErr != nil {
+		return nil, f.readErr
+	}
+	if len(f.records) == 0 {
+		return nil, nil
+	}
+	r := f.records[0]
+	f.records = f.records[1:]
+	return []byte(r), nil
+}
+
+func (f *fakeKmsg) wait(timeout time.Duration) error {
+	f.records = append(f.records, f.follow...)
+	f.follow = nil
+	return nil
+}
+
+func (f *fakeKmsg) Close() error { return nil }
+
+// dmesgStream collects the records sent on a Dmesg stream, cancelling
+// its context once it has max of them.
+type dmesgStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	cancel  func()
+	max     int
+	records []*pb.DmesgRecord
+}
+
+func newDmesgStream(max int) *dmesgStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &dmesgStream{ctx: ctx, cancel: cancel, max: max}
+}
+
+func (d *dmesgStream) Context() context.Context { return d.ctx }
+
+func (d *dmesgStream) Send(r *pb.DmesgReply) error {
+	d.records = append(d.records, r.Record)
+	if len(d.records) == d.max {
+		d.cancel()
+	}
+	return nil
+}
+
+var testBoot = time.Unix(1700000000, 0)
+
+func TestParseKmsg(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		want    *pb.DmesgRecord
+		wantErr bool
+	}{
+		{
+			name:  "simple",
+			input: "6,339,5140900,-;NET: Registered protocol family 10\n",
+			want: &pb.DmesgRecord{
+				Timestamp: timestamppb.New(testBoot.Add(5140900 * time.Microsecond)),
+				Uptime:    durationpb.New(5140900 * time.Microsecond),
+				Facility:  pb.Facility_FACILITY_KERN,
+				Priority:  pb.Priority_PRIORITY_INFO,
+				Sequence:  339,
+				Message:   "NET: Registered protocol family 10",
+			},
+		},
+		{
+			name:  "properties and escapes",
+			input: "27,1000,7000000,c,extra;systemd[1]: a\\x5cb\\x0ac\\xzz\n SUBSYSTEM=pci\n DEVICE=+pci:0000:00:1f.6\\x09\n",
+			want: &pb.DmesgRecord{
+				Timestamp: timestamppb.New(testBoot.Add(7 * time.Second)),
+				Uptime:    durationpb.New(7 * time.Second),
+				Facility:  pb.Facility_FACILITY_DAEMON,
+				Priority:  pb.Priority_PRIORITY_ERR,
+				Sequence:  1000,
+				Message:   "systemd[1]: a\\b\nc\\xzz",
+				Fields: map[string]string{
+					"SUBSYSTEM": "pci",
+					"DEVICE":    "+pci:0000:00:1f.6\t",
+				},
+			},
+		},
+		{
+			name:    "no prefix",
+			input:   "just a message\n",
+			wantErr: true,
+		},
+		{
+			name:    "short prefix",
+			input:   "6,339;message\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad priority",
+			input:   "x,339,1,-;message\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad sequence",
+			input:   "6,x,1,-;message\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad timestamp",
+			input:   "6,339,x,-;message\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad property",
+			input:   "6,339,1,-;message\nSUBSYSTEM=pci\n",
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseKmsg([]byte(tc.input), testBoot)
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("%s: unexpected error state. got %t want %t err %v", tc.name, got, want, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
+
+func TestDmesg(t *testing.T) {
+	savedPath, savedOpen, savedBoot := *kmsgPath, openKmsg, bootTime
+	t.Cleanup(func() {
+		*kmsgPath, openKmsg, bootTime = savedPath, savedOpen, savedBoot
+	})
+	*kmsgPath = "/dev/kmsg"
+	bootTime = func() (time.Time, error) { return testBoot, nil }
+
+	records := []string{
+		"6,1,1000000,-;Linux version 5.15.0\n",
+		"3,2,2000000,-;mce: [Hardware Error]: Machine check events logged\n",
+		"4,3,3000000,-;ata1: link is slow to respond\n SUBSYSTEM=scsi\n",
+		"30,4,4000000,-;systemd[1]: Started Journal Service.\n",
+	}
+	follow := []string{
+		"2,5,5000000,-;EDAC MC0: 1 UE memory read error\n",
+		"6,6,6000000,-;usb 1-1: new device\n",
+	}
+	var want []*pb.DmesgRecord
+	for _, r := range append(append([]string{}, records...), follow...) {
+		rec, err := parseKmsg([]byte(r), testBoot)
+		testutil.FatalOnErr("parsing "+r, err, t)
+		want = append(want, rec)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.DmesgRequest
+		openErr error
+		readErr error
+		max     int
+		want    []*pb.DmesgRecord
+		wantErr codes.Code
+	}{
+		{
+			name: "all",
+			req:  &pb.DmesgRequest{},
+			want: want[:4],
+		},
+		{
+			name: "priority",
+			req:  &pb.DmesgRequest{Priority: pb.Priority_PRIORITY_WARNING},
+			want: want[1:3],
+		},
+		{
+			name: "since",
+			req:  &pb.DmesgRequest{Since: timestamppb.New(testBoot.Add(2500 * time.Millisecond))},
+			want: want[2:4],
+		},
+		{
+			name: "grep",
+			req:  &pb.DmesgRequest{Grep: "(?i)error"},
+			want: want[1:2],
+		},
+		{
+			name:    "follow",
+			req:     &pb.DmesgRequest{Follow: true, Grep: "(?i)error"},
+			max:     2,
+			want:    []*pb.DmesgRecord{want[1], want[4]},
+			wantErr: codes.Canceled,
+		},
+		{
+			name:    "bad priority",
+			req:     &pb.DmesgRequest{Priority: pb.Priority_PRIORITY_DEBUG + 1},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad grep",
+			req:     &pb.DmesgRequest{Grep: "("},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "open fails",
+			req:     &pb.DmesgRequest{},
+			openErr: errors.New("permission denied"),
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "read fails",
+			req:     &pb.DmesgRequest{},
+			readErr: errors.New("invalid argument"),
+			wantErr: codes.Internal,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			openKmsg = func(string) (kmsg, error) {
+				if tc.openErr != nil {
+					return nil, tc.openErr
+				}
+				return &fakeKmsg{records: records, follow: follow, readErr: tc.readErr}, nil
+			}
+			stream := newDmesgStream(tc.max)
+			err := (&server{}).Dmesg(tc.req, stream)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			testutil.DiffErr(tc.name, stream.records, tc.want, t)
+		})
+	}
+
+	// Bad records fail the RPC.
+	openKmsg = func(string) (kmsg, error) { return &fakeKmsg{records: []string{"garbage\n"}}, nil }
+	err := (&server{}).Dmesg(&pb.DmesgRequest{}, newDmesgStream(0))
+	if got, want := status.Code(err), codes.Internal; got != want {
+		t.Fatalf("bad record: got code %v want %v err %v", got, want, err)
+	}
+}
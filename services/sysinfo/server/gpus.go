@@ -0,0 +1,235 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+)
+
+// nvidiaSmiFields are the fields queried from nvidia-smi, in the order
+// parseNvidiaSmi expects them.
+var nvidiaSmiFields = []string{
+	"index",
+	"uuid",
+	"name",
+	"pci.bus_id",
+	"driver_version",
+	"utilization.gpu",
+	"memory.total",
+	"memory.used",
+	"temperature.gpu",
+	"power.draw",
+	"ecc.errors.corrected.volatile.total",
+	"ecc.errors.uncorrected.volatile.total",
+}
+
+// notReported returns true for the values the SMI tools use for fields a
+// GPU doesn't support, i.e. [N/A] or [Not Supported].
+func notReported(v string) bool {
+	v = strings.Trim(v, "[]")
+	return v == "" || v == "N/A" || v == "Not Supported"
+}
+
+// parseGPUFloat parses v, returning -1 if it's not reported.
+func parseGPUFloat(v string) (float64, error) {
+	if notReported(v) {
+		return -1, nil
+	}
+	return strconv.ParseFloat(v, 64)
+}
+
+// parseGPUUint parses v, returning 0 if it's not reported.
+func parseGPUUint(v string) (uint64, error) {
+	if notReported(v) {
+		return 0, nil
+	}
+	return strconv.ParseUint(v, 10, 64)
+}
+
+// parseNvidiaSmi parses the output of nvidia-smi --query-gpu with
+// nvidiaSmiFields and --format=csv,noheader,nounits.
+func parseNvidiaSmi(out string) (*pb.GPUsReply, error) {
+	r := csv.NewReader(strings.NewReader(out))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = len(nvidiaSmiFields)
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("can't parse nvidia-smi output: %v", err)
+	}
+	reply := &pb.GPUsReply{Tool: pb.GPUTool_GPU_TOOL_NVIDIA_SMI}
+	for _, f := range records {
+		index, err := strconv.ParseUint(f[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GPU index %q: %v", f[0], err)
+		}
+		gpu := &pb.GPU{
+			Index:    uint32(index),
+			Uuid:     f[1],
+			Name:     f[2],
+			PciBusId: f[3],
+		}
+		reply.DriverVersion = f[4]
+		busy, err := parseGPUFloat(f[5])
+		if err != nil {
+			return nil, fmt.Errorf("invalid utilization %q: %v", f[5], err)
+		}
+		gpu.Utilization = int32(busy)
+		// Memory is in MiB.
+		if gpu.MemoryTotal, err = parseGPUUint(f[6]); err != nil {
+			return nil, fmt.Errorf("invalid memory total %q: %v", f[6], err)
+		}
+		if gpu.MemoryUsed, err = parseGPUUint(f[7]); err != nil {
+			return nil, fmt.Errorf("invalid memory used %q: %v", f[7], err)
+		}
+		gpu.MemoryTotal *= 1024 * 1024
+		gpu.MemoryUsed *= 1024 * 1024
+		if gpu.Temperature, err = parseGPUFloat(f[8]); err != nil {
+			return nil, fmt.Errorf("invalid temperature %q: %v", f[8], err)
+		}
+		if gpu.PowerDraw, err = parseGPUFloat(f[9]); err != nil {
+			return nil, fmt.Errorf("invalid power draw %q: %v", f[9], err)
+		}
+		if !notReported(f[10]) || !notReported(f[11]) {
+			gpu.EccErrors = &pb.ECCErrors{}
+			if gpu.EccErrors.Corrected, err = parseGPUUint(f[10]); err != nil {
+				return nil, fmt.Errorf("invalid corrected ECC errors %q: %v", f[10], err)
+			}
+			if gpu.EccErrors.Uncorrected, err = parseGPUUint(f[11]); err != nil {
+				return nil, fmt.Errorf("invalid uncorrected ECC errors %q: %v", f[11], err)
+			}
+		}
+		reply.Gpus = append(reply.Gpus, gpu)
+	}
+	return reply, nil
+}
+
+// parseRocmSmi parses the JSON output of rocm-smi with the flags used by
+// rocmSmiStatus. It's an object with a cardN object per GPU and a system
+// object, all with string values keyed by their description.
+func parseRocmSmi(out string) (*pb.GPUsReply, error) {
+	var cards map[string]map[string]string
+	if err := json.Unmarshal([]byte(out), &cards); err != nil {
+		return nil, fmt.Errorf("can't parse rocm-smi output: %v", err)
+	}
+	reply := &pb.GPUsReply{
+		Tool:          pb.GPUTool_GPU_TOOL_ROCM_SMI,
+		DriverVersion: cards["system"]["Driver version"],
+	}
+	for card, vals := range cards {
+		if !strings.HasPrefix(card, "card") {
+			continue
+		}
+		index, err := strconv.ParseUint(strings.TrimPrefix(card, "card"), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid card %q: %v", card, err)
+		}
+		gpu := &pb.GPU{
+			Index:    uint32(index),
+			Uuid:     vals["Unique ID"],
+			Name:     vals["Card series"],
+			PciBusId: vals["PCI Bus"],
+		}
+		busy, err := parseGPUFloat(vals["GPU use (%)"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid utilization %q: %v", vals["GPU use (%)"], err)
+		}
+		gpu.Utilization = int32(busy)
+		if gpu.MemoryTotal, err = parseGPUUint(vals["VRAM Total Memory (B)"]); err != nil {
+			return nil, fmt.Errorf("invalid memory total %q: %v", vals["VRAM Total Memory (B)"], err)
+		}
+		if gpu.MemoryUsed, err = parseGPUUint(vals["VRAM Total Used Memory (B)"]); err != nil {
+			return nil, fmt.Errorf("invalid memory used %q: %v", vals["VRAM Total Used Memory (B)"], err)
+		}
+		gpu.Temperature, gpu.PowerDraw = -1, -1
+		// The sensors and power readings available vary by GPU, so use
+		// the edge sensor (as rocm-smi does by default) falling back to
+		// junction, and whichever package power is reported.
+		for _, k := range []string{"Temperature (Sensor edge) (C)", "Temperature (Sensor junction) (C)"} {
+			if v, ok := vals[k]; ok && !notReported(v) {
+				if gpu.Temperature, err = parseGPUFloat(v); err != nil {
+					return nil, fmt.Errorf("invalid temperature %q: %v", v, err)
+				}
+				break
+			}
+		}
+		for _, k := range []string{"Average Graphics Package Power (W)", "Current Socket Graphics Package Power (W)"} {
+			if v, ok := vals[k]; ok && !notReported(v) {
+				if gpu.PowerDraw, err = parseGPUFloat(v); err != nil {
+					return nil, fmt.Errorf("invalid power draw %q: %v", v, err)
+				}
+				break
+			}
+		}
+		reply.Gpus = append(reply.Gpus, gpu)
+	}
+	sort.Slice(reply.Gpus, func(i, j int) bool { return reply.Gpus[i].Index < reply.Gpus[j].Index })
+	return reply, nil
+}
+
+func nvidiaSmiStatus(ctx context.Context) (*pb.GPUsReply, error) {
+	out, err := runTool(ctx, *nvidiaSmiBin, "--query-gpu="+strings.Join(nvidiaSmiFields, ","), "--format=csv,noheader,nounits")
+	if err != nil {
+		return nil, err
+	}
+	return parseNvidiaSmi(out)
+}
+
+func rocmSmiStatus(ctx context.Context) (*pb.GPUsReply, error) {
+	out, err := runTool(ctx, *rocmSmiBin, "--showuniqueid", "--showproductname", "--showbus", "--showuse", "--showmeminfo", "vram", "--showtemp", "--showpower", "--showdriverversion", "--json")
+	if err != nil {
+		return nil, err
+	}
+	return parseRocmSmi(out)
+}
+
+// GPUs implements pb.SysInfoServer.GPUs
+func (s *server) GPUs(ctx context.Context, req *pb.GPUsRequest) (*pb.GPUsReply, error) {
+	var errs []string
+	for _, t := range []struct {
+		name   string
+		status func(context.Context) (*pb.GPUsReply, error)
+	}{
+		{"nvidia-smi", nvidiaSmiStatus},
+		{"rocm-smi", rocmSmiStatus},
+	} {
+		reply, err := t.status(ctx)
+		if err == nil {
+			return reply, nil
+		}
+		if !errors.Is(err, errNotInstalled) {
+			errs = append(errs, fmt.Sprintf("%s: %v", t.name, err))
+		}
+	}
+	// A tool which is installed but fails usually means the driver isn't
+	// loaded or a GPU has fallen off the bus, which is worth reporting.
+	if len(errs) > 0 {
+		return nil, status.Errorf(codes.Unavailable, "no GPU status available: %s", strings.Join(errs, "; "))
+	}
+	return &pb.GPUsReply{Tool: pb.GPUTool_GPU_TOOL_NONE}, nil
+}
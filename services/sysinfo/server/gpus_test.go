@@ -0,0 +1,223 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+var (
+	nvidiaReply = &pb.GPUsReply{
+		Tool:          pb.GPUTool_GPU_TOOL_NVIDIA_SMI,
+		DriverVersion: "515.65.01",
+		Gpus: []*pb.GPU{
+			{
+				Index:       0,
+				Uuid:        "GPU-5f0d6f4e-1a2b-3c4d-5e6f-0123456789ab",
+				Name:        "NVIDIA A100-SXM4-40GB",
+				PciBusId:    "00000000:07:00.0",
+				Utilization: 37,
+				MemoryTotal: 40960 << 20,
+				MemoryUsed:  1024 << 20,
+				Temperature: 34,
+				PowerDraw:   54.12,
+				EccErrors:   &pb.ECCErrors{},
+			},
+			{
+				Index:       1,
+				Uuid:        "GPU-9b8a7c6d-1a2b-3c4d-5e6f-0123456789ab",
+				Name:        "NVIDIA A100-SXM4-40GB",
+				PciBusId:    "00000000:0F:00.0",
+				Utilization: 100,
+				MemoryTotal: 40960 << 20,
+				MemoryUsed:  39000 << 20,
+				Temperature: 71,
+				PowerDraw:   398.5,
+				EccErrors:   &pb.ECCErrors{Corrected: 12, Uncorrected: 1},
+			},
+			{
+				Index:       2,
+				Uuid:        "GPU-00000000-1a2b-3c4d-5e6f-0123456789ab",
+				Name:        "Tesla T4",
+				PciBusId:    "00000000:87:00.0",
+				Utilization: -1,
+				MemoryTotal: 15360 << 20,
+				Temperature: -1,
+				PowerDraw:   -1,
+			},
+		},
+	}
+	rocmReply = &pb.GPUsReply{
+		Tool:          pb.GPUTool_GPU_TOOL_ROCM_SMI,
+		DriverVersion: "5.13.20.22.10",
+		Gpus: []*pb.GPU{
+			{
+				Index:       0,
+				Uuid:        "0x9a1f6c5a2b3c4d5e",
+				Name:        "AMD INSTINCT MI250 (MCM) OAM AC MBA",
+				PciBusId:    "0000:C1:00.0",
+				Utilization: 12,
+				MemoryTotal: 68702699520,
+				MemoryUsed:  2147483648,
+				Temperature: 38,
+				PowerDraw:   89,
+			},
+			{
+				Index:       1,
+				Uuid:        "0x1f3a5c7e9b0d2468",
+				Name:        "AMD INSTINCT MI250 (MCM) OAM AC MBA",
+				PciBusId:    "0000:C5:00.0",
+				MemoryTotal: 68702699520,
+				MemoryUsed:  10915840,
+				Temperature: 41,
+				PowerDraw:   -1,
+			},
+		},
+	}
+)
+
+func TestParseGPUs(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		file  string
+		parse func(string) (*pb.GPUsReply, error)
+		want  *pb.GPUsReply
+	}{
+		{name: "nvidia-smi", file: "./testdata/nvidia-smi.csv", parse: parseNvidiaSmi, want: nvidiaReply},
+		{name: "rocm-smi", file: "./testdata/rocm-smi.json", parse: parseRocmSmi, want: rocmReply},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := os.ReadFile(tc.file)
+			testutil.FatalOnErr("reading testdata", err, t)
+			got, err := tc.parse(string(out))
+			testutil.FatalOnErr(tc.name, err, t)
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+
+	for _, bad := range []struct {
+		parse func(string) (*pb.GPUsReply, error)
+		out   string
+	}{
+		{parseNvidiaSmi, "0, GPU-1, Tesla T4"},
+		{parseNvidiaSmi, "0, GPU-1, Tesla T4, 0:0, 1, busy, 1, 1, 1, 1, 0, 0"},
+		{parseRocmSmi, "WARNING: No AMD GPUs specified"},
+		{parseRocmSmi, `{"cardX": {}}`},
+	} {
+		if _, err := bad.parse(bad.out); err == nil {
+			t.Errorf("parsing %q didn't fail", bad.out)
+		}
+	}
+}
+
+// fakeSmi writes a script to dir printing file, or failing if file is
+// empty.
+func fakeSmi(t *testing.T, dir string, name string, file string) string {
+	t.Helper()
+	script := "#!/bin/sh\necho \"NVIDIA-SMI has failed because it couldn't communicate with the NVIDIA driver.\"\nexit 9\n"
+	if file != "" {
+		out, err := filepath.Abs(file)
+		testutil.FatalOnErr("output path", err, t)
+		script = fmt.Sprintf("#!/bin/sh\n%s %s\n", testutil.ResolvePath(t, "cat"), out)
+	}
+	bin := filepath.Join(dir, name)
+	testutil.FatalOnErr("writing "+name, os.WriteFile(bin, []byte(script), 0755), t)
+	return bin
+}
+
+func TestGPUs(t *testing.T) {
+	savedNvidiaSmi, savedRocmSmi := *nvidiaSmiBin, *rocmSmiBin
+	t.Cleanup(func() {
+		*nvidiaSmiBin, *rocmSmiBin = savedNvidiaSmi, savedRocmSmi
+	})
+
+	const (
+		missing = "missing"
+		failing = ""
+	)
+	for _, tc := range []struct {
+		name      string
+		nvidiaSmi string
+		rocmSmi   string
+		want      *pb.GPUsReply
+		wantErr   codes.Code
+	}{
+		{
+			name:      "nvidia",
+			nvidiaSmi: "./testdata/nvidia-smi.csv",
+			rocmSmi:   missing,
+			want:      nvidiaReply,
+		},
+		{
+			name:      "amd",
+			nvidiaSmi: missing,
+			rocmSmi:   "./testdata/rocm-smi.json",
+			want:      rocmReply,
+		},
+		{
+			name:      "none installed",
+			nvidiaSmi: missing,
+			rocmSmi:   missing,
+			want:      &pb.GPUsReply{},
+		},
+		{
+			name:      "driver not loaded",
+			nvidiaSmi: failing,
+			rocmSmi:   missing,
+			wantErr:   codes.Unavailable,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, tool := range []struct {
+				flag *string
+				name string
+				file string
+			}{
+				{nvidiaSmiBin, "nvidia-smi", tc.nvidiaSmi},
+				{rocmSmiBin, "rocm-smi", tc.rocmSmi},
+			} {
+				if tool.file == missing {
+					*tool.flag = filepath.Join(dir, tool.name)
+					continue
+				}
+				*tool.flag = fakeSmi(t, dir, tool.name, tool.file)
+			}
+			s := &server{}
+			got, err := s.GPUs(context.Background(), &pb.GPUsRequest{})
+			if status.Code(err) != tc.wantErr {
+				t.Fatalf("GPUs: got error %v, want code %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
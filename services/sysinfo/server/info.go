@@ -0,0 +1,110 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+)
+
+var (
+	// osReleaseFiles are checked in order for os-release(5).
+	osReleaseFiles = []string{"/etc/os-release", "/usr/lib/os-release"}
+	// meminfoFile reports memory usage.
+	meminfoFile = "/proc/meminfo"
+)
+
+// parseOSRelease parses an os-release(5) file, which is a list of shell
+// style assignments such as:
+//
+//	ID=ubuntu
+//	PRETTY_NAME="Ubuntu 22.04.3 LTS"
+func parseOSRelease(r io.Reader) (*pb.OSRelease, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid os-release line %q", line)
+		}
+		v := kv[1]
+		if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+			if u, err := strconv.Unquote(`"` + v[1:len(v)-1] + `"`); err == nil {
+				v = u
+			} else {
+				v = v[1 : len(v)-1]
+			}
+		}
+		values[kv[0]] = v
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &pb.OSRelease{
+		Id:         values["ID"],
+		VersionId:  values["VERSION_ID"],
+		PrettyName: values["PRETTY_NAME"],
+	}, nil
+}
+
+// parseMeminfo parses /proc/meminfo returning each value in bytes.
+// Lines look like:
+//
+//	MemTotal:       16314848 kB
+func parseMeminfo(r io.Reader) (map[string]uint64, error) {
+	out := make(map[string]uint64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 || len(fields) > 3 || !strings.HasSuffix(fields[0], ":") {
+			return nil, fmt.Errorf("invalid meminfo line %q", scanner.Text())
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid meminfo value %q: %v", scanner.Text(), err)
+		}
+		if len(fields) == 3 {
+			if fields[2] != "kB" {
+				return nil, fmt.Errorf("unknown meminfo unit %q", scanner.Text())
+			}
+			v *= 1024
+		}
+		out[strings.TrimSuffix(fields[0], ":")] = v
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Info implements pb.SysInfoServer.Info
+func (s *server) Info(ctx context.Context, req *pb.InfoRequest) (*pb.InfoReply, error) {
+	return systemInfo()
+}
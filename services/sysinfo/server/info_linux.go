@@ -0,0 +1,104 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+)
+
+var (
+	// Replaceable for tests.
+	uname   = unix.Uname
+	sysinfo = unix.Sysinfo
+)
+
+// loadScale is the fixed point scale of load averages from sysinfo(2).
+const loadScale = 1 << unix.SI_LOAD_SHIFT
+
+func systemInfo() (*pb.InfoReply, error) {
+	boot, err := bootTime()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't determine boot time: %v", err)
+	}
+	var uts unix.Utsname
+	if err := uname(&uts); err != nil {
+		return nil, status.Errorf(codes.Internal, "uname failed: %v", err)
+	}
+	var si unix.Sysinfo_t
+	if err := sysinfo(&si); err != nil {
+		return nil, status.Errorf(codes.Internal, "sysinfo failed: %v", err)
+	}
+
+	reply := &pb.InfoReply{
+		Uptime:        durationpb.New(time.Since(boot)),
+		BootTime:      timestamppb.New(boot),
+		KernelName:    unix.ByteSliceToString(uts.Sysname[:]),
+		KernelRelease: unix.ByteSliceToString(uts.Release[:]),
+		KernelVersion: unix.ByteSliceToString(uts.Version[:]),
+		Architecture:  unix.ByteSliceToString(uts.Machine[:]),
+		LoadAverage: &pb.LoadAverage{
+			OneMinute:      float64(si.Loads[0]) / loadScale,
+			FiveMinutes:    float64(si.Loads[1]) / loadScale,
+			FifteenMinutes: float64(si.Loads[2]) / loadScale,
+		},
+		CpuCount: int32(runtime.NumCPU()),
+	}
+
+	for _, path := range osReleaseFiles {
+		f, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't open %s: %v", path, err)
+		}
+		reply.OsRelease, err = parseOSRelease(f)
+		f.Close()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can't parse %s: %v", path, err)
+		}
+		break
+	}
+
+	f, err := os.Open(meminfoFile)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't open %s: %v", meminfoFile, err)
+	}
+	defer f.Close()
+	mem, err := parseMeminfo(f)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't parse %s: %v", meminfoFile, err)
+	}
+	reply.MemoryTotal = mem["MemTotal"]
+	reply.MemoryFree = mem["MemFree"]
+	reply.MemoryAvailable = mem["MemAvailable"]
+	return reply, nil
+}
@@ -0,0 +1,162 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestInfo(t *testing.T) {
+	savedBoot, savedUname, savedSysinfo, savedOSRelease, savedMeminfo := bootTime, uname, sysinfo, osReleaseFiles, meminfoFile
+	t.Cleanup(func() {
+		bootTime, uname, sysinfo, osReleaseFiles, meminfoFile = savedBoot, savedUname, savedSysinfo, savedOSRelease, savedMeminfo
+	})
+
+	boot := time.Now().Add(-time.Hour).Truncate(time.Second)
+	goodUname := func(u *unix.Utsname) error {
+		copy(u.Sysname[:], "Linux")
+		copy(u.Release[:], "5.15.0-86-generic")
+		copy(u.Version[:], "#96-Ubuntu SMP Wed Sep 20 08:23:49 UTC 2023")
+		copy(u.Machine[:], "x86_64")
+		return nil
+	}
+	goodSysinfo := func(si *unix.Sysinfo_t) error {
+		si.Loads = [3]uint64{loadScale / 2, loadScale, 3 * loadScale}
+		return nil
+	}
+	want := &pb.InfoReply{
+		BootTime:        timestamppb.New(boot),
+		KernelName:      "Linux",
+		KernelRelease:   "5.15.0-86-generic",
+		KernelVersion:   "#96-Ubuntu SMP Wed Sep 20 08:23:49 UTC 2023",
+		Architecture:    "x86_64",
+		OsRelease:       &pb.OSRelease{Id: "ubuntu", VersionId: "22.04", PrettyName: "Ubuntu 22.04.3 LTS"},
+		MemoryTotal:     16314848 * 1024,
+		MemoryFree:      1234568 * 1024,
+		MemoryAvailable: 8765432 * 1024,
+		LoadAverage:     &pb.LoadAverage{OneMinute: 0.5, FiveMinutes: 1, FifteenMinutes: 3},
+		CpuCount:        int32(runtime.NumCPU()),
+	}
+	noOSRelease := proto.Clone(want).(*pb.InfoReply)
+	noOSRelease.OsRelease = nil
+
+	for _, tc := range []struct {
+		name      string
+		bootErr   error
+		unameErr  error
+		sysErr    error
+		osRelease []string
+		meminfo   string
+		want      *pb.InfoReply
+		wantErr   codes.Code
+	}{
+		{
+			name:      "good",
+			osRelease: []string{filepath.Join(t.TempDir(), "missing"), "./testdata/os-release"},
+			meminfo:   "./testdata/meminfo",
+			want:      want,
+		},
+		{
+			name:    "no os-release",
+			meminfo: "./testdata/meminfo",
+			want:    noOSRelease,
+		},
+		{
+			name:      "bad os-release",
+			osRelease: []string{"./testdata/meminfo"},
+			meminfo:   "./testdata/meminfo",
+			wantErr:   codes.Internal,
+		},
+		{
+			name:    "bad meminfo",
+			meminfo: "./testdata/os-release",
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "missing meminfo",
+			meminfo: filepath.Join(t.TempDir(), "missing"),
+			wantErr: codes.Internal,
+		},
+		{
+			name:    "boot time fails",
+			bootErr: errors.New("no clock"),
+			meminfo: "./testdata/meminfo",
+			wantErr: codes.Internal,
+		},
+		{
+			name:     "uname fails",
+			unameErr: errors.New("no uname"),
+			meminfo:  "./testdata/meminfo",
+			wantErr:  codes.Internal,
+		},
+		{
+			name:    "sysinfo fails",
+			sysErr:  errors.New("no sysinfo"),
+			meminfo: "./testdata/meminfo",
+			wantErr: codes.Internal,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			bootTime = func() (time.Time, error) { return boot, tc.bootErr }
+			uname = func(u *unix.Utsname) error {
+				if tc.unameErr != nil {
+					return tc.unameErr
+				}
+				return goodUname(u)
+			}
+			sysinfo = func(si *unix.Sysinfo_t) error {
+				if tc.sysErr != nil {
+					return tc.sysErr
+				}
+				return goodSysinfo(si)
+			}
+			osReleaseFiles = tc.osRelease
+			meminfoFile = tc.meminfo
+
+			got, err := (&server{}).Info(context.Background(), &pb.InfoRequest{})
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if up := got.Uptime.AsDuration(); up < time.Hour || up > 2*time.Hour {
+				t.Errorf("unexpected uptime %v", up)
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t, protocmp.IgnoreFields(&pb.InfoReply{}, "uptime"))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestParseOSRelease(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		want    *pb.OSRelease
+		wantErr bool
+	}{
+		{
+			name:  "quoted",
+			input: "ID=\"rhel\"\nVERSION_ID='8.6'\nPRETTY_NAME=\"Red Hat \\\"Enterprise\\\" Linux\"\n",
+			want:  &pb.OSRelease{Id: "rhel", VersionId: "8.6", PrettyName: `Red Hat "Enterprise" Linux`},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  &pb.OSRelease{},
+		},
+		{
+			name:    "bad line",
+			input:   "ID=ubuntu\nnonsense\n",
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseOSRelease(strings.NewReader(tc.input))
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
+
+func TestParseMeminfo(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		want    map[string]uint64
+		wantErr bool
+	}{
+		{
+			name:  "units",
+			input: "MemTotal:       1024 kB\nHugePages_Total:       3\n\n",
+			want:  map[string]uint64{"MemTotal": 1024 * 1024, "HugePages_Total": 3},
+		},
+		{
+			name:    "bad value",
+			input:   "MemTotal:       lots kB\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad unit",
+			input:   "MemTotal:       1024 MB\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad line",
+			input:   "MemTotal 1024 kB\n",
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseMeminfo(strings.NewReader(tc.input))
+			testutil.WantErr(tc.name, err, tc.wantErr, t)
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
@@ -0,0 +1,296 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const (
+	// defaultJournalLimit is how many entries are returned if the request
+	// doesn't say.
+	defaultJournalLimit = 100
+	// maxJournalLimit is the most entries a request can ask for.
+	maxJournalLimit = 10000
+	// maxJournalEntrySize is the largest JSON encoded entry accepted from
+	// journalctl.
+	maxJournalEntrySize = 1024 * 1024
+)
+
+// journalctlArgs returns the arguments to run journalctl with for req.
+// Normally entries are returned newest first so reading can stop once
+// enough have been found. If follow is set only entries after cursor
+// (or new ones if empty) are returned as they're written.
+//
+// Times are only given to the second so entries must still be checked
+// against the request.
+func journalctlArgs(req *pb.JournalRequest, follow bool, cursor string) []string {
+	args := []string{"--output=json", "--no-pager", "--quiet"}
+	if req.Unit != "" {
+		args = append(args, "--unit", req.Unit)
+	}
+	if req.Priority != pb.Priority_PRIORITY_UNKNOWN {
+		args = append(args, fmt.Sprintf("--priority=%d", req.Priority-1))
+	}
+	if follow {
+		args = append(args, "--follow")
+		if cursor != "" {
+			return append(args, "--after-cursor="+cursor)
+		}
+		return append(args, "--lines=0")
+	}
+	args = append(args, "--reverse")
+	if req.Since != nil {
+		args = append(args, fmt.Sprintf("--since=@%d", req.Since.AsTime().Unix()))
+	}
+	if req.Until != nil {
+		// Round up so the last second is included.
+		until := req.Until.AsTime()
+		secs := until.Unix()
+		if until.Nanosecond() > 0 {
+			secs++
+		}
+		args = append(args, fmt.Sprintf("--until=@%d", secs))
+	}
+	return args
+}
+
+// journalValue returns a field value from journalctl's JSON output. Values
+// are strings, arrays of bytes for binary data or arrays of either if the
+// entry has the field more than once, in which case the first is used.
+func journalValue(raw json.RawMessage) (string, error) {
+	var s *string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == nil {
+			return "", nil
+		}
+		return *s, nil
+	}
+	var b []byte
+	var nums []int
+	if err := json.Unmarshal(raw, &nums); err == nil {
+		for _, n := range nums {
+			b = append(b, byte(n))
+		}
+		return string(b), nil
+	}
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return "", fmt.Errorf("unexpected value %s", raw)
+	}
+	if len(values) == 0 {
+		return "", nil
+	}
+	return journalValue(values[0])
+}
+
+// parseJournalEntry parses one line of journalctl JSON output.
+func parseJournalEntry(line []byte, allFields bool) (*pb.JournalRecord, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return nil, fmt.Errorf("can't parse journal entry: %v", err)
+	}
+	fields := make(map[string]string, len(raw))
+	for k, v := range raw {
+		s, err := journalValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", k, err)
+		}
+		fields[k] = s
+	}
+
+	rec := &pb.JournalRecord{
+		Hostname:         fields["_HOSTNAME"],
+		SyslogIdentifier: fields["SYSLOG_IDENTIFIER"],
+		Unit:             fields["_SYSTEMD_UNIT"],
+		Message:          fields["MESSAGE"],
+		Cursor:           fields["__CURSOR"],
+	}
+	if rec.Unit == "" {
+		// Messages from systemd about a unit.
+		rec.Unit = fields["UNIT"]
+	}
+	if v := fields["__REALTIME_TIMESTAMP"]; v != "" {
+		usec, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q: %v", v, err)
+		}
+		rec.RealtimeTimestamp = timestamppb.New(time.UnixMicro(usec))
+	}
+	if v := fields["_PID"]; v != "" {
+		pid, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pid %q: %v", v, err)
+		}
+		rec.Pid = int32(pid)
+	}
+	if v := fields["PRIORITY"]; v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil || p < 0 || p > 7 {
+			return nil, fmt.Errorf("invalid priority %q", v)
+		}
+		rec.Priority = pb.Priority(p + 1)
+	}
+	if allFields {
+		rec.Fields = fields
+	}
+	return rec, nil
+}
+
+// readJournal runs journalctl with args calling f for each entry until it
+// returns true or there are no more. Errors returned by f are returned as is.
+func readJournal(ctx context.Context, args []string, allFields bool, f func(*pb.JournalRecord) (bool, error)) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, *journalctlBin, args...)
+	// As with util.RunCommand nothing is inherited.
+	cmd.Env = []string{}
+	stderr := util.NewLimitedBuffer(util.DefRunBufLimit)
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return status.Errorf(codes.Internal, "can't create pipe: %v", err)
+	}
+	logr.FromContextOrDiscard(ctx).Info("executing local command", "cmd", cmd.String())
+	if err := cmd.Start(); err != nil {
+		return status.Errorf(codes.Internal, "can't run journalctl: %v", err)
+	}
+
+	var ferr error
+	stopped := false
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), maxJournalEntrySize)
+	for scanner.Scan() {
+		rec, err := parseJournalEntry(scanner.Bytes(), allFields)
+		if err != nil {
+			ferr = status.Error(codes.Internal, err.Error())
+			break
+		}
+		if stopped, ferr = f(rec); stopped || ferr != nil {
+			break
+		}
+	}
+	if ferr == nil && !stopped {
+		if err := scanner.Err(); err != nil {
+			ferr = status.Errorf(codes.Internal, "can't read journalctl output: %v", err)
+		}
+	}
+
+	// Stop journalctl if it's still running (i.e. following).
+	cancel()
+	werr := cmd.Wait()
+	switch {
+	case ferr != nil:
+		return ferr
+	case stopped:
+		return nil
+	case ctx.Err() != nil:
+		return status.FromContextError(ctx.Err()).Err()
+	case werr != nil:
+		return status.Errorf(codes.Internal, "error from journalctl: %v\nstderr:\n%s", werr, util.TrimString(stderr.String()))
+	}
+	return nil
+}
+
+// Journal implements pb.SysInfoServer.Journal
+func (s *server) Journal(req *pb.JournalRequest, stream pb.SysInfo_JournalServer) error {
+	ctx := stream.Context()
+	if *journalctlBin == "" {
+		return status.Error(codes.Unimplemented, "reading the journal is not supported on this platform")
+	}
+	if req.Limit < 0 || req.Limit > maxJournalLimit {
+		return status.Errorf(codes.InvalidArgument, "limit must be between 0 and %d", maxJournalLimit)
+	}
+	if req.Follow && req.Until != nil {
+		return status.Error(codes.InvalidArgument, "until can't be set when following")
+	}
+	if _, ok := pb.Priority_name[int32(req.Priority)]; !ok {
+		return status.Errorf(codes.InvalidArgument, "invalid priority %d", req.Priority)
+	}
+	if err := req.Since.CheckValid(); req.Since != nil && err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid since: %v", err)
+	}
+	if err := req.Until.CheckValid(); req.Until != nil && err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid until: %v", err)
+	}
+	grep, err := regexp.Compile(req.Grep)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid grep expression: %v", err)
+	}
+
+	match := func(rec *pb.JournalRecord) bool {
+		if t := rec.RealtimeTimestamp.AsTime(); (req.Since != nil && t.Before(req.Since.AsTime())) || (req.Until != nil && t.After(req.Until.AsTime())) {
+			return false
+		}
+		return grep.MatchString(rec.Message)
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 && !req.Follow {
+		limit = defaultJournalLimit
+	}
+	var backlog []*pb.JournalRecord
+	if limit > 0 {
+		err := readJournal(ctx, journalctlArgs(req, false, ""), req.AllFields, func(rec *pb.JournalRecord) (bool, error) {
+			if match(rec) {
+				backlog = append(backlog, rec)
+			}
+			return len(backlog) == limit, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// The backlog was read newest first.
+	cursor := ""
+	for i := len(backlog) - 1; i >= 0; i-- {
+		if err := stream.Send(&pb.JournalReply{Record: backlog[i]}); err != nil {
+			return status.Errorf(codes.Internal, "can't send on stream: %v", err)
+		}
+		cursor = backlog[i].Cursor
+	}
+	if !req.Follow {
+		return nil
+	}
+
+	return readJournal(ctx, journalctlArgs(req, true, cursor), req.AllFields, func(rec *pb.JournalRecord) (bool, error) {
+		if !match(rec) {
+			return false, nil
+		}
+		if err := stream.Send(&pb.JournalReply{Record: rec}); err != nil {
+			return false, status.Errorf(codes.Internal, "can't send on stream: %v", err)
+		}
+		return false, nil
+	})
+}
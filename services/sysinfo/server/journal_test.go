@@ -0,0 +1,286 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// journalStream collects the records sent on a Journal stream, cancelling
+// its context once it has max of them.
+type journalStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	cancel  func()
+	max     int
+	records []*pb.JournalRecord
+}
+
+func newJournalStream(max int) *journalStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &journalStream{ctx: ctx, cancel: cancel, max: max}
+}
+
+func (j *journalStream) Context() context.Context { return j.ctx }
+
+func (j *journalStream) Send(r *pb.JournalReply) error {
+	j.records = append(j.records, r.Record)
+	if len(j.records) == j.max {
+		j.cancel()
+	}
+	return nil
+}
+
+// fakeJournalctl writes a script to dir which acts like journalctl over the
+// testdata, writing its arguments when following to dir/args.
+func fakeJournalctl(t *testing.T, dir string) string {
+	t.Helper()
+	journal, err := filepath.Abs("./testdata/journal.json")
+	testutil.FatalOnErr("journal path", err, t)
+	follow, err := filepath.Abs("./testdata/journal-follow.json")
+	testutil.FatalOnErr("journal path", err, t)
+	script := fmt.Sprintf(`#!/bin/sh
+for a in "$@"; do
+  case "$a" in
+  --reverse) exec %[1]s %[3]s ;;
+  --follow) echo "$@" > %[5]s; %[2]s %[4]s; exec %[6]s 60 ;;
+  esac
+done
+exec %[2]s %[3]s
+`, testutil.ResolvePath(t, "tac"), testutil.ResolvePath(t, "cat"), journal, follow, filepath.Join(dir, "args"), testutil.ResolvePath(t, "sleep"))
+	bin := filepath.Join(dir, "journalctl")
+	testutil.FatalOnErr("writing journalctl", os.WriteFile(bin, []byte(script), 0755), t)
+	return bin
+}
+
+func TestJournalctlArgs(t *testing.T) {
+	since := timestamppb.New(time.Unix(1700000000, 500))
+	until := timestamppb.New(time.Unix(1700000100, 0))
+	for _, tc := range []struct {
+		name   string
+		req    *pb.JournalRequest
+		follow bool
+		cursor string
+		want   string
+	}{
+		{
+			name: "defaults",
+			req:  &pb.JournalRequest{},
+			want: "--output=json --no-pager --quiet --reverse",
+		},
+		{
+			name: "filters",
+			req:  &pb.JournalRequest{Unit: "nginx.service", Priority: pb.Priority_PRIORITY_ERR, Since: since, Until: until},
+			want: "--output=json --no-pager --quiet --unit nginx.service --priority=3 --reverse --since=@1700000000 --until=@1700000100",
+		},
+		{
+			name:   "follow",
+			req:    &pb.JournalRequest{Unit: "nginx.service", Since: since, Follow: true},
+			follow: true,
+			want:   "--output=json --no-pager --quiet --unit nginx.service --follow --lines=0",
+		},
+		{
+			name:   "follow after cursor",
+			req:    &pb.JournalRequest{Priority: pb.Priority_PRIORITY_EMERG, Follow: true},
+			follow: true,
+			cursor: "s=1;i=5",
+			want:   "--output=json --no-pager --quiet --priority=0 --follow --after-cursor=s=1;i=5",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := strings.Join(journalctlArgs(tc.req, tc.follow, tc.cursor), " ")
+			if got != tc.want {
+				t.Fatalf("got args %q want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJournal(t *testing.T) {
+	dir := t.TempDir()
+	saved := *journalctlBin
+	t.Cleanup(func() { *journalctlBin = saved })
+	*journalctlBin = fakeJournalctl(t, dir)
+
+	ts := func(sec int64) *timestamppb.Timestamp { return timestamppb.New(time.Unix(sec, 0)) }
+	records := []*pb.JournalRecord{
+		{RealtimeTimestamp: ts(1700000000), Hostname: "host1", SyslogIdentifier: "sshd", Pid: 100, Priority: pb.Priority_PRIORITY_INFO, Unit: "ssh.service", Message: "Accepted publickey for alice", Cursor: "s=1;i=1"},
+		{RealtimeTimestamp: ts(1700000001), Hostname: "host1", SyslogIdentifier: "kernel", Priority: pb.Priority_PRIORITY_ERR, Message: "EXT4-fs error (device sda1)", Cursor: "s=1;i=2"},
+		{RealtimeTimestamp: ts(1700000002), Hostname: "host1", SyslogIdentifier: "systemd", Pid: 1, Priority: pb.Priority_PRIORITY_INFO, Unit: "init.scope", Message: "Started nginx.", Cursor: "s=1;i=3"},
+		{RealtimeTimestamp: ts(1700000003), Hostname: "host1", SyslogIdentifier: "nginx", Pid: 200, Priority: pb.Priority_PRIORITY_WARNING, Unit: "nginx.service", Message: "upstream timed out", Cursor: "s=1;i=4"},
+		{RealtimeTimestamp: ts(1700000004), Hostname: "host1", SyslogIdentifier: "app", Pid: 300, Priority: pb.Priority_PRIORITY_DEBUG, Message: "bin\x00ary", Cursor: "s=1;i=5"},
+		{RealtimeTimestamp: ts(1700000005), Hostname: "host1", SyslogIdentifier: "app", Pid: 300, Priority: pb.Priority_PRIORITY_INFO, Message: "first new", Cursor: "s=1;i=6"},
+		{RealtimeTimestamp: ts(1700000006), Hostname: "host1", SyslogIdentifier: "app", Pid: 300, Priority: pb.Priority_PRIORITY_INFO, Message: "second new", Cursor: "s=1;i=7"},
+	}
+	allFields := &pb.JournalRecord{
+		RealtimeTimestamp: records[4].RealtimeTimestamp,
+		Hostname:          "host1",
+		SyslogIdentifier:  "app",
+		Pid:               300,
+		Priority:          pb.Priority_PRIORITY_DEBUG,
+		Message:           "bin\x00ary",
+		Cursor:            "s=1;i=5",
+		Fields: map[string]string{
+			"__CURSOR":             "s=1;i=5",
+			"__REALTIME_TIMESTAMP": "1700000004000000",
+			"_HOSTNAME":            "host1",
+			"SYSLOG_IDENTIFIER":    "app",
+			"_PID":                 "300",
+			"PRIORITY":             "7",
+			"MESSAGE":              "bin\x00ary",
+			"TAG":                  "a",
+			"EMPTY":                "",
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.JournalRequest
+		want    []*pb.JournalRecord
+		wantErr codes.Code
+	}{
+		{
+			name: "all",
+			req:  &pb.JournalRequest{},
+			want: records[:5],
+		},
+		{
+			name: "limit",
+			req:  &pb.JournalRequest{Limit: 2},
+			want: records[3:5],
+		},
+		{
+			name: "grep",
+			req:  &pb.JournalRequest{Grep: "nginx|timed"},
+			want: records[2:4],
+		},
+		{
+			name: "since",
+			req:  &pb.JournalRequest{Since: timestamppb.New(time.Unix(1700000002, 500))},
+			want: records[3:5],
+		},
+		{
+			name: "until",
+			req:  &pb.JournalRequest{Until: ts(1700000001)},
+			want: records[:2],
+		},
+		{
+			name: "all fields",
+			req:  &pb.JournalRequest{Limit: 1, AllFields: true},
+			want: []*pb.JournalRecord{allFields},
+		},
+		{
+			name:    "negative limit",
+			req:     &pb.JournalRequest{Limit: -1},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "large limit",
+			req:     &pb.JournalRequest{Limit: maxJournalLimit + 1},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "follow until",
+			req:     &pb.JournalRequest{Follow: true, Until: ts(1700000001)},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad priority",
+			req:     &pb.JournalRequest{Priority: pb.Priority_PRIORITY_DEBUG + 1},
+			wantErr: codes.InvalidArgument,
+		},
+		{
+			name:    "bad grep",
+			req:     &pb.JournalRequest{Grep: "("},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stream := newJournalStream(-1)
+			err := (&server{}).Journal(tc.req, stream)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, stream.records, tc.want, t)
+		})
+	}
+
+	// Following returns the most recent entry then new ones until cancelled.
+	stream := newJournalStream(3)
+	err := (&server{}).Journal(&pb.JournalRequest{Limit: 1, Follow: true}, stream)
+	if got, want := status.Code(err), codes.Canceled; got != want {
+		t.Fatalf("follow got code %v want %v err %v", got, want, err)
+	}
+	testutil.DiffErr("follow", stream.records, records[4:], t)
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	testutil.FatalOnErr("reading args", err, t)
+	if got, want := strings.TrimSpace(string(args)), "--output=json --no-pager --quiet --follow --after-cursor=s=1;i=5"; got != want {
+		t.Fatalf("follow args got %q want %q", got, want)
+	}
+}
+
+func TestJournalErrors(t *testing.T) {
+	saved := *journalctlBin
+	t.Cleanup(func() { *journalctlBin = saved })
+
+	for _, tc := range []struct {
+		name   string
+		script string
+	}{
+		{
+			name:   "failure",
+			script: "#!/bin/sh\necho no journal >&2\nexit 1\n",
+		},
+		{
+			name:   "bad output",
+			script: "#!/bin/sh\necho not json\n",
+		},
+		{
+			name:   "bad priority",
+			script: "#!/bin/sh\necho '{\"PRIORITY\":\"9\"}'\n",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			*journalctlBin = filepath.Join(t.TempDir(), "journalctl")
+			testutil.FatalOnErr("writing journalctl", os.WriteFile(*journalctlBin, []byte(tc.script), 0755), t)
+			err := (&server{}).Journal(&pb.JournalRequest{}, newJournalStream(-1))
+			if got, want := status.Code(err), codes.Internal; got != want {
+				t.Fatalf("got code %v want %v err %v", got, want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const (
+	// maxDiskUsageEntries limits the directories returned by DiskUsage.
+	maxDiskUsageEntries = 10000
+)
+
+var (
+	// mountinfoFile lists the mounts visible to the server.
+	mountinfoFile = "/proc/self/mountinfo"
+	// statfsTimeout bounds how long to wait for each filesystem's usage, as
+	// statfs(2) can hang on network filesystems.
+	statfsTimeout = 5 * time.Second
+)
+
+// unescapeMount undoes the octal escaping (i.e. \040 for a space) of
+// paths in mountinfo.
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// parseMountinfo parses /proc/<pid>/mountinfo (see proc(5)) where each
+// line looks like:
+//
+//	36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+//
+// with a variable number of optional fields before the separator.
+func parseMountinfo(r io.Reader) ([]*pb.Mount, error) {
+	var mounts []*pb.Mount
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		sep := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 0 || len(fields) < sep+3 {
+			return nil, fmt.Errorf("invalid mountinfo line %q", scanner.Text())
+		}
+		m := &pb.Mount{
+			MountPoint: unescapeMount(fields[4]),
+			FsType:     fields[sep+1],
+			Source:     unescapeMount(fields[sep+2]),
+		}
+		seen := make(map[string]bool)
+		opts := strings.Split(fields[5], ",")
+		if len(fields) > sep+3 {
+			opts = append(opts, strings.Split(fields[sep+3], ",")...)
+		}
+		for _, o := range opts {
+			if o != "" && !seen[o] {
+				seen[o] = true
+				m.Options = append(m.Options, o)
+			}
+		}
+		mounts = append(mounts, m)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mounts, nil
+}
+
+// Mounts implements pb.SysInfoServer.Mounts
+func (s *server) Mounts(ctx context.Context, req *pb.MountsRequest) (*pb.MountsReply, error) {
+	return listMounts(ctx, req)
+}
+
+// DiskUsage implements pb.SysInfoServer.DiskUsage
+func (s *server) DiskUsage(ctx context.Context, req *pb.DiskUsageRequest) (*pb.DiskUsageReply, error) {
+	if err := util.ValidPath(req.Path); err != nil {
+		return nil, err
+	}
+	return diskUsage(ctx, req)
+}
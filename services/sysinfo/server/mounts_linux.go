@@ -0,0 +1,216 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+)
+
+// Replaceable for tests.
+var statfs = unix.Statfs
+
+// filesystemUsage returns the usage of the filesystem mounted at path,
+// giving up after statfsTimeout.
+func filesystemUsage(ctx context.Context, path string) (*pb.FilesystemUsage, error) {
+	ctx, cancel := context.WithTimeout(ctx, statfsTimeout)
+	defer cancel()
+	type result struct {
+		st  unix.Statfs_t
+		err error
+	}
+	// Buffered so the goroutine can exit if statfs returns after we've
+	// given up on it.
+	done := make(chan result, 1)
+	go func() {
+		var r result
+		r.err = statfs(path, &r.st)
+		done <- r
+	}()
+	var r result
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("statfs timed out after %v", statfsTimeout)
+	case r = <-done:
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	bsize := uint64(r.st.Frsize)
+	if bsize == 0 {
+		bsize = uint64(r.st.Bsize)
+	}
+	return &pb.FilesystemUsage{
+		Size:       r.st.Blocks * bsize,
+		Used:       (r.st.Blocks - r.st.Bfree) * bsize,
+		Available:  r.st.Bavail * bsize,
+		Inodes:     r.st.Files,
+		InodesUsed: r.st.Files - r.st.Ffree,
+		InodesFree: r.st.Ffree,
+	}, nil
+}
+
+func listMounts(ctx context.Context, req *pb.MountsRequest) (*pb.MountsReply, error) {
+	f, err := os.Open(mountinfoFile)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read mounts: %v", err)
+	}
+	defer f.Close()
+	mounts, err := parseMountinfo(f)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't parse %s: %v", mountinfoFile, err)
+	}
+
+	var wg sync.WaitGroup
+	for _, m := range mounts {
+		wg.Add(1)
+		go func(m *pb.Mount) {
+			defer wg.Done()
+			usage, err := filesystemUsage(ctx, m.MountPoint)
+			if err != nil {
+				m.UsageError = err.Error()
+				return
+			}
+			m.Usage = usage
+		}(m)
+	}
+	wg.Wait()
+
+	reply := &pb.MountsReply{}
+	for _, m := range mounts {
+		// Mounts with errors are always returned as they may be the
+		// problem being investigated.
+		if req.All || m.Usage == nil || m.Usage.Size != 0 {
+			reply.Mounts = append(reply.Mounts, m)
+		}
+	}
+	return reply, nil
+}
+
+// duWalker totals the usage under a directory.
+type duWalker struct {
+	req     *pb.DiskUsageRequest
+	rootDev uint64
+	// seen has the files with several links which have been counted.
+	seen    map[[2]uint64]bool
+	entries []*pb.DiskUsageEntry
+	errors  uint64
+}
+
+// walk returns the usage of path, adding entries for it and the
+// directories under it down to the requested depth.
+func (w *duWalker) walk(ctx context.Context, path string, fi fs.FileInfo, depth uint32) (*pb.DiskUsageEntry, error) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "no stat information for %s", path)
+	}
+	if w.req.OneFileSystem && st.Dev != w.rootDev {
+		return &pb.DiskUsageEntry{}, nil
+	}
+	if !fi.IsDir() && st.Nlink > 1 {
+		key := [2]uint64{st.Dev, st.Ino}
+		if w.seen[key] {
+			return &pb.DiskUsageEntry{}, nil
+		}
+		w.seen[key] = true
+	}
+	entry := &pb.DiskUsageEntry{
+		Path:         path,
+		Size:         uint64(st.Blocks) * 512,
+		ApparentSize: uint64(fi.Size()),
+		Count:        1,
+	}
+	if !fi.IsDir() {
+		return entry, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	// ReadDir returns what it could read along with any error.
+	dirents, err := os.ReadDir(path)
+	if err != nil {
+		w.errors++
+	}
+	for _, d := range dirents {
+		cfi, err := d.Info()
+		if err != nil {
+			// Removed since the directory was read.
+			if !errors.Is(err, fs.ErrNotExist) {
+				w.errors++
+			}
+			continue
+		}
+		c, err := w.walk(ctx, filepath.Join(path, d.Name()), cfi, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		entry.Size += c.Size
+		entry.ApparentSize += c.ApparentSize
+		entry.Count += c.Count
+	}
+	if depth <= w.req.MaxDepth {
+		if len(w.entries) >= maxDiskUsageEntries {
+			return nil, status.Errorf(codes.ResourceExhausted, "more than %d directories, use a smaller max depth", maxDiskUsageEntries)
+		}
+		w.entries = append(w.entries, entry)
+	}
+	return entry, nil
+}
+
+func diskUsage(ctx context.Context, req *pb.DiskUsageRequest) (*pb.DiskUsageReply, error) {
+	fi, err := os.Lstat(req.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, status.Errorf(codes.NotFound, "%s doesn't exist", req.Path)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't stat %s: %v", req.Path, err)
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "no stat information for %s", req.Path)
+	}
+	w := &duWalker{
+		req:     req,
+		rootDev: st.Dev,
+		seen:    make(map[[2]uint64]bool),
+	}
+	total, err := w.walk(ctx, req.Path, fi, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		w.entries = append(w.entries, total)
+	}
+	sort.Slice(w.entries, func(i, j int) bool { return w.entries[i].Path < w.entries[j].Path })
+	return &pb.DiskUsageReply{Entries: w.entries, Errors: w.errors}, nil
+}
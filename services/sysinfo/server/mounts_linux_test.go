@@ -0,0 +1,217 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestMounts(t *testing.T) {
+	savedStatfs, savedMountinfo, savedTimeout := statfs, mountinfoFile, statfsTimeout
+	t.Cleanup(func() {
+		statfs, mountinfoFile, statfsTimeout = savedStatfs, savedMountinfo, savedTimeout
+	})
+	mountinfoFile = "./testdata/mountinfo"
+	statfsTimeout = 100 * time.Millisecond
+	hang := make(chan struct{})
+	t.Cleanup(func() { close(hang) })
+	statfs = func(path string, st *unix.Statfs_t) error {
+		switch path {
+		case "/":
+			*st = unix.Statfs_t{Bsize: 4096, Frsize: 4096, Blocks: 1000, Bfree: 300, Bavail: 250, Files: 100, Ffree: 40}
+		case "/boot/efi":
+			return unix.EACCES
+		case "/mnt/my share":
+			<-hang
+		default:
+			*st = unix.Statfs_t{Bsize: 4096}
+		}
+		return nil
+	}
+
+	root := &pb.Mount{
+		Source:     "/dev/nvme0n1p2",
+		MountPoint: "/",
+		FsType:     "ext4",
+		Options:    []string{"rw", "relatime", "errors=remount-ro"},
+		Usage:      &pb.FilesystemUsage{Size: 4096000, Used: 2867200, Available: 1024000, Inodes: 100, InodesUsed: 60, InodesFree: 40},
+	}
+	efi := &pb.Mount{
+		Source:     "/dev/nvme0n1p1",
+		MountPoint: "/boot/efi",
+		FsType:     "vfat",
+		Options:    []string{"rw", "relatime", "fmask=0077", "dmask=0077", "codepage=437"},
+		UsageError: unix.EACCES.Error(),
+	}
+	nfs := &pb.Mount{
+		Source:     "server:/export/a b",
+		MountPoint: "/mnt/my share",
+		FsType:     "nfs4",
+		Options:    []string{"rw", "relatime", "vers=4.2", "hard"},
+		UsageError: "statfs timed out after 100ms",
+	}
+	empty := &pb.FilesystemUsage{}
+	for _, tc := range []struct {
+		name string
+		req  *pb.MountsRequest
+		want *pb.MountsReply
+	}{
+		{
+			name: "default",
+			req:  &pb.MountsRequest{},
+			want: &pb.MountsReply{Mounts: []*pb.Mount{root, efi, nfs}},
+		},
+		{
+			name: "all",
+			req:  &pb.MountsRequest{All: true},
+			want: &pb.MountsReply{Mounts: []*pb.Mount{
+				root,
+				{Source: "proc", MountPoint: "/proc", FsType: "proc", Options: []string{"rw", "nosuid", "nodev", "noexec", "relatime"}, Usage: empty},
+				{Source: "sysfs", MountPoint: "/sys", FsType: "sysfs", Options: []string{"rw", "nosuid", "nodev", "noexec", "relatime"}, Usage: empty},
+				efi,
+				nfs,
+			}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&server{}).Mounts(context.Background(), tc.req)
+			testutil.FatalOnErr("Mounts", err, t)
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+
+	mountinfoFile = filepath.Join(t.TempDir(), "missing")
+	if _, err := (&server{}).Mounts(context.Background(), &pb.MountsRequest{}); status.Code(err) != codes.Internal {
+		t.Errorf("Mounts with no mountinfo: got %v want Internal", err)
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, size int) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		testutil.FatalOnErr("mkdir", os.MkdirAll(filepath.Dir(path), 0755), t)
+		testutil.FatalOnErr("write", os.WriteFile(path, make([]byte, size), 0644), t)
+	}
+	write("a/one", 100)
+	write("a/b/two", 200)
+	write("c/three", 300)
+	// Hard links are only counted once.
+	testutil.FatalOnErr("link", os.Link(filepath.Join(dir, "c/three"), filepath.Join(dir, "a/b/three")), t)
+	testutil.FatalOnErr("symlink", os.Symlink("/etc/passwd", filepath.Join(dir, "link")), t)
+
+	dirSize := func(path string) uint64 {
+		fi, err := os.Lstat(path)
+		testutil.FatalOnErr("stat", err, t)
+		return uint64(fi.Size())
+	}
+	apparent := func(dirs ...string) uint64 {
+		var n uint64
+		for _, d := range dirs {
+			n += dirSize(filepath.Join(dir, d))
+		}
+		return n
+	}
+	linkSize := dirSize(filepath.Join(dir, "link"))
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.DiskUsageRequest
+		want    map[string][2]uint64 // path -> apparent size, count
+		wantErr codes.Code
+	}{
+		{
+			name: "total",
+			req:  &pb.DiskUsageRequest{Path: dir},
+			want: map[string][2]uint64{
+				dir: {apparent(".", "a", "a/b", "c") + 600 + linkSize, 8},
+			},
+		},
+		{
+			name: "depth",
+			req:  &pb.DiskUsageRequest{Path: dir, MaxDepth: 1},
+			want: map[string][2]uint64{
+				dir:                     {apparent(".", "a", "a/b", "c") + 600 + linkSize, 8},
+				filepath.Join(dir, "a"): {apparent("a", "a/b") + 600, 5},
+				filepath.Join(dir, "c"): {apparent("c"), 1},
+			},
+		},
+		{
+			name: "file",
+			req:  &pb.DiskUsageRequest{Path: filepath.Join(dir, "a/one"), MaxDepth: 3},
+			want: map[string][2]uint64{
+				filepath.Join(dir, "a/one"): {100, 1},
+			},
+		},
+		{
+			name:    "missing",
+			req:     &pb.DiskUsageRequest{Path: filepath.Join(dir, "missing")},
+			wantErr: codes.NotFound,
+		},
+		{
+			name:    "relative",
+			req:     &pb.DiskUsageRequest{Path: "a"},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := (&server{}).DiskUsage(context.Background(), tc.req)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", got, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			got := make(map[string][2]uint64)
+			var last string
+			for _, e := range resp.Entries {
+				if e.Path < last {
+					t.Errorf("%s sorted after %s", e.Path, last)
+				}
+				last = e.Path
+				got[e.Path] = [2]uint64{e.ApparentSize, e.Count}
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+			if resp.Errors != 0 {
+				t.Errorf("got %d errors want 0", resp.Errors)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&server{}).DiskUsage(ctx, &pb.DiskUsageRequest{Path: dir}); status.Code(err) != codes.Canceled {
+		t.Errorf("DiskUsage with cancelled context: got %v want Canceled", err)
+	}
+}
@@ -0,0 +1,68 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestParseMountinfo(t *testing.T) {
+	f, err := os.Open("./testdata/mountinfo")
+	testutil.FatalOnErr("opening testdata", err, t)
+	defer f.Close()
+	got, err := parseMountinfo(f)
+	testutil.FatalOnErr("parseMountinfo", err, t)
+	want := []*pb.Mount{
+		{Source: "/dev/nvme0n1p2", MountPoint: "/", FsType: "ext4", Options: []string{"rw", "relatime", "errors=remount-ro"}},
+		{Source: "proc", MountPoint: "/proc", FsType: "proc", Options: []string{"rw", "nosuid", "nodev", "noexec", "relatime"}},
+		{Source: "sysfs", MountPoint: "/sys", FsType: "sysfs", Options: []string{"rw", "nosuid", "nodev", "noexec", "relatime"}},
+		{Source: "/dev/nvme0n1p1", MountPoint: "/boot/efi", FsType: "vfat", Options: []string{"rw", "relatime", "fmask=0077", "dmask=0077", "codepage=437"}},
+		{Source: "server:/export/a b", MountPoint: "/mnt/my share", FsType: "nfs4", Options: []string{"rw", "relatime", "vers=4.2", "hard"}},
+	}
+	testutil.DiffErr("parseMountinfo", got, want, t)
+
+	for _, bad := range []string{
+		"22 1 259:2 / / rw,relatime shared:1 ext4 /dev/root rw\n",
+		"22 1 259:2 / / rw - ext4\n",
+	} {
+		if _, err := parseMountinfo(strings.NewReader(bad)); err == nil {
+			t.Errorf("parseMountinfo(%q) didn't fail", bad)
+		}
+	}
+}
+
+func TestUnescapeMount(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+	}{
+		{in: "/plain", want: "/plain"},
+		{in: `/a\040b\011c`, want: "/a b\tc"},
+		{in: `/back\134slash`, want: `/back\slash`},
+		{in: `/short\04`, want: `/short\04`},
+		{in: `/notoctal\999`, want: `/notoctal\999`},
+	} {
+		if got := unescapeMount(tc.in); got != tc.want {
+			t.Errorf("unescapeMount(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
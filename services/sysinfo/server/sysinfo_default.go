@@ -0,0 +1,34 @@
+//go:build !linux
+// +build !linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"flag"
+)
+
+var (
+	journalctlBin  = flag.String("sysinfo-journalctl-bin", "", "Path to the journalctl binary used to read the journal (NOTE: no support on this platform)")
+	kmsgPath       = flag.String("kmsg-path", "", "Path to the kernel ring buffer device (NOTE: no support on this platform)")
+	chronycBin     = flag.String("chronyc-bin", "", "Path to the chronyc binary used to query chrony (NOTE: no support on this platform)")
+	ntpqBin        = flag.String("ntpq-bin", "", "Path to the ntpq binary used to query ntpd (NOTE: no support on this platform)")
+	timedatectlBin = flag.String("timedatectl-bin", "", "Path to the timedatectl binary used to query systemd-timesyncd (NOTE: no support on this platform)")
+	nvidiaSmiBin   = flag.String("nvidia-smi-bin", "", "Path to the nvidia-smi binary used to query NVIDIA GPUs (NOTE: no support on this platform)")
+	rocmSmiBin     = flag.String("rocm-smi-bin", "", "Path to the rocm-smi binary used to query AMD GPUs (NOTE: no support on this platform)")
+)
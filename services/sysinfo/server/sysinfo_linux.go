@@ -0,0 +1,35 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"flag"
+)
+
+var (
+	// Named for this service as the Service service has its own flag.
+	journalctlBin  = flag.String("sysinfo-journalctl-bin", "/usr/bin/journalctl", "Path to the journalctl binary used to read the journal")
+	kmsgPath       = flag.String("kmsg-path", "/dev/kmsg", "Path to the kernel ring buffer device")
+	chronycBin     = flag.String("chronyc-bin", "/usr/bin/chronyc", "Path to the chronyc binary used to query chrony")
+	ntpqBin        = flag.String("ntpq-bin", "/usr/bin/ntpq", "Path to the ntpq binary used to query ntpd")
+	timedatectlBin = flag.String("timedatectl-bin", "/usr/bin/timedatectl", "Path to the timedatectl binary used to query systemd-timesyncd")
+	nvidiaSmiBin   = flag.String("nvidia-smi-bin", "/usr/bin/nvidia-smi", "Path to the nvidia-smi binary used to query NVIDIA GPUs")
+	rocmSmiBin     = flag.String("rocm-smi-bin", "/opt/rocm/bin/rocm-smi", "Path to the rocm-smi binary used to query AMD GPUs")
+)
@@ -0,0 +1,292 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+// ntpEpochOffset is the seconds from the NTP epoch (1900) to the unix one.
+const ntpEpochOffset = 2208988800
+
+// secondsDuration converts fractional seconds to a duration.
+func secondsDuration(s float64) time.Duration {
+	return time.Duration(math.Round(s * float64(time.Second)))
+}
+
+// parseChronyTracking parses the CSV output of chronyc -c tracking, which
+// has the fields:
+//
+//	reference ID, source name, stratum, reference time, system time,
+//	last offset, RMS offset, frequency, residual frequency, skew,
+//	root delay, root dispersion, update interval, leap status
+func parseChronyTracking(out string) (*pb.TimeSyncReply, error) {
+	f := strings.Split(strings.TrimSpace(out), ",")
+	if len(f) < 14 {
+		return nil, fmt.Errorf("unexpected chronyc output %q", out)
+	}
+	stratum, err := strconv.ParseUint(f[2], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stratum %q: %v", f[2], err)
+	}
+	ref, err := strconv.ParseFloat(f[3], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference time %q: %v", f[3], err)
+	}
+	// This is the correction chronyd is applying, which is positive if
+	// the clock is slow.
+	correction, err := strconv.ParseFloat(f[4], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid system time %q: %v", f[4], err)
+	}
+	reply := &pb.TimeSyncReply{
+		Daemon:       pb.TimeDaemon_TIME_DAEMON_CHRONY,
+		Synchronized: f[0] != "00000000" && f[13] != "Not synchronised",
+		Offset:       durationpb.New(-secondsDuration(correction)),
+		Stratum:      uint32(stratum),
+		Source:       f[1],
+	}
+	if ref != 0 {
+		sec, frac := math.Modf(ref)
+		reply.LastSync = timestamppb.New(time.Unix(int64(sec), int64(frac*1e9)))
+	}
+	return reply, nil
+}
+
+// parseNtpqVariables parses the output of ntpq -c rv, which is a comma
+// separated list of name=value pairs (some quoted) after the association
+// status, i.e.
+//
+//	associd=0 status=0615 leap_none, sync_ntp, 1 event, clock_sync,
+//	version="ntpd 4.2.8p15@1.3728-o", processor="x86_64",
+//	stratum=3, precision=-24, rootdelay=1.234, rootdisp=5.678,
+//	refid=192.0.2.1, reftime=e5c1b3a0.12345678  Tue, Mar  1 2022 10:00:00.071,
+//	offset=-0.123, ...
+func parseNtpqVariables(out string) (*pb.TimeSyncReply, error) {
+	vars := make(map[string]string)
+	var words []string
+	for _, field := range strings.Split(strings.ReplaceAll(out, "\n", " "), ",") {
+		field = strings.TrimSpace(field)
+		if kv := strings.SplitN(field, "=", 2); len(kv) == 2 {
+			vars[strings.TrimSpace(kv[0])] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		}
+		words = append(words, strings.Fields(field)...)
+	}
+	// The status words follow status=, i.e. "sync_ntp" once synchronized
+	// and "sync_unspec" before.
+	synchronized := false
+	for _, w := range words {
+		if w == "leap_alarm" || w == "sync_unspec" {
+			synchronized = false
+			break
+		}
+		if strings.HasPrefix(w, "sync_") {
+			synchronized = true
+		}
+	}
+	if _, ok := vars["stratum"]; !ok {
+		return nil, fmt.Errorf("unexpected ntpq output %q", out)
+	}
+	stratum, err := strconv.ParseUint(vars["stratum"], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stratum %q: %v", vars["stratum"], err)
+	}
+	// The offset of the source from us, in milliseconds.
+	offset, err := strconv.ParseFloat(vars["offset"], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid offset %q: %v", vars["offset"], err)
+	}
+	reply := &pb.TimeSyncReply{
+		Daemon:       pb.TimeDaemon_TIME_DAEMON_NTPD,
+		Synchronized: synchronized,
+		Offset:       durationpb.New(-secondsDuration(offset / 1000)),
+		Stratum:      uint32(stratum),
+		Source:       vars["refid"],
+	}
+	// reftime is an NTP timestamp in hex followed by it formatted.
+	if ref := strings.Fields(vars["reftime"]); len(ref) > 0 {
+		parts := strings.SplitN(ref[0], ".", 2)
+		sec, err := strconv.ParseUint(parts[0], 16, 32)
+		if err == nil && sec != 0 {
+			var nsec uint64
+			if len(parts) == 2 {
+				if frac, err := strconv.ParseUint(parts[1], 16, 32); err == nil {
+					nsec = frac * 1e9 >> 32
+				}
+			}
+			reply.LastSync = timestamppb.New(time.Unix(int64(sec)-ntpEpochOffset, int64(nsec)))
+		}
+	}
+	return reply, nil
+}
+
+// parseSystemdDuration parses a duration as systemd formats them, i.e.
+// "-12us", "1.234ms" or "34min 8s".
+func parseSystemdDuration(s string) (time.Duration, error) {
+	var total time.Duration
+	neg := strings.HasPrefix(s, "-")
+	for _, part := range strings.Fields(strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")) {
+		part = strings.Replace(part, "min", "m", 1)
+		d, err := time.ParseDuration(part)
+		if err != nil {
+			return 0, err
+		}
+		total += d
+	}
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// parseTimesyncStatus parses the output of timedatectl timesync-status,
+// which is lines such as:
+//
+//	 Server: 192.0.2.1 (ntp.example.com)
+//	Stratum: 2
+//	 Offset: -12us
+func parseTimesyncStatus(out string) (*pb.TimeSyncReply, error) {
+	vals := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		kv := strings.SplitN(scanner.Text(), ":", 2)
+		if len(kv) == 2 {
+			vals[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	if vals["Server"] == "" {
+		return nil, fmt.Errorf("unexpected timedatectl output %q", out)
+	}
+	reply := &pb.TimeSyncReply{
+		Daemon: pb.TimeDaemon_TIME_DAEMON_TIMESYNCD,
+		Source: vals["Server"],
+	}
+	if v, ok := vals["Stratum"]; ok {
+		stratum, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stratum %q: %v", v, err)
+		}
+		// This is the server's stratum.
+		reply.Stratum = uint32(stratum) + 1
+	}
+	if v, ok := vals["Offset"]; ok {
+		// The offset of the server from us.
+		offset, err := parseSystemdDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset %q: %v", v, err)
+		}
+		reply.Offset = durationpb.New(-offset)
+	}
+	return reply, nil
+}
+
+// errNotInstalled is returned by runTool if the tool isn't installed.
+var errNotInstalled = errors.New("not installed")
+
+// runTool runs bin with args, returning its output.
+func runTool(ctx context.Context, bin string, args ...string) (string, error) {
+	if bin == "" {
+		return "", errNotInstalled
+	}
+	if _, err := os.Stat(bin); errors.Is(err, os.ErrNotExist) {
+		return "", errNotInstalled
+	}
+	run, err := util.RunCommand(ctx, bin, args)
+	if err != nil {
+		return "", err
+	}
+	if err := run.Error; run.ExitCode != 0 || err != nil {
+		return "", fmt.Errorf("%s failed: %v: %s", bin, err, strings.TrimSpace(util.TrimString(run.Stderr.String()+run.Stdout.String())))
+	}
+	return run.Stdout.String(), nil
+}
+
+func chronyStatus(ctx context.Context) (*pb.TimeSyncReply, error) {
+	out, err := runTool(ctx, *chronycBin, "-c", "tracking")
+	if err != nil {
+		return nil, err
+	}
+	return parseChronyTracking(out)
+}
+
+func ntpdStatus(ctx context.Context) (*pb.TimeSyncReply, error) {
+	out, err := runTool(ctx, *ntpqBin, "-c", "rv")
+	if err != nil {
+		return nil, err
+	}
+	return parseNtpqVariables(out)
+}
+
+func timesyncdStatus(ctx context.Context) (*pb.TimeSyncReply, error) {
+	out, err := runTool(ctx, *timedatectlBin, "timesync-status")
+	if err != nil {
+		return nil, err
+	}
+	reply, err := parseTimesyncStatus(out)
+	if err != nil {
+		return nil, err
+	}
+	out, err = runTool(ctx, *timedatectlBin, "show", "--property=NTPSynchronized", "--value")
+	if err != nil {
+		return nil, err
+	}
+	reply.Synchronized = strings.TrimSpace(out) == "yes"
+	return reply, nil
+}
+
+// TimeSync implements pb.SysInfoServer.TimeSync
+func (s *server) TimeSync(ctx context.Context, req *pb.TimeSyncRequest) (*pb.TimeSyncReply, error) {
+	// The tools can be installed without their daemon running (i.e.
+	// timedatectl always is with systemd), so use the first that works.
+	var errs []string
+	for _, d := range []struct {
+		name   string
+		status func(context.Context) (*pb.TimeSyncReply, error)
+	}{
+		{"chrony", chronyStatus},
+		{"ntpd", ntpdStatus},
+		{"timesyncd", timesyncdStatus},
+	} {
+		reply, err := d.status(ctx)
+		if err == nil {
+			return reply, nil
+		}
+		if !errors.Is(err, errNotInstalled) {
+			errs = append(errs, fmt.Sprintf("%s: %v", d.name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, status.Errorf(codes.Unavailable, "no time sync status available: %s", strings.Join(errs, "; "))
+	}
+	return &pb.TimeSyncReply{Daemon: pb.TimeDaemon_TIME_DAEMON_NONE}, nil
+}
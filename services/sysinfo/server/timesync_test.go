@@ -0,0 +1,229 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/sysinfo"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+var (
+	chronyReply = &pb.TimeSyncReply{
+		Daemon:       pb.TimeDaemon_TIME_DAEMON_CHRONY,
+		Synchronized: true,
+		Offset:       durationpb.New(12345 * time.Nanosecond),
+		Stratum:      4,
+		Source:       "169.254.169.123",
+		LastSync:     timestamppb.New(time.Date(2022, 3, 1, 10, 0, 0, 500000000, time.UTC)),
+	}
+	ntpdReply = &pb.TimeSyncReply{
+		Daemon:       pb.TimeDaemon_TIME_DAEMON_NTPD,
+		Synchronized: true,
+		Offset:       durationpb.New(1500 * time.Microsecond),
+		Stratum:      3,
+		Source:       "192.0.2.1",
+		LastSync:     timestamppb.New(time.Date(2022, 3, 1, 10, 0, 0, 500000000, time.UTC)),
+	}
+	timesyncdReply = &pb.TimeSyncReply{
+		Daemon:       pb.TimeDaemon_TIME_DAEMON_TIMESYNCD,
+		Synchronized: true,
+		Offset:       durationpb.New(-1250 * time.Microsecond),
+		Stratum:      3,
+		Source:       "192.0.2.1 (ntp.example.com)",
+	}
+)
+
+func TestParseTimeSync(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		file  string
+		parse func(string) (*pb.TimeSyncReply, error)
+		want  *pb.TimeSyncReply
+	}{
+		{name: "chrony", file: "./testdata/chrony-tracking.csv", parse: parseChronyTracking, want: chronyReply},
+		{name: "ntpd", file: "./testdata/ntpq-rv.out", parse: parseNtpqVariables, want: ntpdReply},
+		{name: "timesyncd", file: "./testdata/timesync-status.out", parse: parseTimesyncStatus, want: &pb.TimeSyncReply{
+			Daemon:  pb.TimeDaemon_TIME_DAEMON_TIMESYNCD,
+			Offset:  timesyncdReply.Offset,
+			Stratum: 3,
+			Source:  timesyncdReply.Source,
+		}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := os.ReadFile(tc.file)
+			testutil.FatalOnErr("reading testdata", err, t)
+			got, err := tc.parse(string(out))
+			testutil.FatalOnErr(tc.name, err, t)
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+
+	// Not yet synchronized.
+	got, err := parseChronyTracking("00000000,,0,0.000000000,0.000000000,0.000000000,0.000000000,0.000,0.000,0.000,1.000000000,1.000000000,0.0,Not synchronised\n")
+	testutil.FatalOnErr("chrony unsynchronized", err, t)
+	testutil.DiffErr("chrony unsynchronized", got, &pb.TimeSyncReply{Daemon: pb.TimeDaemon_TIME_DAEMON_CHRONY, Offset: durationpb.New(0)}, t)
+	got, err = parseNtpqVariables("associd=0 status=c016 leap_alarm, sync_unspec, 1 event, restart,\nstratum=16, refid=INIT, reftime=00000000.00000000  Thu, Feb  7 2036  6:28:16.000, offset=0.000")
+	testutil.FatalOnErr("ntpd unsynchronized", err, t)
+	testutil.DiffErr("ntpd unsynchronized", got, &pb.TimeSyncReply{Daemon: pb.TimeDaemon_TIME_DAEMON_NTPD, Offset: durationpb.New(0), Stratum: 16, Source: "INIT"}, t)
+
+	for _, bad := range []struct {
+		parse func(string) (*pb.TimeSyncReply, error)
+		out   string
+	}{
+		{parseChronyTracking, "506 Cannot talk to daemon"},
+		{parseChronyTracking, "A9FEA97B,x,four,0,0,0,0,0,0,0,0,0,0,Normal"},
+		{parseNtpqVariables, "associd=0 status=0615 leap_none"},
+		{parseNtpqVariables, "stratum=3, offset=soon"},
+		{parseTimesyncStatus, "nothing useful"},
+		{parseTimesyncStatus, "Server: x\nOffset: sideways"},
+	} {
+		if _, err := bad.parse(bad.out); err == nil {
+			t.Errorf("parsing %q didn't fail", bad.out)
+		}
+	}
+}
+
+func TestParseSystemdDuration(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want time.Duration
+	}{
+		{in: "-12us", want: -12 * time.Microsecond},
+		{in: "+1.250ms", want: 1250 * time.Microsecond},
+		{in: "34min 8s", want: 34*time.Minute + 8*time.Second},
+		{in: "-1s 500ms", want: -1500 * time.Millisecond},
+	} {
+		got, err := parseSystemdDuration(tc.in)
+		testutil.FatalOnErr(tc.in, err, t)
+		if got != tc.want {
+			t.Errorf("parseSystemdDuration(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+// fakeTimeTool writes a script to dir printing file for any arguments
+// other than those to check timedatectl's sync state, or failing if file
+// is empty.
+func fakeTimeTool(t *testing.T, dir string, name string, file string) string {
+	t.Helper()
+	script := "#!/bin/sh\necho 506 Cannot talk to daemon >&2\nexit 1\n"
+	if file != "" {
+		out, err := filepath.Abs(file)
+		testutil.FatalOnErr("output path", err, t)
+		script = fmt.Sprintf("#!/bin/sh\n[ \"$1\" = show ] && echo yes && exit 0\n%s %s\n", testutil.ResolvePath(t, "cat"), out)
+	}
+	bin := filepath.Join(dir, name)
+	testutil.FatalOnErr("writing "+name, os.WriteFile(bin, []byte(script), 0755), t)
+	return bin
+}
+
+func TestTimeSync(t *testing.T) {
+	savedChronyc, savedNtpq, savedTimedatectl := *chronycBin, *ntpqBin, *timedatectlBin
+	t.Cleanup(func() {
+		*chronycBin, *ntpqBin, *timedatectlBin = savedChronyc, savedNtpq, savedTimedatectl
+	})
+
+	const (
+		missing = "missing"
+		failing = ""
+	)
+	for _, tc := range []struct {
+		name        string
+		chronyc     string
+		ntpq        string
+		timedatectl string
+		want        *pb.TimeSyncReply
+		wantErr     codes.Code
+	}{
+		{
+			name:        "chrony",
+			chronyc:     "./testdata/chrony-tracking.csv",
+			ntpq:        "./testdata/ntpq-rv.out",
+			timedatectl: "./testdata/timesync-status.out",
+			want:        chronyReply,
+		},
+		{
+			name:        "chrony not running",
+			chronyc:     failing,
+			ntpq:        "./testdata/ntpq-rv.out",
+			timedatectl: "./testdata/timesync-status.out",
+			want:        ntpdReply,
+		},
+		{
+			name:        "timesyncd",
+			chronyc:     missing,
+			ntpq:        missing,
+			timedatectl: "./testdata/timesync-status.out",
+			want:        timesyncdReply,
+		},
+		{
+			name:        "none installed",
+			chronyc:     missing,
+			ntpq:        missing,
+			timedatectl: missing,
+			want:        &pb.TimeSyncReply{},
+		},
+		{
+			name:        "none running",
+			chronyc:     failing,
+			ntpq:        missing,
+			timedatectl: failing,
+			wantErr:     codes.Unavailable,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, tool := range []struct {
+				flag *string
+				name string
+				file string
+			}{
+				{chronycBin, "chronyc", tc.chronyc},
+				{ntpqBin, "ntpq", tc.ntpq},
+				{timedatectlBin, "timedatectl", tc.timedatectl},
+			} {
+				if tool.file == missing {
+					*tool.flag = filepath.Join(dir, "missing-"+tool.name)
+					continue
+				}
+				*tool.flag = fakeTimeTool(t, dir, tool.name, tool.file)
+			}
+			got, err := (&server{}).TimeSync(context.Background(), &pb.TimeSyncRequest{})
+			if code := status.Code(err); code != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", code, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
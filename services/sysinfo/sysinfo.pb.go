@@ -0,0 +1,2743 @@
+// Copyright (c) 2019 Snowflake Inc. All rights reserved.
+//
+//Licensed under the Apache License, Version 2.0 (the
+//"License"); you may not use this file except in compliance
+//with the License.  You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing,
+//software distributed under the License is distributed on an
+//"AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+//KIND, either express or implied.  See the License for the
+//specific language governing permissions and limitations
+//under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.17.3
+// source: sysinfo.proto
+
+package sysinfo
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Syslog priorities. The values are one more than the syslog level so
+// an unset priority can be told apart from emergency.
+type Priority int32
+
+const (
+	Priority_PRIORITY_UNKNOWN Priority = 0
+	Priority_PRIORITY_EMERG   Priority = 1
+	Priority_PRIORITY_ALERT   Priority = 2
+	Priority_PRIORITY_CRIT    Priority = 3
+	Priority_PRIORITY_ERR     Priority = 4
+	Priority_PRIORITY_WARNING Priority = 5
+	Priority_PRIORITY_NOTICE  Priority = 6
+	Priority_PRIORITY_INFO    Priority = 7
+	Priority_PRIORITY_DEBUG   Priority = 8
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_UNKNOWN",
+		1: "PRIORITY_EMERG",
+		2: "PRIORITY_ALERT",
+		3: "PRIORITY_CRIT",
+		4: "PRIORITY_ERR",
+		5: "PRIORITY_WARNING",
+		6: "PRIORITY_NOTICE",
+		7: "PRIORITY_INFO",
+		8: "PRIORITY_DEBUG",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_UNKNOWN": 0,
+		"PRIORITY_EMERG":   1,
+		"PRIORITY_ALERT":   2,
+		"PRIORITY_CRIT":    3,
+		"PRIORITY_ERR":     4,
+		"PRIORITY_WARNING": 5,
+		"PRIORITY_NOTICE":  6,
+		"PRIORITY_INFO":    7,
+		"PRIORITY_DEBUG":   8,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_sysinfo_proto_enumTypes[0].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_sysinfo_proto_enumTypes[0]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{0}
+}
+
+// Syslog facilities. Most kernel ring buffer records are FACILITY_KERN but
+// userspace (i.e. systemd early in boot) can log there too.
+type Facility int32
+
+const (
+	Facility_FACILITY_KERN     Facility = 0
+	Facility_FACILITY_USER     Facility = 1
+	Facility_FACILITY_MAIL     Facility = 2
+	Facility_FACILITY_DAEMON   Facility = 3
+	Facility_FACILITY_AUTH     Facility = 4
+	Facility_FACILITY_SYSLOG   Facility = 5
+	Facility_FACILITY_LPR      Facility = 6
+	Facility_FACILITY_NEWS     Facility = 7
+	Facility_FACILITY_UUCP     Facility = 8
+	Facility_FACILITY_CRON     Facility = 9
+	Facility_FACILITY_AUTHPRIV Facility = 10
+	Facility_FACILITY_FTP      Facility = 11
+	Facility_FACILITY_LOCAL0   Facility = 16
+	Facility_FACILITY_LOCAL1   Facility = 17
+	Facility_FACILITY_LOCAL2   Facility = 18
+	Facility_FACILITY_LOCAL3   Facility = 19
+	Facility_FACILITY_LOCAL4   Facility = 20
+	Facility_FACILITY_LOCAL5   Facility = 21
+	Facility_FACILITY_LOCAL6   Facility = 22
+	Facility_FACILITY_LOCAL7   Facility = 23
+)
+
+// Enum value maps for Facility.
+var (
+	Facility_name = map[int32]string{
+		0:  "FACILITY_KERN",
+		1:  "FACILITY_USER",
+		2:  "FACILITY_MAIL",
+		3:  "FACILITY_DAEMON",
+		4:  "FACILITY_AUTH",
+		5:  "FACILITY_SYSLOG",
+		6:  "FACILITY_LPR",
+		7:  "FACILITY_NEWS",
+		8:  "FACILITY_UUCP",
+		9:  "FACILITY_CRON",
+		10: "FACILITY_AUTHPRIV",
+		11: "FACILITY_FTP",
+		16: "FACILITY_LOCAL0",
+		17: "FACILITY_LOCAL1",
+		18: "FACILITY_LOCAL2",
+		19: "FACILITY_LOCAL3",
+		20: "FACILITY_LOCAL4",
+		21: "FACILITY_LOCAL5",
+		22: "FACILITY_LOCAL6",
+		23: "FACILITY_LOCAL7",
+	}
+	Facility_value = map[string]int32{
+		"FACILITY_KERN":     0,
+		"FACILITY_USER":     1,
+		"FACILITY_MAIL":     2,
+		"FACILITY_DAEMON":   3,
+		"FACILITY_AUTH":     4,
+		"FACILITY_SYSLOG":   5,
+		"FACILITY_LPR":      6,
+		"FACILITY_NEWS":     7,
+		"FACILITY_UUCP":     8,
+		"FACILITY_CRON":     9,
+		"FACILITY_AUTHPRIV": 10,
+		"FACILITY_FTP":      11,
+		"FACILITY_LOCAL0":   16,
+		"FACILITY_LOCAL1":   17,
+		"FACILITY_LOCAL2":   18,
+		"FACILITY_LOCAL3":   19,
+		"FACILITY_LOCAL4":   20,
+		"FACILITY_LOCAL5":   21,
+		"FACILITY_LOCAL6":   22,
+		"FACILITY_LOCAL7":   23,
+	}
+)
+
+func (x Facility) Enum() *Facility {
+	p := new(Facility)
+	*p = x
+	return p
+}
+
+func (x Facility) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Facility) Descriptor() protoreflect.EnumDescriptor {
+	return file_sysinfo_proto_enumTypes[1].Descriptor()
+}
+
+func (Facility) Type() protoreflect.EnumType {
+	return &file_sysinfo_proto_enumTypes[1]
+}
+
+func (x Facility) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Facility.Descriptor instead.
+func (Facility) EnumDescriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{1}
+}
+
+type TimeDaemon int32
+
+const (
+	// No supported daemon is installed.
+	TimeDaemon_TIME_DAEMON_NONE      TimeDaemon = 0
+	TimeDaemon_TIME_DAEMON_CHRONY    TimeDaemon = 1
+	TimeDaemon_TIME_DAEMON_NTPD      TimeDaemon = 2
+	TimeDaemon_TIME_DAEMON_TIMESYNCD TimeDaemon = 3
+)
+
+// Enum value maps for TimeDaemon.
+var (
+	TimeDaemon_name = map[int32]string{
+		0: "TIME_DAEMON_NONE",
+		1: "TIME_DAEMON_CHRONY",
+		2: "TIME_DAEMON_NTPD",
+		3: "TIME_DAEMON_TIMESYNCD",
+	}
+	TimeDaemon_value = map[string]int32{
+		"TIME_DAEMON_NONE":      0,
+		"TIME_DAEMON_CHRONY":    1,
+		"TIME_DAEMON_NTPD":      2,
+		"TIME_DAEMON_TIMESYNCD": 3,
+	}
+)
+
+func (x TimeDaemon) Enum() *TimeDaemon {
+	p := new(TimeDaemon)
+	*p = x
+	return p
+}
+
+func (x TimeDaemon) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TimeDaemon) Descriptor() protoreflect.EnumDescriptor {
+	return file_sysinfo_proto_enumTypes[2].Descriptor()
+}
+
+func (TimeDaemon) Type() protoreflect.EnumType {
+	return &file_sysinfo_proto_enumTypes[2]
+}
+
+func (x TimeDaemon) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TimeDaemon.Descriptor instead.
+func (TimeDaemon) EnumDescriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{2}
+}
+
+type GPUTool int32
+
+const (
+	// Neither tool is installed.
+	GPUTool_GPU_TOOL_NONE       GPUTool = 0
+	GPUTool_GPU_TOOL_NVIDIA_SMI GPUTool = 1
+	GPUTool_GPU_TOOL_ROCM_SMI   GPUTool = 2
+)
+
+// Enum value maps for GPUTool.
+var (
+	GPUTool_name = map[int32]string{
+		0: "GPU_TOOL_NONE",
+		1: "GPU_TOOL_NVIDIA_SMI",
+		2: "GPU_TOOL_ROCM_SMI",
+	}
+	GPUTool_value = map[string]int32{
+		"GPU_TOOL_NONE":       0,
+		"GPU_TOOL_NVIDIA_SMI": 1,
+		"GPU_TOOL_ROCM_SMI":   2,
+	}
+)
+
+func (x GPUTool) Enum() *GPUTool {
+	p := new(GPUTool)
+	*p = x
+	return p
+}
+
+func (x GPUTool) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GPUTool) Descriptor() protoreflect.EnumDescriptor {
+	return file_sysinfo_proto_enumTypes[3].Descriptor()
+}
+
+func (GPUTool) Type() protoreflect.EnumType {
+	return &file_sysinfo_proto_enumTypes[3]
+}
+
+func (x GPUTool) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GPUTool.Descriptor instead.
+func (GPUTool) EnumDescriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{3}
+}
+
+type InfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *InfoRequest) Reset() {
+	*x = InfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InfoRequest) ProtoMessage() {}
+
+func (x *InfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InfoRequest.ProtoReflect.Descriptor instead.
+func (*InfoRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{0}
+}
+
+// OSRelease identifies the OS distribution, from os-release(5).
+type OSRelease struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// i.e. ubuntu
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// i.e. 22.04
+	VersionId string `protobuf:"bytes,2,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	// i.e. Ubuntu 22.04.3 LTS
+	PrettyName string `protobuf:"bytes,3,opt,name=pretty_name,json=prettyName,proto3" json:"pretty_name,omitempty"`
+}
+
+func (x *OSRelease) Reset() {
+	*x = OSRelease{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OSRelease) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OSRelease) ProtoMessage() {}
+
+func (x *OSRelease) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OSRelease.ProtoReflect.Descriptor instead.
+func (*OSRelease) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OSRelease) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OSRelease) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *OSRelease) GetPrettyName() string {
+	if x != nil {
+		return x.PrettyName
+	}
+	return ""
+}
+
+type LoadAverage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OneMinute      float64 `protobuf:"fixed64,1,opt,name=one_minute,json=oneMinute,proto3" json:"one_minute,omitempty"`
+	FiveMinutes    float64 `protobuf:"fixed64,2,opt,name=five_minutes,json=fiveMinutes,proto3" json:"five_minutes,omitempty"`
+	FifteenMinutes float64 `protobuf:"fixed64,3,opt,name=fifteen_minutes,json=fifteenMinutes,proto3" json:"fifteen_minutes,omitempty"`
+}
+
+func (x *LoadAverage) Reset() {
+	*x = LoadAverage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadAverage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadAverage) ProtoMessage() {}
+
+func (x *LoadAverage) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadAverage.ProtoReflect.Descriptor instead.
+func (*LoadAverage) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LoadAverage) GetOneMinute() float64 {
+	if x != nil {
+		return x.OneMinute
+	}
+	return 0
+}
+
+func (x *LoadAverage) GetFiveMinutes() float64 {
+	if x != nil {
+		return x.FiveMinutes
+	}
+	return 0
+}
+
+func (x *LoadAverage) GetFifteenMinutes() float64 {
+	if x != nil {
+		return x.FifteenMinutes
+	}
+	return 0
+}
+
+type InfoReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uptime   *durationpb.Duration   `protobuf:"bytes,1,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	BootTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=boot_time,json=bootTime,proto3" json:"boot_time,omitempty"`
+	// The kernel name, release, version and architecture as from uname(1).
+	KernelName    string `protobuf:"bytes,3,opt,name=kernel_name,json=kernelName,proto3" json:"kernel_name,omitempty"`
+	KernelRelease string `protobuf:"bytes,4,opt,name=kernel_release,json=kernelRelease,proto3" json:"kernel_release,omitempty"`
+	KernelVersion string `protobuf:"bytes,5,opt,name=kernel_version,json=kernelVersion,proto3" json:"kernel_version,omitempty"`
+	Architecture  string `protobuf:"bytes,6,opt,name=architecture,proto3" json:"architecture,omitempty"`
+	// Unset if the system doesn't have os-release(5).
+	OsRelease *OSRelease `protobuf:"bytes,7,opt,name=os_release,json=osRelease,proto3" json:"os_release,omitempty"`
+	// Memory sizes in bytes. Available is an estimate of how much could be
+	// used without swapping (free plus reclaimable caches).
+	MemoryTotal     uint64       `protobuf:"varint,8,opt,name=memory_total,json=memoryTotal,proto3" json:"memory_total,omitempty"`
+	MemoryFree      uint64       `protobuf:"varint,9,opt,name=memory_free,json=memoryFree,proto3" json:"memory_free,omitempty"`
+	MemoryAvailable uint64       `protobuf:"varint,10,opt,name=memory_available,json=memoryAvailable,proto3" json:"memory_available,omitempty"`
+	LoadAverage     *LoadAverage `protobuf:"bytes,11,opt,name=load_average,json=loadAverage,proto3" json:"load_average,omitempty"`
+	// The number of CPUs usable by the server.
+	CpuCount int32 `protobuf:"varint,12,opt,name=cpu_count,json=cpuCount,proto3" json:"cpu_count,omitempty"`
+}
+
+func (x *InfoReply) Reset() {
+	*x = InfoReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InfoReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InfoReply) ProtoMessage() {}
+
+func (x *InfoReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InfoReply.ProtoReflect.Descriptor instead.
+func (*InfoReply) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *InfoReply) GetUptime() *durationpb.Duration {
+	if x != nil {
+		return x.Uptime
+	}
+	return nil
+}
+
+func (x *InfoReply) GetBootTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BootTime
+	}
+	return nil
+}
+
+func (x *InfoReply) GetKernelName() string {
+	if x != nil {
+		return x.KernelName
+	}
+	return ""
+}
+
+func (x *InfoReply) GetKernelRelease() string {
+	if x != nil {
+		return x.KernelRelease
+	}
+	return ""
+}
+
+func (x *InfoReply) GetKernelVersion() string {
+	if x != nil {
+		return x.KernelVersion
+	}
+	return ""
+}
+
+func (x *InfoReply) GetArchitecture() string {
+	if x != nil {
+		return x.Architecture
+	}
+	return ""
+}
+
+func (x *InfoReply) GetOsRelease() *OSRelease {
+	if x != nil {
+		return x.OsRelease
+	}
+	return nil
+}
+
+func (x *InfoReply) GetMemoryTotal() uint64 {
+	if x != nil {
+		return x.MemoryTotal
+	}
+	return 0
+}
+
+func (x *InfoReply) GetMemoryFree() uint64 {
+	if x != nil {
+		return x.MemoryFree
+	}
+	return 0
+}
+
+func (x *InfoReply) GetMemoryAvailable() uint64 {
+	if x != nil {
+		return x.MemoryAvailable
+	}
+	return 0
+}
+
+func (x *InfoReply) GetLoadAverage() *LoadAverage {
+	if x != nil {
+		return x.LoadAverage
+	}
+	return nil
+}
+
+func (x *InfoReply) GetCpuCount() int32 {
+	if x != nil {
+		return x.CpuCount
+	}
+	return 0
+}
+
+type JournalRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set only return entries for this unit.
+	Unit string `protobuf:"bytes,1,opt,name=unit,proto3" json:"unit,omitempty"`
+	// If set only return entries of this priority or more important ones.
+	Priority Priority `protobuf:"varint,2,opt,name=priority,proto3,enum=SysInfo.Priority" json:"priority,omitempty"`
+	// If set only return entries written at or after this time.
+	Since *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	// If set only return entries written at or before this time. Can't be
+	// used with follow.
+	Until *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=until,proto3" json:"until,omitempty"`
+	// If set only return entries with messages matching this regular
+	// expression (RE2 syntax).
+	Grep string `protobuf:"bytes,5,opt,name=grep,proto3" json:"grep,omitempty"`
+	// Return at most this many entries, the most recent ones matching. If
+	// unset 100 are returned unless following, where it's 0 so only new
+	// entries are returned.
+	Limit int32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	// If true keep returning new entries as they're written.
+	Follow bool `protobuf:"varint,7,opt,name=follow,proto3" json:"follow,omitempty"`
+	// If true return every field of each entry in JournalRecord.fields.
+	AllFields bool `protobuf:"varint,8,opt,name=all_fields,json=allFields,proto3" json:"all_fields,omitempty"`
+}
+
+func (x *JournalRequest) Reset() {
+	*x = JournalRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JournalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalRequest) ProtoMessage() {}
+
+func (x *JournalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalRequest.ProtoReflect.Descriptor instead.
+func (*JournalRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *JournalRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *JournalRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNKNOWN
+}
+
+func (x *JournalRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *JournalRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *JournalRequest) GetGrep() string {
+	if x != nil {
+		return x.Grep
+	}
+	return ""
+}
+
+func (x *JournalRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *JournalRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+func (x *JournalRequest) GetAllFields() bool {
+	if x != nil {
+		return x.AllFields
+	}
+	return false
+}
+
+// JournalRecord is a single journal entry.
+type JournalRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RealtimeTimestamp *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=realtime_timestamp,json=realtimeTimestamp,proto3" json:"realtime_timestamp,omitempty"`
+	Hostname          string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	SyslogIdentifier  string                 `protobuf:"bytes,3,opt,name=syslog_identifier,json=syslogIdentifier,proto3" json:"syslog_identifier,omitempty"`
+	Pid               int32                  `protobuf:"varint,4,opt,name=pid,proto3" json:"pid,omitempty"`
+	Priority          Priority               `protobuf:"varint,5,opt,name=priority,proto3,enum=SysInfo.Priority" json:"priority,omitempty"`
+	// The systemd unit which logged the entry.
+	Unit    string `protobuf:"bytes,6,opt,name=unit,proto3" json:"unit,omitempty"`
+	Message string `protobuf:"bytes,7,opt,name=message,proto3" json:"message,omitempty"`
+	// Opaque position of the entry in the journal.
+	Cursor string `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// All fields (i.e. _BOOT_ID, CODE_FILE) if requested. Binary values are
+	// returned as is.
+	Fields map[string]string `protobuf:"bytes,9,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *JournalRecord) Reset() {
+	*x = JournalRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JournalRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalRecord) ProtoMessage() {}
+
+func (x *JournalRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalRecord.ProtoReflect.Descriptor instead.
+func (*JournalRecord) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *JournalRecord) GetRealtimeTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RealtimeTimestamp
+	}
+	return nil
+}
+
+func (x *JournalRecord) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *JournalRecord) GetSyslogIdentifier() string {
+	if x != nil {
+		return x.SyslogIdentifier
+	}
+	return ""
+}
+
+func (x *JournalRecord) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *JournalRecord) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNKNOWN
+}
+
+func (x *JournalRecord) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *JournalRecord) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *JournalRecord) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *JournalRecord) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type JournalReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Record *JournalRecord `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+}
+
+func (x *JournalReply) Reset() {
+	*x = JournalReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JournalReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalReply) ProtoMessage() {}
+
+func (x *JournalReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalReply.ProtoReflect.Descriptor instead.
+func (*JournalReply) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *JournalReply) GetRecord() *JournalRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type DmesgRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set only return records of this priority or more important ones.
+	Priority Priority `protobuf:"varint,1,opt,name=priority,proto3,enum=SysInfo.Priority" json:"priority,omitempty"`
+	// If set only return records logged at or after this time.
+	Since *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	// If set only return records with messages matching this regular
+	// expression (RE2 syntax).
+	Grep string `protobuf:"bytes,3,opt,name=grep,proto3" json:"grep,omitempty"`
+	// If true keep returning new records as they're logged.
+	Follow bool `protobuf:"varint,4,opt,name=follow,proto3" json:"follow,omitempty"`
+}
+
+func (x *DmesgRequest) Reset() {
+	*x = DmesgRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DmesgRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DmesgRequest) ProtoMessage() {}
+
+func (x *DmesgRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DmesgRequest.ProtoReflect.Descriptor instead.
+func (*DmesgRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DmesgRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNKNOWN
+}
+
+func (x *DmesgRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *DmesgRequest) GetGrep() string {
+	if x != nil {
+		return x.Grep
+	}
+	return ""
+}
+
+func (x *DmesgRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+// DmesgRecord is a single record from the kernel ring buffer.
+type DmesgRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// When the record was logged. This is derived from uptime and the
+	// current boot time so it may be off if the system was suspended.
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Time since boot the record was logged.
+	Uptime   *durationpb.Duration `protobuf:"bytes,2,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	Facility Facility             `protobuf:"varint,3,opt,name=facility,proto3,enum=SysInfo.Facility" json:"facility,omitempty"`
+	Priority Priority             `protobuf:"varint,4,opt,name=priority,proto3,enum=SysInfo.Priority" json:"priority,omitempty"`
+	// Sequence number of the record. Gaps mean records were overwritten
+	// before they were read.
+	Sequence uint64 `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Message  string `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	// Any dictionary properties of the record such as SUBSYSTEM and DEVICE.
+	Fields map[string]string `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *DmesgRecord) Reset() {
+	*x = DmesgRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DmesgRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DmesgRecord) ProtoMessage() {}
+
+func (x *DmesgRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DmesgRecord.ProtoReflect.Descriptor instead.
+func (*DmesgRecord) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DmesgRecord) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *DmesgRecord) GetUptime() *durationpb.Duration {
+	if x != nil {
+		return x.Uptime
+	}
+	return nil
+}
+
+func (x *DmesgRecord) GetFacility() Facility {
+	if x != nil {
+		return x.Facility
+	}
+	return Facility_FACILITY_KERN
+}
+
+func (x *DmesgRecord) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNKNOWN
+}
+
+func (x *DmesgRecord) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *DmesgRecord) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DmesgRecord) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type DmesgReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Record *DmesgRecord `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+}
+
+func (x *DmesgReply) Reset() {
+	*x = DmesgReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DmesgReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DmesgReply) ProtoMessage() {}
+
+func (x *DmesgReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DmesgReply.ProtoReflect.Descriptor instead.
+func (*DmesgReply) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DmesgReply) GetRecord() *DmesgRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type MountsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set include filesystems with no blocks (i.e. proc and sysfs) which
+	// are skipped by default as with df(1).
+	All bool `protobuf:"varint,1,opt,name=all,proto3" json:"all,omitempty"`
+}
+
+func (x *MountsRequest) Reset() {
+	*x = MountsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MountsRequest) ProtoMessage() {}
+
+func (x *MountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MountsRequest.ProtoReflect.Descriptor instead.
+func (*MountsRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MountsRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type FilesystemUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Sizes in bytes. Available is the space usable by unprivileged users,
+	// so excludes any reserved for root.
+	Size       uint64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Used       uint64 `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	Available  uint64 `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	Inodes     uint64 `protobuf:"varint,4,opt,name=inodes,proto3" json:"inodes,omitempty"`
+	InodesUsed uint64 `protobuf:"varint,5,opt,name=inodes_used,json=inodesUsed,proto3" json:"inodes_used,omitempty"`
+	InodesFree uint64 `protobuf:"varint,6,opt,name=inodes_free,json=inodesFree,proto3" json:"inodes_free,omitempty"`
+}
+
+func (x *FilesystemUsage) Reset() {
+	*x = FilesystemUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilesystemUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilesystemUsage) ProtoMessage() {}
+
+func (x *FilesystemUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilesystemUsage.ProtoReflect.Descriptor instead.
+func (*FilesystemUsage) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FilesystemUsage) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FilesystemUsage) GetUsed() uint64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *FilesystemUsage) GetAvailable() uint64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *FilesystemUsage) GetInodes() uint64 {
+	if x != nil {
+		return x.Inodes
+	}
+	return 0
+}
+
+func (x *FilesystemUsage) GetInodesUsed() uint64 {
+	if x != nil {
+		return x.InodesUsed
+	}
+	return 0
+}
+
+func (x *FilesystemUsage) GetInodesFree() uint64 {
+	if x != nil {
+		return x.InodesFree
+	}
+	return 0
+}
+
+type Mount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The mounted device or other source, i.e. /dev/sda1 or tmpfs.
+	Source     string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	MountPoint string `protobuf:"bytes,2,opt,name=mount_point,json=mountPoint,proto3" json:"mount_point,omitempty"`
+	FsType     string `protobuf:"bytes,3,opt,name=fs_type,json=fsType,proto3" json:"fs_type,omitempty"`
+	// The mount options (i.e. rw, noatime) followed by any filesystem
+	// specific ones.
+	Options []string `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty"`
+	// Unset if usage couldn't be determined, i.e. a hung NFS mount.
+	Usage *FilesystemUsage `protobuf:"bytes,5,opt,name=usage,proto3" json:"usage,omitempty"`
+	// Why usage is unset.
+	UsageError string `protobuf:"bytes,6,opt,name=usage_error,json=usageError,proto3" json:"usage_error,omitempty"`
+}
+
+func (x *Mount) Reset() {
+	*x = Mount{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Mount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mount) ProtoMessage() {}
+
+func (x *Mount) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mount.ProtoReflect.Descriptor instead.
+func (*Mount) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Mount) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Mount) GetMountPoint() string {
+	if x != nil {
+		return x.MountPoint
+	}
+	return ""
+}
+
+func (x *Mount) GetFsType() string {
+	if x != nil {
+		return x.FsType
+	}
+	return ""
+}
+
+func (x *Mount) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Mount) GetUsage() *FilesystemUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *Mount) GetUsageError() string {
+	if x != nil {
+		return x.UsageError
+	}
+	return ""
+}
+
+type MountsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mounts []*Mount `protobuf:"bytes,1,rep,name=mounts,proto3" json:"mounts,omitempty"`
+}
+
+func (x *MountsReply) Reset() {
+	*x = MountsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MountsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MountsReply) ProtoMessage() {}
+
+func (x *MountsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MountsReply.ProtoReflect.Descriptor instead.
+func (*MountsReply) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MountsReply) GetMounts() []*Mount {
+	if x != nil {
+		return x.Mounts
+	}
+	return nil
+}
+
+type DiskUsageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Absolute path to the file or directory.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Also return the usage of directories this far below path, as with
+	// du --max-depth. If unset only the total is returned.
+	MaxDepth uint32 `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	// If set don't count directories on other filesystems, as with du -x.
+	OneFileSystem bool `protobuf:"varint,3,opt,name=one_file_system,json=oneFileSystem,proto3" json:"one_file_system,omitempty"`
+}
+
+func (x *DiskUsageRequest) Reset() {
+	*x = DiskUsageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiskUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskUsageRequest) ProtoMessage() {}
+
+func (x *DiskUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskUsageRequest.ProtoReflect.Descriptor instead.
+func (*DiskUsageRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DiskUsageRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DiskUsageRequest) GetMaxDepth() uint32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *DiskUsageRequest) GetOneFileSystem() bool {
+	if x != nil {
+		return x.OneFileSystem
+	}
+	return false
+}
+
+type DiskUsageEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Bytes allocated on disk. Files with several hard links are only
+	// counted once.
+	Size uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// The sum of file sizes, which differs from size for sparse files or
+	// those smaller than a block.
+	ApparentSize uint64 `protobuf:"varint,3,opt,name=apparent_size,json=apparentSize,proto3" json:"apparent_size,omitempty"`
+	// The number of files and directories counted, including path itself.
+	Count uint64 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *DiskUsageEntry) Reset() {
+	*x = DiskUsageEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiskUsageEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskUsageEntry) ProtoMessage() {}
+
+func (x *DiskUsageEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskUsageEntry.ProtoReflect.Descriptor instead.
+func (*DiskUsageEntry) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DiskUsageEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DiskUsageEntry) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DiskUsageEntry) GetApparentSize() uint64 {
+	if x != nil {
+		return x.ApparentSize
+	}
+	return 0
+}
+
+func (x *DiskUsageEntry) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type DiskUsageReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Sorted by path, so the total for the requested path is first.
+	Entries []*DiskUsageEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	// The number of files and directories which couldn't be read (i.e. due
+	// to permissions) so aren't included.
+	Errors uint64 `protobuf:"varint,2,opt,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *DiskUsageReply) Reset() {
+	*x = DiskUsageReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiskUsageReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskUsageReply) ProtoMessage() {}
+
+func (x *DiskUsageReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskUsageReply.ProtoReflect.Descriptor instead.
+func (*DiskUsageReply) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DiskUsageReply) GetEntries() []*DiskUsageEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *DiskUsageReply) GetErrors() uint64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+type TimeSyncRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *TimeSyncRequest) Reset() {
+	*x = TimeSyncRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimeSyncRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeSyncRequest) ProtoMessage() {}
+
+func (x *TimeSyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeSyncRequest.ProtoReflect.Descriptor instead.
+func (*TimeSyncRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{17}
+}
+
+type TimeSyncReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The daemon the status is from.
+	Daemon TimeDaemon `protobuf:"varint,1,opt,name=daemon,proto3,enum=SysInfo.TimeDaemon" json:"daemon,omitempty"`
+	// Whether the daemon considers the clock synchronized.
+	Synchronized bool `protobuf:"varint,2,opt,name=synchronized,proto3" json:"synchronized,omitempty"`
+	// How far the system clock is ahead of the source, negative if it's
+	// behind.
+	Offset *durationpb.Duration `protobuf:"bytes,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// The stratum of the host (one more than its source's).
+	Stratum uint32 `protobuf:"varint,4,opt,name=stratum,proto3" json:"stratum,omitempty"`
+	// The source being synchronized to, i.e. an address, host name or
+	// reference clock ID.
+	Source string `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	// When the clock was last updated from the source. Unset if the daemon
+	// doesn't report it (timesyncd).
+	LastSync *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_sync,json=lastSync,proto3" json:"last_sync,omitempty"`
+}
+
+func (x *TimeSyncReply) Reset() {
+	*x = TimeSyncReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimeSyncReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeSyncReply) ProtoMessage() {}
+
+func (x *TimeSyncReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeSyncReply.ProtoReflect.Descriptor instead.
+func (*TimeSyncReply) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TimeSyncReply) GetDaemon() TimeDaemon {
+	if x != nil {
+		return x.Daemon
+	}
+	return TimeDaemon_TIME_DAEMON_NONE
+}
+
+func (x *TimeSyncReply) GetSynchronized() bool {
+	if x != nil {
+		return x.Synchronized
+	}
+	return false
+}
+
+func (x *TimeSyncReply) GetOffset() *durationpb.Duration {
+	if x != nil {
+		return x.Offset
+	}
+	return nil
+}
+
+func (x *TimeSyncReply) GetStratum() uint32 {
+	if x != nil {
+		return x.Stratum
+	}
+	return 0
+}
+
+func (x *TimeSyncReply) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *TimeSyncReply) GetLastSync() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSync
+	}
+	return nil
+}
+
+type GPUsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GPUsRequest) Reset() {
+	*x = GPUsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GPUsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GPUsRequest) ProtoMessage() {}
+
+func (x *GPUsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GPUsRequest.ProtoReflect.Descriptor instead.
+func (*GPUsRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{19}
+}
+
+type ECCErrors struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Errors since the driver loaded.
+	Corrected   uint64 `protobuf:"varint,1,opt,name=corrected,proto3" json:"corrected,omitempty"`
+	Uncorrected uint64 `protobuf:"varint,2,opt,name=uncorrected,proto3" json:"uncorrected,omitempty"`
+}
+
+func (x *ECCErrors) Reset() {
+	*x = ECCErrors{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ECCErrors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ECCErrors) ProtoMessage() {}
+
+func (x *ECCErrors) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ECCErrors.ProtoReflect.Descriptor instead.
+func (*ECCErrors) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ECCErrors) GetCorrected() uint64 {
+	if x != nil {
+		return x.Corrected
+	}
+	return 0
+}
+
+func (x *ECCErrors) GetUncorrected() uint64 {
+	if x != nil {
+		return x.Uncorrected
+	}
+	return 0
+}
+
+type GPU struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// i.e. GPU-5f0d6f4e-... for NVIDIA or the unique ID for AMD.
+	Uuid     string `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name     string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	PciBusId string `protobuf:"bytes,4,opt,name=pci_bus_id,json=pciBusId,proto3" json:"pci_bus_id,omitempty"`
+	// Percent of time the GPU was busy, or -1 if not reported.
+	Utilization int32 `protobuf:"varint,5,opt,name=utilization,proto3" json:"utilization,omitempty"`
+	// In bytes, 0 if not reported.
+	MemoryTotal uint64 `protobuf:"varint,6,opt,name=memory_total,json=memoryTotal,proto3" json:"memory_total,omitempty"`
+	MemoryUsed  uint64 `protobuf:"varint,7,opt,name=memory_used,json=memoryUsed,proto3" json:"memory_used,omitempty"`
+	// In degrees Celsius, or -1 if not reported.
+	Temperature float64 `protobuf:"fixed64,8,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	// In watts, or -1 if not reported.
+	PowerDraw float64 `protobuf:"fixed64,9,opt,name=power_draw,json=powerDraw,proto3" json:"power_draw,omitempty"`
+	// Unset if ECC isn't supported, enabled or reported (rocm-smi).
+	EccErrors *ECCErrors `protobuf:"bytes,10,opt,name=ecc_errors,json=eccErrors,proto3" json:"ecc_errors,omitempty"`
+}
+
+func (x *GPU) Reset() {
+	*x = GPU{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GPU) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GPU) ProtoMessage() {}
+
+func (x *GPU) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GPU.ProtoReflect.Descriptor instead.
+func (*GPU) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GPU) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *GPU) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *GPU) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GPU) GetPciBusId() string {
+	if x != nil {
+		return x.PciBusId
+	}
+	return ""
+}
+
+func (x *GPU) GetUtilization() int32 {
+	if x != nil {
+		return x.Utilization
+	}
+	return 0
+}
+
+func (x *GPU) GetMemoryTotal() uint64 {
+	if x != nil {
+		return x.MemoryTotal
+	}
+	return 0
+}
+
+func (x *GPU) GetMemoryUsed() uint64 {
+	if x != nil {
+		return x.MemoryUsed
+	}
+	return 0
+}
+
+func (x *GPU) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *GPU) GetPowerDraw() float64 {
+	if x != nil {
+		return x.PowerDraw
+	}
+	return 0
+}
+
+func (x *GPU) GetEccErrors() *ECCErrors {
+	if x != nil {
+		return x.EccErrors
+	}
+	return nil
+}
+
+type GPUsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The tool the status is from.
+	Tool          GPUTool `protobuf:"varint,1,opt,name=tool,proto3,enum=SysInfo.GPUTool" json:"tool,omitempty"`
+	DriverVersion string  `protobuf:"bytes,2,opt,name=driver_version,json=driverVersion,proto3" json:"driver_version,omitempty"`
+	Gpus          []*GPU  `protobuf:"bytes,3,rep,name=gpus,proto3" json:"gpus,omitempty"`
+}
+
+func (x *GPUsReply) Reset() {
+	*x = GPUsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GPUsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GPUsReply) ProtoMessage() {}
+
+func (x *GPUsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GPUsReply.ProtoReflect.Descriptor instead.
+func (*GPUsReply) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GPUsReply) GetTool() GPUTool {
+	if x != nil {
+		return x.Tool
+	}
+	return GPUTool_GPU_TOOL_NONE
+}
+
+func (x *GPUsReply) GetDriverVersion() string {
+	if x != nil {
+		return x.DriverVersion
+	}
+	return ""
+}
+
+func (x *GPUsReply) GetGpus() []*GPU {
+	if x != nil {
+		return x.Gpus
+	}
+	return nil
+}
+
+var File_sysinfo_proto protoreflect.FileDescriptor
+
+var file_sysinfo_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x73, 0x79, 0x73, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x07, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x0d, 0x0a, 0x0b, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x5b, 0x0a, 0x09, 0x4f, 0x53, 0x52, 0x65,
+	0x6c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x74, 0x74, 0x79, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x74, 0x74,
+	0x79, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x78, 0x0a, 0x0b, 0x4c, 0x6f, 0x61, 0x64, 0x41, 0x76, 0x65,
+	0x72, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x6e, 0x65, 0x5f, 0x6d, 0x69, 0x6e, 0x75,
+	0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6f, 0x6e, 0x65, 0x4d, 0x69, 0x6e,
+	0x75, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x69, 0x76, 0x65, 0x5f, 0x6d, 0x69, 0x6e, 0x75,
+	0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x66, 0x69, 0x76, 0x65, 0x4d,
+	0x69, 0x6e, 0x75, 0x74, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x66, 0x69, 0x66, 0x74, 0x65, 0x65,
+	0x6e, 0x5f, 0x6d, 0x69, 0x6e, 0x75, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0e, 0x66, 0x69, 0x66, 0x74, 0x65, 0x65, 0x6e, 0x4d, 0x69, 0x6e, 0x75, 0x74, 0x65, 0x73, 0x22,
+	0x82, 0x04, 0x0a, 0x09, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x31, 0x0a,
+	0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x37, 0x0a, 0x09, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x08, 0x62, 0x6f, 0x6f, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6b, 0x65, 0x72,
+	0x6e, 0x65, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x6b, 0x65,
+	0x72, 0x6e, 0x65, 0x6c, 0x5f, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73,
+	0x65, 0x12, 0x25, 0x0a, 0x0e, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6b, 0x65, 0x72, 0x6e, 0x65,
+	0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x72, 0x63, 0x68,
+	0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x12, 0x31, 0x0a, 0x0a,
+	0x6f, 0x73, 0x5f, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x4f, 0x53, 0x52, 0x65, 0x6c,
+	0x65, 0x61, 0x73, 0x65, 0x52, 0x09, 0x6f, 0x73, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x12,
+	0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x54, 0x6f, 0x74,
+	0x61, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x66, 0x72, 0x65,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x46,
+	0x72, 0x65, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x61, 0x76,
+	0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x6d,
+	0x65, 0x6d, 0x6f, 0x72, 0x79, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x37,
+	0x0a, 0x0c, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x4c,
+	0x6f, 0x61, 0x64, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x52, 0x0b, 0x6c, 0x6f, 0x61, 0x64,
+	0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x70, 0x75, 0x5f, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x70, 0x75, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x22, 0x98, 0x02, 0x0a, 0x0e, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x12, 0x2d, 0x0a, 0x08, 0x70,
+	0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e,
+	0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79,
+	0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69,
+	0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x05,
+	0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x12,
+	0x0a, 0x04, 0x67, 0x72, 0x65, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x72,
+	0x65, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x6c,
+	0x6f, 0x77, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77,
+	0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x6c, 0x6c, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x6c, 0x6c, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22,
+	0xa1, 0x03, 0x0a, 0x0d, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x12, 0x49, 0x0a, 0x12, 0x72, 0x65, 0x61, 0x6c, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x11, 0x72, 0x65, 0x61, 0x6c, 0x74,
+	0x69, 0x6d, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1a, 0x0a, 0x08,
+	0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x73, 0x79, 0x73, 0x6c,
+	0x6f, 0x67, 0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x10, 0x73, 0x79, 0x73, 0x6c, 0x6f, 0x67, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x2d, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72,
+	0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x53, 0x79, 0x73, 0x49,
+	0x6e, 0x66, 0x6f, 0x2e, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x70, 0x72,
+	0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x3a, 0x0a, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x53,
+	0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x3e, 0x0a, 0x0c, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x2e, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x4a, 0x6f,
+	0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x06, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x22, 0x9b, 0x01, 0x0a, 0x0c, 0x44, 0x6d, 0x65, 0x73, 0x67, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f,
+	0x2e, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72,
+	0x69, 0x74, 0x79, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05,
+	0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x72, 0x65, 0x70, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x72, 0x65, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c,
+	0x6c, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f,
+	0x77, 0x22, 0x83, 0x03, 0x0a, 0x0b, 0x44, 0x6d, 0x65, 0x73, 0x67, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x31, 0x0a, 0x06, 0x75,
+	0x70, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x2d,
+	0x0a, 0x08, 0x66, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x11, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x46, 0x61, 0x63, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x52, 0x08, 0x66, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x2d, 0x0a,
+	0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x11, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08,
+	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08,
+	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x38, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x44, 0x6d, 0x65,
+	0x73, 0x67, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x1a, 0x39, 0x0a, 0x0b,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x3a, 0x0a, 0x0a, 0x44, 0x6d, 0x65, 0x73, 0x67,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2c, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e,
+	0x44, 0x6d, 0x65, 0x73, 0x67, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x06, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x22, 0x21, 0x0a, 0x0d, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x03, 0x61, 0x6c, 0x6c, 0x22, 0xb1, 0x01, 0x0a, 0x0f, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x75, 0x73,
+	0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x69, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x69, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x6f, 0x64,
+	0x65, 0x73, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x69,
+	0x6e, 0x6f, 0x64, 0x65, 0x73, 0x55, 0x73, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x6f,
+	0x64, 0x65, 0x73, 0x5f, 0x66, 0x72, 0x65, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
+	0x69, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x46, 0x72, 0x65, 0x65, 0x22, 0xc4, 0x01, 0x0a, 0x05, 0x4d,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x17, 0x0a,
+	0x07, 0x66, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x66, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x2e, 0x0a, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x75, 0x73, 0x61, 0x67, 0x65, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x35, 0x0a, 0x0b, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x26, 0x0a, 0x06, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x4d, 0x6f, 0x75, 0x6e, 0x74,
+	0x52, 0x06, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x22, 0x6b, 0x0a, 0x10, 0x44, 0x69, 0x73, 0x6b,
+	0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12, 0x26, 0x0a,
+	0x0f, 0x6f, 0x6e, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x6f, 0x6e, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x53,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x22, 0x73, 0x0a, 0x0e, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x73, 0x61,
+	0x67, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12,
+	0x23, 0x0a, 0x0d, 0x61, 0x70, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x61, 0x70, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x5b, 0x0a, 0x0e, 0x44, 0x69,
+	0x73, 0x6b, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x31, 0x0a, 0x07,
+	0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x11, 0x0a, 0x0f, 0x54, 0x69, 0x6d, 0x65, 0x53,
+	0x79, 0x6e, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xfe, 0x01, 0x0a, 0x0d, 0x54,
+	0x69, 0x6d, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x06,
+	0x64, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x53,
+	0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x44, 0x61, 0x65, 0x6d, 0x6f,
+	0x6e, 0x52, 0x06, 0x64, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x79, 0x6e,
+	0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0c, 0x73, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x64, 0x12, 0x31, 0x0a,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x74, 0x72, 0x61, 0x74, 0x75, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x73, 0x74, 0x72, 0x61, 0x74, 0x75, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x79, 0x6e, 0x63, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x79, 0x6e, 0x63, 0x22, 0x0d, 0x0a, 0x0b, 0x47,
+	0x50, 0x55, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4b, 0x0a, 0x09, 0x45, 0x43,
+	0x43, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x72, 0x72, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x6f, 0x72, 0x72,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x6e, 0x63, 0x6f, 0x72, 0x72, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x75, 0x6e, 0x63, 0x6f,
+	0x72, 0x72, 0x65, 0x63, 0x74, 0x65, 0x64, 0x22, 0xbb, 0x02, 0x0a, 0x03, 0x47, 0x50, 0x55, 0x12,
+	0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a,
+	0x0a, 0x70, 0x63, 0x69, 0x5f, 0x62, 0x75, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x70, 0x63, 0x69, 0x42, 0x75, 0x73, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x75,
+	0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0b, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a,
+	0x0c, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x54, 0x6f, 0x74, 0x61, 0x6c,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x65,
+	0x64, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x64, 0x72, 0x61,
+	0x77, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x44, 0x72,
+	0x61, 0x77, 0x12, 0x31, 0x0a, 0x0a, 0x65, 0x63, 0x63, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f,
+	0x2e, 0x45, 0x43, 0x43, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x52, 0x09, 0x65, 0x63, 0x63, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x7a, 0x0a, 0x09, 0x47, 0x50, 0x55, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x12, 0x24, 0x0a, 0x04, 0x74, 0x6f, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x10, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x50, 0x55, 0x54, 0x6f,
+	0x6f, 0x6c, 0x52, 0x04, 0x74, 0x6f, 0x6f, 0x6c, 0x12, 0x25, 0x0a, 0x0e, 0x64, 0x72, 0x69, 0x76,
+	0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x20, 0x0a, 0x04, 0x67, 0x70, 0x75, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x50, 0x55, 0x52, 0x04, 0x67, 0x70, 0x75,
+	0x73, 0x2a, 0xbf, 0x01, 0x0a, 0x08, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x14,
+	0x0a, 0x10, 0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f,
+	0x57, 0x4e, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59,
+	0x5f, 0x45, 0x4d, 0x45, 0x52, 0x47, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x52, 0x49, 0x4f,
+	0x52, 0x49, 0x54, 0x59, 0x5f, 0x41, 0x4c, 0x45, 0x52, 0x54, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d,
+	0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x43, 0x52, 0x49, 0x54, 0x10, 0x03, 0x12,
+	0x10, 0x0a, 0x0c, 0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x45, 0x52, 0x52, 0x10,
+	0x04, 0x12, 0x14, 0x0a, 0x10, 0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x57, 0x41,
+	0x52, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x05, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x52, 0x49, 0x4f, 0x52,
+	0x49, 0x54, 0x59, 0x5f, 0x4e, 0x4f, 0x54, 0x49, 0x43, 0x45, 0x10, 0x06, 0x12, 0x11, 0x0a, 0x0d,
+	0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x07, 0x12,
+	0x12, 0x0a, 0x0e, 0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x44, 0x45, 0x42, 0x55,
+	0x47, 0x10, 0x08, 0x2a, 0x9c, 0x03, 0x0a, 0x08, 0x46, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x12, 0x11, 0x0a, 0x0d, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x4b, 0x45, 0x52,
+	0x4e, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f,
+	0x55, 0x53, 0x45, 0x52, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49,
+	0x54, 0x59, 0x5f, 0x4d, 0x41, 0x49, 0x4c, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41, 0x43,
+	0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x44, 0x41, 0x45, 0x4d, 0x4f, 0x4e, 0x10, 0x03, 0x12, 0x11,
+	0x0a, 0x0d, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x41, 0x55, 0x54, 0x48, 0x10,
+	0x04, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x53, 0x59,
+	0x53, 0x4c, 0x4f, 0x47, 0x10, 0x05, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49,
+	0x54, 0x59, 0x5f, 0x4c, 0x50, 0x52, 0x10, 0x06, 0x12, 0x11, 0x0a, 0x0d, 0x46, 0x41, 0x43, 0x49,
+	0x4c, 0x49, 0x54, 0x59, 0x5f, 0x4e, 0x45, 0x57, 0x53, 0x10, 0x07, 0x12, 0x11, 0x0a, 0x0d, 0x46,
+	0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x55, 0x55, 0x43, 0x50, 0x10, 0x08, 0x12, 0x11,
+	0x0a, 0x0d, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x43, 0x52, 0x4f, 0x4e, 0x10,
+	0x09, 0x12, 0x15, 0x0a, 0x11, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x41, 0x55,
+	0x54, 0x48, 0x50, 0x52, 0x49, 0x56, 0x10, 0x0a, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x41, 0x43, 0x49,
+	0x4c, 0x49, 0x54, 0x59, 0x5f, 0x46, 0x54, 0x50, 0x10, 0x0b, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41,
+	0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x30, 0x10, 0x10, 0x12,
+	0x13, 0x0a, 0x0f, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x4c, 0x4f, 0x43, 0x41,
+	0x4c, 0x31, 0x10, 0x11, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59,
+	0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x32, 0x10, 0x12, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41, 0x43,
+	0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x33, 0x10, 0x13, 0x12, 0x13,
+	0x0a, 0x0f, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c,
+	0x34, 0x10, 0x14, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f,
+	0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x35, 0x10, 0x15, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41, 0x43, 0x49,
+	0x4c, 0x49, 0x54, 0x59, 0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x36, 0x10, 0x16, 0x12, 0x13, 0x0a,
+	0x0f, 0x46, 0x41, 0x43, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x37,
+	0x10, 0x17, 0x2a, 0x6b, 0x0a, 0x0a, 0x54, 0x69, 0x6d, 0x65, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e,
+	0x12, 0x14, 0x0a, 0x10, 0x54, 0x49, 0x4d, 0x45, 0x5f, 0x44, 0x41, 0x45, 0x4d, 0x4f, 0x4e, 0x5f,
+	0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x54, 0x49, 0x4d, 0x45, 0x5f, 0x44,
+	0x41, 0x45, 0x4d, 0x4f, 0x4e, 0x5f, 0x43, 0x48, 0x52, 0x4f, 0x4e, 0x59, 0x10, 0x01, 0x12, 0x14,
+	0x0a, 0x10, 0x54, 0x49, 0x4d, 0x45, 0x5f, 0x44, 0x41, 0x45, 0x4d, 0x4f, 0x4e, 0x5f, 0x4e, 0x54,
+	0x50, 0x44, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x54, 0x49, 0x4d, 0x45, 0x5f, 0x44, 0x41, 0x45,
+	0x4d, 0x4f, 0x4e, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x53, 0x59, 0x4e, 0x43, 0x44, 0x10, 0x03, 0x2a,
+	0x4c, 0x0a, 0x07, 0x47, 0x50, 0x55, 0x54, 0x6f, 0x6f, 0x6c, 0x12, 0x11, 0x0a, 0x0d, 0x47, 0x50,
+	0x55, 0x5f, 0x54, 0x4f, 0x4f, 0x4c, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x17, 0x0a,
+	0x13, 0x47, 0x50, 0x55, 0x5f, 0x54, 0x4f, 0x4f, 0x4c, 0x5f, 0x4e, 0x56, 0x49, 0x44, 0x49, 0x41,
+	0x5f, 0x53, 0x4d, 0x49, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x47, 0x50, 0x55, 0x5f, 0x54, 0x4f,
+	0x4f, 0x4c, 0x5f, 0x52, 0x4f, 0x43, 0x4d, 0x5f, 0x53, 0x4d, 0x49, 0x10, 0x02, 0x32, 0xa6, 0x03,
+	0x0a, 0x07, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x32, 0x0a, 0x04, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x14, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66,
+	0x6f, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a,
+	0x07, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x12, 0x17, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e,
+	0x66, 0x6f, 0x2e, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x15, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x4a, 0x6f, 0x75, 0x72,
+	0x6e, 0x61, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x37, 0x0a, 0x05,
+	0x44, 0x6d, 0x65, 0x73, 0x67, 0x12, 0x15, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e,
+	0x44, 0x6d, 0x65, 0x73, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x53,
+	0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x44, 0x6d, 0x65, 0x73, 0x67, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x38, 0x0a, 0x06, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12,
+	0x16, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66,
+	0x6f, 0x2e, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
+	0x41, 0x0a, 0x09, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x19, 0x2e, 0x53,
+	0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66,
+	0x6f, 0x2e, 0x44, 0x69, 0x73, 0x6b, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x12, 0x3e, 0x0a, 0x08, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x18,
+	0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x79, 0x6e,
+	0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e,
+	0x66, 0x6f, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x12, 0x32, 0x0a, 0x04, 0x47, 0x50, 0x55, 0x73, 0x12, 0x14, 0x2e, 0x53, 0x79, 0x73,
+	0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x50, 0x55, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x12, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x2e, 0x47, 0x50, 0x55, 0x73, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x36, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x53, 0x6e, 0x6f, 0x77, 0x66, 0x6c, 0x61, 0x6b, 0x65, 0x2d, 0x4c,
+	0x61, 0x62, 0x73, 0x2f, 0x73, 0x61, 0x6e, 0x73, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x2f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2f, 0x73, 0x79, 0x73, 0x69, 0x6e, 0x66, 0x6f, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_sysinfo_proto_rawDescOnce sync.Once
+	file_sysinfo_proto_rawDescData = file_sysinfo_proto_rawDesc
+)
+
+func file_sysinfo_proto_rawDescGZIP() []byte {
+	file_sysinfo_proto_rawDescOnce.Do(func() {
+		file_sysinfo_proto_rawDescData = protoimpl.X.CompressGZIP(file_sysinfo_proto_rawDescData)
+	})
+	return file_sysinfo_proto_rawDescData
+}
+
+var file_sysinfo_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_sysinfo_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_sysinfo_proto_goTypes = []interface{}{
+	(Priority)(0),                 // 0: SysInfo.Priority
+	(Facility)(0),                 // 1: SysInfo.Facility
+	(TimeDaemon)(0),               // 2: SysInfo.TimeDaemon
+	(GPUTool)(0),                  // 3: SysInfo.GPUTool
+	(*InfoRequest)(nil),           // 4: SysInfo.InfoRequest
+	(*OSRelease)(nil),             // 5: SysInfo.OSRelease
+	(*LoadAverage)(nil),           // 6: SysInfo.LoadAverage
+	(*InfoReply)(nil),             // 7: SysInfo.InfoReply
+	(*JournalRequest)(nil),        // 8: SysInfo.JournalRequest
+	(*JournalRecord)(nil),         // 9: SysInfo.JournalRecord
+	(*JournalReply)(nil),          // 10: SysInfo.JournalReply
+	(*DmesgRequest)(nil),          // 11: SysInfo.DmesgRequest
+	(*DmesgRecord)(nil),           // 12: SysInfo.DmesgRecord
+	(*DmesgReply)(nil),            // 13: SysInfo.DmesgReply
+	(*MountsRequest)(nil),         // 14: SysInfo.MountsRequest
+	(*FilesystemUsage)(nil),       // 15: SysInfo.FilesystemUsage
+	(*Mount)(nil),                 // 16: SysInfo.Mount
+	(*MountsReply)(nil),           // 17: SysInfo.MountsReply
+	(*DiskUsageRequest)(nil),      // 18: SysInfo.DiskUsageRequest
+	(*DiskUsageEntry)(nil),        // 19: SysInfo.DiskUsageEntry
+	(*DiskUsageReply)(nil),        // 20: SysInfo.DiskUsageReply
+	(*TimeSyncRequest)(nil),       // 21: SysInfo.TimeSyncRequest
+	(*TimeSyncReply)(nil),         // 22: SysInfo.TimeSyncReply
+	(*GPUsRequest)(nil),           // 23: SysInfo.GPUsRequest
+	(*ECCErrors)(nil),             // 24: SysInfo.ECCErrors
+	(*GPU)(nil),                   // 25: SysInfo.GPU
+	(*GPUsReply)(nil),             // 26: SysInfo.GPUsReply
+	nil,                           // 27: SysInfo.JournalRecord.FieldsEntry
+	nil,                           // 28: SysInfo.DmesgRecord.FieldsEntry
+	(*durationpb.Duration)(nil),   // 29: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 30: google.protobuf.Timestamp
+}
+var file_sysinfo_proto_depIdxs = []int32{
+	29, // 0: SysInfo.InfoReply.uptime:type_name -> google.protobuf.Duration
+	30, // 1: SysInfo.InfoReply.boot_time:type_name -> google.protobuf.Timestamp
+	5,  // 2: SysInfo.InfoReply.os_release:type_name -> SysInfo.OSRelease
+	6,  // 3: SysInfo.InfoReply.load_average:type_name -> SysInfo.LoadAverage
+	0,  // 4: SysInfo.JournalRequest.priority:type_name -> SysInfo.Priority
+	30, // 5: SysInfo.JournalRequest.since:type_name -> google.protobuf.Timestamp
+	30, // 6: SysInfo.JournalRequest.until:type_name -> google.protobuf.Timestamp
+	30, // 7: SysInfo.JournalRecord.realtime_timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 8: SysInfo.JournalRecord.priority:type_name -> SysInfo.Priority
+	27, // 9: SysInfo.JournalRecord.fields:type_name -> SysInfo.JournalRecord.FieldsEntry
+	9,  // 10: SysInfo.JournalReply.record:type_name -> SysInfo.JournalRecord
+	0,  // 11: SysInfo.DmesgRequest.priority:type_name -> SysInfo.Priority
+	30, // 12: SysInfo.DmesgRequest.since:type_name -> google.protobuf.Timestamp
+	30, // 13: SysInfo.DmesgRecord.timestamp:type_name -> google.protobuf.Timestamp
+	29, // 14: SysInfo.DmesgRecord.uptime:type_name -> google.protobuf.Duration
+	1,  // 15: SysInfo.DmesgRecord.facility:type_name -> SysInfo.Facility
+	0,  // 16: SysInfo.DmesgRecord.priority:type_name -> SysInfo.Priority
+	28, // 17: SysInfo.DmesgRecord.fields:type_name -> SysInfo.DmesgRecord.FieldsEntry
+	12, // 18: SysInfo.DmesgReply.record:type_name -> SysInfo.DmesgRecord
+	15, // 19: SysInfo.Mount.usage:type_name -> SysInfo.FilesystemUsage
+	16, // 20: SysInfo.MountsReply.mounts:type_name -> SysInfo.Mount
+	19, // 21: SysInfo.DiskUsageReply.entries:type_name -> SysInfo.DiskUsageEntry
+	2,  // 22: SysInfo.TimeSyncReply.daemon:type_name -> SysInfo.TimeDaemon
+	29, // 23: SysInfo.TimeSyncReply.offset:type_name -> google.protobuf.Duration
+	30, // 24: SysInfo.TimeSyncReply.last_sync:type_name -> google.protobuf.Timestamp
+	24, // 25: SysInfo.GPU.ecc_errors:type_name -> SysInfo.ECCErrors
+	3,  // 26: SysInfo.GPUsReply.tool:type_name -> SysInfo.GPUTool
+	25, // 27: SysInfo.GPUsReply.gpus:type_name -> SysInfo.GPU
+	4,  // 28: SysInfo.SysInfo.Info:input_type -> SysInfo.InfoRequest
+	8,  // 29: SysInfo.SysInfo.Journal:input_type -> SysInfo.JournalRequest
+	11, // 30: SysInfo.SysInfo.Dmesg:input_type -> SysInfo.DmesgRequest
+	14, // 31: SysInfo.SysInfo.Mounts:input_type -> SysInfo.MountsRequest
+	18, // 32: SysInfo.SysInfo.DiskUsage:input_type -> SysInfo.DiskUsageRequest
+	21, // 33: SysInfo.SysInfo.TimeSync:input_type -> SysInfo.TimeSyncRequest
+	23, // 34: SysInfo.SysInfo.GPUs:input_type -> SysInfo.GPUsRequest
+	7,  // 35: SysInfo.SysInfo.Info:output_type -> SysInfo.InfoReply
+	10, // 36: SysInfo.SysInfo.Journal:output_type -> SysInfo.JournalReply
+	13, // 37: SysInfo.SysInfo.Dmesg:output_type -> SysInfo.DmesgReply
+	17, // 38: SysInfo.SysInfo.Mounts:output_type -> SysInfo.MountsReply
+	20, // 39: SysInfo.SysInfo.DiskUsage:output_type -> SysInfo.DiskUsageReply
+	22, // 40: SysInfo.SysInfo.TimeSync:output_type -> SysInfo.TimeSyncReply
+	26, // 41: SysInfo.SysInfo.GPUs:output_type -> SysInfo.GPUsReply
+	35, // [35:42] is the sub-list for method output_type
+	28, // [28:35] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
+}
+
+func init() { file_sysinfo_proto_init() }
+func file_sysinfo_proto_init() {
+	if File_sysinfo_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_sysinfo_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OSRelease); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadAverage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InfoReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JournalRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JournalRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JournalReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DmesgRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DmesgRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DmesgReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MountsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilesystemUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Mount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MountsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiskUsageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiskUsageEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiskUsageReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TimeSyncRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TimeSyncReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GPUsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ECCErrors); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GPU); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GPUsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_sysinfo_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sysinfo_proto_goTypes,
+		DependencyIndexes: file_sysinfo_proto_depIdxs,
+		EnumInfos:         file_sysinfo_proto_enumTypes,
+		MessageInfos:      file_sysinfo_proto_msgTypes,
+	}.Build()
+	File_sysinfo_proto = out.File
+	file_sysinfo_proto_rawDesc = nil
+	file_sysinfo_proto_goTypes = nil
+	file_sysinfo_proto_depIdxs = nil
+}
@@ -0,0 +1,400 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package sysinfo
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// SysInfoClient is the client API for SysInfo service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SysInfoClient interface {
+	// Info returns basic information about the system such as uptime,
+	// kernel and OS versions, memory and load.
+	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoReply, error)
+	// Journal returns entries from the systemd journal, oldest first. In
+	// follow mode it then streams new entries as they're written until the
+	// RPC is cancelled or times out.
+	Journal(ctx context.Context, in *JournalRequest, opts ...grpc.CallOption) (SysInfo_JournalClient, error)
+	// Dmesg returns the records in the kernel ring buffer, oldest first. In
+	// follow mode it then streams new records as they're logged until the
+	// RPC is cancelled or times out.
+	Dmesg(ctx context.Context, in *DmesgRequest, opts ...grpc.CallOption) (SysInfo_DmesgClient, error)
+	// Mounts returns the mounted filesystems with their options and usage
+	// (as with df(1)).
+	Mounts(ctx context.Context, in *MountsRequest, opts ...grpc.CallOption) (*MountsReply, error)
+	// DiskUsage returns the space used under a path (as with du(1)) in total
+	// and for the directories down to a given depth.
+	DiskUsage(ctx context.Context, in *DiskUsageRequest, opts ...grpc.CallOption) (*DiskUsageReply, error)
+	// TimeSync returns the clock synchronization status from whichever of
+	// chrony, ntpd or systemd-timesyncd is running.
+	TimeSync(ctx context.Context, in *TimeSyncRequest, opts ...grpc.CallOption) (*TimeSyncReply, error)
+	// GPUs returns the status of each GPU from nvidia-smi or rocm-smi.
+	GPUs(ctx context.Context, in *GPUsRequest, opts ...grpc.CallOption) (*GPUsReply, error)
+}
+
+type sysInfoClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSysInfoClient(cc grpc.ClientConnInterface) SysInfoClient {
+	return &sysInfoClient{cc}
+}
+
+func (c *sysInfoClient) Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoReply, error) {
+	out := new(InfoReply)
+	err := c.cc.Invoke(ctx, "/SysInfo.SysInfo/Info", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sysInfoClient) Journal(ctx context.Context, in *JournalRequest, opts ...grpc.CallOption) (SysInfo_JournalClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SysInfo_ServiceDesc.Streams[0], "/SysInfo.SysInfo/Journal", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sysInfoJournalClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SysInfo_JournalClient interface {
+	Recv() (*JournalReply, error)
+	grpc.ClientStream
+}
+
+type sysInfoJournalClient struct {
+	grpc.ClientStream
+}
+
+func (x *sysInfoJournalClient) Recv() (*JournalReply, error) {
+	m := new(JournalReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sysInfoClient) Dmesg(ctx context.Context, in *DmesgRequest, opts ...grpc.CallOption) (SysInfo_DmesgClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SysInfo_ServiceDesc.Streams[1], "/SysInfo.SysInfo/Dmesg", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sysInfoDmesgClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SysInfo_DmesgClient interface {
+	Recv() (*DmesgReply, error)
+	grpc.ClientStream
+}
+
+type sysInfoDmesgClient struct {
+	grpc.ClientStream
+}
+
+func (x *sysInfoDmesgClient) Recv() (*DmesgReply, error) {
+	m := new(DmesgReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sysInfoClient) Mounts(ctx context.Context, in *MountsRequest, opts ...grpc.CallOption) (*MountsReply, error) {
+	out := new(MountsReply)
+	err := c.cc.Invoke(ctx, "/SysInfo.SysInfo/Mounts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sysInfoClient) DiskUsage(ctx context.Context, in *DiskUsageRequest, opts ...grpc.CallOption) (*DiskUsageReply, error) {
+	out := new(DiskUsageReply)
+	err := c.cc.Invoke(ctx, "/SysInfo.SysInfo/DiskUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sysInfoClient) TimeSync(ctx context.Context, in *TimeSyncRequest, opts ...grpc.CallOption) (*TimeSyncReply, error) {
+	out := new(TimeSyncReply)
+	err := c.cc.Invoke(ctx, "/SysInfo.SysInfo/TimeSync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sysInfoClient) GPUs(ctx context.Context, in *GPUsRequest, opts ...grpc.CallOption) (*GPUsReply, error) {
+	out := new(GPUsReply)
+	err := c.cc.Invoke(ctx, "/SysInfo.SysInfo/GPUs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SysInfoServer is the server API for SysInfo service.
+// All implementations should embed UnimplementedSysInfoServer
+// for forward compatibility
+type SysInfoServer interface {
+	// Info returns basic information about the system such as uptime,
+	// kernel and OS versions, memory and load.
+	Info(context.Context, *InfoRequest) (*InfoReply, error)
+	// Journal returns entries from the systemd journal, oldest first. In
+	// follow mode it then streams new entries as they're written until the
+	// RPC is cancelled or times out.
+	Journal(*JournalRequest, SysInfo_JournalServer) error
+	// Dmesg returns the records in the kernel ring buffer, oldest first. In
+	// follow mode it then streams new records as they're logged until the
+	// RPC is cancelled or times out.
+	Dmesg(*DmesgRequest, SysInfo_DmesgServer) error
+	// Mounts returns the mounted filesystems with their options and usage
+	// (as with df(1)).
+	Mounts(context.Context, *MountsRequest) (*MountsReply, error)
+	// DiskUsage returns the space used under a path (as with du(1)) in total
+	// and for the directories down to a given depth.
+	DiskUsage(context.Context, *DiskUsageRequest) (*DiskUsageReply, error)
+	// TimeSync returns the clock synchronization status from whichever of
+	// chrony, ntpd or systemd-timesyncd is running.
+	TimeSync(context.Context, *TimeSyncRequest) (*TimeSyncReply, error)
+	// GPUs returns the status of each GPU from nvidia-smi or rocm-smi.
+	GPUs(context.Context, *GPUsRequest) (*GPUsReply, error)
+}
+
+// UnimplementedSysInfoServer should be embedded to have forward compatible implementations.
+type UnimplementedSysInfoServer struct {
+}
+
+func (UnimplementedSysInfoServer) Info(context.Context, *InfoRequest) (*InfoReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Info not implemented")
+}
+func (UnimplementedSysInfoServer) Journal(*JournalRequest, SysInfo_JournalServer) error {
+	return status.Errorf(codes.Unimplemented, "method Journal not implemented")
+}
+func (UnimplementedSysInfoServer) Dmesg(*DmesgRequest, SysInfo_DmesgServer) error {
+	return status.Errorf(codes.Unimplemented, "method Dmesg not implemented")
+}
+func (UnimplementedSysInfoServer) Mounts(context.Context, *MountsRequest) (*MountsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Mounts not implemented")
+}
+func (UnimplementedSysInfoServer) DiskUsage(context.Context, *DiskUsageRequest) (*DiskUsageReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiskUsage not implemented")
+}
+func (UnimplementedSysInfoServer) TimeSync(context.Context, *TimeSyncRequest) (*TimeSyncReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TimeSync not implemented")
+}
+func (UnimplementedSysInfoServer) GPUs(context.Context, *GPUsRequest) (*GPUsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GPUs not implemented")
+}
+
+// UnsafeSysInfoServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SysInfoServer will
+// result in compilation errors.
+type UnsafeSysInfoServer interface {
+	mustEmbedUnimplementedSysInfoServer()
+}
+
+func RegisterSysInfoServer(s grpc.ServiceRegistrar, srv SysInfoServer) {
+	s.RegisterService(&SysInfo_ServiceDesc, srv)
+}
+
+func _SysInfo_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SysInfoServer).Info(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SysInfo.SysInfo/Info",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SysInfoServer).Info(ctx, req.(*InfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SysInfo_Journal_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JournalRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SysInfoServer).Journal(m, &sysInfoJournalServer{stream})
+}
+
+type SysInfo_JournalServer interface {
+	Send(*JournalReply) error
+	grpc.ServerStream
+}
+
+type sysInfoJournalServer struct {
+	grpc.ServerStream
+}
+
+func (x *sysInfoJournalServer) Send(m *JournalReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _SysInfo_Dmesg_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DmesgRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SysInfoServer).Dmesg(m, &sysInfoDmesgServer{stream})
+}
+
+type SysInfo_DmesgServer interface {
+	Send(*DmesgReply) error
+	grpc.ServerStream
+}
+
+type sysInfoDmesgServer struct {
+	grpc.ServerStream
+}
+
+func (x *sysInfoDmesgServer) Send(m *DmesgReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _SysInfo_Mounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SysInfoServer).Mounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SysInfo.SysInfo/Mounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SysInfoServer).Mounts(ctx, req.(*MountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SysInfo_DiskUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiskUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SysInfoServer).DiskUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SysInfo.SysInfo/DiskUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SysInfoServer).DiskUsage(ctx, req.(*DiskUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SysInfo_TimeSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeSyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SysInfoServer).TimeSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SysInfo.SysInfo/TimeSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SysInfoServer).TimeSync(ctx, req.(*TimeSyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SysInfo_GPUs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GPUsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SysInfoServer).GPUs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/SysInfo.SysInfo/GPUs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SysInfoServer).GPUs(ctx, req.(*GPUsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SysInfo_ServiceDesc is the grpc.ServiceDesc for SysInfo service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SysInfo_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "SysInfo.SysInfo",
+	HandlerType: (*SysInfoServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Info",
+			Handler:    _SysInfo_Info_Handler,
+		},
+		{
+			MethodName: "Mounts",
+			Handler:    _SysInfo_Mounts_Handler,
+		},
+		{
+			MethodName: "DiskUsage",
+			Handler:    _SysInfo_DiskUsage_Handler,
+		},
+		{
+			MethodName: "TimeSync",
+			Handler:    _SysInfo_TimeSync_Handler,
+		},
+		{
+			MethodName: "GPUs",
+			Handler:    _SysInfo_GPUs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Journal",
+			Handler:       _SysInfo_Journal_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Dmesg",
+			Handler:       _SysInfo_Dmesg_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "sysinfo.proto",
+}
@@ -0,0 +1,365 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package client provides the client interface for 'users'
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Snowflake-Labs/sansshell/client"
+	pb "github.com/Snowflake-Labs/sansshell/services/users"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const subPackage = "users"
+
+func init() {
+	client.RegisterSubpackage(subPackage, setup)
+}
+
+func setup(f *flag.FlagSet) *subcommands.Commander {
+	c := client.SetupSubpackage(subPackage, f)
+	c.Register(&groupCmd{}, "")
+	c.Register(&groupsCmd{}, "")
+	c.Register(&loginsCmd{}, "")
+	c.Register(&membersCmd{}, "")
+	c.Register(&passwdCmd{}, "")
+	return c
+}
+
+func passwordStateString(s pb.PasswordState) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(s.String(), "PASSWORD_STATE_"), "_", " "))
+}
+
+// printUser prints a user as a passwd entry, with the password state
+// in place of the password.
+func printUser(out io.Writer, u *pb.User) {
+	fmt.Fprintf(out, "%s:%s:%d:%d:%s:%s:%s\n", u.Name, passwordStateString(u.PasswordState), u.Uid, u.Gid, u.Gecos, u.Home, u.Shell)
+}
+
+func printGroup(out io.Writer, g *pb.Group) {
+	fmt.Fprintf(out, "%s:x:%d:%s\n", g.Name, g.Gid, strings.Join(g.Members, ","))
+}
+
+// groupName returns the name of g, or its ID if it's unknown.
+func groupName(g *pb.Group) string {
+	if g.Name == "" {
+		return fmt.Sprint(g.Gid)
+	}
+	return g.Name
+}
+
+// emitRPCError reports an error starting an RPC to every target.
+func emitRPCError(state *util.ExecuteState, cmd string, err error) {
+	// Emit this to every error file as it's not specific to a given target.
+	for _, e := range state.Err {
+		fmt.Fprintf(e, "error executing '%s': %v\n", cmd, err)
+	}
+}
+
+type passwdCmd struct{}
+
+func (*passwdCmd) Name() string     { return "passwd" }
+func (*passwdCmd) Synopsis() string { return "Print users' passwd entries" }
+func (*passwdCmd) Usage() string {
+	return `passwd [<user>...]:
+    Print the passwd entry of each user (by name or UID), or of all the
+    users which can be listed if none are given, as getent passwd does.
+    In place of the password is whether one is set, locked or empty.
+`
+}
+
+func (*passwdCmd) SetFlags(f *flag.FlagSet) {}
+
+func (p *passwdCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewUsersClientProxy(state.Conn)
+	retCode := subcommands.ExitSuccess
+	if f.NArg() == 0 {
+		respChan, err := c.ListUsersOneMany(ctx, &pb.ListUsersRequest{})
+		if err != nil {
+			emitRPCError(state, "passwd", err)
+			return subcommands.ExitFailure
+		}
+		for r := range respChan {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			for _, u := range r.Resp.Users {
+				printUser(state.Out[r.Index], u)
+			}
+		}
+		return retCode
+	}
+
+	for _, user := range f.Args() {
+		respChan, err := c.GetUserOneMany(ctx, &pb.GetUserRequest{User: user})
+		if err != nil {
+			emitRPCError(state, "passwd", err)
+			return subcommands.ExitFailure
+		}
+		for r := range respChan {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			printUser(state.Out[r.Index], r.Resp)
+		}
+	}
+	return retCode
+}
+
+type groupCmd struct{}
+
+func (*groupCmd) Name() string     { return "group" }
+func (*groupCmd) Synopsis() string { return "Print group entries" }
+func (*groupCmd) Usage() string {
+	return `group [<group>...]:
+    Print the entry of each group (by name or GID), or of all the groups
+    which can be listed if none are given, as getent group does.
+`
+}
+
+func (*groupCmd) SetFlags(f *flag.FlagSet) {}
+
+func (g *groupCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	c := pb.NewUsersClientProxy(state.Conn)
+	retCode := subcommands.ExitSuccess
+	if f.NArg() == 0 {
+		respChan, err := c.ListGroupsOneMany(ctx, &pb.ListGroupsRequest{})
+		if err != nil {
+			emitRPCError(state, "group", err)
+			return subcommands.ExitFailure
+		}
+		for r := range respChan {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			for _, g := range r.Resp.Groups {
+				printGroup(state.Out[r.Index], g)
+			}
+		}
+		return retCode
+	}
+
+	for _, group := range f.Args() {
+		respChan, err := c.GetGroupOneMany(ctx, &pb.GetGroupRequest{Group: group})
+		if err != nil {
+			emitRPCError(state, "group", err)
+			return subcommands.ExitFailure
+		}
+		for r := range respChan {
+			if r.Error != nil {
+				fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+				retCode = subcommands.ExitFailure
+				continue
+			}
+			printGroup(state.Out[r.Index], r.Resp)
+		}
+	}
+	return retCode
+}
+
+type groupsCmd struct{}
+
+func (*groupsCmd) Name() string     { return "groups" }
+func (*groupsCmd) Synopsis() string { return "Print the groups a user is in" }
+func (*groupsCmd) Usage() string {
+	return `groups <user>:
+    Print the groups the user is in as groups(1) does, primary group first.
+`
+}
+
+func (*groupsCmd) SetFlags(f *flag.FlagSet) {}
+
+func (g *groupsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify a user.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, g)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewUsersClientProxy(state.Conn)
+	respChan, err := c.UserGroupsOneMany(ctx, &pb.UserGroupsRequest{User: f.Arg(0)})
+	if err != nil {
+		emitRPCError(state, "groups", err)
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		var names []string
+		for _, group := range r.Resp.Groups {
+			names = append(names, groupName(group))
+		}
+		fmt.Fprintf(state.Out[r.Index], "%s : %s\n", r.Resp.User.Name, strings.Join(names, " "))
+	}
+	return retCode
+}
+
+type membersCmd struct{}
+
+func (*membersCmd) Name() string     { return "members" }
+func (*membersCmd) Synopsis() string { return "Print the members of a group" }
+func (*membersCmd) Usage() string {
+	return `members <group>:
+    Print the users in the group, including those with it as their primary
+    group.
+`
+}
+
+func (*membersCmd) SetFlags(f *flag.FlagSet) {}
+
+func (m *membersCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	if f.NArg() != 1 {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, "Please specify a group.")
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, m)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewUsersClientProxy(state.Conn)
+	respChan, err := c.GroupMembersOneMany(ctx, &pb.GroupMembersRequest{Group: f.Arg(0)})
+	if err != nil {
+		emitRPCError(state, "members", err)
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		fmt.Fprintf(state.Out[r.Index], "%s : %s\n", r.Resp.Group.Name, strings.Join(r.Resp.Members, " "))
+	}
+	return retCode
+}
+
+type loginsCmd struct {
+	user  string
+	since string
+	limit uint
+}
+
+func (*loginsCmd) Name() string     { return "logins" }
+func (*loginsCmd) Synopsis() string { return "Print recent, failed and active logins" }
+func (*loginsCmd) Usage() string {
+	return `logins [--user <user>] [--since <time>] [--limit <n>]:
+    Print the recent logins and failed logins (most recent first) and the
+    sessions currently logged in, as last, lastb and w do. Times are in
+    RFC3339 format or a duration before now (i.e. 24h).
+`
+}
+
+func (l *loginsCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&l.user, "user", "", "If set only print sessions of this user")
+	f.StringVar(&l.since, "since", "", "If set only print logins since this time")
+	f.UintVar(&l.limit, "limit", 0, "Print at most this many logins and failed logins. If unset the remote side picks (100)")
+}
+
+// parseTime parses an RFC3339 time or a duration before now.
+func parseTime(val string) (*timestamppb.Timestamp, error) {
+	if val == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(val); err == nil {
+		return timestamppb.New(time.Now().Add(-d)), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return nil, fmt.Errorf("%s isn't an RFC3339 time or a duration", val)
+	}
+	return timestamppb.New(t), nil
+}
+
+func formatTime(t *timestamppb.Timestamp) string {
+	return t.AsTime().Local().Format(time.RFC3339)
+}
+
+func printSession(out io.Writer, s *pb.Session) {
+	from := s.Host
+	if s.Address != "" && s.Address != s.Host {
+		from = fmt.Sprintf("%s (%s)", s.Host, s.Address)
+	}
+	line := fmt.Sprintf("%-12s %-12s %-30s %s", s.User, s.Line, from, formatTime(s.LoginTime))
+	switch {
+	case s.Idle != nil:
+		line += fmt.Sprintf(" idle %v", s.Idle.AsDuration())
+	case s.LogoutTime != nil:
+		line += " - " + formatTime(s.LogoutTime)
+	}
+	fmt.Fprintln(out, line)
+}
+
+func (l *loginsCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	state := args[0].(*util.ExecuteState)
+	since, err := parseTime(l.since)
+	if err != nil {
+		fmt.Fprintln(subcommands.DefaultCommander.Error, err)
+		subcommands.DefaultCommander.ExplainCommand(subcommands.DefaultCommander.Error, l)
+		return subcommands.ExitUsageError
+	}
+
+	c := pb.NewUsersClientProxy(state.Conn)
+	respChan, err := c.LoginsOneMany(ctx, &pb.LoginsRequest{User: l.user, Since: since, Limit: uint32(l.limit)})
+	if err != nil {
+		emitRPCError(state, "logins", err)
+		return subcommands.ExitFailure
+	}
+	retCode := subcommands.ExitSuccess
+	for r := range respChan {
+		if r.Error != nil {
+			fmt.Fprintf(state.Err[r.Index], "target %s (%d) error: %v\n", r.Target, r.Index, r.Error)
+			retCode = subcommands.ExitFailure
+			continue
+		}
+		out := state.Out[r.Index]
+		fmt.Fprintln(out, "# active")
+		for _, s := range r.Resp.Active {
+			printSession(out, s)
+		}
+		fmt.Fprintln(out, "# logins")
+		for _, s := range r.Resp.Logins {
+			printSession(out, s)
+		}
+		fmt.Fprintln(out, "# failed logins")
+		for _, s := range r.Resp.FailedLogins {
+			printSession(out, s)
+		}
+	}
+	return retCode
+}
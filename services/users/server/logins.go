@@ -0,0 +1,306 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/users"
+)
+
+const (
+	// defaultLoginsLimit is how many logins are returned if the request
+	// doesn't say.
+	defaultLoginsLimit = 100
+
+	// utmpSize is the size of a glibc struct utmp (see utmp(5)), which is
+	// the same on 32 and 64 bit systems.
+	utmpSize = 384
+
+	// ut_type values.
+	utmpBootTime    = 2
+	utmpUserProcess = 7
+	utmpDeadProcess = 8
+)
+
+var (
+	// nativeEndian is the byte order utmp files are written in.
+	nativeEndian = func() binary.ByteOrder {
+		x := uint16(1)
+		if *(*byte)(unsafe.Pointer(&x)) == 1 {
+			return binary.LittleEndian
+		}
+		return binary.BigEndian
+	}()
+
+	// devDir contains the terminals of active sessions, for idle times.
+	devDir = "/dev"
+)
+
+// utmpRecord is the useful part of a struct utmp.
+type utmpRecord struct {
+	typ  int16
+	pid  int32
+	line string
+	user string
+	host string
+	addr string
+	time time.Time
+}
+
+// cString returns the NUL terminated string at the start of b.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// parseUtmp decodes a struct utmp, which is laid out as:
+//
+//	short   ut_type;        offset 0
+//	pid_t   ut_pid;         offset 4
+//	char    ut_line[32];    offset 8
+//	char    ut_id[4];       offset 40
+//	char    ut_user[32];    offset 44
+//	char    ut_host[256];   offset 76
+//	short   ut_exit[2];     offset 332
+//	int32_t ut_session;     offset 336
+//	int32_t ut_tv[2];       offset 340
+//	int32_t ut_addr_v6[4];  offset 348
+//	char    reserved[20];   offset 364
+func parseUtmp(b []byte) utmpRecord {
+	r := utmpRecord{
+		typ:  int16(nativeEndian.Uint16(b[0:])),
+		pid:  int32(nativeEndian.Uint32(b[4:])),
+		line: cString(b[8:40]),
+		user: cString(b[44:76]),
+		host: cString(b[76:332]),
+		time: time.Unix(int64(int32(nativeEndian.Uint32(b[340:]))), int64(int32(nativeEndian.Uint32(b[344:])))*1000),
+	}
+	// The address is in network order. IPv4 addresses only use the
+	// first word.
+	addr := b[348:364]
+	switch {
+	case bytes.Equal(addr, make([]byte, 16)):
+	case bytes.Equal(addr[4:], make([]byte, 12)):
+		r.addr = net.IP(addr[:4]).String()
+	default:
+		r.addr = net.IP(addr).String()
+	}
+	return r
+}
+
+// readUtmp calls fn for each record in the utmp format file at path. A
+// missing file has no records.
+func readUtmp(path string, fn func(utmpRecord)) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	b := make([]byte, utmpSize)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			// A partial record at the end is a write in progress.
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+		fn(parseUtmp(b))
+	}
+}
+
+func (r utmpRecord) session() *pb.Session {
+	return &pb.Session{
+		User:      r.user,
+		Line:      r.line,
+		Host:      r.host,
+		Address:   r.addr,
+		Pid:       r.pid,
+		LoginTime: timestamppb.New(r.time),
+	}
+}
+
+// loginFilter decides which sessions a request wants.
+type loginFilter struct {
+	user  string
+	since time.Time
+	limit int
+}
+
+func (f loginFilter) match(r utmpRecord) bool {
+	return (f.user == "" || r.user == f.user) && !r.time.Before(f.since)
+}
+
+// keep returns the newest sessions, up to the limit, most recent first.
+func (f loginFilter) keep(sessions []*pb.Session) []*pb.Session {
+	if len(sessions) > f.limit {
+		sessions = sessions[len(sessions)-f.limit:]
+	}
+	out := make([]*pb.Session, len(sessions))
+	for i, s := range sessions {
+		out[len(sessions)-1-i] = s
+	}
+	return out
+}
+
+// wtmpLogins returns the logins (and reboots) in wtmp matching f, pairing
+// them with their logouts as last does.
+func wtmpLogins(f loginFilter) ([]*pb.Session, error) {
+	var logins []*pb.Session
+	// Sessions not yet logged out, by terminal.
+	open := make(map[string]*pb.Session)
+	err := readUtmp(*wtmpPath, func(r utmpRecord) {
+		switch r.typ {
+		case utmpUserProcess:
+			s := r.session()
+			open[r.line] = s
+			if f.match(r) {
+				logins = append(logins, s)
+			}
+		case utmpDeadProcess:
+			if s, ok := open[r.line]; ok {
+				s.LogoutTime = timestamppb.New(r.time)
+				delete(open, r.line)
+			}
+		case utmpBootTime:
+			for line, s := range open {
+				s.LogoutTime = timestamppb.New(r.time)
+				delete(open, line)
+			}
+			r.user = "reboot"
+			if f.match(r) {
+				logins = append(logins, r.session())
+			}
+		}
+		// Trim as we go as wtmp can be large. Open sessions dropped
+		// here are still updated through open, harmlessly.
+		if len(logins) > 2*f.limit {
+			logins = append([]*pb.Session(nil), logins[len(logins)-f.limit:]...)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.keep(logins), nil
+}
+
+// btmpLogins returns the failed logins in btmp matching f.
+func btmpLogins(f loginFilter) ([]*pb.Session, error) {
+	var failed []*pb.Session
+	err := readUtmp(*btmpPath, func(r utmpRecord) {
+		if f.match(r) {
+			s := r.session()
+			s.Pid = 0
+			failed = append(failed, s)
+			if len(failed) > 2*f.limit {
+				failed = append([]*pb.Session(nil), failed[len(failed)-f.limit:]...)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.keep(failed), nil
+}
+
+// activeSessions returns the sessions in utmp for user (if set), skipping
+// any left behind by processes which have exited.
+func activeSessions(user string) ([]*pb.Session, error) {
+	var active []*pb.Session
+	now := time.Now()
+	err := readUtmp(*utmpPath, func(r utmpRecord) {
+		if r.typ != utmpUserProcess || (user != "" && r.user != user) {
+			return
+		}
+		if r.pid > 0 && errors.Is(unix.Kill(int(r.pid), 0), unix.ESRCH) {
+			return
+		}
+		s := r.session()
+		if fi, err := os.Stat(filepath.Join(devDir, r.line)); r.line != "" && err == nil {
+			if idle := now.Sub(fi.ModTime()); idle > 0 {
+				s.Idle = durationpb.New(idle.Truncate(time.Second))
+			}
+		}
+		active = append(active, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].LoginTime.AsTime().Before(active[j].LoginTime.AsTime())
+	})
+	return active, nil
+}
+
+// Logins implements pb.UsersServer.Logins
+func (s *server) Logins(ctx context.Context, req *pb.LoginsRequest) (*pb.LoginsReply, error) {
+	if *wtmpPath == "" {
+		return nil, status.Error(codes.Unimplemented, "login history is not supported on this platform")
+	}
+	if req.User != "" {
+		if err := checkKey("user", req.User); err != nil {
+			return nil, err
+		}
+	}
+	f := loginFilter{user: req.User, limit: int(req.Limit)}
+	if f.limit == 0 {
+		f.limit = defaultLoginsLimit
+	}
+	if req.Since != nil {
+		if err := req.Since.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid since: %v", err)
+		}
+		f.since = req.Since.AsTime()
+	}
+
+	reply := &pb.LoginsReply{}
+	var err error
+	if reply.Logins, err = wtmpLogins(f); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read %s: %v", *wtmpPath, err)
+	}
+	if reply.FailedLogins, err = btmpLogins(f); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read %s: %v", *btmpPath, err)
+	}
+	if reply.Active, err = activeSessions(req.User); err != nil {
+		return nil, status.Errorf(codes.Internal, "can't read %s: %v", *utmpPath, err)
+	}
+	return reply, nil
+}
@@ -0,0 +1,182 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/users"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// utmp encodes a struct utmp.
+func utmp(typ int16, pid int32, line, user, host, addr string, t time.Time) []byte {
+	b := make([]byte, utmpSize)
+	nativeEndian.PutUint16(b[0:], uint16(typ))
+	nativeEndian.PutUint32(b[4:], uint32(pid))
+	copy(b[8:40], line)
+	copy(b[44:76], user)
+	copy(b[76:332], host)
+	nativeEndian.PutUint32(b[340:], uint32(t.Unix()))
+	nativeEndian.PutUint32(b[344:], uint32(t.Nanosecond()/1000))
+	if ip := net.ParseIP(addr); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			copy(b[348:], ip4)
+		} else {
+			copy(b[348:], ip)
+		}
+	}
+	return b
+}
+
+func writeUtmp(t *testing.T, path string, records ...[]byte) {
+	t.Helper()
+	var b []byte
+	for _, r := range records {
+		b = append(b, r...)
+	}
+	testutil.FatalOnErr("writing "+path, os.WriteFile(path, b, 0644), t)
+}
+
+func TestLogins(t *testing.T) {
+	savedUtmp, savedWtmp, savedBtmp, savedDev := *utmpPath, *wtmpPath, *btmpPath, devDir
+	t.Cleanup(func() {
+		*utmpPath, *wtmpPath, *btmpPath, devDir = savedUtmp, savedWtmp, savedBtmp, savedDev
+	})
+	dir := t.TempDir()
+	*utmpPath = filepath.Join(dir, "utmp")
+	*wtmpPath = filepath.Join(dir, "wtmp")
+	*btmpPath = filepath.Join(dir, "btmp")
+	devDir = filepath.Join(dir, "dev")
+	testutil.FatalOnErr("mkdir", os.MkdirAll(filepath.Join(devDir, "pts"), 0755), t)
+	testutil.FatalOnErr("tty", os.WriteFile(filepath.Join(devDir, "pts/1"), nil, 0644), t)
+	tty := time.Now().Add(-time.Hour)
+	testutil.FatalOnErr("chtimes", os.Chtimes(filepath.Join(devDir, "pts/1"), tty, tty), t)
+
+	base := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	ts := func(minutes int) *timestamppb.Timestamp { return timestamppb.New(at(minutes)) }
+
+	writeUtmp(t, *wtmpPath,
+		utmp(utmpBootTime, 0, "~", "reboot", "5.15.0", "", at(0)),
+		utmp(utmpUserProcess, 100, "pts/0", "alice", "10.0.0.1", "10.0.0.1", at(1)),
+		utmp(utmpUserProcess, 200, "pts/1", "bob", "bastion.example.com", "2001:db8::1", at(2)),
+		utmp(utmpDeadProcess, 100, "pts/0", "", "", "", at(3)),
+		utmp(utmpUserProcess, 300, "pts/0", "alice", "10.0.0.2", "10.0.0.2", at(4)),
+		utmp(utmpBootTime, 0, "~", "reboot", "5.15.0", "", at(5)),
+		utmp(utmpUserProcess, 400, "pts/1", "alice", "10.0.0.3", "10.0.0.3", at(6)),
+	)
+	writeUtmp(t, *btmpPath,
+		utmp(6, 0, "ssh:notty", "root", "192.0.2.1", "192.0.2.1", at(2)),
+		utmp(6, 0, "ssh:notty", "alice", "192.0.2.2", "192.0.2.2", at(3)),
+	)
+	// The test process is alive, the other pid isn't.
+	dead := int32(1 << 22)
+	writeUtmp(t, *utmpPath,
+		utmp(1, 0, "~", "runlevel", "5.15.0", "", at(0)),
+		utmp(utmpUserProcess, int32(os.Getpid()), "pts/1", "alice", "10.0.0.3", "10.0.0.3", at(6)),
+		utmp(utmpUserProcess, dead, "pts/2", "mallory", "192.0.2.9", "192.0.2.9", at(6)),
+		// A partial record is ignored.
+		[]byte{1, 2, 3},
+	)
+
+	reboot1 := &pb.Session{User: "reboot", Line: "~", Host: "5.15.0", LoginTime: ts(0)}
+	alice1 := &pb.Session{User: "alice", Line: "pts/0", Host: "10.0.0.1", Address: "10.0.0.1", Pid: 100, LoginTime: ts(1), LogoutTime: ts(3)}
+	bob := &pb.Session{User: "bob", Line: "pts/1", Host: "bastion.example.com", Address: "2001:db8::1", Pid: 200, LoginTime: ts(2), LogoutTime: ts(5)}
+	alice2 := &pb.Session{User: "alice", Line: "pts/0", Host: "10.0.0.2", Address: "10.0.0.2", Pid: 300, LoginTime: ts(4), LogoutTime: ts(5)}
+	reboot2 := &pb.Session{User: "reboot", Line: "~", Host: "5.15.0", LoginTime: ts(5)}
+	alice3 := &pb.Session{User: "alice", Line: "pts/1", Host: "10.0.0.3", Address: "10.0.0.3", Pid: 400, LoginTime: ts(6)}
+	failedRoot := &pb.Session{User: "root", Line: "ssh:notty", Host: "192.0.2.1", Address: "192.0.2.1", LoginTime: ts(2)}
+	failedAlice := &pb.Session{User: "alice", Line: "ssh:notty", Host: "192.0.2.2", Address: "192.0.2.2", LoginTime: ts(3)}
+	active := &pb.Session{User: "alice", Line: "pts/1", Host: "10.0.0.3", Address: "10.0.0.3", Pid: int32(os.Getpid()), LoginTime: ts(6)}
+
+	for _, tc := range []struct {
+		name    string
+		req     *pb.LoginsRequest
+		want    *pb.LoginsReply
+		wantErr codes.Code
+	}{
+		{
+			name: "all",
+			req:  &pb.LoginsRequest{},
+			want: &pb.LoginsReply{
+				Logins:       []*pb.Session{alice3, reboot2, alice2, bob, alice1, reboot1},
+				FailedLogins: []*pb.Session{failedAlice, failedRoot},
+				Active:       []*pb.Session{active},
+			},
+		},
+		{
+			name: "user",
+			req:  &pb.LoginsRequest{User: "alice"},
+			want: &pb.LoginsReply{
+				Logins:       []*pb.Session{alice3, alice2, alice1},
+				FailedLogins: []*pb.Session{failedAlice},
+				Active:       []*pb.Session{active},
+			},
+		},
+		{
+			name: "since and limit",
+			req:  &pb.LoginsRequest{Since: ts(2), Limit: 2},
+			want: &pb.LoginsReply{
+				Logins:       []*pb.Session{alice3, reboot2},
+				FailedLogins: []*pb.Session{failedAlice, failedRoot},
+				Active:       []*pb.Session{active},
+			},
+		},
+		{
+			name:    "bad user",
+			req:     &pb.LoginsRequest{User: "-a"},
+			wantErr: codes.InvalidArgument,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&server{}).Logins(context.Background(), tc.req)
+			if code := status.Code(err); code != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", code, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			// Idle time depends on when the test runs.
+			for _, s := range got.Active {
+				if idle := s.Idle.AsDuration(); idle < time.Hour || idle > 2*time.Hour {
+					t.Errorf("got idle %v want about an hour", idle)
+				}
+				s.Idle = nil
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+
+	// Missing files have no records.
+	*utmpPath = filepath.Join(dir, "missing")
+	*wtmpPath = filepath.Join(dir, "missing")
+	*btmpPath = filepath.Join(dir, "missing")
+	got, err := (&server{}).Logins(context.Background(), &pb.LoginsRequest{})
+	testutil.FatalOnErr("Logins", err, t)
+	testutil.DiffErr("missing files", got, &pb.LoginsReply{}, t)
+}
@@ -0,0 +1,380 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package server implements the sansshell 'Users' service.
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Snowflake-Labs/sansshell/services"
+	pb "github.com/Snowflake-Labs/sansshell/services/users"
+	"github.com/Snowflake-Labs/sansshell/services/util"
+)
+
+const (
+	// Exit codes from getent(1).
+	getentNotFound      = 2
+	getentNoEnumeration = 3
+)
+
+// server is used to implement the gRPC server
+type server struct{}
+
+// checkKey validates a user or group name (or ID) before passing it to
+// getent.
+func checkKey(what string, key string) error {
+	if key == "" {
+		return status.Errorf(codes.InvalidArgument, "%s must be set", what)
+	}
+	if strings.HasPrefix(key, "-") || strings.ContainsAny(key, ":,") || strings.IndexFunc(key, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}) >= 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid %s %q", what, key)
+	}
+	return nil
+}
+
+// getent returns the lines from getent for database and keys, or all the
+// entries if there are no keys. If only some keys are found it returns
+// their entries along with a NotFound error. Databases which can't be
+// enumerated return no entries.
+func getent(ctx context.Context, database string, keys ...string) ([]string, error) {
+	if *getentBin == "" {
+		return nil, status.Error(codes.Unimplemented, "user lookups are not supported on this platform")
+	}
+	run, err := util.RunCommand(ctx, *getentBin, append([]string{database}, keys...))
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, l := range strings.Split(run.Stdout.String(), "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	switch {
+	case run.Error == nil && run.ExitCode == 0:
+		return lines, nil
+	case run.ExitCode == getentNotFound:
+		return lines, status.Errorf(codes.NotFound, "%s not found in %s", strings.Join(keys, ","), database)
+	case run.ExitCode == getentNoEnumeration:
+		return nil, nil
+	default:
+		return nil, status.Errorf(codes.Internal, "error from getent %s: %v\nstderr:\n%s", database, run.Error, util.TrimString(run.Stderr.String()))
+	}
+}
+
+func parseID(s string) (uint32, error) {
+	id, err := strconv.ParseUint(s, 10, 32)
+	return uint32(id), err
+}
+
+// parsePasswd parses a passwd(5) line.
+func parsePasswd(line string) (*pb.User, error) {
+	f := strings.Split(line, ":")
+	if len(f) != 7 {
+		return nil, fmt.Errorf("invalid passwd entry %q", line)
+	}
+	uid, err := parseID(f[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid uid in passwd entry %q: %v", line, err)
+	}
+	gid, err := parseID(f[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid gid in passwd entry %q: %v", line, err)
+	}
+	return &pb.User{Name: f[0], Uid: uid, Gid: gid, Gecos: f[4], Home: f[5], Shell: f[6]}, nil
+}
+
+// parseGroup parses a group(5) line.
+func parseGroup(line string) (*pb.Group, error) {
+	f := strings.Split(line, ":")
+	if len(f) != 4 {
+		return nil, fmt.Errorf("invalid group entry %q", line)
+	}
+	gid, err := parseID(f[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid gid in group entry %q: %v", line, err)
+	}
+	g := &pb.Group{Name: f[0], Gid: gid}
+	for _, m := range strings.Split(f[3], ",") {
+		if m != "" {
+			g.Members = append(g.Members, m)
+		}
+	}
+	return g, nil
+}
+
+// parseShadow returns the user and password state from a shadow(5) line.
+// The hash is deliberately discarded.
+func parseShadow(line string) (string, pb.PasswordState, error) {
+	f := strings.Split(line, ":")
+	if len(f) < 2 {
+		return "", pb.PasswordState_PASSWORD_STATE_UNKNOWN, fmt.Errorf("invalid shadow entry for %s", f[0])
+	}
+	switch hash := f[1]; {
+	case hash == "":
+		return f[0], pb.PasswordState_PASSWORD_STATE_EMPTY, nil
+	case strings.HasPrefix(hash, "!") || strings.HasPrefix(hash, "*"):
+		return f[0], pb.PasswordState_PASSWORD_STATE_LOCKED, nil
+	default:
+		return f[0], pb.PasswordState_PASSWORD_STATE_SET, nil
+	}
+}
+
+// shadowStates maps users to their password states.
+type shadowStates map[string]pb.PasswordState
+
+// readShadow returns the password state of every user in shadow. It's
+// empty if shadow can't be read, i.e. if the server isn't root.
+func readShadow(ctx context.Context) shadowStates {
+	states := make(shadowStates)
+	lines, err := getent(ctx, "shadow")
+	if err != nil {
+		return states
+	}
+	for _, l := range lines {
+		if name, state, err := parseShadow(l); err == nil {
+			states[name] = state
+		}
+	}
+	return states
+}
+
+// state returns the password state of user.
+func (s shadowStates) state(user string) pb.PasswordState {
+	if len(s) == 0 {
+		return pb.PasswordState_PASSWORD_STATE_UNKNOWN
+	}
+	if state, ok := s[user]; ok {
+		return state
+	}
+	return pb.PasswordState_PASSWORD_STATE_NO_ENTRY
+}
+
+// lookupUser returns the passwd entry for user.
+func lookupUser(ctx context.Context, user string) (*pb.User, error) {
+	if err := checkKey("user", user); err != nil {
+		return nil, err
+	}
+	lines, err := getent(ctx, "passwd", user)
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Errorf(codes.NotFound, "no such user %s", user)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no such user %s", user)
+	}
+	u, err := parsePasswd(lines[0])
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return u, nil
+}
+
+// lookupGroup returns the group entry for group.
+func lookupGroup(ctx context.Context, group string) (*pb.Group, error) {
+	if err := checkKey("group", group); err != nil {
+		return nil, err
+	}
+	lines, err := getent(ctx, "group", group)
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Errorf(codes.NotFound, "no such group %s", group)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no such group %s", group)
+	}
+	g, err := parseGroup(lines[0])
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return g, nil
+}
+
+// listUsers returns all the users which can be enumerated.
+func listUsers(ctx context.Context) ([]*pb.User, error) {
+	lines, err := getent(ctx, "passwd")
+	if err != nil {
+		return nil, err
+	}
+	var users []*pb.User
+	for _, l := range lines {
+		u, err := parsePasswd(l)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+// GetUser implements pb.UsersServer.GetUser
+func (s *server) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	u, err := lookupUser(ctx, req.User)
+	if err != nil {
+		return nil, err
+	}
+	u.PasswordState = readShadow(ctx).state(u.Name)
+	return u, nil
+}
+
+// ListUsers implements pb.UsersServer.ListUsers
+func (s *server) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersReply, error) {
+	users, err := listUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states := readShadow(ctx)
+	for _, u := range users {
+		u.PasswordState = states.state(u.Name)
+	}
+	return &pb.ListUsersReply{Users: users}, nil
+}
+
+// GetGroup implements pb.UsersServer.GetGroup
+func (s *server) GetGroup(ctx context.Context, req *pb.GetGroupRequest) (*pb.Group, error) {
+	return lookupGroup(ctx, req.Group)
+}
+
+// ListGroups implements pb.UsersServer.ListGroups
+func (s *server) ListGroups(ctx context.Context, req *pb.ListGroupsRequest) (*pb.ListGroupsReply, error) {
+	lines, err := getent(ctx, "group")
+	if err != nil {
+		return nil, err
+	}
+	reply := &pb.ListGroupsReply{}
+	for _, l := range lines {
+		g, err := parseGroup(l)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		reply.Groups = append(reply.Groups, g)
+	}
+	return reply, nil
+}
+
+// UserGroups implements pb.UsersServer.UserGroups
+func (s *server) UserGroups(ctx context.Context, req *pb.UserGroupsRequest) (*pb.UserGroupsReply, error) {
+	u, err := lookupUser(ctx, req.User)
+	if err != nil {
+		return nil, err
+	}
+	u.PasswordState = readShadow(ctx).state(u.Name)
+
+	// initgroups gives the supplementary group IDs as "user gid gid...".
+	lines, err := getent(ctx, "initgroups", u.Name)
+	if err != nil {
+		return nil, err
+	}
+	gids := []string{strconv.FormatUint(uint64(u.Gid), 10)}
+	seen := map[string]bool{gids[0]: true}
+	for _, l := range lines {
+		f := strings.Fields(l)
+		for _, gid := range f[1:] {
+			if !seen[gid] {
+				seen[gid] = true
+				gids = append(gids, gid)
+			}
+		}
+	}
+
+	// Groups which can't be found are returned by ID only.
+	lines, err = getent(ctx, "group", gids...)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+	byGID := make(map[uint32]*pb.Group)
+	for _, l := range lines {
+		g, err := parseGroup(l)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		byGID[g.Gid] = g
+	}
+	reply := &pb.UserGroupsReply{User: u}
+	for _, id := range gids {
+		gid, err := parseID(id)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid gid %q from initgroups", id)
+		}
+		g, ok := byGID[gid]
+		if !ok {
+			g = &pb.Group{Gid: gid}
+		}
+		reply.Groups = append(reply.Groups, g)
+	}
+	// Sort by name with any unknown groups last.
+	others := reply.Groups[1:]
+	sort.SliceStable(others, func(i, j int) bool {
+		a, b := others[i].Name, others[j].Name
+		return a != "" && (b == "" || a < b)
+	})
+	return reply, nil
+}
+
+// GroupMembers implements pb.UsersServer.GroupMembers
+func (s *server) GroupMembers(ctx context.Context, req *pb.GroupMembersRequest) (*pb.GroupMembersReply, error) {
+	g, err := lookupGroup(ctx, req.Group)
+	if err != nil {
+		return nil, err
+	}
+	users, err := listUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	reply := &pb.GroupMembersReply{Group: g}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			reply.Members = append(reply.Members, name)
+		}
+	}
+	for _, m := range g.Members {
+		add(m)
+	}
+	for _, u := range users {
+		if u.Gid == g.Gid {
+			add(u.Name)
+		}
+	}
+	sort.Strings(reply.Members)
+	return reply, nil
+}
+
+// Register is called to expose this handler to the gRPC server
+func (s *server) Register(gs *grpc.Server) {
+	pb.RegisterUsersServer(gs, s)
+}
+
+func init() {
+	services.RegisterSansShellService(&server{})
+}
@@ -0,0 +1,31 @@
+//go:build linux
+// +build linux
+
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"flag"
+)
+
+var (
+	getentBin = flag.String("getent-bin", "/usr/bin/getent", "Path to the getent binary used to look up users and groups")
+	utmpPath  = flag.String("utmp-path", "/run/utmp", "Path to the utmp file listing active logins")
+	wtmpPath  = flag.String("wtmp-path", "/var/log/wtmp", "Path to the wtmp file recording logins")
+	btmpPath  = flag.String("btmp-path", "/var/log/btmp", "Path to the btmp file recording failed logins")
+)
@@ -0,0 +1,267 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Snowflake-Labs/sansshell/services/users"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+// fakeGetent points getentBin at a script which looks up entries in the
+// files in dir (named for each database) as getent does, or exits with
+// code if it's set.
+func fakeGetent(t *testing.T, dir string, code int) {
+	t.Helper()
+	saved := *getentBin
+	t.Cleanup(func() { *getentBin = saved })
+	dir, err := filepath.Abs(dir)
+	testutil.FatalOnErr("testdata path", err, t)
+	script := fmt.Sprintf(`#!/bin/sh
+[ %d -ne 0 ] && exit %d
+db=$1
+shift
+file=%s/$db
+[ -f "$file" ] || exit 1
+if [ $# -eq 0 ]; then
+  cat "$file"
+  exit 0
+fi
+rc=0
+for k in "$@"; do
+  case $db in
+  passwd|group) line=$(awk -F: -v k="$k" '$1 == k || $3 == k' "$file") ;;
+  shadow) line=$(awk -F: -v k="$k" '$1 == k' "$file") ;;
+  *) line=$(awk -v k="$k" '$1 == k' "$file") ;;
+  esac
+  if [ -n "$line" ]; then echo "$line"; else rc=2; fi
+done
+exit $rc
+`, code, code, dir)
+	*getentBin = filepath.Join(t.TempDir(), "getent")
+	testutil.FatalOnErr("writing getent", os.WriteFile(*getentBin, []byte(script), 0755), t)
+}
+
+// noShadow returns a copy of testdata without the shadow file, as if the
+// server couldn't read it.
+func noShadow(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range []string{"passwd", "group", "initgroups"} {
+		b, err := os.ReadFile(filepath.Join("testdata", f))
+		testutil.FatalOnErr("reading testdata", err, t)
+		testutil.FatalOnErr("writing testdata", os.WriteFile(filepath.Join(dir, f), b, 0644), t)
+	}
+	return dir
+}
+
+var (
+	root  = &pb.User{Name: "root", Uid: 0, Gid: 0, Gecos: "root", Home: "/root", Shell: "/bin/bash", PasswordState: pb.PasswordState_PASSWORD_STATE_LOCKED}
+	alice = &pb.User{Name: "alice", Uid: 1000, Gid: 1000, Gecos: "Alice Example,,,", Home: "/home/alice", Shell: "/bin/bash", PasswordState: pb.PasswordState_PASSWORD_STATE_SET}
+	bob   = &pb.User{Name: "bob", Uid: 1001, Gid: 100, Gecos: "Bob", Home: "/home/bob", Shell: "/bin/zsh", PasswordState: pb.PasswordState_PASSWORD_STATE_LOCKED}
+	carol = &pb.User{Name: "carol", Uid: 1002, Gid: 100, Home: "/home/carol", Shell: "/bin/sh", PasswordState: pb.PasswordState_PASSWORD_STATE_NO_ENTRY}
+)
+
+func TestGetUser(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		dir     string
+		code    int
+		user    string
+		want    *pb.User
+		wantErr codes.Code
+	}{
+		{name: "by name", user: "alice", want: alice},
+		{name: "by uid", user: "0", want: root},
+		{name: "locked", user: "bob", want: bob},
+		{name: "no shadow entry", user: "carol", want: carol},
+		{
+			name: "unreadable shadow",
+			dir:  "noshadow",
+			user: "alice",
+			want: &pb.User{Name: "alice", Uid: 1000, Gid: 1000, Gecos: "Alice Example,,,", Home: "/home/alice", Shell: "/bin/bash"},
+		},
+		{name: "missing", user: "mallory", wantErr: codes.NotFound},
+		{name: "empty", wantErr: codes.InvalidArgument},
+		{name: "flag", user: "-s", wantErr: codes.InvalidArgument},
+		{name: "several", user: "alice bob", wantErr: codes.InvalidArgument},
+		{name: "getent fails", user: "alice", code: 1, wantErr: codes.Internal},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := "testdata"
+			if tc.dir == "noshadow" {
+				dir = noShadow(t)
+			}
+			fakeGetent(t, dir, tc.code)
+			got, err := (&server{}).GetUser(context.Background(), &pb.GetUserRequest{User: tc.user})
+			if code := status.Code(err); code != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", code, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	fakeGetent(t, "testdata", 0)
+	got, err := (&server{}).ListUsers(context.Background(), &pb.ListUsersRequest{})
+	testutil.FatalOnErr("ListUsers", err, t)
+	daemon := &pb.User{Name: "daemon", Uid: 1, Gid: 1, Gecos: "daemon", Home: "/usr/sbin", Shell: "/usr/sbin/nologin", PasswordState: pb.PasswordState_PASSWORD_STATE_LOCKED}
+	testutil.DiffErr("ListUsers", got, &pb.ListUsersReply{Users: []*pb.User{root, daemon, alice, bob, carol}}, t)
+
+	// Enumeration not being supported isn't an error.
+	fakeGetent(t, "testdata", getentNoEnumeration)
+	got, err = (&server{}).ListUsers(context.Background(), &pb.ListUsersRequest{})
+	testutil.FatalOnErr("ListUsers", err, t)
+	testutil.DiffErr("ListUsers", got, &pb.ListUsersReply{}, t)
+}
+
+func TestGetGroup(t *testing.T) {
+	fakeGetent(t, "testdata", 0)
+	for _, tc := range []struct {
+		name    string
+		group   string
+		want    *pb.Group
+		wantErr codes.Code
+	}{
+		{name: "by name", group: "sudo", want: &pb.Group{Name: "sudo", Gid: 27, Members: []string{"alice", "carol"}}},
+		{name: "by gid", group: "0", want: &pb.Group{Name: "root"}},
+		{name: "missing", group: "wheel", wantErr: codes.NotFound},
+		{name: "invalid", group: "a:b", wantErr: codes.InvalidArgument},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&server{}).GetGroup(context.Background(), &pb.GetGroupRequest{Group: tc.group})
+			if code := status.Code(err); code != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", code, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
+
+func TestListGroups(t *testing.T) {
+	fakeGetent(t, "testdata", 0)
+	got, err := (&server{}).ListGroups(context.Background(), &pb.ListGroupsRequest{})
+	testutil.FatalOnErr("ListGroups", err, t)
+	var names []string
+	for _, g := range got.Groups {
+		names = append(names, g.Name)
+	}
+	testutil.DiffErr("ListGroups", names, []string{"root", "daemon", "users", "sudo", "docker", "alice"}, t)
+}
+
+func TestUserGroups(t *testing.T) {
+	fakeGetent(t, "testdata", 0)
+	for _, tc := range []struct {
+		name    string
+		user    string
+		want    *pb.UserGroupsReply
+		wantErr codes.Code
+	}{
+		{
+			name: "supplementary groups",
+			user: "alice",
+			want: &pb.UserGroupsReply{
+				User: alice,
+				Groups: []*pb.Group{
+					{Name: "alice", Gid: 1000},
+					{Name: "docker", Gid: 999, Members: []string{"alice"}},
+					{Name: "sudo", Gid: 27, Members: []string{"alice", "carol"}},
+					{Name: "users", Gid: 100, Members: []string{"alice"}},
+					{Gid: 4242},
+				},
+			},
+		},
+		{
+			name: "primary only",
+			user: "1001",
+			want: &pb.UserGroupsReply{
+				User:   bob,
+				Groups: []*pb.Group{{Name: "users", Gid: 100, Members: []string{"alice"}}},
+			},
+		},
+		{name: "missing", user: "mallory", wantErr: codes.NotFound},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&server{}).UserGroups(context.Background(), &pb.UserGroupsRequest{User: tc.user})
+			if code := status.Code(err); code != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", code, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got, tc.want, t)
+		})
+	}
+}
+
+func TestGroupMembers(t *testing.T) {
+	fakeGetent(t, "testdata", 0)
+	for _, tc := range []struct {
+		name    string
+		group   string
+		want    []string
+		wantErr codes.Code
+	}{
+		{name: "primary and listed", group: "users", want: []string{"alice", "bob", "carol"}},
+		{name: "listed", group: "sudo", want: []string{"alice", "carol"}},
+		{name: "primary", group: "1000", want: []string{"alice"}},
+		{name: "missing", group: "wheel", wantErr: codes.NotFound},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&server{}).GroupMembers(context.Background(), &pb.GroupMembersRequest{Group: tc.group})
+			if code := status.Code(err); code != tc.wantErr {
+				t.Fatalf("got code %v want %v err %v", code, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			testutil.DiffErr(tc.name, got.Members, tc.want, t)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, bad := range []string{"alice:x:1000", "alice:x:uid:1000::/:/bin/sh", "alice:x:1000:gid::/:/bin/sh"} {
+		if _, err := parsePasswd(bad); err == nil {
+			t.Errorf("parsePasswd(%q) didn't fail", bad)
+		}
+	}
+	for _, bad := range []string{"users:x:100", "users:x:gid:"} {
+		if _, err := parseGroup(bad); err == nil {
+			t.Errorf("parseGroup(%q) didn't fail", bad)
+		}
+	}
+}
@@ -0,0 +1,508 @@
+// Auto generated code by protoc-gen-go-grpcproxy
+// DO NOT EDIT
+
+// Adds OneMany versions of RPC methods for use by proxy clients
+
+package users
+
+import (
+	context "context"
+	proxy "github.com/Snowflake-Labs/sansshell/proxy/proxy"
+	grpc "google.golang.org/grpc"
+)
+
+import (
+	"fmt"
+)
+
+// UsersClientProxy is the superset of UsersClient which additionally includes the OneMany proxy methods
+type UsersClientProxy interface {
+	UsersClient
+	GetUserOneMany(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (<-chan *GetUserManyResponse, error)
+	ListUsersOneMany(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (<-chan *ListUsersManyResponse, error)
+	GetGroupOneMany(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (<-chan *GetGroupManyResponse, error)
+	ListGroupsOneMany(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (<-chan *ListGroupsManyResponse, error)
+	UserGroupsOneMany(ctx context.Context, in *UserGroupsRequest, opts ...grpc.CallOption) (<-chan *UserGroupsManyResponse, error)
+	GroupMembersOneMany(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (<-chan *GroupMembersManyResponse, error)
+	LoginsOneMany(ctx context.Context, in *LoginsRequest, opts ...grpc.CallOption) (<-chan *LoginsManyResponse, error)
+}
+
+// Embed the original client inside of this so we get the other generated methods automatically.
+type usersClientProxy struct {
+	*usersClient
+}
+
+// NewUsersClientProxy creates a UsersClientProxy for use in proxied connections.
+// NOTE: This takes a proxy.Conn instead of a generic ClientConnInterface as the methods here are only valid in proxy.Conn contexts.
+func NewUsersClientProxy(cc *proxy.Conn) UsersClientProxy {
+	return &usersClientProxy{NewUsersClient(cc).(*usersClient)}
+}
+
+// GetUserManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type GetUserManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *User
+	Error error
+}
+
+// GetUserOneMany provides the same API as GetUser but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *usersClientProxy) GetUserOneMany(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (<-chan *GetUserManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *GetUserManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &GetUserManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &User{},
+			}
+			err := conn.Invoke(ctx, "/Users.Users/GetUser", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Users.Users/GetUser", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &GetUserManyResponse{
+				Resp: &User{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ListUsersManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ListUsersManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ListUsersReply
+	Error error
+}
+
+// ListUsersOneMany provides the same API as ListUsers but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *usersClientProxy) ListUsersOneMany(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (<-chan *ListUsersManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ListUsersManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ListUsersManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ListUsersReply{},
+			}
+			err := conn.Invoke(ctx, "/Users.Users/ListUsers", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Users.Users/ListUsers", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ListUsersManyResponse{
+				Resp: &ListUsersReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// GetGroupManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type GetGroupManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *Group
+	Error error
+}
+
+// GetGroupOneMany provides the same API as GetGroup but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *usersClientProxy) GetGroupOneMany(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (<-chan *GetGroupManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *GetGroupManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &GetGroupManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &Group{},
+			}
+			err := conn.Invoke(ctx, "/Users.Users/GetGroup", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Users.Users/GetGroup", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &GetGroupManyResponse{
+				Resp: &Group{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// ListGroupsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type ListGroupsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *ListGroupsReply
+	Error error
+}
+
+// ListGroupsOneMany provides the same API as ListGroups but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *usersClientProxy) ListGroupsOneMany(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (<-chan *ListGroupsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *ListGroupsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &ListGroupsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &ListGroupsReply{},
+			}
+			err := conn.Invoke(ctx, "/Users.Users/ListGroups", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Users.Users/ListGroups", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &ListGroupsManyResponse{
+				Resp: &ListGroupsReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// UserGroupsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type UserGroupsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *UserGroupsReply
+	Error error
+}
+
+// UserGroupsOneMany provides the same API as UserGroups but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *usersClientProxy) UserGroupsOneMany(ctx context.Context, in *UserGroupsRequest, opts ...grpc.CallOption) (<-chan *UserGroupsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *UserGroupsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &UserGroupsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &UserGroupsReply{},
+			}
+			err := conn.Invoke(ctx, "/Users.Users/UserGroups", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Users.Users/UserGroups", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &UserGroupsManyResponse{
+				Resp: &UserGroupsReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// GroupMembersManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type GroupMembersManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *GroupMembersReply
+	Error error
+}
+
+// GroupMembersOneMany provides the same API as GroupMembers but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *usersClientProxy) GroupMembersOneMany(ctx context.Context, in *GroupMembersRequest, opts ...grpc.CallOption) (<-chan *GroupMembersManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *GroupMembersManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &GroupMembersManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &GroupMembersReply{},
+			}
+			err := conn.Invoke(ctx, "/Users.Users/GroupMembers", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Users.Users/GroupMembers", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &GroupMembersManyResponse{
+				Resp: &GroupMembersReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
+
+// LoginsManyResponse encapsulates a proxy data packet.
+// It includes the target, index, response and possible error returned.
+type LoginsManyResponse struct {
+	Target string
+	// As targets can be duplicated this is the index into the slice passed to proxy.Conn.
+	Index int
+	Resp  *LoginsReply
+	Error error
+}
+
+// LoginsOneMany provides the same API as Logins but sends the same request to N destinations at once.
+// N can be a single destination.
+//
+// NOTE: The returned channel must be read until it closes in order to avoid leaking goroutines.
+func (c *usersClientProxy) LoginsOneMany(ctx context.Context, in *LoginsRequest, opts ...grpc.CallOption) (<-chan *LoginsManyResponse, error) {
+	conn := c.cc.(*proxy.Conn)
+	ret := make(chan *LoginsManyResponse)
+	// If this is a single case we can just use Invoke and marshal it onto the channel once and be done.
+	if len(conn.Targets) == 1 {
+		go func() {
+			out := &LoginsManyResponse{
+				Target: conn.Targets[0],
+				Index:  0,
+				Resp:   &LoginsReply{},
+			}
+			err := conn.Invoke(ctx, "/Users.Users/Logins", in, out.Resp, opts...)
+			if err != nil {
+				out.Error = err
+			}
+			// Send and close.
+			ret <- out
+			close(ret)
+		}()
+		return ret, nil
+	}
+	manyRet, err := conn.InvokeOneMany(ctx, "/Users.Users/Logins", in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// A goroutine to retrive untyped responses and convert them to typed ones.
+	go func() {
+		for {
+			typedResp := &LoginsManyResponse{
+				Resp: &LoginsReply{},
+			}
+
+			resp, ok := <-manyRet
+			if !ok {
+				// All done so we can shut down.
+				close(ret)
+				return
+			}
+			typedResp.Target = resp.Target
+			typedResp.Index = resp.Index
+			typedResp.Error = resp.Error
+			if resp.Error == nil {
+				if err := resp.Resp.UnmarshalTo(typedResp.Resp); err != nil {
+					typedResp.Error = fmt.Errorf("can't decode any response - %v. Original Error - %v", err, resp.Error)
+				}
+			}
+			ret <- typedResp
+		}
+	}()
+
+	return ret, nil
+}
@@ -26,6 +26,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/Snowflake-Labs/sansshell/proxy/proxy"
 	"github.com/go-logr/logr"
@@ -59,6 +60,8 @@ type cmdOptions struct {
 	failOnStderr bool
 	stdoutMax    uint
 	stderrMax    uint
+	env          []string
+	output       io.Writer
 }
 
 // Option will run the apply operation to change required checking/state
@@ -101,6 +104,35 @@ func StderrMax(max uint) Option {
 	})
 }
 
+// EnvVar is an option which sets an environment variable for the command. Commands are
+// otherwise run with an empty environment.
+func EnvVar(key, value string) Option {
+	return optionfunc(func(o *cmdOptions) {
+		o.env = append(o.env, key+"="+value)
+	})
+}
+
+// StreamOutput is an option where stdout and stderr are also written to w as they're
+// produced, in addition to being buffered as normal. Writes to w are serialized. If a
+// write to w fails the command is likely to be killed (i.e. by SIGPIPE).
+func StreamOutput(w io.Writer) Option {
+	return optionfunc(func(o *cmdOptions) {
+		o.output = w
+	})
+}
+
+// lockedWriter serializes writes to w so stdout and stderr can share it.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
 // DefRunBufLimit is the default limit we'll buffer for stdout/stderr from RunCommand exec'ing
 // a process.
 const DefRunBufLimit = 10 * 1024 * 1024
@@ -199,9 +231,15 @@ func RunCommand(ctx context.Context, bin string, args []string, opts ...Option)
 	// can buffer. In practice output tends to be in the low K range size wise.
 	cmd.Stdout = run.Stdout
 	cmd.Stderr = run.Stderr
+	if options.output != nil {
+		w := &lockedWriter{w: options.output}
+		cmd.Stdout = io.MultiWriter(run.Stdout, w)
+		cmd.Stderr = io.MultiWriter(run.Stderr, w)
+	}
 	cmd.Stdin = nil
 	// Set to an empty slice to get an empty environment. Nil means inherit.
 	cmd.Env = []string{}
+	cmd.Env = append(cmd.Env, options.env...)
 
 	logger.Info("executing local command", "cmd", cmd.String())
 	run.Error = cmd.Run()
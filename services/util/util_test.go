@@ -19,6 +19,7 @@ package util
 import (
 	"bytes"
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/Snowflake-Labs/sansshell/testing/testutil"
@@ -34,6 +35,7 @@ func TestRunCommand(t *testing.T) {
 		stdout            string
 		stderr            string
 		stderrIsError     bool
+		env               []string
 	}{
 		{
 			name:    "Not absolute path",
@@ -71,6 +73,12 @@ func TestRunCommand(t *testing.T) {
 			bin:    testutil.ResolvePath(t, "env"),
 			stdout: "",
 		},
+		{
+			name:   "Environment variables",
+			bin:    testutil.ResolvePath(t, "env"),
+			env:    []string{"FOO", "bar", "BAZ", "qux"},
+			stdout: "FOO=bar\nBAZ=qux\n",
+		},
 		{
 			name:              "error codes",
 			bin:               testutil.ResolvePath(t, "false"),
@@ -86,6 +94,9 @@ func TestRunCommand(t *testing.T) {
 			if tc.stderrIsError {
 				opts = append(opts, FailOnStderr())
 			}
+			for i := 0; i+1 < len(tc.env); i += 2 {
+				opts = append(opts, EnvVar(tc.env[i], tc.env[i+1]))
+			}
 
 			run, err := RunCommand(context.Background(), tc.bin, tc.args, opts...)
 			t.Logf("%s: response: %+v", tc.name, run)
@@ -109,6 +120,23 @@ func TestRunCommand(t *testing.T) {
 	}
 }
 
+func TestRunCommandStreamOutput(t *testing.T) {
+	var out bytes.Buffer
+	run, err := RunCommand(context.Background(), testutil.ResolvePath(t, "sh"), []string{"-c", "echo foo && echo bar >&2 && echo baz"}, StreamOutput(&out))
+	testutil.FatalOnErr("RunCommand", err, t)
+	// Ordering between stdout and stderr isn't guaranteed.
+	if got := out.String(); len(got) != len("foo\nbar\nbaz\n") || !strings.Contains(got, "foo\n") || !strings.Contains(got, "bar\n") || !strings.Contains(got, "baz\n") {
+		t.Fatalf("streamed output differs. Want foo, bar and baz lines Got %q", got)
+	}
+	// Output is still buffered as well.
+	if got, want := run.Stdout.String(), "foo\nbaz\n"; got != want {
+		t.Fatalf("Stdout differs. Want %q Got %q", want, got)
+	}
+	if got, want := run.Stderr.String(), "bar\n"; got != want {
+		t.Fatalf("Stderr differs. Want %q Got %q", want, got)
+	}
+}
+
 func TestTrimString(t *testing.T) {
 	b := &bytes.Buffer{}
 	for i := 0; i < 2*MaxBuf; i++ {
@@ -0,0 +1,145 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+// Package metrics exports Prometheus metrics from sansshell processes.
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+)
+
+const namespace = "sansshell"
+
+// AuthzMetrics implements rpcauth.Metrics with Prometheus metrics:
+//
+//	sansshell_authz_decisions_total{method, decision, policy_version}
+//	sansshell_authz_latency_seconds{method, decision, policy_version}
+//	sansshell_authz_cache_hits_total
+//	sansshell_authz_cache_misses_total
+//	sansshell_authz_cache_evictions_total
+//
+// counting decisions, the time taken to make them and the effectiveness of
+// the decision cache (see rpcauth.CacheMetrics).
+type AuthzMetrics struct {
+	decisions *prometheus.CounterVec
+	latency   *prometheus.HistogramVec
+	hits      prometheus.Counter
+	misses    prometheus.Counter
+	evictions prometheus.Counter
+}
+
+// NewAuthzMetrics creates AuthzMetrics registered with `reg`.
+func NewAuthzMetrics(reg prometheus.Registerer) (*AuthzMetrics, error) {
+	labels := []string{"method", "decision", "policy_version"}
+	m := &AuthzMetrics{
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "authz",
+			Name:      "decisions_total",
+			Help:      "Authorization decisions by method, decision (allow, deny or error) and policy version.",
+		}, labels),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "authz",
+			Name:      "latency_seconds",
+			Help:      "Time taken to make authorization decisions, including hooks.",
+			Buckets:   []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, labels),
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "authz",
+			Name:      "cache_hits_total",
+			Help:      "Authorization decisions answered from the decision cache.",
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "authz",
+			Name:      "cache_misses_total",
+			Help:      "Authorization decisions not found in the decision cache.",
+		}),
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "authz",
+			Name:      "cache_evictions_total",
+			Help:      "Decisions dropped from the decision cache because they expired, it was full or the policy changed.",
+		}),
+	}
+	for _, c := range []prometheus.Collector{m.decisions, m.latency, m.hits, m.misses, m.evictions} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveDecision implements rpcauth.Metrics.
+func (m *AuthzMetrics) ObserveDecision(method string, decision string, policyVersion string, latency time.Duration) {
+	m.decisions.WithLabelValues(method, decision, policyVersion).Inc()
+	m.latency.WithLabelValues(method, decision, policyVersion).Observe(latency.Seconds())
+}
+
+// ObserveCache implements rpcauth.CacheMetrics.
+func (m *AuthzMetrics) ObserveCache(event string, count int) {
+	switch event {
+	case rpcauth.CacheHit:
+		m.hits.Add(float64(count))
+	case rpcauth.CacheMiss:
+		m.misses.Add(float64(count))
+	case rpcauth.CacheEviction:
+		m.evictions.Add(float64(count))
+	}
+}
+
+var (
+	_ rpcauth.Metrics      = &AuthzMetrics{}
+	_ rpcauth.CacheMetrics = &AuthzMetrics{}
+)
+
+// NewRegistry returns a registry including the standard Go runtime and
+// process metrics.
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return reg
+}
+
+// Serve serves the metrics in `g` at /metrics on `addr` until ctx is done.
+func Serve(ctx context.Context, addr string, g prometheus.Gatherer) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(g, promhttp.HandlerOpts{}))
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.Serve(lis); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
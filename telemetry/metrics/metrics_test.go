@@ -0,0 +1,114 @@
+/* Copyright (c) 2019 Snowflake Inc. All rights reserved.
+
+   Licensed under the Apache License, Version 2.0 (the
+   "License"); you may not use this file except in compliance
+   with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing,
+   software distributed under the License is distributed on an
+   "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+   KIND, either express or implied.  See the License for the
+   specific language governing permissions and limitations
+   under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/Snowflake-Labs/sansshell/auth/opa/rpcauth"
+	"github.com/Snowflake-Labs/sansshell/testing/testutil"
+)
+
+func TestAuthzMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := NewAuthzMetrics(reg)
+	testutil.FatalOnErr("NewAuthzMetrics", err, t)
+	_, err = NewAuthzMetrics(reg)
+	testutil.FatalOnNoErr("duplicate NewAuthzMetrics", err, t)
+
+	m.ObserveDecision("/Foo.Bar/Baz", rpcauth.DecisionAllow, "v1", time.Millisecond)
+	m.ObserveDecision("/Foo.Bar/Baz", rpcauth.DecisionAllow, "v1", 2*time.Millisecond)
+	m.ObserveDecision("/Foo.Bar/Baz", rpcauth.DecisionDeny, "v1", time.Millisecond)
+
+	if got := promtest.ToFloat64(m.decisions.WithLabelValues("/Foo.Bar/Baz", rpcauth.DecisionAllow, "v1")); got != 2 {
+		t.Errorf("allow count = %v, want 2", got)
+	}
+	if got := promtest.ToFloat64(m.decisions.WithLabelValues("/Foo.Bar/Baz", rpcauth.DecisionDeny, "v1")); got != 1 {
+		t.Errorf("deny count = %v, want 1", got)
+	}
+	if got := promtest.CollectAndCount(m.latency, "sansshell_authz_latency_seconds"); got != 2 {
+		t.Errorf("latency series = %d, want 2", got)
+	}
+
+	m.ObserveCache(rpcauth.CacheHit, 1)
+	m.ObserveCache(rpcauth.CacheHit, 1)
+	m.ObserveCache(rpcauth.CacheMiss, 1)
+	m.ObserveCache(rpcauth.CacheEviction, 3)
+	for _, tc := range []struct {
+		c    prometheus.Counter
+		want float64
+	}{
+		{m.hits, 2},
+		{m.misses, 1},
+		{m.evictions, 3},
+	} {
+		if got := promtest.ToFloat64(tc.c); got != tc.want {
+			t.Errorf("%s = %v, want %v", tc.c.Desc(), got, tc.want)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	reg := NewRegistry()
+	m, err := NewAuthzMetrics(reg)
+	testutil.FatalOnErr("NewAuthzMetrics", err, t)
+	m.ObserveDecision("/Foo.Bar/Baz", rpcauth.DecisionAllow, "v1", time.Millisecond)
+
+	// Find a free port.
+	lis, err := net.Listen("tcp", "localhost:0")
+	testutil.FatalOnErr("Listen", err, t)
+	addr := lis.Addr().String()
+	lis.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		errc <- Serve(ctx, addr, reg)
+	}()
+
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = http.Get("http://" + addr + "/metrics"); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	testutil.FatalOnErr("Get", err, t)
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	testutil.FatalOnErr("ReadAll", err, t)
+	for _, want := range []string{
+		`sansshell_authz_decisions_total{decision="allow",method="/Foo.Bar/Baz",policy_version="v1"} 1`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("metrics missing %q:\n%s", want, b)
+		}
+	}
+
+	cancel()
+	testutil.FatalOnErr("Serve", <-errc, t)
+}
@@ -35,11 +35,15 @@ import (
 // targets makes it possible to regenerate all services
 // by executing `go generate` against this file.
 
+//go:generate go generate ./auth/opa/rpcauth/audit
+//go:generate go generate ./auth/redact
 //go:generate go generate ./proxy/testdata
 //go:generate go generate ./services/ansible
+//go:generate go generate ./services/approvals
 //go:generate go generate ./services/exec
 //go:generate go generate ./services/healthcheck
 //go:generate go generate ./services/localfile
 //go:generate go generate ./services/packages
+//go:generate go generate ./services/policy
 //go:generate go generate ./services/process
 //go:generate go generate ./services/service